@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/api/server"
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/ids/aliasdb"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+var errNotPersistedAlias = errors.New("only aliases added with admin.aliasChain can be deleted")
+
+// ChainAlias is an alias that was given to a chain through the admin API
+type ChainAlias struct {
+	Chain ids.ID `json:"chain"`
+	Alias string `json:"alias"`
+}
+
+// ListChainAliasesReply are the results from calling ListChainAliases
+type ListChainAliasesReply struct {
+	Aliases []ChainAlias `json:"aliases"`
+}
+
+// ListChainAliases returns the aliases that were given to chains through the
+// admin API
+func (service *Admin) ListChainAliases(_ *http.Request, _ *struct{}, reply *ListChainAliasesReply) error {
+	service.Log.Debug("Admin: ListChainAliases called")
+
+	aliases, err := service.ChainAliases.PersistedAliases()
+	if err != nil {
+		return err
+	}
+	reply.Aliases = make([]ChainAlias, 0, len(aliases))
+	for alias, chainID := range aliases {
+		reply.Aliases = append(reply.Aliases, ChainAlias{
+			Chain: chainID,
+			Alias: alias,
+		})
+	}
+	sort.Slice(reply.Aliases, func(i, j int) bool {
+		return reply.Aliases[i].Alias < reply.Aliases[j].Alias
+	})
+	return nil
+}
+
+// DeleteChainAliasArgs are the arguments for calling DeleteChainAlias
+type DeleteChainAliasArgs struct {
+	Alias string `json:"alias"`
+}
+
+// DeleteChainAlias removes an alias that was given to a chain through the admin
+// API
+func (service *Admin) DeleteChainAlias(_ *http.Request, args *DeleteChainAliasArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: DeleteChainAlias called with Alias: %s", args.Alias)
+
+	chainID, err := service.ChainAliases.PersistedAlias(args.Alias)
+	if err == database.ErrNotFound {
+		return errNotPersistedAlias
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := service.ChainAliases.RemoveAlias(args.Alias); err != nil {
+		return err
+	}
+	reply.Success = true
+	return service.HTTPServer.RemoveAliasesWithReadLock(constants.ChainAliasPrefix+chainID.String(), constants.ChainAliasPrefix+args.Alias)
+}
+
+// RestoreChainAliases gives chains the aliases persisted by [aliaser] that were
+// given to them through the admin API before the node restarted. Aliases that
+// clash with aliases given since are skipped.
+func RestoreChainAliases(log logging.Logger, aliaser aliasdb.Aliaser, httpServer *server.Server) error {
+	skipped, err := aliaser.Restore()
+	if err != nil {
+		return err
+	}
+	aliases, err := aliaser.PersistedAliases()
+	if err != nil {
+		return err
+	}
+	for alias, chainID := range aliases {
+		if err, ok := skipped[alias]; ok {
+			log.Warn("couldn't restore alias %q of chain %s: %s", alias, chainID, err)
+			continue
+		}
+		if err := httpServer.AddAliases(constants.ChainAliasPrefix+chainID.String(), constants.ChainAliasPrefix+alias); err != nil {
+			return fmt.Errorf("couldn't restore API alias %q of chain %s: %w", alias, chainID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/api/server"
+	"github.com/Toinounet21/avalanchego-mod/chains"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/ids/aliasdb"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+type aliasingManager struct {
+	chains.MockManager
+	aliaser ids.Aliaser
+}
+
+func (m *aliasingManager) Alias(id ids.ID, alias string) error {
+	return m.aliaser.Alias(id, alias)
+}
+
+func (m *aliasingManager) RemoveAlias(alias string) error {
+	return m.aliaser.RemoveAlias(alias)
+}
+
+func newAliasServer(t *testing.T, manager *aliasingManager, db *memdb.Database) *server.Server {
+	srv := &server.Server{}
+	srv.Initialize(logging.NoLog{}, logging.NoFactory{}, "", 0, server.CORSConfig{}, 0, 0, ids.ShortEmpty)
+	handler, err := NewService(Config{
+		Log:          logging.NoLog{},
+		ChainManager: manager,
+		HTTPServer:   srv,
+		ChainAliases: aliasdb.New(manager.aliaser, db),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.AddRoute(handler, &sync.RWMutex{}, "admin", "", io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func callAdmin(t *testing.T, srv *server.Server, method string, params interface{}, reply interface{}) string {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "admin." + method,
+		"params":  params,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/ext/admin", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	response := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error != nil {
+		return response.Error.Message
+	}
+	if err := json.Unmarshal(response.Result, reply); err != nil {
+		t.Fatal(err)
+	}
+	return ""
+}
+
+func TestPersistedChainAliases(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	db := memdb.New()
+	manager := &aliasingManager{aliaser: ids.NewAliaser()}
+	srv := newAliasServer(t, manager, db)
+
+	errMsg := callAdmin(t, srv, "aliasChain", &AliasChainArgs{
+		Chain: chainID.String(),
+		Alias: "myChain",
+	}, &struct{}{})
+	assert.Empty(errMsg)
+
+	listReply := &ListChainAliasesReply{}
+	errMsg = callAdmin(t, srv, "listChainAliases", struct{}{}, listReply)
+	assert.Empty(errMsg)
+	assert.Equal([]ChainAlias{{Chain: chainID, Alias: "myChain"}}, listReply.Aliases)
+
+	// After a restart, the alias is restored
+	restartedAliaser := ids.NewAliaser()
+	restartedSrv := &server.Server{}
+	restartedSrv.Initialize(logging.NoLog{}, logging.NoFactory{}, "", 0, server.CORSConfig{}, 0, 0, ids.ShortEmpty)
+	assert.NoError(RestoreChainAliases(logging.NoLog{}, aliasdb.New(restartedAliaser, db), restartedSrv))
+	restoredID, err := restartedAliaser.Lookup("myChain")
+	assert.NoError(err)
+	assert.Equal(chainID, restoredID)
+
+	errMsg = callAdmin(t, srv, "deleteChainAlias", &DeleteChainAliasArgs{Alias: "myChain"}, &struct{}{})
+	assert.Empty(errMsg)
+	_, err = manager.aliaser.Lookup("myChain")
+	assert.Error(err)
+
+	errMsg = callAdmin(t, srv, "listChainAliases", struct{}{}, listReply)
+	assert.Empty(errMsg)
+	assert.Empty(listReply.Aliases)
+
+	// Only persisted aliases can be deleted
+	errMsg = callAdmin(t, srv, "deleteChainAlias", &DeleteChainAliasArgs{Alias: "myChain"}, &struct{}{})
+	assert.Equal(errNotPersistedAlias.Error(), errMsg)
+}
@@ -7,6 +7,10 @@ import (
 	"context"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/network"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/rpc"
 )
 
@@ -22,7 +26,30 @@ type Client interface {
 	Alias(ctx context.Context, endpoint string, alias string) (bool, error)
 	AliasChain(ctx context.Context, chainID string, alias string) (bool, error)
 	GetChainAliases(ctx context.Context, chainID string) ([]string, error)
+	ListChainAliases(context.Context) ([]ChainAlias, error)
+	DeleteChainAlias(ctx context.Context, alias string) (bool, error)
+	RegisterVM(ctx context.Context, vmID ids.ID, plugin string, aliases []string) (bool, error)
+	AliasVM(ctx context.Context, vm string, alias string) (bool, error)
+	ListRegisteredVMs(context.Context) ([]RegisteredVM, error)
+	ReloadChainConfig(ctx context.Context, chainID string) ([]string, error)
+	PruneChain(ctx context.Context, chain string) (uint64, error)
+	GetFrontierDiagnostic(ctx context.Context, chainID string) (*common.FrontierDiagnostic, error)
+	TrackSubnet(ctx context.Context, subnetID ids.ID) (bool, error)
+	UntrackSubnet(ctx context.Context, subnetID ids.ID) (bool, error)
+	ReloadNodeConfig(context.Context) (*ReloadNodeConfigReply, error)
+	Backup(context.Context) (string, error)
 	Stacktrace(context.Context) (bool, error)
+	BlockPeers(ctx context.Context, nodeIDs []string, ipRanges []string) (bool, error)
+	UnblockPeers(ctx context.Context, nodeIDs []string, ipRanges []string) (bool, error)
+	GetBlockedPeers(context.Context) (*GetBlockedPeersReply, error)
+	GetVersionPolicy(context.Context) (*network.VersionPolicy, error)
+	GetRuntimeStats(context.Context) (*GetRuntimeStatsReply, error)
+	GetQueueDepths(context.Context) (*GetQueueDepthsReply, error)
+	GetKeystoreUsage(context.Context) (*GetKeystoreUsageReply, error)
+	SetLoggerLevel(ctx context.Context, loggerName string, module string, logLevel *logging.Level, displayLevel *logging.Level) (bool, error)
+	GetLoggerLevel(ctx context.Context, loggerName string) (map[string]LogAndDisplayLevels, error)
+	ResetLoggerModuleLevel(ctx context.Context, loggerName string, module string) (bool, error)
+	ListLoggerModules(context.Context) ([]string, error)
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -87,8 +114,181 @@ func (c *client) GetChainAliases(ctx context.Context, chain string) ([]string, e
 	return res.Aliases, err
 }
 
+func (c *client) ListChainAliases(ctx context.Context) ([]ChainAlias, error) {
+	res := &ListChainAliasesReply{}
+	err := c.requester.SendRequest(ctx, "listChainAliases", struct{}{}, res)
+	return res.Aliases, err
+}
+
+func (c *client) DeleteChainAlias(ctx context.Context, alias string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "deleteChainAlias", &DeleteChainAliasArgs{
+		Alias: alias,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) RegisterVM(ctx context.Context, vmID ids.ID, plugin string, aliases []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "registerVM", &RegisterVMArgs{
+		VMID:    vmID,
+		Plugin:  plugin,
+		Aliases: aliases,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) AliasVM(ctx context.Context, vm string, alias string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "aliasVM", &AliasVMArgs{
+		VM:    vm,
+		Alias: alias,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ListRegisteredVMs(ctx context.Context) ([]RegisteredVM, error) {
+	res := &ListRegisteredVMsReply{}
+	err := c.requester.SendRequest(ctx, "listRegisteredVMs", struct{}{}, res)
+	return res.VMs, err
+}
+
+func (c *client) ReloadChainConfig(ctx context.Context, chain string) ([]string, error) {
+	res := &ReloadChainConfigReply{}
+	err := c.requester.SendRequest(ctx, "reloadChainConfig", &ReloadChainConfigArgs{
+		Chain: chain,
+	}, res)
+	return res.UpdatedKeys, err
+}
+
+func (c *client) PruneChain(ctx context.Context, chain string) (uint64, error) {
+	res := &PruneChainReply{}
+	err := c.requester.SendRequest(ctx, "pruneChain", &PruneChainArgs{
+		Chain: chain,
+	}, res)
+	return uint64(res.Pruned), err
+}
+
+func (c *client) GetFrontierDiagnostic(ctx context.Context, chain string) (*common.FrontierDiagnostic, error) {
+	res := &common.FrontierDiagnostic{}
+	err := c.requester.SendRequest(ctx, "getFrontierDiagnostic", &GetFrontierDiagnosticArgs{
+		Chain: chain,
+	}, res)
+	return res, err
+}
+
+func (c *client) TrackSubnet(ctx context.Context, subnetID ids.ID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "trackSubnet", &TrackSubnetArgs{
+		SubnetID: subnetID,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) UntrackSubnet(ctx context.Context, subnetID ids.ID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "untrackSubnet", &TrackSubnetArgs{
+		SubnetID: subnetID,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ReloadNodeConfig(ctx context.Context) (*ReloadNodeConfigReply, error) {
+	res := &ReloadNodeConfigReply{}
+	err := c.requester.SendRequest(ctx, "reloadNodeConfig", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) Backup(ctx context.Context) (string, error) {
+	res := &BackupReply{}
+	err := c.requester.SendRequest(ctx, "backup", struct{}{}, res)
+	return res.Path, err
+}
+
 func (c *client) Stacktrace(ctx context.Context) (bool, error) {
 	res := &api.SuccessResponse{}
 	err := c.requester.SendRequest(ctx, "stacktrace", struct{}{}, res)
 	return res.Success, err
 }
+
+func (c *client) BlockPeers(ctx context.Context, nodeIDs []string, ipRanges []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "blockPeers", &BlockPeersArgs{
+		NodeIDs:  nodeIDs,
+		IPRanges: ipRanges,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) UnblockPeers(ctx context.Context, nodeIDs []string, ipRanges []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "unblockPeers", &BlockPeersArgs{
+		NodeIDs:  nodeIDs,
+		IPRanges: ipRanges,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) GetBlockedPeers(ctx context.Context) (*GetBlockedPeersReply, error) {
+	res := &GetBlockedPeersReply{}
+	err := c.requester.SendRequest(ctx, "getBlockedPeers", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) GetVersionPolicy(ctx context.Context) (*network.VersionPolicy, error) {
+	res := &network.VersionPolicy{}
+	err := c.requester.SendRequest(ctx, "getVersionPolicy", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) GetRuntimeStats(ctx context.Context) (*GetRuntimeStatsReply, error) {
+	res := &GetRuntimeStatsReply{}
+	err := c.requester.SendRequest(ctx, "getRuntimeStats", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) GetQueueDepths(ctx context.Context) (*GetQueueDepthsReply, error) {
+	res := &GetQueueDepthsReply{}
+	err := c.requester.SendRequest(ctx, "getQueueDepths", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) GetKeystoreUsage(ctx context.Context) (*GetKeystoreUsageReply, error) {
+	res := &GetKeystoreUsageReply{}
+	err := c.requester.SendRequest(ctx, "getKeystoreUsage", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) SetLoggerLevel(ctx context.Context, loggerName string, module string, logLevel *logging.Level, displayLevel *logging.Level) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "setLoggerLevel", &SetLoggerLevelArgs{
+		LoggerName:   loggerName,
+		Module:       module,
+		LogLevel:     logLevel,
+		DisplayLevel: displayLevel,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) GetLoggerLevel(ctx context.Context, loggerName string) (map[string]LogAndDisplayLevels, error) {
+	res := &GetLoggerLevelReply{}
+	err := c.requester.SendRequest(ctx, "getLoggerLevel", &GetLoggerLevelArgs{
+		LoggerName: loggerName,
+	}, res)
+	return res.LoggerLevels, err
+}
+
+func (c *client) ResetLoggerModuleLevel(ctx context.Context, loggerName string, module string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "resetLoggerModuleLevel", &ResetLoggerModuleLevelArgs{
+		LoggerName: loggerName,
+		Module:     module,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ListLoggerModules(ctx context.Context) ([]string, error) {
+	res := &ListLoggerModulesReply{}
+	err := c.requester.SendRequest(ctx, "listLoggerModules", struct{}{}, res)
+	return res.Modules, err
+}
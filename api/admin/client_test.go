@@ -11,6 +11,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/api/keystore"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/network"
 	"github.com/Toinounet21/avalanchego-mod/utils/rpc"
 )
 
@@ -62,6 +65,30 @@ func (mc *mockClient) SendRequest(ctx context.Context, method string, params int
 	case *GetChainAliasesReply:
 		response := mc.response.(*GetChainAliasesReply)
 		*p = *response
+	case *ReloadChainConfigReply:
+		response := mc.response.(*ReloadChainConfigReply)
+		*p = *response
+	case *PruneChainReply:
+		response := mc.response.(*PruneChainReply)
+		*p = *response
+	case *ReloadNodeConfigReply:
+		response := mc.response.(*ReloadNodeConfigReply)
+		*p = *response
+	case *BackupReply:
+		response := mc.response.(*BackupReply)
+		*p = *response
+	case *GetBlockedPeersReply:
+		response := mc.response.(*GetBlockedPeersReply)
+		*p = *response
+	case *network.VersionPolicy:
+		response := mc.response.(*network.VersionPolicy)
+		*p = *response
+	case *GetQueueDepthsReply:
+		response := mc.response.(*GetQueueDepthsReply)
+		*p = *response
+	case *GetKeystoreUsageReply:
+		response := mc.response.(*GetKeystoreUsageReply)
+		*p = *response
 	default:
 		panic("illegal type")
 	}
@@ -204,6 +231,128 @@ func TestGetChainAliases(t *testing.T) {
 	})
 }
 
+func TestReloadChainConfig(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []string{"key1", "key2"}
+		mockClient := client{requester: NewMockClient(&ReloadChainConfigReply{
+			UpdatedKeys: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.ReloadChainConfig(context.Background(), "chain")
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&ReloadChainConfigReply{}, errors.New("some error"))}
+
+		_, err := mockClient.ReloadChainConfig(context.Background(), "chain")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestPruneChain(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&PruneChainReply{Pruned: 5}, nil)}
+
+		pruned, err := mockClient.PruneChain(context.Background(), "chain")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 5, pruned)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&PruneChainReply{}, errors.New("some error"))}
+
+		_, err := mockClient.PruneChain(context.Background(), "chain")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestReloadNodeConfig(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := &ReloadNodeConfigReply{
+			Applied:        []string{"loggingConfig.logLevel"},
+			RequireRestart: []string{"httpConfig.httpPort"},
+		}
+		mockClient := client{requester: NewMockClient(expectedReply, nil)}
+
+		reply, err := mockClient.ReloadNodeConfig(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&ReloadNodeConfigReply{}, errors.New("some error"))}
+
+		_, err := mockClient.ReloadNodeConfig(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestBackup(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedPath := "backups/backup-20211104T120000.000000000Z.tar.gz"
+		mockClient := client{requester: NewMockClient(&BackupReply{Path: expectedPath}, nil)}
+
+		path, err := mockClient.Backup(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPath, path)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&BackupReply{}, errors.New("some error"))}
+
+		_, err := mockClient.Backup(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestTrackSubnet(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.TrackSubnet(context.Background(), ids.GenerateTestID())
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestUntrackSubnet(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.UntrackSubnet(context.Background(), ids.GenerateTestID())
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
 func TestStacktrace(t *testing.T) {
 	tests := GetSuccessResponseTests()
 
@@ -222,3 +371,127 @@ func TestStacktrace(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockPeers(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.BlockPeers(context.Background(), []string{"NodeID-111111111111111111116DBWJs"}, []string{"192.0.2.0/24"})
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestGetBlockedPeers(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := &GetBlockedPeersReply{
+			NodeIDs:  []string{"NodeID-111111111111111111116DBWJs"},
+			IPRanges: []string{"192.0.2.0/24"},
+		}
+		mockClient := client{requester: NewMockClient(expectedReply, nil)}
+
+		reply, err := mockClient.GetBlockedPeers(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&GetBlockedPeersReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetBlockedPeers(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetVersionPolicy(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := &network.VersionPolicy{
+			Version:       "avalanche/1.7.4",
+			MaxCompatible: "avalanche/1.8.0",
+			AffectedPeers: []network.AffectedPeer{{
+				ID:                 "NodeID-111111111111111111116DBWJs",
+				Version:            "avalanche/1.7.2",
+				WillBeIncompatible: true,
+			}},
+		}
+		mockClient := client{requester: NewMockClient(expectedReply, nil)}
+
+		reply, err := mockClient.GetVersionPolicy(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&network.VersionPolicy{}, errors.New("some error"))}
+
+		_, err := mockClient.GetVersionPolicy(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetQueueDepths(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := &GetQueueDepthsReply{
+			OutstandingRequests: 3,
+			Chains: map[string]ChainQueueDepths{
+				"X": {
+					Unprocessed: 2,
+					FromVM:      1,
+				},
+			},
+		}
+		mockClient := client{requester: NewMockClient(expectedReply, nil)}
+
+		reply, err := mockClient.GetQueueDepths(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&GetQueueDepthsReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetQueueDepths(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetKeystoreUsage(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := &GetKeystoreUsageReply{
+			Users: []keystore.UserUsage{{
+				Username: "bob",
+				Bytes:    1024,
+				Quota:    4096,
+			}},
+		}
+		mockClient := client{requester: NewMockClient(expectedReply, nil)}
+
+		reply, err := mockClient.GetKeystoreUsage(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&GetKeystoreUsageReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetKeystoreUsage(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	cjson "github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// cpuProfile and traceProfile are collected over a period of time rather than
+// being a snapshot of the process
+const (
+	cpuProfile   = "profile"
+	traceProfile = "trace"
+)
+
+var errNoProfile = errors.New("need to specify a profile")
+
+// NewProfileHandler returns a handler that serves the pprof profile named by
+// the "profile" query parameter. The "seconds" and "debug" parameters are
+// handled as they are by net/http/pprof. For example, a goroutine dump is
+// served for "?profile=goroutine&debug=2".
+func NewProfileHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("profile")
+		switch {
+		case name == "":
+			http.Error(w, errNoProfile.Error(), http.StatusBadRequest)
+		case name == cpuProfile:
+			pprof.Profile(w, r)
+		case name == traceProfile:
+			pprof.Trace(w, r)
+		default:
+			// Unknown profiles are reported by the pprof handler
+			pprof.Handler(name).ServeHTTP(w, r)
+		}
+	})
+}
+
+// GetRuntimeStatsReply are the results from calling GetRuntimeStats
+type GetRuntimeStatsReply struct {
+	NumCPU       cjson.Uint32 `json:"numCPU"`
+	GOMAXPROCS   cjson.Uint32 `json:"gomaxprocs"`
+	NumGoroutine cjson.Uint32 `json:"numGoroutine"`
+
+	HeapAlloc   cjson.Uint64 `json:"heapAlloc"`
+	HeapInuse   cjson.Uint64 `json:"heapInuse"`
+	HeapObjects cjson.Uint64 `json:"heapObjects"`
+	Sys         cjson.Uint64 `json:"sys"`
+	NextGC      cjson.Uint64 `json:"nextGC"`
+
+	NumGC      cjson.Uint64 `json:"numGC"`
+	LastGC     time.Time    `json:"lastGC"`
+	PauseTotal string       `json:"pauseTotal"`
+	// Pauses of the most recent garbage collections, most recent first
+	RecentPauses []string `json:"recentPauses"`
+}
+
+// maxRecentPauses is the number of garbage collection pauses that are reported
+const maxRecentPauses = 16
+
+// GetRuntimeStats returns statistics about the go runtime, including garbage
+// collection
+func (service *Admin) GetRuntimeStats(_ *http.Request, _ *struct{}, reply *GetRuntimeStatsReply) error {
+	service.Log.Debug("Admin: GetRuntimeStats called")
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	var gcStats debug.GCStats
+	debug.ReadGCStats(&gcStats)
+	if len(gcStats.Pause) > maxRecentPauses {
+		gcStats.Pause = gcStats.Pause[:maxRecentPauses]
+	}
+
+	reply.NumCPU = cjson.Uint32(runtime.NumCPU())
+	reply.GOMAXPROCS = cjson.Uint32(runtime.GOMAXPROCS(0))
+	reply.NumGoroutine = cjson.Uint32(runtime.NumGoroutine())
+	reply.HeapAlloc = cjson.Uint64(memStats.HeapAlloc)
+	reply.HeapInuse = cjson.Uint64(memStats.HeapInuse)
+	reply.HeapObjects = cjson.Uint64(memStats.HeapObjects)
+	reply.Sys = cjson.Uint64(memStats.Sys)
+	reply.NextGC = cjson.Uint64(memStats.NextGC)
+	reply.NumGC = cjson.Uint64(gcStats.NumGC)
+	reply.LastGC = gcStats.LastGC
+	reply.PauseTotal = gcStats.PauseTotal.String()
+	reply.RecentPauses = make([]string, len(gcStats.Pause))
+	for i, pause := range gcStats.Pause {
+		reply.RecentPauses[i] = pause.String()
+	}
+	return nil
+}
+
+// ChainQueueDepths is the number of messages waiting to be processed by the
+// engine of a chain
+type ChainQueueDepths struct {
+	Unprocessed cjson.Uint32 `json:"unprocessed"`
+	FromVM      cjson.Uint32 `json:"fromVM"`
+}
+
+// GetQueueDepthsReply are the results from calling GetQueueDepths
+type GetQueueDepthsReply struct {
+	OutstandingRequests cjson.Uint32 `json:"outstandingRequests"`
+	// Chains maps the primary alias of each chain to its queue depths
+	Chains map[string]ChainQueueDepths `json:"chains"`
+}
+
+// GetQueueDepths returns a point-in-time snapshot of the number of messages
+// waiting to be processed by the router and by each chain's engine
+func (service *Admin) GetQueueDepths(_ *http.Request, _ *struct{}, reply *GetQueueDepthsReply) error {
+	service.Log.Debug("Admin: GetQueueDepths called")
+
+	depths := service.ChainManager.Router().QueueDepths()
+	reply.OutstandingRequests = cjson.Uint32(depths.OutstandingRequests)
+	reply.Chains = make(map[string]ChainQueueDepths, len(depths.Chains))
+	for chainID, chainDepths := range depths.Chains {
+		alias, err := service.ChainManager.PrimaryAlias(chainID)
+		if err != nil {
+			alias = chainID.String()
+		}
+		reply.Chains[alias] = ChainQueueDepths{
+			Unprocessed: cjson.Uint32(chainDepths.Unprocessed),
+			FromVM:      cjson.Uint32(chainDepths.FromVM),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+func TestProfileHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := NewProfileHandler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ext/admin/pprof", nil))
+	assert.Equal(http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ext/admin/pprof?profile=goroutine&debug=2", nil))
+	assert.Equal(http.StatusOK, w.Code)
+	assert.True(strings.HasPrefix(w.Body.String(), "goroutine "))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ext/admin/pprof?profile=unknown", nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+}
+
+func TestGetRuntimeStats(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &Admin{Config: Config{Log: logging.NoLog{}}}
+	reply := &GetRuntimeStatsReply{}
+	assert.NoError(service.GetRuntimeStats(nil, nil, reply))
+	assert.NotZero(reply.NumCPU)
+	assert.NotZero(reply.NumGoroutine)
+	assert.NotZero(reply.HeapAlloc)
+	assert.LessOrEqual(len(reply.RecentPauses), maxRecentPauses)
+}
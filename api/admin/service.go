@@ -5,19 +5,26 @@ package admin
 
 import (
 	"errors"
+	"net"
 	"net/http"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/api/keystore"
 	"github.com/Toinounet21/avalanchego-mod/api/server"
 	"github.com/Toinounet21/avalanchego-mod/chains"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/ids/aliasdb"
+	"github.com/Toinounet21/avalanchego-mod/network"
+	"github.com/Toinounet21/avalanchego-mod/network/blocklist"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/perms"
 	"github.com/Toinounet21/avalanchego-mod/utils/profiler"
+	"github.com/Toinounet21/avalanchego-mod/vms"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm"
 
 	cjson "github.com/Toinounet21/avalanchego-mod/utils/json"
 )
@@ -32,6 +39,8 @@ const (
 var (
 	errAliasTooLong = errors.New("alias length is too long")
 	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+	errNoPeers      = errors.New("need to specify at least one node ID or IP range")
+	errNoDiagnostic = errors.New("the beacons didn't disagree about the chain's accepted frontier")
 )
 
 type Config struct {
@@ -41,6 +50,25 @@ type Config struct {
 	NodeConfig   interface{}
 	ChainManager chains.Manager
 	HTTPServer   *server.Server
+	Network      network.Network
+	// ChainAliases gives chains the aliases given through this API, and
+	// persists them
+	ChainAliases aliasdb.Aliaser
+	// Keystore whose storage usage is reported by this API
+	Keystore keystore.Keystore
+	// VMManager tracks the VMs of the node, and VMRegistry persists the VMs
+	// registered through this API
+	VMManager  vms.Manager
+	VMRegistry *vms.Registry
+	// PluginDir is the directory relative plugin paths are resolved in, and
+	// Supervisor configures the supervision of the registered plugins
+	PluginDir  string
+	Supervisor rpcchainvm.SupervisorConfig
+	// ReloadNodeConfig re-reads the node's config and applies the changed
+	// fields that can be changed without a restart
+	ReloadNodeConfig func() (applied []string, requireRestart []string, err error)
+	// Backup writes a backup of the node and returns its path
+	Backup func() (string, error)
 }
 
 // Admin is the API service for node admin management
@@ -132,12 +160,18 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *a
 		return err
 	}
 
-	if err := service.ChainManager.Alias(chainID, args.Alias); err != nil {
+	// Persist the alias so that it's restored when the node restarts
+	if err := service.ChainAliases.PersistAlias(chainID, args.Alias); err != nil {
+		return err
+	}
+	if err := service.HTTPServer.AddAliasesWithReadLock(constants.ChainAliasPrefix+chainID.String(), constants.ChainAliasPrefix+args.Alias); err != nil {
+		// Don't restore an alias that couldn't be given to the chain's API
+		_ = service.ChainAliases.RemoveAlias(args.Alias)
 		return err
 	}
 
 	reply.Success = true
-	return service.HTTPServer.AddAliasesWithReadLock(constants.ChainAliasPrefix+chainID.String(), constants.ChainAliasPrefix+args.Alias)
+	return nil
 }
 
 // GetChainAliasesArgs are the arguments for calling GetChainAliases
@@ -163,6 +197,152 @@ func (service *Admin) GetChainAliases(_ *http.Request, args *GetChainAliasesArgs
 	return err
 }
 
+// ReloadChainConfigArgs are the arguments for calling ReloadChainConfig
+type ReloadChainConfigArgs struct {
+	Chain string `json:"chain"`
+}
+
+// ReloadChainConfigReply are the config keys that were updated
+type ReloadChainConfigReply struct {
+	UpdatedKeys []string `json:"updatedKeys"`
+}
+
+// ReloadChainConfig re-reads the config of the chain from the chain config
+// directory and applies the changed keys to the chain's VM without restarting
+// the chain. Fails if a key that the VM can't reload was changed.
+func (service *Admin) ReloadChainConfig(_ *http.Request, args *ReloadChainConfigArgs, reply *ReloadChainConfigReply) error {
+	service.Log.Debug("Admin: ReloadChainConfig called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	reply.UpdatedKeys, err = service.ChainManager.ReloadChainConfig(chainID)
+	return err
+}
+
+// PruneChainArgs are the arguments for calling PruneChain
+type PruneChainArgs struct {
+	Chain string `json:"chain"`
+}
+
+// PruneChainReply is the number of containers whose data was deleted
+type PruneChainReply struct {
+	Pruned cjson.Uint64 `json:"pruned"`
+}
+
+// PruneChain deletes the history of the chain that is older than the node's
+// pruning retention window, and compacts the chain's database to reclaim the
+// disk space. Fails if the chain's VM doesn't support pruning.
+func (service *Admin) PruneChain(_ *http.Request, args *PruneChainArgs, reply *PruneChainReply) error {
+	service.Log.Debug("Admin: PruneChain called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	pruned, err := service.ChainManager.PruneChain(chainID)
+	reply.Pruned = cjson.Uint64(pruned)
+	return err
+}
+
+// GetFrontierDiagnosticArgs are the arguments for calling
+// GetFrontierDiagnostic
+type GetFrontierDiagnosticArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetFrontierDiagnostic returns the last report of the sampled beacons
+// disagreeing about the accepted frontier of the chain while it was
+// bootstrapping. Fails if they never disagreed.
+func (service *Admin) GetFrontierDiagnostic(_ *http.Request, args *GetFrontierDiagnosticArgs, reply *common.FrontierDiagnostic) error {
+	service.Log.Debug("Admin: GetFrontierDiagnostic called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	diagnostic, ok := service.ChainManager.FrontierDiagnostic(chainID)
+	if !ok {
+		return errNoDiagnostic
+	}
+	*reply = diagnostic
+	return nil
+}
+
+// TrackSubnetArgs are the arguments for calling TrackSubnet and UntrackSubnet
+type TrackSubnetArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// TrackSubnet makes this node validate the subnet without a restart. The
+// chains of the subnet are created and start bootstrapping. The subnet is no
+// longer tracked once the node restarts, unless it's whitelisted in the node's
+// config.
+func (service *Admin) TrackSubnet(_ *http.Request, args *TrackSubnetArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: TrackSubnet called with SubnetID: %s", args.SubnetID)
+
+	if err := service.ChainManager.TrackSubnet(args.SubnetID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UntrackSubnet makes this node stop validating the subnet and stops the
+// chains of the subnet. The subnet can't be tracked again until the node
+// restarts.
+func (service *Admin) UntrackSubnet(_ *http.Request, args *TrackSubnetArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: UntrackSubnet called with SubnetID: %s", args.SubnetID)
+
+	if err := service.ChainManager.UntrackSubnet(args.SubnetID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ReloadNodeConfigReply are the changed fields of the node's config
+type ReloadNodeConfigReply struct {
+	// Applied are the changed fields that were applied
+	Applied []string `json:"applied"`
+	// RequireRestart are the changed fields that are only applied once the
+	// node restarts
+	RequireRestart []string `json:"requireRestart"`
+}
+
+// ReloadNodeConfig re-reads the node's config from its flags, config file and
+// environment, as on SIGHUP, and applies the changed fields that can be changed
+// without a restart, such as the log levels, the inbound throttling limits,
+// the gossip parameters, the enabled APIs and the whitelisted subnets. Fails
+// if the new config isn't valid, in which case nothing is applied.
+func (service *Admin) ReloadNodeConfig(_ *http.Request, _ *struct{}, reply *ReloadNodeConfigReply) error {
+	service.Log.Debug("Admin: ReloadNodeConfig called")
+
+	var err error
+	reply.Applied, reply.RequireRestart, err = service.Config.ReloadNodeConfig()
+	return err
+}
+
+// BackupReply is the backup that was written
+type BackupReply struct {
+	Path string `json:"path"`
+}
+
+// Backup writes an archive of the node's staking key, config, chain aliases
+// and database to the backup directory, from which the node can be restored
+// with --backup-restore-file
+func (service *Admin) Backup(_ *http.Request, _ *struct{}, reply *BackupReply) error {
+	service.Log.Debug("Admin: Backup called")
+
+	var err error
+	reply.Path, err = service.Config.Backup()
+	return err
+}
+
 // Stacktrace returns the current global stacktrace
 func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
 	service.Log.Debug("Admin: Stacktrace called")
@@ -174,7 +354,10 @@ func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.Succes
 
 // See SetLoggerLevel
 type SetLoggerLevelArgs struct {
-	LoggerName   string         `json:"loggerName"`
+	LoggerName string `json:"loggerName"`
+	// If non-empty, only the levels of this module of the loggers are set.
+	// Should be one of the modules returned by logging.Modules.
+	Module       string         `json:"module"`
 	LogLevel     *logging.Level `json:"logLevel"`
 	DisplayLevel *logging.Level `json:"displayLevel"`
 }
@@ -188,8 +371,10 @@ type SetLoggerLevelArgs struct {
 // Sets the display level of these loggers to args.LogLevel.
 // If args.DisplayLevel == nil, doesn't set the display level of these loggers.
 // If args.DisplayLevel != nil, must be a valid string representation of a log level.
+// If args.Module is non-empty, only the events logged by that module, such as
+// "consensus" or "network", are logged/displayed with these levels.
 func (service *Admin) SetLoggerLevel(_ *http.Request, args *SetLoggerLevelArgs, reply *api.SuccessResponse) error {
-	service.Log.Debug("Admin: SetLogLevels called with LoggerName: %q, LogLevel: %q, DisplayLevel: %q", args.LoggerName, args.LogLevel, args.DisplayLevel)
+	service.Log.Debug("Admin: SetLogLevels called with LoggerName: %q, Module: %q, LogLevel: %q, DisplayLevel: %q", args.LoggerName, args.Module, args.LogLevel, args.DisplayLevel)
 
 	if args.LogLevel == nil && args.DisplayLevel == nil {
 		return errNoLogLevel
@@ -204,6 +389,16 @@ func (service *Admin) SetLoggerLevel(_ *http.Request, args *SetLoggerLevelArgs,
 	}
 
 	for _, name := range loggerNames {
+		if args.Module != "" {
+			levels := logging.ModuleLevels{
+				LogLevel:     args.LogLevel,
+				DisplayLevel: args.DisplayLevel,
+			}
+			if err := service.LogFactory.SetModuleLevels(name, args.Module, levels); err != nil {
+				return err
+			}
+			continue
+		}
 		if args.LogLevel != nil {
 			if err := service.LogFactory.SetLogLevel(name, *args.LogLevel); err != nil {
 				return err
@@ -222,6 +417,8 @@ func (service *Admin) SetLoggerLevel(_ *http.Request, args *SetLoggerLevelArgs,
 type LogAndDisplayLevels struct {
 	LogLevel     logging.Level `json:"logLevel"`
 	DisplayLevel logging.Level `json:"displayLevel"`
+	// Levels of the modules that don't log with the levels of the logger
+	Modules map[string]logging.ModuleLevels `json:"modules,omitempty"`
 }
 
 // See GetLoggerLevel
@@ -255,11 +452,61 @@ func (service *Admin) GetLoggerLevel(_ *http.Request, args *GetLoggerLevelArgs,
 		if err != nil {
 			return err
 		}
+		moduleLevels, err := service.LogFactory.GetModuleLevels(name)
+		if err != nil {
+			return err
+		}
+		if len(moduleLevels) == 0 {
+			moduleLevels = nil
+		}
 		reply.LoggerLevels[name] = LogAndDisplayLevels{
 			LogLevel:     logLevel,
 			DisplayLevel: displayLevel,
+			Modules:      moduleLevels,
+		}
+	}
+	return nil
+}
+
+// See ResetLoggerModuleLevel
+type ResetLoggerModuleLevelArgs struct {
+	LoggerName string `json:"loggerName"`
+	Module     string `json:"module"`
+}
+
+// ResetLoggerModuleLevel makes a module log with the levels of the loggers
+// again, undoing SetLoggerLevel calls for that module.
+// If len([args.LoggerName]) == 0, resets the module in all loggers.
+func (service *Admin) ResetLoggerModuleLevel(_ *http.Request, args *ResetLoggerModuleLevelArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: ResetLoggerModuleLevel called with LoggerName: %q, Module: %q", args.LoggerName, args.Module)
+
+	var loggerNames []string
+	if len(args.LoggerName) > 0 {
+		loggerNames = []string{args.LoggerName}
+	} else {
+		// Empty name means all loggers
+		loggerNames = service.LogFactory.GetLoggerNames()
+	}
+
+	for _, name := range loggerNames {
+		if err := service.LogFactory.ResetModuleLevels(name, args.Module); err != nil {
+			return err
 		}
 	}
+	reply.Success = true
+	return nil
+}
+
+// ListLoggerModulesReply are the results from calling ListLoggerModules
+type ListLoggerModulesReply struct {
+	Modules []string `json:"modules"`
+}
+
+// ListLoggerModules returns the modules whose levels can be set separately
+func (service *Admin) ListLoggerModules(_ *http.Request, _ *struct{}, reply *ListLoggerModulesReply) error {
+	service.Log.Debug("Admin: ListLoggerModules called")
+
+	reply.Modules = logging.Modules()
 	return nil
 }
 
@@ -269,3 +516,115 @@ func (service *Admin) GetConfig(_ *http.Request, args *struct{}, reply *interfac
 	*reply = service.NodeConfig
 	return nil
 }
+
+// BlockPeersArgs are the arguments for calling BlockPeers and UnblockPeers
+type BlockPeersArgs struct {
+	NodeIDs []string `json:"nodeIDs"`
+	// IP ranges in CIDR notation, such as "192.0.2.0/24", or single IPs
+	IPRanges []string `json:"ipRanges"`
+}
+
+// parse returns the node IDs and IP ranges in [args]
+func (args *BlockPeersArgs) parse() ([]ids.ShortID, []*net.IPNet, error) {
+	if len(args.NodeIDs) == 0 && len(args.IPRanges) == 0 {
+		return nil, nil, errNoPeers
+	}
+
+	nodeIDs := make([]ids.ShortID, len(args.NodeIDs))
+	for i, nodeIDStr := range args.NodeIDs {
+		nodeID, err := ids.ShortFromPrefixedString(nodeIDStr, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		nodeIDs[i] = nodeID
+	}
+
+	ipRanges := make([]*net.IPNet, len(args.IPRanges))
+	for i, ipRangeStr := range args.IPRanges {
+		ipRange, err := blocklist.ParseIPRange(ipRangeStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		ipRanges[i] = ipRange
+	}
+	return nodeIDs, ipRanges, nil
+}
+
+// BlockPeers prevents this node from connecting to the given node IDs and to
+// the IPs in the given ranges, and disconnects from the matching peers. Blocked
+// peers remain blocked after the node restarts.
+func (service *Admin) BlockPeers(_ *http.Request, args *BlockPeersArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: BlockPeers called with NodeIDs: %v, IPRanges: %v", args.NodeIDs, args.IPRanges)
+
+	nodeIDs, ipRanges, err := args.parse()
+	if err != nil {
+		return err
+	}
+	if err := service.Network.Block(nodeIDs, ipRanges); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnblockPeers allows this node to connect to the given node IDs and to the IPs
+// in the given ranges again.
+func (service *Admin) UnblockPeers(_ *http.Request, args *BlockPeersArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: UnblockPeers called with NodeIDs: %v, IPRanges: %v", args.NodeIDs, args.IPRanges)
+
+	nodeIDs, ipRanges, err := args.parse()
+	if err != nil {
+		return err
+	}
+	if err := service.Network.Unblock(nodeIDs, ipRanges); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetBlockedPeersReply are the blocked node IDs and IP ranges
+type GetBlockedPeersReply struct {
+	NodeIDs  []string `json:"nodeIDs"`
+	IPRanges []string `json:"ipRanges"`
+}
+
+// GetBlockedPeers returns the node IDs and IP ranges that are blocked
+func (service *Admin) GetBlockedPeers(_ *http.Request, _ *struct{}, reply *GetBlockedPeersReply) error {
+	service.Log.Debug("Admin: GetBlockedPeers called")
+
+	nodeIDs, ipRanges := service.Network.Blocked()
+	reply.NodeIDs = make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		reply.NodeIDs[i] = nodeID.PrefixedString(constants.NodeIDPrefix)
+	}
+	reply.IPRanges = make([]string, len(ipRanges))
+	for i, ipRange := range ipRanges {
+		reply.IPRanges[i] = ipRange.String()
+	}
+	return nil
+}
+
+// GetVersionPolicy returns the policy that peer versions are checked against
+// and the connected peers that it affects
+func (service *Admin) GetVersionPolicy(_ *http.Request, _ *struct{}, reply *network.VersionPolicy) error {
+	service.Log.Debug("Admin: GetVersionPolicy called")
+
+	*reply = service.Network.VersionPolicy()
+	return nil
+}
+
+// GetKeystoreUsageReply is the number of bytes stored by each keystore user
+type GetKeystoreUsageReply struct {
+	Users []keystore.UserUsage `json:"users"`
+}
+
+// GetKeystoreUsage returns the number of bytes that each keystore user stores
+// and the quota that they're held to
+func (service *Admin) GetKeystoreUsage(_ *http.Request, _ *struct{}, reply *GetKeystoreUsageReply) error {
+	service.Log.Debug("Admin: GetKeystoreUsage called")
+
+	var err error
+	reply.Users, err = service.Keystore.ListUsage()
+	return err
+}
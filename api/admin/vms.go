@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/vms"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm"
+)
+
+var errNoPlugin = errors.New("need to specify the plugin that runs the VM")
+
+// RegisterVMArgs are the arguments for calling RegisterVM
+type RegisterVMArgs struct {
+	VMID ids.ID `json:"vmID"`
+	// Path of the plugin binary, relative to the plugin directory unless it's
+	// absolute
+	Plugin  string   `json:"plugin"`
+	Aliases []string `json:"aliases"`
+}
+
+// RegisterVM registers a VM that is run by a plugin. The VM is registered
+// again when the node restarts.
+func (service *Admin) RegisterVM(_ *http.Request, args *RegisterVMArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: RegisterVM called with VMID: %s, Plugin: %s, Aliases: %v", args.VMID, args.Plugin, args.Aliases)
+
+	if args.Plugin == "" {
+		return errNoPlugin
+	}
+	for _, alias := range args.Aliases {
+		if len(alias) > maxAliasLength {
+			return errAliasTooLong
+		}
+	}
+
+	entry := vms.RegistryEntry{
+		Plugin:  args.Plugin,
+		Aliases: args.Aliases,
+	}
+	if err := rpcchainvm.RegisterVM(service.PluginDir, service.VMManager, args.VMID, entry, service.Supervisor); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return service.VMRegistry.Put(args.VMID, entry)
+}
+
+// AliasVMArgs are the arguments for calling AliasVM
+type AliasVMArgs struct {
+	VM    string `json:"vm"`
+	Alias string `json:"alias"`
+}
+
+// AliasVM gives a VM a new alias. The alias is given again when the node
+// restarts.
+func (service *Admin) AliasVM(_ *http.Request, args *AliasVMArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: AliasVM called with VM: %s, Alias: %s", args.VM, args.Alias)
+
+	if len(args.Alias) > maxAliasLength {
+		return errAliasTooLong
+	}
+	vmID, err := service.VMManager.Lookup(args.VM)
+	if err != nil {
+		return err
+	}
+
+	entry, err := service.VMRegistry.Get(vmID)
+	if err != nil && err != database.ErrNotFound {
+		return err
+	}
+	if err := service.VMManager.Alias(vmID, args.Alias); err != nil {
+		return err
+	}
+
+	// Persist the alias so that it's restored when the node restarts
+	entry.Aliases = append(entry.Aliases, args.Alias)
+	reply.Success = true
+	return service.VMRegistry.Put(vmID, entry)
+}
+
+// RegisteredVM is a VM that was registered or aliased through the admin API
+type RegisteredVM struct {
+	VMID    ids.ID   `json:"vmID"`
+	Plugin  string   `json:"plugin,omitempty"`
+	Aliases []string `json:"aliases"`
+}
+
+// ListRegisteredVMsReply are the results from calling ListRegisteredVMs
+type ListRegisteredVMsReply struct {
+	VMs []RegisteredVM `json:"vms"`
+}
+
+// ListRegisteredVMs returns the VMs that were registered or aliased through the
+// admin API
+func (service *Admin) ListRegisteredVMs(_ *http.Request, _ *struct{}, reply *ListRegisteredVMsReply) error {
+	service.Log.Debug("Admin: ListRegisteredVMs called")
+
+	entries, err := service.VMRegistry.List()
+	if err != nil {
+		return err
+	}
+
+	reply.VMs = make([]RegisteredVM, 0, len(entries))
+	for vmID, entry := range entries {
+		aliases := entry.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+		reply.VMs = append(reply.VMs, RegisteredVM{
+			VMID:    vmID,
+			Plugin:  entry.Plugin,
+			Aliases: aliases,
+		})
+	}
+	sort.Slice(reply.VMs, func(i, j int) bool {
+		return reply.VMs[i].VMID.String() < reply.VMs[j].VMID.String()
+	})
+	return nil
+}
+
+// RestoreVMs registers the VMs in [registry] that were registered or aliased
+// through the admin API before the node restarted. VMs that can't be
+// registered again are skipped.
+func RestoreVMs(log logging.Logger, registry *vms.Registry, pluginDir string, manager vms.Manager, supervisor rpcchainvm.SupervisorConfig) error {
+	entries, err := registry.List()
+	if err != nil {
+		return err
+	}
+	for vmID, entry := range entries {
+		if err := rpcchainvm.RegisterVM(pluginDir, manager, vmID, entry, supervisor); err != nil {
+			log.Warn("couldn't restore registered VM %s: %s", vmID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/vms"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm"
+)
+
+type testFactory struct{}
+
+func (testFactory) New(*snow.Context) (interface{}, error) { return nil, nil }
+
+func newVMService(t *testing.T, registry *vms.Registry) (*Admin, vms.Manager, ids.ID) {
+	manager := vms.NewManager()
+	vmID := ids.GenerateTestID()
+	assert.NoError(t, manager.RegisterFactory(vmID, testFactory{}))
+	return &Admin{Config: Config{
+		Log:        logging.NoLog{},
+		VMManager:  manager,
+		VMRegistry: registry,
+		PluginDir:  t.TempDir(),
+	}}, manager, vmID
+}
+
+func TestAliasVM(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := vms.NewRegistry(memdb.New())
+	service, manager, vmID := newVMService(t, registry)
+
+	reply := api.SuccessResponse{}
+	assert.Error(service.AliasVM(nil, &AliasVMArgs{VM: "unknown", Alias: "myvm"}, &reply))
+
+	assert.NoError(service.AliasVM(nil, &AliasVMArgs{VM: vmID.String(), Alias: "myvm"}, &reply))
+	assert.True(reply.Success)
+	aliasedID, err := manager.Lookup("myvm")
+	assert.NoError(err)
+	assert.Equal(vmID, aliasedID)
+
+	listReply := ListRegisteredVMsReply{}
+	assert.NoError(service.ListRegisteredVMs(nil, nil, &listReply))
+	assert.Equal([]RegisteredVM{{VMID: vmID, Aliases: []string{"myvm"}}}, listReply.VMs)
+
+	// The alias is given again when the node restarts
+	manager = vms.NewManager()
+	assert.NoError(manager.RegisterFactory(vmID, testFactory{}))
+	assert.NoError(RestoreVMs(logging.NoLog{}, registry, "", manager, rpcchainvm.SupervisorConfig{}))
+	aliasedID, err = manager.Lookup("myvm")
+	assert.NoError(err)
+	assert.Equal(vmID, aliasedID)
+}
+
+func TestRegisterVM(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := vms.NewRegistry(memdb.New())
+	service, _, _ := newVMService(t, registry)
+
+	reply := api.SuccessResponse{}
+	err := service.RegisterVM(nil, &RegisterVMArgs{VMID: ids.GenerateTestID()}, &reply)
+	assert.ErrorIs(err, errNoPlugin)
+
+	// A VM whose plugin can't be run isn't persisted
+	err = service.RegisterVM(nil, &RegisterVMArgs{
+		VMID:   ids.GenerateTestID(),
+		Plugin: filepath.Join(t.TempDir(), "missing"),
+	}, &reply)
+	assert.Error(err)
+
+	entries, err := registry.List()
+	assert.NoError(err)
+	assert.Empty(entries)
+}
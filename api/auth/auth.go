@@ -4,6 +4,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -18,11 +19,14 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/password"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
 
 	cjson "github.com/Toinounet21/avalanchego-mod/utils/json"
+	rpcutils "github.com/Toinounet21/avalanchego-mod/utils/rpc"
 )
 
 const (
@@ -35,9 +39,24 @@ const (
 	// defaultTokenLifespan is how long a token lives before it expires
 	defaultTokenLifespan = time.Hour * 12
 
+	// maxTokenLifespan is the longest a token can live before it expires
+	maxTokenLifespan = 30 * 24 * time.Hour
+
 	maxEndpoints = 128
+	maxAssets    = 128
+	maxMethods   = 128
+
+	// maxBodySize is the maximum size of a request body whose methods are
+	// checked against the methods a token is restricted to
+	maxBodySize = 4 * units.MiB
 )
 
+type contextKey int
+
+// assetsKey is the request context key of the assets the request's token is
+// restricted to
+const assetsKey contextKey = iota
+
 var (
 	errNoToken               = errors.New("auth token not provided")
 	errAuthHeaderNotParsable = fmt.Errorf(
@@ -48,11 +67,15 @@ var (
 	errInvalidSigningMethod        = errors.New("auth token didn't specify the HS256 signing method correctly")
 	errTokenRevoked                = errors.New("the provided auth token was revoked")
 	errTokenInsufficientPermission = errors.New("the provided auth token does not allow access to this endpoint")
+	errTokenMethodNotAllowed       = errors.New("the provided auth token does not allow calling this method")
 	errWrongPassword               = errors.New("incorrect password")
 	errSamePassword                = errors.New("new password can't be same as old password")
 	errNoPassword                  = errors.New("no password")
 	errNoEndpoints                 = errors.New("must name at least one endpoint")
 	errTooManyEndpoints            = fmt.Errorf("can only name at most %d endpoints", maxEndpoints)
+	errTooManyAssets               = fmt.Errorf("can only name at most %d assets", maxAssets)
+	errTooManyMethods              = fmt.Errorf("can only name at most %d methods", maxMethods)
+	errInvalidLifespan             = fmt.Errorf("token lifespan must be positive and at most %s", maxTokenLifespan)
 
 	_ Auth = &auth{}
 )
@@ -61,7 +84,13 @@ type Auth interface {
 	// Create and return a new token that allows access to each API endpoint for
 	// [duration] such that the API's path ends with an element of [endpoints].
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
-	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
+	// If [assets] is non-empty, the wallet APIs only let the token holder
+	// issue or send those assets.
+	// If [methods] is non-empty, the token only allows calling the JSON-RPC
+	// methods that match an element of [methods]. An element that ends with
+	// "*" matches the methods that start with the rest of the element, e.g.
+	// "avm.get*".
+	NewToken(pw string, duration time.Duration, endpoints []string, assets []ids.ID, methods []string) (string, error)
 
 	// Revokes [token]; it will not be accepted as authorization for future API
 	// calls. If the token is invalid, this is a no-op.  If a token is revoked
@@ -85,7 +114,8 @@ type Auth interface {
 	CreateHandler() (http.Handler, error)
 
 	// WrapHandler wraps an http.Handler. Before passing a request to the
-	// provided handler, the auth token is authenticated.
+	// provided handler, the auth token is authenticated. The assets the token
+	// is restricted to are available to the handler through RestrictedAssets.
 	WrapHandler(h http.Handler) http.Handler
 }
 
@@ -121,15 +151,24 @@ func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
 	}
 }
 
-func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string, assets []ids.ID, methods []string) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
+	if duration <= 0 || duration > maxTokenLifespan {
+		return "", errInvalidLifespan
+	}
 	if l := len(endpoints); l == 0 {
 		return "", errNoEndpoints
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	if len(assets) > maxAssets {
+		return "", errTooManyAssets
+	}
+	if len(methods) > maxMethods {
+		return "", errTooManyMethods
+	}
 
 	a.lock.RLock()
 	defer a.lock.RUnlock()
@@ -157,6 +196,8 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 			ExpiresAt: a.clock.Time().Add(duration).Unix(),
 			Id:        id,
 		},
+		Assets:  assets,
+		Methods: methods,
 	}
 	if canAccessAll {
 		claims.Endpoints = []string{"*"}
@@ -202,12 +243,19 @@ func (a *auth) RevokeToken(tokenStr, pw string) error {
 }
 
 func (a *auth) AuthenticateToken(tokenStr, url string) error {
+	_, err := a.authenticateToken(tokenStr, url)
+	return err
+}
+
+// authenticateToken authenticates [tokenStr] for access to [url] and returns
+// its claims
+func (a *auth) authenticateToken(tokenStr, url string) (*endpointClaims, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
 	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
 	if err != nil { // Probably because signature wrong
-		return err
+		return nil, err
 	}
 
 	// Make sure this token gives access to the requested endpoint
@@ -215,20 +263,20 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 	if !ok {
 		// Error is intentionally dropped here as there is nothing left to do
 		// with it.
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+		return nil, fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
 
 	_, revoked := a.revoked[claims.Id]
 	if revoked {
-		return errTokenRevoked
+		return nil, errTokenRevoked
 	}
 
 	for _, endpoint := range claims.Endpoints {
 		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+			return claims, nil
 		}
 	}
-	return errTokenInsufficientPermission
+	return nil, errTokenInsufficientPermission
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
@@ -289,15 +337,50 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 		// Returns actual auth token. Slice guaranteed to not go OOB
 		tokenStr := rawHeader[len(headerValStart):]
 
-		if err := a.AuthenticateToken(tokenStr, r.URL.Path); err != nil {
+		claims, err := a.authenticateToken(tokenStr, r.URL.Path)
+		if err != nil {
 			writeUnauthorizedResponse(w, err)
 			return
 		}
 
+		if len(claims.Methods) > 0 {
+			methods, err := rpcutils.ReadMethods(r, maxBodySize)
+			if err != nil {
+				writeUnauthorizedResponse(w, err)
+				return
+			}
+			// Requests whose methods can't be determined are rejected
+			if len(methods) == 0 {
+				writeUnauthorizedResponse(w, errTokenMethodNotAllowed)
+				return
+			}
+			for _, method := range methods {
+				if !claims.allowsMethod(method) {
+					writeUnauthorizedResponse(w, fmt.Errorf("%w: %q", errTokenMethodNotAllowed, method))
+					return
+				}
+			}
+		}
+
+		if len(claims.Assets) > 0 {
+			assets := ids.NewSet(len(claims.Assets))
+			assets.Add(claims.Assets...)
+			r = r.WithContext(context.WithValue(r.Context(), assetsKey, assets))
+		}
 		h.ServeHTTP(w, r)
 	})
 }
 
+// RestrictedAssets returns the assets that the auth token of [r] is
+// restricted to. Returns false if [r] may use any asset.
+func RestrictedAssets(r *http.Request) (ids.Set, bool) {
+	if r == nil {
+		return nil, false
+	}
+	assets, ok := r.Context().Value(assetsKey).(ids.Set)
+	return assets, ok
+}
+
 // getTokenKey returns the key to use when making and parsing tokens
 func (a *auth) getTokenKey(t *jwt.Token) (interface{}, error) {
 	if t.Method != jwt.SigningMethodHS256 {
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/password"
 )
@@ -40,10 +42,10 @@ var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request)
 func TestNewTokenWrongPassword(t *testing.T) {
 	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
 
-	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 
-	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 }
 
@@ -55,7 +57,7 @@ func TestNewTokenHappyPath(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	// Parse the token
@@ -79,7 +81,7 @@ func TestTokenHasWrongSig(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	// Try to parse the token using the wrong password
@@ -132,7 +134,7 @@ func TestRevokeToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -145,7 +147,7 @@ func TestWrapHandlerHappyPath(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -164,7 +166,7 @@ func TestWrapHandlerRevokedToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -190,7 +192,7 @@ func TestWrapHandlerExpiredToken(t *testing.T) {
 
 	// Make a token that expired well in the past
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -226,7 +228,7 @@ func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	unauthorizedEndpoints := []string{"/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
@@ -248,7 +250,7 @@ func TestWrapHandlerAuthEndpoint(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -264,7 +266,7 @@ func TestWrapHandlerAccessAll(t *testing.T) {
 
 	// Make a token that allows access to all endpoints
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/foo/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -277,6 +279,91 @@ func TestWrapHandlerAccessAll(t *testing.T) {
 	}
 }
 
+func TestWrapHandlerRestrictedAssets(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	assetID := ids.GenerateTestID()
+	restrictedTokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []ids.ID{assetID}, nil)
+	assert.NoError(t, err)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, nil)
+	assert.NoError(t, err)
+
+	var (
+		assets     ids.Set
+		restricted bool
+	)
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assets, restricted = RestrictedAssets(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X/wallet", strings.NewReader(""))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", restrictedTokenStr))
+	wrappedHandler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, restricted)
+	assert.Equal(t, []ids.ID{assetID}, assets.List())
+
+	req = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X/wallet", strings.NewReader(""))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	wrappedHandler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, restricted)
+
+	_, err = auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, make([]ids.ID, maxAssets+1), nil)
+	assert.ErrorIs(t, err, errTooManyAssets)
+}
+
+func TestWrapHandlerRestrictedMethods(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"}, nil, []string{"avm.get*", "info.peers"})
+	assert.NoError(t, err)
+
+	var body string
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The handler can still read the body
+		bodyBytes, _ := ioutil.ReadAll(r.Body)
+		body = string(bodyBytes)
+	}))
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{body: `{"method": "avm.getTx"}`, code: http.StatusOK},
+		{body: `{"method": "info.peers"}`, code: http.StatusOK},
+		{body: `[{"method": "avm.getUTXOs"}, {"method": "avm.getBalance"}]`, code: http.StatusOK},
+		{body: `{"method": "avm.send"}`, code: http.StatusUnauthorized},
+		{body: `{"method": "info.peersBenched"}`, code: http.StatusUnauthorized},
+		{body: `[{"method": "avm.getTx"}, {"method": "avm.issueTx"}]`, code: http.StatusUnauthorized},
+		// Requests whose methods can't be determined are rejected
+		{body: `not json`, code: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		body = ""
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", strings.NewReader(test.body))
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		assert.Equal(t, test.code, rr.Code, test.body)
+		if test.code == http.StatusOK {
+			assert.Equal(t, test.body, body)
+		}
+	}
+
+	_, err = auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, make([]string, maxMethods+1))
+	assert.ErrorIs(t, err, errTooManyMethods)
+}
+
+func TestNewTokenLifespan(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	_, err := auth.NewToken(testPassword, maxTokenLifespan, []string{"*"}, nil, nil)
+	assert.NoError(t, err)
+	_, err = auth.NewToken(testPassword, maxTokenLifespan+time.Second, []string{"*"}, nil, nil)
+	assert.ErrorIs(t, err, errInvalidLifespan)
+	_, err = auth.NewToken(testPassword, 0, []string{"*"}, nil, nil)
+	assert.ErrorIs(t, err, errInvalidLifespan)
+}
+
 func TestWriteUnauthorizedResponse(t *testing.T) {
 	rr := httptest.NewRecorder()
 	writeUnauthorizedResponse(rr, errors.New("example err"))
@@ -289,7 +376,7 @@ func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -4,7 +4,11 @@
 package auth
 
 import (
+	"strings"
+
 	"github.com/golang-jwt/jwt"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
 )
 
 // Custom claim type used for API access token
@@ -15,4 +19,30 @@ type endpointClaims struct {
 	// If endpoints has an element "*", allows access to all API endpoints
 	// In this case, "*" should be the only element of [endpoints]
 	Endpoints []string `json:"endpoints,omitempty"`
+
+	// If non-empty, the wallet APIs only let the token holder issue or send
+	// these assets
+	Assets []ids.ID `json:"assets,omitempty"`
+
+	// If non-empty, only the JSON-RPC methods that match an element may be
+	// called. An element that ends with "*" matches the methods that start
+	// with the rest of the element.
+	Methods []string `json:"methods,omitempty"`
+}
+
+// allowsMethod returns true if the token allows calling [method]
+func (c *endpointClaims) allowsMethod(method string) bool {
+	if len(c.Methods) == 0 {
+		return true
+	}
+	for _, pattern := range c.Methods {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if method == pattern {
+			return true
+		}
+	}
+	return false
 }
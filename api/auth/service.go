@@ -5,8 +5,11 @@ package auth
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
 )
 
 // Service that serves the Auth API functionality.
@@ -26,17 +29,36 @@ type NewTokenArgs struct {
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// If non-empty, the wallet APIs only let the token holder issue or send
+	// these assets. [Assets] must have at most [maxAssets] elements.
+	Assets []ids.ID `json:"assets"`
+	// If non-empty, the token holder may only call these JSON-RPC methods.
+	// An element that ends with "*" allows all the methods that start with
+	// the rest of the element, e.g. ["info.*", "avm.get*"] allows read-only
+	// access. [Methods] must have at most [maxMethods] elements.
+	Methods []string `json:"methods"`
+	// Number of seconds until the token expires. Defaults to
+	// [defaultTokenLifespan] and must be at most [maxTokenLifespan].
+	Lifespan json.Uint64 `json:"lifespan"`
 }
 
 type Token struct {
-	Token string `json:"token"` // The new token. Expires in [TokenLifespan].
+	Token string `json:"token"` // The new token. Expires in [Lifespan].
 }
 
 func (s *Service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) error {
 	s.auth.log.Debug("Auth: NewToken called")
 
+	lifespan := defaultTokenLifespan
+	if args.Lifespan != 0 {
+		if uint64(args.Lifespan) > uint64(maxTokenLifespan/time.Second) {
+			return errInvalidLifespan
+		}
+		lifespan = time.Duration(args.Lifespan) * time.Second
+	}
+
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Token, err = s.auth.NewToken(args.Password.Password, lifespan, args.Endpoints, args.Assets, args.Methods)
 	return err
 }
 
@@ -0,0 +1,246 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Request is a GraphQL request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response to a GraphQL request
+type Response struct {
+	// Nil if the request couldn't be executed
+	Data   *OrderedMap `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error of a GraphQL response
+type Error struct {
+	Message string `json:"message"`
+	// Response keys leading to the field that errored, if any
+	Path []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// OrderedMap is a JSON object whose keys are marshalled in the order they
+// were set, which is the order in which the fields were selected
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Set [key] to [value]
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get the value of [key]
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyBytes)
+		buf.WriteByte(':')
+		buf.Write(valueBytes)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Execute [request] against [schema]
+func Execute(ctx context.Context, schema *Schema, request *Request) *Response {
+	doc, err := Parse(request.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	op, err := doc.operation(request.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	variables := make(map[string]interface{}, len(op.Variables))
+	for name, defaultValue := range op.Variables {
+		if value, ok := request.Variables[name]; ok {
+			variables[name] = value
+		} else {
+			variables[name] = defaultValue
+		}
+	}
+
+	e := &executor{variables: variables}
+	data := e.executeSelectionSet(ctx, schema.Query, nil, op.SelectionSet, nil)
+	return &Response{Data: data, Errors: e.errs}
+}
+
+// operation returns the operation named [name]. If [name] is empty, the
+// document must contain a single operation.
+func (d *Document) operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) != 1 {
+			return nil, errors.New("operationName is required when the document contains several operations")
+		}
+		return d.Operations[0], nil
+	}
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+type executor struct {
+	variables map[string]interface{}
+	errs      []*Error
+}
+
+func (e *executor) fail(path []interface{}, err error) {
+	e.errs = append(e.errs, &Error{
+		Message: err.Error(),
+		Path:    append([]interface{}(nil), path...),
+	})
+}
+
+func (e *executor) executeSelectionSet(
+	ctx context.Context,
+	object *Object,
+	source interface{},
+	selections []*Selection,
+	path []interface{},
+) *OrderedMap {
+	result := &OrderedMap{}
+	for _, sel := range selections {
+		fieldPath := append(path, sel.Alias)
+		if sel.Name == "__typename" {
+			result.Set(sel.Alias, object.Name)
+			continue
+		}
+
+		field, ok := object.Fields[sel.Name]
+		if !ok {
+			e.fail(fieldPath, fmt.Errorf("cannot query field %q on type %q", sel.Name, object.Name))
+			result.Set(sel.Alias, nil)
+			continue
+		}
+		if _, ok := result.Get(sel.Alias); ok {
+			e.fail(fieldPath, fmt.Errorf("duplicate response key %q", sel.Alias))
+			continue
+		}
+		result.Set(sel.Alias, e.executeField(ctx, field, source, sel, fieldPath))
+	}
+	return result
+}
+
+func (e *executor) executeField(
+	ctx context.Context,
+	field *Field,
+	source interface{},
+	sel *Selection,
+	path []interface{},
+) interface{} {
+	rawArgs := make(map[string]interface{}, len(sel.Arguments))
+	for name, value := range sel.Arguments {
+		v, err := resolveValue(value, e.variables)
+		if err != nil {
+			e.fail(path, err)
+			return nil
+		}
+		rawArgs[name] = v
+	}
+	args, err := coerceArgs(field, rawArgs)
+	if err != nil {
+		e.fail(path, err)
+		return nil
+	}
+
+	resolve := field.Resolve
+	if resolve == nil {
+		resolve = defaultResolver(sel.Name)
+	}
+	value, err := resolve(ctx, source, args)
+	if err != nil {
+		e.fail(path, err)
+		return nil
+	}
+	return e.complete(ctx, field.Type, value, sel, path)
+}
+
+// complete the resolved [value] of a field of type [t]
+func (e *executor) complete(
+	ctx context.Context,
+	t Type,
+	value interface{},
+	sel *Selection,
+	path []interface{},
+) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch t := t.(type) {
+	case NonNull:
+		return e.complete(ctx, t.Of, value, sel, path)
+	case Scalar:
+		if len(sel.SelectionSet) != 0 {
+			e.fail(path, fmt.Errorf("field %q of type %s can't have a selection set", sel.Name, t))
+			return nil
+		}
+		return value
+	case *Object:
+		if len(sel.SelectionSet) == 0 {
+			e.fail(path, fmt.Errorf("field %q of type %s must have a selection set", sel.Name, t))
+			return nil
+		}
+		return e.executeSelectionSet(ctx, t, value, sel.SelectionSet, path)
+	case List:
+		values := reflect.ValueOf(value)
+		if values.Kind() != reflect.Slice {
+			e.fail(path, fmt.Errorf("expected a list but got %T", value))
+			return nil
+		}
+		list := make([]interface{}, values.Len())
+		for i := range list {
+			list[i] = e.complete(ctx, t.Of, values.Index(i).Interface(), sel, append(path, i))
+		}
+		return list
+	default:
+		e.fail(path, fmt.Errorf("unknown type %s", t))
+		return nil
+	}
+}
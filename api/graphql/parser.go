@@ -0,0 +1,474 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	errUnsupportedOperation = errors.New("only query operations are supported")
+	errUnsupportedFragments = errors.New("fragments aren't supported")
+	errUnsupportedDirective = errors.New("directives aren't supported")
+	errUnterminatedString   = errors.New("unterminated string")
+)
+
+// Document is a parsed GraphQL request document. Only the executable subset
+// of the language that is needed to query the node is supported: query
+// operations with variables, aliases and arguments. Fragments, directives,
+// mutations and subscriptions are rejected.
+type Document struct {
+	Operations []*Operation
+}
+
+// Operation is a query operation of a document
+type Operation struct {
+	// Empty for the shorthand form of a query
+	Name string
+	// Variable name --> default value, or nil if there is no default
+	Variables    map[string]interface{}
+	SelectionSet []*Selection
+}
+
+// Selection is a field selected on an object
+type Selection struct {
+	// Key of the field in the response. Defaults to [Name].
+	Alias     string
+	Name      string
+	Arguments map[string]Value
+	// Empty for leaf fields
+	SelectionSet []*Selection
+}
+
+// Value is an argument value. It is either a literal, which is a string,
+// int64, float64, bool, nil, enum, []Value or map[string]Value, or a
+// variable.
+type Value interface{}
+
+// Variable references a variable of the operation
+type Variable string
+
+// Enum is an enum value literal
+type Enum string
+
+// Parse parses the GraphQL document [query]
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: lexer{src: query}}
+	p.next()
+
+	doc := &Document{}
+	for p.err == nil && p.tok.kind != tokenEOF {
+		op := p.parseOperation()
+		if p.err != nil {
+			break
+		}
+		doc.Operations = append(doc.Operations, op)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if len(doc.Operations) == 0 {
+		return nil, errors.New("document doesn't contain an operation")
+	}
+	return doc, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+// next returns the next token of the source
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunctuator, value: "...", pos: start}, nil
+	case strings.IndexByte("!$()&:=@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunctuator, value: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return token{}, fmt.Errorf("unexpected character %q at %d", r, start)
+	}
+}
+
+// skipIgnored skips whitespace, commas and comments
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; c {
+		case ' ', '\t', '\n', '\r', ',':
+			l.pos++
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	l.digits()
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		l.digits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		l.digits()
+	}
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) digits() {
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++ // Skip the opening quote
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '"':
+			l.pos++
+			value, err := strconv.Unquote(l.src[start:l.pos])
+			if err != nil {
+				return token{}, fmt.Errorf("invalid string at %d: %w", start, err)
+			}
+			return token{kind: tokenString, value: value, pos: start}, nil
+		case '\\':
+			l.pos += 2
+		case '\n', '\r':
+			return token{}, fmt.Errorf("%w at %d", errUnterminatedString, start)
+		default:
+			l.pos++
+		}
+	}
+	return token{}, fmt.Errorf("%w at %d", errUnterminatedString, start)
+}
+
+func isLetter(c byte) bool { return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+type parser struct {
+	lexer lexer
+	tok   token
+	err   error
+}
+
+// next advances to the next token
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	p.tok, p.err = p.lexer.next()
+}
+
+// peek returns true if the current token is the punctuator [value]
+func (p *parser) peek(value string) bool {
+	return p.err == nil && p.tok.kind == tokenPunctuator && p.tok.value == value
+}
+
+// expect consumes the punctuator [value]
+func (p *parser) expect(value string) {
+	if p.err != nil {
+		return
+	}
+	if !p.peek(value) {
+		p.unexpected()
+		return
+	}
+	p.next()
+}
+
+// name consumes a name
+func (p *parser) name() string {
+	if p.err != nil {
+		return ""
+	}
+	if p.tok.kind != tokenName {
+		p.unexpected()
+		return ""
+	}
+	name := p.tok.value
+	p.next()
+	return name
+}
+
+func (p *parser) unexpected() {
+	if p.err != nil {
+		return
+	}
+	if p.tok.kind == tokenEOF {
+		p.err = errors.New("unexpected end of document")
+		return
+	}
+	p.err = fmt.Errorf("unexpected %q at %d", p.tok.value, p.tok.pos)
+}
+
+func (p *parser) parseOperation() *Operation {
+	op := &Operation{Variables: make(map[string]interface{})}
+	if p.peek("{") {
+		op.SelectionSet = p.parseSelectionSet()
+		return op
+	}
+	if p.tok.kind != tokenName {
+		p.unexpected()
+		return nil
+	}
+	switch p.tok.value {
+	case "query":
+	case "fragment":
+		p.err = errUnsupportedFragments
+		return nil
+	default:
+		p.err = fmt.Errorf("%w: %q", errUnsupportedOperation, p.tok.value)
+		return nil
+	}
+	p.next()
+
+	if p.tok.kind == tokenName {
+		op.Name = p.name()
+	}
+	if p.peek("(") {
+		p.next()
+		for p.err == nil && !p.peek(")") {
+			p.expect("$")
+			name := p.name()
+			p.expect(":")
+			p.parseType()
+			var defaultValue interface{}
+			if p.peek("=") {
+				p.next()
+				value := p.parseValue(true)
+				defaultValue, p.err = resolveValue(value, nil)
+			}
+			op.Variables[name] = defaultValue
+		}
+		p.expect(")")
+	}
+	p.rejectDirectives()
+	op.SelectionSet = p.parseSelectionSet()
+	return op
+}
+
+// parseType consumes a type reference. Variable types aren't checked, the
+// arguments are checked against the schema once the variables are resolved.
+func (p *parser) parseType() {
+	if p.peek("[") {
+		p.next()
+		p.parseType()
+		p.expect("]")
+	} else {
+		p.name()
+	}
+	if p.peek("!") {
+		p.next()
+	}
+}
+
+func (p *parser) rejectDirectives() {
+	if p.peek("@") {
+		p.err = errUnsupportedDirective
+	}
+}
+
+func (p *parser) parseSelectionSet() []*Selection {
+	p.expect("{")
+	var selections []*Selection
+	for p.err == nil && !p.peek("}") {
+		if p.peek("...") {
+			p.err = errUnsupportedFragments
+			return nil
+		}
+		selections = append(selections, p.parseSelection())
+	}
+	p.expect("}")
+	if p.err == nil && len(selections) == 0 {
+		p.err = errors.New("empty selection set")
+	}
+	return selections
+}
+
+func (p *parser) parseSelection() *Selection {
+	sel := &Selection{Name: p.name()}
+	if p.peek(":") {
+		p.next()
+		sel.Alias = sel.Name
+		sel.Name = p.name()
+	}
+	if sel.Alias == "" {
+		sel.Alias = sel.Name
+	}
+	if p.peek("(") {
+		p.next()
+		sel.Arguments = make(map[string]Value)
+		for p.err == nil && !p.peek(")") {
+			name := p.name()
+			p.expect(":")
+			sel.Arguments[name] = p.parseValue(false)
+		}
+		p.expect(")")
+	}
+	p.rejectDirectives()
+	if p.peek("{") {
+		sel.SelectionSet = p.parseSelectionSet()
+	}
+	return sel
+}
+
+// parseValue consumes a value. If [constant], variables aren't allowed.
+func (p *parser) parseValue(constant bool) Value {
+	if p.err != nil {
+		return nil
+	}
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		p.next()
+		i, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			p.err = fmt.Errorf("invalid int %q at %d", tok.value, tok.pos)
+		}
+		return i
+	case tokenFloat:
+		p.next()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			p.err = fmt.Errorf("invalid float %q at %d", tok.value, tok.pos)
+		}
+		return f
+	case tokenString:
+		p.next()
+		return tok.value
+	case tokenName:
+		p.next()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		default:
+			return Enum(tok.value)
+		}
+	}
+
+	switch {
+	case p.peek("$") && !constant:
+		p.next()
+		return Variable(p.name())
+	case p.peek("["):
+		p.next()
+		list := []Value{}
+		for p.err == nil && !p.peek("]") {
+			list = append(list, p.parseValue(constant))
+		}
+		p.expect("]")
+		return list
+	case p.peek("{"):
+		p.next()
+		object := make(map[string]Value)
+		for p.err == nil && !p.peek("}") {
+			name := p.name()
+			p.expect(":")
+			object[name] = p.parseValue(constant)
+		}
+		p.expect("}")
+		return object
+	default:
+		p.unexpected()
+		return nil
+	}
+}
+
+// resolveValue replaces the variables in [value] by their value in
+// [variables]
+func resolveValue(value Value, variables map[string]interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case Variable:
+		v, ok := variables[string(value)]
+		if !ok {
+			return nil, fmt.Errorf("undefined variable $%s", value)
+		}
+		return v, nil
+	case Enum:
+		return string(value), nil
+	case []Value:
+		list := make([]interface{}, len(value))
+		for i, elt := range value {
+			v, err := resolveValue(elt, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case map[string]Value:
+		object := make(map[string]interface{}, len(value))
+		for k, elt := range value {
+			v, err := resolveValue(elt, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[k] = v
+		}
+		return object, nil
+	default:
+		return value, nil
+	}
+}
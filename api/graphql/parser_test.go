@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	doc, err := Parse(`
+		# Fetches the peers
+		query Peers($ids: [String!] = ["a", "b"], $limit: Int) {
+			first: peers(nodeIDs: $ids, limit: 1.5e1, enabled: true, mode: FAST) {
+				nodeID
+			}
+			node { version }
+		}
+	`)
+	assert.NoError(err)
+	assert.Len(doc.Operations, 1)
+
+	op := doc.Operations[0]
+	assert.Equal("Peers", op.Name)
+	assert.Equal(map[string]interface{}{
+		"ids":   []interface{}{"a", "b"},
+		"limit": nil,
+	}, op.Variables)
+	assert.Len(op.SelectionSet, 2)
+
+	peers := op.SelectionSet[0]
+	assert.Equal("first", peers.Alias)
+	assert.Equal("peers", peers.Name)
+	assert.Equal(map[string]Value{
+		"nodeIDs": Variable("ids"),
+		"limit":   15.0,
+		"enabled": true,
+		"mode":    Enum("FAST"),
+	}, peers.Arguments)
+	assert.Equal([]*Selection{{Alias: "nodeID", Name: "nodeID"}}, peers.SelectionSet)
+
+	node := op.SelectionSet[1]
+	assert.Equal("node", node.Alias)
+	assert.Len(node.SelectionSet, 1)
+
+	// Shorthand queries don't have a name
+	doc, err = Parse(`{ tx(id: "abc\n") { status } }`)
+	assert.NoError(err)
+	assert.Empty(doc.Operations[0].Name)
+	assert.Equal("abc\n", doc.Operations[0].SelectionSet[0].Arguments["id"])
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "mutation", query: `mutation { send }`, err: errUnsupportedOperation},
+		{name: "fragment definition", query: `fragment F on Peer { ip }`, err: errUnsupportedFragments},
+		{name: "fragment spread", query: `{ peers { ...F } }`, err: errUnsupportedFragments},
+		{name: "directive", query: `{ peers @skip(if: true) { ip } }`, err: errUnsupportedDirective},
+		{name: "unterminated string", query: `{ tx(id: "abc) { status } }`, err: errUnterminatedString},
+		{name: "unclosed selection set", query: `{ peers { ip }`},
+		{name: "empty selection set", query: `{ }`},
+		{name: "variable in default value", query: `query ($a: Int = $b) { node { version } }`},
+		{name: "empty document", query: ` # nothing`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.query)
+			assert.Error(t, err)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+var (
+	_ Type = Scalar("")
+	_ Type = List{}
+	_ Type = NonNull{}
+	_ Type = &Object{}
+)
+
+// Type of a field or of an argument
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type
+type Scalar string
+
+const (
+	String  Scalar = "String"
+	Int     Scalar = "Int"
+	Float   Scalar = "Float"
+	Boolean Scalar = "Boolean"
+)
+
+func (s Scalar) String() string { return string(s) }
+
+// List of values of type [Of]
+type List struct{ Of Type }
+
+func (l List) String() string { return fmt.Sprintf("[%s]", l.Of) }
+
+// NonNull marks an argument as required
+type NonNull struct{ Of Type }
+
+func (n NonNull) String() string { return fmt.Sprintf("%s!", n.Of) }
+
+// Object is a type whose fields are selected
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+// Resolver returns the value of a field of [source], which is the value of
+// the object the field is selected on. [args] are the arguments of the field,
+// coerced to the types declared by the field.
+type Resolver func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// Field of an object
+type Field struct {
+	Type Type
+	// Argument name --> type of the argument
+	Args map[string]Type
+	// If nil, the value of the field is the value of the key with the name of
+	// the field in [source], which must then be a map[string]interface{}.
+	Resolve Resolver
+}
+
+// Schema of the queries that can be executed
+type Schema struct {
+	Query *Object
+}
+
+// defaultResolver resolves the field [name] of [source]
+func defaultResolver(name string) Resolver {
+	return func(_ context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+		object, ok := source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		return object[name], nil
+	}
+}
+
+// coerceArgs checks [args] against the arguments declared by [field] and
+// converts them to their declared types
+func coerceArgs(field *Field, args map[string]interface{}) (map[string]interface{}, error) {
+	for name := range args {
+		if _, ok := field.Args[name]; !ok {
+			return nil, fmt.Errorf("unknown argument %q", name)
+		}
+	}
+	coerced := make(map[string]interface{}, len(field.Args))
+	for name, argType := range field.Args {
+		value, err := coerce(argType, args[name])
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+		if value != nil {
+			coerced[name] = value
+		}
+	}
+	return coerced, nil
+}
+
+// coerce converts [value] to [t]. JSON numbers of variables are float64s and
+// int literals are int64s, so both are accepted for Int and Float.
+func coerce(t Type, value interface{}) (interface{}, error) {
+	if nonNull, ok := t.(NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected a non-null %s", nonNull.Of)
+		}
+		t = nonNull.Of
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t {
+	case String:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case Boolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case Int:
+		switch n := value.(type) {
+		case int64:
+			return n, nil
+		case float64:
+			if n == math.Trunc(n) && n >= math.MinInt64 && n <= math.MaxInt64 {
+				return int64(n), nil
+			}
+		}
+	case Float:
+		switch n := value.(type) {
+		case int64:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+	}
+
+	if list, ok := t.(List); ok {
+		values, ok := value.([]interface{})
+		if !ok {
+			// A single value is coerced to a list of one value
+			values = []interface{}{value}
+		}
+		coerced := make([]interface{}, len(values))
+		for i, elt := range values {
+			v, err := coerce(list.Of, elt)
+			if err != nil {
+				return nil, err
+			}
+			coerced[i] = v
+		}
+		return coerced, nil
+	}
+	return nil, fmt.Errorf("expected a %s but got %v", t, value)
+}
@@ -0,0 +1,257 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+)
+
+const (
+	infoEndpoint     = "ext/info"
+	platformEndpoint = "ext/bc/P"
+
+	// Chain whose txs and UTXOs are queried by default
+	defaultChain = "X"
+)
+
+var errInvalidChain = errors.New("invalid chain")
+
+// NewNodeSchema returns the schema of the queries served by the node. The
+// fields are resolved by calling the existing APIs through [caller], so a
+// field is only fetched if it is selected.
+func NewNodeSchema(caller Caller) *Schema {
+	call := func(ctx context.Context, endpoint, method string, args interface{}) (map[string]interface{}, error) {
+		reply := make(map[string]interface{})
+		if err := caller.Call(ctx, endpoint, method, args, &reply); err != nil {
+			return nil, fmt.Errorf("%s failed: %w", method, err)
+		}
+		return reply, nil
+	}
+	// replyField resolves a field to the value of [key] in the reply of
+	// [method]
+	replyField := func(t Type, endpoint, method, key string) *Field {
+		return &Field{
+			Type: t,
+			Resolve: func(ctx context.Context, _ interface{}, _ map[string]interface{}) (interface{}, error) {
+				reply, err := call(ctx, endpoint, method, struct{}{})
+				if err != nil {
+					return nil, err
+				}
+				return reply[key], nil
+			},
+		}
+	}
+
+	node := &Object{
+		Name: "Node",
+		Fields: map[string]*Field{
+			"nodeID":    replyField(String, infoEndpoint, "info.getNodeID", "nodeID"),
+			"networkID": replyField(String, infoEndpoint, "info.getNetworkID", "networkID"),
+			"version":   replyField(String, infoEndpoint, "info.getNodeVersion", "version"),
+		},
+	}
+
+	peer := &Object{
+		Name: "Peer",
+		Fields: map[string]*Field{
+			"nodeID":         {Type: String},
+			"ip":             {Type: String},
+			"publicIP":       {Type: String},
+			"version":        {Type: String},
+			"connected":      {Type: Boolean},
+			"lastSent":       {Type: String},
+			"lastReceived":   {Type: String},
+			"benched":        {Type: List{Of: String}},
+			"observedUptime": {Type: String},
+			"trackedSubnets": {Type: List{Of: String}},
+		},
+	}
+
+	validator := &Object{
+		Name: "Validator",
+		Fields: map[string]*Field{
+			"nodeID":          {Type: String},
+			"startTime":       {Type: String},
+			"endTime":         {Type: String},
+			"weight":          {Type: String},
+			"stakeAmount":     {Type: String},
+			"potentialReward": {Type: String},
+			"connected":       {Type: Boolean},
+			"uptime":          {Type: String},
+		},
+	}
+
+	tx := &Object{
+		Name: "Tx",
+		Fields: map[string]*Field{
+			"id":    {Type: String},
+			"chain": {Type: String},
+			"bytes": {
+				Type: String,
+				Resolve: func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+					txRef := source.(map[string]interface{})
+					endpoint, err := chainEndpoint(txRef["chain"].(string))
+					if err != nil {
+						return nil, err
+					}
+					reply, err := call(ctx, endpoint, "avm.getTx", map[string]interface{}{
+						"txID":     txRef["id"],
+						"encoding": formatting.Hex,
+					})
+					if err != nil {
+						return nil, err
+					}
+					return reply["tx"], nil
+				},
+			},
+			"status": {
+				Type: String,
+				Resolve: func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+					txRef := source.(map[string]interface{})
+					endpoint, err := chainEndpoint(txRef["chain"].(string))
+					if err != nil {
+						return nil, err
+					}
+					reply, err := call(ctx, endpoint, "avm.getTxStatus", map[string]interface{}{
+						"txID": txRef["id"],
+					})
+					if err != nil {
+						return nil, err
+					}
+					return reply["status"], nil
+				},
+			},
+		},
+	}
+
+	utxoIndex := &Object{
+		Name: "UTXOIndex",
+		Fields: map[string]*Field{
+			"address": {Type: String},
+			"utxo":    {Type: String},
+		},
+	}
+
+	utxoPage := &Object{
+		Name: "UTXOPage",
+		Fields: map[string]*Field{
+			"numFetched": {Type: String},
+			"utxos":      {Type: List{Of: String}},
+			"endIndex":   {Type: utxoIndex},
+			"encoding":   {Type: String},
+		},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: map[string]*Field{
+			"node": {
+				Type: node,
+				Resolve: func(context.Context, interface{}, map[string]interface{}) (interface{}, error) {
+					// The fields of the node are fetched when they're selected
+					return struct{}{}, nil
+				},
+			},
+			"peers": {
+				Type: List{Of: peer},
+				Args: map[string]Type{
+					"nodeIDs": List{Of: String},
+				},
+				Resolve: func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+					reply, err := call(ctx, infoEndpoint, "info.peers", map[string]interface{}{
+						"nodeIDs": args["nodeIDs"],
+					})
+					if err != nil {
+						return nil, err
+					}
+					return reply["peers"], nil
+				},
+			},
+			"validators": {
+				Type: List{Of: validator},
+				Args: map[string]Type{
+					"subnetID": String,
+					"nodeIDs":  List{Of: String},
+				},
+				Resolve: func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+					// The primary network is queried if [subnetID] is omitted
+					reply, err := call(ctx, platformEndpoint, "platform.getCurrentValidators", args)
+					if err != nil {
+						return nil, err
+					}
+					return reply["validators"], nil
+				},
+			},
+			"tx": {
+				Type: tx,
+				Args: map[string]Type{
+					"chain": String,
+					"id":    NonNull{Of: String},
+				},
+				Resolve: func(_ context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+					chain, ok := args["chain"]
+					if !ok {
+						chain = defaultChain
+					}
+					// The tx is fetched when its fields are selected
+					return map[string]interface{}{
+						"id":    args["id"],
+						"chain": chain,
+					}, nil
+				},
+			},
+			"utxos": {
+				Type: utxoPage,
+				Args: map[string]Type{
+					"chain":        String,
+					"addresses":    NonNull{Of: List{Of: String}},
+					"sourceChain":  String,
+					"limit":        Int,
+					"startAddress": String,
+					"startUTXO":    String,
+				},
+				Resolve: func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+					chain, ok := args["chain"].(string)
+					if !ok {
+						chain = defaultChain
+					}
+					endpoint, err := chainEndpoint(chain)
+					if err != nil {
+						return nil, err
+					}
+					params := map[string]interface{}{
+						"addresses": args["addresses"],
+						"encoding":  formatting.Hex,
+						"startIndex": map[string]interface{}{
+							"address": args["startAddress"],
+							"utxo":    args["startUTXO"],
+						},
+					}
+					if sourceChain, ok := args["sourceChain"]; ok {
+						params["sourceChain"] = sourceChain
+					}
+					if limit, ok := args["limit"].(int64); ok {
+						params["limit"] = fmt.Sprint(limit)
+					}
+					return call(ctx, endpoint, "avm.getUTXOs", params)
+				},
+			},
+		},
+	}
+	return &Schema{Query: query}
+}
+
+// chainEndpoint returns the endpoint of the API of [chain], which is the ID or
+// an alias of the chain
+func chainEndpoint(chain string) (string, error) {
+	if chain == "" || strings.ContainsAny(chain, "/?#%") {
+		return "", fmt.Errorf("%w: %q", errInvalidChain, chain)
+	}
+	return "ext/bc/" + chain, nil
+}
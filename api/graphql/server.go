@@ -0,0 +1,200 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	rpc "github.com/gorilla/rpc/v2/json2"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+const (
+	// Maximum size of a request body
+	maxRequestSize = units.MiB
+
+	// Maximum number of requests in a batch
+	maxBatchSize = 64
+)
+
+var (
+	errBatchTooLarge  = fmt.Errorf("batches are limited to %d requests", maxBatchSize)
+	errEmptyBatch     = errors.New("empty batch")
+	errMissingQuery   = errors.New("missing query")
+	errRequestFailed  = errors.New("request failed")
+	errMethodNotFound = errors.New("only GET and POST requests are supported")
+)
+
+type authorizationKey struct{}
+
+// Caller issues JSON-RPC requests to the APIs of the node
+type Caller interface {
+	// Call [method] of the API at [endpoint], which is relative to the root
+	// of the API server, e.g. "ext/info"
+	Call(ctx context.Context, endpoint, method string, args, reply interface{}) error
+}
+
+// NewHandlerCaller returns a Caller that serves the requests with [handler]
+// in process. The Authorization header of the GraphQL request is forwarded,
+// so that the requests are authorized as if they had been issued by the
+// client.
+func NewHandlerCaller(handler http.Handler) Caller {
+	return &handlerCaller{handler: handler}
+}
+
+type handlerCaller struct {
+	handler http.Handler
+}
+
+func (c *handlerCaller) Call(ctx context.Context, endpoint, method string, args, reply interface{}) error {
+	body, err := rpc.EncodeClientRequest(method, args)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/"+strings.TrimLeft(endpoint, "/"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization, ok := ctx.Value(authorizationKey{}).(string); ok {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	w := &responseBuffer{header: make(http.Header), status: http.StatusOK}
+	c.handler.ServeHTTP(w, req)
+	if w.status < 200 || w.status > 299 {
+		return fmt.Errorf("%w with status code %d: %s", errRequestFailed, w.status, strings.TrimSpace(w.body.String()))
+	}
+	return rpc.DecodeClientResponse(&w.body, reply)
+}
+
+// responseBuffer buffers the response of an in process request
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseBuffer) Header() http.Header         { return w.header }
+func (w *responseBuffer) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *responseBuffer) WriteHeader(status int)      { w.status = status }
+
+// Server executes GraphQL queries against the schema of the node.
+//
+// A request is either a single GraphQL request or a JSON array of requests,
+// which are executed in order and answered with an array of responses.
+type Server struct {
+	log    logging.Logger
+	schema *Schema
+}
+
+// NewServer returns a GraphQL server that fronts the APIs reached through
+// [caller]
+func NewServer(log logging.Logger, caller Caller) *Server {
+	return &Server{
+		log:    log,
+		schema: NewNodeSchema(caller),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		ctx = context.WithValue(ctx, authorizationKey{}, authorization)
+	}
+
+	var body []byte
+	switch r.Method {
+	case http.MethodGet:
+		request := &Request{
+			Query:         r.URL.Query().Get("query"),
+			OperationName: r.URL.Query().Get("operationName"),
+		}
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				s.writeError(w, http.StatusBadRequest, fmt.Errorf("couldn't parse variables: %w", err))
+				return
+			}
+		}
+		s.write(w, s.execute(ctx, request))
+		return
+	case http.MethodPost:
+		var err error
+		body, err = ioutil.ReadAll(io.LimitReader(r.Body, maxRequestSize+1))
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if len(body) > maxRequestSize {
+			s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request exceeds %d bytes", maxRequestSize))
+			return
+		}
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, errMethodNotFound)
+		return
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var requests []*Request
+		if err := json.Unmarshal(trimmed, &requests); err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		switch {
+		case len(requests) == 0:
+			s.writeError(w, http.StatusBadRequest, errEmptyBatch)
+			return
+		case len(requests) > maxBatchSize:
+			s.writeError(w, http.StatusBadRequest, errBatchTooLarge)
+			return
+		}
+		responses := make([]*Response, len(requests))
+		for i, request := range requests {
+			responses[i] = s.execute(ctx, request)
+		}
+		s.write(w, responses)
+		return
+	}
+
+	request := &Request{}
+	if err := json.Unmarshal(body, request); err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.write(w, s.execute(ctx, request))
+}
+
+func (s *Server) execute(ctx context.Context, request *Request) *Response {
+	if request == nil || request.Query == "" {
+		return &Response{Errors: []*Error{{Message: errMissingQuery.Error()}}}
+	}
+	return Execute(ctx, s.schema, request)
+}
+
+func (s *Server) write(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.log.Debug("failed to write GraphQL response: %s", err)
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := &Response{Errors: []*Error{{Message: err.Error()}}}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.log.Debug("failed to write GraphQL response: %s", err)
+	}
+}
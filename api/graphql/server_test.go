@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+
+	cjson "github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// InfoService is exported so that it can be registered as an RPC service
+type InfoService struct {
+	calls int
+}
+
+type NodeIDReply struct {
+	NodeID string `json:"nodeID"`
+}
+
+func (i *InfoService) GetNodeID(r *http.Request, _ *struct{}, reply *NodeIDReply) error {
+	i.calls++
+	if r.Header.Get("Authorization") != "Bearer token" {
+		return errors.New("unauthorized")
+	}
+	reply.NodeID = "NodeID-1"
+	return nil
+}
+
+type PeersArgs struct {
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+type PeersReply struct {
+	Peers []map[string]interface{} `json:"peers"`
+}
+
+func (i *InfoService) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply) error {
+	i.calls++
+	for _, nodeID := range args.NodeIDs {
+		reply.Peers = append(reply.Peers, map[string]interface{}{
+			"nodeID":  nodeID,
+			"ip":      "127.0.0.1:9651",
+			"benched": []string{},
+		})
+	}
+	return nil
+}
+
+func newTestServer(t *testing.T) (*Server, *InfoService) {
+	info := &InfoService{}
+	rpcServer := rpc.NewServer()
+	rpcServer.RegisterCodec(cjson.NewCodec(), "application/json")
+	if err := rpcServer.RegisterService(info, "info"); err != nil {
+		t.Fatal(err)
+	}
+	router := mux.NewRouter()
+	router.Handle("/"+infoEndpoint, rpcServer)
+	return NewServer(logging.NoLog{}, NewHandlerCaller(router)), info
+}
+
+func post(s *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w
+}
+
+func TestServerQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	s, info := newTestServer(t)
+	w := post(s, `{
+		"query": "query ($ids: [String]) { node { id: nodeID __typename } peers(nodeIDs: $ids) { nodeID ip } }",
+		"variables": {"ids": ["NodeID-2", "NodeID-3"]}
+	}`)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.JSONEq(`{
+		"data": {
+			"node": {"id": "NodeID-1", "__typename": "Node"},
+			"peers": [
+				{"nodeID": "NodeID-2", "ip": "127.0.0.1:9651"},
+				{"nodeID": "NodeID-3", "ip": "127.0.0.1:9651"}
+			]
+		}
+	}`, w.Body.String())
+	// Only the selected fields are fetched
+	assert.Equal(2, info.calls)
+	// The fields are returned in the order they were selected
+	assert.True(strings.Index(w.Body.String(), `"id"`) < strings.Index(w.Body.String(), `"__typename"`))
+}
+
+func TestServerBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	s, _ := newTestServer(t)
+	w := post(s, `[
+		{"query": "{ node { nodeID } }"},
+		{"query": "{ node { unknown } }"},
+		{"query": "{ tx { status } }"},
+		{"query": "{ node { nodeID"}
+	]`)
+	assert.Equal(http.StatusOK, w.Code)
+
+	responses := []*struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []*Error               `json:"errors"`
+	}{}
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &responses))
+	assert.Len(responses, 4)
+
+	assert.Equal(map[string]interface{}{"node": map[string]interface{}{"nodeID": "NodeID-1"}}, responses[0].Data)
+	assert.Empty(responses[0].Errors)
+
+	// Unknown fields are null and reported with their path
+	assert.Equal(map[string]interface{}{"node": map[string]interface{}{"unknown": nil}}, responses[1].Data)
+	assert.Len(responses[1].Errors, 1)
+	assert.Equal([]interface{}{"node", "unknown"}, responses[1].Errors[0].Path)
+
+	// Required arguments must be provided
+	assert.Equal(map[string]interface{}{"tx": nil}, responses[2].Data)
+	assert.Len(responses[2].Errors, 1)
+
+	// Documents that can't be parsed aren't executed
+	assert.Nil(responses[3].Data)
+	assert.Len(responses[3].Errors, 1)
+
+	w = post(s, `[]`)
+	assert.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestServerForwardsAuthorization(t *testing.T) {
+	assert := assert.New(t)
+
+	s, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/?query="+strings.ReplaceAll("{ node { nodeID } }", " ", "+"), nil)
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	assert.Equal(http.StatusOK, w.Code)
+
+	response := &struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []*Error               `json:"errors"`
+	}{}
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), response))
+	assert.Equal(map[string]interface{}{"node": map[string]interface{}{"nodeID": nil}}, response.Data)
+	assert.Len(response.Errors, 1)
+	assert.Contains(response.Errors[0].Message, "unauthorized")
+}
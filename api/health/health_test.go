@@ -272,3 +272,34 @@ func TestDeadlockRegression(t *testing.T) {
 
 	awaitHealthy(h, true)
 }
+
+func TestMonotonicCheckDetails(t *testing.T) {
+	assert := assert.New(t)
+
+	var height utils.AtomicInterface
+	height.SetValue(0)
+	check := CheckerFunc(func() (interface{}, error) {
+		return height.GetValue(), nil
+	})
+
+	h, err := New(prometheus.NewRegistry())
+	assert.NoError(err)
+
+	err = h.RegisterReadinessCheck("check", check)
+	assert.NoError(err)
+
+	h.Start(checkFreq)
+	defer h.Stop()
+
+	awaitReadiness(h)
+
+	// The details of a check that passed keep being refreshed
+	height.SetValue(1)
+	for {
+		results, _ := h.Readiness()
+		if results["check"].Details == 1 {
+			break
+		}
+		time.Sleep(awaitFreq)
+	}
+}
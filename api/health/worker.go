@@ -58,17 +58,17 @@ func (w *worker) RegisterCheck(name string, checker Checker) error {
 	return nil
 }
 
+// RegisterMonotonicCheck registers a check that keeps passing once it has
+// passed. The check is still run afterwards so that its details stay current.
 func (w *worker) RegisterMonotonicCheck(name string, checker Checker) error {
-	var result utils.AtomicInterface
+	var passed utils.AtomicBool
 	return w.RegisterCheck(name, CheckerFunc(func() (interface{}, error) {
-		details := result.GetValue()
-		if details != nil {
+		details, err := checker.HealthCheck()
+		if passed.GetValue() {
 			return details, nil
 		}
-
-		details, err := checker.HealthCheck()
 		if err == nil {
-			result.SetValue(details)
+			passed.SetValue(true)
 		}
 		return details, err
 	}))
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/nat"
 	"github.com/Toinounet21/avalanchego-mod/network"
 	"github.com/Toinounet21/avalanchego-mod/utils/rpc"
 )
@@ -23,9 +24,15 @@ type Client interface {
 	GetNetworkName(context.Context) (string, error)
 	GetBlockchainID(context.Context, string) (ids.ID, error)
 	Peers(context.Context) ([]network.PeerInfo, error)
+	FilteredPeers(context.Context, *PeersArgs) (*PeersReply, error)
+	PeerScores(context.Context, []string) ([]network.PeerScore, error)
+	GetNATStatus(context.Context) (*nat.Status, error)
 	IsBootstrapped(context.Context, string) (bool, error)
 	GetTxFee(context.Context) (*GetTxFeeResponse, error)
+	GetDatabaseConfig(context.Context) (*GetDatabaseConfigReply, error)
 	Uptime(context.Context) (*UptimeResponse, error)
+	UptimeView(context.Context) (*network.UptimeView, error)
+	VersionCensus(context.Context) (*network.VersionCensus, error)
 }
 
 // Client implementation for an Info API Client
@@ -84,6 +91,26 @@ func (c *client) Peers(ctx context.Context) ([]network.PeerInfo, error) {
 	return res.Peers, err
 }
 
+func (c *client) FilteredPeers(ctx context.Context, args *PeersArgs) (*PeersReply, error) {
+	res := &PeersReply{}
+	err := c.requester.SendRequest(ctx, "peers", args, res)
+	return res, err
+}
+
+func (c *client) PeerScores(ctx context.Context, nodeIDs []string) ([]network.PeerScore, error) {
+	res := &PeerScoresReply{}
+	err := c.requester.SendRequest(ctx, "peerScores", &PeerScoresArgs{
+		NodeIDs: nodeIDs,
+	}, res)
+	return res.Peers, err
+}
+
+func (c *client) GetNATStatus(ctx context.Context) (*nat.Status, error) {
+	res := &nat.Status{}
+	err := c.requester.SendRequest(ctx, "getNATStatus", struct{}{}, res)
+	return res, err
+}
+
 func (c *client) IsBootstrapped(ctx context.Context, chainID string) (bool, error) {
 	res := &IsBootstrappedResponse{}
 	err := c.requester.SendRequest(ctx, "isBootstrapped", &IsBootstrappedArgs{
@@ -98,8 +125,26 @@ func (c *client) GetTxFee(ctx context.Context) (*GetTxFeeResponse, error) {
 	return res, err
 }
 
+func (c *client) GetDatabaseConfig(ctx context.Context) (*GetDatabaseConfigReply, error) {
+	res := &GetDatabaseConfigReply{}
+	err := c.requester.SendRequest(ctx, "getDatabaseConfig", struct{}{}, res)
+	return res, err
+}
+
 func (c *client) Uptime(ctx context.Context) (*UptimeResponse, error) {
 	res := &UptimeResponse{}
 	err := c.requester.SendRequest(ctx, "uptime", struct{}{}, res)
 	return res, err
 }
+
+func (c *client) UptimeView(ctx context.Context) (*network.UptimeView, error) {
+	res := &network.UptimeView{}
+	err := c.requester.SendRequest(ctx, "uptimeView", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) VersionCensus(ctx context.Context) (*network.VersionCensus, error) {
+	res := &network.VersionCensus{}
+	err := c.requester.SendRequest(ctx, "versionCensus", struct{}{}, res)
+	return res, err
+}
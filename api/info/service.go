@@ -4,6 +4,7 @@
 package info
 
 import (
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/chains"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/nat"
 	"github.com/Toinounet21/avalanchego-mod/network"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
@@ -36,6 +38,7 @@ type Info struct {
 	vmManager     vms.Manager
 	versionParser version.ApplicationParser
 	validators    validators.Set
+	portMapper    *nat.Mapper
 }
 
 type Parameters struct {
@@ -46,6 +49,10 @@ type Parameters struct {
 	CreateAssetTxFee      uint64
 	CreateSubnetTxFee     uint64
 	CreateBlockchainTxFee uint64
+	// Type of the node's database
+	DatabaseName string
+	// Effective config of the node's database
+	DatabaseConfig []byte
 }
 
 // NewService returns a new admin API service
@@ -57,6 +64,7 @@ func NewService(
 	network network.Network,
 	versionParser version.ApplicationParser,
 	validators validators.Set,
+	portMapper *nat.Mapper,
 ) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := json.NewCodec()
@@ -70,6 +78,7 @@ func NewService(
 		networking:    network,
 		versionParser: versionParser,
 		validators:    validators,
+		portMapper:    portMapper,
 	}, "info"); err != nil {
 		return nil, err
 	}
@@ -173,20 +182,101 @@ func (service *Info) GetBlockchainID(_ *http.Request, args *GetBlockchainIDArgs,
 
 // PeersArgs are the arguments for calling Peers
 type PeersArgs struct {
+	// If non-empty, only the peers with these node IDs are returned. Each
+	// peer is returned at most once.
 	NodeIDs []string `json:"nodeIDs"`
+	// If true, the peers that are still performing the handshake are also
+	// returned. Otherwise, only connected peers are returned.
+	IncludeHandshaking bool `json:"includeHandshaking"`
+	// If true, only the peers that are benched on at least one chain are
+	// returned
+	BenchedOnly bool `json:"benchedOnly"`
+	// If non-empty, only the peers that track this subnet are returned
+	SubnetID string `json:"subnetID"`
+	// If true, only the peers that validate [SubnetID] are returned, or the
+	// peers that validate the primary network if [SubnetID] is empty
+	ValidatorOnly bool `json:"validatorOnly"`
+	// If non-empty, only the peers whose node ID is at or after this one are
+	// returned. Used to fetch the next page of peers.
+	StartNodeID string `json:"startNodeID"`
+	// If > 0, at most this many peers are returned
+	Limit json.Uint32 `json:"limit"`
 }
 
 // PeersReply are the results from calling Peers
 type PeersReply struct {
 	// Number of elements in [Peers]
 	NumPeers json.Uint64 `json:"numPeers"`
-	// Each element is a peer
+	// Each element is a peer, sorted by node ID
 	Peers []network.PeerInfo `json:"peers"`
+	// If non-empty, more peers matched the arguments than [Limit]. Setting
+	// [StartNodeID] to this value returns the next page of peers.
+	NextNodeID string `json:"nextNodeID,omitempty"`
 }
 
-// Peers returns the list of current validators
+// Peers returns the peers this node is connected to that match [args]
 func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply) error {
 	service.log.Debug("Info: Peers called")
+
+	filter := network.PeersFilter{
+		NodeIDs:            make([]ids.ShortID, 0, len(args.NodeIDs)),
+		IncludeHandshaking: args.IncludeHandshaking,
+		BenchedOnly:        args.BenchedOnly,
+		ValidatorOnly:      args.ValidatorOnly,
+	}
+	for _, nodeID := range args.NodeIDs {
+		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		filter.NodeIDs = append(filter.NodeIDs, nID)
+	}
+	if args.SubnetID != "" {
+		subnetID, err := ids.FromString(args.SubnetID)
+		if err != nil {
+			return fmt.Errorf("couldn't parse subnetID %q: %w", args.SubnetID, err)
+		}
+		filter.SubnetID = &subnetID
+	}
+	if args.StartNodeID != "" {
+		startNodeID, err := ids.ShortFromPrefixedString(args.StartNodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return fmt.Errorf("couldn't parse startNodeID %q: %w", args.StartNodeID, err)
+		}
+		filter.StartNodeID = startNodeID
+	}
+	if args.Limit > 0 {
+		// Fetch one more peer to know where the next page starts
+		filter.Limit = int(args.Limit) + 1
+	}
+
+	reply.Peers = service.networking.Peers(filter)
+	if args.Limit > 0 && len(reply.Peers) > int(args.Limit) {
+		reply.NextNodeID = reply.Peers[args.Limit].ID
+		reply.Peers = reply.Peers[:args.Limit]
+	}
+	reply.NumPeers = json.Uint64(len(reply.Peers))
+	return nil
+}
+
+// PeerScoresArgs are the arguments for calling PeerScores
+type PeerScoresArgs struct {
+	// If non-empty, the scores of these nodes are returned, whether or not
+	// they're connected. Otherwise, the scores of the connected peers are
+	// returned.
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// PeerScoresReply are the results from calling PeerScores
+type PeerScoresReply struct {
+	// Each element is a peer's score, sorted by node ID
+	Peers []network.PeerScore `json:"peers"`
+}
+
+// PeerScores returns how this node currently rates its peers
+func (service *Info) PeerScores(_ *http.Request, args *PeerScoresArgs, reply *PeerScoresReply) error {
+	service.log.Debug("Info: PeerScores called")
+
 	nodeIDs := make([]ids.ShortID, 0, len(args.NodeIDs))
 	for _, nodeID := range args.NodeIDs {
 		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
@@ -195,9 +285,20 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 		}
 		nodeIDs = append(nodeIDs, nID)
 	}
+	reply.Peers = service.networking.PeerScores(nodeIDs)
+	return nil
+}
 
-	reply.Peers = service.networking.Peers(nodeIDs)
-	reply.NumPeers = json.Uint64(len(reply.Peers))
+// GetNATStatus returns the state of the port mappings this node maintains on
+// its router, and whether peers can reach this node through them
+func (service *Info) GetNATStatus(_ *http.Request, _ *struct{}, reply *nat.Status) error {
+	service.log.Debug("Info: GetNATStatus called")
+
+	if service.portMapper == nil {
+		*reply = nat.Status{Connectivity: nat.ConnectivityDirect}
+		return nil
+	}
+	*reply = service.portMapper.Status()
 	return nil
 }
 
@@ -258,6 +359,26 @@ func (service *Info) Uptime(_ *http.Request, _ *struct{}, reply *UptimeResponse)
 	return nil
 }
 
+// UptimeView returns this node's uptime as observed by the primary network
+// validators it's connected to, weighted by their stake.
+func (service *Info) UptimeView(_ *http.Request, _ *struct{}, reply *network.UptimeView) error {
+	service.log.Debug("Info: UptimeView called")
+	view, isValidator := service.networking.UptimeView()
+	if !isValidator {
+		return errNotValidator
+	}
+	*reply = view
+	return nil
+}
+
+// VersionCensus returns this node and its connected peers aggregated by
+// version, along with the portion of stake running each version.
+func (service *Info) VersionCensus(_ *http.Request, _ *struct{}, reply *network.VersionCensus) error {
+	service.log.Debug("Info: VersionCensus called")
+	*reply = service.networking.VersionCensus()
+	return nil
+}
+
 type GetTxFeeResponse struct {
 	TxFee json.Uint64 `json:"txFee"`
 	// TODO: remove [CreationTxFee] after enough time for dependencies to update
@@ -267,6 +388,25 @@ type GetTxFeeResponse struct {
 	CreateBlockchainTxFee json.Uint64 `json:"createBlockchainTxFee"`
 }
 
+// GetDatabaseConfigReply is the response from GetDatabaseConfig
+type GetDatabaseConfigReply struct {
+	Name string `json:"name"`
+	// Null if the database runs with its defaults
+	Config stdjson.RawMessage `json:"config"`
+}
+
+// GetDatabaseConfig returns the type of the node's database and the config
+// it runs with
+func (service *Info) GetDatabaseConfig(_ *http.Request, _ *struct{}, reply *GetDatabaseConfigReply) error {
+	service.log.Debug("Info: GetDatabaseConfig called")
+
+	reply.Name = service.DatabaseName
+	if len(service.DatabaseConfig) > 0 {
+		reply.Config = service.DatabaseConfig
+	}
+	return nil
+}
+
 // GetTxFee returns the transaction fee in nAVAX.
 func (service *Info) GetTxFee(_ *http.Request, args *struct{}, reply *GetTxFeeResponse) error {
 	reply.TxFee = json.Uint64(service.TxFee)
@@ -23,8 +23,24 @@ type Client interface {
 	ExportUser(context.Context, api.UserPass) ([]byte, error)
 	// Import [exportedUser] to [importTo]
 	ImportUser(ctx context.Context, importTo api.UserPass, exportedUser []byte) (bool, error)
+	// Returns the given user, encrypted with a key derived from its password
+	ExportEncryptedUser(context.Context, api.UserPass) (*EncryptedUser, error)
+	// Import [exportedUser], which was encrypted by ExportEncryptedUser, to
+	// [importTo]
+	ImportEncryptedUser(ctx context.Context, importTo api.UserPass, exportedUser *EncryptedUser) (bool, error)
 	// Delete the given user
 	DeleteUser(context.Context, api.UserPass) (bool, error)
+	// Returns the secret that the TOTP codes of the given user are to be
+	// generated from
+	EnrollTOTP(context.Context, api.UserPass) (*TOTPEnrollment, error)
+	// Enables TOTP for the given user with a code generated from the secret
+	// returned by EnrollTOTP. Returns the user's recovery codes.
+	ConfirmTOTP(ctx context.Context, user api.UserPass, code string) ([]string, error)
+	// Disables TOTP for the given user with a TOTP or recovery code
+	DisableTOTP(ctx context.Context, user api.UserPass, code string) (bool, error)
+	// Allows the keys of the given user to be exported and used to sign for a
+	// limited time
+	UnlockUser(ctx context.Context, user api.UserPass, code string) (bool, error)
 }
 
 // Client implementation for Avalanche Keystore API Endpoint
@@ -76,8 +92,56 @@ func (c *client) ImportUser(ctx context.Context, user api.UserPass, account []by
 	return res.Success, err
 }
 
+func (c *client) ExportEncryptedUser(ctx context.Context, user api.UserPass) (*EncryptedUser, error) {
+	res := &ExportEncryptedUserReply{}
+	err := c.requester.SendRequest(ctx, "exportEncryptedUser", &user, res)
+	return res.User, err
+}
+
+func (c *client) ImportEncryptedUser(ctx context.Context, user api.UserPass, encryptedUser *EncryptedUser) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "importEncryptedUser", &ImportEncryptedUserArgs{
+		UserPass: user,
+		User:     *encryptedUser,
+	}, res)
+	return res.Success, err
+}
+
 func (c *client) DeleteUser(ctx context.Context, user api.UserPass) (bool, error) {
 	res := &api.SuccessResponse{}
 	err := c.requester.SendRequest(ctx, "deleteUser", &user, res)
 	return res.Success, err
 }
+
+func (c *client) EnrollTOTP(ctx context.Context, user api.UserPass) (*TOTPEnrollment, error) {
+	res := &EnrollTOTPReply{}
+	err := c.requester.SendRequest(ctx, "enrollTOTP", &user, res)
+	return &res.TOTPEnrollment, err
+}
+
+func (c *client) ConfirmTOTP(ctx context.Context, user api.UserPass, code string) ([]string, error) {
+	res := &ConfirmTOTPReply{}
+	err := c.requester.SendRequest(ctx, "confirmTOTP", &TOTPArgs{
+		UserPass: user,
+		Code:     code,
+	}, res)
+	return res.RecoveryCodes, err
+}
+
+func (c *client) DisableTOTP(ctx context.Context, user api.UserPass, code string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "disableTOTP", &TOTPArgs{
+		UserPass: user,
+		Code:     code,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) UnlockUser(ctx context.Context, user api.UserPass, code string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "unlockUser", &TOTPArgs{
+		UserPass: user,
+		Code:     code,
+	}, res)
+	return res.Success, err
+}
@@ -8,21 +8,34 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
 	"github.com/Toinounet21/avalanchego-mod/codec/reflectcodec"
 	"github.com/Toinounet21/avalanchego-mod/utils/units"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 )
 
 const (
 	maxPackerSize  = 1 * units.GiB // max size, in bytes, of something being marshalled by Marshal()
 	maxSliceLength = 256 * 1024
 
-	codecVersion = 0
+	// legacyCodecVersion doesn't serialize the parameters of password hashes
+	legacyCodecVersion = 0
+	codecVersion       = 1
+
+	// codecV1TagName enables serialization with [codecVersion]
+	codecV1TagName = "serializeV1"
 )
 
 var c codec.Manager
 
 func init() {
 	lc := linearcodec.New(reflectcodec.DefaultTagName, maxSliceLength)
+	lcV1 := linearcodec.New(codecV1TagName, maxSliceLength)
 	c = codec.NewManager(maxPackerSize)
-	if err := c.RegisterCodec(codecVersion, lc); err != nil {
-		panic(err)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterCodec(legacyCodecVersion, lc),
+		c.RegisterCodec(codecVersion, lcV1),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
 	}
 }
@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/password"
+
+	jsoncodec "github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+const (
+	encryptedUserVersion = 1
+	encryptedUserKDF     = "argon2id"
+	encryptedUserCipher  = "xchacha20-poly1305"
+	encryptedUserSaltLen = 16
+)
+
+var (
+	errUnknownEncryptedUserVersion = errors.New("unknown encrypted user version")
+	errUnknownKDF                  = errors.New("unknown key derivation function")
+	errUnknownCipher               = errors.New("unknown cipher")
+	errWrongSaltLen                = fmt.Errorf("salt must be %d bytes", encryptedUserSaltLen)
+)
+
+// EncryptedUser is a user, including the data of each of its blockchain
+// databases, encrypted with a key derived from the user's password. It can be
+// decrypted without access to a node, given the password.
+type EncryptedUser struct {
+	// Version of the format. Currently 1.
+	Version jsoncodec.Uint32 `json:"version"`
+	// Key derivation function used to derive the encryption key from the
+	// user's password. Currently "argon2id".
+	KDF       string             `json:"kdf"`
+	KDFParams EncryptedKDFParams `json:"kdfParams"`
+	// Authenticated cipher the user is encrypted with. Currently
+	// "xchacha20-poly1305".
+	Cipher string `json:"cipher"`
+	// Hex encoding of the nonce
+	Nonce string `json:"nonce"`
+	// Hex encoding of the encrypted user, as returned by ExportUser
+	Ciphertext string `json:"ciphertext"`
+}
+
+// EncryptedKDFParams are the parameters the encryption key of an
+// EncryptedUser was derived with
+type EncryptedKDFParams struct {
+	password.HashParams
+	// Hex encoding of the salt
+	Salt string `json:"salt"`
+}
+
+// encryptUser encrypts [userBytes] with a key derived from [pw] with [params]
+func encryptUser(userBytes []byte, pw string, params password.HashParams) (*EncryptedUser, error) {
+	salt := make([]byte, encryptedUserSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(deriveUserKey(pw, salt, params))
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &EncryptedUser{
+		Version: encryptedUserVersion,
+		KDF:     encryptedUserKDF,
+		KDFParams: EncryptedKDFParams{
+			HashParams: params,
+			Salt:       hex.EncodeToString(salt),
+		},
+		Cipher:     encryptedUserCipher,
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, userBytes, nil)),
+	}, nil
+}
+
+// decryptUser returns the user that [encryptedUser] encrypts with [pw]
+func decryptUser(encryptedUser *EncryptedUser, pw string) ([]byte, error) {
+	switch {
+	case encryptedUser.Version != encryptedUserVersion:
+		return nil, fmt.Errorf("%w: %d", errUnknownEncryptedUserVersion, encryptedUser.Version)
+	case encryptedUser.KDF != encryptedUserKDF:
+		return nil, fmt.Errorf("%w: %q", errUnknownKDF, encryptedUser.KDF)
+	case encryptedUser.Cipher != encryptedUserCipher:
+		return nil, fmt.Errorf("%w: %q", errUnknownCipher, encryptedUser.Cipher)
+	}
+
+	// The parameters come from the imported user, so they must be bounded
+	// before a key is derived with them.
+	params := encryptedUser.KDFParams.HashParams
+	if err := params.Verify(); err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(encryptedUser.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode salt: %w", err)
+	}
+	if len(salt) != encryptedUserSaltLen {
+		return nil, errWrongSaltLen
+	}
+	nonce, err := hex.DecodeString(encryptedUser.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(encryptedUser.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode ciphertext: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveUserKey(pw, salt, params))
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("nonce must be %d bytes", aead.NonceSize())
+	}
+	userBytes, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decrypt user: %w", err)
+	}
+	return userBytes, nil
+}
+
+func deriveUserKey(pw string, salt []byte, params password.HashParams) []byte {
+	return argon2.IDKey([]byte(pw), salt, params.Time, params.Memory, params.Threads, chacha20poly1305.KeySize)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/password"
+)
+
+func TestServiceExportImportEncryptedUser(t *testing.T) {
+	assert := assert.New(t)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	s := service{ks: ks.(*keystore)}
+
+	userPass := api.UserPass{
+		Username: "bob",
+		Password: strongPassword,
+	}
+	assert.NoError(s.CreateUser(nil, &userPass, &api.SuccessResponse{}))
+
+	db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+	rawDB, err := ks.GetRawDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	assert.NoError(rawDB.Put([]byte("raw"), []byte("value")))
+
+	exportReply := ExportEncryptedUserReply{}
+	assert.NoError(s.ExportEncryptedUser(nil, &userPass, &exportReply))
+	assert.EqualValues(encryptedUserVersion, exportReply.User.Version)
+	assert.Equal(encryptedUserKDF, exportReply.User.KDF)
+	assert.Equal(password.DefaultHashParams, exportReply.User.KDFParams.HashParams)
+
+	// The exported user is imported from its JSON representation
+	userJSON, err := json.Marshal(exportReply.User)
+	assert.NoError(err)
+	encryptedUser := EncryptedUser{}
+	assert.NoError(json.Unmarshal(userJSON, &encryptedUser))
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+	newS := service{ks: newKS.(*keystore)}
+
+	err = newS.ImportEncryptedUser(nil, &ImportEncryptedUserArgs{
+		UserPass: api.UserPass{
+			Username: "bob",
+			Password: strongPassword + "!",
+		},
+		User: encryptedUser,
+	}, &api.SuccessResponse{})
+	assert.Error(err, "should have failed to decrypt with the wrong password")
+
+	reply := api.SuccessResponse{}
+	assert.NoError(newS.ImportEncryptedUser(nil, &ImportEncryptedUserArgs{
+		UserPass: userPass,
+		User:     encryptedUser,
+	}, &reply))
+	assert.True(reply.Success)
+
+	newDB, err := newKS.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	val, err := newDB.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), val)
+	newRawDB, err := newKS.GetRawDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	val, err = newRawDB.Get([]byte("raw"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), val)
+}
+
+func TestDecryptUserInvalid(t *testing.T) {
+	userBytes := []byte("user")
+	params := password.HashParams{
+		Time:    1,
+		Memory:  1024,
+		Threads: 1,
+	}
+
+	tests := map[string]func(*EncryptedUser){
+		"unknown version": func(u *EncryptedUser) { u.Version++ },
+		"unknown kdf":     func(u *EncryptedUser) { u.KDF = "scrypt" },
+		"unknown cipher":  func(u *EncryptedUser) { u.Cipher = "aes-256-gcm" },
+		"unbounded kdf params": func(u *EncryptedUser) {
+			u.KDFParams.Memory = password.MaxHashMemory + 1
+		},
+		"short salt":         func(u *EncryptedUser) { u.KDFParams.Salt = "00" },
+		"short nonce":        func(u *EncryptedUser) { u.Nonce = "00" },
+		"invalid ciphertext": func(u *EncryptedUser) { u.Ciphertext = "zz" },
+		"tampered ciphertext": func(u *EncryptedUser) {
+			ciphertext, _ := hex.DecodeString(u.Ciphertext)
+			ciphertext[0] ^= 1
+			u.Ciphertext = hex.EncodeToString(ciphertext)
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			encryptedUser, err := encryptUser(userBytes, strongPassword, params)
+			assert.NoError(t, err)
+
+			decrypted, err := decryptUser(encryptedUser, strongPassword)
+			assert.NoError(t, err)
+			assert.Equal(t, userBytes, decrypted)
+
+			mutate(encryptedUser)
+			_, err = decryptUser(encryptedUser, strongPassword)
+			assert.Error(t, err)
+		})
+	}
+}
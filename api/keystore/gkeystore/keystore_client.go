@@ -5,6 +5,9 @@ package gkeystore
 
 import (
 	"context"
+	"sync"
+
+	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
 
@@ -22,6 +25,15 @@ var _ keystore.BlockchainKeystore = &Client{}
 type Client struct {
 	client gkeystoreproto.KeystoreClient
 	broker *plugin.GRPCBroker
+
+	lock sync.Mutex
+	// Username --> connection to the server of that user's database
+	conns map[string]*dbConn
+}
+
+type dbConn struct {
+	serverID uint32
+	conn     *grpc.ClientConn
 }
 
 // NewClient returns a keystore instance connected to a remote keystore instance
@@ -29,6 +41,7 @@ func NewClient(client gkeystoreproto.KeystoreClient, broker *plugin.GRPCBroker)
 	return &Client{
 		client: client,
 		broker: broker,
+		conns:  make(map[string]*dbConn),
 	}
 }
 
@@ -49,11 +62,27 @@ func (c *Client) GetRawDatabase(username, password string) (database.Database, e
 		return nil, err
 	}
 
-	dbConn, err := c.broker.Dial(resp.DbServer)
-	if err != nil {
-		return nil, err
-	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	dbClient := rpcdb.NewClient(rpcdbproto.NewDatabaseClient(dbConn))
-	return dbClient, err
+	// A user's database server can only be dialed once, so the connection is
+	// reused until the server is replaced
+	conn, ok := c.conns[username]
+	if !ok || conn.serverID != resp.DbServer {
+		if ok {
+			// The previous server was stopped for being idle
+			_ = conn.conn.Close()
+		}
+		grpcConn, err := c.broker.Dial(resp.DbServer)
+		if err != nil {
+			delete(c.conns, username)
+			return nil, err
+		}
+		conn = &dbConn{
+			serverID: resp.DbServer,
+			conn:     grpcConn,
+		}
+		c.conns[username] = conn
+	}
+	return rpcdb.NewClient(rpcdbproto.NewDatabaseClient(conn.conn)), nil
 }
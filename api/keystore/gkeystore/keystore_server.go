@@ -5,11 +5,17 @@ package gkeystore
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/Toinounet21/avalanchego-mod/api/keystore"
 	"github.com/Toinounet21/avalanchego-mod/api/keystore/gkeystore/gkeystoreproto"
 	"github.com/Toinounet21/avalanchego-mod/database"
@@ -19,57 +25,186 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/grpcutils"
 )
 
-var _ gkeystoreproto.KeystoreServer = &Server{}
+// DefaultIdleTimeout is how long a user's database server may go without
+// requests before it's stopped
+const DefaultIdleTimeout = 5 * time.Minute
+
+var (
+	errClosed = errors.New("keystore server is closed")
+
+	_ gkeystoreproto.KeystoreServer = &Server{}
+)
 
 // Server is a snow.Keystore that is managed over RPC.
+//
+// Each user's database is served by a single gRPC server, which is shared by
+// all of the GetDatabase calls for that user. The server is stopped once it
+// hasn't received a request for the idle timeout.
 type Server struct {
 	gkeystoreproto.UnimplementedKeystoreServer
-	ks     keystore.BlockchainKeystore
-	broker *plugin.GRPCBroker
+	ks          keystore.BlockchainKeystore
+	broker      *plugin.GRPCBroker
+	idleTimeout time.Duration
+	openServers prometheus.Gauge
+
+	lock   sync.Mutex
+	closed bool
+	// Username --> the server of that user's database
+	dbServers map[string]*dbServer
 }
 
 // NewServer returns a keystore connected to a remote keystore
-func NewServer(ks keystore.BlockchainKeystore, broker *plugin.GRPCBroker) *Server {
-	return &Server{
-		ks:     ks,
-		broker: broker,
+func NewServer(
+	ks keystore.BlockchainKeystore,
+	broker *plugin.GRPCBroker,
+	idleTimeout time.Duration,
+	registerer prometheus.Registerer,
+) (*Server, error) {
+	s := &Server{
+		ks:          ks,
+		broker:      broker,
+		idleTimeout: idleTimeout,
+		openServers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "keystore_db_servers",
+			Help: "Number of keystore user databases that are being served to the VM",
+		}),
+		dbServers: make(map[string]*dbServer),
 	}
+	return s, registerer.Register(s.openServers)
 }
 
 func (s *Server) GetDatabase(
 	_ context.Context,
 	req *gkeystoreproto.GetDatabaseRequest,
 ) (*gkeystoreproto.GetDatabaseResponse, error) {
+	// The password is checked on every call, even if the user's database is
+	// already being served
 	db, err := s.ks.GetRawDatabase(req.Username, req.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	closer := dbCloser{Database: db}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil, errClosed
+	}
+	if server, ok := s.dbServers[req.Username]; ok {
+		server.touch()
+		return &gkeystoreproto.GetDatabaseResponse{DbServer: server.id}, nil
+	}
+
+	server := &dbServer{id: s.broker.NextId()}
+	server.touch()
+	dbServer := rpcdb.NewServer(&sharedDB{Database: db})
 
 	// start the db server
-	dbBrokerID := s.broker.NextId()
-	go s.broker.AcceptAndServe(dbBrokerID, func(opts []grpc.ServerOption) *grpc.Server {
+	go s.broker.AcceptAndServe(server.id, func(opts []grpc.ServerOption) *grpc.Server {
 		opts = append(opts,
 			grpc.MaxRecvMsgSize(math.MaxInt),
 			grpc.MaxSendMsgSize(math.MaxInt),
+			grpc.ChainUnaryInterceptor(server.intercept),
 		)
-		server := grpc.NewServer(opts...)
-		closer.closer.Add(server)
-		db := rpcdb.NewServer(&closer)
-		rpcdbproto.RegisterDatabaseServer(server, db)
-		return server
+		grpcServer := grpc.NewServer(opts...)
+		server.closer.Add(grpcServer)
+		rpcdbproto.RegisterDatabaseServer(grpcServer, dbServer)
+		return grpcServer
 	})
-	return &gkeystoreproto.GetDatabaseResponse{DbServer: dbBrokerID}, nil
+	server.timer = time.AfterFunc(s.idleTimeout, func() {
+		s.stopIfIdle(req.Username, server)
+	})
+	s.dbServers[req.Username] = server
+	s.openServers.Inc()
+	return &gkeystoreproto.GetDatabaseResponse{DbServer: server.id}, nil
 }
 
-type dbCloser struct {
-	database.Database
+// Close stops all of the database servers
+func (s *Server) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	for username, server := range s.dbServers {
+		server.stop()
+		delete(s.dbServers, username)
+	}
+	s.openServers.Set(0)
+}
+
+// Reset stops all of the database servers, which were served to a plugin
+// process that is gone, and serves future databases over [broker].
+func (s *Server) Reset(broker *plugin.GRPCBroker) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for username, server := range s.dbServers {
+		server.stop()
+		delete(s.dbServers, username)
+	}
+	s.openServers.Set(0)
+	s.broker = broker
+}
+
+// stopIfIdle stops [server] if it hasn't received a request for the idle
+// timeout. Otherwise, it's checked again when it could next be idle.
+func (s *Server) stopIfIdle(username string, server *dbServer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.dbServers[username] != server {
+		// The server was already stopped
+		return
+	}
+	if idle := time.Since(server.lastUsed()); idle < s.idleTimeout {
+		server.timer.Reset(s.idleTimeout - idle)
+		return
+	}
+	server.stop()
+	delete(s.dbServers, username)
+	s.openServers.Dec()
+}
+
+type dbServer struct {
+	// Unix time, in nanoseconds, of the last use of the server. Accessed
+	// atomically.
+	lastUsedNanos int64
+
+	id     uint32
 	closer grpcutils.ServerCloser
+	timer  *time.Timer
+}
+
+func (s *dbServer) touch() {
+	atomic.StoreInt64(&s.lastUsedNanos, time.Now().UnixNano())
 }
 
-func (db *dbCloser) Close() error {
-	err := db.Database.Close()
-	db.closer.Stop()
-	return err
+func (s *dbServer) lastUsed() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastUsedNanos))
 }
+
+func (s *dbServer) stop() {
+	s.timer.Stop()
+	s.closer.Stop()
+}
+
+// intercept marks the server as used on every request
+func (s *dbServer) intercept(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	s.touch()
+	return handler(ctx, req)
+}
+
+// sharedDB is a database that is shared by all of the clients of a user's
+// database server. Closing it is a no-op, since one client closing its
+// database must not close it for the others. The server's lifetime is managed
+// by the idle timeout instead.
+type sharedDB struct {
+	database.Database
+}
+
+func (*sharedDB) Close() error { return nil }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/password"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 
 	jsoncodec "github.com/Toinounet21/avalanchego-mod/utils/json"
 )
@@ -26,6 +28,10 @@ import (
 const (
 	// maxUserLen is the maximum allowed length of a username
 	maxUserLen = 1024
+
+	// DefaultTOTPUnlockDuration is how long users with TOTP enabled stay
+	// unlocked by default
+	DefaultTOTPUnlockDuration = 5 * time.Minute
 )
 
 var (
@@ -34,6 +40,7 @@ var (
 
 	usersPrefix = []byte("users")
 	bcsPrefix   = []byte("bcs")
+	totpPrefix  = []byte("totp")
 
 	_ Keystore = &keystore{}
 )
@@ -48,11 +55,14 @@ type Keystore interface {
 
 	// Get a database that is able to read and write unencrypted values from the
 	// underlying database.
+	// VMs sign with the keys they read from this database, so users with TOTP
+	// enabled must be unlocked.
 	GetDatabase(bID ids.ID, username, password string) (*encdb.Database, error)
 
 	// Get the underlying database that is able to read and write encrypted
 	// values. This Database will not perform any encrypting or decrypting of
 	// values and is not recommended to be used when implementing a VM.
+	// Users with TOTP enabled must be unlocked.
 	GetRawDatabase(bID ids.ID, username, password string) (database.Database, error)
 
 	// CreateUser attempts to register this username and password as a new user
@@ -71,23 +81,55 @@ type Keystore interface {
 	ImportUser(username, pw string, user []byte) error
 
 	// ExportUser exports a serialized encoding of a user's information complete
-	// with encrypted database values.
+	// with encrypted database values. Users with TOTP enabled must be unlocked.
 	ExportUser(username, pw string) ([]byte, error)
 
+	// ImportEncryptedUser imports a user that was exported with
+	// ExportEncryptedUser. [pw] is used to decrypt the user.
+	ImportEncryptedUser(username, pw string, user *EncryptedUser) error
+
+	// ExportEncryptedUser exports a user's information, complete with its
+	// database values, encrypted with a key derived from [pw]. Users with TOTP
+	// enabled must be unlocked.
+	ExportEncryptedUser(username, pw string) (*EncryptedUser, error)
+
+	// EnrollTOTP generates the secret that the TOTP codes of [username] are
+	// generated from. TOTP codes aren't required until the secret is
+	// confirmed with ConfirmTOTP.
+	EnrollTOTP(username, pw string) (*TOTPEnrollment, error)
+
+	// ConfirmTOTP enables TOTP for [username] if [code] was generated from the
+	// secret returned by EnrollTOTP. Returns recovery codes, each of which can
+	// be used once in place of a TOTP code.
+	ConfirmTOTP(username, pw, code string) ([]string, error)
+
+	// DisableTOTP disables TOTP for [username]. [code] is either a TOTP code or
+	// a recovery code.
+	DisableTOTP(username, pw, code string) error
+
+	// UnlockUser allows the keys of [username], which has TOTP enabled, to be
+	// exported and used to sign for a limited time. [code] is either a TOTP
+	// code or a recovery code.
+	UnlockUser(username, pw, code string) error
+
+	// ListUsage returns the number of bytes that each user stores and the
+	// quota that they're held to.
+	ListUsage() ([]UserUsage, error)
+
 	// Get the password that is used by [username]. If [username] doesn't exist,
 	// no error is returned and a nil password hash is returned.
 	getPassword(username string) (*password.Hash, error)
 }
 
 type kvPair struct {
-	Key   []byte `serialize:"true"`
-	Value []byte `serialize:"true"`
+	Key   []byte `serialize:"true" serializeV1:"true"`
+	Value []byte `serialize:"true" serializeV1:"true"`
 }
 
 // user describes the full content of a user
 type user struct {
-	password.Hash `serialize:"true"`
-	Data          []kvPair `serialize:"true"`
+	password.Hash `serialize:"true" serializeV1:"true"`
+	Data          []kvPair `serialize:"true" serializeV1:"true"`
 }
 
 // keystore implements keystore management logic
@@ -95,13 +137,40 @@ type keystore struct {
 	lock sync.Mutex
 	log  logging.Logger
 
+	// Parameters that passwords are hashed with. Passwords that were hashed
+	// with other parameters are rehashed when they're next checked.
+	hashParams password.HashParams
+
 	// Key: username
 	// Value: The hash of that user's password
 	usernameToPassword map[string]*password.Hash
 
+	// The maximum number of bytes that a user may store. 0 means unlimited.
+	userQuota uint64
+
+	// Key: username
+	// Value: The number of bytes that the user stores
+	usernameToUsage map[string]uint64
+
+	// Key: username
+	// Value: The second factor of that user
+	usernameToTOTP map[string]*totpState
+
+	// Key: username
+	// Value: The time until which that user, which has TOTP enabled, is
+	// unlocked
+	unlockedUntil map[string]time.Time
+
+	// How long users are unlocked for by UnlockUser
+	totpUnlockDuration time.Duration
+
+	// Useful for faking time in tests
+	clock mockable.Clock
+
 	// Used to persist users and their data
 	userDB database.Database
 	bcDB   database.Database
+	totpDB database.Database
 	//           BaseDB
 	//          /      \
 	//    UserDB        BlockchainDB
@@ -111,13 +180,45 @@ type keystore struct {
 	//          BID  BID  BID
 }
 
+// Config configures a Keystore
+type Config struct {
+	// Parameters that passwords are hashed with
+	HashParams password.HashParams
+	// The maximum number of bytes that a user may store across its blockchain
+	// databases. 0 means unlimited.
+	UserQuota uint64
+	// How long users with TOTP enabled stay unlocked after they're unlocked.
+	// Defaults to [DefaultTOTPUnlockDuration].
+	TOTPUnlockDuration time.Duration
+}
+
 func New(log logging.Logger, dbManager manager.Manager) Keystore {
+	return NewWithHashParams(log, dbManager, password.DefaultHashParams)
+}
+
+// NewWithHashParams returns a Keystore that hashes passwords with [hashParams]
+func NewWithHashParams(log logging.Logger, dbManager manager.Manager, hashParams password.HashParams) Keystore {
+	return NewWithConfig(log, dbManager, Config{HashParams: hashParams})
+}
+
+// NewWithConfig returns a Keystore configured by [config]
+func NewWithConfig(log logging.Logger, dbManager manager.Manager, config Config) Keystore {
 	currentDB := dbManager.Current()
+	if config.TOTPUnlockDuration == 0 {
+		config.TOTPUnlockDuration = DefaultTOTPUnlockDuration
+	}
 	return &keystore{
 		log:                log,
+		hashParams:         config.HashParams,
 		usernameToPassword: make(map[string]*password.Hash),
+		userQuota:          config.UserQuota,
+		usernameToUsage:    make(map[string]uint64),
+		usernameToTOTP:     make(map[string]*totpState),
+		unlockedUntil:      make(map[string]time.Time),
+		totpUnlockDuration: config.TOTPUnlockDuration,
 		userDB:             prefixdb.New(usersPrefix, currentDB.Database),
 		bcDB:               prefixdb.New(bcsPrefix, currentDB.Database),
+		totpDB:             prefixdb.New(totpPrefix, currentDB.Database),
 	}
 }
 
@@ -162,10 +263,18 @@ func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Da
 	if passwordHash == nil || !passwordHash.Check(pw) {
 		return nil, fmt.Errorf("incorrect password for user %q", username)
 	}
+	if err := ks.checkUnlocked(username); err != nil {
+		return nil, err
+	}
+	ks.rehashPassword(username, pw, passwordHash)
 
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
 	bcDB := prefixdb.NewNested(bID[:], userDB)
-	return bcDB, nil
+	return &userDatabase{
+		Database: bcDB,
+		ks:       ks,
+		username: username,
+	}, nil
 }
 
 func (ks *keystore) CreateUser(username, pw string) error {
@@ -192,7 +301,7 @@ func (ks *keystore) CreateUser(username, pw string) error {
 	}
 
 	passwordHash = &password.Hash{}
-	if err := passwordHash.Set(pw); err != nil {
+	if err := passwordHash.SetWithParams(pw, ks.hashParams); err != nil {
 		return err
 	}
 
@@ -236,6 +345,10 @@ func (ks *keystore) DeleteUser(username, pw string) error {
 	if err := userBatch.Delete(userNameBytes); err != nil {
 		return err
 	}
+	totpBatch := ks.totpDB.NewBatch()
+	if err := totpBatch.Delete(userNameBytes); err != nil {
+		return err
+	}
 
 	userDataDB := prefixdb.New(userNameBytes, ks.bcDB)
 	dataBatch := userDataDB.NewBatch()
@@ -253,12 +366,15 @@ func (ks *keystore) DeleteUser(username, pw string) error {
 		return err
 	}
 
-	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
+	if err := atomic.WriteAll(dataBatch, userBatch, totpBatch); err != nil {
 		return err
 	}
 
 	// delete from users map.
 	delete(ks.usernameToPassword, username)
+	delete(ks.usernameToUsage, username)
+	delete(ks.usernameToTOTP, username)
+	delete(ks.unlockedUntil, username)
 	return nil
 }
 
@@ -299,9 +415,19 @@ func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
 	if _, err := c.Unmarshal(userBytes, &userData); err != nil {
 		return err
 	}
+	// The hash parameters come from the imported bytes, so they must be
+	// bounded before the password is checked against them.
+	if err := userData.Hash.Verify(); err != nil {
+		return err
+	}
 	if !userData.Hash.Check(pw) {
 		return fmt.Errorf("incorrect password for user %q", username)
 	}
+	if userData.Hash.NeedsRehash(ks.hashParams) {
+		if err := userData.Hash.SetWithParams(pw, ks.hashParams); err != nil {
+			return err
+		}
+	}
 
 	usrBytes, err := c.Marshal(codecVersion, &userData.Hash)
 	if err != nil {
@@ -315,16 +441,22 @@ func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
 
 	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
 	dataBatch := userDataDB.NewBatch()
+	usage := uint64(0)
 	for _, kvp := range userData.Data {
 		if err := dataBatch.Put(kvp.Key, kvp.Value); err != nil {
 			return fmt.Errorf("error on database put: %w", err)
 		}
+		usage += uint64(len(kvp.Key) + len(kvp.Value))
+	}
+	if err := ks.checkQuota(username, 0, usage); err != nil {
+		return err
 	}
 
 	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
 		return err
 	}
 	ks.usernameToPassword[username] = &userData.Hash
+	ks.usernameToUsage[username] = usage
 	return nil
 }
 
@@ -346,6 +478,10 @@ func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
 	if passwordHash == nil || !passwordHash.Check(pw) {
 		return nil, fmt.Errorf("incorrect password for user %q", username)
 	}
+	if err := ks.checkUnlocked(username); err != nil {
+		return nil, err
+	}
+	passwordHash = ks.rehashPassword(username, pw, passwordHash)
 
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
 
@@ -362,8 +498,55 @@ func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
 		return nil, err
 	}
 
+	// Users whose password was hashed with the legacy parameters are exported
+	// in the legacy format, so that they can be imported by nodes that
+	// predate configurable parameters.
+	exportVersion := uint16(codecVersion)
+	if passwordHash.IsLegacy() {
+		exportVersion = legacyCodecVersion
+	}
+
 	// Return the byte representation of the user
-	return c.Marshal(codecVersion, &userData)
+	return c.Marshal(exportVersion, &userData)
+}
+
+func (ks *keystore) ImportEncryptedUser(username, pw string, encryptedUser *EncryptedUser) error {
+	userBytes, err := decryptUser(encryptedUser, pw)
+	if err != nil {
+		return err
+	}
+	return ks.ImportUser(username, pw, userBytes)
+}
+
+func (ks *keystore) ExportEncryptedUser(username, pw string) (*EncryptedUser, error) {
+	userBytes, err := ks.ExportUser(username, pw)
+	if err != nil {
+		return nil, err
+	}
+	return encryptUser(userBytes, pw, ks.hashParams)
+}
+
+func (ks *keystore) ListUsage() ([]UserUsage, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	it := ks.userDB.NewIterator()
+	defer it.Release()
+
+	usages := []UserUsage{}
+	for it.Next() {
+		username := string(it.Key())
+		usage, err := ks.getUsage(username)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, UserUsage{
+			Username: username,
+			Bytes:    jsoncodec.Uint64(usage),
+			Quota:    jsoncodec.Uint64(ks.userQuota),
+		})
+	}
+	return usages, it.Error()
 }
 
 func (ks *keystore) getPassword(username string) (*password.Hash, error) {
@@ -388,3 +571,30 @@ func (ks *keystore) getPassword(username string) (*password.Hash, error) {
 	_, err = c.Unmarshal(userBytes, passwordHash)
 	return passwordHash, err
 }
+
+// rehashPassword hashes [pw] with the keystore's parameters and persists the
+// new hash if [passwordHash] was computed with other parameters. Assumes [pw]
+// is [username]'s password and that [ks.lock] is held.
+// Returns the hash of [username]'s password after the rehash.
+func (ks *keystore) rehashPassword(username, pw string, passwordHash *password.Hash) *password.Hash {
+	if !passwordHash.NeedsRehash(ks.hashParams) {
+		return passwordHash
+	}
+
+	newPasswordHash := &password.Hash{}
+	if err := newPasswordHash.SetWithParams(pw, ks.hashParams); err != nil {
+		ks.log.Warn("couldn't rehash the password of user %q: %s", username, err)
+		return passwordHash
+	}
+	passwordBytes, err := c.Marshal(codecVersion, newPasswordHash)
+	if err != nil {
+		ks.log.Warn("couldn't rehash the password of user %q: %s", username, err)
+		return passwordHash
+	}
+	if err := ks.userDB.Put([]byte(username), passwordBytes); err != nil {
+		ks.log.Warn("couldn't rehash the password of user %q: %s", username, err)
+		return passwordHash
+	}
+	ks.usernameToPassword[username] = newPasswordHash
+	return newPasswordHash
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+
+	jsoncodec "github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+var (
+	errQuotaExceeded = errors.New("keystore user quota exceeded")
+
+	_ database.Database = &userDatabase{}
+	_ database.Batch    = &userBatch{}
+)
+
+// UserUsage is the number of bytes that a user stores in the keystore
+type UserUsage struct {
+	Username string           `json:"username"`
+	Bytes    jsoncodec.Uint64 `json:"bytes"`
+	// The maximum number of bytes that the user may store. 0 means unlimited.
+	Quota jsoncodec.Uint64 `json:"quota"`
+}
+
+// entrySize returns the number of bytes that a user's blockchain database entry
+// takes up. Blockchain databases prefix their keys with a hash.
+func entrySize(key, value []byte) uint64 {
+	return uint64(hashing.HashLen + len(key) + len(value))
+}
+
+// getUsage returns the number of bytes that [username] stores. The usage is
+// computed from the database the first time it's requested and then tracked
+// in memory. Assumes [ks.lock] is held.
+func (ks *keystore) getUsage(username string) (uint64, error) {
+	if usage, ok := ks.usernameToUsage[username]; ok {
+		return usage, nil
+	}
+
+	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
+	it := userDataDB.NewIterator()
+	defer it.Release()
+
+	usage := uint64(0)
+	for it.Next() {
+		usage += uint64(len(it.Key()) + len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+	ks.usernameToUsage[username] = usage
+	return usage, nil
+}
+
+// checkQuota returns an error if a write that changes the usage of [username]
+// from [usage] to [newUsage] takes the user above the keystore's quota. Writes
+// that don't increase the usage are always allowed, so that users above their
+// quota can free up space.
+func (ks *keystore) checkQuota(username string, usage, newUsage uint64) error {
+	if ks.userQuota == 0 || newUsage <= ks.userQuota || newUsage <= usage {
+		return nil
+	}
+	return fmt.Errorf("%w: user %q would store %d bytes but the quota is %d bytes",
+		errQuotaExceeded,
+		username,
+		newUsage,
+		ks.userQuota,
+	)
+}
+
+// userDatabase is one of a user's blockchain databases. It tracks the number of
+// bytes that the user stores and rejects writes that would exceed the
+// keystore's quota.
+type userDatabase struct {
+	database.Database
+	ks       *keystore
+	username string
+}
+
+// storedSize returns the size of the entry at [key], or 0 if there is none
+func (db *userDatabase) storedSize(key []byte) (uint64, error) {
+	value, err := db.Database.Get(key)
+	switch err {
+	case nil:
+		return entrySize(key, value), nil
+	case database.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// Put implements the Database interface
+func (db *userDatabase) Put(key, value []byte) error {
+	db.ks.lock.Lock()
+	defer db.ks.lock.Unlock()
+
+	usage, err := db.ks.getUsage(db.username)
+	if err != nil {
+		return err
+	}
+	oldSize, err := db.storedSize(key)
+	if err != nil {
+		return err
+	}
+	newUsage := usage - oldSize + entrySize(key, value)
+	if err := db.ks.checkQuota(db.username, usage, newUsage); err != nil {
+		return err
+	}
+	if err := db.Database.Put(key, value); err != nil {
+		return err
+	}
+	db.ks.usernameToUsage[db.username] = newUsage
+	return nil
+}
+
+// Delete implements the Database interface
+func (db *userDatabase) Delete(key []byte) error {
+	db.ks.lock.Lock()
+	defer db.ks.lock.Unlock()
+
+	usage, err := db.ks.getUsage(db.username)
+	if err != nil {
+		return err
+	}
+	oldSize, err := db.storedSize(key)
+	if err != nil {
+		return err
+	}
+	if err := db.Database.Delete(key); err != nil {
+		return err
+	}
+	db.ks.usernameToUsage[db.username] = usage - oldSize
+	return nil
+}
+
+// NewBatch implements the Database interface
+func (db *userDatabase) NewBatch() database.Batch {
+	return &userBatch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// userBatch is a batch of writes to a userDatabase. The quota is checked when
+// the batch is written.
+type userBatch struct {
+	database.Batch
+	db     *userDatabase
+	writes []keyValue
+}
+
+// Put implements the Batch interface
+func (b *userBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{
+		key:   append([]byte(nil), key...),
+		value: append([]byte(nil), value...),
+	})
+	return b.Batch.Put(key, value)
+}
+
+// Delete implements the Batch interface
+func (b *userBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{
+		key:    append([]byte(nil), key...),
+		delete: true,
+	})
+	return b.Batch.Delete(key)
+}
+
+// Write implements the Batch interface
+func (b *userBatch) Write() error {
+	b.db.ks.lock.Lock()
+	defer b.db.ks.lock.Unlock()
+
+	usage, err := b.db.ks.getUsage(b.db.username)
+	if err != nil {
+		return err
+	}
+
+	// Key --> size of the entry at that key after the batch is written
+	newSizes := make(map[string]uint64, len(b.writes))
+	for _, kv := range b.writes {
+		if kv.delete {
+			newSizes[string(kv.key)] = 0
+		} else {
+			newSizes[string(kv.key)] = entrySize(kv.key, kv.value)
+		}
+	}
+	newUsage := usage
+	for key, newSize := range newSizes {
+		oldSize, err := b.db.storedSize([]byte(key))
+		if err != nil {
+			return err
+		}
+		newUsage = newUsage - oldSize + newSize
+	}
+
+	if err := b.db.ks.checkQuota(b.db.username, usage, newUsage); err != nil {
+		return err
+	}
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	b.db.ks.usernameToUsage[b.db.username] = newUsage
+	return nil
+}
+
+// Reset implements the Batch interface
+func (b *userBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.Batch.Reset()
+}
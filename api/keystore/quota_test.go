@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/password"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+func newQuotaTestKeystore(t *testing.T, dbManager manager.Manager, quota uint64) *keystore {
+	if dbManager == nil {
+		var err error
+		dbManager, err = manager.NewManagerFromDBs([]*manager.VersionedDatabase{
+			{
+				Database: memdb.New(),
+				Version:  version.DefaultVersion1_0_0,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewWithConfig(logging.NoLog{}, dbManager, Config{
+		HashParams: password.HashParams{
+			Time:    1,
+			Memory:  1024,
+			Threads: 1,
+		},
+		UserQuota: quota,
+	}).(*keystore)
+}
+
+func TestKeystoreUserQuota(t *testing.T) {
+	assert := assert.New(t)
+
+	// Each entry with a 1 byte key and a 10 byte value takes up 43 bytes
+	entry := uint64(hashing.HashLen + 1 + 10)
+	ks := newQuotaTestKeystore(t, nil, 3*entry)
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+
+	chain0, err := ks.GetRawDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	chain1, err := ks.GetRawDatabase(ids.GenerateTestID(), "bob", strongPassword)
+	assert.NoError(err)
+
+	value := make([]byte, 10)
+	assert.NoError(chain0.Put([]byte{0}, value))
+	assert.NoError(chain1.Put([]byte{0}, value))
+	// Overwriting an entry with one of the same size doesn't use more space
+	assert.NoError(chain0.Put([]byte{0}, value))
+
+	// The quota applies across all of the user's blockchains
+	batch := chain1.NewBatch()
+	assert.NoError(batch.Put([]byte{1}, value))
+	assert.NoError(batch.Put([]byte{2}, value))
+	err = batch.Write()
+	assert.True(errors.Is(err, errQuotaExceeded))
+	has, err := chain1.Has([]byte{1})
+	assert.NoError(err)
+	assert.False(has)
+
+	assert.NoError(chain1.Put([]byte{1}, value))
+	err = chain0.Put([]byte{1}, value)
+	assert.True(errors.Is(err, errQuotaExceeded))
+
+	// Deleting entries frees up space
+	assert.NoError(chain1.Delete([]byte{1}))
+	assert.NoError(chain0.Put([]byte{1}, value))
+
+	usages, err := ks.ListUsage()
+	assert.NoError(err)
+	assert.Equal([]UserUsage{{
+		Username: "bob",
+		Bytes:    3 * 43,
+		Quota:    3 * 43,
+	}}, usages)
+}
+
+func TestKeystoreUsageAfterRestart(t *testing.T) {
+	assert := assert.New(t)
+
+	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
+		{
+			Database: memdb.New(),
+			Version:  version.DefaultVersion1_0_0,
+		},
+	})
+	assert.NoError(err)
+	ks := newQuotaTestKeystore(t, dbManager, 0)
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+	assert.NoError(ks.CreateUser("alice", strongPassword))
+
+	db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+	userBytes, err := ks.ExportUser("bob", strongPassword)
+	assert.NoError(err)
+
+	// The usage is recomputed from the database
+	ks = newQuotaTestKeystore(t, dbManager, 0)
+	usages, err := ks.ListUsage()
+	assert.NoError(err)
+	assert.Len(usages, 2)
+	bobUsage := uint64(0)
+	for _, usage := range usages {
+		switch usage.Username {
+		case "alice":
+			assert.Zero(usage.Bytes)
+		case "bob":
+			bobUsage = uint64(usage.Bytes)
+		}
+	}
+	assert.NotZero(bobUsage)
+
+	// Users that would exceed the quota can't be imported
+	ks = newQuotaTestKeystore(t, nil, bobUsage-1)
+	err = ks.ImportUser("bob", strongPassword, userBytes)
+	assert.True(errors.Is(err, errQuotaExceeded))
+
+	ks = newQuotaTestKeystore(t, nil, bobUsage)
+	assert.NoError(ks.ImportUser("bob", strongPassword, userBytes))
+	usages, err = ks.ListUsage()
+	assert.NoError(err)
+	assert.Len(usages, 1)
+	assert.EqualValues(bobUsage, usages[0].Bytes)
+}
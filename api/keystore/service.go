@@ -98,6 +98,82 @@ func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Expor
 	return nil
 }
 
+type ImportEncryptedUserArgs struct {
+	// The username and password of the user being imported
+	api.UserPass
+	// The encrypted user, as returned by ExportEncryptedUser
+	User EncryptedUser `json:"user"`
+}
+
+func (s *service) ImportEncryptedUser(_ *http.Request, args *ImportEncryptedUserArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: ImportEncryptedUser called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.ImportEncryptedUser(args.Username, args.Password, &args.User)
+}
+
+type ExportEncryptedUserReply struct {
+	// The user, encrypted with a key derived from its password
+	User *EncryptedUser `json:"user"`
+}
+
+func (s *service) ExportEncryptedUser(_ *http.Request, args *api.UserPass, reply *ExportEncryptedUserReply) error {
+	s.ks.log.Debug("Keystore: ExportEncryptedUser called for %s", args.Username)
+
+	var err error
+	reply.User, err = s.ks.ExportEncryptedUser(args.Username, args.Password)
+	return err
+}
+
+type EnrollTOTPReply struct {
+	TOTPEnrollment
+}
+
+func (s *service) EnrollTOTP(_ *http.Request, args *api.UserPass, reply *EnrollTOTPReply) error {
+	s.ks.log.Debug("Keystore: EnrollTOTP called for %s", args.Username)
+
+	enrollment, err := s.ks.EnrollTOTP(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	reply.TOTPEnrollment = *enrollment
+	return nil
+}
+
+type TOTPArgs struct {
+	// The username and password of the user
+	api.UserPass
+	// A TOTP code or, except for ConfirmTOTP, a recovery code
+	Code string `json:"code"`
+}
+
+type ConfirmTOTPReply struct {
+	// Codes that can each be used once in place of a TOTP code
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+func (s *service) ConfirmTOTP(_ *http.Request, args *TOTPArgs, reply *ConfirmTOTPReply) error {
+	s.ks.log.Debug("Keystore: ConfirmTOTP called for %s", args.Username)
+
+	var err error
+	reply.RecoveryCodes, err = s.ks.ConfirmTOTP(args.Username, args.Password, args.Code)
+	return err
+}
+
+func (s *service) DisableTOTP(_ *http.Request, args *TOTPArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: DisableTOTP called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.DisableTOTP(args.Username, args.Password, args.Code)
+}
+
+func (s *service) UnlockUser(_ *http.Request, args *TOTPArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: UnlockUser called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.UnlockUser(args.Username, args.Password, args.Code)
+}
+
 // CreateTestKeystore returns a new keystore that can be utilized for testing
 func CreateTestKeystore() (Keystore, error) {
 	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
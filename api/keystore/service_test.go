@@ -10,9 +10,16 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/password"
+	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
 // strongPassword defines a password used for the following tests that
@@ -427,3 +434,125 @@ func TestServiceDeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestKeystoreRehashPassword(t *testing.T) {
+	assert := assert.New(t)
+
+	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
+		{
+			Database: memdb.New(),
+			Version:  version.DefaultVersion1_0_0,
+		},
+	})
+	assert.NoError(err)
+	hashParams := password.HashParams{
+		Time:    2,
+		Memory:  1024,
+		Threads: 1,
+	}
+	ks := NewWithHashParams(logging.NoLog{}, dbManager, hashParams).(*keystore)
+
+	// Store a user the way it was stored before the hash parameters became
+	// configurable
+	legacyHash := password.Hash{}
+	assert.NoError(legacyHash.SetWithParams(strongPassword, password.LegacyHashParams))
+	legacyHash.Params = password.HashParams{}
+	legacyHashBytes, err := c.Marshal(legacyCodecVersion, &legacyHash)
+	assert.NoError(err)
+	assert.Len(legacyHashBytes, 2+32+16)
+	assert.NoError(ks.userDB.Put([]byte("bob"), legacyHashBytes))
+
+	_, err = ks.GetDatabase(ids.Empty, "bob", "wrong password")
+	assert.Error(err)
+	_, err = ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+
+	// The password was rehashed with the keystore's parameters
+	newHashBytes, err := ks.userDB.Get([]byte("bob"))
+	assert.NoError(err)
+	newHash := password.Hash{}
+	version, err := c.Unmarshal(newHashBytes, &newHash)
+	assert.NoError(err)
+	assert.EqualValues(codecVersion, version)
+	assert.Equal(hashParams, newHash.Params)
+	assert.True(newHash.Check(strongPassword))
+
+	// The user can still be used after a restart
+	ks = NewWithHashParams(logging.NoLog{}, dbManager, hashParams).(*keystore)
+	_, err = ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+	_, err = ks.GetDatabase(ids.Empty, "bob", "wrong password")
+	assert.Error(err)
+}
+
+func TestKeystoreImportUserInvalidHashParams(t *testing.T) {
+	assert := assert.New(t)
+
+	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
+		{
+			Database: memdb.New(),
+			Version:  version.DefaultVersion1_0_0,
+		},
+	})
+	assert.NoError(err)
+	ks := New(logging.NoLog{}, dbManager).(*keystore)
+
+	// An imported user must not be able to make the node hash a password with
+	// arbitrary parameters
+	userData := user{}
+	assert.NoError(userData.Hash.Set(strongPassword))
+	for _, params := range []password.HashParams{
+		{Time: 1, Memory: 1024, Threads: 0},
+		{Time: 1, Memory: password.MaxHashMemory + 1, Threads: 1},
+		{Time: password.MaxHashTime + 1, Memory: 1024, Threads: 1},
+	} {
+		userData.Hash.Params = params
+		userBytes, err := c.Marshal(codecVersion, &userData)
+		assert.NoError(err)
+		assert.Error(ks.ImportUser("bob", strongPassword, userBytes))
+	}
+}
+
+func TestKeystoreExportImportHashParams(t *testing.T) {
+	assert := assert.New(t)
+
+	newKeystore := func(hashParams password.HashParams) *keystore {
+		dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
+			{
+				Database: memdb.New(),
+				Version:  version.DefaultVersion1_0_0,
+			},
+		})
+		assert.NoError(err)
+		return NewWithHashParams(logging.NoLog{}, dbManager, hashParams).(*keystore)
+	}
+	hashParams := password.HashParams{
+		Time:    2,
+		Memory:  1024,
+		Threads: 1,
+	}
+
+	// Users hashed with the legacy parameters are exported in the legacy
+	// format
+	legacyKS := newKeystore(password.LegacyHashParams)
+	assert.NoError(legacyKS.CreateUser("bob", strongPassword))
+	userBytes, err := legacyKS.ExportUser("bob", strongPassword)
+	assert.NoError(err)
+	version, err := c.Unmarshal(userBytes, &user{})
+	assert.NoError(err)
+	assert.EqualValues(legacyCodecVersion, version)
+
+	// Imported users are rehashed with the importing keystore's parameters
+	ks := newKeystore(hashParams)
+	assert.NoError(ks.ImportUser("bob", strongPassword, userBytes))
+	assert.Equal(hashParams, ks.usernameToPassword["bob"].Params)
+	_, err = ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+
+	// Users hashed with other parameters are exported in the current format
+	userBytes, err = ks.ExportUser("bob", strongPassword)
+	assert.NoError(err)
+	version, err = c.Unmarshal(userBytes, &user{})
+	assert.NoError(err)
+	assert.EqualValues(codecVersion, version)
+}
@@ -0,0 +1,264 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/utils/totp"
+)
+
+const (
+	// totpIssuer is the issuer shown by authenticator apps
+	totpIssuer = "Avalanche"
+	// totpSkew is the number of time steps that a code may be off by, to allow
+	// for clock skew between the node and the authenticator
+	totpSkew = 1
+
+	numRecoveryCodes = 10
+	// recoveryCodeLen is the number of random bytes of a recovery code
+	recoveryCodeLen = 5
+)
+
+var (
+	errTOTPEnabled     = errors.New("TOTP is already enabled")
+	errTOTPNotEnabled  = errors.New("TOTP isn't enabled")
+	errTOTPNotEnrolled = errors.New("TOTP enrollment wasn't started")
+	errInvalidTOTPCode = errors.New("invalid TOTP code")
+	errUserLocked      = errors.New("user must be unlocked with a TOTP code")
+
+	recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// totpState describes the second factor of a user
+type totpState struct {
+	Secret []byte `serializeV1:"true"`
+	// False until a code generated from [Secret] is confirmed. Until then, the
+	// second factor isn't required.
+	Enabled bool `serializeV1:"true"`
+	// Time step of the last code used. Codes can't be reused.
+	LastStep uint64 `serializeV1:"true"`
+	// Hashes of the recovery codes that haven't been used yet
+	RecoveryCodes [][]byte `serializeV1:"true"`
+}
+
+// TOTPEnrollment is the secret that a user's TOTP codes are generated from
+type TOTPEnrollment struct {
+	// Base32 encoding of the secret, to be entered in authenticator apps
+	Secret string `json:"secret"`
+	// otpauth URI of the secret, to be shown as a QR code
+	URI string `json:"uri"`
+}
+
+func (ks *keystore) EnrollTOTP(username, pw string) (*TOTPEnrollment, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if err := ks.checkPassword(username, pw); err != nil {
+		return nil, err
+	}
+	state, err := ks.getTOTP(username)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Enabled {
+		return nil, fmt.Errorf("%w for user %q", errTOTPEnabled, username)
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.putTOTP(username, &totpState{Secret: secret}); err != nil {
+		return nil, err
+	}
+	return &TOTPEnrollment{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(totpIssuer, username, secret),
+	}, nil
+}
+
+func (ks *keystore) ConfirmTOTP(username, pw, code string) ([]string, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if err := ks.checkPassword(username, pw); err != nil {
+		return nil, err
+	}
+	state, err := ks.getTOTP(username)
+	switch {
+	case err != nil:
+		return nil, err
+	case state == nil:
+		return nil, fmt.Errorf("%w for user %q", errTOTPNotEnrolled, username)
+	case state.Enabled:
+		return nil, fmt.Errorf("%w for user %q", errTOTPEnabled, username)
+	}
+
+	step, ok := totp.Verify(state.Secret, code, ks.clock.Time(), totpSkew)
+	if !ok {
+		return nil, fmt.Errorf("%w for user %q", errInvalidTOTPCode, username)
+	}
+
+	newState := &totpState{
+		Secret:        state.Secret,
+		Enabled:       true,
+		LastStep:      step,
+		RecoveryCodes: make([][]byte, numRecoveryCodes),
+	}
+	recoveryCodes := make([]string, numRecoveryCodes)
+	for i := range recoveryCodes {
+		codeBytes := make([]byte, recoveryCodeLen)
+		if _, err := rand.Read(codeBytes); err != nil {
+			return nil, err
+		}
+		recoveryCodes[i] = recoveryCodeEncoding.EncodeToString(codeBytes)
+		newState.RecoveryCodes[i] = hashRecoveryCode(recoveryCodes[i])
+	}
+	return recoveryCodes, ks.putTOTP(username, newState)
+}
+
+func (ks *keystore) DisableTOTP(username, pw, code string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if err := ks.checkPassword(username, pw); err != nil {
+		return err
+	}
+	if err := ks.checkSecondFactor(username, code); err != nil {
+		return err
+	}
+	if err := ks.totpDB.Delete([]byte(username)); err != nil {
+		return err
+	}
+	delete(ks.usernameToTOTP, username)
+	delete(ks.unlockedUntil, username)
+	return nil
+}
+
+func (ks *keystore) UnlockUser(username, pw, code string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if err := ks.checkPassword(username, pw); err != nil {
+		return err
+	}
+	if err := ks.checkSecondFactor(username, code); err != nil {
+		return err
+	}
+	ks.unlockedUntil[username] = ks.clock.Time().Add(ks.totpUnlockDuration)
+	return nil
+}
+
+// checkPassword returns an error if [pw] isn't [username]'s password.
+// Assumes [ks.lock] is held.
+func (ks *keystore) checkPassword(username, pw string) error {
+	passwordHash, err := ks.getPassword(username)
+	if err != nil {
+		return err
+	}
+	if passwordHash == nil || !passwordHash.Check(pw) {
+		return fmt.Errorf("incorrect password for user %q", username)
+	}
+	return nil
+}
+
+// checkSecondFactor returns an error unless [code] is either an unused TOTP
+// code or an unused recovery code of [username], which must have TOTP enabled.
+// Recovery codes can only be used once.
+// Assumes [ks.lock] is held.
+func (ks *keystore) checkSecondFactor(username, code string) error {
+	state, err := ks.getTOTP(username)
+	if err != nil {
+		return err
+	}
+	if state == nil || !state.Enabled {
+		return fmt.Errorf("%w for user %q", errTOTPNotEnabled, username)
+	}
+
+	newState := *state
+	if step, ok := totp.Verify(state.Secret, code, ks.clock.Time(), totpSkew); ok && step > state.LastStep {
+		newState.LastStep = step
+		return ks.putTOTP(username, &newState)
+	}
+
+	codeHash := hashRecoveryCode(code)
+	for i, recoveryCodeHash := range state.RecoveryCodes {
+		if subtle.ConstantTimeCompare(codeHash, recoveryCodeHash) != 1 {
+			continue
+		}
+		ks.log.Info("recovery code of user %q used, %d remaining", username, len(state.RecoveryCodes)-1)
+		newState.RecoveryCodes = make([][]byte, 0, len(state.RecoveryCodes)-1)
+		newState.RecoveryCodes = append(newState.RecoveryCodes, state.RecoveryCodes[:i]...)
+		newState.RecoveryCodes = append(newState.RecoveryCodes, state.RecoveryCodes[i+1:]...)
+		return ks.putTOTP(username, &newState)
+	}
+	return fmt.Errorf("%w for user %q", errInvalidTOTPCode, username)
+}
+
+// checkUnlocked returns an error if [username] has TOTP enabled and wasn't
+// recently unlocked with UnlockUser.
+// Assumes [ks.lock] is held.
+func (ks *keystore) checkUnlocked(username string) error {
+	state, err := ks.getTOTP(username)
+	if err != nil {
+		return err
+	}
+	if state == nil || !state.Enabled || ks.clock.Time().Before(ks.unlockedUntil[username]) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", errUserLocked, username)
+}
+
+// getTOTP returns the second factor of [username], or nil if it never
+// enrolled.
+// Assumes [ks.lock] is held.
+func (ks *keystore) getTOTP(username string) (*totpState, error) {
+	if state, exists := ks.usernameToTOTP[username]; exists {
+		return state, nil
+	}
+
+	stateBytes, err := ks.totpDB.Get([]byte(username))
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &totpState{}
+	if _, err := c.Unmarshal(stateBytes, state); err != nil {
+		return nil, err
+	}
+	ks.usernameToTOTP[username] = state
+	return state, nil
+}
+
+// Assumes [ks.lock] is held.
+func (ks *keystore) putTOTP(username string, state *totpState) error {
+	stateBytes, err := c.Marshal(codecVersion, state)
+	if err != nil {
+		return err
+	}
+	if err := ks.totpDB.Put([]byte(username), stateBytes); err != nil {
+		return err
+	}
+	ks.usernameToTOTP[username] = state
+	return nil
+}
+
+// hashRecoveryCode returns the hash that [code] is stored as. Recovery codes
+// are random, so they don't need a slow hash.
+func hashRecoveryCode(code string) []byte {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	hash := sha256.Sum256([]byte(code))
+	return hash[:]
+}
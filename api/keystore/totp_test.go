@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/totp"
+)
+
+func TestTOTP(t *testing.T) {
+	assert := assert.New(t)
+
+	ksIntf, err := CreateTestKeystore()
+	assert.NoError(err)
+	ks := ksIntf.(*keystore)
+	now := time.Unix(1234567890, 0)
+	ks.clock.Set(now)
+
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+	bID := ids.GenerateTestID()
+
+	_, err = ks.EnrollTOTP("bob", "wrong password")
+	assert.Error(err)
+	enrollment, err := ks.EnrollTOTP("bob", strongPassword)
+	assert.NoError(err)
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	assert.NoError(err)
+	code := func() string { return totp.Code(secret, totp.Step(ks.clock.Time())) }
+
+	// The second factor isn't required until it's confirmed
+	_, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+
+	_, err = ks.ConfirmTOTP("bob", strongPassword, "000000")
+	assert.True(errors.Is(err, errInvalidTOTPCode))
+	recoveryCodes, err := ks.ConfirmTOTP("bob", strongPassword, code())
+	assert.NoError(err)
+	assert.Len(recoveryCodes, numRecoveryCodes)
+
+	// Keys can't be used or exported until the user is unlocked
+	_, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.True(errors.Is(err, errUserLocked))
+	_, err = ks.ExportUser("bob", strongPassword)
+	assert.True(errors.Is(err, errUserLocked))
+	_, err = ks.ExportEncryptedUser("bob", strongPassword)
+	assert.True(errors.Is(err, errUserLocked))
+	users, err := ks.ListUsers()
+	assert.NoError(err)
+	assert.Equal([]string{"bob"}, users)
+
+	// Codes can't be reused
+	err = ks.UnlockUser("bob", strongPassword, code())
+	assert.True(errors.Is(err, errInvalidTOTPCode))
+
+	ks.clock.Set(now.Add(totp.Period))
+	assert.Error(ks.UnlockUser("bob", "wrong password", code()))
+	assert.NoError(ks.UnlockUser("bob", strongPassword, code()))
+	_, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	_, err = ks.ExportUser("bob", strongPassword)
+	assert.NoError(err)
+
+	// The user locks again once the unlock duration elapses
+	ks.clock.Set(now.Add(totp.Period + DefaultTOTPUnlockDuration))
+	_, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.True(errors.Is(err, errUserLocked))
+
+	// Recovery codes can be used once in place of TOTP codes
+	assert.NoError(ks.UnlockUser("bob", strongPassword, recoveryCodes[0]))
+	err = ks.UnlockUser("bob", strongPassword, recoveryCodes[0])
+	assert.True(errors.Is(err, errInvalidTOTPCode))
+
+	// The second factor is persisted
+	ks.usernameToTOTP = make(map[string]*totpState)
+	ks.unlockedUntil = make(map[string]time.Time)
+	_, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.True(errors.Is(err, errUserLocked))
+
+	_, err = ks.EnrollTOTP("bob", strongPassword)
+	assert.True(errors.Is(err, errTOTPEnabled))
+	assert.NoError(ks.DisableTOTP("bob", strongPassword, recoveryCodes[1]))
+	_, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	err = ks.UnlockUser("bob", strongPassword, code())
+	assert.True(errors.Is(err, errTOTPNotEnabled))
+}
+
+func TestDeleteUserDeletesTOTP(t *testing.T) {
+	assert := assert.New(t)
+
+	ksIntf, err := CreateTestKeystore()
+	assert.NoError(err)
+	ks := ksIntf.(*keystore)
+
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+	enrollment, err := ks.EnrollTOTP("bob", strongPassword)
+	assert.NoError(err)
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	assert.NoError(err)
+	_, err = ks.ConfirmTOTP("bob", strongPassword, totp.Code(secret, totp.Step(ks.clock.Time())))
+	assert.NoError(err)
+
+	assert.NoError(ks.DeleteUser("bob", strongPassword))
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+	_, err = ks.GetDatabase(ids.GenerateTestID(), "bob", strongPassword)
+	assert.NoError(err)
+}
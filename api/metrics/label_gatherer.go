@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+var (
+	errDuplicatedLabels = errors.New("duplicated labels")
+
+	_ LabelGatherer = &labelGatherer{}
+)
+
+// LabelGatherer extends the Gatherer interface by allowing additional
+// gatherers to be registered. Rather than prefixing the metrics of each
+// gatherer with a namespace, metrics with the same name are merged into one
+// metric family and told apart by the labels their gatherer was registered
+// with.
+type LabelGatherer interface {
+	prometheus.Gatherer
+
+	// Register adds the outputs of [gatherer] to the results of future calls to
+	// Gather with the provided [labels] added to the metrics.
+	Register(labels prometheus.Labels, gatherer prometheus.Gatherer) error
+}
+
+type labeledGatherer struct {
+	labels   []*dto.LabelPair
+	gatherer prometheus.Gatherer
+}
+
+type labelGatherer struct {
+	lock sync.RWMutex
+	// Key is the string representation of the labels of the gatherer
+	keys      map[string]struct{}
+	gatherers []labeledGatherer
+}
+
+func NewLabelGatherer() LabelGatherer {
+	return &labelGatherer{
+		keys: make(map[string]struct{}),
+	}
+}
+
+func (g *labelGatherer) Gather() ([]*dto.MetricFamily, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	families := make(map[string]*dto.MetricFamily)
+	for _, labeled := range g.gatherers {
+		mfs, err := labeled.gatherer.Gather()
+		if err != nil {
+			return nil, err
+		}
+		for _, mf := range mfs {
+			for _, metric := range mf.Metric {
+				if err := addLabels(metric, labeled.labels); err != nil {
+					return nil, fmt.Errorf("couldn't label metric %q: %w", mf.GetName(), err)
+				}
+			}
+
+			existing, exists := families[mf.GetName()]
+			if !exists {
+				families[mf.GetName()] = mf
+				continue
+			}
+			if existing.GetType() != mf.GetType() {
+				return nil, fmt.Errorf("metric %q was gathered with types %s and %s", mf.GetName(), existing.GetType(), mf.GetType())
+			}
+			existing.Metric = append(existing.Metric, mf.Metric...)
+		}
+	}
+
+	results := make([]*dto.MetricFamily, 0, len(families))
+	for _, mf := range families {
+		results = append(results, mf)
+	}
+	sortMetrics(results)
+	return results, nil
+}
+
+func (g *labelGatherer) Register(labels prometheus.Labels, gatherer prometheus.Gatherer) error {
+	pairs := make([]*dto.LabelPair, 0, len(labels))
+	for name, value := range labels {
+		name, value := name, value
+		pairs = append(pairs, &dto.LabelPair{
+			Name:  &name,
+			Value: &value,
+		})
+	}
+	sortLabels(pairs)
+
+	keyParts := make([]string, len(pairs))
+	for i, pair := range pairs {
+		keyParts[i] = fmt.Sprintf("%s=%q", pair.GetName(), pair.GetValue())
+	}
+	key := strings.Join(keyParts, ",")
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if _, exists := g.keys[key]; exists {
+		return errDuplicatedLabels
+	}
+
+	g.keys[key] = struct{}{}
+	g.gatherers = append(g.gatherers, labeledGatherer{
+		labels:   pairs,
+		gatherer: gatherer,
+	})
+	return nil
+}
+
+// addLabels adds [labels] to [metric], which must not already have any of the
+// labels.
+func addLabels(metric *dto.Metric, labels []*dto.LabelPair) error {
+	for _, label := range labels {
+		for _, existing := range metric.Label {
+			if existing.GetName() == label.GetName() {
+				return fmt.Errorf("label %q is already set", label.GetName())
+			}
+		}
+		metric.Label = append(metric.Label, label)
+	}
+	sortLabels(metric.Label)
+	return nil
+}
+
+func sortLabels(labels []*dto.LabelPair) {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].GetName() < labels[j].GetName()
+	})
+}
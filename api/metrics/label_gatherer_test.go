@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelGathererDuplicatedLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewLabelGatherer()
+	og := NewOptionalGatherer()
+
+	err := g.Register(prometheus.Labels{"chain": "X", "vm": "avm"}, og)
+	assert.NoError(err)
+
+	err = g.Register(prometheus.Labels{"vm": "avm", "chain": "X"}, og)
+	assert.Equal(errDuplicatedLabels, err)
+
+	err = g.Register(prometheus.Labels{"chain": "P", "vm": "platform"}, og)
+	assert.NoError(err)
+}
+
+func TestLabelGathererAddedError(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewLabelGatherer()
+
+	expected := errors.New(":(")
+	tg := &testGatherer{
+		err: expected,
+	}
+
+	err := g.Register(prometheus.Labels{"chain": "X"}, tg)
+	assert.NoError(err)
+
+	mfs, err := g.Gather()
+	assert.Equal(expected, err)
+	assert.Empty(mfs)
+}
+
+func TestLabelGathererMergesFamilies(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewLabelGatherer()
+
+	for _, chain := range []string{"X", "P"} {
+		reg := prometheus.NewRegistry()
+		counter := prometheus.NewCounter(prometheus.CounterOpts{
+			Name: hello,
+			Help: hello,
+		})
+		assert.NoError(reg.Register(counter))
+		counter.Inc()
+		assert.NoError(g.Register(prometheus.Labels{"chain": chain}, reg))
+	}
+
+	mfs, err := g.Gather()
+	assert.NoError(err)
+	assert.Len(mfs, 1)
+	assert.Equal(hello, mfs[0].GetName())
+	assert.Len(mfs[0].Metric, 2)
+
+	chains := []string{}
+	for _, metric := range mfs[0].Metric {
+		assert.Len(metric.Label, 1)
+		assert.Equal("chain", metric.Label[0].GetName())
+		chains = append(chains, metric.Label[0].GetValue())
+	}
+	assert.ElementsMatch([]string{"X", "P"}, chains)
+}
+
+func TestLabelGathererConflictingLabel(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewLabelGatherer()
+
+	reg := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:        hello,
+		Help:        hello,
+		ConstLabels: prometheus.Labels{"chain": world},
+	})
+	assert.NoError(reg.Register(counter))
+	assert.NoError(g.Register(prometheus.Labels{"chain": "X"}, reg))
+
+	_, err := g.Gather()
+	assert.Error(err)
+}
+
+func TestLabelGathererInconsistentTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewLabelGatherer()
+
+	counterReg := prometheus.NewRegistry()
+	assert.NoError(counterReg.Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: hello,
+		Help: hello,
+	})))
+	assert.NoError(g.Register(prometheus.Labels{"chain": "X"}, counterReg))
+
+	gaugeReg := prometheus.NewRegistry()
+	assert.NoError(gaugeReg.Register(prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: hello,
+		Help: hello,
+	})))
+	assert.NoError(g.Register(prometheus.Labels{"chain": "P"}, gaugeReg))
+
+	_, err := g.Gather()
+	assert.Error(err)
+}
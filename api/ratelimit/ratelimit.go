@@ -0,0 +1,228 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Toinounet21/avalanchego-mod/api/server"
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/utils/rpc"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+const (
+	// KeyByIP limits the requests of each client IP
+	KeyByIP = "ip"
+	// KeyByToken limits the requests of each auth token. Requests without a
+	// token are limited by client IP.
+	KeyByToken = "token"
+
+	// Default maximum number of clients whose request rate is tracked per rule
+	defaultMaxClients = 10000
+
+	// Maximum size of a request body whose methods are parsed
+	maxBodySize = 4 * units.MiB
+
+	tokenPrefix = "Bearer "
+)
+
+var (
+	errInvalidKeyBy = errors.New("keyBy must be \"ip\" or \"token\"")
+	errInvalidRate  = errors.New("rate must be > 0")
+	errInvalidBurst = errors.New("burst must be > 0")
+
+	_ server.Wrapper = &limiter{}
+)
+
+// Rule limits the rate of the requests that match it
+type Rule struct {
+	// Path of the endpoint the rule applies to, e.g. "/ext/bc/X". If empty,
+	// the rule applies to all the endpoints. An endpoint must be referred to
+	// by the same path as the requests, aliases aren't resolved.
+	Endpoint string `json:"endpoint"`
+	// JSON-RPC method the rule applies to, e.g. "avm.getUTXOs". If empty, the
+	// rule applies to all the requests to [Endpoint].
+	Method string `json:"method"`
+	// Number of requests per second each client may make
+	Rate float64 `json:"rate"`
+	// Number of requests each client may make at once
+	Burst int `json:"burst"`
+}
+
+func (r *Rule) String() string {
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = "*"
+	}
+	if r.Method == "" {
+		return endpoint
+	}
+	return fmt.Sprintf("%s %s", endpoint, r.Method)
+}
+
+// Config of the API rate limiter
+type Config struct {
+	// [KeyByIP] or [KeyByToken]. Defaults to [KeyByIP].
+	KeyBy string `json:"keyBy"`
+	// Maximum number of clients whose request rate is tracked per rule. The
+	// least recently seen clients are forgotten first.
+	MaxClients int    `json:"maxClients"`
+	Rules      []Rule `json:"rules"`
+}
+
+// Verify returns an error if [c] is invalid
+func (c *Config) Verify() error {
+	switch c.KeyBy {
+	case "", KeyByIP, KeyByToken:
+	default:
+		return errInvalidKeyBy
+	}
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		switch {
+		case rule.Rate <= 0:
+			return fmt.Errorf("rule %q: %w", rule, errInvalidRate)
+		case rule.Burst <= 0:
+			return fmt.Errorf("rule %q: %w", rule, errInvalidBurst)
+		}
+	}
+	return nil
+}
+
+// New returns a wrapper that limits the rate of the requests to the API
+// server according to [config]. Requests that exceed the rate of a rule they
+// match are rejected with status 429.
+//
+// Every rule that a request matches is applied, so a rule without a method
+// can limit the overall rate of an endpoint while rules with methods limit the
+// rate of the expensive methods. Each element of a JSON-RPC batch counts as a
+// request.
+func New(config Config, namespace string, registerer prometheus.Registerer) (server.Wrapper, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	if config.MaxClients <= 0 {
+		config.MaxClients = defaultMaxClients
+	}
+
+	l := &limiter{
+		keyByToken: config.KeyBy == KeyByToken,
+		throttled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "throttled_requests",
+				Help:      "Number of API requests rejected for exceeding the rate of a rule",
+			},
+			[]string{"rule"},
+		),
+	}
+	for _, rule := range config.Rules {
+		l.rules = append(l.rules, &ruleLimiter{
+			rule:     rule,
+			name:     rule.String(),
+			limiters: &cache.LRU{Size: config.MaxClients},
+		})
+	}
+	return l, registerer.Register(l.throttled)
+}
+
+type limiter struct {
+	keyByToken bool
+	rules      []*ruleLimiter
+	clock      mockable.Clock
+
+	throttled *prometheus.CounterVec
+}
+
+type ruleLimiter struct {
+	rule Rule
+	name string
+
+	lock sync.Mutex
+	// Client key --> *rate.Limiter
+	limiters *cache.LRU
+}
+
+// allow returns true if [key] may make [n] requests that match this rule at
+// [now]
+func (r *ruleLimiter) allow(key string, n int, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var limiter *rate.Limiter
+	if limiterIntf, ok := r.limiters.Get(key); ok {
+		limiter = limiterIntf.(*rate.Limiter)
+	} else {
+		limiter = rate.NewLimiter(rate.Limit(r.rule.Rate), r.rule.Burst)
+		r.limiters.Put(key, limiter)
+	}
+	return limiter.AllowN(now, n)
+}
+
+// WrapHandler implements the server.Wrapper interface
+func (l *limiter) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := strings.TrimRight(r.URL.Path, "/")
+
+		var methods map[string]int
+		for _, rule := range l.rules {
+			if rule.rule.Endpoint != "" && rule.rule.Endpoint != endpoint {
+				continue
+			}
+
+			n := 1
+			if rule.rule.Method != "" {
+				if methods == nil {
+					calls, err := rpc.ReadMethods(r, maxBodySize)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					methods = make(map[string]int, len(calls))
+					for _, method := range calls {
+						methods[method]++
+					}
+				}
+				n = methods[rule.rule.Method]
+				if n == 0 {
+					continue
+				}
+			}
+
+			if !rule.allow(l.key(r), n, l.clock.Time()) {
+				l.throttled.WithLabelValues(rule.name).Inc()
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, fmt.Sprintf("rate limit of %q exceeded", rule.name), http.StatusTooManyRequests)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// key returns the key of the client that made [r]
+func (l *limiter) key(r *http.Request) string {
+	if l.keyByToken {
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, tokenPrefix) {
+			return "token:" + strings.TrimPrefix(header, tokenPrefix)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
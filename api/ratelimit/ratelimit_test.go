@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ratelimit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(t *testing.T, config Config) (*limiter, http.Handler) {
+	wrapper, err := New(config, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := wrapper.(*limiter)
+	l.clock.Set(time.Unix(0, 0))
+	handler := l.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The handler can still read the body
+		body, _ := ioutil.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	return l, handler
+}
+
+func send(handler http.Handler, path, remoteAddr, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestLimiterEndpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	l, handler := newTestLimiter(t, Config{
+		Rules: []Rule{{
+			Endpoint: "/ext/admin",
+			Rate:     1,
+			Burst:    2,
+		}},
+	})
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(http.StatusOK, send(handler, "/ext/admin", "1.2.3.4:1", "", "").Code)
+	}
+	assert.Equal(http.StatusTooManyRequests, send(handler, "/ext/admin/", "1.2.3.4:2", "", "").Code)
+	assert.Equal(1.0, testutil.ToFloat64(l.throttled.WithLabelValues("/ext/admin")))
+
+	// Other clients and endpoints aren't limited
+	assert.Equal(http.StatusOK, send(handler, "/ext/admin", "5.6.7.8:1", "", "").Code)
+	assert.Equal(http.StatusOK, send(handler, "/ext/info", "1.2.3.4:1", "", "").Code)
+
+	// The client regains a request per second
+	l.clock.Set(l.clock.Time().Add(time.Second))
+	assert.Equal(http.StatusOK, send(handler, "/ext/admin", "1.2.3.4:1", "", "").Code)
+	assert.Equal(http.StatusTooManyRequests, send(handler, "/ext/admin", "1.2.3.4:1", "", "").Code)
+}
+
+func TestLimiterMethod(t *testing.T) {
+	assert := assert.New(t)
+
+	_, handler := newTestLimiter(t, Config{
+		KeyBy: KeyByToken,
+		Rules: []Rule{{
+			Endpoint: "/ext/bc/X",
+			Method:   "avm.getUTXOs",
+			Rate:     1,
+			Burst:    2,
+		}},
+	})
+
+	getUTXOs := `{"jsonrpc": "2.0", "method": "avm.getUTXOs", "id": 1}`
+	w := send(handler, "/ext/bc/X", "1.2.3.4:1", "a", getUTXOs)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal(getUTXOs, w.Body.String())
+
+	// Other methods aren't limited
+	for i := 0; i < 3; i++ {
+		assert.Equal(http.StatusOK, send(handler, "/ext/bc/X", "1.2.3.4:1", "a", `{"method": "avm.getTx"}`).Code)
+	}
+
+	// Each call of a batch counts
+	batch := `[` + getUTXOs + `,` + getUTXOs + `]`
+	assert.Equal(http.StatusTooManyRequests, send(handler, "/ext/bc/X", "1.2.3.4:1", "a", batch).Code)
+
+	// Clients are keyed by token
+	assert.Equal(http.StatusOK, send(handler, "/ext/bc/X", "1.2.3.4:1", "b", batch).Code)
+	assert.Equal(http.StatusOK, send(handler, "/ext/bc/X", "1.2.3.4:1", "a", getUTXOs).Code)
+	assert.Equal(http.StatusTooManyRequests, send(handler, "/ext/bc/X", "1.2.3.4:1", "a", getUTXOs).Code)
+}
+
+func TestConfigVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&Config{}).Verify())
+	assert.ErrorIs((&Config{KeyBy: "cookie"}).Verify(), errInvalidKeyBy)
+	assert.ErrorIs((&Config{Rules: []Rule{{Rate: 0, Burst: 1}}}).Verify(), errInvalidRate)
+	assert.ErrorIs((&Config{Rules: []Rule{{Rate: 1, Burst: 0}}}).Verify(), errInvalidBurst)
+}
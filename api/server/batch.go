@@ -0,0 +1,172 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// JSON-RPC 2.0 error codes
+	invalidRequestCode = -32600
+	internalErrorCode  = -32603
+)
+
+type batchError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type batchErrorResponse struct {
+	Version string          `json:"jsonrpc"`
+	Error   batchError      `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func newBatchErrorResponse(id json.RawMessage, code int, msg string) json.RawMessage {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	// Marshalling can't fail as [id] was unmarshalled from valid JSON
+	response, _ := json.Marshal(&batchErrorResponse{
+		Version: "2.0",
+		Error: batchError{
+			Code:    code,
+			Message: msg,
+		},
+		ID: id,
+	})
+	return response
+}
+
+// batchMiddleware serves the JSON-RPC 2.0 batches sent to [handler]. The
+// requests of a batch are served one after the other by [handler], as if they
+// had been sent separately, and their responses are returned in an array.
+// Notifications aren't answered. Batches of more than [maxBatchSize] requests
+// are rejected.
+//
+// Requests that aren't batches are passed through to [handler].
+func batchMiddleware(handler http.Handler, maxBatchSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		// Only peek at the body, so that requests that aren't batches aren't
+		// buffered
+		body := bufio.NewReader(r.Body)
+		if !isBatch(body) {
+			r.Body = readCloser{Reader: body, Closer: r.Body}
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		var requests []json.RawMessage
+		if err := json.NewDecoder(body).Decode(&requests); err != nil {
+			http.Error(w, fmt.Sprintf("couldn't parse batch: %s", err), http.StatusBadRequest)
+			return
+		}
+		if len(requests) == 0 {
+			writeBatchResponse(w, newBatchErrorResponse(nil, invalidRequestCode, "empty batch"))
+			return
+		}
+		if len(requests) > maxBatchSize {
+			writeBatchResponse(w, newBatchErrorResponse(nil, invalidRequestCode, fmt.Sprintf("batches are limited to %d requests", maxBatchSize)))
+			return
+		}
+
+		responses := make([]json.RawMessage, 0, len(requests))
+		for _, request := range requests {
+			if response := serveBatchedRequest(handler, r, request); response != nil {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) == 0 {
+			// The batch only contained notifications
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeBatchResponse(w, responses)
+	})
+}
+
+// isBatch returns true if the first non-whitespace character of [body] opens
+// a JSON array
+func isBatch(body *bufio.Reader) bool {
+	for i := 1; ; i++ {
+		b, err := body.Peek(i)
+		if err != nil || len(b) < i {
+			return false
+		}
+		switch b[i-1] {
+		case ' ', '\t', '\n', '\r':
+		case '[':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// serveBatchedRequest serves [request], which is an element of the batch sent
+// in [r], and returns its response. Returns nil if the request is a
+// notification.
+func serveBatchedRequest(handler http.Handler, r *http.Request, request json.RawMessage) json.RawMessage {
+	fields := struct {
+		ID json.RawMessage `json:"id"`
+	}{}
+	if err := json.Unmarshal(request, &fields); err != nil {
+		return newBatchErrorResponse(nil, invalidRequestCode, "batched requests must be objects")
+	}
+
+	subRequest := r.Clone(r.Context())
+	subRequest.Body = io.NopCloser(bytes.NewReader(request))
+	subRequest.ContentLength = int64(len(request))
+
+	w := &bufferedResponseWriter{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+	handler.ServeHTTP(w, subRequest)
+
+	response := bytes.TrimSpace(w.body.Bytes())
+	switch {
+	case json.Valid(response) && len(response) > 0:
+		return response
+	case w.status >= 200 && w.status <= 299 && len(response) == 0:
+		return nil
+	default:
+		return newBatchErrorResponse(fields.ID, internalErrorCode, strings.TrimSpace(string(response)))
+	}
+}
+
+func writeBatchResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	// If writing fails, the connection is broken and there is no one to
+	// report it to
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bufferedResponseWriter buffers the response to a batched request
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header         { return w.header }
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *bufferedResponseWriter) WriteHeader(status int)      { w.status = status }
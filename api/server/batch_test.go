@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// EchoService is exported so that it can be registered as an RPC service
+type EchoService struct{}
+
+type EchoArgs struct {
+	Message string `json:"message"`
+}
+
+type EchoReply struct {
+	Message string `json:"message"`
+}
+
+func (*EchoService) Echo(_ *http.Request, args *EchoArgs, reply *EchoReply) error {
+	if args.Message == "" {
+		return errors.New("empty message")
+	}
+	reply.Message = args.Message
+	return nil
+}
+
+func newBatchTestHandler(t *testing.T, maxBatchSize int) http.Handler {
+	server := rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	if err := server.RegisterService(&EchoService{}, "echo"); err != nil {
+		t.Fatal(err)
+	}
+	return batchMiddleware(server, maxBatchSize)
+}
+
+func postJSON(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatchMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := newBatchTestHandler(t, 10)
+
+	w := postJSON(handler, ` [
+		{"jsonrpc": "2.0", "method": "echo.echo", "params": {"message": "a"}, "id": 1},
+		{"jsonrpc": "2.0", "method": "echo.echo", "params": {"message": "b"}},
+		{"jsonrpc": "2.0", "method": "echo.echo", "params": {}, "id": "2"},
+		5
+	]`)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.JSONEq(`[
+		{"jsonrpc": "2.0", "result": {"message": "a"}, "id": 1},
+		{"jsonrpc": "2.0", "error": {"code": -32000, "message": "empty message", "data": null}, "id": "2"},
+		{"jsonrpc": "2.0", "error": {"code": -32600, "message": "batched requests must be objects"}, "id": null}
+	]`, w.Body.String())
+
+	// Requests that aren't batched are passed through
+	w = postJSON(handler, `{"jsonrpc": "2.0", "method": "echo.echo", "params": {"message": "c"}, "id": 3}`)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.JSONEq(`{"jsonrpc": "2.0", "result": {"message": "c"}, "id": 3}`, w.Body.String())
+
+	// Notifications aren't answered
+	w = postJSON(handler, `[{"jsonrpc": "2.0", "method": "echo.echo", "params": {"message": "d"}}]`)
+	assert.Equal(http.StatusNoContent, w.Code)
+	assert.Empty(w.Body.String())
+
+	w = postJSON(handler, `[]`)
+	assert.Contains(w.Body.String(), "empty batch")
+
+	w = postJSON(handler, `[`+strings.Repeat(`{"jsonrpc": "2.0", "method": "echo.echo", "id": 1},`, 10)+`{}]`)
+	assert.Contains(w.Body.String(), "batches are limited to 10 requests")
+}
@@ -15,6 +15,7 @@ import (
 var (
 	errUnknownBaseURL  = errors.New("unknown base url")
 	errUnknownEndpoint = errors.New("unknown endpoint")
+	errUnknownAlias    = errors.New("unknown alias")
 )
 
 type router struct {
@@ -135,3 +136,60 @@ func (r *router) AddAlias(base string, aliases ...string) error {
 	}
 	return err
 }
+
+// RemoveAlias removes [aliases], which must have been added with AddAlias
+func (r *router) RemoveAlias(base string, aliases ...string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	baseAliases := r.aliases[base]
+	removed := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		found := false
+		for _, baseAlias := range baseAliases {
+			if baseAlias == alias {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s isn't an alias of %s", errUnknownAlias, alias, base)
+		}
+		removed[alias] = true
+	}
+
+	remainingAliases := make([]string, 0, len(baseAliases))
+	for _, baseAlias := range baseAliases {
+		if !removed[baseAlias] {
+			remainingAliases = append(remainingAliases, baseAlias)
+		}
+	}
+	if len(remainingAliases) == 0 {
+		delete(r.aliases, base)
+	} else {
+		r.aliases[base] = remainingAliases
+	}
+
+	for alias := range removed {
+		delete(r.reservedRoutes, alias)
+		delete(r.routes, alias)
+	}
+
+	// Routes can't be removed from a mux router, so the remaining routes are
+	// added to a new one
+	newRouter := mux.NewRouter()
+	for routeBase, endpoints := range r.routes {
+		for endpoint, handler := range endpoints {
+			url := routeBase + endpoint
+			if route := newRouter.Handle(url, handler); route != nil {
+				route.Name(url)
+			} else {
+				return fmt.Errorf("failed to create new route for %s", url)
+			}
+		}
+	}
+	r.router = newRouter
+	return nil
+}
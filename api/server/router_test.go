@@ -4,7 +4,9 @@
 package server
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -75,3 +77,39 @@ func TestBlock(t *testing.T) {
 		t.Fatalf("Permanently locked %s", "1")
 	}
 }
+
+func TestRemoveAlias(t *testing.T) {
+	r := newRouter()
+
+	handler1 := &testHandler{}
+	if err := r.AddRouter("/1", "", handler1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddAlias("/1", "/2", "/3"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.RemoveAlias("/1", "/4"); !errors.Is(err, errUnknownAlias) {
+		t.Fatalf("Expected %s but got %v", errUnknownAlias, err)
+	}
+	if err := r.RemoveAlias("/1", "/2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.GetHandler("/2", ""); err == nil {
+		t.Fatalf("Should have removed %s", "/2")
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/2", nil))
+	if handler1.called {
+		t.Fatalf("Removed alias %s was routed", "/2")
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/3", nil))
+	if !handler1.called {
+		t.Fatalf("Alias %s should still be routed", "/3")
+	}
+
+	// The removed alias can be reused
+	if err := r.AddAlias("/1", "/2"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -37,6 +37,22 @@ var (
 	_ RouteAdder = &Server{}
 )
 
+// CORSConfig is the Cross-Origin Resource Sharing policy of the API server,
+// which lets browsers call the APIs from pages served by other origins
+type CORSConfig struct {
+	// Origins that may call the APIs. "*" allows all origins.
+	AllowedOrigins []string `json:"allowedOrigins"`
+	// Methods that may be used to call the APIs
+	AllowedMethods []string `json:"allowedMethods"`
+	// Headers that may be sent by the callers. "*" allows all headers.
+	AllowedHeaders []string `json:"allowedHeaders"`
+	// Headers of the responses that the callers may read
+	ExposedHeaders []string `json:"exposedHeaders"`
+	// How long the result of a preflight request may be cached. If 0, the
+	// browser's default is used.
+	MaxAge time.Duration `json:"maxAge"`
+}
+
 type RouteAdder interface {
 	AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, loggingWriter io.Writer) error
 }
@@ -55,6 +71,10 @@ type Server struct {
 
 	shutdownTimeout time.Duration
 
+	// Maximum number of requests in a JSON-RPC batch. If 0, batches aren't
+	// supported.
+	maxBatchSize int
+
 	// Maps endpoints to handlers
 	router *router
 
@@ -67,8 +87,9 @@ func (s *Server) Initialize(
 	factory logging.Factory,
 	host string,
 	port uint16,
-	allowedOrigins []string,
+	corsConfig CORSConfig,
 	shutdownTimeout time.Duration,
+	maxBatchSize int,
 	nodeID ids.ShortID,
 	wrappers ...Wrapper,
 ) {
@@ -77,12 +98,17 @@ func (s *Server) Initialize(
 	s.listenHost = host
 	s.listenPort = port
 	s.shutdownTimeout = shutdownTimeout
+	s.maxBatchSize = maxBatchSize
 	s.router = newRouter()
 
-	s.log.Info("API created with allowed origins: %v", allowedOrigins)
+	s.log.Info("API created with allowed origins: %v", corsConfig.AllowedOrigins)
 
 	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
+		AllowedOrigins:   corsConfig.AllowedOrigins,
+		AllowedMethods:   corsConfig.AllowedMethods,
+		AllowedHeaders:   corsConfig.AllowedHeaders,
+		ExposedHeaders:   corsConfig.ExposedHeaders,
+		MaxAge:           int(corsConfig.MaxAge / time.Second),
 		AllowCredentials: true,
 	}).Handler(s.router)
 	gzipHandler := gziphandler.GzipHandler(corsHandler)
@@ -99,6 +125,10 @@ func (s *Server) Initialize(
 	}
 }
 
+// Handler returns the handler of the API server, which serves the requests
+// of all the routes
+func (s *Server) Handler() http.Handler { return s.handler }
+
 // Dispatch starts the API server
 func (s *Server) Dispatch() error {
 	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
@@ -204,12 +234,14 @@ func (s *Server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.ConsensusC
 	// Apply logging middleware
 	h := handlers.CombinedLoggingHandler(loggingWriter, handler.Handler)
 	// Apply middleware to grab/release chain's lock before/after calling API method
-	h, err := lockMiddleware(h, handler.LockOptions, &ctx.Lock)
+	h, err := lockMiddleware(h, handler.LockOptions, ctx.Lock.As(snow.APICaller))
 	if err != nil {
 		return err
 	}
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
+	// Apply middleware to serve batched calls one at a time
+	h = s.batchMiddleware(h)
 	return s.router.AddRouter(url, endpoint, h)
 }
 
@@ -224,11 +256,28 @@ func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
+	// Apply middleware to serve batched calls one at a time
+	h = s.batchMiddleware(h)
 	return s.router.AddRouter(url, endpoint, h)
 }
 
+// batchMiddleware wraps a handler to serve JSON-RPC batches, if they're
+// enabled
+func (s *Server) batchMiddleware(handler http.Handler) http.Handler {
+	if s.maxBatchSize <= 0 {
+		return handler
+	}
+	return batchMiddleware(handler, s.maxBatchSize)
+}
+
+type rwLocker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+}
+
 // Wraps a handler by grabbing and releasing a lock before calling the handler.
-func lockMiddleware(handler http.Handler, lockOption common.LockOption, lock *sync.RWMutex) (http.Handler, error) {
+func lockMiddleware(handler http.Handler, lockOption common.LockOption, lock rwLocker) (http.Handler, error) {
 	switch lockOption {
 	case common.WriteLock:
 		return middlewareHandler{
@@ -285,6 +334,21 @@ func (s *Server) AddAliasesWithReadLock(endpoint string, aliases ...string) erro
 	return s.AddAliases(endpoint, aliases...)
 }
 
+// RemoveAliasesWithReadLock removes aliases that were registered to the server
+// assuming the http read lock is currently held.
+func (s *Server) RemoveAliasesWithReadLock(endpoint string, aliases ...string) error {
+	// See AddAliasesWithReadLock
+	s.router.lock.RUnlock()
+	defer s.router.lock.RLock()
+
+	url := fmt.Sprintf("%s/%s", baseURL, endpoint)
+	endpoints := make([]string, len(aliases))
+	for i, alias := range aliases {
+		endpoints[i] = fmt.Sprintf("%s/%s", baseURL, alias)
+	}
+	return s.router.RemoveAlias(url, endpoints...)
+}
+
 // Shutdown this server
 func (s *Server) Shutdown() error {
 	if s.srv == nil {
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+func TestCORS(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Server{}
+	s.Initialize(
+		logging.NoLog{},
+		nil,
+		"127.0.0.1",
+		0,
+		CORSConfig{
+			AllowedOrigins: []string{"https://wallet.example"},
+			AllowedMethods: []string{http.MethodPost},
+			AllowedHeaders: []string{"Content-Type", "Authorization"},
+			ExposedHeaders: []string{"node-id"},
+			MaxAge:         time.Minute,
+		},
+		time.Second,
+		0,
+		ids.ShortEmpty,
+	)
+	handler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+	}
+	assert.NoError(s.AddRoute(handler, &sync.RWMutex{}, "info", "", ioutil.Discard))
+
+	preflight := func(origin, method, headers string) http.Header {
+		req := httptest.NewRequest(http.MethodOptions, "/ext/info", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+		w := httptest.NewRecorder()
+		s.Handler().ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	header := preflight("https://wallet.example", http.MethodPost, "Authorization")
+	assert.Equal("https://wallet.example", header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(http.MethodPost, header.Get("Access-Control-Allow-Methods"))
+	assert.Equal("Authorization", header.Get("Access-Control-Allow-Headers"))
+	assert.Equal("60", header.Get("Access-Control-Max-Age"))
+
+	// Disallowed origins, methods and headers aren't allowed
+	assert.Empty(preflight("https://other.example", http.MethodPost, "").Get("Access-Control-Allow-Origin"))
+	assert.Empty(preflight("https://wallet.example", http.MethodPut, "").Get("Access-Control-Allow-Origin"))
+	assert.Empty(preflight("https://wallet.example", http.MethodPost, "X-Custom").Get("Access-Control-Allow-Origin"))
+
+	req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+	req.Header.Set("Origin", "https://wallet.example")
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, req)
+	assert.Equal("https://wallet.example", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal("Node-Id", w.Header().Get("Access-Control-Expose-Headers"))
+}
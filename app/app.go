@@ -26,6 +26,14 @@ type App interface {
 	ExitCode() (int, error)
 }
 
+// Reloader is an application whose configuration can be reloaded while it runs
+type Reloader interface {
+	// Reload re-reads the configuration of the application and applies the
+	// changes that don't require a restart. Reload should only be called after
+	// [Start].
+	Reload()
+}
+
 func Run(app App) int {
 	// start running the application
 	if err := app.Start(); err != nil {
@@ -46,12 +54,26 @@ func Run(app App) int {
 		return nil
 	})
 
+	// reload the configuration of the application on SIGHUP, if it can be
+	// reloaded
+	reloads := make(chan os.Signal, 1)
+	if reloader, ok := app.(Reloader); ok {
+		signal.Notify(reloads, syscall.SIGHUP)
+		go func() {
+			for range reloads {
+				reloader.Reload()
+			}
+		}()
+	}
+
 	// wait for the app to exit and get the exit code response
 	exitCode, err := app.ExitCode()
 
-	// shut down the signal go routine
+	// shut down the signal go routines
 	signal.Stop(signals)
 	close(signals)
+	signal.Stop(reloads)
+	close(reloads)
 
 	// if there was an error closing or running the application, report that error
 	if eg.Wait() != nil || err != nil {
@@ -16,6 +16,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/nat"
 	"github.com/Toinounet21/avalanchego-mod/node"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/crashreport"
 	"github.com/Toinounet21/avalanchego-mod/utils/dynamicip"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/perms"
@@ -35,7 +36,8 @@ var (
 	stakingPortName = fmt.Sprintf("%s-staking", constants.AppName)
 	httpPortName    = fmt.Sprintf("%s-http", constants.AppName)
 
-	_ app.App = &process{}
+	_ app.App      = &process{}
+	_ app.Reloader = &process{}
 )
 
 // process is a wrapper around a node that runs in this process
@@ -63,6 +65,11 @@ func (p *process) Start() error {
 		return fmt.Errorf("failed to restrict the permissions of the log directory with: %w", err)
 	}
 
+	if p.config.CrashReportConfig.Enabled {
+		logFile := filepath.Join(p.config.LoggingConfig.Directory, "main.log")
+		p.config.LoggingConfig.PanicHandler = crashreport.New(p.config.CrashReportConfig, version.CurrentApp.String(), logFile)
+	}
+
 	// we want to create the logger after the plugin has started the app
 	logFactory := logging.NewFactory(p.config.LoggingConfig)
 	log, err := logFactory.Make("main")
@@ -121,6 +128,7 @@ func (p *process) Start() error {
 	}
 
 	mapper := nat.NewPortMapper(log, p.config.Nat)
+	p.config.PortMapper = mapper
 
 	// Open staking port we want for NAT Traversal to have the external port
 	// (config.IP.Port) to connect to our internal listening port
@@ -184,6 +192,7 @@ func (p *process) Start() error {
 		defer func() {
 			mapper.UnmapAllPorts()
 			externalIPUpdater.Stop()
+			log.Info("shutdown: closing the database")
 			if err := dbManager.Close(); err != nil {
 				log.Warn("failed to close the node's DB: %s", err)
 			}
@@ -213,3 +222,9 @@ func (p *process) ExitCode() (int, error) {
 	p.exitWG.Wait()
 	return p.node.ExitCode(), nil
 }
+
+// Reload reloads the config of the node. The fields that were applied and the
+// ones that require a restart, as well as any error, are logged by the node.
+func (p *process) Reload() {
+	_, _, _ = p.node.ReloadConfig()
+}
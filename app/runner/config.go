@@ -7,6 +7,10 @@ type Config struct {
 	// If true, displays version and exits during startup
 	DisplayVersionAndExit bool
 
+	// If true, validates the node config, displays it and exits during
+	// startup
+	ValidateConfigAndExit bool
+
 	// Path to the build directory
 	BuildDir string
 
@@ -0,0 +1,210 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/utils/perms"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+var (
+	errNoManifest              = errors.New("archive has no manifest")
+	errUnsupportedVersion      = errors.New("unsupported archive version")
+	errChecksumMismatch        = errors.New("checksum mismatch")
+	errNoStakingKey            = errors.New("archive has no staking key")
+	errStakingFileExists       = errors.New("a different staking file already exists")
+	errDatabaseVersionMismatch = errors.New("database version mismatch")
+	errDatabaseNotEmpty        = errors.New("database isn't empty")
+)
+
+// Archive is an archive whose contents match its manifest
+type Archive struct {
+	path     string
+	Manifest Manifest
+
+	// Staking key and certificate of the node, or nil if they weren't backed
+	// up
+	stakingKey  []byte
+	stakingCert []byte
+}
+
+// Open reads the archive at [path] and verifies that its contents match its
+// manifest. The database isn't kept in memory, so it's read again when it's
+// restored.
+func Open(path string) (*Archive, error) {
+	a := &Archive{path: path}
+	checksums := make(map[string]string)
+	var manifestBytes []byte
+	err := a.walk(func(name string, content []byte) error {
+		switch name {
+		case manifestFileName:
+			manifestBytes = content
+			return nil
+		case stakingKeyFileName:
+			a.stakingKey = content
+		case stakingCertFileName:
+			a.stakingCert = content
+		}
+		checksums[name] = checksum(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if manifestBytes == nil {
+		return nil, errNoManifest
+	}
+	if err := json.Unmarshal(manifestBytes, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("couldn't parse manifest: %w", err)
+	}
+	if a.Manifest.Version != archiveVersion {
+		return nil, fmt.Errorf("%w %d", errUnsupportedVersion, a.Manifest.Version)
+	}
+	for name, expected := range a.Manifest.Checksums {
+		if checksums[name] != expected {
+			return nil, fmt.Errorf("%w for %s", errChecksumMismatch, name)
+		}
+	}
+	for name := range checksums {
+		if _, ok := a.Manifest.Checksums[name]; !ok {
+			return nil, fmt.Errorf("%w: %s isn't in the manifest", errChecksumMismatch, name)
+		}
+	}
+	if a.stakingKey != nil && a.stakingCert == nil {
+		return nil, errNoStakingCert
+	}
+	return a, nil
+}
+
+// HasStakingKey returns true if the staking key and certificate of the node
+// were backed up
+func (a *Archive) HasStakingKey() bool {
+	return a.stakingKey != nil
+}
+
+// RestoreStakingKeys writes the staking key and certificate of the archive to
+// [keyPath] and [certPath]. Files that already exist are never overwritten, so
+// this fails if they differ from the ones of the archive.
+func (a *Archive) RestoreStakingKeys(keyPath, certPath string) error {
+	if a.stakingKey == nil {
+		return errNoStakingKey
+	}
+	if err := restoreFile(keyPath, a.stakingKey); err != nil {
+		return err
+	}
+	return restoreFile(certPath, a.stakingCert)
+}
+
+// RestoreDatabase writes the snapshot of the database of the archive to [db],
+// which must be empty and of the same version as the database that was backed
+// up
+func (a *Archive) RestoreDatabase(db database.Database, dbVersion version.Version) error {
+	if a.Manifest.DatabaseVersion != dbVersion.String() {
+		return fmt.Errorf("%w: archive has %s but the database is %s",
+			errDatabaseVersionMismatch, a.Manifest.DatabaseVersion, dbVersion)
+	}
+
+	it := db.NewIterator()
+	empty := !it.Next()
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return fmt.Errorf("couldn't iterate over database: %w", err)
+	}
+	if !empty {
+		return errDatabaseNotEmpty
+	}
+
+	return a.walk(func(name string, content []byte) error {
+		if !strings.HasPrefix(name, databaseDir) {
+			return nil
+		}
+		// The archive is verified again, in case it changed since it was
+		// opened
+		if checksum(content) != a.Manifest.Checksums[name] {
+			return fmt.Errorf("%w for %s", errChecksumMismatch, name)
+		}
+
+		batch := db.NewBatch()
+		p := wrappers.Packer{Bytes: content}
+		for p.Offset < len(content) {
+			key := p.UnpackBytes()
+			value := p.UnpackBytes()
+			if p.Err != nil {
+				return fmt.Errorf("couldn't unpack %s: %w", name, p.Err)
+			}
+			if err := batch.Put(key, value); err != nil {
+				return err
+			}
+		}
+		return batch.Write()
+	})
+}
+
+// walk calls [f] with the name and content of each file of the archive
+func (a *Archive) walk(f func(name string, content []byte) error) error {
+	file, err := os.Open(a.path)
+	if err != nil {
+		return fmt.Errorf("couldn't open archive: %w", err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("couldn't decompress archive: %w", err)
+	}
+	defer gzipReader.Close()
+
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't read archive: %w", err)
+		}
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf("couldn't read %s: %w", header.Name, err)
+		}
+		if err := f(header.Name, content); err != nil {
+			return err
+		}
+	}
+}
+
+// restoreFile writes [content] to [path], unless the file already has this
+// content
+func restoreFile(path string, content []byte) error {
+	existing, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil && bytes.Equal(existing, content):
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w at %s", errStakingFileExists, path)
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), perms.ReadWriteExecute); err != nil {
+		return fmt.Errorf("couldn't create directory of %s: %w", path, err)
+	}
+	return perms.WriteFile(path, content, perms.ReadOnly)
+}
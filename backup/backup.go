@@ -0,0 +1,243 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package backup writes archives of the state of a node, from which the node
+// can be restored, such as on another machine.
+//
+// An archive is a gzipped tar file that holds the staking key and certificate
+// of the node, its config, the aliases given to chains through the admin API
+// and a snapshot of its database. The archive ends with a manifest that holds
+// the checksum of every other file, so that an archive is verified before the
+// node is restored from it.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+const (
+	// Version of the format of the archives
+	archiveVersion = 1
+
+	manifestFileName    = "manifest.json"
+	stakingKeyFileName  = "staking/staker.key"
+	stakingCertFileName = "staking/staker.crt"
+	configFileName      = "config.json"
+	aliasesFileName     = "aliases.json"
+
+	// The database is written to files in [databaseDir] of about [chunkSize]
+	// bytes each. Each file is a sequence of length prefixed keys and values.
+	databaseDir = "database/"
+	chunkSize   = 64 * units.MiB
+)
+
+var errNoStakingCert = errors.New("the staking key is backed up without its certificate")
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	Version   uint32    `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	// ID and network of the node that was backed up
+	NodeID    ids.ShortID `json:"nodeID"`
+	NetworkID uint32      `json:"networkID"`
+	// Version of the database that was backed up. The database can only be
+	// restored to a database of the same version.
+	DatabaseVersion string `json:"databaseVersion"`
+	// Hex encoded SHA256 checksum of each file of the archive, other than the
+	// manifest
+	Checksums map[string]string `json:"checksums"`
+}
+
+// State is the state of a node that is backed up
+type State struct {
+	NodeID    ids.ShortID
+	NetworkID uint32
+
+	// Paths of the staking key and certificate of the node. They aren't backed
+	// up if the key isn't stored in a file, such as if it's stored in an HSM.
+	StakingKeyPath  string
+	StakingCertPath string
+
+	// Config of the node, which is written as JSON
+	Config interface{}
+
+	// Aliases given to chains through the admin API. They're also part of the
+	// database, but are written separately so that they can be inspected.
+	ChainAliases map[string]ids.ID
+
+	// Database of the node, and its version. The snapshot of the database is
+	// consistent if its iterators are.
+	Database        database.Database
+	DatabaseVersion version.Version
+}
+
+// Write writes an archive of [state], made at [now], to [w]
+func Write(w io.Writer, state State, now time.Time) (*Manifest, error) {
+	manifest := &Manifest{
+		Version:         archiveVersion,
+		Timestamp:       now,
+		NodeID:          state.NodeID,
+		NetworkID:       state.NetworkID,
+		DatabaseVersion: state.DatabaseVersion.String(),
+		Checksums:       make(map[string]string),
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	aw := &archiveWriter{
+		writer:   tar.NewWriter(gzipWriter),
+		manifest: manifest,
+	}
+
+	if err := aw.writeStakingFiles(state.StakingKeyPath, state.StakingCertPath); err != nil {
+		return nil, err
+	}
+
+	configBytes, err := json.MarshalIndent(state.Config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal config: %w", err)
+	}
+	if err := aw.writeFile(configFileName, configBytes); err != nil {
+		return nil, err
+	}
+
+	aliasesBytes, err := json.MarshalIndent(state.ChainAliases, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal chain aliases: %w", err)
+	}
+	if err := aw.writeFile(aliasesFileName, aliasesBytes); err != nil {
+		return nil, err
+	}
+
+	if err := aw.writeDatabase(state.Database); err != nil {
+		return nil, err
+	}
+
+	// The manifest is written last, once the checksums of the other files are
+	// known
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal manifest: %w", err)
+	}
+	aw.manifest = nil
+	if err := aw.writeFile(manifestFileName, manifestBytes); err != nil {
+		return nil, err
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		aw.writer.Close(),
+		gzipWriter.Close(),
+	)
+	return manifest, errs.Err
+}
+
+// archiveWriter writes the files of an archive, and records their checksums
+// in the manifest
+type archiveWriter struct {
+	writer *tar.Writer
+	// If nil, the checksums of the written files aren't recorded
+	manifest *Manifest
+}
+
+func (w *archiveWriter) writeFile(name string, content []byte) error {
+	err := w.writer.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't write header of %s: %w", name, err)
+	}
+	if _, err := w.writer.Write(content); err != nil {
+		return fmt.Errorf("couldn't write %s: %w", name, err)
+	}
+	if w.manifest != nil {
+		w.manifest.Checksums[name] = checksum(content)
+	}
+	return nil
+}
+
+func (w *archiveWriter) writeStakingFiles(keyPath, certPath string) error {
+	if keyPath == "" {
+		return nil
+	}
+	key, err := ioutil.ReadFile(keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't read staking key: %w", err)
+	}
+	cert, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return fmt.Errorf("couldn't read staking certificate: %w", err)
+	}
+
+	if err := w.writeFile(stakingKeyFileName, key); err != nil {
+		return err
+	}
+	return w.writeFile(stakingCertFileName, cert)
+}
+
+func (w *archiveWriter) writeDatabase(db database.Database) error {
+	it := db.NewIterator()
+	defer it.Release()
+
+	chunk := 0
+	p := wrappers.Packer{MaxSize: math.MaxInt32}
+	flush := func() error {
+		if err := w.writeFile(chunkFileName(chunk), p.Bytes); err != nil {
+			return err
+		}
+		chunk++
+		p = wrappers.Packer{MaxSize: math.MaxInt32}
+		return nil
+	}
+
+	for it.Next() {
+		p.PackBytes(it.Key())
+		p.PackBytes(it.Value())
+		if p.Err != nil {
+			return fmt.Errorf("couldn't pack database entry: %w", p.Err)
+		}
+		if p.Offset >= chunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("couldn't iterate over database: %w", err)
+	}
+	if p.Offset > 0 {
+		return flush()
+	}
+	return nil
+}
+
+func chunkFileName(chunk int) string {
+	return fmt.Sprintf("%s%08d", databaseDir, chunk)
+}
+
+func checksum(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
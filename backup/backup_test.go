@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+func testState(t *testing.T, dir string) State {
+	keyPath := filepath.Join(dir, "staker.key")
+	certPath := filepath.Join(dir, "staker.crt")
+	assert.NoError(t, ioutil.WriteFile(keyPath, []byte("key"), 0o600))
+	assert.NoError(t, ioutil.WriteFile(certPath, []byte("cert"), 0o600))
+
+	db := memdb.New()
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, db.Put([]byte{byte(i)}, []byte{byte(i), byte(i)}))
+	}
+	assert.NoError(t, db.Put([]byte{0xff}, nil))
+
+	return State{
+		NodeID:          ids.GenerateTestShortID(),
+		NetworkID:       12345,
+		StakingKeyPath:  keyPath,
+		StakingCertPath: certPath,
+		Config:          map[string]string{"log-level": "info"},
+		ChainAliases:    map[string]ids.ID{"chain": ids.GenerateTestID()},
+		Database:        db,
+		DatabaseVersion: version.NewDefaultVersion(1, 4, 5),
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	state := testState(t, dir)
+	b := NewBackuper(Config{Dir: filepath.Join(dir, "backups")}, logging.NoLog{}, func() (State, error) {
+		return state, nil
+	})
+	path, err := b.Backup()
+	assert.NoError(err)
+
+	archive, err := Open(path)
+	assert.NoError(err)
+	assert.Equal(state.NodeID, archive.Manifest.NodeID)
+	assert.Equal(state.NetworkID, archive.Manifest.NetworkID)
+
+	restoredDir := filepath.Join(dir, "restored")
+	keyPath := filepath.Join(restoredDir, "staker.key")
+	certPath := filepath.Join(restoredDir, "staker.crt")
+	assert.NoError(archive.RestoreStakingKeys(keyPath, certPath))
+	key, err := ioutil.ReadFile(keyPath)
+	assert.NoError(err)
+	assert.Equal([]byte("key"), key)
+	// Restoring the same keys again is a no-op
+	assert.NoError(archive.RestoreStakingKeys(keyPath, certPath))
+	// Different keys are never overwritten
+	assert.ErrorIs(archive.RestoreStakingKeys(keyPath, state.StakingKeyPath), errStakingFileExists)
+
+	db := memdb.New()
+	assert.ErrorIs(archive.RestoreDatabase(db, version.NewDefaultVersion(1, 4, 4)), errDatabaseVersionMismatch)
+	assert.NoError(archive.RestoreDatabase(db, state.DatabaseVersion))
+	for i := 0; i < 100; i++ {
+		value, err := db.Get([]byte{byte(i)})
+		assert.NoError(err)
+		assert.Equal([]byte{byte(i), byte(i)}, value)
+	}
+	has, err := db.Has([]byte{0xff})
+	assert.NoError(err)
+	assert.True(has)
+	assert.ErrorIs(archive.RestoreDatabase(db, state.DatabaseVersion), errDatabaseNotEmpty)
+}
+
+func TestOpenCorruptedArchive(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	state := testState(t, dir)
+	path := filepath.Join(dir, "backup.tar.gz")
+	file, err := os.Create(path)
+	assert.NoError(err)
+	manifest, err := Write(file, state, time.Now())
+	assert.NoError(err)
+	assert.NoError(file.Close())
+
+	// Rewrite the archive with a config that doesn't match the manifest
+	file, err = os.Create(path)
+	assert.NoError(err)
+	gzipWriter := gzip.NewWriter(file)
+	aw := &archiveWriter{writer: tar.NewWriter(gzipWriter)}
+	assert.NoError(aw.writeFile(configFileName, []byte("other config")))
+	manifestBytes, err := json.Marshal(manifest)
+	assert.NoError(err)
+	assert.NoError(aw.writeFile(manifestFileName, manifestBytes))
+	assert.NoError(aw.writer.Close())
+	assert.NoError(gzipWriter.Close())
+	assert.NoError(file.Close())
+
+	_, err = Open(path)
+	assert.ErrorIs(err, errChecksumMismatch)
+}
+
+func TestBackuperPrunes(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	state := testState(t, dir)
+	backupDir := filepath.Join(dir, "backups")
+	b := NewBackuper(Config{Dir: backupDir, MaxNumFiles: 2}, logging.NoLog{}, func() (State, error) {
+		return state, nil
+	})
+
+	paths := []string(nil)
+	for i := 0; i < 3; i++ {
+		path, err := b.Backup()
+		assert.NoError(err)
+		paths = append(paths, path)
+	}
+
+	files, err := ioutil.ReadDir(backupDir)
+	assert.NoError(err)
+	assert.Len(files, 2)
+	_, err = os.Stat(paths[0])
+	assert.ErrorIs(err, os.ErrNotExist)
+	_, err = os.Stat(paths[2])
+	assert.NoError(err)
+}
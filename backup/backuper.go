@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/perms"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const (
+	fileNamePrefix = "backup-"
+	fileNameSuffix = ".tar.gz"
+	// Names of the archives sort in the order they were written
+	fileNameTimeFormat = "20060102T150405.000000000Z"
+)
+
+// Config that is used to describe the options of the backuper
+type Config struct {
+	// Directory the archives are written to
+	Dir string `json:"dir"`
+	// If non-zero, how often an archive is written. Otherwise, archives are
+	// only written when requested.
+	Freq time.Duration `json:"freq"`
+	// If non-zero, the number of archives that are kept. The oldest ones are
+	// removed.
+	MaxNumFiles int `json:"maxNumFiles"`
+}
+
+// Backuper writes archives of the state of the node
+type Backuper interface {
+	// Backup writes an archive and returns its path
+	Backup() (string, error)
+	// Dispatch writes an archive every [Config.Freq] until Shutdown is called
+	Dispatch() error
+	Shutdown()
+}
+
+type backuper struct {
+	config Config
+	log    logging.Logger
+	state  func() (State, error)
+
+	// Only one archive is written at a time
+	lock sync.Mutex
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// NewBackuper returns a new backuper that writes archives of the state
+// returned by [state]
+func NewBackuper(config Config, log logging.Logger, state func() (State, error)) Backuper {
+	return &backuper{
+		config: config,
+		log:    log,
+		state:  state,
+		closer: make(chan struct{}),
+	}
+}
+
+func (b *backuper) Backup() (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	state, err := b.state()
+	if err != nil {
+		return "", fmt.Errorf("couldn't get state to back up: %w", err)
+	}
+	if err := os.MkdirAll(b.config.Dir, perms.ReadWriteExecute); err != nil {
+		return "", fmt.Errorf("couldn't create backup directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	path := filepath.Join(b.config.Dir, fileNamePrefix+now.Format(fileNameTimeFormat)+fileNameSuffix)
+	// The archive is written to a temporary file first, so that an archive is
+	// never partially written
+	tmpPath := path + ".tmp"
+	// The archive holds the staking key, so only the owner can read it
+	file, err := perms.Create(tmpPath, perms.ReadOnly)
+	if err != nil {
+		return "", fmt.Errorf("couldn't create archive: %w", err)
+	}
+
+	_, err = Write(file, state, now)
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		file.Sync(),
+		file.Close(),
+	)
+	if errs.Errored() {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("couldn't write archive: %w", errs.Err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("couldn't rename archive: %w", err)
+	}
+
+	b.log.Info("wrote backup to %s", path)
+	return path, b.prune()
+}
+
+func (b *backuper) Dispatch() error {
+	t := time.NewTicker(b.config.Freq)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-b.closer:
+			return nil
+		case <-t.C:
+		}
+
+		if _, err := b.Backup(); err != nil {
+			// A failed backup shouldn't stop the next ones
+			b.log.Error("failed to write backup: %s", err)
+		}
+	}
+}
+
+func (b *backuper) Shutdown() {
+	close(b.closer)
+}
+
+// prune removes the oldest archives so that at most [Config.MaxNumFiles] are
+// kept
+func (b *backuper) prune() error {
+	if b.config.MaxNumFiles <= 0 {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(b.config.Dir)
+	if err != nil {
+		return fmt.Errorf("couldn't list backups: %w", err)
+	}
+	names := []string(nil)
+	for _, file := range files {
+		name := file.Name()
+		if !file.IsDir() && strings.HasPrefix(name, fileNamePrefix) && strings.HasSuffix(name, fileNameSuffix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for len(names) > b.config.MaxNumFiles {
+		if err := os.Remove(filepath.Join(b.config.Dir, names[0])); err != nil {
+			return fmt.Errorf("couldn't remove old backup: %w", err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
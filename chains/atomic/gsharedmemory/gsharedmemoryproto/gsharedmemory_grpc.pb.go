@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion7
 type SharedMemoryClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	Indexed(ctx context.Context, in *IndexedRequest, opts ...grpc.CallOption) (*IndexedResponse, error)
+	GetAll(ctx context.Context, in *GetAllRequest, opts ...grpc.CallOption) (*GetAllResponse, error)
 	Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
 }
 
@@ -49,6 +50,15 @@ func (c *sharedMemoryClient) Indexed(ctx context.Context, in *IndexedRequest, op
 	return out, nil
 }
 
+func (c *sharedMemoryClient) GetAll(ctx context.Context, in *GetAllRequest, opts ...grpc.CallOption) (*GetAllResponse, error) {
+	out := new(GetAllResponse)
+	err := c.cc.Invoke(ctx, "/gsharedmemoryproto.SharedMemory/GetAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *sharedMemoryClient) Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error) {
 	out := new(ApplyResponse)
 	err := c.cc.Invoke(ctx, "/gsharedmemoryproto.SharedMemory/Apply", in, out, opts...)
@@ -64,6 +74,7 @@ func (c *sharedMemoryClient) Apply(ctx context.Context, in *ApplyRequest, opts .
 type SharedMemoryServer interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	Indexed(context.Context, *IndexedRequest) (*IndexedResponse, error)
+	GetAll(context.Context, *GetAllRequest) (*GetAllResponse, error)
 	Apply(context.Context, *ApplyRequest) (*ApplyResponse, error)
 	mustEmbedUnimplementedSharedMemoryServer()
 }
@@ -78,6 +89,9 @@ func (UnimplementedSharedMemoryServer) Get(context.Context, *GetRequest) (*GetRe
 func (UnimplementedSharedMemoryServer) Indexed(context.Context, *IndexedRequest) (*IndexedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Indexed not implemented")
 }
+func (UnimplementedSharedMemoryServer) GetAll(context.Context, *GetAllRequest) (*GetAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAll not implemented")
+}
 func (UnimplementedSharedMemoryServer) Apply(context.Context, *ApplyRequest) (*ApplyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Apply not implemented")
 }
@@ -130,6 +144,24 @@ func _SharedMemory_Indexed_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SharedMemory_GetAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharedMemoryServer).GetAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gsharedmemoryproto.SharedMemory/GetAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharedMemoryServer).GetAll(ctx, req.(*GetAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SharedMemory_Apply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ApplyRequest)
 	if err := dec(in); err != nil {
@@ -163,6 +195,10 @@ var SharedMemory_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Indexed",
 			Handler:    _SharedMemory_Indexed_Handler,
 		},
+		{
+			MethodName: "GetAll",
+			Handler:    _SharedMemory_GetAll_Handler,
+		},
 		{
 			MethodName: "Apply",
 			Handler:    _SharedMemory_Apply_Handler,
@@ -150,6 +150,52 @@ func (c *Client) Indexed(
 	return values, lastTrait, lastKey, nil
 }
 
+func (c *Client) GetAll(
+	peerChainID ids.ID,
+	startKey []byte,
+	limit int,
+) (
+	[]*atomic.Element,
+	[]byte,
+	error,
+) {
+	req := &gsharedmemoryproto.GetAllRequest{
+		PeerChainID: peerChainID[:],
+		StartKey:    startKey,
+		Limit:       int32(limit),
+		Id:          stdatomic.AddInt64(&c.uniqueID, 1),
+	}
+	resp, err := c.client.GetAll(context.Background(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+	lastKey := resp.LastKey
+
+	// The elements are sent in chunks, which are fetched with the ID of the
+	// request
+	req.PeerChainID = nil
+	req.StartKey = nil
+	req.Limit = 0
+	elems := make([]*atomic.Element, 0, len(resp.Elements))
+	for {
+		for _, elem := range resp.Elements {
+			elems = append(elems, &atomic.Element{
+				Key:    elem.Key,
+				Value:  elem.Value,
+				Traits: elem.Traits,
+			})
+		}
+		if !resp.Continues {
+			return elems, lastKey, nil
+		}
+
+		resp, err = c.client.GetAll(context.Background(), req)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
 func (c *Client) Apply(requests map[ids.ID]*atomic.Requests, batch ...database.Batch) error {
 	req := &gsharedmemoryproto.ApplyRequest{
 		Continues: true,
@@ -27,6 +27,9 @@ type Server struct {
 	indexedLock sync.Mutex
 	indexed     map[int64]*indexedRequest
 
+	getAllLock sync.Mutex
+	getAll     map[int64]*getAllRequest
+
 	applyLock sync.Mutex
 	apply     map[int64]*applyRequest
 }
@@ -38,6 +41,7 @@ func NewServer(sm atomic.SharedMemory, db database.Database) *Server {
 		db:      db,
 		gets:    make(map[int64]*getRequest),
 		indexed: make(map[int64]*indexedRequest),
+		getAll:  make(map[int64]*getAllRequest),
 		apply:   make(map[int64]*applyRequest),
 	}
 }
@@ -201,6 +205,65 @@ func (s *Server) Indexed(
 	return resp, nil
 }
 
+type getAllRequest struct {
+	remainingElems []*atomic.Element
+}
+
+func (s *Server) GetAll(
+	_ context.Context,
+	req *gsharedmemoryproto.GetAllRequest,
+) (*gsharedmemoryproto.GetAllResponse, error) {
+	s.getAllLock.Lock()
+	defer s.getAllLock.Unlock()
+
+	resp := &gsharedmemoryproto.GetAllResponse{}
+	getAll, exists := s.getAll[req.Id]
+	if !exists {
+		peerChainID, err := ids.ToID(req.PeerChainID)
+		if err != nil {
+			return nil, err
+		}
+
+		elems, lastKey, err := s.sm.GetAll(peerChainID, req.StartKey, int(req.Limit))
+		if err != nil {
+			return nil, err
+		}
+
+		getAll = &getAllRequest{
+			remainingElems: elems,
+		}
+		resp.LastKey = lastKey
+	}
+
+	currentSize := 0
+	for i, elem := range getAll.remainingElems {
+		sizeChange := baseElementSize + len(elem.Key) + len(elem.Value)
+		for _, trait := range elem.Traits {
+			sizeChange += len(trait)
+		}
+		if newSize := currentSize + sizeChange; newSize > maxBatchSize && i > 0 {
+			break
+		}
+		currentSize += sizeChange
+
+		resp.Elements = append(resp.Elements, &gsharedmemoryproto.Element{
+			Key:    elem.Key,
+			Value:  elem.Value,
+			Traits: elem.Traits,
+		})
+	}
+
+	getAll.remainingElems = getAll.remainingElems[len(resp.Elements):]
+	resp.Continues = len(getAll.remainingElems) > 0
+
+	if resp.Continues {
+		s.getAll[req.Id] = getAll
+	} else {
+		delete(s.getAll, req.Id)
+	}
+	return resp, nil
+}
+
 type applyRequest struct {
 	requests map[ids.ID]*atomic.Requests
 	batches  map[int64]database.Batch
@@ -39,6 +39,21 @@ type SharedMemory interface {
 		lastKey []byte,
 		err error,
 	)
+	// Fetches up to [limit] elements from this chain's side, in key order,
+	// starting after [startKey]. Returns the key of the last fetched element,
+	// which is the [startKey] of the next page.
+	GetAll(
+		peerChainID ids.ID,
+		startKey []byte,
+		limit int,
+	) (
+		elems []*Element,
+		lastKey []byte,
+		err error,
+	)
+	// Applies [requests] to shared memory and writes [batches], which must be
+	// batches of the database shared memory is built on, in a single atomic
+	// write.
 	Apply(requests map[ids.ID]*Requests, batches ...database.Batch) error
 }
 
@@ -102,6 +117,22 @@ func (sm *sharedMemory) Indexed(
 	return values, lastTrait, lastKey, nil
 }
 
+func (sm *sharedMemory) GetAll(
+	peerChainID ids.ID,
+	startKey []byte,
+	limit int,
+) ([]*Element, []byte, error) {
+	sharedID := sm.m.sharedID(peerChainID, sm.thisChainID)
+	db := sm.m.GetSharedDatabase(sm.m.db, sharedID)
+	defer sm.m.ReleaseSharedDatabase(sharedID)
+
+	s := state{
+		c:       sm.m.codec,
+		valueDB: inbound.getValueDB(sm.thisChainID, peerChainID, db),
+	}
+	return s.getAll(startKey, limit)
+}
+
 func (sm *sharedMemory) Apply(requests map[ids.ID]*Requests, batches ...database.Batch) error {
 	// Sorting here introduces an ordering over the locks to prevent any
 	// deadlocks
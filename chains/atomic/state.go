@@ -141,6 +141,41 @@ func (s *state) loadValue(key []byte) (*dbElement, error) {
 	return value, err
 }
 
+// getAll returns up to [limit] of the present elements, in key order, whose
+// keys are greater than [startKey]. Also returns the key of the last returned
+// element, which is [startKey] if none were returned.
+func (s *state) getAll(startKey []byte, limit int) ([]*Element, []byte, error) {
+	iter := s.valueDB.NewIteratorWithStart(startKey)
+	defer iter.Release()
+
+	elems := []*Element(nil)
+	lastKey := startKey
+	for len(elems) < limit && iter.Next() {
+		key := iter.Key()
+		if bytes.Equal(key, startKey) {
+			continue
+		}
+
+		value := &dbElement{}
+		if _, err := s.c.Unmarshal(iter.Value(), value); err != nil {
+			return nil, nil, err
+		}
+		// Elements that were removed before they were added aren't in shared
+		// memory yet
+		if !value.Present {
+			continue
+		}
+
+		lastKey = utils.CopyBytes(key)
+		elems = append(elems, &Element{
+			Key:    lastKey,
+			Value:  value.Value,
+			Traits: value.Traits,
+		})
+	}
+	return elems, lastKey, iter.Error()
+}
+
 func (s *state) getKeys(traits [][]byte, startTrait, startKey []byte, limit int) ([][]byte, []byte, []byte, error) {
 	tracked := ids.Set{}
 	keys := [][]byte(nil)
@@ -19,6 +19,8 @@ var SharedMemoryTests = []func(t *testing.T, chainID0, chainID1 ids.ID, sm0, sm1
 	TestSharedMemoryLargePutGetAndRemove,
 	TestSharedMemoryIndexed,
 	TestSharedMemoryLargeIndexed,
+	TestSharedMemoryGetAll,
+	TestSharedMemoryLargeGetAll,
 	TestSharedMemoryCantDuplicatePut,
 	TestSharedMemoryCantDuplicateRemove,
 	TestSharedMemoryCommitOnPut,
@@ -190,6 +192,76 @@ func TestSharedMemoryLargeIndexed(t *testing.T, chainID0, chainID1 ids.ID, sm0,
 	assert.Len(values, len(elems), "wrong number of values returned")
 }
 
+func TestSharedMemoryGetAll(t *testing.T, chainID0, chainID1 ids.ID, sm0, sm1 SharedMemory, _ database.Database) {
+	assert := assert.New(t)
+
+	elems := []*Element{
+		{Key: []byte{0}, Value: []byte{1}, Traits: [][]byte{{2}}},
+		{Key: []byte{3}, Value: []byte{4}, Traits: [][]byte{{9}}},
+		{Key: []byte{5}, Value: []byte{6}, Traits: [][]byte{{2}, {7}}},
+	}
+	err := sm0.Apply(map[ids.ID]*Requests{chainID1: {
+		PutRequests: elems,
+		// Removing an element that wasn't added yet doesn't make it fetchable
+		RemoveRequests: [][]byte{{8}},
+	}})
+	assert.NoError(err)
+
+	// The elements are only on the peer's side
+	fetched, _, err := sm0.GetAll(chainID1, nil, 10)
+	assert.NoError(err)
+	assert.Empty(fetched, "wrong elements returned")
+
+	fetched, lastKey, err := sm1.GetAll(chainID0, nil, 2)
+	assert.NoError(err)
+	assert.Equal(elems[:2], fetched, "wrong elements returned")
+	assert.Equal([]byte{3}, lastKey, "wrong last key returned")
+
+	// The next page starts after the last fetched key
+	fetched, lastKey, err = sm1.GetAll(chainID0, lastKey, 2)
+	assert.NoError(err)
+	assert.Equal(elems[2:], fetched, "wrong elements returned")
+	assert.Equal([]byte{5}, lastKey, "wrong last key returned")
+
+	fetched, lastKey, err = sm1.GetAll(chainID0, lastKey, 2)
+	assert.NoError(err)
+	assert.Empty(fetched, "wrong elements returned")
+	assert.Equal([]byte{5}, lastKey, "wrong last key returned")
+}
+
+func TestSharedMemoryLargeGetAll(t *testing.T, chainID0, chainID1 ids.ID, sm0, sm1 SharedMemory, _ database.Database) {
+	assert := assert.New(t)
+
+	totalSize := 8 * units.MiB   // 8 MiB
+	elementSize := 1 * units.KiB // 1 KiB
+	pairSize := 2 * elementSize  // 2 KiB
+
+	b := make([]byte, totalSize)
+	_, err := rand.Read(b) // #nosec G404
+	assert.NoError(err)
+
+	elems := []*Element{}
+	for len(b) > pairSize {
+		key := b[:elementSize]
+		b = b[elementSize:]
+
+		value := b[:elementSize]
+		b = b[elementSize:]
+
+		elems = append(elems, &Element{
+			Key:   key,
+			Value: value,
+		})
+	}
+
+	err = sm0.Apply(map[ids.ID]*Requests{chainID1: {PutRequests: elems}})
+	assert.NoError(err)
+
+	fetched, _, err := sm1.GetAll(chainID0, nil, len(elems)+1)
+	assert.NoError(err)
+	assert.Len(fetched, len(elems), "wrong number of elements returned")
+}
+
 func TestSharedMemoryCantDuplicatePut(t *testing.T, _, chainID1 ids.ID, sm0, _ SharedMemory, _ database.Database) {
 	assert := assert.New(t)
 	err := sm0.Apply(map[ids.ID]*Requests{chainID1: {PutRequests: []*Element{
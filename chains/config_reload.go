@@ -0,0 +1,191 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/storage"
+)
+
+const chainConfigFileName = "config"
+
+var (
+	errNoChainConfigDir   = errors.New("chain configs were not loaded from a directory")
+	errNotReloadable      = errors.New("chain config contains changes to keys that can't be reloaded")
+	errChainNotReloadable = errors.New("chain doesn't support reloading its config")
+)
+
+// reloadableChain is the state needed to hot-reload the config of a running
+// chain, and to prune its history.
+type reloadableChain struct {
+	lock sync.Mutex
+
+	ctx *snow.ConsensusContext
+	vm  common.VM
+	// db is the database of the chain, which is compacted once the chain is
+	// pruned
+	db database.Database
+
+	// config is the most recently applied chain config
+	config []byte
+}
+
+// ReloadChainConfig re-reads the config of the chain with ID [chainID] from
+// the chain config directory and delivers the changed keys to the chain's VM.
+// An error is returned, and nothing is delivered, if any key that the VM didn't
+// register as reloadable was changed.
+// Returns the keys that were updated.
+func (m *manager) ReloadChainConfig(chainID ids.ID) ([]string, error) {
+	if len(m.ChainConfigDir) == 0 {
+		return nil, errNoChainConfigDir
+	}
+
+	m.chainsLock.Lock()
+	chain, exists := m.reloadable[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errUnknownChainID
+	}
+
+	reloader, ok := chain.vm.(common.ConfigReloader)
+	if !ok {
+		return nil, errChainNotReloadable
+	}
+
+	newConfig, err := m.readChainConfigFile(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read chain config: %w", err)
+	}
+
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	changes, err := DiffChainConfig(chain.config, newConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	chain.ctx.Lock.Lock()
+	defer chain.ctx.Lock.Unlock()
+
+	keys, err := reloader.ReloadableConfigKeys()
+	if err != nil {
+		return nil, err
+	}
+	reloadableKeys := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		reloadableKeys[key] = struct{}{}
+	}
+
+	changedKeys := make([]string, 0, len(changes))
+	nonReloadable := []string(nil)
+	for key := range changes {
+		changedKeys = append(changedKeys, key)
+		if _, ok := reloadableKeys[key]; !ok {
+			nonReloadable = append(nonReloadable, key)
+		}
+	}
+	if len(nonReloadable) > 0 {
+		sort.Strings(nonReloadable)
+		return nil, fmt.Errorf("%w: %s", errNotReloadable, strings.Join(nonReloadable, ", "))
+	}
+
+	if err := reloader.ReloadConfig(changes); err != nil {
+		return nil, err
+	}
+	chain.config = newConfig
+
+	sort.Strings(changedKeys)
+	m.Log.Info("reloaded config of chain %s. Updated keys: %s", chainID, changedKeys)
+	return changedKeys, nil
+}
+
+// readChainConfigFile reads the config file of the chain with ID [chainID]. It
+// first searches the directory named after the chain's ID, then falls back to
+// the chain's aliases.
+func (m *manager) readChainConfigFile(chainID ids.ID) ([]byte, error) {
+	names := []string{chainID.String()}
+	aliases, err := m.Aliases(chainID)
+	if err != nil {
+		return nil, err
+	}
+	names = append(names, aliases...)
+
+	for _, name := range names {
+		chainDir := filepath.Join(m.ChainConfigDir, name)
+		exists, err := storage.FolderExists(chainDir)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return storage.ReadFileWithName(chainDir, chainConfigFileName)
+		}
+	}
+	return nil, nil
+}
+
+// DiffChainConfig compares the top-level keys of two JSON encoded chain
+// configs. It returns the keys whose values differ, mapped to their value in
+// [newConfig]. Keys that are only present in [oldConfig] are mapped to an empty
+// value. Empty configs are treated as empty JSON objects.
+func DiffChainConfig(oldConfig, newConfig []byte) (map[string][]byte, error) {
+	oldValues, err := parseChainConfig(oldConfig)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse current chain config: %w", err)
+	}
+	newValues, err := parseChainConfig(newConfig)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse new chain config: %w", err)
+	}
+
+	changes := make(map[string][]byte)
+	for key, newValue := range newValues {
+		if oldValue, ok := oldValues[key]; !ok || !bytes.Equal(oldValue, newValue) {
+			changes[key] = newValue
+		}
+	}
+	for key := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			changes[key] = nil
+		}
+	}
+	return changes, nil
+}
+
+// parseChainConfig returns the compacted JSON encoding of every top-level key
+// of [config].
+func parseChainConfig(config []byte) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	if len(bytes.TrimSpace(config)) == 0 {
+		return values, nil
+	}
+
+	rawValues := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(config, &rawValues); err != nil {
+		return nil, err
+	}
+	for key, rawValue := range rawValues {
+		buf := bytes.Buffer{}
+		if err := json.Compact(&buf, rawValue); err != nil {
+			return nil, err
+		}
+		values[key] = buf.Bytes()
+	}
+	return values, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffChainConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldConfig string
+		newConfig string
+		expected  map[string][]byte
+		shouldErr bool
+	}{
+		{
+			name:      "both empty",
+			oldConfig: "",
+			newConfig: "",
+			expected:  map[string][]byte{},
+		},
+		{
+			name:      "unchanged with different formatting",
+			oldConfig: `{"a": 1, "b": {"c": "d"}}`,
+			newConfig: `{"b":{"c":"d"},"a":1}`,
+			expected:  map[string][]byte{},
+		},
+		{
+			name:      "added key",
+			oldConfig: "",
+			newConfig: `{"a": [1, 2]}`,
+			expected: map[string][]byte{
+				"a": []byte("[1,2]"),
+			},
+		},
+		{
+			name:      "changed and removed keys",
+			oldConfig: `{"a": 1, "b": true, "c": "same"}`,
+			newConfig: `{"a": 2, "c": "same"}`,
+			expected: map[string][]byte{
+				"a": []byte("2"),
+				"b": nil,
+			},
+		},
+		{
+			name:      "invalid new config",
+			oldConfig: `{"a": 1}`,
+			newConfig: `{"a": `,
+			shouldErr: true,
+		},
+		{
+			name:      "config isn't an object",
+			oldConfig: `[1, 2]`,
+			newConfig: `{}`,
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			changes, err := DiffChainConfig([]byte(test.oldConfig), []byte(test.newConfig))
+			if test.shouldErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, changes)
+		})
+	}
+}
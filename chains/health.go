@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/api/health"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+var (
+	errChainNotBootstrapped = errors.New("chain is not bootstrapped")
+
+	_ snow.Acceptor = &acceptanceTracker{}
+)
+
+// ChainReadiness is the result of the readiness check of a chain
+type ChainReadiness struct {
+	Bootstrapped bool `json:"bootstrapped"`
+	// LastAcceptedHeight is the height of the last accepted block. For a DAG,
+	// it is the height of the highest accepted vertex.
+	LastAcceptedHeight json.Uint64 `json:"lastAcceptedHeight"`
+	// SecondsSinceLastAccepted is the number of seconds since this node
+	// accepted a container of the chain. If this node hasn't accepted a
+	// container since it started, it is the number of seconds since the chain
+	// was created.
+	SecondsSinceLastAccepted json.Uint64 `json:"secondsSinceLastAccepted"`
+}
+
+// acceptanceTracker records when a container of a chain was last accepted
+type acceptanceTracker struct {
+	clock        mockable.Clock
+	lastAccepted utils.AtomicInterface
+}
+
+func newAcceptanceTracker() *acceptanceTracker {
+	t := &acceptanceTracker{}
+	t.lastAccepted.SetValue(t.clock.Time())
+	return t
+}
+
+func (t *acceptanceTracker) Accept(*snow.ConsensusContext, ids.ID, []byte) error {
+	t.lastAccepted.SetValue(t.clock.Time())
+	return nil
+}
+
+func (t *acceptanceTracker) sinceLastAccepted() time.Duration {
+	return t.clock.Time().Sub(t.lastAccepted.GetValue().(time.Time))
+}
+
+// readinessCheck returns a check that passes once the chain of [ctx] is
+// bootstrapped. [lastAcceptedHeight] is called while holding the context lock.
+func readinessCheck(
+	ctx *snow.ConsensusContext,
+	tracker *acceptanceTracker,
+	lastAcceptedHeight func() (uint64, error),
+) health.Checker {
+	return health.CheckerFunc(func() (interface{}, error) {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		height, err := lastAcceptedHeight()
+		if err != nil {
+			return nil, err
+		}
+		details := ChainReadiness{
+			Bootstrapped:             ctx.IsBootstrapped(),
+			LastAcceptedHeight:       json.Uint64(height),
+			SecondsSinceLastAccepted: json.Uint64(tracker.sinceLastAccepted() / time.Second),
+		}
+		if !details.Bootstrapped {
+			return details, errChainNotBootstrapped
+		}
+		return details, nil
+	})
+}
+
+// failedChainCheck returns a check that always reports [err], the error that
+// prevented the chain from being created
+func failedChainCheck(err error) health.Checker {
+	return health.CheckerFunc(func() (interface{}, error) {
+		return nil, fmt.Errorf("couldn't create chain: %w", err)
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+)
+
+func TestReadinessCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	tracker := newAcceptanceTracker()
+	now := time.Unix(1000, 0)
+	tracker.clock.Set(now)
+	assert.NoError(tracker.Accept(ctx, ids.Empty, nil))
+	tracker.clock.Set(now.Add(5 * time.Second))
+
+	height := uint64(10)
+	check := readinessCheck(ctx, tracker, func() (uint64, error) { return height, nil })
+
+	details, err := check.HealthCheck()
+	assert.ErrorIs(err, errChainNotBootstrapped)
+	assert.Equal(ChainReadiness{
+		LastAcceptedHeight:       10,
+		SecondsSinceLastAccepted: 5,
+	}, details)
+
+	ctx.SetState(snow.NormalOp)
+	height = 11
+	details, err = check.HealthCheck()
+	assert.NoError(err)
+	assert.Equal(ChainReadiness{
+		Bootstrapped:             true,
+		LastAcceptedHeight:       11,
+		SecondsSinceLastAccepted: 5,
+	}, details)
+
+	errHeight := errors.New("unknown height")
+	check = readinessCheck(ctx, tracker, func() (uint64, error) { return 0, errHeight })
+	_, err = check.HealthCheck()
+	assert.ErrorIs(err, errHeight)
+}
+
+func TestFailedChainCheck(t *testing.T) {
+	errCreation := errors.New("incompatible plugin")
+	_, err := failedChainCheck(errCreation).HealthCheck()
+	assert.ErrorIs(t, err, errCreation)
+}
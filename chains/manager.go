@@ -18,6 +18,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/api/metrics"
 	"github.com/Toinounet21/avalanchego-mod/api/server"
 	"github.com/Toinounet21/avalanchego-mod/chains/atomic"
+	"github.com/Toinounet21/avalanchego-mod/database/deferreddb"
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
@@ -32,11 +33,12 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/sender"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/timeout"
-	"github.com/Toinounet21/avalanchego-mod/snow/triggers"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/Toinounet21/avalanchego-mod/vms"
+	"github.com/Toinounet21/avalanchego-mod/vms/heightindexvm"
 	"github.com/Toinounet21/avalanchego-mod/vms/metervm"
 	"github.com/Toinounet21/avalanchego-mod/vms/proposervm"
 
@@ -53,12 +55,34 @@ import (
 	snowgetter "github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/getter"
 )
 
-const defaultChannelSize = 1
+const (
+	defaultChannelSize = 1
+
+	// Chains' consensus metrics are gathered under the
+	// "avalanche_chain" namespace, and their VM's metrics under the
+	// "avalanche_vm" namespace
+	chainMetricsNamespace = "chain"
+	vmMetricsNamespace    = "vm"
+
+	// Labels that tell apart the metrics of different chains
+	chainLabel    = "chain"
+	chainIDLabel  = "chainID"
+	subnetIDLabel = "subnetID"
+	vmLabel       = "vm"
+
+	// Granularity of the engines' timeouts
+	timeoutWheelTick = 100 * time.Millisecond
+)
 
 var (
 	errUnknownChainID = errors.New("unknown chain ID")
 	errUnknownVMType  = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
 
+	errAllSubnetsTracked   = errors.New("every subnet is tracked when staking is disabled")
+	errPrimaryNetwork      = errors.New("the primary network is always tracked")
+	errNoSubnetTracker     = errors.New("the P-chain can't track subnets")
+	errSubnetChainsStopped = errors.New("chains of the subnet were stopped, restart the node to track it again")
+
 	_ Manager = &manager{}
 )
 
@@ -96,9 +120,41 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the last report of the sampled beacons disagreeing about the
+	// accepted frontier of the chain with the given ID, if any
+	FrontierDiagnostic(chainID ids.ID) (common.FrontierDiagnostic, bool)
+
+	// Re-reads the config of the chain with the given ID and delivers the
+	// changed keys to the chain's VM. Returns the keys that were updated.
+	ReloadChainConfig(chainID ids.ID) ([]string, error)
+
+	// Deletes the history of the chain with the given ID that is older than
+	// the pruning retention window, and compacts the chain's database.
+	// Returns the number of containers whose data was deleted.
+	PruneChain(chainID ids.ID) (int, error)
+
+	// Starts validating the subnet with the given ID without a restart. The
+	// chains of the subnet are created and start bootstrapping.
+	TrackSubnet(subnetID ids.ID) error
+
+	// Stops validating the subnet with the given ID. The chains of the subnet
+	// are stopped, and the subnet can't be tracked again until the node
+	// restarts.
+	UntrackSubnet(subnetID ids.ID) error
+
 	Shutdown()
 }
 
+// SubnetTracker is implemented by the VM of the P-chain, which knows the
+// validators and the chains of every subnet
+type SubnetTracker interface {
+	// Loads the validator set of the subnet and creates its chains
+	TrackSubnet(subnetID ids.ID) error
+
+	// Stops updating the validator set of the subnet and creating its chains
+	UntrackSubnet(subnetID ids.ID) error
+}
+
 // ChainParameters defines the chain being created
 type ChainParameters struct {
 	ID          ids.ID   // The ID of the chain being created
@@ -111,10 +167,11 @@ type ChainParameters struct {
 }
 
 type chain struct {
-	Name    string
-	Engine  common.Engine
-	Handler *router.Handler
-	Beacons validators.Set
+	Name       string
+	Engine     common.Engine
+	Handler    *router.Handler
+	Beacons    validators.Set
+	Reloadable *reloadableChain
 }
 
 // ChainConfig is configuration settings for the current execution.
@@ -130,12 +187,13 @@ type ManagerConfig struct {
 	StakingCert                 tls.Certificate // needed to sign snowman++ blocks
 	Log                         logging.Logger
 	LogFactory                  logging.Factory
-	VMManager                   vms.Manager // Manage mappings from vm ID --> vm
-	DecisionEvents              *triggers.EventDispatcher
-	ConsensusEvents             *triggers.EventDispatcher
+	VMManager                   vms.Manager      // Manage mappings from vm ID --> vm
+	DecisionEvents              *common.EventBus // Also publishes the bootstrap phases and engine states of the chains
+	ConsensusEvents             *common.EventBus
 	DBManager                   dbManager.Manager
 	MsgCreator                  message.Creator    // message creator, shared with network
 	Router                      router.Router      // Routes incoming messages to the appropriate chain
+	Scheduler                   *router.Scheduler  // Gives the chains their turns to process messages
 	Net                         network.Network    // Sends consensus messages to other validators
 	ConsensusParams             avcon.Parameters   // The consensus parameters (alpha, beta, etc.) for new chains
 	Validators                  validators.Manager // Validators validating on this chain
@@ -146,14 +204,18 @@ type ManagerConfig struct {
 	AtomicMemory                *atomic.Memory
 	AVAXAssetID                 ids.ID
 	XChainID                    ids.ID
-	CriticalChains              ids.Set          // Chains that can't exit gracefully
-	WhitelistedSubnets          ids.Set          // Subnets to validate
-	TimeoutManager              *timeout.Manager // Manages request timeouts when sending messages to other validators
+	CriticalChains              ids.Set           // Chains that can't exit gracefully
+	WhitelistedSubnets          ids.Set           // Subnets to validate
+	TimeoutManager              *timeout.Manager  // Manages request timeouts when sending messages to other validators
+	PeerScorer                  common.PeerScorer // Tracks how useful each peer has been
 	Health                      health.Registerer
 	RetryBootstrap              bool                    // Should Bootstrap be retried
 	RetryBootstrapWarnFrequency int                     // Max number of times to retry bootstrap before warning the node operator
 	SubnetConfigs               map[ids.ID]SubnetConfig // ID -> SubnetConfig
 	ChainConfigs                map[string]ChainConfig  // alias -> ChainConfig
+	// Directory that [ChainConfigs] were read from. Empty if the chain
+	// configs weren't read from a directory.
+	ChainConfigDir string
 	// ShutdownNodeFunc allows the chain manager to issue a request to shutdown the node
 	ShutdownNodeFunc func(exitCode int)
 	MeterVMEnabled   bool // Should each VM be wrapped with a MeterVM
@@ -172,8 +234,30 @@ type ManagerConfig struct {
 	// containers in an ancestors message it receives.
 	BootstrapAncestorsMaxContainersReceived int
 
+	// Duration that chits sent by Snowman engines in response to a query are
+	// re-sent in response to queries for the same block
+	ChitCacheDuration time.Duration
+
+	// If true, the validators polled by queries are sampled with a VRF under
+	// the staking key, so that they can't be predicted by other nodes
+	VRFQuerySampling bool
+
+	// If non-nil, the writes made while the primary network bootstraps are
+	// deferred in [DeferredDB], which is flushed by each bootstrapping chain
+	// after every [DeferredFlushFrequency] executed containers. Writes stop
+	// being deferred once the primary network is bootstrapped.
+	DeferredDB             *deferreddb.Database
+	DeferredFlushFrequency int
+
+	// If > 0, the bootstrapping jobs and missing IDs of a chain that are older
+	// than [BootstrapQueueMaxAge] are evicted when the chain is created
+	BootstrapQueueMaxAge time.Duration
+
 	ApricotPhase4Time            time.Time
 	ApricotPhase4MinPChainHeight uint64
+
+	// Pruning of the history of the chains whose VMs support it
+	Pruning PruningConfig
 }
 
 type manager struct {
@@ -196,19 +280,76 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]*router.Handler
+	// Key: Chain's ID
+	// Value: The state needed to reload the chain's config and to prune the
+	//        chain
+	reloadable map[ids.ID]*reloadableChain
+
+	frontierDiagnosticsLock sync.Mutex
+	// Key: Chain's ID
+	// Value: The last report of the beacons disagreeing about the chain's
+	//        accepted frontier
+	frontierDiagnostics map[ids.ID]common.FrontierDiagnostic
 
 	// snowman++ related interface to allow validators retrival
 	validatorState validators.State
+
+	whitelistLock sync.RWMutex
+	// Subnets whose chains are created. Starts as
+	// [ManagerConfig.WhitelistedSubnets].
+	whitelistedSubnets ids.Set
+	// Untracked subnets whose chains were stopped
+	stoppedSubnets ids.Set
+	// The P-chain, which creates the chains of the tracked subnets. Nil until
+	// the P-chain is created.
+	subnetTracker SubnetTracker
+
+	// Gathers the consensus and VM metrics of every chain, labelled by chain
+	chainMetrics metrics.LabelGatherer
+	vmMetrics    metrics.LabelGatherer
+
+	// Schedules the timeouts registered by the engines of every chain
+	timeoutWheel *timer.Wheel
+
+	// Closed when the manager shuts down, which stops pruning the chains
+	closer chan struct{}
 }
 
 // New returns a new Manager
-func New(config *ManagerConfig) Manager {
-	return &manager{
+func New(config *ManagerConfig) (Manager, error) {
+	m := &manager{
 		Aliaser:       ids.NewAliaser(),
 		ManagerConfig: *config,
 		subnets:       make(map[ids.ID]Subnet),
 		chains:        make(map[ids.ID]*router.Handler),
+		reloadable:    make(map[ids.ID]*reloadableChain),
+
+		frontierDiagnostics: make(map[ids.ID]common.FrontierDiagnostic),
+
+		chainMetrics: metrics.NewLabelGatherer(),
+		vmMetrics:    metrics.NewLabelGatherer(),
+
+		timeoutWheel: timer.NewDefaultWheel(timeoutWheelTick),
+
+		closer: make(chan struct{}),
+	}
+	m.whitelistedSubnets.Union(config.WhitelistedSubnets)
+
+	// Metrics of all the chains share their names, so that one query covers
+	// every chain
+	chainNamespace := fmt.Sprintf("%s_%s", constants.PlatformName, chainMetricsNamespace)
+	if err := m.Metrics.Register(chainNamespace, m.chainMetrics); err != nil {
+		return nil, fmt.Errorf("error while registering chains' metrics %w", err)
 	}
+	vmNamespace := fmt.Sprintf("%s_%s", constants.PlatformName, vmMetricsNamespace)
+	if err := m.Metrics.Register(vmNamespace, m.vmMetrics); err != nil {
+		return nil, fmt.Errorf("error while registering vms' metrics %w", err)
+	}
+	go m.Log.RecoverAndPanic(m.timeoutWheel.Dispatch)
+	if m.Pruning.Retention > 0 && m.Pruning.Frequency > 0 {
+		go m.Log.RecoverAndPanic(m.dispatchPruning)
+	}
+	return m, nil
 }
 
 // Router that this chain manager is using to route consensus messages to chains
@@ -226,7 +367,7 @@ func (m *manager) CreateChain(chain ChainParameters) {
 // Create a chain, this is only called from the P-chain thread, except for
 // creating the P-chain.
 func (m *manager) ForceCreateChain(chainParams ChainParameters) {
-	if m.StakingEnabled && chainParams.SubnetID != constants.PrimaryNetworkID && !m.WhitelistedSubnets.Contains(chainParams.SubnetID) {
+	if m.StakingEnabled && chainParams.SubnetID != constants.PrimaryNetworkID && !m.isWhitelisted(chainParams.SubnetID) {
 		m.Log.Debug("Skipped creating non-whitelisted chain:\n"+
 			"    ID: %s\n"+
 			"    VMID:%s",
@@ -254,6 +395,9 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 	if !exists {
 		sb = newSubnet()
 		m.subnets[chainParams.SubnetID] = sb
+		if m.DeferredDB != nil && chainParams.SubnetID == constants.PrimaryNetworkID {
+			go m.stopDeferringWrites(sb)
+		}
 	}
 
 	sb.addChain(chainParams.ID)
@@ -268,11 +412,22 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 			return
 		}
 		m.Log.Error("error creating chain %s: %s", chainParams.ID, err)
+
+		// Report the failure through the node's health so that it isn't only
+		// visible in the logs.
+		chainAlias, aliasErr := m.PrimaryAlias(chainParams.ID)
+		if aliasErr != nil {
+			chainAlias = chainParams.ID.String()
+		}
+		if err := m.Health.RegisterHealthCheck(chainAlias, failedChainCheck(err)); err != nil {
+			m.Log.Error("couldn't add health check for failed chain %s: %s", chainAlias, err)
+		}
 		return
 	}
 
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	m.reloadable[chainParams.ID] = chain.Reloadable
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
@@ -314,15 +469,24 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, fmt.Errorf("error while creating chain's log %w", err)
 	}
 
+	vmAlias, err := m.VMManager.PrimaryAlias(vmID)
+	if err != nil {
+		vmAlias = vmID.String()
+	}
+	metricsLabels := prometheus.Labels{
+		chainLabel:    primaryAlias,
+		chainIDLabel:  chainParams.ID.String(),
+		subnetIDLabel: chainParams.SubnetID.String(),
+		vmLabel:       vmAlias,
+	}
+
 	consensusMetrics := prometheus.NewRegistry()
-	chainNamespace := fmt.Sprintf("%s_%s", constants.PlatformName, primaryAlias)
-	if err := m.Metrics.Register(chainNamespace, consensusMetrics); err != nil {
+	if err := m.chainMetrics.Register(metricsLabels, consensusMetrics); err != nil {
 		return nil, fmt.Errorf("error while registering chain's metrics %w", err)
 	}
 
 	vmMetrics := metrics.NewOptionalGatherer()
-	vmNamespace := fmt.Sprintf("%s_vm", chainNamespace)
-	if err := m.Metrics.Register(vmNamespace, vmMetrics); err != nil {
+	if err := m.vmMetrics.Register(metricsLabels, vmMetrics); err != nil {
 		return nil, fmt.Errorf("error while registering vm's metrics %w", err)
 	}
 
@@ -351,6 +515,9 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		ConsensusDispatcher: m.ConsensusEvents,
 		Registerer:          consensusMetrics,
 	}
+	if err := ctx.Lock.InitMetrics("ctx_lock", consensusMetrics); err != nil {
+		return nil, fmt.Errorf("error while registering chain's lock metrics %w", err)
+	}
 
 	if sbConfigs, ok := m.SubnetConfigs[chainParams.SubnetID]; ok {
 		if sbConfigs.ValidatorOnly {
@@ -467,6 +634,17 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 // Implements Manager.AddRegistrant
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
+// stopDeferringWrites flushes the writes deferred in [m.DeferredDB] and stops
+// deferring writes once [sb] is bootstrapped.
+func (m *manager) stopDeferringWrites(sb Subnet) {
+	<-sb.afterBootstrapped()
+	if err := m.DeferredDB.Stop(); err != nil {
+		m.Log.Error("failed to flush the writes deferred while bootstrapping: %s", err)
+		return
+	}
+	m.Log.Info("primary network is bootstrapped. Database writes are no longer deferred")
+}
+
 func (m *manager) unblockChains() {
 	m.unblocked = true
 	blocked := m.blockedChains
@@ -491,11 +669,15 @@ func (m *manager) createAvalancheChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
+	// Limits the resources used by the handler of this chain, including the
+	// rate of writes to its database
+	quota := router.NewQuota(m.SubnetConfigs[ctx.SubnetID].Quota)
 	meterDBManager, err := m.DBManager.NewMeterDBManager("db", ctx.Registerer)
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
+	observedDBManager := meterDBManager.NewObservedDBManager(quota.ObserveDBWrite)
+	prefixDBManager := observedDBManager.NewPrefixDBManager(ctx.ChainID[:])
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
 	db := prefixDBManager.Current()
@@ -511,6 +693,12 @@ func (m *manager) createAvalancheChain(
 	if err != nil {
 		return nil, err
 	}
+	if m.DeferredDB != nil {
+		vtxBlocker.SetFlusher(m.DeferredDB, m.DeferredFlushFrequency)
+		txBlocker.SetFlusher(m.DeferredDB, m.DeferredFlushFrequency)
+	}
+	vtxBlocker.SetMaxAge(m.BootstrapQueueMaxAge)
+	txBlocker.SetMaxAge(m.BootstrapQueueMaxAge)
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
@@ -536,6 +724,13 @@ func (m *manager) createAvalancheChain(
 		return nil, fmt.Errorf("error while fetching chain config: %w", err)
 	}
 
+	reloadable := &reloadableChain{
+		ctx:    ctx,
+		vm:     vm,
+		db:     db.Database,
+		config: chainConfig.Config,
+	}
+
 	if m.MeterVMEnabled {
 		vm = metervm.NewVertexVM(vm)
 	}
@@ -572,10 +767,13 @@ func (m *manager) createAvalancheChain(
 	if err != nil {
 		return nil, fmt.Errorf("error initializing network handler: %w", err)
 	}
+	handler.SetQuota(quota)
+	handler.SetScheduler(m.Scheduler)
 
 	timer := &router.Timer{
 		Handler: handler,
 		Preempt: sb.afterBootstrapped(),
+		Wheel:   m.timeoutWheel,
 	}
 
 	commonCfg := common.Config{
@@ -593,6 +791,9 @@ func (m *manager) createAvalancheChain(
 		MaxTimeGetAncestors:            m.BootstrapMaxTimeGetAncestors,
 		AncestorsMaxContainersSent:     m.BootstrapAncestorsMaxContainersSent,
 		AncestorsMaxContainersReceived: m.BootstrapAncestorsMaxContainersReceived,
+		PeerScorer:                     m.PeerScorer,
+		OnFrontierDisagreement:         m.frontierDiagnosticRecorder(ctx.ChainID),
+		Events:                         m.DecisionEvents,
 		SharedCfg:                      &common.SharedConfig{},
 	}
 
@@ -620,6 +821,11 @@ func (m *manager) createAvalancheChain(
 	}
 	handler.RegisterBootstrap(bootstrapper)
 
+	querySampler, err := m.newQuerySampler(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing avalanche query sampler: %w", err)
+	}
+
 	// create engine gear
 	engineConfig := aveng.Config{
 		Ctx:           bootstrapperConfig.Ctx,
@@ -630,6 +836,8 @@ func (m *manager) createAvalancheChain(
 		Validators:    vdrs,
 		Params:        consensusParams,
 		Consensus:     &avcon.Topological{},
+		Events:        bootstrapperConfig.Events,
+		QuerySampler:  querySampler,
 	}
 	engine, err := aveng.New(engineConfig)
 	if err != nil {
@@ -660,10 +868,37 @@ func (m *manager) createAvalancheChain(
 		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
 	}
 
+	tracker := newAcceptanceTracker()
+	if err := m.ConsensusEvents.SubscribeChain(ctx.ChainID, "health", tracker, false); err != nil {
+		return nil, fmt.Errorf("couldn't track acceptance for chain %s: %w", chainAlias, err)
+	}
+	// The height of a DAG is the height of its highest accepted vertex
+	readiness := readinessCheck(ctx, tracker, func() (uint64, error) {
+		height := uint64(0)
+		for _, vtxID := range vtxManager.Edge() {
+			vtx, err := vtxManager.GetVtx(vtxID)
+			if err != nil {
+				return 0, err
+			}
+			vtxHeight, err := vtx.Height()
+			if err != nil {
+				return 0, err
+			}
+			if vtxHeight > height {
+				height = vtxHeight
+			}
+		}
+		return height, nil
+	})
+	if err := m.Health.RegisterReadinessCheck(chainAlias, readiness); err != nil {
+		return nil, fmt.Errorf("couldn't add readiness check for chain %s: %w", chainAlias, err)
+	}
+
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
+		Name:       chainAlias,
+		Engine:     engine,
+		Handler:    handler,
+		Reloadable: reloadable,
 	}, err
 }
 
@@ -682,11 +917,15 @@ func (m *manager) createSnowmanChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
+	// Limits the resources used by the handler of this chain, including the
+	// rate of writes to its database
+	quota := router.NewQuota(m.SubnetConfigs[ctx.SubnetID].Quota)
 	meterDBManager, err := m.DBManager.NewMeterDBManager("db", ctx.Registerer)
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
+	observedDBManager := meterDBManager.NewObservedDBManager(quota.ObserveDBWrite)
+	prefixDBManager := observedDBManager.NewPrefixDBManager(ctx.ChainID[:])
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
 	db := prefixDBManager.Current()
@@ -696,6 +935,10 @@ func (m *manager) createSnowmanChain(
 	if err != nil {
 		return nil, err
 	}
+	if m.DeferredDB != nil {
+		blocked.SetFlusher(m.DeferredDB, m.DeferredFlushFrequency)
+	}
+	blocked.SetMaxAge(m.BootstrapQueueMaxAge)
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
@@ -727,6 +970,12 @@ func (m *manager) createSnowmanChain(
 			// Initialize the validator state for future chains.
 			m.validatorState = validators.NewLockedState(&ctx.Lock, valState)
 
+			if tracker, ok := vm.(SubnetTracker); ok {
+				m.whitelistLock.Lock()
+				m.subnetTracker = tracker
+				m.whitelistLock.Unlock()
+			}
+
 			// Notice that this context is left unlocked. This is because the
 			// lock will already be held when accessing these values on the
 			// P-chain.
@@ -743,9 +992,19 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("error while fetching chain config: %w", err)
 	}
 
+	reloadable := &reloadableChain{
+		ctx:    ctx,
+		vm:     vm,
+		db:     db.Database,
+		config: chainConfig.Config,
+	}
+
 	// enable ProposerVM on this VM
 	vm = proposervm.New(vm, m.ApricotPhase4Time, m.ApricotPhase4MinPChainHeight)
 
+	// index the accepted blocks of this VM by height
+	vm = heightindexvm.New(vm)
+
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
 	}
@@ -777,10 +1036,13 @@ func (m *manager) createSnowmanChain(
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
 	}
+	handler.SetQuota(quota)
+	handler.SetScheduler(m.Scheduler)
 
 	timer := &router.Timer{
 		Handler: handler,
 		Preempt: sb.afterBootstrapped(),
+		Wheel:   m.timeoutWheel,
 	}
 
 	commonCfg := common.Config{
@@ -798,6 +1060,9 @@ func (m *manager) createSnowmanChain(
 		MaxTimeGetAncestors:            m.BootstrapMaxTimeGetAncestors,
 		AncestorsMaxContainersSent:     m.BootstrapAncestorsMaxContainersSent,
 		AncestorsMaxContainersReceived: m.BootstrapAncestorsMaxContainersReceived,
+		PeerScorer:                     m.PeerScorer,
+		OnFrontierDisagreement:         m.frontierDiagnosticRecorder(ctx.ChainID),
+		Events:                         m.DecisionEvents,
 		SharedCfg:                      &common.SharedConfig{},
 	}
 
@@ -824,15 +1089,23 @@ func (m *manager) createSnowmanChain(
 	}
 	handler.RegisterBootstrap(bootstrapper)
 
+	querySampler, err := m.newQuerySampler(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing snowman query sampler: %w", err)
+	}
+
 	// create engine gear
 	engineConfig := smeng.Config{
-		Ctx:           bootstrapCfg.Ctx,
-		AllGetsServer: snowGetHandler,
-		VM:            bootstrapCfg.VM,
-		Sender:        bootstrapCfg.Sender,
-		Validators:    vdrs,
-		Params:        consensusParams,
-		Consensus:     &smcon.Topological{},
+		Ctx:               bootstrapCfg.Ctx,
+		AllGetsServer:     snowGetHandler,
+		VM:                bootstrapCfg.VM,
+		Sender:            bootstrapCfg.Sender,
+		Validators:        vdrs,
+		Params:            consensusParams,
+		Consensus:         &smcon.Topological{},
+		Events:            bootstrapCfg.Events,
+		ChitCacheDuration: m.ChitCacheDuration,
+		QuerySampler:      querySampler,
 	}
 	engine, err := smeng.New(engineConfig)
 	if err != nil {
@@ -863,10 +1136,30 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
 	}
 
+	tracker := newAcceptanceTracker()
+	if err := m.ConsensusEvents.SubscribeChain(ctx.ChainID, "health", tracker, false); err != nil {
+		return nil, fmt.Errorf("couldn't track acceptance for chain %s: %w", chainAlias, err)
+	}
+	readiness := readinessCheck(ctx, tracker, func() (uint64, error) {
+		lastAcceptedID, err := vm.LastAccepted()
+		if err != nil {
+			return 0, err
+		}
+		lastAccepted, err := vm.GetBlock(lastAcceptedID)
+		if err != nil {
+			return 0, err
+		}
+		return lastAccepted.Height(), nil
+	})
+	if err := m.Health.RegisterReadinessCheck(chainAlias, readiness); err != nil {
+		return nil, fmt.Errorf("couldn't add readiness check for chain %s: %w", chainAlias, err)
+	}
+
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
+		Name:       chainAlias,
+		Engine:     engine,
+		Handler:    handler,
+		Reloadable: reloadable,
 	}, nil
 }
 
@@ -892,15 +1185,160 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Engine().IsBootstrapped()
 }
 
+// FrontierDiagnostic implements the Manager interface
+func (m *manager) FrontierDiagnostic(chainID ids.ID) (common.FrontierDiagnostic, bool) {
+	m.frontierDiagnosticsLock.Lock()
+	defer m.frontierDiagnosticsLock.Unlock()
+
+	diagnostic, ok := m.frontierDiagnostics[chainID]
+	return diagnostic, ok
+}
+
+// frontierDiagnosticRecorder returns a function that records the reports of
+// the beacons disagreeing about the accepted frontier of [chainID]
+func (m *manager) frontierDiagnosticRecorder(chainID ids.ID) func(common.FrontierDiagnostic) {
+	return func(diagnostic common.FrontierDiagnostic) {
+		m.frontierDiagnosticsLock.Lock()
+		defer m.frontierDiagnosticsLock.Unlock()
+
+		m.frontierDiagnostics[chainID] = diagnostic
+	}
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.Log.Info("shutting down chain manager")
 	m.ManagerConfig.Router.Shutdown()
+	m.timeoutWheel.Stop()
+	close(m.closer)
+}
+
+// TrackSubnet implements the Manager interface
+func (m *manager) TrackSubnet(subnetID ids.ID) error {
+	if err := m.checkTrackable(subnetID); err != nil {
+		return err
+	}
+
+	m.whitelistLock.Lock()
+	switch {
+	case m.stoppedSubnets.Contains(subnetID):
+		m.whitelistLock.Unlock()
+		return fmt.Errorf("%w: %s", errSubnetChainsStopped, subnetID)
+	case m.whitelistedSubnets.Contains(subnetID):
+		m.whitelistLock.Unlock()
+		return nil
+	}
+	m.whitelistedSubnets.Add(subnetID)
+	tracker := m.subnetTracker
+	m.whitelistLock.Unlock()
+
+	m.Log.Info("tracking subnet %s", subnetID)
+
+	// Peers must learn that this node tracks the subnet for the new chains to
+	// bootstrap from them
+	m.Net.TrackSubnet(subnetID)
+	if err := tracker.TrackSubnet(subnetID); err != nil {
+		m.whitelistLock.Lock()
+		m.whitelistedSubnets.Remove(subnetID)
+		m.whitelistLock.Unlock()
+
+		m.Net.UntrackSubnet(subnetID)
+		return fmt.Errorf("couldn't track subnet %s: %w", subnetID, err)
+	}
+	return nil
+}
+
+// UntrackSubnet implements the Manager interface
+func (m *manager) UntrackSubnet(subnetID ids.ID) error {
+	if err := m.checkTrackable(subnetID); err != nil {
+		return err
+	}
+
+	m.whitelistLock.Lock()
+	if !m.whitelistedSubnets.Contains(subnetID) {
+		m.whitelistLock.Unlock()
+		return nil
+	}
+	m.whitelistedSubnets.Remove(subnetID)
+	tracker := m.subnetTracker
+	m.whitelistLock.Unlock()
+
+	m.Log.Info("untracking subnet %s", subnetID)
+
+	if err := tracker.UntrackSubnet(subnetID); err != nil {
+		return fmt.Errorf("couldn't untrack subnet %s: %w", subnetID, err)
+	}
+	m.Net.UntrackSubnet(subnetID)
+
+	m.chainsLock.Lock()
+	stopped := []*router.Handler(nil)
+	for chainID, handler := range m.chains {
+		if handler.Context().SubnetID == subnetID {
+			stopped = append(stopped, handler)
+			delete(m.chains, chainID)
+			delete(m.reloadable, chainID)
+		}
+	}
+	m.chainsLock.Unlock()
+
+	if len(stopped) == 0 {
+		return nil
+	}
+
+	// The APIs, metrics and aliases of the stopped chains stay registered, so
+	// the chains can't be created again until the node restarts
+	m.whitelistLock.Lock()
+	m.stoppedSubnets.Add(subnetID)
+	m.whitelistLock.Unlock()
+
+	for _, handler := range stopped {
+		m.Log.Info("stopping chain %s of untracked subnet %s", handler.Context().ChainID, subnetID)
+		// The router stops routing messages to the chain once it's shut down
+		handler.StartShutdown()
+	}
+	return nil
+}
+
+// checkTrackable returns an error if the subnet with ID [subnetID] can't be
+// tracked or untracked at runtime
+func (m *manager) checkTrackable(subnetID ids.ID) error {
+	switch {
+	case !m.StakingEnabled:
+		return errAllSubnetsTracked
+	case subnetID == constants.PrimaryNetworkID:
+		return errPrimaryNetwork
+	}
+
+	m.whitelistLock.RLock()
+	defer m.whitelistLock.RUnlock()
+
+	if m.subnetTracker == nil {
+		return errNoSubnetTracker
+	}
+	return nil
+}
+
+// isWhitelisted returns true if the chains of the subnet with ID [subnetID]
+// should be created
+func (m *manager) isWhitelisted(subnetID ids.ID) bool {
+	m.whitelistLock.RLock()
+	defer m.whitelistLock.RUnlock()
+
+	return m.whitelistedSubnets.Contains(subnetID)
 }
 
 // LookupVM returns the ID of the VM associated with an alias
 func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.VMManager.Lookup(alias) }
 
+// newQuerySampler returns the sampler of the validators polled by the engine
+// of the chain with context [ctx]
+func (m *manager) newQuerySampler(ctx *snow.ConsensusContext) (common.QuerySampler, error) {
+	if !m.VRFQuerySampling {
+		return common.NewRandomQuerySampler(), nil
+	}
+	return common.NewVRFQuerySampler(ctx.Log, ctx.StakingLeafSigner, ctx.ChainID)
+}
+
 // Notify registrants [those who want to know about the creation of chains]
 // that the specified chain has been created
 func (m *manager) notifyRegistrants(name string, engine common.Engine) {
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/network"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+var _ SubnetTracker = &testSubnetTracker{}
+
+type testSubnetTracker struct {
+	tracked ids.Set
+	err     error
+}
+
+func (t *testSubnetTracker) TrackSubnet(subnetID ids.ID) error {
+	if t.err != nil {
+		return t.err
+	}
+	t.tracked.Add(subnetID)
+	return nil
+}
+
+func (t *testSubnetTracker) UntrackSubnet(subnetID ids.ID) error {
+	t.tracked.Remove(subnetID)
+	return nil
+}
+
+// testNetwork only implements the subnet tracking of a network
+type testNetwork struct {
+	network.Network
+	tracked ids.Set
+}
+
+func (n *testNetwork) TrackSubnet(subnetID ids.ID)   { n.tracked.Add(subnetID) }
+func (n *testNetwork) UntrackSubnet(subnetID ids.ID) { n.tracked.Remove(subnetID) }
+
+func TestTrackSubnet(t *testing.T) {
+	assert := assert.New(t)
+
+	net := &testNetwork{}
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			StakingEnabled: true,
+			Log:            logging.NoLog{},
+			Net:            net,
+		},
+	}
+	subnetID := ids.GenerateTestID()
+
+	assert.ErrorIs(m.TrackSubnet(subnetID), errNoSubnetTracker)
+
+	tracker := &testSubnetTracker{}
+	m.subnetTracker = tracker
+
+	assert.ErrorIs(m.TrackSubnet(constants.PrimaryNetworkID), errPrimaryNetwork)
+
+	assert.NoError(m.TrackSubnet(subnetID))
+	assert.True(m.isWhitelisted(subnetID))
+	assert.True(tracker.tracked.Contains(subnetID))
+	assert.True(net.tracked.Contains(subnetID))
+
+	assert.NoError(m.UntrackSubnet(subnetID))
+	assert.False(m.isWhitelisted(subnetID))
+	assert.False(tracker.tracked.Contains(subnetID))
+	assert.False(net.tracked.Contains(subnetID))
+
+	// The subnet isn't tracked if the P-chain can't track it
+	tracker.err = errors.New("unknown subnet")
+	assert.Error(m.TrackSubnet(subnetID))
+	assert.False(m.isWhitelisted(subnetID))
+	assert.False(net.tracked.Contains(subnetID))
+
+	m.StakingEnabled = false
+	assert.ErrorIs(m.TrackSubnet(subnetID), errAllSubnetsTracked)
+}
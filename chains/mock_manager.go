@@ -5,6 +5,7 @@ package chains
 
 import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 )
 
@@ -21,11 +22,24 @@ func (mm MockManager) AddRegistrant(Registrant)            {}
 func (mm MockManager) Aliases(ids.ID) ([]string, error)    { return nil, nil }
 func (mm MockManager) PrimaryAlias(ids.ID) (string, error) { return "", nil }
 func (mm MockManager) Alias(ids.ID, string) error          { return nil }
+func (mm MockManager) RemoveAlias(string) error            { return nil }
 func (mm MockManager) RemoveAliases(ids.ID)                {}
 func (mm MockManager) Shutdown()                           {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)     { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool          { return false }
 
+func (mm MockManager) ReloadChainConfig(ids.ID) ([]string, error) { return nil, nil }
+
+func (mm MockManager) PruneChain(ids.ID) (int, error) { return 0, nil }
+
+func (mm MockManager) TrackSubnet(ids.ID) error { return nil }
+
+func (mm MockManager) UntrackSubnet(ids.ID) error { return nil }
+
+func (mm MockManager) FrontierDiagnostic(ids.ID) (common.FrontierDiagnostic, bool) {
+	return common.FrontierDiagnostic{}, false
+}
+
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
 	if err == nil {
@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+)
+
+var (
+	errPruningDisabled  = errors.New("pruning is disabled")
+	errChainNotPrunable = errors.New("chain doesn't support pruning")
+)
+
+// PruningConfig configures the pruning of the history of the chains whose VMs
+// implement common.Pruner
+type PruningConfig struct {
+	// The history that was accepted more than [Retention] ago is deleted. If
+	// 0, chains are never pruned.
+	Retention time.Duration `json:"retention"`
+	// How often the chains are pruned. If 0, chains are only pruned when
+	// requested through the admin API.
+	Frequency time.Duration `json:"frequency"`
+}
+
+// PruneChain implements the Manager interface
+func (m *manager) PruneChain(chainID ids.ID) (int, error) {
+	if m.Pruning.Retention <= 0 {
+		return 0, errPruningDisabled
+	}
+
+	m.chainsLock.Lock()
+	chain, exists := m.reloadable[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return 0, errUnknownChainID
+	}
+
+	pruner, ok := chain.vm.(common.Pruner)
+	if !ok {
+		return 0, errChainNotPrunable
+	}
+	// The history of a chain may still be needed while it bootstraps
+	if !chain.ctx.IsBootstrapped() {
+		return 0, errChainNotBootstrapped
+	}
+
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	cutoff := time.Now().Add(-m.Pruning.Retention)
+	chain.ctx.Lock.Lock()
+	pruned, err := pruner.Prune(cutoff)
+	chain.ctx.Lock.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("couldn't prune chain %s: %w", chainID, err)
+	}
+	if pruned == 0 {
+		return 0, nil
+	}
+
+	// The deleted data only frees disk space once the database is compacted.
+	// The chain isn't locked while it's compacted, since compacting doesn't
+	// change the contents of the database.
+	if err := chain.db.Compact(nil, nil); err != nil {
+		return pruned, fmt.Errorf("couldn't compact database of chain %s: %w", chainID, err)
+	}
+	m.Log.Info("pruned %d containers accepted before %s from chain %s", pruned, cutoff, chainID)
+	return pruned, nil
+}
+
+// dispatchPruning prunes every chain that supports it every
+// [PruningConfig.Frequency] until the manager shuts down
+func (m *manager) dispatchPruning() {
+	t := time.NewTicker(m.Pruning.Frequency)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-m.closer:
+			return
+		case <-t.C:
+		}
+
+		m.chainsLock.Lock()
+		chainIDs := make([]ids.ID, 0, len(m.reloadable))
+		for chainID, chain := range m.reloadable {
+			if _, ok := chain.vm.(common.Pruner); ok {
+				chainIDs = append(chainIDs, chainID)
+			}
+		}
+		m.chainsLock.Unlock()
+
+		for _, chainID := range chainIDs {
+			_, err := m.PruneChain(chainID)
+			switch {
+			case errors.Is(err, errChainNotBootstrapped), errors.Is(err, errUnknownChainID):
+				// The chain will be pruned once it's bootstrapped, or was
+				// stopped since it was listed
+			case err != nil:
+				m.Log.Error("failed to prune chain %s: %s", chainID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+type prunableVM struct {
+	common.TestVM
+
+	pruned int
+	cutoff time.Time
+}
+
+func (vm *prunableVM) Prune(cutoff time.Time) (int, error) {
+	vm.cutoff = cutoff
+	return vm.pruned, nil
+}
+
+func TestPruneChain(t *testing.T) {
+	assert := assert.New(t)
+
+	newManager := func(retention time.Duration) *manager {
+		return &manager{
+			ManagerConfig: ManagerConfig{
+				Log:     logging.NoLog{},
+				Pruning: PruningConfig{Retention: retention},
+			},
+			reloadable: make(map[ids.ID]*reloadableChain),
+		}
+	}
+
+	_, err := newManager(0).PruneChain(ids.GenerateTestID())
+	assert.ErrorIs(err, errPruningDisabled)
+
+	m := newManager(time.Hour)
+	_, err = m.PruneChain(ids.GenerateTestID())
+	assert.ErrorIs(err, errUnknownChainID)
+
+	unprunableID := ids.GenerateTestID()
+	m.reloadable[unprunableID] = &reloadableChain{
+		ctx: snow.DefaultConsensusContextTest(),
+		vm:  &common.TestVM{},
+		db:  memdb.New(),
+	}
+	_, err = m.PruneChain(unprunableID)
+	assert.ErrorIs(err, errChainNotPrunable)
+
+	chainID := ids.GenerateTestID()
+	vm := &prunableVM{pruned: 5}
+	ctx := snow.DefaultConsensusContextTest()
+	m.reloadable[chainID] = &reloadableChain{
+		ctx: ctx,
+		vm:  vm,
+		db:  memdb.New(),
+	}
+	_, err = m.PruneChain(chainID)
+	assert.ErrorIs(err, errChainNotBootstrapped)
+
+	ctx.SetState(snow.NormalOp)
+	before := time.Now()
+	pruned, err := m.PruneChain(chainID)
+	after := time.Now()
+	assert.NoError(err)
+	assert.Equal(5, pruned)
+	// Only the history older than the retention window is pruned
+	assert.False(vm.cutoff.Before(before.Add(-time.Hour)))
+	assert.False(vm.cutoff.After(after.Add(-time.Hour)))
+}
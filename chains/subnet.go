@@ -4,11 +4,15 @@
 package chains
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/avalanche"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 )
 
 var _ Subnet = &subnet{}
@@ -29,6 +33,26 @@ type SubnetConfig struct {
 	// ValidatorOnly indicates that this Subnet's Chains are available to only subnet validators.
 	ValidatorOnly       bool                 `json:"validatorOnly"`
 	ConsensusParameters avalanche.Parameters `json:"consensusParameters"`
+	// OutboundBandwidth overrides the node-wide budget for the bandwidth used
+	// to send messages on behalf of this Subnet. If nil, the node-wide budget
+	// applies.
+	OutboundBandwidth *throttling.BandwidthThrottlerConfig `json:"outboundBandwidth,omitempty"`
+	// Quota limits the resources used by each of this Subnet's Chains before
+	// the router sheds their load. Unlimited by default.
+	Quota router.QuotaConfig `json:"quota"`
+}
+
+// Valid returns an error if this config isn't valid
+func (c *SubnetConfig) Valid() error {
+	if err := c.ConsensusParameters.Valid(); err != nil {
+		return err
+	}
+	if c.OutboundBandwidth != nil &&
+		c.OutboundBandwidth.RefillRate > 0 &&
+		c.OutboundBandwidth.MaxBurstSize < constants.DefaultMaxMessageSize {
+		return fmt.Errorf("outbound bandwidth max burst size must be >= %d", constants.DefaultMaxMessageSize)
+	}
+	return c.Quota.Valid()
 }
 
 type subnet struct {
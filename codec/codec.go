@@ -8,5 +8,8 @@ import "github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 // Codec marshals and unmarshals
 type Codec interface {
 	MarshalInto(interface{}, *wrappers.Packer) error
+	// Unmarshal the bytes into the destination. Byte slices in the destination
+	// alias the provided bytes rather than being copied out of them, so the
+	// bytes must not be modified while the destination is in use.
 	Unmarshal([]byte, interface{}) error
 }
@@ -49,6 +49,9 @@ type Manager interface {
 	// Unmarshal the given bytes into the given destination. [destination] must
 	// be a pointer or an interface. Returns the version of the codec that
 	// produces the given bytes.
+	// Byte slices in [destination] alias [source] rather than being copied out
+	// of it. This avoids allocations for callers that treat the result as
+	// read-only. [source] must not be modified while [destination] is in use.
 	Unmarshal(source []byte, destination interface{}) (version uint16, err error)
 }
 
@@ -115,7 +118,7 @@ func (m *manager) Marshal(version uint16, value interface{}) ([]byte, error) {
 }
 
 // Unmarshal unmarshals [bytes] into [dest], where [dest] must be a pointer or
-// interface.
+// interface. Byte slices in [dest] alias [bytes].
 func (m *manager) Unmarshal(bytes []byte, dest interface{}) (uint16, error) {
 	if dest == nil {
 		return 0, errUnmarshalNil
@@ -191,7 +191,10 @@ func (c *genericCodec) marshal(value reflect.Value, p *wrappers.Packer, maxSlice
 }
 
 // Unmarshal unmarshals [bytes] into [dest], where
-// [dest] must be a pointer or interface
+// [dest] must be a pointer or interface.
+// Byte slices in [dest] alias [bytes] rather than being copied out of it. The
+// caller must not modify [bytes] while [dest] is in use, and must not modify
+// the byte slices in [dest].
 func (c *genericCodec) Unmarshal(bytes []byte, dest interface{}) error {
 	if dest == nil {
 		return errUnmarshalNil
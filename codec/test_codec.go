@@ -37,6 +37,7 @@ var Tests = []func(c GeneralCodec, t testing.TB){
 	TestRestrictedSlice,
 	TestExtraSpace,
 	TestSliceLengthOverflow,
+	TestUnmarshalAliasesBytes,
 }
 
 // The below structs and interfaces exist
@@ -993,3 +994,38 @@ func TestSliceLengthOverflow(codec GeneralCodec, t testing.TB) {
 		t.Fatalf("Should have errored due to large of a slice")
 	}
 }
+
+// Ensure that unmarshaled byte slices alias the source bytes
+func TestUnmarshalAliasesBytes(codec GeneralCodec, t testing.TB) {
+	var _ GeneralCodec = codec
+
+	type inner struct {
+		Val []byte `serialize:"true"`
+	}
+	bytes := []byte{
+		// Codec Version:
+		0x00, 0x00,
+		// Slice Length:
+		0x00, 0x00, 0x00, 0x02,
+		// Slice Contents:
+		0x01, 0x02,
+	}
+
+	manager := NewDefaultManager()
+	if err := manager.RegisterCodec(0, codec); err != nil {
+		t.Fatal(err)
+	}
+
+	s := inner{}
+	if _, err := manager.Unmarshal(bytes, &s); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0x01, 0x02}; !reflect.DeepEqual(s.Val, expected) {
+		t.Fatalf("Unmarshaled wrong value, expected %v but got %v", expected, s.Val)
+	}
+
+	bytes[len(bytes)-1] = 0xff
+	if expected := []byte{0x01, 0xff}; !reflect.DeepEqual(s.Val, expected) {
+		t.Fatalf("Unmarshaled bytes should alias the source, expected %v but got %v", expected, s.Val)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package unioncodec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/codec"
+	"github.com/Toinounet21/avalanchego-mod/codec/reflectcodec"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const (
+	// default max length of a slice being marshalled by Marshal(). Should be <= math.MaxUint32.
+	defaultMaxSliceLength = 256 * 1024
+
+	// DefaultUnionName is the name of the union that RegisterType registers
+	// types in
+	DefaultUnionName = "default"
+	// DefaultUnionIndex is the index of the union that RegisterType registers
+	// types in
+	DefaultUnionIndex = 0
+)
+
+var (
+	errDuplicateUnionName  = errors.New("duplicate union name")
+	errDuplicateUnionIndex = errors.New("duplicate union index")
+	errNoMaxTypes          = errors.New("union must allow at least one type")
+	errUnionFull           = errors.New("union is full")
+	errNotImplemented      = errors.New("type doesn't implement the union's interface")
+
+	_ Codec              = &unionCodec{}
+	_ codec.Codec        = &unionCodec{}
+	_ codec.Registry     = &unionCodec{}
+	_ codec.GeneralCodec = &unionCodec{}
+	_ Union              = &union{}
+)
+
+// Codec marshals and unmarshals
+type Codec interface {
+	codec.Registry
+	codec.Codec
+
+	// RegisterUnion creates the union [name], whose types are identified by
+	// [index] followed by their index in the union. At most [maxTypes] types
+	// may be registered in the union. If [intf] is a pointer to an interface,
+	// only implementations of that interface may be registered in the union.
+	RegisterUnion(name string, index uint16, maxTypes uint16, intf interface{}) (Union, error)
+
+	// Union returns the union [name], if it was registered
+	Union(name string) (Union, bool)
+}
+
+// Union is a named group of types. Types are numbered from 0 in the order
+// they're registered in their union, independently of other unions, so that
+// the types of each union keep their IDs when other unions register types.
+type Union interface {
+	codec.Registry
+
+	// SkipRegistrations skips some number of type indices of this union
+	SkipRegistrations(int)
+}
+
+type typeID struct {
+	unionIndex uint16
+	typeIndex  uint16
+}
+
+// Codec handles marshaling and unmarshaling of structs
+type unionCodec struct {
+	codec.Codec
+
+	lock         sync.RWMutex
+	defaultUnion *union
+	nameToUnion  map[string]*union
+	indexToUnion map[uint16]*union
+	typeIDToType map[typeID]reflect.Type
+	typeToTypeID map[reflect.Type]typeID
+}
+
+type union struct {
+	codec *unionCodec
+
+	name          string
+	index         uint16
+	maxTypes      uint16
+	intf          reflect.Type
+	nextTypeIndex int
+}
+
+// New returns a new, concurrency-safe codec
+func New(tagName string, maxSliceLen uint32) Codec {
+	uCodec := &unionCodec{
+		nameToUnion:  map[string]*union{},
+		indexToUnion: map[uint16]*union{},
+		typeIDToType: map[typeID]reflect.Type{},
+		typeToTypeID: map[reflect.Type]typeID{},
+	}
+	uCodec.Codec = reflectcodec.New(uCodec, tagName, maxSliceLen)
+	uCodec.defaultUnion = uCodec.newUnion(DefaultUnionName, DefaultUnionIndex, math.MaxUint16, nil)
+	return uCodec
+}
+
+// NewDefault returns a new codec with reasonable default values
+func NewDefault() Codec { return New(reflectcodec.DefaultTagName, defaultMaxSliceLength) }
+
+// RegisterType registers [val] in the default union
+func (c *unionCodec) RegisterType(val interface{}) error {
+	return c.defaultUnion.RegisterType(val)
+}
+
+func (c *unionCodec) RegisterUnion(name string, index uint16, maxTypes uint16, intf interface{}) (Union, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, exists := c.nameToUnion[name]; exists {
+		return nil, fmt.Errorf("%w: %q", errDuplicateUnionName, name)
+	}
+	if existing, exists := c.indexToUnion[index]; exists {
+		return nil, fmt.Errorf("%w: %d is used by %q", errDuplicateUnionIndex, index, existing.name)
+	}
+	if maxTypes == 0 {
+		return nil, fmt.Errorf("%w: %q", errNoMaxTypes, name)
+	}
+
+	var intfType reflect.Type
+	if intf != nil {
+		intfType = reflect.TypeOf(intf).Elem()
+	}
+	return c.newUnion(name, index, maxTypes, intfType), nil
+}
+
+func (c *unionCodec) Union(name string) (Union, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	u, ok := c.nameToUnion[name]
+	return u, ok
+}
+
+// Assumes [c.lock] is held or that [c] isn't shared yet
+func (c *unionCodec) newUnion(name string, index uint16, maxTypes uint16, intf reflect.Type) *union {
+	u := &union{
+		codec:    c,
+		name:     name,
+		index:    index,
+		maxTypes: maxTypes,
+		intf:     intf,
+	}
+	c.nameToUnion[name] = u
+	c.indexToUnion[index] = u
+	return u
+}
+
+func (u *union) SkipRegistrations(num int) {
+	u.codec.lock.Lock()
+	u.nextTypeIndex += num
+	u.codec.lock.Unlock()
+}
+
+// RegisterType is used to register types that may be unmarshaled into an interface
+// [val] is a value of the type being registered
+func (u *union) RegisterType(val interface{}) error {
+	u.codec.lock.Lock()
+	defer u.codec.lock.Unlock()
+
+	valType := reflect.TypeOf(val)
+	if _, exists := u.codec.typeToTypeID[valType]; exists {
+		return fmt.Errorf("type %v has already been registered", valType)
+	}
+	if u.nextTypeIndex >= int(u.maxTypes) {
+		return fmt.Errorf("%w: %q allows %d types", errUnionFull, u.name, u.maxTypes)
+	}
+	if u.intf != nil && !valType.Implements(u.intf) {
+		return fmt.Errorf("%w: %s doesn't implement %s of %q", errNotImplemented, valType, u.intf, u.name)
+	}
+
+	valTypeID := typeID{
+		unionIndex: u.index,
+		typeIndex:  uint16(u.nextTypeIndex),
+	}
+	u.nextTypeIndex++
+
+	u.codec.typeIDToType[valTypeID] = valType
+	u.codec.typeToTypeID[valType] = valTypeID
+	return nil
+}
+
+func (c *unionCodec) PackPrefix(p *wrappers.Packer, valueType reflect.Type) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	typeID, ok := c.typeToTypeID[valueType] // Get the type ID of the value being marshaled
+	if !ok {
+		return fmt.Errorf("can't marshal unregistered type %q", valueType)
+	}
+	// Pack type ID so we know what to unmarshal this into
+	p.PackShort(typeID.unionIndex)
+	p.PackShort(typeID.typeIndex)
+	return p.Err
+}
+
+func (c *unionCodec) UnpackPrefix(p *wrappers.Packer, valueType reflect.Type) (reflect.Value, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	unionIndex := p.UnpackShort() // Get the union index
+	typeIndex := p.UnpackShort()  // Get the type index in the union
+	if p.Err != nil {
+		return reflect.Value{}, fmt.Errorf("couldn't unmarshal interface: %w", p.Err)
+	}
+	t := typeID{
+		unionIndex: unionIndex,
+		typeIndex:  typeIndex,
+	}
+	// Get a type that implements the interface
+	implementingType, ok := c.typeIDToType[t]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("couldn't unmarshal interface: unknown type ID %+v", t)
+	}
+	// Ensure type actually does implement the interface
+	if !implementingType.Implements(valueType) {
+		return reflect.Value{}, fmt.Errorf("couldn't unmarshal interface: %s does not implement interface %s", implementingType, valueType)
+	}
+	return reflect.New(implementingType).Elem(), nil // instance of the proper type
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package unioncodec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/codec"
+)
+
+func TestVectors(t *testing.T) {
+	for _, test := range codec.Tests {
+		c := NewDefault()
+		test(c, t)
+	}
+}
+
+type notFoo struct{}
+
+func TestUnions(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewDefault()
+	fxUnion, err := c.RegisterUnion("fx", 5, 1, (*codec.Foo)(nil))
+	assert.NoError(err)
+	pluginUnion, err := c.RegisterUnion("plugin", 6, 2, nil)
+	assert.NoError(err)
+
+	_, err = c.RegisterUnion("fx", 7, 1, nil)
+	assert.True(errors.Is(err, errDuplicateUnionName))
+	_, err = c.RegisterUnion("other", DefaultUnionIndex, 1, nil)
+	assert.True(errors.Is(err, errDuplicateUnionIndex))
+	_, err = c.RegisterUnion("empty", 7, 0, nil)
+	assert.True(errors.Is(err, errNoMaxTypes))
+
+	err = fxUnion.RegisterType(&notFoo{})
+	assert.True(errors.Is(err, errNotImplemented))
+	assert.NoError(fxUnion.RegisterType(&codec.MyInnerStruct{}))
+	err = fxUnion.RegisterType(&codec.MyInnerStruct2{})
+	assert.True(errors.Is(err, errUnionFull))
+
+	// Types registered in other unions don't change the IDs of this union's
+	// types
+	pluginUnion.SkipRegistrations(1)
+	assert.NoError(pluginUnion.RegisterType(&codec.MyInnerStruct2{}))
+	assert.Error(c.RegisterType(&codec.MyInnerStruct2{}))
+
+	manager := codec.NewDefaultManager()
+	assert.NoError(manager.RegisterCodec(0, c))
+
+	var f codec.Foo = &codec.MyInnerStruct{Str: "fx"}
+	bytes, err := manager.Marshal(0, &f)
+	assert.NoError(err)
+	// codec version, union index, type index, string length, string
+	assert.Equal([]byte{0, 0, 0, 5, 0, 0, 0, 2, 'f', 'x'}, bytes)
+
+	f = &codec.MyInnerStruct2{Bool: true}
+	bytes, err = manager.Marshal(0, &f)
+	assert.NoError(err)
+	assert.Equal([]byte{0, 0, 0, 6, 0, 1, 1}, bytes)
+
+	var unmarshaledFoo codec.Foo
+	_, err = manager.Unmarshal(bytes, &unmarshaledFoo)
+	assert.NoError(err)
+	assert.Equal(f, unmarshaledFoo)
+
+	union, ok := c.Union("plugin")
+	assert.True(ok)
+	assert.Equal(pluginUnion, union)
+	_, ok = c.Union("missing")
+	assert.False(ok)
+}
@@ -4,6 +4,7 @@
 package config
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
@@ -19,14 +20,21 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/Toinounet21/avalanchego-mod/api/ratelimit"
+	"github.com/Toinounet21/avalanchego-mod/api/server"
 	"github.com/Toinounet21/avalanchego-mod/app/runner"
+	"github.com/Toinounet21/avalanchego-mod/backup"
 	"github.com/Toinounet21/avalanchego-mod/chains"
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/leveldb"
+	"github.com/Toinounet21/avalanchego-mod/database/vaultdb"
 	"github.com/Toinounet21/avalanchego-mod/genesis"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/ipcs"
 	"github.com/Toinounet21/avalanchego-mod/nat"
 	"github.com/Toinounet21/avalanchego-mod/network"
 	"github.com/Toinounet21/avalanchego-mod/network/dialer"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/node"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/avalanche"
@@ -35,15 +43,21 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 	"github.com/Toinounet21/avalanchego-mod/staking"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/crashreport"
 	"github.com/Toinounet21/avalanchego-mod/utils/dynamicip"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/password"
 	"github.com/Toinounet21/avalanchego-mod/utils/profiler"
 	"github.com/Toinounet21/avalanchego-mod/utils/storage"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/Toinounet21/avalanchego-mod/utils/ulimit"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+	"github.com/Toinounet21/avalanchego-mod/version"
 	"github.com/Toinounet21/avalanchego-mod/vms"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm"
 )
 
 const (
@@ -56,10 +70,12 @@ const (
 var (
 	deprecatedKeys = map[string]string{
 		InboundConnUpgradeThrottlerMaxRecentKey: fmt.Sprintf("please use --%s to specify connection upgrade throttling", InboundThrottlerMaxConnsPerSecKey),
+		ConsensusGossipOnAcceptSizeKey:          fmt.Sprintf("please use --%s and --%s to bound the number of peers accepted containers are gossiped to", ConsensusGossipOnAcceptMinSizeKey, ConsensusGossipOnAcceptMaxSizeKey),
 	}
 
 	errInvalidStakerWeights          = errors.New("staking weights must be positive")
 	errStakingDisableOnPublicNetwork = errors.New("staking disabled on public network")
+	errUpgradeConfigOnPublicNetwork  = errors.New("upgrade times can't be changed on a public network")
 	errAuthPasswordTooWeak           = errors.New("API auth password is not strong enough")
 	errInvalidUptimeRequirement      = errors.New("uptime requirement must be in the range [0, 1]")
 	errMinValidatorStakeAboveMax     = errors.New("minimum validator stake can't be greater than maximum validator stake")
@@ -71,11 +87,16 @@ var (
 	errCannotWhitelistPrimaryNetwork = errors.New("cannot whitelist primary network")
 	errStakingKeyContentUnset        = fmt.Errorf("%s key not set but %s set", StakingKeyContentKey, StakingCertContentKey)
 	errStakingCertContentUnset       = fmt.Errorf("%s key set but %s not set", StakingKeyContentKey, StakingCertContentKey)
+	errStakingKeyPKCS11Conflict      = fmt.Errorf("%s can't be set with %s or %s", StakingKeyPKCS11ModuleKey, StakingKeyPathKey, StakingKeyContentKey)
+	errRestoreNetworkMismatch        = errors.New("backup is of a different network")
+	errRestoreStakingKeyNotInFile    = fmt.Errorf("the staking key of a backup can only be restored to %s", StakingKeyPathKey)
+	errRestoreNodeIDMismatch         = errors.New("staking key isn't the one of the backup")
 )
 
 func GetRunnerConfig(v *viper.Viper) (runner.Config, error) {
 	config := runner.Config{
 		DisplayVersionAndExit: v.GetBool(VersionKey),
+		ValidateConfigAndExit: v.GetBool(ConfigValidateKey),
 		BuildDir:              os.ExpandEnv(v.GetString(BuildDirKey)),
 		PluginMode:            v.GetBool(PluginModeKey),
 	}
@@ -155,7 +176,33 @@ func getLoggingConfig(v *viper.Viper) (logging.Config, error) {
 		return loggingConfig, err
 	}
 	loggingConfig.DisplayHighlight, err = logging.ToHighlight(v.GetString(LogDisplayHighlightKey), os.Stdout.Fd())
-	return loggingConfig, err
+	if err != nil {
+		return loggingConfig, err
+	}
+	loggingConfig.LogFormat, err = logging.ToFormat(v.GetString(LogFormatKey))
+	if err != nil {
+		return loggingConfig, err
+	}
+	loggingConfig.FileSize = int(v.GetUint(LogRotaterMaxSizeKey) * units.MiB)
+	loggingConfig.RotationSize = int(v.GetUint(LogRotaterMaxFilesKey))
+	loggingConfig.MaxAge = v.GetDuration(LogRotaterMaxAgeKey)
+	if loggingConfig.MaxAge < 0 {
+		return loggingConfig, fmt.Errorf("%s must be >= 0", LogRotaterMaxAgeKey)
+	}
+	loggingConfig.Compress = v.GetBool(LogRotaterCompressEnabledKey)
+	return loggingConfig, nil
+}
+
+func getCrashReportConfig(v *viper.Viper, logsDir string) crashreport.Config {
+	config := crashreport.Config{
+		Enabled:   v.GetBool(CrashReportEnabledKey),
+		Directory: filepath.Join(logsDir, "crash-reports"),
+		UploadURL: v.GetString(CrashReportUploadURLKey),
+	}
+	if v.IsSet(CrashReportDirKey) {
+		config.Directory = os.ExpandEnv(v.GetString(CrashReportDirKey))
+	}
+	return config
 }
 
 func getAPIAuthConfig(v *viper.Viper) (node.APIAuthConfig, error) {
@@ -236,22 +283,47 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			APIIndexerConfig: node.APIIndexerConfig{
 				IndexAPIEnabled:      v.GetBool(IndexEnabledKey),
 				IndexAllowIncomplete: v.GetBool(IndexAllowIncompleteKey),
+				IndexChains:          v.GetStringSlice(IndexChainsKey),
 			},
 			AdminAPIEnabled:    v.GetBool(AdminAPIEnabledKey),
 			InfoAPIEnabled:     v.GetBool(InfoAPIEnabledKey),
 			KeystoreAPIEnabled: v.GetBool(KeystoreAPIEnabledKey),
 			MetricsAPIEnabled:  v.GetBool(MetricsAPIEnabledKey),
 			HealthAPIEnabled:   v.GetBool(HealthAPIEnabledKey),
+			EventsAPIEnabled:   v.GetBool(EventsAPIEnabledKey),
+			GraphQLAPIEnabled:  v.GetBool(GraphQLAPIEnabledKey),
+		},
+		HTTPHost:     v.GetString(HTTPHostKey),
+		HTTPPort:     uint16(v.GetUint(HTTPPortKey)),
+		HTTPSEnabled: v.GetBool(HTTPSEnabledKey),
+		HTTPSKey:     httpsKey,
+		HTTPSCert:    httpsCert,
+		CORSConfig: server.CORSConfig{
+			AllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
+			AllowedMethods: v.GetStringSlice(HTTPAllowedMethodsKey),
+			AllowedHeaders: v.GetStringSlice(HTTPAllowedHeadersKey),
+			ExposedHeaders: v.GetStringSlice(HTTPExposedHeadersKey),
+			MaxAge:         v.GetDuration(HTTPCORSMaxAgeKey),
 		},
-		HTTPHost:          v.GetString(HTTPHostKey),
-		HTTPPort:          uint16(v.GetUint(HTTPPortKey)),
-		HTTPSEnabled:      v.GetBool(HTTPSEnabledKey),
-		HTTPSKey:          httpsKey,
-		HTTPSCert:         httpsCert,
-		APIAllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
 
 		ShutdownTimeout: v.GetDuration(HTTPShutdownTimeoutKey),
 		ShutdownWait:    v.GetDuration(HTTPShutdownWaitKey),
+		APIMaxBatchSize: int(v.GetUint(APIMaxBatchSizeKey)),
+	}
+
+	config.KeystoreHashParams, err = getKeystoreHashParams(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	config.KeystoreUserQuota = v.GetUint64(KeystoreUserQuotaKey)
+	config.KeystoreTOTPUnlockDuration = v.GetDuration(KeystoreTOTPUnlockDurationKey)
+	if config.KeystoreTOTPUnlockDuration <= 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%q must be positive", KeystoreTOTPUnlockDurationKey)
+	}
+	config.KeystoreBackend = v.GetString(KeystoreBackendKey)
+	config.KeystoreVaultConfig, err = getKeystoreVaultConfig(v, config.KeystoreBackend)
+	if err != nil {
+		return node.HTTPConfig{}, err
 	}
 
 	config.APIAuthConfig, err = getAPIAuthConfig(v)
@@ -259,6 +331,93 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		return node.HTTPConfig{}, err
 	}
 	config.IPCConfig = getIPCConfig(v)
+
+	config.APIRateLimits, err = getAPIRateLimits(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	return config, nil
+}
+
+func getAPIRateLimits(v *viper.Viper) (ratelimit.Config, error) {
+	var (
+		config      ratelimit.Config
+		configBytes []byte
+		err         error
+	)
+	switch {
+	case v.IsSet(APIRateLimitsContentKey):
+		configBytes, err = base64.StdEncoding.DecodeString(v.GetString(APIRateLimitsContentKey))
+		if err != nil {
+			return config, fmt.Errorf("unable to decode base64 content: %w", err)
+		}
+	case v.GetString(APIRateLimitsFileKey) != "":
+		configBytes, err = ioutil.ReadFile(filepath.Clean(os.ExpandEnv(v.GetString(APIRateLimitsFileKey))))
+		if err != nil {
+			return config, err
+		}
+	default:
+		return config, nil
+	}
+
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return config, fmt.Errorf("problem unmarshaling API rate limits: %w", err)
+	}
+	if err := config.Verify(); err != nil {
+		return config, fmt.Errorf("invalid API rate limits: %w", err)
+	}
+	return config, nil
+}
+
+func getKeystoreHashParams(v *viper.Viper) (password.HashParams, error) {
+	passes := v.GetUint(KeystoreArgon2TimeKey)
+	memory := v.GetUint(KeystoreArgon2MemoryKey)
+	threads := v.GetUint(KeystoreArgon2ThreadsKey)
+	switch {
+	case passes > math.MaxUint32:
+		return password.HashParams{}, fmt.Errorf("%q must be <= %d", KeystoreArgon2TimeKey, math.MaxUint32)
+	case memory > math.MaxUint32:
+		return password.HashParams{}, fmt.Errorf("%q must be <= %d", KeystoreArgon2MemoryKey, math.MaxUint32)
+	case threads > math.MaxUint8:
+		return password.HashParams{}, fmt.Errorf("%q must be <= %d", KeystoreArgon2ThreadsKey, math.MaxUint8)
+	}
+	params := password.HashParams{
+		Time:    uint32(passes),
+		Memory:  uint32(memory),
+		Threads: uint8(threads),
+	}
+	if err := params.Verify(); err != nil {
+		return password.HashParams{}, fmt.Errorf("invalid keystore password hashing parameters: %w", err)
+	}
+	return params, nil
+}
+
+func getKeystoreVaultConfig(v *viper.Viper, backend string) (vaultdb.Config, error) {
+	switch backend {
+	case node.KeystoreBackendLocal:
+		return vaultdb.Config{}, nil
+	case node.KeystoreBackendVault:
+	default:
+		return vaultdb.Config{}, fmt.Errorf("%q must be one of %q or %q but is %q", KeystoreBackendKey, node.KeystoreBackendLocal, node.KeystoreBackendVault, backend)
+	}
+
+	config := vaultdb.Config{
+		Address:        v.GetString(KeystoreVaultAddressKey),
+		Mount:          v.GetString(KeystoreVaultMountKey),
+		Path:           v.GetString(KeystoreVaultPathKey),
+		RequestTimeout: v.GetDuration(KeystoreVaultRequestTimeoutKey),
+	}
+	tokenFilePath := v.GetString(KeystoreVaultTokenFileKey)
+	if tokenFilePath != "" {
+		tokenBytes, err := ioutil.ReadFile(filepath.Clean(os.ExpandEnv(tokenFilePath)))
+		if err != nil {
+			return vaultdb.Config{}, fmt.Errorf("vault token file %q failed to be read: %w", tokenFilePath, err)
+		}
+		config.Token = strings.TrimSpace(string(tokenBytes))
+	}
+	if err := config.Verify(); err != nil {
+		return vaultdb.Config{}, fmt.Errorf("invalid keystore vault config: %w", err)
+	}
 	return config, nil
 }
 
@@ -288,6 +447,12 @@ func getAdaptiveTimeoutConfig(v *viper.Viper) (timer.AdaptiveTimeoutConfig, erro
 		MaximumTimeout:     v.GetDuration(NetworkMaximumTimeoutKey),
 		TimeoutHalflife:    v.GetDuration(NetworkTimeoutHalflifeKey),
 		TimeoutCoefficient: v.GetFloat64(NetworkTimeoutCoefficientKey),
+
+		TimeoutAlgorithm:            v.GetString(NetworkTimeoutAlgorithmKey),
+		TimeoutPercentile:           v.GetFloat64(NetworkTimeoutPercentileKey),
+		TimeoutPercentileWindowSize: v.GetInt(NetworkTimeoutPercentileWindowSizeKey),
+		TimeoutIncreaseCoefficient:  v.GetFloat64(NetworkTimeoutIncreaseCoefficientKey),
+		TimeoutDecreaseCoefficient:  v.GetFloat64(NetworkTimeoutDecreaseCoefficientKey),
 	}
 	switch {
 	case config.MinimumTimeout < 1:
@@ -300,6 +465,16 @@ func getAdaptiveTimeoutConfig(v *viper.Viper) (timer.AdaptiveTimeoutConfig, erro
 		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must > 0", NetworkTimeoutHalflifeKey)
 	case config.TimeoutCoefficient < 1:
 		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must be >= 1", NetworkTimeoutCoefficientKey)
+	case config.TimeoutAlgorithm != timer.AverageTimeoutAlgorithm && config.TimeoutAlgorithm != timer.PercentileTimeoutAlgorithm:
+		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must be one of {%s, %s}", NetworkTimeoutAlgorithmKey, timer.AverageTimeoutAlgorithm, timer.PercentileTimeoutAlgorithm)
+	case config.TimeoutPercentile <= 0 || config.TimeoutPercentile > 1:
+		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must be in (0, 1]", NetworkTimeoutPercentileKey)
+	case config.TimeoutPercentileWindowSize <= 0:
+		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must be positive", NetworkTimeoutPercentileWindowSizeKey)
+	case config.TimeoutIncreaseCoefficient < 0 || config.TimeoutIncreaseCoefficient > 1:
+		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must be in [0, 1]", NetworkTimeoutIncreaseCoefficientKey)
+	case config.TimeoutDecreaseCoefficient < 0 || config.TimeoutDecreaseCoefficient > 1:
+		return timer.AdaptiveTimeoutConfig{}, fmt.Errorf("%q must be in [0, 1]", NetworkTimeoutDecreaseCoefficientKey)
 	}
 
 	return config, nil
@@ -312,6 +487,15 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 	upgradeCooldown := v.GetDuration(InboundConnUpgradeThrottlerCooldownKey)
 	upgradeCooldownInSeconds := upgradeCooldown.Seconds()
 	maxRecentConnsUpgraded := int(math.Ceil(maxInboundConnsPerSec * upgradeCooldownInSeconds))
+
+	compressionType, err := compression.TypeFromString(v.GetString(NetworkCompressionTypeKey))
+	if err != nil {
+		return network.Config{}, fmt.Errorf("couldn't parse %s: %w", NetworkCompressionTypeKey, err)
+	}
+	if compressionType == compression.TypeNone {
+		return network.Config{}, fmt.Errorf("%s must be %s or %s; use %s to disable compression", NetworkCompressionTypeKey, compression.TypeGzip, compression.TypeZstd, NetworkCompressionEnabledKey)
+	}
+
 	config := network.Config{
 		// Throttling
 		ThrottlerConfig: network.ThrottlerConfig{
@@ -320,6 +504,10 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 				UpgradeCooldown:        upgradeCooldown,
 				MaxRecentConnsUpgraded: maxRecentConnsUpgraded,
 			},
+			InboundConnAttemptThrottlerConfig: throttling.InboundConnAttemptThrottlerConfig{
+				MaxAttemptsPerIPPerMinute:     v.GetUint32(InboundConnAttemptThrottlerMaxPerIPKey),
+				MaxAttemptsPerSubnetPerMinute: v.GetUint32(InboundConnAttemptThrottlerMaxPerSubnetKey),
+			},
 
 			InboundMsgThrottlerConfig: throttling.InboundMsgThrottlerConfig{
 				MsgByteThrottlerConfig: throttling.MsgByteThrottlerConfig{
@@ -331,6 +519,11 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 					RefillRate:   v.GetUint64(InboundThrottlerBandwidthRefillRateKey),
 					MaxBurstSize: v.GetUint64(InboundThrottlerBandwidthMaxBurstSizeKey),
 				},
+				CPUThrottlerConfig: throttling.CPUThrottlerConfig{
+					VdrAlloc:         v.GetFloat64(InboundThrottlerCPUVdrAllocKey),
+					NodeAtLargeAlloc: v.GetFloat64(InboundThrottlerCPUNodeAtLargeAllocKey),
+					MaxRecheckDelay:  v.GetDuration(InboundThrottlerCPUMaxRecheckDelayKey),
+				},
 				MaxProcessingMsgsPerNode: v.GetUint64(InboundThrottlerMaxProcessingMsgsPerNodeKey),
 			},
 
@@ -339,15 +532,35 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 				VdrAllocSize:        v.GetUint64(OutboundThrottlerVdrAllocSizeKey),
 				NodeMaxAtLargeBytes: v.GetUint64(OutboundThrottlerNodeMaxAtLargeBytesKey),
 			},
+
+			OutboundSubnetBandwidthConfig: throttling.OutboundSubnetBandwidthThrottlerConfig{
+				BandwidthThrottlerConfig: throttling.BandwidthThrottlerConfig{
+					RefillRate:   v.GetUint64(OutboundSubnetBandwidthRefillRateKey),
+					MaxBurstSize: v.GetUint64(OutboundSubnetBandwidthMaxBurstSizeKey),
+				},
+			},
+
+			OutboundBandwidthConfig: throttling.OutboundBandwidthThrottlerConfig{
+				Peer: throttling.BandwidthThrottlerConfig{
+					RefillRate:   v.GetUint64(OutboundPeerBandwidthRefillRateKey),
+					MaxBurstSize: v.GetUint64(OutboundPeerBandwidthMaxBurstSizeKey),
+				},
+				Global: throttling.BandwidthThrottlerConfig{
+					RefillRate:   v.GetUint64(OutboundBandwidthRefillRateKey),
+					MaxBurstSize: v.GetUint64(OutboundBandwidthMaxBurstSizeKey),
+				},
+			},
 		},
 
 		HealthConfig: network.HealthConfig{
-			MaxTimeSinceMsgSent:          v.GetDuration(NetworkHealthMaxTimeSinceMsgSentKey),
-			MaxTimeSinceMsgReceived:      v.GetDuration(NetworkHealthMaxTimeSinceMsgReceivedKey),
-			MaxPortionSendQueueBytesFull: v.GetFloat64(NetworkHealthMaxPortionSendQueueFillKey),
-			MinConnectedPeers:            v.GetUint(NetworkHealthMinPeersKey),
-			MaxSendFailRate:              v.GetFloat64(NetworkHealthMaxSendFailRateKey),
-			MaxSendFailRateHalflife:      halflife,
+			MaxTimeSinceMsgSent:              v.GetDuration(NetworkHealthMaxTimeSinceMsgSentKey),
+			MaxTimeSinceMsgReceived:          v.GetDuration(NetworkHealthMaxTimeSinceMsgReceivedKey),
+			MaxTimeSinceConsensusMsgReceived: v.GetDuration(NetworkHealthMaxTimeSinceConsensusMsgKey),
+			MinConnectedStake:                v.GetFloat64(NetworkHealthMinConnectedStakeKey),
+			MaxPortionSendQueueBytesFull:     v.GetFloat64(NetworkHealthMaxPortionSendQueueFillKey),
+			MinConnectedPeers:                v.GetUint(NetworkHealthMinPeersKey),
+			MaxSendFailRate:                  v.GetFloat64(NetworkHealthMaxSendFailRateKey),
+			MaxSendFailRateHalflife:          halflife,
 		},
 
 		DialerConfig: dialer.Config{
@@ -367,27 +580,72 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 			PeerListGossipFreq:           v.GetDuration(NetworkPeerListGossipFreqKey),
 			PeerListGossipSize:           v.GetUint32(NetworkPeerListGossipSizeKey),
 			PeerListStakerGossipFraction: v.GetUint32(NetworkPeerListStakerGossipFractionKey),
+			PeerListMaxIPAge:             v.GetDuration(NetworkPeerListMaxIPAgeKey),
 		},
 
 		GossipConfig: network.GossipConfig{
 			GossipAcceptedFrontierSize: uint(v.GetUint32(ConsensusGossipAcceptedFrontierSizeKey)),
-			GossipOnAcceptSize:         uint(v.GetUint32(ConsensusGossipOnAcceptSizeKey)),
 			AppGossipNonValidatorSize:  uint(v.GetUint32(AppGossipNonValidatorSizeKey)),
 			AppGossipValidatorSize:     uint(v.GetUint32(AppGossipValidatorSizeKey)),
+
+			AdaptiveGossipMinSize:             uint(v.GetUint32(ConsensusGossipOnAcceptMinSizeKey)),
+			AdaptiveGossipMaxSize:             uint(v.GetUint32(ConsensusGossipOnAcceptMaxSizeKey)),
+			AdaptiveGossipRedundancyHalflife:  v.GetDuration(ConsensusGossipRedundancyHalflifeKey),
+			AdaptiveGossipRedundancyThreshold: v.GetFloat64(ConsensusGossipRedundancyThresholdKey),
 		},
 
 		DelayConfig: network.DelayConfig{
-			MaxReconnectDelay:     v.GetDuration(NetworkMaxReconnectDelayKey),
-			InitialReconnectDelay: v.GetDuration(NetworkInitialReconnectDelayKey),
+			MaxReconnectDelay:        v.GetDuration(NetworkMaxReconnectDelayKey),
+			InitialReconnectDelay:    v.GetDuration(NetworkInitialReconnectDelayKey),
+			ReconnectDelayMultiplier: v.GetFloat64(NetworkReconnectDelayMultiplierKey),
+			ReconnectDelayJitter:     v.GetFloat64(NetworkReconnectDelayJitterKey),
+			MaxReconnectAttempts:     v.GetUint32(NetworkMaxReconnectAttemptsKey),
 		},
 
-		MaxClockDifference: v.GetDuration(NetworkMaxClockDifferenceKey),
-		CompressionEnabled: v.GetBool(NetworkCompressionEnabledKey),
-		PingFrequency:      v.GetDuration(NetworkPingFrequencyKey),
-		AllowPrivateIPs:    v.GetBool(NetworkAllowPrivateIPsKey),
-		UptimeMetricFreq:   v.GetDuration(UptimeMetricFreqKey),
+		MaxClockDifference:   v.GetDuration(NetworkMaxClockDifferenceKey),
+		CompressionEnabled:   v.GetBool(NetworkCompressionEnabledKey),
+		CompressionType:      compressionType,
+		CompressionThreshold: int(v.GetUint(NetworkCompressionThresholdKey)),
+		ProtoMessagesEnabled: v.GetBool(NetworkProtoMessagesEnabledKey),
+		QUICEnabled:          v.GetBool(NetworkQUICEnabledKey),
+		PeerStoreSize:        v.GetInt(NetworkPeerStoreSizeKey),
+		PingFrequency:        v.GetDuration(NetworkPingFrequencyKey),
+		AllowPrivateIPs:      v.GetBool(NetworkAllowPrivateIPsKey),
+		UptimeMetricFreq:     v.GetDuration(UptimeMetricFreqKey),
 
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
+		PrimaryNetworkSampleSize:  v.GetInt(NetworkPrimaryNetworkSampleSizeKey),
+		AllowlistEnabled:          v.GetBool(NetworkAllowlistEnabledKey),
+	}
+
+	config.AllowedNodeIDs, err = getAllowedNodeIDs(v)
+	if err != nil {
+		return network.Config{}, err
+	}
+
+	if subnet := v.GetString(NetworkConnectivitySubnetIDKey); subnet != "" {
+		config.ConnectivitySubnetID, err = ids.FromString(subnet)
+		if err != nil {
+			return network.Config{}, fmt.Errorf("couldn't parse %s: %w", NetworkConnectivitySubnetIDKey, err)
+		}
+	}
+
+	if proxyURL := v.GetString(NetworkProxyKey); proxyURL != "" {
+		config.DialerConfig.Proxy, err = dialer.ParseProxyURL(proxyURL)
+		if err != nil {
+			return network.Config{}, fmt.Errorf("couldn't parse %s: %w", NetworkProxyKey, err)
+		}
+	}
+
+	// The deprecated fixed gossip size is used for whichever bound isn't set
+	if v.IsSet(ConsensusGossipOnAcceptSizeKey) {
+		gossipSize := uint(v.GetUint32(ConsensusGossipOnAcceptSizeKey))
+		if !v.IsSet(ConsensusGossipOnAcceptMinSizeKey) {
+			config.AdaptiveGossipMinSize = gossipSize
+		}
+		if !v.IsSet(ConsensusGossipOnAcceptMaxSizeKey) {
+			config.AdaptiveGossipMaxSize = gossipSize
+		}
 	}
 
 	switch {
@@ -395,6 +653,10 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkHealthMaxTimeSinceMsgSentKey)
 	case config.HealthConfig.MaxTimeSinceMsgReceived < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkHealthMaxTimeSinceMsgReceivedKey)
+	case config.HealthConfig.MaxTimeSinceConsensusMsgReceived < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkHealthMaxTimeSinceConsensusMsgKey)
+	case config.HealthConfig.MinConnectedStake < 0 || config.HealthConfig.MinConnectedStake > 1:
+		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkHealthMinConnectedStakeKey)
 	case config.HealthConfig.MaxSendFailRate < 0 || config.HealthConfig.MaxSendFailRate > 1:
 		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkHealthMaxSendFailRateKey)
 	case config.HealthConfig.MaxPortionSendQueueBytesFull < 0 || config.HealthConfig.MaxPortionSendQueueBytesFull > 1:
@@ -405,6 +667,8 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%q must be >= 0", PeerAliasTimeoutKey)
 	case config.PeerListGossipFreq < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPeerListGossipFreqKey)
+	case config.PeerListMaxIPAge < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPeerListMaxIPAgeKey)
 	case config.GetVersionTimeout < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkGetVersionTimeoutKey)
 	case config.PeerListStakerGossipFraction < 1:
@@ -415,6 +679,16 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkInitialReconnectDelayKey)
 	case config.MaxReconnectDelay < config.InitialReconnectDelay:
 		return network.Config{}, fmt.Errorf("%s must be >= %s", NetworkMaxReconnectDelayKey, NetworkInitialReconnectDelayKey)
+	case config.ReconnectDelayMultiplier < 1:
+		return network.Config{}, fmt.Errorf("%s must be >= 1", NetworkReconnectDelayMultiplierKey)
+	case config.ReconnectDelayJitter < 0 || config.ReconnectDelayJitter >= 1:
+		return network.Config{}, fmt.Errorf("%s must be in [0, 1)", NetworkReconnectDelayJitterKey)
+	case config.AdaptiveGossipMaxSize < config.AdaptiveGossipMinSize:
+		return network.Config{}, fmt.Errorf("%s must be >= %s", ConsensusGossipOnAcceptMaxSizeKey, ConsensusGossipOnAcceptMinSizeKey)
+	case config.AdaptiveGossipRedundancyHalflife <= 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", ConsensusGossipRedundancyHalflifeKey)
+	case config.AdaptiveGossipRedundancyThreshold < 0 || config.AdaptiveGossipRedundancyThreshold >= 1:
+		return network.Config{}, fmt.Errorf("%s must be in [0, 1)", ConsensusGossipRedundancyThresholdKey)
 	case config.PingPongTimeout < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPingTimeoutKey)
 	case config.PingFrequency < 0:
@@ -423,13 +697,90 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be > %s", NetworkPingTimeoutKey, NetworkPingFrequencyKey)
 	case config.ReadHandshakeTimeout < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkReadHandshakeTimeoutKey)
+	case config.PeerStoreSize < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPeerStoreSizeKey)
+	case config.PrimaryNetworkSampleSize < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPrimaryNetworkSampleSizeKey)
+	case config.ThrottlerConfig.InboundMsgThrottlerConfig.CPUThrottlerConfig.VdrAlloc < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", InboundThrottlerCPUVdrAllocKey)
+	case config.ThrottlerConfig.InboundMsgThrottlerConfig.CPUThrottlerConfig.NodeAtLargeAlloc < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", InboundThrottlerCPUNodeAtLargeAllocKey)
+	case config.ThrottlerConfig.InboundMsgThrottlerConfig.CPUThrottlerConfig.MaxRecheckDelay <= 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", InboundThrottlerCPUMaxRecheckDelayKey)
 	case config.MaxClockDifference < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkMaxClockDifferenceKey)
+	case config.ThrottlerConfig.OutboundSubnetBandwidthConfig.RefillRate > 0 &&
+		config.ThrottlerConfig.OutboundSubnetBandwidthConfig.MaxBurstSize < constants.DefaultMaxMessageSize:
+		return network.Config{}, fmt.Errorf("%s must be >= %d", OutboundSubnetBandwidthMaxBurstSizeKey, constants.DefaultMaxMessageSize)
+	case config.ThrottlerConfig.OutboundBandwidthConfig.Peer.RefillRate > 0 &&
+		config.ThrottlerConfig.OutboundBandwidthConfig.Peer.MaxBurstSize < constants.DefaultMaxMessageSize:
+		return network.Config{}, fmt.Errorf("%s must be >= %d", OutboundPeerBandwidthMaxBurstSizeKey, constants.DefaultMaxMessageSize)
+	case config.ThrottlerConfig.OutboundBandwidthConfig.Global.RefillRate > 0 &&
+		config.ThrottlerConfig.OutboundBandwidthConfig.Global.MaxBurstSize < constants.DefaultMaxMessageSize:
+		return network.Config{}, fmt.Errorf("%s must be >= %d", OutboundBandwidthMaxBurstSizeKey, constants.DefaultMaxMessageSize)
 	}
 
 	return config, nil
 }
 
+// getVersionCompatibility returns the peer version policy of a network whose
+// upgrades activate as in [upgradeConfig], overridden by the values that are
+// set in [v]
+func getVersionCompatibility(v *viper.Viper, upgradeConfig version.UpgradeConfig) (version.Compatibility, error) {
+	config := upgradeConfig.CompatibilityConfig()
+	parser := version.NewDefaultApplicationParser()
+	versions := []struct {
+		key     string
+		version *version.Application
+	}{
+		{key: NetworkMinCompatibleVersionKey, version: &config.MinCompatible},
+		{key: NetworkPrevMinCompatibleVersionKey, version: &config.PrevMinCompatible},
+		{key: NetworkMaxCompatibleVersionKey, version: &config.MaxCompatible},
+		{key: NetworkMinUnmaskedVersionKey, version: &config.MinUnmaskable},
+		{key: NetworkPrevMinUnmaskedVersionKey, version: &config.PrevMinUnmaskable},
+	}
+	for _, entry := range versions {
+		versionStr := v.GetString(entry.key)
+		if versionStr == "" {
+			continue
+		}
+		parsed, err := parser.Parse(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", entry.key, err)
+		}
+		*entry.version = parsed
+	}
+
+	times := []struct {
+		key  string
+		time *time.Time
+	}{
+		{key: NetworkMinCompatibleTimeKey, time: &config.MinCompatibleTime},
+		{key: NetworkMinUnmaskedTimeKey, time: &config.MinUnmaskableTime},
+	}
+	for _, entry := range times {
+		timeStr := v.GetString(entry.key)
+		if timeStr == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", entry.key, err)
+		}
+		*entry.time = parsed
+	}
+
+	switch {
+	case config.MinCompatible.Before(config.PrevMinCompatible):
+		return nil, fmt.Errorf("%s must be >= %s", NetworkMinCompatibleVersionKey, NetworkPrevMinCompatibleVersionKey)
+	case config.MinUnmaskable.Before(config.PrevMinUnmaskable):
+		return nil, fmt.Errorf("%s must be >= %s", NetworkMinUnmaskedVersionKey, NetworkPrevMinUnmaskedVersionKey)
+	case config.MaxCompatible != nil && config.MaxCompatible.Before(version.CurrentApp):
+		return nil, fmt.Errorf("%s must be >= %s", NetworkMaxCompatibleVersionKey, version.CurrentApp)
+	}
+	return version.NewCompatibilityFromConfig(version.CurrentApp, config), nil
+}
+
 func getBenchlistConfig(v *viper.Viper, alpha, k int) (benchlist.Config, error) {
 	config := benchlist.Config{
 		Threshold:              v.GetInt(BenchlistFailThresholdKey),
@@ -437,12 +788,41 @@ func getBenchlistConfig(v *viper.Viper, alpha, k int) (benchlist.Config, error)
 		Duration:               v.GetDuration(BenchlistDurationKey),
 		MinimumFailingDuration: v.GetDuration(BenchlistMinFailingDurationKey),
 		MaxPortion:             (1.0 - (float64(alpha) / float64(k))) / 3.0,
+		AdaptivePolicy: benchlist.AdaptivePolicy{
+			FailureHalflife:    v.GetDuration(BenchlistFailureHalflifeKey),
+			DurationMultiplier: v.GetFloat64(BenchlistDurationMultiplierKey),
+			MaxDuration:        v.GetDuration(BenchlistMaxDurationKey),
+			OffenseHalflife:    v.GetDuration(BenchlistOffenseHalflifeKey),
+		},
 	}
 	switch {
 	case config.Duration < 0:
 		return benchlist.Config{}, fmt.Errorf("%q must be >= 0", BenchlistDurationKey)
 	case config.MinimumFailingDuration < 0:
 		return benchlist.Config{}, fmt.Errorf("%q must be >= 0", BenchlistMinFailingDurationKey)
+	case config.FailureHalflife < 0:
+		return benchlist.Config{}, fmt.Errorf("%q must be >= 0", BenchlistFailureHalflifeKey)
+	case config.MaxDuration < 0:
+		return benchlist.Config{}, fmt.Errorf("%q must be >= 0", BenchlistMaxDurationKey)
+	case config.MaxDuration > 0 && config.MaxDuration < config.Duration:
+		return benchlist.Config{}, fmt.Errorf("%q must be >= %q", BenchlistMaxDurationKey, BenchlistDurationKey)
+	case config.OffenseHalflife < 0:
+		return benchlist.Config{}, fmt.Errorf("%q must be >= 0", BenchlistOffenseHalflifeKey)
+	}
+	return config, nil
+}
+
+func getPeerScoringConfig(v *viper.Viper) (scoring.Config, error) {
+	config := scoring.Config{
+		Enabled:       v.GetBool(PeerScoringEnabledKey),
+		Halflife:      v.GetDuration(PeerScoringHalflifeKey),
+		PoorThreshold: v.GetFloat64(PeerScoringPoorThresholdKey),
+	}
+	switch {
+	case config.Halflife <= 0:
+		return scoring.Config{}, fmt.Errorf("%q must be > 0", PeerScoringHalflifeKey)
+	case config.PoorThreshold >= 0:
+		return scoring.Config{}, fmt.Errorf("%q must be < 0", PeerScoringPoorThresholdKey)
 	}
 	return config, nil
 }
@@ -455,6 +835,11 @@ func getBootstrapConfig(v *viper.Viper, networkID uint32) (node.BootstrapConfig,
 		BootstrapMaxTimeGetAncestors:            v.GetDuration(BootstrapMaxTimeGetAncestorsKey),
 		BootstrapAncestorsMaxContainersSent:     int(v.GetUint(BootstrapAncestorsMaxContainersSentKey)),
 		BootstrapAncestorsMaxContainersReceived: int(v.GetUint(BootstrapAncestorsMaxContainersReceivedKey)),
+		BootstrapDeferredFlushFrequency:         int(v.GetUint(BootstrapDeferredFlushFrequencyKey)),
+		BootstrapQueueMaxAge:                    v.GetDuration(BootstrapQueueMaxAgeKey),
+	}
+	if config.BootstrapQueueMaxAge < 0 {
+		return node.BootstrapConfig{}, fmt.Errorf("%q must be >= 0", BootstrapQueueMaxAgeKey)
 	}
 
 	bootstrapIPs, bootstrapIDs := genesis.SampleBeacons(networkID, 5)
@@ -529,6 +914,20 @@ func getIPConfig(v *viper.Viper) (node.IPConfig, error) {
 
 	stakingPort := uint16(v.GetUint(StakingPortKey))
 	config.IP = utils.NewDynamicIPDesc(ip, stakingPort)
+
+	if publicIPv6 := v.GetString(PublicIPv6Key); publicIPv6 != "" {
+		ipv6 := net.ParseIP(publicIPv6)
+		if ipv6 == nil || ipv6.To4() != nil {
+			return node.IPConfig{}, fmt.Errorf("%q must be an IPv6 address", PublicIPv6Key)
+		}
+		if ip.To4() == nil {
+			return node.IPConfig{}, fmt.Errorf("public IP must be an IPv4 address when %q is set", PublicIPv6Key)
+		}
+		config.IPv6 = utils.IPDesc{
+			IP:   ipv6,
+			Port: stakingPort,
+		}
+	}
 	return config, nil
 }
 
@@ -545,6 +944,84 @@ func getProfilerConfig(v *viper.Viper) (profiler.Config, error) {
 	return config, nil
 }
 
+func getPruningConfig(v *viper.Viper) (chains.PruningConfig, error) {
+	config := chains.PruningConfig{
+		Retention: v.GetDuration(PruningRetentionKey),
+		Frequency: v.GetDuration(PruningFrequencyKey),
+	}
+	switch {
+	case config.Retention < 0:
+		return chains.PruningConfig{}, fmt.Errorf("%s must be >= 0", PruningRetentionKey)
+	case config.Frequency < 0:
+		return chains.PruningConfig{}, fmt.Errorf("%s must be >= 0", PruningFrequencyKey)
+	}
+	return config, nil
+}
+
+func getBackupConfig(v *viper.Viper) (backup.Config, error) {
+	config := backup.Config{
+		Dir:         os.ExpandEnv(v.GetString(BackupDirKey)),
+		Freq:        v.GetDuration(BackupFreqKey),
+		MaxNumFiles: v.GetInt(BackupMaxFilesKey),
+	}
+	switch {
+	case config.Freq < 0:
+		return backup.Config{}, fmt.Errorf("%s must be >= 0", BackupFreqKey)
+	case config.MaxNumFiles < 0:
+		return backup.Config{}, fmt.Errorf("%s must be >= 0", BackupMaxFilesKey)
+	}
+	return config, nil
+}
+
+// getRestoreArchive opens the backup the node is restored from, if any, and
+// restores its staking key and certificate. The database is restored once it's
+// opened by the node.
+func getRestoreArchive(v *viper.Viper, networkID uint32) (*backup.Archive, error) {
+	if !v.IsSet(BackupRestoreFileKey) {
+		return nil, nil
+	}
+
+	archive, err := backup.Open(os.ExpandEnv(v.GetString(BackupRestoreFileKey)))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open backup: %w", err)
+	}
+	if archive.Manifest.NetworkID != networkID {
+		return nil, fmt.Errorf("%w: backup is of network %d but the node is on network %d",
+			errRestoreNetworkMismatch, archive.Manifest.NetworkID, networkID)
+	}
+	if !archive.HasStakingKey() {
+		// The staking key wasn't stored in a file, so the node must be given
+		// the same key some other way. This is verified once the key is loaded.
+		return archive, nil
+	}
+
+	if v.GetBool(StakingEphemeralCertEnabledKey) || v.IsSet(StakingKeyContentKey) || v.IsSet(StakingKeyPKCS11ModuleKey) {
+		return nil, errRestoreStakingKeyNotInFile
+	}
+	err = archive.RestoreStakingKeys(
+		os.ExpandEnv(v.GetString(StakingKeyPathKey)),
+		os.ExpandEnv(v.GetString(StakingCertPathKey)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't restore staking key: %w", err)
+	}
+	return archive, nil
+}
+
+func getPluginSupervisorConfig(v *viper.Viper) (rpcchainvm.SupervisorConfig, error) {
+	config := rpcchainvm.SupervisorConfig{
+		HealthCheckFrequency:   v.GetDuration(PluginHealthCheckFreqKey),
+		HealthCheckTimeout:     v.GetDuration(PluginHealthCheckTimeoutKey),
+		MaxHealthCheckFailures: v.GetInt(PluginMaxHealthCheckFailuresKey),
+		InitialRestartBackoff:  v.GetDuration(PluginInitialRestartBackoffKey),
+		MaxRestartBackoff:      v.GetDuration(PluginMaxRestartBackoffKey),
+	}
+	if err := config.Verify(); err != nil {
+		return rpcchainvm.SupervisorConfig{}, fmt.Errorf("invalid plugin supervisor config: %w", err)
+	}
+	return config, nil
+}
+
 func getStakingTLSCertFromFlag(v *viper.Viper) (tls.Certificate, error) {
 	stakingKeyRawContent := v.GetString(StakingKeyContentKey)
 	stakingKeyContent, err := base64.StdEncoding.DecodeString(stakingKeyRawContent)
@@ -593,6 +1070,41 @@ func getStakingTLSCertFromFile(v *viper.Viper) (tls.Certificate, error) {
 	return *cert, nil
 }
 
+func getStakingTLSCertFromPKCS11(v *viper.Viper) (tls.Certificate, error) {
+	if v.IsSet(StakingKeyPathKey) || v.IsSet(StakingKeyContentKey) {
+		return tls.Certificate{}, errStakingKeyPKCS11Conflict
+	}
+
+	signer, err := staking.NewPKCS11Signer(staking.PKCS11Config{
+		ModulePath: os.ExpandEnv(v.GetString(StakingKeyPKCS11ModuleKey)),
+		TokenLabel: v.GetString(StakingKeyPKCS11TokenLabelKey),
+		PIN:        v.GetString(StakingKeyPKCS11PINKey),
+		KeyLabel:   v.GetString(StakingKeyPKCS11KeyLabelKey),
+	})
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("couldn't load staking key: %w", err)
+	}
+
+	var cert *tls.Certificate
+	if v.IsSet(StakingCertContentKey) {
+		stakingCertContent, err := base64.StdEncoding.DecodeString(v.GetString(StakingCertContentKey))
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("unable to decode base64 content: %w", err)
+		}
+		cert, err = staking.LoadTLSCertFromSignerBytes(signer, stakingCertContent)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("couldn't read staking certificate: %w", err)
+		}
+	} else {
+		stakingCertPath := os.ExpandEnv(v.GetString(StakingCertPathKey))
+		cert, err = staking.LoadTLSCertFromSigner(signer, stakingCertPath)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("couldn't read staking certificate: %w", err)
+		}
+	}
+	return *cert, nil
+}
+
 func getStakingTLSCert(v *viper.Viper) (tls.Certificate, error) {
 	if v.GetBool(StakingEphemeralCertEnabledKey) {
 		// Use an ephemeral staking key/cert
@@ -604,6 +1116,8 @@ func getStakingTLSCert(v *viper.Viper) (tls.Certificate, error) {
 	}
 
 	switch {
+	case v.IsSet(StakingKeyPKCS11ModuleKey):
+		return getStakingTLSCertFromPKCS11(v)
 	case v.IsSet(StakingKeyContentKey) && !v.IsSet(StakingCertContentKey):
 		return tls.Certificate{}, errStakingCertContentUnset
 	case !v.IsSet(StakingKeyContentKey) && v.IsSet(StakingCertContentKey):
@@ -717,6 +1231,109 @@ func getWhitelistedSubnets(v *viper.Viper) (ids.Set, error) {
 	return whitelistedSubnetIDs, nil
 }
 
+func getTxForwardingNodeIDs(v *viper.Viper) (ids.ShortSet, error) {
+	nodeIDs := ids.ShortSet{}
+	for _, id := range strings.Split(v.GetString(TxForwardingNodeIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse tx forwarding node ID %q: %w", id, err)
+		}
+		nodeIDs.Add(nodeID)
+	}
+	return nodeIDs, nil
+}
+
+func getAllowedNodeIDs(v *viper.Viper) (ids.ShortSet, error) {
+	nodeIDs := ids.ShortSet{}
+	for _, id := range strings.Split(v.GetString(NetworkAllowlistNodeIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse allowed node ID %q: %w", id, err)
+		}
+		nodeIDs.Add(nodeID)
+	}
+	return nodeIDs, nil
+}
+
+// getUpgradeConfig returns when the network upgrades of [networkID] activate.
+// The upgrade times of a custom network may be set by an upgrade config, in
+// which case the upgrades that it doesn't set activate at their default times.
+func getUpgradeConfig(v *viper.Viper, networkID uint32) (version.UpgradeConfig, error) {
+	config := version.GetUpgradeConfig(networkID)
+
+	var (
+		configBytes []byte
+		err         error
+	)
+	switch {
+	case v.IsSet(UpgradeConfigContentKey):
+		upgradeConfigContent := v.GetString(UpgradeConfigContentKey)
+		configBytes, err = base64.StdEncoding.DecodeString(upgradeConfigContent)
+		if err != nil {
+			return version.UpgradeConfig{}, fmt.Errorf("unable to decode base64 content: %w", err)
+		}
+	case v.IsSet(UpgradeConfigFileKey):
+		path := os.ExpandEnv(v.GetString(UpgradeConfigFileKey))
+		configBytes, err = ioutil.ReadFile(path)
+		if err != nil {
+			return version.UpgradeConfig{}, err
+		}
+	default:
+		return config, nil
+	}
+
+	if networkID == constants.MainnetID || networkID == constants.FujiID {
+		return version.UpgradeConfig{}, errUpgradeConfigOnPublicNetwork
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(configBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return version.UpgradeConfig{}, fmt.Errorf("couldn't parse upgrade config: %w", err)
+	}
+	if err := config.Valid(); err != nil {
+		return version.UpgradeConfig{}, err
+	}
+	return config, nil
+}
+
+// getLevelDBConfig returns the leveldb config that the node runs with. The
+// options of [configBytes], which was read from the database config file,
+// take precedence over the leveldb flags, which take precedence over the
+// defaults of the leveldb profile.
+func getLevelDBConfig(v *viper.Viper, configBytes []byte) ([]byte, error) {
+	config, err := leveldb.ProfileConfig(v.GetString(LevelDBProfileKey))
+	if err != nil {
+		return nil, err
+	}
+	for key, field := range map[string]*int{
+		LevelDBBlockCacheSizeKey:      &config.BlockCacheCapacity,
+		LevelDBWriteBufferSizeKey:     &config.WriteBuffer,
+		LevelDBOpenFilesKey:           &config.OpenFilesCacheCapacity,
+		LevelDBBloomFilterBitsKey:     &config.FilterBitsPerKey,
+		LevelDBCompactionL0TriggerKey: &config.CompactionL0Trigger,
+	} {
+		switch value := v.GetInt(key); {
+		case value < 0:
+			return nil, fmt.Errorf("%s must be >= 0", key)
+		case value > 0:
+			*field = value
+		}
+	}
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse db config: %w", err)
+		}
+	}
+	return json.Marshal(&config)
+}
+
 func getDatabaseConfig(v *viper.Viper, networkID uint32) (node.DatabaseConfig, error) {
 	var (
 		configBytes []byte
@@ -736,43 +1353,64 @@ func getDatabaseConfig(v *viper.Viper, networkID uint32) (node.DatabaseConfig, e
 		}
 	}
 
+	name := v.GetString(DBTypeKey)
+	if name == leveldb.Name {
+		configBytes, err = getLevelDBConfig(v, configBytes)
+		if err != nil {
+			return node.DatabaseConfig{}, err
+		}
+	}
+
+	maxWriteLatency := v.GetDuration(DBHealthMaxWriteLatencyKey)
+	if maxWriteLatency < 0 {
+		return node.DatabaseConfig{}, fmt.Errorf("%s must be >= 0", DBHealthMaxWriteLatencyKey)
+	}
+
 	return node.DatabaseConfig{
-		Name: v.GetString(DBTypeKey),
+		Name: name,
 		Path: filepath.Join(
 			os.ExpandEnv(v.GetString(DBPathKey)),
 			constants.NetworkName(networkID),
 		),
 		Config: configBytes,
+		HealthConfig: database.HealthConfig{
+			MinFreeDiskSpace:     v.GetUint64(DBHealthMinFreeDiskSpaceKey),
+			MaxWriteLatency:      maxWriteLatency,
+			MaxFailedCompactions: v.GetUint64(DBHealthMaxFailedCompactionsKey),
+		},
 	}, nil
 }
 
-func getVMAliases(v *viper.Viper) (map[ids.ID][]string, error) {
-	var fileBytes []byte
-	if v.IsSet(VMAliasesContentKey) {
-		var err error
-		aliasFlagContent := v.GetString(VMAliasesContentKey)
-		fileBytes, err = base64.StdEncoding.DecodeString(aliasFlagContent)
+// getVMConfigBytes returns the base64 decoded value of [contentKey] if it's
+// set, and the contents of the file at [fileKey] otherwise. Returns nil if the
+// file wasn't specified and doesn't exist at its default location.
+func getVMConfigBytes(v *viper.Viper, contentKey, fileKey, name string) ([]byte, error) {
+	if v.IsSet(contentKey) {
+		fileBytes, err := base64.StdEncoding.DecodeString(v.GetString(contentKey))
 		if err != nil {
 			return nil, fmt.Errorf("unable to decode base64 content: %w", err)
 		}
-	} else {
-		aliasFilePath := filepath.Clean(v.GetString(VMAliasesFileKey))
-		exists, err := storage.FileExists(aliasFilePath)
-		if err != nil {
-			return nil, err
-		}
+		return fileBytes, nil
+	}
 
-		if !exists {
-			if v.IsSet(VMAliasesFileKey) {
-				return nil, fmt.Errorf("vm alias file does not exist in %v", aliasFilePath)
-			}
-			return nil, nil
+	filePath := filepath.Clean(v.GetString(fileKey))
+	exists, err := storage.FileExists(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if v.IsSet(fileKey) {
+			return nil, fmt.Errorf("%s file does not exist in %v", name, filePath)
 		}
+		return nil, nil
+	}
+	return ioutil.ReadFile(filePath)
+}
 
-		fileBytes, err = ioutil.ReadFile(aliasFilePath)
-		if err != nil {
-			return nil, err
-		}
+func getVMAliases(v *viper.Viper) (map[ids.ID][]string, error) {
+	fileBytes, err := getVMConfigBytes(v, VMAliasesContentKey, VMAliasesFileKey, "vm alias")
+	if err != nil || fileBytes == nil {
+		return nil, err
 	}
 
 	vmAliasMap := make(map[ids.ID][]string)
@@ -782,6 +1420,19 @@ func getVMAliases(v *viper.Viper) (map[ids.ID][]string, error) {
 	return vmAliasMap, nil
 }
 
+func getVMRegistry(v *viper.Viper) (map[ids.ID]vms.RegistryEntry, error) {
+	fileBytes, err := getVMConfigBytes(v, VMRegistryContentKey, VMRegistryFileKey, "vm registry")
+	if err != nil || fileBytes == nil {
+		return nil, err
+	}
+
+	registry := make(map[ids.ID]vms.RegistryEntry)
+	if err := json.Unmarshal(fileBytes, &registry); err != nil {
+		return nil, fmt.Errorf("problem unmarshaling vm registry: %w", err)
+	}
+	return registry, nil
+}
+
 func getVMManager(v *viper.Viper) (vms.Manager, error) {
 	vmAliases, err := getVMAliases(v)
 	if err != nil {
@@ -911,7 +1562,7 @@ func getSubnetConfigsFromFlags(v *viper.Viper, subnetIDs []ids.ID) (map[ids.ID]c
 	res := make(map[ids.ID]chains.SubnetConfig)
 	for _, subnetID := range subnetIDs {
 		if subnetConfig, ok := subnetConfigs[subnetID]; ok {
-			if err := subnetConfig.ConsensusParameters.Valid(); err != nil {
+			if err := subnetConfig.Valid(); err != nil {
 				return nil, err
 			}
 			res[subnetID] = subnetConfig
@@ -971,7 +1622,7 @@ func readSubnetConfigs(subnetConfigPath string, subnetIDs []ids.ID, defaultSubne
 		if err := json.Unmarshal(file, &configData); err != nil {
 			return nil, err
 		}
-		if err := configData.ConsensusParameters.Valid(); err != nil {
+		if err := configData.Valid(); err != nil {
 			return nil, err
 		}
 		subnetConfigs[subnetID] = configData
@@ -1002,6 +1653,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if nodeConfig.ConsensusShutdownTimeout < 0 {
 		return node.Config{}, fmt.Errorf("%q must be >= 0", ConsensusShutdownTimeoutKey)
 	}
+	nodeConfig.ConsensusMaxProcessingMsgs = int(v.GetUint(ConsensusMaxProcessingMsgsKey))
+	nodeConfig.ConsensusChitCacheDuration = v.GetDuration(ConsensusChitCacheDurationKey)
+	if nodeConfig.ConsensusChitCacheDuration < 0 {
+		return node.Config{}, fmt.Errorf("%q must be >= 0", ConsensusChitCacheDurationKey)
+	}
+	nodeConfig.ConsensusVRFSampling = v.GetBool(ConsensusVRFSamplingKey)
 
 	// Gossiping
 	nodeConfig.ConsensusGossipFrequency = v.GetDuration(ConsensusGossipFrequencyKey)
@@ -1016,12 +1673,21 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Crash reporting
+	nodeConfig.CrashReportConfig = getCrashReportConfig(v, nodeConfig.LoggingConfig.Directory)
+
 	// Network ID
 	nodeConfig.NetworkID, err = constants.NetworkID(v.GetString(NetworkNameKey))
 	if err != nil {
 		return node.Config{}, err
 	}
 
+	// Upgrades
+	nodeConfig.UpgradeConfig, err = getUpgradeConfig(v, nodeConfig.NetworkID)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// Database
 	nodeConfig.DatabaseConfig, err = getDatabaseConfig(v, nodeConfig.NetworkID)
 	if err != nil {
@@ -1034,11 +1700,31 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Restoring from a backup, which must be done before the staking key is
+	// loaded
+	nodeConfig.RestoreArchive, err = getRestoreArchive(v, nodeConfig.NetworkID)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// Staking
 	nodeConfig.StakingConfig, err = getStakingConfig(v, nodeConfig.NetworkID)
 	if err != nil {
 		return node.Config{}, err
 	}
+	if archive := nodeConfig.RestoreArchive; archive != nil {
+		nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(nodeConfig.StakingTLSCert.Leaf.Raw))
+		if err != nil {
+			return node.Config{}, fmt.Errorf("problem deriving node ID from certificate: %w", err)
+		}
+		if nodeID != archive.Manifest.NodeID {
+			return node.Config{}, fmt.Errorf("%w: backup is of node %s but the staking key is of node %s",
+				errRestoreNodeIDMismatch,
+				archive.Manifest.NodeID.PrefixedString(constants.NodeIDPrefix),
+				nodeID.PrefixedString(constants.NodeIDPrefix),
+			)
+		}
+	}
 
 	// Whitelisted Subnets
 	nodeConfig.WhitelistedSubnets, err = getWhitelistedSubnets(v)
@@ -1046,6 +1732,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Tx Forwarding
+	nodeConfig.TxForwardingNodeIDs, err = getTxForwardingNodeIDs(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// HTTP APIs
 	nodeConfig.HTTPConfig, err = getHTTPConfig(v)
 	if err != nil {
@@ -1084,6 +1776,16 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.NetworkConfig.VersionCompatibility, err = getVersionCompatibility(v, nodeConfig.UpgradeConfig)
+	if err != nil {
+		return node.Config{}, err
+	}
+	if subnetID := nodeConfig.NetworkConfig.ConnectivitySubnetID; subnetID != ids.Empty && !nodeConfig.WhitelistedSubnets.Contains(subnetID) {
+		return node.Config{}, fmt.Errorf("%s must be a whitelisted subnet but got %s", NetworkConnectivitySubnetIDKey, subnetID)
+	}
+	if nodeConfig.NetworkConfig.AllowlistEnabled && nodeConfig.NetworkConfig.AllowedNodeIDs.Len() == 0 && nodeConfig.WhitelistedSubnets.Len() == 0 {
+		return node.Config{}, fmt.Errorf("%s requires %s or whitelisted subnets to be set", NetworkAllowlistEnabledKey, NetworkAllowlistNodeIDsKey)
+	}
 
 	// Benchlist
 	nodeConfig.BenchlistConfig, err = getBenchlistConfig(v, nodeConfig.ConsensusParams.Alpha, nodeConfig.ConsensusParams.K)
@@ -1091,6 +1793,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Peer scoring
+	nodeConfig.PeerScoringConfig, err = getPeerScoringConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// File Descriptor Limit
 	fdLimit := v.GetUint64(FdLimitKey)
 	if err := ulimit.Set(fdLimit); err != nil {
@@ -1125,12 +1833,26 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 	nodeConfig.SubnetConfigs = subnetConfigs
+	subnetBandwidthConfigs := make(map[ids.ID]throttling.BandwidthThrottlerConfig)
+	for subnetID, subnetConfig := range subnetConfigs {
+		if subnetConfig.OutboundBandwidth != nil {
+			subnetBandwidthConfigs[subnetID] = *subnetConfig.OutboundBandwidth
+		}
+	}
+	nodeConfig.NetworkConfig.ThrottlerConfig.OutboundSubnetBandwidthConfig.Subnets = subnetBandwidthConfigs
 
 	// Chain Configs
 	nodeConfig.ChainConfigs, err = getChainConfigs(v)
 	if err != nil {
 		return node.Config{}, err
 	}
+	if !v.IsSet(ChainConfigContentKey) {
+		// Chain configs read from a directory can be reloaded at runtime
+		nodeConfig.ChainConfigDir, err = getPathFromDirKey(v, ChainConfigDirKey)
+		if err != nil {
+			return node.Config{}, err
+		}
+	}
 
 	// Profiler
 	nodeConfig.ProfilerConfig, err = getProfilerConfig(v)
@@ -1138,10 +1860,34 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Pruning
+	nodeConfig.PruningConfig, err = getPruningConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Backups
+	nodeConfig.BackupConfig, err = getBackupConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Plugin supervision
+	nodeConfig.PluginSupervisorConfig, err = getPluginSupervisorConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// VM Aliases
 	nodeConfig.VMManager, err = getVMManager(v)
 	if err != nil {
 		return node.Config{}, err
 	}
+
+	// VM Registry
+	nodeConfig.VMRegistry, err = getVMRegistry(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 	return nodeConfig, nil
 }
@@ -12,16 +12,23 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Toinounet21/avalanchego-mod/api/ratelimit"
 	"github.com/Toinounet21/avalanchego-mod/chains"
+	"github.com/Toinounet21/avalanchego-mod/database/leveldb"
+	"github.com/Toinounet21/avalanchego-mod/database/vaultdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/node"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/avalanche"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowball"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
 func TestGetChainConfigsFromFiles(t *testing.T) {
@@ -432,6 +439,28 @@ func TestGetSubnetConfigsFromFile(t *testing.T) {
 			},
 			errMessage: "fails the condition that: alpha <= k",
 		},
+		"invalid outbound bandwidth": {
+			fileName:  "2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i.json",
+			givenJSON: `{"outboundBandwidth":{"bandwidthRefillRate": 1024, "bandwidthMaxBurstRate": 1024} }`,
+			testF: func(assert *assert.Assertions, given map[ids.ID]chains.SubnetConfig) {
+				assert.Nil(given)
+			},
+			errMessage: "outbound bandwidth max burst size",
+		},
+		"outbound bandwidth": {
+			fileName:  "2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i.json",
+			givenJSON: `{"outboundBandwidth":{"bandwidthRefillRate": 1024, "bandwidthMaxBurstRate": 4194304} }`,
+			testF: func(assert *assert.Assertions, given map[ids.ID]chains.SubnetConfig) {
+				id, _ := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+				config, ok := given[id]
+				assert.True(ok)
+
+				assert.NotNil(config.OutboundBandwidth)
+				assert.EqualValues(1024, config.OutboundBandwidth.RefillRate)
+				assert.EqualValues(4194304, config.OutboundBandwidth.MaxBurstSize)
+			},
+			errMessage: "",
+		},
 		"correct config": {
 			fileName:  "2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i.json",
 			givenJSON: `{"validatorOnly": true, "consensusParameters":{"parents": 111, "alpha":16} }`,
@@ -445,6 +474,7 @@ func TestGetSubnetConfigsFromFile(t *testing.T) {
 				assert.Equal(16, config.ConsensusParameters.Alpha)
 				// must still respect defaults
 				assert.Equal(20, config.ConsensusParameters.K)
+				assert.Nil(config.OutboundBandwidth)
 			},
 			errMessage: "",
 		},
@@ -646,3 +676,295 @@ func setupViper(configFilePath string) *viper.Viper {
 	}
 	return v
 }
+
+func TestGetNetworkConfigDeprecatedGossipSize(t *testing.T) {
+	assert := assert.New(t)
+
+	v := setupViperFlags()
+	config, err := getNetworkConfig(v, time.Minute)
+	assert.NoError(err)
+	assert.EqualValues(6, config.AdaptiveGossipMinSize)
+	assert.EqualValues(30, config.AdaptiveGossipMaxSize)
+
+	// The deprecated size fixes the fanout
+	v.Set(ConsensusGossipOnAcceptSizeKey, 20)
+	config, err = getNetworkConfig(v, time.Minute)
+	assert.NoError(err)
+	assert.EqualValues(20, config.AdaptiveGossipMinSize)
+	assert.EqualValues(20, config.AdaptiveGossipMaxSize)
+
+	// Explicitly set bounds take precedence
+	v.Set(ConsensusGossipOnAcceptMaxSizeKey, 40)
+	config, err = getNetworkConfig(v, time.Minute)
+	assert.NoError(err)
+	assert.EqualValues(20, config.AdaptiveGossipMinSize)
+	assert.EqualValues(40, config.AdaptiveGossipMaxSize)
+}
+
+func TestGetVersionCompatibility(t *testing.T) {
+	assert := assert.New(t)
+
+	// Unset keys use the network's default policy
+	v := setupViperFlags()
+	compatibility, err := getVersionCompatibility(v, version.GetUpgradeConfig(constants.MainnetID))
+	assert.NoError(err)
+	assert.Equal(version.GetCompatibilityConfig(constants.MainnetID), compatibility.Config())
+
+	v.Set(NetworkMinCompatibleVersionKey, "avalanche/1.7.0")
+	v.Set(NetworkMinCompatibleTimeKey, "2022-01-02T15:04:05Z")
+	v.Set(NetworkPrevMinCompatibleVersionKey, "avalanche/1.6.0")
+	v.Set(NetworkMaxCompatibleVersionKey, "avalanche/1.99.0")
+	compatibility, err = getVersionCompatibility(v, version.GetUpgradeConfig(constants.MainnetID))
+	assert.NoError(err)
+	config := compatibility.Config()
+	assert.Equal("avalanche/1.7.0", config.MinCompatible.String())
+	assert.Equal(time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC), config.MinCompatibleTime)
+	assert.Equal("avalanche/1.6.0", config.PrevMinCompatible.String())
+	assert.Equal("avalanche/1.99.0", config.MaxCompatible.String())
+
+	// The previous minimum can't be after the minimum
+	v.Set(NetworkPrevMinCompatibleVersionKey, "avalanche/1.8.0")
+	_, err = getVersionCompatibility(v, version.GetUpgradeConfig(constants.MainnetID))
+	assert.Error(err)
+	v.Set(NetworkPrevMinCompatibleVersionKey, "avalanche/1.6.0")
+
+	v.Set(NetworkMinUnmaskedTimeKey, "not a time")
+	_, err = getVersionCompatibility(v, version.GetUpgradeConfig(constants.MainnetID))
+	assert.Error(err)
+}
+
+func TestGetUpgradeConfig(t *testing.T) {
+	assert := assert.New(t)
+	customID := uint32(12345)
+
+	// Without an upgrade config, the upgrades activate at their default times
+	v := setupViperFlags()
+	config, err := getUpgradeConfig(v, customID)
+	assert.NoError(err)
+	assert.Equal(version.GetUpgradeConfig(customID), config)
+
+	content := `{"apricotPhase4Time": "2022-01-02T15:04:05Z", "apricotPhase5Time": "2022-01-03T15:04:05Z", "apricotPhase4MinPChainHeight": 10}`
+	v.Set(UpgradeConfigContentKey, base64.StdEncoding.EncodeToString([]byte(content)))
+	config, err = getUpgradeConfig(v, customID)
+	assert.NoError(err)
+	assert.Equal(version.ApricotPhase3DefaultTime, config.ApricotPhase3Time)
+	assert.Equal(time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC), config.ApricotPhase4Time)
+	assert.Equal(time.Date(2022, 1, 3, 15, 4, 5, 0, time.UTC), config.ApricotPhase5Time)
+	assert.EqualValues(10, config.ApricotPhase4MinPChainHeight)
+
+	// The upgrade times of public networks can't be changed
+	_, err = getUpgradeConfig(v, constants.MainnetID)
+	assert.ErrorIs(err, errUpgradeConfigOnPublicNetwork)
+
+	// Upgrades must activate in order
+	content = `{"apricotPhase4Time": "2022-01-02T15:04:05Z", "apricotPhase5Time": "2022-01-01T15:04:05Z"}`
+	v.Set(UpgradeConfigContentKey, base64.StdEncoding.EncodeToString([]byte(content)))
+	_, err = getUpgradeConfig(v, customID)
+	assert.Error(err)
+
+	// Unknown upgrades are rejected
+	content = `{"apricotPhase7Time": "2022-01-02T15:04:05Z"}`
+	v.Set(UpgradeConfigContentKey, base64.StdEncoding.EncodeToString([]byte(content)))
+	_, err = getUpgradeConfig(v, customID)
+	assert.Error(err)
+}
+
+func TestGetPruningConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	// Chains aren't pruned by default
+	v := setupViperFlags()
+	config, err := getPruningConfig(v)
+	assert.NoError(err)
+	assert.Zero(config.Retention)
+
+	v.Set(PruningRetentionKey, 24*time.Hour)
+	config, err = getPruningConfig(v)
+	assert.NoError(err)
+	assert.Equal(24*time.Hour, config.Retention)
+
+	v.Set(PruningFrequencyKey, -time.Second)
+	_, err = getPruningConfig(v)
+	assert.Error(err)
+}
+
+func TestGetBackupConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	// Backups are only written on request by default
+	v := setupViperFlags()
+	config, err := getBackupConfig(v)
+	assert.NoError(err)
+	assert.Zero(config.Freq)
+	assert.Zero(config.MaxNumFiles)
+
+	v.Set(BackupFreqKey, time.Hour)
+	v.Set(BackupMaxFilesKey, 3)
+	config, err = getBackupConfig(v)
+	assert.NoError(err)
+	assert.Equal(time.Hour, config.Freq)
+	assert.Equal(3, config.MaxNumFiles)
+
+	v.Set(BackupMaxFilesKey, -1)
+	_, err = getBackupConfig(v)
+	assert.Error(err)
+}
+
+func TestGetAPIRateLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	// Rate limiting is disabled by default
+	v := setupViperFlags()
+	config, err := getAPIRateLimits(v)
+	assert.NoError(err)
+	assert.Empty(config.Rules)
+
+	v.Set(APIRateLimitsContentKey, base64.StdEncoding.EncodeToString([]byte(`{
+		"keyBy": "token",
+		"rules": [{"endpoint": "/ext/bc/X", "method": "avm.getUTXOs", "rate": 5, "burst": 10}]
+	}`)))
+	config, err = getAPIRateLimits(v)
+	assert.NoError(err)
+	assert.Equal(ratelimit.Config{
+		KeyBy: ratelimit.KeyByToken,
+		Rules: []ratelimit.Rule{{
+			Endpoint: "/ext/bc/X",
+			Method:   "avm.getUTXOs",
+			Rate:     5,
+			Burst:    10,
+		}},
+	}, config)
+
+	v.Set(APIRateLimitsContentKey, base64.StdEncoding.EncodeToString([]byte(`{"rules": [{"rate": 5}]}`)))
+	_, err = getAPIRateLimits(v)
+	assert.Error(err)
+}
+
+func TestGetKeystoreVaultConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	// Users are stored locally by default
+	v := setupViperFlags()
+	config, err := getKeystoreVaultConfig(v, v.GetString(KeystoreBackendKey))
+	assert.NoError(err)
+	assert.Equal(vaultdb.Config{}, config)
+
+	_, err = getKeystoreVaultConfig(v, "disk")
+	assert.Error(err)
+
+	// The vault backend requires an address and a token
+	_, err = getKeystoreVaultConfig(v, node.KeystoreBackendVault)
+	assert.Error(err)
+
+	tokenDir := t.TempDir()
+	setupFile(t, tokenDir, "token", "s.token\n")
+	v.Set(KeystoreVaultAddressKey, "https://127.0.0.1:8200")
+	v.Set(KeystoreVaultTokenFileKey, filepath.Join(tokenDir, "token"))
+	config, err = getKeystoreVaultConfig(v, node.KeystoreBackendVault)
+	assert.NoError(err)
+	assert.Equal(vaultdb.Config{
+		Address:        "https://127.0.0.1:8200",
+		Token:          "s.token",
+		Mount:          vaultdb.DefaultMount,
+		Path:           vaultdb.DefaultPath,
+		RequestTimeout: vaultdb.DefaultRequestTimeout,
+	}, config)
+}
+
+func TestGetLevelDBConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	// The validator profile is used by default
+	v := setupViperFlags()
+	configBytes, err := getLevelDBConfig(v, nil)
+	assert.NoError(err)
+	config := leveldb.Config{}
+	assert.NoError(json.Unmarshal(configBytes, &config))
+	expected, err := leveldb.ProfileConfig(leveldb.ValidatorProfile)
+	assert.NoError(err)
+	assert.Equal(expected, config)
+
+	v.Set(LevelDBProfileKey, "archive")
+	_, err = getLevelDBConfig(v, nil)
+	assert.Error(err)
+
+	// Flags override the profile, and the database config file overrides
+	// the flags
+	v.Set(LevelDBProfileKey, leveldb.APIProfile)
+	v.Set(LevelDBBlockCacheSizeKey, 1024)
+	v.Set(LevelDBOpenFilesKey, 10)
+	configBytes, err = getLevelDBConfig(v, []byte(`{"openFilesCacheCapacity":20}`))
+	assert.NoError(err)
+	config = leveldb.Config{}
+	assert.NoError(json.Unmarshal(configBytes, &config))
+	expected, err = leveldb.ProfileConfig(leveldb.APIProfile)
+	assert.NoError(err)
+	expected.BlockCacheCapacity = 1024
+	expected.OpenFilesCacheCapacity = 20
+	assert.Equal(expected, config)
+
+	v.Set(LevelDBWriteBufferSizeKey, -1)
+	_, err = getLevelDBConfig(v, nil)
+	assert.Error(err)
+}
+
+func TestBuildViperConfigFormats(t *testing.T) {
+	tests := map[string]struct {
+		fileName string
+		content  string
+	}{
+		"json": {
+			fileName: "config.json",
+			content:  `{"http-port": 9652, "network-peer-list-size": 25}`,
+		},
+		"yaml": {
+			fileName: "config.yaml",
+			content:  "# the API port\nhttp-port: 9652\nnetwork-peer-list-size: 25\n",
+		},
+		"toml": {
+			fileName: "config.toml",
+			content:  "# the API port\nhttp-port = 9652\nnetwork-peer-list-size = 25\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			root := t.TempDir()
+			setupFile(t, root, test.fileName, test.content)
+
+			v, err := BuildViper(BuildFlagSet(), []string{"--" + ConfigFileKey + "=" + filepath.Join(root, test.fileName)})
+			assert.NoError(err)
+			assert.Equal(9652, v.GetInt(HTTPPortKey))
+			assert.Equal(25, v.GetInt(NetworkPeerListSizeKey))
+		})
+	}
+}
+
+func TestBuildViperConfigContent(t *testing.T) {
+	assert := assert.New(t)
+
+	content := base64.StdEncoding.EncodeToString([]byte("http-port: 9652\n"))
+	v, err := BuildViper(BuildFlagSet(), []string{
+		"--" + ConfigContentKey + "=" + content,
+		"--" + ConfigContentTypeKey + "=yaml",
+	})
+	assert.NoError(err)
+	assert.Equal(9652, v.GetInt(HTTPPortKey))
+
+	_, err = BuildViper(BuildFlagSet(), []string{
+		"--" + ConfigContentKey + "=" + content,
+		"--" + ConfigContentTypeKey + "=ini",
+	})
+	assert.ErrorIs(err, errUnsupportedConfigFormat)
+}
+
+func TestBuildViperUnknownConfigKeys(t *testing.T) {
+	assert := assert.New(t)
+	root := t.TempDir()
+	setupFile(t, root, "config.yaml", "http-port: 9652\nhttp-prot: 9650\nnetwork:\n  peer-list-size: 25\n")
+
+	_, err := BuildViper(BuildFlagSet(), []string{"--" + ConfigFileKey + "=" + filepath.Join(root, "config.yaml")})
+	assert.ErrorIs(err, errUnknownConfigKeys)
+	assert.Contains(err.Error(), "http-prot, network")
+}
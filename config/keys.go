@@ -8,9 +8,12 @@ const (
 	ConfigFileKey                               = "config-file"
 	ConfigContentKey                            = "config-file-content"
 	ConfigContentTypeKey                        = "config-file-content-type"
+	ConfigValidateKey                           = "config-validate"
 	VersionKey                                  = "version"
 	GenesisConfigFileKey                        = "genesis"
 	GenesisConfigContentKey                     = "genesis-content"
+	UpgradeConfigFileKey                        = "upgrade-config-file"
+	UpgradeConfigContentKey                     = "upgrade-config-file-content"
 	NetworkNameKey                              = "network-id"
 	TxFeeKey                                    = "tx-fee"
 	CreateAssetTxFeeKey                         = "create-asset-tx-fee"
@@ -33,12 +36,24 @@ const (
 	DBPathKey                                   = "db-dir"
 	DBConfigFileKey                             = "db-config-file"
 	DBConfigContentKey                          = "db-config-file-content"
+	DBHealthMinFreeDiskSpaceKey                 = "db-health-min-free-disk-space"
+	DBHealthMaxWriteLatencyKey                  = "db-health-max-write-latency"
+	DBHealthMaxFailedCompactionsKey             = "db-health-max-failed-compactions"
+	LevelDBProfileKey                           = "leveldb-profile"
+	LevelDBBlockCacheSizeKey                    = "leveldb-block-cache-size"
+	LevelDBWriteBufferSizeKey                   = "leveldb-write-buffer-size"
+	LevelDBOpenFilesKey                         = "leveldb-open-files"
+	LevelDBBloomFilterBitsKey                   = "leveldb-bloom-filter-bits"
+	LevelDBCompactionL0TriggerKey               = "leveldb-compaction-l0-trigger"
 	PublicIPKey                                 = "public-ip"
+	PublicIPv6Key                               = "public-ipv6"
 	DynamicUpdateDurationKey                    = "dynamic-update-duration"
 	DynamicPublicIPResolverKey                  = "dynamic-public-ip"
 	InboundConnUpgradeThrottlerCooldownKey      = "inbound-connection-throttling-cooldown"
 	InboundConnUpgradeThrottlerMaxRecentKey     = "inbound-connection-throttling-max-recent" // Deprecated starting in v1.6.0. TODO remove in a future release.
 	InboundThrottlerMaxConnsPerSecKey           = "inbound-connection-throttling-max-conns-per-sec"
+	InboundConnAttemptThrottlerMaxPerIPKey      = "inbound-connection-throttling-max-attempts-per-ip"
+	InboundConnAttemptThrottlerMaxPerSubnetKey  = "inbound-connection-throttling-max-attempts-per-subnet"
 	OutboundConnectionThrottlingRps             = "outbound-connection-throttling-rps"
 	OutboundConnectionTimeout                   = "outbound-connection-timeout"
 	HTTPHostKey                                 = "http-host"
@@ -49,7 +64,14 @@ const (
 	HTTPSCertFileKey                            = "http-tls-cert-file"
 	HTTPSCertContentKey                         = "http-tls-cert-file-content"
 	HTTPAllowedOrigins                          = "http-allowed-origins"
+	HTTPAllowedMethodsKey                       = "http-allowed-methods"
+	HTTPAllowedHeadersKey                       = "http-allowed-headers"
+	HTTPExposedHeadersKey                       = "http-exposed-headers"
+	HTTPCORSMaxAgeKey                           = "http-cors-max-age"
 	HTTPShutdownTimeoutKey                      = "http-shutdown-timeout"
+	APIMaxBatchSizeKey                          = "api-max-batch-size"
+	APIRateLimitsFileKey                        = "api-rate-limits-file"
+	APIRateLimitsContentKey                     = "api-rate-limits-file-content"
 	HTTPShutdownWaitKey                         = "http-shutdown-wait"
 	APIAuthRequiredKey                          = "api-auth-required"
 	APIAuthPasswordKey                          = "api-auth-password"
@@ -63,15 +85,26 @@ const (
 	StakingKeyContentKey                        = "staking-tls-key-file-content"
 	StakingCertPathKey                          = "staking-tls-cert-file"
 	StakingCertContentKey                       = "staking-tls-cert-file-content"
+	StakingKeyPKCS11ModuleKey                   = "staking-tls-key-pkcs11-module"
+	StakingKeyPKCS11TokenLabelKey               = "staking-tls-key-pkcs11-token-label"
+	StakingKeyPKCS11PINKey                      = "staking-tls-key-pkcs11-pin"
+	StakingKeyPKCS11KeyLabelKey                 = "staking-tls-key-pkcs11-key-label"
 	StakingDisabledWeightKey                    = "staking-disabled-weight"
 	NetworkInitialTimeoutKey                    = "network-initial-timeout"
 	NetworkMinimumTimeoutKey                    = "network-minimum-timeout"
 	NetworkMaximumTimeoutKey                    = "network-maximum-timeout"
 	NetworkTimeoutHalflifeKey                   = "network-timeout-halflife"
 	NetworkTimeoutCoefficientKey                = "network-timeout-coefficient"
+	NetworkTimeoutAlgorithmKey                  = "network-timeout-algorithm"
+	NetworkTimeoutPercentileKey                 = "network-timeout-percentile"
+	NetworkTimeoutPercentileWindowSizeKey       = "network-timeout-percentile-window-size"
+	NetworkTimeoutIncreaseCoefficientKey        = "network-timeout-increase-coefficient"
+	NetworkTimeoutDecreaseCoefficientKey        = "network-timeout-decrease-coefficient"
 	NetworkHealthMinPeersKey                    = "network-health-min-conn-peers"
 	NetworkHealthMaxTimeSinceMsgReceivedKey     = "network-health-max-time-since-msg-received"
 	NetworkHealthMaxTimeSinceMsgSentKey         = "network-health-max-time-since-msg-sent"
+	NetworkHealthMaxTimeSinceConsensusMsgKey    = "network-health-max-time-since-consensus-msg-received"
+	NetworkHealthMinConnectedStakeKey           = "network-health-min-connected-stake"
 	NetworkHealthMaxPortionSendQueueFillKey     = "network-health-max-portion-send-queue-full"
 	NetworkHealthMaxSendFailRateKey             = "network-health-max-send-fail-rate"
 	NetworkHealthMaxOutstandingDurationKey      = "network-health-max-outstanding-request-duration"
@@ -79,25 +112,61 @@ const (
 	NetworkPeerListGossipSizeKey                = "network-peer-list-gossip-size"
 	NetworkPeerListGossipFreqKey                = "network-peer-list-gossip-frequency"
 	NetworkPeerListStakerGossipFractionKey      = "network-peer-list-staker-gossip-fraction"
+	NetworkPeerListMaxIPAgeKey                  = "network-peer-list-max-ip-age"
 	NetworkInitialReconnectDelayKey             = "network-initial-reconnect-delay"
 	NetworkGetVersionTimeoutKey                 = "network-get-version-timeout"
 	NetworkReadHandshakeTimeoutKey              = "network-read-handshake-timeout"
 	NetworkPingTimeoutKey                       = "network-ping-timeout"
 	NetworkPingFrequencyKey                     = "network-ping-frequency"
 	NetworkMaxReconnectDelayKey                 = "network-max-reconnect-delay"
+	NetworkReconnectDelayMultiplierKey          = "network-reconnect-delay-multiplier"
+	NetworkReconnectDelayJitterKey              = "network-reconnect-delay-jitter"
+	NetworkMaxReconnectAttemptsKey              = "network-max-reconnect-attempts"
 	NetworkCompressionEnabledKey                = "network-compression-enabled"
+	NetworkCompressionTypeKey                   = "network-compression-type"
+	NetworkCompressionThresholdKey              = "network-compression-threshold"
+	NetworkProtoMessagesEnabledKey              = "network-proto-messages-enabled"
 	NetworkMaxClockDifferenceKey                = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                   = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey         = "network-require-validator-to-connect"
+	NetworkConnectivitySubnetIDKey              = "network-connectivity-subnet-id"
+	NetworkPrimaryNetworkSampleSizeKey          = "network-primary-network-sample-size"
+	NetworkQUICEnabledKey                       = "network-quic-enabled"
+	NetworkPeerStoreSizeKey                     = "network-peer-store-size"
+	NetworkProxyKey                             = "network-proxy"
+	NetworkAllowlistEnabledKey                  = "network-allowlist-enabled"
+	NetworkAllowlistNodeIDsKey                  = "network-allowlist-node-ids"
+	NetworkMinCompatibleVersionKey              = "network-min-compatible-version"
+	NetworkMinCompatibleTimeKey                 = "network-min-compatible-time"
+	NetworkPrevMinCompatibleVersionKey          = "network-prev-min-compatible-version"
+	NetworkMaxCompatibleVersionKey              = "network-max-compatible-version"
+	NetworkMinUnmaskedVersionKey                = "network-min-unmasked-version"
+	NetworkMinUnmaskedTimeKey                   = "network-min-unmasked-time"
+	NetworkPrevMinUnmaskedVersionKey            = "network-prev-min-unmasked-version"
 	BenchlistFailThresholdKey                   = "benchlist-fail-threshold"
 	BenchlistPeerSummaryEnabledKey              = "benchlist-peer-summary-enabled"
 	BenchlistDurationKey                        = "benchlist-duration"
 	BenchlistMinFailingDurationKey              = "benchlist-min-failing-duration"
+	BenchlistFailureHalflifeKey                 = "benchlist-failure-halflife"
+	BenchlistDurationMultiplierKey              = "benchlist-duration-multiplier"
+	BenchlistMaxDurationKey                     = "benchlist-max-duration"
+	BenchlistOffenseHalflifeKey                 = "benchlist-offense-halflife"
+	PeerScoringEnabledKey                       = "peer-scoring-enabled"
+	PeerScoringHalflifeKey                      = "peer-scoring-halflife"
+	PeerScoringPoorThresholdKey                 = "peer-scoring-poor-threshold"
 	BuildDirKey                                 = "build-dir"
 	LogsDirKey                                  = "log-dir"
 	LogLevelKey                                 = "log-level"
 	LogDisplayLevelKey                          = "log-display-level"
 	LogDisplayHighlightKey                      = "log-display-highlight"
+	LogFormatKey                                = "log-format"
+	LogRotaterMaxSizeKey                        = "log-rotater-max-size"
+	LogRotaterMaxFilesKey                       = "log-rotater-max-files"
+	LogRotaterMaxAgeKey                         = "log-rotater-max-age"
+	LogRotaterCompressEnabledKey                = "log-rotater-compress-enabled"
+	CrashReportEnabledKey                       = "crash-report-enabled"
+	CrashReportDirKey                           = "crash-report-dir"
+	CrashReportUploadURLKey                     = "crash-report-upload-url"
 	SnowSampleSizeKey                           = "snow-sample-size"
 	SnowQuorumSizeKey                           = "snow-quorum-size"
 	SnowVirtuousCommitThresholdKey              = "snow-virtuous-commit-threshold"
@@ -109,24 +178,46 @@ const (
 	SnowMaxProcessingKey                        = "snow-max-processing"
 	SnowMaxTimeProcessingKey                    = "snow-max-time-processing"
 	WhitelistedSubnetsKey                       = "whitelisted-subnets"
+	TxForwardingNodeIDsKey                      = "tx-forwarding-node-ids"
 	AdminAPIEnabledKey                          = "api-admin-enabled"
 	InfoAPIEnabledKey                           = "api-info-enabled"
 	KeystoreAPIEnabledKey                       = "api-keystore-enabled"
+	KeystoreArgon2TimeKey                       = "keystore-argon2-time"
+	KeystoreArgon2MemoryKey                     = "keystore-argon2-memory"
+	KeystoreArgon2ThreadsKey                    = "keystore-argon2-threads"
+	KeystoreBackendKey                          = "keystore-backend"
+	KeystoreVaultAddressKey                     = "keystore-vault-address"
+	KeystoreVaultTokenFileKey                   = "keystore-vault-token-file"
+	KeystoreVaultMountKey                       = "keystore-vault-mount"
+	KeystoreVaultPathKey                        = "keystore-vault-path"
+	KeystoreVaultRequestTimeoutKey              = "keystore-vault-request-timeout"
+	KeystoreUserQuotaKey                        = "keystore-user-quota"
+	KeystoreTOTPUnlockDurationKey               = "keystore-totp-unlock-duration"
 	MetricsAPIEnabledKey                        = "api-metrics-enabled"
 	HealthAPIEnabledKey                         = "api-health-enabled"
 	IpcAPIEnabledKey                            = "api-ipcs-enabled"
+	EventsAPIEnabledKey                         = "api-events-enabled"
+	GraphQLAPIEnabledKey                        = "api-graphql-enabled"
 	IpcsChainIDsKey                             = "ipcs-chain-ids"
 	IpcsPathKey                                 = "ipcs-path"
 	MeterVMsEnabledKey                          = "meter-vms-enabled"
 	ConsensusGossipFrequencyKey                 = "consensus-gossip-frequency"
 	ConsensusGossipAcceptedFrontierSizeKey      = "consensus-accepted-frontier-gossip-size"
-	ConsensusGossipOnAcceptSizeKey              = "consensus-on-accept-gossip-size"
+	ConsensusGossipOnAcceptSizeKey              = "consensus-on-accept-gossip-size" // Deprecated starting in v1.7.5. TODO remove in a future release.
+	ConsensusGossipOnAcceptMinSizeKey           = "consensus-on-accept-gossip-min-size"
+	ConsensusGossipOnAcceptMaxSizeKey           = "consensus-on-accept-gossip-max-size"
+	ConsensusGossipRedundancyHalflifeKey        = "consensus-gossip-redundancy-halflife"
+	ConsensusGossipRedundancyThresholdKey       = "consensus-gossip-redundancy-threshold"
 	AppGossipNonValidatorSizeKey                = "consensus-app-gossip-non-validator-size"
 	AppGossipValidatorSizeKey                   = "consensus-app-gossip-validator-size"
 	ConsensusShutdownTimeoutKey                 = "consensus-shutdown-timeout"
+	ConsensusChitCacheDurationKey               = "consensus-chit-cache-duration"
+	ConsensusVRFSamplingKey                     = "consensus-vrf-sampling"
+	ConsensusMaxProcessingMsgsKey               = "consensus-max-processing-msgs"
 	FdLimitKey                                  = "fd-limit"
 	IndexEnabledKey                             = "index-enabled"
 	IndexAllowIncompleteKey                     = "index-allow-incomplete"
+	IndexChainsKey                              = "index-chains"
 	RouterHealthMaxDropRateKey                  = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey       = "router-health-max-outstanding-requests"
 	HealthCheckFreqKey                          = "health-check-frequency"
@@ -139,6 +230,8 @@ const (
 	BootstrapMaxTimeGetAncestorsKey             = "boostrap-max-time-get-ancestors"
 	BootstrapAncestorsMaxContainersSentKey      = "bootstrap-ancestors-max-containers-sent"
 	BootstrapAncestorsMaxContainersReceivedKey  = "bootstrap-ancestors-max-containers-received"
+	BootstrapDeferredFlushFrequencyKey          = "bootstrap-deferred-flush-frequency"
+	BootstrapQueueMaxAgeKey                     = "bootstrap-queue-max-age"
 	ChainConfigDirKey                           = "chain-config-dir"
 	ChainConfigContentKey                       = "chain-config-content"
 	SubnetConfigDirKey                          = "subnet-config-dir"
@@ -147,16 +240,38 @@ const (
 	ProfileContinuousEnabledKey                 = "profile-continuous-enabled"
 	ProfileContinuousFreqKey                    = "profile-continuous-freq"
 	ProfileContinuousMaxFilesKey                = "profile-continuous-max-files"
+	BackupDirKey                                = "backup-dir"
+	BackupFreqKey                               = "backup-frequency"
+	BackupMaxFilesKey                           = "backup-max-files"
+	BackupRestoreFileKey                        = "backup-restore-file"
+	PruningRetentionKey                         = "pruning-retention"
+	PruningFrequencyKey                         = "pruning-frequency"
 	InboundThrottlerAtLargeAllocSizeKey         = "throttler-inbound-at-large-alloc-size"
 	InboundThrottlerVdrAllocSizeKey             = "throttler-inbound-validator-alloc-size"
 	InboundThrottlerNodeMaxAtLargeBytesKey      = "throttler-inbound-node-max-at-large-bytes"
 	InboundThrottlerMaxProcessingMsgsPerNodeKey = "throttler-inbound-node-max-processing-msgs"
 	InboundThrottlerBandwidthRefillRateKey      = "throttler-inbound-bandwidth-refill-rate"
 	InboundThrottlerBandwidthMaxBurstSizeKey    = "throttler-inbound-bandwidth-max-burst-size"
+	InboundThrottlerCPUVdrAllocKey              = "throttler-inbound-cpu-validator-alloc"
+	InboundThrottlerCPUNodeAtLargeAllocKey      = "throttler-inbound-cpu-node-at-large-alloc"
+	InboundThrottlerCPUMaxRecheckDelayKey       = "throttler-inbound-cpu-max-recheck-delay"
 	OutboundThrottlerAtLargeAllocSizeKey        = "throttler-outbound-at-large-alloc-size"
 	OutboundThrottlerVdrAllocSizeKey            = "throttler-outbound-validator-alloc-size"
 	OutboundThrottlerNodeMaxAtLargeBytesKey     = "throttler-outbound-node-max-at-large-bytes"
+	OutboundSubnetBandwidthRefillRateKey        = "throttler-outbound-subnet-bandwidth-refill-rate"
+	OutboundSubnetBandwidthMaxBurstSizeKey      = "throttler-outbound-subnet-bandwidth-max-burst-size"
+	OutboundPeerBandwidthRefillRateKey          = "throttler-outbound-peer-bandwidth-refill-rate"
+	OutboundPeerBandwidthMaxBurstSizeKey        = "throttler-outbound-peer-bandwidth-max-burst-size"
+	OutboundBandwidthRefillRateKey              = "throttler-outbound-bandwidth-refill-rate"
+	OutboundBandwidthMaxBurstSizeKey            = "throttler-outbound-bandwidth-max-burst-size"
 	UptimeMetricFreqKey                         = "uptime-metric-freq"
 	VMAliasesFileKey                            = "vm-aliases-file"
 	VMAliasesContentKey                         = "vm-aliases-file-content"
+	VMRegistryFileKey                           = "vm-registry-file"
+	VMRegistryContentKey                        = "vm-registry-file-content"
+	PluginHealthCheckFreqKey                    = "plugin-health-check-frequency"
+	PluginHealthCheckTimeoutKey                 = "plugin-health-check-timeout"
+	PluginMaxHealthCheckFailuresKey             = "plugin-max-health-check-failures"
+	PluginInitialRestartBackoffKey              = "plugin-initial-restart-backoff"
+	PluginMaxRestartBackoffKey                  = "plugin-max-restart-backoff"
 )
@@ -11,12 +11,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
-var errMissingConfigFormat = errors.New("config content format not specified")
+var (
+	errMissingConfigFormat     = errors.New("config content format not specified")
+	errUnsupportedConfigFormat = errors.New("unsupported config format")
+	errUnknownConfigKeys       = errors.New("unknown config keys")
+
+	// configFormats are the formats that a config file may be written in
+	configFormats = []string{"json", "yaml", "yml", "toml"}
+)
 
 // BuildViper returns the viper environment from parsing config file from
 // default search paths and any parsed command line flags
@@ -50,17 +60,21 @@ func BuildViper(fs *flag.FlagSet, args []string) (*viper.Viper, error) {
 			return nil, fmt.Errorf("unable to decode base64 content: %w", err)
 		}
 
-		v.SetConfigType(v.GetString(ConfigContentTypeKey))
-		if err := v.ReadConfig(bytes.NewBuffer(configBytes)); err != nil {
+		if err := readConfig(v, pfs, v.GetString(ConfigContentTypeKey), configBytes); err != nil {
 			return nil, err
 		}
 
 	case v.IsSet(ConfigFileKey):
 		filename := os.ExpandEnv(v.GetString(ConfigFileKey))
-		v.SetConfigFile(filename)
-		if err := v.ReadInConfig(); err != nil {
+		configBytes, err := os.ReadFile(filename)
+		if err != nil {
 			return nil, err
 		}
+
+		format := strings.TrimPrefix(filepath.Ext(filename), ".")
+		if err := readConfig(v, pfs, format, configBytes); err != nil {
+			return nil, fmt.Errorf("couldn't read config file %s: %w", filename, err)
+		}
 	}
 
 	// Config deprecations must be after v.ReadInConfig
@@ -68,6 +82,44 @@ func BuildViper(fs *flag.FlagSet, args []string) (*viper.Viper, error) {
 	return v, nil
 }
 
+// readConfig reads the config [configBytes], written in [format], into [v].
+// Fails if the config sets a key that isn't a flag of [pfs], so that typos
+// don't silently fall back to the default value.
+func readConfig(v *viper.Viper, pfs *pflag.FlagSet, format string, configBytes []byte) error {
+	format = strings.ToLower(format)
+	supported := false
+	for _, configFormat := range configFormats {
+		supported = supported || format == configFormat
+	}
+	if !supported {
+		return fmt.Errorf("%w %q, expected one of %s", errUnsupportedConfigFormat, format, strings.Join(configFormats, ", "))
+	}
+
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewBuffer(configBytes)); err != nil {
+		return err
+	}
+
+	// Nested keys are flattened by viper, so only the top-level keys are
+	// looked up
+	unknownKeys := map[string]struct{}{}
+	for _, key := range v.AllKeys() {
+		key = strings.SplitN(key, ".", 2)[0]
+		if v.InConfig(key) && pfs.Lookup(key) == nil {
+			unknownKeys[key] = struct{}{}
+		}
+	}
+	if len(unknownKeys) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(unknownKeys))
+	for key := range unknownKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("%w: %s", errUnknownConfigKeys, strings.Join(keys, ", "))
+}
+
 func deprecateConfigs(v *viper.Viper, output io.Writer) {
 	for key, message := range deprecatedKeys {
 		if v.InConfig(key) {
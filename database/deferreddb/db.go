@@ -0,0 +1,273 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package deferreddb
+
+import (
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/nodb"
+	"github.com/Toinounet21/avalanchego-mod/database/versiondb"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+)
+
+// Database buffers all writes in memory until Flush is called, which writes
+// them to the underlying database in a single batch. Once Stop is called,
+// writes are passed directly to the underlying database.
+//
+// Since every write made between two flushes is persisted atomically, a crash
+// loses at most the writes made since the last flush, but never persists only
+// part of them.
+type Database struct {
+	// lock ensures that no write is made to [vdb] after it was stopped
+	lock sync.RWMutex
+	db   database.Database
+	// If nil, writes aren't deferred
+	vdb    *versiondb.Database
+	closed bool
+}
+
+// New returns a database that defers all writes to [db] until they are
+// flushed.
+func New(db database.Database) *Database {
+	return &Database{
+		db:  db,
+		vdb: versiondb.New(db),
+	}
+}
+
+// Flush writes all the deferred writes to the underlying database. If writes
+// are no longer deferred, this is a no-op.
+func (db *Database) Flush() error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	if db.vdb == nil {
+		return nil
+	}
+	return db.vdb.Commit()
+}
+
+// Stop flushes the deferred writes and stops deferring writes.
+func (db *Database) Stop() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.stop()
+}
+
+// Assumes [db.lock] is held.
+func (db *Database) stop() error {
+	if db.closed {
+		return database.ErrClosed
+	}
+	if db.vdb == nil {
+		return nil
+	}
+	if err := db.vdb.Commit(); err != nil {
+		return err
+	}
+	db.vdb = nil
+	return nil
+}
+
+// Deferring returns true if writes are currently deferred
+func (db *Database) Deferring() bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.vdb != nil
+}
+
+// current returns the database that reads and writes should be made to.
+// Assumes [db.lock] is held.
+func (db *Database) current() (database.Database, error) {
+	switch {
+	case db.closed:
+		return nil, database.ErrClosed
+	case db.vdb == nil:
+		return db.db, nil
+	default:
+		return db.vdb, nil
+	}
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	current, err := db.current()
+	if err != nil {
+		return false, err
+	}
+	return current.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	current, err := db.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.Get(key)
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	current, err := db.current()
+	if err != nil {
+		return err
+	}
+	return current.Put(key, value)
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	current, err := db.current()
+	if err != nil {
+		return err
+	}
+	return current.Delete(key)
+}
+
+func (db *Database) NewBatch() database.Batch { return &batch{db: db} }
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	current, err := db.current()
+	if err != nil {
+		return &nodb.Iterator{Err: err}
+	}
+	return current.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+func (db *Database) Stat(stat string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return "", database.ErrClosed
+	}
+	return db.db.Stat(stat)
+}
+
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+// Close flushes the deferred writes. The underlying database isn't closed.
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.stop(); err != nil {
+		return err
+	}
+	db.closed = true
+	return nil
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch buffers its writes until Write is called, so that they're written to
+// the database that is current at that time.
+type batch struct {
+	db     *Database
+	writes []keyValue
+	size   int
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	b.size += len(key)
+	return nil
+}
+
+func (b *batch) Size() int { return b.size }
+
+// Write atomically writes the batch to the current database
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	current, err := b.db.current()
+	if err != nil {
+		return err
+	}
+	currentBatch := current.NewBatch()
+	if err := b.Replay(currentBatch); err != nil {
+		return err
+	}
+	return currentBatch.Write()
+}
+
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.size = 0
+}
+
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself, so that batches written atomically with this batch
+// are deferred along with it.
+func (b *batch) Inner() database.Batch { return b }
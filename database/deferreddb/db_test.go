@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package deferreddb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		baseDB := memdb.New()
+		test(t, New(baseDB))
+	}
+}
+
+func TestInterfaceStopped(t *testing.T) {
+	for _, test := range database.Tests {
+		baseDB := memdb.New()
+		db := New(baseDB)
+		if err := db.Stop(); err != nil {
+			t.Fatal(err)
+		}
+		test(t, db)
+	}
+}
+
+func TestDeferredWrites(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+	assert.True(db.Deferring())
+
+	key1, value1 := []byte("key1"), []byte("value1")
+	key2, value2 := []byte("key2"), []byte("value2")
+	key3, value3 := []byte("key3"), []byte("value3")
+
+	// Writes are visible through [db] but aren't written to [baseDB]
+	assert.NoError(db.Put(key1, value1))
+	batch := db.NewBatch()
+	assert.NoError(batch.Put(key2, value2))
+	assert.NoError(batch.Write())
+	value, err := db.Get(key2)
+	assert.NoError(err)
+	assert.Equal(value2, value)
+	has, err := baseDB.Has(key1)
+	assert.NoError(err)
+	assert.False(has)
+	has, err = baseDB.Has(key2)
+	assert.NoError(err)
+	assert.False(has)
+
+	// Flushing writes them to [baseDB]
+	assert.NoError(db.Flush())
+	value, err = baseDB.Get(key1)
+	assert.NoError(err)
+	assert.Equal(value1, value)
+	value, err = baseDB.Get(key2)
+	assert.NoError(err)
+	assert.Equal(value2, value)
+
+	// Writes are deferred until [db] is stopped
+	assert.NoError(db.Delete(key1))
+	has, err = baseDB.Has(key1)
+	assert.NoError(err)
+	assert.True(has)
+
+	// A batch created while writes were deferred is written to the database
+	// that is current when it's written
+	batch = db.NewBatch()
+	assert.NoError(batch.Put(key3, value3))
+	assert.NoError(db.Stop())
+	assert.False(db.Deferring())
+	has, err = baseDB.Has(key1)
+	assert.NoError(err)
+	assert.False(has)
+	assert.NoError(batch.Write())
+	value, err = baseDB.Get(key3)
+	assert.NoError(err)
+	assert.Equal(value3, value)
+
+	// Flushing a stopped database is a no-op
+	assert.NoError(db.Flush())
+
+	// Closing doesn't close [baseDB]
+	assert.NoError(db.Close())
+	assert.Equal(database.ErrClosed, db.Flush())
+	_, err = baseDB.Get(key3)
+	assert.NoError(err)
+}
+
+func TestCloseFlushes(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key, value := []byte("key"), []byte("value")
+	assert.NoError(db.Put(key, value))
+	assert.NoError(db.Close())
+
+	gotValue, err := baseDB.Get(key)
+	assert.NoError(err)
+	assert.Equal(value, gotValue)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/storage"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+// FailedCompactionsStat is the stat, supported by the databases that compact
+// in the background, of the number of background compactions and flushes that
+// failed since the database was opened
+const FailedCompactionsStat = "failedcompactions"
+
+// healthCheckKey is written to and deleted from the database by every health
+// check
+var healthCheckKey = []byte("health check")
+
+// HealthConfig is the thresholds past which a database is unhealthy
+type HealthConfig struct {
+	// Must have at least this many bytes of free disk space where the database
+	// is stored to be considered healthy. If 0, this isn't checked
+	MinFreeDiskSpace uint64 `json:"minFreeDiskSpace"`
+
+	// Must write to the database within this duration to be considered
+	// healthy. If 0, this isn't checked
+	MaxWriteLatency time.Duration `json:"maxWriteLatency"`
+
+	// Must have had at most this many failed background compactions since
+	// the database was opened to be considered healthy
+	MaxFailedCompactions uint64 `json:"maxFailedCompactions"`
+}
+
+// HealthChecker checks that [db], which is stored at [path], can keep being
+// written to
+type HealthChecker struct {
+	db     Database
+	path   string
+	config HealthConfig
+	clock  mockable.Clock
+}
+
+// NewHealthChecker returns a HealthChecker of [db]. If [path] is empty, the
+// free disk space isn't checked.
+func NewHealthChecker(db Database, path string, config HealthConfig) *HealthChecker {
+	return &HealthChecker{
+		db:     db,
+		path:   path,
+		config: config,
+	}
+}
+
+// HealthCheck writes to the database, and returns how long the write took, how
+// much disk space is free and how many background compactions failed. Returns
+// an error if any of them crosses its threshold.
+func (h *HealthChecker) HealthCheck() (interface{}, error) {
+	details := map[string]interface{}{}
+	var errorReasons []string
+
+	if h.path != "" && h.config.MinFreeDiskSpace != 0 {
+		freeDiskSpace, err := storage.OsDiskStat(h.path)
+		if err != nil {
+			return details, fmt.Errorf("couldn't get free disk space: %w", err)
+		}
+		details["freeDiskSpace"] = freeDiskSpace
+		if freeDiskSpace < h.config.MinFreeDiskSpace {
+			errorReasons = append(errorReasons, fmt.Sprintf("%d bytes of free disk space < %d", freeDiskSpace, h.config.MinFreeDiskSpace))
+		}
+	}
+
+	startTime := h.clock.Time()
+	if err := h.db.Put(healthCheckKey, healthCheckKey); err != nil {
+		return details, fmt.Errorf("couldn't write to the database: %w", err)
+	}
+	if err := h.db.Delete(healthCheckKey); err != nil {
+		return details, fmt.Errorf("couldn't write to the database: %w", err)
+	}
+	writeLatency := h.clock.Time().Sub(startTime)
+	details["writeLatency"] = writeLatency.String()
+	if maxLatency := h.config.MaxWriteLatency; maxLatency != 0 && writeLatency > maxLatency {
+		errorReasons = append(errorReasons, fmt.Sprintf("write took %s > %s", writeLatency, maxLatency))
+	}
+
+	stat, err := h.db.Stat(FailedCompactionsStat)
+	switch err {
+	case nil:
+		failedCompactions, err := strconv.ParseUint(stat, 10, 64)
+		if err != nil {
+			return details, fmt.Errorf("couldn't parse number of failed compactions %q: %w", stat, err)
+		}
+		details["failedCompactions"] = failedCompactions
+		if failedCompactions > h.config.MaxFailedCompactions {
+			errorReasons = append(errorReasons, fmt.Sprintf("%d failed compactions > %d", failedCompactions, h.config.MaxFailedCompactions))
+		}
+	case ErrNotFound:
+		// This database doesn't compact in the background
+	default:
+		return details, fmt.Errorf("couldn't get number of failed compactions: %w", err)
+	}
+
+	if len(errorReasons) != 0 {
+		return details, fmt.Errorf("database is unhealthy reason: %s", strings.Join(errorReasons, ", "))
+	}
+	return details, nil
+}
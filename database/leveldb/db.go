@@ -7,12 +7,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
 	"github.com/Toinounet21/avalanchego-mod/database"
@@ -37,6 +39,14 @@ const (
 	// BitsPerKey is the number of bits to add to the bloom filter per key.
 	BitsPerKey = 10
 
+	// ValidatorProfile tunes leveldb for nodes that mostly verify and accept
+	// blocks, keeping its memory usage low
+	ValidatorProfile = "validator"
+
+	// APIProfile tunes leveldb for nodes that serve many reads over the APIs,
+	// trading memory for fewer disk reads
+	APIProfile = "api"
+
 	// levelDBByteOverhead is the number of bytes of constant overhead that
 	// should be added to a batch size per operation.
 	levelDBByteOverhead = 8
@@ -53,9 +63,14 @@ var (
 // in binary-alphabetical order.
 type Database struct {
 	*leveldb.DB
+
+	// The storage is closed along with the DB
+	stor *compactionErrorStorage
 }
 
-type config struct {
+// Config is the tuning of a leveldb database. Zero values use the defaults of
+// goleveldb.
+type Config struct {
 	// BlockSize is the minimum uncompressed size in bytes of each 'sorted
 	// table' block.
 	BlockCacheCapacity int `json:"blockCacheCapacity"`
@@ -100,13 +115,33 @@ type config struct {
 	FilterBitsPerKey int `json:"filterBitsPerKey"`
 }
 
+// ProfileConfig returns the default tuning of nodes that run with [profile]
+func ProfileConfig(profile string) (Config, error) {
+	switch profile {
+	case ValidatorProfile:
+		return Config{
+			BlockCacheCapacity:     BlockCacheSize,
+			OpenFilesCacheCapacity: HandleCap,
+			WriteBuffer:            WriteBufferSize / 2,
+			FilterBitsPerKey:       BitsPerKey,
+		}, nil
+	case APIProfile:
+		return Config{
+			BlockCacheCapacity:     256 * opt.MiB,
+			OpenFilesCacheCapacity: 1024,
+			WriteBuffer:            64 * opt.MiB,
+			FilterBitsPerKey:       BitsPerKey,
+		}, nil
+	default:
+		return Config{}, fmt.Errorf("unknown leveldb profile %q", profile)
+	}
+}
+
 // New returns a wrapped LevelDB object.
 func New(file string, configBytes []byte, log logging.Logger) (database.Database, error) {
-	parsedConfig := config{
-		BlockCacheCapacity:     BlockCacheSize,
-		OpenFilesCacheCapacity: HandleCap,
-		WriteBuffer:            WriteBufferSize / 2,
-		FilterBitsPerKey:       BitsPerKey,
+	parsedConfig, err := ProfileConfig(ValidatorProfile)
+	if err != nil {
+		return nil, err
 	}
 	if len(configBytes) > 0 {
 		if err := json.Unmarshal(configBytes, &parsedConfig); err != nil {
@@ -119,8 +154,14 @@ func New(file string, configBytes []byte, log logging.Logger) (database.Database
 	}
 	log.Info("leveldb config: %s", string(configJSON))
 
-	// Open the db and recover any potential corruptions
-	db, err := leveldb.OpenFile(file, &opt.Options{
+	// Open the db and recover any potential corruptions. The storage is opened
+	// separately to count the failed compactions that leveldb reports to it.
+	fileStor, err := storage.OpenFile(file, false)
+	if err != nil {
+		return nil, err
+	}
+	stor := &compactionErrorStorage{Storage: fileStor}
+	db, err := leveldb.Open(stor, &opt.Options{
 		BlockCacheCapacity:            parsedConfig.BlockCacheCapacity,
 		BlockSize:                     parsedConfig.BlockSize,
 		CompactionExpandLimitFactor:   parsedConfig.CompactionExpandLimitFactor,
@@ -136,11 +177,16 @@ func New(file string, configBytes []byte, log logging.Logger) (database.Database
 		Filter:                        filter.NewBloomFilter(parsedConfig.FilterBitsPerKey),
 	})
 	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
-		db, err = leveldb.RecoverFile(file, nil)
+		db, err = leveldb.Recover(stor, nil)
+	}
+	if err != nil {
+		_ = stor.Close()
+		return nil, err
 	}
 	return &Database{
-		DB: db,
-	}, err
+		DB:   db,
+		stor: stor,
+	}, nil
 }
 
 // Has returns if the key is set in the database
@@ -199,6 +245,9 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 
 // Stat returns a particular internal stat of the database.
 func (db *Database) Stat(property string) (string, error) {
+	if property == database.FailedCompactionsStat {
+		return strconv.FormatUint(db.stor.failedCompactions(), 10), nil
+	}
 	stat, err := db.DB.GetProperty(property)
 	return stat, updateError(err)
 }
@@ -219,7 +268,12 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 }
 
 // Close implements the Database interface
-func (db *Database) Close() error { return updateError(db.DB.Close()) }
+func (db *Database) Close() error {
+	if err := db.DB.Close(); err != nil {
+		return updateError(err)
+	}
+	return db.stor.Close()
+}
 
 // batch is a wrapper around a levelDB batch to contain sizes.
 type batch struct {
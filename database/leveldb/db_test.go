@@ -4,7 +4,12 @@
 package leveldb
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	"github.com/Toinounet21/avalanchego-mod/database"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
@@ -28,6 +33,40 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	folder := t.TempDir()
+	db, err := New(folder, nil, logging.NoLog{})
+	assert.NoError(err)
+	defer db.Close()
+
+	checker := database.NewHealthChecker(db, folder, database.HealthConfig{
+		MinFreeDiskSpace: 1,
+		MaxWriteLatency:  time.Hour,
+	})
+	details, err := checker.HealthCheck()
+	assert.NoError(err)
+	assert.Equal(uint64(0), details.(map[string]interface{})["failedCompactions"])
+	has, err := db.Has([]byte("health check"))
+	assert.NoError(err)
+	assert.False(has)
+
+	// leveldb reports failed compactions by logging them
+	db.(*Database).stor.Log(`table@build error I·0 "no space left on device"`)
+	db.(*Database).stor.Log("table@build committed F·1")
+	details, err = checker.HealthCheck()
+	assert.Error(err)
+	assert.Equal(uint64(1), details.(map[string]interface{})["failedCompactions"])
+
+	checker = database.NewHealthChecker(db, folder, database.HealthConfig{
+		MinFreeDiskSpace:     math.MaxUint64,
+		MaxFailedCompactions: 1,
+	})
+	_, err = checker.HealthCheck()
+	assert.Error(err)
+}
+
 func BenchmarkInterface(b *testing.B) {
 	for _, size := range database.BenchmarkSizes {
 		keys, values := database.SetupBenchmark(b, size[0], size[1], size[2])
@@ -47,3 +86,24 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+func TestProfileConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	validatorConfig, err := ProfileConfig(ValidatorProfile)
+	assert.NoError(err)
+	apiConfig, err := ProfileConfig(APIProfile)
+	assert.NoError(err)
+	assert.Greater(apiConfig.BlockCacheCapacity, validatorConfig.BlockCacheCapacity)
+	assert.Greater(apiConfig.WriteBuffer, validatorConfig.WriteBuffer)
+
+	_, err = ProfileConfig("archive")
+	assert.Error(err)
+
+	// The profile's config can be used to open a database
+	configBytes, err := json.Marshal(&apiConfig)
+	assert.NoError(err)
+	db, err := New(t.TempDir(), configBytes, logging.NoLog{})
+	assert.NoError(err)
+	assert.NoError(db.Close())
+}
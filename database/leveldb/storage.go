@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package leveldb
+
+import (
+	"regexp"
+	"sync/atomic"
+
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// leveldb doesn't expose the errors of its background compactions. It only
+// logs them to its storage, as "<task> error ...", where task is
+// "table@build" or "memdb@flush", and retries them.
+var compactionErrorLog = regexp.MustCompile(`^\w+@\w+ error `)
+
+// compactionErrorStorage counts the failed compactions that leveldb logs to
+// the storage it wraps
+type compactionErrorStorage struct {
+	storage.Storage
+
+	numFailedCompactions uint64 // atomic
+}
+
+func (s *compactionErrorStorage) Log(str string) {
+	if compactionErrorLog.MatchString(str) {
+		atomic.AddUint64(&s.numFailedCompactions, 1)
+	}
+	s.Storage.Log(str)
+}
+
+// failedCompactions returns the number of compactions that failed since the
+// storage was opened
+func (s *compactionErrorStorage) failedCompactions() uint64 {
+	return atomic.LoadUint64(&s.numFailedCompactions)
+}
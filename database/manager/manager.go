@@ -17,6 +17,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/database/leveldb"
 	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/database/meterdb"
+	"github.com/Toinounet21/avalanchego-mod/database/observerdb"
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/database/rocksdb"
 	"github.com/Toinounet21/avalanchego-mod/utils"
@@ -65,6 +66,11 @@ type Manager interface {
 	// Note: calling this more than once with the same [namespace] will cause a
 	// conflict error for the [registerer].
 	NewCompleteMeterDBManager(namespace string, registerer prometheus.Registerer) (Manager, error)
+
+	// NewObservedDBManager returns a new database manager with each of its
+	// databases wrapped with an observerdb instance that calls [onWrite] with
+	// the number of bytes of each write.
+	NewObservedDBManager(onWrite func(bytes int)) Manager
 }
 
 type manager struct {
@@ -308,6 +314,18 @@ func (m *manager) NewCompleteMeterDBManager(namespace string, registerer prometh
 	})
 }
 
+// NewObservedDBManager creates a new manager with each database instance
+// wrapped with an observerdb that reports its writes to [onWrite]
+func (m *manager) NewObservedDBManager(onWrite func(bytes int)) Manager {
+	m, _ = m.wrapManager(func(vdb *VersionedDatabase) (*VersionedDatabase, error) {
+		return &VersionedDatabase{
+			Database: observerdb.New(onWrite, vdb.Database),
+			Version:  vdb.Version,
+		}, nil
+	})
+	return m
+}
+
 // wrapManager returns a new database manager with each managed database wrapped
 // by the [wrap] function. If an error is returned by wrap, the error is
 // returned immediately. If [wrap] never returns an error, then wrapManager is
@@ -157,6 +157,22 @@ func (_m *Manager) NewNestedPrefixDBManager(prefix []byte) manager.Manager {
 	return r0
 }
 
+// NewObservedDBManager provides a mock function with given fields: onWrite
+func (_m *Manager) NewObservedDBManager(onWrite func(int)) manager.Manager {
+	ret := _m.Called(onWrite)
+
+	var r0 manager.Manager
+	if rf, ok := ret.Get(0).(func(func(int)) manager.Manager); ok {
+		r0 = rf(onWrite)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(manager.Manager)
+		}
+	}
+
+	return r0
+}
+
 // NewPrefixDBManager provides a mock function with given fields: prefix
 func (_m *Manager) NewPrefixDBManager(prefix []byte) manager.Manager {
 	ret := _m.Called(prefix)
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package observerdb
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/database"
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+)
+
+// Database is a wrapper around Database that reports the number of bytes
+// written to it, such as to enforce a limit on the rate of writes
+type Database struct {
+	database.Database
+
+	// Called with the number of bytes of each write
+	onWrite func(bytes int)
+}
+
+// New returns a new database that calls [onWrite] with the number of bytes of
+// each write to [db]
+func New(onWrite func(bytes int), db database.Database) *Database {
+	return &Database{
+		Database: db,
+		onWrite:  onWrite,
+	}
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.onWrite(len(key) + len(value))
+	return db.Database.Put(key, value)
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.onWrite(len(key))
+	return db.Database.Delete(key)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+// batch is a wrapper around the batch that reports its size when it's written
+type batch struct {
+	database.Batch
+	db *Database
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.onWrite(b.Batch.Size())
+	return b.Batch.Write()
+}
+
+func (b *batch) Inner() database.Batch { return b.Batch }
@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package observerdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db := New(func(int) {}, memdb.New())
+		test(t, db)
+	}
+}
+
+func TestObservesWrites(t *testing.T) {
+	assert := assert.New(t)
+
+	written := 0
+	db := New(func(bytes int) { written += bytes }, memdb.New())
+
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+	assert.Equal(10, written)
+
+	assert.NoError(db.Delete([]byte("hello")))
+	assert.Equal(15, written)
+
+	_, err := db.Get([]byte("hello"))
+	assert.ErrorIs(err, database.ErrNotFound)
+	assert.Equal(15, written, "reads shouldn't be observed")
+
+	batch := db.NewBatch()
+	assert.NoError(batch.Put([]byte("key"), []byte("value")))
+	assert.Equal(15, written, "batches should only be observed when they're written")
+	size := batch.Size()
+	assert.NoError(batch.Write())
+	assert.Equal(15+size, written)
+}
@@ -248,9 +248,20 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
-// Stat returns a particular internal stat of the database.
+// Stat returns a particular internal stat of the database. Only
+// database.FailedCompactionsStat is supported.
 func (db *Database) Stat(property string) (string, error) {
-	return "", database.ErrNotFound
+	if property != database.FailedCompactionsStat {
+		return "", database.ErrNotFound
+	}
+
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return "", database.ErrClosed
+	}
+	return db.db.GetProperty("rocksdb.background-errors"), nil
 }
 
 // Compact the underlying DB for the given key range.
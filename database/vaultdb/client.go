@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vaultdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	tokenHeader = "X-Vault-Token"
+
+	// Keys are stored under names that start with [keyNamePrefix], so that
+	// the empty key has a valid name
+	keyNamePrefix = "k"
+)
+
+// client reads and writes the secrets under a path of a KV version 2 secrets
+// engine through Vault's HTTP API
+type client struct {
+	address string
+	token   string
+	mount   string
+	path    string
+	timeout time.Duration
+	http    *http.Client
+}
+
+func newClient(config Config) *client {
+	return &client{
+		address: strings.TrimSuffix(config.Address, "/"),
+		token:   config.Token,
+		mount:   strings.Trim(config.Mount, "/"),
+		path:    strings.Trim(config.Path, "/"),
+		timeout: config.RequestTimeout,
+		http:    &http.Client{},
+	}
+}
+
+type secretData struct {
+	Value string `json:"value"`
+}
+
+type readResponse struct {
+	Data struct {
+		Data secretData `json:"data"`
+	} `json:"data"`
+}
+
+type writeRequest struct {
+	Data secretData `json:"data"`
+}
+
+type listResponse struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+// list returns the keys stored under the path
+func (c *client) list() ([][]byte, error) {
+	response := listResponse{}
+	found, err := c.do("LIST", c.url("metadata", ""), nil, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+	keys := make([][]byte, 0, len(response.Data.Keys))
+	for _, name := range response.Data.Keys {
+		if !strings.HasPrefix(name, keyNamePrefix) {
+			// Not written by this database
+			continue
+		}
+		key, err := hex.DecodeString(strings.TrimPrefix(name, keyNamePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse key %q: %w", name, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// get returns the value of [key]. If [key] isn't stored, it returns false.
+func (c *client) get(key []byte) ([]byte, bool, error) {
+	response := readResponse{}
+	found, err := c.do(http.MethodGet, c.url("data", keyName(key)), nil, &response)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	value, err := hex.DecodeString(response.Data.Data.Value)
+	if err != nil {
+		return nil, false, fmt.Errorf("couldn't parse value of key %q: %w", keyName(key), err)
+	}
+	return value, true, nil
+}
+
+func (c *client) put(key, value []byte) error {
+	_, err := c.do(http.MethodPost, c.url("data", keyName(key)), &writeRequest{
+		Data: secretData{Value: hex.EncodeToString(value)},
+	}, nil)
+	return err
+}
+
+// delete removes [key] and all of its versions
+func (c *client) delete(key []byte) error {
+	_, err := c.do(http.MethodDelete, c.url("metadata", keyName(key)), nil, nil)
+	return err
+}
+
+func (c *client) url(endpoint, name string) string {
+	return fmt.Sprintf("%s/v1/%s/%s/%s/%s", c.address, c.mount, endpoint, c.path, name)
+}
+
+// do sends a request to Vault and decodes its response into [reply], if it's
+// non-nil. It returns false if Vault responded that nothing is stored at the
+// requested path.
+func (c *client) do(method, url string, args interface{}, reply interface{}) (bool, error) {
+	var body io.Reader
+	if args != nil {
+		argsBytes, err := json.Marshal(args)
+		if err != nil {
+			return false, err
+		}
+		body = bytes.NewReader(argsBytes)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set(tokenHeader, c.token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return false, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return false, nil
+	case response.StatusCode < 200 || response.StatusCode >= 300:
+		// Vault's error responses don't contain secrets, so they're safe to
+		// report
+		errorBody, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return false, fmt.Errorf("vault responded to %s with status %d: %s", method, response.StatusCode, strings.TrimSpace(string(errorBody)))
+	case reply == nil || response.StatusCode == http.StatusNoContent:
+		return true, nil
+	}
+	return true, json.NewDecoder(response.Body).Decode(reply)
+}
+
+func keyName(key []byte) string {
+	return keyNamePrefix + hex.EncodeToString(key)
+}
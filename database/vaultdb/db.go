@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vaultdb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "vaultdb"
+
+	DefaultMount          = "secret"
+	DefaultPath           = "avalanchego/keystore"
+	DefaultRequestTimeout = 10 * time.Second
+)
+
+var (
+	errNoAddress = errors.New("vault address must be specified")
+	errNoToken   = errors.New("vault token must be specified")
+	errNoMount   = errors.New("vault mount must be specified")
+	errNoPath    = errors.New("vault path must be specified")
+	errNoTimeout = errors.New("vault request timeout must be > 0")
+
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+)
+
+// Config describes where a Database stores its key-value pairs in Vault
+type Config struct {
+	// Address of the Vault server. e.g. https://127.0.0.1:8200
+	Address string `json:"address"`
+	// Token used to authenticate to Vault
+	Token string `json:"-"`
+	// Mount path of the KV version 2 secrets engine
+	Mount string `json:"mount"`
+	// Path, under [Mount], that the key-value pairs are stored under
+	Path string `json:"path"`
+	// Timeout of each request to Vault
+	RequestTimeout time.Duration `json:"requestTimeout"`
+}
+
+// Verify returns an error if the config is invalid
+func (c *Config) Verify() error {
+	switch {
+	case c.Address == "":
+		return errNoAddress
+	case c.Token == "":
+		return errNoToken
+	case c.Mount == "":
+		return errNoMount
+	case c.Path == "":
+		return errNoPath
+	case c.RequestTimeout <= 0:
+		return errNoTimeout
+	default:
+		return nil
+	}
+}
+
+// Database is a key-value store whose key-value pairs are stored as secrets
+// in a HashiCorp Vault KV version 2 secrets engine, so that they never touch
+// the local disk.
+//
+// Every key-value pair is read from Vault when the database is created and is
+// kept in memory, so reads and iteration don't make requests to Vault. Writes
+// are made to Vault before they're applied in memory. Batches are written to
+// Vault one key at a time, so a batch that fails to be written may have been
+// partially written.
+type Database struct {
+	// Serializes writes so that Vault and the in-memory copy agree
+	lock   sync.Mutex
+	client *client
+	*memdb.Database
+	closed bool
+}
+
+// New returns a Database that stores its key-value pairs under the path in
+// Vault described by [config]
+func New(config Config) (*Database, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	db := &Database{
+		client:   newClient(config),
+		Database: memdb.New(),
+	}
+	keys, err := db.client.list()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list keys: %w", err)
+	}
+	for _, key := range keys {
+		value, ok, err := db.client.get(key)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read key: %w", err)
+		}
+		if !ok {
+			// The key was deleted since it was listed
+			continue
+		}
+		if err := db.Database.Put(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.closed = true
+	return db.Database.Close()
+}
+
+// Put implements the Database interface
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.put(key, value)
+}
+
+// Delete implements the Database interface
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.delete(key)
+}
+
+// NewBatch implements the Database interface
+func (db *Database) NewBatch() database.Batch { return &batch{db: db} }
+
+// assumes [db.lock] is held
+func (db *Database) put(key []byte, value []byte) error {
+	if db.closed {
+		return database.ErrClosed
+	}
+	if err := db.client.put(key, value); err != nil {
+		return err
+	}
+	return db.Database.Put(key, value)
+}
+
+// assumes [db.lock] is held
+func (db *Database) delete(key []byte) error {
+	if db.closed {
+		return database.ErrClosed
+	}
+	if err := db.client.delete(key); err != nil {
+		return err
+	}
+	return db.Database.Delete(key)
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+type batch struct {
+	db     *Database
+	writes []keyValue
+	size   int
+}
+
+// Put implements the Batch interface
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+// Delete implements the Batch interface
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	b.size += len(key)
+	return nil
+}
+
+// Size implements the Batch interface
+func (b *batch) Size() int { return b.size }
+
+// Write implements the Batch interface
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+	return b.Replay(writer{db: b.db})
+}
+
+// Reset implements the Batch interface
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.size = 0
+}
+
+// Replay implements the Batch interface
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, keyvalue := range b.writes {
+		if keyvalue.delete {
+			if err := w.Delete(keyvalue.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(keyvalue.key, keyvalue.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+// writer writes to a database whose lock is already held
+type writer struct {
+	db *Database
+}
+
+func (w writer) Put(key, value []byte) error { return w.db.put(key, value) }
+func (w writer) Delete(key []byte) error     { return w.db.delete(key) }
@@ -0,0 +1,159 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vaultdb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+)
+
+const (
+	testToken = "token"
+	testMount = "secret"
+	testPath  = "node/keystore"
+)
+
+// fakeVault serves the subset of the KV version 2 secrets engine's HTTP API
+// that the database uses
+type fakeVault struct {
+	lock    sync.Mutex
+	secrets map[string]string
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if r.Header.Get(tokenHeader) != testToken {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+
+	dataPrefix := "/v1/" + testMount + "/data/" + testPath + "/"
+	metadataPrefix := "/v1/" + testMount + "/metadata/" + testPath + "/"
+	switch {
+	case r.Method == "LIST" && r.URL.Path == metadataPrefix:
+		if len(v.secrets) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		response := listResponse{}
+		for name := range v.secrets {
+			response.Data.Keys = append(response.Data.Keys, name)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, dataPrefix):
+		value, ok := v.secrets[strings.TrimPrefix(r.URL.Path, dataPrefix)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		response := readResponse{}
+		response.Data.Data.Value = value
+		_ = json.NewEncoder(w).Encode(response)
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, dataPrefix):
+		request := writeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.secrets[strings.TrimPrefix(r.URL.Path, dataPrefix)] = request.Data.Value
+		_, _ = w.Write([]byte(`{"data":{"version":1}}`))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, metadataPrefix):
+		delete(v.secrets, strings.TrimPrefix(r.URL.Path, metadataPrefix))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestConfig(address string) Config {
+	return Config{
+		Address:        address,
+		Token:          testToken,
+		Mount:          testMount,
+		Path:           testPath,
+		RequestTimeout: DefaultRequestTimeout,
+	}
+}
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		vault := httptest.NewServer(&fakeVault{secrets: make(map[string]string)})
+		db, err := New(newTestConfig(vault.URL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		test(t, db)
+		vault.Close()
+	}
+}
+
+func TestReadFromVault(t *testing.T) {
+	assert := assert.New(t)
+
+	vault := httptest.NewServer(&fakeVault{secrets: make(map[string]string)})
+	defer vault.Close()
+
+	db, err := New(newTestConfig(vault.URL))
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+	assert.NoError(db.Put([]byte{}, []byte("empty key")))
+	assert.NoError(db.Put([]byte("deleted"), []byte("value")))
+	assert.NoError(db.Delete([]byte("deleted")))
+
+	batch := db.NewBatch()
+	assert.NoError(batch.Put([]byte("batched"), []byte("value")))
+	assert.NoError(batch.Write())
+	assert.NoError(db.Close())
+
+	// A database created with the same config reads what was written
+	db, err = New(newTestConfig(vault.URL))
+	assert.NoError(err)
+
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), value)
+	value, err = db.Get([]byte{})
+	assert.NoError(err)
+	assert.Equal([]byte("empty key"), value)
+	value, err = db.Get([]byte("batched"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	_, err = db.Get([]byte("deleted"))
+	assert.Equal(database.ErrNotFound, err)
+}
+
+func TestVaultErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	vault := httptest.NewServer(&fakeVault{secrets: make(map[string]string)})
+	defer vault.Close()
+
+	config := newTestConfig(vault.URL)
+	config.Token = "wrong token"
+	_, err := New(config)
+	assert.Error(err)
+
+	config.Token = ""
+	_, err = New(config)
+	assert.Equal(errNoToken, err)
+
+	// Failed writes aren't applied
+	db, err := New(newTestConfig(vault.URL))
+	assert.NoError(err)
+	db.client.token = "wrong token"
+	assert.Error(db.Put([]byte("hello"), []byte("world")))
+	has, err := db.Has([]byte("hello"))
+	assert.NoError(err)
+	assert.False(has)
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+// subscription of a client to the decisions of a chain
+type subscription struct {
+	accepted     bool
+	rejected     bool
+	includeBytes bool
+	// If non-empty, only the containers referencing one of these addresses
+	// are sent
+	addresses ids.ShortSet
+}
+
+// client is a websocket connection subscribed to the decisions of chains
+type client struct {
+	s *Server
+
+	// The websocket connection.
+	conn *websocket.Conn
+
+	// Buffered channel of outbound messages.
+	sendQueue chan interface{}
+
+	lock sync.RWMutex
+	// Chain ID --> subscription of this client to the chain
+	subscriptions map[ids.ID]subscription
+	closed        bool
+	// Closed when the client stops
+	done chan struct{}
+}
+
+func newClient(s *Server, conn *websocket.Conn) *client {
+	return &client{
+		s:             s,
+		conn:          conn,
+		sendQueue:     make(chan interface{}, maxPendingMessages),
+		subscriptions: make(map[ids.ID]subscription),
+		done:          make(chan struct{}),
+	}
+}
+
+// subscription returns the subscription of this client to [event] on
+// [chainID], if any
+func (c *client) subscription(chainID ids.ID, event string) (subscription, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	sub, ok := c.subscriptions[chainID]
+	if !ok {
+		return sub, false
+	}
+	switch event {
+	case Accepted:
+		return sub, sub.accepted
+	case Rejected:
+		return sub, sub.rejected
+	default:
+		return sub, false
+	}
+}
+
+func (c *client) subscribe(chainIDs []ids.ID, sub subscription) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, chainID := range chainIDs {
+		c.subscriptions[chainID] = sub
+	}
+}
+
+func (c *client) unsubscribe(chainIDs []ids.ID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, chainID := range chainIDs {
+		delete(c.subscriptions, chainID)
+	}
+}
+
+// send queues [msg] to be written to the client. Returns false if the
+// message was dropped. Never blocks.
+func (c *client) send(msg interface{}) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.sendQueue <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop removes this client from the server and closes its connection. Safe to
+// call more than once.
+func (c *client) stop() {
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return
+	}
+	c.closed = true
+	close(c.done)
+	c.lock.Unlock()
+
+	c.s.removeClient(c)
+	_ = c.conn.Close()
+}
+
+// readPump reads the commands of the client.
+//
+// There is at most one reader on a connection as all reads are executed from
+// this goroutine.
+func (c *client) readPump() {
+	defer c.stop()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	// SetReadDeadline returns an error if the connection is corrupted
+	if err := c.conn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+		return
+	}
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, r, err := c.conn.NextReader()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.s.config.Log.Debug("unexpected close in events websocket: %s", err)
+			}
+			return
+		}
+
+		reply := &Reply{}
+		cmd := &Command{}
+		if err := json.NewDecoder(r).Decode(cmd); err != nil {
+			reply.Command = "unknown"
+			reply.Error = err.Error()
+		} else {
+			reply.Command, err = c.s.handle(c, cmd)
+			if err != nil {
+				reply.Error = err.Error()
+			}
+		}
+		reply.Success = reply.Error == ""
+		c.send(reply)
+	}
+}
+
+// writePump writes the queued messages to the client.
+//
+// There is at most one writer to a connection as all writes are executed from
+// this goroutine.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.stop()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.sendQueue:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				c.s.config.Log.Debug("failed to set the write deadline, closing the connection due to %s", err)
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				c.s.config.Log.Debug("failed to set the write deadline, closing the connection due to %s", err)
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+)
+
+const (
+	// Accepted is the event of a container being accepted
+	Accepted = "accepted"
+	// Rejected is the event of a container being rejected
+	Rejected = "rejected"
+)
+
+// Subscribe command to receive the decisions of chains
+type Subscribe struct {
+	// IDs or aliases of the chains to receive the decisions of
+	Chains []string `json:"chains"`
+	// Events to receive. Must be [Accepted] or [Rejected]. If empty, both are
+	// received.
+	Events []string `json:"events"`
+	// If true, the bytes of the decided containers are included
+	IncludeBytes bool `json:"includeBytes"`
+	// If non-empty, only the containers that reference one of these addresses
+	// are received. Only chains whose VM reports the addresses referenced by
+	// its containers support address filters.
+	Addresses []string `json:"addresses"`
+}
+
+// Unsubscribe command to stop receiving the decisions of chains
+type Unsubscribe struct {
+	// IDs or aliases of the chains to stop receiving the decisions of
+	Chains []string `json:"chains"`
+}
+
+// Command sent by a client
+type Command struct {
+	Subscribe   *Subscribe   `json:"subscribe,omitempty"`
+	Unsubscribe *Unsubscribe `json:"unsubscribe,omitempty"`
+}
+
+// Decision is sent to the clients subscribed to the chain of a decided
+// container
+type Decision struct {
+	ChainID     ids.ID `json:"chainID"`
+	Event       string `json:"event"`
+	ContainerID ids.ID `json:"containerID"`
+	// Only set if the client subscribed with [IncludeBytes]
+	Container string              `json:"container,omitempty"`
+	Encoding  formatting.Encoding `json:"encoding,omitempty"`
+}
+
+// Reply is sent to a client after each of its commands
+type Reply struct {
+	Command string `json:"command"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,354 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Toinounet21/avalanchego-mod/chains"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+const (
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum message size allowed from peer.
+	maxMessageSize = 1 * units.MiB
+
+	// Size of the ws read buffer
+	readBufferSize = units.KiB
+
+	// Size of the ws write buffer
+	writeBufferSize = units.KiB
+
+	// Maximum number of pending messages to send to a client
+	maxPendingMessages = 1024
+
+	// Maximum number of addresses a client may filter on
+	maxAddresses = 10000
+)
+
+var (
+	errUnknownChain      = errors.New("unknown chain")
+	errUnknownEvent      = errors.New("unknown event")
+	errNoChains          = errors.New("no chains specified")
+	errNoAddressSupport  = errors.New("chain doesn't support address filters")
+	errTooManyAddresses  = errors.New("address limit exceeded")
+	errInvalidCommand    = errors.New("invalid command")
+	errTooManyNewClients = errors.New("too many clients")
+
+	_ snow.Acceptor     = &Server{}
+	_ snow.Rejector     = &Server{}
+	_ chains.Registrant = &Server{}
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  readBufferSize,
+	WriteBufferSize: writeBufferSize,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// Addresser is implemented by the VMs that can report the addresses a
+// decided container references, so that clients can filter a chain's
+// decisions by address.
+type Addresser interface {
+	// Addresses returns the addresses referenced by [container]
+	Addresses(container []byte) ([][]byte, error)
+}
+
+// ChainLookup resolves chain aliases
+type ChainLookup interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// Config of the events server
+type Config struct {
+	Log    logging.Logger
+	Chains ChainLookup
+	// Max number of clients connected at once
+	MaxClients int
+}
+
+// Server publishes the containers decided by the chains to the clients
+// subscribed to them over WebSocket.
+//
+// Server must be subscribed to the decision events bus to receive the
+// decisions, and registered with the chain manager to learn about the chains.
+type Server struct {
+	config Config
+
+	lock sync.RWMutex
+	// Chain ID --> VM of the chain, or nil if the VM isn't an Addresser
+	chains map[ids.ID]Addresser
+	// Clients currently connected
+	clients map[*client]struct{}
+}
+
+// NewServer returns a new events server
+func NewServer(config Config) *Server {
+	return &Server{
+		config:  config,
+		chains:  make(map[ids.ID]Addresser),
+		clients: make(map[*client]struct{}),
+	}
+}
+
+// RegisterChain implements the chains.Registrant interface
+func (s *Server) RegisterChain(name string, engine common.Engine) {
+	addresser, _ := engine.GetVM().(Addresser)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.config.Log.Debug("publishing the decisions of chain %s", name)
+	s.chains[engine.Context().ChainID] = addresser
+}
+
+// Accept implements the snow.Acceptor interface
+func (s *Server) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	s.publish(ctx.ChainID, Accepted, containerID, container)
+	return nil
+}
+
+// Reject implements the snow.Rejector interface
+func (s *Server) Reject(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	s.publish(ctx.ChainID, Rejected, containerID, container)
+	return nil
+}
+
+func (s *Server) publish(chainID ids.ID, event string, containerID ids.ID, container []byte) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	addresser, ok := s.chains[chainID]
+	if !ok || len(s.clients) == 0 {
+		return
+	}
+
+	var (
+		// Lazily computed
+		addrs        ids.ShortSet
+		addrsErr     error
+		addrsParsed  bool
+		encoded      string
+		encodedErr   error
+		encodedBytes bool
+	)
+	for c := range s.clients {
+		sub, ok := c.subscription(chainID, event)
+		if !ok {
+			continue
+		}
+
+		if sub.addresses.Len() > 0 {
+			if !addrsParsed {
+				addrs, addrsErr = parseAddresses(addresser, container)
+				addrsParsed = true
+				if addrsErr != nil {
+					s.config.Log.Debug("couldn't get the addresses of %s on chain %s: %s", containerID, chainID, addrsErr)
+				}
+			}
+			if !overlaps(sub.addresses, addrs) {
+				continue
+			}
+		}
+
+		decision := &Decision{
+			ChainID:     chainID,
+			Event:       event,
+			ContainerID: containerID,
+		}
+		if sub.includeBytes {
+			if !encodedBytes {
+				encoded, encodedErr = formatting.EncodeWithChecksum(formatting.Hex, container)
+				encodedBytes = true
+			}
+			if encodedErr == nil {
+				decision.Container = encoded
+				decision.Encoding = formatting.Hex
+			}
+		}
+		if !c.send(decision) {
+			s.config.Log.Verbo("dropping decision of %s to client due to too many pending messages", containerID)
+		}
+	}
+}
+
+// parseAddresses returns the addresses referenced by [container]
+func parseAddresses(addresser Addresser, container []byte) (ids.ShortSet, error) {
+	addrs := ids.ShortSet{}
+	if addresser == nil {
+		return addrs, nil
+	}
+	addrsBytes, err := addresser.Addresses(container)
+	if err != nil {
+		return addrs, err
+	}
+	for _, addrBytes := range addrsBytes {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return addrs, err
+		}
+		addrs.Add(addr)
+	}
+	return addrs, nil
+}
+
+// overlaps returns true if [a] and [b] share an element
+func overlaps(a, b ids.ShortSet) bool {
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	for addr := range a {
+		if b.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection with a new client
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.RLock()
+	numClients := len(s.clients)
+	s.lock.RUnlock()
+	if s.config.MaxClients > 0 && numClients >= s.config.MaxClients {
+		http.Error(w, errTooManyNewClients.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.config.Log.Debug("failed to upgrade %s", err)
+		return
+	}
+	c := newClient(s, wsConn)
+
+	s.lock.Lock()
+	s.clients[c] = struct{}{}
+	s.lock.Unlock()
+
+	go c.writePump()
+	go c.readPump()
+}
+
+func (s *Server) removeClient(c *client) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.clients, c)
+}
+
+// handle applies [cmd] to the subscriptions of [c]
+func (s *Server) handle(c *client, cmd *Command) (string, error) {
+	switch {
+	case cmd.Subscribe != nil:
+		return "subscribe", s.subscribe(c, cmd.Subscribe)
+	case cmd.Unsubscribe != nil:
+		return "unsubscribe", s.unsubscribe(c, cmd.Unsubscribe)
+	default:
+		return "unknown", errInvalidCommand
+	}
+}
+
+func (s *Server) subscribe(c *client, cmd *Subscribe) error {
+	if len(cmd.Chains) == 0 {
+		return errNoChains
+	}
+	if len(cmd.Addresses) > maxAddresses {
+		return errTooManyAddresses
+	}
+
+	sub := subscription{
+		accepted:     len(cmd.Events) == 0,
+		rejected:     len(cmd.Events) == 0,
+		includeBytes: cmd.IncludeBytes,
+		addresses:    ids.NewShortSet(len(cmd.Addresses)),
+	}
+	for _, event := range cmd.Events {
+		switch event {
+		case Accepted:
+			sub.accepted = true
+		case Rejected:
+			sub.rejected = true
+		default:
+			return fmt.Errorf("%w: %q", errUnknownEvent, event)
+		}
+	}
+	for _, addrStr := range cmd.Addresses {
+		_, _, addrBytes, err := formatting.ParseAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		sub.addresses.Add(addr)
+	}
+
+	chainIDs, err := s.lookup(cmd.Chains)
+	if err != nil {
+		return err
+	}
+	if sub.addresses.Len() > 0 {
+		s.lock.RLock()
+		for _, chainID := range chainIDs {
+			if s.chains[chainID] == nil {
+				s.lock.RUnlock()
+				return fmt.Errorf("%w: %s", errNoAddressSupport, chainID)
+			}
+		}
+		s.lock.RUnlock()
+	}
+
+	c.subscribe(chainIDs, sub)
+	return nil
+}
+
+func (s *Server) unsubscribe(c *client, cmd *Unsubscribe) error {
+	chainIDs, err := s.lookup(cmd.Chains)
+	if err != nil {
+		return err
+	}
+	c.unsubscribe(chainIDs)
+	return nil
+}
+
+// lookup returns the IDs of the registered chains [chains]
+func (s *Server) lookup(chains []string) ([]ids.ID, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	chainIDs := make([]ids.ID, len(chains))
+	for i, chain := range chains {
+		chainID, err := s.config.Chains.Lookup(chain)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", errUnknownChain, chain)
+		}
+		if _, ok := s.chains[chainID]; !ok {
+			return nil, fmt.Errorf("%w: %q", errUnknownChain, chain)
+		}
+		chainIDs[i] = chainID
+	}
+	return chainIDs, nil
+}
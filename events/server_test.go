@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+// testAddresser reports the first byte of a container as its only address
+type testAddresser struct {
+	common.TestVM
+}
+
+func (*testAddresser) Addresses(container []byte) ([][]byte, error) {
+	addr := ids.ShortID{container[0]}
+	return [][]byte{addr[:]}, nil
+}
+
+func registerTestChain(t *testing.T, s *Server, aliaser ids.Aliaser, alias string, vm common.VM) *snow.ConsensusContext {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	if err := aliaser.Alias(ctx.ChainID, alias); err != nil {
+		t.Fatal(err)
+	}
+	engine := &common.EngineTest{
+		T:        t,
+		ContextF: func() *snow.ConsensusContext { return ctx },
+		GetVMF:   func() common.VM { return vm },
+	}
+	s.RegisterChain(alias, engine)
+	return ctx
+}
+
+func TestServer(t *testing.T) {
+	assert := assert.New(t)
+
+	aliaser := ids.NewAliaser()
+	s := NewServer(Config{
+		Log:    logging.NoLog{},
+		Chains: aliaser,
+	})
+	xCtx := registerTestChain(t, s, aliaser, "X", &testAddresser{})
+	pCtx := registerTestChain(t, s, aliaser, "P", &common.TestVM{})
+
+	httpServer := httptest.NewServer(s)
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	assert.NoError(err)
+	defer conn.Close()
+	assert.NoError(conn.SetReadDeadline(time.Now().Add(10 * time.Second)))
+
+	addr, err := formatting.FormatAddress("X", "avax", ids.ShortID{1}.Bytes())
+	assert.NoError(err)
+
+	// Address filters aren't supported by chains whose VM isn't an Addresser
+	assert.NoError(conn.WriteJSON(&Command{Subscribe: &Subscribe{
+		Chains:    []string{"P"},
+		Addresses: []string{addr},
+	}}))
+	reply := Reply{}
+	assert.NoError(conn.ReadJSON(&reply))
+	assert.False(reply.Success)
+	assert.Contains(reply.Error, errNoAddressSupport.Error())
+
+	// Unknown chains can't be subscribed to
+	assert.NoError(conn.WriteJSON(&Command{Subscribe: &Subscribe{
+		Chains: []string{"C"},
+	}}))
+	reply = Reply{}
+	assert.NoError(conn.ReadJSON(&reply))
+	assert.False(reply.Success)
+
+	assert.NoError(conn.WriteJSON(&Command{Subscribe: &Subscribe{
+		Chains:       []string{"X"},
+		Events:       []string{Accepted},
+		IncludeBytes: true,
+		Addresses:    []string{addr},
+	}}))
+	reply = Reply{}
+	assert.NoError(conn.ReadJSON(&reply))
+	assert.True(reply.Success, reply.Error)
+	assert.Equal("subscribe", reply.Command)
+
+	// Only the accepted containers of [X] that reference [addr] are sent
+	assert.NoError(s.Accept(xCtx, ids.GenerateTestID(), []byte{2}))
+	assert.NoError(s.Reject(xCtx, ids.GenerateTestID(), []byte{1}))
+	assert.NoError(s.Accept(pCtx, ids.GenerateTestID(), []byte{1}))
+	containerID := ids.GenerateTestID()
+	assert.NoError(s.Accept(xCtx, containerID, []byte{1, 2, 3}))
+
+	decision := Decision{}
+	assert.NoError(conn.ReadJSON(&decision))
+	assert.Equal(xCtx.ChainID, decision.ChainID)
+	assert.Equal(Accepted, decision.Event)
+	assert.Equal(containerID, decision.ContainerID)
+	assert.Equal(formatting.Hex, decision.Encoding)
+	container, err := formatting.Decode(decision.Encoding, decision.Container)
+	assert.NoError(err)
+	assert.Equal([]byte{1, 2, 3}, container)
+
+	assert.NoError(conn.WriteJSON(&Command{Unsubscribe: &Unsubscribe{
+		Chains: []string{"X"},
+	}}))
+	reply = Reply{}
+	assert.NoError(conn.ReadJSON(&reply))
+	assert.True(reply.Success, reply.Error)
+	assert.Equal("unsubscribe", reply.Command)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/vms/avm"
+	"github.com/Toinounet21/avalanchego-mod/vms/blsfx"
 	"github.com/Toinounet21/avalanchego-mod/vms/evm"
 	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
 	"github.com/Toinounet21/avalanchego-mod/vms/platformvm"
@@ -65,5 +66,6 @@ func GetVMAliases() map[ids.ID][]string {
 		secp256k1fx.ID: {"secp256k1fx"},
 		nftfx.ID:       {"nftfx"},
 		propertyfx.ID:  {"propertyfx"},
+		blsfx.ID:       {"blsfx"},
 	}
 }
@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aliasdb
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+var _ Aliaser = &aliaser{}
+
+// Aliaser is an ids.Aliaser that can also persist aliases to a database, so
+// that aliases given at runtime survive restarts. Aliases given with Alias
+// aren't persisted, which lets built-in aliases be given on every start.
+type Aliaser interface {
+	ids.Aliaser
+
+	// PersistAlias gives [id] the alias [alias] and persists it
+	PersistAlias(id ids.ID, alias string) error
+
+	// PersistedAlias returns the ID that was given the persisted alias
+	// [alias], or database.ErrNotFound if [alias] isn't persisted
+	PersistedAlias(alias string) (ids.ID, error)
+
+	// PersistedAliases returns the persisted aliases, mapped to the IDs they
+	// were given to
+	PersistedAliases() (map[string]ids.ID, error)
+
+	// Restore gives the persisted aliases to their IDs. Aliases that clash
+	// with aliases given since they were persisted are skipped, and returned
+	// along with the reason they were skipped.
+	Restore() (map[string]error, error)
+}
+
+type aliaser struct {
+	ids.Aliaser
+
+	// lock keeps the database in sync with the in-memory aliases
+	lock sync.Mutex
+	db   database.Database
+}
+
+// New returns an Aliaser that gives aliases with [a] and persists them to
+// [db]. The keys of [db] are the aliases, and the values are the IDs they were
+// given to.
+func New(a ids.Aliaser, db database.Database) Aliaser {
+	return &aliaser{
+		Aliaser: a,
+		db:      db,
+	}
+}
+
+func (a *aliaser) PersistAlias(id ids.ID, alias string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.Aliaser.Alias(id, alias); err != nil {
+		return err
+	}
+	if err := a.db.Put([]byte(alias), id[:]); err != nil {
+		// Don't keep an alias that won't be restored
+		_ = a.Aliaser.RemoveAlias(alias)
+		return err
+	}
+	return nil
+}
+
+func (a *aliaser) PersistedAlias(alias string) (ids.ID, error) {
+	idBytes, err := a.db.Get([]byte(alias))
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(idBytes)
+}
+
+func (a *aliaser) PersistedAliases() (map[string]ids.ID, error) {
+	it := a.db.NewIterator()
+	defer it.Release()
+
+	aliases := make(map[string]ids.ID)
+	for it.Next() {
+		id, err := ids.ToID(it.Value())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse aliased ID: %w", err)
+		}
+		aliases[string(it.Key())] = id
+	}
+	return aliases, it.Error()
+}
+
+func (a *aliaser) Restore() (map[string]error, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	aliases, err := a.PersistedAliases()
+	if err != nil {
+		return nil, err
+	}
+	skipped := make(map[string]error)
+	for alias, id := range aliases {
+		if err := a.Aliaser.Alias(id, alias); err != nil {
+			skipped[alias] = err
+		}
+	}
+	return skipped, nil
+}
+
+// RemoveAlias removes [alias] from the ID it was given to. If [alias] was
+// persisted, it's removed from the database as well.
+func (a *aliaser) RemoveAlias(alias string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.Aliaser.RemoveAlias(alias); err != nil {
+		return err
+	}
+	return a.db.Delete([]byte(alias))
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aliasdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func TestPersistAlias(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	id1 := ids.GenerateTestID()
+	id2 := ids.GenerateTestID()
+
+	a := New(ids.NewAliaser(), db)
+	assert.NoError(a.Alias(id1, "builtin"))
+	assert.NoError(a.PersistAlias(id1, "persisted"))
+	assert.NoError(a.PersistAlias(id2, "removed"))
+	assert.Error(a.PersistAlias(id2, "persisted"))
+
+	assert.NoError(a.RemoveAlias("removed"))
+	_, err := a.Lookup("removed")
+	assert.Error(err)
+	_, err = a.PersistedAlias("removed")
+	assert.Equal(database.ErrNotFound, err)
+
+	persisted, err := a.PersistedAliases()
+	assert.NoError(err)
+	assert.Equal(map[string]ids.ID{"persisted": id1}, persisted)
+
+	// After a restart, only the persisted alias is restored
+	restarted := New(ids.NewAliaser(), db)
+	assert.NoError(restarted.Alias(id2, "builtin"))
+	skipped, err := restarted.Restore()
+	assert.NoError(err)
+	assert.Empty(skipped)
+
+	id, err := restarted.Lookup("persisted")
+	assert.NoError(err)
+	assert.Equal(id1, id)
+	id, err = restarted.Lookup("builtin")
+	assert.NoError(err)
+	assert.Equal(id2, id)
+}
+
+func TestRestoreClash(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	id1 := ids.GenerateTestID()
+	id2 := ids.GenerateTestID()
+
+	a := New(ids.NewAliaser(), db)
+	assert.NoError(a.PersistAlias(id1, "alias"))
+
+	// The alias was given to another ID before the persisted one is restored
+	restarted := New(ids.NewAliaser(), db)
+	assert.NoError(restarted.Alias(id2, "alias"))
+	skipped, err := restarted.Restore()
+	assert.NoError(err)
+	assert.Contains(skipped, "alias")
+
+	id, err := restarted.Lookup("alias")
+	assert.NoError(err)
+	assert.Equal(id2, id)
+}
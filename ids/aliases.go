@@ -19,6 +19,7 @@ type AliaserReader interface {
 // aliases; two IDs may not have the same alias.
 type AliaserWriter interface {
 	Alias(id ID, alias string) error
+	RemoveAlias(alias string) error
 	RemoveAliases(id ID)
 }
 
@@ -87,6 +88,32 @@ func (a *aliaser) Alias(id ID, alias string) error {
 	return nil
 }
 
+// RemoveAlias removes [alias] from the ID it was given to
+func (a *aliaser) RemoveAlias(alias string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	id, exists := a.dealias[alias]
+	if !exists {
+		return fmt.Errorf("there is no ID with alias %s", alias)
+	}
+	delete(a.dealias, alias)
+
+	oldAliases := a.aliases[id]
+	aliases := make([]string, 0, len(oldAliases)-1)
+	for _, idAlias := range oldAliases {
+		if idAlias != alias {
+			aliases = append(aliases, idAlias)
+		}
+	}
+	if len(aliases) == 0 {
+		delete(a.aliases, id)
+	} else {
+		a.aliases[id] = aliases
+	}
+	return nil
+}
+
 // RemoveAliases of the provided ID
 func (a *aliaser) RemoveAliases(id ID) {
 	a.lock.Lock()
@@ -0,0 +1,216 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// Universe assigns each ID added to it a dense index, starting from 0, so that
+// sets of these IDs can be stored as bitsets. A universe is meant to be shared
+// by many short-lived DenseSets, such as the sets built while processing a
+// single poll.
+type Universe struct {
+	ids     []ID
+	indices map[ID]int
+}
+
+// NewUniverse returns a new empty universe with initial capacity [size].
+func NewUniverse(size int) *Universe {
+	if size < 0 {
+		size = 0
+	}
+	return &Universe{
+		ids:     make([]ID, 0, size),
+		indices: make(map[ID]int, size),
+	}
+}
+
+// Add [id] to the universe if it isn't already in it, and return its index
+func (u *Universe) Add(id ID) int {
+	if index, ok := u.indices[id]; ok {
+		return index
+	}
+	if u.indices == nil {
+		u.indices = make(map[ID]int, minSetSize)
+	}
+	index := len(u.ids)
+	u.ids = append(u.ids, id)
+	u.indices[id] = index
+	return index
+}
+
+// Index returns the index of [id], and false if [id] isn't in the universe
+func (u *Universe) Index(id ID) (int, bool) {
+	index, ok := u.indices[id]
+	return index, ok
+}
+
+// ID returns the ID with index [index]
+func (u *Universe) ID(index int) ID { return u.ids[index] }
+
+// Len returns the number of IDs in the universe
+func (u *Universe) Len() int { return len(u.ids) }
+
+// Clear removes all the IDs from the universe. Any DenseSet drawn from the
+// universe must be cleared as well before it's used again.
+func (u *Universe) Clear() {
+	u.ids = u.ids[:0]
+	for id := range u.indices {
+		delete(u.indices, id)
+	}
+}
+
+// DenseSet is a set of IDs drawn from a Universe. Membership is stored as a
+// bitset over the indices of the universe, so adding, removing, and checking
+// for an ID doesn't hash into a map of its own, and a set over n IDs only
+// takes n/8 bytes. The zero value can't be used; use NewDenseSet.
+type DenseSet struct {
+	universe *Universe
+	bits     []uint64
+}
+
+// NewDenseSet returns a new empty set of IDs drawn from [universe]
+func NewDenseSet(universe *Universe) DenseSet {
+	return DenseSet{
+		universe: universe,
+		bits:     make([]uint64, (universe.Len()+63)/64),
+	}
+}
+
+// NewDenseSetFromSet returns a set containing the IDs in [set]. IDs not in
+// [universe] are added to it.
+func NewDenseSetFromSet(universe *Universe, set Set) DenseSet {
+	s := NewDenseSet(universe)
+	for id := range set {
+		s.Add(id)
+	}
+	return s
+}
+
+// Universe returns the universe this set's IDs are drawn from
+func (s *DenseSet) Universe() *Universe { return s.universe }
+
+func (s *DenseSet) addIndex(index int) {
+	word := index / 64
+	if word >= len(s.bits) {
+		// Grow to cover the whole universe, so that adding the IDs added to the
+		// universe since this set was created only allocates once
+		newBits := make([]uint64, (s.universe.Len()+63)/64)
+		copy(newBits, s.bits)
+		s.bits = newBits
+	}
+	s.bits[word] |= 1 << uint(index%64)
+}
+
+// Add all the ids to this set. IDs not in the universe are added to it.
+func (s *DenseSet) Add(idList ...ID) {
+	for _, id := range idList {
+		s.addIndex(s.universe.Add(id))
+	}
+}
+
+// Remove all the ids from this set
+func (s *DenseSet) Remove(idList ...ID) {
+	for _, id := range idList {
+		index, ok := s.universe.Index(id)
+		if !ok {
+			continue
+		}
+		if word := index / 64; word < len(s.bits) {
+			s.bits[word] &^= 1 << uint(index%64)
+		}
+	}
+}
+
+// Contains returns true if the set contains [id]
+func (s *DenseSet) Contains(id ID) bool {
+	index, ok := s.universe.Index(id)
+	if !ok {
+		return false
+	}
+	word := index / 64
+	return word < len(s.bits) && s.bits[word]&(1<<uint(index%64)) != 0
+}
+
+// Union adds all the ids in [set] to this set. [set] must be drawn from the
+// same universe as this set.
+func (s *DenseSet) Union(set DenseSet) {
+	if len(set.bits) > len(s.bits) {
+		newBits := make([]uint64, len(set.bits))
+		copy(newBits, s.bits)
+		s.bits = newBits
+	}
+	for i, word := range set.bits {
+		s.bits[i] |= word
+	}
+}
+
+// Difference removes all the ids in [set] from this set. [set] must be drawn
+// from the same universe as this set.
+func (s *DenseSet) Difference(set DenseSet) {
+	for i := 0; i < len(s.bits) && i < len(set.bits); i++ {
+		s.bits[i] &^= set.bits[i]
+	}
+}
+
+// Len returns the number of ids in this set
+func (s *DenseSet) Len() int {
+	size := 0
+	for _, word := range s.bits {
+		size += bits.OnesCount64(word)
+	}
+	return size
+}
+
+// Clear empties this set
+func (s *DenseSet) Clear() {
+	for i := range s.bits {
+		s.bits[i] = 0
+	}
+}
+
+// List returns the ids in this set, in the order of their indices in the
+// universe
+func (s *DenseSet) List() []ID {
+	idList := make([]ID, 0, s.Len())
+	s.ForEach(func(id ID) { idList = append(idList, id) })
+	return idList
+}
+
+// Set converts this set into a Set
+func (s *DenseSet) Set() Set {
+	set := NewSet(s.Len())
+	s.ForEach(func(id ID) { set[id] = struct{}{} })
+	return set
+}
+
+// ForEach calls [f] with each id in this set, in the order of their indices in
+// the universe. Unlike List, it doesn't allocate.
+func (s *DenseSet) ForEach(f func(ID)) {
+	for i, word := range s.bits {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			f(s.universe.ID(i*64 + bit))
+			word &^= 1 << uint(bit)
+		}
+	}
+}
+
+// String returns the string representation of this set
+func (s *DenseSet) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("{")
+	first := true
+	s.ForEach(func(id ID) {
+		if !first {
+			sb.WriteString(", ")
+		}
+		first = false
+		sb.WriteString(id.String())
+	})
+	sb.WriteString("}")
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import "testing"
+
+func TestDenseSet(t *testing.T) {
+	id1 := ID{1}
+	id2 := ID{2}
+	id3 := ID{3}
+
+	universe := NewUniverse(0)
+	s := NewDenseSet(universe)
+	if s.Len() != 0 {
+		t.Fatalf("Empty set's len should be 0")
+	}
+	if s.Contains(id1) {
+		t.Fatalf("Empty set shouldn't contain an element")
+	}
+
+	s.Add(id1, id2)
+	switch {
+	case s.Len() != 2:
+		t.Fatalf("Wrong set length")
+	case !s.Contains(id1) || !s.Contains(id2):
+		t.Fatalf("Set should contain element")
+	case universe.Len() != 2:
+		t.Fatalf("Added IDs should be added to the universe")
+	}
+
+	s.Remove(id1, id3)
+	switch {
+	case s.Len() != 1:
+		t.Fatalf("Wrong set length")
+	case s.Contains(id1):
+		t.Fatalf("Set shouldn't contain removed element")
+	case !s.Contains(id2):
+		t.Fatalf("Set should contain element")
+	}
+
+	s.Clear()
+	if s.Len() != 0 {
+		t.Fatalf("Cleared set's len should be 0")
+	}
+}
+
+func TestDenseSetGrowsWithUniverse(t *testing.T) {
+	universe := NewUniverse(0)
+	s1 := NewDenseSet(universe)
+	s2 := NewDenseSet(universe)
+
+	for i := 0; i < 200; i++ {
+		id := ID{byte(i), byte(i >> 8)}
+		if i%2 == 0 {
+			s1.Add(id)
+		} else {
+			s2.Add(id)
+		}
+	}
+	if s1.Len() != 100 || s2.Len() != 100 {
+		t.Fatalf("Wrong set lengths %d and %d", s1.Len(), s2.Len())
+	}
+
+	s1.Union(s2)
+	if s1.Len() != 200 {
+		t.Fatalf("Wrong union length %d", s1.Len())
+	}
+
+	s1.Difference(s2)
+	if s1.Len() != 100 {
+		t.Fatalf("Wrong difference length %d", s1.Len())
+	}
+	for _, id := range s1.List() {
+		if s2.Contains(id) {
+			t.Fatalf("Difference shouldn't contain %s", id)
+		}
+	}
+}
+
+func TestDenseSetConversion(t *testing.T) {
+	set := Set{}
+	set.Add(ID{1}, ID{2}, ID{3})
+
+	universe := NewUniverse(set.Len())
+	universe.Add(ID{4})
+	s := NewDenseSetFromSet(universe, set)
+	if s.Len() != 3 {
+		t.Fatalf("Wrong set length")
+	}
+	if s.Contains(ID{4}) {
+		t.Fatalf("Set shouldn't contain an ID that is only in the universe")
+	}
+	if converted := s.Set(); !converted.Equals(set) {
+		t.Fatalf("Expected %s, got %s", set, converted)
+	}
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/avalanche"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman"
-	"github.com/Toinounet21/avalanchego-mod/snow/triggers"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/gorilla/rpc/v2"
 )
@@ -60,9 +59,12 @@ type Config struct {
 	Log                                     logging.Logger
 	IndexingEnabled                         bool
 	AllowIncompleteIndex                    bool
-	DecisionDispatcher, ConsensusDispatcher *triggers.EventDispatcher
+	DecisionDispatcher, ConsensusDispatcher *common.EventBus
 	APIServer                               server.RouteAdder
 	ShutdownF                               func()
+	// If non-empty, only the chains whose ID or alias is in IndexedChains
+	// are indexed
+	IndexedChains []string
 }
 
 // Indexer causes accepted containers for a given chain
@@ -83,6 +85,7 @@ func NewIndexer(config Config) (Indexer, error) {
 		db:                   config.DB,
 		allowIncompleteIndex: config.AllowIncompleteIndex,
 		indexingEnabled:      config.IndexingEnabled,
+		indexedChains:        make(map[string]bool, len(config.IndexedChains)),
 		consensusDispatcher:  config.ConsensusDispatcher,
 		decisionDispatcher:   config.DecisionDispatcher,
 		txIndices:            map[ids.ID]Index{},
@@ -91,6 +94,9 @@ func NewIndexer(config Config) (Indexer, error) {
 		routeAdder:           config.APIServer,
 		shutdownF:            config.ShutdownF,
 	}
+	for _, chain := range config.IndexedChains {
+		indexer.indexedChains[chain] = true
+	}
 	if err := indexer.codec.RegisterCodec(
 		codecVersion,
 		linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32),
@@ -130,6 +136,10 @@ type indexer struct {
 	// If false, don't create index for a chain when RegisterChain is called
 	indexingEnabled bool
 
+	// If non-empty, only create an index for a chain if its ID or the name it
+	// is registered with is in [indexedChains]
+	indexedChains map[string]bool
+
 	// Chain ID --> index of blocks of that chain (if applicable)
 	blockIndices map[ids.ID]Index
 	// Chain ID --> index of vertices of that chain (if applicable)
@@ -138,9 +148,9 @@ type indexer struct {
 	txIndices map[ids.ID]Index
 
 	// Notifies of newly accepted blocks and vertices
-	consensusDispatcher *triggers.EventDispatcher
+	consensusDispatcher *common.EventBus
 	// Notifies of newly accepted transactions
-	decisionDispatcher *triggers.EventDispatcher
+	decisionDispatcher *common.EventBus
 }
 
 // Assumes [engine]'s context lock is not held
@@ -183,7 +193,7 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		return
 	}
 
-	if !i.indexingEnabled { // Indexing is disabled
+	if !i.shouldIndex(name, chainID) { // Indexing is disabled for this chain
 		if previouslyIndexed && !i.allowIncompleteIndex {
 			// We indexed this chain in a previous run but not in this run.
 			// This would create an incomplete index, which is not allowed, so exit.
@@ -263,11 +273,20 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 	}
 }
 
+// shouldIndex returns true if the chain [chainID], registered as [name], should
+// be indexed
+func (i *indexer) shouldIndex(name string, chainID ids.ID) bool {
+	if !i.indexingEnabled {
+		return false
+	}
+	return len(i.indexedChains) == 0 || i.indexedChains[name] || i.indexedChains[chainID.String()]
+}
+
 func (i *indexer) registerChainHelper(
 	chainID ids.ID,
 	prefixEnd byte,
 	name, endpoint string,
-	dispatcher *triggers.EventDispatcher,
+	dispatcher *common.EventBus,
 ) (Index, error) {
 	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
 	copy(prefix, chainID[:])
@@ -280,7 +299,7 @@ func (i *indexer) registerChainHelper(
 	}
 
 	// Register index to learn about new accepted vertices
-	if err := dispatcher.RegisterChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID), index, true); err != nil {
+	if err := dispatcher.SubscribeChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID), index, true); err != nil {
 		_ = index.Close()
 		return nil, err
 	}
@@ -299,6 +318,15 @@ func (i *indexer) registerChainHelper(
 		_ = index.Close()
 		return nil, err
 	}
+
+	// Serve the index over plain GET requests as well
+	for restEndpoint, restHandler := range newRESTHandlers(index) {
+		handler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: restHandler}
+		if err := i.routeAdder.AddRoute(handler, &sync.RWMutex{}, "index/"+name, "/"+endpoint+restEndpoint, i.log); err != nil {
+			_ = index.Close()
+			return nil, err
+		}
+	}
 	return index, nil
 }
 
@@ -323,19 +351,19 @@ func (i *indexer) close() error {
 	for chainID, txIndex := range i.txIndices {
 		errs.Add(
 			txIndex.Close(),
-			i.decisionDispatcher.DeregisterChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID)),
+			i.decisionDispatcher.UnsubscribeChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID)),
 		)
 	}
 	for chainID, vtxIndex := range i.vtxIndices {
 		errs.Add(
 			vtxIndex.Close(),
-			i.consensusDispatcher.DeregisterChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID)),
+			i.consensusDispatcher.UnsubscribeChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID)),
 		)
 	}
 	for chainID, blockIndex := range i.blockIndices {
 		errs.Add(
 			blockIndex.Close(),
-			i.consensusDispatcher.DeregisterChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID)),
+			i.consensusDispatcher.UnsubscribeChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID)),
 		)
 	}
 	errs.Add(i.db.Close())
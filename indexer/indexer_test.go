@@ -21,7 +21,6 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowstorm"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/avalanche/mocks"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
-	"github.com/Toinounet21/avalanchego-mod/snow/triggers"
 	"github.com/Toinounet21/avalanchego-mod/utils"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 
@@ -46,8 +45,7 @@ func (a *apiServerMock) AddRoute(_ *common.HTTPHandler, _ *sync.RWMutex, base, e
 // Test that newIndexer sets fields correctly
 func TestNewIndexer(t *testing.T) {
 	assert := assert.New(t)
-	ed := &triggers.EventDispatcher{}
-	ed.Initialize(logging.NoLog{})
+	ed := common.NewEventBus(logging.NoLog{})
 	config := Config{
 		IndexingEnabled:      true,
 		AllowIncompleteIndex: true,
@@ -85,10 +83,8 @@ func TestNewIndexer(t *testing.T) {
 // Test that [hasRunBefore] is set correctly and that Shutdown is called on close
 func TestMarkHasRunAndShutdown(t *testing.T) {
 	assert := assert.New(t)
-	cd := &triggers.EventDispatcher{}
-	cd.Initialize(logging.NoLog{})
-	dd := &triggers.EventDispatcher{}
-	dd.Initialize(logging.NoLog{})
+	cd := common.NewEventBus(logging.NoLog{})
+	dd := common.NewEventBus(logging.NoLog{})
 	baseDB := memdb.New()
 	db := versiondb.New(baseDB)
 	shutdown := &sync.WaitGroup{}
@@ -125,10 +121,8 @@ func TestMarkHasRunAndShutdown(t *testing.T) {
 // some vertices
 func TestIndexer(t *testing.T) {
 	assert := assert.New(t)
-	cd := &triggers.EventDispatcher{}
-	cd.Initialize(logging.NoLog{})
-	dd := &triggers.EventDispatcher{}
-	dd.Initialize(logging.NoLog{})
+	cd := common.NewEventBus(logging.NoLog{})
+	dd := common.NewEventBus(logging.NoLog{})
 	baseDB := memdb.New()
 	db := versiondb.New(baseDB)
 	config := Config{
@@ -174,9 +168,12 @@ func TestIndexer(t *testing.T) {
 	assert.NoError(err)
 	assert.True(previouslyIndexed)
 	server := config.APIServer.(*apiServerMock)
-	assert.EqualValues(1, server.timesCalled)
+	assert.EqualValues(4, server.timesCalled) // JSON-RPC endpoint and REST endpoints
 	assert.EqualValues("index/chain1", server.bases[0])
 	assert.EqualValues("/block", server.endpoints[0])
+	assert.Contains(server.endpoints, "/block/lastAccepted")
+	assert.Contains(server.endpoints, "/block/container")
+	assert.Contains(server.endpoints, "/block/containers")
 	assert.Len(idxr.blockIndices, 1)
 	assert.Len(idxr.txIndices, 0)
 	assert.Len(idxr.vtxIndices, 0)
@@ -284,7 +281,7 @@ func TestIndexer(t *testing.T) {
 	idxr.RegisterChain("chain2", dagEngine)
 	assert.NoError(err)
 	server = config.APIServer.(*apiServerMock)
-	assert.EqualValues(3, server.timesCalled) // block index, vtx index, tx index
+	assert.EqualValues(12, server.timesCalled) // block index, vtx index, tx index
 	assert.Contains(server.bases, "index/chain2")
 	assert.Contains(server.endpoints, "/vtx")
 	assert.Contains(server.endpoints, "/tx")
@@ -431,10 +428,8 @@ func TestIndexer(t *testing.T) {
 func TestIncompleteIndex(t *testing.T) {
 	// Create an indexer with indexing disabled
 	assert := assert.New(t)
-	cd := &triggers.EventDispatcher{}
-	cd.Initialize(logging.NoLog{})
-	dd := &triggers.EventDispatcher{}
-	dd.Initialize(logging.NoLog{})
+	cd := common.NewEventBus(logging.NoLog{})
+	dd := common.NewEventBus(logging.NoLog{})
 	baseDB := memdb.New()
 	config := Config{
 		IndexingEnabled:      false,
@@ -515,10 +510,8 @@ func TestIncompleteIndex(t *testing.T) {
 // Ensure we only index chains in the primary network
 func TestIgnoreNonDefaultChains(t *testing.T) {
 	assert := assert.New(t)
-	cd := &triggers.EventDispatcher{}
-	cd.Initialize(logging.NoLog{})
-	dd := &triggers.EventDispatcher{}
-	dd.Initialize(logging.NoLog{})
+	cd := common.NewEventBus(logging.NoLog{})
+	dd := common.NewEventBus(logging.NoLog{})
 	baseDB := memdb.New()
 	db := versiondb.New(baseDB)
 	config := Config{
@@ -551,3 +544,21 @@ func TestIgnoreNonDefaultChains(t *testing.T) {
 	idxr.RegisterChain("chain1", chainEngine)
 	assert.Len(idxr.blockIndices, 0)
 }
+
+func TestShouldIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	idxr := &indexer{indexingEnabled: true}
+	assert.True(idxr.shouldIndex("X", chainID))
+
+	idxr.indexedChains = map[string]bool{"X": true}
+	assert.True(idxr.shouldIndex("X", chainID))
+	assert.False(idxr.shouldIndex("P", ids.GenerateTestID()))
+
+	idxr.indexedChains = map[string]bool{chainID.String(): true}
+	assert.True(idxr.shouldIndex("X", chainID))
+
+	idxr.indexingEnabled = false
+	assert.False(idxr.shouldIndex("X", chainID))
+}
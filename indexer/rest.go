@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	stdjson "encoding/json"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// Endpoints, relative to the JSON-RPC endpoint of an index, that serve the
+// index over plain GET requests
+const (
+	lastAcceptedEndpoint = "/lastAccepted"
+	containerEndpoint    = "/container"
+	containersEndpoint   = "/containers"
+)
+
+var errNoContainer = errors.New("need to specify either index or id")
+
+// newRESTHandlers returns the handlers that serve [index] over plain GET
+// requests, by endpoint:
+//
+//	/lastAccepted                              the last accepted container
+//	/container?index=<index> or ?id=<id>       a single container
+//	/containers?startIndex=<i>&numToFetch=<n>  a range of containers
+//
+// Each endpoint accepts an "encoding" parameter for the container bytes.
+func newRESTHandlers(index Index) map[string]http.Handler {
+	s := &service{Index: index}
+	return map[string]http.Handler{
+		lastAcceptedEndpoint: restHandler(func(r *http.Request) (interface{}, error) {
+			args := &GetLastAcceptedArgs{}
+			if err := parseEncoding(r, &args.Encoding); err != nil {
+				return nil, err
+			}
+			reply := &FormattedContainer{}
+			return reply, s.GetLastAccepted(r, args, reply)
+		}),
+		containerEndpoint: restHandler(func(r *http.Request) (interface{}, error) {
+			var encoding formatting.Encoding
+			if err := parseEncoding(r, &encoding); err != nil {
+				return nil, err
+			}
+
+			query := r.URL.Query()
+			reply := &FormattedContainer{}
+			switch {
+			case query.Get("id") != "":
+				containerID, err := ids.FromString(query.Get("id"))
+				if err != nil {
+					return nil, fmt.Errorf("couldn't parse id: %w", err)
+				}
+				return reply, s.GetContainerByID(r, &GetIndexArgs{
+					ContainerID: containerID,
+					Encoding:    encoding,
+				}, reply)
+			case query.Get("index") != "":
+				containerIndex, err := parseUint64(r, "index")
+				if err != nil {
+					return nil, err
+				}
+				return reply, s.GetContainerByIndex(r, &GetContainer{
+					Index:    json.Uint64(containerIndex),
+					Encoding: encoding,
+				}, reply)
+			default:
+				return nil, errNoContainer
+			}
+		}),
+		containersEndpoint: restHandler(func(r *http.Request) (interface{}, error) {
+			args := &GetContainerRangeArgs{}
+			if err := parseEncoding(r, &args.Encoding); err != nil {
+				return nil, err
+			}
+			startIndex, err := parseUint64(r, "startIndex")
+			if err != nil {
+				return nil, err
+			}
+			numToFetch, err := parseUint64(r, "numToFetch")
+			if err != nil {
+				return nil, err
+			}
+			args.StartIndex = json.Uint64(startIndex)
+			args.NumToFetch = json.Uint64(numToFetch)
+			reply := &GetContainerRangeResponse{}
+			return reply, s.GetContainerRange(r, args, reply)
+		}),
+	}
+}
+
+// restHandler serves the reply of [f] as JSON to GET requests
+func restHandler(f func(*http.Request) (interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		reply, err := f(r)
+		switch {
+		case errors.Is(err, database.ErrNotFound) || errors.Is(err, errNoneAccepted):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = stdjson.NewEncoder(w).Encode(reply)
+	})
+}
+
+// parseEncoding sets [encoding] to the "encoding" parameter of [r], if it's
+// set
+func parseEncoding(r *http.Request, encoding *formatting.Encoding) error {
+	encodingStr := r.URL.Query().Get("encoding")
+	if encodingStr == "" {
+		return nil
+	}
+	return encoding.UnmarshalJSON([]byte(strconv.Quote(encodingStr)))
+}
+
+func parseUint64(r *http.Request, name string) (uint64, error) {
+	value, err := strconv.ParseUint(r.URL.Query().Get(name), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s: %w", name, err)
+	}
+	return value, nil
+}
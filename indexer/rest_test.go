@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/codec"
+	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/database/versiondb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+func TestRESTHandlers(t *testing.T) {
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	assert.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	idx, err := newIndex(versiondb.New(memdb.New()), logging.NoLog{}, codec, mockable.Clock{})
+	assert.NoError(err)
+
+	handlers := newRESTHandlers(idx)
+	get := func(endpoint, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handlers[endpoint].ServeHTTP(w, httptest.NewRequest(http.MethodGet, endpoint+query, nil))
+		return w
+	}
+
+	// Nothing has been accepted yet
+	assert.Equal(http.StatusNotFound, get(lastAcceptedEndpoint, "").Code)
+
+	ctx := snow.DefaultConsensusContextTest()
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	for i, containerID := range containerIDs {
+		assert.NoError(idx.Accept(ctx, containerID, []byte{byte(i)}))
+	}
+
+	w := get(lastAcceptedEndpoint, "?encoding=cb58")
+	assert.Equal(http.StatusOK, w.Code)
+	container := FormattedContainer{}
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &container))
+	assert.Equal(containerIDs[1], container.ID)
+	assert.EqualValues(1, container.Index)
+	assert.Equal(formatting.CB58, container.Encoding)
+
+	w = get(containerEndpoint, "?index=0")
+	assert.Equal(http.StatusOK, w.Code)
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &container))
+	assert.Equal(containerIDs[0], container.ID)
+
+	w = get(containerEndpoint, fmt.Sprintf("?id=%s", containerIDs[1]))
+	assert.Equal(http.StatusOK, w.Code)
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &container))
+	assert.EqualValues(1, container.Index)
+
+	assert.Equal(http.StatusNotFound, get(containerEndpoint, fmt.Sprintf("?id=%s", ids.GenerateTestID())).Code)
+	assert.Equal(http.StatusBadRequest, get(containerEndpoint, "").Code)
+	assert.Equal(http.StatusBadRequest, get(containerEndpoint, "?index=0&encoding=unknown").Code)
+
+	w = get(containersEndpoint, "?startIndex=0&numToFetch=10")
+	assert.Equal(http.StatusOK, w.Code)
+	containers := GetContainerRangeResponse{}
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &containers))
+	assert.Len(containers.Containers, 2)
+
+	w = httptest.NewRecorder()
+	handlers[containersEndpoint].ServeHTTP(w, httptest.NewRequest(http.MethodPost, containersEndpoint, nil))
+	assert.Equal(http.StatusMethodNotAllowed, w.Code)
+}
@@ -8,7 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
-	"github.com/Toinounet21/avalanchego-mod/snow/triggers"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 )
@@ -32,13 +32,13 @@ type context struct {
 type ChainIPCs struct {
 	context
 	chains          map[ids.ID]*EventSockets
-	consensusEvents *triggers.EventDispatcher
-	decisionEvents  *triggers.EventDispatcher
+	consensusEvents *common.EventBus
+	decisionEvents  *common.EventBus
 }
 
 // NewChainIPCs creates a new *ChainIPCs that writes consensus and decision
 // events to IPC sockets
-func NewChainIPCs(log logging.Logger, path string, networkID uint32, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher, defaultChainIDs []ids.ID) (*ChainIPCs, error) {
+func NewChainIPCs(log logging.Logger, path string, networkID uint32, consensusEvents *common.EventBus, decisionEvents *common.EventBus, defaultChainIDs []ids.ID) (*ChainIPCs, error) {
 	cipcs := &ChainIPCs{
 		context: context{
 			log:       log,
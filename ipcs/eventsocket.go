@@ -11,7 +11,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/ipcs/socket"
 	"github.com/Toinounet21/avalanchego-mod/snow"
-	"github.com/Toinounet21/avalanchego-mod/snow/triggers"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 )
@@ -25,7 +25,7 @@ type EventSockets struct {
 }
 
 // newEventSockets creates a *ChainIPCs with both consensus and decisions IPCs
-func newEventSockets(ctx context, chainID ids.ID, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher) (*EventSockets, error) {
+func newEventSockets(ctx context, chainID ids.ID, consensusEvents *common.EventBus, decisionEvents *common.EventBus) (*EventSockets, error) {
 	consensusIPC, err := newEventIPCSocket(ctx, chainID, ipcConsensusIdentifier, consensusEvents)
 	if err != nil {
 		return nil, err
@@ -94,7 +94,7 @@ type eventSocket struct {
 
 // newEventIPCSocket creates a *eventSocket for the given chain and
 // EventDispatcher that writes to a local IPC socket
-func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *triggers.EventDispatcher) (*eventSocket, error) {
+func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *common.EventBus) (*eventSocket, error) {
 	var (
 		url     = ipcURL(ctx, chainID, name)
 		ipcName = ipcIdentifierPrefix + "-" + name
@@ -110,7 +110,7 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *trigger
 		url:    url,
 		socket: socket.NewSocket(url, ctx.log),
 		unregisterFn: func() error {
-			return events.DeregisterChain(chainID, ipcName)
+			return events.UnsubscribeChain(chainID, ipcName)
 		},
 	}
 
@@ -121,7 +121,7 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *trigger
 		return nil, err
 	}
 
-	if err := events.RegisterChain(chainID, ipcName, eis, false); err != nil {
+	if err := events.SubscribeChain(chainID, ipcName, eis, false); err != nil {
 		if err := eis.stop(); err != nil {
 			return nil, err
 		}
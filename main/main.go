@@ -4,11 +4,13 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/Toinounet21/avalanchego-mod/app/runner"
 	"github.com/Toinounet21/avalanchego-mod/config"
+	"github.com/Toinounet21/avalanchego-mod/node"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
@@ -37,5 +39,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	if runnerConfig.ValidateConfigAndExit {
+		configJSON, err := json.MarshalIndent(&nodeConfig, "", "  ")
+		if err != nil {
+			fmt.Printf("couldn't marshal node config: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(configJSON))
+		os.Exit(0)
+	}
+
+	// The config is reloaded from the same flags, config file and environment
+	nodeConfig.LoadConfig = func() (node.Config, error) {
+		v, err := config.BuildViper(config.BuildFlagSet(), os.Args[1:])
+		if err != nil {
+			return node.Config{}, err
+		}
+		return config.GetNodeConfig(v, runnerConfig.BuildDir)
+	}
+
 	runner.Run(runnerConfig, nodeConfig)
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/units"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
@@ -32,7 +33,7 @@ func init() {
 		panic(err)
 	}
 	TestCodec = codec
-	UncompressingBuilder = NewOutboundBuilder(codec, false /*compress*/)
+	UncompressingBuilder = NewOutboundBuilder(codec, compression.TypeNone)
 }
 
 func TestBuildGetVersion(t *testing.T) {
@@ -101,6 +102,80 @@ func TestBuildGetPeerList(t *testing.T) {
 	assert.Equal(t, GetPeerList, parsedMsg.Op())
 }
 
+func TestBuildIPv6(t *testing.T) {
+	ip := utils.IPDesc{
+		IP:   net.ParseIP("2001:db8::1"),
+		Port: 9651,
+	}
+	myVersionTime := uint64(time.Now().Unix())
+	sig := make([]byte, 65)
+	msg, err := UncompressingBuilder.IPv6(ip, myVersionTime, sig)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, IPv6, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, IPv6, parsedMsg.Op())
+	assert.True(t, ip.Equal(parsedMsg.Get(IP).(utils.IPDesc)))
+	assert.EqualValues(t, myVersionTime, parsedMsg.Get(VersionTime))
+	assert.EqualValues(t, sig, parsedMsg.Get(SigBytes))
+}
+
+func TestBuildSignedIP(t *testing.T) {
+	ip := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 9651,
+	}
+	myVersionTime := uint64(time.Now().Unix())
+	sig := make([]byte, 65)
+	msg, err := UncompressingBuilder.SignedIP(ip, myVersionTime, sig)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, SignedIP, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, SignedIP, parsedMsg.Op())
+	assert.True(t, ip.Equal(parsedMsg.Get(IP).(utils.IPDesc)))
+	assert.EqualValues(t, myVersionTime, parsedMsg.Get(VersionTime))
+	assert.EqualValues(t, sig, parsedMsg.Get(SigBytes))
+}
+
+func TestBuildFeatures(t *testing.T) {
+	supportedFeatures := uint64(0b101)
+	msg, err := UncompressingBuilder.Features(supportedFeatures)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Features, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Features, parsedMsg.Op())
+	assert.EqualValues(t, supportedFeatures, parsedMsg.Get(SupportedFeatures))
+}
+
+func TestBuildSubnets(t *testing.T) {
+	trackedSubnets := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	msg, err := UncompressingBuilder.Subnets(trackedSubnets)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Subnets, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Subnets, parsedMsg.Op())
+	subnetIDsBytes := parsedMsg.Get(TrackedSubnets).([][]byte)
+	assert.Len(t, subnetIDsBytes, len(trackedSubnets))
+	for i, subnetID := range trackedSubnets {
+		assert.Equal(t, subnetID[:], subnetIDsBytes[i])
+	}
+}
+
 func TestBuildGetAcceptedFrontier(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
@@ -209,8 +284,8 @@ func TestBuildPut(t *testing.T) {
 	containerID := ids.Empty.Prefix(1)
 	container := []byte{2}
 
-	for _, compress := range []bool{false, true} {
-		builder := NewOutboundBuilder(TestCodec, compress)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip, compression.TypeZstd} {
+		builder := NewOutboundBuilder(TestCodec, compressionType)
 		msg, err := builder.Put(chainID, requestID, containerID, container)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
@@ -234,8 +309,8 @@ func TestBuildPushQuery(t *testing.T) {
 	containerID := ids.Empty.Prefix(1)
 	container := []byte{2}
 
-	for _, compress := range []bool{false, true} {
-		builder := NewOutboundBuilder(TestCodec, compress)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip, compression.TypeZstd} {
+		builder := NewOutboundBuilder(TestCodec, compressionType)
 		msg, err := builder.PushQuery(chainID, requestID, time.Duration(deadline), containerID, container)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
@@ -301,8 +376,8 @@ func TestBuildAncestors(t *testing.T) {
 	container2 := ids.Empty.Prefix(2)
 	containers := [][]byte{container[:], container2[:]}
 
-	for _, compress := range []bool{false, true} {
-		builder := NewOutboundBuilder(TestCodec, compress)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip, compression.TypeZstd} {
+		builder := NewOutboundBuilder(TestCodec, compressionType)
 		msg, err := builder.Ancestors(chainID, requestID, containers)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
@@ -325,8 +400,8 @@ func TestBuildAppRequestMsg(t *testing.T) {
 	appRequestBytes[len(appRequestBytes)-1] = 1
 	deadline := uint64(time.Now().Unix())
 
-	for _, compress := range []bool{false, true} {
-		builder := NewOutboundBuilder(TestCodec, compress)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip, compression.TypeZstd} {
+		builder := NewOutboundBuilder(TestCodec, compressionType)
 		msg, err := builder.AppRequest(chainID, 1, time.Duration(deadline), appRequestBytes)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
@@ -345,8 +420,8 @@ func TestBuildAppResponseMsg(t *testing.T) {
 	appResponseBytes[0] = 1
 	appResponseBytes[len(appResponseBytes)-1] = 1
 
-	for _, compress := range []bool{false, true} {
-		builder := NewOutboundBuilder(TestCodec, compress)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip, compression.TypeZstd} {
+		builder := NewOutboundBuilder(TestCodec, compressionType)
 		msg, err := builder.AppResponse(chainID, 1, appResponseBytes)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
@@ -368,8 +443,8 @@ func TestBuildAppGossipMsg(t *testing.T) {
 	appGossipBytes[0] = 1
 	appGossipBytes[len(appGossipBytes)-1] = 1
 
-	for _, compress := range []bool{false, true} {
-		testBuilder := NewOutboundBuilder(TestCodec, compress)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip, compression.TypeZstd} {
+		testBuilder := NewOutboundBuilder(TestCodec, compressionType)
 		msg, err := testBuilder.AppGossip(chainID, appGossipBytes)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
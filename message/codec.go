@@ -21,8 +21,9 @@ import (
 )
 
 var (
-	errMissingField = errors.New("message missing field")
-	errBadOp        = errors.New("input field has invalid operation")
+	errMissingField           = errors.New("message missing field")
+	errBadOp                  = errors.New("input field has invalid operation")
+	errUnknownCompressionType = errors.New("unknown compression type")
 
 	_ Codec = &codec{}
 )
@@ -31,10 +32,30 @@ type Packer interface {
 	Pack(
 		op Op,
 		fieldValues map[Field]interface{},
-		compress bool,
+		compressionType compression.Type,
 	) (OutboundMessage, error)
+
+	// Recompress returns [msg] with its payload compressed using
+	// [compressionType]. The returned message has its own reference, which
+	// must be released independently of [msg].
+	Recompress(
+		msg OutboundMessage,
+		compressionType compression.Type,
+	) (OutboundMessage, error)
+
+	// StripExtensions returns [msg] without its envelope. The returned
+	// message has its own reference, which must be released independently of
+	// [msg].
+	StripExtensions(msg OutboundMessage) (OutboundMessage, error)
+
+	// ToLegacy returns [msg] packed with the legacy codec. The returned
+	// message has its own reference, which must be released independently of
+	// [msg].
+	ToLegacy(msg OutboundMessage) (OutboundMessage, error)
 }
 
+// Parser parses messages packed with either the protobuf schema or the legacy
+// codec.
 type Parser interface {
 	SetTime(t time.Time) // useful in UTs
 	Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error)
@@ -56,10 +77,27 @@ type codec struct {
 
 	compressTimeMetrics   map[Op]metric.Averager
 	decompressTimeMetrics map[Op]metric.Averager
-	compressor            compression.Compressor
+	compressors           map[compression.Type]compression.Compressor
+	// Compressable messages are only compressed if their payload is at least
+	// this many bytes
+	compressionThreshold int
+
+	// If true, messages are packed with the protobuf schema rather than the
+	// legacy codec
+	proto bool
 }
 
+// NewCodecWithMemoryPool returns a Codec that packs messages with the legacy
+// codec
 func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64) (Codec, error) {
+	return newCodecWithMemoryPool(namespace, metrics, maxMessageSize, 0)
+}
+
+func newCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64, compressionThreshold int) (*codec, error) {
+	zstdCompressor, err := compression.NewZstdCompressor(maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
 	c := &codec{
 		byteSlicePool: sync.Pool{
 			New: func() interface{} {
@@ -68,7 +106,11 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 		},
 		compressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
 		decompressTimeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
-		compressor:            compression.NewGzipCompressor(maxMessageSize),
+		compressors: map[compression.Type]compression.Compressor{
+			compression.TypeGzip: compression.NewGzipCompressor(maxMessageSize),
+			compression.TypeZstd: zstdCompressor,
+		},
+		compressionThreshold: compressionThreshold,
 	}
 
 	errs := wrappers.Errs{}
@@ -99,16 +141,32 @@ func (c *codec) SetTime(t time.Time) {
 	c.clock.Set(t)
 }
 
-// Pack attempts to pack a map of fields into a message.
-// The first byte of the message is the opcode of the message.
-// Uses [buffer] to hold the message's byte repr.
-// [buffer]'s contents may be overwritten by this method.
-// [buffer] may be nil.
-// If [compress], compress the payload.
+// Pack attempts to pack a map of fields into a message, with the protobuf
+// schema if this codec was created with NewProtoCodecWithMemoryPool, and with
+// the legacy codec otherwise.
 func (c *codec) Pack(
 	op Op,
 	fieldValues map[Field]interface{},
-	compress bool,
+	compressionType compression.Type,
+) (OutboundMessage, error) {
+	if c.proto {
+		return c.packProto(op, fieldValues, compressionType)
+	}
+	return c.packLegacy(op, fieldValues, compressionType)
+}
+
+// packLegacy attempts to pack a map of fields into a message.
+// The first byte of the message is the opcode of the message.
+// If the message type may be compressed, the second byte is the type of
+// compression applied to the payload. Payloads smaller than the codec's
+// compression threshold are never compressed.
+// If [fieldValues] contains optional fields of [op], they're packed in an
+// envelope after the required fields.
+// If [compressionType] isn't TypeNone, compress the payload.
+func (c *codec) packLegacy(
+	op Op,
+	fieldValues map[Field]interface{},
+	compressionType compression.Type,
 ) (OutboundMessage, error) {
 	msgFields, ok := messages[op]
 	if !ok {
@@ -123,9 +181,10 @@ func (c *codec) Pack(
 	// Pack the op code (message type)
 	p.PackByte(byte(op))
 
-	// Optionally, pack whether the payload is compressed
+	// Optionally, pack the compression type of the payload. The payload isn't
+	// compressed yet, so this is overwritten by [compress].
 	if op.Compressable() {
-		p.PackBool(compress)
+		p.PackByte(byte(compression.TypeNone))
 	}
 
 	// Pack the uncompressed payload
@@ -136,40 +195,195 @@ func (c *codec) Pack(
 		}
 		field.Packer()(&p, data)
 	}
+
+	// Pack the optional fields
+	extensionsOffset := 0
+	if hasExtensions(op, fieldValues) {
+		extensionsOffset = p.Offset
+		packEnvelope(&p, op, fieldValues)
+	}
 	if p.Err != nil {
 		return nil, p.Err
 	}
 	msg := &outboundMessage{
-		op:    op,
-		bytes: p.Bytes,
-		refs:  1,
-		c:     c,
+		op:               op,
+		bytes:            p.Bytes,
+		compressionType:  compression.TypeNone,
+		extensionsOffset: extensionsOffset,
+		refs:             1,
+		c:                c,
 	}
-	if !compress {
+	if !op.Compressable() {
 		return msg, nil
 	}
+	if err := c.compress(msg, compressionType); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Recompress returns a new message with the same contents as [msg], with its
+// payload compressed using [compressionType].
+// If [msg] already uses [compressionType], a new reference to [msg] is
+// returned.
+func (c *codec) Recompress(
+	msg OutboundMessage,
+	compressionType compression.Type,
+) (OutboundMessage, error) {
+	if msg.CompressionType() == compressionType || !msg.Op().Compressable() {
+		msg.AddRef()
+		return msg, nil
+	}
+	if msg.IsProto() {
+		return c.recompressProto(msg, compressionType)
+	}
+
+	op := msg.Op()
+	// The slice below is guaranteed to be in-bounds because [msg] is a
+	// compressable message packed by this codec.
+	payloadBytes := msg.Bytes()[wrappers.ByteLen+wrappers.ByteLen:]
+	if msg.CompressionType() != compression.TypeNone {
+		var err error
+		payloadBytes, err = c.decompress(op, msg.CompressionType(), payloadBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buffer := c.byteSlicePool.Get().([]byte)
+	buffer = append(buffer[:0], byte(op), byte(compression.TypeNone))
+	newMsg := &outboundMessage{
+		op:               op,
+		bytes:            append(buffer, payloadBytes...),
+		compressionType:  compression.TypeNone,
+		extensionsOffset: extensionsOffset(msg),
+		refs:             1,
+		c:                c,
+	}
+	if err := c.compress(newMsg, compressionType); err != nil {
+		return nil, err
+	}
+	return newMsg, nil
+}
+
+// StripExtensions returns a new message with the same required fields as
+// [msg], and without its envelope.
+// Peers that predate envelopes may not parse zstd either, so if [msg] is
+// compressed with zstd, the returned message is compressed with gzip.
+// If [msg] doesn't have an envelope, a new reference to [msg] is returned.
+func (c *codec) StripExtensions(msg OutboundMessage) (OutboundMessage, error) {
+	offset := extensionsOffset(msg)
+	if offset == 0 {
+		msg.AddRef()
+		return msg, nil
+	}
+
+	op := msg.Op()
+	compressionType := msg.CompressionType()
+	uncompressedBytes := msg.Bytes()
+	if compressionType != compression.TypeNone {
+		// The slice below is guaranteed to be in-bounds because [msg] is a
+		// compressed message packed by this codec.
+		payloadBytes, err := c.decompress(op, compressionType, uncompressedBytes[wrappers.ByteLen+wrappers.ByteLen:])
+		if err != nil {
+			return nil, err
+		}
+		uncompressedBytes = append([]byte{byte(op), byte(compression.TypeNone)}, payloadBytes...)
+		if compressionType == compression.TypeZstd {
+			compressionType = compression.TypeGzip
+		}
+	}
+
+	buffer := c.byteSlicePool.Get().([]byte)
+	newMsg := &outboundMessage{
+		op:              op,
+		bytes:           append(buffer[:0], uncompressedBytes[:offset]...),
+		compressionType: compression.TypeNone,
+		refs:            1,
+		c:               c,
+	}
+	if !op.Compressable() {
+		return newMsg, nil
+	}
+	if err := c.compress(newMsg, compressionType); err != nil {
+		return nil, err
+	}
+	return newMsg, nil
+}
+
+// extensionsOffset returns the offset of the envelope of [msg] in its
+// uncompressed bytes, or 0 if [msg] doesn't have an envelope
+func extensionsOffset(msg OutboundMessage) int {
+	if outMsg, ok := msg.(*outboundMessage); ok {
+		return outMsg.extensionsOffset
+	}
+	return 0
+}
+
+// compress compresses the payload of [msg] (not the op code, not the
+// compression type) using [compressionType], if the payload is at least as
+// large as the compression threshold.
+// Assumes [msg] is compressable and its payload is uncompressed.
+func (c *codec) compress(msg *outboundMessage, compressionType compression.Type) error {
+	if compressionType == compression.TypeNone {
+		return nil
+	}
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownCompressionType, compressionType)
+	}
+
+	// The slice below is guaranteed to be in-bounds because compressable
+	// messages always contain the op code and the compression type.
+	payloadBytes := msg.bytes[wrappers.ByteLen+wrappers.ByteLen:]
+	if len(payloadBytes) < c.compressionThreshold {
+		return nil
+	}
 
-	// If [compress], compress the payload (not the op code, not isCompressed).
-	// The slice below is guaranteed to be in-bounds because [p.Err] == nil
-	// implies that len(msg.bytes) >= 2
-	payloadBytes := msg.bytes[wrappers.BoolLen+wrappers.ByteLen:]
 	startTime := time.Now()
-	compressedPayloadBytes, err := c.compressor.Compress(payloadBytes)
+	compressedPayloadBytes, err := compressor.Compress(payloadBytes)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't compress payload of %s message: %w", op, err)
+		return fmt.Errorf("couldn't compress payload of %s message: %w", msg.op, err)
 	}
-	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	c.compressTimeMetrics[msg.op].Observe(float64(time.Since(startTime)))
 	msg.bytesSavedCompression = len(payloadBytes) - len(compressedPayloadBytes) // may be negative
-	// Remove the uncompressed payload (keep just the message type and isCompressed)
-	msg.bytes = msg.bytes[:wrappers.BoolLen+wrappers.ByteLen]
+	msg.compressionType = compressionType
+	// Remove the uncompressed payload (keep just the message type and the
+	// compression type)
+	msg.bytes = msg.bytes[:wrappers.ByteLen+wrappers.ByteLen]
+	msg.bytes[wrappers.ByteLen] = byte(compressionType)
 	// Attach the compressed payload
 	msg.bytes = append(msg.bytes, compressedPayloadBytes...)
-	return msg, nil
+	return nil
+}
+
+// decompress returns the decompressed [compressedPayloadBytes] of an [op]
+// message that was compressed using [compressionType].
+func (c *codec) decompress(op Op, compressionType compression.Type, compressedPayloadBytes []byte) ([]byte, error) {
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownCompressionType, compressionType)
+	}
+	startTime := time.Now()
+	payloadBytes, err := compressor.Decompress(compressedPayloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
+	}
+	c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	return payloadBytes, nil
 }
 
 // Parse attempts to convert bytes into a message.
-// The first byte of the message is the opcode of the message.
+// If the first byte of the message has its high bit set, the message was packed
+// with the protobuf schema. Otherwise, the first byte of the message is the
+// opcode of the message.
+// If there are bytes after the required fields of the message, they're parsed
+// as the envelope of the message.
 func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error) {
+	if len(bytes) > 0 && bytes[0] >= protoMessagePrefix {
+		return c.parseProto(bytes, nodeID, onFinishedHandling)
+	}
+
 	p := wrappers.Packer{Bytes: bytes}
 
 	// Unpack the op code (message type)
@@ -181,9 +395,9 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 	}
 
 	// See if messages of this type may be compressed
-	compressed := false
+	compressionType := compression.TypeNone
 	if op.Compressable() {
-		compressed = p.UnpackBool()
+		compressionType = compression.Type(p.UnpackByte())
 	}
 	if p.Err != nil {
 		return nil, p.Err
@@ -192,21 +406,20 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 	bytesSaved := 0
 
 	// If the payload is compressed, decompress it
-	if compressed {
+	if compressionType != compression.TypeNone {
 		// The slice below is guaranteed to be in-bounds because [p.Err] == nil
-		compressedPayloadBytes := p.Bytes[wrappers.ByteLen+wrappers.BoolLen:]
-		startTime := time.Now()
-		payloadBytes, err := c.compressor.Decompress(compressedPayloadBytes)
+		compressedPayloadBytes := p.Bytes[wrappers.ByteLen+wrappers.ByteLen:]
+		payloadBytes, err := c.decompress(op, compressionType, compressedPayloadBytes)
 		if err != nil {
-			return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
+			return nil, err
 		}
-		c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
 		// Replace the compressed payload with the decompressed payload.
-		// Remove the compressed payload and isCompressed; keep just the message type
+		// Remove the compressed payload and the compression type; keep just
+		// the message type
 		p.Bytes = p.Bytes[:wrappers.ByteLen]
-		// Rewind offset by 1 because we removed the bool flag
+		// Rewind offset by 1 because we removed the compression type
 		// since the data now is uncompressed
-		p.Offset -= wrappers.BoolLen
+		p.Offset -= wrappers.ByteLen
 		// Attach the decompressed payload.
 		p.Bytes = append(p.Bytes, payloadBytes...)
 		bytesSaved = len(payloadBytes) - len(compressedPayloadBytes)
@@ -218,6 +431,11 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 		fieldValues[field] = field.Unpacker()(&p)
 	}
 
+	// Parse the optional fields
+	if !p.Errored() && p.Offset < len(p.Bytes) {
+		parseEnvelope(&p, op, fieldValues)
+	}
+
 	if p.Offset != len(p.Bytes) {
 		return nil, fmt.Errorf("expected length %d but got %d", len(p.Bytes), p.Offset)
 	}
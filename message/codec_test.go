@@ -16,6 +16,7 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/units"
 )
 
@@ -23,10 +24,10 @@ func TestCodecPackInvalidOp(t *testing.T) {
 	codec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
 	assert.NoError(t, err)
 
-	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), false)
+	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), compression.TypeNone)
 	assert.Error(t, err)
 
-	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), true)
+	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), compression.TypeGzip)
 	assert.Error(t, err)
 }
 
@@ -34,10 +35,10 @@ func TestCodecPackMissingField(t *testing.T) {
 	codec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
 	assert.NoError(t, err)
 
-	_, err = codec.Pack(Get, make(map[Field]interface{}), false)
+	_, err = codec.Pack(Get, make(map[Field]interface{}), compression.TypeNone)
 	assert.Error(t, err)
 
-	_, err = codec.Pack(Get, make(map[Field]interface{}), true)
+	_, err = codec.Pack(Get, make(map[Field]interface{}), compression.TypeGzip)
 	assert.Error(t, err)
 }
 
@@ -201,7 +202,11 @@ func TestCodecPackParseGzip(t *testing.T) {
 		},
 	}
 	for _, m := range msgs {
-		packedIntf, err := c.Pack(m.op, m.fields, m.op.Compressable())
+		compressionType := compression.TypeNone
+		if m.op.Compressable() {
+			compressionType = compression.TypeGzip
+		}
+		packedIntf, err := c.Pack(m.op, m.fields, compressionType)
 		assert.NoError(t, err, "failed to pack on operation %s", m.op)
 
 		unpackedIntf, err := c.Parse(packedIntf.Bytes(), dummyNodeID, dummyOnFinishedHandling)
@@ -212,3 +217,136 @@ func TestCodecPackParseGzip(t *testing.T) {
 		assert.EqualValues(t, len(m.fields), len(unpacked.fields))
 	}
 }
+
+func TestCodecCompressionThreshold(t *testing.T) {
+	c, err := newCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 256)
+	assert.NoError(t, err)
+
+	chainID := ids.GenerateTestID()
+	small, err := c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: make([]byte, 1),
+	}, compression.TypeZstd)
+	assert.NoError(t, err)
+	assert.Equal(t, compression.TypeNone, small.CompressionType())
+	assert.Equal(t, byte(compression.TypeNone), small.Bytes()[1])
+
+	large, err := c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: make([]byte, 1024),
+	}, compression.TypeZstd)
+	assert.NoError(t, err)
+	assert.Equal(t, compression.TypeZstd, large.CompressionType())
+	assert.Equal(t, byte(compression.TypeZstd), large.Bytes()[1])
+	assert.Positive(t, large.BytesSavedCompression())
+}
+
+func TestCodecRecompress(t *testing.T) {
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(t, err)
+
+	chainID := ids.GenerateTestID()
+	appBytes := make([]byte, 1024)
+	appBytes[0] = 1
+	msg, err := c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: appBytes,
+	}, compression.TypeZstd)
+	assert.NoError(t, err)
+
+	gzipMsg, err := c.Recompress(msg, compression.TypeGzip)
+	assert.NoError(t, err)
+	assert.Equal(t, AppGossip, gzipMsg.Op())
+	assert.Equal(t, compression.TypeGzip, gzipMsg.CompressionType())
+
+	parsedMsg, err := c.Parse(gzipMsg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, appBytes, parsedMsg.Get(AppBytes))
+
+	sameMsg, err := c.Recompress(msg, compression.TypeZstd)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, sameMsg)
+}
+
+func TestCodecParseUnknownCompressionType(t *testing.T) {
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(t, err)
+
+	_, err = c.Parse([]byte{byte(AppGossip), math.MaxUint8}, dummyNodeID, dummyOnFinishedHandling)
+	assert.ErrorIs(t, err, errUnknownCompressionType)
+}
+
+func TestCodecEnvelope(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(err)
+
+	// Pretend that AppGossip has optional fields
+	extensions[AppGossip] = []Field{RequestID, Deadline}
+	defer delete(extensions, AppGossip)
+
+	chainID := ids.GenerateTestID()
+	appBytes := make([]byte, 1024)
+	appBytes[0] = 1
+
+	// A message without optional fields doesn't have an envelope
+	msg, err := c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: appBytes,
+	}, compression.TypeZstd)
+	assert.NoError(err)
+	assert.False(msg.HasExtensions())
+
+	msg, err = c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:   chainID[:],
+		AppBytes:  appBytes,
+		RequestID: uint32(7),
+	}, compression.TypeZstd)
+	assert.NoError(err)
+	assert.True(msg.HasExtensions())
+
+	// Parsing a compressed message overwrites its bytes, so parse a copy
+	parsedMsg, err := c.Parse(append([]byte{}, msg.Bytes()...), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(err)
+	assert.Equal(appBytes, parsedMsg.Get(AppBytes))
+	assert.Equal(uint32(7), parsedMsg.Get(RequestID))
+	assert.Nil(parsedMsg.Get(Deadline))
+
+	// Peers that predate envelopes are sent the message without its envelope,
+	// compressed with gzip
+	strippedMsg, err := c.StripExtensions(msg)
+	assert.NoError(err)
+	assert.False(strippedMsg.HasExtensions())
+	assert.Equal(compression.TypeGzip, strippedMsg.CompressionType())
+
+	parsedMsg, err = c.Parse(strippedMsg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(err)
+	assert.Equal(appBytes, parsedMsg.Get(AppBytes))
+	assert.Nil(parsedMsg.Get(RequestID))
+
+	// Optional fields added by later versions of the message are ignored
+	msg, err = c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: appBytes,
+		Deadline: uint64(time.Second),
+	}, compression.TypeNone)
+	assert.NoError(err)
+	extensions[AppGossip] = []Field{RequestID}
+
+	parsedMsg, err = c.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(err)
+	assert.Equal(appBytes, parsedMsg.Get(AppBytes))
+	assert.Nil(parsedMsg.Get(Deadline))
+
+	// An envelope must have a version
+	msg, err = c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: appBytes,
+	}, compression.TypeNone)
+	assert.NoError(err)
+	msgBytes := append([]byte{}, msg.Bytes()...)
+	_, err = c.Parse(append(msgBytes, 0), dummyNodeID, dummyOnFinishedHandling)
+	assert.ErrorIs(err, errBadEnvelopeVersion)
+}
@@ -6,6 +6,7 @@ package message
 import (
 	"fmt"
 
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -25,14 +26,41 @@ type creator struct {
 }
 
 func NewCreator(metrics prometheus.Registerer, compressionEnabled bool, parentNamespace string) (Creator, error) {
+	compressionType := compression.TypeNone
+	if compressionEnabled {
+		compressionType = compression.TypeGzip
+	}
+	return NewCreatorWithCompressionType(metrics, compressionType, 0, parentNamespace)
+}
+
+// NewCreatorWithCompressionType returns a Creator whose outbound messages are
+// compressed using [compressionType], if their payload is at least
+// [compressionThreshold] bytes.
+func NewCreatorWithCompressionType(metrics prometheus.Registerer, compressionType compression.Type, compressionThreshold int, parentNamespace string) (Creator, error) {
+	namespace := fmt.Sprintf("%s_codec", parentNamespace)
+	codec, err := newCodecWithMemoryPool(namespace, metrics, int64(constants.DefaultMaxMessageSize), compressionThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return newCreator(codec, compressionType), nil
+}
+
+// NewProtoCreatorWithCompressionType returns a Creator whose outbound messages
+// are packed with the protobuf schema, and compressed using [compressionType]
+// if they're at least [compressionThreshold] bytes.
+func NewProtoCreatorWithCompressionType(metrics prometheus.Registerer, compressionType compression.Type, compressionThreshold int, parentNamespace string) (Creator, error) {
 	namespace := fmt.Sprintf("%s_codec", parentNamespace)
-	codec, err := NewCodecWithMemoryPool(namespace, metrics, int64(constants.DefaultMaxMessageSize))
+	codec, err := newProtoCodecWithMemoryPool(namespace, metrics, int64(constants.DefaultMaxMessageSize), compressionThreshold)
 	if err != nil {
 		return nil, err
 	}
+	return newCreator(codec, compressionType), nil
+}
+
+func newCreator(codec Codec, compressionType compression.Type) Creator {
 	return &creator{
-		OutboundMsgBuilder: NewOutboundBuilder(codec, compressionEnabled),
+		OutboundMsgBuilder: NewOutboundBuilder(codec, compressionType),
 		InboundMsgBuilder:  NewInboundBuilder(codec),
 		InternalMsgBuilder: NewInternalBuilder(),
-	}, nil
+	}
 }
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+// EnvelopeVersion is the version of the envelope that this node packs after
+// the required fields of a message.
+//
+// The envelope carries the optional fields of a message, so that fields can be
+// added to a message without a network upgrade. An envelope is the envelope
+// version (1 byte), followed by the number of entries (4 bytes), followed by
+// the entries. Each entry is the tag of a field (4 bytes) followed by the
+// length prefixed packed field.
+//
+// Later envelope versions may only append data after the entries. Parsers
+// ignore entries whose tag they don't know, and data after the entries.
+const EnvelopeVersion byte = 1
+
+var (
+	errBadEnvelopeVersion = errors.New("invalid envelope version")
+	errBadExtension       = errors.New("invalid extension field")
+)
+
+// hasExtensions returns true if [fieldValues] contains any of the optional
+// fields of [op]
+func hasExtensions(op Op, fieldValues map[Field]interface{}) bool {
+	for _, field := range extensions[op] {
+		if _, ok := fieldValues[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// packEnvelope packs the optional fields of [op] that are in [fieldValues]
+func packEnvelope(p *wrappers.Packer, op Op, fieldValues map[Field]interface{}) {
+	fields := extensions[op]
+	numEntries := uint32(0)
+	for _, field := range fields {
+		if _, ok := fieldValues[field]; ok {
+			numEntries++
+		}
+	}
+
+	p.PackByte(EnvelopeVersion)
+	p.PackInt(numEntries)
+	for tag, field := range fields {
+		value, ok := fieldValues[field]
+		if !ok {
+			continue
+		}
+		fieldPacker := wrappers.Packer{MaxSize: math.MaxInt32}
+		field.Packer()(&fieldPacker, value)
+		if fieldPacker.Err != nil {
+			p.Add(fieldPacker.Err)
+			return
+		}
+		p.PackInt(uint32(tag))
+		p.PackBytes(fieldPacker.Bytes)
+	}
+}
+
+// parseEnvelope parses the envelope of an [op] message into [fieldValues]
+func parseEnvelope(p *wrappers.Packer, op Op, fieldValues map[Field]interface{}) {
+	if version := p.UnpackByte(); version == 0 {
+		p.Add(errBadEnvelopeVersion)
+		return
+	}
+
+	fields := extensions[op]
+	numEntries := p.UnpackInt()
+	for i := uint32(0); i < numEntries && !p.Errored(); i++ {
+		tag := p.UnpackInt()
+		fieldBytes := p.UnpackBytes()
+		if p.Errored() || int(tag) >= len(fields) {
+			// The field was added by a later version of the message
+			continue
+		}
+
+		field := fields[tag]
+		fieldPacker := wrappers.Packer{Bytes: fieldBytes}
+		value := field.Unpacker()(&fieldPacker)
+		if fieldPacker.Errored() || fieldPacker.Offset != len(fieldBytes) {
+			p.Add(errBadExtension)
+			return
+		}
+		fieldValues[field] = value
+	}
+
+	// Skip the data appended by later envelope versions
+	if !p.Errored() {
+		p.Offset = len(p.Bytes)
+	}
+}
@@ -33,6 +33,8 @@ const (
 	VMMessage                        // Used internally
 	Uptime                           // Used for Pong
 	VersionStruct                    // Used internally
+	SupportedFeatures                // Used in handshake
+	PeerInfoStruct                   // Used internally
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -76,6 +78,8 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case Uptime:
 		return wrappers.TryPackByte
+	case SupportedFeatures:
+		return wrappers.TryPackLong
 	default:
 		return nil
 	}
@@ -122,6 +126,8 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case Uptime:
 		return wrappers.TryUnpackByte
+	case SupportedFeatures:
+		return wrappers.TryUnpackLong
 	default:
 		return nil
 	}
@@ -171,6 +177,10 @@ func (f Field) String() string {
 		return "Uptime"
 	case VersionStruct:
 		return "VersionStruct"
+	case SupportedFeatures:
+		return "SupportedFeatures"
+	case PeerInfoStruct:
+		return "PeerInfoStruct"
 	default:
 		return "Unknown Field"
 	}
@@ -5,7 +5,7 @@ package message
 
 import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
-	"github.com/Toinounet21/avalanchego-mod/version"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 )
 
 var _ InternalMsgBuilder = internalMsgBuilder{}
@@ -19,7 +19,7 @@ type InternalMsgBuilder interface {
 	) InboundMessage
 
 	InternalTimeout(nodeID ids.ShortID) InboundMessage
-	InternalConnected(nodeID ids.ShortID, nodeVersion version.Application) InboundMessage
+	InternalConnected(nodeID ids.ShortID, peerInfo validators.PeerInfo) InboundMessage
 	InternalDisconnected(nodeID ids.ShortID) InboundMessage
 	InternalVMMessage(nodeID ids.ShortID, notification uint32) InboundMessage
 	InternalGossipRequest(nodeID ids.ShortID) InboundMessage
@@ -54,11 +54,12 @@ func (internalMsgBuilder) InternalTimeout(nodeID ids.ShortID) InboundMessage {
 	}
 }
 
-func (internalMsgBuilder) InternalConnected(nodeID ids.ShortID, nodeVersion version.Application) InboundMessage {
+func (internalMsgBuilder) InternalConnected(nodeID ids.ShortID, peerInfo validators.PeerInfo) InboundMessage {
 	return &inboundMessage{
 		op: Connected,
 		fields: map[Field]interface{}{
-			VersionStruct: nodeVersion,
+			VersionStruct:  peerInfo.Version,
+			PeerInfoStruct: peerInfo,
 		},
 		nodeID: nodeID,
 	}
@@ -0,0 +1,2771 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: message.proto
+
+package messageproto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Message is a p2p message. Exactly one of its fields is set.
+//
+// The number of the field of each message type is 16 plus the op code of the
+// message type in the legacy codec, and the fields of each message type are
+// numbered in the order they're packed in by the legacy codec. Field numbers
+// start at 16 so that the first byte of an encoded message is never a valid
+// legacy op code, which lets parsers tell both encodings apart.
+type Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*Message_CompressedGzip
+	//	*Message_CompressedZstd
+	//	*Message_GetVersion
+	//	*Message_GetPeerList
+	//	*Message_Pong
+	//	*Message_Ping
+	//	*Message_GetAcceptedFrontier
+	//	*Message_AcceptedFrontier_
+	//	*Message_GetAccepted
+	//	*Message_Accepted_
+	//	*Message_GetAncestors
+	//	*Message_Ancestors_
+	//	*Message_Get
+	//	*Message_Put
+	//	*Message_PushQuery
+	//	*Message_PullQuery
+	//	*Message_Chits
+	//	*Message_PeerList_
+	//	*Message_Version_
+	//	*Message_AppRequest
+	//	*Message_AppResponse
+	//	*Message_AppGossip
+	//	*Message_Ipv6
+	//	*Message_Features
+	//	*Message_Subnets
+	//	*Message_SignedIP
+	Message isMessage_Message `protobuf_oneof:"message"`
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *Message) GetMessage() isMessage_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *Message) GetCompressedGzip() []byte {
+	if x, ok := x.GetMessage().(*Message_CompressedGzip); ok {
+		return x.CompressedGzip
+	}
+	return nil
+}
+
+func (x *Message) GetCompressedZstd() []byte {
+	if x, ok := x.GetMessage().(*Message_CompressedZstd); ok {
+		return x.CompressedZstd
+	}
+	return nil
+}
+
+func (x *Message) GetGetVersion() *GetVersion {
+	if x, ok := x.GetMessage().(*Message_GetVersion); ok {
+		return x.GetVersion
+	}
+	return nil
+}
+
+func (x *Message) GetGetPeerList() *GetPeerList {
+	if x, ok := x.GetMessage().(*Message_GetPeerList); ok {
+		return x.GetPeerList
+	}
+	return nil
+}
+
+func (x *Message) GetPong() *Pong {
+	if x, ok := x.GetMessage().(*Message_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
+func (x *Message) GetPing() *Ping {
+	if x, ok := x.GetMessage().(*Message_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (x *Message) GetGetAcceptedFrontier() *GetAcceptedFrontier {
+	if x, ok := x.GetMessage().(*Message_GetAcceptedFrontier); ok {
+		return x.GetAcceptedFrontier
+	}
+	return nil
+}
+
+func (x *Message) GetAcceptedFrontier_() *AcceptedFrontier {
+	if x, ok := x.GetMessage().(*Message_AcceptedFrontier_); ok {
+		return x.AcceptedFrontier_
+	}
+	return nil
+}
+
+func (x *Message) GetGetAccepted() *GetAccepted {
+	if x, ok := x.GetMessage().(*Message_GetAccepted); ok {
+		return x.GetAccepted
+	}
+	return nil
+}
+
+func (x *Message) GetAccepted_() *Accepted {
+	if x, ok := x.GetMessage().(*Message_Accepted_); ok {
+		return x.Accepted_
+	}
+	return nil
+}
+
+func (x *Message) GetGetAncestors() *GetAncestors {
+	if x, ok := x.GetMessage().(*Message_GetAncestors); ok {
+		return x.GetAncestors
+	}
+	return nil
+}
+
+func (x *Message) GetAncestors_() *Ancestors {
+	if x, ok := x.GetMessage().(*Message_Ancestors_); ok {
+		return x.Ancestors_
+	}
+	return nil
+}
+
+func (x *Message) GetGet() *Get {
+	if x, ok := x.GetMessage().(*Message_Get); ok {
+		return x.Get
+	}
+	return nil
+}
+
+func (x *Message) GetPut() *Put {
+	if x, ok := x.GetMessage().(*Message_Put); ok {
+		return x.Put
+	}
+	return nil
+}
+
+func (x *Message) GetPushQuery() *PushQuery {
+	if x, ok := x.GetMessage().(*Message_PushQuery); ok {
+		return x.PushQuery
+	}
+	return nil
+}
+
+func (x *Message) GetPullQuery() *PullQuery {
+	if x, ok := x.GetMessage().(*Message_PullQuery); ok {
+		return x.PullQuery
+	}
+	return nil
+}
+
+func (x *Message) GetChits() *Chits {
+	if x, ok := x.GetMessage().(*Message_Chits); ok {
+		return x.Chits
+	}
+	return nil
+}
+
+func (x *Message) GetPeerList_() *PeerList {
+	if x, ok := x.GetMessage().(*Message_PeerList_); ok {
+		return x.PeerList_
+	}
+	return nil
+}
+
+func (x *Message) GetVersion_() *Version {
+	if x, ok := x.GetMessage().(*Message_Version_); ok {
+		return x.Version_
+	}
+	return nil
+}
+
+func (x *Message) GetAppRequest() *AppRequest {
+	if x, ok := x.GetMessage().(*Message_AppRequest); ok {
+		return x.AppRequest
+	}
+	return nil
+}
+
+func (x *Message) GetAppResponse() *AppResponse {
+	if x, ok := x.GetMessage().(*Message_AppResponse); ok {
+		return x.AppResponse
+	}
+	return nil
+}
+
+func (x *Message) GetAppGossip() *AppGossip {
+	if x, ok := x.GetMessage().(*Message_AppGossip); ok {
+		return x.AppGossip
+	}
+	return nil
+}
+
+func (x *Message) GetIpv6() *IPv6 {
+	if x, ok := x.GetMessage().(*Message_Ipv6); ok {
+		return x.Ipv6
+	}
+	return nil
+}
+
+func (x *Message) GetFeatures() *Features {
+	if x, ok := x.GetMessage().(*Message_Features); ok {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *Message) GetSubnets() *Subnets {
+	if x, ok := x.GetMessage().(*Message_Subnets); ok {
+		return x.Subnets
+	}
+	return nil
+}
+
+func (x *Message) GetSignedIP() *SignedIP {
+	if x, ok := x.GetMessage().(*Message_SignedIP); ok {
+		return x.SignedIP
+	}
+	return nil
+}
+
+type isMessage_Message interface {
+	isMessage_Message()
+}
+
+type Message_CompressedGzip struct {
+	// A Message, compressed with gzip or zstd
+	CompressedGzip []byte `protobuf:"bytes,128,opt,name=compressedGzip,proto3,oneof"`
+}
+
+type Message_CompressedZstd struct {
+	CompressedZstd []byte `protobuf:"bytes,129,opt,name=compressedZstd,proto3,oneof"`
+}
+
+type Message_GetVersion struct {
+	// Handshake:
+	GetVersion *GetVersion `protobuf:"bytes,16,opt,name=getVersion,proto3,oneof"`
+}
+
+type Message_GetPeerList struct {
+	GetPeerList *GetPeerList `protobuf:"bytes,18,opt,name=getPeerList,proto3,oneof"`
+}
+
+type Message_Pong struct {
+	Pong *Pong `protobuf:"bytes,19,opt,name=pong,proto3,oneof"`
+}
+
+type Message_Ping struct {
+	Ping *Ping `protobuf:"bytes,20,opt,name=ping,proto3,oneof"`
+}
+
+type Message_GetAcceptedFrontier struct {
+	// Bootstrapping:
+	GetAcceptedFrontier *GetAcceptedFrontier `protobuf:"bytes,22,opt,name=getAcceptedFrontier,proto3,oneof"`
+}
+
+type Message_AcceptedFrontier_ struct {
+	AcceptedFrontier_ *AcceptedFrontier `protobuf:"bytes,23,opt,name=acceptedFrontier,proto3,oneof"`
+}
+
+type Message_GetAccepted struct {
+	GetAccepted *GetAccepted `protobuf:"bytes,24,opt,name=getAccepted,proto3,oneof"`
+}
+
+type Message_Accepted_ struct {
+	Accepted_ *Accepted `protobuf:"bytes,25,opt,name=accepted,proto3,oneof"`
+}
+
+type Message_GetAncestors struct {
+	GetAncestors *GetAncestors `protobuf:"bytes,26,opt,name=getAncestors,proto3,oneof"`
+}
+
+type Message_Ancestors_ struct {
+	Ancestors_ *Ancestors `protobuf:"bytes,27,opt,name=ancestors,proto3,oneof"`
+}
+
+type Message_Get struct {
+	// Consensus:
+	Get *Get `protobuf:"bytes,28,opt,name=get,proto3,oneof"`
+}
+
+type Message_Put struct {
+	Put *Put `protobuf:"bytes,29,opt,name=put,proto3,oneof"`
+}
+
+type Message_PushQuery struct {
+	PushQuery *PushQuery `protobuf:"bytes,30,opt,name=pushQuery,proto3,oneof"`
+}
+
+type Message_PullQuery struct {
+	PullQuery *PullQuery `protobuf:"bytes,31,opt,name=pullQuery,proto3,oneof"`
+}
+
+type Message_Chits struct {
+	Chits *Chits `protobuf:"bytes,32,opt,name=chits,proto3,oneof"`
+}
+
+type Message_PeerList_ struct {
+	// Handshake / peer gossiping:
+	PeerList_ *PeerList `protobuf:"bytes,34,opt,name=peerList,proto3,oneof"`
+}
+
+type Message_Version_ struct {
+	Version_ *Version `protobuf:"bytes,35,opt,name=version,proto3,oneof"`
+}
+
+type Message_AppRequest struct {
+	// Application level:
+	AppRequest *AppRequest `protobuf:"bytes,36,opt,name=appRequest,proto3,oneof"`
+}
+
+type Message_AppResponse struct {
+	AppResponse *AppResponse `protobuf:"bytes,37,opt,name=appResponse,proto3,oneof"`
+}
+
+type Message_AppGossip struct {
+	AppGossip *AppGossip `protobuf:"bytes,38,opt,name=appGossip,proto3,oneof"`
+}
+
+type Message_Ipv6 struct {
+	// Handshake:
+	Ipv6 *IPv6 `protobuf:"bytes,39,opt,name=ipv6,proto3,oneof"`
+}
+
+type Message_Features struct {
+	Features *Features `protobuf:"bytes,40,opt,name=features,proto3,oneof"`
+}
+
+type Message_Subnets struct {
+	Subnets *Subnets `protobuf:"bytes,41,opt,name=subnets,proto3,oneof"`
+}
+
+type Message_SignedIP struct {
+	SignedIP *SignedIP `protobuf:"bytes,42,opt,name=signedIP,proto3,oneof"`
+}
+
+func (*Message_CompressedGzip) isMessage_Message() {}
+
+func (*Message_CompressedZstd) isMessage_Message() {}
+
+func (*Message_GetVersion) isMessage_Message() {}
+
+func (*Message_GetPeerList) isMessage_Message() {}
+
+func (*Message_Pong) isMessage_Message() {}
+
+func (*Message_Ping) isMessage_Message() {}
+
+func (*Message_GetAcceptedFrontier) isMessage_Message() {}
+
+func (*Message_AcceptedFrontier_) isMessage_Message() {}
+
+func (*Message_GetAccepted) isMessage_Message() {}
+
+func (*Message_Accepted_) isMessage_Message() {}
+
+func (*Message_GetAncestors) isMessage_Message() {}
+
+func (*Message_Ancestors_) isMessage_Message() {}
+
+func (*Message_Get) isMessage_Message() {}
+
+func (*Message_Put) isMessage_Message() {}
+
+func (*Message_PushQuery) isMessage_Message() {}
+
+func (*Message_PullQuery) isMessage_Message() {}
+
+func (*Message_Chits) isMessage_Message() {}
+
+func (*Message_PeerList_) isMessage_Message() {}
+
+func (*Message_Version_) isMessage_Message() {}
+
+func (*Message_AppRequest) isMessage_Message() {}
+
+func (*Message_AppResponse) isMessage_Message() {}
+
+func (*Message_AppGossip) isMessage_Message() {}
+
+func (*Message_Ipv6) isMessage_Message() {}
+
+func (*Message_Features) isMessage_Message() {}
+
+func (*Message_Subnets) isMessage_Message() {}
+
+func (*Message_SignedIP) isMessage_Message() {}
+
+type IPDesc struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 16 bytes, IPv4 addresses are mapped to IPv6
+	Ip   []byte `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+}
+
+func (x *IPDesc) Reset() {
+	*x = IPDesc{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPDesc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPDesc) ProtoMessage() {}
+
+func (x *IPDesc) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPDesc.ProtoReflect.Descriptor instead.
+func (*IPDesc) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IPDesc) GetIp() []byte {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *IPDesc) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type SignedPeer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// DER encoded
+	Cert      []byte  `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+	Ip        *IPDesc `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Time      uint64  `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Signature []byte  `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *SignedPeer) Reset() {
+	*x = SignedPeer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedPeer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedPeer) ProtoMessage() {}
+
+func (x *SignedPeer) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedPeer.ProtoReflect.Descriptor instead.
+func (*SignedPeer) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SignedPeer) GetCert() []byte {
+	if x != nil {
+		return x.Cert
+	}
+	return nil
+}
+
+func (x *SignedPeer) GetIp() *IPDesc {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *SignedPeer) GetTime() uint64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *SignedPeer) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type GetVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetVersion) Reset() {
+	*x = GetVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersion) ProtoMessage() {}
+
+func (x *GetVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersion.ProtoReflect.Descriptor instead.
+func (*GetVersion) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{3}
+}
+
+type Version struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NetworkID      uint32   `protobuf:"varint,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	NodeID         uint32   `protobuf:"varint,2,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	MyTime         uint64   `protobuf:"varint,3,opt,name=myTime,proto3" json:"myTime,omitempty"`
+	Ip             *IPDesc  `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	VersionStr     string   `protobuf:"bytes,5,opt,name=versionStr,proto3" json:"versionStr,omitempty"`
+	VersionTime    uint64   `protobuf:"varint,6,opt,name=versionTime,proto3" json:"versionTime,omitempty"`
+	SigBytes       []byte   `protobuf:"bytes,7,opt,name=sigBytes,proto3" json:"sigBytes,omitempty"`
+	TrackedSubnets [][]byte `protobuf:"bytes,8,rep,name=trackedSubnets,proto3" json:"trackedSubnets,omitempty"`
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Version) GetNetworkID() uint32 {
+	if x != nil {
+		return x.NetworkID
+	}
+	return 0
+}
+
+func (x *Version) GetNodeID() uint32 {
+	if x != nil {
+		return x.NodeID
+	}
+	return 0
+}
+
+func (x *Version) GetMyTime() uint64 {
+	if x != nil {
+		return x.MyTime
+	}
+	return 0
+}
+
+func (x *Version) GetIp() *IPDesc {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *Version) GetVersionStr() string {
+	if x != nil {
+		return x.VersionStr
+	}
+	return ""
+}
+
+func (x *Version) GetVersionTime() uint64 {
+	if x != nil {
+		return x.VersionTime
+	}
+	return 0
+}
+
+func (x *Version) GetSigBytes() []byte {
+	if x != nil {
+		return x.SigBytes
+	}
+	return nil
+}
+
+func (x *Version) GetTrackedSubnets() [][]byte {
+	if x != nil {
+		return x.TrackedSubnets
+	}
+	return nil
+}
+
+type GetPeerList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetPeerList) Reset() {
+	*x = GetPeerList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerList) ProtoMessage() {}
+
+func (x *GetPeerList) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerList.ProtoReflect.Descriptor instead.
+func (*GetPeerList) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{5}
+}
+
+type PeerList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SignedPeers []*SignedPeer `protobuf:"bytes,1,rep,name=signedPeers,proto3" json:"signedPeers,omitempty"`
+}
+
+func (x *PeerList) Reset() {
+	*x = PeerList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerList) ProtoMessage() {}
+
+func (x *PeerList) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerList.ProtoReflect.Descriptor instead.
+func (*PeerList) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PeerList) GetSignedPeers() []*SignedPeer {
+	if x != nil {
+		return x.SignedPeers
+	}
+	return nil
+}
+
+type Ping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Ping) Reset() {
+	*x = Ping{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ping) ProtoMessage() {}
+
+func (x *Ping) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ping.ProtoReflect.Descriptor instead.
+func (*Ping) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{7}
+}
+
+type Pong struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uptime uint32 `protobuf:"varint,1,opt,name=uptime,proto3" json:"uptime,omitempty"`
+}
+
+func (x *Pong) Reset() {
+	*x = Pong{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pong) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pong) ProtoMessage() {}
+
+func (x *Pong) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pong.ProtoReflect.Descriptor instead.
+func (*Pong) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Pong) GetUptime() uint32 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type IPv6 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip          *IPDesc `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	VersionTime uint64  `protobuf:"varint,2,opt,name=versionTime,proto3" json:"versionTime,omitempty"`
+	SigBytes    []byte  `protobuf:"bytes,3,opt,name=sigBytes,proto3" json:"sigBytes,omitempty"`
+}
+
+func (x *IPv6) Reset() {
+	*x = IPv6{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPv6) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPv6) ProtoMessage() {}
+
+func (x *IPv6) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPv6.ProtoReflect.Descriptor instead.
+func (*IPv6) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *IPv6) GetIp() *IPDesc {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *IPv6) GetVersionTime() uint64 {
+	if x != nil {
+		return x.VersionTime
+	}
+	return 0
+}
+
+func (x *IPv6) GetSigBytes() []byte {
+	if x != nil {
+		return x.SigBytes
+	}
+	return nil
+}
+
+type Features struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SupportedFeatures uint64 `protobuf:"varint,1,opt,name=supportedFeatures,proto3" json:"supportedFeatures,omitempty"`
+}
+
+func (x *Features) Reset() {
+	*x = Features{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Features) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Features) ProtoMessage() {}
+
+func (x *Features) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Features.ProtoReflect.Descriptor instead.
+func (*Features) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Features) GetSupportedFeatures() uint64 {
+	if x != nil {
+		return x.SupportedFeatures
+	}
+	return 0
+}
+
+type Subnets struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TrackedSubnets [][]byte `protobuf:"bytes,1,rep,name=trackedSubnets,proto3" json:"trackedSubnets,omitempty"`
+}
+
+func (x *Subnets) Reset() {
+	*x = Subnets{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subnets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subnets) ProtoMessage() {}
+
+func (x *Subnets) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subnets.ProtoReflect.Descriptor instead.
+func (*Subnets) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Subnets) GetTrackedSubnets() [][]byte {
+	if x != nil {
+		return x.TrackedSubnets
+	}
+	return nil
+}
+
+// Refreshes the timestamp and signature of the IP that a node advertised in
+// its Version message
+type SignedIP struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip          *IPDesc `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	VersionTime uint64  `protobuf:"varint,2,opt,name=versionTime,proto3" json:"versionTime,omitempty"`
+	SigBytes    []byte  `protobuf:"bytes,3,opt,name=sigBytes,proto3" json:"sigBytes,omitempty"`
+}
+
+func (x *SignedIP) Reset() {
+	*x = SignedIP{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedIP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedIP) ProtoMessage() {}
+
+func (x *SignedIP) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedIP.ProtoReflect.Descriptor instead.
+func (*SignedIP) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SignedIP) GetIp() *IPDesc {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *SignedIP) GetVersionTime() uint64 {
+	if x != nil {
+		return x.VersionTime
+	}
+	return 0
+}
+
+func (x *SignedIP) GetSigBytes() []byte {
+	if x != nil {
+		return x.SigBytes
+	}
+	return nil
+}
+
+type GetAcceptedFrontier struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID   []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline  uint64 `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+}
+
+func (x *GetAcceptedFrontier) Reset() {
+	*x = GetAcceptedFrontier{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAcceptedFrontier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAcceptedFrontier) ProtoMessage() {}
+
+func (x *GetAcceptedFrontier) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAcceptedFrontier.ProtoReflect.Descriptor instead.
+func (*GetAcceptedFrontier) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetAcceptedFrontier) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *GetAcceptedFrontier) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *GetAcceptedFrontier) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+type AcceptedFrontier struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID      []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID    uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerIDs [][]byte `protobuf:"bytes,3,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+}
+
+func (x *AcceptedFrontier) Reset() {
+	*x = AcceptedFrontier{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AcceptedFrontier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptedFrontier) ProtoMessage() {}
+
+func (x *AcceptedFrontier) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptedFrontier.ProtoReflect.Descriptor instead.
+func (*AcceptedFrontier) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AcceptedFrontier) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *AcceptedFrontier) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *AcceptedFrontier) GetContainerIDs() [][]byte {
+	if x != nil {
+		return x.ContainerIDs
+	}
+	return nil
+}
+
+type GetAccepted struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID      []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID    uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline     uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerIDs [][]byte `protobuf:"bytes,4,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+}
+
+func (x *GetAccepted) Reset() {
+	*x = GetAccepted{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAccepted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccepted) ProtoMessage() {}
+
+func (x *GetAccepted) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccepted.ProtoReflect.Descriptor instead.
+func (*GetAccepted) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAccepted) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *GetAccepted) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *GetAccepted) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *GetAccepted) GetContainerIDs() [][]byte {
+	if x != nil {
+		return x.ContainerIDs
+	}
+	return nil
+}
+
+type Accepted struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID      []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID    uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerIDs [][]byte `protobuf:"bytes,3,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+}
+
+func (x *Accepted) Reset() {
+	*x = Accepted{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Accepted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Accepted) ProtoMessage() {}
+
+func (x *Accepted) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Accepted.ProtoReflect.Descriptor instead.
+func (*Accepted) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Accepted) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *Accepted) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *Accepted) GetContainerIDs() [][]byte {
+	if x != nil {
+		return x.ContainerIDs
+	}
+	return nil
+}
+
+type GetAncestors struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID     []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID   uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline    uint64 `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID []byte `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+}
+
+func (x *GetAncestors) Reset() {
+	*x = GetAncestors{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAncestors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAncestors) ProtoMessage() {}
+
+func (x *GetAncestors) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAncestors.ProtoReflect.Descriptor instead.
+func (*GetAncestors) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetAncestors) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *GetAncestors) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *GetAncestors) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *GetAncestors) GetContainerID() []byte {
+	if x != nil {
+		return x.ContainerID
+	}
+	return nil
+}
+
+type Ancestors struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID             []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID           uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	MultiContainerBytes [][]byte `protobuf:"bytes,3,rep,name=multiContainerBytes,proto3" json:"multiContainerBytes,omitempty"`
+}
+
+func (x *Ancestors) Reset() {
+	*x = Ancestors{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ancestors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ancestors) ProtoMessage() {}
+
+func (x *Ancestors) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ancestors.ProtoReflect.Descriptor instead.
+func (*Ancestors) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Ancestors) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *Ancestors) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *Ancestors) GetMultiContainerBytes() [][]byte {
+	if x != nil {
+		return x.MultiContainerBytes
+	}
+	return nil
+}
+
+type Get struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID     []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID   uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline    uint64 `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID []byte `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+}
+
+func (x *Get) Reset() {
+	*x = Get{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Get) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Get) ProtoMessage() {}
+
+func (x *Get) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Get.ProtoReflect.Descriptor instead.
+func (*Get) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Get) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *Get) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *Get) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *Get) GetContainerID() []byte {
+	if x != nil {
+		return x.ContainerID
+	}
+	return nil
+}
+
+type Put struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID        []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID      uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerID    []byte `protobuf:"bytes,3,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	ContainerBytes []byte `protobuf:"bytes,4,opt,name=containerBytes,proto3" json:"containerBytes,omitempty"`
+}
+
+func (x *Put) Reset() {
+	*x = Put{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Put) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Put) ProtoMessage() {}
+
+func (x *Put) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Put.ProtoReflect.Descriptor instead.
+func (*Put) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Put) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *Put) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *Put) GetContainerID() []byte {
+	if x != nil {
+		return x.ContainerID
+	}
+	return nil
+}
+
+func (x *Put) GetContainerBytes() []byte {
+	if x != nil {
+		return x.ContainerBytes
+	}
+	return nil
+}
+
+type PushQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID        []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID      uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline       uint64 `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID    []byte `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	ContainerBytes []byte `protobuf:"bytes,5,opt,name=containerBytes,proto3" json:"containerBytes,omitempty"`
+}
+
+func (x *PushQuery) Reset() {
+	*x = PushQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushQuery) ProtoMessage() {}
+
+func (x *PushQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushQuery.ProtoReflect.Descriptor instead.
+func (*PushQuery) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PushQuery) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *PushQuery) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *PushQuery) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *PushQuery) GetContainerID() []byte {
+	if x != nil {
+		return x.ContainerID
+	}
+	return nil
+}
+
+func (x *PushQuery) GetContainerBytes() []byte {
+	if x != nil {
+		return x.ContainerBytes
+	}
+	return nil
+}
+
+type PullQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID     []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID   uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline    uint64 `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID []byte `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+}
+
+func (x *PullQuery) Reset() {
+	*x = PullQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PullQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullQuery) ProtoMessage() {}
+
+func (x *PullQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullQuery.ProtoReflect.Descriptor instead.
+func (*PullQuery) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PullQuery) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *PullQuery) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *PullQuery) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *PullQuery) GetContainerID() []byte {
+	if x != nil {
+		return x.ContainerID
+	}
+	return nil
+}
+
+type Chits struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID      []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID    uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerIDs [][]byte `protobuf:"bytes,3,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+}
+
+func (x *Chits) Reset() {
+	*x = Chits{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Chits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chits) ProtoMessage() {}
+
+func (x *Chits) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chits.ProtoReflect.Descriptor instead.
+func (*Chits) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Chits) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *Chits) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *Chits) GetContainerIDs() [][]byte {
+	if x != nil {
+		return x.ContainerIDs
+	}
+	return nil
+}
+
+type AppRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID   []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline  uint64 `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	AppBytes  []byte `protobuf:"bytes,4,opt,name=appBytes,proto3" json:"appBytes,omitempty"`
+}
+
+func (x *AppRequest) Reset() {
+	*x = AppRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppRequest) ProtoMessage() {}
+
+func (x *AppRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppRequest.ProtoReflect.Descriptor instead.
+func (*AppRequest) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AppRequest) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *AppRequest) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *AppRequest) GetDeadline() uint64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *AppRequest) GetAppBytes() []byte {
+	if x != nil {
+		return x.AppBytes
+	}
+	return nil
+}
+
+type AppResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID   []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID uint32 `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	AppBytes  []byte `protobuf:"bytes,3,opt,name=appBytes,proto3" json:"appBytes,omitempty"`
+}
+
+func (x *AppResponse) Reset() {
+	*x = AppResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppResponse) ProtoMessage() {}
+
+func (x *AppResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppResponse.ProtoReflect.Descriptor instead.
+func (*AppResponse) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AppResponse) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *AppResponse) GetRequestID() uint32 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *AppResponse) GetAppBytes() []byte {
+	if x != nil {
+		return x.AppBytes
+	}
+	return nil
+}
+
+type AppGossip struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChainID  []byte `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	AppBytes []byte `protobuf:"bytes,2,opt,name=appBytes,proto3" json:"appBytes,omitempty"`
+}
+
+func (x *AppGossip) Reset() {
+	*x = AppGossip{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppGossip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppGossip) ProtoMessage() {}
+
+func (x *AppGossip) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppGossip.ProtoReflect.Descriptor instead.
+func (*AppGossip) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AppGossip) GetChainID() []byte {
+	if x != nil {
+		return x.ChainID
+	}
+	return nil
+}
+
+func (x *AppGossip) GetAppBytes() []byte {
+	if x != nil {
+		return x.AppBytes
+	}
+	return nil
+}
+
+var File_message_proto protoreflect.FileDescriptor
+
+var file_message_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa1, 0x0b,
+	0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x0e, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x47, 0x7a, 0x69, 0x70, 0x18, 0x80, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64,
+	0x47, 0x7a, 0x69, 0x70, 0x12, 0x29, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x5a, 0x73, 0x74, 0x64, 0x18, 0x81, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5a, 0x73, 0x74, 0x64, 0x12,
+	0x3a, 0x0a, 0x0a, 0x67, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x10, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x0a, 0x67, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x0b, 0x67,
+	0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x67,
+	0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x6f,
+	0x6e, 0x67, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04,
+	0x70, 0x6f, 0x6e, 0x67, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x55,
+	0x0a, 0x13, 0x67, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f,
+	0x6e, 0x74, 0x69, 0x65, 0x72, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63,
+	0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x48, 0x00,
+	0x52, 0x13, 0x67, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f,
+	0x6e, 0x74, 0x69, 0x65, 0x72, 0x12, 0x4c, 0x0a, 0x10, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65,
+	0x64, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x48,
+	0x00, 0x52, 0x10, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6e, 0x74,
+	0x69, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0b, 0x67, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0b, 0x67, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x12, 0x34, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x19,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x08,
+	0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x40, 0x0a, 0x0c, 0x67, 0x65, 0x74, 0x41,
+	0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65,
+	0x74, 0x41, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x48, 0x00, 0x52, 0x0c, 0x67, 0x65,
+	0x74, 0x41, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x37, 0x0a, 0x09, 0x61, 0x6e,
+	0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x6e, 0x63,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x48, 0x00, 0x52, 0x09, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x73, 0x12, 0x25, 0x0a, 0x03, 0x67, 0x65, 0x74, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x47, 0x65, 0x74, 0x48, 0x00, 0x52, 0x03, 0x67, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x03, 0x70, 0x75,
+	0x74, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x74, 0x48, 0x00, 0x52, 0x03, 0x70, 0x75,
+	0x74, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x75, 0x73, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x48, 0x00, 0x52,
+	0x09, 0x70, 0x75, 0x73, 0x68, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x75,
+	0x6c, 0x6c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x6c,
+	0x6c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x48, 0x00, 0x52, 0x09, 0x70, 0x75, 0x6c, 0x6c, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x68, 0x69, 0x74, 0x73, 0x18, 0x20, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x43, 0x68, 0x69, 0x74, 0x73, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x69, 0x74, 0x73,
+	0x12, 0x34, 0x0a, 0x08, 0x70, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x22, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x00, 0x52, 0x08, 0x70, 0x65,
+	0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x23, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x0a, 0x61, 0x70, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x24, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x70, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x18, 0x25, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x61, 0x70, 0x70, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x61, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69,
+	0x70, 0x18, 0x26, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70,
+	0x48, 0x00, 0x52, 0x09, 0x61, 0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x12, 0x28, 0x0a,
+	0x04, 0x69, 0x70, 0x76, 0x36, 0x18, 0x27, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x50, 0x76, 0x36, 0x48,
+	0x00, 0x52, 0x04, 0x69, 0x70, 0x76, 0x36, 0x12, 0x34, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x18, 0x28, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x48, 0x00, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x31, 0x0a,
+	0x07, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x29, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x75,
+	0x62, 0x6e, 0x65, 0x74, 0x73, 0x48, 0x00, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73,
+	0x12, 0x34, 0x0a, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x49, 0x50, 0x18, 0x2a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x49, 0x50, 0x48, 0x00, 0x52, 0x08, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x49, 0x50, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0x2c, 0x0a, 0x06, 0x49, 0x50, 0x44, 0x65, 0x73, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22,
+	0x78, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x65, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x63, 0x65, 0x72,
+	0x74, 0x12, 0x24, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x50, 0x44,
+	0x65, 0x73, 0x63, 0x52, 0x02, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x0c, 0x0a, 0x0a, 0x47, 0x65, 0x74,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x83, 0x02, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49,
+	0x44, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x79, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6d, 0x79, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x24, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x50, 0x44,
+	0x65, 0x73, 0x63, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x69, 0x67,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x73, 0x69, 0x67,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64,
+	0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0e, 0x74,
+	0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x22, 0x0d, 0x0a,
+	0x0b, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x46, 0x0a, 0x08,
+	0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x52, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50,
+	0x65, 0x65, 0x72, 0x73, 0x22, 0x06, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x22, 0x1e, 0x0a, 0x04,
+	0x50, 0x6f, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x6a, 0x0a, 0x04,
+	0x49, 0x50, 0x76, 0x36, 0x12, 0x24, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x49, 0x50, 0x44, 0x65, 0x73, 0x63, 0x52, 0x02, 0x69, 0x70, 0x12, 0x20, 0x0a, 0x0b, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x69, 0x67, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
+	0x73, 0x69, 0x67, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x38, 0x0a, 0x08, 0x46, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x11, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x73, 0x22, 0x31, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x12, 0x26, 0x0a,
+	0x0e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x53, 0x75,
+	0x62, 0x6e, 0x65, 0x74, 0x73, 0x22, 0x6e, 0x0a, 0x08, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x49,
+	0x50, 0x12, 0x24, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x50, 0x44,
+	0x65, 0x73, 0x63, 0x52, 0x02, 0x69, 0x70, 0x12, 0x20, 0x0a, 0x0b, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x69, 0x67,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x73, 0x69, 0x67,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x69, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65,
+	0x22, 0x6e, 0x0a, 0x10, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6e,
+	0x74, 0x69, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x73,
+	0x22, 0x85, 0x01, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64,
+	0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64,
+	0x6c, 0x69, 0x6e, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x73, 0x22, 0x66, 0x0a, 0x08, 0x41, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x73,
+	0x22, 0x84, 0x01, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x41, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x75, 0x0a, 0x09, 0x41, 0x6e, 0x63, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x30, 0x0a, 0x13,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x13, 0x6d, 0x75, 0x6c, 0x74, 0x69,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x7b,
+	0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a,
+	0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x87, 0x01, 0x0a, 0x03,
+	0x50, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x63,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x26, 0x0a,
+	0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x09, 0x50, 0x75, 0x73, 0x68, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x22, 0x81, 0x01, 0x0a, 0x09, 0x50, 0x75, 0x6c, 0x6c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69,
+	0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x63, 0x0a, 0x05, 0x43, 0x68, 0x69, 0x74, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69,
+	0x6e, 0x65, 0x72, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x63, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x73, 0x22, 0x7c, 0x0a, 0x0a, 0x41, 0x70,
+	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44,
+	0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x61, 0x70, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
+	0x61, 0x70, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x61, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49,
+	0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12,
+	0x1a, 0x0a, 0x08, 0x61, 0x70, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x61, 0x70, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x41, 0x0a, 0x09, 0x41,
+	0x70, 0x70, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x70, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x61, 0x70, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x3d,
+	0x5a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x54, 0x6f, 0x69,
+	0x6e, 0x6f, 0x75, 0x6e, 0x65, 0x74, 0x32, 0x31, 0x2f, 0x61, 0x76, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x68, 0x65, 0x67, 0x6f, 0x2d, 0x6d, 0x6f, 0x64, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_message_proto_rawDescOnce sync.Once
+	file_message_proto_rawDescData = file_message_proto_rawDesc
+)
+
+func file_message_proto_rawDescGZIP() []byte {
+	file_message_proto_rawDescOnce.Do(func() {
+		file_message_proto_rawDescData = protoimpl.X.CompressGZIP(file_message_proto_rawDescData)
+	})
+	return file_message_proto_rawDescData
+}
+
+var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_message_proto_goTypes = []interface{}{
+	(*Message)(nil),             // 0: messageproto.Message
+	(*IPDesc)(nil),              // 1: messageproto.IPDesc
+	(*SignedPeer)(nil),          // 2: messageproto.SignedPeer
+	(*GetVersion)(nil),          // 3: messageproto.GetVersion
+	(*Version)(nil),             // 4: messageproto.Version
+	(*GetPeerList)(nil),         // 5: messageproto.GetPeerList
+	(*PeerList)(nil),            // 6: messageproto.PeerList
+	(*Ping)(nil),                // 7: messageproto.Ping
+	(*Pong)(nil),                // 8: messageproto.Pong
+	(*IPv6)(nil),                // 9: messageproto.IPv6
+	(*Features)(nil),            // 10: messageproto.Features
+	(*Subnets)(nil),             // 11: messageproto.Subnets
+	(*SignedIP)(nil),            // 12: messageproto.SignedIP
+	(*GetAcceptedFrontier)(nil), // 13: messageproto.GetAcceptedFrontier
+	(*AcceptedFrontier)(nil),    // 14: messageproto.AcceptedFrontier
+	(*GetAccepted)(nil),         // 15: messageproto.GetAccepted
+	(*Accepted)(nil),            // 16: messageproto.Accepted
+	(*GetAncestors)(nil),        // 17: messageproto.GetAncestors
+	(*Ancestors)(nil),           // 18: messageproto.Ancestors
+	(*Get)(nil),                 // 19: messageproto.Get
+	(*Put)(nil),                 // 20: messageproto.Put
+	(*PushQuery)(nil),           // 21: messageproto.PushQuery
+	(*PullQuery)(nil),           // 22: messageproto.PullQuery
+	(*Chits)(nil),               // 23: messageproto.Chits
+	(*AppRequest)(nil),          // 24: messageproto.AppRequest
+	(*AppResponse)(nil),         // 25: messageproto.AppResponse
+	(*AppGossip)(nil),           // 26: messageproto.AppGossip
+}
+var file_message_proto_depIdxs = []int32{
+	3,  // 0: messageproto.Message.getVersion:type_name -> messageproto.GetVersion
+	5,  // 1: messageproto.Message.getPeerList:type_name -> messageproto.GetPeerList
+	8,  // 2: messageproto.Message.pong:type_name -> messageproto.Pong
+	7,  // 3: messageproto.Message.ping:type_name -> messageproto.Ping
+	13, // 4: messageproto.Message.getAcceptedFrontier:type_name -> messageproto.GetAcceptedFrontier
+	14, // 5: messageproto.Message.acceptedFrontier:type_name -> messageproto.AcceptedFrontier
+	15, // 6: messageproto.Message.getAccepted:type_name -> messageproto.GetAccepted
+	16, // 7: messageproto.Message.accepted:type_name -> messageproto.Accepted
+	17, // 8: messageproto.Message.getAncestors:type_name -> messageproto.GetAncestors
+	18, // 9: messageproto.Message.ancestors:type_name -> messageproto.Ancestors
+	19, // 10: messageproto.Message.get:type_name -> messageproto.Get
+	20, // 11: messageproto.Message.put:type_name -> messageproto.Put
+	21, // 12: messageproto.Message.pushQuery:type_name -> messageproto.PushQuery
+	22, // 13: messageproto.Message.pullQuery:type_name -> messageproto.PullQuery
+	23, // 14: messageproto.Message.chits:type_name -> messageproto.Chits
+	6,  // 15: messageproto.Message.peerList:type_name -> messageproto.PeerList
+	4,  // 16: messageproto.Message.version:type_name -> messageproto.Version
+	24, // 17: messageproto.Message.appRequest:type_name -> messageproto.AppRequest
+	25, // 18: messageproto.Message.appResponse:type_name -> messageproto.AppResponse
+	26, // 19: messageproto.Message.appGossip:type_name -> messageproto.AppGossip
+	9,  // 20: messageproto.Message.ipv6:type_name -> messageproto.IPv6
+	10, // 21: messageproto.Message.features:type_name -> messageproto.Features
+	11, // 22: messageproto.Message.subnets:type_name -> messageproto.Subnets
+	12, // 23: messageproto.Message.signedIP:type_name -> messageproto.SignedIP
+	1,  // 24: messageproto.SignedPeer.ip:type_name -> messageproto.IPDesc
+	1,  // 25: messageproto.Version.ip:type_name -> messageproto.IPDesc
+	2,  // 26: messageproto.PeerList.signedPeers:type_name -> messageproto.SignedPeer
+	1,  // 27: messageproto.IPv6.ip:type_name -> messageproto.IPDesc
+	1,  // 28: messageproto.SignedIP.ip:type_name -> messageproto.IPDesc
+	29, // [29:29] is the sub-list for method output_type
+	29, // [29:29] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
+}
+
+func init() { file_message_proto_init() }
+func file_message_proto_init() {
+	if File_message_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_message_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPDesc); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignedPeer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Version); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pong); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPv6); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Features); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subnets); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignedIP); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAcceptedFrontier); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceptedFrontier); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAccepted); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Accepted); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAncestors); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ancestors); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Get); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Put); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PullQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Chits); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppGossip); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_message_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Message_CompressedGzip)(nil),
+		(*Message_CompressedZstd)(nil),
+		(*Message_GetVersion)(nil),
+		(*Message_GetPeerList)(nil),
+		(*Message_Pong)(nil),
+		(*Message_Ping)(nil),
+		(*Message_GetAcceptedFrontier)(nil),
+		(*Message_AcceptedFrontier_)(nil),
+		(*Message_GetAccepted)(nil),
+		(*Message_Accepted_)(nil),
+		(*Message_GetAncestors)(nil),
+		(*Message_Ancestors_)(nil),
+		(*Message_Get)(nil),
+		(*Message_Put)(nil),
+		(*Message_PushQuery)(nil),
+		(*Message_PullQuery)(nil),
+		(*Message_Chits)(nil),
+		(*Message_PeerList_)(nil),
+		(*Message_Version_)(nil),
+		(*Message_AppRequest)(nil),
+		(*Message_AppResponse)(nil),
+		(*Message_AppGossip)(nil),
+		(*Message_Ipv6)(nil),
+		(*Message_Features)(nil),
+		(*Message_Subnets)(nil),
+		(*Message_SignedIP)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_message_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_message_proto_goTypes,
+		DependencyIndexes: file_message_proto_depIdxs,
+		MessageInfos:      file_message_proto_msgTypes,
+	}.Build()
+	File_message_proto = out.File
+	file_message_proto_rawDesc = nil
+	file_message_proto_goTypes = nil
+	file_message_proto_depIdxs = nil
+}
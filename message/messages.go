@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 )
 
@@ -98,6 +99,13 @@ type OutboundMessage interface {
 	BytesSavedCompression() int
 	Bytes() []byte
 	Op() Op
+	CompressionType() compression.Type
+	// HasExtensions returns true if this message has an envelope with
+	// optional fields, which peers that predate envelopes can't parse
+	HasExtensions() bool
+	// IsProto returns true if this message was packed with the protobuf
+	// schema, which peers that predate it can't parse
+	IsProto() bool
 
 	AddRef()
 	DecRef()
@@ -106,7 +114,13 @@ type OutboundMessage interface {
 type outboundMessage struct {
 	bytes                 []byte
 	bytesSavedCompression int
+	compressionType       compression.Type
 	op                    Op
+	// Offset of the envelope in the uncompressed message. 0 if the message
+	// doesn't have an envelope.
+	extensionsOffset int
+	// True if the message was packed with the protobuf schema
+	proto bool
 
 	refLock sync.Mutex
 	refs    int
@@ -125,6 +139,17 @@ func (outMsg *outboundMessage) Bytes() []byte { return outMsg.bytes }
 // compressed.
 func (outMsg *outboundMessage) BytesSavedCompression() int { return outMsg.bytesSavedCompression }
 
+// CompressionType returns the type of compression that was applied to this
+// message's payload.
+func (outMsg *outboundMessage) CompressionType() compression.Type { return outMsg.compressionType }
+
+// HasExtensions returns true if this message has an envelope with optional
+// fields
+func (outMsg *outboundMessage) HasExtensions() bool { return outMsg.extensionsOffset != 0 }
+
+// IsProto returns true if this message was packed with the protobuf schema
+func (outMsg *outboundMessage) IsProto() bool { return outMsg.proto }
+
 func (outMsg *outboundMessage) AddRef() {
 	outMsg.refLock.Lock()
 	defer outMsg.refLock.Unlock()
@@ -7,8 +7,8 @@ package message
 type Op byte
 
 // Types of messages that may be sent between nodes
-// Note: If you add a new parseable Op below, you must also add it to ops
-// (declared below)
+// Note: If you add a new parseable Op below, you must also add it to the
+// protobuf schema in messageproto/message.proto, with field number 16 + Op
 const (
 	// Handshake:
 	GetVersion Op = iota
@@ -38,6 +38,11 @@ const (
 	AppRequest
 	AppResponse
 	AppGossip
+	// Handshake:
+	IPv6
+	Features
+	Subnets
+	SignedIP
 
 	// Internal messages (External messages should be added above these):
 	GetAcceptedFrontierFailed
@@ -61,6 +66,10 @@ var (
 		PeerList,
 		Ping,
 		Pong,
+		IPv6,
+		Features,
+		Subnets,
+		SignedIP,
 	}
 
 	// List of all consensus request message types
@@ -143,33 +152,29 @@ var (
 		AppGossip:           {},
 	}
 
-	// Defines the messages that can be sent/received with this network
-	messages = map[Op][]Field{
-		// Handshake:
-		GetVersion:  {},
-		Version:     {NetworkID, NodeID, MyTime, IP, VersionStr, VersionTime, SigBytes, TrackedSubnets},
-		GetPeerList: {},
-		PeerList:    {SignedPeers},
-		Ping:        {},
-		Pong:        {Uptime},
-		// Bootstrapping:
-		GetAcceptedFrontier: {ChainID, RequestID, Deadline},
-		AcceptedFrontier:    {ChainID, RequestID, ContainerIDs},
-		GetAccepted:         {ChainID, RequestID, Deadline, ContainerIDs},
-		Accepted:            {ChainID, RequestID, ContainerIDs},
-		GetAncestors:        {ChainID, RequestID, Deadline, ContainerID},
-		Ancestors:           {ChainID, RequestID, MultiContainerBytes},
-		// Consensus:
-		Get:       {ChainID, RequestID, Deadline, ContainerID},
-		Put:       {ChainID, RequestID, ContainerID, ContainerBytes},
-		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
-		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
-		Chits:     {ChainID, RequestID, ContainerIDs},
-		// Application level:
-		AppRequest:  {ChainID, RequestID, Deadline, AppBytes},
-		AppResponse: {ChainID, RequestID, AppBytes},
-		AppGossip:   {ChainID, AppBytes},
+	// PriorityOps are sent to a peer before any other queued message, so
+	// that the handshake and consensus polls aren't delayed behind bulk
+	// transfers such as Ancestors or AppGossip
+	PriorityOps = map[Op]struct{}{
+		GetVersion: {},
+		Version:    {},
+		Ping:       {},
+		Pong:       {},
+		PushQuery:  {},
+		PullQuery:  {},
+		Chits:      {},
 	}
+
+	// Defines the messages that can be sent/received with this network, and
+	// the order the legacy codec packs their fields in. Derived from the
+	// protobuf schema in messageproto, so that both encodings always agree.
+	messages = schemaMessages()
+
+	// Defines the optional fields that may be packed in the envelope of each
+	// message, after its required fields. The position of a field in the list
+	// is its tag on the wire, so fields must only be appended. Optional fields
+	// that a message doesn't contain are nil when it's parsed.
+	extensions = map[Op][]Field{}
 )
 
 func (op Op) Compressable() bool {
@@ -195,6 +200,14 @@ func (op Op) String() string {
 		return "ping"
 	case Pong:
 		return "pong"
+	case IPv6:
+		return "ipv6"
+	case Features:
+		return "features"
+	case Subnets:
+		return "subnets"
+	case SignedIP:
+		return "signed_ip"
 	case GetAcceptedFrontier:
 		return "get_accepted_frontier"
 	case AcceptedFrontier:
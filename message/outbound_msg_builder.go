@@ -8,6 +8,7 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 )
 
 var _ OutboundMsgBuilder = &outMsgBuilder{}
@@ -37,6 +38,22 @@ type OutboundMsgBuilder interface {
 
 	Pong(uptimePercentage uint8) (OutboundMessage, error)
 
+	IPv6(
+		ip utils.IPDesc,
+		myVersionTime uint64,
+		sig []byte,
+	) (OutboundMessage, error)
+
+	Features(supportedFeatures uint64) (OutboundMessage, error)
+
+	Subnets(trackedSubnets []ids.ID) (OutboundMessage, error)
+
+	SignedIP(
+		ip utils.IPDesc,
+		myVersionTime uint64,
+		sig []byte,
+	) (OutboundMessage, error)
+
 	GetAcceptedFrontier(
 		chainID ids.ID,
 		requestID uint32,
@@ -127,17 +144,35 @@ type OutboundMsgBuilder interface {
 		chainID ids.ID,
 		msg []byte,
 	) (OutboundMessage, error)
+
+	// Recompress returns [msg] compressed using [compressionType]. This allows
+	// sending messages to peers that don't support the default compression
+	// type.
+	Recompress(
+		msg OutboundMessage,
+		compressionType compression.Type,
+	) (OutboundMessage, error)
+
+	// StripExtensions returns [msg] without its optional fields. This allows
+	// sending messages to peers that predate message envelopes.
+	StripExtensions(msg OutboundMessage) (OutboundMessage, error)
+
+	// ToLegacy returns [msg] packed with the legacy codec. This allows
+	// sending messages to peers that predate the protobuf schema.
+	ToLegacy(msg OutboundMessage) (OutboundMessage, error)
 }
 
 type outMsgBuilder struct {
-	c        Codec
-	compress bool
+	c               Codec
+	compressionType compression.Type
 }
 
-func NewOutboundBuilder(c Codec, enableCompression bool) OutboundMsgBuilder {
+// NewOutboundBuilder returns a builder that compresses compressable messages
+// using [compressionType].
+func NewOutboundBuilder(c Codec, compressionType compression.Type) OutboundMsgBuilder {
 	return &outMsgBuilder{
-		c:        c,
-		compress: enableCompression,
+		c:               c,
+		compressionType: compressionType,
 	}
 }
 
@@ -145,7 +180,7 @@ func (b *outMsgBuilder) GetVersion() (OutboundMessage, error) {
 	return b.c.Pack(
 		GetVersion,
 		nil,
-		GetVersion.Compressable(), // GetVersion messages can't be compressed
+		compression.TypeNone, // GetVersion messages can't be compressed
 	)
 }
 
@@ -176,7 +211,7 @@ func (b *outMsgBuilder) Version(
 			SigBytes:       sig,
 			TrackedSubnets: subnetIDBytes,
 		},
-		Version.Compressable(), // Version Messages can't be compressed
+		compression.TypeNone, // Version Messages can't be compressed
 	)
 }
 
@@ -184,7 +219,7 @@ func (b *outMsgBuilder) GetPeerList() (OutboundMessage, error) {
 	return b.c.Pack(
 		GetPeerList,
 		nil,
-		GetPeerList.Compressable(), // GetPeerList messages can't be compressed
+		compression.TypeNone, // GetPeerList messages can't be compressed
 	)
 }
 
@@ -194,7 +229,7 @@ func (b *outMsgBuilder) PeerList(peers []utils.IPCertDesc) (OutboundMessage, err
 		map[Field]interface{}{
 			SignedPeers: peers,
 		},
-		b.compress && PeerList.Compressable(), // PeerList messages may be compressed
+		b.compressionType, // PeerList messages may be compressed
 	)
 }
 
@@ -202,7 +237,7 @@ func (b *outMsgBuilder) Ping() (OutboundMessage, error) {
 	return b.c.Pack(
 		Ping,
 		nil,
-		Ping.Compressable(), // Ping messages can't be compressed
+		compression.TypeNone, // Ping messages can't be compressed
 	)
 }
 
@@ -212,7 +247,64 @@ func (b *outMsgBuilder) Pong(uptimePercentage uint8) (OutboundMessage, error) {
 		map[Field]interface{}{
 			Uptime: uptimePercentage,
 		},
-		Pong.Compressable(), // Pong messages can't be compressed
+		compression.TypeNone, // Pong messages can't be compressed
+	)
+}
+
+func (b *outMsgBuilder) IPv6(
+	ip utils.IPDesc,
+	myVersionTime uint64,
+	sig []byte,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		IPv6,
+		map[Field]interface{}{
+			IP:          ip,
+			VersionTime: myVersionTime,
+			SigBytes:    sig,
+		},
+		compression.TypeNone, // IPv6 messages can't be compressed
+	)
+}
+
+func (b *outMsgBuilder) Features(supportedFeatures uint64) (OutboundMessage, error) {
+	return b.c.Pack(
+		Features,
+		map[Field]interface{}{
+			SupportedFeatures: supportedFeatures,
+		},
+		compression.TypeNone, // Features messages can't be compressed
+	)
+}
+
+func (b *outMsgBuilder) Subnets(trackedSubnets []ids.ID) (OutboundMessage, error) {
+	subnetIDBytes := make([][]byte, len(trackedSubnets))
+	for i, subnetID := range trackedSubnets {
+		copy := subnetID
+		subnetIDBytes[i] = copy[:]
+	}
+	return b.c.Pack(
+		Subnets,
+		map[Field]interface{}{
+			TrackedSubnets: subnetIDBytes,
+		},
+		compression.TypeNone, // Subnets messages can't be compressed
+	)
+}
+
+func (b *outMsgBuilder) SignedIP(
+	ip utils.IPDesc,
+	myVersionTime uint64,
+	sig []byte,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		SignedIP,
+		map[Field]interface{}{
+			IP:          ip,
+			VersionTime: myVersionTime,
+			SigBytes:    sig,
+		},
+		compression.TypeNone, // SignedIP messages can't be compressed
 	)
 }
 
@@ -228,7 +320,7 @@ func (b *outMsgBuilder) GetAcceptedFrontier(
 			RequestID: requestID,
 			Deadline:  uint64(deadline),
 		},
-		GetAcceptedFrontier.Compressable(), // GetAcceptedFrontier messages can't be compressed
+		compression.TypeNone, // GetAcceptedFrontier messages can't be compressed
 	)
 }
 
@@ -249,7 +341,7 @@ func (b *outMsgBuilder) AcceptedFrontier(
 			RequestID:    requestID,
 			ContainerIDs: containerIDBytes,
 		},
-		AcceptedFrontier.Compressable(), // AcceptedFrontier messages can't be compressed
+		compression.TypeNone, // AcceptedFrontier messages can't be compressed
 	)
 }
 
@@ -272,7 +364,7 @@ func (b *outMsgBuilder) GetAccepted(
 			Deadline:     uint64(deadline),
 			ContainerIDs: containerIDBytes,
 		},
-		GetAccepted.Compressable(), // GetAccepted messages can't be compressed
+		compression.TypeNone, // GetAccepted messages can't be compressed
 	)
 }
 
@@ -293,7 +385,7 @@ func (b *outMsgBuilder) Accepted(
 			RequestID:    requestID,
 			ContainerIDs: containerIDBytes,
 		},
-		Accepted.Compressable(), // Accepted messages can't be compressed
+		compression.TypeNone, // Accepted messages can't be compressed
 	)
 }
 
@@ -311,7 +403,7 @@ func (b *outMsgBuilder) GetAncestors(
 			Deadline:    uint64(deadline),
 			ContainerID: containerID[:],
 		},
-		GetAncestors.Compressable(), // GetAncestors messages can't be compressed
+		compression.TypeNone, // GetAncestors messages can't be compressed
 	)
 }
 
@@ -327,7 +419,7 @@ func (b *outMsgBuilder) Ancestors(
 			RequestID:           requestID,
 			MultiContainerBytes: containers,
 		},
-		b.compress && Ancestors.Compressable(), // Ancestors messages may be compressed
+		b.compressionType, // Ancestors messages may be compressed
 	)
 }
 
@@ -345,7 +437,7 @@ func (b *outMsgBuilder) Get(
 			Deadline:    uint64(deadline),
 			ContainerID: containerID[:],
 		},
-		Get.Compressable(), // Get messages can't be compressed
+		compression.TypeNone, // Get messages can't be compressed
 	)
 }
 
@@ -363,7 +455,7 @@ func (b *outMsgBuilder) Put(
 			ContainerID:    containerID[:],
 			ContainerBytes: container,
 		},
-		b.compress && Put.Compressable(), // Put messages may be compressed
+		b.compressionType, // Put messages may be compressed
 	)
 }
 
@@ -383,7 +475,7 @@ func (b *outMsgBuilder) PushQuery(
 			ContainerID:    containerID[:],
 			ContainerBytes: container,
 		},
-		b.compress && PushQuery.Compressable(), // PushQuery messages may be compressed
+		b.compressionType, // PushQuery messages may be compressed
 	)
 }
 
@@ -401,7 +493,7 @@ func (b *outMsgBuilder) PullQuery(
 			Deadline:    uint64(deadline),
 			ContainerID: containerID[:],
 		},
-		PullQuery.Compressable(), // PullQuery messages can't be compressed
+		compression.TypeNone, // PullQuery messages can't be compressed
 	)
 }
 
@@ -422,7 +514,7 @@ func (b *outMsgBuilder) Chits(
 			RequestID:    requestID,
 			ContainerIDs: containerIDBytes,
 		},
-		Chits.Compressable(), // Chits messages can't be compressed
+		compression.TypeNone, // Chits messages can't be compressed
 	)
 }
 
@@ -441,7 +533,7 @@ func (b *outMsgBuilder) AppRequest(
 			Deadline:  uint64(deadline),
 			AppBytes:  msg,
 		},
-		b.compress && AppRequest.Compressable(), // App messages may be compressed
+		b.compressionType, // App messages may be compressed
 	)
 }
 
@@ -454,7 +546,7 @@ func (b *outMsgBuilder) AppResponse(chainID ids.ID, requestID uint32, msg []byte
 			RequestID: requestID,
 			AppBytes:  msg,
 		},
-		b.compress && AppResponse.Compressable(), // App messages may be compressed
+		b.compressionType, // App messages may be compressed
 	)
 }
 
@@ -466,6 +558,21 @@ func (b *outMsgBuilder) AppGossip(chainID ids.ID, msg []byte) (OutboundMessage,
 			ChainID:  chainID[:],
 			AppBytes: msg,
 		},
-		b.compress && AppGossip.Compressable(), // App messages may be compressed
+		b.compressionType, // App messages may be compressed
 	)
 }
+
+func (b *outMsgBuilder) Recompress(
+	msg OutboundMessage,
+	compressionType compression.Type,
+) (OutboundMessage, error) {
+	return b.c.Recompress(msg, compressionType)
+}
+
+func (b *outMsgBuilder) StripExtensions(msg OutboundMessage) (OutboundMessage, error) {
+	return b.c.StripExtensions(msg)
+}
+
+func (b *outMsgBuilder) ToLegacy(msg OutboundMessage) (OutboundMessage, error) {
+	return b.c.ToLegacy(msg)
+}
@@ -0,0 +1,278 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message/messageproto"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
+)
+
+// protoMessagePrefix is the smallest first byte of a message packed with the
+// protobuf schema. Since every field of messageproto.Message is numbered 16 or
+// more, its tag is at least two bytes long, so its first byte has the high bit
+// set, which no legacy op code has.
+const protoMessagePrefix = 0x80
+
+var (
+	errNestedCompression = errors.New("compressed message contains a compressed message")
+	errBadCompression    = errors.New("message type may not be compressed")
+
+	// Describes the message oneof of messageproto.Message
+	messageOneof = messageDescriptor.Oneofs().ByName("message")
+
+	// Maps compression types to the fields of messageproto.Message that hold
+	// messages compressed with them
+	protoCompressedFields = map[compression.Type]protoreflect.FieldDescriptor{
+		compression.TypeGzip: messageDescriptor.Fields().ByName("compressedGzip"),
+		compression.TypeZstd: messageDescriptor.Fields().ByName("compressedZstd"),
+	}
+
+	marshalOptions = proto.MarshalOptions{Deterministic: true}
+)
+
+// NewProtoCodecWithMemoryPool returns a Codec that packs messages with the
+// protobuf schema in messageproto. Like every Codec, it parses messages packed
+// with either the protobuf schema or the legacy codec, and it converts its
+// messages to the legacy codec with ToLegacy, for peers that can't parse the
+// protobuf schema.
+func NewProtoCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64) (Codec, error) {
+	return newProtoCodecWithMemoryPool(namespace, metrics, maxMessageSize, 0)
+}
+
+func newProtoCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64, compressionThreshold int) (*codec, error) {
+	c, err := newCodecWithMemoryPool(namespace, metrics, maxMessageSize, compressionThreshold)
+	if err != nil {
+		return nil, err
+	}
+	c.proto = true
+	return c, nil
+}
+
+// packProto packs [fieldValues] into an [op] message of the protobuf schema.
+// If [op] messages may be compressed, and the message is at least as large as
+// the codec's compression threshold, it's compressed using [compressionType] and
+// wrapped in another message.
+func (c *codec) packProto(
+	op Op,
+	fieldValues map[Field]interface{},
+	compressionType compression.Type,
+) (OutboundMessage, error) {
+	msgFD := schemaOpField(op)
+	if msgFD == nil || msgFD.Kind() != protoreflect.MessageKind {
+		return nil, errBadOp
+	}
+
+	msg := (&messageproto.Message{}).ProtoReflect()
+	payload := msg.NewField(msgFD).Message()
+	fds := payload.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		field := schemaFields[fd.Name()]
+		value, ok := fieldValues[field]
+		if !ok {
+			return nil, errMissingField
+		}
+		if err := setSchemaField(payload, fd, field, value); err != nil {
+			return nil, fmt.Errorf("couldn't pack %s of %s message: %w", field, op, err)
+		}
+	}
+	msg.Set(msgFD, protoreflect.ValueOfMessage(payload))
+
+	buffer := c.byteSlicePool.Get().([]byte)
+	bytes, err := marshalOptions.MarshalAppend(buffer[:0], msg.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return c.compressProto(op, bytes, compressionType)
+}
+
+// compressProto returns an [op] message whose uncompressed encoding is [bytes],
+// compressed using [compressionType] if [op] messages may be compressed and
+// [bytes] is at least as large as the compression threshold.
+func (c *codec) compressProto(op Op, bytes []byte, compressionType compression.Type) (*outboundMessage, error) {
+	msg := &outboundMessage{
+		op:              op,
+		bytes:           bytes,
+		compressionType: compression.TypeNone,
+		proto:           true,
+		refs:            1,
+		c:               c,
+	}
+	if !op.Compressable() || compressionType == compression.TypeNone || len(bytes) < c.compressionThreshold {
+		return msg, nil
+	}
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownCompressionType, compressionType)
+	}
+
+	startTime := time.Now()
+	compressedBytes, err := compressor.Compress(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't compress %s message: %w", op, err)
+	}
+	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+
+	wrapper := (&messageproto.Message{}).ProtoReflect()
+	wrapper.Set(protoCompressedFields[compressionType], protoreflect.ValueOfBytes(compressedBytes))
+	msg.bytes, err = marshalOptions.MarshalAppend(bytes[:0], wrapper.Interface())
+	if err != nil {
+		return nil, err
+	}
+	msg.bytesSavedCompression = len(bytes) - len(msg.bytes) // may be negative
+	msg.compressionType = compressionType
+	return msg, nil
+}
+
+// recompressProto returns a new message with the same contents as [msg], which
+// was packed with the protobuf schema, compressed using [compressionType].
+func (c *codec) recompressProto(msg OutboundMessage, compressionType compression.Type) (OutboundMessage, error) {
+	bytes := msg.Bytes()
+	if msg.CompressionType() != compression.TypeNone {
+		wrapper := &messageproto.Message{}
+		if err := proto.Unmarshal(bytes, wrapper); err != nil {
+			return nil, err
+		}
+		var err error
+		bytes, _, _, err = c.decompressProto(wrapper.ProtoReflect())
+		if err != nil {
+			return nil, err
+		}
+	}
+	buffer := c.byteSlicePool.Get().([]byte)
+	return c.compressProto(msg.Op(), append(buffer[:0], bytes...), compressionType)
+}
+
+// ToLegacy returns a new message with the same contents as [msg], packed with
+// the legacy codec.
+// Peers that can't parse the protobuf schema may not parse zstd either, so if
+// [msg] is compressed, the returned message is compressed with gzip.
+// If [msg] was packed with the legacy codec, a new reference to [msg] is
+// returned.
+func (c *codec) ToLegacy(msg OutboundMessage) (OutboundMessage, error) {
+	if !msg.IsProto() {
+		msg.AddRef()
+		return msg, nil
+	}
+
+	op, fieldValues, _, err := c.unmarshalProto(msg.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	compressionType := compression.TypeNone
+	if msg.CompressionType() != compression.TypeNone {
+		compressionType = compression.TypeGzip
+	}
+	return c.packLegacy(op, fieldValues, compressionType)
+}
+
+// parseProto parses [bytes], a message packed with the protobuf schema
+func (c *codec) parseProto(bytes []byte, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error) {
+	op, fieldValues, bytesSaved, err := c.unmarshalProto(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var expirationTime time.Time
+	if deadline, hasDeadline := fieldValues[Deadline]; hasDeadline {
+		expirationTime = c.clock.Time().Add(time.Duration(deadline.(uint64)))
+	}
+
+	return &inboundMessage{
+		op:                    op,
+		fields:                fieldValues,
+		bytesSavedCompression: bytesSaved,
+		nodeID:                nodeID,
+		expirationTime:        expirationTime,
+		onFinishedHandling:    onFinishedHandling,
+	}, nil
+}
+
+// unmarshalProto returns the op and the fields of [bytes], a message packed
+// with the protobuf schema, and the number of bytes its compression saved.
+func (c *codec) unmarshalProto(bytes []byte) (Op, map[Field]interface{}, int, error) {
+	msg := &messageproto.Message{}
+	if err := proto.Unmarshal(bytes, msg); err != nil {
+		return 0, nil, 0, err
+	}
+
+	// If the message is compressed, decompress it
+	m := msg.ProtoReflect()
+	uncompressedBytes, compressionType, decompressTime, err := c.decompressProto(m)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	if compressionType != compression.TypeNone {
+		msg = &messageproto.Message{}
+		if err := proto.Unmarshal(uncompressedBytes, msg); err != nil {
+			return 0, nil, 0, err
+		}
+		m = msg.ProtoReflect()
+	}
+
+	msgFD := m.WhichOneof(messageOneof)
+	if msgFD == nil { // Unknown message type
+		return 0, nil, 0, errBadOp
+	}
+	if msgFD.Kind() != protoreflect.MessageKind {
+		return 0, nil, 0, errNestedCompression
+	}
+	op := Op(msgFD.Number() - opFieldNumberOffset)
+
+	bytesSaved := 0
+	if compressionType != compression.TypeNone {
+		if !op.Compressable() {
+			return 0, nil, 0, fmt.Errorf("%w: %s", errBadCompression, op)
+		}
+		c.decompressTimeMetrics[op].Observe(float64(decompressTime))
+		bytesSaved = len(uncompressedBytes) - len(bytes)
+	}
+
+	payload := m.Get(msgFD).Message()
+	fds := payload.Descriptor().Fields()
+	fieldValues := make(map[Field]interface{}, fds.Len())
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		field := schemaFields[fd.Name()]
+		value, err := getSchemaField(payload, fd, field)
+		if err != nil {
+			return 0, nil, 0, fmt.Errorf("couldn't parse %s of %s message: %w", field, op, err)
+		}
+		fieldValues[field] = value
+	}
+	return op, fieldValues, bytesSaved, nil
+}
+
+// decompressProto returns the uncompressed encoding of the message wrapped in
+// [m], the type of compression it was compressed with, and how long
+// decompressing it took. If [m] doesn't wrap a compressed message, the
+// compression type is TypeNone.
+func (c *codec) decompressProto(m protoreflect.Message) ([]byte, compression.Type, time.Duration, error) {
+	for compressionType, fd := range protoCompressedFields {
+		if !m.Has(fd) {
+			continue
+		}
+		compressor, ok := c.compressors[compressionType]
+		if !ok {
+			return nil, compression.TypeNone, 0, fmt.Errorf("%w: %s", errUnknownCompressionType, compressionType)
+		}
+		startTime := time.Now()
+		uncompressedBytes, err := compressor.Decompress(m.Get(fd).Bytes())
+		if err != nil {
+			return nil, compression.TypeNone, 0, fmt.Errorf("couldn't decompress message: %w", err)
+		}
+		return uncompressedBytes, compressionType, time.Since(startTime), nil
+	}
+	return nil, compression.TypeNone, 0, nil
+}
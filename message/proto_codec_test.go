@@ -0,0 +1,261 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message/messageproto"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+// The wire format of the legacy codec, which must not change when the schema
+// does
+var legacyMessages = map[Op][]Field{
+	GetVersion:          {},
+	Version:             {NetworkID, NodeID, MyTime, IP, VersionStr, VersionTime, SigBytes, TrackedSubnets},
+	GetPeerList:         {},
+	PeerList:            {SignedPeers},
+	Ping:                {},
+	Pong:                {Uptime},
+	IPv6:                {IP, VersionTime, SigBytes},
+	Features:            {SupportedFeatures},
+	Subnets:             {TrackedSubnets},
+	SignedIP:            {IP, VersionTime, SigBytes},
+	GetAcceptedFrontier: {ChainID, RequestID, Deadline},
+	AcceptedFrontier:    {ChainID, RequestID, ContainerIDs},
+	GetAccepted:         {ChainID, RequestID, Deadline, ContainerIDs},
+	Accepted:            {ChainID, RequestID, ContainerIDs},
+	GetAncestors:        {ChainID, RequestID, Deadline, ContainerID},
+	Ancestors:           {ChainID, RequestID, MultiContainerBytes},
+	Get:                 {ChainID, RequestID, Deadline, ContainerID},
+	Put:                 {ChainID, RequestID, ContainerID, ContainerBytes},
+	PushQuery:           {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
+	PullQuery:           {ChainID, RequestID, Deadline, ContainerID},
+	Chits:               {ChainID, RequestID, ContainerIDs},
+	AppRequest:          {ChainID, RequestID, Deadline, AppBytes},
+	AppResponse:         {ChainID, RequestID, AppBytes},
+	AppGossip:           {ChainID, AppBytes},
+}
+
+func TestSchemaMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(legacyMessages, messages)
+	assert.Len(messages, len(ExternalOps))
+	for _, op := range ExternalOps {
+		fd := schemaOpField(op)
+		if !assert.NotNil(fd, "%s isn't in the schema", op) {
+			continue
+		}
+		assert.Equal(
+			strings.ReplaceAll(op.String(), "_", ""),
+			strings.ToLower(string(fd.Name())),
+		)
+	}
+}
+
+func testProtoMessages() []inboundMessage {
+	id := ids.GenerateTestID()
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	now := uint64(time.Now().Unix())
+	return []inboundMessage{
+		{
+			op:     GetVersion,
+			fields: map[Field]interface{}{},
+		},
+		{
+			op: Version,
+			fields: map[Field]interface{}{
+				NetworkID:      uint32(1),
+				NodeID:         uint32(1337),
+				MyTime:         now,
+				IP:             ip,
+				VersionStr:     "v1.2.3",
+				VersionTime:    now,
+				SigBytes:       []byte{'y', 'e', 'e', 't'},
+				TrackedSubnets: [][]byte{id[:]},
+			},
+		},
+		{
+			op:     GetPeerList,
+			fields: map[Field]interface{}{},
+		},
+		{
+			op: Pong,
+			fields: map[Field]interface{}{
+				Uptime: uint8(80),
+			},
+		},
+		{
+			op: IPv6,
+			fields: map[Field]interface{}{
+				IP:          utils.IPDesc{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+				VersionTime: now,
+				SigBytes:    []byte{'y', 'e', 'e', 't'},
+			},
+		},
+		{
+			op: Features,
+			fields: map[Field]interface{}{
+				SupportedFeatures: uint64(5),
+			},
+		},
+		{
+			op: GetAccepted,
+			fields: map[Field]interface{}{
+				ChainID:      id[:],
+				RequestID:    uint32(1337),
+				Deadline:     uint64(time.Second),
+				ContainerIDs: [][]byte{id[:]},
+			},
+		},
+		{
+			op: Ancestors,
+			fields: map[Field]interface{}{
+				ChainID:             id[:],
+				RequestID:           uint32(1337),
+				MultiContainerBytes: [][]byte{make([]byte, 1024)},
+			},
+		},
+		{
+			op: PushQuery,
+			fields: map[Field]interface{}{
+				ChainID:        id[:],
+				RequestID:      uint32(1337),
+				Deadline:       uint64(time.Second),
+				ContainerID:    id[:],
+				ContainerBytes: make([]byte, 1024),
+			},
+		},
+		{
+			op: AppGossip,
+			fields: map[Field]interface{}{
+				ChainID:  id[:],
+				AppBytes: []byte{1},
+			},
+		},
+	}
+}
+
+// Test packing messages with the protobuf schema, parsing them, and converting
+// them to the legacy codec
+func TestProtoCodecPackParse(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewProtoCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(err)
+	legacyCodec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(err)
+
+	for _, m := range testProtoMessages() {
+		compressionType := compression.TypeNone
+		if m.op.Compressable() {
+			compressionType = compression.TypeZstd
+		}
+		msg, err := c.Pack(m.op, m.fields, compressionType)
+		assert.NoError(err, "failed to pack %s", m.op)
+		assert.True(msg.IsProto())
+		assert.GreaterOrEqual(msg.Bytes()[0], byte(protoMessagePrefix))
+
+		// Every codec parses messages packed with the protobuf schema
+		parsedMsg, err := legacyCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(err, "failed to parse %s", m.op)
+		assert.Equal(m.op, parsedMsg.Op())
+		assert.Equal(m.fields, parsedMsg.(*inboundMessage).fields)
+		if _, ok := m.fields[Deadline]; ok {
+			assert.False(parsedMsg.ExpirationTime().IsZero())
+		}
+
+		// Peers that predate the schema are sent the message packed with the
+		// legacy codec
+		legacyMsg, err := c.ToLegacy(msg)
+		assert.NoError(err, "failed to convert %s", m.op)
+		assert.False(legacyMsg.IsProto())
+		assert.Equal(byte(m.op), legacyMsg.Bytes()[0])
+		if msg.CompressionType() != compression.TypeNone {
+			assert.Equal(compression.TypeGzip, legacyMsg.CompressionType())
+		}
+
+		parsedMsg, err = legacyCodec.Parse(legacyMsg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(err, "failed to parse legacy %s", m.op)
+		assert.Equal(m.fields, parsedMsg.(*inboundMessage).fields)
+
+		// Converting a legacy message returns the message itself
+		sameMsg, err := c.ToLegacy(legacyMsg)
+		assert.NoError(err)
+		assert.Equal(legacyMsg, sameMsg)
+	}
+}
+
+func TestProtoCodecCompression(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := newProtoCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 256)
+	assert.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	small, err := c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: make([]byte, 1),
+	}, compression.TypeZstd)
+	assert.NoError(err)
+	assert.Equal(compression.TypeNone, small.CompressionType())
+
+	appBytes := make([]byte, 1024)
+	appBytes[0] = 1
+	large, err := c.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: appBytes,
+	}, compression.TypeZstd)
+	assert.NoError(err)
+	assert.Equal(compression.TypeZstd, large.CompressionType())
+	assert.Positive(large.BytesSavedCompression())
+
+	gzipMsg, err := c.Recompress(large, compression.TypeGzip)
+	assert.NoError(err)
+	assert.True(gzipMsg.IsProto())
+	assert.Equal(compression.TypeGzip, gzipMsg.CompressionType())
+
+	parsedMsg, err := c.Parse(gzipMsg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(err)
+	assert.Equal(AppGossip, parsedMsg.Op())
+	assert.Equal(appBytes, parsedMsg.Get(AppBytes))
+	assert.Positive(parsedMsg.BytesSavedCompression())
+
+	// Messages whose type may not be compressed are rejected if they are
+	pong, err := c.Pack(Pong, map[Field]interface{}{Uptime: uint8(1)}, compression.TypeNone)
+	assert.NoError(err)
+	compressedPongBytes, err := compression.NewGzipCompressor(2 * units.MiB).Compress(pong.Bytes())
+	assert.NoError(err)
+	compressedPong, err := proto.Marshal(&messageproto.Message{
+		Message: &messageproto.Message_CompressedGzip{CompressedGzip: compressedPongBytes},
+	})
+	assert.NoError(err)
+	_, err = c.Parse(compressedPong, dummyNodeID, dummyOnFinishedHandling)
+	assert.ErrorIs(err, errBadCompression)
+}
+
+func TestProtoCodecPackInvalidOp(t *testing.T) {
+	c, err := NewProtoCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(t, err)
+
+	_, err = c.Pack(Notify, map[Field]interface{}{}, compression.TypeNone)
+	assert.ErrorIs(t, err, errBadOp)
+
+	_, err = c.Pack(Get, map[Field]interface{}{}, compression.TypeNone)
+	assert.ErrorIs(t, err, errMissingField)
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Toinounet21/avalanchego-mod/message/messageproto"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+)
+
+// opFieldNumberOffset is the difference between the number of the field of a
+// message type in messageproto.Message and the op code of the message type
+const opFieldNumberOffset = 16
+
+var (
+	errBadFieldType = errors.New("field has an invalid type")
+	errBadFieldSize = errors.New("field has an invalid size")
+
+	// Describes messageproto.Message
+	messageDescriptor = (&messageproto.Message{}).ProtoReflect().Descriptor()
+
+	// Maps the names of the fields of the message types in the protobuf schema
+	// to the fields they hold
+	schemaFields = map[protoreflect.Name]Field{
+		"networkID":           NetworkID,
+		"nodeID":              NodeID,
+		"myTime":              MyTime,
+		"ip":                  IP,
+		"versionStr":          VersionStr,
+		"versionTime":         VersionTime,
+		"sigBytes":            SigBytes,
+		"trackedSubnets":      TrackedSubnets,
+		"signedPeers":         SignedPeers,
+		"uptime":              Uptime,
+		"supportedFeatures":   SupportedFeatures,
+		"chainID":             ChainID,
+		"requestID":           RequestID,
+		"deadline":            Deadline,
+		"containerID":         ContainerID,
+		"containerIDs":        ContainerIDs,
+		"containerBytes":      ContainerBytes,
+		"multiContainerBytes": MultiContainerBytes,
+		"appBytes":            AppBytes,
+	}
+)
+
+// schemaMessages returns the fields of each message type in the protobuf
+// schema, in the order of their field numbers, which is the order the legacy
+// codec packs them in.
+// Panics if the schema holds a field that isn't in [schemaFields].
+func schemaMessages() map[Op][]Field {
+	fds := messageDescriptor.Oneofs().ByName("message").Fields()
+	msgs := make(map[Op][]Field, fds.Len())
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		// Skip the compressed payloads
+		if fd.Kind() != protoreflect.MessageKind {
+			continue
+		}
+
+		msgFDs := fd.Message().Fields()
+		sortedFDs := make([]protoreflect.FieldDescriptor, msgFDs.Len())
+		for j := range sortedFDs {
+			sortedFDs[j] = msgFDs.Get(j)
+		}
+		sort.Slice(sortedFDs, func(i, j int) bool {
+			return sortedFDs[i].Number() < sortedFDs[j].Number()
+		})
+
+		fields := make([]Field, len(sortedFDs))
+		for j, msgFD := range sortedFDs {
+			field, ok := schemaFields[msgFD.Name()]
+			if !ok {
+				panic(fmt.Sprintf("unknown field %s in the schema", msgFD.FullName()))
+			}
+			fields[j] = field
+		}
+		msgs[Op(fd.Number()-opFieldNumberOffset)] = fields
+	}
+	return msgs
+}
+
+// schemaOpField returns the descriptor of the field of messageproto.Message
+// that holds [op] messages, or nil if there isn't one
+func schemaOpField(op Op) protoreflect.FieldDescriptor {
+	return messageDescriptor.Fields().ByNumber(protoreflect.FieldNumber(op) + opFieldNumberOffset)
+}
+
+// schemaField returns the descriptor of the field of [md] that holds [field]
+func schemaField(md protoreflect.MessageDescriptor, field Field) protoreflect.FieldDescriptor {
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		if fd := fds.Get(i); schemaFields[fd.Name()] == field {
+			return fd
+		}
+	}
+	return nil
+}
+
+// setSchemaField sets [fd] of [m] to [value], which is a value of [field]
+func setSchemaField(m protoreflect.Message, fd protoreflect.FieldDescriptor, field Field, value interface{}) error {
+	switch field {
+	case NetworkID, NodeID, RequestID:
+		v, ok := value.(uint32)
+		if !ok {
+			return errBadFieldType
+		}
+		m.Set(fd, protoreflect.ValueOfUint32(v))
+	case MyTime, Deadline, VersionTime, SupportedFeatures:
+		v, ok := value.(uint64)
+		if !ok {
+			return errBadFieldType
+		}
+		m.Set(fd, protoreflect.ValueOfUint64(v))
+	case Uptime:
+		v, ok := value.(uint8)
+		if !ok {
+			return errBadFieldType
+		}
+		m.Set(fd, protoreflect.ValueOfUint32(uint32(v)))
+	case VersionStr:
+		v, ok := value.(string)
+		if !ok {
+			return errBadFieldType
+		}
+		m.Set(fd, protoreflect.ValueOfString(v))
+	case ChainID, ContainerID, ContainerBytes, SigBytes, AppBytes:
+		v, ok := value.([]byte)
+		if !ok {
+			return errBadFieldType
+		}
+		m.Set(fd, protoreflect.ValueOfBytes(v))
+	case ContainerIDs, MultiContainerBytes, TrackedSubnets:
+		v, ok := value.([][]byte)
+		if !ok {
+			return errBadFieldType
+		}
+		list := m.Mutable(fd).List()
+		for _, b := range v {
+			list.Append(protoreflect.ValueOfBytes(b))
+		}
+	case IP:
+		v, ok := value.(utils.IPDesc)
+		if !ok {
+			return errBadFieldType
+		}
+		m.Set(fd, protoreflect.ValueOfMessage(newSchemaIP(v).ProtoReflect()))
+	case SignedPeers:
+		v, ok := value.([]utils.IPCertDesc)
+		if !ok {
+			return errBadFieldType
+		}
+		list := m.Mutable(fd).List()
+		for _, peer := range v {
+			signedPeer := &messageproto.SignedPeer{
+				Ip:        newSchemaIP(peer.IPDesc),
+				Time:      peer.Time,
+				Signature: peer.Signature,
+			}
+			if peer.Cert != nil {
+				signedPeer.Cert = peer.Cert.Raw
+			}
+			list.Append(protoreflect.ValueOfMessage(signedPeer.ProtoReflect()))
+		}
+	default:
+		return fmt.Errorf("%w: %s", errBadFieldType, field)
+	}
+	return nil
+}
+
+// getSchemaField returns the value of [fd] of [m] as a value of [field]
+func getSchemaField(m protoreflect.Message, fd protoreflect.FieldDescriptor, field Field) (interface{}, error) {
+	value := m.Get(fd)
+	switch field {
+	case NetworkID, NodeID, RequestID:
+		return uint32(value.Uint()), nil
+	case MyTime, Deadline, VersionTime, SupportedFeatures:
+		return value.Uint(), nil
+	case Uptime:
+		if value.Uint() > math.MaxUint8 {
+			return nil, errBadFieldSize
+		}
+		return uint8(value.Uint()), nil
+	case VersionStr:
+		return value.String(), nil
+	case ChainID, ContainerID, ContainerBytes, SigBytes, AppBytes:
+		return value.Bytes(), nil
+	case ContainerIDs, MultiContainerBytes, TrackedSubnets:
+		list := value.List()
+		v := make([][]byte, list.Len())
+		for i := range v {
+			v[i] = list.Get(i).Bytes()
+		}
+		return v, nil
+	case IP:
+		return parseSchemaIP(value.Message().Interface().(*messageproto.IPDesc))
+	case SignedPeers:
+		list := value.List()
+		v := make([]utils.IPCertDesc, list.Len())
+		for i := range v {
+			signedPeer := list.Get(i).Message().Interface().(*messageproto.SignedPeer)
+			ip, err := parseSchemaIP(signedPeer.Ip)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = utils.IPCertDesc{
+				IPDesc:    ip,
+				Time:      signedPeer.Time,
+				Signature: signedPeer.Signature,
+			}
+			if len(signedPeer.Cert) != 0 {
+				if v[i].Cert, err = x509.ParseCertificate(signedPeer.Cert); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errBadFieldType, field)
+	}
+}
+
+func newSchemaIP(ip utils.IPDesc) *messageproto.IPDesc {
+	return &messageproto.IPDesc{
+		Ip:   ip.IP.To16(),
+		Port: uint32(ip.Port),
+	}
+}
+
+func parseSchemaIP(ip *messageproto.IPDesc) (utils.IPDesc, error) {
+	if ip == nil || len(ip.Ip) != net.IPv6len || ip.Port > math.MaxUint16 {
+		return utils.IPDesc{}, errBadFieldSize
+	}
+	return utils.IPDesc{
+		IP:   net.IP(ip.Ip),
+		Port: uint16(ip.Port),
+	}, nil
+}
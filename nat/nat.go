@@ -4,17 +4,45 @@
 package nat
 
 import (
+	"fmt"
 	"net"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 )
 
 const (
 	mapTimeout        = 30 * time.Minute
 	maxRefreshRetries = 3
+	// How long to wait when connecting to our own external IP to verify that
+	// a mapping works
+	verifyTimeout = 5 * time.Second
+)
+
+// Reachability of a mapped port from the internet
+const (
+	// The mapping wasn't verified yet
+	ReachabilityUnknown = "unknown"
+	// Connecting to the external IP and port reached this node
+	ReachabilityReachable = "reachable"
+	// Connecting to the external IP and port failed
+	ReachabilityUnreachable = "unreachable"
+)
+
+// How peers can connect to this node
+const (
+	// No mapped port is known to be unreachable. Peers may dial this node.
+	ConnectivityDirect = "direct"
+	// A port is unreachable or couldn't be mapped. This node only
+	// communicates with peers over the connections it dials itself, which the
+	// NAT lets through.
+	ConnectivityOutboundOnly = "outboundOnly"
 )
 
 // Router describes the functionality that a network device must support to be
@@ -42,23 +70,106 @@ func GetRouter() Router {
 	return NewNoRouter()
 }
 
+// MappingStatus describes a port mapping that a Mapper maintains
+type MappingStatus struct {
+	Protocol     string      `json:"protocol"`
+	Description  string      `json:"description"`
+	InternalPort json.Uint16 `json:"internalPort"`
+	ExternalPort json.Uint16 `json:"externalPort"`
+	// True if the router accepted the last attempt to map the port
+	Mapped bool `json:"mapped"`
+	// Last time the router accepted to map the port
+	LastMapped time.Time `json:"lastMapped"`
+	// Why the last attempt to map the port failed, if it did
+	LastError string `json:"lastError,omitempty"`
+	// Whether connecting to our external IP on [ExternalPort] reached this
+	// node when the mapping was last renewed. Routers that don't support
+	// hairpinning report the port as unreachable even if it's reachable
+	// from the internet.
+	Reachability string `json:"reachability"`
+}
+
+// Status describes the port mappings that a Mapper maintains
+type Status struct {
+	// False if no router that supports NAT traversal was found, in which
+	// case no port is mapped
+	SupportsNAT bool `json:"supportsNAT"`
+	// The mappings, in the order they were requested
+	Mappings []MappingStatus `json:"mappings"`
+	// How peers can connect to this node
+	Connectivity string `json:"connectivity"`
+}
+
 // Mapper attempts to open a set of ports on a router
 type Mapper struct {
 	log    logging.Logger
 	r      Router
 	closer chan struct{}
 	wg     sync.WaitGroup
+
+	lock sync.RWMutex
+	// The mappings, in the order they were requested
+	mappings []*MappingStatus
+
+	numMapped      prometheus.Counter
+	numMapFailures prometheus.Counter
+	numUnreachable prometheus.Gauge
 }
 
 // NewPortMapper returns an initialized mapper
-func NewPortMapper(log logging.Logger, r Router) Mapper {
-	return Mapper{
+func NewPortMapper(log logging.Logger, r Router) *Mapper {
+	return &Mapper{
 		log:    log,
 		r:      r,
 		closer: make(chan struct{}),
+		numMapped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nat",
+			Name:      "mapped",
+			Help:      "Number of times the router accepted to map or renew a port mapping",
+		}),
+		numMapFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nat",
+			Name:      "map_failures",
+			Help:      "Number of times mapping or renewing a port mapping failed",
+		}),
+		numUnreachable: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "nat",
+			Name:      "unreachable_mappings",
+			Help:      "Number of mapped ports that couldn't be reached through our external IP",
+		}),
 	}
 }
 
+// RegisterMetrics registers the metrics of this mapper with [registerer]
+func (m *Mapper) RegisterMetrics(registerer prometheus.Registerer) error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.numMapped),
+		registerer.Register(m.numMapFailures),
+		registerer.Register(m.numUnreachable),
+	)
+	return errs.Err
+}
+
+// Status returns the current state of the port mappings
+func (m *Mapper) Status() Status {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	status := Status{
+		SupportsNAT:  m.r.SupportsNAT(),
+		Mappings:     make([]MappingStatus, len(m.mappings)),
+		Connectivity: ConnectivityDirect,
+	}
+	for i, mapping := range m.mappings {
+		status.Mappings[i] = *mapping
+		if !mapping.Mapped || mapping.Reachability == ReachabilityUnreachable {
+			status.Connectivity = ConnectivityOutboundOnly
+		}
+	}
+	return status
+}
+
 // Map external port [extPort] (exposed to the internet) to internal port [intPort] (where our process is listening)
 // and set [ip]. Does this every [updateTime]. [ip] may be nil.
 func (m *Mapper) Map(protocol string, intPort, extPort uint16, desc string, ip *utils.DynamicIPDesc, updateTime time.Duration) {
@@ -66,15 +177,78 @@ func (m *Mapper) Map(protocol string, intPort, extPort uint16, desc string, ip *
 		return
 	}
 
+	mapping := &MappingStatus{
+		Protocol:     protocol,
+		Description:  desc,
+		InternalPort: json.Uint16(intPort),
+		ExternalPort: json.Uint16(extPort),
+		Reachability: ReachabilityUnknown,
+	}
+	m.lock.Lock()
+	m.mappings = append(m.mappings, mapping)
+	m.lock.Unlock()
+
 	// we attempt a port map, and log an Error if it fails.
 	err := m.retryMapPort(protocol, intPort, extPort, desc, mapTimeout)
+	m.recordMapping(mapping, err)
 	if err != nil {
 		m.log.Error("NAT Traversal failed from external port %d to internal port %d with %s", extPort, intPort, err)
 	} else {
 		m.log.Info("NAT Traversal successful from external port %d to internal port %d", extPort, intPort)
 	}
 
-	go m.keepPortMapping(protocol, intPort, extPort, desc, ip, updateTime)
+	go m.keepPortMapping(mapping, ip, updateTime)
+}
+
+// recordMapping records the result [err] of an attempt to map [mapping]
+func (m *Mapper) recordMapping(mapping *MappingStatus, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	mapping.Mapped = err == nil
+	if err != nil {
+		mapping.LastError = err.Error()
+		m.numMapFailures.Inc()
+		return
+	}
+	mapping.LastMapped = time.Now()
+	mapping.LastError = ""
+	m.numMapped.Inc()
+}
+
+// verifyMapping checks whether [mapping] lets the internet reach this node by
+// connecting to our external IP on the mapped port
+func (m *Mapper) verifyMapping(mapping *MappingStatus) {
+	reachability := ReachabilityReachable
+	if err := m.dialExternalPort(mapping); err != nil {
+		m.log.Warn("couldn't reach this node through external port %d: %s", mapping.ExternalPort, err)
+		reachability = ReachabilityUnreachable
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	mapping.Reachability = reachability
+	numUnreachable := 0
+	for _, mapping := range m.mappings {
+		if mapping.Reachability == ReachabilityUnreachable {
+			numUnreachable++
+		}
+	}
+	m.numUnreachable.Set(float64(numUnreachable))
+}
+
+func (m *Mapper) dialExternalPort(mapping *MappingStatus) error {
+	ip, err := m.r.ExternalIP()
+	if err != nil {
+		return fmt.Errorf("failed to get external IP: %w", err)
+	}
+	addr := utils.IPDesc{IP: ip, Port: uint16(mapping.ExternalPort)}
+	conn, err := net.DialTimeout("tcp", addr.String(), verifyTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
 }
 
 // Retry port map up to maxRefreshRetries with a 1 second delay
@@ -94,9 +268,13 @@ func (m *Mapper) retryMapPort(protocol string, intPort, extPort uint16, desc str
 	return err
 }
 
-// keepPortMapping runs in the background to keep a port mapped. It renews the mapping from [extPort]
-// to [intPort]] every [updateTime]. Updates [ip] every [updateTime].
-func (m *Mapper) keepPortMapping(protocol string, intPort, extPort uint16, desc string, ip *utils.DynamicIPDesc, updateTime time.Duration) {
+// keepPortMapping runs in the background to keep a port mapped. It renews
+// [mapping] and verifies that it's reachable every [updateTime]. Updates [ip]
+// every [updateTime].
+func (m *Mapper) keepPortMapping(mapping *MappingStatus, ip *utils.DynamicIPDesc, updateTime time.Duration) {
+	protocol := mapping.Protocol
+	intPort := uint16(mapping.InternalPort)
+	extPort := uint16(mapping.ExternalPort)
 	updateTimer := time.NewTimer(updateTime)
 
 	m.wg.Add(1)
@@ -115,12 +293,16 @@ func (m *Mapper) keepPortMapping(protocol string, intPort, extPort uint16, desc
 	for {
 		select {
 		case <-updateTimer.C:
-			err := m.retryMapPort(protocol, intPort, extPort, desc, mapTimeout)
+			err := m.retryMapPort(protocol, intPort, extPort, mapping.Description, mapTimeout)
+			m.recordMapping(mapping, err)
 			if err != nil {
 				m.log.Warn("Renew NAT Traversal failed from external port %d to internal port %d with %s",
 					extPort, intPort, err)
 			}
 			m.updateIP(ip)
+			if protocol == "TCP" {
+				m.verifyMapping(mapping)
+			}
 			updateTimer.Reset(updateTime)
 		case <-m.closer:
 			return
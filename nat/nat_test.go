@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nat
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+var errMapFailed = errors.New("map failed")
+
+// loopbackRouter maps ports on the loopback interface
+type loopbackRouter struct {
+	mapErr error
+}
+
+func (*loopbackRouter) SupportsNAT() bool { return true }
+
+func (r *loopbackRouter) MapPort(string, uint16, uint16, string, time.Duration) error {
+	return r.mapErr
+}
+
+func (*loopbackRouter) UnmapPort(string, uint16, uint16) error { return nil }
+
+func (*loopbackRouter) ExternalIP() (net.IP, error) { return net.IPv4(127, 0, 0, 1), nil }
+
+func TestMapperStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	defer listener.Close()
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+	r := &loopbackRouter{}
+	m := NewPortMapper(logging.NoLog{}, r)
+	assert.NoError(m.RegisterMetrics(prometheus.NewRegistry()))
+	m.Map("TCP", port, port, "staking", nil, time.Hour)
+	defer m.UnmapAllPorts()
+
+	status := m.Status()
+	assert.True(status.SupportsNAT)
+	assert.Len(status.Mappings, 1)
+	assert.True(status.Mappings[0].Mapped)
+	assert.Equal(ReachabilityUnknown, status.Mappings[0].Reachability)
+	assert.Equal(ConnectivityDirect, status.Connectivity)
+
+	// The mapped port reaches the listener
+	m.verifyMapping(m.mappings[0])
+	status = m.Status()
+	assert.Equal(ReachabilityReachable, status.Mappings[0].Reachability)
+	assert.Equal(ConnectivityDirect, status.Connectivity)
+
+	// Once the listener is gone, the mapped port is unreachable
+	assert.NoError(listener.Close())
+	m.verifyMapping(m.mappings[0])
+	status = m.Status()
+	assert.Equal(ReachabilityUnreachable, status.Mappings[0].Reachability)
+	assert.Equal(ConnectivityOutboundOnly, status.Connectivity)
+
+	// Failing to renew the mapping is reported
+	r.mapErr = errMapFailed
+	m.recordMapping(m.mappings[0], r.mapErr)
+	status = m.Status()
+	assert.False(status.Mappings[0].Mapped)
+	assert.Equal(errMapFailed.Error(), status.Mappings[0].LastError)
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+)
+
+var (
+	_ Allowlist = &allowlist{}
+	_ Allowlist = &noAllowlist{}
+)
+
+// Allowlist is the set of node IDs that this node is allowed to connect to.
+// Used to run closed networks, such as the ones of permissioned subnets.
+type Allowlist interface {
+	// IsAllowed returns true if this node may connect to [nodeID]
+	IsAllowed(nodeID ids.ShortID) bool
+}
+
+type allowlist struct {
+	nodeIDs   ids.ShortSet
+	subnetIDs ids.Set
+	vdrs      validators.Manager
+}
+
+// New returns an Allowlist that allows [nodeIDs] and the current validators,
+// as tracked by [vdrs], of the subnets in [subnetIDs]
+func New(nodeIDs ids.ShortSet, subnetIDs ids.Set, vdrs validators.Manager) Allowlist {
+	return &allowlist{
+		nodeIDs:   nodeIDs,
+		subnetIDs: subnetIDs,
+		vdrs:      vdrs,
+	}
+}
+
+func (a *allowlist) IsAllowed(nodeID ids.ShortID) bool {
+	if a.nodeIDs.Contains(nodeID) {
+		return true
+	}
+	for subnetID := range a.subnetIDs {
+		if a.vdrs.Contains(subnetID, nodeID) {
+			return true
+		}
+	}
+	return false
+}
+
+type noAllowlist struct{}
+
+// NewNoAllowlist returns an Allowlist that allows every node
+func NewNoAllowlist() Allowlist { return &noAllowlist{} }
+
+func (*noAllowlist) IsAllowed(ids.ShortID) bool { return true }
@@ -0,0 +1,40 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package allowlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+)
+
+func TestAllowlist(t *testing.T) {
+	assert := assert.New(t)
+
+	allowedID := ids.GenerateTestShortID()
+	subnetID := ids.GenerateTestID()
+	vdrs := validators.NewManager()
+	a := New(ids.ShortSet{allowedID: struct{}{}}, ids.Set{subnetID: struct{}{}}, vdrs)
+
+	vdrID := ids.GenerateTestShortID()
+	assert.True(a.IsAllowed(allowedID))
+	assert.False(a.IsAllowed(vdrID))
+
+	// Validators of the subnets are allowed as soon as they start validating
+	assert.NoError(vdrs.AddWeight(subnetID, vdrID, 1))
+	assert.True(a.IsAllowed(vdrID))
+
+	// Validators of other subnets aren't
+	otherVdrID := ids.GenerateTestShortID()
+	assert.NoError(vdrs.AddWeight(ids.GenerateTestID(), otherVdrID, 1))
+	assert.False(a.IsAllowed(otherVdrID))
+
+	assert.NoError(vdrs.RemoveWeight(subnetID, vdrID, 1))
+	assert.False(a.IsAllowed(vdrID))
+
+	assert.True(NewNoAllowlist().IsAllowed(vdrID))
+}
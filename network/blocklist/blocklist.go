@@ -0,0 +1,230 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blocklist
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+var (
+	_ Blocklist = &blocklist{}
+	_ Blocklist = &noBlocklist{}
+
+	nodeIDsPrefix  = []byte("nodeIDs")
+	ipRangesPrefix = []byte("ipRanges")
+
+	errDisabled = errors.New("the blocklist is disabled")
+)
+
+// Blocklist is the set of node IDs and IP ranges that this node refuses to
+// connect to. Changes are persisted, so that they survive restarts.
+type Blocklist interface {
+	// BlockNodeID blocks [nodeID]. Blocking a blocked node ID is a no-op.
+	BlockNodeID(nodeID ids.ShortID) error
+	// UnblockNodeID unblocks [nodeID]. Unblocking a node ID that isn't blocked
+	// is a no-op.
+	UnblockNodeID(nodeID ids.ShortID) error
+	// BlockIPRange blocks every IP in [ipRange]. Blocking a blocked range is a
+	// no-op.
+	BlockIPRange(ipRange *net.IPNet) error
+	// UnblockIPRange unblocks [ipRange]. IPs in [ipRange] that are in another
+	// blocked range remain blocked.
+	UnblockIPRange(ipRange *net.IPNet) error
+
+	// IsNodeIDBlocked returns true if [nodeID] is blocked
+	IsNodeIDBlocked(nodeID ids.ShortID) bool
+	// IsIPBlocked returns true if [ip] is in a blocked range
+	IsIPBlocked(ip net.IP) bool
+
+	// NodeIDs returns the blocked node IDs
+	NodeIDs() []ids.ShortID
+	// IPRanges returns the blocked IP ranges, sorted by their string
+	// representation
+	IPRanges() []*net.IPNet
+}
+
+type blocklist struct {
+	lock sync.RWMutex
+	// Keyed by node ID
+	nodeIDsDB database.Database
+	// Keyed by the string representation of the range
+	ipRangesDB database.Database
+
+	nodeIDs ids.ShortSet
+	// String representation of the range --> Range
+	ipRanges map[string]*net.IPNet
+}
+
+// New returns a Blocklist that persists its entries in [db], and that contains
+// the entries previously persisted in [db]. [db] should not be used by
+// anything else.
+func New(db database.Database) (Blocklist, error) {
+	b := &blocklist{
+		nodeIDsDB:  prefixdb.New(nodeIDsPrefix, db),
+		ipRangesDB: prefixdb.New(ipRangesPrefix, db),
+		ipRanges:   make(map[string]*net.IPNet),
+	}
+
+	nodeIDsIt := b.nodeIDsDB.NewIterator()
+	defer nodeIDsIt.Release()
+	for nodeIDsIt.Next() {
+		nodeID, err := ids.ToShortID(nodeIDsIt.Key())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse blocked node ID: %w", err)
+		}
+		b.nodeIDs.Add(nodeID)
+	}
+	if err := nodeIDsIt.Error(); err != nil {
+		return nil, err
+	}
+
+	ipRangesIt := b.ipRangesDB.NewIterator()
+	defer ipRangesIt.Release()
+	for ipRangesIt.Next() {
+		_, ipRange, err := net.ParseCIDR(string(ipRangesIt.Key()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse blocked IP range: %w", err)
+		}
+		b.ipRanges[ipRange.String()] = ipRange
+	}
+	return b, ipRangesIt.Error()
+}
+
+func (b *blocklist) BlockNodeID(nodeID ids.ShortID) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.nodeIDsDB.Put(nodeID[:], nil); err != nil {
+		return err
+	}
+	b.nodeIDs.Add(nodeID)
+	return nil
+}
+
+func (b *blocklist) UnblockNodeID(nodeID ids.ShortID) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.nodeIDsDB.Delete(nodeID[:]); err != nil {
+		return err
+	}
+	b.nodeIDs.Remove(nodeID)
+	return nil
+}
+
+func (b *blocklist) BlockIPRange(ipRange *net.IPNet) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := ipRange.String()
+	if err := b.ipRangesDB.Put([]byte(key), nil); err != nil {
+		return err
+	}
+	b.ipRanges[key] = ipRange
+	return nil
+}
+
+func (b *blocklist) UnblockIPRange(ipRange *net.IPNet) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := ipRange.String()
+	if err := b.ipRangesDB.Delete([]byte(key)); err != nil {
+		return err
+	}
+	delete(b.ipRanges, key)
+	return nil
+}
+
+func (b *blocklist) IsNodeIDBlocked(nodeID ids.ShortID) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.nodeIDs.Contains(nodeID)
+}
+
+func (b *blocklist) IsIPBlocked(ip net.IP) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	for _, ipRange := range b.ipRanges {
+		if ipRange.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *blocklist) NodeIDs() []ids.ShortID {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.nodeIDs.List()
+}
+
+func (b *blocklist) IPRanges() []*net.IPNet {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	keys := make([]string, 0, len(b.ipRanges))
+	for key := range b.ipRanges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ipRanges := make([]*net.IPNet, len(keys))
+	for i, key := range keys {
+		ipRanges[i] = b.ipRanges[key]
+	}
+	return ipRanges
+}
+
+// ParseIPRange parses [s] as either a range in CIDR notation, such as
+// "192.0.2.0/24", or as a single IP, such as "192.0.2.1".
+func ParseIPRange(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, ipRange, err := net.ParseCIDR(s)
+		return ipRange, err
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP %q", s)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}, nil
+}
+
+type noBlocklist struct{}
+
+// NewNoBlocklist returns a Blocklist that never blocks anything, and that
+// can't be modified.
+func NewNoBlocklist() Blocklist { return &noBlocklist{} }
+
+func (*noBlocklist) BlockNodeID(ids.ShortID) error { return errDisabled }
+
+func (*noBlocklist) UnblockNodeID(ids.ShortID) error { return errDisabled }
+
+func (*noBlocklist) BlockIPRange(*net.IPNet) error { return errDisabled }
+
+func (*noBlocklist) UnblockIPRange(*net.IPNet) error { return errDisabled }
+
+func (*noBlocklist) IsNodeIDBlocked(ids.ShortID) bool { return false }
+
+func (*noBlocklist) IsIPBlocked(net.IP) bool { return false }
+
+func (*noBlocklist) NodeIDs() []ids.ShortID { return nil }
+
+func (*noBlocklist) IPRanges() []*net.IPNet { return nil }
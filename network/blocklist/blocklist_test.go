@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blocklist
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func TestBlocklistPersists(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	b, err := New(db)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	ipRange, err := ParseIPRange("192.0.2.0/24")
+	assert.NoError(err)
+	assert.NoError(b.BlockNodeID(nodeID))
+	assert.NoError(b.BlockIPRange(ipRange))
+
+	// A new blocklist over the same database should contain the same entries
+	b, err = New(db)
+	assert.NoError(err)
+	assert.True(b.IsNodeIDBlocked(nodeID))
+	assert.False(b.IsNodeIDBlocked(ids.GenerateTestShortID()))
+	assert.True(b.IsIPBlocked(net.IPv4(192, 0, 2, 1)))
+	assert.False(b.IsIPBlocked(net.IPv4(192, 0, 3, 1)))
+	assert.Equal([]ids.ShortID{nodeID}, b.NodeIDs())
+	assert.Equal([]*net.IPNet{ipRange}, b.IPRanges())
+
+	// Unblocking is persisted as well
+	assert.NoError(b.UnblockNodeID(nodeID))
+	assert.NoError(b.UnblockIPRange(ipRange))
+	b, err = New(db)
+	assert.NoError(err)
+	assert.False(b.IsNodeIDBlocked(nodeID))
+	assert.False(b.IsIPBlocked(net.IPv4(192, 0, 2, 1)))
+	assert.Empty(b.NodeIDs())
+	assert.Empty(b.IPRanges())
+}
+
+func TestBlocklistOverlappingRanges(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := New(memdb.New())
+	assert.NoError(err)
+
+	wide, err := ParseIPRange("10.0.0.0/8")
+	assert.NoError(err)
+	narrow, err := ParseIPRange("10.1.0.0/16")
+	assert.NoError(err)
+	assert.NoError(b.BlockIPRange(narrow))
+	assert.NoError(b.BlockIPRange(wide))
+	assert.Equal([]*net.IPNet{wide, narrow}, b.IPRanges())
+
+	// IPs in [narrow] remain blocked by [wide]
+	assert.NoError(b.UnblockIPRange(narrow))
+	assert.True(b.IsIPBlocked(net.IPv4(10, 1, 0, 1)))
+	assert.NoError(b.UnblockIPRange(wide))
+	assert.False(b.IsIPBlocked(net.IPv4(10, 1, 0, 1)))
+}
+
+func TestParseIPRange(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    string
+		shouldError bool
+	}{
+		{input: "192.0.2.0/24", expected: "192.0.2.0/24"},
+		{input: "192.0.2.7/24", expected: "192.0.2.0/24"},
+		{input: "192.0.2.1", expected: "192.0.2.1/32"},
+		{input: "2001:db8::/32", expected: "2001:db8::/32"},
+		{input: "2001:db8::1", expected: "2001:db8::1/128"},
+		{input: "192.0.2.0/33", shouldError: true},
+		{input: "not an ip", shouldError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			ipRange, err := ParseIPRange(test.input)
+			if test.shouldError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, ipRange.String())
+		})
+	}
+}
+
+func TestNoBlocklist(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewNoBlocklist()
+	assert.Error(b.BlockNodeID(ids.GenerateTestShortID()))
+	assert.False(b.IsNodeIDBlocked(ids.GenerateTestShortID()))
+	assert.False(b.IsIPBlocked(net.IPv4(192, 0, 2, 1)))
+}
@@ -5,16 +5,31 @@ package dialer
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/url"
 	"time"
 
+	"golang.org/x/net/proxy"
+
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/network/quic"
 	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/utils"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+const (
+	// maxQUICPeers is the max number of IPs whose QUIC support is remembered
+	maxQUICPeers = 4096
+	// quicFailureBackoff is how long an IP is only dialed over TCP after a
+	// QUIC connection to it failed
+	quicFailureBackoff = 10 * time.Minute
 )
 
-var _ Dialer = &dialer{}
+var _ QUICDialer = &dialer{}
 
 // Dialer attempts to create a connection with the provided IP/port pair
 type Dialer interface {
@@ -23,16 +38,40 @@ type Dialer interface {
 	Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error)
 }
 
+// QUICDialer is a Dialer that connects over QUIC to the peers known to
+// accept QUIC connections
+type QUICDialer interface {
+	Dialer
+
+	// AcceptsQUIC marks [ip] as accepting QUIC connections. Until then, [ip]
+	// is only dialed over TCP.
+	AcceptsQUIC(ip utils.IPDesc)
+}
+
 type dialer struct {
 	log               logging.Logger
 	network           string
 	throttler         throttling.DialThrottler
 	connectionTimeout time.Duration
+
+	// If non-nil, a QUIC connection is attempted before falling back to
+	// [network].
+	quicTLSConfig *tls.Config
+	// IP string --> time.Time of the last failed QUIC dial, or the zero time.
+	// QUIC is only attempted for the IPs in this cache.
+	quicPeers cache.LRU
+	clock     mockable.Clock
+
+	// If non-nil, connections are established through this proxy.
+	proxy proxy.ContextDialer
 }
 
 type Config struct {
 	ThrottleRps       uint32        `json:"throttleRps"`
 	ConnectionTimeout time.Duration `json:"connectionTimeout"`
+	// If non-nil, outbound connections are routed through this proxy.
+	// Not serialized since it may contain credentials.
+	Proxy *url.URL `json:"-"`
 }
 
 // NewDialer returns a new Dialer that calls net.Dial with the provided network.
@@ -40,7 +79,8 @@ type Config struct {
 // [dialerConfig.connectionTimeout] gives the timeout when dialing an IP.
 // [dialerConfig.throttleRps] gives the max number of outgoing connection attempts/second.
 // If [dialerConfig.throttleRps] == 0, outgoing connections aren't rate-limited.
-func NewDialer(network string, dialerConfig Config, log logging.Logger) Dialer {
+// If [dialerConfig.Proxy] is non-nil, connections are routed through that proxy.
+func NewDialer(network string, dialerConfig Config, log logging.Logger) (Dialer, error) {
 	var throttler throttling.DialThrottler
 	if dialerConfig.ThrottleRps <= 0 {
 		throttler = throttling.NewNoDialThrottler()
@@ -52,23 +92,127 @@ func NewDialer(network string, dialerConfig Config, log logging.Logger) Dialer {
 		dialerConfig.ThrottleRps,
 		dialerConfig.ConnectionTimeout,
 	)
-	return &dialer{
+	d := &dialer{
 		log:               log,
 		network:           network,
 		throttler:         throttler,
 		connectionTimeout: dialerConfig.ConnectionTimeout,
+		quicPeers:         cache.LRU{Size: maxQUICPeers},
 	}
+	if dialerConfig.Proxy != nil {
+		proxyDialer, err := newProxyDialer(dialerConfig.Proxy, &net.Dialer{Timeout: d.connectionTimeout})
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create proxy dialer: %w", err)
+		}
+		log.Info("routing outbound connections through a %s proxy at %s", dialerConfig.Proxy.Scheme, dialerConfig.Proxy.Host)
+		d.proxy = proxyDialer
+	}
+	return d, nil
+}
+
+// NewQUICDialer returns a new Dialer that first attempts to establish a QUIC
+// connection using [tlsConfig] with the IPs marked by AcceptsQUIC. If the peer
+// doesn't accept the QUIC connection, the dialer falls back to calling
+// net.Dial with the provided network, and doesn't attempt QUIC with that IP
+// again for a while. The remaining arguments are the same as in NewDialer.
+// QUIC isn't attempted if [dialerConfig.Proxy] is non-nil, since the proxy
+// only relays TCP connections.
+func NewQUICDialer(network string, dialerConfig Config, tlsConfig *tls.Config, log logging.Logger) (QUICDialer, error) {
+	d, err := NewDialer(network, dialerConfig, log)
+	if err != nil {
+		return nil, err
+	}
+	qd := d.(*dialer)
+	if dialerConfig.Proxy == nil {
+		qd.quicTLSConfig = tlsConfig
+	}
+	return qd, nil
+}
+
+func (d *dialer) AcceptsQUIC(ip utils.IPDesc) {
+	key := ip.String()
+	if _, ok := d.quicPeers.Get(key); !ok {
+		d.quicPeers.Put(key, time.Time{})
+	}
+}
+
+// shouldDialQUIC returns true if [ip] accepts QUIC connections and a QUIC
+// dial to it hasn't failed recently
+func (d *dialer) shouldDialQUIC(ip utils.IPDesc) bool {
+	if d.quicTLSConfig == nil {
+		return false
+	}
+	lastFailureIntf, ok := d.quicPeers.Get(ip.String())
+	if !ok {
+		return false
+	}
+	lastFailure := lastFailureIntf.(time.Time)
+	return lastFailure.IsZero() || d.clock.Time().Sub(lastFailure) >= quicFailureBackoff
 }
 
 func (d *dialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
 	if err := d.throttler.Acquire(ctx); err != nil {
 		return nil, err
 	}
+	if d.shouldDialQUIC(ip) {
+		d.log.Verbo("dialing %s over QUIC", ip)
+		conn, err := d.dialQUIC(ctx, ip)
+		if err == nil {
+			d.quicPeers.Put(ip.String(), time.Time{})
+			return conn, nil
+		}
+		d.quicPeers.Put(ip.String(), d.clock.Time())
+		d.log.Verbo("falling back to %s after failing to dial %s over QUIC: %s", d.network, ip, err)
+	}
+	if d.proxy != nil {
+		d.log.Verbo("dialing %s through proxy", ip)
+		conn, err := d.dialProxy(ctx, ip)
+		if err != nil {
+			return nil, fmt.Errorf("error while dialing %s through proxy: %w", ip, err)
+		}
+		return conn, nil
+	}
 	d.log.Verbo("dialing %s", ip)
 	dialer := net.Dialer{Timeout: d.connectionTimeout}
-	conn, err := dialer.DialContext(ctx, d.network, ip.String())
+	conn, err := dialer.DialContext(ctx, d.networkFor(ip), ip.String())
 	if err != nil {
 		return nil, fmt.Errorf("error while dialing %s: %w", ip, err)
 	}
 	return conn, nil
 }
+
+// networkFor returns the network to dial [ip] on. If [d.network] is "tcp", the
+// network matching the address family of [ip] is returned so that IPv6
+// addresses advertised by dual-stack peers are dialed over IPv6 rather than
+// being mapped onto IPv4.
+func (d *dialer) networkFor(ip utils.IPDesc) string {
+	if d.network != "tcp" || ip.IP == nil {
+		return d.network
+	}
+	if ip.IP.To4() != nil {
+		return "tcp4"
+	}
+	return "tcp6"
+}
+
+func (d *dialer) dialQUIC(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
+	if d.connectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.connectionTimeout)
+		defer cancel()
+	}
+	return quic.Dial(ctx, ip.String(), d.quicTLSConfig)
+}
+
+func (d *dialer) dialProxy(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
+	if d.connectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.connectionTimeout)
+		defer cancel()
+	}
+	conn, err := d.proxy.DialContext(ctx, d.network, ip.String())
+	if err != nil {
+		return nil, err
+	}
+	return newProxiedConn(conn, ip), nil
+}
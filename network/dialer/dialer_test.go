@@ -5,6 +5,7 @@ package dialer
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"strconv"
 	"strings"
@@ -64,7 +65,8 @@ func TestDialerCancelDial(t *testing.T) {
 	}
 
 	// Create a dialer that should allow 10 outgoing connections per second
-	dialer := NewDialer("tcp", Config{ThrottleRps: 10, ConnectionTimeout: 30 * time.Second}, logging.NoLog{})
+	dialer, err := NewDialer("tcp", Config{ThrottleRps: 10, ConnectionTimeout: 30 * time.Second}, logging.NoLog{})
+	assert.NoError(t, err)
 	// Make 5 outgoing connections. Should not be throttled.
 	for i := 0; i < 5; i++ {
 		startTime := time.Now()
@@ -93,3 +95,68 @@ func TestDialerCancelDial(t *testing.T) {
 	done <- struct{}{} // mark that test is done
 	_ = l.Close()
 }
+
+func TestDialerNetworkFor(t *testing.T) {
+	assert := assert.New(t)
+
+	d := &dialer{network: "tcp"}
+	assert.Equal("tcp4", d.networkFor(utils.IPDesc{IP: net.IPv4(127, 0, 0, 1), Port: 9651}))
+	assert.Equal("tcp4", d.networkFor(utils.IPDesc{IP: net.ParseIP("::ffff:127.0.0.1"), Port: 9651}))
+	assert.Equal("tcp6", d.networkFor(utils.IPDesc{IP: net.IPv6loopback, Port: 9651}))
+
+	// Explicit networks are left as is
+	d = &dialer{network: "tcp4"}
+	assert.Equal("tcp4", d.networkFor(utils.IPDesc{IP: net.IPv6loopback, Port: 9651}))
+}
+
+func TestDialerBacksOffQUIC(t *testing.T) {
+	assert := assert.New(t)
+
+	// Only accepts TCP connections
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	assert.NoError(err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	ip := utils.IPDesc{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: uint16(l.Addr().(*net.TCPAddr).Port),
+	}
+
+	// #nosec G402
+	qd, err := NewQUICDialer("tcp", Config{ConnectionTimeout: 500 * time.Millisecond}, &tls.Config{}, logging.NoLog{})
+	assert.NoError(err)
+	d := qd.(*dialer)
+	d.clock.Set(time.Now())
+
+	// QUIC isn't attempted until the peer is known to accept it
+	assert.False(d.shouldDialQUIC(ip))
+	d.AcceptsQUIC(ip)
+	assert.True(d.shouldDialQUIC(ip))
+
+	// The QUIC dial fails, so the dialer falls back to TCP
+	conn, err := d.Dial(context.Background(), ip)
+	assert.NoError(err)
+	_, isTCP := conn.(*net.TCPConn)
+	assert.True(isTCP)
+	_ = conn.Close()
+
+	// QUIC isn't attempted again until the backoff has elapsed, even if the
+	// peer advertises QUIC again
+	d.AcceptsQUIC(ip)
+	assert.False(d.shouldDialQUIC(ip))
+	d.clock.Set(d.clock.Time().Add(quicFailureBackoff))
+	assert.True(d.shouldDialQUIC(ip))
+
+	// The dialer doesn't attempt QUIC without a TLS config
+	d.quicTLSConfig = nil
+	assert.False(d.shouldDialQUIC(ip))
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+// Optional protocol features that a peer may support. Each node advertises the
+// features it supports in a Features message sent right after its Version
+// message. Nodes that predate the Features message drop it as unparsable, and
+// are treated as supporting none of these features.
+const (
+	// The peer can parse messages compressed with zstd
+	featureZstdCompression uint64 = 1 << iota
+	// The peer can parse IPv6 messages
+	featureIPv6
+	// The peer accepts QUIC connections on its staking port
+	featureQUIC
+	// The peer can parse message envelopes with optional fields
+	featureEnvelope
+	// The peer can parse Subnets messages, which update the subnets it tracks
+	// after the handshake
+	featureSubnets
+	// The peer can parse messages packed with the protobuf schema
+	featureProtoMessages
+	// The peer can parse SignedIP messages, which refresh the signature of the
+	// IP it advertised in its Version message
+	featureSignedIP
+)
+
+// defaultFeatures are the features this node always advertises to its peers
+const defaultFeatures = featureZstdCompression | featureIPv6 | featureEnvelope | featureSubnets | featureProtoMessages | featureSignedIP
+
+// featureNames are the names of features, in the order they're reported
+var featureNames = []struct {
+	feature uint64
+	name    string
+}{
+	{feature: featureZstdCompression, name: "zstdCompression"},
+	{feature: featureIPv6, name: "ipv6"},
+	{feature: featureQUIC, name: "quic"},
+	{feature: featureEnvelope, name: "envelope"},
+	{feature: featureSubnets, name: "subnets"},
+	{feature: featureProtoMessages, name: "protoMessages"},
+	{feature: featureSignedIP, name: "signedIP"},
+}
+
+// featureList returns the names of the features in [features]
+func featureList(features uint64) []string {
+	names := []string{}
+	for _, f := range featureNames {
+		if features&f.feature != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	gomath "math"
+
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/math"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+const (
+	// Number of recently seen containers remembered to detect redundant
+	// gossip
+	gossipSeenCacheSize = 8192
+
+	// A container gossiped to this node is only counted as redundant if this
+	// node first saw it at least this long ago. Gossip of a freshly accepted
+	// container from the peers that accepted it around the same time is
+	// expected, and doesn't mean the chain is gossiped more than needed.
+	gossipRedundancyGracePeriod = 5 * time.Second
+
+	// Max number of received containers queued to be recorded. Containers
+	// received while the queue is full aren't recorded.
+	gossipObservationQueueSize = 1024
+
+	// The fanout before backing off is this factor times log2 of the number of
+	// validators, which is enough for gossip to reach the whole validator set
+	// with high probability.
+	gossipFanoutLogFactor = 2
+)
+
+// gossipObservation is a container received from a peer
+type gossipObservation struct {
+	chainID, containerID ids.ID
+	// True if the container was gossiped rather than sent in a query
+	gossiped bool
+	time     time.Time
+}
+
+// gossipFanout decides how many peers each accepted container is gossiped to.
+// The fanout grows logarithmically with the size of the validator set, and
+// backs off when most of the containers gossiped to this node were seen well
+// before, since that means the chain is gossiped more than needed.
+type gossipFanout struct {
+	clock *mockable.Clock
+
+	// Container ID --> Time this node first saw the container
+	seen cache.LRU
+
+	// Received containers waiting to be recorded, so that recording them
+	// isn't done while handling messages
+	observations chan gossipObservation
+	// Closed when [dispatch] should return
+	closing chan struct{}
+
+	// [lock] must be held while accessing [config] and [redundancy]
+	lock   sync.Mutex
+	config GossipConfig
+	// Chain ID --> Average fraction of the containers gossiped to this node
+	// that were already seen
+	redundancy map[ids.ID]math.Averager
+}
+
+func newGossipFanout(config GossipConfig, clock *mockable.Clock) *gossipFanout {
+	return &gossipFanout{
+		config:       config,
+		clock:        clock,
+		seen:         cache.LRU{Size: gossipSeenCacheSize},
+		observations: make(chan gossipObservation, gossipObservationQueueSize),
+		closing:      make(chan struct{}),
+		redundancy:   make(map[ids.ID]math.Averager),
+	}
+}
+
+// dispatch records received containers until [stop] is called
+func (f *gossipFanout) dispatch() {
+	for {
+		select {
+		case obs := <-f.observations:
+			f.observe(obs)
+		case <-f.closing:
+			return
+		}
+	}
+}
+
+// setConfig changes the adaptive gossip parameters of [config] that the fanout
+// is decided by
+func (f *gossipFanout) setConfig(config GossipConfig) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config = config
+}
+
+// stop causes [dispatch] to return. Must only be called once.
+func (f *gossipFanout) stop() {
+	close(f.closing)
+}
+
+// accepted marks [containerID] as seen, if it wasn't already
+func (f *gossipFanout) accepted(containerID ids.ID) {
+	if _, ok := f.seen.Get(containerID); !ok {
+		f.seen.Put(containerID, f.clock.Time())
+	}
+}
+
+// received queues the container in [msg] to be recorded. [msg] must be a Put
+// or a PushQuery. Never blocks.
+func (f *gossipFanout) received(msg message.InboundMessage) {
+	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
+	if err != nil {
+		return
+	}
+	containerID, err := ids.ToID(msg.Get(message.ContainerID).([]byte))
+	if err != nil {
+		return
+	}
+	requestID, _ := msg.Get(message.RequestID).(uint32)
+
+	obs := gossipObservation{
+		chainID:     chainID,
+		containerID: containerID,
+		gossiped:    msg.Op() == message.Put && requestID == constants.GossipMsgRequestID,
+		time:        f.clock.Time(),
+	}
+	select {
+	case f.observations <- obs:
+	default:
+	}
+}
+
+// observe marks the container in [obs] as seen and, if it was gossiped,
+// records whether it was redundant
+func (f *gossipFanout) observe(obs gossipObservation) {
+	redundant := 0.
+	if firstSeen, ok := f.seen.Get(obs.containerID); !ok {
+		f.seen.Put(obs.containerID, obs.time)
+	} else if obs.time.Sub(firstSeen.(time.Time)) >= gossipRedundancyGracePeriod {
+		redundant = 1
+	}
+
+	if !obs.gossiped {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	averager, ok := f.redundancy[obs.chainID]
+	if !ok {
+		averager = math.NewAverager(0, f.config.AdaptiveGossipRedundancyHalflife, obs.time)
+		f.redundancy[obs.chainID] = averager
+	}
+	averager.Observe(redundant, obs.time)
+}
+
+// size returns the number of peers to gossip a container of [chainID] to,
+// given that the chain is validated by [numValidators] validators
+func (f *gossipFanout) size(chainID ids.ID, numValidators int) int {
+	f.lock.Lock()
+	config := f.config
+	redundancy := 0.
+	if averager, ok := f.redundancy[chainID]; ok {
+		redundancy = averager.Read()
+	}
+	f.lock.Unlock()
+
+	minSize := float64(config.AdaptiveGossipMinSize)
+	maxSize := float64(config.AdaptiveGossipMaxSize)
+
+	size := gossipFanoutLogFactor * gomath.Log2(float64(numValidators+1))
+	size = gomath.Max(minSize, gomath.Min(maxSize, size))
+
+	// Above the threshold, the fanout decreases linearly to the minimum as the
+	// redundancy approaches 1
+	threshold := config.AdaptiveGossipRedundancyThreshold
+	if redundancy > threshold {
+		backoff := (1 - redundancy) / (1 - threshold)
+		size = minSize + (size-minSize)*backoff
+	}
+	return int(gomath.Ceil(size))
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+func newTestGossipFanout() *gossipFanout {
+	clock := &mockable.Clock{}
+	clock.Set(time.Unix(0, 0))
+	return newGossipFanout(
+		GossipConfig{
+			AdaptiveGossipMinSize:             6,
+			AdaptiveGossipMaxSize:             30,
+			AdaptiveGossipRedundancyHalflife:  time.Minute,
+			AdaptiveGossipRedundancyThreshold: 0.5,
+		},
+		clock,
+	)
+}
+
+// receive records [msg] as if it was received by [f] and then handled by
+// [f.dispatch]
+func receive(f *gossipFanout, msg message.InboundMessage) {
+	f.received(msg)
+	f.observe(<-f.observations)
+}
+
+func TestGossipFanoutScalesWithValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newTestGossipFanout()
+	chainID := ids.GenerateTestID()
+
+	// Small validator sets are gossiped to the min
+	assert.Equal(6, f.size(chainID, 0))
+	assert.Equal(6, f.size(chainID, 3))
+
+	// 2 * log2(1024) = 20
+	assert.Equal(20, f.size(chainID, 1023))
+
+	// Large validator sets are gossiped to the max
+	assert.Equal(30, f.size(chainID, 1<<20))
+}
+
+func TestGossipFanoutSetConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	f := newTestGossipFanout()
+	chainID := ids.GenerateTestID()
+
+	f.setConfig(GossipConfig{
+		AdaptiveGossipMinSize:             10,
+		AdaptiveGossipMaxSize:             12,
+		AdaptiveGossipRedundancyHalflife:  time.Minute,
+		AdaptiveGossipRedundancyThreshold: 0.5,
+	})
+	assert.Equal(10, f.size(chainID, 0))
+	assert.Equal(12, f.size(chainID, 1023))
+}
+
+func TestGossipFanoutBacksOffWhenRedundant(t *testing.T) {
+	assert := assert.New(t)
+
+	codec, err := message.NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(err)
+	builder := message.NewInboundBuilder(codec)
+
+	f := newTestGossipFanout()
+	chainID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestShortID()
+
+	// Containers that weren't seen yet don't reduce the fanout
+	for i := 0; i < 10; i++ {
+		receive(f, builder.InboundPut(chainID, constants.GossipMsgRequestID, ids.GenerateTestID(), nil, nodeID))
+	}
+	assert.Equal(20, f.size(chainID, 1023))
+
+	// Containers gossiped right after they were accepted aren't redundant
+	for i := 0; i < 1000; i++ {
+		containerID := ids.GenerateTestID()
+		f.accepted(containerID)
+		receive(f, builder.InboundPut(chainID, constants.GossipMsgRequestID, containerID, nil, nodeID))
+	}
+	assert.Equal(20, f.size(chainID, 1023))
+
+	// Containers gossiped long after they were accepted are redundant
+	containerIDs := make([]ids.ID, 1000)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		f.accepted(containerIDs[i])
+	}
+	// Wait long enough for the earlier observations to be mostly forgotten
+	f.clock.Set(f.clock.Time().Add(10 * time.Minute))
+	for _, containerID := range containerIDs {
+		receive(f, builder.InboundPut(chainID, constants.GossipMsgRequestID, containerID, nil, nodeID))
+	}
+	size := f.size(chainID, 1023)
+	assert.Less(size, 20)
+	assert.GreaterOrEqual(size, 6)
+
+	// Redundancy is tracked per chain
+	assert.Equal(20, f.size(otherChainID, 1023))
+
+	// Responses to requests aren't gossip, so they don't affect the fanout
+	for _, containerID := range containerIDs {
+		receive(f, builder.InboundPut(otherChainID, 1, containerID, nil, nodeID))
+	}
+	assert.Equal(20, f.size(otherChainID, 1023))
+}
+
+func TestGossipFanoutReceivedNeverBlocks(t *testing.T) {
+	codec, err := message.NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(t, err)
+	builder := message.NewInboundBuilder(codec)
+
+	f := newTestGossipFanout()
+	chainID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestShortID()
+
+	// Nothing is dispatching, so observations past the queue size are dropped
+	for i := 0; i < 2*gossipObservationQueueSize; i++ {
+		f.received(builder.InboundPut(chainID, constants.GossipMsgRequestID, ids.GenerateTestID(), nil, nodeID))
+	}
+	assert.Len(t, f.observations, gossipObservationQueueSize)
+}
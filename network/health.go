@@ -18,6 +18,15 @@ type HealthConfig struct {
 	// to be considered healthy. Must be positive
 	MaxTimeSinceMsgSent time.Duration `json:"maxTimeSinceMsgSent"`
 
+	// Must have received a consensus message from the network within this
+	// duration to be considered healthy. If 0, this isn't checked
+	MaxTimeSinceConsensusMsgReceived time.Duration `json:"maxTimeSinceConsensusMsgReceived"`
+
+	// Must be connected to at least this portion of the primary network's
+	// stake, including this node's own stake, to be considered healthy. Must be
+	// in [0,1]
+	MinConnectedStake float64 `json:"minConnectedStake"`
+
 	// If greater than this portion of the pending send byte queue is full,
 	// will report unhealthy. Must be in (0,1]
 	MaxPortionSendQueueBytesFull float64 `json:"maxPortionSendQueueBytesFull"`
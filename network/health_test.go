@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+)
+
+func TestConnectedStakePortion(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewManager()
+	n := &network{config: &Config{Validators: vdrs}}
+
+	_, err := n.connectedStakePortion(ids.ShortSet{})
+	assert.ErrorIs(err, errNoPrimaryValidators)
+
+	primaryVdrs := validators.NewSet()
+	assert.NoError(vdrs.Set(constants.PrimaryNetworkID, primaryVdrs))
+
+	// Without any stake, there is nothing to be partitioned from
+	portion, err := n.connectedStakePortion(ids.ShortSet{})
+	assert.NoError(err)
+	assert.Equal(1., portion)
+
+	vdr0 := ids.ShortID{0}
+	vdr1 := ids.ShortID{1}
+	assert.NoError(primaryVdrs.AddWeight(vdr0, 1))
+	assert.NoError(primaryVdrs.AddWeight(vdr1, 3))
+
+	connected := ids.ShortSet{}
+	connected.Add(vdr0, ids.ShortID{2})
+	portion, err = n.connectedStakePortion(connected)
+	assert.NoError(err)
+	assert.Equal(.25, portion)
+
+	connected.Add(vdr1)
+	portion, err = n.connectedStakePortion(connected)
+	assert.NoError(err)
+	assert.Equal(1., portion)
+}
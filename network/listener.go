@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+var (
+	errListenerClosed = errors.New("listener closed")
+
+	_ net.Listener = &multiListener{}
+)
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// multiListener accepts connections from several listeners at once, such as
+// the TCP and QUIC listeners bound to the staking port.
+type multiListener struct {
+	listeners []net.Listener
+	results   chan acceptResult
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewMultiListener returns a net.Listener that accepts connections from all of
+// [listeners]. The address of the first listener is reported as the address of
+// the returned listener. Closing the returned listener closes all of
+// [listeners].
+func NewMultiListener(listeners ...net.Listener) net.Listener {
+	l := &multiListener{
+		listeners: listeners,
+		results:   make(chan acceptResult),
+		closed:    make(chan struct{}),
+	}
+	for _, listener := range listeners {
+		go l.accept(listener)
+	}
+	return l
+}
+
+func (l *multiListener) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		select {
+		case l.results <- acceptResult{conn: conn, err: err}:
+		case <-l.closed:
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		if err == nil {
+			continue
+		}
+		if netErr, ok := err.(net.Error); !ok || !netErr.Temporary() {
+			return
+		}
+	}
+}
+
+func (l *multiListener) Accept() (net.Conn, error) {
+	select {
+	case result := <-l.results:
+		return result.conn, result.err
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *multiListener) Close() error {
+	errs := wrappers.Errs{}
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		for _, listener := range l.listeners {
+			errs.Add(listener.Close())
+		}
+	})
+	return errs.Err
+}
+
+func (l *multiListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiListenerAccept(t *testing.T) {
+	assert := assert.New(t)
+
+	listener0, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	listener1, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	listener := NewMultiListener(listener0, listener1)
+	defer listener.Close()
+
+	assert.Equal(listener0.Addr(), listener.Addr())
+
+	for _, l := range []net.Listener{listener0, listener1} {
+		dialedConn, err := net.Dial("tcp", l.Addr().String())
+		assert.NoError(err)
+
+		conn, err := listener.Accept()
+		assert.NoError(err)
+		assert.Equal(dialedConn.LocalAddr().String(), conn.RemoteAddr().String())
+
+		assert.NoError(dialedConn.Close())
+		assert.NoError(conn.Close())
+	}
+}
+
+func TestMultiListenerClose(t *testing.T) {
+	assert := assert.New(t)
+
+	listener0, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	listener1, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+
+	listener := NewMultiListener(listener0, listener1)
+	assert.NoError(listener.Close())
+
+	_, err = listener.Accept()
+	assert.ErrorIs(err, errListenerClosed)
+
+	// The underlying listeners should have been closed as well.
+	_, err = listener0.Accept()
+	assert.Error(err)
+	_, err = listener1.Accept()
+	assert.Error(err)
+}
@@ -82,13 +82,21 @@ type metrics struct {
 	timeSinceLastMsgReceived  prometheus.Gauge
 	sendQueuePortionFull      prometheus.Gauge
 	sendFailRate              prometheus.Gauge
+	connectedStakePortion     prometheus.Gauge
 	failedToParse             prometheus.Counter
 	connected                 prometheus.Counter
 	disconnected              prometheus.Counter
 	inboundConnRateLimited    prometheus.Counter
+	inboundConnBlocked        prometheus.Counter
 	inboundConnAllowed        prometheus.Counter
+	dialAttempts              prometheus.Counter
+	dialFailures              prometheus.Counter
+	dialGiveUps               prometheus.Counter
+	consecutiveDialFailures   prometheus.Histogram
+	pingLatency               *prometheus.HistogramVec
 	nodeUptimeWeightedAverage prometheus.Gauge
 	nodeUptimeRewardingStake  prometheus.Gauge
+	gossipFanout              metric.Averager
 
 	messageMetrics map[message.Op]*messageMetrics
 }
@@ -119,6 +127,11 @@ func (m *metrics) initialize(namespace string, registerer prometheus.Registerer)
 		Name:      "send_fail_rate",
 		Help:      "Portion of messages that recently failed to be sent over the network",
 	})
+	m.connectedStakePortion = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "connected_stake_portion",
+		Help:      "Portion of the primary network's stake that this node is connected to",
+	})
 	m.failedToParse = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "msgs_failed_to_parse",
@@ -144,6 +157,41 @@ func (m *metrics) initialize(namespace string, registerer prometheus.Registerer)
 		Name:      "inbound_conn_throttler_rate_limited",
 		Help:      "Times this node rejected an inbound connection due to rate-limiting",
 	})
+	m.inboundConnBlocked = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "inbound_conn_blocked",
+		Help:      "Times this node rejected an inbound connection from a blocked IP",
+	})
+	m.dialAttempts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dial_attempts",
+		Help:      "Times this node attempted to connect to a peer",
+	})
+	m.dialFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dial_failures",
+		Help:      "Times this node failed to connect to a peer",
+	})
+	m.dialGiveUps = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dial_give_ups",
+		Help:      "Times this node stopped attempting to connect to a peer after too many failed attempts",
+	})
+	m.consecutiveDialFailures = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "consecutive_dial_failures",
+		Help:      "Number of consecutive failed attempts to connect to a peer before connecting to it or giving up on it",
+		Buckets:   []float64{0, 1, 2, 4, 8, 16, 32, 64, 128},
+	})
+	m.pingLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ping_latency",
+			Help:      "Round-trip time (in ms) of Ping/Pong exchanges with each peer",
+			Buckets:   []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+		},
+		[]string{"nodeID"},
+	)
 	m.nodeUptimeWeightedAverage = prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Name:      "node_uptime_weighted_average",
@@ -162,15 +210,30 @@ func (m *metrics) initialize(namespace string, registerer prometheus.Registerer)
 		registerer.Register(m.timeSinceLastMsgSent),
 		registerer.Register(m.sendQueuePortionFull),
 		registerer.Register(m.sendFailRate),
+		registerer.Register(m.connectedStakePortion),
 		registerer.Register(m.failedToParse),
 		registerer.Register(m.connected),
 		registerer.Register(m.disconnected),
 		registerer.Register(m.inboundConnAllowed),
 		registerer.Register(m.inboundConnRateLimited),
+		registerer.Register(m.inboundConnBlocked),
+		registerer.Register(m.dialAttempts),
+		registerer.Register(m.dialFailures),
+		registerer.Register(m.dialGiveUps),
+		registerer.Register(m.consecutiveDialFailures),
+		registerer.Register(m.pingLatency),
 		registerer.Register(m.nodeUptimeWeightedAverage),
 		registerer.Register(m.nodeUptimeRewardingStake),
 	)
 
+	m.gossipFanout = metric.NewAveragerWithErrs(
+		namespace,
+		"gossip_fanout",
+		"number of peers each accepted container is gossiped to",
+		registerer,
+		&errs,
+	)
+
 	m.messageMetrics = make(map[message.Op]*messageMetrics, len(message.ExternalOps))
 	for _, op := range message.ExternalOps {
 		m.messageMetrics[op] = newMessageMetrics(op, namespace, registerer, &errs)
@@ -4,6 +4,7 @@
 package network
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/tls"
@@ -11,6 +12,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,28 +26,39 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/api/health"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/allowlist"
+	"github.com/Toinounet21/avalanchego-mod/network/blocklist"
 	"github.com/Toinounet21/avalanchego-mod/network/dialer"
+	"github.com/Toinounet21/avalanchego-mod/network/peerstore"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/sender"
 	"github.com/Toinounet21/avalanchego-mod/snow/uptime"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
 	"github.com/Toinounet21/avalanchego-mod/utils/json"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/math"
 	"github.com/Toinounet21/avalanchego-mod/utils/sampler"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
+// Granularity of the pings scheduled to the peers
+const pingWheelTick = 100 * time.Millisecond
+
 var (
 	errNetworkClosed       = errors.New("network closed")
 	errPeerIsMyself        = errors.New("peer is myself")
+	errPeerIsBlocked       = errors.New("peer is blocked")
 	errNoPrimaryValidators = errors.New("no default subnet validators")
 
 	_ Network = &network{}
@@ -76,10 +89,15 @@ type Network interface {
 	// internally to the network.
 	Track(ip utils.IPDesc, nodeID ids.ShortID)
 
-	// Returns the description of the specified [nodeIDs] this network is currently
-	// connected to externally or all nodes this network is connected to if [nodeIDs]
-	// is empty. Thread safety must be managed internally to the network.
-	Peers(nodeIDs []ids.ShortID) []PeerInfo
+	// Returns the description of the peers this network is currently
+	// connected to that match [filter], sorted by node ID. Thread safety must
+	// be managed internally to the network.
+	Peers(filter PeersFilter) []PeerInfo
+
+	// Returns the scores of the peers with [nodeIDs], sorted by node ID. If
+	// [nodeIDs] is empty, the scores of the connected peers are returned.
+	// Thread safety must be managed internally to the network.
+	PeerScores(nodeIDs []ids.ShortID) []PeerScore
 
 	// Close this network and all existing connections it has. Thread safety
 	// must be managed internally to the network. Calling close multiple times
@@ -91,6 +109,57 @@ type Network interface {
 
 	NodeUptime() (UptimeResult, bool)
 
+	// Returns this node's uptime as observed by the primary network
+	// validators it's connected to, and true if this node is a primary network
+	// validator. Thread safety must be managed internally to the network.
+	UptimeView() (UptimeView, bool)
+
+	// Returns this node and the peers that have finished the handshake
+	// aggregated by version. Thread safety must be managed internally to the
+	// network.
+	VersionCensus() VersionCensus
+
+	// Returns the policy that peer versions are checked against and the
+	// connected peers that it affects. Thread safety must be managed
+	// internally to the network.
+	VersionPolicy() VersionPolicy
+
+	// Block prevents this node from connecting to [nodeIDs] and to the IPs in
+	// [ipRanges], and disconnects from the peers that are now blocked. Thread
+	// safety must be managed internally to the network.
+	Block(nodeIDs []ids.ShortID, ipRanges []*net.IPNet) error
+
+	// Unblock allows this node to connect to [nodeIDs] and to the IPs in
+	// [ipRanges] again. Thread safety must be managed internally to the
+	// network.
+	Unblock(nodeIDs []ids.ShortID, ipRanges []*net.IPNet) error
+
+	// Blocked returns the blocked node IDs and IP ranges. Thread safety must be
+	// managed internally to the network.
+	Blocked() ([]ids.ShortID, []*net.IPNet)
+
+	// TrackSubnet starts tracking the subnet with ID [subnetID]. Messages of
+	// the subnet are routed to and from the peers that also track it, and
+	// peers are told that this node now tracks it. Thread safety must be
+	// managed internally to the network.
+	TrackSubnet(subnetID ids.ID)
+
+	// UntrackSubnet stops tracking the subnet with ID [subnetID]. Thread
+	// safety must be managed internally to the network.
+	UntrackSubnet(subnetID ids.ID)
+
+	// SetGossipConfig changes the adaptive gossip parameters that decide how
+	// many peers accepted containers are gossiped to. The other parameters of
+	// [config] are given to the chains when they're created, so they aren't
+	// changed. Thread safety must be managed internally to the network.
+	SetGossipConfig(config GossipConfig)
+
+	// SetInboundMsgThrottlerConfig changes the limits of the inbound message
+	// throttler that can be changed at runtime. See
+	// throttling.InboundMsgThrottler. Thread safety must be managed internally
+	// to the network.
+	SetInboundMsgThrottlerConfig(config throttling.InboundMsgThrottlerConfig)
+
 	// Has a health check
 	health.Checker
 }
@@ -110,6 +179,9 @@ type network struct {
 	// Unix time at which last message of any type sent over network
 	// Must only be accessed atomically
 	lastMsgSentTime int64
+	// Unix time at which last consensus message was received over network
+	// Must only be accessed atomically
+	lastConsensusMsgReceivedTime int64
 	// Keeps track of the percentage of sends that fail
 	sendFailRateCalculator math.Averager
 	log                    logging.Logger
@@ -118,6 +190,8 @@ type network struct {
 	parser                 version.ApplicationParser
 	listener               net.Listener
 	dialer                 dialer.Dialer
+	quicDialer             dialer.QUICDialer // nil if QUIC is disabled
+	supportedFeatures      uint64            // features advertised to peers
 	serverUpgrader         Upgrader
 	clientUpgrader         Upgrader
 	router                 router.Router // router must be thread safe
@@ -134,6 +208,18 @@ type network struct {
 	// May contain peers that we have not finished the handshake with.
 	peers peersData
 
+	// Subnets this node tracks, in addition to the primary network. Starts as
+	// [config.WhitelistedSubnets]. [stateLock] should be held when accessing
+	// it.
+	whitelistedSubnets ids.Set
+
+	// Primary network validators, outside of [config.ConnectivitySubnetID],
+	// that this node holds connections to. At most
+	// [config.PrimaryNetworkSampleSize]. Only used when
+	// [config.ConnectivitySubnetID] is set. [stateLock] should be held when
+	// accessing it.
+	primarySample ids.ShortSet
+
 	// disconnectedIPs, connectedIPs, peerAliasIPs, and myIPs
 	// are maps with utils.IPDesc.String() keys that are used to determine if
 	// we should attempt to dial an IP. [stateLock] should be held
@@ -152,11 +238,17 @@ type network struct {
 	// ensures the close of the network only happens once.
 	closeOnce sync.Once
 
+	// Schedules the pings sent to every peer
+	pingWheel *timer.Wheel
+
 	hasMasked        bool
 	maskedValidators ids.ShortSet
 
 	benchlistManager benchlist.Manager
 
+	// Decides how many peers accepted containers are gossiped to
+	gossipFanout *gossipFanout
+
 	// [lastTimestampLock] should be held when touching  [lastVersionIP],
 	// [lastVersionTimestamp], and [lastVersionSignature]
 	timeForIPLock sync.Mutex
@@ -167,6 +259,10 @@ type network struct {
 	lastVersionTimestamp uint64
 	// The signature we included in the most recent Version message we sent.
 	lastVersionSignature []byte
+	// The timestamp and signature sent in IPv6 messages. The timestamp is
+	// always [lastVersionTimestamp] once set.
+	lastIPv6Timestamp uint64
+	lastIPv6Signature []byte
 
 	// Node ID --> Latest IP/timestamp of this node from a Version or PeerList message
 	// The values in this map all have [signature] == nil
@@ -182,6 +278,12 @@ type network struct {
 	// Rate-limits incoming messages
 	inboundMsgThrottler         throttling.InboundMsgThrottler
 	inboundConnUpgradeThrottler throttling.InboundConnUpgradeThrottler
+	inboundConnAttemptThrottler throttling.InboundConnAttemptThrottler
+	// Limits the bandwidth used to send messages on behalf of each subnet
+	outboundSubnetBandwidthThrottler throttling.OutboundSubnetBandwidthThrottler
+	// Limits the bandwidth used to send messages to each peer, and to all
+	// peers together
+	outboundBandwidthThrottler throttling.OutboundBandwidthThrottler
 
 	// Rate-limits outgoing messages
 	outboundMsgThrottler throttling.OutboundMsgThrottler
@@ -192,6 +294,9 @@ type PeerListGossipConfig struct {
 	PeerListGossipSize           uint32        `json:"peerListGossipSize"`
 	PeerListStakerGossipFraction uint32        `json:"peerListStakerGossipFraction"`
 	PeerListGossipFreq           time.Duration `json:"peerListGossipFreq"`
+	// Gossiped IPs that were signed longer than this ago are stale, and are
+	// ignored. If 0, gossiped IPs never become stale.
+	PeerListMaxIPAge time.Duration `json:"peerListMaxIPAge"`
 }
 
 type TimeoutConfig struct {
@@ -207,20 +312,45 @@ type TimeoutConfig struct {
 type DelayConfig struct {
 	InitialReconnectDelay time.Duration `json:"initialReconnectDelay"`
 	MaxReconnectDelay     time.Duration `json:"maxReconnectDelay"`
+	// Factor the reconnect delay is multiplied by after each failed attempt
+	// to connect to a peer. Must be >= 1.
+	ReconnectDelayMultiplier float64 `json:"reconnectDelayMultiplier"`
+	// Each reconnect delay is randomized by up to this fraction of its value
+	// so that reconnection attempts to a peer that restarted are spread out.
+	// Must be in [0, 1).
+	ReconnectDelayJitter float64 `json:"reconnectDelayJitter"`
+	// Number of consecutive failed attempts to connect to a peer after which
+	// we stop trying, until its IP is learned again. If 0, we never stop.
+	// Attempts to connect to bootstrap beacons are never stopped.
+	MaxReconnectAttempts uint32 `json:"maxReconnectAttempts"`
 }
 
 type GossipConfig struct {
 	GossipAcceptedFrontierSize uint `json:"gossipAcceptedFrontierSize"`
-	GossipOnAcceptSize         uint `json:"gossipOnAcceptSize"`
 	AppGossipNonValidatorSize  uint `json:"appGossipNonValidatorSize"`
 	AppGossipValidatorSize     uint `json:"appGossipValidatorSize"`
+	// Bounds on the number of peers each accepted container is gossiped to.
+	// Within these bounds, the number grows with the size of the validator
+	// set.
+	AdaptiveGossipMinSize uint `json:"adaptiveGossipMinSize"`
+	AdaptiveGossipMaxSize uint `json:"adaptiveGossipMaxSize"`
+	// Halflife of the average fraction of gossiped containers that were
+	// already seen by this node
+	AdaptiveGossipRedundancyHalflife time.Duration `json:"adaptiveGossipRedundancyHalflife"`
+	// Once the fraction of gossiped containers that were already seen exceeds
+	// this value, the number of peers gossiped to is reduced towards
+	// [AdaptiveGossipMinSize]. Must be in [0, 1).
+	AdaptiveGossipRedundancyThreshold float64 `json:"adaptiveGossipRedundancyThreshold"`
 }
 
 type ThrottlerConfig struct {
-	InboundConnUpgradeThrottlerConfig throttling.InboundConnUpgradeThrottlerConfig `json:"inboundConnUpgradeThrottlerConfig"`
-	InboundMsgThrottlerConfig         throttling.InboundMsgThrottlerConfig         `json:"inboundMsgThrottlerConfig"`
-	OutboundMsgThrottlerConfig        throttling.MsgByteThrottlerConfig            `json:"outboundMsgThrottlerConfig"`
-	MaxIncomingConnsPerSec            float64                                      `json:"maxIncomingConnsPerSec"`
+	InboundConnUpgradeThrottlerConfig throttling.InboundConnUpgradeThrottlerConfig      `json:"inboundConnUpgradeThrottlerConfig"`
+	InboundConnAttemptThrottlerConfig throttling.InboundConnAttemptThrottlerConfig      `json:"inboundConnAttemptThrottlerConfig"`
+	InboundMsgThrottlerConfig         throttling.InboundMsgThrottlerConfig              `json:"inboundMsgThrottlerConfig"`
+	OutboundMsgThrottlerConfig        throttling.MsgByteThrottlerConfig                 `json:"outboundMsgThrottlerConfig"`
+	OutboundSubnetBandwidthConfig     throttling.OutboundSubnetBandwidthThrottlerConfig `json:"outboundSubnetBandwidthConfig"`
+	OutboundBandwidthConfig           throttling.OutboundBandwidthThrottlerConfig       `json:"outboundBandwidthConfig"`
+	MaxIncomingConnsPerSec            float64                                           `json:"maxIncomingConnsPerSec"`
 }
 
 type Config struct {
@@ -234,14 +364,31 @@ type Config struct {
 	DialerConfig dialer.Config `json:"dialerConfig"`
 	TLSConfig    *tls.Config   `json:"-"`
 
-	Namespace          string              `json:"namespace"`
-	MyNodeID           ids.ShortID         `json:"myNodeID"`
-	MyIP               utils.DynamicIPDesc `json:"myIP"`
-	NetworkID          uint32              `json:"networkID"`
-	MaxClockDifference time.Duration       `json:"maxClockDifference"`
-	PingFrequency      time.Duration       `json:"pingFrequency"`
-	AllowPrivateIPs    bool                `json:"allowPrivateIPs"`
-	CompressionEnabled bool                `json:"compressionEnabled"`
+	Namespace string              `json:"namespace"`
+	MyNodeID  ids.ShortID         `json:"myNodeID"`
+	MyIP      utils.DynamicIPDesc `json:"myIP"`
+	// IPv6 address advertised in addition to [MyIP]. If zero, only [MyIP] is
+	// advertised.
+	MyIPv6             utils.IPDesc  `json:"myIPv6"`
+	NetworkID          uint32        `json:"networkID"`
+	MaxClockDifference time.Duration `json:"maxClockDifference"`
+	PingFrequency      time.Duration `json:"pingFrequency"`
+	AllowPrivateIPs    bool          `json:"allowPrivateIPs"`
+	CompressionEnabled bool          `json:"compressionEnabled"`
+	// Compression applied to compressable outbound messages when
+	// [CompressionEnabled]. Peers that don't support it are sent gzip
+	// compressed messages instead.
+	CompressionType compression.Type `json:"compressionType"`
+	// Compressable outbound messages are only compressed if their payload is
+	// at least this many bytes.
+	CompressionThreshold int `json:"compressionThreshold"`
+	// If true, outbound messages are packed with the protobuf schema. Peers
+	// that can't parse it are sent messages packed with the legacy codec.
+	ProtoMessagesEnabled bool `json:"protoMessagesEnabled"`
+	// If true, outbound connections are attempted over QUIC before falling
+	// back to TCP. The listener passed into the network is expected to accept
+	// QUIC connections as well.
+	QUICEnabled bool `json:"quicEnabled"`
 	// This node's TLS key
 	TLSKey crypto.Signer `json:"-"`
 	// WhitelistedSubnets of the node
@@ -252,11 +399,57 @@ type Config struct {
 	UptimeCalculator  uptime.Calculator  `json:"-"`
 	UptimeMetricFreq  time.Duration      `json:"uptimeMetricFreq"`
 	UptimeRequirement float64            `json:"uptimeRequirement"`
+	// Tracks how useful each peer has been. Poor peers are disconnected from.
+	// If nil, peers aren't scored.
+	PeerScorer common.PeerScorer `json:"-"`
+	// Max number of peers to remember across restarts. If 0, peers aren't
+	// remembered.
+	PeerStoreSize int `json:"peerStoreSize"`
+	// Persists the peers this node connected to. If nil, peers aren't
+	// persisted.
+	PeerStore peerstore.Store `json:"-"`
+	// The node IDs and IP ranges this node refuses to connect to. If nil,
+	// nothing is blocked.
+	Blocklist blocklist.Blocklist `json:"-"`
+	// If true, this node only connects to [AllowedNodeIDs] and to the
+	// validators of [WhitelistedSubnets]
+	AllowlistEnabled bool         `json:"allowlistEnabled"`
+	AllowedNodeIDs   ids.ShortSet `json:"allowedNodeIDs"`
+	// The nodes this node is allowed to connect to. If nil, every node is
+	// allowed. Nodes that aren't allowed should also be rejected during the
+	// TLS handshake, see AllowlistTLSConfig.
+	Allowlist allowlist.Allowlist `json:"-"`
+	// The policy that peer versions are checked against. If nil, the default
+	// policy of [NetworkID] is used.
+	VersionCompatibility version.Compatibility `json:"-"`
 
 	// Require that all connections must have at least one validator between the
 	// 2 peers. This can be useful to enable if the node wants to connect to the
 	// minimum number of nodes without impacting the network negatively.
 	RequireValidatorToConnect bool `json:"requireValidatorToConnect"`
+
+	// If non-empty, this node only holds connections to the beacons, to the
+	// validators of this subnet and to at most [PrimaryNetworkSampleSize] other
+	// primary network validators. Overrides [RequireValidatorToConnect].
+	// Useful for nodes that only validate this subnet.
+	ConnectivitySubnetID ids.ID `json:"connectivitySubnetID"`
+	// Max number of primary network validators, outside of
+	// [ConnectivitySubnetID], that this node holds connections to
+	PrimaryNetworkSampleSize int `json:"primaryNetworkSampleSize"`
+}
+
+// routerCPUTracker reports the CPU time that the chains of [router] recently
+// spent handling each node's messages
+type routerCPUTracker struct {
+	router router.Router
+}
+
+func (t routerCPUTracker) Utilization(nodeID ids.ShortID) float64 {
+	utilization := 0.0
+	for _, chainUtilization := range t.router.CPUUtilization(nodeID) {
+		utilization += chainUtilization
+	}
+	return utilization
 }
 
 // peerElement holds onto the peer object as a result of helper functions
@@ -295,25 +488,88 @@ func NewNetwork(
 		inboundConnUpgradeThrottler: throttling.NewInboundConnUpgradeThrottler(log, config.ThrottlerConfig.InboundConnUpgradeThrottlerConfig),
 		benchlistManager:            benchlistManager,
 		latestPeerIP:                make(map[ids.ShortID]signedPeerIP),
-		versionCompatibility:        version.GetCompatibility(config.NetworkID),
+		versionCompatibility:        config.VersionCompatibility,
 		config:                      config,
 		mc:                          msgCreator,
+		pingWheel:                   timer.NewDefaultWheel(pingWheelTick),
+		whitelistedSubnets:          ids.NewSet(config.WhitelistedSubnets.Len()),
+	}
+	netw.whitelistedSubnets.Union(config.WhitelistedSubnets)
+
+	if !config.MyIPv6.IsZero() {
+		netw.myIPs[config.MyIPv6.String()] = struct{}{}
+	}
+
+	if config.PeerScorer == nil {
+		config.PeerScorer = scoring.NewNoScorer()
+	}
+	if netw.versionCompatibility == nil {
+		netw.versionCompatibility = version.GetCompatibility(config.NetworkID)
+	}
+	if config.PeerStore == nil {
+		config.PeerStore = peerstore.NewNoStore()
+	}
+	if config.Blocklist == nil {
+		config.Blocklist = blocklist.NewNoBlocklist()
+	}
+	if config.Allowlist == nil {
+		config.Allowlist = allowlist.NewNoAllowlist()
 	}
 
 	netw.serverUpgrader = NewTLSServerUpgrader(config.TLSConfig)
 	netw.clientUpgrader = NewTLSClientUpgrader(config.TLSConfig)
 
-	netw.dialer = dialer.NewDialer(constants.NetworkType, config.DialerConfig, log)
+	netw.supportedFeatures = defaultFeatures
+	var err error
+	if config.QUICEnabled {
+		netw.quicDialer, err = dialer.NewQUICDialer(constants.NetworkType, config.DialerConfig, config.TLSConfig, log)
+		netw.dialer = netw.quicDialer
+		netw.supportedFeatures |= featureQUIC
+	} else {
+		netw.dialer, err = dialer.NewDialer(constants.NetworkType, config.DialerConfig, log)
+	}
+	if err != nil {
+		return nil, err
+	}
 	primaryNetworkValidators, ok := config.Validators.GetValidators(constants.PrimaryNetworkID)
 	if !ok {
 		return nil, errNoPrimaryValidators
 	}
 
+	netw.inboundConnAttemptThrottler, err = throttling.NewInboundConnAttemptThrottler(
+		config.Namespace,
+		metricsRegisterer,
+		config.ThrottlerConfig.InboundConnAttemptThrottlerConfig,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initializing inbound connection attempt throttler failed with: %w", err)
+	}
+
+	netw.outboundSubnetBandwidthThrottler, err = throttling.NewOutboundSubnetBandwidthThrottler(
+		config.Namespace,
+		metricsRegisterer,
+		config.ThrottlerConfig.OutboundSubnetBandwidthConfig,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initializing outbound subnet bandwidth throttler failed with: %w", err)
+	}
+
+	netw.outboundBandwidthThrottler, err = throttling.NewOutboundBandwidthThrottler(
+		log,
+		config.Namespace,
+		metricsRegisterer,
+		config.ThrottlerConfig.OutboundBandwidthConfig,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initializing outbound bandwidth throttler failed with: %w", err)
+	}
+
 	inboundMsgThrottler, err := throttling.NewInboundMsgThrottler(
 		log,
 		config.Namespace,
 		metricsRegisterer,
 		primaryNetworkValidators,
+		routerCPUTracker{router: router},
 		config.ThrottlerConfig.InboundMsgThrottlerConfig,
 	)
 	if err != nil {
@@ -334,6 +590,7 @@ func NewNetwork(
 	netw.outboundMsgThrottler = outboundMsgThrottler
 
 	netw.peers.initialize()
+	netw.gossipFanout = newGossipFanout(config.GossipConfig, &netw.clock)
 	netw.sendFailRateCalculator = math.NewSyncAverager(math.NewAverager(0, config.MaxSendFailRateHalflife, netw.clock.Time()))
 	if err := netw.metrics.initialize(config.Namespace, metricsRegisterer); err != nil {
 		return nil, fmt.Errorf("initializing network failed with: %w", err)
@@ -345,6 +602,7 @@ func NewNetwork(
 func (n *network) Send(msg message.OutboundMessage, nodeIDs ids.ShortSet, subnetID ids.ID, validatorOnly bool) ids.ShortSet {
 	// retrieve target peers
 	peers := n.getPeers(nodeIDs, subnetID, validatorOnly)
+	n.limitSubnetBandwidth(msg, subnetID, peers)
 	return n.send(msg, true, peers)
 }
 
@@ -366,9 +624,21 @@ func (n *network) Gossip(
 		msg.DecRef()
 		return nil
 	}
+	n.limitSubnetBandwidth(msg, subnetID, peers)
 	return n.send(msg, true, peers)
 }
 
+// limitSubnetBandwidth replaces with nil the peers in [peers] that [msg] can't
+// be sent to without exceeding the outbound bandwidth budget of [subnetID].
+func (n *network) limitSubnetBandwidth(msg message.OutboundMessage, subnetID ids.ID, peers []*peer) {
+	msgSize := uint64(len(msg.Bytes()))
+	for i, peer := range peers {
+		if peer != nil && !n.outboundSubnetBandwidthThrottler.Acquire(subnetID, msgSize) {
+			peers[i] = nil
+		}
+	}
+}
+
 // Accept is called after every consensus decision
 // Assumes [n.stateLock] is not held.
 func (n *network) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
@@ -386,7 +656,16 @@ func (n *network) Accept(ctx *snow.ConsensusContext, containerID ids.ID, contain
 		return fmt.Errorf("attempted to pack too large of a Put message.\nContainer length: %d", len(container))
 	}
 
-	n.Gossip(msg, ctx.SubnetID, ctx.IsValidatorOnly(), 0, int(n.config.GossipOnAcceptSize))
+	n.gossipFanout.accepted(containerID)
+
+	numValidators := 0
+	if vdrs, ok := n.config.Validators.GetValidators(ctx.SubnetID); ok {
+		numValidators = vdrs.Len()
+	}
+	gossipSize := n.gossipFanout.size(ctx.ChainID, numValidators)
+	n.metrics.gossipFanout.Observe(float64(gossipSize))
+
+	n.Gossip(msg, ctx.SubnetID, ctx.IsValidatorOnly(), 0, gossipSize)
 	return nil
 }
 
@@ -425,9 +704,18 @@ func (n *network) selectPeersForGossip(subnetID ids.ID, validatorOnly bool, numV
 func (n *network) send(msg message.OutboundMessage, connectedOnly bool, peers []*peer) ids.ShortSet {
 	var (
 		now    = n.clock.Time()
-		msgLen = len(msg.Bytes())
 		sentTo = ids.NewShortSet(len(peers))
 		op     = msg.Op()
+
+		// Peers that can't parse the compression type of [msg] are sent
+		// [fallbackMsg] instead. It's only created if it's needed.
+		fallbackMsg message.OutboundMessage
+		// Peers that can't parse the envelope of [msg] are sent [strippedMsg]
+		// instead. It's only created if it's needed.
+		strippedMsg message.OutboundMessage
+		// Peers that can't parse the protobuf schema are sent [legacyMsg]
+		// instead. It's only created if it's needed.
+		legacyMsg message.OutboundMessage
 	)
 
 	msgMetrics := n.metrics.messageMetrics[op]
@@ -443,20 +731,57 @@ func (n *network) send(msg message.OutboundMessage, connectedOnly bool, peers []
 	// send to peer and update metrics
 	// note: peer may be nil
 	for _, peer := range peers {
+		peerMsg := msg
+		switch {
+		case peer != nil && msg.IsProto() && !peer.supports(featureProtoMessages):
+			if legacyMsg == nil {
+				var err error
+				legacyMsg, err = n.mc.ToLegacy(msg)
+				if err != nil {
+					n.log.Error("failed to convert %s message to the legacy codec: %s", op, err)
+					legacyMsg = nil
+				}
+			}
+			peerMsg = legacyMsg
+		case peer != nil && msg.HasExtensions() && !peer.supports(featureEnvelope):
+			if strippedMsg == nil {
+				var err error
+				strippedMsg, err = n.mc.StripExtensions(msg)
+				if err != nil {
+					n.log.Error("failed to strip the envelope of %s message: %s", op, err)
+					strippedMsg = nil
+				}
+			}
+			peerMsg = strippedMsg
+		case peer != nil && !peer.canParse(msg.CompressionType()):
+			if fallbackMsg == nil {
+				var err error
+				fallbackMsg, err = n.mc.Recompress(msg, compression.TypeGzip)
+				if err != nil {
+					n.log.Error("failed to recompress %s message: %s", op, err)
+					fallbackMsg = nil
+				}
+			}
+			peerMsg = fallbackMsg
+		}
+
 		// Add a reference to the message so that if it is sent, it won't be
 		// collected until it is done being processed.
-		msg.AddRef()
+		if peerMsg != nil {
+			peerMsg.AddRef()
+		}
 		if peer != nil &&
+			peerMsg != nil &&
 			(!connectedOnly || peer.finishedHandshake.GetValue()) &&
 			!sentTo.Contains(peer.nodeID) &&
-			peer.Send(msg) {
+			peer.Send(peerMsg) {
 			sentTo.Add(peer.nodeID)
 
 			// record metrics for success
 			n.sendFailRateCalculator.Observe(0, now)
 			msgMetrics.numSent.Inc()
-			msgMetrics.sentBytes.Add(float64(msgLen))
-			if saved := msg.BytesSavedCompression(); saved != 0 {
+			msgMetrics.sentBytes.Add(float64(len(peerMsg.Bytes())))
+			if saved := peerMsg.BytesSavedCompression(); saved != 0 {
 				msgMetrics.savedSentBytes.Observe(float64(saved))
 			}
 		} else {
@@ -466,13 +791,24 @@ func (n *network) send(msg message.OutboundMessage, connectedOnly bool, peers []
 
 			// The message wasn't passed to the peer, so we should remove the
 			// reference that was added.
-			msg.DecRef()
+			if peerMsg != nil {
+				peerMsg.DecRef()
+			}
 		}
 	}
 
 	// The message has been passed to all peers that it will be sent to, so we
 	// can decrease the sender reference now.
 	msg.DecRef()
+	if fallbackMsg != nil {
+		fallbackMsg.DecRef()
+	}
+	if strippedMsg != nil {
+		strippedMsg.DecRef()
+	}
+	if legacyMsg != nil {
+		legacyMsg.DecRef()
+	}
 	return sentTo
 }
 
@@ -513,20 +849,68 @@ func (n *network) shouldUpgradeIncoming(ip utils.IPDesc) bool {
 // the provided peerID. If the node is attempting to connect to the minimum
 // number of peers, then it should only connect if this node is a validator, or
 // the peer is a validator/beacon.
+// Assumes [n.stateLock] is held.
 func (n *network) shouldHoldConnection(peerID ids.ShortID) bool {
+	if n.config.ConnectivitySubnetID != ids.Empty {
+		return n.shouldHoldSubnetConnection(peerID)
+	}
 	return !n.config.RequireValidatorToConnect ||
 		n.config.Validators.Contains(constants.PrimaryNetworkID, n.config.MyNodeID) ||
 		n.config.Validators.Contains(constants.PrimaryNetworkID, peerID) ||
 		n.config.Beacons.Contains(peerID)
 }
 
+// shouldHoldSubnetConnection returns true if this node, whose connectivity is
+// scoped to [n.config.ConnectivitySubnetID], should have a connection to the
+// provided peerID. Primary network validators outside of the subnet are only
+// connected to while the primary network sample isn't full.
+// Assumes [n.stateLock] is held.
+func (n *network) shouldHoldSubnetConnection(peerID ids.ShortID) bool {
+	return n.config.Beacons.Contains(peerID) ||
+		n.config.Validators.Contains(n.config.ConnectivitySubnetID, peerID) ||
+		n.primarySample.Contains(peerID) ||
+		(n.primarySample.Len() < n.config.PrimaryNetworkSampleSize &&
+			n.config.Validators.Contains(constants.PrimaryNetworkID, peerID))
+}
+
+// stillHoldsConnection returns true if this node should keep its connection to
+// the provided connected peerID. As validators join and leave
+// [n.config.ConnectivitySubnetID], they're moved out of and into the primary
+// network sample.
+// Assumes [n.stateLock] is not held.
+func (n *network) stillHoldsConnection(peerID ids.ShortID) bool {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.sampledPrimaryPeer(peerID) {
+		n.primarySample.Remove(peerID)
+		return n.shouldHoldConnection(peerID)
+	}
+	if !n.shouldHoldConnection(peerID) {
+		return false
+	}
+	n.primarySample.Add(peerID)
+	return true
+}
+
+// sampledPrimaryPeer returns true if [peerID] is only connected to as part of
+// the primary network sample.
+// Assumes [n.stateLock] is held.
+func (n *network) sampledPrimaryPeer(peerID ids.ShortID) bool {
+	return n.config.ConnectivitySubnetID != ids.Empty &&
+		!n.config.Beacons.Contains(peerID) &&
+		!n.config.Validators.Contains(n.config.ConnectivitySubnetID, peerID)
+}
+
 // Dispatch starts accepting connections from other nodes attempting to connect
 // to this node.
 // Assumes [n.stateLock] is not held.
 func (n *network) Dispatch() error {
-	go n.gossipPeerList()      // Periodically gossip peers
-	go n.updateUptimeMetrics() // Periodically update uptime metrics
-	go n.inboundConnUpgradeThrottler.Dispatch()
+	go n.log.RecoverAndPanic(n.gossipPeerList)      // Periodically gossip peers
+	go n.log.RecoverAndPanic(n.updateUptimeMetrics) // Periodically update uptime metrics
+	go n.log.RecoverAndPanic(n.gossipFanout.dispatch)
+	go n.log.RecoverAndPanic(n.pingWheel.Dispatch)
+	go n.log.RecoverAndPanic(n.inboundConnUpgradeThrottler.Dispatch)
 	defer n.inboundConnUpgradeThrottler.Stop()
 	go func() {
 		duration := time.Until(n.versionCompatibility.MaskTime())
@@ -576,6 +960,17 @@ func (n *network) Dispatch() error {
 		if err != nil {
 			return fmt.Errorf("unable to convert remote address %s to IPDesc: %w", remoteAddr, err)
 		}
+		if n.config.Blocklist.IsIPBlocked(ip.IP) {
+			n.log.Debug("not upgrading connection to %s because it's blocked", ip)
+			n.metrics.inboundConnBlocked.Inc()
+			_ = conn.Close()
+			continue
+		}
+		if !n.inboundConnAttemptThrottler.Allow(ip) {
+			n.log.Debug("not upgrading connection to %s because too many connection attempts were made from it", ip)
+			_ = conn.Close()
+			continue
+		}
 		upgrade := n.shouldUpgradeIncoming(ip)
 		if !upgrade {
 			_ = conn.Close()
@@ -600,31 +995,108 @@ func (n *network) Dispatch() error {
 }
 
 // Returns information about peers.
-// If [nodeIDs] is empty, returns info about all peers that have finished
-// the handshake. Otherwise, returns info about the peers in [nodeIDs]
-// that have finished the handshake.
+// Peers returns info about the peers that match [filter], sorted by node ID.
 // Assumes [n.stateLock] is not held.
-func (n *network) Peers(nodeIDs []ids.ShortID) []PeerInfo {
+func (n *network) Peers(filter PeersFilter) []PeerInfo {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
 
-	if len(nodeIDs) == 0 { // Return info about all peers
-		peers := make([]PeerInfo, 0, n.peers.size())
+	var peers []*peer
+	if len(filter.NodeIDs) == 0 { // Consider all peers
+		peers = make([]*peer, 0, n.peers.size())
 		for _, peer := range n.peers.peersList {
-			if peer.finishedHandshake.GetValue() {
-				peers = append(peers, n.NewPeerInfo(peer))
+			if n.matches(peer, filter) {
+				peers = append(peers, peer)
 			}
 		}
-		return peers
+	} else { // Consider the given peers
+		peers = make([]*peer, 0, len(filter.NodeIDs))
+		nodeIDs := ids.NewShortSet(len(filter.NodeIDs))
+		for _, nodeID := range filter.NodeIDs {
+			if nodeIDs.Contains(nodeID) {
+				continue
+			}
+			nodeIDs.Add(nodeID)
+			if peer, ok := n.peers.getByID(nodeID); ok && n.matches(peer, filter) {
+				peers = append(peers, peer)
+			}
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return bytes.Compare(peers[i].nodeID[:], peers[j].nodeID[:]) < 0
+	})
+	if filter.Limit > 0 && len(peers) > filter.Limit {
+		peers = peers[:filter.Limit]
+	}
+
+	peerInfos := make([]PeerInfo, len(peers))
+	for i, peer := range peers {
+		peerInfos[i] = n.NewPeerInfo(peer)
 	}
+	return peerInfos
+}
 
-	peers := make([]PeerInfo, 0, len(nodeIDs))
-	for _, nodeID := range nodeIDs { // Return info about given peers
-		if peer, ok := n.peers.getByID(nodeID); ok && peer.finishedHandshake.GetValue() {
-			peers = append(peers, n.NewPeerInfo(peer))
+// matches returns true if [peer] matches [filter], ignoring [filter.NodeIDs]
+// and [filter.Limit].
+// Assumes [n.stateLock] is held.
+func (n *network) matches(peer *peer, filter PeersFilter) bool {
+	finishedHandshake := peer.finishedHandshake.GetValue()
+	switch {
+	case !finishedHandshake && !filter.IncludeHandshaking:
+		return false
+	case bytes.Compare(peer.nodeID[:], filter.StartNodeID[:]) < 0:
+		return false
+	case filter.BenchedOnly && len(n.benchlistManager.GetBenched(peer.nodeID)) == 0:
+		return false
+	}
+
+	subnetID := constants.PrimaryNetworkID
+	if filter.SubnetID != nil {
+		subnetID = *filter.SubnetID
+	}
+	// The tracked subnets are only known once the handshake is finished
+	if subnetID != constants.PrimaryNetworkID && (!finishedHandshake || !peer.trackedSubnets.Contains(subnetID)) {
+		return false
+	}
+	return !filter.ValidatorOnly || n.config.Validators.Contains(subnetID, peer.nodeID)
+}
+
+// PeerScores implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) PeerScores(nodeIDs []ids.ShortID) []PeerScore {
+	var sortedNodeIDs []ids.ShortID
+	if len(nodeIDs) == 0 {
+		n.stateLock.RLock()
+		sortedNodeIDs = make([]ids.ShortID, 0, n.peers.size())
+		for _, peer := range n.peers.peersList {
+			if peer.finishedHandshake.GetValue() {
+				sortedNodeIDs = append(sortedNodeIDs, peer.nodeID)
+			}
 		}
+		n.stateLock.RUnlock()
+	} else {
+		nodeIDSet := ids.NewShortSet(len(nodeIDs))
+		nodeIDSet.Add(nodeIDs...)
+		sortedNodeIDs = nodeIDSet.List()
 	}
-	return peers
+	ids.SortShortIDs(sortedNodeIDs)
+
+	scores := make([]PeerScore, len(sortedNodeIDs))
+	for i, nodeID := range sortedNodeIDs {
+		utilizations := n.router.CPUUtilization(nodeID)
+		cpuUtilization := make(map[ids.ID]json.Float64, len(utilizations))
+		for chainID, utilization := range utilizations {
+			cpuUtilization[chainID] = json.Float64(utilization)
+		}
+		scores[i] = PeerScore{
+			ID:             nodeID.PrefixedString(constants.NodeIDPrefix),
+			Score:          json.Float64(n.config.PeerScorer.Score(nodeID)),
+			PoorScore:      n.config.PeerScorer.IsPoor(nodeID),
+			BenchInfo:      n.benchlistManager.GetBenchInfo(nodeID),
+			CPUUtilization: cpuUtilization,
+		}
+	}
+	return scores
 }
 
 func (n *network) NewPeerInfo(peer *peer) PeerInfo {
@@ -632,15 +1104,38 @@ func (n *network) NewPeerInfo(peer *peer) PeerInfo {
 	if !peer.ip.IsZero() {
 		publicIPStr = peer.getIP().String()
 	}
+	publicIPv6Str := ""
+	if signedIPv6, ok := peer.ipv6.GetValue().(signedPeerIP); ok {
+		publicIPv6Str = signedIPv6.ip.String()
+	}
+	versionStr, _ := peer.versionStr.GetValue().(string)
+	// The tracked subnets are only known once the handshake is finished
+	finishedHandshake := peer.finishedHandshake.GetValue()
+	var trackedSubnets []ids.ID
+	if finishedHandshake {
+		trackedSubnets = peer.trackedSubnets.List()
+		ids.SortIDs(trackedSubnets)
+	}
 	return PeerInfo{
-		IP:             peer.conn.RemoteAddr().String(),
-		PublicIP:       publicIPStr,
-		ID:             peer.nodeID.PrefixedString(constants.NodeIDPrefix),
-		Version:        peer.versionStr.GetValue().(string),
-		LastSent:       time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
-		LastReceived:   time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
-		Benched:        n.benchlistManager.GetBenched(peer.nodeID),
-		ObservedUptime: json.Uint8(peer.observedUptime),
+		IP:               peer.conn.RemoteAddr().String(),
+		PublicIP:         publicIPStr,
+		PublicIPv6:       publicIPv6Str,
+		ID:               peer.nodeID.PrefixedString(constants.NodeIDPrefix),
+		Version:          versionStr,
+		Connected:        finishedHandshake,
+		LastSent:         time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
+		LastReceived:     time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+		Benched:          n.benchlistManager.GetBenched(peer.nodeID),
+		ObservedUptime:   json.Uint8(peer.observedUptime),
+		BenchInfo:        n.benchlistManager.GetBenchInfo(peer.nodeID),
+		PoorScore:        n.config.PeerScorer.IsPoor(peer.nodeID),
+		TrackedSubnets:   trackedSubnets,
+		Features:         peer.features(),
+		BytesSent:        json.Uint64(atomic.LoadUint64(&peer.bytesSent)),
+		BytesReceived:    json.Uint64(atomic.LoadUint64(&peer.bytesReceived)),
+		MessagesSent:     peer.msgsSent.Counts(),
+		MessagesReceived: peer.msgsReceived.Counts(),
+		LatencyMs:        json.Float64(float64(atomic.LoadInt64(&peer.latency)) / float64(time.Millisecond)),
 	}
 }
 
@@ -668,6 +1163,8 @@ func (n *network) close() {
 		return
 	}
 	n.closed.SetValue(true)
+	n.gossipFanout.stop()
+	n.pingWheel.Stop()
 
 	peersToClose := make([]*peer, n.peers.size())
 	copy(peersToClose, n.peers.peersList)
@@ -743,6 +1240,197 @@ func (n *network) NodeUptime() (UptimeResult, bool) {
 	}, true
 }
 
+// UptimeView implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) UptimeView() (UptimeView, bool) {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	primaryValidators, ok := n.config.Validators.GetValidators(constants.PrimaryNetworkID)
+	if !ok || !primaryValidators.Contains(n.config.MyNodeID) {
+		return UptimeView{}, false
+	}
+
+	builder := newUptimeViewBuilder(primaryValidators, n.config.UptimeRequirement)
+	builder.add(n.config.MyNodeID, 100)
+	for _, peer := range n.peers.peersList {
+		if peer.finishedHandshake.GetValue() {
+			builder.add(peer.nodeID, peer.observedUptime)
+		}
+	}
+	return builder.build(), true
+}
+
+// VersionCensus implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) VersionCensus() VersionCensus {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	primaryValidators, ok := n.config.Validators.GetValidators(constants.PrimaryNetworkID)
+	if !ok {
+		primaryValidators = validators.NewSet()
+	}
+	builder := newVersionCensusBuilder(primaryValidators)
+
+	mySubnets := ids.NewSet(n.whitelistedSubnets.Len() + 1)
+	mySubnets.Union(n.whitelistedSubnets)
+	mySubnets.Add(constants.PrimaryNetworkID)
+	builder.add(n.versionCompatibility.Version().String(), n.config.MyNodeID, mySubnets)
+
+	for _, peer := range n.peers.peersList {
+		if peer.finishedHandshake.GetValue() {
+			builder.add(peer.versionStr.GetValue().(string), peer.nodeID, peer.trackedSubnets)
+		}
+	}
+	return builder.build()
+}
+
+// VersionPolicy implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) VersionPolicy() VersionPolicy {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	primaryValidators, ok := n.config.Validators.GetValidators(constants.PrimaryNetworkID)
+	if !ok {
+		primaryValidators = validators.NewSet()
+	}
+	builder := newVersionPolicyBuilder(n.versionCompatibility, primaryValidators)
+	for _, peer := range n.peers.peersList {
+		if peer.finishedHandshake.GetValue() {
+			builder.add(peer.nodeID, peer.versionStruct.GetValue().(version.Application))
+		}
+	}
+	return builder.build()
+}
+
+// Assumes [n.stateLock] is not held.
+func (n *network) Block(nodeIDs []ids.ShortID, ipRanges []*net.IPNet) error {
+	for _, nodeID := range nodeIDs {
+		if err := n.config.Blocklist.BlockNodeID(nodeID); err != nil {
+			return err
+		}
+	}
+	for _, ipRange := range ipRanges {
+		if err := n.config.Blocklist.BlockIPRange(ipRange); err != nil {
+			return err
+		}
+	}
+
+	n.stateLock.RLock()
+	blockedPeers := []*peer(nil)
+	for _, peer := range n.peers.peersList {
+		// The IP the peer connected from may differ from the IP it claims
+		remoteIP, _ := utils.ToIPDesc(peer.conn.RemoteAddr().String())
+		if n.isBlocked(peer.nodeID, peer.getIP()) || n.isBlocked(ids.ShortEmpty, remoteIP) {
+			blockedPeers = append(blockedPeers, peer)
+		}
+	}
+	n.stateLock.RUnlock()
+
+	// Closing a peer grabs [n.stateLock], so it must not be held here
+	for _, peer := range blockedPeers {
+		n.log.Info("disconnecting from %s%s because it was blocked", constants.NodeIDPrefix, peer.nodeID)
+		peer.Close()
+	}
+	return nil
+}
+
+// Assumes [n.stateLock] is not held.
+func (n *network) Unblock(nodeIDs []ids.ShortID, ipRanges []*net.IPNet) error {
+	for _, nodeID := range nodeIDs {
+		if err := n.config.Blocklist.UnblockNodeID(nodeID); err != nil {
+			return err
+		}
+	}
+	for _, ipRange := range ipRanges {
+		if err := n.config.Blocklist.UnblockIPRange(ipRange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *network) Blocked() ([]ids.ShortID, []*net.IPNet) {
+	return n.config.Blocklist.NodeIDs(), n.config.Blocklist.IPRanges()
+}
+
+func (n *network) SetGossipConfig(config GossipConfig) {
+	n.gossipFanout.setConfig(config)
+}
+
+func (n *network) SetInboundMsgThrottlerConfig(config throttling.InboundMsgThrottlerConfig) {
+	n.inboundMsgThrottler.SetLimits(config)
+}
+
+// Assumes [n.stateLock] is not held.
+func (n *network) TrackSubnet(subnetID ids.ID) {
+	n.stateLock.Lock()
+	if n.whitelistedSubnets.Contains(subnetID) {
+		n.stateLock.Unlock()
+		return
+	}
+	n.whitelistedSubnets.Add(subnetID)
+	for _, peer := range n.peers.peersList {
+		if peer.advertisedSubnets.Contains(subnetID) {
+			peer.trackedSubnets.Add(subnetID)
+		}
+	}
+	n.stateLock.Unlock()
+
+	n.log.Info("started tracking subnet %s", subnetID)
+	n.sendSubnets()
+}
+
+// Assumes [n.stateLock] is not held.
+func (n *network) UntrackSubnet(subnetID ids.ID) {
+	if subnetID == constants.PrimaryNetworkID {
+		return
+	}
+
+	n.stateLock.Lock()
+	if !n.whitelistedSubnets.Contains(subnetID) {
+		n.stateLock.Unlock()
+		return
+	}
+	n.whitelistedSubnets.Remove(subnetID)
+	for _, peer := range n.peers.peersList {
+		peer.trackedSubnets.Remove(subnetID)
+	}
+	n.stateLock.Unlock()
+
+	n.log.Info("stopped tracking subnet %s", subnetID)
+	n.sendSubnets()
+}
+
+// sendSubnets tells the peers that can parse Subnets messages which subnets
+// this node tracks. Peers that can't learn them the next time they connect.
+// Assumes [n.stateLock] is not held.
+func (n *network) sendSubnets() {
+	n.stateLock.RLock()
+	msg, err := n.mc.Subnets(n.whitelistedSubnets.List())
+	peers := []*peer(nil)
+	for _, peer := range n.peers.peersList {
+		if peer.finishedHandshake.GetValue() && peer.supports(featureSubnets) {
+			peers = append(peers, peer)
+		}
+	}
+	n.stateLock.RUnlock()
+
+	n.log.AssertNoError(err)
+
+	n.send(msg, true, peers)
+}
+
+// isBlocked returns true if [nodeID] or [ip] is blocked, or if [nodeID] isn't
+// allowed. [nodeID] may be empty and [ip] may be zero if they aren't known.
+func (n *network) isBlocked(nodeID ids.ShortID, ip utils.IPDesc) bool {
+	return (nodeID != ids.ShortEmpty && n.config.Blocklist.IsNodeIDBlocked(nodeID)) ||
+		(nodeID != ids.ShortEmpty && !n.config.Allowlist.IsAllowed(nodeID)) ||
+		(!ip.IsZero() && n.config.Blocklist.IsIPBlocked(ip.IP))
+}
+
 // assumes the stateLock is held.
 // Try to connect to [nodeID] at [ip].
 func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
@@ -763,6 +1451,14 @@ func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
 	if _, ok := n.myIPs[str]; ok {
 		return
 	}
+	if n.isBlocked(nodeID, ip) {
+		return
+	}
+	// Don't dial nodes outside of the subnet this node's connectivity is
+	// scoped to, unless they're needed for the primary network sample
+	if n.config.ConnectivitySubnetID != ids.Empty && nodeID != ids.ShortEmpty && !n.shouldHoldSubnetConnection(nodeID) {
+		return
+	}
 	// If we saw an IP gossiped for this node ID
 	// with a later timestamp, don't track this old IP
 	if latestIP, ok := n.latestPeerIP[nodeID]; ok {
@@ -772,7 +1468,7 @@ func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
 	}
 	n.disconnectedIPs[str] = struct{}{}
 
-	go n.connectTo(ip, nodeID)
+	go n.log.RecoverAndPanic(func() { n.connectTo(ip, nodeID) })
 }
 
 // Assumes [n.stateLock] is not held. Only returns after the network is closed.
@@ -785,32 +1481,116 @@ func (n *network) gossipPeerList() {
 			return
 		}
 
-		ipCerts, err := n.validatorIPs()
+		n.refreshSignedIP()
+
+		numStakersToSend := int((n.config.PeerListGossipSize + n.config.PeerListStakerGossipFraction - 1) / n.config.PeerListStakerGossipFraction)
+		numNonStakersToSend := int(n.config.PeerListGossipSize) - numStakersToSend
+
+		peers, err := n.selectPeersForGossip(constants.PrimaryNetworkID, false, numStakersToSend, numNonStakersToSend)
 		if err != nil {
-			n.log.Error("failed to fetch validator IPs: %s", err)
+			n.log.Error("failed to sample peers: %s", err)
 			continue
 		}
 
-		if len(ipCerts) == 0 {
-			n.log.Debug("skipping validator IP gossiping as no IPs are connected")
-			continue
+		// Peers reached over IPv6 are sent the IPv6 addresses of validators
+		// that advertise one.
+		var ipv4Peers, ipv6Peers []*peer
+		for _, p := range peers {
+			if p.prefersIPv6() {
+				ipv6Peers = append(ipv6Peers, p)
+			} else {
+				ipv4Peers = append(ipv4Peers, p)
+			}
 		}
+		n.gossipPeerListTo(ipv4Peers, false)
+		n.gossipPeerListTo(ipv6Peers, true)
+	}
+}
 
-		msg, err := n.mc.PeerList(ipCerts)
-		if err != nil {
-			n.log.Error("failed to build signed peerlist to gossip: %s. len(ips): %d",
-				err,
-				len(ipCerts))
+// refreshSignedIP re-signs this node's IP once its signature is old enough, and
+// sends the new signature to the peers that can parse SignedIP messages, so
+// that the IP they gossip for this node never becomes stale. Peers that can't
+// learn the new signature the next time they connect.
+// Assumes [n.stateLock] is not held.
+func (n *network) refreshSignedIP() {
+	n.stateLock.RLock()
+	ip := n.currentIP.IP()
+	if ip.IsZero() {
+		n.stateLock.RUnlock()
+		return
+	}
+	n.timeForIPLock.Lock()
+	lastTimestamp := n.lastVersionTimestamp
+	n.timeForIPLock.Unlock()
+	timestamp, sig, err := n.getVersion(ip)
+	if err != nil || timestamp == lastTimestamp {
+		n.stateLock.RUnlock()
+		return
+	}
+
+	msg, err := n.mc.SignedIP(ip, timestamp, sig)
+	var signedIPPeers, ipv6Peers []*peer
+	for _, peer := range n.peers.peersList {
+		if !peer.finishedHandshake.GetValue() {
 			continue
 		}
+		if peer.supports(featureSignedIP) {
+			signedIPPeers = append(signedIPPeers, peer)
+		}
+		if peer.supports(featureIPv6) {
+			ipv6Peers = append(ipv6Peers, peer)
+		}
+	}
+	n.stateLock.RUnlock()
 
-		numStakersToSend := int((n.config.PeerListGossipSize + n.config.PeerListStakerGossipFraction - 1) / n.config.PeerListStakerGossipFraction)
-		numNonStakersToSend := int(n.config.PeerListGossipSize) - numStakersToSend
+	n.log.AssertNoError(err)
+	n.log.Debug("refreshed the signature of this node's IP %s with timestamp %d", ip, timestamp)
 
-		n.Gossip(msg, constants.PrimaryNetworkID, false, numStakersToSend, numNonStakersToSend)
+	n.send(msg, true, signedIPPeers)
+	// The IPv6 address is signed with the same timestamp, so its signature
+	// was refreshed as well
+	for _, peer := range ipv6Peers {
+		peer.sendIPv6()
 	}
 }
 
+// isIPStale returns true if an IP signed at [timestamp] is too old to be
+// gossiped, as the node that signed it may have moved since
+func (n *network) isIPStale(timestamp uint64) bool {
+	maxAge := uint64(n.config.PeerListMaxIPAge / time.Second)
+	return maxAge > 0 && timestamp+maxAge < n.clock.Unix()
+}
+
+// Sends the IPs of validators to [peers]. If [preferIPv6], validators' IPv6
+// addresses are sent when they are known.
+// Assumes [n.stateLock] is not held.
+func (n *network) gossipPeerListTo(peers []*peer, preferIPv6 bool) {
+	if len(peers) == 0 {
+		return
+	}
+
+	ipCerts, err := n.validatorIPs(preferIPv6)
+	if err != nil {
+		n.log.Error("failed to fetch validator IPs: %s", err)
+		return
+	}
+
+	if len(ipCerts) == 0 {
+		n.log.Debug("skipping validator IP gossiping as no IPs are connected")
+		return
+	}
+
+	msg, err := n.mc.PeerList(ipCerts)
+	if err != nil {
+		n.log.Error("failed to build signed peerlist to gossip: %s. len(ips): %d",
+			err,
+			len(ipCerts))
+		return
+	}
+
+	n.send(msg, true, peers)
+}
+
 // Assumes [n.stateLock] is not held. Only returns after the network is closed.
 func (n *network) updateUptimeMetrics() {
 	t := time.NewTicker(n.config.UptimeMetricFreq)
@@ -830,6 +1610,7 @@ func (n *network) updateUptimeMetrics() {
 // * We connected to [ip]
 // * The network is closed
 // * We gave up connecting to [ip] because the IP is stale
+// * We gave up connecting to [ip] after [MaxReconnectAttempts] failed attempts
 // If [nodeID] == ids.ShortEmpty, won't cancel an existing
 // attempt to connect to the peer with that IP.
 // We do this so we don't cancel attempted to connect to bootstrap beacons.
@@ -841,21 +1622,16 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 	delay := n.retryDelay[str]
 	n.stateLock.RUnlock()
 
+	// Bootstrap beacons are tracked without a node ID
+	peerStr := str
+	if nodeID != ids.ShortEmpty {
+		peerStr = nodeID.PrefixedString(constants.NodeIDPrefix)
+	}
+
+	var failedAttempts uint32
 	for {
 		time.Sleep(delay)
-
-		if delay == 0 {
-			delay = n.config.InitialReconnectDelay
-		}
-
-		// Randomization is only performed here to distribute reconnection
-		// attempts to a node that previously shut down. This doesn't require
-		// cryptographically secure random number generation.
-		delay = time.Duration(float64(delay) * (1 + rand.Float64())) // #nosec G404
-		if delay > n.config.MaxReconnectDelay {
-			// set the timeout to [.75, 1) * maxReconnectDelay
-			delay = time.Duration(float64(n.config.MaxReconnectDelay) * (3 + rand.Float64()) / 4) // #nosec G404
-		}
+		delay = n.nextReconnectDelay(delay)
 
 		n.stateLock.Lock()
 		_, isDisconnected := n.disconnectedIPs[str]
@@ -868,8 +1644,12 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 			isLatestIP = latestIP.ip.Equal(ip)
 		}
 		closed := n.closed
+		isBlocked := n.isBlocked(nodeID, ip)
+		if isBlocked {
+			delete(n.disconnectedIPs, str)
+		}
 
-		if !isDisconnected || !isLatestIP || isConnected || isMyself || closed.GetValue() {
+		if !isDisconnected || !isLatestIP || isConnected || isMyself || isBlocked || closed.GetValue() {
 			// If the IP was discovered by the peer connecting to us, we don't
 			// need to attempt to connect anymore
 
@@ -879,6 +1659,8 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 			// If the IP was discovered to be our IP address, we don't need to
 			// attempt to connect anymore
 
+			// If the peer was blocked, we should never connect to it
+
 			// If the network was closed, we should stop attempting to connect
 			// to the peer
 
@@ -913,7 +1695,28 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 
 		n.connAttempts.Delete(nodeID)
 
+		n.metrics.dialAttempts.Inc()
 		if err == nil {
+			n.metrics.consecutiveDialFailures.Observe(float64(failedAttempts))
+			return
+		}
+		n.metrics.dialFailures.Inc()
+		failedAttempts++
+
+		if nodeID != ids.ShortEmpty &&
+			n.config.MaxReconnectAttempts > 0 &&
+			failedAttempts >= n.config.MaxReconnectAttempts {
+			n.log.Debug("giving up on connecting to %s at %s after %d failed attempts: %s",
+				peerStr, ip, failedAttempts, err)
+			n.metrics.dialGiveUps.Inc()
+			n.metrics.consecutiveDialFailures.Observe(float64(failedAttempts))
+
+			// Stop tracking [ip] so that it's tracked again if it's learned
+			// again. [n.retryDelay] is kept so that the next attempts to
+			// connect to [ip] don't start from the initial delay.
+			n.stateLock.Lock()
+			delete(n.disconnectedIPs, str)
+			n.stateLock.Unlock()
 			return
 		}
 		n.log.Verbo("error attempting to connect to %s: %s. Reattempting in %s",
@@ -921,6 +1724,25 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 	}
 }
 
+// nextReconnectDelay returns how long to wait before the next attempt to
+// connect to a peer, given that we waited [delay] before the previous attempt.
+func (n *network) nextReconnectDelay(delay time.Duration) time.Duration {
+	if delay == 0 {
+		delay = n.config.InitialReconnectDelay
+	}
+
+	// Randomization is only performed here to distribute reconnection
+	// attempts to a node that previously shut down. This doesn't require
+	// cryptographically secure random number generation.
+	jitter := n.config.ReconnectDelayJitter
+	delay = time.Duration(float64(delay) * n.config.ReconnectDelayMultiplier * (1 + jitter*(2*rand.Float64()-1))) // #nosec G404
+	if delay > n.config.MaxReconnectDelay {
+		// set the timeout to [1-jitter, 1] * maxReconnectDelay
+		delay = time.Duration(float64(n.config.MaxReconnectDelay) * (1 - jitter*rand.Float64())) // #nosec G404
+	}
+	return delay
+}
+
 // Attempt to connect to the peer at [ip].
 // If [ctx] is canceled, stops trying to connect.
 // Returns nil if:
@@ -1016,6 +1838,15 @@ func (n *network) tryAddPeer(p *peer) error {
 		return errPeerIsMyself
 	}
 
+	if n.isBlocked(p.nodeID, ip) {
+		if !ip.IsZero() {
+			str := ip.String()
+			delete(n.disconnectedIPs, str)
+			delete(n.retryDelay, str)
+		}
+		return fmt.Errorf("%w: %s at %s", errPeerIsBlocked, p.nodeID.PrefixedString(constants.NodeIDPrefix), ip)
+	}
+
 	if !n.shouldHoldConnection(p.nodeID) {
 		if !ip.IsZero() {
 			str := ip.String()
@@ -1037,6 +1868,9 @@ func (n *network) tryAddPeer(p *peer) error {
 		return fmt.Errorf("duplicated connection from %s at %s", p.nodeID.PrefixedString(constants.NodeIDPrefix), ip)
 	}
 
+	if n.sampledPrimaryPeer(p.nodeID) {
+		n.primarySample.Add(p.nodeID)
+	}
 	n.peers.add(p)
 	n.metrics.numPeers.Set(float64(n.peers.size()))
 	p.Start()
@@ -1044,9 +1878,11 @@ func (n *network) tryAddPeer(p *peer) error {
 }
 
 // Returns the IPs, certs and signatures of validators we're connected
-// to that have finished the handshake.
+// to that have finished the handshake. If [preferIPv6], a validator's IPv6
+// address is returned instead of the IP we're connected to it at, if the
+// validator advertised one.
 // Assumes [n.stateLock] is not held.
-func (n *network) validatorIPs() ([]utils.IPCertDesc, error) {
+func (n *network) validatorIPs(preferIPv6 bool) ([]utils.IPCertDesc, error) {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
 
@@ -1097,13 +1933,20 @@ func (n *network) validatorIPs() ([]utils.IPCertDesc, error) {
 			continue
 		}
 
+		// Peers that don't support SignedIP messages can only sign their IP
+		// during the handshake, so their IP is gossiped however old it is
+		canRefresh := peer.supports(featureSignedIP)
 		signedIP, ok := peer.sigAndTime.GetValue().(signedPeerIP)
-		if !ok || !signedIP.ip.Equal(peerIP) {
+		if !ok || !signedIP.ip.Equal(peerIP) || (canRefresh && n.isIPStale(signedIP.time)) {
 			continue
 		}
 
+		if signedIPv6, ok := peer.ipv6.GetValue().(signedPeerIP); preferIPv6 && ok && (!canRefresh || !n.isIPStale(signedIPv6.time)) {
+			signedIP = signedIPv6
+		}
+
 		res = append(res, utils.IPCertDesc{
-			IPDesc:    peerIP,
+			IPDesc:    signedIP.ip,
 			Signature: signedIP.signature,
 			Cert:      peer.cert,
 			Time:      signedIP.time,
@@ -1152,12 +1995,24 @@ func (n *network) connected(p *peer) {
 	if !ip.IsZero() {
 		str := ip.String()
 
+		if err := n.config.PeerStore.Connected(p.nodeID, ip, n.clock.Time()); err != nil {
+			n.log.Warn("failed to persist peer %s at %s due to %s", p.nodeID, ip, err)
+		}
+
 		delete(n.disconnectedIPs, str)
 		delete(n.retryDelay, str)
 		n.connectedIPs[str] = struct{}{}
 	}
 
-	n.router.Connected(p.nodeID, peerVersion)
+	// The set is copied, since the peer may change the subnets it tracks
+	trackedSubnets := ids.NewSet(p.trackedSubnets.Len())
+	trackedSubnets.Union(p.trackedSubnets)
+	n.router.Connected(p.nodeID, validators.PeerInfo{
+		Version:        peerVersion,
+		IP:             ip,
+		TrackedSubnets: trackedSubnets,
+		Features:       featureList(n.supportedFeatures & atomic.LoadUint64(&p.supportedFeatures)),
+	})
 	n.metrics.connected.Inc()
 }
 
@@ -1171,6 +2026,7 @@ func (n *network) disconnected(p *peer) {
 	n.log.Debug("disconnected from %s at %s", p.nodeID, ip)
 
 	n.peers.remove(p)
+	n.primarySample.Remove(p.nodeID)
 	n.metrics.numPeers.Set(float64(n.peers.size()))
 
 	p.releaseAllAliases()
@@ -1190,6 +2046,7 @@ func (n *network) disconnected(p *peer) {
 	if p.finishedHandshake.GetValue() {
 		n.router.Disconnected(p.nodeID)
 	}
+	n.metrics.pingLatency.DeleteLabelValues(p.nodeID.PrefixedString(constants.NodeIDPrefix))
 	n.metrics.disconnected.Inc()
 }
 
@@ -1251,10 +2108,13 @@ func (n *network) getPeers(nodeIDs ids.ShortSet, subnetID ids.ID, validatorOnly
 func (n *network) HealthCheck() (interface{}, error) {
 	// Get some data with the state lock held
 	connectedTo := 0
+	connectedIDs := ids.ShortSet{}
+	connectedIDs.Add(n.config.MyNodeID)
 	n.stateLock.RLock()
 	for _, peer := range n.peers.peersList {
 		if peer != nil && peer.finishedHandshake.GetValue() {
 			connectedTo++
+			connectedIDs.Add(peer.nodeID)
 		}
 	}
 	sendFailRate := n.sendFailRateCalculator.Read()
@@ -1285,6 +2145,26 @@ func (n *network) HealthCheck() (interface{}, error) {
 	details["timeSinceLastMsgSent"] = timeSinceLastMsgSent.String()
 	n.metrics.timeSinceLastMsgSent.Set(float64(timeSinceLastMsgSent))
 
+	// Make sure we've received a consensus message within the threshold
+	isConsensusMsgRcvd := true
+	if maxTime := n.config.HealthConfig.MaxTimeSinceConsensusMsgReceived; maxTime != 0 {
+		lastConsensusMsgReceivedAt := time.Unix(atomic.LoadInt64(&n.lastConsensusMsgReceivedTime), 0)
+		timeSinceLastConsensusMsgReceived := now.Sub(lastConsensusMsgReceivedAt)
+		isConsensusMsgRcvd = timeSinceLastConsensusMsgReceived <= maxTime
+		details["timeSinceLastConsensusMsgReceived"] = timeSinceLastConsensusMsgReceived.String()
+	}
+	healthy = healthy && isConsensusMsgRcvd
+
+	// Make sure we're connected to enough of the primary network's stake
+	connectedStake, err := n.connectedStakePortion(connectedIDs)
+	if err != nil {
+		return details, fmt.Errorf("couldn't calculate connected stake: %w", err)
+	}
+	isConnectedStake := connectedStake >= n.config.HealthConfig.MinConnectedStake
+	healthy = healthy && isConnectedStake
+	details["connectedStake"] = connectedStake
+	n.metrics.connectedStakePortion.Set(connectedStake)
+
 	// Make sure the message send failed rate isn't too high
 	isMsgFailRate := sendFailRate <= n.config.HealthConfig.MaxSendFailRate
 	healthy = healthy && isMsgFailRate
@@ -1303,6 +2183,12 @@ func (n *network) HealthCheck() (interface{}, error) {
 		if !isMsgSent {
 			errorReasons = append(errorReasons, fmt.Sprintf("no messages from network sent in %s > %s", timeSinceLastMsgSent, n.config.HealthConfig.MaxTimeSinceMsgSent))
 		}
+		if !isConsensusMsgRcvd {
+			errorReasons = append(errorReasons, fmt.Sprintf("no consensus messages from network received in %s > %s", details["timeSinceLastConsensusMsgReceived"], n.config.HealthConfig.MaxTimeSinceConsensusMsgReceived))
+		}
+		if !isConnectedStake {
+			errorReasons = append(errorReasons, fmt.Sprintf("connected to %g of the stake < %g", connectedStake, n.config.HealthConfig.MinConnectedStake))
+		}
 		if !isMsgFailRate {
 			errorReasons = append(errorReasons, fmt.Sprintf("messages failure send rate %g > %g", sendFailRate, n.config.HealthConfig.MaxSendFailRate))
 		}
@@ -1312,15 +2198,36 @@ func (n *network) HealthCheck() (interface{}, error) {
 	return details, nil
 }
 
+// connectedStakePortion returns the portion of the primary network's stake
+// that is held by [nodeIDs]
+func (n *network) connectedStakePortion(nodeIDs ids.ShortSet) (float64, error) {
+	vdrs, ok := n.config.Validators.GetValidators(constants.PrimaryNetworkID)
+	if !ok {
+		return 0, errNoPrimaryValidators
+	}
+	totalWeight := vdrs.Weight()
+	if totalWeight == 0 {
+		return 1, nil
+	}
+	connectedWeight, err := vdrs.SubsetWeight(nodeIDs)
+	if err != nil {
+		return 0, err
+	}
+	return float64(connectedWeight) / float64(totalWeight), nil
+}
+
 // assume [n.stateLock] is held. Returns the timestamp and signature that should
 // be sent in a Version message. We only update these values when our IP has
-// changed.
+// changed, or when the signature is half as old as the age at which peers
+// consider it stale.
 func (n *network) getVersion(ip utils.IPDesc) (uint64, []byte, error) {
 	n.timeForIPLock.Lock()
 	defer n.timeForIPLock.Unlock()
 
-	if !ip.Equal(n.lastVersionIP) {
-		newTimestamp := n.clock.Unix()
+	now := n.clock.Unix()
+	refreshAge := uint64(n.config.PeerListMaxIPAge / time.Second / 2)
+	if !ip.Equal(n.lastVersionIP) || (refreshAge > 0 && now >= n.lastVersionTimestamp+refreshAge) {
+		newTimestamp := now
 		msgHash := ipAndTimeHash(ip, newTimestamp)
 		sig, err := n.config.TLSKey.Sign(cryptorand.Reader, msgHash, crypto.SHA256)
 		if err != nil {
@@ -1334,3 +2241,30 @@ func (n *network) getVersion(ip utils.IPDesc) (uint64, []byte, error) {
 
 	return n.lastVersionTimestamp, n.lastVersionSignature, nil
 }
+
+// assume [n.stateLock] is held. Returns the timestamp and signature that should
+// be sent in an IPv6 message. [n.config.MyIPv6] is signed with the same
+// timestamp as the IP sent in Version messages so that peers don't consider
+// either address to be stale.
+func (n *network) getIPv6Version() (uint64, []byte, error) {
+	timestamp, _, err := n.getVersion(n.currentIP.IP())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	n.timeForIPLock.Lock()
+	defer n.timeForIPLock.Unlock()
+
+	if n.lastIPv6Signature == nil || n.lastIPv6Timestamp != timestamp {
+		msgHash := ipAndTimeHash(n.config.MyIPv6, timestamp)
+		sig, err := n.config.TLSKey.Sign(cryptorand.Reader, msgHash, crypto.SHA256)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		n.lastIPv6Timestamp = timestamp
+		n.lastIPv6Signature = sig
+	}
+
+	return n.lastIPv6Timestamp, n.lastIPv6Signature, nil
+}
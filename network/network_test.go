@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"io"
 	"math"
 	"net"
 	"sync"
@@ -16,11 +17,15 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/allowlist"
+	"github.com/Toinounet21/avalanchego-mod/network/blocklist"
 	"github.com/Toinounet21/avalanchego-mod/network/dialer"
 	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
@@ -31,6 +36,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
@@ -41,7 +47,8 @@ const (
 	defaultPeerListSize               = 50
 	defaultGossipPeerListTo           = 100
 	defaultGossipAcceptedFrontierSize = 35
-	defaultGossipOnAcceptSize         = 20
+	defaultAdaptiveGossipMinSize      = 6
+	defaultAdaptiveGossipMaxSize      = 30
 	defaultAppGossipNonValidatorSize  = 2
 	defaultAppGossipValidatorSize     = 4
 )
@@ -220,9 +227,10 @@ func (c *testConn) SetWriteDeadline(time.Time) error { return nil }
 
 type testHandler struct {
 	router.Router
-	ConnectedF    func(nodeID ids.ShortID, nodeVersion version.Application)
-	DisconnectedF func(nodeID ids.ShortID)
-	PutF          func(
+	ConnectedF      func(nodeID ids.ShortID, nodeVersion version.Application)
+	DisconnectedF   func(nodeID ids.ShortID)
+	CPUUtilizationF func(nodeID ids.ShortID) map[ids.ID]float64
+	PutF            func(
 		validatorID ids.ShortID,
 		chainID ids.ID,
 		requestID uint32,
@@ -238,9 +246,9 @@ type testHandler struct {
 	)
 }
 
-func (h *testHandler) Connected(id ids.ShortID, nodeVersion version.Application) {
+func (h *testHandler) Connected(id ids.ShortID, peerInfo validators.PeerInfo) {
 	if h.ConnectedF != nil {
-		h.ConnectedF(id, nodeVersion)
+		h.ConnectedF(id, peerInfo.Version)
 	}
 }
 
@@ -250,6 +258,13 @@ func (h *testHandler) Disconnected(id ids.ShortID) {
 	}
 }
 
+func (h *testHandler) CPUUtilization(id ids.ShortID) map[ids.ID]float64 {
+	if h.CPUUtilizationF != nil {
+		return h.CPUUtilizationF(id)
+	}
+	return nil
+}
+
 func (h *testHandler) HandleInbound(msg message.InboundMessage) {
 	switch msg.Op() {
 	case message.Put:
@@ -448,12 +463,16 @@ func TestEstablishConnection(t *testing.T) {
 	metrics0 := prometheus.NewRegistry()
 	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
 	assert.NoError(t, err)
+	chainID := ids.GenerateTestID()
 	handler0 := &testHandler{
 		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
 			if id != id0 {
 				wg0.Done()
 			}
 		},
+		CPUUtilizationF: func(id ids.ShortID) map[ids.ID]float64 {
+			return map[ids.ID]float64{chainID: 0.5}
+		},
 	}
 
 	metrics1 := prometheus.NewRegistry()
@@ -517,6 +536,53 @@ func TestEstablishConnection(t *testing.T) {
 	wg0.Wait()
 	wg1.Wait()
 
+	// The handshake messages are reflected in the peer's info
+	peers := net0.Peers(PeersFilter{})
+	assert.Len(t, peers, 1)
+	assert.NotZero(t, peers[0].BytesReceived)
+	assert.NotZero(t, peers[0].MessagesReceived[message.Version.String()])
+	assert.NotZero(t, peers[0].MessagesReceived[message.PeerList.String()])
+	assert.Equal(t, []ids.ID{constants.PrimaryNetworkID}, peers[0].TrackedSubnets)
+	assert.Empty(t, peers[0].BenchInfo)
+	assert.False(t, peers[0].PoorScore)
+	assert.Equal(t, featureList(defaultFeatures), peers[0].Features)
+	assert.True(t, peers[0].Connected)
+
+	// Filters are applied to the connected peers
+	peerID, err := ids.ShortFromPrefixedString(peers[0].ID, constants.NodeIDPrefix)
+	assert.NoError(t, err)
+	assert.Len(t, net0.Peers(PeersFilter{NodeIDs: []ids.ShortID{peerID}}), 1)
+	assert.Empty(t, net0.Peers(PeersFilter{NodeIDs: []ids.ShortID{ids.GenerateTestShortID()}}))
+	assert.Empty(t, net0.Peers(PeersFilter{BenchedOnly: true}))
+	assert.Len(t, net0.Peers(PeersFilter{NodeIDs: []ids.ShortID{peerID, peerID}}), 1)
+	primaryNetworkID := constants.PrimaryNetworkID
+	assert.Len(t, net0.Peers(PeersFilter{SubnetID: &primaryNetworkID}), 1)
+	unknownSubnetID := ids.GenerateTestID()
+	assert.Empty(t, net0.Peers(PeersFilter{SubnetID: &unknownSubnetID}))
+	// ValidatorOnly defaults to the primary network
+	assert.Empty(t, net0.Peers(PeersFilter{ValidatorOnly: true}))
+	assert.Empty(t, net0.Peers(PeersFilter{SubnetID: &primaryNetworkID, ValidatorOnly: true}))
+	assert.NoError(t, vdrs.AddWeight(constants.PrimaryNetworkID, peerID, 1))
+	assert.Len(t, net0.Peers(PeersFilter{ValidatorOnly: true}), 1)
+	assert.Len(t, net0.Peers(PeersFilter{SubnetID: &primaryNetworkID, ValidatorOnly: true}), 1)
+	assert.Len(t, net0.Peers(PeersFilter{StartNodeID: peerID}), 1)
+	assert.Empty(t, net0.Peers(PeersFilter{StartNodeID: ids.ShortID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}))
+	assert.Eventually(t, func() bool {
+		peers := net0.Peers(PeersFilter{})
+		return len(peers) == 1 && peers[0].BytesSent > 0 && peers[0].MessagesSent[message.Version.String()] > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Scores are reported for the connected peers, or for the given node IDs
+	scores := net0.PeerScores(nil)
+	assert.Len(t, scores, 1)
+	assert.Equal(t, peers[0].ID, scores[0].ID)
+	assert.Zero(t, scores[0].Score)
+	assert.False(t, scores[0].PoorScore)
+	assert.Empty(t, scores[0].BenchInfo)
+	assert.Equal(t, map[ids.ID]json.Float64{chainID: 0.5}, scores[0].CPUUtilization)
+	unknownNodeID := ids.GenerateTestShortID()
+	assert.Len(t, net0.PeerScores([]ids.ShortID{peerID, unknownNodeID, peerID}), 2)
+
 	err = net0.Close()
 	assert.NoError(t, err)
 
@@ -1375,12 +1441,12 @@ func TestPeerAliasesTicker(t *testing.T) {
 	wg0.Wait()
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip1.String(): id1,
-	}, net0.Peers(nil))
+	}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
-	assert.Len(t, net3.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
+	assert.Len(t, net3.Peers(PeersFilter{}), 0)
 
 	// Attempt to connect to ip2 (same id as ip1)
 	net0.Track(ip2.IP(), id2)
@@ -1390,12 +1456,12 @@ func TestPeerAliasesTicker(t *testing.T) {
 	wg1Done = true
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip1.String(): id1,
-	}, net0.Peers(nil))
+	}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
-	assert.Len(t, net3.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
+	assert.Len(t, net3.Peers(PeersFilter{}), 0)
 
 	// Subsequent track call returns immediately with no connection attempts
 	// (would cause fatal error from unauthorized connection if allowed)
@@ -1414,14 +1480,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip1.String(): id1,
 		ip2.String(): id2,
-	}, net0.Peers(nil))
+	}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net3.Peers(nil))
+	}, net3.Peers(PeersFilter{}))
 
 	// Cleanup
 	cleanup = true
@@ -1781,12 +1847,12 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	wg0.Wait()
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip1.String(): id1,
-	}, net0.Peers(nil))
+	}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
-	assert.Len(t, net3.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
+	assert.Len(t, net3.Peers(PeersFilter{}), 0)
 
 	// Attempt to connect to ip2 (same id as ip1)
 	net0.Track(ip2.IP(), id2)
@@ -1796,12 +1862,12 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	wg1Done = true
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip1.String(): id1,
-	}, net0.Peers(nil))
+	}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
-	assert.Len(t, net3.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
+	assert.Len(t, net3.Peers(PeersFilter{}), 0)
 
 	// Disconnect original peer
 	_ = caller0.clients[ip1.String()].Close()
@@ -1809,12 +1875,12 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	// Track ip2 on net3
 	wg2.Wait()
 	wg2Done = true
-	assertEqualPeers(t, map[string]ids.ShortID{}, net0.Peers(nil))
+	assertEqualPeers(t, map[string]ids.ShortID{}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
-	assert.Len(t, net3.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
+	assert.Len(t, net3.Peers(PeersFilter{}), 0)
 	upgrader.Update(ip2, id2)
 	caller0.Update(ip2, listener3)
 	net0.Track(ip2.IP(), id2)
@@ -1823,14 +1889,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	wg3.Wait()
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip2.String(): id2,
-	}, net0.Peers(nil))
+	}, net0.Peers(PeersFilter{}))
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net1.Peers(nil))
-	assert.Len(t, net2.Peers(nil), 0)
+	}, net1.Peers(PeersFilter{}))
+	assert.Len(t, net2.Peers(PeersFilter{}), 0)
 	assertEqualPeers(t, map[string]ids.ShortID{
 		ip0.String(): id0,
-	}, net3.Peers(nil))
+	}, net3.Peers(PeersFilter{}))
 
 	// Cleanup
 	cleanup = true
@@ -2143,7 +2209,7 @@ func TestValidatorIPs(t *testing.T) {
 	assert.True(t, dummyNetwork.config.Validators.Contains(constants.PrimaryNetworkID, thirdValidatorPeer.nodeID))
 
 	// test
-	validatorIPs, err := dummyNetwork.validatorIPs()
+	validatorIPs, err := dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2157,7 +2223,7 @@ func TestValidatorIPs(t *testing.T) {
 	clearPeersData(&dummyNetwork)
 
 	// test
-	validatorIPs, err = dummyNetwork.validatorIPs()
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2176,7 +2242,7 @@ func TestValidatorIPs(t *testing.T) {
 	assert.True(t, dummyNetwork.config.Validators.Contains(constants.PrimaryNetworkID, disconnectedValidatorPeer.nodeID))
 
 	// test
-	validatorIPs, err = dummyNetwork.validatorIPs()
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2194,7 +2260,7 @@ func TestValidatorIPs(t *testing.T) {
 	assert.True(t, dummyNetwork.config.Validators.Contains(constants.PrimaryNetworkID, zeroValidatorPeer.nodeID))
 
 	// test
-	validatorIPs, err = dummyNetwork.validatorIPs()
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2213,12 +2279,76 @@ func TestValidatorIPs(t *testing.T) {
 	assert.False(t, dummyNetwork.config.Validators.Contains(constants.PrimaryNetworkID, nonValidatorPeer.nodeID))
 
 	// test
-	validatorIPs, err = dummyNetwork.validatorIPs()
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
 	assert.True(t, len(validatorIPs) == 0)
 
+	// SCENARIO: IPv6 addresses are picked only if preferred
+	// context
+	clearPeersData(&dummyNetwork)
+	dualStackValidatorIPDesc := utils.IPDesc{
+		IP:   net.IPv4(172, 17, 0, 9),
+		Port: 9,
+	}
+	dualStackValidatorIPv6Desc := utils.IPDesc{
+		IP:   net.ParseIP("2001:db8::9"),
+		Port: 9,
+	}
+	dualStackValidatorPeer := createPeer(ids.ShortID{0x01}, dualStackValidatorIPDesc, appVersion)
+	dualStackValidatorPeer.ipv6.SetValue(signedPeerIP{
+		ip:   dualStackValidatorIPv6Desc,
+		time: uint64(0),
+	})
+	addPeerToNetwork(&dummyNetwork, dualStackValidatorPeer, true)
+
+	// test
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
+
+	// checks
+	assert.NoError(t, err)
+	assert.Len(t, validatorIPs, 1)
+	assert.True(t, isIPDescIn(dualStackValidatorIPDesc, validatorIPs))
+
+	// test
+	validatorIPs, err = dummyNetwork.validatorIPs(true)
+
+	// checks
+	assert.NoError(t, err)
+	assert.Len(t, validatorIPs, 1)
+	assert.True(t, isIPDescIn(dualStackValidatorIPv6Desc, validatorIPs))
+
+	// SCENARIO: stale IPs are only skipped if the validator can refresh them
+	// context
+	clearPeersData(&dummyNetwork)
+	dummyNetwork.config.PeerListMaxIPAge = time.Hour
+	dummyNetwork.clock.Set(time.Unix(int64(2*time.Hour/time.Second), 0))
+
+	refreshingValidatorIPDesc := utils.IPDesc{
+		IP:   net.IPv4(172, 17, 0, 10),
+		Port: 10,
+	}
+	refreshingValidatorPeer := createPeer(ids.ShortID{0x01}, refreshingValidatorIPDesc, appVersion)
+	refreshingValidatorPeer.supportedFeatures = featureSignedIP
+	addPeerToNetwork(&dummyNetwork, refreshingValidatorPeer, true)
+
+	legacyValidatorIPDesc := utils.IPDesc{
+		IP:   net.IPv4(172, 17, 0, 11),
+		Port: 11,
+	}
+	legacyValidatorPeer := createPeer(ids.ShortID{0x02}, legacyValidatorIPDesc, appVersion)
+	addPeerToNetwork(&dummyNetwork, legacyValidatorPeer, true)
+
+	// test
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
+	dummyNetwork.config.PeerListMaxIPAge = 0
+
+	// checks
+	assert.NoError(t, err)
+	assert.Len(t, validatorIPs, 1)
+	assert.True(t, isIPDescIn(legacyValidatorIPDesc, validatorIPs))
+
 	// SCENARIO: validators with wrong version are not picked
 	// context
 	clearPeersData(&dummyNetwork)
@@ -2233,7 +2363,7 @@ func TestValidatorIPs(t *testing.T) {
 	assert.True(t, dummyNetwork.config.Validators.Contains(constants.PrimaryNetworkID, maskedValidatorPeer.nodeID))
 
 	// test
-	validatorIPs, err = dummyNetwork.validatorIPs()
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2259,7 +2389,7 @@ func TestValidatorIPs(t *testing.T) {
 	assert.True(t, dummyNetwork.config.Validators.Contains(constants.PrimaryNetworkID, wrongCertValidatorPeer.nodeID))
 
 	// test
-	validatorIPs, err = dummyNetwork.validatorIPs()
+	validatorIPs, err = dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2282,7 +2412,7 @@ func TestValidatorIPs(t *testing.T) {
 	}
 
 	// test
-	IPs, err := dummyNetwork.validatorIPs()
+	IPs, err := dummyNetwork.validatorIPs(false)
 
 	// checks
 	assert.NoError(t, err)
@@ -2592,6 +2722,177 @@ func TestPeerTrackedSubnets(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestTrackSubnet(t *testing.T) {
+	initCerts(t)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	vdrs := getDefaultManager()
+	beacons := validators.NewSet()
+
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+
+	metrics0 := prometheus.NewRegistry()
+	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler0 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			assert.NotEqual(t, id0, id)
+			wg0.Done()
+		},
+	}
+
+	metrics1 := prometheus.NewRegistry()
+	msgCreator1, err := message.NewCreator(metrics1, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	handler1 := &testHandler{
+		ConnectedF: func(id ids.ShortID, nodeVersion version.Application) {
+			assert.NotEqual(t, id1, id)
+			wg1.Done()
+		},
+	}
+
+	subnetSet := ids.Set{}
+	subnetSet.Add(testSubnetID)
+	net0, err := newTestNetwork(
+		id0,
+		ip0,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener0,
+		caller0,
+		metrics0,
+		msgCreator0,
+		handler0,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net0)
+
+	net1, err := newTestNetwork(
+		id1,
+		ip1,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert1.PrivateKey.(crypto.Signer),
+		subnetSet,
+		tlsConfig1,
+		listener1,
+		caller1,
+		metrics1,
+		msgCreator1,
+		handler1,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	net0.Track(ip1.IP(), id1)
+
+	wg0.Wait()
+	wg1.Wait()
+	// [net1]'s peer tracks [testSubnetID] once [net0] is told that it's now
+	// tracked
+	trackedBy := func(n Network, subnetID ids.ID) bool {
+		n.(*network).stateLock.RLock()
+		defer n.(*network).stateLock.RUnlock()
+
+		for _, peer := range n.(*network).peers.peersList {
+			if peer != nil && peer.supports(featureSubnets) && peer.trackedSubnets.Contains(subnetID) {
+				return true
+			}
+		}
+		return false
+	}
+	gotFeatures := func(n Network) bool {
+		n.(*network).stateLock.RLock()
+		defer n.(*network).stateLock.RUnlock()
+
+		for _, peer := range n.(*network).peers.peersList {
+			if peer != nil && peer.finishedHandshake.GetValue() && peer.gotFeatures.GetValue() {
+				return true
+			}
+		}
+		return false
+	}
+	assert.Eventually(t, func() bool { return gotFeatures(net0) && gotFeatures(net1) }, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, trackedBy(net0, testSubnetID))
+
+	net0.TrackSubnet(testSubnetID)
+	assert.True(t, trackedBy(net0, testSubnetID))
+	assert.Eventually(t, func() bool { return trackedBy(net1, testSubnetID) }, 5*time.Second, 10*time.Millisecond)
+
+	net0.UntrackSubnet(testSubnetID)
+	assert.False(t, trackedBy(net0, testSubnetID))
+	assert.Eventually(t, func() bool { return !trackedBy(net1, testSubnetID) }, 5*time.Second, 10*time.Millisecond)
+
+	err = net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
 func TestPeerGossip(t *testing.T) {
 	initCerts(t)
 
@@ -3154,7 +3455,8 @@ func newDefaultNetwork(
 	netConfig.PeerListGossipSize = defaultGossipPeerListTo
 	netConfig.PeerListGossipFreq = defaultGossipPeerListFreq
 	netConfig.GossipAcceptedFrontierSize = defaultGossipAcceptedFrontierSize
-	netConfig.GossipOnAcceptSize = defaultGossipOnAcceptSize
+	netConfig.AdaptiveGossipMinSize = defaultAdaptiveGossipMinSize
+	netConfig.AdaptiveGossipMaxSize = defaultAdaptiveGossipMaxSize
 	netConfig.CompressionEnabled = true
 	netConfig.WhitelistedSubnets = subnetSet
 	netConfig.UptimeCalculator = uptimeManager
@@ -3198,8 +3500,10 @@ func newDefaultConfig() Config {
 			PeerListStakerGossipFraction: 2,
 		},
 		DelayConfig: DelayConfig{
-			MaxReconnectDelay:     time.Hour,
-			InitialReconnectDelay: time.Second,
+			MaxReconnectDelay:        time.Hour,
+			InitialReconnectDelay:    time.Second,
+			ReconnectDelayMultiplier: 1.5,
+			ReconnectDelayJitter:     0.25,
 		},
 		TimeoutConfig: TimeoutConfig{
 			GetVersionTimeout:    10 * time.Second,
@@ -3207,8 +3511,10 @@ func newDefaultConfig() Config {
 			ReadHandshakeTimeout: 15 * time.Second,
 		},
 		GossipConfig: GossipConfig{
-			AppGossipNonValidatorSize: defaultAppGossipNonValidatorSize,
-			AppGossipValidatorSize:    defaultAppGossipValidatorSize,
+			AppGossipNonValidatorSize:         defaultAppGossipNonValidatorSize,
+			AppGossipValidatorSize:            defaultAppGossipValidatorSize,
+			AdaptiveGossipRedundancyHalflife:  time.Minute,
+			AdaptiveGossipRedundancyThreshold: 0.8,
 		},
 		MaxClockDifference: time.Minute,
 		AllowPrivateIPs:    true,
@@ -3216,3 +3522,390 @@ func newDefaultConfig() Config {
 		UptimeMetricFreq:   30 * time.Second,
 	}
 }
+
+func TestNextReconnectDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	n := &network{
+		config: &Config{
+			DelayConfig: DelayConfig{
+				InitialReconnectDelay:    time.Second,
+				MaxReconnectDelay:        time.Minute,
+				ReconnectDelayMultiplier: 2,
+				ReconnectDelayJitter:     0.25,
+			},
+		},
+	}
+
+	for i := 0; i < 100; i++ {
+		delay := n.nextReconnectDelay(0)
+		assert.GreaterOrEqual(delay, 1500*time.Millisecond)
+		assert.LessOrEqual(delay, 2500*time.Millisecond)
+
+		delay = n.nextReconnectDelay(10 * time.Second)
+		assert.GreaterOrEqual(delay, 15*time.Second)
+		assert.LessOrEqual(delay, 25*time.Second)
+
+		delay = n.nextReconnectDelay(time.Minute)
+		assert.GreaterOrEqual(delay, 45*time.Second)
+		assert.LessOrEqual(delay, time.Minute)
+	}
+
+	// Without jitter, the schedule is deterministic
+	n.config.ReconnectDelayJitter = 0
+	assert.Equal(2*time.Second, n.nextReconnectDelay(0))
+	assert.Equal(4*time.Second, n.nextReconnectDelay(2*time.Second))
+	assert.Equal(time.Minute, n.nextReconnectDelay(40*time.Second))
+}
+
+func TestSubnetScopedConnectivity(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	beaconID := ids.GenerateTestShortID()
+	subnetVdrID := ids.GenerateTestShortID()
+	primaryVdrID0 := ids.GenerateTestShortID()
+	primaryVdrID1 := ids.GenerateTestShortID()
+	nonVdrID := ids.GenerateTestShortID()
+
+	beacons := validators.NewSet()
+	assert.NoError(beacons.AddWeight(beaconID, 1))
+	vdrs := getDefaultManager()
+	for _, vdrID := range []ids.ShortID{subnetVdrID, primaryVdrID0, primaryVdrID1} {
+		assert.NoError(vdrs.AddWeight(constants.PrimaryNetworkID, vdrID, 1))
+	}
+	assert.NoError(vdrs.AddWeight(subnetID, subnetVdrID, 1))
+
+	n := &network{
+		config: &Config{
+			Beacons:                  beacons,
+			Validators:               vdrs,
+			ConnectivitySubnetID:     subnetID,
+			PrimaryNetworkSampleSize: 1,
+		},
+	}
+
+	assert.True(n.shouldHoldConnection(beaconID))
+	assert.True(n.shouldHoldConnection(subnetVdrID))
+	assert.True(n.shouldHoldConnection(primaryVdrID0))
+	assert.False(n.shouldHoldConnection(nonVdrID))
+
+	// Once the primary network sample is full, other primary network
+	// validators aren't connected to
+	assert.True(n.sampledPrimaryPeer(primaryVdrID0))
+	assert.False(n.sampledPrimaryPeer(subnetVdrID))
+	n.primarySample.Add(primaryVdrID0)
+	assert.True(n.shouldHoldConnection(primaryVdrID0))
+	assert.False(n.shouldHoldConnection(primaryVdrID1))
+	assert.True(n.shouldHoldConnection(subnetVdrID))
+
+	// A sampled validator that joins the subnet frees its spot
+	assert.NoError(vdrs.AddWeight(subnetID, primaryVdrID0, 1))
+	assert.True(n.stillHoldsConnection(primaryVdrID0))
+	assert.True(n.shouldHoldConnection(primaryVdrID1))
+
+	// A validator that leaves the subnet takes up a spot, if there's one
+	assert.NoError(vdrs.RemoveWeight(subnetID, subnetVdrID, 1))
+	assert.True(n.stillHoldsConnection(subnetVdrID))
+	assert.True(n.primarySample.Contains(subnetVdrID))
+	assert.False(n.shouldHoldConnection(primaryVdrID1))
+}
+
+func TestConnectToGivesUp(t *testing.T) {
+	initCerts(t)
+	assert := assert.New(t)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	// [caller0] can't reach anyone
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	metrics0 := prometheus.NewRegistry()
+	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+
+	net0, err := newTestNetwork(
+		id0,
+		ip0,
+		defaultVersionManager,
+		getDefaultManager(),
+		validators.NewSet(),
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener0,
+		caller0,
+		metrics0,
+		msgCreator0,
+		&testHandler{},
+	)
+	assert.NoError(err)
+	netw := net0.(*network)
+	netw.config.InitialReconnectDelay = time.Millisecond
+	netw.config.MaxReconnectDelay = 2 * time.Millisecond
+	netw.config.MaxReconnectAttempts = 3
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(err)
+	}()
+
+	counterValue := func(c prometheus.Counter) float64 {
+		metric := &dto.Metric{}
+		assert.NoError(c.Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+
+	net0.Track(ip1.IP(), id1)
+	assert.Eventually(func() bool {
+		return counterValue(netw.metrics.dialGiveUps) == 1
+	}, 5*time.Second, time.Millisecond)
+	assert.EqualValues(3, counterValue(netw.metrics.dialAttempts))
+	assert.EqualValues(3, counterValue(netw.metrics.dialFailures))
+	metric := &dto.Metric{}
+	assert.NoError(netw.metrics.consecutiveDialFailures.Write(metric))
+	assert.EqualValues(1, metric.GetHistogram().GetSampleCount())
+	assert.EqualValues(3, metric.GetHistogram().GetSampleSum())
+
+	netw.stateLock.RLock()
+	_, isDisconnected := netw.disconnectedIPs[ip1.IP().String()]
+	netw.stateLock.RUnlock()
+	assert.False(isDisconnected)
+
+	// The IP is tracked again once it's learned again
+	net0.Track(ip1.IP(), id1)
+	assert.Eventually(func() bool {
+		return counterValue(netw.metrics.dialGiveUps) == 2
+	}, 5*time.Second, time.Millisecond)
+
+	assert.NoError(net0.Close())
+}
+
+func TestBlockPeer(t *testing.T) {
+	initCerts(t)
+	assert := assert.New(t)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	vdrs := getDefaultManager()
+	beacons := validators.NewSet()
+
+	metrics0 := prometheus.NewRegistry()
+	msgCreator0, err := message.NewCreator(metrics0, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+	metrics1 := prometheus.NewRegistry()
+	msgCreator1, err := message.NewCreator(metrics1, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+
+	net0, err := newTestNetwork(
+		id0,
+		ip0,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert0.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig0,
+		listener0,
+		caller0,
+		metrics0,
+		msgCreator0,
+		&testHandler{},
+	)
+	assert.NoError(err)
+	net1, err := newTestNetwork(
+		id1,
+		ip1,
+		defaultVersionManager,
+		vdrs,
+		beacons,
+		cert1.PrivateKey.(crypto.Signer),
+		ids.Set{},
+		tlsConfig1,
+		listener1,
+		caller1,
+		metrics1,
+		msgCreator1,
+		&testHandler{},
+	)
+	assert.NoError(err)
+
+	blocklist0, err := blocklist.New(memdb.New())
+	assert.NoError(err)
+	netw0 := net0.(*network)
+	netw0.config.Blocklist = blocklist0
+	netw0.config.InitialReconnectDelay = time.Millisecond
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(err)
+	}()
+
+	net0.Track(ip1.IP(), id1)
+	assert.Eventually(func() bool {
+		return len(net0.Peers(PeersFilter{})) == 1
+	}, 5*time.Second, time.Millisecond)
+	// The node ID is derived from the peer's certificate
+	peerID, err := ids.ShortFromPrefixedString(net0.Peers(PeersFilter{})[0].ID, constants.NodeIDPrefix)
+	assert.NoError(err)
+
+	// Blocking the peer disconnects from it
+	assert.NoError(net0.Block([]ids.ShortID{peerID}, nil))
+	assert.Eventually(func() bool {
+		return len(net0.Peers(PeersFilter{IncludeHandshaking: true})) == 0
+	}, 5*time.Second, time.Millisecond)
+	blockedNodeIDs, blockedIPRanges := net0.Blocked()
+	assert.Equal([]ids.ShortID{peerID}, blockedNodeIDs)
+	assert.Empty(blockedIPRanges)
+
+	// A blocked peer isn't dialed, and its connections aren't accepted
+	net0.Track(ip1.IP(), id1)
+	net1.Track(ip0.IP(), id0)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(net0.Peers(PeersFilter{IncludeHandshaking: true}))
+	netw0.stateLock.RLock()
+	_, isDisconnected := netw0.disconnectedIPs[ip1.IP().String()]
+	netw0.stateLock.RUnlock()
+	assert.False(isDisconnected)
+
+	// Once unblocked, the peer can be connected to again
+	assert.NoError(net0.Unblock([]ids.ShortID{peerID}, nil))
+	net0.Track(ip1.IP(), id1)
+	assert.Eventually(func() bool {
+		return len(net0.Peers(PeersFilter{})) == 1
+	}, 5*time.Second, time.Millisecond)
+
+	// Blocking an IP range that contains the peer's IP disconnects from it
+	_, ipRange, err := net.ParseCIDR("::1/128")
+	assert.NoError(err)
+	assert.NoError(net0.Block(nil, []*net.IPNet{ipRange}))
+	assert.Eventually(func() bool {
+		return len(net0.Peers(PeersFilter{IncludeHandshaking: true})) == 0
+	}, 5*time.Second, time.Millisecond)
+
+	assert.NoError(net0.Close())
+	assert.NoError(net1.Close())
+}
+
+// Test that peers that aren't allowed are rejected during the TLS handshake
+func TestAllowlistTLSConfig(t *testing.T) {
+	initCerts(t)
+
+	x509Cert0, err := x509.ParseCertificate(cert0.Certificate[0])
+	assert.NoError(t, err)
+	nodeID0 := certToID(x509Cert0)
+	serverConfig := AllowlistTLSConfig(
+		tlsConfig1,
+		allowlist.New(ids.ShortSet{nodeID0: struct{}{}}, nil, validators.NewManager()),
+	)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	handshake := func(clientConfig *tls.Config) (error, error) {
+		clientErr := make(chan error, 1)
+		go func() {
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			if err == nil {
+				var tlsConn net.Conn
+				_, tlsConn, _, err = NewTLSClientUpgrader(clientConfig).Upgrade(conn)
+				if err == nil {
+					// The server verifies the client's certificate after the
+					// client's side of the handshake is done, so the client
+					// only learns whether it was rejected on its next read.
+					// If it wasn't, the read fails when the server closes the
+					// connection.
+					_, err = tlsConn.Read(make([]byte, 1))
+				}
+				_ = conn.Close()
+			}
+			clientErr <- err
+		}()
+
+		conn, err := listener.Accept()
+		assert.NoError(t, err)
+		_, _, _, serverErr := NewTLSServerUpgrader(serverConfig).Upgrade(conn)
+		_ = conn.Close()
+		return serverErr, <-clientErr
+	}
+
+	serverErr, clientErr := handshake(tlsConfig0)
+	assert.NoError(t, serverErr)
+	assert.ErrorIs(t, clientErr, io.EOF)
+
+	serverErr, clientErr = handshake(tlsConfig2)
+	assert.ErrorIs(t, serverErr, errNotAllowed)
+	assert.Error(t, clientErr)
+	assert.NotErrorIs(t, clientErr, io.EOF)
+}
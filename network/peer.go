@@ -17,7 +17,9 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
@@ -79,12 +81,21 @@ type peer struct {
 
 	// queue of messages to be sent to this peer
 	sendQueue []message.OutboundMessage
-
-	// Signalled when a message is added to [sendQueue],
-	// and when [p.closed] is set to true.
-	// [sendQueueCond.L] must be held when using [sendQueue].
+	// queue of messages in [message.PriorityOps] to be sent to this peer.
+	// These messages are sent before the ones in [sendQueue].
+	prioritySendQueue []message.OutboundMessage
+
+	// Signalled when a message is added to [sendQueue] or
+	// [prioritySendQueue], and when [p.closed] is set to true.
+	// [sendQueueCond.L] must be held when using [sendQueue] or
+	// [prioritySendQueue].
 	sendQueueCond *sync.Cond
 
+	// Signalled when a message is added to [prioritySendQueue], so that
+	// priority messages aren't held back while a bulk message waits for
+	// outbound bandwidth.
+	priorityQueued chan struct{}
+
 	// ip may or may not be set when the peer is first started. is only modified
 	// on the connection's reader routine.
 	ip utils.IPDesc
@@ -116,8 +127,22 @@ type peer struct {
 	// Must only be accessed atomically
 	lastSent, lastReceived int64
 
+	// Number of bytes sent and received respectively since the connection was
+	// established, including the length prefix of each message.
+	// Must only be accessed atomically
+	bytesSent, bytesReceived uint64
+
+	// Number of messages of each type sent and received respectively since the
+	// connection was established
+	msgsSent, msgsReceived opCounter
+
 	tickerCloser chan struct{}
 
+	// pingTimer is the next ping to this peer scheduled on the network's ping
+	// wheel. pingLock must be held when accessing [pingTimer].
+	pingTimer *timer.WheelTimer
+	pingLock  sync.Mutex
+
 	// ticker processes
 	tickerOnce sync.Once
 
@@ -130,21 +155,50 @@ type peer struct {
 	// The time in [sigAndTime] is the one mentioned above.
 	sigAndTime utils.AtomicInterface
 
+	// ipv6 contains a struct of type signedPeerIP with the IPv6 address this
+	// peer advertised in addition to the IP in its Version message. It's
+	// empty if the peer didn't advertise an IPv6 address.
+	ipv6 utils.AtomicInterface
+
 	// trackedSubnets hold subnetIDs that this peer is interested in.
 	trackedSubnets ids.Set
 
+	// advertisedSubnets hold the subnetIDs that this peer advertised, even
+	// the ones this node doesn't track. Once the handshake is finished,
+	// [net.stateLock] should be held when accessing it or [trackedSubnets].
+	advertisedSubnets ids.Set
+
+	// Optional protocol features that this peer advertised. Zero until the
+	// peer's Features message is handled.
+	// Must only be accessed atomically
+	supportedFeatures uint64
+
+	// True if the Features message from this peer was handled
+	gotFeatures utils.AtomicBool
+
 	// observedUptime is the uptime of this node in peer's point of view
 	observedUptime uint8
+
+	// Unix time, in nanoseconds, that the last Ping was sent to this peer. 0
+	// if the Pong for that Ping was already received.
+	// Must only be accessed atomically
+	pingSent int64
+
+	// Round-trip time, in nanoseconds, of the last Ping/Pong exchange with
+	// this peer. 0 if no exchange completed yet.
+	// Must only be accessed atomically
+	latency int64
 }
 
 // newPeer returns a properly initialized *peer.
 func newPeer(net *network, conn net.Conn, ip utils.IPDesc) *peer {
 	p := &peer{
-		sendQueueCond: sync.NewCond(&sync.Mutex{}),
-		net:           net,
-		conn:          conn,
-		ip:            ip,
-		tickerCloser:  make(chan struct{}),
+		sendQueueCond:  sync.NewCond(&sync.Mutex{}),
+		priorityQueued: make(chan struct{}, 1),
+		net:            net,
+		conn:           conn,
+		ip:             ip,
+		tickerCloser:   make(chan struct{}),
 	}
 	p.aliasTimer = timer.NewTimer(p.releaseExpiredAliases)
 	p.trackedSubnets.Add(constants.PrimaryNetworkID)
@@ -159,39 +213,43 @@ func (p *peer) Start() {
 	// This happens in [p.Close].
 	// Failure to call RemoveNode will cause a memory leak.
 	p.net.inboundMsgThrottler.AddNode(p.nodeID)
+	// Likewise, [p.net.outboundBandwidthThrottler.RemoveNode(p.nodeID)] is
+	// called in [p.Close].
+	p.net.outboundBandwidthThrottler.AddNode(p.nodeID)
 	go func() {
 		// Make sure that the version is the first message sent
 		p.sendVersion()
 
-		go p.ReadMessages()
-		go p.WriteMessages()
+		go p.net.log.RecoverAndPanic(p.ReadMessages)
+		go p.net.log.RecoverAndPanic(p.WriteMessages)
 	}()
 }
 
 func (p *peer) StartTicker() {
-	go p.requestFinishHandshake()
-	go p.sendPings()
-	go p.monitorAliases()
+	go p.net.log.RecoverAndPanic(p.requestFinishHandshake)
+	go p.net.log.RecoverAndPanic(p.monitorAliases)
+	p.schedulePing()
 }
 
-func (p *peer) sendPings() {
-	sendPingsTicker := time.NewTicker(p.net.config.PingFrequency)
-	defer sendPingsTicker.Stop()
+// schedulePing schedules the next ping to this peer, unless the peer is closed
+func (p *peer) schedulePing() {
+	p.pingLock.Lock()
+	defer p.pingLock.Unlock()
 
-	for {
-		select {
-		case <-sendPingsTicker.C:
-			closed := p.closed.GetValue()
-
-			if closed {
-				return
-			}
+	if p.closed.GetValue() {
+		return
+	}
+	p.pingTimer = p.net.pingWheel.Schedule(p.net.config.PingFrequency, p.ping)
+}
 
-			p.sendPing()
-		case <-p.tickerCloser:
-			return
-		}
+// ping sends a ping to this peer and schedules the next one.
+// Called on the dispatch goroutine of the network's ping wheel.
+func (p *peer) ping() {
+	if p.closed.GetValue() {
+		return
 	}
+	p.sendPing()
+	p.schedulePing()
 }
 
 // request missing handshake messages from the peer
@@ -235,25 +293,74 @@ func (p *peer) monitorAliases() {
 	p.aliasTimer.Dispatch()
 }
 
+// messageConn is a connection that frames messages itself, rather than
+// carrying a single stream of bytes that messages are length prefixed in. A QUIC
+// connection, which sends each message on its own stream, is a messageConn.
+type messageConn interface {
+	// ReadMessageLen returns the length of the next message, which is then
+	// read by ReadMessage
+	ReadMessageLen() (uint32, error)
+	ReadMessage() ([]byte, error)
+	WriteMessage(msg []byte) error
+}
+
 // Read and handle messages from this peer.
 // When this method returns, the connection is closed.
 func (p *peer) ReadMessages() {
 	defer p.Close()
 
+	readMessage := p.framedMessageReader()
+	if conn, ok := p.conn.(messageConn); ok {
+		readMessage = func() ([]byte, func(), bool) { return p.readMessageFrom(conn) }
+	}
+
 	// Continuously read and handle messages from this peer.
+	for {
+		// Invariant: When done processing this message, onFinishedHandling() is called.
+		// If this is not honored, the message throttler will leak until no new messages can be read.
+		// You can look at message throttler metrics to verify that there is no leak.
+		msgBytes, onFinishedHandling, ok := readMessage()
+		if !ok {
+			return
+		}
+
+		p.net.log.Verbo("parsing message from %s%s at %s:\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes(msgBytes))
+
+		// Parse the message
+		msg, err := p.net.mc.Parse(msgBytes, p.nodeID, onFinishedHandling)
+		if err != nil {
+			p.net.log.Verbo("failed to parse message from %s%s at %s:\n%s\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes(msgBytes), err)
+			// Couldn't parse the message. Read the next one.
+			onFinishedHandling()
+			p.net.metrics.failedToParse.Inc()
+			continue
+		}
+
+		// Handle the message. Note that when we are done handling
+		// this message, we must call [p.net.msgThrottler.Release]
+		// to release the bytes used by this message. See MsgThrottler.
+		p.handle(msg, float64(len(msgBytes)))
+	}
+}
+
+// framedMessageReader returns a function that reads the next length prefixed
+// message from [p.conn]. The function returns false if the connection should
+// be closed. Otherwise, the returned func must be called once the message has
+// been handled.
+func (p *peer) framedMessageReader() func() ([]byte, func(), bool) {
 	reader := bufio.NewReader(p.conn)
 	msgLenBytes := make([]byte, wrappers.IntLen)
-	for {
+	return func() ([]byte, func(), bool) {
 		// Time out and close connection if we can't read message length
 		if err := p.conn.SetReadDeadline(p.nextTimeout()); err != nil {
 			p.net.log.Verbo("error setting the connection read timeout on %s%s at %s %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
-			return
+			return nil, nil, false
 		}
 
 		// Read the message length
 		if _, err := io.ReadFull(reader, msgLenBytes); err != nil {
 			p.net.log.Verbo("error reading from %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
-			return
+			return nil, nil, false
 		}
 
 		// Parse the message length
@@ -262,7 +369,7 @@ func (p *peer) ReadMessages() {
 		// Make sure the message length is valid.
 		if int64(msgLen) > int64(constants.DefaultMaxMessageSize) {
 			p.net.log.Verbo("too large message length %d from %s%s at %s", msgLen, constants.NodeIDPrefix, p.nodeID, p.getIP())
-			return
+			return nil, nil, false
 		}
 
 		// Wait until the throttler says we can proceed to read the message.
@@ -270,17 +377,13 @@ func (p *peer) ReadMessages() {
 		// trying to read it, we must call [p.net.msgThrottler.Release]
 		// to give back the bytes used by this message.
 		p.net.inboundMsgThrottler.Acquire(uint64(msgLen), p.nodeID)
-
-		// Invariant: When done processing this message, onFinishedHandling() is called.
-		// If this is not honored, the message throttler will leak until no new messages can be read.
-		// You can look at message throttler metrics to verify that there is no leak.
 		onFinishedHandling := func() { p.net.inboundMsgThrottler.Release(uint64(msgLen), p.nodeID) }
 
 		// Time out and close connection if we can't read message
 		if err := p.conn.SetReadDeadline(p.nextTimeout()); err != nil {
 			p.net.log.Verbo("error setting the connection read timeout on %s%s at %s %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
 			onFinishedHandling()
-			return
+			return nil, nil, false
 		}
 
 		// Read the message
@@ -288,34 +391,64 @@ func (p *peer) ReadMessages() {
 		if _, err := io.ReadFull(reader, msgBytes); err != nil {
 			p.net.log.Verbo("error reading from %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
 			onFinishedHandling()
-			return
+			return nil, nil, false
 		}
+		atomic.AddUint64(&p.bytesReceived, uint64(wrappers.IntLen)+uint64(msgLen))
+		return msgBytes, onFinishedHandling, true
+	}
+}
 
-		p.net.log.Verbo("parsing message from %s%s at %s:\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes(msgBytes))
+// readMessageFrom reads the next message from [conn]. Returns false if the
+// connection should be closed. Otherwise, the returned func must be called
+// once the message has been handled.
+func (p *peer) readMessageFrom(conn messageConn) ([]byte, func(), bool) {
+	// Time out and close connection if we can't read message length
+	if err := p.conn.SetReadDeadline(p.nextTimeout()); err != nil {
+		p.net.log.Verbo("error setting the connection read timeout on %s%s at %s %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return nil, nil, false
+	}
 
-		// Parse the message
-		msg, err := p.net.mc.Parse(msgBytes, p.nodeID, onFinishedHandling)
-		if err != nil {
-			p.net.log.Verbo("failed to parse message from %s%s at %s:\n%s\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes(msgBytes), err)
-			// Couldn't parse the message. Read the next one.
-			onFinishedHandling()
-			p.net.metrics.failedToParse.Inc()
-			continue
-		}
+	// Read the message length. Its validity is checked by [conn].
+	msgLen, err := conn.ReadMessageLen()
+	if err != nil {
+		p.net.log.Verbo("error reading from %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return nil, nil, false
+	}
 
-		// Handle the message. Note that when we are done handling
-		// this message, we must call [p.net.msgThrottler.Release]
-		// to release the bytes used by this message. See MsgThrottler.
-		p.handle(msg, float64(len(msgBytes)))
+	// Wait until the throttler says we can proceed to read the message.
+	// Note that when we are done handling this message, or give up
+	// trying to read it, we must call [p.net.msgThrottler.Release]
+	// to give back the bytes used by this message.
+	p.net.inboundMsgThrottler.Acquire(uint64(msgLen), p.nodeID)
+	onFinishedHandling := func() { p.net.inboundMsgThrottler.Release(uint64(msgLen), p.nodeID) }
+
+	// Time out and close connection if we can't read message
+	if err := p.conn.SetReadDeadline(p.nextTimeout()); err != nil {
+		p.net.log.Verbo("error setting the connection read timeout on %s%s at %s %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		onFinishedHandling()
+		return nil, nil, false
+	}
+
+	// Read the message
+	msgBytes, err := conn.ReadMessage()
+	if err != nil {
+		p.net.log.Verbo("error reading from %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		onFinishedHandling()
+		return nil, nil, false
 	}
+	atomic.AddUint64(&p.bytesReceived, uint64(wrappers.IntLen)+uint64(msgLen))
+	return msgBytes, onFinishedHandling, true
 }
 
 // attempt to write messages to the peer
 func (p *peer) WriteMessages() {
 	defer p.Close()
 
-	var reader bytes.Reader
-	writer := bufio.NewWriter(p.conn)
+	writeMessage := p.framedMessageWriter()
+	if conn, ok := p.conn.(messageConn); ok {
+		writeMessage = func(msgBytes []byte) (uint64, bool) { return p.writeMessageTo(conn, msgBytes) }
+	}
+
 	for { // When this loop exits, p.sendQueueCond.L is unlocked
 		p.sendQueueCond.L.Lock()
 		for {
@@ -323,49 +456,131 @@ func (p *peer) WriteMessages() {
 				p.sendQueueCond.L.Unlock()
 				return
 			}
-			if len(p.sendQueue) > 0 {
+			if len(p.prioritySendQueue) > 0 || len(p.sendQueue) > 0 {
 				// There is a message to send
 				break
 			}
 			// Wait until there is a message to send
 			p.sendQueueCond.Wait()
 		}
-		msg := p.sendQueue[0]
-		p.sendQueue = p.sendQueue[1:]
+		msg := p.popSendQueue()
 		p.sendQueueCond.L.Unlock()
 
+		// The messages of the priority queue are sent right away, so that
+		// consensus isn't delayed by bulk transfers. Their bytes still count
+		// against the bandwidth budgets.
 		msgLen := uint32(len(msg.Bytes()))
-		p.net.outboundMsgThrottler.Release(uint64(msgLen), p.nodeID)
-		p.net.log.Verbo("sending message to %s%s at %s:\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes(msg.Bytes()))
+		delay := p.net.outboundBandwidthThrottler.Acquire(uint64(msgLen), p.nodeID)
+		if _, ok := message.PriorityOps[msg.Op()]; !ok && delay > 0 {
+			if !p.waitBandwidth(delay, writeMessage) {
+				p.net.outboundMsgThrottler.Release(uint64(msgLen), p.nodeID)
+				msg.DecRef()
+				return
+			}
+		}
+		if !p.writeQueuedMessage(msg, writeMessage) {
+			return
+		}
+	}
+}
+
+// waitBandwidth waits for [delay] before a bulk message can be sent. Priority
+// messages queued in the meantime are sent without waiting for the delay to
+// pass. Returns false if the peer was closed, or a priority message couldn't
+// be written, before the delay passed.
+func (p *peer) waitBandwidth(delay time.Duration, writeMessage func([]byte) (uint64, bool)) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case <-p.priorityQueued:
+			p.sendQueueCond.L.Lock()
+			priorityMsgs := p.prioritySendQueue
+			p.prioritySendQueue = nil
+			p.sendQueueCond.L.Unlock()
+
+			for i, msg := range priorityMsgs {
+				p.net.outboundBandwidthThrottler.Acquire(uint64(len(msg.Bytes())), p.nodeID)
+				if !p.writeQueuedMessage(msg, writeMessage) {
+					for _, msg := range priorityMsgs[i+1:] {
+						p.net.outboundMsgThrottler.Release(uint64(len(msg.Bytes())), p.nodeID)
+						msg.DecRef()
+					}
+					return false
+				}
+			}
+		case <-p.tickerCloser:
+			return false
+		}
+	}
+}
+
+// writeQueuedMessage writes [msg], which was popped from the send queue, to the
+// connection and releases it. Returns false if [msg] couldn't be written.
+func (p *peer) writeQueuedMessage(msg message.OutboundMessage, writeMessage func([]byte) (uint64, bool)) bool {
+	defer msg.DecRef()
+
+	p.net.outboundMsgThrottler.Release(uint64(len(msg.Bytes())), p.nodeID)
+	p.net.log.Verbo("sending message to %s%s at %s:\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes(msg.Bytes()))
+	bytesSent, ok := writeMessage(msg.Bytes())
+	if !ok {
+		return false
+	}
+	p.tickerOnce.Do(p.StartTicker)
+
+	now := p.net.clock.Time().Unix()
+	atomic.StoreInt64(&p.lastSent, now)
+	atomic.StoreInt64(&p.net.lastMsgSentTime, now)
+	atomic.AddUint64(&p.bytesSent, bytesSent)
+	p.msgsSent.Inc(msg.Op())
+	return true
+}
+
+// framedMessageWriter returns a function that writes a length prefixed message
+// to [p.conn]. The function returns the number of bytes written, or false if
+// the connection should be closed.
+func (p *peer) framedMessageWriter() func([]byte) (uint64, bool) {
+	var reader bytes.Reader
+	writer := bufio.NewWriter(p.conn)
+	return func(msgBytes []byte) (uint64, bool) {
+		msgLen := uint32(len(msgBytes))
 		msgb := [wrappers.IntLen]byte{}
 		binary.BigEndian.PutUint32(msgb[:], msgLen)
-		for _, byteSlice := range [2][]byte{msgb[:], msg.Bytes()} {
+		for _, byteSlice := range [2][]byte{msgb[:], msgBytes} {
 			reader.Reset(byteSlice)
 			if err := p.conn.SetWriteDeadline(p.nextTimeout()); err != nil {
 				p.net.log.Verbo("error setting write deadline to %s%s at %s due to: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
-				msg.DecRef()
-				return
+				return 0, false
 			}
 			if _, err := io.CopyN(writer, &reader, int64(len((byteSlice)))); err != nil {
 				p.net.log.Verbo("error writing to %s%s at %s due to: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
-				msg.DecRef()
-				return
+				return 0, false
 			}
 		}
-		p.tickerOnce.Do(p.StartTicker)
 		// Make sure the peer got the entire message
 		if err := writer.Flush(); err != nil {
 			p.net.log.Verbo("couldn't flush writer to %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
-			msg.DecRef()
-			return
+			return 0, false
 		}
+		return uint64(wrappers.IntLen) + uint64(msgLen), true
+	}
+}
 
-		now := p.net.clock.Time().Unix()
-		atomic.StoreInt64(&p.lastSent, now)
-		atomic.StoreInt64(&p.net.lastMsgSentTime, now)
-
-		msg.DecRef()
+// writeMessageTo writes [msgBytes] to [conn]. Returns the number of bytes
+// written, or false if the connection should be closed.
+func (p *peer) writeMessageTo(conn messageConn, msgBytes []byte) (uint64, bool) {
+	if err := p.conn.SetWriteDeadline(p.nextTimeout()); err != nil {
+		p.net.log.Verbo("error setting write deadline to %s%s at %s due to: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return 0, false
+	}
+	if err := conn.WriteMessage(msgBytes); err != nil {
+		p.net.log.Verbo("error writing to %s%s at %s due to: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return 0, false
 	}
+	return uint64(wrappers.IntLen) + uint64(len(msgBytes)), true
 }
 
 // send assumes that the [stateLock] is not held.
@@ -394,11 +609,69 @@ func (p *peer) Send(msg message.OutboundMessage) bool {
 		return false
 	}
 
-	p.sendQueue = append(p.sendQueue, msg)
+	p.pushSendQueue(msg)
 	p.sendQueueCond.Signal()
 	return true
 }
 
+// pushSendQueue queues [msg] to be sent after the queued messages of the same
+// priority.
+// Assumes [p.sendQueueCond.L] is held.
+func (p *peer) pushSendQueue(msg message.OutboundMessage) {
+	if _, ok := message.PriorityOps[msg.Op()]; ok {
+		p.prioritySendQueue = append(p.prioritySendQueue, msg)
+		select {
+		case p.priorityQueued <- struct{}{}:
+		default:
+		}
+		return
+	}
+	p.sendQueue = append(p.sendQueue, msg)
+}
+
+// popSendQueue removes and returns the next message to send. Priority messages
+// preempt the other queued messages.
+// Assumes [p.sendQueueCond.L] is held and that a message is queued.
+func (p *peer) popSendQueue() message.OutboundMessage {
+	if len(p.prioritySendQueue) > 0 {
+		msg := p.prioritySendQueue[0]
+		p.prioritySendQueue = p.prioritySendQueue[1:]
+		return msg
+	}
+	msg := p.sendQueue[0]
+	p.sendQueue = p.sendQueue[1:]
+	return msg
+}
+
+// canParse returns true if the peer is able to parse messages whose payload
+// was compressed using [compressionType].
+// Unless the peer advertised zstd support, only gzip is assumed to be
+// supported.
+func (p *peer) canParse(compressionType compression.Type) bool {
+	if compressionType != compression.TypeZstd {
+		return true
+	}
+	return p.supports(featureZstdCompression)
+}
+
+// supports returns true if this peer advertised [feature]
+func (p *peer) supports(feature uint64) bool {
+	return atomic.LoadUint64(&p.supportedFeatures)&feature != 0
+}
+
+// features returns the names of the optional protocol features that this peer
+// advertised during the handshake.
+func (p *peer) features() []string {
+	return featureList(atomic.LoadUint64(&p.supportedFeatures))
+}
+
+// prefersIPv6 returns true if this peer is connected to over IPv6, in which
+// case it should be sent IPv6 addresses when they're known.
+func (p *peer) prefersIPv6() bool {
+	remoteIP, err := utils.ToIPDesc(p.conn.RemoteAddr().String())
+	return err == nil && !remoteIP.IsIPv4()
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) handle(msg message.InboundMessage, msgLen float64) {
 	now := p.net.clock.Time()
@@ -414,6 +687,7 @@ func (p *peer) handle(msg message.InboundMessage, msgLen float64) {
 	}
 	msgMetrics.numReceived.Inc()
 	msgMetrics.receivedBytes.Add(msgLen)
+	p.msgsReceived.Inc(op)
 	// assume that if [saved] == 0, [msg] wasn't compressed
 	if saved := msg.BytesSavedCompression(); saved != 0 {
 		msgMetrics.savedReceivedBytes.Observe(float64(saved))
@@ -444,6 +718,22 @@ func (p *peer) handle(msg message.InboundMessage, msgLen float64) {
 		p.handlePeerList(msg)
 		msg.OnFinishedHandling()
 		return
+	case message.IPv6:
+		p.handleIPv6(msg)
+		msg.OnFinishedHandling()
+		return
+	case message.Features:
+		p.handleFeatures(msg)
+		msg.OnFinishedHandling()
+		return
+	case message.Subnets:
+		p.handleSubnets(msg)
+		msg.OnFinishedHandling()
+		return
+	case message.SignedIP:
+		p.handleSignedIP(msg)
+		msg.OnFinishedHandling()
+		return
 	}
 	if !p.finishedHandshake.GetValue() {
 		p.net.log.Debug("dropping %s from %s%s at %s because handshake isn't finished", op, constants.NodeIDPrefix, p.nodeID, p.getIP())
@@ -460,6 +750,10 @@ func (p *peer) handle(msg message.InboundMessage, msgLen float64) {
 	}
 
 	// Consensus and app-level messages
+	atomic.StoreInt64(&p.net.lastConsensusMsgReceivedTime, now.Unix())
+	if op == message.Put || op == message.PushQuery {
+		p.net.gossipFanout.received(msg)
+	}
 	p.net.router.HandleInbound(msg)
 }
 
@@ -475,25 +769,34 @@ func (p *peer) close() {
 
 	p.closed.SetValue(true)
 
+	p.pingLock.Lock()
+	if p.pingTimer != nil {
+		p.pingTimer.Cancel()
+	}
+	p.pingLock.Unlock()
+
 	if err := p.conn.Close(); err != nil {
 		p.net.log.Debug("closing connection to %s%s at %s resulted in an error: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
 	}
 
-	// Remove this node from the throttler.
+	// Remove this node from the throttlers.
 	p.net.inboundMsgThrottler.RemoveNode(p.nodeID)
+	p.net.outboundBandwidthThrottler.RemoveNode(p.nodeID)
 
 	p.sendQueueCond.L.Lock()
 	// Release the bytes of the unsent messages to the outbound message throttler
-	for i := 0; i < len(p.sendQueue); i++ {
-		msg := p.sendQueue[i]
-		p.net.outboundMsgThrottler.Release(uint64(len(msg.Bytes())), p.nodeID)
-		msg.DecRef()
+	for _, queue := range [][]message.OutboundMessage{p.prioritySendQueue, p.sendQueue} {
+		for _, msg := range queue {
+			p.net.outboundMsgThrottler.Release(uint64(len(msg.Bytes())), p.nodeID)
+			msg.DecRef()
+		}
 	}
+	p.prioritySendQueue = nil
 	p.sendQueue = nil
 	p.sendQueueCond.L.Unlock()
 	// Per [p.sendQueueCond]'s spec, it is signalled when [p.closed] is set to true
 	// so that we exit the WriteMessages goroutine.
-	// Since [p.closed] is now true, nothing else will be put on [p.sendQueue]
+	// Since [p.closed] is now true, nothing else will be put on the send queues
 	p.sendQueueCond.Signal()
 	p.net.disconnected(p)
 }
@@ -514,7 +817,7 @@ func (p *peer) sendVersion() {
 		p.net.stateLock.RUnlock()
 		return
 	}
-	whitelistedSubnets := p.net.config.WhitelistedSubnets
+	whitelistedSubnets := p.net.whitelistedSubnets
 	msg, err := p.net.mc.Version(
 		p.net.config.NetworkID,
 		p.net.dummyNodeID,
@@ -528,6 +831,35 @@ func (p *peer) sendVersion() {
 	p.net.stateLock.RUnlock()
 	p.net.log.AssertNoError(err)
 
+	p.net.send(msg, false, []*peer{p})
+	p.sendFeatures()
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendFeatures() {
+	msg, err := p.net.mc.Features(p.net.supportedFeatures)
+	p.net.log.AssertNoError(err)
+
+	p.net.send(msg, false, []*peer{p})
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendIPv6() {
+	if p.net.config.MyIPv6.IsZero() {
+		return
+	}
+
+	p.net.stateLock.RLock()
+	versionTime, sig, err := p.net.getIPv6Version()
+	p.net.stateLock.RUnlock()
+	if err != nil {
+		p.net.log.Warn("failed to sign IPv6 address %s: %s", p.net.config.MyIPv6, err)
+		return
+	}
+
+	msg, err := p.net.mc.IPv6(p.net.config.MyIPv6, versionTime, sig)
+	p.net.log.AssertNoError(err)
+
 	p.net.send(msg, false, []*peer{p})
 }
 
@@ -541,7 +873,7 @@ func (p *peer) sendGetPeerList() {
 
 // assumes the stateLock is not held
 func (p *peer) sendPeerList() {
-	peers, err := p.net.validatorIPs()
+	peers, err := p.net.validatorIPs(p.prefersIPv6())
 	if err != nil {
 		return
 	}
@@ -560,6 +892,7 @@ func (p *peer) sendPing() {
 	msg, err := p.net.mc.Ping()
 	p.net.log.AssertNoError(err)
 
+	atomic.StoreInt64(&p.pingSent, p.net.clock.Time().UnixNano())
 	p.net.send(msg, false, []*peer{p})
 }
 
@@ -628,6 +961,7 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 	peerVersion, err := p.net.parser.Parse(peerVersionStr)
 	if err != nil {
 		p.net.log.Debug("version of %s%s at %s could not be parsed: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.net.config.PeerScorer.Observe(p.nodeID, common.HandshakeFailure)
 		p.discardIP()
 		p.net.metrics.failedToParse.Inc()
 		return
@@ -649,6 +983,7 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 
 	if err := p.net.versionCompatibility.Compatible(peerVersion); err != nil {
 		p.net.log.Verbo("peer %s%s at %s version (%s) not compatible: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), peerVersion, err)
+		p.net.config.PeerScorer.Observe(p.nodeID, common.HandshakeFailure)
 		p.discardIP()
 		return
 	}
@@ -673,24 +1008,21 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 	}
 
 	// handle subnet IDs
-	subnetIDsBytes := msg.Get(message.TrackedSubnets).([][]byte)
-	for _, subnetIDBytes := range subnetIDsBytes {
-		subnetID, err := ids.ToID(subnetIDBytes)
-		if err != nil {
-			p.net.log.Debug("tracked subnet of %s%s at %s could not be parsed: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
-			p.discardIP()
-			return
-		}
-		// add only if we also track this subnet
-		if p.net.config.WhitelistedSubnets.Contains(subnetID) {
-			p.trackedSubnets.Add(subnetID)
-		}
+	advertisedSubnets, err := parseSubnets(msg)
+	if err != nil {
+		p.net.log.Debug("tracked subnet of %s%s at %s could not be parsed: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.discardIP()
+		return
 	}
+	p.net.stateLock.RLock()
+	p.setAdvertisedSubnets(advertisedSubnets)
+	p.net.stateLock.RUnlock()
 
 	sig := msg.Get(message.SigBytes).([]byte)
 	signed := ipAndTimeBytes(peerIP, versionTime)
 	if err := p.cert.CheckSignature(p.cert.SignatureAlgorithm, signed, sig); err != nil {
 		p.net.log.Debug("signature verification failed for %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.net.config.PeerScorer.Observe(p.nodeID, common.HandshakeFailure)
 		p.discardIP()
 		return
 	}
@@ -729,6 +1061,152 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 	p.tryMarkFinishedHandshake()
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) handleFeatures(msg message.InboundMessage) {
+	if p.gotFeatures.GetValue() {
+		p.net.log.Verbo("dropping duplicated features message from %s%s at %s", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+	p.gotFeatures.SetValue(true)
+
+	atomic.StoreUint64(&p.supportedFeatures, msg.Get(message.SupportedFeatures).(uint64))
+
+	if p.supports(featureIPv6) {
+		p.sendIPv6()
+	}
+	if ip := p.getIP(); p.supports(featureQUIC) && p.net.quicDialer != nil && !ip.IsZero() {
+		p.net.quicDialer.AcceptsQUIC(ip)
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleSubnets(msg message.InboundMessage) {
+	if !p.finishedHandshake.GetValue() {
+		p.net.log.Verbo("dropping subnets message from %s%s at %s because handshake isn't finished", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+
+	advertisedSubnets, err := parseSubnets(msg)
+	if err != nil {
+		p.net.log.Debug("tracked subnet of %s%s at %s could not be parsed: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+
+	p.net.stateLock.Lock()
+	p.setAdvertisedSubnets(advertisedSubnets)
+	p.net.stateLock.Unlock()
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleIPv6(msg message.InboundMessage) {
+	ip := msg.Get(message.IP).(utils.IPDesc)
+	switch {
+	case ip.IsZero(), ip.IsIPv4():
+		p.net.log.Debug("%s%s at %s advertised invalid IPv6 address %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), ip)
+		return
+	case !p.net.config.AllowPrivateIPs && ip.IsPrivate():
+		p.net.log.Verbo("ignoring private IPv6 address %s of %s%s", ip, constants.NodeIDPrefix, p.nodeID)
+		return
+	}
+
+	versionTime := msg.Get(message.VersionTime).(uint64)
+	if float64(versionTime)-float64(p.net.clock.Unix()) > p.net.config.MaxClockDifference.Seconds() {
+		p.net.log.Debug(
+			"%s%s at %s advertised IPv6 address with version timestamp (%d) too far in the future",
+			constants.NodeIDPrefix, p.nodeID, p.getIP(), versionTime,
+		)
+		return
+	}
+
+	sig := msg.Get(message.SigBytes).([]byte)
+	signed := ipAndTimeBytes(ip, versionTime)
+	if err := p.cert.CheckSignature(p.cert.SignatureAlgorithm, signed, sig); err != nil {
+		p.net.log.Debug("IPv6 signature verification failed for %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.net.config.PeerScorer.Observe(p.nodeID, common.HandshakeFailure)
+		return
+	}
+
+	p.ipv6.SetValue(signedPeerIP{
+		ip:        ip,
+		time:      versionTime,
+		signature: sig,
+	})
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleSignedIP(msg message.InboundMessage) {
+	if !p.finishedHandshake.GetValue() {
+		p.net.log.Verbo("dropping signed IP from %s%s at %s because handshake isn't finished", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+
+	ip := msg.Get(message.IP).(utils.IPDesc)
+	if ip.IsZero() {
+		p.net.log.Debug("%s%s at %s advertised an invalid signed IP", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+
+	versionTime := msg.Get(message.VersionTime).(uint64)
+	if float64(versionTime)-float64(p.net.clock.Unix()) > p.net.config.MaxClockDifference.Seconds() {
+		p.net.log.Debug(
+			"%s%s at %s advertised signed IP with version timestamp (%d) too far in the future",
+			constants.NodeIDPrefix, p.nodeID, p.getIP(), versionTime,
+		)
+		return
+	}
+	// Only fresher signatures replace the one the peer advertised
+	if signedIP, ok := p.sigAndTime.GetValue().(signedPeerIP); ok && signedIP.time >= versionTime {
+		p.net.log.Verbo(
+			"ignoring signed IP of %s%s at %s: the given timestamp (%d) <= latest (%d)",
+			constants.NodeIDPrefix, p.nodeID, p.getIP(), versionTime, signedIP.time,
+		)
+		return
+	}
+
+	sig := msg.Get(message.SigBytes).([]byte)
+	signed := ipAndTimeBytes(ip, versionTime)
+	if err := p.cert.CheckSignature(p.cert.SignatureAlgorithm, signed, sig); err != nil {
+		p.net.log.Debug("IP signature verification failed for %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.net.config.PeerScorer.Observe(p.nodeID, common.HandshakeFailure)
+		return
+	}
+
+	p.sigAndTime.SetValue(signedPeerIP{
+		ip:        ip,
+		time:      versionTime,
+		signature: sig,
+	})
+}
+
+// setAdvertisedSubnets records the subnets this peer advertised and tracks
+// the ones this node also tracks. Assumes [stateLock] is held. It only needs
+// to be held for reading before the handshake is finished.
+func (p *peer) setAdvertisedSubnets(advertisedSubnets ids.Set) {
+	p.advertisedSubnets = advertisedSubnets
+	p.trackedSubnets = ids.NewSet(advertisedSubnets.Len() + 1)
+	p.trackedSubnets.Add(constants.PrimaryNetworkID)
+	for subnetID := range advertisedSubnets {
+		// add only if we also track this subnet
+		if p.net.whitelistedSubnets.Contains(subnetID) {
+			p.trackedSubnets.Add(subnetID)
+		}
+	}
+}
+
+// parseSubnets returns the subnets in the TrackedSubnets field of [msg]
+func parseSubnets(msg message.InboundMessage) (ids.Set, error) {
+	subnetIDsBytes := msg.Get(message.TrackedSubnets).([][]byte)
+	subnets := ids.NewSet(len(subnetIDsBytes))
+	for _, subnetIDBytes := range subnetIDsBytes {
+		subnetID, err := ids.ToID(subnetIDBytes)
+		if err != nil {
+			return nil, err
+		}
+		subnets.Add(subnetID)
+	}
+	return subnets, nil
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) handleGetPeerList(_ message.InboundMessage) {
 	if p.gotVersion.GetValue() && !p.peerListSent.GetValue() {
@@ -743,6 +1221,8 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 	switch {
 	case peer.IPDesc.Equal(p.net.currentIP.IP()):
 		return
+	case peer.IPDesc.Equal(p.net.config.MyIPv6):
+		return
 	case peer.IPDesc.IsZero():
 		return
 	case !p.net.config.AllowPrivateIPs && peer.IPDesc.IsPrivate():
@@ -753,6 +1233,10 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 		p.net.log.Debug("ignoring gossiped peer with version timestamp (%d) too far in the future", peer.Time)
 		return
 	}
+	if p.net.isIPStale(peer.Time) {
+		p.net.log.Verbo("ignoring gossiped peer at %s with stale version timestamp (%d)", peer.IPDesc, peer.Time)
+		return
+	}
 
 	nodeID := certToID(peer.Cert)
 	if !p.net.config.Validators.Contains(constants.PrimaryNetworkID, nodeID) && !p.net.config.Beacons.Contains(nodeID) {
@@ -816,7 +1300,9 @@ func (p *peer) handlePing(_ message.InboundMessage) {
 
 // assumes the [stateLock] is not held
 func (p *peer) handlePong(msg message.InboundMessage) {
-	if !p.net.shouldHoldConnection(p.nodeID) {
+	p.recordLatency()
+
+	if !p.net.stillHoldsConnection(p.nodeID) {
 		p.net.log.Debug("disconnecting from peer %s%s at %s because the peer is not a validator", constants.NodeIDPrefix, p.nodeID, p.getIP())
 		p.discardIP()
 		return
@@ -833,6 +1319,14 @@ func (p *peer) handlePong(msg message.InboundMessage) {
 		p.discardIP()
 	}
 
+	// Beacons are never disconnected from, as we may not be able to bootstrap
+	// without them.
+	if p.net.config.PeerScorer.IsPoor(p.nodeID) && !p.net.config.Beacons.Contains(p.nodeID) {
+		p.net.log.Debug("disconnecting from peer %s%s at %s because its score (%f) is too low", constants.NodeIDPrefix, p.nodeID, p.getIP(), p.net.config.PeerScorer.Score(p.nodeID))
+		p.discardIP()
+		return
+	}
+
 	// if the peer or this node is not a validator, we don't need their uptime.
 	if p.net.config.Validators.Contains(constants.PrimaryNetworkID, p.nodeID) &&
 		p.net.config.Validators.Contains(constants.PrimaryNetworkID, p.net.config.MyNodeID) {
@@ -843,6 +1337,23 @@ func (p *peer) handlePong(msg message.InboundMessage) {
 	}
 }
 
+// recordLatency records the round-trip time of the Ping/Pong exchange that
+// just completed, if a Ping is outstanding
+func (p *peer) recordLatency() {
+	pingSent := atomic.SwapInt64(&p.pingSent, 0)
+	if pingSent == 0 {
+		return
+	}
+	latency := p.net.clock.Time().UnixNano() - pingSent
+	if latency < 0 {
+		return
+	}
+	atomic.StoreInt64(&p.latency, latency)
+	p.net.metrics.pingLatency.
+		WithLabelValues(p.nodeID.PrefixedString(constants.NodeIDPrefix)).
+		Observe(float64(latency) / float64(time.Millisecond))
+}
+
 // assumes the [stateLock] is held
 func (p *peer) tryMarkFinishedHandshake() {
 	if !p.finishedHandshake.GetValue() && // not already marked as finished with handshake
@@ -4,19 +4,117 @@
 package network
 
 import (
+	"sync"
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/utils/json"
 )
 
 type PeerInfo struct {
-	IP             string     `json:"ip"`
-	PublicIP       string     `json:"publicIP,omitempty"`
-	ID             string     `json:"nodeID"`
-	Version        string     `json:"version"`
+	IP         string `json:"ip"`
+	PublicIP   string `json:"publicIP,omitempty"`
+	PublicIPv6 string `json:"publicIPv6,omitempty"`
+	ID         string `json:"nodeID"`
+	Version    string `json:"version"`
+	// False if the peer is still performing the handshake
+	Connected      bool       `json:"connected"`
 	LastSent       time.Time  `json:"lastSent"`
 	LastReceived   time.Time  `json:"lastReceived"`
 	Benched        []ids.ID   `json:"benched"`
 	ObservedUptime json.Uint8 `json:"observedUptime"`
+	// Chain ID --> Why, and until when, the peer is benched on that chain
+	BenchInfo map[ids.ID]benchlist.BenchInfo `json:"benchInfo"`
+	// True if the peer's score is too low, in which case it's benched after a
+	// single failed query and avoided when fetching containers
+	PoorScore bool `json:"poorScore"`
+	// Subnets the peer said it's tracking during the handshake
+	TrackedSubnets []ids.ID `json:"trackedSubnets"`
+	// Optional protocol features that the peer advertised during the
+	// handshake
+	Features []string `json:"features"`
+	// Number of bytes sent to and received from the peer since the
+	// connection was established
+	BytesSent     json.Uint64 `json:"bytesSent"`
+	BytesReceived json.Uint64 `json:"bytesReceived"`
+	// Message type --> Number of messages of that type sent to and received
+	// from the peer since the connection was established
+	MessagesSent     map[string]json.Uint64 `json:"messagesSent"`
+	MessagesReceived map[string]json.Uint64 `json:"messagesReceived"`
+	// Round-trip time, in milliseconds, of the last Ping/Pong exchange with
+	// the peer. 0 if no exchange completed yet.
+	LatencyMs json.Float64 `json:"latencyMs"`
+}
+
+// PeerScore describes how this node currently rates a peer. It's meant to be
+// consumed by external automation that decides which peers to whitelist or
+// report.
+type PeerScore struct {
+	ID string `json:"nodeID"`
+	// Score of the peer's past behavior. Starts at 0, increases when the peer
+	// is useful and decreases when it isn't.
+	Score json.Float64 `json:"score"`
+	// True if [Score] is too low, in which case the peer is benched after a
+	// single failed query and avoided when fetching containers
+	PoorScore bool `json:"poorScore"`
+	// Chain ID --> Why, and until when, the peer is benched on that chain
+	BenchInfo map[ids.ID]benchlist.BenchInfo `json:"benchInfo"`
+	// Chain ID --> Recent portion of this node's CPU time spent processing
+	// messages from the peer on that chain. Chains that didn't recently
+	// process any message from the peer are omitted.
+	CPUUtilization map[ids.ID]json.Float64 `json:"cpuUtilization"`
+}
+
+// PeersFilter describes the peers to return information about
+type PeersFilter struct {
+	// If non-empty, only the peers with these node IDs match. Repeated node
+	// IDs are only matched once.
+	NodeIDs []ids.ShortID
+	// If false, only the peers that finished the handshake match
+	IncludeHandshaking bool
+	// If true, only the peers that are benched on at least one chain match
+	BenchedOnly bool
+	// If non-nil, only the peers that track *[SubnetID] match. Every peer
+	// tracks the primary network.
+	SubnetID *ids.ID
+	// If true, only the peers that validate *[SubnetID] match, or the peers
+	// that validate the primary network if [SubnetID] is nil.
+	ValidatorOnly bool
+	// Only the peers whose node ID is >= [StartNodeID] match
+	StartNodeID ids.ShortID
+	// If > 0, at most [Limit] peers are returned
+	Limit int
+}
+
+// opCounter counts messages by type.
+// It's safe for multiple goroutines to use it concurrently.
+type opCounter struct {
+	lock sync.Mutex
+	// Op --> Number of messages with that op
+	counts map[message.Op]uint64
+}
+
+// Inc increments the number of messages with [op]
+func (c *opCounter) Inc(op message.Op) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.counts == nil {
+		c.counts = make(map[message.Op]uint64)
+	}
+	c.counts[op]++
+}
+
+// Counts returns the number of messages of each type that were counted
+func (c *opCounter) Counts() map[string]json.Uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	counts := make(map[string]json.Uint64, len(c.counts))
+	for op, count := range c.counts {
+		counts[op.String()] = json.Uint64(count)
+	}
+	return counts
 }
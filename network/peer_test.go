@@ -6,15 +6,26 @@ package network
 import (
 	"context"
 	"crypto"
+	"crypto/rand"
+	"crypto/x509"
 	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/dialer"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
+	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +54,18 @@ func (m *TestMsg) BytesSavedCompression() int {
 	return 0
 }
 
+func (m *TestMsg) CompressionType() compression.Type {
+	return compression.TypeNone
+}
+
+func (*TestMsg) HasExtensions() bool {
+	return false
+}
+
+func (*TestMsg) IsProto() bool {
+	return false
+}
+
 func (m *TestMsg) AddRef() {}
 
 func (m *TestMsg) DecRef() {}
@@ -122,3 +145,249 @@ func TestPeer_Close(t *testing.T) {
 
 	peer.Close()
 }
+
+func TestPeerCanParse(t *testing.T) {
+	p := &peer{}
+	assert.True(t, p.canParse(compression.TypeNone))
+	assert.True(t, p.canParse(compression.TypeGzip))
+	// The peer didn't advertise zstd support
+	assert.False(t, p.canParse(compression.TypeZstd))
+
+	p.supportedFeatures = featureIPv6
+	assert.True(t, p.canParse(compression.TypeGzip))
+	assert.False(t, p.canParse(compression.TypeZstd))
+
+	p.supportedFeatures = featureZstdCompression
+	assert.True(t, p.canParse(compression.TypeZstd))
+}
+
+func TestPeerFeatures(t *testing.T) {
+	p := &peer{}
+	// The peer didn't advertise any features
+	assert.Empty(t, p.features())
+
+	p.supportedFeatures = featureIPv6
+	assert.Equal(t, []string{"ipv6"}, p.features())
+
+	// Unknown features aren't reported
+	p.supportedFeatures = featureZstdCompression | featureIPv6 | 1<<63
+	assert.Equal(t, []string{"zstdCompression", "ipv6"}, p.features())
+}
+
+type testQUICDialer struct {
+	dialer.Dialer
+	acceptsQUIC []utils.IPDesc
+}
+
+func (d *testQUICDialer) AcceptsQUIC(ip utils.IPDesc) {
+	d.acceptsQUIC = append(d.acceptsQUIC, ip)
+}
+
+func TestPeerHandleFeaturesAcceptsQUIC(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+
+	quicDialer := &testQUICDialer{}
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	newPeerWithFeatures := func(features uint64) *peer {
+		p := &peer{
+			net: &network{
+				log:        logging.NoLog{},
+				mc:         mc,
+				quicDialer: quicDialer,
+			},
+			ip: ip,
+		}
+		outMsg, err := mc.Features(features)
+		assert.NoError(err)
+		inMsg, err := mc.Parse(outMsg.Bytes(), p.nodeID, func() {})
+		assert.NoError(err)
+		p.handleFeatures(inMsg)
+		return p
+	}
+
+	// The peer doesn't accept QUIC connections
+	newPeerWithFeatures(featureZstdCompression)
+	assert.Empty(quicDialer.acceptsQUIC)
+
+	p := newPeerWithFeatures(featureZstdCompression | featureQUIC)
+	assert.Equal([]utils.IPDesc{ip}, quicDialer.acceptsQUIC)
+	assert.Equal([]string{"zstdCompression", "quic"}, p.features())
+}
+
+func TestPeerSendQueuePriority(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	ancestors, err := mc.Ancestors(chainID, 1, [][]byte{{1}})
+	assert.NoError(err)
+	appGossip, err := mc.AppGossip(chainID, []byte{2})
+	assert.NoError(err)
+	chits, err := mc.Chits(chainID, 3, []ids.ID{ids.GenerateTestID()})
+	assert.NoError(err)
+	ping, err := mc.Ping()
+	assert.NoError(err)
+
+	p := &peer{}
+	for _, msg := range []message.OutboundMessage{ancestors, chits, appGossip, ping} {
+		p.pushSendQueue(msg)
+	}
+
+	// Priority messages preempt the bulk messages, and each lane keeps its
+	// order
+	for _, expectedOp := range []message.Op{message.Chits, message.Ping, message.Ancestors, message.AppGossip} {
+		assert.Equal(expectedOp, p.popSendQueue().Op())
+	}
+	assert.Empty(p.prioritySendQueue)
+	assert.Empty(p.sendQueue)
+}
+
+func TestPeerPriorityMessagesSkipBandwidthWait(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+	chits, err := mc.Chits(ids.GenerateTestID(), 1, []ids.ID{ids.GenerateTestID()})
+	assert.NoError(err)
+
+	bandwidthThrottler, err := throttling.NewOutboundBandwidthThrottler(
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+		throttling.OutboundBandwidthThrottlerConfig{},
+	)
+	assert.NoError(err)
+	p := newPeer(&network{
+		log:                        logging.NoLog{},
+		outboundMsgThrottler:       throttling.NewNoOutboundThrottler(),
+		outboundBandwidthThrottler: bandwidthThrottler,
+	}, nil, utils.IPDesc{})
+	p.tickerOnce.Do(func() {})
+
+	chitsBytes := append([]byte(nil), chits.Bytes()...)
+	written := make(chan []byte, 1)
+	writeMessage := func(msgBytes []byte) (uint64, bool) {
+		written <- append([]byte(nil), msgBytes...)
+		return uint64(len(msgBytes)), true
+	}
+	waited := make(chan bool, 1)
+	go func() {
+		waited <- p.waitBandwidth(time.Hour, writeMessage)
+	}()
+
+	// A priority message queued while a bulk message waits for bandwidth is
+	// sent right away
+	p.sendQueueCond.L.Lock()
+	p.pushSendQueue(chits)
+	p.sendQueueCond.L.Unlock()
+	select {
+	case msgBytes := <-written:
+		assert.Equal(chitsBytes, msgBytes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("priority message was held back by the bandwidth wait")
+	}
+	p.sendQueueCond.L.Lock()
+	assert.Empty(p.prioritySendQueue)
+	p.sendQueueCond.L.Unlock()
+
+	// Closing the peer stops the wait
+	close(p.tickerCloser)
+	assert.False(<-waited)
+}
+
+func TestPeerRecordLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	n := &network{}
+	assert.NoError(n.metrics.initialize("", prometheus.NewRegistry()))
+	now := time.Unix(1000, 0)
+	n.clock.Set(now)
+	p := &peer{net: n}
+
+	// A Pong without an outstanding Ping isn't measured
+	p.recordLatency()
+	assert.Zero(p.latency)
+
+	atomic.StoreInt64(&p.pingSent, now.UnixNano())
+	n.clock.Set(now.Add(40 * time.Millisecond))
+	p.recordLatency()
+	assert.Equal(int64(40*time.Millisecond), p.latency)
+	assert.Zero(p.pingSent)
+
+	metric := &dto.Metric{}
+	histogram := n.metrics.pingLatency.WithLabelValues(p.nodeID.PrefixedString(constants.NodeIDPrefix)).(prometheus.Histogram)
+	assert.NoError(histogram.Write(metric))
+	assert.EqualValues(1, metric.GetHistogram().GetSampleCount())
+	assert.EqualValues(40, metric.GetHistogram().GetSampleSum())
+}
+
+func TestPeerHandleSignedIP(t *testing.T) {
+	assert := assert.New(t)
+	initCerts(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(err)
+	cert, err := x509.ParseCertificate(cert0.Certificate[0])
+	assert.NoError(err)
+	key := cert0.PrivateKey.(crypto.Signer)
+
+	n := &network{
+		log: logging.NoLog{},
+		mc:  mc,
+		config: &Config{
+			PeerScorer:         scoring.NewNoScorer(),
+			MaxClockDifference: time.Minute,
+		},
+	}
+	n.clock.Set(time.Unix(1000, 0))
+	p := &peer{
+		net:  n,
+		cert: cert,
+	}
+	p.finishedHandshake.SetValue(true)
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	p.sigAndTime.SetValue(signedPeerIP{ip: ip, time: 900})
+
+	handleSignedIP := func(timestamp uint64, sig []byte) {
+		outMsg, err := mc.SignedIP(ip, timestamp, sig)
+		assert.NoError(err)
+		inMsg, err := mc.Parse(outMsg.Bytes(), p.nodeID, func() {})
+		assert.NoError(err)
+		p.handleSignedIP(inMsg)
+	}
+	sign := func(timestamp uint64) []byte {
+		sig, err := key.Sign(rand.Reader, ipAndTimeHash(ip, timestamp), crypto.SHA256)
+		assert.NoError(err)
+		return sig
+	}
+
+	// A fresher signature replaces the advertised one
+	sig := sign(950)
+	handleSignedIP(950, sig)
+	assert.Equal(signedPeerIP{ip: ip, time: 950, signature: sig}, p.sigAndTime.GetValue())
+
+	// Older signatures, invalid signatures and signatures from the future
+	// don't
+	handleSignedIP(940, sign(940))
+	handleSignedIP(960, sign(970))
+	handleSignedIP(2000, sign(2000))
+	assert.Equal(signedPeerIP{ip: ip, time: 950, signature: sig}, p.sigAndTime.GetValue())
+}
+
+func TestIsIPStale(t *testing.T) {
+	n := &network{config: &Config{}}
+	n.clock.Set(time.Unix(int64(2*time.Hour/time.Second), 0))
+
+	// IPs never become stale if there's no max age
+	assert.False(t, n.isIPStale(0))
+
+	n.config.PeerListMaxIPAge = time.Hour
+	assert.True(t, n.isIPStale(0))
+	assert.False(t, n.isIPStale(uint64(time.Hour/time.Second)))
+	assert.False(t, n.isIPStale(uint64(2*time.Hour/time.Second)))
+}
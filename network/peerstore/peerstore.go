@@ -0,0 +1,218 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peerstore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const (
+	valueLen = wrappers.IPLen + wrappers.LongLen
+
+	// flushFrequency is how often the peers that were connected to are
+	// written to the database
+	flushFrequency = time.Minute
+)
+
+var (
+	_ Store = &store{}
+	_ Store = &noStore{}
+)
+
+// Peer is a peer that this node was connected to.
+type Peer struct {
+	NodeID        ids.ShortID
+	IP            utils.IPDesc
+	LastConnected time.Time
+}
+
+// Store persists the peers that this node connected to, so that they can be
+// dialed after a restart rather than relying only on the bootstrap beacons.
+type Store interface {
+	// Connected records that this node connected to [nodeID] at [ip] at time
+	// [now]. The peer is persisted asynchronously, so this doesn't block on
+	// the database.
+	Connected(nodeID ids.ShortID, ip utils.IPDesc, now time.Time) error
+
+	// Peers returns the most recently connected to peers, most recent first.
+	// Peers beyond the maximum size of the store are removed from the store.
+	Peers() ([]Peer, error)
+
+	// Close persists the peers that haven't been persisted yet. The store
+	// must not be used after it's closed.
+	Close() error
+}
+
+type store struct {
+	log logging.Logger
+	// db is keyed by node ID. The value is the peer's IP followed by the unix
+	// time that the peer was last connected to.
+	db       database.Database
+	maxPeers int
+
+	lock sync.Mutex
+	// Node ID --> Peer connected to since the last flush
+	pending map[ids.ShortID]Peer
+
+	closeOnce sync.Once
+	closer    chan struct{}
+	flusherWg sync.WaitGroup
+}
+
+// New returns a Store that persists peers in [db] and keeps at most
+// [maxPeers] of them. [db] should not be used by anything else.
+// The peers that were connected to are written to [db], and the oldest peers
+// beyond [maxPeers] are removed from it, once per minute.
+func New(db database.Database, maxPeers int, log logging.Logger) Store {
+	return newStore(db, maxPeers, flushFrequency, log)
+}
+
+func newStore(db database.Database, maxPeers int, flushFrequency time.Duration, log logging.Logger) *store {
+	s := &store{
+		log:      log,
+		db:       db,
+		maxPeers: maxPeers,
+		pending:  make(map[ids.ShortID]Peer),
+		closer:   make(chan struct{}),
+	}
+	s.flusherWg.Add(1)
+	go s.flushPeriodically(flushFrequency)
+	return s
+}
+
+func (s *store) flushPeriodically(frequency time.Duration) {
+	defer s.flusherWg.Done()
+
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.Peers(); err != nil {
+				s.log.Warn("failed to persist peers due to %s", err)
+			}
+		case <-s.closer:
+			return
+		}
+	}
+}
+
+func (s *store) Connected(nodeID ids.ShortID, ip utils.IPDesc, now time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.pending[nodeID] = Peer{
+		NodeID:        nodeID,
+		IP:            ip,
+		LastConnected: now,
+	}
+	return nil
+}
+
+// flush writes the pending peers to the database in a single batch
+func (s *store) flush() error {
+	s.lock.Lock()
+	pending := s.pending
+	s.pending = make(map[ids.ShortID]Peer)
+	s.lock.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	for nodeID, peer := range pending {
+		p := wrappers.Packer{Bytes: make([]byte, valueLen)}
+		p.PackIP(peer.IP)
+		p.PackLong(uint64(peer.LastConnected.Unix()))
+		if p.Err != nil {
+			return p.Err
+		}
+		if err := batch.Put(nodeID[:], p.Bytes); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+func (s *store) Peers() ([]Peer, error) {
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+	return s.prune()
+}
+
+func (s *store) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closer)
+	})
+	s.flusherWg.Wait()
+	return s.flush()
+}
+
+// prune removes the oldest peers beyond [s.maxPeers] from the database and
+// returns the remaining peers, most recent first
+func (s *store) prune() ([]Peer, error) {
+	it := s.db.NewIterator()
+	defer it.Release()
+
+	peers := []Peer(nil)
+	for it.Next() {
+		nodeID, err := ids.ToShortID(it.Key())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse peer node ID: %w", err)
+		}
+		p := wrappers.Packer{Bytes: it.Value()}
+		ip := p.UnpackIP()
+		lastConnected := p.UnpackLong()
+		if p.Err != nil {
+			return nil, fmt.Errorf("failed to parse peer %s: %w", nodeID, p.Err)
+		}
+		peers = append(peers, Peer{
+			NodeID:        nodeID,
+			IP:            ip,
+			LastConnected: time.Unix(int64(lastConnected), 0),
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		return peers[i].LastConnected.After(peers[j].LastConnected)
+	})
+	if len(peers) <= s.maxPeers {
+		return peers, nil
+	}
+
+	// Forget the peers that were connected to the longest time ago
+	batch := s.db.NewBatch()
+	for _, peer := range peers[s.maxPeers:] {
+		if err := batch.Delete(peer.NodeID[:]); err != nil {
+			return nil, err
+		}
+	}
+	return peers[:s.maxPeers], batch.Write()
+}
+
+type noStore struct{}
+
+// NewNoStore returns a Store that doesn't persist any peers.
+func NewNoStore() Store { return &noStore{} }
+
+func (*noStore) Connected(ids.ShortID, utils.IPDesc, time.Time) error { return nil }
+
+func (*noStore) Peers() ([]Peer, error) { return nil, nil }
+
+func (*noStore) Close() error { return nil }
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peerstore
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+func TestStorePersistsPeers(t *testing.T) {
+	db := memdb.New()
+	s := New(db, 10, logging.NoLog{})
+
+	nodeID := ids.GenerateTestShortID()
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	now := time.Unix(1000, 0)
+	assert.NoError(t, s.Connected(nodeID, ip, now))
+	assert.NoError(t, s.Close())
+
+	// A new store over the same database should return the persisted peer
+	s = New(db, 10, logging.NoLog{})
+	defer s.Close()
+	peers, err := s.Peers()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1)
+	assert.Equal(t, nodeID, peers[0].NodeID)
+	assert.True(t, ip.Equal(peers[0].IP))
+	assert.Equal(t, now, peers[0].LastConnected)
+}
+
+func TestStoreUpdatesPeer(t *testing.T) {
+	s := New(memdb.New(), 10, logging.NoLog{})
+	defer s.Close()
+
+	nodeID := ids.GenerateTestShortID()
+	oldIP := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	newIP := utils.IPDesc{IP: net.IPv4(5, 6, 7, 8), Port: 9651}
+	assert.NoError(t, s.Connected(nodeID, oldIP, time.Unix(1000, 0)))
+	assert.NoError(t, s.Connected(nodeID, newIP, time.Unix(2000, 0)))
+
+	peers, err := s.Peers()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1)
+	assert.True(t, newIP.Equal(peers[0].IP))
+	assert.Equal(t, time.Unix(2000, 0), peers[0].LastConnected)
+}
+
+func TestStorePrunesOldestPeers(t *testing.T) {
+	db := memdb.New()
+	s := New(db, 2, logging.NoLog{})
+	defer s.Close()
+
+	nodeIDs := []ids.ShortID{
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+	}
+	for i, nodeID := range nodeIDs {
+		ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, byte(i)), Port: 9651}
+		assert.NoError(t, s.Connected(nodeID, ip, time.Unix(int64(1000+i), 0)))
+	}
+
+	peers, err := s.Peers()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 2)
+	assert.Equal(t, nodeIDs[2], peers[0].NodeID)
+	assert.Equal(t, nodeIDs[1], peers[1].NodeID)
+
+	has, err := db.Has(nodeIDs[0][:])
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestStoreFlushesPeriodically(t *testing.T) {
+	db := memdb.New()
+	s := newStore(db, 1, 10*time.Millisecond, logging.NoLog{})
+	defer s.Close()
+
+	oldNodeID := ids.GenerateTestShortID()
+	newNodeID := ids.GenerateTestShortID()
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	assert.NoError(t, s.Connected(oldNodeID, ip, time.Unix(1000, 0)))
+	assert.NoError(t, s.Connected(newNodeID, ip, time.Unix(2000, 0)))
+
+	// The peers are written in the background, and the oldest one is pruned
+	assert.Eventually(t, func() bool {
+		hasNew, err := db.Has(newNodeID[:])
+		assert.NoError(t, err)
+		hasOld, err := db.Has(oldNodeID[:])
+		assert.NoError(t, err)
+		return hasNew && !hasOld
+	}, 5*time.Second, 10*time.Millisecond)
+}
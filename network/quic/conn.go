@@ -0,0 +1,306 @@
+//go:build quic
+// +build quic
+
+// ^ Only build this file if QUIC support is enabled, as quic-go only builds on
+// the Go versions it was released for
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	quicgo "github.com/lucas-clemente/quic-go"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+// maxPendingMessages is the max number of messages from the peer that are
+// received concurrently. The peer can't open more streams than this until the
+// earlier messages are read.
+const maxPendingMessages = 16
+
+var (
+	errNotStreamed     = errors.New("QUIC connections must be read and written one message at a time")
+	errMessageTooLarge = errors.New("message exceeds the max message size")
+	errMessageTooLong  = errors.New("message is longer than its length prefix")
+	errConnClosed      = errors.New("connection closed")
+
+	_ net.Conn = &Conn{}
+)
+
+// incomingMessage is a message that is being read from its own stream
+type incomingMessage struct {
+	stream quicgo.ReceiveStream
+
+	// Length prefix of the message and the number of its bytes read so far
+	lenBytes [wrappers.IntLen]byte
+	lenRead  int
+
+	// Allocated once the message is read, after its length is returned by
+	// ReadMessageLen
+	msg     []byte
+	msgRead int
+}
+
+// Conn is a net.Conn backed by a QUIC session. Rather than carrying a single
+// stream of bytes, each message is sent on its own unidirectional stream, so
+// that a lost packet only delays the message it belongs to, and so that the
+// messages queued behind a large message are transferred alongside it.
+// Messages are still read in the order they were written.
+//
+// Each message is prefixed with its length, so that the reader can wait for
+// room for the message before reading it. Until then, the bytes of the
+// message are buffered by QUIC, which bounds them with its flow control
+// windows.
+//
+// Messages must be read with ReadMessageLen and ReadMessage, and written with
+// WriteMessage. The TLS handshake has already been completed by the time a
+// Conn is returned, so the peer's certificate is immediately available.
+type Conn struct {
+	session quicgo.Session
+
+	// Streams opened by the peer that haven't been read yet, in the order
+	// they were opened
+	pending chan quicgo.ReceiveStream
+	// Message taken from [pending] that hasn't been fully read yet, if any.
+	// Kept until it's returned so that a read deadline doesn't drop it. Only
+	// accessed by ReadMessageLen and ReadMessage.
+	reading *incomingMessage
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	deadlineLock  sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func newConn(session quicgo.Session) *Conn {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Conn{
+		session: session,
+		pending: make(chan quicgo.ReceiveStream, maxPendingMessages),
+		closed:  make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go c.acceptStreams()
+	return c
+}
+
+// acceptStreams queues each stream the peer opens until the connection is
+// closed
+func (c *Conn) acceptStreams() {
+	for {
+		stream, err := c.session.AcceptUniStream(c.ctx)
+		if err != nil {
+			_ = c.Close()
+			return
+		}
+
+		select {
+		case c.pending <- stream:
+		case <-c.closed:
+			stream.CancelRead(0)
+			return
+		}
+	}
+}
+
+// ReadMessageLen returns the length of the next message written by the peer,
+// without reading the message. The message is then read by ReadMessage.
+// Must not be called concurrently with ReadMessageLen or ReadMessage.
+func (c *Conn) ReadMessageLen() (uint32, error) {
+	c.deadlineLock.Lock()
+	deadline := c.readDeadline
+	c.deadlineLock.Unlock()
+
+	if c.reading == nil {
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case stream := <-c.pending:
+			c.reading = &incomingMessage{stream: stream}
+		case <-c.closed:
+			return 0, errConnClosed
+		case <-timeout:
+			return 0, os.ErrDeadlineExceeded
+		}
+	}
+
+	m := c.reading
+	if err := m.stream.SetReadDeadline(deadline); err != nil {
+		return 0, c.readFailed(err)
+	}
+	if err := readFull(m.stream, m.lenBytes[:], &m.lenRead); err != nil {
+		return 0, c.readFailed(err)
+	}
+	msgLen := binary.BigEndian.Uint32(m.lenBytes[:])
+	if int64(msgLen) > int64(constants.DefaultMaxMessageSize) {
+		return 0, c.readFailed(errMessageTooLarge)
+	}
+	return msgLen, nil
+}
+
+// ReadMessage returns the next message written by the peer.
+// Must not be called concurrently with ReadMessageLen or ReadMessage.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	msgLen, err := c.ReadMessageLen()
+	if err != nil {
+		return nil, err
+	}
+
+	m := c.reading
+	if m.msg == nil {
+		m.msg = make([]byte, msgLen)
+	}
+	if err := readFull(m.stream, m.msg, &m.msgRead); err != nil {
+		return nil, c.readFailed(err)
+	}
+
+	// The message must end the stream. Reading the end of the stream also
+	// lets the peer open another one.
+	var extra [1]byte
+	switch n, err := m.stream.Read(extra[:]); {
+	case n > 0:
+		return nil, c.readFailed(errMessageTooLong)
+	case err != io.EOF:
+		return nil, c.readFailed(err)
+	}
+
+	c.reading = nil
+	return m.msg, nil
+}
+
+// readFailed returns [err] after dropping the message being read, unless
+// [err] is a read deadline, in which case the message is read by the next
+// call.
+func (c *Conn) readFailed(err error) error {
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		c.reading.stream.CancelRead(0)
+		c.reading = nil
+	}
+	return err
+}
+
+// readFull reads from [r] into [buf], starting after the [*read] bytes that
+// were already read, until [buf] is full. [*read] is updated as bytes are
+// read, so that a read that fails can be resumed.
+func readFull(r io.Reader, buf []byte, read *int) error {
+	for *read < len(buf) {
+		n, err := r.Read(buf[*read:])
+		*read += n
+		switch {
+		case *read == len(buf):
+			return nil
+		case err == io.EOF:
+			return io.ErrUnexpectedEOF
+		case err != nil:
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMessage sends [msg] to the peer on a new stream
+func (c *Conn) WriteMessage(msg []byte) error {
+	c.deadlineLock.Lock()
+	deadline := c.writeDeadline
+	c.deadlineLock.Unlock()
+
+	ctx := c.ctx
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	// Blocks until the peer allows another stream to be opened
+	stream, err := c.session.OpenUniStreamSync(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.SetWriteDeadline(deadline); err != nil {
+		stream.CancelWrite(0)
+		return err
+	}
+	msgLen := make([]byte, wrappers.IntLen)
+	binary.BigEndian.PutUint32(msgLen, uint32(len(msg)))
+	for _, b := range [][]byte{msgLen, msg} {
+		if _, err := stream.Write(b); err != nil {
+			stream.CancelWrite(0)
+			return err
+		}
+	}
+	return stream.Close()
+}
+
+// Read isn't supported. Use ReadMessage instead.
+func (c *Conn) Read([]byte) (int, error) { return 0, errNotStreamed }
+
+// Write isn't supported. Use WriteMessage instead.
+func (c *Conn) Write([]byte) (int, error) { return 0, errNotStreamed }
+
+// PeerCertificates returns the certificates presented by the remote peer
+// during the TLS handshake.
+func (c *Conn) PeerCertificates() []*x509.Certificate {
+	return c.session.ConnectionState().TLS.PeerCertificates
+}
+
+func (c *Conn) LocalAddr() net.Addr { return c.session.LocalAddr() }
+
+func (c *Conn) RemoteAddr() net.Addr { return c.session.RemoteAddr() }
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.deadlineLock.Lock()
+	defer c.deadlineLock.Unlock()
+
+	c.readDeadline = t
+	c.writeDeadline = t
+	return nil
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.deadlineLock.Lock()
+	defer c.deadlineLock.Unlock()
+
+	c.readDeadline = t
+	return nil
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.deadlineLock.Lock()
+	defer c.deadlineLock.Unlock()
+
+	c.writeDeadline = t
+	return nil
+}
+
+// Close tears down the underlying session.
+func (c *Conn) Close() error {
+	err := errConnClosed
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.cancel()
+		err = c.session.CloseWithError(0, "")
+	})
+	return err
+}
@@ -0,0 +1,93 @@
+//go:build quic
+// +build quic
+
+// ^ Only build this file if QUIC support is enabled, as quic-go only builds on
+// the Go versions it was released for
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+
+	quicgo "github.com/lucas-clemente/quic-go"
+)
+
+var (
+	errListenerClosed = errors.New("listener closed")
+
+	_ net.Listener = &listener{}
+)
+
+type listener struct {
+	listener quicgo.Listener
+	conns    chan *Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// Listen starts accepting QUIC sessions on [addr]. Each session is exposed as a
+// net.Conn once its handshake is complete.
+func Listen(addr string, tlsConfig *tls.Config) (net.Listener, error) {
+	l, err := quicgo.ListenAddr(addr, withNextProto(tlsConfig), quicConfig())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ql := &listener{
+		listener: l,
+		conns:    make(chan *Conn),
+		closed:   make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	go ql.acceptSessions()
+	return ql, nil
+}
+
+func (l *listener) acceptSessions() {
+	for {
+		session, err := l.listener.Accept(l.ctx)
+		if err != nil {
+			// Returns an error when the listener is closed.
+			return
+		}
+
+		conn := newConn(session)
+		select {
+		case l.conns <- conn:
+		case <-l.closed:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *listener) Close() error {
+	err := errListenerClosed
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		l.cancel()
+		err = l.listener.Close()
+	})
+	return err
+}
+
+func (l *listener) Addr() net.Addr { return l.listener.Addr() }
@@ -0,0 +1,66 @@
+//go:build quic
+// +build quic
+
+// ^ Only build this file if QUIC support is enabled, as quic-go only builds on
+// the Go versions it was released for
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	quicgo "github.com/lucas-clemente/quic-go"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+)
+
+const (
+	// NextProto is the ALPN protocol negotiated during the QUIC handshake.
+	// Peers that don't advertise it are rejected before any application data
+	// is exchanged. Version 3 sends each message on its own stream, prefixed
+	// with its length.
+	NextProto = "avalanche-p2p/3"
+
+	// handshakeIdleTimeout bounds how long a dial to a peer that isn't
+	// listening for QUIC connections takes to fail.
+	handshakeIdleTimeout = 5 * time.Second
+	maxIdleTimeout       = time.Minute
+
+	// maxReceiveWindow bounds the bytes of the messages that have been sent
+	// by the peer but not read yet, which QUIC buffers for the connection.
+	maxReceiveWindow = 2 * constants.DefaultMaxMessageSize
+)
+
+// Dial establishes a QUIC session with [addr].
+func Dial(ctx context.Context, addr string, tlsConfig *tls.Config) (*Conn, error) {
+	session, err := quicgo.DialAddrContext(ctx, addr, withNextProto(tlsConfig), quicConfig())
+	if err != nil {
+		return nil, err
+	}
+	return newConn(session), nil
+}
+
+func quicConfig() *quicgo.Config {
+	return &quicgo.Config{
+		HandshakeIdleTimeout: handshakeIdleTimeout,
+		MaxIdleTimeout:       maxIdleTimeout,
+		KeepAlive:            true,
+		// Messages are sent on unidirectional streams
+		MaxIncomingStreams:    -1,
+		MaxIncomingUniStreams: maxPendingMessages,
+
+		MaxStreamReceiveWindow:     maxReceiveWindow,
+		MaxConnectionReceiveWindow: maxReceiveWindow,
+	}
+}
+
+// withNextProto returns a copy of [config] that only negotiates [NextProto].
+func withNextProto(config *tls.Config) *tls.Config {
+	config = config.Clone()
+	config.NextProtos = []string{NextProto}
+	return config
+}
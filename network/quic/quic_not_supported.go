@@ -0,0 +1,33 @@
+//go:build !quic
+// +build !quic
+
+// ^ Only build this file if QUIC support is disabled
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+)
+
+var errQUICNotSupported = errors.New("QUIC isn't supported, the node must be built with the quic tag")
+
+// Conn is a QUIC connection. None are created when QUIC isn't supported.
+type Conn struct{ net.Conn }
+
+// PeerCertificates returns nil.
+func (*Conn) PeerCertificates() []*x509.Certificate { return nil }
+
+// Listen returns an error.
+func Listen(string, *tls.Config) (net.Listener, error) {
+	return nil, errQUICNotSupported
+}
+
+// Dial returns an error.
+func Dial(context.Context, string, *tls.Config) (*Conn, error) {
+	return nil, errQUICNotSupported
+}
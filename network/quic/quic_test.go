@@ -0,0 +1,246 @@
+//go:build quic
+// +build quic
+
+// ^ Only build this file if QUIC support is enabled, as quic-go only builds on
+// the Go versions it was released for
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/staking"
+)
+
+func newTLSConfig(t *testing.T) *tls.Config {
+	cert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	// #nosec G402
+	return &tls.Config{
+		Certificates:       []tls.Certificate{*cert},
+		ClientAuth:         tls.RequireAnyClientCert,
+		InsecureSkipVerify: true,
+	}
+}
+
+func TestDialAndAccept(t *testing.T) {
+	assert := assert.New(t)
+
+	serverConfig := newTLSConfig(t)
+	clientConfig := newTLSConfig(t)
+
+	listener, err := Listen("127.0.0.1:0", serverConfig)
+	assert.NoError(err)
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientConn, err := Dial(ctx, listener.Addr().String(), clientConfig)
+	assert.NoError(err)
+	defer clientConn.Close()
+
+	conn, err := listener.Accept()
+	assert.NoError(err)
+	defer conn.Close()
+
+	serverConn, ok := conn.(*Conn)
+	assert.True(ok)
+
+	msg := []byte("hello")
+	assert.NoError(clientConn.WriteMessage(msg))
+
+	received, err := serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Equal(msg, received)
+
+	clientCerts := serverConn.PeerCertificates()
+	assert.Len(clientCerts, 1)
+	assert.Equal(clientConfig.Certificates[0].Certificate[0], clientCerts[0].Raw)
+
+	serverCerts := clientConn.PeerCertificates()
+	assert.Len(serverCerts, 1)
+	assert.Equal(serverConfig.Certificates[0].Certificate[0], serverCerts[0].Raw)
+}
+
+func TestMessagesKeepTheirOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := Listen("127.0.0.1:0", newTLSConfig(t))
+	assert.NoError(err)
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientConn, err := Dial(ctx, listener.Addr().String(), newTLSConfig(t))
+	assert.NoError(err)
+	defer clientConn.Close()
+
+	conn, err := listener.Accept()
+	assert.NoError(err)
+	defer conn.Close()
+	serverConn := conn.(*Conn)
+
+	// Sends more messages than can be received at once, with a large message
+	// first, so that the streams are transferred concurrently.
+	numMsgs := 4 * maxPendingMessages
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(clientConn.WriteMessage(make([]byte, 1<<20)))
+		for i := 1; i < numMsgs; i++ {
+			assert.NoError(clientConn.WriteMessage([]byte(fmt.Sprintf("message %d", i))))
+		}
+	}()
+
+	msg, err := serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Len(msg, 1<<20)
+	for i := 1; i < numMsgs; i++ {
+		msg, err := serverConn.ReadMessage()
+		assert.NoError(err)
+		assert.Equal(fmt.Sprintf("message %d", i), string(msg))
+	}
+	wg.Wait()
+
+	// There are no more messages to read
+	assert.NoError(serverConn.SetReadDeadline(time.Now().Add(50 * time.Millisecond)))
+	_, err = serverConn.ReadMessage()
+	assert.ErrorIs(err, os.ErrDeadlineExceeded)
+
+	// The connection doesn't carry a byte stream
+	_, err = serverConn.Read(make([]byte, 1))
+	assert.ErrorIs(err, errNotStreamed)
+}
+
+func TestListenerClose(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := Listen("127.0.0.1:0", newTLSConfig(t))
+	assert.NoError(err)
+	assert.NoError(listener.Close())
+
+	_, err = listener.Accept()
+	assert.ErrorIs(err, errListenerClosed)
+}
+
+// newConnPair returns the two ends of a QUIC connection
+func newConnPair(t *testing.T) (*Conn, *Conn) {
+	listener, err := Listen("127.0.0.1:0", newTLSConfig(t))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientConn, err := Dial(ctx, listener.Addr().String(), newTLSConfig(t))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	conn, err := listener.Accept()
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return clientConn, conn.(*Conn)
+}
+
+func TestReadMessageLen(t *testing.T) {
+	assert := assert.New(t)
+
+	clientConn, serverConn := newConnPair(t)
+	assert.NoError(clientConn.WriteMessage([]byte("hello")))
+	assert.NoError(clientConn.WriteMessage(nil))
+
+	// The length of a message can be read before the message
+	msgLen, err := serverConn.ReadMessageLen()
+	assert.NoError(err)
+	assert.EqualValues(5, msgLen)
+	msgLen, err = serverConn.ReadMessageLen()
+	assert.NoError(err)
+	assert.EqualValues(5, msgLen)
+	msg, err := serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Equal("hello", string(msg))
+
+	msgLen, err = serverConn.ReadMessageLen()
+	assert.NoError(err)
+	assert.Zero(msgLen)
+	msg, err = serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Empty(msg)
+}
+
+func TestReadMessageRejectsBadLength(t *testing.T) {
+	assert := assert.New(t)
+
+	clientConn, serverConn := newConnPair(t)
+	writeRaw := func(b []byte) {
+		stream, err := clientConn.session.OpenUniStream()
+		assert.NoError(err)
+		_, err = stream.Write(b)
+		assert.NoError(err)
+		assert.NoError(stream.Close())
+	}
+
+	// The message is longer than the max message size
+	writeRaw([]byte{0xff, 0xff, 0xff, 0xff})
+	_, err := serverConn.ReadMessageLen()
+	assert.ErrorIs(err, errMessageTooLarge)
+
+	// The message is longer than its length prefix
+	writeRaw([]byte{0x00, 0x00, 0x00, 0x01, 0x01, 0x02})
+	_, err = serverConn.ReadMessage()
+	assert.ErrorIs(err, errMessageTooLong)
+
+	// The stream ends before the message does
+	writeRaw([]byte{0x00, 0x00, 0x00, 0x02, 0x01})
+	_, err = serverConn.ReadMessage()
+	assert.ErrorIs(err, io.ErrUnexpectedEOF)
+
+	// The connection is still usable
+	assert.NoError(clientConn.WriteMessage([]byte("hello")))
+	msg, err := serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Equal("hello", string(msg))
+}
+
+func TestReadDeadlineKeepsPartialMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	clientConn, serverConn := newConnPair(t)
+	partial, err := clientConn.session.OpenUniStreamSync(context.Background())
+	assert.NoError(err)
+	_, err = partial.Write([]byte{0x00, 0x00, 0x00, 0x07, 'p', 'a', 'r'})
+	assert.NoError(err)
+
+	// The deadline passes while the first message is still being received
+	assert.NoError(serverConn.SetReadDeadline(time.Now().Add(100 * time.Millisecond)))
+	_, err = serverConn.ReadMessage()
+	assert.ErrorIs(err, os.ErrDeadlineExceeded)
+
+	assert.NoError(clientConn.WriteMessage([]byte("next")))
+	_, err = partial.Write([]byte("tial"))
+	assert.NoError(err)
+	assert.NoError(partial.Close())
+
+	// The first message is still read before the next one
+	assert.NoError(serverConn.SetReadDeadline(time.Now().Add(10 * time.Second)))
+	msg, err := serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Equal("partial", string(msg))
+	msg, err = serverConn.ReadMessage()
+	assert.NoError(err)
+	assert.Equal("next", string(msg))
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scoring
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+const (
+	// Max number of peers whose scores are remembered. Once more peers have
+	// been scored, the least recently used scores are forgotten. Since scores
+	// decay towards 0, those are the scores that matter the least.
+	maxScores = 16384
+)
+
+var (
+	_ common.PeerScorer = &scorer{}
+	_ common.PeerScorer = &noScorer{}
+)
+
+// weights maps each event to how much it changes a peer's score.
+var weights = map[common.PeerEvent]float64{
+	common.ValidContainer:   1,
+	common.InvalidContainer: -5,
+	common.UselessResponse:  -1,
+	common.RequestTimeout:   -1,
+	common.HandshakeFailure: -10,
+}
+
+// Config defines the configuration for a peer scorer
+type Config struct {
+	// Enabled is true if peers should be scored.
+	Enabled bool `json:"enabled"`
+	// Halflife is the time it takes for a score to decay halfway to 0.
+	Halflife time.Duration `json:"halflife"`
+	// PoorThreshold is the score below which a peer is considered poor.
+	PoorThreshold float64 `json:"poorThreshold"`
+}
+
+type score struct {
+	value       float64
+	lastUpdated time.Time
+}
+
+type scorer struct {
+	config Config
+	clock  mockable.Clock
+
+	lock sync.Mutex
+	// Node ID --> *score
+	scores cache.LRU
+}
+
+// NewScorer returns a new peer scorer. If scoring is disabled, the returned
+// scorer reports every peer as having a score of 0.
+func NewScorer(config Config) common.PeerScorer {
+	if !config.Enabled {
+		return NewNoScorer()
+	}
+	return &scorer{
+		config: config,
+		scores: cache.LRU{Size: maxScores},
+	}
+}
+
+func (s *scorer) Observe(nodeID ids.ShortID, event common.PeerEvent) {
+	weight, ok := weights[event]
+	if !ok {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	nodeScore := &score{}
+	if cached, ok := s.scores.Get(nodeID); ok {
+		nodeScore = cached.(*score)
+	} else {
+		s.scores.Put(nodeID, nodeScore)
+	}
+	nodeScore.value = s.decay(nodeScore, now) + weight
+	nodeScore.lastUpdated = now
+}
+
+func (s *scorer) Score(nodeID ids.ShortID) float64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.score(nodeID, s.clock.Time())
+}
+
+func (s *scorer) IsPoor(nodeID ids.ShortID) bool {
+	return s.Score(nodeID) < s.config.PoorThreshold
+}
+
+func (s *scorer) Best(nodeIDs []ids.ShortID) (ids.ShortID, bool) {
+	if len(nodeIDs) == 0 {
+		return ids.ShortEmpty, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	bestNodeID := nodeIDs[0]
+	bestScore := s.score(bestNodeID, now)
+	for _, nodeID := range nodeIDs[1:] {
+		if nodeScore := s.score(nodeID, now); nodeScore > bestScore {
+			bestNodeID = nodeID
+			bestScore = nodeScore
+		}
+	}
+	return bestNodeID, true
+}
+
+// Assumes [s.lock] is held.
+func (s *scorer) score(nodeID ids.ShortID, now time.Time) float64 {
+	nodeScore, ok := s.scores.Get(nodeID)
+	if !ok {
+		return 0
+	}
+	return s.decay(nodeScore.(*score), now)
+}
+
+// decay returns the value of [nodeScore] at [now].
+func (s *scorer) decay(nodeScore *score, now time.Time) float64 {
+	elapsed := now.Sub(nodeScore.lastUpdated)
+	if elapsed <= 0 || s.config.Halflife <= 0 {
+		return nodeScore.value
+	}
+	halflives := float64(elapsed) / float64(s.config.Halflife)
+	return nodeScore.value * math.Pow(0.5, halflives)
+}
+
+type noScorer struct{}
+
+// NewNoScorer returns a peer scorer that reports every peer as having a score
+// of 0.
+func NewNoScorer() common.PeerScorer { return &noScorer{} }
+
+func (*noScorer) Observe(ids.ShortID, common.PeerEvent) {}
+
+func (*noScorer) Score(ids.ShortID) float64 { return 0 }
+
+func (*noScorer) IsPoor(ids.ShortID) bool { return false }
+
+func (*noScorer) Best(nodeIDs []ids.ShortID) (ids.ShortID, bool) {
+	if len(nodeIDs) == 0 {
+		return ids.ShortEmpty, false
+	}
+	return nodeIDs[0], true
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+)
+
+func TestScorerObserve(t *testing.T) {
+	s := NewScorer(Config{
+		Enabled:       true,
+		Halflife:      time.Minute,
+		PoorThreshold: -5,
+	}).(*scorer)
+	s.clock.Set(time.Now())
+
+	nodeID := ids.GenerateTestShortID()
+	assert.Equal(t, 0., s.Score(nodeID))
+	assert.False(t, s.IsPoor(nodeID))
+
+	s.Observe(nodeID, common.ValidContainer)
+	assert.Equal(t, 1., s.Score(nodeID))
+
+	s.Observe(nodeID, common.InvalidContainer)
+	assert.Equal(t, -4., s.Score(nodeID))
+	assert.False(t, s.IsPoor(nodeID))
+
+	s.Observe(nodeID, common.RequestTimeout)
+	s.Observe(nodeID, common.UselessResponse)
+	assert.Equal(t, -6., s.Score(nodeID))
+	assert.True(t, s.IsPoor(nodeID))
+}
+
+func TestScorerDecay(t *testing.T) {
+	s := NewScorer(Config{
+		Enabled:       true,
+		Halflife:      time.Minute,
+		PoorThreshold: -5,
+	}).(*scorer)
+	now := time.Now()
+	s.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+	s.Observe(nodeID, common.HandshakeFailure)
+	assert.Equal(t, -10., s.Score(nodeID))
+	assert.True(t, s.IsPoor(nodeID))
+
+	now = now.Add(time.Minute)
+	s.clock.Set(now)
+	assert.InDelta(t, -5., s.Score(nodeID), 0.0001)
+
+	now = now.Add(time.Minute)
+	s.clock.Set(now)
+	assert.InDelta(t, -2.5, s.Score(nodeID), 0.0001)
+	assert.False(t, s.IsPoor(nodeID))
+
+	s.Observe(nodeID, common.ValidContainer)
+	assert.InDelta(t, -1.5, s.Score(nodeID), 0.0001)
+}
+
+func TestScorerBest(t *testing.T) {
+	s := NewScorer(Config{
+		Enabled:  true,
+		Halflife: time.Minute,
+	})
+
+	_, ok := s.Best(nil)
+	assert.False(t, ok)
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeID2 := ids.GenerateTestShortID()
+	nodeIDs := []ids.ShortID{nodeID0, nodeID1, nodeID2}
+
+	best, ok := s.Best(nodeIDs)
+	assert.True(t, ok)
+	assert.Equal(t, nodeID0, best)
+
+	s.Observe(nodeID0, common.RequestTimeout)
+	s.Observe(nodeID2, common.ValidContainer)
+	best, ok = s.Best(nodeIDs)
+	assert.True(t, ok)
+	assert.Equal(t, nodeID2, best)
+}
+
+func TestScorerEvictsLeastRecentlyUsed(t *testing.T) {
+	s := NewScorer(Config{
+		Enabled:  true,
+		Halflife: time.Minute,
+	}).(*scorer)
+
+	firstNodeID := ids.GenerateTestShortID()
+	s.Observe(firstNodeID, common.HandshakeFailure)
+	for i := 0; i < maxScores; i++ {
+		s.Observe(ids.GenerateTestShortID(), common.ValidContainer)
+	}
+
+	// The first score was forgotten to make room for the others
+	assert.Equal(t, 0., s.Score(firstNodeID))
+}
+
+func TestNoScorer(t *testing.T) {
+	s := NewScorer(Config{})
+
+	nodeID := ids.GenerateTestShortID()
+	s.Observe(nodeID, common.HandshakeFailure)
+	assert.Equal(t, 0., s.Score(nodeID))
+	assert.False(t, s.IsPoor(nodeID))
+
+	best, ok := s.Best([]ids.ShortID{nodeID})
+	assert.True(t, ok)
+	assert.Equal(t, nodeID, best)
+}
@@ -60,6 +60,15 @@ func NewBandwidthThrottler(
 	registerer prometheus.Registerer,
 	config BandwidthThrottlerConfig,
 ) (BandwidthThrottler, error) {
+	return newBandwidthThrottler(log, namespace, registerer, config)
+}
+
+func newBandwidthThrottler(
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+	config BandwidthThrottlerConfig,
+) (*bandwidthThrottler, error) {
 	errs := wrappers.Errs{}
 	t := &bandwidthThrottler{
 		BandwidthThrottlerConfig: config,
@@ -144,3 +153,16 @@ func (t *bandwidthThrottler) RemoveNode(nodeID ids.ShortID) {
 	}
 	delete(t.limiters, nodeID)
 }
+
+// setConfig changes the refill rate and max burst size of the bandwidth
+// allocation of every node, including the nodes already added
+func (t *bandwidthThrottler) setConfig(config BandwidthThrottlerConfig) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.BandwidthThrottlerConfig = config
+	for _, limiter := range t.limiters {
+		limiter.SetLimit(rate.Limit(config.RefillRate))
+		limiter.SetBurst(int(config.MaxBurstSize))
+	}
+}
@@ -57,3 +57,29 @@ func TestBandwidthThrottler(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestBandwidthThrottlerSetConfig(t *testing.T) {
+	assert := assert.New(t)
+	throttler, err := newBandwidthThrottler(logging.NoLog{}, "", prometheus.NewRegistry(), BandwidthThrottlerConfig{
+		RefillRate:   8,
+		MaxBurstSize: 10,
+	})
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	throttler.AddNode(nodeID)
+
+	config := BandwidthThrottlerConfig{
+		RefillRate:   16,
+		MaxBurstSize: 20,
+	}
+	throttler.setConfig(config)
+	assert.Equal(config, throttler.BandwidthThrottlerConfig)
+
+	// Nodes added before and after the change get the new allocation
+	throttler.AddNode(ids.GenerateTestShortID())
+	for _, limiter := range throttler.limiters {
+		assert.EqualValues(16, limiter.Limit())
+		assert.Equal(20, limiter.Burst())
+	}
+}
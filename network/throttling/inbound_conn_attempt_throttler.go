@@ -0,0 +1,192 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const (
+	attemptWindow = time.Minute
+
+	// Size of the prefix that IPv4 and IPv6 addresses are grouped by when
+	// limiting connection attempts per subnet.
+	ipv4SubnetPrefixLen = 24
+	ipv6SubnetPrefixLen = 64
+)
+
+var (
+	_ InboundConnAttemptThrottler = &inboundConnAttemptThrottler{}
+	_ InboundConnAttemptThrottler = &noInboundConnAttemptThrottler{}
+)
+
+// InboundConnAttemptThrottler returns whether an inbound connection attempt
+// should be accepted, based on how many connection attempts were recently made
+// from the same IP and from the same subnet.
+// Note that InboundConnAttemptThrottler limits every connection attempt,
+// whereas InboundConnUpgradeThrottler only limits the attempts that would
+// otherwise be upgraded.
+type InboundConnAttemptThrottler interface {
+	// Allow records a connection attempt from [ip] and returns whether it
+	// should be accepted.
+	// If [ip] is a local IP, this method always returns true.
+	Allow(ip utils.IPDesc) bool
+}
+
+type InboundConnAttemptThrottlerConfig struct {
+	// Max number of inbound connection attempts accepted from a single IP per
+	// minute. If 0, connection attempts aren't limited per IP.
+	MaxAttemptsPerIPPerMinute uint32 `json:"maxAttemptsPerIPPerMinute"`
+	// Max number of inbound connection attempts accepted from a single /24
+	// IPv4 or /64 IPv6 subnet per minute. If 0, connection attempts aren't
+	// limited per subnet.
+	MaxAttemptsPerSubnetPerMinute uint32 `json:"maxAttemptsPerSubnetPerMinute"`
+}
+
+// NewInboundConnAttemptThrottler returns an InboundConnAttemptThrottler that
+// enforces the limits in [config].
+func NewInboundConnAttemptThrottler(
+	namespace string,
+	registerer prometheus.Registerer,
+	config InboundConnAttemptThrottlerConfig,
+) (InboundConnAttemptThrottler, error) {
+	if config.MaxAttemptsPerIPPerMinute == 0 && config.MaxAttemptsPerSubnetPerMinute == 0 {
+		return &noInboundConnAttemptThrottler{}, nil
+	}
+	t := &inboundConnAttemptThrottler{
+		InboundConnAttemptThrottlerConfig: config,
+		ipLimiters:                        make(map[string]*attemptLimiter),
+		subnetLimiters:                    make(map[string]*attemptLimiter),
+	}
+	t.lastPruned = t.clock.Time()
+	return t, t.metrics.initialize(namespace, registerer)
+}
+
+// noInboundConnAttemptThrottler accepts all inbound connection attempts
+type noInboundConnAttemptThrottler struct{}
+
+func (*noInboundConnAttemptThrottler) Allow(utils.IPDesc) bool { return true }
+
+// attemptLimiter limits the connection attempts from a single IP or subnet.
+type attemptLimiter struct {
+	limiter     *rate.Limiter
+	lastAttempt time.Time
+}
+
+type inboundConnAttemptThrottlerMetrics struct {
+	ipRateLimited     prometheus.Counter
+	subnetRateLimited prometheus.Counter
+}
+
+func (m *inboundConnAttemptThrottlerMetrics) initialize(namespace string, reg prometheus.Registerer) error {
+	m.ipRateLimited = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "inbound_conn_attempt_ip_rate_limited",
+		Help:      "Number of inbound connection attempts rejected because too many attempts were made from the same IP",
+	})
+	m.subnetRateLimited = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "inbound_conn_attempt_subnet_rate_limited",
+		Help:      "Number of inbound connection attempts rejected because too many attempts were made from the same subnet",
+	})
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.ipRateLimited),
+		reg.Register(m.subnetRateLimited),
+	)
+	return errs.Err
+}
+
+// inboundConnAttemptThrottler implements InboundConnAttemptThrottler
+type inboundConnAttemptThrottler struct {
+	InboundConnAttemptThrottlerConfig
+	metrics inboundConnAttemptThrottlerMetrics
+	// Useful for faking time in tests
+	clock mockable.Clock
+
+	lock sync.Mutex
+	// IP --> Limiter of connection attempts from that IP
+	ipLimiters map[string]*attemptLimiter
+	// Subnet --> Limiter of connection attempts from that subnet
+	subnetLimiters map[string]*attemptLimiter
+	// Last time limiters that are no longer limiting anything were removed
+	lastPruned time.Time
+}
+
+func (t *inboundConnAttemptThrottler) Allow(ip utils.IPDesc) bool {
+	if ip.IsPrivate() {
+		// Don't rate-limit private (local) IPs
+		return true
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	if now.Sub(t.lastPruned) >= attemptWindow {
+		prune(t.ipLimiters, now)
+		prune(t.subnetLimiters, now)
+		t.lastPruned = now
+	}
+
+	// Only use IP (not port). This mitigates DoS
+	// attacks from many nodes on one host.
+	if !allow(t.ipLimiters, ip.IP.String(), t.MaxAttemptsPerIPPerMinute, now) {
+		t.metrics.ipRateLimited.Inc()
+		return false
+	}
+	if !allow(t.subnetLimiters, subnet(ip.IP), t.MaxAttemptsPerSubnetPerMinute, now) {
+		t.metrics.subnetRateLimited.Inc()
+		return false
+	}
+	return true
+}
+
+// allow records an attempt from [key] at [now] and returns whether fewer than
+// [maxAttempts] attempts per minute have been made from [key].
+// If [maxAttempts] is 0, always returns true.
+func allow(limiters map[string]*attemptLimiter, key string, maxAttempts uint32, now time.Time) bool {
+	if maxAttempts == 0 {
+		return true
+	}
+	l, ok := limiters[key]
+	if !ok {
+		l = &attemptLimiter{
+			limiter: rate.NewLimiter(rate.Limit(float64(maxAttempts)/attemptWindow.Seconds()), int(maxAttempts)),
+		}
+		limiters[key] = l
+	}
+	l.lastAttempt = now
+	return l.limiter.AllowN(now, 1)
+}
+
+// prune removes the limiters that haven't seen an attempt within the last
+// minute. Since their bucket has fully refilled, removing them doesn't change
+// which attempts are allowed.
+func prune(limiters map[string]*attemptLimiter, now time.Time) {
+	for key, l := range limiters {
+		if now.Sub(l.lastAttempt) >= attemptWindow {
+			delete(limiters, key)
+		}
+	}
+}
+
+// subnet returns the /24 subnet of [ip] if it's an IPv4 address or its /64
+// subnet otherwise.
+func subnet(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%s/%d", ip4.Mask(net.CIDRMask(ipv4SubnetPrefixLen, net.IPv4len*8)), ipv4SubnetPrefixLen)
+	}
+	return fmt.Sprintf("%s/%d", ip.Mask(net.CIDRMask(ipv6SubnetPrefixLen, net.IPv6len*8)), ipv6SubnetPrefixLen)
+}
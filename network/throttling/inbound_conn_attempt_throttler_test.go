@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils"
+)
+
+func TestNoInboundConnAttemptThrottler(t *testing.T) {
+	assert := assert.New(t)
+
+	throttler, err := NewInboundConnAttemptThrottler("", prometheus.NewRegistry(), InboundConnAttemptThrottlerConfig{})
+	assert.NoError(err)
+	assert.IsType(&noInboundConnAttemptThrottler{}, throttler)
+
+	// throttler should allow all
+	for i := 0; i < 10; i++ {
+		assert.True(throttler.Allow(host1))
+	}
+}
+
+func TestInboundConnAttemptThrottlerPerIP(t *testing.T) {
+	assert := assert.New(t)
+
+	throttlerIntf, err := NewInboundConnAttemptThrottler(
+		"",
+		prometheus.NewRegistry(),
+		InboundConnAttemptThrottlerConfig{
+			MaxAttemptsPerIPPerMinute: 3,
+		},
+	)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*inboundConnAttemptThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	for i := 0; i < 3; i++ {
+		assert.True(throttler.Allow(host1))
+	}
+	// Fourth attempt in the same minute should be rejected
+	assert.False(throttler.Allow(host1))
+	// Limits are per IP, not per IP and port
+	assert.False(throttler.Allow(utils.IPDesc{IP: host1.IP, Port: host1.Port + 1}))
+	// Other IPs in the same subnet aren't affected
+	assert.True(throttler.Allow(host2))
+	// Local IPs are never rate-limited
+	for i := 0; i < 10; i++ {
+		assert.True(throttler.Allow(localhost))
+	}
+
+	// After 20 seconds, one more attempt is allowed
+	throttler.clock.Set(now.Add(20 * time.Second))
+	assert.True(throttler.Allow(host1))
+	assert.False(throttler.Allow(host1))
+
+	// After a minute without attempts, all attempts are allowed again
+	throttler.clock.Set(now.Add(80 * time.Second))
+	for i := 0; i < 3; i++ {
+		assert.True(throttler.Allow(host1))
+	}
+	assert.False(throttler.Allow(host1))
+}
+
+func TestInboundConnAttemptThrottlerPerSubnet(t *testing.T) {
+	assert := assert.New(t)
+
+	throttlerIntf, err := NewInboundConnAttemptThrottler(
+		"",
+		prometheus.NewRegistry(),
+		InboundConnAttemptThrottlerConfig{
+			MaxAttemptsPerIPPerMinute:     2,
+			MaxAttemptsPerSubnetPerMinute: 3,
+		},
+	)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*inboundConnAttemptThrottler)
+	throttler.clock.Set(time.Now())
+
+	assert.True(throttler.Allow(host1))
+	assert.True(throttler.Allow(host1))
+	assert.False(throttler.Allow(host1))
+	assert.True(throttler.Allow(host2))
+	// [host3] is in the same /24 as [host1] and [host2]
+	assert.False(throttler.Allow(host3))
+	// A different /24 isn't affected
+	assert.True(throttler.Allow(utils.IPDesc{IP: net.IPv4(1, 2, 4, 1), Port: 9651}))
+
+	// IPv6 addresses are grouped by /64
+	ipv6 := func(s string) utils.IPDesc { return utils.IPDesc{IP: net.ParseIP(s), Port: 9651} }
+	assert.True(throttler.Allow(ipv6("2001:db8:1:1::1")))
+	assert.True(throttler.Allow(ipv6("2001:db8:1:1::2")))
+	assert.True(throttler.Allow(ipv6("2001:db8:1:1:ffff::3")))
+	assert.False(throttler.Allow(ipv6("2001:db8:1:1::4")))
+	assert.True(throttler.Allow(ipv6("2001:db8:1:2::1")))
+
+	assert.EqualValues(1, counterValue(t, throttler.metrics.ipRateLimited))
+	assert.EqualValues(2, counterValue(t, throttler.metrics.subnetRateLimited))
+}
+
+func TestInboundConnAttemptThrottlerPrune(t *testing.T) {
+	assert := assert.New(t)
+
+	throttlerIntf, err := NewInboundConnAttemptThrottler(
+		"",
+		prometheus.NewRegistry(),
+		InboundConnAttemptThrottlerConfig{
+			MaxAttemptsPerIPPerMinute:     1,
+			MaxAttemptsPerSubnetPerMinute: 10,
+		},
+	)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*inboundConnAttemptThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	assert.True(throttler.Allow(host1))
+	assert.True(throttler.Allow(host2))
+	assert.Len(throttler.ipLimiters, 2)
+	assert.Len(throttler.subnetLimiters, 1)
+
+	throttler.clock.Set(now.Add(30 * time.Second))
+	assert.True(throttler.Allow(host3))
+	assert.Len(throttler.ipLimiters, 3)
+
+	// Limiters that haven't been used in the last minute are removed
+	throttler.clock.Set(now.Add(time.Minute + time.Second))
+	assert.True(throttler.Allow(host4))
+	assert.Len(throttler.ipLimiters, 2)
+	assert.Contains(throttler.ipLimiters, host3.IP.String())
+	assert.Contains(throttler.ipLimiters, host4.IP.String())
+	assert.Len(throttler.subnetLimiters, 1)
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	if err := counter.Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
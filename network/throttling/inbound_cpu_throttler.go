@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/metric"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+// See inbound_msg_throttler.go
+
+// CPUTracker reports how much CPU time was recently spent handling the
+// messages of each node.
+type CPUTracker interface {
+	// Utilization returns the recent portion of CPU time spent handling
+	// messages from [nodeID]
+	Utilization(nodeID ids.ShortID) float64
+}
+
+type CPUThrottlerConfig struct {
+	// Portion of CPU time split between the validators by stake weight. A
+	// validator with 10% of the stake may use 10% of [VdrAlloc] in addition to
+	// [NodeAtLargeAlloc].
+	VdrAlloc float64 `json:"vdrAlloc"`
+	// Portion of CPU time that every node may use, regardless of its stake.
+	// If this and [VdrAlloc] are 0, messages aren't throttled based on CPU
+	// time.
+	NodeAtLargeAlloc float64 `json:"nodeAtLargeAlloc"`
+	// Max amount of time to wait before checking again whether a node that
+	// exceeded its allowance may be read from.
+	MaxRecheckDelay time.Duration `json:"maxRecheckDelay"`
+}
+
+func newInboundCPUThrottler(
+	namespace string,
+	registerer prometheus.Registerer,
+	vdrs validators.Set,
+	tracker CPUTracker,
+	config CPUThrottlerConfig,
+) (*inboundCPUThrottler, error) {
+	t := &inboundCPUThrottler{
+		CPUThrottlerConfig: config,
+		vdrs:               vdrs,
+		tracker:            tracker,
+	}
+	return t, t.metrics.initialize(namespace, registerer)
+}
+
+// Rate-limits inbound messages based on the CPU time recently spent handling
+// the messages of a given node. A node that used more than its stake weighted
+// allowance isn't read from until its utilization decays below the
+// allowance.
+type inboundCPUThrottler struct {
+	CPUThrottlerConfig
+	metrics inboundCPUThrottlerMetrics
+	// Primary network validator set
+	vdrs    validators.Set
+	tracker CPUTracker
+}
+
+// Acquire returns when the CPU time recently spent handling messages from
+// [nodeID] is within the node's allowance.
+func (t *inboundCPUThrottler) Acquire(nodeID ids.ShortID) {
+	if t.VdrAlloc == 0 && t.NodeAtLargeAlloc == 0 {
+		return
+	}
+
+	startTime := time.Now()
+	defer func() {
+		t.metrics.acquireLatency.Observe(float64(time.Since(startTime)))
+	}()
+
+	if t.tracker.Utilization(nodeID) <= t.allowance(nodeID) {
+		return
+	}
+
+	t.metrics.awaitingAcquire.Inc()
+	defer t.metrics.awaitingAcquire.Dec()
+	// The utilization of [nodeID] decays while none of its messages are read
+	for {
+		time.Sleep(t.MaxRecheckDelay)
+		if t.tracker.Utilization(nodeID) <= t.allowance(nodeID) {
+			return
+		}
+	}
+}
+
+// allowance returns the portion of CPU time that [nodeID] may use
+func (t *inboundCPUThrottler) allowance(nodeID ids.ShortID) float64 {
+	allowance := t.NodeAtLargeAlloc
+	weight, ok := t.vdrs.GetWeight(nodeID)
+	if totalWeight := t.vdrs.Weight(); ok && totalWeight > 0 {
+		allowance += t.VdrAlloc * float64(weight) / float64(totalWeight)
+	}
+	return allowance
+}
+
+type inboundCPUThrottlerMetrics struct {
+	acquireLatency  metric.Averager
+	awaitingAcquire prometheus.Gauge
+}
+
+func (m *inboundCPUThrottlerMetrics) initialize(namespace string, reg prometheus.Registerer) error {
+	errs := wrappers.Errs{}
+	m.acquireLatency = metric.NewAveragerWithErrs(
+		namespace,
+		"cpu_throttler_inbound_acquire_latency",
+		"average time (in ns) to wait for a node's recent CPU utilization to be within its allowance",
+		reg,
+		&errs,
+	)
+	m.awaitingAcquire = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cpu_throttler_inbound_awaiting_acquire",
+		Help:      "Number of nodes that aren't read from because they exceeded their CPU allowance",
+	})
+	errs.Add(
+		reg.Register(m.awaitingAcquire),
+	)
+	return errs.Err
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+)
+
+type testCPUTracker struct {
+	lock         sync.Mutex
+	utilizations map[ids.ShortID]float64
+}
+
+func (t *testCPUTracker) Utilization(nodeID ids.ShortID) float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.utilizations[nodeID]
+}
+
+func (t *testCPUTracker) set(nodeID ids.ShortID, utilization float64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.utilizations[nodeID] = utilization
+}
+
+func TestInboundCPUThrottler(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewSet()
+	vdr1, vdr2 := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	nonVdr := ids.GenerateTestShortID()
+	assert.NoError(vdrs.AddWeight(vdr1, 3))
+	assert.NoError(vdrs.AddWeight(vdr2, 1))
+
+	tracker := &testCPUTracker{utilizations: make(map[ids.ShortID]float64)}
+	throttler, err := newInboundCPUThrottler(
+		"",
+		prometheus.NewRegistry(),
+		vdrs,
+		tracker,
+		CPUThrottlerConfig{
+			VdrAlloc:         1,
+			NodeAtLargeAlloc: 0.1,
+			MaxRecheckDelay:  time.Millisecond,
+		},
+	)
+	assert.NoError(err)
+
+	// The allowance is weighted by stake
+	assert.InDelta(0.85, throttler.allowance(vdr1), 1e-9)
+	assert.InDelta(0.35, throttler.allowance(vdr2), 1e-9)
+	assert.InDelta(0.1, throttler.allowance(nonVdr), 1e-9)
+
+	// Nodes within their allowance aren't delayed
+	tracker.set(vdr1, 0.8)
+	throttler.Acquire(vdr1)
+
+	// Nodes over their allowance are delayed until their usage decays
+	tracker.set(nonVdr, 0.2)
+	done := make(chan struct{})
+	go func() {
+		throttler.Acquire(nonVdr)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("should block on acquiring")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	tracker.set(nonVdr, 0.05)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("should have acquired")
+	}
+}
+
+func TestInboundCPUThrottlerDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	tracker := &testCPUTracker{utilizations: map[ids.ShortID]float64{nodeID: 1}}
+	throttler, err := newInboundCPUThrottler(
+		"",
+		prometheus.NewRegistry(),
+		validators.NewSet(),
+		tracker,
+		CPUThrottlerConfig{},
+	)
+	assert.NoError(err)
+
+	// Without allocations, nodes are never delayed
+	throttler.Acquire(nodeID)
+}
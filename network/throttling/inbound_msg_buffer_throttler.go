@@ -87,6 +87,28 @@ func (t *inboundMsgBufferThrottler) Acquire(nodeID ids.ShortID) {
 
 // Release marks that we've finished processing a message from [nodeID]
 // and can release the space it took on the inbound message buffer.
+// setMaxProcessingMsgsPerNode changes the max number of messages processing
+// from a given node. If it's raised, the messages that were waiting for space
+// on the buffer are given the new space.
+func (t *inboundMsgBufferThrottler) setMaxProcessingMsgsPerNode(maxProcessingMsgsPerNode uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.maxProcessingMsgsPerNode = maxProcessingMsgsPerNode
+	for nodeID, waiting := range t.awaitingAcquire {
+		for len(waiting) > 0 && t.nodeToNumProcessingMsgs[nodeID] < t.maxProcessingMsgsPerNode {
+			t.nodeToNumProcessingMsgs[nodeID]++
+			close(waiting[0])
+			waiting = waiting[1:]
+		}
+		if len(waiting) == 0 {
+			delete(t.awaitingAcquire, nodeID)
+		} else {
+			t.awaitingAcquire[nodeID] = waiting
+		}
+	}
+}
+
 func (t *inboundMsgBufferThrottler) Release(nodeID ids.ShortID) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -90,3 +90,31 @@ func TestMsgBufferThrottler(t *testing.T) {
 	throttler.Release(nodeID1)
 	assert.Len(throttler.nodeToNumProcessingMsgs, 0)
 }
+
+// Test that raising the limit of inboundMsgBufferThrottler unblocks the
+// messages that were waiting
+func TestMsgBufferThrottlerSetMax(t *testing.T) {
+	assert := assert.New(t)
+	throttler, err := newInboundMsgBufferThrottler("", prometheus.NewRegistry(), 1)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	throttler.Acquire(nodeID)
+
+	done := make(chan struct{})
+	go func() {
+		throttler.Acquire(nodeID)
+		throttler.Acquire(nodeID)
+		done <- struct{}{}
+	}()
+	select {
+	case <-done:
+		t.Fatal("should block on acquiring")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	throttler.setMaxProcessingMsgsPerNode(3)
+	<-done
+	assert.EqualValues(3, throttler.nodeToNumProcessingMsgs[nodeID])
+	assert.Empty(throttler.awaitingAcquire)
+}
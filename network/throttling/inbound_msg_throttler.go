@@ -25,11 +25,18 @@ type InboundMsgThrottler interface {
 	// Mark that we're done processing a message of size [msgSize]
 	// from [nodeID].
 	Release(msgSize uint64, nodeID ids.ShortID)
+
+	// SetLimits changes the max number of messages processing from a given
+	// node and the bandwidth allocation of each node to the ones in [config].
+	// The other limits of [config] can't be changed once the throttler is
+	// created.
+	SetLimits(config InboundMsgThrottlerConfig)
 }
 
 type InboundMsgThrottlerConfig struct {
 	MsgByteThrottlerConfig
 	BandwidthThrottlerConfig
+	CPUThrottlerConfig       `json:"cpuThrottlerConfig"`
 	MaxProcessingMsgsPerNode uint64 `json:"maxProcessingMsgsPerNode"`
 }
 
@@ -39,6 +46,7 @@ func NewInboundMsgThrottler(
 	namespace string,
 	registerer prometheus.Registerer,
 	vdrs validators.Set,
+	cpuTracker CPUTracker,
 	config InboundMsgThrottlerConfig,
 ) (InboundMsgThrottler, error) {
 	byteThrottler, err := newInboundMsgByteThrottler(
@@ -59,7 +67,7 @@ func NewInboundMsgThrottler(
 	if err != nil {
 		return nil, err
 	}
-	bandwidthThrottler, err := NewBandwidthThrottler(
+	bandwidthThrottler, err := newBandwidthThrottler(
 		log,
 		namespace,
 		registerer,
@@ -68,7 +76,18 @@ func NewInboundMsgThrottler(
 	if err != nil {
 		return nil, err
 	}
+	cpuThrottler, err := newInboundCPUThrottler(
+		namespace,
+		registerer,
+		vdrs,
+		cpuTracker,
+		config.CPUThrottlerConfig,
+	)
+	if err != nil {
+		return nil, err
+	}
 	return &inboundMsgThrottler{
+		cpuThrottler:       cpuThrottler,
 		byteThrottler:      byteThrottler,
 		bufferThrottler:    bufferThrottler,
 		bandwidthThrottler: bandwidthThrottler,
@@ -78,21 +97,27 @@ func NewInboundMsgThrottler(
 // A sybil-safe inbound message throttler.
 // Rate-limits reading of inbound messages to prevent peers from
 // consuming excess resources.
-// The three resources considered are:
+// The four resources considered are:
 // 1. An inbound message buffer, where each message that we're currently
 //    processing takes up 1 unit of space on the buffer.
 // 2. An inbound message byte buffer, where a message of length n
 //    that we're currently processing takes up n units of space on the buffer.
 // 3. Bandwidth. The bandwidth rate-limiting is implemented using a token bucket,
 //    where each token is 1 byte. See BandwidthThrottler.
+// 4. CPU time. A node that recently used more than its stake weighted share of
+//    the CPU time spent handling messages isn't read from until its usage
+//    decays.
 // A call to Acquire([msgSize], [nodeID]) blocks until we've secured
-// enough of both these resources to read a message of size [msgSize] from [nodeID].
+// enough of all these resources to read a message of size [msgSize] from [nodeID].
 type inboundMsgThrottler struct {
+	// Rate-limits based on the CPU time recently spent handling
+	// messages from a given node.
+	cpuThrottler *inboundCPUThrottler
 	// Rate-limits based on number of messages from a given
 	// node that we're currently processing.
 	bufferThrottler *inboundMsgBufferThrottler
 	// Rate-limits based on recent bandwidth usage
-	bandwidthThrottler BandwidthThrottler
+	bandwidthThrottler *bandwidthThrottler
 	// Rate-limits based on size of all messages from a given
 	// node that we're currently processing.
 	byteThrottler *inboundMsgByteThrottler
@@ -102,6 +127,8 @@ type inboundMsgThrottler struct {
 // Release([msgSize], [nodeID]) must be called (!) when done with the message
 // or when we give up trying to read the message, if applicable.
 func (t *inboundMsgThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) {
+	// Wait for the node's CPU utilization to be within its allowance
+	t.cpuThrottler.Acquire(nodeID)
 	// Acquire space on the inbound message buffer
 	t.bufferThrottler.Acquire(nodeID)
 	// Acquire bandwidth
@@ -119,6 +146,12 @@ func (t *inboundMsgThrottler) Release(msgSize uint64, nodeID ids.ShortID) {
 	t.byteThrottler.Release(msgSize, nodeID)
 }
 
+// See InboundMsgThrottler.
+func (t *inboundMsgThrottler) SetLimits(config InboundMsgThrottlerConfig) {
+	t.bufferThrottler.setMaxProcessingMsgsPerNode(config.MaxProcessingMsgsPerNode)
+	t.bandwidthThrottler.setConfig(config.BandwidthThrottlerConfig)
+}
+
 // See BandwidthThrottler.
 func (t *inboundMsgThrottler) AddNode(nodeID ids.ShortID) {
 	t.bandwidthThrottler.AddNode(nodeID)
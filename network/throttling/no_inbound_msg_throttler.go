@@ -23,3 +23,5 @@ func (*noInboundMsgThrottler) Release(uint64, ids.ShortID) {}
 func (*noInboundMsgThrottler) AddNode(ids.ShortID) {}
 
 func (*noInboundMsgThrottler) RemoveNode(ids.ShortID) {}
+
+func (*noInboundMsgThrottler) SetLimits(InboundMsgThrottlerConfig) {}
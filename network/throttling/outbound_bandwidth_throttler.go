@@ -0,0 +1,175 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/metric"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+var (
+	_ OutboundBandwidthThrottler = &outboundBandwidthThrottler{}
+	_ OutboundBandwidthThrottler = &noOutboundBandwidthThrottler{}
+)
+
+// OutboundBandwidthThrottler limits the bandwidth used to send messages to
+// each peer, and to all peers together, so that serving bulk data to some
+// peers can't use up the bandwidth needed by the others.
+// Messages aren't dropped. Instead, the send loop of each peer waits until the
+// bytes of its next message are available.
+type OutboundBandwidthThrottler interface {
+	// Acquire consumes [msgSize] bytes from the budget of [nodeID] and from
+	// the budget shared by all peers. Returns how long the message must wait
+	// before being sent so that neither budget is exceeded. Messages that are
+	// exempt from throttling may be sent right away, in which case the
+	// messages sent after them wait longer.
+	// AddNode([nodeID]) must have been called since the last time
+	// RemoveNode([nodeID]) was called, if any.
+	// It's safe for multiple goroutines to concurrently call Acquire.
+	Acquire(msgSize uint64, nodeID ids.ShortID) time.Duration
+
+	// AddNode gives [nodeID] a full budget.
+	// Must be called before Acquire(..., [nodeID]) is called.
+	// It's safe for multiple goroutines to concurrently call AddNode.
+	AddNode(nodeID ids.ShortID)
+
+	// RemoveNode removes the budget of [nodeID].
+	// Must be called when we stop sending messages to [nodeID].
+	// It's safe for multiple goroutines to concurrently call RemoveNode.
+	RemoveNode(nodeID ids.ShortID)
+}
+
+type OutboundBandwidthThrottlerConfig struct {
+	// Budget of each peer. If [Peer.RefillRate] is 0, peers aren't throttled
+	// individually.
+	Peer BandwidthThrottlerConfig `json:"peer"`
+	// Budget shared by all peers. If [Global.RefillRate] is 0, the total
+	// bandwidth isn't throttled.
+	Global BandwidthThrottlerConfig `json:"global"`
+}
+
+// NewOutboundBandwidthThrottler returns an OutboundBandwidthThrottler that
+// enforces the budgets in [config].
+func NewOutboundBandwidthThrottler(
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+	config OutboundBandwidthThrottlerConfig,
+) (OutboundBandwidthThrottler, error) {
+	if config.Peer.RefillRate == 0 && config.Global.RefillRate == 0 {
+		return &noOutboundBandwidthThrottler{}, nil
+	}
+
+	errs := wrappers.Errs{}
+	t := &outboundBandwidthThrottler{
+		OutboundBandwidthThrottlerConfig: config,
+		log:                              log,
+		limiters:                         make(map[ids.ShortID]*rate.Limiter),
+		delayedMsgs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bandwidth_throttler_outbound_delayed_msgs",
+			Help:      "Number of outbound messages that waited for bandwidth from the outbound bandwidth throttler",
+		}),
+		delay: metric.NewAveragerWithErrs(
+			namespace,
+			"bandwidth_throttler_outbound_delay",
+			"average time (in ns) that delayed outbound messages waited for bandwidth from the outbound bandwidth throttler",
+			registerer,
+			&errs,
+		),
+	}
+	if config.Global.RefillRate > 0 {
+		t.globalLimiter = rate.NewLimiter(rate.Limit(config.Global.RefillRate), int(config.Global.MaxBurstSize))
+	}
+	errs.Add(registerer.Register(t.delayedMsgs))
+	return t, errs.Err
+}
+
+type outboundBandwidthThrottler struct {
+	OutboundBandwidthThrottlerConfig
+	log   logging.Logger
+	clock mockable.Clock
+
+	lock sync.Mutex
+	// Node ID --> token bucket based rate limiter where each token is a byte
+	// of bandwidth. Nil if peers aren't throttled individually.
+	limiters map[ids.ShortID]*rate.Limiter
+	// Nil if the total bandwidth isn't throttled
+	globalLimiter *rate.Limiter
+
+	delayedMsgs prometheus.Counter
+	delay       metric.Averager
+}
+
+func (t *outboundBandwidthThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) time.Duration {
+	t.lock.Lock()
+	limiter, ok := t.limiters[nodeID]
+	t.lock.Unlock()
+	if !ok {
+		// This should never happen. If it is, the caller is misusing this struct.
+		t.log.Debug("tried to acquire %d bytes for %s but that node isn't registered", msgSize, nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+
+	now := t.clock.Time()
+	delay := time.Duration(0)
+	for _, limiter := range [2]*rate.Limiter{limiter, t.globalLimiter} {
+		if limiter == nil {
+			continue
+		}
+		// Messages larger than the max burst size can never be sent within
+		// the budget, so they aren't throttled
+		if reservation := limiter.ReserveN(now, int(msgSize)); reservation.OK() {
+			if limiterDelay := reservation.DelayFrom(now); limiterDelay > delay {
+				delay = limiterDelay
+			}
+		}
+	}
+	if delay > 0 {
+		t.delayedMsgs.Inc()
+		t.delay.Observe(float64(delay))
+	}
+	return delay
+}
+
+func (t *outboundBandwidthThrottler) AddNode(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.limiters[nodeID]; ok {
+		t.log.Debug("tried to add %s but it's already registered", nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	var limiter *rate.Limiter
+	if t.Peer.RefillRate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(t.Peer.RefillRate), int(t.Peer.MaxBurstSize))
+	}
+	t.limiters[nodeID] = limiter
+}
+
+func (t *outboundBandwidthThrottler) RemoveNode(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.limiters[nodeID]; !ok {
+		t.log.Debug("tried to remove %s but it isn't registered", nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	delete(t.limiters, nodeID)
+}
+
+type noOutboundBandwidthThrottler struct{}
+
+func (*noOutboundBandwidthThrottler) Acquire(uint64, ids.ShortID) time.Duration { return 0 }
+
+func (*noOutboundBandwidthThrottler) AddNode(ids.ShortID) {}
+
+func (*noOutboundBandwidthThrottler) RemoveNode(ids.ShortID) {}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+func TestNoOutboundBandwidthThrottler(t *testing.T) {
+	assert := assert.New(t)
+
+	throttler, err := NewOutboundBandwidthThrottler(logging.NoLog{}, "", prometheus.NewRegistry(), OutboundBandwidthThrottlerConfig{})
+	assert.NoError(err)
+	assert.IsType(&noOutboundBandwidthThrottler{}, throttler)
+	assert.Zero(throttler.Acquire(1<<30, ids.GenerateTestShortID()))
+}
+
+func TestOutboundBandwidthThrottler(t *testing.T) {
+	assert := assert.New(t)
+
+	throttlerIntf, err := NewOutboundBandwidthThrottler(
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+		OutboundBandwidthThrottlerConfig{
+			Peer: BandwidthThrottlerConfig{
+				RefillRate:   100,
+				MaxBurstSize: 1000,
+			},
+			Global: BandwidthThrottlerConfig{
+				RefillRate:   200,
+				MaxBurstSize: 1500,
+			},
+		},
+	)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundBandwidthThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	node1 := ids.GenerateTestShortID()
+	node2 := ids.GenerateTestShortID()
+	throttler.AddNode(node1)
+	throttler.AddNode(node2)
+
+	// Messages within the budgets are sent right away
+	assert.Zero(throttler.Acquire(1000, node1))
+
+	// [node1] used up its own budget
+	assert.Equal(time.Second, throttler.Acquire(100, node1))
+
+	// [node2] has its own budget, but the global budget is shared with
+	// [node1]
+	assert.Zero(throttler.Acquire(400, node2))
+	assert.Equal(time.Second, throttler.Acquire(200, node2))
+
+	// Budgets refill over time
+	throttler.clock.Set(now.Add(10 * time.Second))
+	assert.Zero(throttler.Acquire(100, node2))
+
+	assert.EqualValues(2, counterValue(t, throttler.delayedMsgs))
+
+	throttler.RemoveNode(node1)
+	_, ok := throttler.limiters[node1]
+	assert.False(ok)
+}
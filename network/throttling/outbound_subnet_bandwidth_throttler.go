@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+var (
+	_ OutboundSubnetBandwidthThrottler = &outboundSubnetBandwidthThrottler{}
+	_ OutboundSubnetBandwidthThrottler = &noOutboundSubnetBandwidthThrottler{}
+)
+
+// OutboundSubnetBandwidthThrottler limits the bandwidth used to send messages
+// on behalf of each subnet, so that the chains of one subnet can't use up the
+// bandwidth needed by the chains of other subnets.
+// Messages sent on behalf of the primary network are never throttled.
+// Like the other outbound throttlers, it never blocks; messages that don't fit
+// in the budget should be dropped.
+type OutboundSubnetBandwidthThrottler interface {
+	// Acquire returns true and consumes [msgSize] bytes from the budget of
+	// [subnetID] if there are enough bytes in it. Otherwise returns false.
+	// It's safe for multiple goroutines to concurrently call Acquire.
+	Acquire(subnetID ids.ID, msgSize uint64) bool
+}
+
+type OutboundSubnetBandwidthThrottlerConfig struct {
+	// Budget of each subnet other than the primary network, unless it's
+	// overridden in [Subnets]. If [RefillRate] is 0, subnets aren't throttled.
+	BandwidthThrottlerConfig
+	// Subnet ID --> Budget of that subnet
+	Subnets map[ids.ID]BandwidthThrottlerConfig `json:"subnets"`
+}
+
+// NewOutboundSubnetBandwidthThrottler returns an OutboundSubnetBandwidthThrottler
+// that enforces the budgets in [config].
+func NewOutboundSubnetBandwidthThrottler(
+	namespace string,
+	registerer prometheus.Registerer,
+	config OutboundSubnetBandwidthThrottlerConfig,
+) (OutboundSubnetBandwidthThrottler, error) {
+	throttled := config.RefillRate > 0
+	for _, subnetConfig := range config.Subnets {
+		throttled = throttled || subnetConfig.RefillRate > 0
+	}
+	if !throttled {
+		return &noOutboundSubnetBandwidthThrottler{}, nil
+	}
+
+	t := &outboundSubnetBandwidthThrottler{
+		OutboundSubnetBandwidthThrottlerConfig: config,
+		limiters:                               make(map[ids.ID]*rate.Limiter),
+		throttledMsgs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "subnet_bandwidth_throttler_outbound_throttled_msgs",
+				Help:      "Number of outbound messages dropped because their subnet exceeded its bandwidth budget",
+			},
+			[]string{"subnetID"},
+		),
+		throttledBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "subnet_bandwidth_throttler_outbound_throttled_bytes",
+				Help:      "Number of outbound bytes dropped because their subnet exceeded its bandwidth budget",
+			},
+			[]string{"subnetID"},
+		),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(t.throttledMsgs),
+		registerer.Register(t.throttledBytes),
+	)
+	return t, errs.Err
+}
+
+// noOutboundSubnetBandwidthThrottler never throttles
+type noOutboundSubnetBandwidthThrottler struct{}
+
+func (*noOutboundSubnetBandwidthThrottler) Acquire(ids.ID, uint64) bool { return true }
+
+type outboundSubnetBandwidthThrottler struct {
+	OutboundSubnetBandwidthThrottlerConfig
+	// Useful for faking time in tests
+	clock mockable.Clock
+
+	lock sync.Mutex
+	// Subnet ID --> Rate limiter of that subnet.
+	// A nil limiter means the subnet isn't throttled.
+	limiters map[ids.ID]*rate.Limiter
+
+	throttledMsgs  *prometheus.CounterVec
+	throttledBytes *prometheus.CounterVec
+}
+
+func (t *outboundSubnetBandwidthThrottler) Acquire(subnetID ids.ID, msgSize uint64) bool {
+	if subnetID == constants.PrimaryNetworkID {
+		return true
+	}
+
+	t.lock.Lock()
+	limiter, ok := t.limiters[subnetID]
+	if !ok {
+		config, ok := t.Subnets[subnetID]
+		if !ok {
+			config = t.BandwidthThrottlerConfig
+		}
+		if config.RefillRate > 0 {
+			limiter = rate.NewLimiter(rate.Limit(config.RefillRate), int(config.MaxBurstSize))
+		}
+		t.limiters[subnetID] = limiter
+	}
+	t.lock.Unlock()
+
+	if limiter == nil || limiter.AllowN(t.clock.Time(), int(msgSize)) {
+		return true
+	}
+
+	subnetIDStr := subnetID.String()
+	t.throttledMsgs.WithLabelValues(subnetIDStr).Inc()
+	t.throttledBytes.WithLabelValues(subnetIDStr).Add(float64(msgSize))
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+)
+
+func TestNoOutboundSubnetBandwidthThrottler(t *testing.T) {
+	assert := assert.New(t)
+
+	throttler, err := NewOutboundSubnetBandwidthThrottler("", prometheus.NewRegistry(), OutboundSubnetBandwidthThrottlerConfig{})
+	assert.NoError(err)
+	assert.IsType(&noOutboundSubnetBandwidthThrottler{}, throttler)
+	assert.True(throttler.Acquire(ids.GenerateTestID(), 1<<30))
+}
+
+func TestOutboundSubnetBandwidthThrottler(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		subnet1 = ids.GenerateTestID()
+		subnet2 = ids.GenerateTestID()
+		subnet3 = ids.GenerateTestID()
+	)
+	throttlerIntf, err := NewOutboundSubnetBandwidthThrottler(
+		"",
+		prometheus.NewRegistry(),
+		OutboundSubnetBandwidthThrottlerConfig{
+			BandwidthThrottlerConfig: BandwidthThrottlerConfig{
+				RefillRate:   100,
+				MaxBurstSize: 1000,
+			},
+			Subnets: map[ids.ID]BandwidthThrottlerConfig{
+				subnet2: {
+					RefillRate:   1000,
+					MaxBurstSize: 2000,
+				},
+				subnet3: {},
+			},
+		},
+	)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundSubnetBandwidthThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	// The primary network is never throttled
+	for i := 0; i < 10; i++ {
+		assert.True(throttler.Acquire(constants.PrimaryNetworkID, 1000))
+	}
+
+	// [subnet1] uses the default budget
+	assert.True(throttler.Acquire(subnet1, 600))
+	assert.True(throttler.Acquire(subnet1, 400))
+	assert.False(throttler.Acquire(subnet1, 1))
+
+	// [subnet2] uses its own budget, independent of [subnet1]'s
+	assert.True(throttler.Acquire(subnet2, 2000))
+	assert.False(throttler.Acquire(subnet2, 1))
+
+	// [subnet3] isn't throttled
+	for i := 0; i < 10; i++ {
+		assert.True(throttler.Acquire(subnet3, 1000))
+	}
+
+	// Budgets refill over time
+	throttler.clock.Set(now.Add(time.Second))
+	assert.True(throttler.Acquire(subnet1, 100))
+	assert.False(throttler.Acquire(subnet1, 100))
+	assert.True(throttler.Acquire(subnet2, 1000))
+	assert.False(throttler.Acquire(subnet2, 100))
+
+	assert.EqualValues(2, counterValue(t, throttler.throttledMsgs.WithLabelValues(subnet1.String())))
+	assert.EqualValues(101, counterValue(t, throttler.throttledBytes.WithLabelValues(subnet1.String())))
+	assert.EqualValues(2, counterValue(t, throttler.throttledMsgs.WithLabelValues(subnet2.String())))
+}
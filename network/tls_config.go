@@ -3,7 +3,17 @@
 
 package network
 
-import "crypto/tls"
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/network/allowlist"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+)
+
+var errNotAllowed = errors.New("peer isn't on the allowlist")
 
 func TLSConfig(cert tls.Certificate) *tls.Config {
 	// #nosec G402
@@ -19,3 +29,23 @@ func TLSConfig(cert tls.Certificate) *tls.Config {
 		InsecureSkipVerify: true,
 	}
 }
+
+// AllowlistTLSConfig returns a copy of [config] whose handshakes fail, on both
+// inbound and outbound connections, if the peer isn't allowed by [allowlist]
+func AllowlistTLSConfig(config *tls.Config, allowlist allowlist.Allowlist) *tls.Config {
+	config = config.Clone()
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errNoCert
+		}
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return err
+		}
+		if nodeID := certToID(cert); !allowlist.IsAllowed(nodeID) {
+			return fmt.Errorf("%w: %s", errNotAllowed, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		return nil
+	}
+	return config
+}
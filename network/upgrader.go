@@ -10,6 +10,7 @@ import (
 	"net"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/network/quic"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
 )
 
@@ -35,6 +36,9 @@ func NewTLSServerUpgrader(config *tls.Config) Upgrader {
 }
 
 func (t tlsServerUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+	if conn, ok := conn.(*quic.Conn); ok {
+		return quicConnToIDAndCert(conn)
+	}
 	return connToIDAndCert(tls.Server(conn, t.config))
 }
 
@@ -49,6 +53,9 @@ func NewTLSClientUpgrader(config *tls.Config) Upgrader {
 }
 
 func (t tlsClientUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+	if conn, ok := conn.(*quic.Conn); ok {
+		return quicConnToIDAndCert(conn)
+	}
 	return connToIDAndCert(tls.Client(conn, t.config))
 }
 
@@ -65,6 +72,18 @@ func connToIDAndCert(conn *tls.Conn) (ids.ShortID, net.Conn, *x509.Certificate,
 	return certToID(peerCert), conn, peerCert, nil
 }
 
+// quicConnToIDAndCert returns the peer's identity from a QUIC connection. The
+// TLS handshake is performed as part of establishing the QUIC session, so no
+// further upgrade is required.
+func quicConnToIDAndCert(conn *quic.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+	peerCerts := conn.PeerCertificates()
+	if len(peerCerts) == 0 {
+		return ids.ShortID{}, nil, nil, errNoCert
+	}
+	peerCert := peerCerts[0]
+	return certToID(peerCert), conn, peerCert, nil
+}
+
 func certToID(cert *x509.Certificate) ids.ShortID {
 	return ids.ShortID(
 		hashing.ComputeHash160Array(
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sort"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// UptimeView aggregates the uptime of this node as observed by the primary
+// network validators it's connected to. This node observes itself to be up
+// 100% of the time.
+type UptimeView struct {
+	// Stake weight of all the primary network validators
+	TotalStake json.Uint64 `json:"totalStake"`
+	// Stake weight of the primary network validators that observed this
+	// node's uptime
+	ObservedStake json.Uint64 `json:"observedStake"`
+	// Percentage of uptime a validator must observe for this node to be
+	// rewarded
+	UptimeRequirement json.Float64 `json:"uptimeRequirement"`
+	// Percentage of [TotalStake] that observed an uptime that meets
+	// [UptimeRequirement]
+	RewardingStakePercentage json.Float64 `json:"rewardingStakePercentage"`
+	// The following are weighted by the stake of the validators that observed
+	// this node's uptime
+	WeightedAveragePercentage json.Float64 `json:"weightedAveragePercentage"`
+	MinPercentage             json.Float64 `json:"minPercentage"`
+	MedianPercentage          json.Float64 `json:"medianPercentage"`
+	MaxPercentage             json.Float64 `json:"maxPercentage"`
+	// Sorted by observed uptime, lowest first
+	Observations []UptimeObservation `json:"observations"`
+}
+
+// UptimeObservation is the uptime of this node as observed by a validator.
+type UptimeObservation struct {
+	NodeID     string       `json:"nodeID"`
+	Stake      json.Uint64  `json:"stake"`
+	Percentage json.Float64 `json:"percentage"`
+}
+
+type uptimeViewBuilder struct {
+	vdrs              validators.Set
+	uptimeRequirement float64
+	observations      []UptimeObservation
+	stake             uint64
+}
+
+func newUptimeViewBuilder(vdrs validators.Set, uptimeRequirement float64) *uptimeViewBuilder {
+	return &uptimeViewBuilder{
+		vdrs:              vdrs,
+		uptimeRequirement: uptimeRequirement,
+	}
+}
+
+// add counts the [percentage] of uptime that [nodeID] observed. Nodes that
+// aren't validators are ignored.
+func (b *uptimeViewBuilder) add(nodeID ids.ShortID, percentage uint8) {
+	weight, ok := b.vdrs.GetWeight(nodeID)
+	if !ok {
+		return
+	}
+	b.observations = append(b.observations, UptimeObservation{
+		NodeID:     nodeID.PrefixedString(constants.NodeIDPrefix),
+		Stake:      json.Uint64(weight),
+		Percentage: json.Float64(percentage),
+	})
+	b.stake += weight
+}
+
+func (b *uptimeViewBuilder) build() UptimeView {
+	totalStake := b.vdrs.Weight()
+	view := UptimeView{
+		TotalStake:        json.Uint64(totalStake),
+		ObservedStake:     json.Uint64(b.stake),
+		UptimeRequirement: json.Float64(100 * b.uptimeRequirement),
+		Observations:      b.observations,
+	}
+	if view.Observations == nil {
+		view.Observations = []UptimeObservation{}
+	}
+	sort.SliceStable(view.Observations, func(i, j int) bool {
+		return view.Observations[i].Percentage < view.Observations[j].Percentage
+	})
+	if b.stake == 0 {
+		return view
+	}
+
+	var (
+		rewardingStake  uint64
+		weightedPercent float64
+		cumulativeStake uint64
+		foundMedian     bool
+	)
+	for _, observation := range view.Observations {
+		if float64(observation.Percentage)/100 >= b.uptimeRequirement {
+			rewardingStake += uint64(observation.Stake)
+		}
+		weightedPercent += float64(observation.Percentage) * float64(observation.Stake)
+
+		cumulativeStake += uint64(observation.Stake)
+		if !foundMedian && 2*cumulativeStake >= b.stake {
+			view.MedianPercentage = observation.Percentage
+			foundMedian = true
+		}
+	}
+	view.MinPercentage = view.Observations[0].Percentage
+	view.MaxPercentage = view.Observations[len(view.Observations)-1].Percentage
+	view.WeightedAveragePercentage = json.Float64(weightedPercent / float64(b.stake))
+	if totalStake > 0 {
+		view.RewardingStakePercentage = json.Float64(100 * float64(rewardingStake) / float64(totalStake))
+	}
+	return view
+}
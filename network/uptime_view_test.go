@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+func TestUptimeView(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	vdr2 := ids.GenerateTestShortID()
+	vdr3 := ids.GenerateTestShortID()
+	nonVdr := ids.GenerateTestShortID()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 40))
+	assert.NoError(t, vdrs.AddWeight(vdr1, 30))
+	assert.NoError(t, vdrs.AddWeight(vdr2, 20))
+	assert.NoError(t, vdrs.AddWeight(vdr3, 10))
+
+	builder := newUptimeViewBuilder(vdrs, .8)
+	builder.add(vdr0, 100)
+	builder.add(vdr1, 70)
+	builder.add(vdr2, 90)
+	builder.add(nonVdr, 0)
+	view := builder.build()
+
+	assert.Equal(t, json.Uint64(100), view.TotalStake)
+	assert.Equal(t, json.Uint64(90), view.ObservedStake)
+	assert.Equal(t, json.Float64(80), view.UptimeRequirement)
+	assert.Equal(t, json.Float64(60), view.RewardingStakePercentage)
+	assert.Equal(t, json.Float64(7900./90), view.WeightedAveragePercentage)
+	assert.Equal(t, json.Float64(70), view.MinPercentage)
+	assert.Equal(t, json.Float64(90), view.MedianPercentage)
+	assert.Equal(t, json.Float64(100), view.MaxPercentage)
+	assert.Len(t, view.Observations, 3)
+	assert.Equal(t, json.Float64(70), view.Observations[0].Percentage)
+	assert.Equal(t, json.Uint64(30), view.Observations[0].Stake)
+}
+
+func TestUptimeViewNoObservations(t *testing.T) {
+	builder := newUptimeViewBuilder(validators.NewSet(), .8)
+	view := builder.build()
+
+	assert.Zero(t, view.ObservedStake)
+	assert.Zero(t, view.MedianPercentage)
+	assert.Empty(t, view.Observations)
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sort"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// VersionCensus aggregates this node and its connected peers by version.
+type VersionCensus struct {
+	// Stake weight of all the primary network validators
+	TotalStake json.Uint64 `json:"totalStake"`
+	// Stake weight of the primary network validators included in the census
+	ConnectedStake json.Uint64 `json:"connectedStake"`
+	// Sorted by stake weight, largest first
+	Versions []VersionCount `json:"versions"`
+}
+
+// VersionCount describes the nodes running a version.
+type VersionCount struct {
+	Version  string      `json:"version"`
+	NumPeers json.Uint64 `json:"numPeers"`
+	// Stake weight of the primary network validators running this version
+	Stake json.Uint64 `json:"stake"`
+	// Percentage of [VersionCensus.TotalStake] running this version
+	StakePercentage json.Float64 `json:"stakePercentage"`
+	// Subnet ID --> number of nodes running this version that track the
+	// subnet
+	Subnets map[string]json.Uint64 `json:"subnets"`
+}
+
+type versionCensusBuilder struct {
+	vdrs     validators.Set
+	versions map[string]*VersionCount
+	stake    uint64
+}
+
+func newVersionCensusBuilder(vdrs validators.Set) *versionCensusBuilder {
+	return &versionCensusBuilder{
+		vdrs:     vdrs,
+		versions: make(map[string]*VersionCount),
+	}
+}
+
+// add counts [nodeID], which runs [version] and tracks [subnets]
+func (b *versionCensusBuilder) add(version string, nodeID ids.ShortID, subnets ids.Set) {
+	count, ok := b.versions[version]
+	if !ok {
+		count = &VersionCount{
+			Version: version,
+			Subnets: make(map[string]json.Uint64),
+		}
+		b.versions[version] = count
+	}
+	count.NumPeers++
+	for subnetID := range subnets {
+		count.Subnets[subnetID.String()]++
+	}
+
+	weight, ok := b.vdrs.GetWeight(nodeID)
+	if !ok {
+		return
+	}
+	count.Stake += json.Uint64(weight)
+	b.stake += weight
+}
+
+func (b *versionCensusBuilder) build() VersionCensus {
+	totalStake := b.vdrs.Weight()
+	census := VersionCensus{
+		TotalStake:     json.Uint64(totalStake),
+		ConnectedStake: json.Uint64(b.stake),
+		Versions:       make([]VersionCount, 0, len(b.versions)),
+	}
+	for _, count := range b.versions {
+		if totalStake > 0 {
+			count.StakePercentage = json.Float64(100 * float64(count.Stake) / float64(totalStake))
+		}
+		census.Versions = append(census.Versions, *count)
+	}
+	sort.Slice(census.Versions, func(i, j int) bool {
+		if census.Versions[i].Stake != census.Versions[j].Stake {
+			return census.Versions[i].Stake > census.Versions[j].Stake
+		}
+		return census.Versions[i].Version < census.Versions[j].Version
+	})
+	return census
+}
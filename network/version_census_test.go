@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+func TestVersionCensus(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	vdr2 := ids.GenerateTestShortID()
+	nonVdr := ids.GenerateTestShortID()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 50))
+	assert.NoError(t, vdrs.AddWeight(vdr1, 30))
+	assert.NoError(t, vdrs.AddWeight(vdr2, 20))
+
+	subnetID := ids.GenerateTestID()
+	primary := ids.Set{}
+	primary.Add(constants.PrimaryNetworkID)
+	primaryAndSubnet := ids.Set{}
+	primaryAndSubnet.Add(constants.PrimaryNetworkID, subnetID)
+
+	builder := newVersionCensusBuilder(vdrs)
+	builder.add("avalanche/1.7.4", vdr0, primaryAndSubnet)
+	builder.add("avalanche/1.7.3", vdr1, primary)
+	builder.add("avalanche/1.7.4", nonVdr, primary)
+	census := builder.build()
+
+	assert.Equal(t, json.Uint64(100), census.TotalStake)
+	assert.Equal(t, json.Uint64(80), census.ConnectedStake)
+	assert.Len(t, census.Versions, 2)
+
+	newest := census.Versions[0]
+	assert.Equal(t, "avalanche/1.7.4", newest.Version)
+	assert.Equal(t, json.Uint64(2), newest.NumPeers)
+	assert.Equal(t, json.Uint64(50), newest.Stake)
+	assert.Equal(t, json.Float64(50), newest.StakePercentage)
+	assert.Equal(t, json.Uint64(2), newest.Subnets[constants.PrimaryNetworkID.String()])
+	assert.Equal(t, json.Uint64(1), newest.Subnets[subnetID.String()])
+
+	oldest := census.Versions[1]
+	assert.Equal(t, "avalanche/1.7.3", oldest.Version)
+	assert.Equal(t, json.Uint64(1), oldest.NumPeers)
+	assert.Equal(t, json.Uint64(30), oldest.Stake)
+	assert.Equal(t, json.Float64(30), oldest.StakePercentage)
+	assert.NotContains(t, oldest.Subnets, subnetID.String())
+}
+
+func TestVersionCensusNoValidators(t *testing.T) {
+	builder := newVersionCensusBuilder(validators.NewSet())
+	builder.add("avalanche/1.7.4", ids.GenerateTestShortID(), ids.Set{})
+	census := builder.build()
+
+	assert.Equal(t, json.Uint64(0), census.TotalStake)
+	assert.Len(t, census.Versions, 1)
+	assert.Equal(t, json.Float64(0), census.Versions[0].StakePercentage)
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+// VersionPolicy describes the policy that peer versions are checked against,
+// and the connected peers that the policy affects.
+type VersionPolicy struct {
+	Version           string    `json:"version"`
+	MinCompatible     string    `json:"minCompatible"`
+	MinCompatibleTime time.Time `json:"minCompatibleTime"`
+	PrevMinCompatible string    `json:"prevMinCompatible"`
+	// Empty if there is no maximum compatible version
+	MaxCompatible     string    `json:"maxCompatible"`
+	MinUnmaskable     string    `json:"minUnmaskable"`
+	MinUnmaskableTime time.Time `json:"minUnmaskableTime"`
+	PrevMinUnmaskable string    `json:"prevMinUnmaskable"`
+	// Connected peers that are, or will be, incompatible or masked. Sorted by
+	// node ID.
+	AffectedPeers []AffectedPeer `json:"affectedPeers"`
+	// Stake weight of the primary network validators whose stake is, or will
+	// be, masked
+	MaskedStake   json.Uint64 `json:"maskedStake"`
+	WillMaskStake json.Uint64 `json:"willMaskStake"`
+}
+
+// AffectedPeer describes how the version policy affects a connected peer.
+type AffectedPeer struct {
+	ID      string `json:"nodeID"`
+	Version string `json:"version"`
+	// Stake weight of the peer on the primary network
+	Stake json.Uint64 `json:"stake"`
+	// True if the peer is incompatible now, in which case it will be
+	// disconnected from
+	Incompatible bool `json:"incompatible"`
+	// True if the peer will be incompatible once [MinCompatibleTime] passes
+	WillBeIncompatible bool `json:"willBeIncompatible"`
+	// True if the peer's stake is masked now
+	Masked bool `json:"masked"`
+	// True if the peer's stake will be masked once [MinUnmaskableTime] passes
+	WillBeMasked bool `json:"willBeMasked"`
+}
+
+type versionPolicyBuilder struct {
+	compatibility version.Compatibility
+	vdrs          validators.Set
+	policy        VersionPolicy
+}
+
+func newVersionPolicyBuilder(compatibility version.Compatibility, vdrs validators.Set) *versionPolicyBuilder {
+	config := compatibility.Config()
+	policy := VersionPolicy{
+		Version:           compatibility.Version().String(),
+		MinCompatible:     config.MinCompatible.String(),
+		MinCompatibleTime: config.MinCompatibleTime,
+		PrevMinCompatible: config.PrevMinCompatible.String(),
+		MinUnmaskable:     config.MinUnmaskable.String(),
+		MinUnmaskableTime: config.MinUnmaskableTime,
+		PrevMinUnmaskable: config.PrevMinUnmaskable.String(),
+		AffectedPeers:     []AffectedPeer{},
+	}
+	if config.MaxCompatible != nil {
+		policy.MaxCompatible = config.MaxCompatible.String()
+	}
+	return &versionPolicyBuilder{
+		compatibility: compatibility,
+		vdrs:          vdrs,
+		policy:        policy,
+	}
+}
+
+// add records [nodeID], which runs [peerVersion], if the policy affects it
+func (b *versionPolicyBuilder) add(nodeID ids.ShortID, peerVersion version.Application) {
+	config := b.compatibility.Config()
+	affected := AffectedPeer{
+		ID:                 nodeID.PrefixedString(constants.NodeIDPrefix),
+		Version:            peerVersion.String(),
+		Incompatible:       b.compatibility.Compatible(peerVersion) != nil,
+		WillBeIncompatible: peerVersion.Before(config.MinCompatible),
+		Masked:             b.compatibility.Unmaskable(peerVersion) != nil,
+		WillBeMasked:       b.compatibility.WontMask(peerVersion) != nil,
+	}
+	if !affected.Incompatible && !affected.WillBeIncompatible && !affected.Masked && !affected.WillBeMasked {
+		return
+	}
+
+	weight, _ := b.vdrs.GetWeight(nodeID)
+	affected.Stake = json.Uint64(weight)
+	if affected.Masked {
+		b.policy.MaskedStake += affected.Stake
+	}
+	if affected.WillBeMasked {
+		b.policy.WillMaskStake += affected.Stake
+	}
+	b.policy.AffectedPeers = append(b.policy.AffectedPeers, affected)
+}
+
+func (b *versionPolicyBuilder) build() VersionPolicy {
+	sort.Slice(b.policy.AffectedPeers, func(i, j int) bool {
+		return b.policy.AffectedPeers[i].ID < b.policy.AffectedPeers[j].ID
+	})
+	return b.policy
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+func TestVersionPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewSet()
+	vdr0 := ids.ShortID{0}
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	assert.NoError(vdrs.AddWeight(vdr0, 50))
+	assert.NoError(vdrs.AddWeight(vdr1, 30))
+	assert.NoError(vdrs.AddWeight(vdr2, 20))
+
+	compatibility := version.NewCompatibilityFromConfig(
+		version.NewDefaultApplication("avalanche", 1, 7, 4),
+		version.CompatibilityConfig{
+			// The minimum compatible version is raised in the future
+			MinCompatible:     version.NewDefaultApplication("avalanche", 1, 7, 3),
+			MinCompatibleTime: time.Unix(1<<40, 0),
+			PrevMinCompatible: version.NewDefaultApplication("avalanche", 1, 7, 0),
+			MaxCompatible:     version.NewDefaultApplication("avalanche", 1, 8, 0),
+			// Older stake is already masked
+			MinUnmaskable:     version.NewDefaultApplication("avalanche", 1, 7, 2),
+			MinUnmaskableTime: time.Unix(0, 0),
+			PrevMinUnmaskable: version.NewDefaultApplication("avalanche", 1, 7, 0),
+		},
+	)
+
+	builder := newVersionPolicyBuilder(compatibility, vdrs)
+	builder.add(vdr3, version.NewDefaultApplication("avalanche", 1, 8, 1))
+	builder.add(vdr2, version.NewDefaultApplication("avalanche", 1, 7, 1))
+	builder.add(vdr1, version.NewDefaultApplication("avalanche", 1, 7, 2))
+	builder.add(vdr0, version.NewDefaultApplication("avalanche", 1, 7, 4))
+	policy := builder.build()
+
+	assert.Equal("avalanche/1.7.4", policy.Version)
+	assert.Equal("avalanche/1.8.0", policy.MaxCompatible)
+	assert.Equal(json.Uint64(20), policy.MaskedStake)
+	assert.Equal(json.Uint64(20), policy.WillMaskStake)
+	assert.Equal([]AffectedPeer{
+		{
+			ID:                 vdr1.PrefixedString(constants.NodeIDPrefix),
+			Version:            "avalanche/1.7.2",
+			Stake:              30,
+			WillBeIncompatible: true,
+		},
+		{
+			ID:                 vdr2.PrefixedString(constants.NodeIDPrefix),
+			Version:            "avalanche/1.7.1",
+			Stake:              20,
+			WillBeIncompatible: true,
+			Masked:             true,
+			WillBeMasked:       true,
+		},
+		{
+			ID:           vdr3.PrefixedString(constants.NodeIDPrefix),
+			Version:      "avalanche/1.8.1",
+			Incompatible: true,
+			Masked:       true,
+			WillBeMasked: true,
+		},
+	}, policy.AffectedPeers)
+}
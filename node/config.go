@@ -7,20 +7,39 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/Toinounet21/avalanchego-mod/api/ratelimit"
+	"github.com/Toinounet21/avalanchego-mod/api/server"
+	"github.com/Toinounet21/avalanchego-mod/backup"
 	"github.com/Toinounet21/avalanchego-mod/chains"
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/vaultdb"
 	"github.com/Toinounet21/avalanchego-mod/genesis"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/nat"
 	"github.com/Toinounet21/avalanchego-mod/network"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/avalanche"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/crashreport"
 	"github.com/Toinounet21/avalanchego-mod/utils/dynamicip"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/password"
 	"github.com/Toinounet21/avalanchego-mod/utils/profiler"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
+	"github.com/Toinounet21/avalanchego-mod/version"
 	"github.com/Toinounet21/avalanchego-mod/vms"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm"
+)
+
+// Backends that the keystore's users can be stored in
+const (
+	// KeystoreBackendLocal stores users in the node's database
+	KeystoreBackendLocal = "local"
+	// KeystoreBackendVault stores users in HashiCorp Vault, so that they never
+	// touch the node's disk
+	KeystoreBackendVault = "vault"
 )
 
 type IPCConfig struct {
@@ -35,8 +54,9 @@ type APIAuthConfig struct {
 }
 
 type APIIndexerConfig struct {
-	IndexAPIEnabled      bool `json:"indexAPIEnabled"`
-	IndexAllowIncomplete bool `json:"indexAllowIncomplete"`
+	IndexAPIEnabled      bool     `json:"indexAPIEnabled"`
+	IndexAllowIncomplete bool     `json:"indexAllowIncomplete"`
+	IndexChains          []string `json:"indexChains"`
 }
 
 type HTTPConfig struct {
@@ -48,10 +68,16 @@ type HTTPConfig struct {
 	HTTPSKey     []byte `json:"-"`
 	HTTPSCert    []byte `json:"-"`
 
-	APIAllowedOrigins []string `json:"apiAllowedOrigins"`
+	server.CORSConfig `json:"corsConfig"`
 
 	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
 	ShutdownWait    time.Duration `json:"shutdownWait"`
+
+	// Maximum number of requests in a JSON-RPC batch
+	APIMaxBatchSize int `json:"apiMaxBatchSize"`
+
+	// Rate limits of the API endpoints and methods
+	APIRateLimits ratelimit.Config `json:"apiRateLimits"`
 }
 
 type APIConfig struct {
@@ -65,14 +91,38 @@ type APIConfig struct {
 	KeystoreAPIEnabled bool `json:"keystoreAPIEnabled"`
 	MetricsAPIEnabled  bool `json:"metricsAPIEnabled"`
 	HealthAPIEnabled   bool `json:"healthAPIEnabled"`
+	EventsAPIEnabled   bool `json:"eventsAPIEnabled"`
+	GraphQLAPIEnabled  bool `json:"graphQLAPIEnabled"`
+
+	// Parameters that keystore passwords are hashed with
+	KeystoreHashParams password.HashParams `json:"keystoreHashParams"`
+	// Maximum number of bytes that a keystore user may store. 0 means
+	// unlimited.
+	KeystoreUserQuota uint64 `json:"keystoreUserQuota"`
+
+	// Duration that keystore users with TOTP enabled stay unlocked
+	KeystoreTOTPUnlockDuration time.Duration `json:"keystoreTOTPUnlockDuration"`
+
+	// Where the keystore's users are stored. Either [KeystoreBackendLocal] or
+	// [KeystoreBackendVault].
+	KeystoreBackend string `json:"keystoreBackend"`
+	// Describes where users are stored in Vault, if [KeystoreBackend] is
+	// [KeystoreBackendVault]
+	KeystoreVaultConfig vaultdb.Config `json:"keystoreVaultConfig"`
 }
 
 type IPConfig struct {
 	IP utils.DynamicIPDesc `json:"ip"`
+	// IPv6 address advertised in addition to [IP]. Zero if this node only
+	// advertises [IP].
+	IPv6 utils.IPDesc `json:"ipv6"`
 	// True if we attempted NAT Traversal
 	AttemptedNATTraversal bool `json:"attemptedNATTraversal"`
 	// Tries to perform network address translation
 	Nat nat.Router `json:"-"`
+	// Maps the node's ports on [Nat]. Set by the process that runs the node.
+	// May be nil.
+	PortMapper *nat.Mapper `json:"-"`
 	// Dynamic Update duration for IP or NAT traversal
 	DynamicUpdateDuration time.Duration `json:"dynamicUpdateDuration"`
 	// Tries to resolve our IP from an external source
@@ -109,6 +159,15 @@ type BootstrapConfig struct {
 	// ancestors while responding to a GetAncestors message
 	BootstrapMaxTimeGetAncestors time.Duration `json:"bootstrapMaxTimeGetAncestors"`
 
+	// If > 0, database writes are deferred until the primary network is
+	// bootstrapped, and flushed after every [BootstrapDeferredFlushFrequency]
+	// containers executed by a bootstrapping chain.
+	BootstrapDeferredFlushFrequency int `json:"bootstrapDeferredFlushFrequency"`
+
+	// If > 0, the bootstrapping jobs and missing IDs that are older than
+	// [BootstrapQueueMaxAge] are evicted on startup
+	BootstrapQueueMaxAge time.Duration `json:"bootstrapQueueMaxAge"`
+
 	BootstrapIDs []ids.ShortID  `json:"bootstrapIDs"`
 	BootstrapIPs []utils.IPDesc `json:"bootstrapIPs"`
 }
@@ -120,8 +179,12 @@ type DatabaseConfig struct {
 	// Name of the database type to use
 	Name string `json:"name"`
 
-	// Path to config file
+	// Config of the database. For leveldb, this is the effective config,
+	// including the defaults of its profile.
 	Config []byte `json:"-"`
+
+	// Thresholds past which the database is unhealthy
+	HealthConfig database.HealthConfig `json:"healthConfig"`
 }
 
 // Config contains all of the configurations of an Avalanche node.
@@ -140,6 +203,9 @@ type Config struct {
 	// ID of the network this node should connect to
 	NetworkID uint32 `json:"networkID"`
 
+	// When the network upgrades of the network activate
+	UpgradeConfig version.UpgradeConfig `json:"upgradeConfig"`
+
 	// Assertions configuration
 	EnableAssertions bool `json:"enableAssertions"`
 
@@ -157,15 +223,33 @@ type Config struct {
 	// Benchlist Configuration
 	BenchlistConfig benchlist.Config `json:"benchlistConfig"`
 
+	// Peer scoring configuration
+	PeerScoringConfig scoring.Config `json:"peerScoringConfig"`
+
 	// Profiling configurations
 	ProfilerConfig profiler.Config `json:"profilerConfig"`
 
+	// Pruning of the history of the chains
+	PruningConfig chains.PruningConfig `json:"pruningConfig"`
+
+	// Backup configuration
+	BackupConfig backup.Config `json:"backupConfig"`
+	// If non-nil, the database is restored from this backup before the node
+	// starts
+	RestoreArchive *backup.Archive `json:"-"`
+
 	// Logging configuration
 	LoggingConfig logging.Config `json:"loggingConfig"`
 
+	// Crash reporting configuration
+	CrashReportConfig crashreport.Config `json:"crashReportConfig"`
+
 	// Plugin directory
 	PluginDir string `json:"pluginDir"`
 
+	// Health checks and restarts of the VM plugin processes
+	PluginSupervisorConfig rpcchainvm.SupervisorConfig `json:"pluginSupervisorConfig"`
+
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters `json:"consensusParams"`
 
@@ -176,18 +260,41 @@ type Config struct {
 	ConsensusRouter          router.Router       `json:"-"`
 	RouterHealthConfig       router.HealthConfig `json:"routerHealthConfig"`
 	ConsensusShutdownTimeout time.Duration       `json:"consensusShutdownTimeout"`
+	// Max number of messages, over all chains, processed at once.
+	// If 0, there is no limit.
+	ConsensusMaxProcessingMsgs int `json:"consensusMaxProcessingMsgs"`
 	// Gossip a container in the accepted frontier every [ConsensusGossipFrequency]
 	ConsensusGossipFrequency time.Duration `json:"consensusGossipFreq"`
+	// Re-send the chits sent in response to a query for a block in response
+	// to queries for the same block for [ConsensusChitCacheDuration]
+	ConsensusChitCacheDuration time.Duration `json:"consensusChitCacheDuration"`
+	// Sample the validators polled by queries with a VRF under the staking key
+	ConsensusVRFSampling bool `json:"consensusVRFSampling"`
 
 	// Subnet Whitelist
 	WhitelistedSubnets ids.Set `json:"whitelistedSubnets"`
 
+	// Nodes that P-chain txs issued to this node's API are forwarded to
+	TxForwardingNodeIDs ids.ShortSet `json:"txForwardingNodeIDs"`
+
 	// SubnetConfigs
 	SubnetConfigs map[ids.ID]chains.SubnetConfig `json:"subnetConfigs"`
 
 	// ChainConfigs
 	ChainConfigs map[string]chains.ChainConfig `json:"-"`
 
+	// Directory the chain configs were read from. Empty if the chain configs
+	// were provided as content.
+	ChainConfigDir string `json:"chainConfigDir"`
+
 	// VM management
 	VMManager vms.Manager `json:"-"`
+
+	// VMs to register, and the plugins that run them
+	VMRegistry map[ids.ID]vms.RegistryEntry `json:"vmRegistry"`
+
+	// LoadConfig re-reads the config from the sources it was read from, so that
+	// it can be reloaded while the node runs. If nil, the config can't be
+	// reloaded.
+	LoadConfig func() (Config, error) `json:"-"`
 }
@@ -22,32 +22,46 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/api/admin"
 	"github.com/Toinounet21/avalanchego-mod/api/auth"
+	"github.com/Toinounet21/avalanchego-mod/api/graphql"
 	"github.com/Toinounet21/avalanchego-mod/api/health"
 	"github.com/Toinounet21/avalanchego-mod/api/info"
 	"github.com/Toinounet21/avalanchego-mod/api/keystore"
 	"github.com/Toinounet21/avalanchego-mod/api/metrics"
+	"github.com/Toinounet21/avalanchego-mod/api/ratelimit"
 	"github.com/Toinounet21/avalanchego-mod/api/server"
+	"github.com/Toinounet21/avalanchego-mod/backup"
 	"github.com/Toinounet21/avalanchego-mod/chains"
 	"github.com/Toinounet21/avalanchego-mod/chains/atomic"
 	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/deferreddb"
 	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/database/vaultdb"
+	"github.com/Toinounet21/avalanchego-mod/events"
 	"github.com/Toinounet21/avalanchego-mod/genesis"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/ids/aliasdb"
 	"github.com/Toinounet21/avalanchego-mod/indexer"
 	"github.com/Toinounet21/avalanchego-mod/ipcs"
 	"github.com/Toinounet21/avalanchego-mod/message"
 	"github.com/Toinounet21/avalanchego-mod/network"
+	"github.com/Toinounet21/avalanchego-mod/network/allowlist"
+	"github.com/Toinounet21/avalanchego-mod/network/blocklist"
+	"github.com/Toinounet21/avalanchego-mod/network/peerstore"
+	"github.com/Toinounet21/avalanchego-mod/network/quic"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/network/throttling"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/timeout"
-	"github.com/Toinounet21/avalanchego-mod/snow/triggers"
 	"github.com/Toinounet21/avalanchego-mod/snow/uptime"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/compression"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/crashreport"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/math"
@@ -55,7 +69,9 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 	"github.com/Toinounet21/avalanchego-mod/version"
+	"github.com/Toinounet21/avalanchego-mod/vms"
 	"github.com/Toinounet21/avalanchego-mod/vms/avm"
+	"github.com/Toinounet21/avalanchego-mod/vms/blsfx"
 	"github.com/Toinounet21/avalanchego-mod/vms/evm"
 	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
 	"github.com/Toinounet21/avalanchego-mod/vms/platformvm"
@@ -67,8 +83,13 @@ import (
 )
 
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey    = []byte("genesisID")
+	indexerDBPrefix   = []byte{0x00}
+	peerStoreDBPrefix = []byte("peer store")
+	blocklistDBPrefix = []byte("blocklist")
+	benchlistDBPrefix = []byte("benchlist")
+	aliasDBPrefix     = []byte("chain aliases")
+	vmRegistryPrefix  = []byte("vm registry")
 
 	errInvalidTLSKey   = errors.New("invalid TLS key")
 	errPNotCreated     = errors.New("P-Chain not created")
@@ -91,10 +112,18 @@ type Node struct {
 	// Storage for this node
 	DBManager manager.Manager
 	DB        database.Database
+	// Defers writes to the database while the primary network bootstraps.
+	// Nil if writes aren't deferred.
+	deferredDB *deferreddb.Database
+	// Checks that the database can keep being written to
+	dbHealthChecker *database.HealthChecker
 
 	// Profiles the process. Nil if continuous profiling is disabled.
 	profiler profiler.ContinuousProfiler
 
+	// Writes backups of the node
+	backuper backup.Backuper
+
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
@@ -113,14 +142,26 @@ type Node struct {
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
+	// Persists the VMs registered through the admin API
+	vmRegistry *vms.Registry
+
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
+	// Tracks how useful each peer has been
+	peerScorer common.PeerScorer
+
+	// Remembers peers this node connected to across restarts
+	peerStore peerstore.Store
+
+	// Node IDs and IP ranges this node refuses to connect to
+	blocklist blocklist.Blocklist
+
 	uptimeCalculator uptime.LockedCalculator
 
 	// dispatcher for events as they happen in consensus
-	DecisionDispatcher  *triggers.EventDispatcher
-	ConsensusDispatcher *triggers.EventDispatcher
+	DecisionDispatcher  *common.EventBus
+	ConsensusDispatcher *common.EventBus
 
 	IPCs *ipcs.ChainIPCs
 
@@ -140,6 +181,15 @@ type Node struct {
 	// This node's configuration
 	Config *Config
 
+	// Serializes the reloads of the config
+	reloadLock sync.Mutex
+	// The config as it was read, with the reloaded fields updated. The changes
+	// of a reloaded config are found by comparing it to this config.
+	loadedConfig Config
+	// Enables and disables the APIs that were enabled when the node started,
+	// keyed by the config field that enables them
+	apiToggles map[string]*utils.AtomicBool
+
 	// ensures that we only close the node once.
 	shutdownOnce sync.Once
 
@@ -165,10 +215,47 @@ type Node struct {
  */
 
 func (n *Node) initNetworking() error {
-	listener, err := net.Listen(constants.NetworkType, fmt.Sprintf(":%d", n.Config.IP.Port))
+	if n.Config.PortMapper != nil {
+		if err := n.Config.PortMapper.RegisterMetrics(n.MetricsRegisterer); err != nil {
+			return fmt.Errorf("couldn't register NAT metrics: %w", err)
+		}
+	}
+
+	// Initialize validator manager and primary network's validator set
+	primaryNetworkValidators := validators.NewSet()
+	n.vdrs = validators.NewManager()
+	if err := n.vdrs.Set(constants.PrimaryNetworkID, primaryNetworkValidators); err != nil {
+		return err
+	}
+
+	tlsConfig := network.TLSConfig(n.Config.StakingTLSCert)
+	if n.Config.NetworkConfig.AllowlistEnabled {
+		// Reject the nodes that aren't allowed during the TLS handshake
+		n.Config.NetworkConfig.Allowlist = allowlist.New(
+			n.Config.NetworkConfig.AllowedNodeIDs,
+			n.Config.WhitelistedSubnets,
+			n.vdrs,
+		)
+		tlsConfig = network.AllowlistTLSConfig(tlsConfig, n.Config.NetworkConfig.Allowlist)
+		n.Log.Info("only connecting to %d allowed nodes and to the validators of the whitelisted subnets",
+			n.Config.NetworkConfig.AllowedNodeIDs.Len())
+	}
+
+	listener, err := n.listen()
 	if err != nil {
 		return err
 	}
+	if n.Config.NetworkConfig.QUICEnabled {
+		// Bind QUIC to the same port as TCP so that peers can dial either
+		// transport using the IP this node advertises.
+		port := listener.Addr().(*net.TCPAddr).Port
+		quicListener, err := quic.Listen(fmt.Sprintf(":%d", port), tlsConfig)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("couldn't listen for QUIC connections: %w", err)
+		}
+		listener = network.NewMultiListener(listener, quicListener)
+	}
 	// Wrap listener so it will only accept a certain number of incoming connections per second
 	listener = throttling.NewThrottledListener(listener, n.Config.NetworkConfig.ThrottlerConfig.MaxIncomingConnsPerSec)
 
@@ -182,27 +269,37 @@ func (n *Node) initNetworking() error {
 		}
 		n.Log.Info("this node's IP is set to: %q", ipDesc)
 	}
+	if !n.Config.IPv6.IsZero() {
+		n.Log.Info("this node's IPv6 address is set to: %q", n.Config.IPv6)
+	}
 
 	tlsKey, ok := n.Config.StakingTLSCert.PrivateKey.(crypto.Signer)
 	if !ok {
 		return errInvalidTLSKey
 	}
 
-	tlsConfig := network.TLSConfig(n.Config.StakingTLSCert)
-
-	// Initialize validator manager and primary network's validator set
-	primaryNetworkValidators := validators.NewSet()
-	n.vdrs = validators.NewManager()
-	if err := n.vdrs.Set(constants.PrimaryNetworkID, primaryNetworkValidators); err != nil {
-		return err
-	}
+	n.peerScorer = scoring.NewScorer(n.Config.PeerScoringConfig)
 
 	// Configure benchlist
 	n.Config.BenchlistConfig.Validators = n.vdrs
 	n.Config.BenchlistConfig.Benchable = n.Config.ConsensusRouter
 	n.Config.BenchlistConfig.StakingEnabled = n.Config.EnableStaking
+	n.Config.BenchlistConfig.Scorer = n.peerScorer
+	n.Config.BenchlistConfig.DB = prefixdb.New(benchlistDBPrefix, n.DB)
 	n.benchlistManager = benchlist.NewManager(&n.Config.BenchlistConfig)
 
+	if n.Config.NetworkConfig.PeerStoreSize > 0 {
+		peerStoreDB := prefixdb.New(peerStoreDBPrefix, n.DB)
+		n.peerStore = peerstore.New(peerStoreDB, n.Config.NetworkConfig.PeerStoreSize, n.Log)
+	} else {
+		n.peerStore = peerstore.NewNoStore()
+	}
+
+	n.blocklist, err = blocklist.New(prefixdb.New(blocklistDBPrefix, n.DB))
+	if err != nil {
+		return fmt.Errorf("couldn't load blocklist: %w", err)
+	}
+
 	n.uptimeCalculator = uptime.NewLockedCalculator()
 
 	consensusRouter := n.Config.ConsensusRouter
@@ -248,6 +345,7 @@ func (n *Node) initNetworking() error {
 	n.Config.NetworkConfig.Namespace = n.networkNamespace
 	n.Config.NetworkConfig.MyNodeID = n.ID
 	n.Config.NetworkConfig.MyIP = n.Config.IP
+	n.Config.NetworkConfig.MyIPv6 = n.Config.IPv6
 	n.Config.NetworkConfig.NetworkID = n.Config.NetworkID
 	n.Config.NetworkConfig.Validators = n.vdrs
 	n.Config.NetworkConfig.Beacons = n.beacons
@@ -256,6 +354,9 @@ func (n *Node) initNetworking() error {
 	n.Config.NetworkConfig.WhitelistedSubnets = n.Config.WhitelistedSubnets
 	n.Config.NetworkConfig.UptimeCalculator = n.uptimeCalculator
 	n.Config.NetworkConfig.UptimeRequirement = n.Config.UptimeRequirement
+	n.Config.NetworkConfig.PeerScorer = n.peerScorer
+	n.Config.NetworkConfig.PeerStore = n.peerStore
+	n.Config.NetworkConfig.Blocklist = n.blocklist
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -270,15 +371,38 @@ func (n *Node) initNetworking() error {
 	return err
 }
 
+// listen returns the listener for TCP connections from peers. If this node
+// advertises an IPv6 address, separate IPv4 and IPv6 sockets are bound to the
+// staking port so that both address families are accepted regardless of
+// whether the host maps IPv4 onto IPv6 sockets.
+func (n *Node) listen() (net.Listener, error) {
+	if n.Config.IPv6.IsZero() {
+		return net.Listen(constants.NetworkType, fmt.Sprintf(":%d", n.Config.IP.Port))
+	}
+
+	listener4, err := net.Listen("tcp4", fmt.Sprintf("0.0.0.0:%d", n.Config.IP.Port))
+	if err != nil {
+		return nil, err
+	}
+	// Bind IPv6 to the same port as IPv4, which may have been chosen by the OS
+	port := listener4.Addr().(*net.TCPAddr).Port
+	listener6, err := net.Listen("tcp6", fmt.Sprintf("[::]:%d", port))
+	if err != nil {
+		_ = listener4.Close()
+		return nil, fmt.Errorf("couldn't listen for IPv6 connections: %w", err)
+	}
+	return network.NewMultiListener(listener4, listener6), nil
+}
+
 type insecureValidatorManager struct {
 	router.Router
 	vdrs   validators.Set
 	weight uint64
 }
 
-func (i *insecureValidatorManager) Connected(vdrID ids.ShortID, nodeVersion version.Application) {
+func (i *insecureValidatorManager) Connected(vdrID ids.ShortID, peerInfo validators.PeerInfo) {
 	_ = i.vdrs.AddWeight(vdrID, i.weight)
-	i.Router.Connected(vdrID, nodeVersion)
+	i.Router.Connected(vdrID, peerInfo)
 }
 
 func (i *insecureValidatorManager) Disconnected(vdrID ids.ShortID) {
@@ -296,24 +420,24 @@ type beaconManager struct {
 	totalWeight    uint64
 }
 
-func (b *beaconManager) Connected(vdrID ids.ShortID, nodeVersion version.Application) {
+func (b *beaconManager) Connected(vdrID ids.ShortID, peerInfo validators.PeerInfo) {
 	// TODO: this is always 1, beacons can be reduced to ShortSet?
 	weight, ok := b.beacons.GetWeight(vdrID)
 	if !ok {
-		b.Router.Connected(vdrID, nodeVersion)
+		b.Router.Connected(vdrID, peerInfo)
 		return
 	}
 	weight, err := math.Add64(weight, b.totalWeight)
 	if err != nil {
 		b.timer.Cancel()
-		b.Router.Connected(vdrID, nodeVersion)
+		b.Router.Connected(vdrID, peerInfo)
 		return
 	}
 	b.totalWeight = weight
 	if b.totalWeight >= b.requiredWeight {
 		b.timer.Cancel()
 	}
-	b.Router.Connected(vdrID, nodeVersion)
+	b.Router.Connected(vdrID, peerInfo)
 }
 
 func (b *beaconManager) Disconnected(vdrID ids.ShortID) {
@@ -353,6 +477,18 @@ func (n *Node) Dispatch() error {
 		n.Shutdown(1)
 	})
 
+	// Add peers this node was connected to before it restarted. The bootstrap
+	// nodes are added below in case none of these peers are reachable.
+	storedPeers, err := n.peerStore.Peers()
+	if err != nil {
+		n.Log.Warn("failed to load stored peers due to %s", err)
+	}
+	for _, peer := range storedPeers {
+		if peer.NodeID != n.ID && !peer.IP.Equal(n.Config.IP.IP()) {
+			n.Net.Track(peer.IP, peer.NodeID)
+		}
+	}
+
 	// Add bootstrap nodes to the peer network
 	for _, peerIP := range n.Config.BootstrapIPs {
 		if !peerIP.Equal(n.Config.IP.IP()) {
@@ -361,7 +497,7 @@ func (n *Node) Dispatch() error {
 	}
 
 	// Start P2P connections
-	err := n.Net.Dispatch()
+	err = n.Net.Dispatch()
 
 	// If the P2P server isn't running, shut down the node.
 	// If node is already shutting down, this does nothing.
@@ -382,6 +518,21 @@ func (n *Node) initDatabase(dbManager manager.Manager) error {
 	n.DBManager = dbManager
 	n.DB = dbManager.Current().Database
 
+	// The free disk space isn't checked if the database is in memory. Writes
+	// are checked against the database itself, even when they're deferred.
+	dbPath := n.Config.DatabaseConfig.Path
+	if n.Config.DatabaseConfig.Name == memdb.Name {
+		dbPath = ""
+	}
+	n.dbHealthChecker = database.NewHealthChecker(n.DB, dbPath, n.Config.DatabaseConfig.HealthConfig)
+
+	if archive := n.Config.RestoreArchive; archive != nil {
+		n.Log.Info("restoring database from backup written at %s", archive.Manifest.Timestamp)
+		if err := archive.RestoreDatabase(n.DB, dbManager.Current().Version); err != nil {
+			return fmt.Errorf("couldn't restore database from backup: %w", err)
+		}
+	}
+
 	rawExpectedGenesisHash := hashing.ComputeHash256(n.Config.GenesisBytes)
 
 	rawGenesisHash, err := n.DB.Get(genesisHashKey)
@@ -405,7 +556,26 @@ func (n *Node) initDatabase(dbManager manager.Manager) error {
 	if genesisHash != expectedGenesisHash {
 		return fmt.Errorf("db contains invalid genesis hash. DB Genesis: %s Generated Genesis: %s", genesisHash, expectedGenesisHash)
 	}
-	return nil
+
+	if n.Config.BootstrapDeferredFlushFrequency <= 0 {
+		return nil
+	}
+
+	// Defer the writes to the current database until the primary network is
+	// bootstrapped. Previous databases are only read from.
+	n.Log.Info("deferring database writes while bootstrapping, flushing every %d containers",
+		n.Config.BootstrapDeferredFlushFrequency)
+	currentDB := dbManager.Current()
+	n.deferredDB = deferreddb.New(currentDB.Database)
+	databases := make([]*manager.VersionedDatabase, len(dbManager.GetDatabases()))
+	copy(databases, dbManager.GetDatabases())
+	databases[0] = &manager.VersionedDatabase{
+		Database: n.deferredDB,
+		Version:  currentDB.Version,
+	}
+	n.DBManager, err = manager.NewManagerFromDBs(databases)
+	n.DB = n.deferredDB
+	return err
 }
 
 // Set the node IDs of the peers this node should first connect to
@@ -419,16 +589,13 @@ func (n *Node) initBeacons() error {
 	return nil
 }
 
-// Create the EventDispatcher used for hooking events
+// Create the event buses used for hooking events
 // into the general process flow.
 func (n *Node) initEventDispatcher() error {
-	n.DecisionDispatcher = &triggers.EventDispatcher{}
-	n.DecisionDispatcher.Initialize(n.Log)
+	n.DecisionDispatcher = common.NewEventBus(n.Log)
+	n.ConsensusDispatcher = common.NewEventBus(n.Log)
 
-	n.ConsensusDispatcher = &triggers.EventDispatcher{}
-	n.ConsensusDispatcher.Initialize(n.Log)
-
-	return n.ConsensusDispatcher.Register("gossip", n.Net)
+	return n.ConsensusDispatcher.Subscribe("gossip", n.Net)
 }
 
 func (n *Node) initIPCs() error {
@@ -455,6 +622,7 @@ func (n *Node) initIndexer() error {
 	n.indexer, err = indexer.NewIndexer(indexer.Config{
 		IndexingEnabled:      n.Config.IndexAPIEnabled,
 		AllowIncompleteIndex: n.Config.IndexAllowIncomplete,
+		IndexedChains:        n.Config.IndexChains,
 		DB:                   txIndexerDB,
 		Log:                  n.Log,
 		DecisionDispatcher:   n.DecisionDispatcher,
@@ -491,22 +659,24 @@ func (n *Node) initChains(genesisBytes []byte) {
 func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 
-	if !n.Config.APIRequireAuthToken {
-		n.APIServer.Initialize(
-			n.Log,
-			n.LogFactory,
-			n.Config.HTTPHost,
-			n.Config.HTTPPort,
-			n.Config.APIAllowedOrigins,
-			n.Config.ShutdownTimeout,
-			n.ID,
-		)
-		return nil
+	// The wrappers are applied in order, so the rate limiter rejects requests
+	// before their auth token is checked
+	var wrappers []server.Wrapper
+	var a auth.Auth
+	if n.Config.APIRequireAuthToken {
+		var err error
+		a, err = auth.New(n.Log, "auth", n.Config.APIAuthPassword)
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, a)
 	}
-
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
-	if err != nil {
-		return err
+	if len(n.Config.APIRateLimits.Rules) != 0 {
+		limiter, err := ratelimit.New(n.Config.APIRateLimits, "api_rate_limiter", n.MetricsRegisterer)
+		if err != nil {
+			return fmt.Errorf("couldn't initialize API rate limiter: %w", err)
+		}
+		wrappers = append(wrappers, limiter)
 	}
 
 	n.APIServer.Initialize(
@@ -514,11 +684,15 @@ func (n *Node) initAPIServer() error {
 		n.LogFactory,
 		n.Config.HTTPHost,
 		n.Config.HTTPPort,
-		n.Config.APIAllowedOrigins,
+		n.Config.CORSConfig,
 		n.Config.ShutdownTimeout,
+		n.Config.APIMaxBatchSize,
 		n.ID,
-		a,
+		wrappers...,
 	)
+	if !n.Config.APIRequireAuthToken {
+		return nil
+	}
 
 	// only create auth service if token authorization is required
 	n.Log.Info("API authorization is enabled. Auth tokens must be passed in the header of API requests, except requests to the auth service.")
@@ -577,6 +751,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	if err := timeoutManager.Initialize(
 		&n.Config.AdaptiveTimeoutConfig,
 		n.benchlistManager,
+		n.peerScorer,
 		"requests",
 		n.MetricsRegisterer,
 	); err != nil {
@@ -602,7 +777,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		return fmt.Errorf("couldn't initialize chain router: %w", err)
 	}
 
-	n.chainManager = chains.New(&chains.ManagerConfig{
+	n.chainManager, err = chains.New(&chains.ManagerConfig{
 		StakingEnabled:                          n.Config.EnableStaking,
 		StakingCert:                             n.Config.StakingTLSCert,
 		Log:                                     n.Log,
@@ -613,6 +788,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		DBManager:                               n.DBManager,
 		MsgCreator:                              n.msgCreator,
 		Router:                                  n.Config.ConsensusRouter,
+		Scheduler:                               router.NewScheduler(n.Config.ConsensusMaxProcessingMsgs),
 		Net:                                     n.Net,
 		ConsensusParams:                         n.Config.ConsensusParams,
 		Validators:                              n.vdrs,
@@ -625,6 +801,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		XChainID:                                xChainID,
 		CriticalChains:                          criticalChains,
 		TimeoutManager:                          timeoutManager,
+		PeerScorer:                              n.peerScorer,
 		Health:                                  n.health,
 		WhitelistedSubnets:                      n.Config.WhitelistedSubnets,
 		RetryBootstrap:                          n.Config.RetryBootstrap,
@@ -634,15 +811,25 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		Metrics:                                 n.MetricsGatherer,
 		SubnetConfigs:                           n.Config.SubnetConfigs,
 		ChainConfigs:                            n.Config.ChainConfigs,
+		ChainConfigDir:                          n.Config.ChainConfigDir,
 		AppGossipValidatorSize:                  int(n.Config.NetworkConfig.AppGossipValidatorSize),
 		AppGossipNonValidatorSize:               int(n.Config.NetworkConfig.AppGossipNonValidatorSize),
 		GossipAcceptedFrontierSize:              int(n.Config.NetworkConfig.GossipAcceptedFrontierSize),
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapAncestorsMaxContainersSent:     n.Config.BootstrapAncestorsMaxContainersSent,
 		BootstrapAncestorsMaxContainersReceived: n.Config.BootstrapAncestorsMaxContainersReceived,
-		ApricotPhase4Time:                       version.GetApricotPhase4Time(n.Config.NetworkID),
-		ApricotPhase4MinPChainHeight:            version.GetApricotPhase4MinPChainHeight(n.Config.NetworkID),
+		ChitCacheDuration:                       n.Config.ConsensusChitCacheDuration,
+		VRFQuerySampling:                        n.Config.ConsensusVRFSampling,
+		DeferredDB:                              n.deferredDB,
+		DeferredFlushFrequency:                  n.Config.BootstrapDeferredFlushFrequency,
+		BootstrapQueueMaxAge:                    n.Config.BootstrapQueueMaxAge,
+		ApricotPhase4Time:                       n.Config.UpgradeConfig.ApricotPhase4Time,
+		ApricotPhase4MinPChainHeight:            n.Config.UpgradeConfig.ApricotPhase4MinPChainHeight,
+		Pruning:                                 n.Config.PruningConfig,
 	})
+	if err != nil {
+		return fmt.Errorf("couldn't initialize chain manager: %w", err)
+	}
 
 	vdrs := n.vdrs
 
@@ -662,6 +849,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 			UptimeLockedCalculator: n.uptimeCalculator,
 			StakingEnabled:         n.Config.EnableStaking,
 			WhitelistedSubnets:     n.Config.WhitelistedSubnets,
+			TxForwardingNodeIDs:    n.Config.TxForwardingNodeIDs,
 			TxFee:                  n.Config.TxFee,
 			CreateAssetTxFee:       n.Config.CreateAssetTxFee,
 			CreateSubnetTxFee:      n.Config.CreateSubnetTxFee,
@@ -674,24 +862,39 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 			MinStakeDuration:       n.Config.MinStakeDuration,
 			MaxStakeDuration:       n.Config.MaxStakeDuration,
 			RewardConfig:           n.Config.RewardConfig,
-			ApricotPhase3Time:      version.GetApricotPhase3Time(n.Config.NetworkID),
-			ApricotPhase4Time:      version.GetApricotPhase4Time(n.Config.NetworkID),
-			ApricotPhase5Time:      version.GetApricotPhase5Time(n.Config.NetworkID),
+			ApricotPhase3Time:      n.Config.UpgradeConfig.ApricotPhase3Time,
+			ApricotPhase4Time:      n.Config.UpgradeConfig.ApricotPhase4Time,
+			ApricotPhase5Time:      n.Config.UpgradeConfig.ApricotPhase5Time,
 		}),
 		n.Config.VMManager.RegisterFactory(avm.ID, &avm.Factory{
-			TxFee:            n.Config.TxFee,
-			CreateAssetTxFee: n.Config.CreateAssetTxFee,
+			TxFee:             n.Config.TxFee,
+			CreateAssetTxFee:  n.Config.CreateAssetTxFee,
+			ApricotPhase6Time: n.Config.UpgradeConfig.ApricotPhase6Time,
 		}),
 		n.Config.VMManager.RegisterFactory(secp256k1fx.ID, &secp256k1fx.Factory{}),
 		n.Config.VMManager.RegisterFactory(nftfx.ID, &nftfx.Factory{}),
 		n.Config.VMManager.RegisterFactory(propertyfx.ID, &propertyfx.Factory{}),
+		n.Config.VMManager.RegisterFactory(blsfx.ID, &blsfx.Factory{}),
 		n.Config.VMManager.RegisterFactory(evm.ID, &coreth.Factory{}),
-		rpcchainvm.RegisterPlugins(n.Config.PluginDir, n.Config.VMManager),
 	)
 	if errs.Errored() {
 		return errs.Err
 	}
 
+	// The VMs registered in the config, or through the admin API before the
+	// node restarted, are registered before the plugin directory is scanned so
+	// that their plugins aren't registered again under their file names.
+	if err := rpcchainvm.RegisterVMs(n.Config.PluginDir, n.Config.VMManager, n.Config.VMRegistry, n.Config.PluginSupervisorConfig); err != nil {
+		return err
+	}
+	n.vmRegistry = vms.NewRegistry(prefixdb.New(vmRegistryPrefix, n.DB))
+	if err := admin.RestoreVMs(n.Log, n.vmRegistry, n.Config.PluginDir, n.Config.VMManager, n.Config.PluginSupervisorConfig); err != nil {
+		return fmt.Errorf("couldn't restore registered VMs: %w", err)
+	}
+	if err := rpcchainvm.RegisterPlugins(n.Config.PluginDir, n.Config.VMManager, n.Config.PluginSupervisorConfig); err != nil {
+		return err
+	}
+
 	vmIDs, err := n.Config.VMManager.ListFactories()
 	if err != nil {
 		return err
@@ -771,12 +974,39 @@ func (n *Node) initSharedMemory() error {
 	return n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
 }
 
+// keystoreDBManager returns the databases that the keystore stores its users
+// in, according to the configured keystore backend
+func (n *Node) keystoreDBManager() (manager.Manager, error) {
+	if n.Config.KeystoreBackend != KeystoreBackendVault {
+		return n.DBManager.NewPrefixDBManager([]byte("keystore")), nil
+	}
+
+	n.Log.Info("reading keystore users from vault at %s", n.Config.KeystoreVaultConfig.Address)
+	db, err := vaultdb.New(n.Config.KeystoreVaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	return manager.NewManagerFromDBs([]*manager.VersionedDatabase{
+		{
+			Database: db,
+			Version:  version.CurrentDatabase,
+		},
+	})
+}
+
 // initKeystoreAPI initializes the keystore service, which is an on-node wallet.
 // Assumes n.APIServer is already set
 func (n *Node) initKeystoreAPI() error {
 	n.Log.Info("initializing keystore")
-	keystoreDB := n.DBManager.NewPrefixDBManager([]byte("keystore"))
-	n.keystore = keystore.New(n.Log, keystoreDB)
+	keystoreDB, err := n.keystoreDBManager()
+	if err != nil {
+		return fmt.Errorf("couldn't initialize keystore database: %w", err)
+	}
+	n.keystore = keystore.NewWithConfig(n.Log, keystoreDB, keystore.Config{
+		HashParams:         n.Config.KeystoreHashParams,
+		UserQuota:          n.Config.KeystoreUserQuota,
+		TOTPUnlockDuration: n.Config.KeystoreTOTPUnlockDuration,
+	})
 	keystoreHandler, err := n.keystore.CreateHandler()
 	if err != nil {
 		return err
@@ -790,15 +1020,12 @@ func (n *Node) initKeystoreAPI() error {
 		LockOptions: common.NoLock,
 		Handler:     keystoreHandler,
 	}
-	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "keystore", "", n.HTTPLog)
+	return n.addAPIRoute(keystoreAPIToggle, handler, "keystore", "")
 }
 
 // initMetricsAPI initializes the Metrics API
 // Assumes n.APIServer is already set
 func (n *Node) initMetricsAPI() error {
-	n.MetricsRegisterer = prometheus.NewRegistry()
-	n.MetricsGatherer = metrics.NewMultiGatherer()
-
 	if !n.Config.MetricsAPIEnabled {
 		n.Log.Info("skipping metrics API initialization because it has been disabled")
 		return nil
@@ -816,7 +1043,8 @@ func (n *Node) initMetricsAPI() error {
 	}
 	n.DBManager = meterDBManager
 
-	return n.APIServer.AddRoute(
+	return n.addAPIRoute(
+		metricsAPIToggle,
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler: promhttp.HandlerFor(
@@ -824,10 +1052,8 @@ func (n *Node) initMetricsAPI() error {
 				promhttp.HandlerOpts{},
 			),
 		},
-		&sync.RWMutex{},
 		"metrics",
 		"",
-		n.HTTPLog,
 	)
 }
 
@@ -853,12 +1079,66 @@ func (n *Node) initAdminAPI() error {
 			ProfileDir:   n.Config.ProfilerConfig.Dir,
 			LogFactory:   n.LogFactory,
 			NodeConfig:   n.Config,
+			Network:      n.Net,
+			ChainAliases: aliasdb.New(n.chainManager, prefixdb.New(aliasDBPrefix, n.DB)),
+			Keystore:     n.keystore,
+			VMManager:    n.Config.VMManager,
+			VMRegistry:   n.vmRegistry,
+			PluginDir:    n.Config.PluginDir,
+			Supervisor:   n.Config.PluginSupervisorConfig,
+
+			ReloadNodeConfig: n.ReloadConfig,
+			Backup:           n.backuper.Backup,
 		},
 	)
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
+	if err := n.addAPIRoute(adminAPIToggle, service, "admin", ""); err != nil {
+		return err
+	}
+	return n.addAPIRoute(
+		adminAPIToggle,
+		&common.HTTPHandler{
+			LockOptions: common.NoLock,
+			Handler:     admin.NewProfileHandler(),
+		},
+		"admin",
+		"/pprof",
+	)
+}
+
+// initBackuper initializes the backups of the node
+func (n *Node) initBackuper() {
+	n.backuper = backup.NewBackuper(n.Config.BackupConfig, n.Log, n.backupState)
+	if n.Config.BackupConfig.Freq <= 0 {
+		return
+	}
+
+	n.Log.Info("backing up the node every %s", n.Config.BackupConfig.Freq)
+	go n.Log.RecoverAndPanic(func() {
+		if err := n.backuper.Dispatch(); err != nil {
+			n.Log.Error("backuper failed with %s", err)
+		}
+	})
+}
+
+// backupState returns the state of the node that is backed up
+func (n *Node) backupState() (backup.State, error) {
+	aliases, err := aliasdb.New(n.chainManager, prefixdb.New(aliasDBPrefix, n.DB)).PersistedAliases()
+	if err != nil {
+		return backup.State{}, fmt.Errorf("couldn't get chain aliases: %w", err)
+	}
+	return backup.State{
+		NodeID:          n.ID,
+		NetworkID:       n.Config.NetworkID,
+		StakingKeyPath:  n.Config.StakingKeyPath,
+		StakingCertPath: n.Config.StakingCertPath,
+		Config:          n.Config,
+		ChainAliases:    aliases,
+		Database:        n.DB,
+		DatabaseVersion: n.DBManager.Current().Version,
+	}, nil
 }
 
 // initProfiler initializes the continuous profiling
@@ -901,6 +1181,8 @@ func (n *Node) initInfoAPI() error {
 			CreateAssetTxFee:      n.Config.CreateAssetTxFee,
 			CreateSubnetTxFee:     n.Config.CreateSubnetTxFee,
 			CreateBlockchainTxFee: n.Config.CreateBlockchainTxFee,
+			DatabaseName:          n.Config.DatabaseConfig.Name,
+			DatabaseConfig:        n.Config.DatabaseConfig.Config,
 		},
 		n.Log,
 		n.chainManager,
@@ -908,11 +1190,37 @@ func (n *Node) initInfoAPI() error {
 		n.Net,
 		version.NewDefaultApplicationParser(),
 		primaryValidators,
+		n.Config.PortMapper,
 	)
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "info", "", n.HTTPLog)
+	return n.addAPIRoute(infoAPIToggle, service, "info", "")
+}
+
+// initCrashReporter includes the state of the primary network's chains in
+// crash reports, if crash reporting is enabled.
+// Assumes n.chainManager is initialized.
+func (n *Node) initCrashReporter() {
+	reporter, ok := n.Config.LoggingConfig.PanicHandler.(*crashreport.Reporter)
+	if !ok {
+		return
+	}
+	reporter.SetChainStates(func() map[string]string {
+		states := make(map[string]string, 3)
+		for _, alias := range []string{"P", "X", "C"} {
+			chainID, err := n.chainManager.Lookup(alias)
+			switch {
+			case err != nil:
+				states[alias] = "not created"
+			case n.chainManager.IsBootstrapped(chainID):
+				states[alias] = "bootstrapped"
+			default:
+				states[alias] = "bootstrapping"
+			}
+		}
+		return states
+	})
 }
 
 // initHealthAPI initializes the Health API service
@@ -978,6 +1286,35 @@ func (n *Node) initHealthAPI() error {
 		return fmt.Errorf("couldn't register bootstrapped health check: %w", err)
 	}
 
+	// Passes if the database can be read from, and can keep being written to
+	databaseCheck := health.CheckerFunc(func() (interface{}, error) {
+		if _, err := n.DB.Has(genesisHashKey); err != nil {
+			return nil, err
+		}
+		return n.dbHealthChecker.HealthCheck()
+	})
+
+	err = healthChecker.RegisterReadinessCheck("database", databaseCheck)
+	if err != nil {
+		return fmt.Errorf("couldn't register database readiness check: %w", err)
+	}
+
+	err = healthChecker.RegisterHealthCheck("database", databaseCheck)
+	if err != nil {
+		return fmt.Errorf("couldn't register database health check: %w", err)
+	}
+
+	// Passes as long as the node is able to run its health checks
+	startTime := time.Now()
+	err = healthChecker.RegisterLivenessCheck("process", health.CheckerFunc(func() (interface{}, error) {
+		return map[string]interface{}{
+			"uptime": time.Since(startTime).Round(time.Second).String(),
+		}, nil
+	}))
+	if err != nil {
+		return fmt.Errorf("couldn't register process liveness check: %w", err)
+	}
+
 	err = healthChecker.RegisterHealthCheck("network", n.Net)
 	if err != nil {
 		return fmt.Errorf("couldn't register network health check: %w", err)
@@ -993,57 +1330,53 @@ func (n *Node) initHealthAPI() error {
 		return err
 	}
 
-	err = n.APIServer.AddRoute(
+	err = n.addAPIRoute(
+		healthAPIToggle,
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     handler,
 		},
-		&sync.RWMutex{},
 		"health",
 		"",
-		n.HTTPLog,
 	)
 	if err != nil {
 		return err
 	}
 
-	err = n.APIServer.AddRoute(
+	err = n.addAPIRoute(
+		healthAPIToggle,
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Readiness),
 		},
-		&sync.RWMutex{},
 		"health",
 		"/readiness",
-		n.HTTPLog,
 	)
 	if err != nil {
 		return err
 	}
 
-	err = n.APIServer.AddRoute(
+	err = n.addAPIRoute(
+		healthAPIToggle,
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Health),
 		},
-		&sync.RWMutex{},
 		"health",
 		"/health",
-		n.HTTPLog,
 	)
 	if err != nil {
 		return err
 	}
 
-	return n.APIServer.AddRoute(
+	return n.addAPIRoute(
+		healthAPIToggle,
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Liveness),
 		},
-		&sync.RWMutex{},
 		"health",
 		"/liveness",
-		n.HTTPLog,
 	)
 }
 
@@ -1059,7 +1392,50 @@ func (n *Node) initIPCAPI() error {
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "ipcs", "", n.HTTPLog)
+	return n.addAPIRoute(ipcAPIToggle, service, "ipcs", "")
+}
+
+// initEventsAPI initializes the events API, which publishes the containers
+// decided by the chains over WebSocket
+// Assumes n.DecisionDispatcher and n.chainManager already initialized
+func (n *Node) initEventsAPI() error {
+	if !n.Config.EventsAPIEnabled {
+		n.Log.Info("skipping events API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing events API")
+	server := events.NewServer(events.Config{
+		Log:    n.Log,
+		Chains: n.chainManager,
+	})
+	if err := n.DecisionDispatcher.Subscribe("events", server); err != nil {
+		return err
+	}
+	n.chainManager.AddRegistrant(server)
+	return n.addAPIRoute(
+		eventsAPIToggle,
+		&common.HTTPHandler{LockOptions: common.NoLock, Handler: server},
+		"events",
+		"",
+	)
+}
+
+// initGraphQLAPI initializes the GraphQL API, which serves the queries by
+// calling the other APIs of the node in process
+// Assumes n.APIServer is already initialized
+func (n *Node) initGraphQLAPI() error {
+	if !n.Config.GraphQLAPIEnabled {
+		n.Log.Info("skipping GraphQL API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing GraphQL API")
+	server := graphql.NewServer(n.Log, graphql.NewHandlerCaller(n.APIServer.Handler()))
+	return n.addAPIRoute(
+		graphQLAPIToggle,
+		&common.HTTPHandler{LockOptions: common.NoLock, Handler: server},
+		"graphql",
+		"",
+	)
 }
 
 // Give chains aliases as specified by the genesis information
@@ -1105,6 +1481,9 @@ func (n *Node) Initialize(
 ) error {
 	n.Log = logger
 	n.Config = config
+	// The node fills in [config] as it initializes, so the config is copied
+	// before it's modified
+	n.loadedConfig = *config
 	var err error
 	n.ID, err = ids.ToShortID(hashing.PubkeyBytesToAddress(n.Config.StakingTLSCert.Leaf.Raw))
 	if err != nil {
@@ -1129,6 +1508,9 @@ func (n *Node) Initialize(
 	if err = n.initBeacons(); err != nil { // Configure the beacons
 		return fmt.Errorf("problem initializing node beacons: %w", err)
 	}
+	n.MetricsRegisterer = prometheus.NewRegistry()
+	n.MetricsGatherer = metrics.NewMultiGatherer()
+
 	// Start HTTP APIs
 	if err := n.initAPIServer(); err != nil { // Start the API Server
 		return fmt.Errorf("couldn't initialize API server: %w", err)
@@ -1149,8 +1531,17 @@ func (n *Node) Initialize(
 	// and the engine (initChains) but after the metrics (initMetricsAPI)
 	// message.Creator currently record metrics under network namespace
 	n.networkNamespace = "network"
-	if n.msgCreator, err = message.NewCreator(n.MetricsRegisterer,
-		n.Config.NetworkConfig.CompressionEnabled,
+	compressionType := compression.TypeNone
+	if n.Config.NetworkConfig.CompressionEnabled {
+		compressionType = n.Config.NetworkConfig.CompressionType
+	}
+	newCreator := message.NewCreatorWithCompressionType
+	if n.Config.NetworkConfig.ProtoMessagesEnabled {
+		newCreator = message.NewProtoCreatorWithCompressionType
+	}
+	if n.msgCreator, err = newCreator(n.MetricsRegisterer,
+		compressionType,
+		n.Config.NetworkConfig.CompressionThreshold,
 		n.networkNamespace); err != nil {
 		return fmt.Errorf("problem TheOneCreator: %w", err)
 	}
@@ -1174,6 +1565,8 @@ func (n *Node) Initialize(
 	if err := n.initChainManager(n.Config.AvaxAssetID); err != nil { // Set up the chain manager
 		return fmt.Errorf("couldn't initialize chain manager: %w", err)
 	}
+	n.initCrashReporter()
+	n.initBackuper()
 	if err := n.initAdminAPI(); err != nil { // Start the Admin API
 		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
@@ -1186,12 +1579,21 @@ func (n *Node) Initialize(
 	if err := n.initIPCAPI(); err != nil { // Start the IPC API
 		return fmt.Errorf("couldn't initialize the IPC API: %w", err)
 	}
+	if err := n.initEventsAPI(); err != nil { // Start the events API
+		return fmt.Errorf("couldn't initialize the events API: %w", err)
+	}
+	if err := n.initGraphQLAPI(); err != nil { // Start the GraphQL API
+		return fmt.Errorf("couldn't initialize the GraphQL API: %w", err)
+	}
 	if err := n.initChainAliases(n.Config.GenesisBytes); err != nil {
 		return fmt.Errorf("couldn't initialize chain aliases: %w", err)
 	}
 	if err := n.initAPIAliases(n.Config.GenesisBytes); err != nil {
 		return fmt.Errorf("couldn't initialize API aliases: %w", err)
 	}
+	if err := admin.RestoreChainAliases(n.Log, aliasdb.New(n.chainManager, prefixdb.New(aliasDBPrefix, n.DB)), &n.APIServer); err != nil {
+		return fmt.Errorf("couldn't restore chain aliases: %w", err)
+	}
 	if err := n.initIndexer(); err != nil {
 		return fmt.Errorf("couldn't initialize indexer: %w", err)
 	}
@@ -1233,30 +1635,51 @@ func (n *Node) shutdown() {
 		time.Sleep(n.Config.ShutdownWait)
 	}
 
+	// Stop accepting new work before the chains finish their in-flight
+	// operations, so that no new work reaches them while they drain
+	n.Log.Info("shutdown: stopping the API server and the network")
+	if err := n.APIServer.Shutdown(); err != nil {
+		n.Log.Debug("error during API shutdown: %s", err)
+	}
 	if n.IPCs != nil {
 		if err := n.IPCs.Shutdown(); err != nil {
 			n.Log.Debug("error during IPC shutdown: %s", err)
 		}
 	}
+	if n.Net != nil {
+		// Close already logs its own error if one occurs, so the error is ignored here
+		_ = n.Net.Close()
+	}
+
+	n.Log.Info("shutdown: waiting for the chains to finish their in-flight operations")
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
 	if n.profiler != nil {
 		n.profiler.Shutdown()
 	}
-	if n.Net != nil {
-		// Close already logs its own error if one occurs, so the error is ignored here
-		_ = n.Net.Close()
+	if n.backuper != nil {
+		n.backuper.Shutdown()
 	}
-	if err := n.APIServer.Shutdown(); err != nil {
-		n.Log.Debug("error during API shutdown: %s", err)
+
+	n.Log.Info("shutdown: flushing state to the database")
+	if n.peerStore != nil {
+		if err := n.peerStore.Close(); err != nil {
+			n.Log.Debug("error persisting peers: %s", err)
+		}
 	}
 	if err := n.indexer.Close(); err != nil {
 		n.Log.Debug("error closing tx indexer: %w", err)
 	}
+	if n.deferredDB != nil {
+		// Persist the writes that were deferred while bootstrapping
+		if err := n.deferredDB.Stop(); err != nil {
+			n.Log.Error("error flushing deferred database writes: %s", err)
+		}
+	}
 
 	// Make sure all plugin subprocesses are killed
-	n.Log.Info("cleaning up plugin subprocesses")
+	n.Log.Info("shutdown: cleaning up plugin subprocesses")
 	plugin.CleanupClients()
 	n.DoneShuttingDown.Done()
 	n.Log.Info("finished node shutdown")
@@ -0,0 +1,336 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+// Config fields that enable the APIs that can be toggled at runtime
+const (
+	adminAPIToggle    = "httpConfig.apiConfig.adminAPIEnabled"
+	infoAPIToggle     = "httpConfig.apiConfig.infoAPIEnabled"
+	keystoreAPIToggle = "httpConfig.apiConfig.keystoreAPIEnabled"
+	metricsAPIToggle  = "httpConfig.apiConfig.metricsAPIEnabled"
+	healthAPIToggle   = "httpConfig.apiConfig.healthAPIEnabled"
+	eventsAPIToggle   = "httpConfig.apiConfig.eventsAPIEnabled"
+	graphQLAPIToggle  = "httpConfig.apiConfig.graphQLAPIEnabled"
+	ipcAPIToggle      = "httpConfig.apiConfig.ipcConfig.ipcAPIEnabled"
+)
+
+var (
+	errConfigNotReloadable = errors.New("the node's config can't be reloaded")
+
+	// reloaders apply the changes of the fields of a reloaded config that can
+	// be changed without a restart
+	reloaders = []reloader{
+		{
+			fields: []string{
+				"loggingConfig.logLevel",
+				"loggingConfig.displayLevel",
+			},
+			reload: reloadLogLevels,
+		},
+		{
+			fields: []string{
+				"networkConfig.gossipConfig.adaptiveGossipMinSize",
+				"networkConfig.gossipConfig.adaptiveGossipMaxSize",
+				"networkConfig.gossipConfig.adaptiveGossipRedundancyHalflife",
+				"networkConfig.gossipConfig.adaptiveGossipRedundancyThreshold",
+			},
+			reload: reloadGossipConfig,
+		},
+		{
+			fields: []string{
+				"networkConfig.throttlerConfig.inboundMsgThrottlerConfig.maxProcessingMsgsPerNode",
+				"networkConfig.throttlerConfig.inboundMsgThrottlerConfig.bandwidthRefillRate",
+				"networkConfig.throttlerConfig.inboundMsgThrottlerConfig.bandwidthMaxBurstRate",
+			},
+			reload: reloadInboundMsgThrottlerConfig,
+		},
+		{
+			fields: []string{
+				"whitelistedSubnets",
+				"networkConfig.whitelistedSubnets",
+			},
+			reload: reloadWhitelistedSubnets,
+		},
+		apiReloader(adminAPIToggle, func(c *Config) *bool { return &c.AdminAPIEnabled }),
+		apiReloader(infoAPIToggle, func(c *Config) *bool { return &c.InfoAPIEnabled }),
+		apiReloader(keystoreAPIToggle, func(c *Config) *bool { return &c.KeystoreAPIEnabled }),
+		apiReloader(metricsAPIToggle, func(c *Config) *bool { return &c.MetricsAPIEnabled }),
+		apiReloader(healthAPIToggle, func(c *Config) *bool { return &c.HealthAPIEnabled }),
+		apiReloader(eventsAPIToggle, func(c *Config) *bool { return &c.EventsAPIEnabled }),
+		apiReloader(graphQLAPIToggle, func(c *Config) *bool { return &c.GraphQLAPIEnabled }),
+		apiReloader(ipcAPIToggle, func(c *Config) *bool { return &c.IPCAPIEnabled }),
+	}
+)
+
+// reloader applies the changes of some fields of a reloaded config
+type reloader struct {
+	// The fields of the config, as dotted JSON paths, that this reloader
+	// applies. The reloader is only called if one of them changed.
+	fields []string
+	// reload applies the changes from [loaded] to [config], then copies the
+	// applied fields to [loaded]. Returns false if the changes can only be
+	// applied by restarting the node.
+	reload func(n *Node, loaded, config *Config) (bool, error)
+}
+
+// ReloadConfig re-reads the node's config and applies the fields that changed
+// since the node started, or since the last reload, and that can be changed
+// without a restart. Returns the changed fields that were applied and the ones
+// that require a restart, as dotted JSON paths of the config.
+func (n *Node) ReloadConfig() ([]string, []string, error) {
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	if n.Config.LoadConfig == nil {
+		return nil, nil, errConfigNotReloadable
+	}
+
+	n.Log.Info("reloading the node's config")
+	config, err := n.Config.LoadConfig()
+	if err != nil {
+		n.Log.Error("couldn't reload the node's config: %s", err)
+		return nil, nil, fmt.Errorf("couldn't load config: %w", err)
+	}
+
+	changed, err := changedFields(&n.loadedConfig, &config)
+	if err != nil {
+		return nil, nil, err
+	}
+	isChanged := make(map[string]bool, len(changed))
+	for _, field := range changed {
+		isChanged[field] = true
+	}
+
+	isApplied := make(map[string]bool)
+	errs := wrappers.Errs{}
+	for _, r := range reloaders {
+		var fields []string
+		for _, field := range r.fields {
+			if isChanged[field] {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		applied, err := r.reload(n, &n.loadedConfig, &config)
+		if err != nil {
+			n.Log.Error("couldn't reload %v: %s", fields, err)
+			errs.Add(fmt.Errorf("couldn't reload %v: %w", fields, err))
+			continue
+		}
+		if applied {
+			for _, field := range fields {
+				isApplied[field] = true
+			}
+		}
+	}
+
+	applied := []string{}
+	requireRestart := []string{}
+	for _, field := range changed {
+		if isApplied[field] {
+			applied = append(applied, field)
+		} else {
+			requireRestart = append(requireRestart, field)
+		}
+	}
+	n.Log.Info("reloaded the node's config. Applied: %v. Require a restart: %v", applied, requireRestart)
+	return applied, requireRestart, errs.Err
+}
+
+func reloadLogLevels(n *Node, loaded, config *Config) (bool, error) {
+	for _, name := range n.LogFactory.GetLoggerNames() {
+		if config.LoggingConfig.LogLevel != loaded.LoggingConfig.LogLevel {
+			if err := n.LogFactory.SetLogLevel(name, config.LoggingConfig.LogLevel); err != nil {
+				return false, err
+			}
+		}
+		if config.LoggingConfig.DisplayLevel != loaded.LoggingConfig.DisplayLevel {
+			if err := n.LogFactory.SetDisplayLevel(name, config.LoggingConfig.DisplayLevel); err != nil {
+				return false, err
+			}
+		}
+	}
+	loaded.LoggingConfig.LogLevel = config.LoggingConfig.LogLevel
+	loaded.LoggingConfig.DisplayLevel = config.LoggingConfig.DisplayLevel
+	return true, nil
+}
+
+func reloadGossipConfig(n *Node, loaded, config *Config) (bool, error) {
+	gossipConfig := loaded.NetworkConfig.GossipConfig
+	gossipConfig.AdaptiveGossipMinSize = config.NetworkConfig.AdaptiveGossipMinSize
+	gossipConfig.AdaptiveGossipMaxSize = config.NetworkConfig.AdaptiveGossipMaxSize
+	gossipConfig.AdaptiveGossipRedundancyHalflife = config.NetworkConfig.AdaptiveGossipRedundancyHalflife
+	gossipConfig.AdaptiveGossipRedundancyThreshold = config.NetworkConfig.AdaptiveGossipRedundancyThreshold
+
+	n.Net.SetGossipConfig(gossipConfig)
+	loaded.NetworkConfig.GossipConfig = gossipConfig
+	return true, nil
+}
+
+func reloadInboundMsgThrottlerConfig(n *Node, loaded, config *Config) (bool, error) {
+	throttlerConfig := loaded.NetworkConfig.ThrottlerConfig.InboundMsgThrottlerConfig
+	newConfig := config.NetworkConfig.ThrottlerConfig.InboundMsgThrottlerConfig
+	throttlerConfig.MaxProcessingMsgsPerNode = newConfig.MaxProcessingMsgsPerNode
+	throttlerConfig.RefillRate = newConfig.RefillRate
+	throttlerConfig.MaxBurstSize = newConfig.MaxBurstSize
+
+	n.Net.SetInboundMsgThrottlerConfig(throttlerConfig)
+	loaded.NetworkConfig.ThrottlerConfig.InboundMsgThrottlerConfig = throttlerConfig
+	return true, nil
+}
+
+func reloadWhitelistedSubnets(n *Node, loaded, config *Config) (bool, error) {
+	// The loaded set is shared with the network's config, so it's replaced
+	// rather than modified
+	tracked := ids.NewSet(loaded.WhitelistedSubnets.Len())
+	tracked.Union(loaded.WhitelistedSubnets)
+	defer func() {
+		loaded.WhitelistedSubnets = tracked
+		loaded.NetworkConfig.WhitelistedSubnets = tracked
+	}()
+
+	for subnetID := range loaded.WhitelistedSubnets {
+		if config.WhitelistedSubnets.Contains(subnetID) {
+			continue
+		}
+		if err := n.chainManager.UntrackSubnet(subnetID); err != nil {
+			return false, fmt.Errorf("couldn't untrack subnet %s: %w", subnetID, err)
+		}
+		tracked.Remove(subnetID)
+	}
+	for subnetID := range config.WhitelistedSubnets {
+		if tracked.Contains(subnetID) {
+			continue
+		}
+		if err := n.chainManager.TrackSubnet(subnetID); err != nil {
+			return false, fmt.Errorf("couldn't track subnet %s: %w", subnetID, err)
+		}
+		tracked.Add(subnetID)
+	}
+	return true, nil
+}
+
+// apiReloader returns the reloader of the field [key] of the config, which
+// enables the API of the toggle with the same key. The API can only be enabled
+// without a restart if it was enabled when the node started.
+func apiReloader(key string, field func(*Config) *bool) reloader {
+	return reloader{
+		fields: []string{key},
+		reload: func(n *Node, loaded, config *Config) (bool, error) {
+			toggle, ok := n.apiToggles[key]
+			if !ok {
+				return false, nil
+			}
+			enabled := *field(config)
+			toggle.SetValue(enabled)
+			*field(loaded) = enabled
+			return true, nil
+		},
+	}
+}
+
+// addAPIRoute adds the route of an API that is enabled by the config field
+// [key], such that the API can be disabled and enabled again without a
+// restart. Requests made while the API is disabled fail.
+func (n *Node) addAPIRoute(key string, handler *common.HTTPHandler, base, endpoint string) error {
+	if n.apiToggles == nil {
+		n.apiToggles = make(map[string]*utils.AtomicBool)
+	}
+	enabled, ok := n.apiToggles[key]
+	if !ok {
+		enabled = &utils.AtomicBool{}
+		enabled.SetValue(true)
+		n.apiToggles[key] = enabled
+	}
+
+	inner := handler.Handler
+	return n.APIServer.AddRoute(
+		&common.HTTPHandler{
+			LockOptions: handler.LockOptions,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !enabled.GetValue() {
+					http.Error(w, "this API has been disabled", http.StatusServiceUnavailable)
+					return
+				}
+				inner.ServeHTTP(w, r)
+			}),
+		},
+		&sync.RWMutex{},
+		base,
+		endpoint,
+		n.HTTPLog,
+	)
+}
+
+// changedFields returns the sorted dotted JSON paths of the fields that differ
+// between [before] and [after]
+func changedFields(before, after *Config) ([]string, error) {
+	beforeFields, err := fieldsOf(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fieldsOf(after)
+	if err != nil {
+		return nil, err
+	}
+	changed := diffFields("", beforeFields, afterFields, nil)
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func fieldsOf(config *Config) (map[string]interface{}, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal config: %w", err)
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(configJSON, &fields); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal config: %w", err)
+	}
+	return fields, nil
+}
+
+// diffFields appends to [changed] the paths of the fields that differ between
+// the objects [before] and [after], with each path prefixed by [prefix]
+func diffFields(prefix string, before, after map[string]interface{}, changed []string) []string {
+	keys := make(map[string]struct{}, len(before))
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+
+	for key := range keys {
+		path := prefix + key
+		beforeValue, afterValue := before[key], after[key]
+		beforeObject, beforeIsObject := beforeValue.(map[string]interface{})
+		afterObject, afterIsObject := afterValue.(map[string]interface{})
+		switch {
+		case beforeIsObject && afterIsObject:
+			changed = diffFields(path+".", beforeObject, afterObject, changed)
+		case !reflect.DeepEqual(beforeValue, afterValue):
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
@@ -92,6 +92,11 @@ type Topological struct {
 	// We use this one instance instead of creating a new ids.UniqueBag
 	// during each call to [pushVotes].
 	votes ids.UniqueBag
+
+	// Used in [pushVotes]. Should only be accessed in that method.
+	// Indexes the txIDs whose conflicts are tracked during a poll, so that
+	// the conflicts can be stored as bitsets rather than as ids.Sets.
+	conflictIDs *ids.Universe
 }
 
 type kahnNode struct {
@@ -113,6 +118,7 @@ func (ta *Topological) Initialize(
 	ta.params = params
 	ta.leaves = ids.Set{}
 	ta.votes = ids.UniqueBag{}
+	ta.conflictIDs = ids.NewUniverse(minMapSize)
 	ta.kahnNodes = make(map[ids.ID]kahnNode)
 
 	if err := ta.Latency.Initialize("vtx", "vertex/vertices", ctx.Log, "", ctx.Registerer); err != nil {
@@ -395,7 +401,8 @@ func (ta *Topological) markAncestorInDegrees(
 // vertex ancestors.
 func (ta *Topological) pushVotes() (ids.Bag, error) {
 	ta.votes.Clear()
-	txConflicts := make(map[ids.ID]ids.Set, minMapSize)
+	ta.conflictIDs.Clear()
+	txConflicts := make(map[ids.ID]ids.DenseSet, minMapSize)
 
 	// A leaf is a node with no inbound edges. This removes each leaf and pushes
 	// the votes upwards, potentially creating new leaves, until there are no
@@ -424,7 +431,9 @@ func (ta *Topological) pushVotes() (ids.Bag, error) {
 
 				// Map txID to set of Conflicts
 				if _, exists := txConflicts[txID]; !exists {
-					txConflicts[txID] = ta.cg.Conflicts(tx)
+					conflicts := ids.NewDenseSet(ta.conflictIDs)
+					ta.cg.AddConflicts(tx, &conflicts)
+					txConflicts[txID] = conflicts
 				}
 			}
 
@@ -436,7 +445,9 @@ func (ta *Topological) pushVotes() (ids.Bag, error) {
 			// Map the vertexID to the set of conflicts from the transaction
 			// vertex.
 			if _, exists := txConflicts[leaf]; !exists {
-				txConflicts[leaf] = ta.cg.Conflicts(tv)
+				conflicts := ids.NewDenseSet(ta.conflictIDs)
+				ta.cg.AddConflicts(tv, &conflicts)
+				txConflicts[leaf] = conflicts
 			}
 
 			parents, err := vtx.Parents()
@@ -463,9 +474,9 @@ func (ta *Topological) pushVotes() (ids.Bag, error) {
 	// Create bag of votes for conflicting transactions
 	conflictingVotes := make(ids.UniqueBag)
 	for txID, conflicts := range txConflicts {
-		for conflictTxID := range conflicts {
+		conflicts.ForEach(func(conflictTxID ids.ID) {
 			conflictingVotes.UnionSet(txID, ta.votes.GetSet(conflictTxID))
-		}
+		})
 	}
 
 	ta.votes.Difference(&conflictingVotes)
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/choices"
+	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowball"
+	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowstorm"
+)
+
+// newConflictingTxs adds [numTxs] processing txs to a conflict graph. Each tx
+// conflicts with the other txs of its group of [groupSize] txs. Each tx
+// receives a vote from a different node.
+func newConflictingTxs(b *testing.B, numTxs, groupSize int) (snowstorm.Consensus, []snowstorm.Tx, ids.UniqueBag) {
+	cg := &snowstorm.Directed{}
+	params := snowball.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	if err := cg.Initialize(snow.DefaultConsensusContextTest(), params); err != nil {
+		b.Fatal(err)
+	}
+
+	txs := make([]snowstorm.Tx, numTxs)
+	votes := make(ids.UniqueBag)
+	for i := range txs {
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{ids.Empty.Prefix(uint64(i / groupSize))},
+		}
+		if err := cg.Add(tx); err != nil {
+			b.Fatal(err)
+		}
+		txs[i] = tx
+		votes.Add(uint(i), tx.ID())
+	}
+	return cg, txs, votes
+}
+
+// BenchmarkConflictingVotes compares counting the votes for conflicting txs,
+// as done at the end of pushVotes, with the conflicts of each tx stored in a
+// map based ids.Set and in a bitset based ids.DenseSet.
+func BenchmarkConflictingVotes(b *testing.B) {
+	const groupSize = 4
+	for _, numTxs := range []int{64, 1024} {
+		cg, txs, votes := newConflictingTxs(b, numTxs, groupSize)
+
+		b.Run(fmt.Sprintf("set/%d", numTxs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				txConflicts := make(map[ids.ID]ids.Set, minMapSize)
+				for _, tx := range txs {
+					txConflicts[tx.ID()] = cg.Conflicts(tx)
+				}
+
+				conflictingVotes := make(ids.UniqueBag)
+				for txID, conflicts := range txConflicts {
+					for conflictTxID := range conflicts {
+						conflictingVotes.UnionSet(txID, votes.GetSet(conflictTxID))
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("dense_set/%d", numTxs), func(b *testing.B) {
+			b.ReportAllocs()
+			universe := ids.NewUniverse(minMapSize)
+			for i := 0; i < b.N; i++ {
+				universe.Clear()
+				txConflicts := make(map[ids.ID]ids.DenseSet, minMapSize)
+				for _, tx := range txs {
+					conflicts := ids.NewDenseSet(universe)
+					cg.AddConflicts(tx, &conflicts)
+					txConflicts[tx.ID()] = conflicts
+				}
+
+				conflictingVotes := make(ids.UniqueBag)
+				for txID, conflicts := range txConflicts {
+					conflicts.ForEach(func(conflictTxID ids.ID) {
+						conflictingVotes.UnionSet(txID, votes.GetSet(conflictTxID))
+					})
+				}
+			}
+		})
+	}
+}
@@ -52,6 +52,10 @@ type Consensus interface {
 	// Returns the set of transactions conflicting with <Tx>
 	Conflicts(Tx) ids.Set
 
+	// Adds the transactions conflicting with <Tx> to <conflicts>. Unlike
+	// Conflicts, doesn't allocate a set of its own.
+	AddConflicts(tx Tx, conflicts *ids.DenseSet)
+
 	// Collects the results of a network poll. Assumes all transactions
 	// have been previously added. Returns true is any statuses or preferences
 	// changed. Returns if a critical error has occurred.
@@ -1353,13 +1353,30 @@ func ConflictsTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong number of conflicts")
 	} else if !orangeConflicts.Contains(purple.IDV) {
 		t.Fatalf("Conflicts does not contain the right transaction")
-	} else if err := graph.Add(orange); err != nil {
+	}
+
+	// [orange] isn't processing yet, so its conflicts are found through its
+	// inputs
+	addedConflicts := ids.NewDenseSet(ids.NewUniverse(0))
+	graph.AddConflicts(orange, &addedConflicts)
+	if !addedConflicts.Set().Equals(graph.Conflicts(orange)) {
+		t.Fatalf("AddConflicts added %s but the conflicts are %s", addedConflicts.String(), graph.Conflicts(orange))
+	}
+
+	if err := graph.Add(orange); err != nil {
 		t.Fatal(err)
 	} else if orangeConflicts := graph.Conflicts(orange); orangeConflicts.Len() != 1 {
 		t.Fatalf("Wrong number of conflicts")
 	} else if !orangeConflicts.Contains(purple.IDV) {
 		t.Fatalf("Conflicts does not contain the right transaction")
 	}
+
+	// Now that [orange] is processing, its conflicts are tracked by the graph
+	addedConflicts.Clear()
+	graph.AddConflicts(orange, &addedConflicts)
+	if !addedConflicts.Set().Equals(graph.Conflicts(orange)) {
+		t.Fatalf("AddConflicts added %s but the conflicts are %s", addedConflicts.String(), graph.Conflicts(orange))
+	}
 }
 
 func VirtuousDependsOnRogueTest(t *testing.T, factory Factory) {
@@ -249,6 +249,24 @@ func (dg *Directed) Conflicts(tx Tx) ids.Set {
 	return conflicts
 }
 
+// AddConflicts implements the Consensus interface
+func (dg *Directed) AddConflicts(tx Tx, conflicts *ids.DenseSet) {
+	if node, exists := dg.txs[tx.ID()]; exists {
+		for conflictID := range node.ins {
+			conflicts.Add(conflictID)
+		}
+		for conflictID := range node.outs {
+			conflicts.Add(conflictID)
+		}
+		return
+	}
+	for _, inputID := range tx.InputIDs() {
+		for conflictID := range dg.utxos[inputID] {
+			conflicts.Add(conflictID)
+		}
+	}
+}
+
 // Add implements the Consensus interface
 func (dg *Directed) Add(tx Tx) error {
 	if shouldVote, err := dg.shouldVote(tx); !shouldVote || err != nil {
@@ -4,6 +4,7 @@
 package snow
 
 import (
+	"context"
 	"crypto"
 	"crypto/x509"
 	"sync"
@@ -53,7 +54,7 @@ type Context struct {
 	AVAXAssetID ids.ID
 
 	Log          logging.Logger
-	Lock         sync.RWMutex
+	Lock         ContextLock
 	Keystore     keystore.BlockchainKeystore
 	SharedMemory atomic.SharedMemory
 	BCLookup     ids.AliaserReader
@@ -64,6 +65,9 @@ type Context struct {
 	ValidatorState    validators.State  // interface for P-Chain validators
 	StakingLeafSigner crypto.Signer     // block signer
 	StakingCertLeaf   *x509.Certificate // block certificate
+
+	// Request the chain is processing, if any
+	request utils.AtomicInterface
 }
 
 type ConsensusContext struct {
@@ -82,6 +86,13 @@ type ConsensusContext struct {
 
 	// Indicates this chain is available to only validators.
 	validatorOnly utils.AtomicBool
+
+	// shutdownLock must be held when accessing [shutdownCtx] or
+	// [cancelShutdown].
+	shutdownLock sync.Mutex
+	// Cancelled once this chain starts shutting down. Created on first use.
+	shutdownCtx    context.Context
+	cancelShutdown context.CancelFunc
 }
 
 func (ctx *ConsensusContext) SetState(newState State) {
@@ -121,6 +132,33 @@ func (ctx *ConsensusContext) SetValidatorOnly() {
 	ctx.validatorOnly.SetValue(true)
 }
 
+// ShutdownContext returns a context that is cancelled once this chain starts
+// shutting down. Long running work done on behalf of this chain should stop
+// once the context is done.
+func (ctx *ConsensusContext) ShutdownContext() context.Context {
+	ctx.shutdownLock.Lock()
+	defer ctx.shutdownLock.Unlock()
+
+	ctx.initShutdownContext()
+	return ctx.shutdownCtx
+}
+
+// StartShutdown cancels the context returned by ShutdownContext
+func (ctx *ConsensusContext) StartShutdown() {
+	ctx.shutdownLock.Lock()
+	defer ctx.shutdownLock.Unlock()
+
+	ctx.initShutdownContext()
+	ctx.cancelShutdown()
+}
+
+// assumes [ctx.shutdownLock] is held
+func (ctx *ConsensusContext) initShutdownContext() {
+	if ctx.shutdownCtx == nil {
+		ctx.shutdownCtx, ctx.cancelShutdown = context.WithCancel(context.Background())
+	}
+}
+
 func DefaultContextTest() *Context {
 	return &Context{
 		NetworkID: 0,
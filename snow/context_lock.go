@@ -0,0 +1,201 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+// LockCaller identifies the kind of work that is acquiring a chain's context
+// lock.
+type LockCaller uint8
+
+const (
+	OtherCaller LockCaller = iota
+	EngineCaller
+	APICaller
+	GossipCaller
+
+	numLockCallers
+)
+
+func (c LockCaller) String() string {
+	switch c {
+	case EngineCaller:
+		return "engine"
+	case APICaller:
+		return "api"
+	case GossipCaller:
+		return "gossip"
+	default:
+		return "other"
+	}
+}
+
+const (
+	writeMode = iota
+	readMode
+
+	numLockModes
+)
+
+var lockModes = [numLockModes]string{
+	writeMode: "write",
+	readMode:  "read",
+}
+
+// ContextLock is the lock that serializes access to a chain's VM and consensus
+// engine.
+//
+// The zero value is an uninstrumented read/write mutex. After InitMetrics is
+// called, the time spent waiting for the lock and the time the lock is held
+// are reported per caller, so it is possible to tell whether, for example, API
+// calls are slow because the engine is holding the lock.
+//
+// Lock, Unlock, RLock and RUnlock attribute the acquisition to OtherCaller. Use
+// As to attribute it to a specific caller.
+type ContextLock struct {
+	lock    sync.RWMutex
+	metrics *contextLockMetrics
+
+	// Only accessed while [lock] is held for writing.
+	writeCaller LockCaller
+	writeStart  time.Time
+
+	// Times at which the read lock was acquired, per caller, oldest first.
+	// Since read locks are not tied to a specific acquisition, the hold time of
+	// a read lock is attributed to the oldest outstanding acquisition of the
+	// same caller.
+	readStartsLock sync.Mutex
+	readStarts     [numLockCallers][]time.Time
+}
+
+type contextLockMetrics struct {
+	wait [numLockCallers][numLockModes]prometheus.Observer
+	hold [numLockCallers][numLockModes]prometheus.Observer
+}
+
+// InitMetrics registers the lock's metrics with [reg] and enables reporting.
+// It must be called before the lock is shared with other goroutines.
+func (l *ContextLock) InitMetrics(namespace string, reg prometheus.Registerer) error {
+	// Buckets range from 1us to ~4s.
+	buckets := prometheus.ExponentialBuckets(float64(time.Microsecond), 4, 12)
+	wait := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "wait",
+			Help:      "time (in ns) spent waiting to acquire the context lock",
+			Buckets:   buckets,
+		},
+		[]string{"caller", "mode"},
+	)
+	hold := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "hold",
+			Help:      "time (in ns) the context lock was held",
+			Buckets:   buckets,
+		},
+		[]string{"caller", "mode"},
+	)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(wait),
+		reg.Register(hold),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+
+	m := &contextLockMetrics{}
+	for caller := LockCaller(0); caller < numLockCallers; caller++ {
+		for mode, modeStr := range lockModes {
+			m.wait[caller][mode] = wait.WithLabelValues(caller.String(), modeStr)
+			m.hold[caller][mode] = hold.WithLabelValues(caller.String(), modeStr)
+		}
+	}
+	l.metrics = m
+	return nil
+}
+
+// As returns a view of this lock that attributes acquisitions to [caller].
+func (l *ContextLock) As(caller LockCaller) CallerLock {
+	return CallerLock{
+		lock:   l,
+		caller: caller,
+	}
+}
+
+func (l *ContextLock) Lock()    { l.lockAs(OtherCaller) }
+func (l *ContextLock) Unlock()  { l.unlock() }
+func (l *ContextLock) RLock()   { l.rLockAs(OtherCaller) }
+func (l *ContextLock) RUnlock() { l.rUnlockAs(OtherCaller) }
+
+func (l *ContextLock) lockAs(caller LockCaller) {
+	if l.metrics == nil {
+		l.lock.Lock()
+		return
+	}
+
+	start := time.Now()
+	l.lock.Lock()
+	now := time.Now()
+	l.metrics.wait[caller][writeMode].Observe(float64(now.Sub(start)))
+	l.writeCaller = caller
+	l.writeStart = now
+}
+
+func (l *ContextLock) unlock() {
+	if l.metrics != nil {
+		l.metrics.hold[l.writeCaller][writeMode].Observe(float64(time.Since(l.writeStart)))
+	}
+	l.lock.Unlock()
+}
+
+func (l *ContextLock) rLockAs(caller LockCaller) {
+	if l.metrics == nil {
+		l.lock.RLock()
+		return
+	}
+
+	start := time.Now()
+	l.lock.RLock()
+	now := time.Now()
+	l.metrics.wait[caller][readMode].Observe(float64(now.Sub(start)))
+
+	l.readStartsLock.Lock()
+	l.readStarts[caller] = append(l.readStarts[caller], now)
+	l.readStartsLock.Unlock()
+}
+
+func (l *ContextLock) rUnlockAs(caller LockCaller) {
+	if l.metrics != nil {
+		l.readStartsLock.Lock()
+		starts := l.readStarts[caller]
+		if len(starts) > 0 {
+			l.metrics.hold[caller][readMode].Observe(float64(time.Since(starts[0])))
+			l.readStarts[caller] = starts[1:]
+		}
+		l.readStartsLock.Unlock()
+	}
+	l.lock.RUnlock()
+}
+
+// CallerLock is a view of a ContextLock that attributes acquisitions to a
+// specific caller.
+type CallerLock struct {
+	lock   *ContextLock
+	caller LockCaller
+}
+
+func (l CallerLock) Lock()    { l.lock.lockAs(l.caller) }
+func (l CallerLock) Unlock()  { l.lock.unlock() }
+func (l CallerLock) RLock()   { l.lock.rLockAs(l.caller) }
+func (l CallerLock) RUnlock() { l.lock.rUnlockAs(l.caller) }
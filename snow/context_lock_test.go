@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextLockUninstrumented(t *testing.T) {
+	var lock ContextLock
+
+	lock.Lock()
+	lock.Unlock()
+
+	apiLock := lock.As(APICaller)
+	apiLock.RLock()
+	lock.RLock()
+	lock.RUnlock()
+	apiLock.RUnlock()
+}
+
+func TestContextLockMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock ContextLock
+	reg := prometheus.NewRegistry()
+	assert.NoError(lock.InitMetrics("ctx_lock", reg))
+
+	engineLock := lock.As(EngineCaller)
+	engineLock.Lock()
+	engineLock.Unlock()
+
+	apiLock := lock.As(APICaller)
+	apiLock.RLock()
+	apiLock.RLock()
+	apiLock.RUnlock()
+	apiLock.RUnlock()
+
+	// Releasing a read lock without a matching attributed acquisition must not
+	// report a hold time.
+	lock.RLock()
+	apiLock.RUnlock()
+
+	counts := map[string]uint64{}
+	families, err := reg.Gather()
+	assert.NoError(err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			key := family.GetName() + "/" + labels["caller"] + "/" + labels["mode"]
+			counts[key] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	assert.EqualValues(1, counts["ctx_lock_wait/engine/write"])
+	assert.EqualValues(1, counts["ctx_lock_hold/engine/write"])
+	assert.EqualValues(2, counts["ctx_lock_wait/api/read"])
+	assert.EqualValues(2, counts["ctx_lock_hold/api/read"])
+	assert.EqualValues(1, counts["ctx_lock_wait/other/read"])
+	assert.EqualValues(0, counts["ctx_lock_hold/other/read"])
+	assert.EqualValues(0, counts["ctx_lock_wait/gossip/write"])
+}
+
+func TestContextLockDuplicateMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	var lock0, lock1 ContextLock
+	assert.NoError(t, lock0.InitMetrics("ctx_lock", reg))
+	assert.Error(t, lock1.InitMetrics("ctx_lock", reg))
+
+	// A lock whose metrics failed to register is still usable.
+	lock1.Lock()
+	lock1.Unlock()
+}
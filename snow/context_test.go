@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensusContextShutdownContext(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := DefaultConsensusContextTest()
+	shutdownCtx := ctx.ShutdownContext()
+	assert.NoError(shutdownCtx.Err())
+
+	ctx.StartShutdown()
+	assert.ErrorIs(shutdownCtx.Err(), context.Canceled)
+	// Contexts requested after the shutdown started are already done
+	assert.ErrorIs(ctx.ShutdownContext().Err(), context.Canceled)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/avalanche"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/avalanche/vertex"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
@@ -33,7 +34,8 @@ const (
 )
 
 var (
-	_ common.BootstrapableEngine = &bootstrapper{}
+	_ common.BootstrapableEngine   = &bootstrapper{}
+	_ validators.PeerInfoConnector = &bootstrapper{}
 
 	errUnexpectedTimeout = errors.New("unexpected timeout fired")
 )
@@ -75,8 +77,9 @@ func New(config Config, onFinished func(lastReqID uint32) error) (common.Bootstr
 	}
 
 	config.Config.Bootstrapable = b
-	b.Bootstrapper = common.NewCommonBootstrapper(config.Config)
-	return b, nil
+	var err error
+	b.Bootstrapper, err = common.NewCommonBootstrapper(config.Config)
+	return b, err
 }
 
 type bootstrapper struct {
@@ -112,6 +115,7 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, vtxs [][]byt
 	lenVtxs := len(vtxs)
 	if lenVtxs == 0 {
 		b.Ctx.Log.Debug("Ancestors(%s, %d) contains no vertices", vdr, requestID)
+		b.Config.PeerScorer.Observe(vdr, common.UselessResponse)
 		return b.GetAncestorsFailed(vdr, requestID)
 	}
 	if lenVtxs > b.Config.AncestorsMaxContainersReceived {
@@ -129,6 +133,7 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, vtxs [][]byt
 		}
 		b.Ctx.Log.Debug("failed to parse requested vertex %s: %s", requestedVtxID, err)
 		b.Ctx.Log.Verbo("vertex: %s", formatting.DumpBytes(vtxs[0]))
+		b.Config.PeerScorer.Observe(vdr, common.InvalidContainer)
 		return b.fetch(requestedVtxID)
 	}
 
@@ -136,6 +141,7 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, vtxs [][]byt
 	// If the vertex is neither the requested vertex nor a needed vertex, return early and re-fetch if necessary
 	if requested && requestedVtxID != vtxID {
 		b.Ctx.Log.Debug("received incorrect vertex from %s with vertexID %s", vdr, vtxID)
+		b.Config.PeerScorer.Observe(vdr, common.InvalidContainer)
 		return b.fetch(requestedVtxID)
 	}
 	if !requested && !b.OutstandingRequests.Contains(vtxID) && !b.needToFetch.Contains(vtxID) {
@@ -143,6 +149,8 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, vtxs [][]byt
 		return nil
 	}
 
+	b.Config.PeerScorer.Observe(vdr, common.ValidContainer)
+
 	// Do not remove from outstanding requests if this did not answer a specific outstanding request
 	// to ensure that real responses are not dropped in favor of potentially byzantine Ancestors messages that
 	// could force the node to bootstrap 1 vertex at a time.
@@ -200,7 +208,12 @@ func (b *bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) err
 
 // Connected implements the InternalHandler interface.
 func (b *bootstrapper) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
-	if err := b.VM.Connected(nodeID, nodeVersion); err != nil {
+	return b.ConnectedWithInfo(nodeID, validators.PeerInfo{Version: nodeVersion})
+}
+
+// ConnectedWithInfo implements the validators.PeerInfoConnector interface.
+func (b *bootstrapper) ConnectedWithInfo(nodeID ids.ShortID, peerInfo validators.PeerInfo) error {
+	if err := validators.Connect(b.VM, nodeID, peerInfo); err != nil {
 		return err
 	}
 
@@ -257,6 +270,7 @@ func (b *bootstrapper) IsBootstrapped() bool { return b.Ctx.IsBootstrapped() }
 func (b *bootstrapper) Start(startReqID uint32) error {
 	b.Ctx.Log.Info("Starting bootstrap...")
 	b.Ctx.SetState(snow.Bootstrapping)
+	b.Events.PublishState(b.Ctx, snow.Bootstrapping)
 	b.Config.SharedCfg.RequestID = startReqID
 
 	if b.WeightTracker.EnoughConnectedWeight() {
@@ -298,11 +312,10 @@ func (b *bootstrapper) fetch(vtxIDs ...ids.ID) error {
 			continue
 		}
 
-		validators, err := b.Config.Beacons.Sample(1) // validator to send request to
+		validatorID, err := b.Config.SampleFetchTarget() // validator to send request to
 		if err != nil {
 			return fmt.Errorf("dropping request for %s as there are no validators", vtxID)
 		}
-		validatorID := validators[0].ID()
 		b.Config.SharedCfg.RequestID++
 
 		b.OutstandingRequests.Add(validatorID, b.Config.SharedCfg.RequestID, vtxID)
@@ -474,6 +487,7 @@ func (b *bootstrapper) checkFinish() error {
 	} else {
 		b.Ctx.Log.Debug("bootstrapping fetched %d vertices. Executing transaction state transitions...", b.VtxBlocked.PendingJobs())
 	}
+	b.Events.PublishBootstrapPhase(b.Ctx, common.ExecutingContainers)
 
 	_, err := b.TxBlocked.ExecuteAll(b.Config.Ctx, b, b.Config.SharedCfg.Restarted, b.Ctx.DecisionDispatcher)
 	if err != nil || b.Halted() {
@@ -19,6 +19,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/queue"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 
 	avagetter "github.com/Toinounet21/avalanchego-mod/snow/engine/avalanche/getter"
 )
@@ -76,6 +77,8 @@ func newConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *vertex.T
 		Timer:                          &common.TimerTest{},
 		AncestorsMaxContainersSent:     2000,
 		AncestorsMaxContainersReceived: 2000,
+		PeerScorer:                     &common.PeerScorerTest{},
+		Events:                         common.NewEventBus(logging.NoLog{}),
 		SharedCfg:                      &common.SharedConfig{},
 	}
 
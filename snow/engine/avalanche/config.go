@@ -22,4 +22,11 @@ type Config struct {
 
 	Params    avalanche.Parameters
 	Consensus avalanche.Consensus
+
+	// Publishes the state changes of this chain
+	Events common.Publisher
+
+	// Selects the validators polled by queries. If nil, they're sampled with
+	// a local source of randomness.
+	QuerySampler common.QuerySampler
 }
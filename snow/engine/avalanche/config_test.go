@@ -35,6 +35,7 @@ func DefaultConfig() (common.Config, bootstrap.Config, Config) {
 		Manager:    bootstrapConfig.Manager,
 		Sender:     bootstrapConfig.Sender,
 		Validators: bootstrapConfig.Validators,
+		Events:     bootstrapConfig.Events,
 		Params: avalanche.Parameters{
 			Parameters: snowball.Parameters{
 				K:                     1,
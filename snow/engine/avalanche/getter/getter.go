@@ -80,7 +80,9 @@ func (gh *getter) GetAncestors(validatorID ids.ShortID, requestID uint32, vtxID
 	visited := ids.Set{}                                                   // IDs of vertices that have been in queue before
 	visited.Add(vertex.ID())
 
-	for len(ancestorsBytes) < gh.cfg.AncestorsMaxContainersSent && len(queue) > 0 && time.Since(startTime) < gh.cfg.MaxTimeGetAncestors {
+	// Stop early if the chain starts shutting down
+	ctx := gh.cfg.Ctx.ShutdownContext()
+	for len(ancestorsBytes) < gh.cfg.AncestorsMaxContainersSent && len(queue) > 0 && time.Since(startTime) < gh.cfg.MaxTimeGetAncestors && ctx.Err() == nil {
 		var vtx avalanche.Vertex
 		vtx, queue = queue[0], queue[1:] // pop
 		vtxBytes := vtx.Bytes()
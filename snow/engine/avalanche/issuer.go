@@ -89,7 +89,7 @@ func (i *issuer) Update() {
 
 	// Issue a poll for this vertex.
 	p := i.t.Consensus.Parameters()
-	vdrs, err := i.t.Validators.Sample(p.K) // Validators to sample
+	vdrs, err := i.t.QuerySampler.Sample(i.t.Validators, p.K, i.t.RequestID+1) // Validators to sample
 
 	vdrBag := ids.ShortBag{} // Validators to sample repr. as a set
 	for _, vdr := range vdrs {
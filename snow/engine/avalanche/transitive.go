@@ -15,13 +15,17 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/avalanche/vertex"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/events"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
 	"github.com/Toinounet21/avalanchego-mod/utils/sampler"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
-var _ Engine = &Transitive{}
+var (
+	_ Engine                       = &Transitive{}
+	_ validators.PeerInfoConnector = &Transitive{}
+)
 
 func New(config Config) (Engine, error) {
 	return newTransitive(config)
@@ -70,6 +74,9 @@ type Transitive struct {
 func newTransitive(config Config) (*Transitive, error) {
 	config.Ctx.Log.Info("initializing consensus engine")
 
+	if config.QuerySampler == nil {
+		config.QuerySampler = common.NewRandomQuerySampler()
+	}
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t := &Transitive{
 		Config:                  config,
@@ -227,6 +234,11 @@ func (t *Transitive) Connected(nodeID ids.ShortID, nodeVersion version.Applicati
 	return t.VM.Connected(nodeID, nodeVersion)
 }
 
+// ConnectedWithInfo implements the validators.PeerInfoConnector interface.
+func (t *Transitive) ConnectedWithInfo(nodeID ids.ShortID, peerInfo validators.PeerInfo) error {
+	return validators.Connect(t.VM, nodeID, peerInfo)
+}
+
 // Disconnected implements the InternalHandler interface.
 func (t *Transitive) Disconnected(nodeID ids.ShortID) error {
 	return t.VM.Disconnected(nodeID)
@@ -317,6 +329,7 @@ func (t *Transitive) Start(startReqID uint32) error {
 	t.metrics.bootstrapFinished.Set(1)
 
 	t.Ctx.SetState(snow.NormalOp)
+	t.Config.Events.PublishState(t.Ctx, snow.NormalOp)
 	return t.Consensus.Initialize(t.Ctx, t.Params, frontier)
 }
 
@@ -570,8 +583,8 @@ func (t *Transitive) issueRepoll() {
 	}
 
 	vtxID := preferredIDs.CappedList(1)[0]
-	vdrs, err := t.Validators.Sample(t.Params.K) // Validators to sample
-	vdrBag := ids.ShortBag{}                     // IDs of validators to be sampled
+	vdrs, err := t.QuerySampler.Sample(t.Validators, t.Params.K, t.RequestID+1) // Validators to sample
+	vdrBag := ids.ShortBag{}                                                    // IDs of validators to be sampled
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
 	}
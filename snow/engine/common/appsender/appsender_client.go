@@ -5,16 +5,24 @@ package appsender
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/appsender/appsenderproto"
 )
 
-var _ common.AppSender = &Client{}
+var (
+	// ErrGossipValidatorsUnsupported is returned by SendAppGossipValidators
+	// when the node doesn't support gossiping to validators
+	ErrGossipValidatorsUnsupported = errors.New("the node doesn't support gossiping to validators")
+
+	_ common.AppSender = &Client{}
+)
 
 type Client struct {
-	client appsenderproto.AppSenderClient
+	client             appsenderproto.AppSenderClient
+	noGossipValidators bool
 }
 
 // NewClient returns a client that is connected to a remote AppSender.
@@ -80,3 +88,22 @@ func (c *Client) SendAppGossipSpecific(nodeIDs ids.ShortSet, msg []byte) error {
 	)
 	return err
 }
+
+// DisableGossipValidators makes SendAppGossipValidators fail with
+// ErrGossipValidatorsUnsupported, for nodes that predate it.
+func (c *Client) DisableGossipValidators() { c.noGossipValidators = true }
+
+func (c *Client) SendAppGossipValidators(subnetID ids.ID, numValidators int, msg []byte) error {
+	if c.noGossipValidators {
+		return ErrGossipValidatorsUnsupported
+	}
+	_, err := c.client.SendAppGossipValidators(
+		context.Background(),
+		&appsenderproto.SendAppGossipValidatorsMsg{
+			SubnetID:      subnetID[:],
+			NumValidators: uint32(numValidators),
+			Msg:           msg,
+		},
+	)
+	return err
+}
@@ -63,3 +63,12 @@ func (s *Server) SendAppGossipSpecific(_ context.Context, req *appsenderproto.Se
 	err := s.appSender.SendAppGossipSpecific(nodeIDs, req.Msg)
 	return &emptypb.Empty{}, err
 }
+
+func (s *Server) SendAppGossipValidators(_ context.Context, req *appsenderproto.SendAppGossipValidatorsMsg) (*emptypb.Empty, error) {
+	subnetID, err := ids.ToID(req.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.appSender.SendAppGossipValidators(subnetID, int(req.NumValidators), req.Msg)
+	return &emptypb.Empty{}, err
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package appsender
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/appsender/appsenderproto"
+)
+
+const (
+	bufSize = 1024 * 1024
+)
+
+func setupAppSender(t *testing.T, sender common.AppSender) (*Client, func()) {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	appsenderproto.RegisterAppSenderServer(server, NewServer(sender))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Logf("Server exited with error: %v", err)
+		}
+	}()
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	assert.NoError(t, err)
+
+	client := NewClient(appsenderproto.NewAppSenderClient(conn))
+	return client, func() {
+		server.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	}
+}
+
+// Messages sent by the plugin are passed through to the node's sender
+func TestAppSenderPassthrough(t *testing.T) {
+	assert := assert.New(t)
+
+	sender := &common.SenderTest{T: t}
+	sender.Default(true)
+	client, cleanup := setupAppSender(t, sender)
+	defer cleanup()
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeIDs := ids.ShortSet{}
+	nodeIDs.Add(nodeID0, nodeID1)
+	msg := []byte("message")
+
+	called := false
+	sender.SendAppRequestF = func(gotNodeIDs ids.ShortSet, requestID uint32, request []byte) error {
+		called = true
+		assert.Equal(nodeIDs, gotNodeIDs)
+		assert.EqualValues(1, requestID)
+		assert.Equal(msg, request)
+		return nil
+	}
+	assert.NoError(client.SendAppRequest(nodeIDs, 1, msg))
+	assert.True(called)
+
+	called = false
+	sender.SendAppResponseF = func(nodeID ids.ShortID, requestID uint32, response []byte) error {
+		called = true
+		assert.Equal(nodeID0, nodeID)
+		assert.EqualValues(2, requestID)
+		assert.Equal(msg, response)
+		return nil
+	}
+	assert.NoError(client.SendAppResponse(nodeID0, 2, msg))
+	assert.True(called)
+
+	called = false
+	sender.SendAppGossipF = func(gossip []byte) error {
+		called = true
+		assert.Equal(msg, gossip)
+		return nil
+	}
+	assert.NoError(client.SendAppGossip(msg))
+	assert.True(called)
+
+	called = false
+	sender.SendAppGossipSpecificF = func(gotNodeIDs ids.ShortSet, gossip []byte) error {
+		called = true
+		assert.Equal(nodeIDs, gotNodeIDs)
+		assert.Equal(msg, gossip)
+		return nil
+	}
+	assert.NoError(client.SendAppGossipSpecific(nodeIDs, msg))
+	assert.True(called)
+
+	subnetID := ids.GenerateTestID()
+	called = false
+	sender.SendAppGossipValidatorsF = func(gotSubnetID ids.ID, numValidators int, gossip []byte) error {
+		called = true
+		assert.Equal(subnetID, gotSubnetID)
+		assert.Equal(3, numValidators)
+		assert.Equal(msg, gossip)
+		return nil
+	}
+	assert.NoError(client.SendAppGossipValidators(subnetID, 3, msg))
+	assert.True(called)
+}
+
+// Errors of the node's sender are returned to the plugin
+func TestAppSenderError(t *testing.T) {
+	sender := &common.SenderTest{T: t}
+	sender.Default(false)
+	client, cleanup := setupAppSender(t, sender)
+	defer cleanup()
+
+	assert.Error(t, client.SendAppGossip([]byte("message")))
+}
+
+// Gossiping to validators fails without contacting a node that predates it
+func TestAppSenderGossipValidatorsDisabled(t *testing.T) {
+	sender := &common.SenderTest{T: t}
+	sender.Default(true)
+	client, cleanup := setupAppSender(t, sender)
+	defer cleanup()
+
+	client.DisableGossipValidators()
+	err := client.SendAppGossipValidators(ids.GenerateTestID(), 1, []byte("message"))
+	assert.ErrorIs(t, err, ErrGossipValidatorsUnsupported)
+}
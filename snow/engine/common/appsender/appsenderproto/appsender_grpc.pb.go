@@ -23,6 +23,7 @@ type AppSenderClient interface {
 	SendAppResponse(ctx context.Context, in *SendAppResponseMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SendAppGossip(ctx context.Context, in *SendAppGossipMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SendAppGossipSpecific(ctx context.Context, in *SendAppGossipSpecificMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SendAppGossipValidators(ctx context.Context, in *SendAppGossipValidatorsMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type appSenderClient struct {
@@ -69,6 +70,15 @@ func (c *appSenderClient) SendAppGossipSpecific(ctx context.Context, in *SendApp
 	return out, nil
 }
 
+func (c *appSenderClient) SendAppGossipValidators(ctx context.Context, in *SendAppGossipValidatorsMsg, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/appsenderproto.AppSender/SendAppGossipValidators", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AppSenderServer is the server API for AppSender service.
 // All implementations must embed UnimplementedAppSenderServer
 // for forward compatibility
@@ -77,6 +87,7 @@ type AppSenderServer interface {
 	SendAppResponse(context.Context, *SendAppResponseMsg) (*emptypb.Empty, error)
 	SendAppGossip(context.Context, *SendAppGossipMsg) (*emptypb.Empty, error)
 	SendAppGossipSpecific(context.Context, *SendAppGossipSpecificMsg) (*emptypb.Empty, error)
+	SendAppGossipValidators(context.Context, *SendAppGossipValidatorsMsg) (*emptypb.Empty, error)
 	mustEmbedUnimplementedAppSenderServer()
 }
 
@@ -96,6 +107,9 @@ func (UnimplementedAppSenderServer) SendAppGossip(context.Context, *SendAppGossi
 func (UnimplementedAppSenderServer) SendAppGossipSpecific(context.Context, *SendAppGossipSpecificMsg) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendAppGossipSpecific not implemented")
 }
+func (UnimplementedAppSenderServer) SendAppGossipValidators(context.Context, *SendAppGossipValidatorsMsg) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppGossipValidators not implemented")
+}
 func (UnimplementedAppSenderServer) mustEmbedUnimplementedAppSenderServer() {}
 
 // UnsafeAppSenderServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +195,24 @@ func _AppSender_SendAppGossipSpecific_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AppSender_SendAppGossipValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppGossipValidatorsMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppSenderServer).SendAppGossipValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appsenderproto.AppSender/SendAppGossipValidators",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppSenderServer).SendAppGossipValidators(ctx, req.(*SendAppGossipValidatorsMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AppSender_ServiceDesc is the grpc.ServiceDesc for AppSender service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -204,6 +236,10 @@ var AppSender_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendAppGossipSpecific",
 			Handler:    _AppSender_SendAppGossipSpecific_Handler,
 		},
+		{
+			MethodName: "SendAppGossipValidators",
+			Handler:    _AppSender_SendAppGossipValidators_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "appsender.proto",
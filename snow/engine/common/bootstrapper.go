@@ -8,8 +8,11 @@ import (
 
 	stdmath "math"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
 	"github.com/Toinounet21/avalanchego-mod/utils/math"
 )
 
@@ -30,6 +33,10 @@ const (
 	// MaxTimeFetchingAncestors is the maximum amount of time to spend fetching
 	// vertices during a call to GetAncestors
 	MaxTimeFetchingAncestors = 50 * time.Millisecond
+
+	// MaxFetchCandidates is the number of beacons sampled when choosing which
+	// beacon to send a GetAncestors to. The best scored candidate is chosen.
+	MaxFetchCandidates = 3
 )
 
 var _ Bootstrapper = &bootstrapper{}
@@ -59,6 +66,8 @@ type bootstrapper struct {
 	failedAcceptedFrontier ids.ShortSet
 	// IDs of all the returned accepted frontiers
 	acceptedFrontierSet ids.Set
+	// Validator ID --> The accepted frontier it returned
+	acceptedFrontiers map[ids.ShortID][]ids.ID
 
 	// IDs of validators we should request filtering the accepted frontier from
 	pendingSendAccepted ids.ShortSet
@@ -75,12 +84,22 @@ type bootstrapper struct {
 
 	// number of times the bootstrap has been attempted
 	bootstrapAttempts int
+
+	// Number of times the sampled beacons disagreed about the accepted
+	// frontier
+	numFrontierDisagreements prometheus.Counter
 }
 
-func NewCommonBootstrapper(config Config) Bootstrapper {
-	return &bootstrapper{
+func NewCommonBootstrapper(config Config) (Bootstrapper, error) {
+	b := &bootstrapper{
 		Config: config,
+		numFrontierDisagreements: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "bs",
+			Name:      "frontier_disagreements",
+			Help:      "Number of times the sampled beacons disagreed about the accepted frontier",
+		}),
 	}
+	return b, config.Ctx.Registerer.Register(b.numFrontierDisagreements)
 }
 
 // AcceptedFrontier implements the AcceptedFrontierHandler interface.
@@ -104,6 +123,9 @@ func (b *bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 
 	// Union the reported accepted frontier from [validatorID] with the accepted frontier we got from others
 	b.acceptedFrontierSet.Add(containerIDs...)
+	if !b.failedAcceptedFrontier.Contains(validatorID) {
+		b.acceptedFrontiers[validatorID] = containerIDs
+	}
 
 	b.sendGetAcceptedFrontiers()
 
@@ -113,6 +135,8 @@ func (b *bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 	}
 
 	// We've received the accepted frontier from every bootstrap validator
+	b.diagnoseFrontiers()
+
 	// Ask each bootstrap validator to filter the list of containers that we were
 	// told are on the accepted frontier such that the list only contains containers
 	// they think are accepted
@@ -144,6 +168,7 @@ func (b *bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 	b.Config.SharedCfg.RequestID++
 	b.acceptedFrontier = b.acceptedFrontierSet.List()
 
+	b.Events.PublishBootstrapPhase(b.Ctx, FetchingAccepted)
 	b.sendGetAccepted()
 	return nil
 }
@@ -237,6 +262,7 @@ func (b *bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 		b.Ctx.Log.Debug("Bootstrapping started syncing with %d vertices in the accepted frontier", size)
 	}
 
+	b.Events.PublishBootstrapPhase(b.Ctx, FetchingContainers)
 	return b.Bootstrapable.ForceAccepted(accepted)
 }
 
@@ -278,6 +304,7 @@ func (b *bootstrapper) Startup() error {
 	b.pendingReceiveAcceptedFrontier.Clear()
 	b.failedAcceptedFrontier.Clear()
 	b.acceptedFrontierSet.Clear()
+	b.acceptedFrontiers = make(map[ids.ShortID][]ids.ID)
 
 	b.pendingSendAccepted.Clear()
 	for _, vdr := range b.Beacons.List() {
@@ -292,10 +319,12 @@ func (b *bootstrapper) Startup() error {
 	b.bootstrapAttempts++
 	if b.pendingSendAcceptedFrontier.Len() == 0 {
 		b.Ctx.Log.Info("Bootstrapping skipped due to no provided bootstraps")
+		b.Events.PublishBootstrapPhase(b.Ctx, FetchingContainers)
 		return b.Bootstrapable.ForceAccepted(nil)
 	}
 
 	b.Config.SharedCfg.RequestID++
+	b.Events.PublishBootstrapPhase(b.Ctx, FetchingFrontier)
 	b.sendGetAcceptedFrontiers()
 	return nil
 }
@@ -318,6 +347,33 @@ func (b *bootstrapper) Restart(reset bool) error {
 	return b.Startup()
 }
 
+// diagnoseFrontiers reports a FrontierDiagnostic if the sampled beacons
+// disagreed about the accepted frontier by more than
+// [FrontierDisagreementThreshold]
+func (b *bootstrapper) diagnoseFrontiers() {
+	diagnostic := newFrontierDiagnostic(b.acceptedFrontiers, func(nodeID ids.ShortID) uint64 {
+		weight, _ := b.Beacons.GetWeight(nodeID)
+		return weight
+	})
+	if float64(diagnostic.DisagreementPortion) <= FrontierDisagreementThreshold {
+		return
+	}
+	diagnostic.Time = time.Now()
+	diagnostic.BootstrapAttempt = json.Uint32(b.bootstrapAttempts)
+	diagnostic.FailedNodeIDs = b.failedAcceptedFrontier.List()
+	ids.SortShortIDs(diagnostic.FailedNodeIDs)
+
+	b.numFrontierDisagreements.Inc()
+	b.Ctx.Log.Warn("sampled beacons disagree about the accepted frontier - %d distinct frontiers - disagreeing stake portion: %f - bootstrap attempt: %d",
+		len(diagnostic.Claims), diagnostic.DisagreementPortion, b.bootstrapAttempts)
+	for _, claim := range diagnostic.Claims {
+		b.Ctx.Log.Debug("beacons %v with weight %d reported the accepted frontier %v", claim.NodeIDs, claim.Weight, claim.Frontier)
+	}
+	if b.OnFrontierDisagreement != nil {
+		b.OnFrontierDisagreement(diagnostic)
+	}
+}
+
 // Ask up to [MaxOutstandingBootstrapRequests] bootstrap validators to send
 // their accepted frontier with the current accepted frontier
 func (b *bootstrapper) sendGetAcceptedFrontiers() {
@@ -4,12 +4,16 @@
 package common
 
 import (
+	"errors"
 	"time"
 
+	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 )
 
+var errNoBeacons = errors.New("no beacons to sample from")
+
 // Config wraps the common configurations that are needed by a Snow consensus
 // engine
 type Config struct {
@@ -42,9 +46,43 @@ type Config struct {
 	// containers in an ancestors message it receives.
 	AncestorsMaxContainersReceived int
 
+	// Tracks how useful each peer has been. Used to select which peers to
+	// fetch containers from.
+	PeerScorer PeerScorer
+
+	// Called when the sampled beacons disagree about the accepted frontier
+	// by more than [FrontierDisagreementThreshold]. May be nil.
+	OnFrontierDisagreement func(FrontierDiagnostic)
+
+	// Publishes the bootstrap phases and the state changes of this chain
+	Events Publisher
+
 	SharedCfg *SharedConfig
 }
 
+// SampleFetchTarget returns the beacon that a GetAncestors should be sent to.
+// A few beacons are sampled, and the one that has been the most useful is
+// returned.
+func (c *Config) SampleFetchTarget() (ids.ShortID, error) {
+	numCandidates := c.Beacons.Len()
+	if numCandidates > MaxFetchCandidates {
+		numCandidates = MaxFetchCandidates
+	}
+	candidates, err := c.Beacons.Sample(numCandidates)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	candidateIDs := make([]ids.ShortID, len(candidates))
+	for i, candidate := range candidates {
+		candidateIDs[i] = candidate.ID()
+	}
+	nodeID, ok := c.PeerScorer.Best(candidateIDs)
+	if !ok {
+		return ids.ShortID{}, errNoBeacons
+	}
+	return nodeID, nil
+}
+
 // Context implements the Engine interface
 func (c *Config) Context() *snow.ConsensusContext { return c.Ctx }
 
@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+)
+
+var ErrConfigReloadNotSupported = errors.New("vm does not support reloading its config")
+
+// ConfigReloader is an optional interface that a VM can implement to allow a
+// subset of its chain config to be updated without restarting the chain.
+type ConfigReloader interface {
+	// ReloadableConfigKeys returns the top-level keys of the chain config that
+	// can be changed while the chain is running.
+	ReloadableConfigKeys() ([]string, error)
+
+	// ReloadConfig is called with the reloadable keys whose values changed,
+	// mapped to their new JSON encoded values. A key that was removed from the
+	// config is mapped to an empty value, in which case the VM should fall back
+	// to its default.
+	ReloadConfig(changes map[string][]byte) error
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func TestSampleFetchTargetNoBeacons(t *testing.T) {
+	config := DefaultConfigTest()
+
+	_, err := config.SampleFetchTarget()
+	assert.Error(t, err)
+}
+
+func TestSampleFetchTargetPrefersBestScore(t *testing.T) {
+	config := DefaultConfigTest()
+
+	goodID := ids.GenerateTestShortID()
+	badID := ids.GenerateTestShortID()
+	assert.NoError(t, config.Beacons.AddWeight(goodID, 1))
+	assert.NoError(t, config.Beacons.AddWeight(badID, 1))
+
+	config.PeerScorer = &PeerScorerTest{
+		BestF: func(nodeIDs []ids.ShortID) (ids.ShortID, bool) {
+			assert.ElementsMatch(t, []ids.ShortID{goodID, badID}, nodeIDs)
+			return goodID, true
+		},
+	}
+
+	// Both beacons are always sampled, so the best scored one is chosen
+	for i := 0; i < 10; i++ {
+		nodeID, err := config.SampleFetchTarget()
+		assert.NoError(t, err)
+		assert.Equal(t, goodID, nodeID)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+var (
+	_ snow.EventDispatcher = &EventBus{}
+	_ Publisher            = &EventBus{}
+)
+
+// BootstrapPhase is a stage of bootstrapping a chain
+type BootstrapPhase uint8
+
+const (
+	// FetchingFrontier is the phase where the accepted frontiers of the
+	// beacons are requested
+	FetchingFrontier BootstrapPhase = iota
+	// FetchingAccepted is the phase where the beacons are asked which
+	// containers of the frontiers they accepted
+	FetchingAccepted
+	// FetchingContainers is the phase where the accepted containers and their
+	// ancestors are fetched
+	FetchingContainers
+	// ExecutingContainers is the phase where the fetched containers are
+	// executed
+	ExecutingContainers
+)
+
+func (p BootstrapPhase) String() string {
+	switch p {
+	case FetchingFrontier:
+		return "fetching frontier"
+	case FetchingAccepted:
+		return "fetching accepted"
+	case FetchingContainers:
+		return "fetching containers"
+	case ExecutingContainers:
+		return "executing containers"
+	default:
+		return "unknown phase"
+	}
+}
+
+// BootstrapPhaseSubscriber is notified when a chain enters a phase of
+// bootstrapping
+type BootstrapPhaseSubscriber interface {
+	BootstrapPhase(ctx *snow.ConsensusContext, phase BootstrapPhase) error
+}
+
+// StateSubscriber is notified when the engine of a chain changes state
+type StateSubscriber interface {
+	EngineState(ctx *snow.ConsensusContext, state snow.State) error
+}
+
+// Publisher is used by the engines to publish the progress of their chain
+type Publisher interface {
+	// PublishBootstrapPhase is called when the chain of [ctx] enters [phase]
+	PublishBootstrapPhase(ctx *snow.ConsensusContext, phase BootstrapPhase)
+
+	// PublishState is called when the engine of the chain of [ctx] moves to
+	// [state]
+	PublishState(ctx *snow.ConsensusContext, state snow.State)
+}
+
+type subscriber struct {
+	// Implements at least one of snow.Acceptor, snow.Rejector, snow.Issuer,
+	// BootstrapPhaseSubscriber and StateSubscriber
+	handler interface{}
+	// If true and [handler] returns an error during a call to Accept, the
+	// chain this subscriber is subscribed to will stop.
+	dieOnError bool
+}
+
+// EventBus publishes the containers issued, accepted and rejected by the
+// chains, the phases they go through while bootstrapping and the state
+// changes of their engines to in-process subscribers.
+type EventBus struct {
+	lock sync.Mutex
+	log  logging.Logger
+	// Chain ID --> Name --> subscriber of the chain
+	chainSubscribers map[ids.ID]map[string]subscriber
+	// Name --> subscriber of all chains
+	subscribers map[string]interface{}
+}
+
+// NewEventBus returns an EventBus without subscribers
+func NewEventBus(log logging.Logger) *EventBus {
+	return &EventBus{
+		log:              log,
+		chainSubscribers: make(map[ids.ID]map[string]subscriber),
+		subscribers:      make(map[string]interface{}),
+	}
+}
+
+// Accept is called when a transaction or block is accepted.
+// If the returned error is non-nil, the chain associated with [ctx] should shut
+// down and not commit [container] or any other container to its database as accepted.
+func (b *EventBus) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return b.publish(ctx, fmt.Sprintf("accepting %s", containerID), true, func(handler interface{}) error {
+		acceptor, ok := handler.(snow.Acceptor)
+		if !ok {
+			return nil
+		}
+		return acceptor.Accept(ctx, containerID, container)
+	})
+}
+
+// Reject is called when a transaction or block is rejected
+func (b *EventBus) Reject(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return b.publish(ctx, fmt.Sprintf("rejecting %s", containerID), false, func(handler interface{}) error {
+		rejector, ok := handler.(snow.Rejector)
+		if !ok {
+			return nil
+		}
+		return rejector.Reject(ctx, containerID, container)
+	})
+}
+
+// Issue is called when a transaction or block is issued
+func (b *EventBus) Issue(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return b.publish(ctx, fmt.Sprintf("issuing %s", containerID), false, func(handler interface{}) error {
+		issuer, ok := handler.(snow.Issuer)
+		if !ok {
+			return nil
+		}
+		return issuer.Issue(ctx, containerID, container)
+	})
+}
+
+// PublishBootstrapPhase implements the Publisher interface
+func (b *EventBus) PublishBootstrapPhase(ctx *snow.ConsensusContext, phase BootstrapPhase) {
+	_ = b.publish(ctx, fmt.Sprintf("entering bootstrap phase %q", phase), false, func(handler interface{}) error {
+		phaseSubscriber, ok := handler.(BootstrapPhaseSubscriber)
+		if !ok {
+			return nil
+		}
+		return phaseSubscriber.BootstrapPhase(ctx, phase)
+	})
+}
+
+// PublishState implements the Publisher interface
+func (b *EventBus) PublishState(ctx *snow.ConsensusContext, state snow.State) {
+	_ = b.publish(ctx, fmt.Sprintf("moving to %s", state), false, func(handler interface{}) error {
+		stateSubscriber, ok := handler.(StateSubscriber)
+		if !ok {
+			return nil
+		}
+		return stateSubscriber.EngineState(ctx, state)
+	})
+}
+
+// publish passes the subscribers of all chains, and then the subscribers of
+// the chain of [ctx], to [notify]. Errors are logged. If [fatal], the first
+// error of a chain subscriber that asked to die on error is returned.
+func (b *EventBus) publish(ctx *snow.ConsensusContext, event string, fatal bool, notify func(handler interface{}) error) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for name, handler := range b.subscribers {
+		if err := notify(handler); err != nil {
+			b.log.Error("subscriber %s on chain %s errored while %s: %s", name, ctx.ChainID, event, err)
+		}
+	}
+
+	for name, subscriber := range b.chainSubscribers[ctx.ChainID] {
+		if err := notify(subscriber.handler); err != nil {
+			b.log.Error("subscriber %s on chain %s errored while %s: %s", name, ctx.ChainID, event, err)
+			if fatal && subscriber.dieOnError {
+				return fmt.Errorf("subscriber %s on chain %s errored while %s: %w", name, ctx.ChainID, event, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SubscribeChain causes [handler] to be notified of the events of chain
+// [chainID].
+// [handler] should implement at least one of snow.Acceptor, snow.Rejector,
+// snow.Issuer, BootstrapPhaseSubscriber and StateSubscriber.
+// If [dieOnError], chain [chainID] stops if [handler].Accept is invoked and
+// returns a non-nil error.
+func (b *EventBus) SubscribeChain(chainID ids.ID, name string, handler interface{}, dieOnError bool) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	subscribers, exist := b.chainSubscribers[chainID]
+	if !exist {
+		subscribers = make(map[string]subscriber)
+		b.chainSubscribers[chainID] = subscribers
+	}
+
+	if _, ok := subscribers[name]; ok {
+		return fmt.Errorf("subscriber %s already exists on chain %s", name, chainID)
+	}
+
+	subscribers[name] = subscriber{
+		handler:    handler,
+		dieOnError: dieOnError,
+	}
+	return nil
+}
+
+// UnsubscribeChain removes a subscriber of chain [chainID]
+func (b *EventBus) UnsubscribeChain(chainID ids.ID, name string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	subscribers, exist := b.chainSubscribers[chainID]
+	if !exist {
+		return fmt.Errorf("chain %s has no subscribers", chainID)
+	}
+
+	if _, ok := subscribers[name]; !ok {
+		return fmt.Errorf("subscriber %s does not exist on chain %s", name, chainID)
+	}
+
+	if len(subscribers) == 1 {
+		delete(b.chainSubscribers, chainID)
+	} else {
+		delete(subscribers, name)
+	}
+	return nil
+}
+
+// Subscribe causes [handler] to be notified of the events of all chains.
+// [handler] should implement at least one of snow.Acceptor, snow.Rejector,
+// snow.Issuer, BootstrapPhaseSubscriber and StateSubscriber.
+func (b *EventBus) Subscribe(name string, handler interface{}) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, exist := b.subscribers[name]; exist {
+		return fmt.Errorf("subscriber %s already exists", name)
+	}
+
+	b.subscribers[name] = handler
+	return nil
+}
+
+// Unsubscribe removes a subscriber of all chains
+func (b *EventBus) Unsubscribe(name string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, exist := b.subscribers[name]; !exist {
+		return fmt.Errorf("subscriber %s does not exist", name)
+	}
+
+	delete(b.subscribers, name)
+	return nil
+}
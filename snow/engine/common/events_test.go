@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+)
+
+var errTestSubscriber = errors.New("subscriber failed")
+
+type testSubscriber struct {
+	accepted []ids.ID
+	phases   []BootstrapPhase
+	states   []snow.State
+	err      error
+}
+
+func (s *testSubscriber) Accept(_ *snow.ConsensusContext, containerID ids.ID, _ []byte) error {
+	s.accepted = append(s.accepted, containerID)
+	return s.err
+}
+
+func (s *testSubscriber) BootstrapPhase(_ *snow.ConsensusContext, phase BootstrapPhase) error {
+	s.phases = append(s.phases, phase)
+	return s.err
+}
+
+func (s *testSubscriber) EngineState(_ *snow.ConsensusContext, state snow.State) error {
+	s.states = append(s.states, state)
+	return s.err
+}
+
+func TestEventBusPublish(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewEventBus(logging.NoLog{})
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	otherCtx := snow.DefaultConsensusContextTest()
+	otherCtx.ChainID = ids.GenerateTestID()
+
+	global := &testSubscriber{}
+	chain := &testSubscriber{}
+	assert.NoError(bus.Subscribe("global", global))
+	assert.Error(bus.Subscribe("global", global))
+	assert.NoError(bus.SubscribeChain(ctx.ChainID, "chain", chain, false))
+	assert.Error(bus.SubscribeChain(ctx.ChainID, "chain", chain, false))
+
+	containerID := ids.GenerateTestID()
+	assert.NoError(bus.Accept(ctx, containerID, nil))
+	assert.NoError(bus.Accept(otherCtx, containerID, nil))
+	// Subscribers that don't implement snow.Rejector are skipped
+	assert.NoError(bus.Reject(ctx, containerID, nil))
+	bus.PublishBootstrapPhase(ctx, FetchingContainers)
+	bus.PublishState(ctx, snow.NormalOp)
+
+	assert.Equal([]ids.ID{containerID, containerID}, global.accepted)
+	assert.Equal([]ids.ID{containerID}, chain.accepted)
+	assert.Equal([]BootstrapPhase{FetchingContainers}, chain.phases)
+	assert.Equal([]snow.State{snow.NormalOp}, chain.states)
+
+	assert.NoError(bus.UnsubscribeChain(ctx.ChainID, "chain"))
+	assert.Error(bus.UnsubscribeChain(ctx.ChainID, "chain"))
+	assert.NoError(bus.Unsubscribe("global"))
+	assert.Error(bus.Unsubscribe("global"))
+
+	bus.PublishState(ctx, snow.Bootstrapping)
+	assert.Len(global.states, 1)
+	assert.Len(chain.states, 1)
+}
+
+func TestEventBusDieOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewEventBus(logging.NoLog{})
+	ctx := snow.DefaultConsensusContextTest()
+
+	// Errors of subscribers of all chains are only logged
+	assert.NoError(bus.Subscribe("global", &testSubscriber{err: errTestSubscriber}))
+	assert.NoError(bus.Accept(ctx, ids.GenerateTestID(), nil))
+
+	assert.NoError(bus.SubscribeChain(ctx.ChainID, "tolerant", &testSubscriber{err: errTestSubscriber}, false))
+	assert.NoError(bus.Accept(ctx, ids.GenerateTestID(), nil))
+
+	assert.NoError(bus.SubscribeChain(ctx.ChainID, "fatal", &testSubscriber{err: errTestSubscriber}, true))
+	err := bus.Accept(ctx, ids.GenerateTestID(), nil)
+	assert.ErrorIs(err, errTestSubscriber)
+
+	// Only accepting a container can stop the chain
+	bus.PublishBootstrapPhase(ctx, ExecutingContainers)
+	bus.PublishState(ctx, snow.NormalOp)
+}
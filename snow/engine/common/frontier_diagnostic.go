@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// FrontierDisagreementThreshold is the portion of the responding stake of the
+// sampled beacons that must not have reported the most reported accepted
+// frontier for a FrontierDiagnostic to be reported
+const FrontierDisagreementThreshold = 0.2
+
+// FrontierClaim is an accepted frontier that some sampled beacons reported
+type FrontierClaim struct {
+	// The reported accepted frontier, sorted
+	Frontier []ids.ID `json:"frontier"`
+	// The beacons that reported [Frontier], sorted
+	NodeIDs []ids.ShortID `json:"nodeIDs"`
+	// Total stake weight of [NodeIDs]
+	Weight json.Uint64 `json:"weight"`
+}
+
+// FrontierDiagnostic describes how the sampled beacons disagreed about the
+// accepted frontier during a bootstrap attempt
+type FrontierDiagnostic struct {
+	Time             time.Time   `json:"time"`
+	BootstrapAttempt json.Uint32 `json:"bootstrapAttempt"`
+	// Each distinct reported frontier, the most heavily weighted first
+	Claims []FrontierClaim `json:"claims"`
+	// Beacons that didn't report their accepted frontier
+	FailedNodeIDs []ids.ShortID `json:"failedNodeIDs"`
+	// Portion of the responding stake that didn't report the most heavily
+	// weighted frontier
+	DisagreementPortion json.Float64 `json:"disagreementPortion"`
+}
+
+// newFrontierDiagnostic groups the accepted frontiers in [frontiers], which
+// maps each beacon that responded to the frontier it reported, by frontier.
+// [weights] returns the stake weight of a beacon.
+func newFrontierDiagnostic(frontiers map[ids.ShortID][]ids.ID, weights func(ids.ShortID) uint64) FrontierDiagnostic {
+	// Sorted frontier bytes --> Beacons that reported that frontier
+	claims := make(map[string]*FrontierClaim)
+	totalWeight := uint64(0)
+	for nodeID, frontier := range frontiers {
+		sortedFrontier := make([]ids.ID, len(frontier))
+		copy(sortedFrontier, frontier)
+		ids.SortIDs(sortedFrontier)
+
+		key := make([]byte, 0, len(sortedFrontier)*len(ids.Empty))
+		for _, containerID := range sortedFrontier {
+			key = append(key, containerID[:]...)
+		}
+		claim, ok := claims[string(key)]
+		if !ok {
+			claim = &FrontierClaim{Frontier: sortedFrontier}
+			claims[string(key)] = claim
+		}
+		weight := weights(nodeID)
+		claim.NodeIDs = append(claim.NodeIDs, nodeID)
+		// The total weight of a validator set fits in a uint64
+		claim.Weight += json.Uint64(weight)
+		totalWeight += weight
+	}
+
+	diagnostic := FrontierDiagnostic{
+		Claims: make([]FrontierClaim, 0, len(claims)),
+	}
+	for _, claim := range claims {
+		ids.SortShortIDs(claim.NodeIDs)
+		diagnostic.Claims = append(diagnostic.Claims, *claim)
+	}
+	sort.Slice(diagnostic.Claims, func(i, j int) bool {
+		if diagnostic.Claims[i].Weight != diagnostic.Claims[j].Weight {
+			return diagnostic.Claims[i].Weight > diagnostic.Claims[j].Weight
+		}
+		return bytes.Compare(diagnostic.Claims[i].NodeIDs[0][:], diagnostic.Claims[j].NodeIDs[0][:]) < 0
+	})
+	if totalWeight > 0 {
+		agreeingWeight := uint64(diagnostic.Claims[0].Weight)
+		diagnostic.DisagreementPortion = json.Float64(float64(totalWeight-agreeingWeight) / float64(totalWeight))
+	}
+	return diagnostic
+}
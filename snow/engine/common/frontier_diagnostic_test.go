@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+func TestNewFrontierDiagnostic(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.ShortID{0}
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	weights := map[ids.ShortID]uint64{
+		vdr0: 1,
+		vdr1: 2,
+		vdr2: 1,
+	}
+	blkID0 := ids.ID{0}
+	blkID1 := ids.ID{1}
+
+	// [vdr0] and [vdr1] report the same frontier in a different order
+	diagnostic := newFrontierDiagnostic(
+		map[ids.ShortID][]ids.ID{
+			vdr0: {blkID1, blkID0},
+			vdr1: {blkID0, blkID1},
+			vdr2: {blkID0},
+		},
+		func(nodeID ids.ShortID) uint64 { return weights[nodeID] },
+	)
+	assert.Equal([]FrontierClaim{
+		{
+			Frontier: []ids.ID{blkID0, blkID1},
+			NodeIDs:  []ids.ShortID{vdr0, vdr1},
+			Weight:   3,
+		},
+		{
+			Frontier: []ids.ID{blkID0},
+			NodeIDs:  []ids.ShortID{vdr2},
+			Weight:   1,
+		},
+	}, diagnostic.Claims)
+	assert.Equal(json.Float64(0.25), diagnostic.DisagreementPortion)
+
+	// Beacons that agree don't disagree
+	diagnostic = newFrontierDiagnostic(
+		map[ids.ShortID][]ids.ID{
+			vdr0: {blkID0},
+			vdr2: {blkID0},
+		},
+		func(nodeID ids.ShortID) uint64 { return weights[nodeID] },
+	)
+	assert.Len(diagnostic.Claims, 1)
+	assert.Zero(diagnostic.DisagreementPortion)
+
+	// No beacon responded
+	diagnostic = newFrontierDiagnostic(nil, func(ids.ShortID) uint64 { return 0 })
+	assert.Empty(diagnostic.Claims)
+	assert.Zero(diagnostic.DisagreementPortion)
+}
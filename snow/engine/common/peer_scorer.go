@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+// PeerEvent is something a peer did that changes its score.
+type PeerEvent uint8
+
+const (
+	// ValidContainer is a container received from the peer that was valid.
+	ValidContainer PeerEvent = iota
+	// InvalidContainer is a container received from the peer that couldn't
+	// be parsed or wasn't the container that was requested.
+	InvalidContainer
+	// UselessResponse is a response from the peer that didn't contain
+	// anything that was requested, e.g. an empty Ancestors during bootstrap.
+	UselessResponse
+	// RequestTimeout is a request to the peer that wasn't responded to in
+	// time.
+	RequestTimeout
+	// HandshakeFailure is a handshake with the peer that failed.
+	HandshakeFailure
+)
+
+func (e PeerEvent) String() string {
+	switch e {
+	case ValidContainer:
+		return "valid_container"
+	case InvalidContainer:
+		return "invalid_container"
+	case UselessResponse:
+		return "useless_response"
+	case RequestTimeout:
+		return "timeout"
+	case HandshakeFailure:
+		return "handshake_failure"
+	default:
+		return "unknown"
+	}
+}
+
+// PeerScorer tracks how useful each peer has been. Scores start at 0, are
+// increased by useful behavior and decreased by useless or malicious
+// behavior, and decay back towards 0 over time.
+type PeerScorer interface {
+	// Observe records that [nodeID] caused [event].
+	Observe(nodeID ids.ShortID, event PeerEvent)
+
+	// Score returns the current score of [nodeID].
+	Score(nodeID ids.ShortID) float64
+
+	// IsPoor returns true if the score of [nodeID] is below the configured
+	// threshold. Requests to poor peers should be avoided and poor peers may
+	// be disconnected from.
+	IsPoor(nodeID ids.ShortID) bool
+
+	// Best returns the node in [nodeIDs] with the highest score. If multiple
+	// nodes have the highest score, the first one in [nodeIDs] is returned.
+	// Returns false if [nodeIDs] is empty.
+	Best(nodeIDs []ids.ShortID) (ids.ShortID, bool)
+}
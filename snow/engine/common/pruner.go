@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+)
+
+// Pruner is an optional interface that a VM can implement to allow nodes that
+// don't serve the history of the chain to delete it and reclaim its disk space.
+type Pruner interface {
+	// Prune deletes the historical data that was accepted before [cutoff] and
+	// that the VM no longer needs to verify new containers.
+	// Returns the number of containers whose data was deleted.
+	Prune(cutoff time.Time) (int, error)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+
+	avacrypto "github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+// vrfEpochLen is the number of consecutive request IDs whose samples are
+// seeded by the same VRF output. Computing the VRF takes a signature, so it's
+// computed once per epoch rather than once per query.
+const vrfEpochLen = 1024
+
+var (
+	_ QuerySampler = &randomQuerySampler{}
+	_ QuerySampler = &vrfQuerySampler{}
+)
+
+// QuerySampler selects the validators that are polled by a query
+type QuerySampler interface {
+	// Sample returns [k] validators, potentially with duplicates, from [vdrs]
+	// to poll in the query with ID [requestID]. The validators are sampled in
+	// proportion to their weight.
+	Sample(vdrs validators.Set, k int, requestID uint32) ([]validators.Validator, error)
+}
+
+type randomQuerySampler struct{}
+
+// NewRandomQuerySampler returns a QuerySampler that samples validators with a
+// local source of randomness
+func NewRandomQuerySampler() QuerySampler { return &randomQuerySampler{} }
+
+func (*randomQuerySampler) Sample(vdrs validators.Set, k int, _ uint32) ([]validators.Validator, error) {
+	return vdrs.Sample(k)
+}
+
+type vrfQuerySampler struct {
+	log     logging.Logger
+	signer  crypto.Signer
+	chainID ids.ID
+	// Makes the VRF inputs unique across restarts, since request IDs aren't
+	nonce [hashing.HashLen]byte
+
+	// VRF output of [epoch], if [hasEpoch]
+	hasEpoch bool
+	epoch    uint32
+	output   hashing.Hash256
+}
+
+// NewVRFQuerySampler returns a QuerySampler whose samples are seeded by the
+// output of a VRF under the key of [signer]. The request IDs are split into
+// epochs of [vrfEpochLen] requests, and the seed of each request is derived
+// from the VRF output of its epoch. Which validators are polled is
+// unpredictable to anyone that doesn't have the key, but every sample can be
+// audited: the input and proof of each epoch are logged, and anyone can check
+// the proof with the public key and reproduce the sample with vrfSeed and
+// validators.Set.SampleSeeded.
+func NewVRFQuerySampler(log logging.Logger, signer crypto.Signer, chainID ids.ID) (QuerySampler, error) {
+	s := &vrfQuerySampler{
+		log:     log,
+		signer:  signer,
+		chainID: chainID,
+	}
+	if _, err := rand.Read(s.nonce[:]); err != nil {
+		return nil, fmt.Errorf("couldn't generate VRF nonce: %w", err)
+	}
+	// Makes sure the key is supported before any query is sent
+	if err := s.setEpoch(0); err != nil {
+		return nil, fmt.Errorf("couldn't compute VRF: %w", err)
+	}
+	log.Info("sampling validators of chain %s with a VRF using nonce %x", chainID, s.nonce)
+	return s, nil
+}
+
+func (s *vrfQuerySampler) Sample(vdrs validators.Set, k int, requestID uint32) ([]validators.Validator, error) {
+	if epoch := requestID / vrfEpochLen; !s.hasEpoch || s.epoch != epoch {
+		if err := s.setEpoch(epoch); err != nil {
+			return nil, err
+		}
+	}
+	return vdrs.SampleSeeded(k, vrfSeed(s.output, requestID))
+}
+
+// setEpoch computes the VRF output of [epoch]
+func (s *vrfQuerySampler) setEpoch(epoch uint32) error {
+	input := s.input(epoch)
+	output, proof, err := avacrypto.VRFProve(s.signer, input)
+	if err != nil {
+		return err
+	}
+	s.log.Verbo("sampling validators for requests of epoch %d with VRF input %x and proof %x", epoch, input, proof)
+	s.hasEpoch = true
+	s.epoch = epoch
+	s.output = output
+	return nil
+}
+
+// input returns the VRF input of [epoch]
+func (s *vrfQuerySampler) input(epoch uint32) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, 2*hashing.HashLen+wrappers.IntLen)}
+	p.PackFixedBytes(s.chainID[:])
+	p.PackFixedBytes(s.nonce[:])
+	p.PackInt(epoch)
+	return p.Bytes
+}
+
+// vrfSeed returns the seed of the sample of the query with ID [requestID],
+// given the VRF [output] of the query's epoch
+func vrfSeed(output hashing.Hash256, requestID uint32) int64 {
+	p := wrappers.Packer{Bytes: make([]byte, hashing.HashLen+wrappers.IntLen)}
+	p.PackFixedBytes(output[:])
+	p.PackInt(requestID)
+	seed := hashing.ComputeHash256(p.Bytes)
+	return int64(binary.BigEndian.Uint64(seed))
+}
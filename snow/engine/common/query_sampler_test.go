@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+
+	avacrypto "github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+func TestVRFQuerySampler(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewSet()
+	for i := 0; i < 10; i++ {
+		assert.NoError(vdrs.AddWeight(ids.GenerateTestShortID(), 1))
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	s, err := NewVRFQuerySampler(logging.NoLog{}, key, chainID)
+	assert.NoError(err)
+
+	const requestID = vrfEpochLen + 5
+	sample, err := s.Sample(vdrs, 5, requestID)
+	assert.NoError(err)
+	assert.Len(sample, 5)
+
+	// Anyone with the public key can check the sample from the logged input
+	// and proof of its epoch
+	input := s.(*vrfQuerySampler).input(requestID / vrfEpochLen)
+	_, proof, err := avacrypto.VRFProve(key, input)
+	assert.NoError(err)
+	output, err := avacrypto.VRFVerify(&key.PublicKey, input, proof)
+	assert.NoError(err)
+
+	expectedSample, err := vdrs.SampleSeeded(5, vrfSeed(output, requestID))
+	assert.NoError(err)
+	assert.Equal(expectedSample, sample)
+
+	// The queries of an epoch share its VRF output, but not their seed
+	assert.NotEqual(vrfSeed(output, requestID), vrfSeed(output, requestID+1))
+
+	// Only RSA staking keys are supported
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	_, err = NewVRFQuerySampler(logging.NoLog{}, edKey, chainID)
+	assert.Error(err)
+}
+
+// countingSigner counts the signatures computed by its key
+type countingSigner struct {
+	*rsa.PrivateKey
+	numSigs int
+}
+
+func (s *countingSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.numSigs++
+	return s.PrivateKey.Sign(rand, digest, opts)
+}
+
+func TestVRFQuerySamplerSignsOncePerEpoch(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(ids.GenerateTestShortID(), 1))
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	signer := &countingSigner{PrivateKey: key}
+
+	s, err := NewVRFQuerySampler(logging.NoLog{}, signer, ids.GenerateTestID())
+	assert.NoError(err)
+	assert.Equal(1, signer.numSigs)
+
+	for requestID := uint32(0); requestID < vrfEpochLen; requestID++ {
+		_, err := s.Sample(vdrs, 1, requestID)
+		assert.NoError(err)
+	}
+	assert.Equal(1, signer.numSigs)
+
+	_, err = s.Sample(vdrs, 1, vrfEpochLen)
+	assert.NoError(err)
+	assert.Equal(2, signer.numSigs)
+}
@@ -23,22 +23,36 @@ const (
 	StatusUpdateFrequency = 2500
 )
 
+// Flusher persists writes that were deferred
+type Flusher interface {
+	Flush() error
+}
+
 // Jobs tracks a series of jobs that form a DAG of dependencies.
 type Jobs struct {
 	// db ensures that database updates are atomically updated.
 	db *versiondb.Database
 	// state writes the job queue to [db].
 	state *state
+
+	// If non-nil, [flusher] is flushed after every [flushFrequency] executed
+	// jobs.
+	flusher        Flusher
+	flushFrequency int
+
+	// If > 0, the jobs and missing IDs that were added more than [maxAge]
+	// before the parser is set are evicted.
+	maxAge time.Duration
 }
 
 // New attempts to create a new job queue from the provided database.
 func New(
 	db database.Database,
-	metricsNamespace string,
+	queueName string,
 	metricsRegisterer prometheus.Registerer,
 ) (*Jobs, error) {
 	vdb := versiondb.New(db)
-	state, err := newState(vdb, metricsNamespace, metricsRegisterer)
+	state, err := newState(vdb, queueName, metricsRegisterer)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create new jobs state: %w", err)
 	}
@@ -50,7 +64,26 @@ func New(
 }
 
 // SetParser tells this job queue how to parse jobs from the database.
-func (j *Jobs) SetParser(parser Parser) error { j.state.parser = parser; return nil }
+func (j *Jobs) SetParser(parser Parser) error {
+	j.state.parser = parser
+	if _, err := j.evictStaleJobs(); err != nil {
+		return err
+	}
+	return j.Commit()
+}
+
+// SetFlusher tells this job queue to flush [flusher] after every [frequency]
+// executed jobs and once all the runnable jobs have been executed.
+func (j *Jobs) SetFlusher(flusher Flusher, frequency int) {
+	j.flusher = flusher
+	j.flushFrequency = frequency
+}
+
+// SetMaxAge tells this job queue to evict, when its parser is set, the jobs
+// that have been blocked for longer than [maxAge]. Such jobs are typically left
+// over from a bootstrap attempt that was abandoned. If [maxAge] is 0, jobs are
+// never evicted.
+func (j *Jobs) SetMaxAge(maxAge time.Duration) { j.maxAge = maxAge }
 
 func (j *Jobs) Has(jobID ids.ID) (bool, error) { return j.state.HasJob(jobID) }
 
@@ -111,6 +144,13 @@ func (j *Jobs) ExecuteAll(ctx *snow.ConsensusContext, halter common.Haltable, re
 	j.state.DisableCaching()
 	for {
 		if halter.Halted() {
+			// Every executed job was committed, so flushing the deferred
+			// writes leaves the database consistent with the job queue.
+			if j.flusher != nil && numExecuted%j.flushFrequency != 0 {
+				if err := j.flusher.Flush(); err != nil {
+					return numExecuted, fmt.Errorf("failed to flush after interrupting execution due to %w", err)
+				}
+			}
 			ctx.Log.Info("Interrupted execution after executing %d operations", numExecuted)
 			return numExecuted, nil
 		}
@@ -143,6 +183,10 @@ func (j *Jobs) ExecuteAll(ctx *snow.ConsensusContext, halter common.Haltable, re
 
 		for _, dependentID := range dependentIDs {
 			job, err := j.state.GetJob(dependentID)
+			if err == database.ErrNotFound {
+				// The dependent was evicted
+				continue
+			}
 			if err != nil {
 				return 0, fmt.Errorf("failed to get job %s from blocking jobs due to %w", dependentID, err)
 			}
@@ -162,6 +206,11 @@ func (j *Jobs) ExecuteAll(ctx *snow.ConsensusContext, halter common.Haltable, re
 		}
 
 		numExecuted++
+		if j.flusher != nil && numExecuted%j.flushFrequency == 0 {
+			if err := j.flusher.Flush(); err != nil {
+				return 0, fmt.Errorf("failed to flush after executing %d jobs due to %w", numExecuted, err)
+			}
+		}
 		if numExecuted%StatusUpdateFrequency == 0 { // Periodically print progress
 			eta := timer.EstimateETA(
 				startTime,
@@ -177,6 +226,12 @@ func (j *Jobs) ExecuteAll(ctx *snow.ConsensusContext, halter common.Haltable, re
 		}
 	}
 
+	if j.flusher != nil && numExecuted%j.flushFrequency != 0 {
+		if err := j.flusher.Flush(); err != nil {
+			return 0, fmt.Errorf("failed to flush after executing %d jobs due to %w", numExecuted, err)
+		}
+	}
+
 	if !restarted {
 		ctx.Log.Info("executed %d operations", numExecuted)
 	} else {
@@ -190,6 +245,19 @@ func (j *Jobs) Commit() error {
 	return j.db.Commit()
 }
 
+// evictStaleJobs removes the jobs that have been blocked for longer than
+// [maxAge], and returns how many were removed
+func (j *Jobs) evictStaleJobs() (int, error) {
+	if j.maxAge <= 0 {
+		return 0, nil
+	}
+	numEvicted, err := j.state.RemoveStaleJobs(j.state.clock.Time().Add(-j.maxAge))
+	if err != nil {
+		return 0, fmt.Errorf("failed to evict stale jobs due to %w", err)
+	}
+	return numEvicted, nil
+}
+
 type JobsWithMissing struct {
 	*Jobs
 
@@ -201,10 +269,10 @@ type JobsWithMissing struct {
 
 func NewWithMissing(
 	db database.Database,
-	metricsNamespace string,
+	queueName string,
 	metricsRegisterer prometheus.Registerer,
 ) (*JobsWithMissing, error) {
-	innerJobs, err := New(db, metricsNamespace, metricsRegisterer)
+	innerJobs, err := New(db, queueName, metricsRegisterer)
 	if err != nil {
 		return nil, err
 	}
@@ -221,7 +289,16 @@ func NewWithMissing(
 // SetParser tells this job queue how to parse jobs from the database.
 func (jm *JobsWithMissing) SetParser(parser Parser) error {
 	jm.state.parser = parser
-	return jm.cleanRunnableStack()
+	if err := jm.cleanRunnableStack(); err != nil {
+		return err
+	}
+	if _, err := jm.evictStaleJobs(); err != nil {
+		return err
+	}
+	if err := jm.evictStaleMissingIDs(); err != nil {
+		return err
+	}
+	return jm.Commit()
 }
 
 func (jm *JobsWithMissing) Has(jobID ids.ID) (bool, error) {
@@ -311,6 +388,20 @@ func (jm *JobsWithMissing) Commit() error {
 	return jm.Jobs.Commit()
 }
 
+// evictStaleMissingIDs removes the missing IDs that have been missing for
+// longer than [maxAge] and that no job is blocked on anymore
+func (jm *JobsWithMissing) evictStaleMissingIDs() error {
+	if jm.maxAge <= 0 {
+		return nil
+	}
+	staleIDs, err := jm.state.RemoveStaleMissingJobIDs(jm.state.clock.Time().Add(-jm.maxAge))
+	if err != nil {
+		return fmt.Errorf("failed to evict stale missing IDs due to %w", err)
+	}
+	jm.missingIDs.Remove(staleIDs...)
+	return nil
+}
+
 // cleanRunnableStack iterates over the jobs on the runnable stack and resets any job
 // that has missing dependencies to block on those dependencies.
 // Note: the jobs queue ensures that no job with missing dependencies will be placed
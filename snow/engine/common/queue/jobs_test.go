@@ -5,9 +5,12 @@ package queue
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/deferreddb"
 	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
@@ -16,6 +19,18 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var errTest = errors.New("non-nil error")
+
+type testFlusher struct {
+	*deferreddb.Database
+	numFlushes int
+}
+
+func (f *testFlusher) Flush() error {
+	f.numFlushes++
+	return f.Database.Flush()
+}
+
 // Magic value that comes from the size in bytes of a serialized key-value bootstrap checkpoint in a database +
 // the overhead of the key-value storage.
 const bootstrapProgressCheckpointSize = 59
@@ -457,3 +472,215 @@ func TestHandleJobWithMissingDependencyOnRunnableStack(t *testing.T) {
 	assert.Equal(2, count)
 	assert.True(executed1)
 }
+
+// Test that the flusher is flushed periodically while executing jobs, and that
+// the jobs executed since the last flush are executed again after a crash.
+func TestFlushWhileExecuting(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &TestParser{T: t}
+	baseDB := memdb.New()
+	db := deferreddb.New(baseDB)
+	flusher := &testFlusher{Database: db}
+
+	jobs, err := New(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+	jobs.SetFlusher(flusher, 2)
+
+	numExecuted := 0
+	jobsByBytes := make(map[byte]*TestJob)
+	for i := byte(0); i < 5; i++ {
+		jobID := ids.GenerateTestID()
+		b := []byte{i}
+		job := &TestJob{
+			T: t,
+
+			IDF:                     func() ids.ID { return jobID },
+			MissingDependenciesF:    func() (ids.Set, error) { return ids.Set{}, nil },
+			HasMissingDependenciesF: func() (bool, error) { return false, nil },
+			ExecuteF: func() error {
+				numExecuted++
+				if numExecuted == 3 {
+					return errTest
+				}
+				return nil
+			},
+			BytesF: func() []byte { return b },
+		}
+		jobsByBytes[i] = job
+		pushed, err := jobs.Push(job)
+		assert.NoError(err)
+		assert.True(pushed)
+	}
+	assert.NoError(jobs.Commit())
+	assert.NoError(db.Flush())
+	flusher.numFlushes = 0
+
+	parser.ParseF = func(b []byte) (Job, error) { return jobsByBytes[b[0]], nil }
+
+	// The third job fails, which simulates a crash after the first flush
+	_, err = jobs.ExecuteAll(snow.DefaultConsensusContextTest(), &common.Halter{}, false)
+	assert.ErrorIs(err, errTest)
+	assert.Equal(1, flusher.numFlushes)
+
+	// Only the writes made up to the flush were persisted, so the third job
+	// is still pending
+	db = deferreddb.New(baseDB)
+	flusher = &testFlusher{Database: db}
+	jobs, err = New(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+	jobs.SetFlusher(flusher, 2)
+	assert.EqualValues(3, jobs.PendingJobs())
+
+	count, err := jobs.ExecuteAll(snow.DefaultConsensusContextTest(), &common.Halter{}, false)
+	assert.NoError(err)
+	assert.Equal(3, count)
+	assert.Equal(2, flusher.numFlushes)
+
+	// Everything was flushed once all the jobs were executed
+	jobs, err = New(baseDB, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.Zero(jobs.PendingJobs())
+}
+
+// Test that the writes of the executed jobs are flushed when execution is
+// interrupted, such as when the node shuts down.
+func TestFlushWhenHalted(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &TestParser{T: t}
+	baseDB := memdb.New()
+	db := deferreddb.New(baseDB)
+	flusher := &testFlusher{Database: db}
+
+	jobs, err := New(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+	jobs.SetFlusher(flusher, 10)
+
+	halter := &common.Halter{}
+	numExecuted := 0
+	jobsByBytes := make(map[byte]*TestJob)
+	for i := byte(0); i < 5; i++ {
+		jobID := ids.GenerateTestID()
+		b := []byte{i}
+		job := &TestJob{
+			T: t,
+
+			IDF:                     func() ids.ID { return jobID },
+			MissingDependenciesF:    func() (ids.Set, error) { return ids.Set{}, nil },
+			HasMissingDependenciesF: func() (bool, error) { return false, nil },
+			ExecuteF: func() error {
+				numExecuted++
+				if numExecuted == 3 {
+					halter.Halt()
+				}
+				return nil
+			},
+			BytesF: func() []byte { return b },
+		}
+		jobsByBytes[i] = job
+		pushed, err := jobs.Push(job)
+		assert.NoError(err)
+		assert.True(pushed)
+	}
+	assert.NoError(jobs.Commit())
+	assert.NoError(db.Flush())
+	flusher.numFlushes = 0
+
+	parser.ParseF = func(b []byte) (Job, error) { return jobsByBytes[b[0]], nil }
+
+	count, err := jobs.ExecuteAll(snow.DefaultConsensusContextTest(), halter, false)
+	assert.NoError(err)
+	assert.Equal(3, count)
+	assert.Equal(1, flusher.numFlushes)
+
+	// The executed jobs were persisted without waiting for the flush frequency
+	jobs, err = New(baseDB, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.EqualValues(2, jobs.PendingJobs())
+}
+
+// Test that the jobs and missing IDs that are older than the max age are
+// evicted when the queue is restarted, unless they can still be executed.
+func TestEvictStaleJobs(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	jobs, err := NewWithMissing(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+
+	newJob := func(b byte, deps ids.Set) *TestJob {
+		jobID := ids.GenerateTestID()
+		return &TestJob{
+			T: t,
+
+			IDF:                     func() ids.ID { return jobID },
+			MissingDependenciesF:    func() (ids.Set, error) { return deps, nil },
+			HasMissingDependenciesF: func() (bool, error) { return deps.Len() != 0, nil },
+			ExecuteF:                func() error { return nil },
+			BytesF:                  func() []byte { return []byte{b} },
+		}
+	}
+	staleDepID := ids.GenerateTestID()
+	unusedDepID := ids.GenerateTestID()
+	freshDepID := ids.GenerateTestID()
+	staleJob := newJob(0, ids.Set{staleDepID: struct{}{}})
+	runnableJob := newJob(1, ids.Set{})
+	freshJob := newJob(2, ids.Set{freshDepID: struct{}{}})
+	parser.ParseF = func(b []byte) (Job, error) {
+		return []Job{staleJob, runnableJob, freshJob}[b[0]], nil
+	}
+
+	now := time.Now()
+	jobs.state.clock.Set(now.Add(-2 * time.Hour))
+	for _, job := range []Job{staleJob, runnableJob} {
+		pushed, err := jobs.Push(job)
+		assert.NoError(err)
+		assert.True(pushed)
+	}
+	jobs.AddMissingID(staleDepID, unusedDepID)
+	assert.NoError(jobs.Commit())
+
+	jobs.state.clock.Set(now)
+	pushed, err := jobs.Push(freshJob)
+	assert.NoError(err)
+	assert.True(pushed)
+	jobs.AddMissingID(freshDepID)
+	assert.NoError(jobs.Commit())
+	assert.EqualValues(3, jobs.PendingJobs())
+
+	jobs, err = NewWithMissing(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	jobs.SetMaxAge(time.Hour)
+	jobs.state.clock.Set(now)
+	assert.NoError(jobs.SetParser(parser))
+
+	has, err := jobs.Has(staleJob.ID())
+	assert.NoError(err)
+	assert.False(has)
+	has, err = jobs.Has(runnableJob.ID())
+	assert.NoError(err)
+	assert.True(has)
+	has, err = jobs.Has(freshJob.ID())
+	assert.NoError(err)
+	assert.True(has)
+	assert.EqualValues(2, jobs.PendingJobs())
+	assert.Equal([]ids.ID{freshDepID}, jobs.MissingIDs())
+
+	// The eviction is persisted
+	jobs, err = NewWithMissing(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+	assert.EqualValues(2, jobs.PendingJobs())
+	assert.Equal([]ids.ID{freshDepID}, jobs.MissingIDs())
+
+	count, err := jobs.ExecuteAll(snow.DefaultConsensusContextTest(), &common.Halter{}, false)
+	assert.NoError(err)
+	assert.Equal(1, count)
+}
@@ -5,5 +5,7 @@ package queue
 
 // Parser allows parsing a job from bytes.
 type Parser interface {
+	// The returned job may alias the given bytes, which must not be modified
+	// while the job is in use.
 	Parse([]byte) (Job, error)
 }
@@ -5,6 +5,7 @@ package queue
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/cache"
 	"github.com/Toinounet21/avalanchego-mod/cache/metercacher"
@@ -12,12 +13,17 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/database/linkeddb"
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
 	dependentsCacheSize = 1024
 	jobsCacheSize       = 2048
+
+	jobsCacheMetricsNamespace = "jobs_cache"
+	jobsMetricsNamespace      = "jobs"
+	queueLabel                = "queue"
 )
 
 var (
@@ -26,6 +32,7 @@ var (
 	dependenciesKey   = []byte("dependencies")
 	missingJobIDsKey  = []byte("missing job IDs")
 	pendingJobsKey    = []byte("pendingJobs")
+	jobTimestampsKey  = []byte("job timestamps")
 )
 
 type state struct {
@@ -41,20 +48,34 @@ type state struct {
 	// This is a cache that tracks LinkedDB iterators that have recently been
 	// made.
 	dependentsCache cache.Cacher
-	missingJobIDs   linkeddb.LinkedDB
+	// Maps missing job IDs to the time they were added at. Missing job IDs
+	// that were added before the times were recorded map to nil.
+	missingJobIDs linkeddb.LinkedDB
+	// Maps job IDs to the time the jobs were added at. Jobs that were added
+	// before the times were recorded aren't in [jobTimestamps].
+	jobTimestamps database.Database
 	// data store that tracks the last known checkpoint of how many jobs were pending in the queue.
 	pendingJobs database.KeyValueReaderWriter
 	// represents the number of pending jobs in the queue.
 	numPendingJobs uint64
+
+	clock mockable.Clock
+
+	numEvictedJobs          prometheus.Counter
+	numEvictedMissingJobIDs prometheus.Counter
 }
 
 func newState(
 	db database.Database,
-	metricsNamespace string,
+	queueName string,
 	metricsRegisterer prometheus.Registerer,
 ) (*state, error) {
-	jobsCacheMetricsNamespace := fmt.Sprintf("%s_jobs_cache", metricsNamespace)
-	jobsCache, err := metercacher.New(jobsCacheMetricsNamespace, metricsRegisterer, &cache.LRU{Size: jobsCacheSize})
+	// Queues share their metric names and are told apart by the queue label
+	queueRegisterer := prometheus.WrapRegistererWith(
+		prometheus.Labels{queueLabel: queueName},
+		metricsRegisterer,
+	)
+	jobsCache, err := metercacher.New(jobsCacheMetricsNamespace, queueRegisterer, &cache.LRU{Size: jobsCacheSize})
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create metered cache: %w", err)
 	}
@@ -64,6 +85,24 @@ func newState(
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize pending jobs: %w", err)
 	}
+
+	numEvictedJobs := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: jobsMetricsNamespace,
+		Name:      "evicted",
+		Help:      "Number of stale jobs evicted from the queue",
+	})
+	numEvictedMissingJobIDs := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: jobsMetricsNamespace,
+		Name:      "missing_ids_evicted",
+		Help:      "Number of stale missing job IDs evicted from the queue",
+	})
+	if err := queueRegisterer.Register(numEvictedJobs); err != nil {
+		return nil, fmt.Errorf("couldn't register evicted jobs metric: %w", err)
+	}
+	if err := queueRegisterer.Register(numEvictedMissingJobIDs); err != nil {
+		return nil, fmt.Errorf("couldn't register evicted missing job IDs metric: %w", err)
+	}
+
 	return &state{
 		runnableJobIDs:  linkeddb.NewDefault(prefixdb.New(runnableJobIDsKey, db)),
 		cachingEnabled:  true,
@@ -72,8 +111,12 @@ func newState(
 		dependencies:    prefixdb.New(dependenciesKey, db),
 		dependentsCache: &cache.LRU{Size: dependentsCacheSize},
 		missingJobIDs:   linkeddb.NewDefault(prefixdb.New(missingJobIDsKey, db)),
+		jobTimestamps:   prefixdb.New(jobTimestampsKey, db),
 		pendingJobs:     pendingJobs,
 		numPendingJobs:  numPendingJobs,
+
+		numEvictedJobs:          numEvictedJobs,
+		numEvictedMissingJobIDs: numEvictedMissingJobIDs,
 	}, nil
 }
 
@@ -135,6 +178,9 @@ func (s *state) RemoveRunnableJob() (Job, error) {
 	if err := s.jobs.Delete(jobIDBytes); err != nil {
 		return job, err
 	}
+	if err := s.jobTimestamps.Delete(jobIDBytes); err != nil {
+		return job, err
+	}
 
 	// Guard rail to make sure we don't underflow.
 	if s.numPendingJobs == 0 {
@@ -155,6 +201,9 @@ func (s *state) PutJob(job Job) error {
 	if err := s.jobs.Put(id[:], job.Bytes()); err != nil {
 		return err
 	}
+	if err := database.PutTimestamp(s.jobTimestamps, id[:], s.clock.Time()); err != nil {
+		return err
+	}
 
 	s.numPendingJobs++
 	return database.PutUInt64(s.pendingJobs, pendingJobsKey, s.numPendingJobs)
@@ -223,9 +272,13 @@ func (s *state) DisableCaching() {
 }
 
 func (s *state) AddMissingJobIDs(missingIDs ids.Set) error {
+	timestamp, err := s.clock.Time().MarshalBinary()
+	if err != nil {
+		return err
+	}
 	for missingID := range missingIDs {
 		missingID := missingID
-		if err := s.missingJobIDs.Put(missingID[:], nil); err != nil {
+		if err := s.missingJobIDs.Put(missingID[:], timestamp); err != nil {
 			return err
 		}
 	}
@@ -257,6 +310,133 @@ func (s *state) MissingJobIDs() ([]ids.ID, error) {
 	return missingIDs, nil
 }
 
+// RemoveStaleJobs removes the jobs that were added before [cutoff] and are
+// still blocked on their dependencies, and returns how many were removed. Jobs
+// that were added before the times were recorded are never removed.
+func (s *state) RemoveStaleJobs(cutoff time.Time) (int, error) {
+	staleJobIDs, err := s.staleJobIDs(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	numRemoved := 0
+	for _, jobID := range staleJobIDs {
+		// Runnable jobs will be executed, so they aren't stale
+		if runnable, err := s.runnableJobIDs.Has(jobID[:]); err != nil {
+			return numRemoved, err
+		} else if runnable {
+			continue
+		}
+
+		job, err := s.GetJob(jobID)
+		if err != nil {
+			return numRemoved, fmt.Errorf("couldn't get stale job %s: %w", jobID, err)
+		}
+		deps, err := job.MissingDependencies()
+		if err != nil {
+			return numRemoved, fmt.Errorf("couldn't get missing dependencies of stale job %s: %w", jobID, err)
+		}
+		for depID := range deps {
+			if err := s.getDependentsDB(depID).Delete(jobID[:]); err != nil {
+				return numRemoved, err
+			}
+		}
+
+		if s.cachingEnabled {
+			s.jobsCache.Evict(jobID)
+		}
+		if err := s.jobs.Delete(jobID[:]); err != nil {
+			return numRemoved, err
+		}
+		if err := s.jobTimestamps.Delete(jobID[:]); err != nil {
+			return numRemoved, err
+		}
+		if s.numPendingJobs > 0 {
+			s.numPendingJobs--
+		}
+		numRemoved++
+	}
+
+	if numRemoved == 0 {
+		return 0, nil
+	}
+	s.numEvictedJobs.Add(float64(numRemoved))
+	return numRemoved, database.PutUInt64(s.pendingJobs, pendingJobsKey, s.numPendingJobs)
+}
+
+// staleJobIDs returns the IDs of the jobs that were added before [cutoff]
+func (s *state) staleJobIDs(cutoff time.Time) ([]ids.ID, error) {
+	iterator := s.jobTimestamps.NewIterator()
+	defer iterator.Release()
+
+	staleJobIDs := []ids.ID(nil)
+	for iterator.Next() {
+		timestamp, err := database.ParseTimestamp(iterator.Value())
+		if err != nil {
+			return nil, err
+		}
+		if !timestamp.Before(cutoff) {
+			continue
+		}
+		jobID, err := ids.ToID(iterator.Key())
+		if err != nil {
+			return nil, err
+		}
+		staleJobIDs = append(staleJobIDs, jobID)
+	}
+	return staleJobIDs, iterator.Error()
+}
+
+// RemoveStaleMissingJobIDs removes the missing job IDs that were added before
+// [cutoff] and that no job is blocked on, and returns them. Missing job IDs
+// that were added before the times were recorded are never removed.
+func (s *state) RemoveStaleMissingJobIDs(cutoff time.Time) ([]ids.ID, error) {
+	staleIDs, err := s.staleMissingJobIDs(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, missingID := range staleIDs {
+		if err := s.missingJobIDs.Delete(missingID[:]); err != nil {
+			return nil, err
+		}
+	}
+	s.numEvictedMissingJobIDs.Add(float64(len(staleIDs)))
+	return staleIDs, nil
+}
+
+// staleMissingJobIDs returns the missing job IDs that were added before
+// [cutoff] and that no job is blocked on
+func (s *state) staleMissingJobIDs(cutoff time.Time) ([]ids.ID, error) {
+	iterator := s.missingJobIDs.NewIterator()
+	defer iterator.Release()
+
+	staleIDs := []ids.ID(nil)
+	for iterator.Next() {
+		value := iterator.Value()
+		if len(value) == 0 {
+			continue
+		}
+		timestamp, err := database.ParseTimestamp(value)
+		if err != nil {
+			return nil, err
+		}
+		if !timestamp.Before(cutoff) {
+			continue
+		}
+		missingID, err := ids.ToID(iterator.Key())
+		if err != nil {
+			return nil, err
+		}
+		if isEmpty, err := s.getDependentsDB(missingID).IsEmpty(); err != nil {
+			return nil, err
+		} else if !isEmpty {
+			continue
+		}
+		staleIDs = append(staleIDs, missingID)
+	}
+	return staleIDs, iterator.Error()
+}
+
 func (s *state) getDependentsDB(dependency ids.ID) linkeddb.LinkedDB {
 	if s.cachingEnabled {
 		if dependentsDBIntf, ok := s.dependentsCache.Get(dependency); ok {
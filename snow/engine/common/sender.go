@@ -4,6 +4,8 @@
 package common
 
 import (
+	"context"
+
 	"github.com/Toinounet21/avalanchego-mod/ids"
 )
 
@@ -16,6 +18,13 @@ type Sender interface {
 	QuerySender
 	Gossiper
 	AppSender
+
+	// WithContext returns a Sender whose requests are bound to [ctx]. Requests
+	// aren't sent once [ctx] is done, and requests that are still outstanding
+	// when [ctx] is done fail immediately, rather than when they time out. As
+	// with any other failure, exactly one response or failure message is
+	// received for each request.
+	WithContext(ctx context.Context) Sender
 }
 
 // FrontierSender defines how a consensus engine sends frontier messages to
@@ -125,4 +134,9 @@ type AppSender interface {
 	// A non-nil error should be considered fatal.
 	SendAppGossip(appGossipBytes []byte) error
 	SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes []byte) error
+	// Gossip an application-level message to [numValidators] validators of
+	// [subnetID], sampled uniformly at random from the validators this node is
+	// connected to. Non-validators never receive the message.
+	// A non-nil error should be considered fatal.
+	SendAppGossipValidators(subnetID ids.ID, numValidators int, appGossipBytes []byte) error
 }
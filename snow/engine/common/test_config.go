@@ -7,6 +7,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 )
 
 // DefaultConfigTest returns a test configuration
@@ -27,6 +28,8 @@ func DefaultConfigTest() Config {
 		Timer:                          &TimerTest{},
 		AncestorsMaxContainersSent:     2000,
 		AncestorsMaxContainersReceived: 2000,
+		PeerScorer:                     &PeerScorerTest{},
+		Events:                         NewEventBus(logging.NoLog{}),
 		SharedCfg:                      &SharedConfig{},
 	}
 }
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+var _ PeerScorer = &PeerScorerTest{}
+
+// PeerScorerTest is a test peer scorer. By default, every peer has a score of
+// 0 and Best returns the first node.
+type PeerScorerTest struct {
+	ObserveF func(ids.ShortID, PeerEvent)
+	ScoreF   func(ids.ShortID) float64
+	IsPoorF  func(ids.ShortID) bool
+	BestF    func([]ids.ShortID) (ids.ShortID, bool)
+}
+
+func (s *PeerScorerTest) Observe(nodeID ids.ShortID, event PeerEvent) {
+	if s.ObserveF != nil {
+		s.ObserveF(nodeID, event)
+	}
+}
+
+func (s *PeerScorerTest) Score(nodeID ids.ShortID) float64 {
+	if s.ScoreF != nil {
+		return s.ScoreF(nodeID)
+	}
+	return 0
+}
+
+func (s *PeerScorerTest) IsPoor(nodeID ids.ShortID) bool {
+	if s.IsPoorF != nil {
+		return s.IsPoorF(nodeID)
+	}
+	return false
+}
+
+func (s *PeerScorerTest) Best(nodeIDs []ids.ShortID) (ids.ShortID, bool) {
+	if s.BestF != nil {
+		return s.BestF(nodeIDs)
+	}
+	if len(nodeIDs) == 0 {
+		return ids.ShortEmpty, false
+	}
+	return nodeIDs[0], true
+}
@@ -4,6 +4,7 @@
 package common
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -11,10 +12,11 @@ import (
 )
 
 var (
-	errSendAppRequest        = errors.New("unexpectedly called SendAppRequest")
-	errSendAppResponse       = errors.New("unexpectedly called SendAppResponse")
-	errSendAppGossip         = errors.New("unexpectedly called SendAppGossip")
-	errSendAppGossipSpecific = errors.New("unexpectedly called SendAppGossipSpecific")
+	errSendAppRequest          = errors.New("unexpectedly called SendAppRequest")
+	errSendAppResponse         = errors.New("unexpectedly called SendAppResponse")
+	errSendAppGossip           = errors.New("unexpectedly called SendAppGossip")
+	errSendAppGossipSpecific   = errors.New("unexpectedly called SendAppGossipSpecific")
+	errSendAppGossipValidators = errors.New("unexpectedly called SendAppGossipValidators")
 )
 
 // SenderTest is a test sender
@@ -26,7 +28,8 @@ type SenderTest struct {
 	CantSendGet, CantSendGetAncestors, CantSendPut, CantSendAncestors,
 	CantSendPullQuery, CantSendPushQuery, CantSendChits,
 	CantSendGossip,
-	CantSendAppRequest, CantSendAppResponse, CantSendAppGossip, CantSendAppGossipSpecific bool
+	CantSendAppRequest, CantSendAppResponse, CantSendAppGossip, CantSendAppGossipSpecific,
+	CantSendAppGossipValidators bool
 
 	SendGetAcceptedFrontierF func(ids.ShortSet, uint32)
 	SendAcceptedFrontierF    func(ids.ShortID, uint32, []ids.ID)
@@ -44,6 +47,8 @@ type SenderTest struct {
 	SendAppResponseF         func(ids.ShortID, uint32, []byte) error
 	SendAppGossipF           func([]byte) error
 	SendAppGossipSpecificF   func(ids.ShortSet, []byte) error
+	SendAppGossipValidatorsF func(ids.ID, int, []byte) error
+	WithContextF             func(context.Context) Sender
 }
 
 // Default set the default callable value to [cant]
@@ -64,6 +69,7 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantSendAppResponse = cant
 	s.CantSendAppGossip = cant
 	s.CantSendAppGossipSpecific = cant
+	s.CantSendAppGossipValidators = cant
 }
 
 // SendGetAcceptedFrontier calls SendGetAcceptedFrontierF if it was initialized.
@@ -249,3 +255,25 @@ func (s *SenderTest) SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes
 	}
 	return errSendAppGossipSpecific
 }
+
+// SendAppGossipValidators calls SendAppGossipValidatorsF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) SendAppGossipValidators(subnetID ids.ID, numValidators int, appGossipBytes []byte) error {
+	switch {
+	case s.SendAppGossipValidatorsF != nil:
+		return s.SendAppGossipValidatorsF(subnetID, numValidators, appGossipBytes)
+	case s.CantSendAppGossipValidators && s.T != nil:
+		s.T.Fatal(errSendAppGossipValidators)
+	}
+	return errSendAppGossipValidators
+}
+
+// WithContext calls WithContextF if it was initialized. If it wasn't
+// initialized, this sender is returned, so requests ignore [ctx].
+func (s *SenderTest) WithContext(ctx context.Context) Sender {
+	if s.WithContextF != nil {
+		return s.WithContextF(ctx)
+	}
+	return s
+}
@@ -4,6 +4,7 @@
 package block
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -16,19 +17,24 @@ var ErrRemoteVMNotImplemented = errors.New("vm does not implement RemoteVM inter
 
 // BatchedChainVM extends the minimal functionalities exposed by ChainVM for VMs
 // communicating over network (gRPC in our case). This allows more efficient
-// operations since calls over network can be duly batched
+// operations since calls over network can be duly batched.
+// Implementations should stop and return what they have once [ctx] is done.
 type BatchedChainVM interface {
 	GetAncestors(
+		ctx context.Context,
 		blkID ids.ID, // first requested block
 		maxBlocksNum int, // max number of blocks to be retrieved
 		maxBlocksSize int, // max cumulated byte size of retrieved blocks
 		maxBlocksRetrivalTime time.Duration, // max duration of retrival operation
 	) ([][]byte, error)
 
-	BatchedParseBlock(blks [][]byte) ([]snowman.Block, error)
+	BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error)
 }
 
+// GetAncestors returns the bytes of [blkID] and of its ancestors, most recent
+// first. Stops fetching ancestors once [ctx] is done.
 func GetAncestors(
+	ctx context.Context,
 	vm Getter, // fetch blocks
 	blkID ids.ID, // first requested block
 	maxBlocksNum int, // max number of blocks to be retrieved
@@ -38,6 +44,7 @@ func GetAncestors(
 	// Try and batch GetBlock requests
 	if vm, ok := vm.(BatchedChainVM); ok {
 		blocks, err := vm.GetAncestors(
+			ctx,
 			blkID,
 			maxBlocksNum,
 			maxBlocksSize,
@@ -63,7 +70,7 @@ func GetAncestors(
 	ancestorsBytes[0] = blk.Bytes()
 	ancestorsBytesLen := len(blk.Bytes()) + wrappers.IntLen // length, in bytes, of all elements of ancestors
 
-	for numFetched := 1; numFetched < maxBlocksNum && time.Since(startTime) < maxBlocksRetrivalTime; numFetched++ {
+	for numFetched := 1; numFetched < maxBlocksNum && time.Since(startTime) < maxBlocksRetrivalTime && ctx.Err() == nil; numFetched++ {
 		if blk, err = vm.GetBlock(blk.Parent()); err != nil {
 			break
 		}
@@ -82,10 +89,12 @@ func GetAncestors(
 	return ancestorsBytes, nil
 }
 
-func BatchedParseBlock(vm Parser, blks [][]byte) ([]snowman.Block, error) {
+// BatchedParseBlock parses [blks]. Returns [ctx]'s error if [ctx] is done
+// before all the blocks are parsed.
+func BatchedParseBlock(ctx context.Context, vm Parser, blks [][]byte) ([]snowman.Block, error) {
 	// Try and batch ParseBlock requests
 	if vm, ok := vm.(BatchedChainVM); ok {
-		blocks, err := vm.BatchedParseBlock(blks)
+		blocks, err := vm.BatchedParseBlock(ctx, blks)
 		if err == nil {
 			return blocks, nil
 		}
@@ -98,6 +107,9 @@ func BatchedParseBlock(vm Parser, blks [][]byte) ([]snowman.Block, error) {
 	// time.
 	blocks := make([]snowman.Block, len(blks))
 	for i, blockBytes := range blks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		block, err := vm.ParseBlock(blockBytes)
 		if err != nil {
 			return nil, err
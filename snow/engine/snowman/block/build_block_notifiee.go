@@ -0,0 +1,16 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+// BuildBlockNotifiee is an optional interface a ChainVM can implement to be
+// told when consensus is ready to issue a newly built block. This allows the
+// VM to signal PendingTxs as soon as its previously built block is decided,
+// rather than polling for the decision.
+type BuildBlockNotifiee interface {
+	// ReadyToBuild is called once every block that was verified has been
+	// decided.
+	//
+	// It may be called concurrently with the other methods of the VM.
+	ReadyToBuild()
+}
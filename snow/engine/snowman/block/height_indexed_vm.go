@@ -0,0 +1,15 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import "github.com/Toinounet21/avalanchego-mod/ids"
+
+// HeightIndexedChainVM extends the minimal functionalities exposed by ChainVM
+// for VMs that index their accepted blocks by height.
+type HeightIndexedChainVM interface {
+	// GetBlockIDAtHeight returns the ID of the block that was accepted at
+	// [height]. Returns database.ErrNotFound if no block has been accepted at
+	// [height].
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+}
@@ -4,6 +4,7 @@
 package block
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -42,6 +43,7 @@ func (vm *TestBatchedVM) Default(cant bool) {
 }
 
 func (vm *TestBatchedVM) GetAncestors(
+	_ context.Context,
 	blkID ids.ID,
 	maxBlocksNum int,
 	maxBlocksSize int,
@@ -56,7 +58,7 @@ func (vm *TestBatchedVM) GetAncestors(
 	return nil, errGetAncestor
 }
 
-func (vm *TestBatchedVM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
+func (vm *TestBatchedVM) BatchedParseBlock(_ context.Context, blks [][]byte) ([]snowman.Block, error) {
 	if vm.BatchedParseBlockF != nil {
 		return vm.BatchedParseBlockF(blks)
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
@@ -23,7 +24,8 @@ import (
 const bootstrappingDelay = 10 * time.Second
 
 var (
-	_ common.BootstrapableEngine = &bootstrapper{}
+	_ common.BootstrapableEngine   = &bootstrapper{}
+	_ validators.PeerInfoConnector = &bootstrapper{}
 
 	errUnexpectedTimeout = errors.New("unexpected timeout fired")
 )
@@ -69,8 +71,8 @@ func New(config Config, onFinished func(lastReqID uint32) error) (common.Bootstr
 	}
 
 	config.Bootstrapable = b
-	b.Bootstrapper = common.NewCommonBootstrapper(config.Config)
-	return b, nil
+	b.Bootstrapper, err = common.NewCommonBootstrapper(config.Config)
+	return b, err
 }
 
 type bootstrapper struct {
@@ -113,6 +115,7 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, blks [][]byt
 	lenBlks := len(blks)
 	if lenBlks == 0 {
 		b.Ctx.Log.Debug("Ancestors(%s, %d) contains no blocks", vdr, requestID)
+		b.Config.PeerScorer.Observe(vdr, common.UselessResponse)
 		return b.GetAncestorsFailed(vdr, requestID)
 	}
 	if lenBlks > b.Config.AncestorsMaxContainersReceived {
@@ -128,14 +131,21 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, blks [][]byt
 		return nil
 	}
 
-	blocks, err := block.BatchedParseBlock(b.VM, blks)
+	ctx := b.Ctx.ShutdownContext()
+	blocks, err := block.BatchedParseBlock(ctx, b.VM, blks)
+	if ctx.Err() != nil { // the chain is shutting down
+		b.Ctx.Log.Debug("dropping Ancestors from %s with ID %d because the chain is shutting down", vdr, requestID)
+		return nil
+	}
 	if err != nil { // the provided blocks couldn't be parsed
 		b.Ctx.Log.Debug("failed to parse blocks in Ancestors from %s with ID %d", vdr, requestID)
+		b.Config.PeerScorer.Observe(vdr, common.InvalidContainer)
 		return b.fetch(wantedBlkID)
 	}
 
 	if len(blocks) == 0 {
 		b.Ctx.Log.Debug("parsing blocks returned an empty set of blocks from %s with ID %d", vdr, requestID)
+		b.Config.PeerScorer.Observe(vdr, common.InvalidContainer)
 		return b.fetch(wantedBlkID)
 	}
 
@@ -143,8 +153,10 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, blks [][]byt
 	if actualID := requestedBlock.ID(); actualID != wantedBlkID {
 		b.Ctx.Log.Debug("expected the first block to be the requested block, %s, but is %s",
 			wantedBlkID, actualID)
+		b.Config.PeerScorer.Observe(vdr, common.InvalidContainer)
 		return b.fetch(wantedBlkID)
 	}
+	b.Config.PeerScorer.Observe(vdr, common.ValidContainer)
 
 	blockSet := make(map[ids.ID]snowman.Block, len(blocks))
 	for _, block := range blocks[1:] {
@@ -167,7 +179,12 @@ func (b *bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) err
 
 // Connected implements the InternalHandler interface.
 func (b *bootstrapper) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
-	if err := b.VM.Connected(nodeID, nodeVersion); err != nil {
+	return b.ConnectedWithInfo(nodeID, validators.PeerInfo{Version: nodeVersion})
+}
+
+// ConnectedWithInfo implements the validators.PeerInfoConnector interface.
+func (b *bootstrapper) ConnectedWithInfo(nodeID ids.ShortID, peerInfo validators.PeerInfo) error {
+	if err := validators.Connect(b.VM, nodeID, peerInfo); err != nil {
 		return err
 	}
 
@@ -224,6 +241,7 @@ func (b *bootstrapper) IsBootstrapped() bool { return b.Ctx.IsBootstrapped() }
 func (b *bootstrapper) Start(startReqID uint32) error {
 	b.Ctx.Log.Info("Starting bootstrap...")
 	b.Ctx.SetState(snow.Bootstrapping)
+	b.Events.PublishState(b.Ctx, snow.Bootstrapping)
 	b.Config.SharedCfg.RequestID = startReqID
 
 	if b.WeightTracker.EnoughConnectedWeight() {
@@ -305,11 +323,10 @@ func (b *bootstrapper) fetch(blkID ids.ID) error {
 		return b.checkFinish()
 	}
 
-	validators, err := b.Config.Beacons.Sample(1) // validator to send request to
+	validatorID, err := b.Config.SampleFetchTarget() // validator to send request to
 	if err != nil {
 		return fmt.Errorf("dropping request for %s as there are no validators", blkID)
 	}
-	validatorID := validators[0].ID()
 	b.Config.SharedCfg.RequestID++
 
 	b.OutstandingRequests.Add(validatorID, b.Config.SharedCfg.RequestID, blkID)
@@ -420,6 +437,7 @@ func (b *bootstrapper) checkFinish() error {
 	} else {
 		b.Ctx.Log.Debug("bootstrapping fetched %d blocks. Executing state transitions...", b.Blocked.PendingJobs())
 	}
+	b.Events.PublishBootstrapPhase(b.Ctx, common.ExecutingContainers)
 
 	executedBlocks, err := b.Blocked.ExecuteAll(
 		b.Config.Ctx,
@@ -21,6 +21,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
 	snowgetter "github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/getter"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 )
 
 var errUnknownBlock = errors.New("unknown block")
@@ -66,6 +67,8 @@ func newConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *block.Te
 		Timer:                          &common.TimerTest{},
 		AncestorsMaxContainersSent:     2000,
 		AncestorsMaxContainersReceived: 2000,
+		PeerScorer:                     &common.PeerScorerTest{},
+		Events:                         common.NewEventBus(logging.NoLog{}),
 		SharedCfg:                      &common.SharedConfig{},
 	}
 
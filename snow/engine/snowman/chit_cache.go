@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+const chitCacheSize = 512
+
+// chitCache remembers the chits that were recently sent in response to queries
+// for a block. When many peers query the same block in a burst, the chits can
+// be sent again without looking the block up in the VM for every query.
+type chitCache struct {
+	// Chits are only re-sent if they were sent within the last [duration].
+	// If [duration] is 0, chits are never re-sent.
+	duration time.Duration
+	// Useful for faking time in tests
+	clock mockable.Clock
+
+	// Block ID --> *cachedChit
+	chits cache.LRU
+}
+
+type cachedChit struct {
+	pref   ids.ID
+	sentAt time.Time
+}
+
+func newChitCache(duration time.Duration) *chitCache {
+	return &chitCache{
+		duration: duration,
+		chits:    cache.LRU{Size: chitCacheSize},
+	}
+}
+
+// Put records that chits for [pref] were sent in response to a query for
+// [blkID].
+func (c *chitCache) Put(blkID, pref ids.ID) {
+	if c.duration <= 0 {
+		return
+	}
+	c.chits.Put(blkID, &cachedChit{
+		pref:   pref,
+		sentAt: c.clock.Time(),
+	})
+}
+
+// Has returns true if chits for [pref] were sent in response to a query for
+// [blkID] within the last [duration]. Since [pref] must still be the current
+// preference, re-sending the chits can't send a stale vote.
+func (c *chitCache) Has(blkID, pref ids.ID) bool {
+	chitIntf, ok := c.chits.Get(blkID)
+	if !ok {
+		return false
+	}
+	chit := chitIntf.(*cachedChit)
+	if chit.pref != pref || c.clock.Time().Sub(chit.sentAt) > c.duration {
+		c.chits.Evict(blkID)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func TestChitCache(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newChitCache(time.Second)
+	now := time.Now()
+	c.clock.Set(now)
+
+	blkID := ids.GenerateTestID()
+	pref := ids.GenerateTestID()
+	assert.False(c.Has(blkID, pref))
+
+	c.Put(blkID, pref)
+	assert.True(c.Has(blkID, pref))
+	assert.False(c.Has(ids.GenerateTestID(), pref))
+
+	// Chits aren't re-sent once the preference changes
+	assert.False(c.Has(blkID, ids.GenerateTestID()))
+	assert.False(c.Has(blkID, pref))
+
+	// Chits aren't re-sent once they've expired
+	c.Put(blkID, pref)
+	c.clock.Set(now.Add(time.Second))
+	assert.True(c.Has(blkID, pref))
+	c.clock.Set(now.Add(time.Second + 1))
+	assert.False(c.Has(blkID, pref))
+}
+
+func TestChitCacheDisabled(t *testing.T) {
+	c := newChitCache(0)
+
+	blkID := ids.GenerateTestID()
+	pref := ids.GenerateTestID()
+	c.Put(blkID, pref)
+	assert.False(t, c.Has(blkID, pref))
+}
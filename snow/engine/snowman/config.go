@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowball"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman"
@@ -22,4 +24,16 @@ type Config struct {
 	Validators validators.Set
 	Params     snowball.Parameters
 	Consensus  snowman.Consensus
+
+	// Publishes the state changes of this chain
+	Events common.Publisher
+
+	// Chits sent in response to a query for a block are re-sent in response
+	// to queries for the same block for [ChitCacheDuration], as long as the
+	// preference doesn't change. If 0, chits aren't re-sent.
+	ChitCacheDuration time.Duration
+
+	// Selects the validators polled by queries. If nil, they're sampled with
+	// a local source of randomness.
+	QuerySampler common.QuerySampler
 }
@@ -29,6 +29,7 @@ func DefaultConfigs() (bootstrap.Config, Config) {
 		VM:         bootstrapConfig.VM,
 		Sender:     bootstrapConfig.Sender,
 		Validators: bootstrapConfig.Validators,
+		Events:     bootstrapConfig.Events,
 		Params: snowball.Parameters{
 			K:                     1,
 			Alpha:                 1,
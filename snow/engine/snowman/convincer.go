@@ -16,6 +16,8 @@ type convincer struct {
 	sender    common.Sender
 	vdr       ids.ShortID
 	requestID uint32
+	blkID     ids.ID
+	chits     *chitCache
 	sent      bool
 	abandoned bool
 	deps      ids.Set
@@ -42,6 +44,12 @@ func (c *convincer) Update() {
 	}
 	c.sent = true
 
-	pref := []ids.ID{c.consensus.Preference()}
-	c.sender.SendChits(c.vdr, c.requestID, pref)
+	pref := c.consensus.Preference()
+	c.sender.SendChits(c.vdr, c.requestID, []ids.ID{pref})
+
+	// If the query was abandoned, [blkID] may never have been issued, so the
+	// chits shouldn't be re-sent without looking it up again.
+	if !c.abandoned {
+		c.chits.Put(c.blkID, pref)
+	}
 }
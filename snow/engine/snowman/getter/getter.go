@@ -65,6 +65,7 @@ func (gh *getter) GetAccepted(validatorID ids.ShortID, requestID uint32, contain
 
 func (gh *getter) GetAncestors(validatorID ids.ShortID, requestID uint32, blkID ids.ID) error {
 	ancestorsBytes, err := block.GetAncestors(
+		gh.cfg.Ctx.ShutdownContext(),
 		gh.vm,
 		blkID,
 		gh.cfg.AncestorsMaxContainersSent,
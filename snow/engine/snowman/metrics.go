@@ -12,7 +12,7 @@ import (
 
 type metrics struct {
 	bootstrapFinished, numRequests, numBlocked, numBlockers, numNonVerifieds prometheus.Gauge
-	numBuilt, numBuildsFailed                                                prometheus.Counter
+	numBuilt, numBuildsFailed, numCachedChits                                prometheus.Counter
 	getAncestorsBlks                                                         metric.Averager
 }
 
@@ -49,6 +49,11 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		Name:      "blk_builds_failed",
 		Help:      "Number of BuildBlock calls that have failed",
 	})
+	m.numCachedChits = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cached_chits",
+		Help:      "Number of queries answered with recently sent chits",
+	})
 	m.getAncestorsBlks = metric.NewAveragerWithErrs(
 		namespace,
 		"get_ancestors_blks",
@@ -69,6 +74,7 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		reg.Register(m.numBlockers),
 		reg.Register(m.numBuilt),
 		reg.Register(m.numBuildsFailed),
+		reg.Register(m.numCachedChits),
 		reg.Register(m.numNonVerifieds),
 	)
 	return errs.Err
@@ -14,12 +14,16 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman/poll"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/events"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
-var _ Engine = &Transitive{}
+var (
+	_ Engine                       = &Transitive{}
+	_ validators.PeerInfoConnector = &Transitive{}
+)
 
 func New(config Config) (Engine, error) {
 	return newTransitive(config)
@@ -59,6 +63,9 @@ type Transitive struct {
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
 
+	// chits recently sent in response to queries
+	chits *chitCache
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -66,6 +73,9 @@ type Transitive struct {
 func newTransitive(config Config) (*Transitive, error) {
 	config.Ctx.Log.Info("initializing consensus engine")
 
+	if config.QuerySampler == nil {
+		config.QuerySampler = common.NewRandomQuerySampler()
+	}
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t := &Transitive{
 		Config:                  config,
@@ -74,6 +84,7 @@ func newTransitive(config Config) (*Transitive, error) {
 		AncestorsHandler:        common.NewNoOpAncestorsHandler(config.Ctx.Log),
 		pending:                 make(map[ids.ID]snowman.Block),
 		nonVerifieds:            NewAncestorTree(),
+		chits:                   newChitCache(config.ChitCacheDuration),
 		polls: poll.NewSet(factory,
 			config.Ctx.Log,
 			"",
@@ -125,12 +136,22 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 
 // PullQuery implements the QueryHandler interface
 func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID) error {
+	// If we recently answered a query for [blkID] and our preference hasn't
+	// changed since, answer with the same chits.
+	if pref := t.Consensus.Preference(); t.chits.Has(blkID, pref) {
+		t.metrics.numCachedChits.Inc()
+		t.Sender.SendChits(vdr, requestID, []ids.ID{pref})
+		return t.buildBlocks()
+	}
+
 	// Will send chits once we've issued block [blkID] into consensus
 	c := &convincer{
 		consensus: t.Consensus,
 		sender:    t.Sender,
 		vdr:       vdr,
 		requestID: requestID,
+		blkID:     blkID,
+		chits:     t.chits,
 		errs:      &t.errs,
 	}
 
@@ -250,6 +271,11 @@ func (t *Transitive) Connected(nodeID ids.ShortID, nodeVersion version.Applicati
 	return t.VM.Connected(nodeID, nodeVersion)
 }
 
+// ConnectedWithInfo implements the validators.PeerInfoConnector interface.
+func (t *Transitive) ConnectedWithInfo(nodeID ids.ShortID, peerInfo validators.PeerInfo) error {
+	return validators.Connect(t.VM, nodeID, peerInfo)
+}
+
 // Disconnected implements the InternalHandler interface.
 func (t *Transitive) Disconnected(nodeID ids.ShortID) error {
 	return t.VM.Disconnected(nodeID)
@@ -354,6 +380,7 @@ func (t *Transitive) Start(startReqID uint32) error {
 	t.Ctx.Log.Info("bootstrapping finished with %s as the last accepted block", lastAcceptedID)
 	t.metrics.bootstrapFinished.Set(1)
 	t.Ctx.SetState(snow.NormalOp)
+	t.Config.Events.PublishState(t.Ctx, snow.NormalOp)
 	return nil
 }
 
@@ -588,7 +615,7 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, blkID ids.ID) {
 func (t *Transitive) pullQuery(blkID ids.ID) {
 	t.Ctx.Log.Verbo("about to sample from: %s", t.Validators)
 	// The validators we will query
-	vdrs, err := t.Validators.Sample(t.Params.K)
+	vdrs, err := t.QuerySampler.Sample(t.Validators, t.Params.K, t.RequestID+1)
 	vdrBag := ids.ShortBag{}
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
@@ -608,7 +635,7 @@ func (t *Transitive) pullQuery(blkID ids.ID) {
 // send a push query for this block
 func (t *Transitive) pushQuery(blk snowman.Block) {
 	t.Ctx.Log.Verbo("about to sample from: %s", t.Validators)
-	vdrs, err := t.Validators.Sample(t.Params.K)
+	vdrs, err := t.QuerySampler.Sample(t.Validators, t.Params.K, t.RequestID+1)
 	vdrBag := ids.ShortBag{}
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
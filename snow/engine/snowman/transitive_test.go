@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow/choices"
@@ -3203,3 +3204,62 @@ func TestEngineBubbleVotesThroughInvalidChain(t *testing.T) {
 		t.Fatalf("Expected blk1 to be Accepted, but found status: %s", blk1.Status())
 	}
 }
+
+func TestEngineCachedChits(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	te.chits = newChitCache(time.Second)
+	now := time.Now()
+	te.chits.clock.Set(now)
+
+	getBlockCalls := 0
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		getBlockCalls++
+		if blkID != gBlk.ID() {
+			t.Fatalf("Loaded unknown block")
+		}
+		return gBlk, nil
+	}
+
+	chitsSent := 0
+	sender.SendChitsF = func(_ ids.ShortID, _ uint32, votes []ids.ID) {
+		chitsSent++
+		if len(votes) != 1 || votes[0] != gBlk.ID() {
+			t.Fatalf("Voted for the wrong block")
+		}
+	}
+
+	if err := te.PullQuery(vdr, 0, gBlk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if getBlockCalls != 1 || chitsSent != 1 {
+		t.Fatalf("Should have looked up the block and sent chits")
+	}
+
+	// The same query is answered without looking up the block again
+	// and pending blocks are still built
+	buildBlockCalls := 0
+	vm.BuildBlockF = func() (snowman.Block, error) {
+		buildBlockCalls++
+		return nil, errUnknownBlock
+	}
+	te.pendingBuildBlocks = 1
+	if err := te.PullQuery(vdr, 1, gBlk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if getBlockCalls != 1 || chitsSent != 2 {
+		t.Fatalf("Should have sent cached chits")
+	}
+	if buildBlockCalls != 1 {
+		t.Fatalf("Should have built the pending block")
+	}
+
+	// Once the chits have expired, the block is looked up again
+	te.chits.clock.Set(now.Add(2 * time.Second))
+	if err := te.PullQuery(vdr, 2, gBlk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if getBlockCalls != 2 || chitsSent != 3 {
+		t.Fatalf("Shouldn't have sent cached chits")
+	}
+}
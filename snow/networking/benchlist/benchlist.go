@@ -6,13 +6,16 @@ package benchlist
 import (
 	"container/heap"
 	"fmt"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/Toinounet21/avalanchego-mod/database"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
@@ -38,20 +41,70 @@ type Benchlist interface {
 	// IsBenched returns true if messages to [validatorID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(validatorID ids.ShortID) bool
+	// GetBenchInfo returns why and until when [validatorID] is benched.
+	// Returns false if [validatorID] isn't benched.
+	GetBenchInfo(validatorID ids.ShortID) (BenchInfo, bool)
+}
+
+// BenchInfo describes why, and until when, a validator is benched
+type BenchInfo struct {
+	// Time the validator was benched at
+	BenchedAt time.Time `json:"benchedAt"`
+	// Time the validator will leave the bench at
+	BenchedUntil time.Time `json:"benchedUntil"`
+	// Number of consecutive queries to the validator that timed out before it
+	// was benched
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// Time the first of those queries timed out at
+	FirstFailure time.Time `json:"firstFailure"`
+	// True if the validator was benched because its score was poor, rather
+	// than after enough consecutive queries to it timed out
+	PoorScore bool `json:"poorScore"`
+	// Decayed number of times the validator was benched before. The bench
+	// duration grows with it.
+	PriorOffenses float64 `json:"priorOffenses"`
+}
+
+// AdaptivePolicy makes the benchlist lenient towards sporadic failures and
+// harsh towards repeat offenders. The zero value benches validators after a
+// fixed number of consecutive failures, for a fixed duration.
+type AdaptivePolicy struct {
+	// Halflife of a validator's failure count. Failures decay between
+	// consecutive failures, so that a validator that only sporadically fails
+	// isn't benched. If 0, failures don't decay.
+	FailureHalflife time.Duration `json:"failureHalflife"`
+	// The bench duration is multiplied by [DurationMultiplier] for every prior
+	// offense of the validator. If <= 1, the bench duration doesn't grow.
+	DurationMultiplier float64 `json:"durationMultiplier"`
+	// Max amount of time a validator is benched for. If 0, the bench duration
+	// isn't capped.
+	MaxDuration time.Duration `json:"maxDuration"`
+	// Halflife of a validator's prior offenses. If 0, offenses are never
+	// forgotten.
+	OffenseHalflife time.Duration `json:"offenseHalflife"`
+}
+
+// decay returns [value] decayed by [halflife] over [elapsed]. If [halflife] is
+// 0, [value] doesn't decay.
+func decay(value float64, elapsed, halflife time.Duration) float64 {
+	if halflife <= 0 || elapsed <= 0 {
+		return value
+	}
+	return value * math.Exp2(-float64(elapsed)/float64(halflife))
 }
 
 // Data about a validator who is benched
 type benchData struct {
-	benchedUntil time.Time
-	validatorID  ids.ShortID
-	index        int
+	BenchInfo
+	validatorID ids.ShortID
+	index       int
 }
 
 // Implements heap.Interface. Each element is a benched validator
 type benchedQueue []*benchData
 
 func (bq benchedQueue) Len() int           { return len(bq) }
-func (bq benchedQueue) Less(i, j int) bool { return bq[i].benchedUntil.Before(bq[j].benchedUntil) }
+func (bq benchedQueue) Less(i, j int) bool { return bq[i].BenchedUntil.Before(bq[j].BenchedUntil) }
 func (bq benchedQueue) Swap(i, j int) {
 	bq[i], bq[j] = bq[j], bq[i]
 	bq[i].index = i
@@ -77,16 +130,35 @@ func (bq *benchedQueue) Pop() interface{} {
 type failureStreak struct {
 	// Time of first consecutive timeout
 	firstFailure time.Time
+	// Time of last consecutive timeout
+	lastFailure time.Time
 	// Number of consecutive message timeouts
 	consecutive int
+	// Number of consecutive message timeouts, decayed by the failure halflife
+	failures float64
+}
+
+type offenseRecord struct {
+	// Number of times the validator was benched, decayed by the offense
+	// halflife as of [last]
+	count float64
+	// Time the validator was last benched at
+	last time.Time
 }
 
 type benchlist struct {
 	lock sync.RWMutex
-	// This is the benchlist for chain [chainID]
-	chainID ids.ID
-	log     logging.Logger
-	metrics metrics
+	// This is the benchlist for chain [chainID], validated by subnet
+	// [subnetID]
+	chainID  ids.ID
+	subnetID ids.ID
+	log      logging.Logger
+	metrics  metrics
+
+	// Validator ID --> What's known about the validator. Lets benches and
+	// prior offenses survive restarts.
+	// [lock] must be held when touching [db]
+	db database.Database
 
 	// Fires when the next validator should leave the bench
 	// Calls [update] when it fires
@@ -101,6 +173,10 @@ type benchlist struct {
 	// Validator set of the network
 	vdrs validators.Set
 
+	// Validators with a poor score are benched after a single failed query.
+	// May be nil.
+	scorer common.PeerScorer
+
 	// Validator ID --> Consecutive failure information
 	// [streaklock] must be held when touching [failureStreaks]
 	streaklock     sync.Mutex
@@ -109,6 +185,10 @@ type benchlist struct {
 	// IDs of validators that are currently benched
 	benchlistSet ids.ShortSet
 
+	// Validator ID --> Times the validator was benched
+	// [lock] must be held when touching [offenses]
+	offenses map[ids.ShortID]offenseRecord
+
 	// Min heap containing benched validators and their endtimes
 	// Pop() returns the next validator to leave
 	benchedQueue benchedQueue
@@ -119,24 +199,32 @@ type benchlist struct {
 	threshold              int
 	minimumFailingDuration time.Duration
 
-	// A benched validator will be benched for between [duration/2] and [duration]
+	// A benched validator will be benched for between [duration/2] and [duration],
+	// scaled by [policy] for repeat offenders
 	duration time.Duration
 
+	policy AdaptivePolicy
+
 	// The maximum percentage of total network stake that may be benched
 	// Must be in [0,1)
 	maxPortion float64
 }
 
-// NewBenchlist returns a new Benchlist
+// NewBenchlist returns a new Benchlist that persists its benches in [db], and
+// that benches the validators that were benched in [db] when it was last used.
 func NewBenchlist(
 	chainID ids.ID,
+	subnetID ids.ID,
 	log logging.Logger,
 	benchable Benchable,
 	validators validators.Set,
+	scorer common.PeerScorer,
 	threshold int,
 	minimumFailingDuration,
 	duration time.Duration,
 	maxPortion float64,
+	policy AdaptivePolicy,
+	db database.Database,
 	registerer prometheus.Registerer,
 ) (Benchlist, error) {
 	if maxPortion < 0 || maxPortion >= 1 {
@@ -144,19 +232,111 @@ func NewBenchlist(
 	}
 	benchlist := &benchlist{
 		chainID:                chainID,
+		subnetID:               subnetID,
 		log:                    log,
+		db:                     db,
 		failureStreaks:         make(map[ids.ShortID]failureStreak),
 		benchlistSet:           ids.ShortSet{},
+		offenses:               make(map[ids.ShortID]offenseRecord),
 		benchable:              benchable,
 		vdrs:                   validators,
+		scorer:                 scorer,
 		threshold:              threshold,
 		minimumFailingDuration: minimumFailingDuration,
 		duration:               duration,
 		maxPortion:             maxPortion,
+		policy:                 policy,
+	}
+	if err := benchlist.metrics.Initialize(registerer); err != nil {
+		return nil, err
 	}
 	benchlist.timer = timer.NewTimer(benchlist.update)
 	go benchlist.timer.Dispatch()
-	return benchlist, benchlist.metrics.Initialize(registerer)
+	if err := benchlist.restore(); err != nil {
+		benchlist.timer.Stop()
+		return nil, fmt.Errorf("couldn't restore benchlist: %w", err)
+	}
+	return benchlist, nil
+}
+
+// restore benches the validators whose bench, as persisted in [b.db], isn't
+// over yet, and restores the prior offenses of every validator in [b.db]
+func (b *benchlist) restore() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	it := b.db.NewIterator()
+	defer it.Release()
+
+	now := b.clock.Time()
+	for it.Next() {
+		validatorID, err := ids.ToShortID(it.Key())
+		if err != nil {
+			return fmt.Errorf("failed to parse benched node ID: %w", err)
+		}
+		record, err := parseBenchRecord(it.Value())
+		if err != nil {
+			return fmt.Errorf("failed to parse bench of %s: %w", validatorID, err)
+		}
+		if record.subnetID != b.subnetID {
+			// The chain is validated by another subnet than when the record
+			// was written, so the record is meaningless
+			continue
+		}
+
+		b.offenses[validatorID] = record.offense
+		if !record.benched || !now.Before(record.info.BenchedUntil) {
+			continue
+		}
+		b.benchlistSet.Add(validatorID)
+		b.benchable.Benched(b.chainID, validatorID)
+		heap.Push(
+			&b.benchedQueue,
+			&benchData{
+				BenchInfo:   record.info,
+				validatorID: validatorID,
+			},
+		)
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if b.benchedQueue.Len() != 0 {
+		b.log.Info("restored the bench of %d validators", b.benchedQueue.Len())
+	}
+
+	// Set [b.timer] to fire when next validator should leave bench
+	b.setNextLeaveTime()
+
+	// Update metrics
+	b.metrics.numBenched.Set(float64(b.benchedQueue.Len()))
+	benchedStake, err := b.vdrs.SubsetWeight(b.benchlistSet)
+	if err != nil {
+		return err
+	}
+	b.metrics.weightBenched.Set(float64(benchedStake))
+	return nil
+}
+
+// persist writes the prior offenses of [validatorID] and, if it's benched, its
+// bench [info] to [b.db]
+// Assumes [b.lock] is held
+func (b *benchlist) persist(validatorID ids.ShortID, info *BenchInfo) {
+	record := benchRecord{
+		subnetID: b.subnetID,
+		offense:  b.offenses[validatorID],
+	}
+	if info != nil {
+		record.benched = true
+		record.info = *info
+	}
+	bytes, err := record.Bytes()
+	if err == nil {
+		err = b.db.Put(validatorID[:], bytes)
+	}
+	if err != nil {
+		b.log.Error("couldn't persist bench of %s: %s", validatorID, err)
+	}
 }
 
 // Update removes benched validators whose time on the bench is over
@@ -187,6 +367,7 @@ func (b *benchlist) remove(validator *benchData) {
 	heap.Remove(&b.benchedQueue, validator.index)
 	b.benchlistSet.Remove(id)
 	b.benchable.Unbenched(b.chainID, id)
+	b.persist(id, nil)
 
 	// Update metrics
 	b.metrics.numBenched.Set(float64(b.benchedQueue.Len()))
@@ -207,7 +388,7 @@ func (b *benchlist) nextToLeave(now time.Time) *benchData {
 		return nil
 	}
 	next := b.benchedQueue[0]
-	if now.Before(next.benchedUntil) {
+	if now.Before(next.BenchedUntil) {
 		return nil
 	}
 	return next
@@ -222,7 +403,7 @@ func (b *benchlist) setNextLeaveTime() {
 	}
 	now := b.clock.Time()
 	next := b.benchedQueue[0]
-	nextLeave := next.benchedUntil.Sub(now)
+	nextLeave := next.BenchedUntil.Sub(now)
 	b.timer.SetTimeoutIn(nextLeave)
 }
 
@@ -234,6 +415,21 @@ func (b *benchlist) IsBenched(validatorID ids.ShortID) bool {
 	return b.isBenched(validatorID)
 }
 
+// GetBenchInfo returns why and until when [validatorID] is benched.
+// Benchings that expired but weren't cleaned up yet aren't returned.
+func (b *benchlist) GetBenchInfo(validatorID ids.ShortID) (BenchInfo, bool) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	now := b.clock.Time()
+	for _, benchData := range b.benchedQueue {
+		if benchData.validatorID == validatorID {
+			return benchData.BenchInfo, now.Before(benchData.BenchedUntil)
+		}
+	}
+	return BenchInfo{}, false
+}
+
 // isBenched checks if [validatorID] is currently benched
 // and calls cleanup if its benching period has elapsed
 // Assumes [b.lock] is held.
@@ -263,9 +459,11 @@ func (b *benchlist) RegisterFailure(validatorID ids.ShortID) {
 
 	b.streaklock.Lock()
 	failureStreak := b.failureStreaks[validatorID]
+	now := b.clock.Time()
 	// Increment consecutive failures
 	failureStreak.consecutive++
-	now := b.clock.Time()
+	failureStreak.failures = decay(failureStreak.failures, now.Sub(failureStreak.lastFailure), b.policy.FailureHalflife) + 1
+	failureStreak.lastFailure = now
 	// Update first failure time
 	if failureStreak.firstFailure.IsZero() {
 		// This is the first consecutive failure
@@ -274,14 +472,17 @@ func (b *benchlist) RegisterFailure(validatorID ids.ShortID) {
 	b.failureStreaks[validatorID] = failureStreak
 	b.streaklock.Unlock()
 
-	if failureStreak.consecutive >= b.threshold && now.After(failureStreak.firstFailure.Add(b.minimumFailingDuration)) {
-		b.bench(validatorID)
+	if failureStreak.failures >= float64(b.threshold) && now.After(failureStreak.firstFailure.Add(b.minimumFailingDuration)) {
+		b.bench(validatorID, failureStreak, false)
+	} else if b.scorer != nil && b.scorer.IsPoor(validatorID) {
+		b.bench(validatorID, failureStreak, true)
 	}
 }
 
 // Assumes [b.lock] is held
 // Assumes [validatorID] is not already benched
-func (b *benchlist) bench(validatorID ids.ShortID) {
+// [poorScore] is true if [validatorID] is benched because its score is poor.
+func (b *benchlist) bench(validatorID ids.ShortID, failureStreak failureStreak, poorScore bool) {
 	benchedStake, err := b.vdrs.SubsetWeight(b.benchlistSet)
 	if err != nil {
 		// This should never happen
@@ -316,32 +517,58 @@ func (b *benchlist) bench(validatorID ids.ShortID) {
 		return
 	}
 
-	// Validator is benched for between [b.duration]/2 and [b.duration]
+	// Validator is benched for between [duration]/2 and [duration]
 	now := b.clock.Time()
-	minBenchDuration := b.duration / 2
+	priorOffenses := b.priorOffenses(validatorID, now)
+	duration := b.benchDuration(priorOffenses)
+	minBenchDuration := duration / 2
 	minBenchedUntil := now.Add(minBenchDuration)
-	maxBenchedUntil := now.Add(b.duration)
+	maxBenchedUntil := now.Add(duration)
 	diff := maxBenchedUntil.Sub(minBenchedUntil)
 	benchedUntil := minBenchedUntil.Add(time.Duration(rand.Float64() * float64(diff))) // #nosec G404
 
 	// Add to benchlist times with randomized delay
 	b.benchlistSet.Add(validatorID)
+	b.offenses[validatorID] = offenseRecord{
+		count: priorOffenses + 1,
+		last:  now,
+	}
 	b.benchable.Benched(b.chainID, validatorID)
 
 	b.streaklock.Lock()
 	delete(b.failureStreaks, validatorID)
 	b.streaklock.Unlock()
 
+	benchInfo := BenchInfo{
+		BenchedAt:           now,
+		BenchedUntil:        benchedUntil,
+		ConsecutiveFailures: failureStreak.consecutive,
+		FirstFailure:        failureStreak.firstFailure,
+		PoorScore:           poorScore,
+		PriorOffenses:       priorOffenses,
+	}
 	heap.Push(
 		&b.benchedQueue,
-		&benchData{validatorID: validatorID, benchedUntil: benchedUntil},
-	)
-	b.log.Debug(
-		"benching validator %s for %s after %d consecutive failed queries.",
-		validatorID,
-		benchedUntil.Sub(now),
-		b.threshold,
+		&benchData{
+			BenchInfo:   benchInfo,
+			validatorID: validatorID,
+		},
 	)
+	b.persist(validatorID, &benchInfo)
+	if poorScore {
+		b.log.Debug(
+			"benching validator %s for %s because its score is poor.",
+			validatorID,
+			benchedUntil.Sub(now),
+		)
+	} else {
+		b.log.Debug(
+			"benching validator %s for %s after %d consecutive failed queries.",
+			validatorID,
+			benchedUntil.Sub(now),
+			b.threshold,
+		)
+	}
 
 	// Set [b.timer] to fire when next validator should leave bench
 	b.setNextLeaveTime()
@@ -350,3 +577,30 @@ func (b *benchlist) bench(validatorID ids.ShortID) {
 	b.metrics.numBenched.Set(float64(b.benchedQueue.Len()))
 	b.metrics.weightBenched.Set(float64(newBenchedStake))
 }
+
+// priorOffenses returns the decayed number of times [validatorID] was benched
+// as of [now]
+// Assumes [b.lock] is held
+func (b *benchlist) priorOffenses(validatorID ids.ShortID, now time.Time) float64 {
+	offense, ok := b.offenses[validatorID]
+	if !ok {
+		return 0
+	}
+	return decay(offense.count, now.Sub(offense.last), b.policy.OffenseHalflife)
+}
+
+// benchDuration returns the max amount of time a validator with
+// [priorOffenses] is benched for
+func (b *benchlist) benchDuration(priorOffenses float64) time.Duration {
+	duration := float64(b.duration)
+	if b.policy.DurationMultiplier > 1 {
+		duration *= math.Pow(b.policy.DurationMultiplier, priorOffenses)
+	}
+	if b.policy.MaxDuration > 0 && duration > float64(b.policy.MaxDuration) {
+		return b.policy.MaxDuration
+	}
+	if duration > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(duration)
+}
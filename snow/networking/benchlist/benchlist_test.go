@@ -11,7 +11,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
@@ -47,14 +49,18 @@ func TestBenchlistAdd(t *testing.T) {
 	duration := time.Minute
 	maxPortion := 0.5
 	benchIntf, err := NewBenchlist(
+		ids.Empty,
 		ids.Empty,
 		logging.NoLog{},
 		benchable,
 		vdrs,
+		nil,
 		threshold,
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		AdaptivePolicy{},
+		memdb.New(),
 		prometheus.NewRegistry(),
 	)
 	if err != nil {
@@ -124,13 +130,32 @@ func TestBenchlistAdd(t *testing.T) {
 
 	next := b.benchedQueue[0]
 	assert.Equal(t, vdr0.ID(), next.validatorID)
-	assert.True(t, !next.benchedUntil.After(now.Add(duration)))
-	assert.True(t, !next.benchedUntil.Before(now.Add(duration/2)))
+	assert.True(t, !next.BenchedUntil.After(now.Add(duration)))
+	assert.True(t, !next.BenchedUntil.Before(now.Add(duration/2)))
 	assert.Len(t, b.failureStreaks, 0)
 	assert.True(t, benched)
 	benchable.BenchedF = nil
 	b.lock.Unlock()
 
+	benchInfo, ok := b.GetBenchInfo(vdr0.ID())
+	assert.True(t, ok)
+	assert.Equal(t, now, benchInfo.BenchedAt)
+	assert.Equal(t, next.BenchedUntil, benchInfo.BenchedUntil)
+	assert.Equal(t, threshold+1, benchInfo.ConsecutiveFailures)
+	assert.Equal(t, now.Add(-minimumFailingDuration).Add(-time.Second), benchInfo.FirstFailure)
+	_, ok = b.GetBenchInfo(vdr1.ID())
+	assert.False(t, ok)
+
+	// Expired benchings aren't reported, even if they weren't cleaned up yet
+	b.lock.Lock()
+	b.clock.Set(next.BenchedUntil)
+	b.lock.Unlock()
+	_, ok = b.GetBenchInfo(vdr0.ID())
+	assert.False(t, ok)
+	b.lock.Lock()
+	b.clock.Set(now)
+	b.lock.Unlock()
+
 	// Give another validator [threshold-1] failures
 	for i := 0; i < threshold-1; i++ {
 		b.RegisterFailure(vdr1.ID())
@@ -190,14 +215,18 @@ func TestBenchlistMaxStake(t *testing.T) {
 	// Shouldn't bench more than 2550 (5100/2)
 	maxPortion := 0.5
 	benchIntf, err := NewBenchlist(
+		ids.Empty,
 		ids.Empty,
 		logging.NoLog{},
 		&TestBenchable{T: t},
 		vdrs,
+		nil,
 		threshold,
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		AdaptivePolicy{},
+		memdb.New(),
 		prometheus.NewRegistry(),
 	)
 	if err != nil {
@@ -285,11 +314,11 @@ func TestBenchlistMaxStake(t *testing.T) {
 	assert.Contains(t, b.failureStreaks, vdr2.ID())
 
 	// Ensure the benched queue root has the min end time
-	minEndTime := b.benchedQueue[0].benchedUntil
+	minEndTime := b.benchedQueue[0].BenchedUntil
 	benchedIDs := []ids.ShortID{vdr0.ID(), vdr1.ID(), vdr4.ID()}
 	for _, benchedVdr := range b.benchedQueue {
 		assert.Contains(t, benchedIDs, benchedVdr.validatorID)
-		assert.True(t, !benchedVdr.benchedUntil.Before(minEndTime))
+		assert.True(t, !benchedVdr.BenchedUntil.Before(minEndTime))
 	}
 
 	b.lock.Unlock()
@@ -330,14 +359,18 @@ func TestBenchlistRemove(t *testing.T) {
 	duration := 2 * time.Second
 	maxPortion := 0.76 // can bench 3 of the 5 validators
 	benchIntf, err := NewBenchlist(
+		ids.Empty,
 		ids.Empty,
 		logging.NoLog{},
 		benchable,
 		vdrs,
+		nil,
 		threshold,
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		AdaptivePolicy{},
+		memdb.New(),
 		prometheus.NewRegistry(),
 	)
 	if err != nil {
@@ -377,11 +410,11 @@ func TestBenchlistRemove(t *testing.T) {
 	assert.Len(t, b.failureStreaks, 0)
 
 	// Ensure the benched queue root has the min end time
-	minEndTime := b.benchedQueue[0].benchedUntil
+	minEndTime := b.benchedQueue[0].BenchedUntil
 	benchedIDs := []ids.ShortID{vdr0.ID(), vdr1.ID(), vdr2.ID()}
 	for _, benchedVdr := range b.benchedQueue {
 		assert.Contains(t, benchedIDs, benchedVdr.validatorID)
-		assert.True(t, !benchedVdr.benchedUntil.Before(minEndTime))
+		assert.True(t, !benchedVdr.BenchedUntil.Before(minEndTime))
 	}
 
 	// Set the benchlist's clock past when all validators should be unbenched
@@ -419,3 +452,238 @@ func TestBenchlistRemove(t *testing.T) {
 
 	assert.Equal(t, 3, count)
 }
+
+// Test that validators with a poor score are benched after a single failure,
+// without exceeding the max portion of benched stake
+func TestBenchlistPoorScore(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(1000)
+	vdr1 := validators.GenerateRandomValidator(1000)
+	vdr2 := validators.GenerateRandomValidator(1000)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+		vdrs.AddWeight(vdr2.ID(), vdr2.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benchable := &TestBenchable{T: t}
+	benchable.Default(false)
+	scorer := &common.PeerScorerTest{
+		IsPoorF: func(validatorID ids.ShortID) bool {
+			return validatorID != vdr2.ID()
+		},
+	}
+
+	// Shouldn't bench more than 1500 (3000/2)
+	maxPortion := 0.5
+	benchIntf, err := NewBenchlist(
+		ids.Empty,
+		ids.Empty,
+		logging.NoLog{},
+		benchable,
+		vdrs,
+		scorer,
+		3,
+		minimumFailingDuration,
+		time.Hour,
+		maxPortion,
+		AdaptivePolicy{},
+		memdb.New(),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := benchIntf.(*benchlist)
+	defer b.timer.Stop()
+	b.clock.Set(time.Now())
+
+	for _, vdr := range []validators.Validator{vdr0, vdr1, vdr2} {
+		b.RegisterFailure(vdr.ID())
+	}
+
+	// Only vdr0 should be benched. vdr2 doesn't have a poor score, and
+	// benching vdr1 would exceed the max benched stake.
+	assert.True(t, b.IsBenched(vdr0.ID()))
+	assert.False(t, b.IsBenched(vdr1.ID()))
+	assert.False(t, b.IsBenched(vdr2.ID()))
+
+	benchInfo, ok := b.GetBenchInfo(vdr0.ID())
+	assert.True(t, ok)
+	assert.True(t, benchInfo.PoorScore)
+	assert.Equal(t, 1, benchInfo.ConsecutiveFailures)
+}
+
+// Test that sporadic failures decay and that repeat offenders are benched for
+// longer
+func TestBenchlistAdaptivePolicy(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(1000)
+	vdr1 := validators.GenerateRandomValidator(1000)
+	vdr2 := validators.GenerateRandomValidator(1000)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+		vdrs.AddWeight(vdr2.ID(), vdr2.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benchable := &TestBenchable{T: t}
+	benchable.Default(false)
+
+	threshold := 3
+	duration := time.Minute
+	benchIntf, err := NewBenchlist(
+		ids.Empty,
+		ids.Empty,
+		logging.NoLog{},
+		benchable,
+		vdrs,
+		nil,
+		threshold,
+		0,
+		duration,
+		0.5,
+		AdaptivePolicy{
+			FailureHalflife:    time.Minute,
+			DurationMultiplier: 2,
+			MaxDuration:        3 * time.Minute,
+			OffenseHalflife:    time.Hour,
+		},
+		memdb.New(),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := benchIntf.(*benchlist)
+	defer b.timer.Stop()
+	now := time.Now()
+	b.clock.Set(now)
+
+	// Failures that are a halflife apart never add up to the threshold
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Minute)
+		b.clock.Set(now)
+		b.RegisterFailure(vdr0.ID())
+	}
+	assert.False(t, b.IsBenched(vdr0.ID()))
+	assert.Equal(t, 10, b.failureStreaks[vdr0.ID()].consecutive)
+	assert.Less(t, b.failureStreaks[vdr0.ID()].failures, float64(threshold))
+
+	// Failures in quick succession still bench the validator, once time
+	// passed since the first failure
+	now = now.Add(time.Second)
+	b.clock.Set(now)
+	for i := 0; i < threshold; i++ {
+		b.RegisterFailure(vdr1.ID())
+	}
+	assert.False(t, b.IsBenched(vdr1.ID()))
+	now = now.Add(time.Millisecond)
+	b.clock.Set(now)
+	b.RegisterFailure(vdr1.ID())
+	assert.True(t, b.IsBenched(vdr1.ID()))
+	benchInfo, ok := b.GetBenchInfo(vdr1.ID())
+	assert.True(t, ok)
+	assert.Zero(t, benchInfo.PriorOffenses)
+	assert.False(t, benchInfo.BenchedUntil.After(now.Add(duration)))
+
+	// The bench duration grows with repeated offenses, up to the max
+	b.lock.Lock()
+	assert.Equal(t, duration, b.benchDuration(0))
+	assert.Equal(t, 2*duration, b.benchDuration(1))
+	assert.Equal(t, 3*time.Minute, b.benchDuration(2))
+
+	// Offenses decay
+	b.offenses[vdr2.ID()] = offenseRecord{count: 2, last: now}
+	assert.InDelta(t, 1, b.priorOffenses(vdr2.ID(), now.Add(time.Hour)), 1e-9)
+	b.lock.Unlock()
+}
+
+// Test that benches and prior offenses survive a restart
+func TestBenchlistRestore(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(1000)
+	vdr1 := validators.GenerateRandomValidator(1000)
+	vdr2 := validators.GenerateRandomValidator(1000)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+		vdrs.AddWeight(vdr2.ID(), vdr2.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benchable := &TestBenchable{T: t}
+	benchable.Default(false)
+	scorer := &common.PeerScorerTest{
+		IsPoorF: func(ids.ShortID) bool { return true },
+	}
+
+	db := memdb.New()
+	subnetID := ids.GenerateTestID()
+	newBenchlist := func(subnetID ids.ID) *benchlist {
+		benchIntf, err := NewBenchlist(
+			ids.Empty,
+			subnetID,
+			logging.NoLog{},
+			benchable,
+			vdrs,
+			scorer,
+			3,
+			minimumFailingDuration,
+			time.Hour,
+			0.5,
+			AdaptivePolicy{},
+			db,
+			prometheus.NewRegistry(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return benchIntf.(*benchlist)
+	}
+
+	b := newBenchlist(subnetID)
+	b.RegisterFailure(vdr0.ID())
+	assert.True(t, b.IsBenched(vdr0.ID()))
+	benchInfo, ok := b.GetBenchInfo(vdr0.ID())
+	assert.True(t, ok)
+	b.timer.Stop()
+
+	// The bench is restored after a restart
+	restored := 0
+	benchable.BenchedF = func(_ ids.ID, validatorID ids.ShortID) {
+		assert.Equal(t, vdr0.ID(), validatorID)
+		restored++
+	}
+	b = newBenchlist(subnetID)
+	assert.Equal(t, 1, restored)
+	assert.True(t, b.IsBenched(vdr0.ID()))
+	assert.False(t, b.IsBenched(vdr1.ID()))
+	restoredInfo, ok := b.GetBenchInfo(vdr0.ID())
+	assert.True(t, ok)
+	assert.Equal(t, benchInfo.BenchedUntil.Unix(), restoredInfo.BenchedUntil.Unix())
+	assert.True(t, restoredInfo.PoorScore)
+	assert.Contains(t, b.offenses, vdr0.ID())
+	b.timer.Stop()
+
+	// Benches recorded for another subnet are ignored
+	restored = 0
+	b = newBenchlist(ids.GenerateTestID())
+	defer b.timer.Stop()
+	assert.Zero(t, restored)
+	assert.False(t, b.IsBenched(vdr0.ID()))
+}
@@ -8,8 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 )
@@ -37,6 +40,9 @@ type Manager interface {
 	// [validatorID] is benched. If called on an id.ShortID that does
 	// not map to a validator, it will return an empty array.
 	GetBenched(validatorID ids.ShortID) []ids.ID
+	// GetBenchInfo returns, for each chain where [validatorID] is benched, why
+	// and until when it's benched.
+	GetBenchInfo(validatorID ids.ShortID) map[ids.ID]BenchInfo
 }
 
 // Config defines the configuration for a benchlist
@@ -49,6 +55,12 @@ type Config struct {
 	Duration               time.Duration      `json:"duration"`
 	MaxPortion             float64            `json:"maxPortion"`
 	PeerSummaryEnabled     bool               `json:"peerSummaryEnabled"`
+	AdaptivePolicy         `json:"adaptivePolicy"`
+	// Validators with a poor score are benched after a single failed query,
+	// subject to [MaxPortion]. May be nil.
+	Scorer common.PeerScorer `json:"-"`
+	// Persists the benches of every chain, so that they survive restarts
+	DB database.Database `json:"-"`
 }
 
 type manager struct {
@@ -104,6 +116,21 @@ func (m *manager) GetBenched(validatorID ids.ShortID) []ids.ID {
 	return benched
 }
 
+// GetBenchInfo returns, for each chain where [validatorID] is benched, why and
+// until when it's benched.
+func (m *manager) GetBenchInfo(validatorID ids.ShortID) map[ids.ID]BenchInfo {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	benchInfos := make(map[ids.ID]BenchInfo)
+	for chainID, benchlist := range m.chainBenchlists {
+		if benchInfo, benched := benchlist.GetBenchInfo(validatorID); benched {
+			benchInfos[chainID] = benchInfo
+		}
+	}
+	return benchInfos
+}
+
 func (m *manager) RegisterChain(ctx *snow.ConsensusContext) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -128,13 +155,17 @@ func (m *manager) RegisterChain(ctx *snow.ConsensusContext) error {
 
 	benchlist, err := NewBenchlist(
 		ctx.ChainID,
+		ctx.SubnetID,
 		ctx.Log,
 		m.config.Benchable,
 		vdrs,
+		m.config.Scorer,
 		m.config.Threshold,
 		m.config.MinimumFailingDuration,
 		m.config.Duration,
 		m.config.MaxPortion,
+		m.config.AdaptivePolicy,
+		prefixdb.New(ctx.ChainID[:], m.config.DB),
 		ctx.Registerer,
 	)
 	if err != nil {
@@ -179,3 +210,6 @@ func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID)       {}
 func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)        {}
 func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool         { return false }
 func (noBenchlist) GetBenched(ids.ShortID) []ids.ID            { return []ids.ID{} }
+func (noBenchlist) GetBenchInfo(ids.ShortID) map[ids.ID]BenchInfo {
+	return map[ids.ID]BenchInfo{}
+}
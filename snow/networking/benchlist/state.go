@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchlist
+
+import (
+	"math"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const benchRecordLen = hashing.HashLen + // subnet ID
+	2*wrappers.LongLen + // prior offenses
+	wrappers.BoolLen + // benched
+	3*wrappers.LongLen + // bench times
+	wrappers.IntLen + // consecutive failures
+	wrappers.BoolLen + // poor score
+	wrappers.LongLen // prior offenses when benched
+
+// benchRecord is what's persisted about a validator, so that a restart doesn't
+// unbench it or forget its prior offenses
+type benchRecord struct {
+	// Subnet of the chain the validator is benched on
+	subnetID ids.ID
+	offense  offenseRecord
+	// If false, the validator isn't benched and [info] is empty
+	benched bool
+	info    BenchInfo
+}
+
+func (r *benchRecord) Bytes() ([]byte, error) {
+	p := wrappers.Packer{Bytes: make([]byte, benchRecordLen)}
+	p.PackFixedBytes(r.subnetID[:])
+	p.PackLong(math.Float64bits(r.offense.count))
+	packTime(&p, r.offense.last)
+	p.PackBool(r.benched)
+	packTime(&p, r.info.BenchedAt)
+	packTime(&p, r.info.BenchedUntil)
+	packTime(&p, r.info.FirstFailure)
+	p.PackInt(uint32(r.info.ConsecutiveFailures))
+	p.PackBool(r.info.PoorScore)
+	p.PackLong(math.Float64bits(r.info.PriorOffenses))
+	return p.Bytes, p.Err
+}
+
+func parseBenchRecord(b []byte) (*benchRecord, error) {
+	r := &benchRecord{}
+	p := wrappers.Packer{Bytes: b}
+	copy(r.subnetID[:], p.UnpackFixedBytes(hashing.HashLen))
+	r.offense.count = math.Float64frombits(p.UnpackLong())
+	r.offense.last = unpackTime(&p)
+	r.benched = p.UnpackBool()
+	r.info.BenchedAt = unpackTime(&p)
+	r.info.BenchedUntil = unpackTime(&p)
+	r.info.FirstFailure = unpackTime(&p)
+	r.info.ConsecutiveFailures = int(p.UnpackInt())
+	r.info.PoorScore = p.UnpackBool()
+	r.info.PriorOffenses = math.Float64frombits(p.UnpackLong())
+	return r, p.Err
+}
+
+// packTime packs [t] with a precision of a second. The zero time is packed
+// such that it's unpacked as the zero time.
+func packTime(p *wrappers.Packer, t time.Time) {
+	p.PackLong(uint64(t.Unix()))
+}
+
+func unpackTime(p *wrappers.Packer) time.Time {
+	return time.Unix(int64(p.UnpackLong()), 0)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/timeout"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
 	"github.com/Toinounet21/avalanchego-mod/utils/linkedhashmap"
@@ -28,6 +29,7 @@ import (
 
 var (
 	errUnknownChain = errors.New("received message for unknown chain")
+	errNotValidator = errors.New("received message from a non-validator of a validator only chain")
 
 	_ Router = &ChainRouter{}
 )
@@ -57,7 +59,7 @@ type ChainRouter struct {
 
 	gossiper     *timer.Repeater
 	closeTimeout time.Duration
-	peers        map[ids.ShortID]version.Application
+	peers        map[ids.ShortID]validators.PeerInfo
 	// node ID --> chains that node is benched on
 	// invariant: if a node is benched on any chain, it is treated as disconnected on all chains
 	benched        map[ids.ShortID]ids.Set
@@ -104,8 +106,8 @@ func (cr *ChainRouter) Initialize(
 	cr.criticalChains = criticalChains
 	cr.onFatal = onFatal
 	cr.timedRequests = linkedhashmap.New()
-	cr.peers = make(map[ids.ShortID]version.Application)
-	cr.peers[nodeID] = version.CurrentApp
+	cr.peers = make(map[ids.ShortID]validators.PeerInfo)
+	cr.peers[nodeID] = validators.PeerInfo{Version: version.CurrentApp}
 	cr.healthConfig = healthConfig
 	cr.requestIDBytes = make([]byte, hashing.AddrLen+hashing.HashLen+wrappers.IntLen+wrappers.ByteLen) // Validator ID, Chain ID, Request ID, Msg Type
 
@@ -182,7 +184,7 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 
 	// Get the chain, if it exists
 	chain, exists := cr.chains[chainID]
-	if !exists || !chain.isValidator(nodeID) {
+	if !exists {
 		cr.log.Debug(
 			"Message %s from (%s. %s) dropped. Error: %s",
 			op,
@@ -190,10 +192,23 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 			chainID,
 			errUnknownChain,
 		)
+		cr.metrics.droppedUnknownChain.Inc()
 
 		msg.OnFinishedHandling()
 		return
 	}
+	if !chain.isValidator(nodeID) {
+		cr.log.Debug(
+			"Message %s from (%s. %s) dropped. Error: %s",
+			op,
+			nodeID,
+			chainID,
+			errNotValidator,
+		)
+
+		chain.drop(msg, dropNotValidator)
+		return
+	}
 
 	if _, notRequested := message.UnrequestedOps[op]; notRequested ||
 		(op == message.Put && requestID == constants.GossipMsgRequestID) {
@@ -201,7 +216,14 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 			cr.log.Debug("dropping %s and skipping queue since the chain is currently executing", op)
 			cr.metrics.droppedRequests.Inc()
 
-			msg.OnFinishedHandling()
+			chain.drop(msg, dropExecuting)
+			return
+		}
+		if chain.shedsLoad(op, requestID) {
+			cr.log.Debug("dropping %s and skipping queue since the chain exceeds its quota", op)
+			cr.metrics.droppedRequests.Inc()
+
+			chain.drop(msg, dropQuota)
 			return
 		}
 		chain.Push(msg)
@@ -214,7 +236,7 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 		uniqueRequestID, req := cr.clearRequest(expectedResponse, nodeID, chainID, requestID)
 		if req == nil {
 			// This was a duplicated response.
-			msg.OnFinishedHandling()
+			chain.drop(msg, dropUnrequested)
 			return
 		}
 
@@ -230,14 +252,14 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 		cr.log.Debug("dropping %s and skipping queue since the chain is currently executing", op)
 		cr.metrics.droppedRequests.Inc()
 
-		msg.OnFinishedHandling()
+		chain.drop(msg, dropExecuting)
 		return
 	}
 
 	uniqueRequestID, req := cr.clearRequest(op, nodeID, chainID, requestID)
 	if req == nil {
 		// We didn't request this message.
-		msg.OnFinishedHandling()
+		chain.drop(msg, dropUnrequested)
 		return
 	}
 
@@ -265,19 +287,27 @@ func (cr *ChainRouter) Shutdown() {
 		chain.StartShutdown()
 	}
 
-	ticker := time.NewTicker(cr.closeTimeout)
-	timedOut := false
-	for _, chain := range prevChains {
+	// The chains finish their in-flight operations concurrently, so they
+	// share a single deadline
+	cr.log.Info("waiting up to %s for %d chains to shut down", cr.closeTimeout, len(prevChains))
+	timer := time.NewTimer(cr.closeTimeout)
+	defer timer.Stop()
+	for chainID, chain := range prevChains {
 		select {
 		case <-chain.closed:
-		case <-ticker.C:
-			timedOut = true
+			cr.log.Debug("chain %s shut down", chainID)
+		case <-timer.C:
+			for chainID, chain := range prevChains {
+				select {
+				case <-chain.closed:
+				default:
+					cr.log.Warn("timed out while waiting for chain %s to shut down", chainID)
+				}
+			}
+			return
 		}
 	}
-	if timedOut {
-		cr.log.Warn("timed out while shutting down the chains")
-	}
-	ticker.Stop()
+	cr.log.Info("shut down all chains")
 }
 
 // AddChain registers the specified chain so that incoming
@@ -291,27 +321,27 @@ func (cr *ChainRouter) AddChain(chain *Handler) {
 	chain.onCloseF = func() { cr.removeChain(chainID) }
 	cr.chains[chainID] = chain
 
-	for validatorID, version := range cr.peers {
+	for validatorID, peerInfo := range cr.peers {
 		// If this validator is benched on any chain, treat them as disconnected on all chains
 		if _, benched := cr.benched[validatorID]; !benched {
-			msg := cr.msgCreator.InternalConnected(validatorID, version)
+			msg := cr.msgCreator.InternalConnected(validatorID, peerInfo)
 			chain.Push(msg)
 		}
 	}
 }
 
 // Connected routes an incoming notification that a validator was just connected
-func (cr *ChainRouter) Connected(validatorID ids.ShortID, nodeVersion version.Application) {
+func (cr *ChainRouter) Connected(validatorID ids.ShortID, peerInfo validators.PeerInfo) {
 	cr.lock.Lock()
 	defer cr.lock.Unlock()
 
-	cr.peers[validatorID] = nodeVersion
+	cr.peers[validatorID] = peerInfo
 	// If this validator is benched on any chain, treat them as disconnected on all chains
 	if _, benched := cr.benched[validatorID]; benched {
 		return
 	}
 
-	msg := cr.msgCreator.InternalConnected(validatorID, nodeVersion)
+	msg := cr.msgCreator.InternalConnected(validatorID, peerInfo)
 
 	// TODO: fire up an event when validator state changes i.e when they leave set, disconnect.
 	// we cannot put a subnet-only validator check here since Disconnected would not be handled properly.
@@ -374,18 +404,47 @@ func (cr *ChainRouter) Unbenched(chainID ids.ID, validatorID ids.ShortID) {
 		return // This node is still benched
 	}
 
-	version, found := cr.peers[validatorID]
+	peerInfo, found := cr.peers[validatorID]
 	if !found {
 		return
 	}
 
-	msg := cr.msgCreator.InternalConnected(validatorID, version)
+	msg := cr.msgCreator.InternalConnected(validatorID, peerInfo)
 
 	for _, chain := range cr.chains {
 		chain.Push(msg)
 	}
 }
 
+// CPUUtilization implements the Router interface
+func (cr *ChainRouter) CPUUtilization(nodeID ids.ShortID) map[ids.ID]float64 {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	utilizations := make(map[ids.ID]float64)
+	for chainID, chain := range cr.chains {
+		if utilization := chain.CPUUtilization(nodeID); utilization > 0 {
+			utilizations[chainID] = utilization
+		}
+	}
+	return utilizations
+}
+
+// QueueDepths implements the Router interface
+func (cr *ChainRouter) QueueDepths() QueueDepths {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	depths := QueueDepths{
+		OutstandingRequests: cr.timedRequests.Len(),
+		Chains:              make(map[ids.ID]ChainQueueDepths, len(cr.chains)),
+	}
+	for chainID, chain := range cr.chains {
+		depths.Chains[chainID] = chain.QueueDepths()
+	}
+	return depths
+}
+
 // Gossip accepted containers
 func (cr *ChainRouter) Gossip() {
 	cr.lock.Lock()
@@ -13,6 +13,7 @@ type routerMetrics struct {
 	outstandingRequests   prometheus.Gauge
 	longestRunningRequest prometheus.Gauge
 	droppedRequests       prometheus.Counter
+	droppedUnknownChain   prometheus.Counter
 }
 
 func newRouterMetrics(namespace string, registerer prometheus.Registerer) (*routerMetrics, error) {
@@ -38,12 +39,20 @@ func newRouterMetrics(namespace string, registerer prometheus.Registerer) (*rout
 			Help:      "Number of dropped requests (all types)",
 		},
 	)
+	rMetrics.droppedUnknownChain = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped_unknown_chain",
+			Help:      "Number of dropped messages (all types) for chains this node doesn't run",
+		},
+	)
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(rMetrics.outstandingRequests),
 		registerer.Register(rMetrics.longestRunningRequest),
 		registerer.Register(rMetrics.droppedRequests),
+		registerer.Register(rMetrics.droppedUnknownChain),
 	)
 	return rMetrics, errs.Err
 }
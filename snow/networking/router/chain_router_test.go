@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
@@ -38,6 +40,7 @@ func TestShutdown(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -127,6 +130,7 @@ func TestShutdownTimesOut(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		metrics,
 	)
@@ -230,6 +234,7 @@ func TestRouterTimeout(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -347,6 +352,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -454,6 +460,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -530,6 +537,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 	chainRouter.HandleInbound(inMsg)
 
 	assert.False(t, calledF) // should not be called
+	assert.Equal(t, 1.0, testutil.ToFloat64(handler.metrics.dropped.WithLabelValues(dropNotValidator)))
 
 	// Validator case
 	calledF = false
@@ -19,7 +19,6 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 	"github.com/Toinounet21/avalanchego-mod/utils/uptime"
-	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
 const cpuHalflife = 15 * time.Second
@@ -46,6 +45,10 @@ type Handler struct {
 	msgFromVMChan <-chan common.Message
 	// Tracks CPU time spent processing messages from each node
 	cpuTracker tracker.TimeTracker
+	// Limits the resources this handler may use before its load is shed
+	quota *Quota
+	// Gives this handler its turns to process messages
+	scheduler *Scheduler
 	// Called in a goroutine when this handler/engine shuts down.
 	// May be nil.
 	onCloseF            func()
@@ -54,6 +57,15 @@ type Handler struct {
 	// [unprocessedMsgsCond.L] must be held while accessing [unprocessedMsgs].
 	unprocessedMsgs unprocessedMsgs
 	closing         utils.AtomicBool
+	// ID of the last request processed by [engine].
+	// Only accessed while [ctx.Lock] is held.
+	lastRequestID uint64
+}
+
+// receivedMsg is a message along with the time at which it was queued
+type receivedMsg struct {
+	message.InboundMessage
+	received time.Time
 }
 
 // Initialize this consensus handler
@@ -72,9 +84,11 @@ func NewHandler(
 		validators:          validators,
 		unprocessedMsgsCond: sync.NewCond(&sync.Mutex{}),
 		cpuTracker:          tracker.NewCPUTracker(uptime.ContinuousFactory{}, cpuHalflife),
+		quota:               NewQuota(QuotaConfig{}),
+		scheduler:           NewScheduler(0),
 	}
 
-	if err := h.metrics.Initialize("handler", h.ctx.Registerer); err != nil {
+	if err := h.metrics.Initialize("handler", h.ctx.Registerer, h.usage); err != nil {
 		return nil, fmt.Errorf("initializing handler metrics errored with: %w", err)
 	}
 	var err error
@@ -104,6 +118,64 @@ func (h *Handler) Engine() common.Engine { return h.engine }
 // SetEngine sets the engine for this handler to dispatch to
 func (h *Handler) SetEngine(engine common.Engine) { h.engine = engine }
 
+// SetQuota sets the quota this handler is held to.
+// Must be called before the handler starts dispatching messages.
+func (h *Handler) SetQuota(quota *Quota) { h.quota = quota }
+
+// SetScheduler sets the scheduler this handler takes turns with the handlers of
+// the other chains through.
+// Must be called before the handler starts dispatching messages.
+func (h *Handler) SetScheduler(scheduler *Scheduler) { h.scheduler = scheduler }
+
+// CPUUtilization returns the recent portion of CPU time this handler spent
+// processing messages from [nodeID]
+func (h *Handler) CPUUtilization(nodeID ids.ShortID) float64 {
+	return h.cpuTracker.Utilization(nodeID, h.clock.Time())
+}
+
+// QueueDepths returns the number of messages waiting to be handled by this
+// handler
+func (h *Handler) QueueDepths() ChainQueueDepths {
+	h.unprocessedMsgsCond.L.Lock()
+	defer h.unprocessedMsgsCond.L.Unlock()
+
+	return ChainQueueDepths{
+		Unprocessed: h.unprocessedMsgs.Len(),
+		FromVM:      len(h.msgFromVMChan),
+	}
+}
+
+// usage returns the recent CPU utilization and DB write rate of this handler
+func (h *Handler) usage() (float64, float64) {
+	return h.quota.usage(h.clock.Time())
+}
+
+// shedsLoad returns true if the unrequested message with [op] and [requestID]
+// should be dropped because this handler exceeds its quota
+func (h *Handler) shedsLoad(op message.Op, requestID uint32) bool {
+	if !h.quota.sheds(op, requestID) {
+		return false
+	}
+
+	h.unprocessedMsgsCond.L.Lock()
+	pending := h.unprocessedMsgs.Len()
+	h.unprocessedMsgsCond.L.Unlock()
+
+	cpu, dbWriteRate := h.usage()
+	limit := h.quota.exceeded(pending, cpu, dbWriteRate)
+	if limit == "" {
+		return false
+	}
+	h.metrics.shed.WithLabelValues(limit).Inc()
+	return true
+}
+
+// drop [msg] without processing it because of [reason]
+func (h *Handler) drop(msg message.InboundMessage, reason string) {
+	h.metrics.dropped.WithLabelValues(reason).Inc()
+	msg.OnFinishedHandling()
+}
+
 // Push the message onto the handler's queue
 func (h *Handler) Push(msg message.InboundMessage) {
 	nodeID := msg.NodeID()
@@ -115,7 +187,10 @@ func (h *Handler) Push(msg message.InboundMessage) {
 	h.unprocessedMsgsCond.L.Lock()
 	defer h.unprocessedMsgsCond.L.Unlock()
 
-	h.unprocessedMsgs.Push(msg)
+	h.unprocessedMsgs.Push(&receivedMsg{
+		InboundMessage: msg,
+		received:       h.clock.Time(),
+	})
 	h.unprocessedMsgsCond.Signal()
 }
 
@@ -154,13 +229,17 @@ func (h *Handler) Dispatch() {
 			h.ctx.Log.Verbo("Dropping message from %s%s due to timeout. msg: %s",
 				constants.NodeIDPrefix, nodeID, msg)
 			h.metrics.expired.Inc()
-			msg.OnFinishedHandling()
+			h.drop(msg, dropExpired)
 			continue
 		}
 
-		// Process the message.
+		// Process the message once it's this chain's turn.
 		// If there was an error, shut down this chain
-		if err := h.handleMsg(msg); err != nil {
+		h.scheduler.acquire()
+		err := h.handleMsg(msg)
+		h.scheduler.release()
+		h.metrics.processed.Inc()
+		if err != nil {
 			h.ctx.Log.Fatal("chain shutting down due to error %q while processing message: %s",
 				err, msg)
 			h.StartShutdown()
@@ -188,15 +267,13 @@ func isPeriodic(inMsg message.InboundMessage) bool {
 func (h *Handler) handleMsg(msg message.InboundMessage) error {
 	startTime := h.clock.Time()
 
-	isPeriodic := isPeriodic(msg)
-	if isPeriodic {
-		h.ctx.Log.Verbo("Forwarding message to consensus: %s", msg)
-	} else {
-		h.ctx.Log.Debug("Forwarding message to consensus: %s", msg)
+	lockCaller := snow.EngineCaller
+	if op := msg.Op(); op == message.AppGossip || op == message.GossipRequest {
+		lockCaller = snow.GossipCaller
 	}
-
-	h.ctx.Lock.Lock()
-	defer h.ctx.Lock.Unlock()
+	lock := h.ctx.Lock.As(lockCaller)
+	lock.Lock()
+	defer lock.Unlock()
 
 	var (
 		err        error
@@ -204,6 +281,29 @@ func (h *Handler) handleMsg(msg message.InboundMessage) error {
 		targetGear common.Engine
 	)
 
+	// Makes the message available to the engine and the VM while they process
+	// it
+	h.lastRequestID++
+	request := &snow.Request{
+		ID:       h.lastRequestID,
+		NodeID:   msg.NodeID(),
+		Op:       op.String(),
+		Received: startTime,
+		Started:  startTime,
+	}
+	if msg, ok := msg.(*receivedMsg); ok {
+		request.Received = msg.received
+	}
+	h.ctx.SetRequest(request)
+	defer h.ctx.SetRequest(nil)
+
+	isPeriodic := isPeriodic(msg)
+	if isPeriodic {
+		h.ctx.Log.Verbo("Forwarding %s to consensus: %s", request, msg)
+	} else {
+		h.ctx.Log.Debug("Forwarding %s to consensus: %s", request, msg)
+	}
+
 	switch h.ctx.GetState() {
 	case snow.Bootstrapping:
 		targetGear = h.bootstrapper
@@ -234,22 +334,26 @@ func (h *Handler) handleMsg(msg message.InboundMessage) error {
 		nodeID := msg.NodeID()
 		h.cpuTracker.UtilizeTime(nodeID, startTime, endTime)
 	}
+	h.quota.utilizeCPU(startTime, endTime)
 
-	// Track how long the operation took.
+	// Track how long the message waited and how long the operation took.
+	queueTime := startTime.Sub(request.Received)
+	processingTime := endTime.Sub(startTime)
+	h.metrics.queued.Observe(float64(queueTime))
 	histogram := h.metrics.messages[op]
 	// TODO: should not be needed
 	if histogram == nil {
 		h.ctx.Log.Warn("could not find metric map for message type %s", op.String())
 	} else {
-		histogram.Observe(float64(endTime.Sub(startTime)))
+		histogram.Observe(float64(processingTime))
 	}
 
 	msg.OnFinishedHandling()
 
 	if isPeriodic {
-		h.ctx.Log.Verbo("Finished handling message: %s", op)
+		h.ctx.Log.Verbo("Finished handling %s: queued for %s, processed in %s", request, queueTime, processingTime)
 	} else {
-		h.ctx.Log.Debug("Finished handling message: %s", op)
+		h.ctx.Log.Debug("Finished handling %s: queued for %s, processed in %s", request, queueTime, processingTime)
 	}
 	return err
 }
@@ -382,8 +486,8 @@ func (h *Handler) handleConsensusMsg(msg message.InboundMessage) error {
 		return targetGear.QueryFailed(nodeID, reqID)
 
 	case message.Connected:
-		peerVersion := msg.Get(message.VersionStruct).(version.Application)
-		return targetGear.Connected(nodeID, peerVersion)
+		peerInfo := msg.Get(message.PeerInfoStruct).(validators.PeerInfo)
+		return validators.Connect(targetGear, nodeID, peerInfo)
 
 	case message.Disconnected:
 		return targetGear.Disconnected(nodeID)
@@ -467,12 +571,16 @@ func (h *Handler) StartShutdown() {
 	// finished executing state transitions, which may take a long time.
 	// As a result, the router would time out on shutting down this chain.
 	h.bootstrapper.Halt()
+	// Interrupt any long running work done on behalf of this chain, such as
+	// serving a GetAncestors.
+	h.ctx.StartShutdown()
 }
 
 // Calls [h.engine.Shutdown] and [h.onCloseF]; closes [h.closed].
 func (h *Handler) shutdown() {
-	h.ctx.Lock.Lock()
-	defer h.ctx.Lock.Unlock()
+	lock := h.ctx.Lock.As(snow.EngineCaller)
+	lock.Lock()
+	defer lock.Unlock()
 
 	startTime := h.clock.Time()
 	if err := h.engine.Shutdown(); err != nil {
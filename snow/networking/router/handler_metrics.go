@@ -13,22 +13,96 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 )
 
+// Reasons why an incoming message may be dropped before it is processed
+const (
+	dropExpired      = "expired"
+	dropExecuting    = "executing"
+	dropQuota        = "quota"
+	dropUnrequested  = "unrequested"
+	dropNotValidator = "not_validator"
+)
+
+var dropReasons = []string{
+	dropExpired,
+	dropExecuting,
+	dropQuota,
+	dropUnrequested,
+	dropNotValidator,
+}
+
 type handlerMetrics struct {
-	expired  prometheus.Counter
+	expired   prometheus.Counter
+	processed prometheus.Counter
+	// Incoming messages dropped before being processed, by reason
+	dropped  *prometheus.CounterVec
 	messages map[message.Op]metric.Averager
+	queued   metric.Averager
 	shutdown metric.Averager
+	// Unrequested messages dropped because the quota was exceeded, by limit
+	shed *prometheus.CounterVec
 }
 
 // Initialize implements the Engine interface
-func (m *handlerMetrics) Initialize(namespace string, reg prometheus.Registerer) error {
+// [usage] returns the CPU utilization and DB write rate tracked by the quota
+func (m *handlerMetrics) Initialize(
+	namespace string,
+	reg prometheus.Registerer,
+	usage func() (float64, float64),
+) error {
 	m.expired = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "expired",
 		Help:      "Incoming messages dropped because the message deadline expired",
 	})
 
+	m.processed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "processed",
+		Help:      "Incoming messages processed by the engine",
+	})
+	m.dropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped",
+		Help:      "Incoming messages dropped before being processed",
+	}, []string{"reason"})
+
+	m.shed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shed",
+		Help:      "Unrequested incoming messages dropped because the chain exceeded its quota",
+	}, []string{"limit"})
+	cpuUtilization := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cpu_utilization",
+		Help:      "Recent portion of a CPU core spent processing messages",
+	}, func() float64 {
+		cpu, _ := usage()
+		return cpu
+	})
+	dbWriteRate := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "db_write_rate",
+		Help:      "Recent bytes per second written to the database",
+	}, func() float64 {
+		_, dbWriteRate := usage()
+		return dbWriteRate
+	})
+
 	errs := wrappers.Errs{}
-	errs.Add(reg.Register(m.expired))
+	errs.Add(
+		reg.Register(m.expired),
+		reg.Register(m.processed),
+		reg.Register(m.dropped),
+		reg.Register(m.shed),
+		reg.Register(cpuUtilization),
+		reg.Register(dbWriteRate),
+	)
+	for _, reason := range dropReasons {
+		m.dropped.WithLabelValues(reason)
+	}
+	for _, limit := range quotaLimits {
+		m.shed.WithLabelValues(limit)
+	}
 
 	m.messages = make(map[message.Op]metric.Averager, len(message.ConsensusOps))
 	for _, op := range message.ConsensusOps {
@@ -42,6 +116,13 @@ func (m *handlerMetrics) Initialize(namespace string, reg prometheus.Registerer)
 		)
 	}
 
+	m.queued = metric.NewAveragerWithErrs(
+		namespace,
+		"queued",
+		"time (in ns) messages spent queued before being processed",
+		reg,
+		&errs,
+	)
 	m.shutdown = metric.NewAveragerWithErrs(
 		namespace,
 		"shutdown",
@@ -249,3 +249,52 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	case <-calledNotify:
 	}
 }
+
+func TestHandlerSetsRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace")
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	handler, err := NewHandler(mc, ctx, validators.NewSet(), nil)
+	assert.NoError(err)
+
+	requests := make(chan snow.Request, 2)
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+	bootstrapper.GetAcceptedF = func(nodeID ids.ShortID, requestID uint32, containerIDs []ids.ID) error {
+		requests <- *ctx.Request()
+		return nil
+	}
+	handler.RegisterBootstrap(bootstrapper)
+	ctx.SetState(snow.Bootstrapping)
+
+	receivedTime := time.Now()
+	handler.clock.Set(receivedTime)
+	nodeID := ids.GenerateTestShortID()
+	handler.Push(mc.InboundGetAccepted(ctx.ChainID, 1, time.Hour, nil, nodeID))
+	handler.Push(mc.InboundGetAccepted(ctx.ChainID, 2, time.Hour, nil, nodeID))
+
+	startTime := receivedTime.Add(time.Second)
+	handler.clock.Set(startTime)
+	go handler.Dispatch()
+
+	for id := uint64(1); id <= 2; id++ {
+		request := <-requests
+		assert.Equal(id, request.ID)
+		assert.Equal(nodeID, request.NodeID)
+		assert.Equal(message.GetAccepted.String(), request.Op)
+		assert.Equal(receivedTime, request.Received)
+		assert.Equal(startTime, request.Started)
+	}
+	assert.Eventually(func() bool { return ctx.Request() == nil }, time.Second, time.Millisecond)
+}
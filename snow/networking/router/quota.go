@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/uptime"
+)
+
+// Shed policies decide which messages the router drops for a chain that
+// exceeds its quota. Responses to requests of the chain are never dropped, so
+// that the requests don't time out.
+const (
+	// ShedUnrequested drops every message that the chain didn't request, such
+	// as queries and gossip
+	ShedUnrequested ShedPolicy = "unrequested"
+	// ShedGossip only drops gossip
+	ShedGossip ShedPolicy = "gossip"
+)
+
+// Limits of a quota
+const (
+	cpuLimit     = "cpu"
+	pendingLimit = "pending"
+	dbWriteLimit = "db_write"
+)
+
+var (
+	errNegativeQuota     = errors.New("quota limits can't be negative")
+	errUnknownShedPolicy = errors.New("unknown shed policy")
+
+	quotaLimits = []string{cpuLimit, pendingLimit, dbWriteLimit}
+)
+
+// ShedPolicy decides which messages are dropped for a chain that exceeds its
+// quota
+type ShedPolicy string
+
+// QuotaConfig limits the resources that the handler of a chain may use before
+// the router sheds its load. A zero limit means that the resource is unlimited.
+type QuotaConfig struct {
+	// Portion of a CPU core spent processing the messages of the chain,
+	// averaged over the last [cpuHalflife]
+	MaxCPUUtilization float64 `json:"maxCPUUtilization"`
+	// Number of messages waiting to be processed by the chain
+	MaxPendingMessages int `json:"maxPendingMessages"`
+	// Bytes per second written to the database of the chain, averaged over the
+	// last [cpuHalflife]
+	MaxDBWriteRate float64 `json:"maxDBWriteRate"`
+	// Decides which messages are dropped while the chain exceeds a limit.
+	// Defaults to ShedUnrequested.
+	ShedPolicy ShedPolicy `json:"shedPolicy"`
+}
+
+// Valid returns an error if this config isn't valid
+func (c *QuotaConfig) Valid() error {
+	if c.MaxCPUUtilization < 0 || c.MaxPendingMessages < 0 || c.MaxDBWriteRate < 0 {
+		return errNegativeQuota
+	}
+	switch c.ShedPolicy {
+	case "", ShedUnrequested, ShedGossip:
+		return nil
+	default:
+		return fmt.Errorf("%w %q", errUnknownShedPolicy, c.ShedPolicy)
+	}
+}
+
+// Quota tracks the resources used by the handler of a chain against the limits
+// of its config
+type Quota struct {
+	config QuotaConfig
+
+	lock sync.Mutex
+	// Tracks the CPU time spent processing the messages of the chain
+	cpu uptime.Meter
+	// Tracks the bytes written to the database of the chain
+	dbWrites rateMeter
+}
+
+// NewQuota returns a quota that enforces the limits of [config]
+func NewQuota(config QuotaConfig) *Quota {
+	return &Quota{
+		config:   config,
+		cpu:      uptime.ContinuousFactory{}.New(cpuHalflife),
+		dbWrites: rateMeter{halflife: cpuHalflife},
+	}
+}
+
+// ObserveDBWrite records that [bytes] were written to the database of the
+// chain
+func (q *Quota) ObserveDBWrite(bytes int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.dbWrites.observe(float64(bytes), time.Now())
+}
+
+// utilizeCPU records that the CPU was busy processing a message of the chain
+// from [startTime] to [endTime]
+func (q *Quota) utilizeCPU(startTime, endTime time.Time) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.cpu.Start(startTime)
+	q.cpu.Stop(endTime)
+}
+
+// usage returns the CPU utilization and the DB write rate of the chain
+func (q *Quota) usage(now time.Time) (float64, float64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.cpu.Read(now), q.dbWrites.read(now)
+}
+
+// exceeded returns the limit that is exceeded by a chain that has [pending]
+// messages waiting to be processed, or the empty string if none is
+func (q *Quota) exceeded(pending int, cpu, dbWriteRate float64) string {
+	switch {
+	case q.config.MaxCPUUtilization > 0 && cpu > q.config.MaxCPUUtilization:
+		return cpuLimit
+	case q.config.MaxPendingMessages > 0 && pending > q.config.MaxPendingMessages:
+		return pendingLimit
+	case q.config.MaxDBWriteRate > 0 && dbWriteRate > q.config.MaxDBWriteRate:
+		return dbWriteLimit
+	default:
+		return ""
+	}
+}
+
+// sheds returns true if an unrequested message with [op] and [requestID] is
+// dropped while the chain exceeds its quota
+func (q *Quota) sheds(op message.Op, requestID uint32) bool {
+	if q.config.ShedPolicy != ShedGossip {
+		return true
+	}
+	return op == message.AppGossip || (op == message.Put && requestID == constants.GossipMsgRequestID)
+}
+
+// rateMeter tracks an exponentially decaying average of the rate at which an
+// amount, such as bytes written, grows
+type rateMeter struct {
+	halflife time.Duration
+	// Sum of the observed amounts, each decayed by the time since it was
+	// observed
+	sum  float64
+	last time.Time
+}
+
+func (m *rateMeter) observe(amount float64, now time.Time) {
+	m.decay(now)
+	m.sum += amount
+}
+
+// read returns the average rate, per second, over the last [halflife]
+func (m *rateMeter) read(now time.Time) float64 {
+	m.decay(now)
+	// A constant rate r grows the sum towards r * halflife / ln(2)
+	return m.sum * math.Ln2 / m.halflife.Seconds()
+}
+
+func (m *rateMeter) decay(now time.Time) {
+	if elapsed := now.Sub(m.last); elapsed > 0 {
+		if !m.last.IsZero() {
+			m.sum *= math.Exp2(-float64(elapsed) / float64(m.halflife))
+		}
+		m.last = now
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
+	"github.com/Toinounet21/avalanchego-mod/snow/networking/timeout"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer"
+)
+
+func TestQuotaConfigValid(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&QuotaConfig{}).Valid())
+	assert.NoError((&QuotaConfig{MaxPendingMessages: 1, ShedPolicy: ShedGossip}).Valid())
+	assert.ErrorIs((&QuotaConfig{MaxDBWriteRate: -1}).Valid(), errNegativeQuota)
+	assert.ErrorIs((&QuotaConfig{ShedPolicy: "everything"}).Valid(), errUnknownShedPolicy)
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	assert := assert.New(t)
+
+	unlimited := NewQuota(QuotaConfig{})
+	assert.Empty(unlimited.exceeded(1000, 1, 1000))
+
+	quota := NewQuota(QuotaConfig{
+		MaxCPUUtilization:  0.5,
+		MaxPendingMessages: 10,
+		MaxDBWriteRate:     100,
+	})
+	assert.Empty(quota.exceeded(10, 0.5, 100))
+	assert.Equal(cpuLimit, quota.exceeded(0, 0.6, 0))
+	assert.Equal(pendingLimit, quota.exceeded(11, 0, 0))
+	assert.Equal(dbWriteLimit, quota.exceeded(0, 0, 101))
+
+	now := time.Now()
+	quota.utilizeCPU(now.Add(-2*cpuHalflife), now)
+	cpu, _ := quota.usage(now)
+	assert.Greater(cpu, 0.5)
+}
+
+func TestQuotaSheds(t *testing.T) {
+	assert := assert.New(t)
+
+	unrequested := NewQuota(QuotaConfig{})
+	assert.True(unrequested.sheds(message.PullQuery, 1))
+	assert.True(unrequested.sheds(message.Put, constants.GossipMsgRequestID))
+
+	gossip := NewQuota(QuotaConfig{ShedPolicy: ShedGossip})
+	assert.False(gossip.sheds(message.PullQuery, 1))
+	assert.True(gossip.sheds(message.AppGossip, constants.GossipMsgRequestID))
+	assert.True(gossip.sheds(message.Put, constants.GossipMsgRequestID))
+}
+
+func TestRateMeter(t *testing.T) {
+	assert := assert.New(t)
+
+	m := rateMeter{halflife: time.Second}
+	now := time.Now()
+	// Writing 100 bytes every millisecond converges to 100k bytes per second
+	for i := 0; i < 10000; i++ {
+		now = now.Add(time.Millisecond)
+		m.observe(100, now)
+	}
+	assert.InEpsilon(100000, m.read(now), 0.01)
+
+	// The rate halves every halflife once writes stop
+	assert.InEpsilon(50000, m.read(now.Add(time.Second)), 0.01)
+}
+
+func TestRouterShedsLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	tm := timeout.Manager{}
+	err := tm.Initialize(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     10 * time.Millisecond,
+			MinimumTimeout:     10 * time.Millisecond,
+			MaximumTimeout:     25 * time.Millisecond,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		scoring.NewNoScorer(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace")
+	assert.NoError(err)
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, &tm, time.Hour, time.Millisecond, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vdrs := validators.NewSet()
+	vID := ids.GenerateTestShortID()
+	assert.NoError(vdrs.AddWeight(vID, 1))
+
+	handler, err := NewHandler(mc, ctx, vdrs, nil)
+	assert.NoError(err)
+	handler.SetQuota(NewQuota(QuotaConfig{MaxPendingMessages: 1}))
+	// The handler isn't dispatching, so pushed messages stay pending
+	chainRouter.AddChain(handler)
+
+	pending := func() int { return handler.QueueDepths().Unprocessed }
+
+	reqID := uint32(0)
+	for i := 0; i < 3; i++ {
+		reqID++
+		chainRouter.HandleInbound(mc.InboundPullQuery(ctx.ChainID, reqID, time.Hour, ids.GenerateTestID(), vID))
+	}
+	assert.Equal(2, pending(), "queries should be shed once the queue exceeds its limit")
+	assert.Equal(2.0, testutil.ToFloat64(handler.metrics.dropped.WithLabelValues(dropQuota)))
+
+	// Responses are never shed
+	reqID++
+	chainRouter.RegisterRequest(vID, ctx.ChainID, reqID, message.Put)
+	chainRouter.HandleInbound(mc.InboundPut(ctx.ChainID, reqID, ids.GenerateTestID(), nil, vID))
+	assert.Equal(3, pending())
+
+	// Only gossip is shed under the gossip policy
+	handler.SetQuota(NewQuota(QuotaConfig{MaxPendingMessages: 1, ShedPolicy: ShedGossip}))
+	chainRouter.HandleInbound(mc.InboundPut(ctx.ChainID, constants.GossipMsgRequestID, ids.GenerateTestID(), nil, vID))
+	assert.Equal(3, pending())
+	reqID++
+	chainRouter.HandleInbound(mc.InboundPullQuery(ctx.ChainID, reqID, time.Hour, ids.GenerateTestID(), vID))
+	assert.Equal(4, pending())
+	assert.Equal(3.0, testutil.ToFloat64(handler.metrics.dropped.WithLabelValues(dropQuota)))
+
+	// Responses that weren't requested are dropped
+	chainRouter.HandleInbound(mc.InboundPut(ctx.ChainID, reqID+1, ids.GenerateTestID(), nil, vID))
+	assert.Equal(4, pending())
+	assert.Equal(1.0, testutil.ToFloat64(handler.metrics.dropped.WithLabelValues(dropUnrequested)))
+
+	// Messages for chains that aren't tracked are counted by the router
+	chainRouter.HandleInbound(mc.InboundPullQuery(ids.GenerateTestID(), reqID, time.Hour, ids.GenerateTestID(), vID))
+	assert.Equal(1.0, testutil.ToFloat64(chainRouter.metrics.droppedUnknownChain))
+}
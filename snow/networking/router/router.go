@@ -11,8 +11,8 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/message"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/timeout"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
-	"github.com/Toinounet21/avalanchego-mod/version"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -37,6 +37,12 @@ type Router interface {
 	) error
 	Shutdown()
 	AddChain(chain *Handler)
+	// CPUUtilization returns, for each chain that recently processed
+	// messages from [nodeID], the portion of CPU time spent doing so
+	CPUUtilization(nodeID ids.ShortID) map[ids.ID]float64
+	// QueueDepths returns the number of messages that are waiting to be
+	// processed
+	QueueDepths() QueueDepths
 	health.Checker
 }
 
@@ -57,6 +63,24 @@ type ExternalRouter interface {
 type InternalRouter interface {
 	benchlist.Benchable
 
-	Connected(nodeID ids.ShortID, nodeVersion version.Application)
+	// Connected is called when [nodeID] finishes the handshake
+	Connected(nodeID ids.ShortID, peerInfo validators.PeerInfo)
 	Disconnected(nodeID ids.ShortID)
 }
+
+// QueueDepths describes the messages that are waiting to be processed
+type QueueDepths struct {
+	// Number of requests that are waiting for a response
+	OutstandingRequests int `json:"outstandingRequests"`
+	// Queue depths of the handler of each chain
+	Chains map[ids.ID]ChainQueueDepths `json:"chains"`
+}
+
+// ChainQueueDepths describes the messages that are waiting to be processed by
+// the handler of a chain
+type ChainQueueDepths struct {
+	// Number of messages from the network that the engine hasn't processed
+	Unprocessed int `json:"unprocessed"`
+	// Number of messages from the VM that the engine hasn't processed
+	FromVM int `json:"fromVM"`
+}
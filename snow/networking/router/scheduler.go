@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import "sync"
+
+// Scheduler limits the number of messages, over all chains, that are processed
+// at once. Chains take turns: once a chain is done processing a message, it
+// waits behind the chains that were already waiting before it may process its
+// next one. As a result, a burst of messages on one chain can't delay the
+// messages of the other chains by more than one message per chain.
+type Scheduler struct {
+	lock sync.Mutex
+	// Max number of messages processed at once. If <= 0, there is no limit.
+	maxProcessing int
+	// Number of messages being processed
+	processing int
+	// Closed, in order, to give the waiting chains their turn
+	waiting []chan struct{}
+}
+
+// NewScheduler returns a Scheduler that allows at most [maxProcessing]
+// messages to be processed at once. If [maxProcessing] <= 0, messages are
+// never delayed.
+func NewScheduler(maxProcessing int) *Scheduler {
+	return &Scheduler{maxProcessing: maxProcessing}
+}
+
+// acquire blocks until the caller may process a message.
+// [release] must be called once the message is processed.
+func (s *Scheduler) acquire() {
+	s.lock.Lock()
+	if s.maxProcessing <= 0 || (s.processing < s.maxProcessing && len(s.waiting) == 0) {
+		s.processing++
+		s.lock.Unlock()
+		return
+	}
+	turn := make(chan struct{})
+	s.waiting = append(s.waiting, turn)
+	s.lock.Unlock()
+
+	<-turn
+}
+
+// release hands the caller's slot to the chain that has been waiting the
+// longest, if any.
+func (s *Scheduler) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.waiting) == 0 {
+		s.processing--
+		return
+	}
+	turn := s.waiting[0]
+	s.waiting[0] = nil
+	s.waiting = s.waiting[1:]
+	close(turn)
+}
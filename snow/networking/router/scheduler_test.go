@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerTakesTurns(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewScheduler(1)
+	numWaiting := func() int {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return len(s.waiting)
+	}
+
+	// Chain 0 is processing a message
+	s.acquire()
+
+	// Chains 1 and 2 wait for their turn, in that order
+	turns := make(chan int, 3)
+	for chain := 1; chain <= 2; chain++ {
+		chain := chain
+		go func() {
+			s.acquire()
+			turns <- chain
+		}()
+		assert.Eventually(func() bool { return numWaiting() == chain }, time.Second, time.Millisecond)
+	}
+
+	// Chain 0 wants to process another message, so it waits behind them
+	go func() {
+		s.acquire()
+		turns <- 0
+	}()
+	assert.Eventually(func() bool { return numWaiting() == 3 }, time.Second, time.Millisecond)
+
+	s.release()
+	for _, expected := range []int{1, 2, 0} {
+		assert.Equal(expected, <-turns)
+		select {
+		case chain := <-turns:
+			t.Fatalf("chain %d got a turn while chain %d was processing", chain, expected)
+		default:
+		}
+		s.release()
+	}
+
+	assert.Equal(0, numWaiting())
+	assert.Equal(0, s.processing)
+}
+
+func TestSchedulerNoLimit(t *testing.T) {
+	s := NewScheduler(0)
+	for i := 0; i < 10; i++ {
+		s.acquire()
+	}
+	for i := 0; i < 10; i++ {
+		s.release()
+	}
+	assert.Equal(t, 0, s.processing)
+}
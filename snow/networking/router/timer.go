@@ -4,9 +4,11 @@
 package router
 
 import (
+	"sync"
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 )
 
 var _ common.Timer = &Timer{}
@@ -14,18 +16,69 @@ var _ common.Timer = &Timer{}
 type Timer struct {
 	Handler *Handler
 	Preempt chan struct{}
+	// Wheel schedules the timeouts. If nil, each timeout waits on its own
+	// goroutine.
+	Wheel *timer.Wheel
+
+	preemptOnce sync.Once
+	lock        sync.Mutex
+	// timeouts scheduled on [Wheel] that haven't fired yet
+	pending map[*timer.WheelTimer]struct{}
 }
 
 func (t *Timer) RegisterTimeout(d time.Duration) {
-	go func() {
-		timer := time.NewTimer(d)
-		defer timer.Stop()
+	if t.Wheel == nil {
+		go func() {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
 
-		select {
-		case <-timer.C:
-		case <-t.Preempt:
-		}
+			select {
+			case <-timer.C:
+			case <-t.Preempt:
+			}
 
+			t.Handler.Timeout()
+		}()
+		return
+	}
+
+	select {
+	case <-t.Preempt:
 		t.Handler.Timeout()
-	}()
+		return
+	default:
+	}
+
+	t.preemptOnce.Do(func() { go t.awaitPreempt() })
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.pending == nil {
+		t.pending = make(map[*timer.WheelTimer]struct{})
+	}
+	var wheelTimer *timer.WheelTimer
+	wheelTimer = t.Wheel.Schedule(d, func() {
+		t.lock.Lock()
+		delete(t.pending, wheelTimer)
+		t.lock.Unlock()
+
+		t.Handler.Timeout()
+	})
+	t.pending[wheelTimer] = struct{}{}
+}
+
+// awaitPreempt fires the pending timeouts once [Preempt] is closed
+func (t *Timer) awaitPreempt() {
+	<-t.Preempt
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for wheelTimer := range t.pending {
+		if wheelTimer.Cancel() {
+			t.Handler.Timeout()
+		}
+	}
+	t.pending = nil
 }
@@ -0,0 +1,135 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+)
+
+type boundRequest struct {
+	nodeID    ids.ShortID
+	requestID uint32
+	op        message.Op
+}
+
+// boundRequests are the requests sent with a context. Once the context is
+// done, the requests that are still outstanding are failed.
+type boundRequests struct {
+	ctx  context.Context
+	fail func(nodeID ids.ShortID, requestID uint32, op message.Op)
+
+	lock     sync.Mutex
+	watching bool
+	done     bool
+	requests []boundRequest
+}
+
+// WithContext returns a copy of this sender whose requests are bound to [ctx].
+// Requests aren't sent once [ctx] is done, and requests that are outstanding
+// when [ctx] is done are failed immediately rather than when they time out.
+// Responses to requests bound to a context whose deadline is sooner than the
+// network timeout are only waited for until that deadline.
+func (s *Sender) WithContext(ctx context.Context) common.Sender {
+	bound := *s
+	bound.requests = &boundRequests{
+		ctx:  ctx,
+		fail: s.failRequest,
+	}
+	return &bound
+}
+
+// registerRequest tells the router to expect a response message, with
+// response type [op], or a message notifying that we won't get a response from
+// [nodeID] to request [requestID].
+func (s *Sender) registerRequest(nodeID ids.ShortID, requestID uint32, op message.Op) {
+	s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, op)
+	if s.requests != nil {
+		s.requests.add(boundRequest{
+			nodeID:    nodeID,
+			requestID: requestID,
+			op:        op,
+		})
+	}
+}
+
+// dropExpired returns true if the request [requestID] shouldn't be sent since
+// its context is done. The registered requests are failed by the context.
+func (s *Sender) dropExpired(op message.Op, requestID uint32) bool {
+	if s.requests == nil || s.requests.ctx.Err() == nil {
+		return false
+	}
+	s.ctx.Log.Debug(
+		"dropping %s(%s, %d) since its context is done",
+		op,
+		s.ctx.ChainID,
+		requestID,
+	)
+	return true
+}
+
+// requestDeadline returns how long the recipients of a request have to respond
+func (s *Sender) requestDeadline() time.Duration {
+	// Note that this timeout duration won't exactly match the one that gets
+	// registered. That's OK.
+	deadline := s.timeouts.TimeoutDuration()
+	if s.requests == nil {
+		return deadline
+	}
+	if ctxDeadline, ok := s.requests.ctx.Deadline(); ok {
+		if remaining := time.Until(ctxDeadline); remaining < deadline {
+			return remaining
+		}
+	}
+	return deadline
+}
+
+// failRequest passes a failure of the request to the router. The router drops
+// the failure if a response was already received, and otherwise stops
+// tracking the timeout of the request.
+func (s *Sender) failRequest(nodeID ids.ShortID, requestID uint32, op message.Op) {
+	failedOp, ok := message.ResponseToFailedOps[op]
+	if !ok {
+		// This should never happen
+		s.ctx.Log.Error("failed to convert operation type: %s", op)
+		return
+	}
+	inMsg := s.msgCreator.InternalFailedRequest(failedOp, nodeID, s.ctx.ChainID, requestID)
+	s.router.HandleInbound(inMsg)
+}
+
+func (b *boundRequests) add(request boundRequest) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.done {
+		go b.fail(request.nodeID, request.requestID, request.op)
+		return
+	}
+	b.requests = append(b.requests, request)
+	if !b.watching {
+		b.watching = true
+		go b.watch()
+	}
+}
+
+// watch fails the outstanding requests once the context is done
+func (b *boundRequests) watch() {
+	<-b.ctx.Done()
+
+	b.lock.Lock()
+	requests := b.requests
+	b.requests = nil
+	b.done = true
+	b.lock.Unlock()
+
+	for _, request := range requests {
+		b.fail(request.nodeID, request.requestID, request.op)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
 	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/router"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/timeout"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
@@ -16,6 +17,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+var _ common.Sender = &Sender{}
+
 // Sender is a wrapper around an ExternalSender.
 // Messages to this node are put directly into [router] rather than
 // being sent over the network via the wrapped ExternalSender.
@@ -35,6 +38,10 @@ type Sender struct {
 	// Request message type --> Counts how many of that request
 	// have failed because the node was benched
 	failedDueToBench map[message.Op]prometheus.Counter
+
+	// If non-nil, the requests sent by this sender are bound to [requests].
+	// See WithContext.
+	requests *boundRequests
 }
 
 // Initialize this sender
@@ -81,7 +88,7 @@ func (s *Sender) Context() *snow.ConsensusContext { return s.ctx }
 func (s *Sender) SendGetAcceptedFrontier(nodeIDs ids.ShortSet, requestID uint32) {
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from each of these nodes.
@@ -89,7 +96,11 @@ func (s *Sender) SendGetAcceptedFrontier(nodeIDs ids.ShortSet, requestID uint32)
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.AcceptedFrontier)
+		s.registerRequest(nodeID, requestID, message.AcceptedFrontier)
+	}
+
+	if s.dropExpired(message.GetAcceptedFrontier, requestID) {
+		return
 	}
 
 	// Sending a message to myself. No need to send it over the network.
@@ -156,7 +167,7 @@ func (s *Sender) SendAcceptedFrontier(nodeID ids.ShortID, requestID uint32, cont
 func (s *Sender) SendGetAccepted(nodeIDs ids.ShortSet, requestID uint32, containerIDs []ids.ID) {
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from each of these nodes.
@@ -164,7 +175,11 @@ func (s *Sender) SendGetAccepted(nodeIDs ids.ShortSet, requestID uint32, contain
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Accepted)
+		s.registerRequest(nodeID, requestID, message.Accepted)
+	}
+
+	if s.dropExpired(message.GetAccepted, requestID) {
+		return
 	}
 
 	// Sending a message to myself. No need to send it over the network.
@@ -248,7 +263,11 @@ func (s *Sender) SendGetAncestors(nodeID ids.ShortID, requestID uint32, containe
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from this node.
-	s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Ancestors)
+	s.registerRequest(nodeID, requestID, message.Ancestors)
+
+	if s.dropExpired(message.GetAncestors, requestID) {
+		return
+	}
 
 	// Sending a GetAncestors to myself always fails.
 	if nodeID == s.ctx.NodeID {
@@ -269,7 +288,7 @@ func (s *Sender) SendGetAncestors(nodeID ids.ShortID, requestID uint32, containe
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.GetAncestors(s.ctx.ChainID, requestID, deadline, containerID)
 	if err != nil {
@@ -340,7 +359,11 @@ func (s *Sender) SendGet(nodeID ids.ShortID, requestID uint32, containerID ids.I
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from this node.
-	s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Put)
+	s.registerRequest(nodeID, requestID, message.Put)
+
+	if s.dropExpired(message.Get, requestID) {
+		return
+	}
 
 	// Sending a Get to myself always fails.
 	if nodeID == s.ctx.NodeID {
@@ -361,7 +384,7 @@ func (s *Sender) SendGet(nodeID ids.ShortID, requestID uint32, containerID ids.I
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.Get(s.ctx.ChainID, requestID, deadline, containerID)
 	s.ctx.Log.AssertNoError(err)
@@ -443,12 +466,16 @@ func (s *Sender) SendPushQuery(nodeIDs ids.ShortSet, requestID uint32, container
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Chits)
+		s.registerRequest(nodeID, requestID, message.Chits)
+	}
+
+	if s.dropExpired(message.PushQuery, requestID) {
+		return
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 
 	// Sending a message to myself. No need to send it over the network.
 	// Just put it right into the router. Do so asynchronously to avoid deadlock.
@@ -527,12 +554,16 @@ func (s *Sender) SendPullQuery(nodeIDs ids.ShortSet, requestID uint32, container
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Chits)
+		s.registerRequest(nodeID, requestID, message.Chits)
+	}
+
+	if s.dropExpired(message.PullQuery, requestID) {
+		return
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 
 	// Sending a message to myself. No need to send it over the network.
 	// Just put it right into the router. Do so asynchronously to avoid deadlock.
@@ -639,12 +670,16 @@ func (s *Sender) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, appReque
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.AppResponse)
+		s.registerRequest(nodeID, requestID, message.AppResponse)
+	}
+
+	if s.dropExpired(message.AppRequest, requestID) {
+		return nil
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.requestDeadline()
 
 	// Sending a message to myself. No need to send it over the network.
 	// Just put it right into the router. Do so asynchronously to avoid deadlock.
@@ -762,6 +797,25 @@ func (s *Sender) SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes []by
 	return nil
 }
 
+// SendAppGossipValidators sends an application-level gossip message to
+// [numValidators] random validators of [subnetID].
+func (s *Sender) SendAppGossipValidators(subnetID ids.ID, numValidators int, appGossipBytes []byte) error {
+	// Create the outbound message.
+	outMsg, err := s.msgCreator.AppGossip(s.ctx.ChainID, appGossipBytes)
+	if err != nil {
+		s.ctx.Log.Error("failed to build AppGossip(%s) for ValidatorsGossip: %s", s.ctx.ChainID, err)
+		s.ctx.Log.Verbo("message: %s", formatting.DumpBytes(appGossipBytes))
+		return nil
+	}
+
+	sentTo := s.sender.Gossip(outMsg, subnetID, s.ctx.IsValidatorOnly(), numValidators, 0)
+	if sentTo.Len() == 0 {
+		s.ctx.Log.Debug("failed to gossip ValidatorsGossip(%s, %s)", s.ctx.ChainID, subnetID)
+		s.ctx.Log.Verbo("failed message: %s", formatting.DumpBytes(appGossipBytes))
+	}
+	return nil
+}
+
 // SendAppGossip sends an application-level gossip message.
 func (s *Sender) SendAppGossip(appGossipBytes []byte) error {
 	// Create the outbound message.
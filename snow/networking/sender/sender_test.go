@@ -4,6 +4,7 @@
 package sender
 
 import (
+	"context"
 	"math/rand"
 	"reflect"
 	"sync"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
@@ -66,6 +68,7 @@ func TestTimeout(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -136,6 +139,112 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+func TestContextFailsRequests(t *testing.T) {
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(t, err)
+	benchlist := benchlist.NewNoBenchlist()
+	tm := timeout.Manager{}
+	err = tm.Initialize(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     time.Hour,
+			MinimumTimeout:     time.Hour,
+			MaximumTimeout:     time.Hour,
+			TimeoutHalflife:    5 * time.Minute,
+			TimeoutCoefficient: 1.25,
+		},
+		benchlist,
+		scoring.NewNoScorer(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+	go tm.Dispatch()
+
+	chainRouter := router.ChainRouter{}
+	metrics := prometheus.NewRegistry()
+	mc, err := message.NewCreator(metrics, true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, &tm, time.Hour, time.Second, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	externalSender := &ExternalSenderTest{TB: t}
+	externalSender.Default(true)
+	sender := Sender{}
+	err = sender.Initialize(snow.DefaultConsensusContextTest(), mc, externalSender, &chainRouter, &tm, 2, 2, 2)
+	assert.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	failedLock := sync.Mutex{}
+	failedVDRs := ids.ShortSet{}
+	ctx := snow.DefaultConsensusContextTest()
+	handler, err := router.NewHandler(
+		mc,
+		ctx,
+		vdrs,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(true)
+	bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+	bootstrapper.ConnectedF = func(nodeID ids.ShortID, nodeVersion version.Application) error { return nil }
+	bootstrapper.QueryFailedF = func(nodeID ids.ShortID, _ uint32) error {
+		failedLock.Lock()
+		failedVDRs.Add(nodeID)
+		failedLock.Unlock()
+		wg.Done()
+		return nil
+	}
+	handler.RegisterBootstrap(bootstrapper)
+	ctx.SetState(snow.Bootstrapping) // assumed bootstrap is ongoing
+
+	go handler.Dispatch()
+
+	chainRouter.AddChain(handler)
+
+	vdrIDs := ids.ShortSet{}
+	vdrIDs.Add(ids.ShortID{255})
+	vdrIDs.Add(ids.ShortID{254})
+
+	// Requests with a context that is already done aren't sent, and fail
+	doneCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	wg.Add(vdrIDs.Len())
+	queried := ids.NewShortSet(vdrIDs.Len())
+	queried.Union(vdrIDs)
+	sender.WithContext(doneCtx).SendPullQuery(queried, 0, ids.Empty)
+	wg.Wait()
+
+	assert.True(t, failedVDRs.Equals(vdrIDs))
+
+	// Outstanding requests fail once their context is done, long before they
+	// time out
+	failedVDRs.Clear()
+	externalSender.SendF = func(_ message.OutboundMessage, nodeIDs ids.ShortSet, _ ids.ID, _ bool) ids.ShortSet {
+		return nodeIDs
+	}
+	liveCtx, cancel := context.WithCancel(context.Background())
+
+	wg.Add(vdrIDs.Len())
+	queried = ids.NewShortSet(vdrIDs.Len())
+	queried.Union(vdrIDs)
+	sender.WithContext(liveCtx).SendPullQuery(queried, 1, ids.Empty)
+	cancel()
+	wg.Wait()
+
+	assert.True(t, failedVDRs.Equals(vdrIDs))
+}
+
 func TestReliableMessages(t *testing.T) {
 	vdrs := validators.NewSet()
 	err := vdrs.AddWeight(ids.ShortID{1}, 1)
@@ -151,6 +260,7 @@ func TestReliableMessages(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -249,6 +359,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -323,3 +434,29 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		<-await
 	}
 }
+
+func TestSendAppGossipValidators(t *testing.T) {
+	ctx := snow.DefaultConsensusContextTest()
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true /*compressionEnabled*/, "dummyNamespace" /*parentNamespace*/)
+	assert.NoError(t, err)
+
+	subnetID := ids.GenerateTestID()
+	called := false
+	externalSender := &ExternalSenderTest{TB: t}
+	externalSender.Default(true)
+	externalSender.GossipF = func(msg message.OutboundMessage, gotSubnetID ids.ID, _ bool, numValidatorsToSend, numNonValidatorsToSend int) ids.ShortSet {
+		called = true
+		assert.Equal(t, message.AppGossip, msg.Op())
+		assert.Equal(t, subnetID, gotSubnetID)
+		assert.Equal(t, 3, numValidatorsToSend)
+		assert.Zero(t, numNonValidatorsToSend)
+		return nil
+	}
+
+	sender := Sender{}
+	err = sender.Initialize(ctx, mc, externalSender, &router.ChainRouter{}, &timeout.Manager{}, 2, 2, 2)
+	assert.NoError(t, err)
+
+	assert.NoError(t, sender.SendAppGossipValidators(subnetID, 3, []byte("message")))
+	assert.True(t, called)
+}
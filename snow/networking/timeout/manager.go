@@ -10,6 +10,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
 	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,6 +20,7 @@ import (
 type Manager struct {
 	tm           timer.AdaptiveTimeoutManager
 	benchlistMgr benchlist.Manager
+	peerScorer   common.PeerScorer
 	metrics      metrics
 }
 
@@ -26,10 +28,12 @@ type Manager struct {
 func (m *Manager) Initialize(
 	timeoutConfig *timer.AdaptiveTimeoutConfig,
 	benchlistMgr benchlist.Manager,
+	peerScorer common.PeerScorer,
 	metricsNamespace string,
 	metricsRegister prometheus.Registerer,
 ) error {
 	m.benchlistMgr = benchlistMgr
+	m.peerScorer = peerScorer
 	return m.tm.Initialize(timeoutConfig, metricsNamespace, metricsRegister)
 }
 
@@ -69,7 +73,10 @@ func (m *Manager) RegisterRequest(
 	timeoutHandler func(),
 ) (time.Time, bool) {
 	newTimeoutHandler := func() {
-		// If this request timed out, tell the benchlist manager
+		// If this request timed out, tell the peer scorer and the benchlist
+		// manager. The scorer is told first so that the benchlist sees the
+		// updated score.
+		m.peerScorer.Observe(validatorID, common.RequestTimeout)
 		m.benchlistMgr.RegisterFailure(chainID, validatorID)
 		timeoutHandler()
 	}
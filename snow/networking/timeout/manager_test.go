@@ -10,6 +10,7 @@ import (
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/snow/networking/benchlist"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,6 +28,7 @@ func TestManagerFire(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -55,6 +57,7 @@ func TestManagerCancel(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
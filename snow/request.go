@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+)
+
+// Request describes the message a chain is processing. It allows the work done
+// by the engine and the VM, such as building a block, to be traced back to the
+// message that triggered it.
+type Request struct {
+	// Unique, among the requests processed by this chain since it started, ID
+	// of the request
+	ID uint64
+	// Node that sent the message. This node's ID for internal messages, such as
+	// timeouts and notifications from the VM.
+	NodeID ids.ShortID
+	// Name of the message's op, e.g. "push_query"
+	Op string
+	// Time at which the message was queued by the chain
+	Received time.Time
+	// Time at which the chain started processing the message
+	Started time.Time
+}
+
+func (r *Request) String() string {
+	return fmt.Sprintf("request %d (%s from %s%s)", r.ID, r.Op, constants.NodeIDPrefix, r.NodeID)
+}
+
+// Request returns the request this chain is processing, or nil if it isn't
+// processing any. Should be called while [ctx.Lock] is held.
+func (ctx *Context) Request() *Request {
+	request, _ := ctx.request.GetValue().(*Request)
+	return request
+}
+
+// SetRequest marks this chain as processing [request]. nil marks this chain as
+// not processing any request.
+func (ctx *Context) SetRequest(request *Request) {
+	ctx.request.SetValue(request)
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const (
+	// BucketDuration is the length of the periods the history of a validator
+	// is recorded in
+	BucketDuration = time.Hour
+
+	historyKeyLen   = hashing.AddrLen + wrappers.LongLen
+	historyValueLen = 2 * wrappers.LongLen
+)
+
+var errInvalidHistoryValue = errors.New("invalid uptime history value")
+
+// History stores the uptime of validators, as observed by this node, bucketed
+// by hour. Unlike the uptime tracked by the Manager, it can be queried over
+// any window.
+type History interface {
+	// Record that [nodeID] was observed from [start] to [end], and whether it
+	// was connected during that time.
+	Record(nodeID ids.ShortID, start, end time.Time, connected bool) error
+
+	// Get returns how long [nodeID] was observed to be connected, and how
+	// long it was observed for, between [start] and [end]. The buckets that
+	// are only partially in the window are counted in proportion to the part
+	// of them that is.
+	Get(nodeID ids.ShortID, start, end time.Time) (upDuration, observedDuration time.Duration, err error)
+}
+
+type history struct {
+	db database.Database
+}
+
+// NewHistory returns a History that is persisted to [db]
+func NewHistory(db database.Database) History {
+	return &history{db: db}
+}
+
+func (h *history) Record(nodeID ids.ShortID, start, end time.Time, connected bool) error {
+	for start.Before(end) {
+		bucket := start.Truncate(BucketDuration)
+		bucketEnd := bucket.Add(BucketDuration)
+		if end.Before(bucketEnd) {
+			bucketEnd = end
+		}
+		duration := bucketEnd.Sub(start)
+
+		key := historyKey(nodeID, bucket)
+		upDuration, observedDuration, err := h.getBucket(key)
+		if err != nil {
+			return err
+		}
+		observedDuration += duration
+		if connected {
+			upDuration += duration
+		}
+		if err := h.db.Put(key, historyValue(upDuration, observedDuration)); err != nil {
+			return err
+		}
+		start = bucketEnd
+	}
+	return nil
+}
+
+func (h *history) Get(nodeID ids.ShortID, start, end time.Time) (time.Duration, time.Duration, error) {
+	if !start.Before(end) {
+		return 0, 0, nil
+	}
+
+	it := h.db.NewIteratorWithStartAndPrefix(
+		historyKey(nodeID, start.Truncate(BucketDuration)),
+		nodeID[:],
+	)
+	defer it.Release()
+
+	var upDuration, observedDuration time.Duration
+	for it.Next() {
+		key := it.Key()
+		if len(key) != historyKeyLen {
+			continue
+		}
+		bucket := time.Unix(int64(binary.BigEndian.Uint64(key[hashing.AddrLen:])), 0)
+		if !bucket.Before(end) {
+			break
+		}
+
+		bucketUp, bucketObserved, err := parseHistoryValue(it.Value())
+		if err != nil {
+			return 0, 0, err
+		}
+
+		// Only count the part of the bucket that is in the window
+		overlapStart, overlapEnd := bucket, bucket.Add(BucketDuration)
+		if overlapStart.Before(start) {
+			overlapStart = start
+		}
+		if end.Before(overlapEnd) {
+			overlapEnd = end
+		}
+		fraction := float64(overlapEnd.Sub(overlapStart)) / float64(BucketDuration)
+		upDuration += time.Duration(float64(bucketUp) * fraction)
+		observedDuration += time.Duration(float64(bucketObserved) * fraction)
+	}
+	return upDuration, observedDuration, it.Error()
+}
+
+func (h *history) getBucket(key []byte) (time.Duration, time.Duration, error) {
+	value, err := h.db.Get(key)
+	if err == database.ErrNotFound {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseHistoryValue(value)
+}
+
+// historyKey sorts the buckets of a validator in chronological order
+func historyKey(nodeID ids.ShortID, bucket time.Time) []byte {
+	key := make([]byte, historyKeyLen)
+	copy(key, nodeID[:])
+	binary.BigEndian.PutUint64(key[hashing.AddrLen:], uint64(bucket.Unix()))
+	return key
+}
+
+func historyValue(upDuration, observedDuration time.Duration) []byte {
+	value := make([]byte, historyValueLen)
+	binary.BigEndian.PutUint64(value, uint64(upDuration))
+	binary.BigEndian.PutUint64(value[wrappers.LongLen:], uint64(observedDuration))
+	return value
+}
+
+func parseHistoryValue(value []byte) (time.Duration, time.Duration, error) {
+	if len(value) != historyValueLen {
+		return 0, 0, errInvalidHistoryValue
+	}
+	upDuration := time.Duration(binary.BigEndian.Uint64(value))
+	observedDuration := time.Duration(binary.BigEndian.Uint64(value[wrappers.LongLen:]))
+	return upDuration, observedDuration, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func TestHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	h := NewHistory(db)
+	nodeID := ids.GenerateTestShortID()
+	otherNodeID := ids.GenerateTestShortID()
+	start := time.Unix(0, 0).Add(100 * BucketDuration)
+
+	// Connected for the first 90 minutes, then disconnected for 30 minutes
+	assert.NoError(h.Record(nodeID, start, start.Add(90*time.Minute), true))
+	assert.NoError(h.Record(nodeID, start.Add(90*time.Minute), start.Add(2*time.Hour), false))
+	assert.NoError(h.Record(otherNodeID, start, start.Add(2*time.Hour), false))
+
+	up, observed, err := h.Get(nodeID, start, start.Add(2*time.Hour))
+	assert.NoError(err)
+	assert.Equal(90*time.Minute, up)
+	assert.Equal(2*time.Hour, observed)
+
+	up, observed, err = h.Get(nodeID, start.Add(time.Hour), start.Add(2*time.Hour))
+	assert.NoError(err)
+	assert.Equal(30*time.Minute, up)
+	assert.Equal(time.Hour, observed)
+
+	// Partial buckets are counted in proportion
+	up, observed, err = h.Get(nodeID, start.Add(90*time.Minute), start.Add(2*time.Hour))
+	assert.NoError(err)
+	assert.Equal(15*time.Minute, up)
+	assert.Equal(30*time.Minute, observed)
+
+	up, observed, err = h.Get(nodeID, start.Add(2*time.Hour), start.Add(3*time.Hour))
+	assert.NoError(err)
+	assert.Zero(up)
+	assert.Zero(observed)
+
+	// The history is persisted
+	up, observed, err = NewHistory(db).Get(otherNodeID, start, start.Add(2*time.Hour))
+	assert.NoError(err)
+	assert.Zero(up)
+	assert.Equal(2*time.Hour, observed)
+}
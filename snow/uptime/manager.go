@@ -4,6 +4,7 @@
 package uptime
 
 import (
+	"errors"
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/database"
@@ -11,11 +12,21 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 )
 
-var _ TestManager = &manager{}
+var (
+	_ TestManager = &manager{}
+
+	errNoHistory = errors.New("uptime history isn't recorded")
+)
 
 type Manager interface {
 	Tracker
 	Calculator
+
+	// CalculateUptimeHistory returns how long [nodeID] was observed to be
+	// connected, and how long it was observed for, between [start] and [end].
+	// Unlike the other uptimes, it doesn't count the time this node was
+	// offline as uptime.
+	CalculateUptimeHistory(nodeID ids.ShortID, start, end time.Time) (upDuration, observedDuration time.Duration, err error)
 }
 
 type Tracker interface {
@@ -48,12 +59,26 @@ type manager struct {
 	state           State
 	connections     map[ids.ShortID]time.Time
 	startedTracking bool
+
+	// If non-nil, the observed uptimes are recorded in [history]
+	history History
+	// Validator ID --> The time since which whether it's connected hasn't
+	// been recorded in [history]
+	observed map[ids.ShortID]time.Time
 }
 
 func NewManager(state State) Manager {
+	return NewManagerWithHistory(state, nil)
+}
+
+// NewManagerWithHistory returns a Manager that records the observed uptimes
+// of the validators in [history]
+func NewManagerWithHistory(state State, history History) Manager {
 	return &manager{
 		state:       state,
 		connections: make(map[ids.ShortID]time.Time),
+		history:     history,
+		observed:    make(map[ids.ShortID]time.Time),
 	}
 }
 
@@ -77,6 +102,11 @@ func (m *manager) StartTracking(nodeIDs []ids.ShortID) error {
 			return err
 		}
 	}
+	if m.history != nil {
+		for _, nodeID := range nodeIDs {
+			m.observed[nodeID] = currentLocalTime
+		}
+	}
 	m.startedTracking = true
 	return nil
 }
@@ -84,6 +114,10 @@ func (m *manager) StartTracking(nodeIDs []ids.ShortID) error {
 func (m *manager) Shutdown(nodeIDs []ids.ShortID) error {
 	currentLocalTime := m.clock.Time()
 	for _, nodeID := range nodeIDs {
+		if err := m.recordHistory(nodeID, currentLocalTime); err != nil {
+			return err
+		}
+
 		if _, connected := m.connections[nodeID]; connected {
 			if err := m.Disconnect(nodeID); err != nil {
 				return err
@@ -110,7 +144,14 @@ func (m *manager) Shutdown(nodeIDs []ids.ShortID) error {
 }
 
 func (m *manager) Connect(nodeID ids.ShortID) error {
-	m.connections[nodeID] = m.clock.Time()
+	currentLocalTime := m.clock.Time()
+	if m.startedTracking {
+		// Record the time the validator was disconnected for
+		if err := m.recordHistory(nodeID, currentLocalTime); err != nil {
+			return err
+		}
+	}
+	m.connections[nodeID] = currentLocalTime
 	return nil
 }
 
@@ -125,10 +166,16 @@ func (m *manager) Disconnect(nodeID ids.ShortID) error {
 		return nil
 	}
 
+	// Record the time the validator was connected for
+	if err := m.recordHistory(nodeID, m.clock.Time()); err != nil {
+		return err
+	}
+
 	newDuration, newLastUpdated, err := m.CalculateUptime(nodeID)
 	delete(m.connections, nodeID)
 	if err == database.ErrNotFound {
 		// If a non-validator disconnects, we don't care
+		delete(m.observed, nodeID)
 		return nil
 	}
 	if err != nil {
@@ -195,6 +242,51 @@ func (m *manager) CalculateUptimePercentFrom(nodeID ids.ShortID, startTime time.
 	return uptime, nil
 }
 
+func (m *manager) CalculateUptimeHistory(nodeID ids.ShortID, start, end time.Time) (time.Duration, time.Duration, error) {
+	if m.history == nil {
+		return 0, 0, errNoHistory
+	}
+	// Make sure the time since the validator last connected or disconnected
+	// is counted
+	if m.startedTracking {
+		if err := m.recordHistory(nodeID, m.clock.Time()); err != nil {
+			return 0, 0, err
+		}
+	}
+	return m.history.Get(nodeID, start, end)
+}
+
+// recordHistory records in [m.history] whether [nodeID] was connected since it
+// was last recorded until [currentLocalTime].
+func (m *manager) recordHistory(nodeID ids.ShortID, currentLocalTime time.Time) error {
+	if m.history == nil {
+		return nil
+	}
+
+	observedSince, observed := m.observed[nodeID]
+	if !observed {
+		// Only validators are recorded. Validators that were added after
+		// tracking started are recorded from the first time they're seen.
+		if _, _, err := m.state.GetUptime(nodeID); err == nil {
+			m.observed[nodeID] = currentLocalTime
+		}
+		return nil
+	}
+
+	// If we are in a weird reality where time has gone backwards, make sure
+	// that we don't double count any uptime.
+	if !observedSince.Before(currentLocalTime) {
+		return nil
+	}
+
+	_, connected := m.connections[nodeID]
+	if err := m.history.Record(nodeID, observedSince, currentLocalTime, connected); err != nil {
+		return err
+	}
+	m.observed[nodeID] = currentLocalTime
+	return nil
+}
+
 func (m *manager) SetTime(newTime time.Time) {
 	m.clock.Set(newTime)
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/stretchr/testify/assert"
 )
@@ -518,3 +519,42 @@ func TestCalculateUptimePercentage(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(float64(0), uptime)
 }
+
+func TestCalculateUptimeHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID0 := ids.GenerateTestShortID()
+	startTime := time.Unix(0, 0).Add(100 * BucketDuration)
+
+	s := NewTestState()
+	s.AddNode(nodeID0, startTime)
+
+	history := NewHistory(memdb.New())
+	up := NewManagerWithHistory(s, history).(*manager)
+	up.clock.Set(startTime)
+
+	assert.NoError(up.StartTracking([]ids.ShortID{nodeID0}))
+
+	// Disconnected for 30 minutes, then connected for 30 minutes
+	up.clock.Set(startTime.Add(30 * time.Minute))
+	assert.NoError(up.Connect(nodeID0))
+	up.clock.Set(startTime.Add(time.Hour))
+
+	upDuration, observedDuration, err := up.CalculateUptimeHistory(nodeID0, startTime, startTime.Add(time.Hour))
+	assert.NoError(err)
+	assert.Equal(30*time.Minute, upDuration)
+	assert.Equal(time.Hour, observedDuration)
+
+	// The time this node is offline isn't observed
+	assert.NoError(up.Shutdown([]ids.ShortID{nodeID0}))
+	up = NewManagerWithHistory(s, history).(*manager)
+	up.clock.Set(startTime.Add(3 * time.Hour))
+	assert.NoError(up.StartTracking([]ids.ShortID{nodeID0}))
+	upDuration, observedDuration, err = up.CalculateUptimeHistory(nodeID0, startTime, startTime.Add(3*time.Hour))
+	assert.NoError(err)
+	assert.Equal(30*time.Minute, upDuration)
+	assert.Equal(time.Hour, observedDuration)
+
+	_, _, err = NewManager(s).CalculateUptimeHistory(nodeID0, startTime, startTime.Add(time.Hour))
+	assert.ErrorIs(err, errNoHistory)
+}
@@ -5,6 +5,7 @@ package validators
 
 import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils"
 	"github.com/Toinounet21/avalanchego-mod/version"
 )
 
@@ -14,3 +15,38 @@ type Connector interface {
 	Connected(id ids.ShortID, nodeVersion version.Application) error
 	Disconnected(id ids.ShortID) error
 }
+
+// PeerInfo describes a peer at the time its connection was marked as
+// connected
+type PeerInfo struct {
+	// Version of the peer
+	Version version.Application
+	// IP the peer is reachable at. It's zero if the peer didn't advertise one.
+	IP utils.IPDesc
+	// Subnets the peer tracks that this node also tracks
+	TrackedSubnets ids.Set
+	// Names of the optional protocol features both this node and the peer
+	// support
+	Features []string
+}
+
+// PeerInfoConnector is a Connector that is told more than the version of the
+// connected peers. Connectors that don't implement it are still called with
+// Connected, so implementing it is optional.
+type PeerInfoConnector interface {
+	Connector
+
+	// ConnectedWithInfo is called instead of Connected when a connection is
+	// marked as connected
+	ConnectedWithInfo(id ids.ShortID, info PeerInfo) error
+}
+
+// Connect notifies [connector] that [id] was connected. If [connector]
+// implements PeerInfoConnector, it's given [info]. Otherwise, it's only given
+// the version of the peer.
+func Connect(connector Connector, id ids.ShortID, info PeerInfo) error {
+	if c, ok := connector.(PeerInfoConnector); ok {
+		return c.ConnectedWithInfo(id, info)
+	}
+	return connector.Connected(id, info.Version)
+}
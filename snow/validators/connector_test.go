@@ -0,0 +1,58 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+type versionConnector struct {
+	version version.Application
+}
+
+func (c *versionConnector) Connected(_ ids.ShortID, nodeVersion version.Application) error {
+	c.version = nodeVersion
+	return nil
+}
+
+func (c *versionConnector) Disconnected(ids.ShortID) error { return nil }
+
+type infoConnector struct {
+	versionConnector
+
+	info PeerInfo
+}
+
+func (c *infoConnector) ConnectedWithInfo(_ ids.ShortID, info PeerInfo) error {
+	c.info = info
+	return nil
+}
+
+func TestConnect(t *testing.T) {
+	assert := assert.New(t)
+
+	info := PeerInfo{
+		Version:        version.CurrentApp,
+		IP:             utils.IPDesc{IP: []byte{127, 0, 0, 1}, Port: 9651},
+		TrackedSubnets: ids.NewSet(1),
+		Features:       []string{"subnets"},
+	}
+	info.TrackedSubnets.Add(ids.GenerateTestID())
+
+	// Connectors that only know about versions are still notified
+	vc := &versionConnector{}
+	assert.NoError(Connect(vc, ids.GenerateTestShortID(), info))
+	assert.Equal(version.CurrentApp, vc.version)
+
+	ic := &infoConnector{}
+	assert.NoError(Connect(ic, ids.GenerateTestShortID(), info))
+	assert.Equal(info, ic.info)
+	assert.Nil(ic.version)
+}
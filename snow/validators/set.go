@@ -4,7 +4,9 @@
 package validators
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -64,27 +66,65 @@ type Set interface {
 	// If sampling the requested size isn't possible, an error will be returned.
 	Sample(size int) ([]Validator, error)
 
+	// SampleSeeded returns a collection of validators, potentially with
+	// duplicates, sampled deterministically from [seed]. The validators are
+	// sampled in order of node ID, so any set with the same validators and
+	// weights returns the same sample for the same seed.
+	SampleSeeded(size int, seed int64) ([]Validator, error)
+
 	// MaskValidator hides the named validator from future samplings
 	MaskValidator(ids.ShortID) error
 
 	// RevealValidator ensures the named validator is not hidden from future
 	// samplings
 	RevealValidator(ids.ShortID) error
+
+	// GetByIndex returns the validator at [index] when the validators are
+	// sorted by node ID. Returns false if [index] is out of range.
+	GetByIndex(index int) (Validator, bool)
+
+	// Page returns at most [limit] validators, sorted by node ID, starting
+	// with the first validator whose node ID is >= [start]. If [limit] is
+	// negative, every validator from [start] on is returned. Since the order
+	// doesn't depend on the order validators were added in, the next page
+	// starts at the node ID after the last one returned.
+	Page(start ids.ShortID, limit int) []Validator
+
+	// WeightBefore returns the cumulative weight of the validators whose node
+	// ID is < [nodeID]. Masked validators have no weight.
+	WeightBefore(nodeID ids.ShortID) uint64
+
+	// RegisterCallbackListener registers [listener] to be notified of the
+	// changes to the set. OnValidatorAdded is immediately called for every
+	// validator currently in the set.
+	RegisterCallbackListener(listener SetCallbackListener)
+}
+
+// SetCallbackListener is notified when validators are added to, removed from,
+// or change weight in a Set. The weights are the weights of the validators
+// regardless of whether they are masked. The callbacks are called while the
+// set is locked, so they must not call back into the set.
+type SetCallbackListener interface {
+	OnValidatorAdded(validatorID ids.ShortID, weight uint64)
+	OnValidatorRemoved(validatorID ids.ShortID, weight uint64)
+	OnValidatorWeightChanged(validatorID ids.ShortID, oldWeight, newWeight uint64)
 }
 
 // NewSet returns a new, empty set of validators.
 func NewSet() Set {
 	return &set{
-		vdrMap:  make(map[ids.ShortID]int),
-		sampler: sampler.NewWeightedWithoutReplacement(),
+		vdrMap:        make(map[ids.ShortID]int),
+		sampler:       sampler.NewWeightedWithoutReplacement(),
+		seededSampler: sampler.NewDeterministicWeightedWithoutReplacement(),
 	}
 }
 
 // NewBestSet returns a new, empty set of validators.
 func NewBestSet(expectedSampleSize int) Set {
 	return &set{
-		vdrMap:  make(map[ids.ShortID]int),
-		sampler: sampler.NewBestWeightedWithoutReplacement(expectedSampleSize),
+		vdrMap:        make(map[ids.ShortID]int),
+		sampler:       sampler.NewBestWeightedWithoutReplacement(expectedSampleSize),
+		seededSampler: sampler.NewDeterministicWeightedWithoutReplacement(),
 	}
 }
 
@@ -101,6 +141,21 @@ type set struct {
 	sampler          sampler.WeightedWithoutReplacement
 	totalWeight      uint64
 	maskedVdrs       ids.ShortSet
+
+	// If false, [sortedVdrs] and [sortedWeights] must be recomputed before
+	// being used
+	sorted bool
+	// The validators, sorted by node ID
+	sortedVdrs []*validator
+	// sortedWeights[i] is the cumulative weight of sortedVdrs[:i]
+	sortedWeights []uint64
+
+	// Samples [sortedVdrs]. If false, [seededSampler] must be initialized
+	// before being used.
+	seededInitialized bool
+	seededSampler     sampler.WeightedWithoutReplacement
+
+	callbackListeners []SetCallbackListener
 }
 
 // Set implements the Set interface.
@@ -112,6 +167,17 @@ func (s *set) Set(vdrs []Validator) error {
 }
 
 func (s *set) set(vdrs []Validator) error {
+	// The previous weights are only needed to notify the listeners of the
+	// differences between the old and the new validators
+	var oldWeights map[ids.ShortID]uint64
+	if len(s.callbackListeners) > 0 {
+		oldWeights = make(map[ids.ShortID]uint64, len(s.vdrSlice))
+		for i, vdr := range s.vdrSlice {
+			oldWeights[vdr.nodeID] = s.vdrWeights[i]
+		}
+		defer s.callSetCallbacks(oldWeights)
+	}
+
 	lenVdrs := len(vdrs)
 	// If the underlying arrays are much larger than necessary, resize them to
 	// allow garbage collection of unused memory
@@ -131,6 +197,7 @@ func (s *set) set(vdrs []Validator) error {
 	s.vdrMap = make(map[ids.ShortID]int, lenVdrs)
 	s.totalWeight = 0
 	s.initialized = false
+	s.sorted = false
 
 	for _, vdr := range vdrs {
 		vdrID := vdr.ID()
@@ -177,6 +244,8 @@ func (s *set) AddWeight(vdrID ids.ShortID, weight uint64) error {
 }
 
 func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
+	s.sorted = false
+
 	var vdr *validator
 	i, ok := s.vdrMap[vdrID]
 	if !ok {
@@ -192,8 +261,14 @@ func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
 		vdr = s.vdrSlice[i]
 	}
 
+	oldWeight := s.vdrWeights[i]
 	s.vdrWeights[i] += weight
 	vdr.addWeight(weight)
+	if oldWeight == 0 {
+		s.callValidatorAddedCallbacks(vdrID, weight)
+	} else {
+		s.callWeightChangedCallbacks(vdrID, oldWeight, s.vdrWeights[i])
+	}
 
 	if s.maskedVdrs.Contains(vdrID) {
 		return nil
@@ -260,11 +335,13 @@ func (s *set) removeWeight(vdrID ids.ShortID, weight uint64) error {
 	if !ok {
 		return nil
 	}
+	s.sorted = false
 
 	// Validator exists
 	vdr := s.vdrSlice[i]
 
-	weight = safemath.Min64(s.vdrWeights[i], weight)
+	oldWeight := s.vdrWeights[i]
+	weight = safemath.Min64(oldWeight, weight)
 	s.vdrWeights[i] -= weight
 	vdr.removeWeight(weight)
 	if !s.maskedVdrs.Contains(vdrID) {
@@ -276,6 +353,9 @@ func (s *set) removeWeight(vdrID ids.ShortID, weight uint64) error {
 		if err := s.remove(vdrID); err != nil {
 			return err
 		}
+		s.callValidatorRemovedCallbacks(vdrID, oldWeight)
+	} else {
+		s.callWeightChangedCallbacks(vdrID, oldWeight, s.vdrWeights[i])
 	}
 	s.initialized = false
 	return nil
@@ -401,6 +481,111 @@ func (s *set) sample(size int) ([]Validator, error) {
 	return list, nil
 }
 
+// SampleSeeded implements the Set interface.
+func (s *set) SampleSeeded(size int, seed int64) ([]Validator, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sort()
+	if !s.seededInitialized {
+		weights := make([]uint64, len(s.sortedVdrs))
+		for i := range weights {
+			weights[i] = s.sortedWeights[i+1] - s.sortedWeights[i]
+		}
+		if err := s.seededSampler.Initialize(weights); err != nil {
+			return nil, err
+		}
+		s.seededInitialized = true
+	}
+
+	s.seededSampler.Seed(seed)
+	indices, err := s.seededSampler.Sample(size)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Validator, size)
+	for i, index := range indices {
+		list[i] = s.sortedVdrs[index]
+	}
+	return list, nil
+}
+
+// GetByIndex implements the Set interface.
+func (s *set) GetByIndex(index int) (Validator, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sort()
+	if index < 0 || index >= len(s.sortedVdrs) {
+		return nil, false
+	}
+	return s.sortedVdrs[index], true
+}
+
+// Page implements the Set interface.
+func (s *set) Page(start ids.ShortID, limit int) []Validator {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sort()
+	i := s.sortedIndex(start)
+	end := len(s.sortedVdrs)
+	if limit >= 0 && i+limit < end {
+		end = i + limit
+	}
+
+	page := make([]Validator, end-i)
+	for j, vdr := range s.sortedVdrs[i:end] {
+		page[j] = vdr
+	}
+	return page
+}
+
+// WeightBefore implements the Set interface.
+func (s *set) WeightBefore(nodeID ids.ShortID) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sort()
+	return s.sortedWeights[s.sortedIndex(nodeID)]
+}
+
+// sort computes [s.sortedVdrs] and [s.sortedWeights], if they aren't up to
+// date.
+func (s *set) sort() {
+	if s.sorted {
+		return
+	}
+
+	s.sortedVdrs = append(s.sortedVdrs[:0], s.vdrSlice...)
+	sort.Slice(s.sortedVdrs, func(i, j int) bool {
+		return bytes.Compare(s.sortedVdrs[i].nodeID[:], s.sortedVdrs[j].nodeID[:]) < 0
+	})
+
+	s.sortedWeights = append(s.sortedWeights[:0], 0)
+	cumulativeWeight := uint64(0)
+	for _, vdr := range s.sortedVdrs {
+		// Can't overflow, since the sum of the weights is [s.totalWeight]
+		cumulativeWeight += s.vdrMaskedWeights[s.vdrMap[vdr.nodeID]]
+		s.sortedWeights = append(s.sortedWeights, cumulativeWeight)
+	}
+	s.sorted = true
+	s.seededInitialized = false
+}
+
+// sortedIndex returns the index of the first validator in [s.sortedVdrs]
+// whose node ID is >= [nodeID].
+// Assumes [s.sortedVdrs] is up to date.
+func (s *set) sortedIndex(nodeID ids.ShortID) int {
+	return sort.Search(len(s.sortedVdrs), func(i int) bool {
+		return bytes.Compare(s.sortedVdrs[i].nodeID[:], nodeID[:]) >= 0
+	})
+}
+
 func (s *set) Weight() uint64 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -467,6 +652,7 @@ func (s *set) maskValidator(vdrID ids.ShortID) error {
 	s.vdrMaskedWeights[i] = 0
 	s.totalWeight -= s.vdrWeights[i]
 	s.initialized = false
+	s.sorted = false
 
 	return nil
 }
@@ -499,6 +685,59 @@ func (s *set) revealValidator(vdrID ids.ShortID) error {
 	}
 	s.totalWeight = newTotalWeight
 	s.initialized = false
+	s.sorted = false
 
 	return nil
 }
+
+func (s *set) RegisterCallbackListener(listener SetCallbackListener) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.callbackListeners = append(s.callbackListeners, listener)
+	for i, vdr := range s.vdrSlice {
+		listener.OnValidatorAdded(vdr.nodeID, s.vdrWeights[i])
+	}
+}
+
+// Assumes [s.lock] is held
+func (s *set) callWeightChangedCallbacks(vdrID ids.ShortID, oldWeight, newWeight uint64) {
+	for _, listener := range s.callbackListeners {
+		listener.OnValidatorWeightChanged(vdrID, oldWeight, newWeight)
+	}
+}
+
+// Assumes [s.lock] is held
+func (s *set) callValidatorAddedCallbacks(vdrID ids.ShortID, weight uint64) {
+	for _, listener := range s.callbackListeners {
+		listener.OnValidatorAdded(vdrID, weight)
+	}
+}
+
+// Assumes [s.lock] is held
+func (s *set) callValidatorRemovedCallbacks(vdrID ids.ShortID, weight uint64) {
+	for _, listener := range s.callbackListeners {
+		listener.OnValidatorRemoved(vdrID, weight)
+	}
+}
+
+// callSetCallbacks notifies the listeners of the differences between
+// [oldWeights] and the current validators.
+// Assumes [s.lock] is held
+func (s *set) callSetCallbacks(oldWeights map[ids.ShortID]uint64) {
+	for i, vdr := range s.vdrSlice {
+		newWeight := s.vdrWeights[i]
+		oldWeight, existed := oldWeights[vdr.nodeID]
+		switch {
+		case !existed:
+			s.callValidatorAddedCallbacks(vdr.nodeID, newWeight)
+		case oldWeight != newWeight:
+			s.callWeightChangedCallbacks(vdr.nodeID, oldWeight, newWeight)
+		}
+	}
+	for vdrID, oldWeight := range oldWeights {
+		if _, ok := s.vdrMap[vdrID]; !ok {
+			s.callValidatorRemovedCallbacks(vdrID, oldWeight)
+		}
+	}
+}
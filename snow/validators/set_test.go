@@ -255,3 +255,205 @@ func TestSamplerMasked(t *testing.T) {
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
 }
+
+func TestSetPage(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.ShortID{0x01}
+	vdr1 := ids.ShortID{0x02}
+	vdr2 := ids.ShortID{0x03}
+
+	s := NewSet()
+	// Added out of order, to check that pages are sorted by node ID
+	assert.NoError(s.AddWeight(vdr2, 3))
+	assert.NoError(s.AddWeight(vdr0, 1))
+	assert.NoError(s.AddWeight(vdr1, 2))
+
+	page := s.Page(ids.ShortEmpty, 2)
+	assert.Len(page, 2)
+	assert.Equal(vdr0, page[0].ID())
+	assert.Equal(vdr1, page[1].ID())
+
+	page = s.Page(vdr2, 2)
+	assert.Len(page, 1)
+	assert.Equal(vdr2, page[0].ID())
+
+	// [start] doesn't need to be a validator
+	page = s.Page(ids.ShortID{0x01, 0x01}, -1)
+	assert.Len(page, 2)
+	assert.Equal(vdr1, page[0].ID())
+
+	assert.Empty(s.Page(ids.ShortID{0x04}, 2))
+	assert.Empty(s.Page(ids.ShortEmpty, 0))
+
+	// Pages reflect changes to the set
+	assert.NoError(s.RemoveWeight(vdr0, 1))
+	page = s.Page(ids.ShortEmpty, 1)
+	assert.Len(page, 1)
+	assert.Equal(vdr1, page[0].ID())
+}
+
+func TestSetGetByIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.ShortID{0x01}
+	vdr1 := ids.ShortID{0x02}
+
+	s := NewSet()
+	assert.NoError(s.AddWeight(vdr1, 2))
+	assert.NoError(s.AddWeight(vdr0, 1))
+
+	vdr, ok := s.GetByIndex(0)
+	assert.True(ok)
+	assert.Equal(vdr0, vdr.ID())
+	assert.EqualValues(1, vdr.Weight())
+
+	vdr, ok = s.GetByIndex(1)
+	assert.True(ok)
+	assert.Equal(vdr1, vdr.ID())
+
+	_, ok = s.GetByIndex(2)
+	assert.False(ok)
+	_, ok = s.GetByIndex(-1)
+	assert.False(ok)
+}
+
+func TestSetWeightBefore(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.ShortID{0x01}
+	vdr1 := ids.ShortID{0x02}
+	vdr2 := ids.ShortID{0x03}
+
+	s := NewSet()
+	assert.NoError(s.AddWeight(vdr2, 4))
+	assert.NoError(s.AddWeight(vdr1, 2))
+	assert.NoError(s.AddWeight(vdr0, 1))
+
+	assert.EqualValues(0, s.WeightBefore(ids.ShortEmpty))
+	assert.EqualValues(0, s.WeightBefore(vdr0))
+	assert.EqualValues(1, s.WeightBefore(vdr1))
+	assert.EqualValues(3, s.WeightBefore(vdr2))
+	assert.EqualValues(7, s.WeightBefore(ids.ShortID{0x04}))
+
+	// Masked validators have no weight
+	assert.NoError(s.MaskValidator(vdr1))
+	assert.EqualValues(1, s.WeightBefore(vdr2))
+	assert.NoError(s.RevealValidator(vdr1))
+	assert.EqualValues(3, s.WeightBefore(vdr2))
+
+	assert.NoError(s.AddWeight(vdr0, 10))
+	assert.EqualValues(13, s.WeightBefore(vdr2))
+}
+
+type callbackListener struct {
+	added   map[ids.ShortID]uint64
+	removed map[ids.ShortID]uint64
+	changed map[ids.ShortID][2]uint64
+}
+
+func newCallbackListener() *callbackListener {
+	return &callbackListener{
+		added:   make(map[ids.ShortID]uint64),
+		removed: make(map[ids.ShortID]uint64),
+		changed: make(map[ids.ShortID][2]uint64),
+	}
+}
+
+func (c *callbackListener) OnValidatorAdded(validatorID ids.ShortID, weight uint64) {
+	c.added[validatorID] = weight
+}
+
+func (c *callbackListener) OnValidatorRemoved(validatorID ids.ShortID, weight uint64) {
+	c.removed[validatorID] = weight
+}
+
+func (c *callbackListener) OnValidatorWeightChanged(validatorID ids.ShortID, oldWeight, newWeight uint64) {
+	c.changed[validatorID] = [2]uint64{oldWeight, newWeight}
+}
+
+func TestSetCallbackListener(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.ShortID{0x01}
+	vdr1 := ids.ShortID{0x02}
+	vdr2 := ids.ShortID{0x03}
+
+	s := NewSet()
+	assert.NoError(s.AddWeight(vdr0, 1))
+
+	// The listener is notified of the validators already in the set
+	listener := newCallbackListener()
+	s.RegisterCallbackListener(listener)
+	assert.Equal(map[ids.ShortID]uint64{vdr0: 1}, listener.added)
+
+	listener = newCallbackListener()
+	s.RegisterCallbackListener(listener)
+	assert.NoError(s.AddWeight(vdr1, 2))
+	assert.NoError(s.AddWeight(vdr0, 3))
+	assert.Equal(uint64(2), listener.added[vdr1])
+	assert.Equal([2]uint64{1, 4}, listener.changed[vdr0])
+
+	// Masked validators keep their weight
+	assert.NoError(s.MaskValidator(vdr1))
+	assert.NoError(s.RemoveWeight(vdr1, 1))
+	assert.Equal([2]uint64{2, 1}, listener.changed[vdr1])
+	assert.NoError(s.RemoveWeight(vdr1, 5))
+	assert.Equal(map[ids.ShortID]uint64{vdr1: 1}, listener.removed)
+
+	// Only the differences with the previous validators are reported
+	listener = newCallbackListener()
+	s.RegisterCallbackListener(listener)
+	listener.added = make(map[ids.ShortID]uint64)
+	assert.NoError(s.Set([]Validator{
+		NewValidator(vdr0, 4),
+		NewValidator(vdr2, 6),
+	}))
+	assert.Equal(map[ids.ShortID]uint64{vdr2: 6}, listener.added)
+	assert.Empty(listener.removed)
+	assert.Empty(listener.changed)
+
+	assert.NoError(s.Set([]Validator{NewValidator(vdr2, 7)}))
+	assert.Equal(map[ids.ShortID]uint64{vdr0: 4}, listener.removed)
+	assert.Equal(map[ids.ShortID][2]uint64{vdr2: {6, 7}}, listener.changed)
+}
+
+func TestSetSampleSeeded(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := make([]ids.ShortID, 10)
+	for i := range vdrs {
+		vdrs[i] = ids.GenerateTestShortID()
+	}
+
+	// The sample only depends on the validators and the seed, not on the order
+	// the validators were added in
+	s0 := NewSet()
+	s1 := NewSet()
+	for i, vdr := range vdrs {
+		assert.NoError(s0.AddWeight(vdr, uint64(i+1)))
+		assert.NoError(s1.AddWeight(vdrs[len(vdrs)-1-i], uint64(len(vdrs)-i)))
+	}
+
+	sample0, err := s0.SampleSeeded(5, 1)
+	assert.NoError(err)
+	sample1, err := s1.SampleSeeded(5, 1)
+	assert.NoError(err)
+	assert.Len(sample0, 5)
+	assert.Len(sample1, 5)
+	for i, vdr := range sample0 {
+		assert.Equal(vdr.ID(), sample1[i].ID())
+	}
+
+	// Changing the weights changes the sample
+	assert.NoError(s0.Set([]Validator{NewValidator(vdrs[0], 10)}))
+	sample0, err = s0.SampleSeeded(5, 1)
+	assert.NoError(err)
+	for _, vdr := range sample0 {
+		assert.Equal(vdrs[0], vdr.ID())
+	}
+
+	sample0, err = s0.SampleSeeded(0, 1)
+	assert.NoError(err)
+	assert.Empty(sample0)
+}
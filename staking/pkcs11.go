@@ -0,0 +1,39 @@
+//go:build pkcs11
+// +build pkcs11
+
+// ^ Only build this file if PKCS#11 support is enabled, as it requires cgo
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package staking
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/ThalesIgnite/crypto11"
+)
+
+var errPKCS11KeyNotFound = errors.New("key not found on the PKCS#11 token")
+
+// NewPKCS11Signer returns a signer that performs its private key operations on
+// the PKCS#11 token described by [config]. The token session is kept open for
+// the lifetime of the process.
+func NewPKCS11Signer(config PKCS11Config) (crypto.Signer, error) {
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:       config.ModulePath,
+		TokenLabel: config.TokenLabel,
+		Pin:        config.PIN,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open PKCS#11 token: %w", err)
+	}
+	signer, err := ctx.FindKeyPair(nil, []byte(config.KeyLabel))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find PKCS#11 key: %w", err)
+	}
+	if signer == nil {
+		return nil, errPKCS11KeyNotFound
+	}
+	return signer, nil
+}
@@ -0,0 +1,19 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+// ^ Only build this file if PKCS#11 support is disabled
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package staking
+
+import (
+	"crypto"
+	"errors"
+)
+
+var errPKCS11NotSupported = errors.New("PKCS#11 isn't supported, the node must be built with the pkcs11 tag")
+
+// NewPKCS11Signer returns an error.
+func NewPKCS11Signer(PKCS11Config) (crypto.Signer, error) {
+	return nil, errPKCS11NotSupported
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	errNoCertificate       = errors.New("no PEM encoded certificate found")
+	errCertificateMismatch = errors.New("certificate doesn't match the signer's public key")
+)
+
+// PKCS11Config describes where to find a staking key held by a PKCS#11 token,
+// such as a hardware security module.
+type PKCS11Config struct {
+	// ModulePath is the path of the PKCS#11 library of the token's vendor
+	ModulePath string
+	// TokenLabel is the label of the token holding the key
+	TokenLabel string
+	// PIN is used to log into the token
+	PIN string
+	// KeyLabel is the label of the key pair on the token
+	KeyLabel string
+}
+
+// LoadTLSCertFromSigner returns a TLS certificate whose private key operations
+// are performed by [signer], which may be backed by a hardware security module
+// or a key management service. [certPath] must hold the PEM encoded
+// certificate of [signer]'s public key.
+func LoadTLSCertFromSigner(signer crypto.Signer, certPath string) (*tls.Certificate, error) {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+	return LoadTLSCertFromSignerBytes(signer, certBytes)
+}
+
+// LoadTLSCertFromSignerBytes is LoadTLSCertFromSigner with the PEM encoded
+// certificate provided directly.
+func LoadTLSCertFromSignerBytes(signer crypto.Signer, certBytes []byte) (*tls.Certificate, error) {
+	block, _ := pem.Decode(certBytes)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errNoCertificate
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse certificate: %w", err)
+	}
+
+	pk, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pk.Equal(signer.Public()) {
+		return nil, errCertificateMismatch
+	}
+	return &tls.Certificate{
+		Certificate: [][]byte{block.Bytes},
+		PrivateKey:  signer,
+		Leaf:        leaf,
+	}, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+func TestLoadTLSCertFromSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	certBytes, keyBytes, err := NewCertAndKeyBytes()
+	assert.NoError(err)
+	keyPair, err := tls.X509KeyPair(certBytes, keyBytes)
+	assert.NoError(err)
+	signer := keyPair.PrivateKey.(crypto.Signer)
+
+	certPath := filepath.Join(t.TempDir(), "staker.crt")
+	assert.NoError(os.WriteFile(certPath, certBytes, 0o600))
+
+	cert, err := LoadTLSCertFromSigner(signer, certPath)
+	assert.NoError(err)
+	assert.Equal(signer, cert.PrivateKey)
+	assert.Equal(keyPair.Certificate, cert.Certificate)
+
+	msg := []byte("msg")
+	sig, err := cert.PrivateKey.(crypto.Signer).Sign(rand.Reader, hashing.ComputeHash256(msg), crypto.SHA256)
+	assert.NoError(err)
+	assert.NoError(cert.Leaf.CheckSignature(cert.Leaf.SignatureAlgorithm, msg, sig))
+}
+
+func TestLoadTLSCertFromSignerMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	certBytes, _, err := NewCertAndKeyBytes()
+	assert.NoError(err)
+	otherCert, err := NewTLSCert()
+	assert.NoError(err)
+
+	_, err = LoadTLSCertFromSignerBytes(otherCert.PrivateKey.(crypto.Signer), certBytes)
+	assert.ErrorIs(err, errCertificateMismatch)
+
+	_, err = LoadTLSCertFromSignerBytes(otherCert.PrivateKey.(crypto.Signer), []byte("not a certificate"))
+	assert.ErrorIs(err, errNoCertificate)
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errUnknownCompressionType = errors.New("unknown compression type")
+
+// Type is the type of compression that was applied to a message.
+// Type is sent over the wire, so existing values must not be changed.
+type Type byte
+
+const (
+	TypeNone Type = iota
+	TypeGzip
+	TypeZstd
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeNone:
+		return "none"
+	case TypeGzip:
+		return "gzip"
+	case TypeZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// TypeFromString returns the compression type with the string representation
+// [s].
+func TypeFromString(s string) (Type, error) {
+	switch s {
+	case TypeNone.String():
+		return TypeNone, nil
+	case TypeGzip.String():
+		return TypeGzip, nil
+	case TypeZstd.String():
+		return TypeZstd, nil
+	default:
+		return TypeNone, fmt.Errorf("%w: %q", errUnknownCompressionType, s)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crashreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/perms"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+const (
+	// Max number of bytes read from the end of the log file
+	maxLogTailSize = 64 * 1024
+	// Max number of log lines included in a report
+	maxLogTailLines = 100
+
+	uploadTimeout = 10 * time.Second
+
+	redacted = "[REDACTED]"
+)
+
+var (
+	_ logging.PanicHandler = &Reporter{}
+
+	// Patterns of the sensitive information that is removed from reports
+	redactions = []struct {
+		pattern     *regexp.Regexp
+		replacement string
+	}{
+		// Credentials in URLs
+		{regexp.MustCompile(`//[^/\s:@]+:[^/\s@]+@`), "//" + redacted + "@"},
+		// Passwords in JSON
+		{regexp.MustCompile(`(?i)("password"\s*:\s*)"[^"]*"`), `${1}"` + redacted + `"`},
+		// Private keys
+		{regexp.MustCompile(`PrivateKey-[1-9A-HJ-NP-Za-km-z]+`), "PrivateKey-" + redacted},
+		// IPv4 addresses
+		{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}\b`), redacted},
+		// IPv6 addresses. Requires at least 3 colons, or a "::", so that
+		// timestamps aren't redacted.
+		{regexp.MustCompile(`\b([0-9a-fA-F]{1,4}:){3,7}[0-9a-fA-F]{1,4}\b|[0-9a-fA-F:]*::[0-9a-fA-F:]*[0-9a-fA-F]`), redacted},
+	}
+)
+
+// Config describes whether and where crash reports are written
+type Config struct {
+	// If false, crash reports aren't written
+	Enabled bool `json:"enabled"`
+	// Directory crash reports are written to
+	Directory string `json:"directory"`
+	// If non-empty, crash reports are also sent to this URL in a POST request
+	UploadURL string `json:"uploadURL"`
+}
+
+// Report is a diagnostic bundle describing a crash.
+// Sensitive information, such as IP addresses, is redacted.
+type Report struct {
+	Time        time.Time `json:"time"`
+	NodeVersion string    `json:"nodeVersion"`
+	// Value the node panicked with
+	Panic string `json:"panic"`
+	Stack string `json:"stack"`
+	// Chain --> State of that chain
+	ChainStates map[string]string `json:"chainStates"`
+	// Last lines of the log file before the crash
+	LogTail []string `json:"logTail"`
+}
+
+// Reporter writes crash reports when the node panics.
+// Only the panics recovered by a logger, using StopOnPanic, RecoverAndPanic
+// or RecoverAndExit, are reported. This covers the node's main goroutine, the
+// chains' handlers, the networking goroutines and the VMs' timers. A panic in
+// any other goroutine crashes the node without a report.
+// It's safe for multiple goroutines to use it concurrently.
+type Reporter struct {
+	config      Config
+	nodeVersion string
+	// Log file whose last lines are included in reports
+	logFile string
+	client  *http.Client
+	// Useful for faking time in tests
+	clock mockable.Clock
+
+	lock sync.Mutex
+	// If non-nil, returns the state of each chain
+	chainStates func() map[string]string
+}
+
+// New returns a Reporter that writes reports as described by [config],
+// including the last lines of [logFile].
+func New(config Config, nodeVersion string, logFile string) *Reporter {
+	return &Reporter{
+		config:      config,
+		nodeVersion: nodeVersion,
+		logFile:     logFile,
+		client:      &http.Client{Timeout: uploadTimeout},
+	}
+}
+
+// SetChainStates sets the function used to get the state of each chain when
+// a report is written
+func (r *Reporter) SetChainStates(chainStates func() map[string]string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.chainStates = chainStates
+}
+
+// HandlePanic writes a report about the panic [panicValue]. Errors are printed
+// to stderr, since the logger may already be stopped.
+func (r *Reporter) HandlePanic(panicValue interface{}, stack string) {
+	path, err := r.Write(panicValue, stack)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't write crash report: %s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "wrote crash report to %s\n", path)
+}
+
+// Write writes a report about the panic [panicValue] to the configured
+// directory, and uploads it if an upload URL is configured. Returns the path
+// of the report.
+func (r *Reporter) Write(panicValue interface{}, stack string) (string, error) {
+	report := r.newReport(panicValue, stack)
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(r.config.Directory, perms.ReadWriteExecute); err != nil {
+		return "", fmt.Errorf("couldn't create crash report directory: %w", err)
+	}
+	path := filepath.Join(r.config.Directory, fmt.Sprintf("crash-%d.json", report.Time.UnixNano()))
+	if err := perms.WriteFile(path, reportBytes, perms.ReadWrite); err != nil {
+		return "", fmt.Errorf("couldn't write crash report: %w", err)
+	}
+
+	if r.config.UploadURL == "" {
+		return path, nil
+	}
+	return path, r.upload(reportBytes)
+}
+
+func (r *Reporter) newReport(panicValue interface{}, stack string) *Report {
+	r.lock.Lock()
+	chainStates := r.chainStates
+	r.lock.Unlock()
+
+	report := &Report{
+		Time:        r.clock.Time(),
+		NodeVersion: r.nodeVersion,
+		Panic:       redact(fmt.Sprint(panicValue)),
+		Stack:       redact(stack),
+		ChainStates: map[string]string{},
+		LogTail:     []string{},
+	}
+	if chainStates != nil {
+		report.ChainStates = chainStates()
+	}
+	logTail, err := readTail(r.logFile)
+	if err != nil {
+		report.LogTail = append(report.LogTail, fmt.Sprintf("couldn't read log file: %s", err))
+	}
+	for _, line := range logTail {
+		report.LogTail = append(report.LogTail, redact(line))
+	}
+	return report
+}
+
+func (r *Reporter) upload(reportBytes []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.config.UploadURL, bytes.NewReader(reportBytes))
+	if err != nil {
+		return fmt.Errorf("couldn't create crash report upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("couldn't upload crash report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("crash report upload failed with status %s", resp.Status)
+	}
+	return nil
+}
+
+// readTail returns the last lines of the file at [path]
+func readTail(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - maxLogTailSize
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	tail, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(tail), "\n"), "\n")
+	if offset > 0 {
+		// The first line is likely incomplete
+		lines = lines[1:]
+	}
+	if len(lines) > maxLogTailLines {
+		lines = lines[len(lines)-maxLogTailLines:]
+	}
+	return lines, nil
+}
+
+// redact removes sensitive information from [s]
+func redact(s string) string {
+	for _, redaction := range redactions {
+		s = redaction.pattern.ReplaceAllString(s, redaction.replacement)
+	}
+	return s
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+
+	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+const (
+	// BLSSKLen is the number of bytes in a BLS12-381 private key
+	BLSSKLen = 32
+
+	// BLSPKLen is the number of bytes in a BLS12-381 public key. Public keys
+	// are uncompressed G1 points.
+	BLSPKLen = 96
+
+	// BLSSigLen is the number of bytes in a BLS12-381 signature. Signatures
+	// are uncompressed G2 points.
+	BLSSigLen = 192
+)
+
+var (
+	// blsSigDST is the domain separation tag of signatures. Messages are
+	// augmented with the signer's public key, which prevents rogue key attacks
+	// when signatures of the same message are aggregated.
+	blsSigDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_")
+
+	// blsPoPDST is the domain separation tag of proofs of possession
+	blsPoPDST = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+	errInvalidBLSPrivateKey = errors.New("invalid bls private key")
+	errInvalidBLSPublicKey  = errors.New("invalid bls public key")
+	errInvalidBLSSignature  = errors.New("invalid bls signature")
+	errNoBLSSignatures      = errors.New("no bls signatures to aggregate")
+
+	_ Factory    = &FactoryBLS{}
+	_ PublicKey  = &PublicKeyBLS{}
+	_ PrivateKey = &PrivateKeyBLS{}
+)
+
+// FactoryBLS creates BLS12-381 keys. The curve arithmetic is done by blst, in
+// constant time where secret values are involved.
+type FactoryBLS struct{}
+
+// NewPrivateKey implements the Factory interface
+func (*FactoryBLS) NewPrivateKey() (PrivateKey, error) {
+	ikm := make([]byte, BLSSKLen)
+	if _, err := rand.Read(ikm); err != nil {
+		return nil, err
+	}
+	return &PrivateKeyBLS{sk: blst.KeyGen(ikm)}, nil
+}
+
+// ToPublicKey implements the Factory interface. The key must be a
+// non-identity element of the prime order subgroup.
+func (*FactoryBLS) ToPublicKey(b []byte) (PublicKey, error) {
+	pk := new(blst.P1Affine).Deserialize(b)
+	if pk == nil || !pk.KeyValidate() || !bytes.Equal(pk.Serialize(), b) {
+		return nil, errInvalidBLSPublicKey
+	}
+	return &PublicKeyBLS{
+		pk:    pk,
+		bytes: b,
+	}, nil
+}
+
+// ToPrivateKey implements the Factory interface
+func (*FactoryBLS) ToPrivateKey(b []byte) (PrivateKey, error) {
+	sk := new(blst.SecretKey).Deserialize(b)
+	if sk == nil {
+		return nil, errInvalidBLSPrivateKey
+	}
+	return &PrivateKeyBLS{
+		sk:    sk,
+		bytes: b,
+	}, nil
+}
+
+type PublicKeyBLS struct {
+	pk    *blst.P1Affine
+	addr  ids.ShortID
+	bytes []byte
+}
+
+// Verify implements the PublicKey interface
+func (k *PublicKeyBLS) Verify(msg, sig []byte) bool {
+	return k.VerifyHash(hashing.ComputeHash256(msg), sig)
+}
+
+// VerifyHash implements the PublicKey interface
+func (k *PublicKeyBLS) VerifyHash(hash, sig []byte) bool {
+	return AggregateVerifyBLSHash([]*PublicKeyBLS{k}, hash, sig)
+}
+
+// VerifyProofOfPossession returns true iff [proof] proves that the owner of
+// this key knows the corresponding private key
+func (k *PublicKeyBLS) VerifyProofOfPossession(proof []byte) bool {
+	sig, err := blsSignatureFromBytes(proof)
+	if err != nil {
+		return false
+	}
+	return sig.Verify(false, k.pk, false, k.Bytes(), blsPoPDST)
+}
+
+// Address implements the PublicKey interface
+func (k *PublicKeyBLS) Address() ids.ShortID {
+	if k.addr == ids.ShortEmpty {
+		addr, err := ids.ToShortID(hashing.PubkeyBytesToAddress(k.Bytes()))
+		if err != nil {
+			panic(err)
+		}
+		k.addr = addr
+	}
+	return k.addr
+}
+
+// Bytes implements the PublicKey interface
+func (k *PublicKeyBLS) Bytes() []byte {
+	if k.bytes == nil {
+		k.bytes = k.pk.Serialize()
+	}
+	return k.bytes
+}
+
+type PrivateKeyBLS struct {
+	sk    *blst.SecretKey
+	pk    *PublicKeyBLS
+	bytes []byte
+}
+
+// PublicKey implements the PrivateKey interface
+func (k *PrivateKeyBLS) PublicKey() PublicKey {
+	return k.blsPublicKey()
+}
+
+func (k *PrivateKeyBLS) blsPublicKey() *PublicKeyBLS {
+	if k.pk == nil {
+		k.pk = &PublicKeyBLS{pk: new(blst.P1Affine).From(k.sk)}
+	}
+	return k.pk
+}
+
+// Sign implements the PrivateKey interface
+func (k *PrivateKeyBLS) Sign(msg []byte) ([]byte, error) {
+	return k.SignHash(hashing.ComputeHash256(msg))
+}
+
+// SignHash implements the PrivateKey interface. The hash is augmented with
+// this key's public key before being signed.
+func (k *PrivateKeyBLS) SignHash(hash []byte) ([]byte, error) {
+	return k.sign(blsSigDST, blsAugment(k.blsPublicKey(), hash)), nil
+}
+
+// ProofOfPossession returns a signature of this key's public key, which proves
+// that the owner of the public key knows this private key
+func (k *PrivateKeyBLS) ProofOfPossession() ([]byte, error) {
+	return k.sign(blsPoPDST, k.blsPublicKey().Bytes()), nil
+}
+
+func (k *PrivateKeyBLS) sign(dst, msg []byte) []byte {
+	return new(blst.P2Affine).Sign(k.sk, msg, dst).Serialize()
+}
+
+// Bytes implements the PrivateKey interface
+func (k *PrivateKeyBLS) Bytes() []byte {
+	if k.bytes == nil {
+		k.bytes = k.sk.Serialize()
+	}
+	return k.bytes
+}
+
+// AggregateBLSSignatures combines [sigs] into a single signature
+func AggregateBLSSignatures(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errNoBLSSignatures
+	}
+	points := make([]*blst.P2Affine, len(sigs))
+	for i, sigBytes := range sigs {
+		sig, err := blsSignatureFromBytes(sigBytes)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = sig
+	}
+	agg := new(blst.P2Aggregate)
+	if !agg.Aggregate(points, false) {
+		return nil, errInvalidBLSSignature
+	}
+	return agg.ToAffine().Serialize(), nil
+}
+
+// AggregateVerifyBLSHash returns true iff [sig] is an aggregation of the
+// signatures of [hash] by every key in [pks]
+func AggregateVerifyBLSHash(pks []*PublicKeyBLS, hash, sig []byte) bool {
+	if len(pks) == 0 {
+		return false
+	}
+	aggSig, err := blsSignatureFromBytes(sig)
+	if err != nil {
+		return false
+	}
+	points := make([]*blst.P1Affine, len(pks))
+	msgs := make([]blst.Message, len(pks))
+	for i, pk := range pks {
+		points[i] = pk.pk
+		msgs[i] = blsAugment(pk, hash)
+	}
+	return aggSig.AggregateVerify(false, points, false, msgs, blsSigDST)
+}
+
+// blsSignatureFromBytes parses a signature, which must be the canonical
+// encoding of an element of the prime order subgroup
+func blsSignatureFromBytes(b []byte) (*blst.P2Affine, error) {
+	sig := new(blst.P2Affine).Deserialize(b)
+	if sig == nil || !sig.SigValidate(false) || !bytes.Equal(sig.Serialize(), b) {
+		return nil, errInvalidBLSSignature
+	}
+	return sig, nil
+}
+
+// blsAugment returns [pk] || [msg]
+func blsAugment(pk *PublicKeyBLS, msg []byte) []byte {
+	pkBytes := pk.Bytes()
+	augmented := make([]byte, 0, len(pkBytes)+len(msg))
+	augmented = append(augmented, pkBytes...)
+	return append(augmented, msg...)
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+func newBLSKey(t *testing.T) *PrivateKeyBLS {
+	factory := FactoryBLS{}
+	sk, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk.(*PrivateKeyBLS)
+}
+
+func TestBLSKnownAnswer(t *testing.T) {
+	// Signatures are deterministic, so the encodings of keys, signatures and
+	// proofs of possession must not change
+	skBytes, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	expectedPK, _ := hex.DecodeString("0491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a17cd7061575d3e8034fcea62adaa1a3bc38dca4b50e4c5c01d04dd78037c9cee914e17944ea99e7ad84278e5d49f36c4")
+	expectedSig, _ := hex.DecodeString("165d6bf3e006a6eaa91ccd6e62f5e8323be5001ebc25f84d0930f1358d813af1008b5f5508fc9be9bc0656355480a31512e356669db0ca0a31f2d4e34a1ae754a9f1072991e742182b366aafff5022366c4a4d9bab90cc86e1810f5c5fdc6a570e2d620dfc435a5fc0ffbd82587aa89a1ef4d3969e64de1f3f46285dab5c42f2114940c2b7e41427a581ded58ce582cb081c8944a34d4f619902bbbb5d0b031fccefaaa3fba2a9108bd0c9fe45485974b6a399e72a76b8e236283976406c8881")
+	expectedPoP, _ := hex.DecodeString("0cd5b8c7cfcc6c5a2ddcd65071416d6dfcc1880517895cb66c9b2f43bef87be4e71e391fd380f3f13b11122a520d08420f1c69be48e62f814cc17d5ecd7b94471108add418cd86fbba3bcca2537689b0b82195b34d2108d06635d20d58baa9a8196927c8065eb46b43d19fd73379143c7157ff4983f2cc4f15d3123f581e19c3e9af1f89cfeb81dd2fa535adb6224bbe0c2a4bd5710d9cc8b2756b4d1ed7430d1131b0254e14be64e1d9a6dbfcee526c56c51248f74cf939da2ee2b946df6384")
+
+	factory := FactoryBLS{}
+	skIntf, err := factory.ToPrivateKey(skBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := skIntf.(*PrivateKeyBLS)
+	if pk := sk.PublicKey().Bytes(); !bytes.Equal(pk, expectedPK) {
+		t.Fatalf("expected public key %x but got %x", expectedPK, pk)
+	}
+	sig, err := sk.Sign([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, expectedSig) {
+		t.Fatalf("expected signature %x but got %x", expectedSig, sig)
+	}
+	pop, err := sk.ProofOfPossession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pop, expectedPoP) {
+		t.Fatalf("expected proof of possession %x but got %x", expectedPoP, pop)
+	}
+}
+
+func TestBLSSignVerify(t *testing.T) {
+	sk := newBLSKey(t)
+	pk := sk.PublicKey()
+	msg := []byte("hello")
+
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != BLSSigLen {
+		t.Fatalf("expected %d signature bytes but got %d", BLSSigLen, len(sig))
+	}
+	if !pk.Verify(msg, sig) {
+		t.Fatalf("signature should have been valid")
+	}
+	if !pk.VerifyHash(hashing.ComputeHash256(msg), sig) {
+		t.Fatalf("signature of the hash should have been valid")
+	}
+	if pk.Verify([]byte("world"), sig) {
+		t.Fatalf("signature of a different message should have been invalid")
+	}
+	if newBLSKey(t).PublicKey().Verify(msg, sig) {
+		t.Fatalf("signature by a different key should have been invalid")
+	}
+}
+
+func TestBLSAggregateVerify(t *testing.T) {
+	hash := hashing.ComputeHash256([]byte("hello"))
+	pks := []*PublicKeyBLS{}
+	sigs := [][]byte{}
+	for i := 0; i < 3; i++ {
+		sk := newBLSKey(t)
+		sig, err := sk.SignHash(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks = append(pks, sk.PublicKey().(*PublicKeyBLS))
+		sigs = append(sigs, sig)
+	}
+
+	aggSig, err := AggregateBLSSignatures(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !AggregateVerifyBLSHash(pks, hash, aggSig) {
+		t.Fatalf("aggregate signature should have been valid")
+	}
+	if AggregateVerifyBLSHash(pks[:2], hash, aggSig) {
+		t.Fatalf("aggregate signature should have required every signer")
+	}
+	if AggregateVerifyBLSHash(nil, hash, aggSig) {
+		t.Fatalf("aggregate signature without signers should have been invalid")
+	}
+
+	partialSig, err := AggregateBLSSignatures(sigs[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if AggregateVerifyBLSHash(pks, hash, partialSig) {
+		t.Fatalf("partial aggregate signature should have been invalid")
+	}
+
+	if _, err := AggregateBLSSignatures(nil); err != errNoBLSSignatures {
+		t.Fatalf("expected %v but got %v", errNoBLSSignatures, err)
+	}
+}
+
+func TestBLSProofOfPossession(t *testing.T) {
+	sk := newBLSKey(t)
+	pk := sk.PublicKey().(*PublicKeyBLS)
+
+	proof, err := sk.ProofOfPossession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.VerifyProofOfPossession(proof) {
+		t.Fatalf("proof of possession should have been valid")
+	}
+
+	otherPK := newBLSKey(t).PublicKey().(*PublicKeyBLS)
+	if otherPK.VerifyProofOfPossession(proof) {
+		t.Fatalf("proof of possession of a different key should have been invalid")
+	}
+
+	// A proof of possession must not be usable as a signature of the public
+	// key, and vice versa
+	sig, err := sk.Sign(pk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.VerifyProofOfPossession(sig) {
+		t.Fatalf("signature shouldn't have been a valid proof of possession")
+	}
+	if pk.Verify(pk.Bytes(), proof) {
+		t.Fatalf("proof of possession shouldn't have been a valid signature")
+	}
+}
+
+func TestBLSSerialization(t *testing.T) {
+	factory := FactoryBLS{}
+	sk := newBLSKey(t)
+	msg := []byte("hello")
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedSK, err := factory.ToPrivateKey(sk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parsedSK.Bytes(), sk.Bytes()) {
+		t.Fatalf("private key changed after parsing")
+	}
+	if !bytes.Equal(parsedSK.PublicKey().Bytes(), sk.PublicKey().Bytes()) {
+		t.Fatalf("public key changed after parsing the private key")
+	}
+
+	pkBytes := sk.PublicKey().Bytes()
+	if len(pkBytes) != BLSPKLen {
+		t.Fatalf("expected %d public key bytes but got %d", BLSPKLen, len(pkBytes))
+	}
+	pk, err := factory.ToPublicKey(pkBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Verify(msg, sig) {
+		t.Fatalf("signature should have been valid for the parsed public key")
+	}
+	if pk.Address() != sk.PublicKey().Address() {
+		t.Fatalf("address changed after parsing the public key")
+	}
+
+	if _, err := factory.ToPrivateKey(make([]byte, BLSSKLen)); err != errInvalidBLSPrivateKey {
+		t.Fatalf("expected %v but got %v", errInvalidBLSPrivateKey, err)
+	}
+	if _, err := factory.ToPublicKey(make([]byte, BLSPKLen)); err != errInvalidBLSPublicKey {
+		t.Fatalf("expected %v but got %v", errInvalidBLSPublicKey, err)
+	}
+	if pk.Verify(msg, make([]byte, BLSSigLen-1)) {
+		t.Fatalf("malformed signature should have been invalid")
+	}
+}
@@ -19,6 +19,7 @@ const (
 	RSAPSS
 	ED25519
 	SECP256K1
+	BLS
 )
 
 var (
@@ -43,6 +44,7 @@ func init() {
 		RSAPSS:    &FactoryRSAPSS{},
 		ED25519:   &FactoryED25519{},
 		SECP256K1: &FactorySECP256K1R{},
+		BLS:       &FactoryBLS{},
 	}
 	for _, f := range factories {
 		fKeys := []PublicKey{}
@@ -102,3 +104,10 @@ func BenchmarkSECP256k1Verify(b *testing.B) {
 		verify(SECP256K1)
 	}
 }
+
+// BenchmarkBLSVerify runs the benchmark with BLS keys
+func BenchmarkBLSVerify(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		verify(BLS)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+const (
+	// HardenedKeyStart is the index of the first hardened child key, as
+	// defined by BIP32
+	HardenedKeyStart uint32 = 1 << 31
+
+	// AvalancheCoinType is the BIP44 coin type registered for Avalanche
+	AvalancheCoinType uint32 = 9000
+
+	// MinSeedLen is the minimum length of a BIP32 seed
+	MinSeedLen = 16
+
+	// MaxSeedLen is the maximum length of a BIP32 seed
+	MaxSeedLen = 64
+
+	// RecommendedSeedLen is the seed length recommended by BIP32
+	RecommendedSeedLen = 32
+
+	hdChainCodeLen = 32
+)
+
+var (
+	// masterKeyHMACKey is the HMAC key used to derive a master key from a seed,
+	// as defined by BIP32
+	masterKeyHMACKey = []byte("Bitcoin seed")
+
+	errInvalidSeedLen   = fmt.Errorf("seed length must be between %d and %d bytes", MinSeedLen, MaxSeedLen)
+	errUnusableSeed     = errors.New("seed produced an invalid master key")
+	errInvalidChild     = errors.New("child index produced an invalid key")
+	errEmptyDerivation  = errors.New("derivation path is empty")
+	errInvalidPathStart = errors.New("derivation path must start with \"m\"")
+)
+
+// ExtendedKeySECP256K1R is a BIP32 extended private key
+type ExtendedKeySECP256K1R struct {
+	key       secp256k1.ModNScalar
+	chainCode [hdChainCodeLen]byte
+}
+
+// NewMasterKeySECP256K1R returns the BIP32 master key derived from [seed]
+func NewMasterKeySECP256K1R(seed []byte) (*ExtendedKeySECP256K1R, error) {
+	if len(seed) < MinSeedLen || len(seed) > MaxSeedLen {
+		return nil, errInvalidSeedLen
+	}
+
+	mac := hmac.New(sha512.New, masterKeyHMACKey)
+	_, _ = mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	k := &ExtendedKeySECP256K1R{}
+	if overflow := k.key.SetByteSlice(sum[:32]); overflow || k.key.IsZero() {
+		return nil, errUnusableSeed
+	}
+	copy(k.chainCode[:], sum[32:])
+	return k, nil
+}
+
+// Child returns the child key at [index]. Indices at or above
+// [HardenedKeyStart] derive hardened children.
+func (k *ExtendedKeySECP256K1R) Child(index uint32) (*ExtendedKeySECP256K1R, error) {
+	// data = ser256(k) or serP(point(k)), followed by ser32(i)
+	var data []byte
+	if index >= HardenedKeyStart {
+		keyBytes := k.key.Bytes()
+		data = make([]byte, 1+len(keyBytes), 1+len(keyBytes)+4)
+		copy(data[1:], keyBytes[:])
+	} else {
+		data = k.privateKey().PubKey().SerializeCompressed()
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode[:])
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+
+	child := &ExtendedKeySECP256K1R{}
+	if overflow := child.key.SetByteSlice(sum[:32]); overflow {
+		return nil, errInvalidChild
+	}
+	child.key.Add(&k.key)
+	if child.key.IsZero() {
+		return nil, errInvalidChild
+	}
+	copy(child.chainCode[:], sum[32:])
+	return child, nil
+}
+
+// Derive returns the descendant of this key at [path], relative to this key
+func (k *ExtendedKeySECP256K1R) Derive(path []uint32) (*ExtendedKeySECP256K1R, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ChainCode returns the chain code of this key
+func (k *ExtendedKeySECP256K1R) ChainCode() []byte {
+	chainCode := k.chainCode
+	return chainCode[:]
+}
+
+// PrivateKey returns the secp256k1 private key of this extended key
+func (k *ExtendedKeySECP256K1R) PrivateKey() *PrivateKeySECP256K1R {
+	return &PrivateKeySECP256K1R{sk: k.privateKey()}
+}
+
+func (k *ExtendedKeySECP256K1R) privateKey() *secp256k1.PrivateKey {
+	keyBytes := k.key.Bytes()
+	return secp256k1.PrivKeyFromBytes(keyBytes[:])
+}
+
+// AvalancheDerivationPath returns the BIP44 path
+// m/44'/9000'/[account]'/[change]/[index]
+func AvalancheDerivationPath(account, change, index uint32) []uint32 {
+	return []uint32{
+		44 + HardenedKeyStart,
+		AvalancheCoinType + HardenedKeyStart,
+		account + HardenedKeyStart,
+		change,
+		index,
+	}
+}
+
+// ParseDerivationPath parses a path of the form m/44'/9000'/0'/0/0. Hardened
+// indices may be marked with either ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) == 0 || components[0] != "m" {
+		return nil, errInvalidPathStart
+	}
+	components = components[1:]
+	if len(components) == 0 {
+		return nil, errEmptyDerivation
+	}
+
+	indices := make([]uint32, len(components))
+	for i, component := range components {
+		hardened := strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h")
+		if hardened {
+			component = component[:len(component)-1]
+		}
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path component %q: %w", components[i], err)
+		}
+		if uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("derivation path component %q is out of range", components[i])
+		}
+		indices[i] = uint32(index)
+		if hardened {
+			indices[i] += HardenedKeyStart
+		}
+	}
+	return indices, nil
+}
+
+// FormatDerivationPath returns the string representation of [path], using '
+// to mark hardened indices
+func FormatDerivationPath(path []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range path {
+		sb.WriteString("/")
+		if index >= HardenedKeyStart {
+			sb.WriteString(strconv.FormatUint(uint64(index-HardenedKeyStart), 10))
+			sb.WriteString("'")
+		} else {
+			sb.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vector 1 from BIP32
+func TestExtendedKeySECP256K1RVectors(t *testing.T) {
+	assert := assert.New(t)
+
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	assert.NoError(err)
+
+	tests := []struct {
+		path      string
+		key       string
+		chainCode string
+	}{
+		{
+			path:      "m/0'",
+			key:       "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			chainCode: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+		},
+		{
+			path:      "m/0'/1",
+			key:       "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			chainCode: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+		},
+		{
+			path:      "m/0h/1/2h",
+			key:       "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+			chainCode: "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f",
+		},
+	}
+
+	master, err := NewMasterKeySECP256K1R(seed)
+	assert.NoError(err)
+	assert.Equal("e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", hex.EncodeToString(master.PrivateKey().Bytes()))
+	assert.Equal("873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", hex.EncodeToString(master.ChainCode()))
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			path, err := ParseDerivationPath(test.path)
+			assert.NoError(err)
+
+			key, err := master.Derive(path)
+			assert.NoError(err)
+			assert.Equal(test.key, hex.EncodeToString(key.PrivateKey().Bytes()))
+			assert.Equal(test.chainCode, hex.EncodeToString(key.ChainCode()))
+		})
+	}
+}
+
+func TestNewMasterKeySECP256K1RInvalidSeed(t *testing.T) {
+	_, err := NewMasterKeySECP256K1R(make([]byte, MinSeedLen-1))
+	assert.ErrorIs(t, err, errInvalidSeedLen)
+
+	_, err = NewMasterKeySECP256K1R(make([]byte, MaxSeedLen+1))
+	assert.ErrorIs(t, err, errInvalidSeedLen)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ParseDerivationPath("m/44'/9000'/0'/0/7")
+	assert.NoError(err)
+	assert.Equal(AvalancheDerivationPath(0, 0, 7), path)
+	assert.Equal("m/44'/9000'/0'/0/7", FormatDerivationPath(path))
+
+	_, err = ParseDerivationPath("44'/9000'")
+	assert.ErrorIs(err, errInvalidPathStart)
+
+	_, err = ParseDerivationPath("m")
+	assert.ErrorIs(err, errEmptyDerivation)
+
+	_, err = ParseDerivationPath("m/x")
+	assert.Error(err)
+
+	_, err = ParseDerivationPath("m/2147483648")
+	assert.Error(err)
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package ledger signs with the keys held by a Ledger hardware wallet running
+// the Avalanche app. The node doesn't talk to the device directly, but through
+// a bridge: a local HTTP service, connected to the device, that serves:
+//
+//	POST /publicKey {"path": "m/44'/9000'/0'/0/0"}
+//	  -> {"publicKey": "<hex of the compressed public key>"}
+//	POST /signHash {"path": "m/44'/9000'/0'/0/0", "hash": "<hex>"}
+//	  -> {"signature": "<hex of the 65 byte recoverable signature>"}
+//
+// Signing prompts the user to approve the signature on the device.
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+const (
+	// Avalanche's BIP-44 coin type
+	coinType = 9000
+
+	DefaultNumAddresses   = 10
+	DefaultRequestTimeout = 2 * time.Minute
+)
+
+var (
+	errNoBridge       = errors.New("ledger bridge URL must be specified")
+	errNoAddresses    = errors.New("number of ledger addresses must be > 0")
+	errNoTimeout      = errors.New("ledger request timeout must be > 0")
+	errWrongSignature = errors.New("ledger signature doesn't match the requested address")
+)
+
+// Config describes how to reach a Ledger and which of its keys to use
+type Config struct {
+	// URL of the bridge to the Ledger
+	BridgeURL string `json:"bridgeURL"`
+	// The keys at m/44'/9000'/0'/0/i, for i in [0, NumAddresses), are used
+	NumAddresses uint32 `json:"numAddresses"`
+	// Timeout of each request to the bridge. Must leave time for the user to
+	// approve signatures on the device.
+	RequestTimeout time.Duration `json:"requestTimeout"`
+}
+
+// Verify returns an error if the config is invalid
+func (c *Config) Verify() error {
+	switch {
+	case c.BridgeURL == "":
+		return errNoBridge
+	case c.NumAddresses == 0:
+		return errNoAddresses
+	case c.RequestTimeout <= 0:
+		return errNoTimeout
+	default:
+		return nil
+	}
+}
+
+// Ledger signs hashes with the keys held by a Ledger
+type Ledger struct {
+	config  Config
+	factory crypto.FactorySECP256K1R
+	http    *http.Client
+
+	// Address --> derivation path of its key
+	paths map[ids.ShortID]string
+	addrs ids.ShortSet
+}
+
+// New returns a Ledger that signs with the keys described by [config]. The
+// addresses of the keys are read from the device.
+func New(config Config) (*Ledger, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	l := &Ledger{
+		config: config,
+		http:   &http.Client{},
+		paths:  make(map[ids.ShortID]string, config.NumAddresses),
+	}
+	l.config.BridgeURL = strings.TrimSuffix(config.BridgeURL, "/")
+	for i := uint32(0); i < config.NumAddresses; i++ {
+		path := DerivationPath(i)
+		pk, err := l.publicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read the public key at %s: %w", path, err)
+		}
+		addr := pk.Address()
+		l.paths[addr] = path
+		l.addrs.Add(addr)
+	}
+	return l, nil
+}
+
+// DerivationPath returns the BIP-44 derivation path of the [index]'th key
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", coinType, index)
+}
+
+// Addresses implements the secp256k1fx.Signer interface
+func (l *Ledger) Addresses() ids.ShortSet { return l.addrs }
+
+// SignHash implements the secp256k1fx.Signer interface. It blocks until the
+// signature is approved or rejected on the device.
+func (l *Ledger) SignHash(addr ids.ShortID, hash []byte) ([]byte, error) {
+	path, ok := l.paths[addr]
+	if !ok {
+		return nil, fmt.Errorf("no ledger key for address %s", addr)
+	}
+
+	reply := signHashReply{}
+	err := l.send("/signHash", &signHashArgs{
+		Path: path,
+		Hash: hex.EncodeToString(hash),
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := hex.DecodeString(reply.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse signature: %w", err)
+	}
+
+	// The bridge isn't trusted to sign with the right key
+	pk, err := l.factory.RecoverHashPublicKey(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't recover the signer: %w", err)
+	}
+	if pk.Address() != addr {
+		return nil, errWrongSignature
+	}
+	return sig, nil
+}
+
+type publicKeyArgs struct {
+	Path string `json:"path"`
+}
+
+type publicKeyReply struct {
+	PublicKey string `json:"publicKey"`
+}
+
+type signHashArgs struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+}
+
+type signHashReply struct {
+	Signature string `json:"signature"`
+}
+
+func (l *Ledger) publicKey(path string) (crypto.PublicKey, error) {
+	reply := publicKeyReply{}
+	if err := l.send("/publicKey", &publicKeyArgs{Path: path}, &reply); err != nil {
+		return nil, err
+	}
+	pkBytes, err := hex.DecodeString(reply.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse public key: %w", err)
+	}
+	return l.factory.ToPublicKey(pkBytes)
+}
+
+func (l *Ledger) send(endpoint string, args interface{}, reply interface{}) error {
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.config.RequestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, l.config.BridgeURL+endpoint, bytes.NewReader(argsBytes))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := l.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("ledger bridge request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		errorBody, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("ledger bridge responded with status %d: %s", response.StatusCode, strings.TrimSpace(string(errorBody)))
+	}
+	return json.NewDecoder(response.Body).Decode(reply)
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ledger
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+// fakeBridge serves the bridge API with keys held in memory
+type fakeBridge struct {
+	keys map[string]*crypto.PrivateKeySECP256K1R
+	// If non-empty, signatures are made by the key at [signWith] rather than
+	// the requested key
+	signWith string
+}
+
+func newFakeBridge(t *testing.T, numKeys uint32) *fakeBridge {
+	factory := crypto.FactorySECP256K1R{}
+	b := &fakeBridge{keys: make(map[string]*crypto.PrivateKeySECP256K1R)}
+	for i := uint32(0); i < numKeys; i++ {
+		key, err := factory.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.keys[DerivationPath(i)] = key.(*crypto.PrivateKeySECP256K1R)
+	}
+	return b
+}
+
+func (b *fakeBridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/publicKey":
+		args := publicKeyArgs{}
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key, ok := b.keys[args.Path]
+		if !ok {
+			http.Error(w, "unknown path", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&publicKeyReply{
+			PublicKey: hex.EncodeToString(key.PublicKey().Bytes()),
+		})
+	case "/signHash":
+		args := signHashArgs{}
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if b.signWith != "" {
+			args.Path = b.signWith
+		}
+		hash, _ := hex.DecodeString(args.Hash)
+		sig, err := b.keys[args.Path].SignHash(hash)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&signHashReply{
+			Signature: hex.EncodeToString(sig),
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestLedgerSignHash(t *testing.T) {
+	assert := assert.New(t)
+
+	bridge := newFakeBridge(t, 3)
+	server := httptest.NewServer(bridge)
+	defer server.Close()
+
+	l, err := New(Config{
+		BridgeURL:      server.URL,
+		NumAddresses:   3,
+		RequestTimeout: DefaultRequestTimeout,
+	})
+	assert.NoError(err)
+	addrs := l.Addresses()
+	assert.Equal(3, addrs.Len())
+
+	addr := bridge.keys[DerivationPath(1)].PublicKey().Address()
+	assert.True(addrs.Contains(addr))
+
+	hash := hashing.ComputeHash256([]byte("unsigned tx"))
+	sig, err := l.SignHash(addr, hash)
+	assert.NoError(err)
+	assert.True(bridge.keys[DerivationPath(1)].PublicKey().VerifyHash(hash, sig))
+
+	_, err = l.SignHash(ids.GenerateTestShortID(), hash)
+	assert.Error(err)
+
+	// Signatures by the wrong key are rejected
+	bridge.signWith = DerivationPath(2)
+	_, err = l.SignHash(addr, hash)
+	assert.Equal(errWrongSignature, err)
+}
+
+func TestLedgerConfig(t *testing.T) {
+	_, err := New(Config{NumAddresses: 1, RequestTimeout: DefaultRequestTimeout})
+	assert.Equal(t, errNoBridge, err)
+
+	// The keys are read from the device
+	server := httptest.NewServer(newFakeBridge(t, 1))
+	defer server.Close()
+	_, err = New(Config{
+		BridgeURL:      server.URL,
+		NumAddresses:   2,
+		RequestTimeout: DefaultRequestTimeout,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+// vrfDomain separates the messages signed to compute VRF outputs from the
+// messages signed by the same key for other purposes, such as blocks
+var vrfDomain = []byte("avalanche vrf")
+
+var errUnsupportedVRFKey = errors.New("VRF requires an RSA key")
+
+// VRFProve computes the output of a verifiable random function for [input]
+// under the key of [signer], along with a proof that the output is correct.
+// Only the holder of the private key can compute the output, but anyone with
+// the public key can verify it with VRFVerify.
+//
+// The proof is a PKCS #1 v1.5 signature, which is deterministic, so there is
+// exactly one valid output for each key and input. Only RSA keys are supported.
+func VRFProve(signer crypto.Signer, input []byte) (hashing.Hash256, []byte, error) {
+	if _, ok := signer.Public().(*rsa.PublicKey); !ok {
+		return hashing.Hash256{}, nil, errUnsupportedVRFKey
+	}
+
+	// The randomness is only used for blinding, it doesn't change the signature
+	proof, err := signer.Sign(rand.Reader, vrfDigest(input), crypto.SHA256)
+	if err != nil {
+		return hashing.Hash256{}, nil, err
+	}
+	return hashing.ComputeHash256Array(proof), proof, nil
+}
+
+// VRFVerify verifies that [proof] was computed for [input] by the holder of
+// the private key of [publicKey], and returns the output of the VRF.
+func VRFVerify(publicKey crypto.PublicKey, input, proof []byte) (hashing.Hash256, error) {
+	key, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return hashing.Hash256{}, errUnsupportedVRFKey
+	}
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, vrfDigest(input), proof); err != nil {
+		return hashing.Hash256{}, err
+	}
+	return hashing.ComputeHash256Array(proof), nil
+}
+
+func vrfDigest(input []byte) []byte {
+	msg := make([]byte, 0, len(vrfDomain)+len(input))
+	msg = append(msg, vrfDomain...)
+	msg = append(msg, input...)
+	return hashing.ComputeHash256(msg)
+}
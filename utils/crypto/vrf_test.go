@@ -0,0 +1,58 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVRF(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+
+	input := []byte("input")
+	output, proof, err := VRFProve(key, input)
+	assert.NoError(err)
+
+	verifiedOutput, err := VRFVerify(&key.PublicKey, input, proof)
+	assert.NoError(err)
+	assert.Equal(output, verifiedOutput)
+
+	// There is only one output for each input
+	sameOutput, _, err := VRFProve(key, input)
+	assert.NoError(err)
+	assert.Equal(output, sameOutput)
+
+	otherOutput, _, err := VRFProve(key, []byte("other input"))
+	assert.NoError(err)
+	assert.NotEqual(output, otherOutput)
+
+	_, err = VRFVerify(&key.PublicKey, []byte("other input"), proof)
+	assert.Error(err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	_, err = VRFVerify(&otherKey.PublicKey, input, proof)
+	assert.Error(err)
+}
+
+func TestVRFUnsupportedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	pk, sk, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+
+	_, _, err = VRFProve(sk, []byte("input"))
+	assert.ErrorIs(err, errUnsupportedVRFKey)
+
+	_, err = VRFVerify(pk, []byte("input"), nil)
+	assert.ErrorIs(err, errUnsupportedVRFKey)
+}
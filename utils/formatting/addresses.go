@@ -13,6 +13,16 @@ import (
 
 const (
 	addressSep = "-"
+
+	// bech32Charset is the set of characters the data part of a bech32 address
+	// is encoded with
+	bech32Charset     = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32Sep         = '1'
+	bech32ChecksumLen = 6
+
+	// maxSuggestions is the maximum number of corrections suggested for an
+	// address with a bad checksum
+	maxSuggestions = 8
 )
 
 var (
@@ -21,6 +31,65 @@ var (
 	errBits8To5    = errors.New("unable to convert address from 8-bit to 5-bit formatting")
 )
 
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// HRPError is returned when an address has a different HRP than expected, such
+// as a Fuji address given to a mainnet node.
+type HRPError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *HRPError) Error() string {
+	return fmt.Sprintf("expected hrp %q but got %q", e.Expected, e.Actual)
+}
+
+// ChainError is returned when an address is for a different chain than
+// expected, such as a P-Chain address given to the X-Chain.
+type ChainError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("expected chainID to be %q but was %q", e.Expected, e.Actual)
+}
+
+// Correction is a single character substitution that would give an address a
+// valid checksum
+type Correction struct {
+	// Index of the character in the address
+	Index int
+	// Character currently at [Index]
+	Actual byte
+	// Character that would make the checksum valid
+	Suggested byte
+}
+
+func (c Correction) String() string {
+	return fmt.Sprintf("%q at position %d should maybe be %q", c.Actual, c.Index, c.Suggested)
+}
+
+// ChecksumError is returned when an address is well formed but its checksum
+// doesn't match, which usually means that a character was mistyped.
+// [Corrections] are the single character substitutions that would make the
+// checksum valid, if any.
+type ChecksumError struct {
+	Address     string
+	Corrections []Correction
+}
+
+func (e *ChecksumError) Error() string {
+	if len(e.Corrections) == 0 {
+		return fmt.Sprintf("invalid checksum in address %q", e.Address)
+	}
+	corrections := make([]string, len(e.Corrections))
+	for i, correction := range e.Corrections {
+		corrections[i] = correction.String()
+	}
+	return fmt.Sprintf("invalid checksum in address %q: %s", e.Address, strings.Join(corrections, ", or "))
+}
+
 // ParseAddress takes in an address string and splits returns the corresponding
 // parts. This returns the chain ID alias, bech32 HRP, address bytes, and an
 // error if it occurs.
@@ -33,9 +102,28 @@ func ParseAddress(addrStr string) (string, string, []byte, error) {
 	rawAddr := addressParts[1]
 
 	hrp, addr, err := ParseBech32(rawAddr)
+	if checksumErr, ok := err.(*ChecksumError); ok {
+		// Report the corrections relative to the full address
+		offset := len(chainID) + len(addressSep)
+		for i := range checksumErr.Corrections {
+			checksumErr.Corrections[i].Index += offset
+		}
+		checksumErr.Address = addrStr
+	}
 	return chainID, hrp, addr, err
 }
 
+// ParseAnyChain is like ParseAddress, but also accepts an address without a
+// chain ID alias, such as "avax1...". In that case the returned chain ID alias
+// is empty.
+func ParseAnyChain(addrStr string) (string, string, []byte, error) {
+	if strings.Contains(addrStr, addressSep) {
+		return ParseAddress(addrStr)
+	}
+	hrp, addr, err := ParseBech32(addrStr)
+	return "", hrp, addr, err
+}
+
 // FormatAddress takes in a chain prefix, HRP, and byte slice to produce a
 // string for an address.
 func FormatAddress(
@@ -55,6 +143,9 @@ func FormatAddress(
 func ParseBech32(addrStr string) (string, []byte, error) {
 	rawHRP, decoded, err := bech32.Decode(addrStr)
 	if err != nil {
+		if checksumErr := diagnoseChecksum(addrStr); checksumErr != nil {
+			return "", nil, checksumErr
+		}
 		return "", nil, err
 	}
 	addrBytes, err := bech32.ConvertBits(decoded, 5, 8, true)
@@ -72,3 +163,93 @@ func FormatBech32(hrp string, payload []byte) (string, error) {
 	}
 	return bech32.Encode(hrp, fiveBits)
 }
+
+// diagnoseChecksum returns a *ChecksumError if [addrStr] is a well formed bech32
+// string whose checksum doesn't match, or that only has one character that
+// isn't part of the bech32 charset. Otherwise nil is returned.
+func diagnoseChecksum(addrStr string) *ChecksumError {
+	lower := strings.ToLower(addrStr)
+	if addrStr != lower && addrStr != strings.ToUpper(addrStr) {
+		return nil
+	}
+	sepIndex := strings.LastIndexByte(lower, bech32Sep)
+	if sepIndex < 1 || sepIndex+bech32ChecksumLen+1 > len(lower) {
+		return nil
+	}
+	hrp := lower[:sepIndex]
+	chars := lower[sepIndex+1:]
+
+	data := make([]byte, len(chars))
+	invalidIndex := -1
+	for i := 0; i < len(chars); i++ {
+		value := strings.IndexByte(bech32Charset, chars[i])
+		if value == -1 {
+			if invalidIndex != -1 {
+				// Too many characters are wrong to suggest corrections
+				return nil
+			}
+			invalidIndex = i
+			continue
+		}
+		data[i] = byte(value)
+	}
+
+	positions := make([]int, 0, len(data))
+	if invalidIndex != -1 {
+		positions = append(positions, invalidIndex)
+	} else {
+		if bech32Polymod(hrp, data) == 1 {
+			// The checksum is valid, so the address is wrong in another way
+			return nil
+		}
+		for i := range data {
+			positions = append(positions, i)
+		}
+	}
+
+	err := &ChecksumError{Address: addrStr}
+	for _, i := range positions {
+		original := data[i]
+		for value := byte(0); value < byte(len(bech32Charset)); value++ {
+			if value == original && i != invalidIndex {
+				continue
+			}
+			data[i] = value
+			if bech32Polymod(hrp, data) == 1 && len(err.Corrections) < maxSuggestions {
+				err.Corrections = append(err.Corrections, Correction{
+					Index:     sepIndex + 1 + i,
+					Actual:    addrStr[sepIndex+1+i],
+					Suggested: bech32Charset[value],
+				})
+			}
+		}
+		data[i] = original
+	}
+	return err
+}
+
+// bech32Polymod computes the bech32 checksum polynomial of [hrp] and [data],
+// as specified in BIP 173. The checksum of a valid address evaluates to 1.
+func bech32Polymod(hrp string, data []byte) uint32 {
+	chk := uint32(1)
+	step := func(value byte) {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i, generator := range bech32Generator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator
+			}
+		}
+	}
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] >> 5)
+	}
+	step(0)
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] & 31)
+	}
+	for _, value := range data {
+		step(value)
+	}
+	return chk
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package formatting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validAddress = "X-local1g65uqn6t77p656w64023nh8nd9updzmxyymev2"
+
+func TestParseAddressChecksumError(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, expectedBytes, err := ParseAddress(validAddress)
+	assert.NoError(err)
+
+	// Mistype the character at index 10
+	mistyped := []byte(validAddress)
+	mistyped[10] = 'q'
+
+	_, _, _, err = ParseAddress(string(mistyped))
+	checksumErr, ok := err.(*ChecksumError)
+	if !assert.True(ok, "expected a *ChecksumError but got %v", err) {
+		return
+	}
+	assert.Equal(string(mistyped), checksumErr.Address)
+	assert.Contains(checksumErr.Corrections, Correction{
+		Index:     10,
+		Actual:    'q',
+		Suggested: validAddress[10],
+	})
+
+	// Applying any of the corrections gives a valid address
+	for _, correction := range checksumErr.Corrections {
+		corrected := []byte(string(mistyped))
+		corrected[correction.Index] = correction.Suggested
+		_, _, _, err := ParseAddress(string(corrected))
+		assert.NoError(err)
+	}
+
+	_, _, addrBytes, err := ParseAddress(validAddress)
+	assert.NoError(err)
+	assert.Equal(expectedBytes, addrBytes)
+}
+
+func TestParseAddressInvalidCharacter(t *testing.T) {
+	assert := assert.New(t)
+
+	// 'b' isn't part of the bech32 charset
+	mistyped := []byte(validAddress)
+	mistyped[12] = 'b'
+
+	_, _, _, err := ParseAddress(string(mistyped))
+	checksumErr, ok := err.(*ChecksumError)
+	if !assert.True(ok, "expected a *ChecksumError but got %v", err) {
+		return
+	}
+	assert.Equal([]Correction{{
+		Index:     12,
+		Actual:    'b',
+		Suggested: validAddress[12],
+	}}, checksumErr.Corrections)
+}
+
+func TestParseAnyChain(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID, hrp, addrBytes, err := ParseAnyChain(validAddress)
+	assert.NoError(err)
+	assert.Equal("X", chainID)
+	assert.Equal("local", hrp)
+
+	chainID, hrp, noChainBytes, err := ParseAnyChain(validAddress[2:])
+	assert.NoError(err)
+	assert.Empty(chainID)
+	assert.Equal("local", hrp)
+	assert.Equal(addrBytes, noChainBytes)
+}
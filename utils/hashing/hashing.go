@@ -4,11 +4,14 @@
 package hashing
 
 import (
-	"crypto/sha256"
 	"errors"
 	"io"
 
 	"golang.org/x/crypto/ripemd160"
+
+	// sha256-simd uses the SHA extensions (SHA-NI) or AVX2 when the CPU
+	// supports them and falls back to crypto/sha256 otherwise.
+	sha256 "github.com/minio/sha256-simd"
 )
 
 var errBadLength = errors.New("input has insufficient length")
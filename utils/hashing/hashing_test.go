@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	stdsha256 "crypto/sha256"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+var benchmarkSizes = []int{
+	32,
+	units.KiB,
+	64 * units.KiB,
+	units.MiB,
+}
+
+func TestComputeHash256MatchesStdlib(t *testing.T) {
+	for _, size := range benchmarkSizes {
+		buf := make([]byte, size)
+		_, _ = rand.Read(buf) // #nosec G404
+
+		assert.Equal(t, stdsha256.Sum256(buf), ComputeHash256Array(buf))
+	}
+}
+
+func TestComputeHash256Ranges(t *testing.T) {
+	buf := []byte{1, 2, 4, 8, 16}
+	assert.Equal(t,
+		ComputeHash256([]byte{2, 8, 16}),
+		ComputeHash256Ranges(buf, [][2]int{{1, 2}, {3, 5}}),
+	)
+}
+
+func BenchmarkComputeHash256(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		buf := make([]byte, size)
+		b.Run(fmt.Sprintf("%d bytes", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for n := 0; n < b.N; n++ {
+				ComputeHash256Array(buf)
+			}
+		})
+	}
+}
+
+func BenchmarkStdlibSHA256(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		buf := make([]byte, size)
+		b.Run(fmt.Sprintf("%d bytes", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for n := 0; n < b.N; n++ {
+				stdsha256.Sum256(buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"sync"
+
+	sha256 "github.com/minio/sha256-simd"
+)
+
+// SHA256 is the name of the SHA-256 hash, which is always registered.
+const SHA256 = "sha256"
+
+var (
+	errEmptyHashName = errors.New("hash name can't be empty")
+	errNilHash       = errors.New("hash constructor can't be nil")
+	errDuplicateHash = errors.New("hash is already registered")
+	errUnknownHash   = errors.New("unknown hash")
+
+	_ Registry = &registry{}
+
+	defaultRegistry = NewRegistry()
+)
+
+// Registry maps names to hash functions. This allows VMs to use digests
+// other than SHA-256 (e.g. blake3) by name.
+type Registry interface {
+	// Register makes the hash created by [newHash] available under [name].
+	// Returns an error if [name] is already registered.
+	Register(name string, newHash func() hash.Hash) error
+
+	// Get returns the constructor of the hash registered under [name].
+	Get(name string) (func() hash.Hash, error)
+
+	// Compute returns the digest of [buf] using the hash registered under
+	// [name].
+	Compute(name string, buf []byte) ([]byte, error)
+}
+
+type registry struct {
+	lock    sync.RWMutex
+	hashers map[string]func() hash.Hash
+}
+
+// NewRegistry returns a new Registry that only contains SHA-256.
+func NewRegistry() Registry {
+	return &registry{
+		hashers: map[string]func() hash.Hash{
+			SHA256: sha256.New,
+		},
+	}
+}
+
+func (r *registry) Register(name string, newHash func() hash.Hash) error {
+	switch {
+	case name == "":
+		return errEmptyHashName
+	case newHash == nil:
+		return errNilHash
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, exists := r.hashers[name]; exists {
+		return fmt.Errorf("%w: %q", errDuplicateHash, name)
+	}
+	r.hashers[name] = newHash
+	return nil
+}
+
+func (r *registry) Get(name string) (func() hash.Hash, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	newHash, exists := r.hashers[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", errUnknownHash, name)
+	}
+	return newHash, nil
+}
+
+func (r *registry) Compute(name string, buf []byte) ([]byte, error) {
+	newHash, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	h := newHash()
+	if _, err := h.Write(buf); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Register makes the hash created by [newHash] available under [name] in the
+// default registry.
+func Register(name string, newHash func() hash.Hash) error {
+	return defaultRegistry.Register(name, newHash)
+}
+
+// Get returns the constructor of the hash registered under [name] in the
+// default registry.
+func Get(name string) (func() hash.Hash, error) {
+	return defaultRegistry.Get(name)
+}
+
+// Compute returns the digest of [buf] using the hash registered under [name]
+// in the default registry.
+func Compute(name string, buf []byte) ([]byte, error) {
+	return defaultRegistry.Compute(name, buf)
+}
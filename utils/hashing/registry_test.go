@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryDefaultSHA256(t *testing.T) {
+	r := NewRegistry()
+
+	buf := []byte("avalanche")
+	digest, err := r.Compute(SHA256, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, ComputeHash256(buf), digest)
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+
+	_, err := r.Get("sha512")
+	assert.ErrorIs(t, err, errUnknownHash)
+
+	err = r.Register("sha512", sha512.New)
+	assert.NoError(t, err)
+
+	buf := []byte("avalanche")
+	digest, err := r.Compute("sha512", buf)
+	assert.NoError(t, err)
+	expected := sha512.Sum512(buf)
+	assert.Equal(t, expected[:], digest)
+
+	err = r.Register("sha512", sha512.New)
+	assert.ErrorIs(t, err, errDuplicateHash)
+
+	err = r.Register(SHA256, sha512.New)
+	assert.ErrorIs(t, err, errDuplicateHash)
+}
+
+func TestRegistryRegisterInvalid(t *testing.T) {
+	r := NewRegistry()
+
+	err := r.Register("", sha512.New)
+	assert.ErrorIs(t, err, errEmptyHashName)
+
+	err = r.Register("sha512", nil)
+	assert.ErrorIs(t, err, errNilHash)
+}
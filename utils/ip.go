@@ -70,6 +70,12 @@ func (ipDesc IPDesc) IsPrivate() bool {
 	return false
 }
 
+// IsIPv4 returns true if the IP in this descriptor is an IPv4 address or an
+// IPv4-mapped IPv6 address
+func (ipDesc IPDesc) IsIPv4() bool {
+	return ipDesc.IP.To4() != nil
+}
+
 // IsZero returns if the IP or port is zeroed out
 func (ipDesc IPDesc) IsZero() bool {
 	ip := ipDesc.IP
@@ -105,6 +105,26 @@ func TestIPDescString(t *testing.T) {
 	}
 }
 
+func TestIPDescIsIPv4(t *testing.T) {
+	tests := []struct {
+		ipDesc IPDesc
+		result bool
+	}{
+		{IPDesc{net.ParseIP("127.0.0.1"), 0}, true},
+		{IPDesc{net.ParseIP("::ffff:127.0.0.1"), 0}, true},
+		{IPDesc{net.ParseIP("::1"), 0}, false},
+		{IPDesc{net.ParseIP("2001:db8::1"), 0}, false},
+		{IPDesc{net.IP{}, 0}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ipDesc.String(), func(t *testing.T) {
+			if result := tt.ipDesc.IsIPv4(); result != tt.result {
+				t.Errorf("Expected %t, got %t", tt.result, result)
+			}
+		})
+	}
+}
+
 func TestToIPDescError(t *testing.T) {
 	tests := []struct {
 		in  string
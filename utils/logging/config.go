@@ -30,9 +30,28 @@ type Config struct {
 	LogLevel                    Level         `json:"logLevel"`
 	DisplayLevel                Level         `json:"displayLevel"`
 	DisplayHighlight            Highlight     `json:"displayHighlight"`
-	Directory                   string        `json:"-"`
-	MsgPrefix                   string        `json:"-"`
-	LoggerName                  string        `json:"-"`
+	LogFormat                   Format        `json:"logFormat"`
+	// MaxAge is how long rotated log files are kept for. If 0, they are kept
+	// until they are rotated out.
+	MaxAge time.Duration `json:"maxAge"`
+	// Compress rotated log files with gzip
+	Compress   bool   `json:"compress"`
+	Directory  string `json:"-"`
+	MsgPrefix  string `json:"-"`
+	LoggerName string `json:"-"`
+	// ChainID and Module are reported in the JSON log format
+	ChainID string `json:"-"`
+	Module  string `json:"-"`
+	// If non-nil, notified when a logger recovers from a panic
+	PanicHandler PanicHandler `json:"-"`
+}
+
+// PanicHandler is notified of the panics that loggers recover from
+type PanicHandler interface {
+	// HandlePanic is called after the logger recovered from a panic with
+	// [panicValue] and was stopped, before the panic is re-raised or the
+	// process exits.
+	HandlePanic(panicValue interface{}, stack string)
 }
 
 // DefaultConfig returns a logger configuration with default parameters
@@ -31,6 +31,18 @@ type Factory interface {
 	// GetDisplayLevels returns all log display levels in factory as name, level pairs
 	GetDisplayLevel(name string) (Level, error)
 
+	// SetModuleLevels overrides the levels that [module] logs with in the
+	// logger with name [name]
+	SetModuleLevels(name string, module string, levels ModuleLevels) error
+
+	// ResetModuleLevels makes [module] log with the levels of the logger with
+	// name [name] again
+	ResetModuleLevels(name string, module string) error
+
+	// GetModuleLevels returns the overridden module levels of the logger with
+	// name [name]
+	GetModuleLevels(name string) (map[string]ModuleLevels, error)
+
 	// GetLoggerNames returns the names of all logs created by this factory
 	GetLoggerNames() []string
 
@@ -77,6 +89,7 @@ func (f *factory) Make(name string) (Logger, error) {
 
 	config := f.config
 	config.LoggerName = name
+	config.Module = name
 	return f.makeLogger(config)
 }
 
@@ -88,6 +101,7 @@ func (f *factory) MakeChain(chainID string) (Logger, error) {
 	config := f.config
 	config.MsgPrefix = chainID + " Chain"
 	config.LoggerName = chainID
+	config.ChainID = chainID
 	return f.makeLogger(config)
 }
 
@@ -99,6 +113,8 @@ func (f *factory) MakeChainChild(chainID string, name string) (Logger, error) {
 	config := f.config
 	config.MsgPrefix = chainID + " Chain"
 	config.LoggerName = chainID + "." + name
+	config.ChainID = chainID
+	config.Module = name
 	return f.makeLogger(config)
 }
 
@@ -152,6 +168,56 @@ func (f *factory) GetDisplayLevel(name string) (Level, error) {
 	return logger.GetDisplayLevel(), nil
 }
 
+// Assumes [f.lock] is held
+func (f *factory) getLog(name string) (*Log, error) {
+	logger, ok := f.loggers[name]
+	if !ok {
+		return nil, fmt.Errorf("logger with name %q not found", name)
+	}
+	log, ok := logger.(*Log)
+	if !ok {
+		return nil, fmt.Errorf("logger with name %q doesn't support module levels", name)
+	}
+	return log, nil
+}
+
+// SetModuleLevels implements the Factory interface
+func (f *factory) SetModuleLevels(name string, module string, levels ModuleLevels) error {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	log, err := f.getLog(name)
+	if err != nil {
+		return err
+	}
+	return log.SetModuleLevels(module, levels)
+}
+
+// ResetModuleLevels implements the Factory interface
+func (f *factory) ResetModuleLevels(name string, module string) error {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	log, err := f.getLog(name)
+	if err != nil {
+		return err
+	}
+	log.ResetModuleLevels(module)
+	return nil
+}
+
+// GetModuleLevels implements the Factory interface
+func (f *factory) GetModuleLevels(name string) (map[string]ModuleLevels, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	log, err := f.getLog(name)
+	if err != nil {
+		return nil, err
+	}
+	return log.GetModuleLevels(), nil
+}
+
 // GetLoggerNames implements the Factory interface
 func (f *factory) GetLoggerNames() []string {
 	f.lock.RLock()
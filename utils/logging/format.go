@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats available for logged events
+const (
+	Text Format = iota
+	JSON
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// Format of the logged events
+type Format int
+
+// ToFormat chooses a log format
+func ToFormat(f string) (Format, error) {
+	switch strings.ToUpper(f) {
+	case "TEXT":
+		return Text, nil
+	case "JSON":
+		return JSON, nil
+	default:
+		return Text, fmt.Errorf("unknown log format: %s", f)
+	}
+}
+
+func (f *Format) MarshalJSON() ([]byte, error) {
+	switch *f {
+	case Text:
+		return []byte("\"TEXT\""), nil
+	case JSON:
+		return []byte("\"JSON\""), nil
+	default:
+		return nil, errUnknownFormat
+	}
+}
+
+// Field is a structured field of a logged event. Fields can be passed to a
+// Logger along with the arguments of the format string. They aren't used to
+// format the message, and are appended to it as key=value pairs in the text
+// format, or reported in the "fields" object in the JSON format.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a field with key [key] and value [value]
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// splitFields separates the fields in [args] from the arguments of the format
+// string
+func splitFields(args []interface{}) ([]interface{}, []Field) {
+	hasFields := false
+	for _, arg := range args {
+		if _, ok := arg.(Field); ok {
+			hasFields = true
+			break
+		}
+	}
+	if !hasFields {
+		return args, nil
+	}
+
+	var fields []Field
+	formatArgs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if field, ok := arg.(Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+		formatArgs = append(formatArgs, arg)
+	}
+	return formatArgs, fields
+}
+
+// jsonEntry is a logged event in the JSON format
+type jsonEntry struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Level     string                 `json:"level"`
+	ChainID   string                 `json:"chainID,omitempty"`
+	Module    string                 `json:"module,omitempty"`
+	Caller    string                 `json:"caller"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+func formatJSON(entry jsonEntry) string {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		// A field couldn't be marshalled, so report the fields as strings
+		for key, value := range entry.Fields {
+			entry.Fields[key] = fmt.Sprint(value)
+		}
+		b, _ = json.Marshal(entry)
+	}
+	return string(b) + "\n"
+}
+
+func formatTextFields(fields []Field) string {
+	sb := strings.Builder{}
+	for _, field := range fields {
+		sb.WriteString(fmt.Sprintf(" %s=%v", field.Key, field.Value))
+	}
+	return sb.String()
+}
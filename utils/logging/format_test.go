@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	l := &Log{config: Config{
+		LogFormat: JSON,
+		ChainID:   "X",
+		Module:    "snowman",
+	}}
+	output := l.format(Info, []Field{F("height", 5)}, "accepted %s", "block")
+	assert.True(strings.HasSuffix(output, "\n"))
+
+	entry := map[string]interface{}{}
+	assert.NoError(json.Unmarshal([]byte(output), &entry))
+	assert.Equal("INFO", entry["level"])
+	assert.Equal("X", entry["chainID"])
+	assert.Equal("snowman", entry["module"])
+	assert.Equal("accepted block", entry["message"])
+	assert.Equal(map[string]interface{}{"height": float64(5)}, entry["fields"])
+	assert.Contains(entry, "timestamp")
+	assert.Contains(entry, "caller")
+}
+
+func TestFormatTextFields(t *testing.T) {
+	assert := assert.New(t)
+
+	args, fields := splitFields([]interface{}{"block", F("height", 5)})
+	assert.Equal([]interface{}{"block"}, args)
+
+	l := &Log{}
+	output := l.format(Info, fields, "accepted %s", args...)
+	assert.True(strings.HasSuffix(output, "accepted block height=5\n"), output)
+}
+
+func TestToFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	format, err := ToFormat("json")
+	assert.NoError(err)
+	assert.Equal(JSON, format)
+
+	_, err = ToFormat("yaml")
+	assert.Error(err)
+}
+
+func TestRotateCompressAndExpire(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	fw := &fileWriter{}
+	_, err := fw.Initialize(Config{
+		Directory:    dir,
+		LoggerName:   "test",
+		RotationSize: 2,
+		Compress:     true,
+		MaxAge:       time.Hour,
+	})
+	assert.NoError(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = fw.WriteString("message\n")
+		assert.NoError(err)
+		assert.NoError(fw.Flush())
+		assert.NoError(fw.Close())
+		assert.NoError(fw.Rotate())
+	}
+	assert.NoError(fw.Close())
+
+	assert.FileExists(filepath.Join(dir, "test.log.1.gz"))
+	assert.FileExists(filepath.Join(dir, "test.log.2.gz"))
+	assert.NoFileExists(filepath.Join(dir, "test.log.1"))
+	assert.NoFileExists(filepath.Join(dir, "test.log.3.gz"))
+
+	// Rotated logs older than MaxAge are removed on the next rotation
+	old := time.Now().Add(-2 * time.Hour)
+	assert.NoError(os.Chtimes(filepath.Join(dir, "test.log.1.gz"), old, old))
+	_, err = fw.Initialize(fw.config)
+	assert.NoError(err)
+	assert.NoError(fw.Close())
+	assert.NoError(fw.Rotate())
+	assert.NoError(fw.Close())
+	assert.NoFileExists(filepath.Join(dir, "test.log.2.gz"))
+	assert.FileExists(filepath.Join(dir, "test.log.1.gz"))
+}
@@ -5,8 +5,10 @@ package logging
 
 import (
 	"bufio"
+	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -17,6 +19,9 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/perms"
 )
 
+// compressedSuffix is appended to the names of compressed rotated log files
+const compressedSuffix = ".gz"
+
 var (
 	fileSuffix = "utils/logging/log.go"
 	filePrefix string
@@ -47,6 +52,10 @@ type Log struct {
 	closed bool
 
 	writer RotatingWriter
+
+	// moduleLevels overrides the levels of the modules that log with this
+	// logger. Protected by [configLock].
+	moduleLevels map[string]ModuleLevels
 }
 
 // New returns a new logger set up according to [config]
@@ -161,16 +170,28 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	l.configLock.Lock()
 	defer l.configLock.Unlock()
 
-	shouldLog := !l.config.DisableLogging && level <= l.config.LogLevel
-	shouldDisplay := (!l.config.DisableDisplaying && level <= l.config.DisplayLevel) || level == Fatal
+	logLevel, displayLevel := l.config.LogLevel, l.config.DisplayLevel
+	if len(l.moduleLevels) > 0 {
+		// Skip this function and the [Level] function that called it
+		moduleLevels := l.moduleLevels[callerModule(2)]
+		if moduleLevels.LogLevel != nil {
+			logLevel = *moduleLevels.LogLevel
+		}
+		if moduleLevels.DisplayLevel != nil {
+			displayLevel = *moduleLevels.DisplayLevel
+		}
+	}
+
+	shouldLog := !l.config.DisableLogging && level <= logLevel
+	shouldDisplay := (!l.config.DisableDisplaying && level <= displayLevel) || level == Fatal
 
 	if !shouldLog && !shouldDisplay {
 		return
 	}
 
-	args = SanitizeArgs(args)
+	args, fields := splitFields(SanitizeArgs(args))
 
-	output := l.format(level, format, args...)
+	output := l.format(level, fields, format, args...)
 
 	if shouldLog {
 		l.flushLock.Lock()
@@ -183,8 +204,8 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	if shouldDisplay {
 		switch {
 		case l.config.DisableContextualDisplaying:
-			fmt.Println(fmt.Sprintf(format, args...))
-		case l.config.DisplayHighlight == Plain:
+			fmt.Println(fmt.Sprintf(format, args...) + formatTextFields(fields))
+		case l.config.DisplayHighlight == Plain || l.config.LogFormat == JSON:
 			fmt.Print(output)
 		default:
 			fmt.Print(level.Color().Wrap(output))
@@ -192,13 +213,33 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	}
 }
 
-func (l *Log) format(level Level, format string, args ...interface{}) string {
+func (l *Log) format(level Level, fields []Field, format string, args ...interface{}) string {
 	loc := "?"
 	if _, file, no, ok := runtime.Caller(3); ok {
 		localFile := strings.TrimPrefix(file, filePrefix)
 		loc = fmt.Sprintf("%s#%d", localFile, no)
 	}
-	text := fmt.Sprintf("%s: %s", loc, fmt.Sprintf(format, args...))
+	msg := fmt.Sprintf(format, args...)
+
+	if l.config.LogFormat == JSON {
+		entry := jsonEntry{
+			Timestamp: time.Now().UTC(),
+			Level:     level.String(),
+			ChainID:   l.config.ChainID,
+			Module:    l.config.Module,
+			Caller:    loc,
+			Message:   msg,
+		}
+		if len(fields) > 0 {
+			entry.Fields = make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				entry.Fields[field.Key] = field.Value
+			}
+		}
+		return formatJSON(entry)
+	}
+
+	text := fmt.Sprintf("%s: %s%s", loc, msg, formatTextFields(fields))
 
 	prefix := ""
 	if l.config.MsgPrefix != "" {
@@ -283,8 +324,7 @@ func (l *Log) AssertDeferredNoError(f func() error) {
 // StopOnPanic implements the Logger interface
 func (l *Log) StopOnPanic() {
 	if r := recover(); r != nil {
-		l.Fatal("Panicking due to:\n%s\nFrom:\n%s", r, Stacktrace{})
-		l.Stop()
+		l.stopOnPanic(r)
 		panic(r)
 	}
 }
@@ -294,12 +334,22 @@ func (l *Log) RecoverAndPanic(f func()) { defer l.StopOnPanic(); f() }
 
 func (l *Log) stopAndExit(exit func()) {
 	if r := recover(); r != nil {
-		l.Fatal("Panicking due to:\n%s\nFrom:\n%s", r, Stacktrace{})
-		l.Stop()
+		l.stopOnPanic(r)
 		exit()
 	}
 }
 
+// stopOnPanic logs the panic [r], stops the logger and notifies the panic
+// handler, if there is one.
+func (l *Log) stopOnPanic(r interface{}) {
+	stack := Stacktrace{}.String()
+	l.Fatal("Panicking due to:\n%s\nFrom:\n%s", r, stack)
+	l.Stop()
+	if l.config.PanicHandler != nil {
+		l.config.PanicHandler.HandlePanic(r, stack)
+	}
+}
+
 // RecoverAndExit implements the Logger interface
 func (l *Log) RecoverAndExit(f, exit func()) { defer l.stopAndExit(exit); f() }
 
@@ -396,20 +446,39 @@ func (fw *fileWriter) Close() error {
 
 // Rotate implements the RotatingWriter interface
 func (fw *fileWriter) Rotate() error {
+	// Make room for the log file that is rotated out of the last slot
+	for _, compressed := range []bool{false, true} {
+		filename := fw.rotatedFilename(fw.config.RotationSize, compressed)
+		if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
 	for i := fw.config.RotationSize - 1; i > 0; i-- {
-		sourceFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.%d", fw.config.LoggerName, i))
-		destFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.%d", fw.config.LoggerName, i+1))
-		if _, err := os.Stat(sourceFilename); !errors.Is(err, os.ErrNotExist) {
-			if err := os.Rename(sourceFilename, destFilename); err != nil {
-				return err
+		for _, compressed := range []bool{false, true} {
+			sourceFilename := fw.rotatedFilename(i, compressed)
+			destFilename := fw.rotatedFilename(i+1, compressed)
+			if _, err := os.Stat(sourceFilename); !errors.Is(err, os.ErrNotExist) {
+				if err := os.Rename(sourceFilename, destFilename); err != nil {
+					return err
+				}
 			}
 		}
 	}
 	sourceFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log", fw.config.LoggerName))
-	destFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.1", fw.config.LoggerName))
+	destFilename := fw.rotatedFilename(1, false)
 	if err := os.Rename(sourceFilename, destFilename); err != nil {
 		return err
 	}
+	if fw.config.Compress {
+		if err := compressFile(destFilename); err != nil {
+			return err
+		}
+	}
+	if fw.config.MaxAge > 0 {
+		if err := fw.removeExpired(); err != nil {
+			return err
+		}
+	}
 	writer, file, err := fw.create()
 	if err != nil {
 		return err
@@ -419,6 +488,70 @@ func (fw *fileWriter) Rotate() error {
 	return nil
 }
 
+// rotatedFilename returns the name of the [i]th most recently rotated log file
+func (fw *fileWriter) rotatedFilename(i int, compressed bool) string {
+	filename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.%d", fw.config.LoggerName, i))
+	if compressed {
+		filename += compressedSuffix
+	}
+	return filename
+}
+
+// removeExpired removes the rotated log files that were last written to more
+// than MaxAge ago
+func (fw *fileWriter) removeExpired() error {
+	expiry := time.Now().Add(-fw.config.MaxAge)
+	for i := 1; i <= fw.config.RotationSize; i++ {
+		for _, compressed := range []bool{false, true} {
+			filename := fw.rotatedFilename(i, compressed)
+			info, err := os.Stat(filename)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if info.ModTime().Before(expiry) {
+				if err := os.Remove(filename); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile replaces [filename] with a gzipped copy of it
+func compressFile(filename string) error {
+	source, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	dest, err := os.OpenFile(filename+compressedSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(dest)
+	if _, err := io.Copy(writer, source); err != nil {
+		_ = dest.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		_ = dest.Close()
+		return err
+	}
+	if err := dest.Close(); err != nil {
+		return err
+	}
+	// Keep the modification time, so that the age of the log is preserved
+	if info, err := source.Stat(); err == nil {
+		_ = os.Chtimes(filename+compressedSuffix, info.ModTime(), info.ModTime())
+	}
+	return os.Remove(filename)
+}
+
 // Creates a file if it does not exist or opens it in append mode if it does
 func (fw *fileWriter) create() (*bufio.Writer, *os.File, error) {
 	filename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log", fw.config.LoggerName))
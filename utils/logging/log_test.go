@@ -29,3 +29,36 @@ func TestLog(t *testing.T) {
 		t.Fatalf("Exit function was never called")
 	}
 }
+
+type testPanicHandler struct {
+	panicValue interface{}
+	stack      string
+}
+
+func (h *testPanicHandler) HandlePanic(panicValue interface{}, stack string) {
+	h.panicValue = panicValue
+	h.stack = stack
+}
+
+func TestLogPanicHandler(t *testing.T) {
+	config, err := DefaultConfig()
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	handler := &testPanicHandler{}
+	config.PanicHandler = handler
+
+	log, err := NewTestLog(config)
+	if err != nil {
+		t.Fatalf("Error creating log: %s", err)
+	}
+
+	log.RecoverAndExit(func() { panic("DON'T PANIC!") }, func() {})
+
+	if handler.panicValue != "DON'T PANIC!" {
+		t.Fatalf("Panic handler was called with %v", handler.panicValue)
+	}
+	if handler.stack == "" {
+		t.Fatalf("Panic handler was called without a stack trace")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Modules of the node whose log levels can be set separately from the level of
+// the logger they log with. The module of a logged event is the module of the
+// source file that logged it.
+const (
+	NetworkModule   = "network"
+	RouterModule    = "router"
+	ConsensusModule = "consensus"
+	VMModule        = "vm"
+)
+
+var modules = moduleRegistry{
+	dirs: map[string]string{
+		"network/":         NetworkModule,
+		"snow/networking/": RouterModule,
+		"snow/consensus/":  ConsensusModule,
+		"snow/engine/":     ConsensusModule,
+		"vms/":             VMModule,
+	},
+}
+
+// moduleRegistry maps source directories, relative to the root of the
+// repository, to the module they belong to
+type moduleRegistry struct {
+	lock sync.RWMutex
+	dirs map[string]string
+}
+
+// RegisterModule adds the source directories [dirs], relative to the root of
+// the repository, to the module [name]
+func RegisterModule(name string, dirs ...string) {
+	modules.lock.Lock()
+	defer modules.lock.Unlock()
+
+	for _, dir := range dirs {
+		modules.dirs[dir] = name
+	}
+}
+
+// Modules returns the names of the registered modules
+func Modules() []string {
+	modules.lock.RLock()
+	defer modules.lock.RUnlock()
+
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range modules.dirs {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isModule(name string) bool {
+	for _, module := range Modules() {
+		if module == name {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleOf returns the module that [file] belongs to, or "" if it doesn't
+// belong to a module. The directory with the longest match takes precedence.
+func moduleOf(file string) string {
+	modules.lock.RLock()
+	defer modules.lock.RUnlock()
+
+	localFile := strings.TrimPrefix(file, filePrefix)
+	module, matchLen := "", 0
+	for dir, name := range modules.dirs {
+		if len(dir) > matchLen && strings.HasPrefix(localFile, dir) {
+			module, matchLen = name, len(dir)
+		}
+	}
+	return module
+}
+
+// callerModule returns the module of the caller [skip] frames above the
+// caller of callerModule
+func callerModule(skip int) string {
+	_, file, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	return moduleOf(file)
+}
+
+// ModuleLevels are the levels a module logs with, overriding the levels of the
+// logger. A nil level isn't overridden.
+type ModuleLevels struct {
+	LogLevel     *Level `json:"logLevel,omitempty"`
+	DisplayLevel *Level `json:"displayLevel,omitempty"`
+}
+
+// SetModuleLevels overrides the levels that [module] logs with. Only the
+// non-nil levels of [levels] are overridden.
+func (l *Log) SetModuleLevels(module string, levels ModuleLevels) error {
+	if !isModule(module) {
+		return fmt.Errorf("unknown module %q", module)
+	}
+
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	if l.moduleLevels == nil {
+		l.moduleLevels = make(map[string]ModuleLevels)
+	}
+	current := l.moduleLevels[module]
+	if levels.LogLevel != nil {
+		current.LogLevel = levels.LogLevel
+	}
+	if levels.DisplayLevel != nil {
+		current.DisplayLevel = levels.DisplayLevel
+	}
+	l.moduleLevels[module] = current
+	return nil
+}
+
+// ResetModuleLevels makes [module] log with the levels of the logger again
+func (l *Log) ResetModuleLevels(module string) {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	delete(l.moduleLevels, module)
+}
+
+// GetModuleLevels returns the overridden levels of the modules
+func (l *Log) GetModuleLevels() map[string]ModuleLevels {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	levels := make(map[string]ModuleLevels, len(l.moduleLevels))
+	for module, moduleLevels := range l.moduleLevels {
+		levels[module] = moduleLevels
+	}
+	return levels
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newModuleTestLog() *Log {
+	l := &Log{config: Config{
+		LogLevel:          Info,
+		DisableDisplaying: true,
+	}}
+	l.needsFlush = sync.NewCond(&l.flushLock)
+	return l
+}
+
+func TestModuleLevels(t *testing.T) {
+	assert := assert.New(t)
+
+	// Events logged by this file are logged by the consensus module
+	RegisterModule(ConsensusModule, "utils/logging/module_test.go")
+	defer func() {
+		modules.lock.Lock()
+		delete(modules.dirs, "utils/logging/module_test.go")
+		modules.lock.Unlock()
+	}()
+
+	l := newModuleTestLog()
+	l.Debug("filtered")
+	assert.Empty(l.messages)
+
+	debug := Debug
+	assert.NoError(l.SetModuleLevels(ConsensusModule, ModuleLevels{LogLevel: &debug}))
+	l.Debug("logged")
+	assert.Len(l.messages, 1)
+
+	// Other modules still log with the level of the logger
+	assert.NoError(l.SetModuleLevels(NetworkModule, ModuleLevels{LogLevel: &debug}))
+	l.ResetModuleLevels(ConsensusModule)
+	l.Debug("filtered")
+	assert.Len(l.messages, 1)
+	assert.Equal(map[string]ModuleLevels{NetworkModule: {LogLevel: &debug}}, l.GetModuleLevels())
+
+	assert.Error(l.SetModuleLevels("unknown", ModuleLevels{LogLevel: &debug}))
+}
+
+func TestModuleOf(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(RouterModule, moduleOf(filePrefix+"snow/networking/router/chain_router.go"))
+	assert.Equal(NetworkModule, moduleOf(filePrefix+"network/network.go"))
+	assert.Equal(ConsensusModule, moduleOf(filePrefix+"snow/engine/snowman/transitive.go"))
+	assert.Equal(VMModule, moduleOf(filePrefix+"vms/platformvm/vm.go"))
+	assert.Equal("", moduleOf(filePrefix+"node/node.go"))
+}
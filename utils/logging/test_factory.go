@@ -21,4 +21,10 @@ func (NoFactory) GetLogLevel(name string) (Level, error) { return Off, nil }
 
 func (NoFactory) GetDisplayLevel(name string) (Level, error) { return Off, nil }
 
+func (NoFactory) SetModuleLevels(string, string, ModuleLevels) error { return nil }
+
+func (NoFactory) ResetModuleLevels(string, string) error { return nil }
+
+func (NoFactory) GetModuleLevels(string) (map[string]ModuleLevels, error) { return nil, nil }
+
 func (NoFactory) GetLoggerNames() []string { return nil }
@@ -5,13 +5,47 @@ package math
 
 import (
 	"errors"
-	"math"
+	"unsafe"
 )
 
 var errOverflow = errors.New("overflow occurred")
 
-// Max64 returns the maximum of the values provided
-func Max64(max uint64, nums ...uint64) uint64 {
+// Int is the set of integer types the arithmetic helpers of this package are
+// defined for
+type Int interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// MaxValue returns the largest value of type T
+func MaxValue[T Int]() T {
+	var zero T
+	if !isSigned[T]() {
+		return ^zero
+	}
+	return T(1)<<(bitSize[T]()-1) - 1
+}
+
+// MinValue returns the smallest value of type T
+func MinValue[T Int]() T {
+	if !isSigned[T]() {
+		return 0
+	}
+	return -MaxValue[T]() - 1
+}
+
+func isSigned[T Int]() bool {
+	var zero T
+	return zero-1 < zero
+}
+
+func bitSize[T Int]() uint {
+	var zero T
+	return uint(unsafe.Sizeof(zero)) * 8
+}
+
+// Max returns the maximum of the values provided
+func Max[T Int](max T, nums ...T) T {
 	for _, num := range nums {
 		if num > max {
 			max = num
@@ -20,8 +54,8 @@ func Max64(max uint64, nums ...uint64) uint64 {
 	return max
 }
 
-// Min64 returns the minimum of the values provided
-func Min64(min uint64, nums ...uint64) uint64 {
+// Min returns the minimum of the values provided
+func Min[T Int](min T, nums ...T) T {
 	for _, num := range nums {
 		if num < min {
 			min = num
@@ -30,36 +64,119 @@ func Min64(min uint64, nums ...uint64) uint64 {
 	return min
 }
 
-// Add64 returns:
+// Add returns:
 // 1) a + b
 // 2) If there is overflow, an error
-func Add64(a, b uint64) (uint64, error) {
-	if a > math.MaxUint64-b {
+func Add[T Int](a, b T) (T, error) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
 		return 0, errOverflow
 	}
-	return a + b, nil
+	return c, nil
 }
 
-// Sub64 returns:
+// Sub returns:
 // 1) a - b
 // 2) If there is underflow, an error
-func Sub64(a, b uint64) (uint64, error) {
-	if a < b {
+func Sub[T Int](a, b T) (T, error) {
+	c := a - b
+	if (b > 0 && c > a) || (b < 0 && c < a) {
 		return 0, errOverflow
 	}
-	return a - b, nil
+	return c, nil
 }
 
-// Mul64 returns:
+// Mul returns:
 // 1) a * b
 // 2) If there is overflow, an error
-func Mul64(a, b uint64) (uint64, error) {
-	if b != 0 && a > math.MaxUint64/b {
+func Mul[T Int](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	// MinValue / -1 overflows back to MinValue, so it must be checked
+	// separately
+	var zero T
+	if c/b != a || (isSigned[T]() && b == zero-1 && a == MinValue[T]()) {
 		return 0, errOverflow
 	}
-	return a * b, nil
+	return c, nil
 }
 
-func Diff64(a, b uint64) uint64 {
-	return Max64(a, b) - Min64(a, b)
+// SaturatingAdd returns a + b, clamped to the range of T
+func SaturatingAdd[T Int](a, b T) T {
+	c, err := Add(a, b)
+	switch {
+	case err == nil:
+		return c
+	case b > 0:
+		return MaxValue[T]()
+	default:
+		return MinValue[T]()
+	}
+}
+
+// SaturatingSub returns a - b, clamped to the range of T
+func SaturatingSub[T Int](a, b T) T {
+	c, err := Sub(a, b)
+	switch {
+	case err == nil:
+		return c
+	case b > 0:
+		return MinValue[T]()
+	default:
+		return MaxValue[T]()
+	}
 }
+
+// SaturatingMul returns a * b, clamped to the range of T
+func SaturatingMul[T Int](a, b T) T {
+	c, err := Mul(a, b)
+	switch {
+	case err == nil:
+		return c
+	case (a < 0) != (b < 0):
+		return MinValue[T]()
+	default:
+		return MaxValue[T]()
+	}
+}
+
+// WrappingAdd returns a + b, wrapping around the range of T on overflow. It
+// should be used where wrapping is intended, to tell it apart from unchecked
+// arithmetic.
+func WrappingAdd[T Int](a, b T) T { return a + b }
+
+// WrappingSub returns a - b, wrapping around the range of T on underflow
+func WrappingSub[T Int](a, b T) T { return a - b }
+
+// WrappingMul returns a * b, wrapping around the range of T on overflow
+func WrappingMul[T Int](a, b T) T { return a * b }
+
+// Diff returns the absolute difference between a and b
+func Diff[T Int](a, b T) T {
+	return Max(a, b) - Min(a, b)
+}
+
+// Max64 returns the maximum of the values provided
+func Max64(max uint64, nums ...uint64) uint64 { return Max(max, nums...) }
+
+// Min64 returns the minimum of the values provided
+func Min64(min uint64, nums ...uint64) uint64 { return Min(min, nums...) }
+
+// Add64 returns:
+// 1) a + b
+// 2) If there is overflow, an error
+func Add64(a, b uint64) (uint64, error) { return Add(a, b) }
+
+// Sub64 returns:
+// 1) a - b
+// 2) If there is underflow, an error
+func Sub64(a, b uint64) (uint64, error) { return Sub(a, b) }
+
+// Mul64 returns:
+// 1) a * b
+// 2) If there is overflow, an error
+func Mul64(a, b uint64) (uint64, error) { return Mul(a, b) }
+
+func Diff64(a, b uint64) uint64 { return Diff(a, b) }
@@ -116,3 +116,90 @@ func TestDiff64(t *testing.T) {
 		t.Fatalf("Expected %d, got %d", maxUint64, actual)
 	}
 }
+
+func TestAddGeneric(t *testing.T) {
+	if _, err := Add[int8](math.MaxInt8, 1); err == nil {
+		t.Fatalf("Add should have overflowed")
+	}
+	if _, err := Add[int8](math.MinInt8, -1); err == nil {
+		t.Fatalf("Add should have underflowed")
+	}
+	if sum, err := Add[int8](math.MaxInt8, math.MinInt8); err != nil || sum != -1 {
+		t.Fatalf("Expected -1, got %d, %v", sum, err)
+	}
+	if _, err := Add[uint16](math.MaxUint16, 1); err == nil {
+		t.Fatalf("Add should have overflowed")
+	}
+}
+
+func TestSubGeneric(t *testing.T) {
+	if _, err := Sub[uint32](0, 1); err == nil {
+		t.Fatalf("Sub should have underflowed")
+	}
+	if _, err := Sub[int32](math.MinInt32, 1); err == nil {
+		t.Fatalf("Sub should have underflowed")
+	}
+	if _, err := Sub[int32](math.MaxInt32, -1); err == nil {
+		t.Fatalf("Sub should have overflowed")
+	}
+	if diff, err := Sub[int32](-1, math.MinInt32); err != nil || diff != math.MaxInt32 {
+		t.Fatalf("Expected %d, got %d, %v", math.MaxInt32, diff, err)
+	}
+}
+
+func TestMulGeneric(t *testing.T) {
+	if _, err := Mul[int64](math.MinInt64, -1); err == nil {
+		t.Fatalf("Mul should have overflowed")
+	}
+	if _, err := Mul[int64](-1, math.MinInt64); err == nil {
+		t.Fatalf("Mul should have overflowed")
+	}
+	if _, err := Mul[uint8](16, 16); err == nil {
+		t.Fatalf("Mul should have overflowed")
+	}
+	if product, err := Mul[int8](-8, 16); err != nil || product != math.MinInt8 {
+		t.Fatalf("Expected %d, got %d, %v", math.MinInt8, product, err)
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	if sum := SaturatingAdd[uint8](200, 100); sum != math.MaxUint8 {
+		t.Fatalf("Expected %d, got %d", math.MaxUint8, sum)
+	}
+	if sum := SaturatingAdd[int16](math.MinInt16, -1); sum != math.MinInt16 {
+		t.Fatalf("Expected %d, got %d", math.MinInt16, sum)
+	}
+	if diff := SaturatingSub[uint64](1, 2); diff != 0 {
+		t.Fatalf("Expected 0, got %d", diff)
+	}
+	if diff := SaturatingSub[int8](math.MaxInt8, -1); diff != math.MaxInt8 {
+		t.Fatalf("Expected %d, got %d", math.MaxInt8, diff)
+	}
+	if product := SaturatingMul[int32](math.MaxInt32, -2); product != math.MinInt32 {
+		t.Fatalf("Expected %d, got %d", math.MinInt32, product)
+	}
+	if product := SaturatingMul[int32](math.MinInt32, -2); product != math.MaxInt32 {
+		t.Fatalf("Expected %d, got %d", math.MaxInt32, product)
+	}
+}
+
+func TestWrapping(t *testing.T) {
+	if sum := WrappingAdd[uint8](math.MaxUint8, 1); sum != 0 {
+		t.Fatalf("Expected 0, got %d", sum)
+	}
+	if diff := WrappingSub[int8](math.MinInt8, 1); diff != math.MaxInt8 {
+		t.Fatalf("Expected %d, got %d", math.MaxInt8, diff)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	if MaxValue[int16]() != math.MaxInt16 || MinValue[int16]() != math.MinInt16 {
+		t.Fatalf("Wrong int16 bounds")
+	}
+	if MaxValue[uint32]() != math.MaxUint32 || MinValue[uint32]() != 0 {
+		t.Fatalf("Wrong uint32 bounds")
+	}
+	if MaxValue[int]() != math.MaxInt || MinValue[int]() != math.MinInt {
+		t.Fatalf("Wrong int bounds")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package math
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// windowedPercentile tracks a percentile of the most recently provided values.
+// Unlike an average, a few outliers don't move a low percentile.
+type windowedPercentile struct {
+	percentile        float64
+	initialPrediction float64
+	windowSize        int
+	// Values in the order they were observed. Once the window is full, [next]
+	// is the index of the oldest value.
+	window []float64
+	next   int
+	// The values in [window], sorted
+	sorted []float64
+}
+
+// NewWindowedPercentile returns an Averager whose Read returns the
+// [percentile], in (0, 1], of the last [windowSize] observed values. Read
+// returns [initialPrediction] until a value is observed.
+func NewWindowedPercentile(
+	initialPrediction float64,
+	percentile float64,
+	windowSize int,
+) Averager {
+	return &windowedPercentile{
+		percentile:        percentile,
+		initialPrediction: initialPrediction,
+		windowSize:        windowSize,
+		window:            make([]float64, 0, windowSize),
+		sorted:            make([]float64, 0, windowSize),
+	}
+}
+
+func (p *windowedPercentile) Observe(value float64, _ time.Time) {
+	if len(p.window) < p.windowSize {
+		p.window = append(p.window, value)
+	} else {
+		// Evict the oldest value
+		oldest := p.window[p.next]
+		i := sort.SearchFloat64s(p.sorted, oldest)
+		p.sorted = append(p.sorted[:i], p.sorted[i+1:]...)
+
+		p.window[p.next] = value
+		p.next = (p.next + 1) % p.windowSize
+	}
+
+	i := sort.SearchFloat64s(p.sorted, value)
+	p.sorted = append(p.sorted, 0)
+	copy(p.sorted[i+1:], p.sorted[i:])
+	p.sorted[i] = value
+}
+
+func (p *windowedPercentile) Read() float64 {
+	if len(p.sorted) == 0 {
+		return p.initialPrediction
+	}
+	i := int(math.Ceil(p.percentile*float64(len(p.sorted)))) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(p.sorted) {
+		i = len(p.sorted) - 1
+	}
+	return p.sorted[i]
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package math
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowedPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewWindowedPercentile(10, 0.9, 10)
+	assert.Equal(10.0, p.Read())
+
+	now := time.Now()
+	for i := 1; i <= 10; i++ {
+		p.Observe(float64(i), now)
+	}
+	assert.Equal(9.0, p.Read())
+
+	// A single outlier doesn't move the percentile
+	p.Observe(1000, now)
+	assert.Equal(10.0, p.Read())
+
+	// Old values leave the window
+	for i := 0; i < 10; i++ {
+		p.Observe(1, now)
+	}
+	assert.Equal(1.0, p.Read())
+}
+
+func TestWindowedPercentileMax(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewWindowedPercentile(0, 1, 3)
+	now := time.Now()
+	p.Observe(3, now)
+	p.Observe(1, now)
+	p.Observe(2, now)
+	assert.Equal(3.0, p.Read())
+
+	p.Observe(1, now)
+	assert.Equal(2.0, p.Read())
+}
@@ -6,30 +6,146 @@ package password
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
+	"fmt"
 
 	"golang.org/x/crypto/argon2"
 )
 
+var (
+	// LegacyHashParams are the parameters that every password was hashed with
+	// before the parameters became configurable. A Hash with zero parameters
+	// was computed with these parameters.
+	LegacyHashParams = HashParams{
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	// DefaultHashParams are the parameters used by Set. They follow the
+	// second recommendation of RFC 9106 for memory-constrained environments.
+	// Passwords hashed with [LegacyHashParams] are rehashed with these
+	// parameters the next time they're used.
+	DefaultHashParams = HashParams{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	errZeroTime     = errors.New("argon2id time must be > 0")
+	errZeroThreads  = errors.New("argon2id threads must be > 0")
+	errLowMemory    = errors.New("argon2id memory must be at least 8 KiB per thread")
+	errHighTime     = fmt.Errorf("argon2id time must be <= %d", MaxHashTime)
+	errHighMemory   = fmt.Errorf("argon2id memory must be <= %d KiB", MaxHashMemory)
+	errHighThreads  = fmt.Errorf("argon2id threads must be <= %d", MaxHashThreads)
+	errInvalidParam = errors.New("password was hashed with invalid parameters")
+)
+
+// Upper bounds on the parameters a password can be hashed with. Hashes may
+// come from untrusted sources, such as imported users, so checking a password
+// against a hash must never be arbitrarily expensive.
+const (
+	MaxHashTime    = 16
+	MaxHashMemory  = 256 * 1024
+	MaxHashThreads = 64
+)
+
+// HashParams are the argon2id parameters used to hash a password
+type HashParams struct {
+	// Number of passes over the memory
+	Time uint32 `serializeV1:"true" json:"time"`
+	// Memory used, in KiB
+	Memory uint32 `serializeV1:"true" json:"memory"`
+	// Degree of parallelism
+	Threads uint8 `serializeV1:"true" json:"threads"`
+}
+
+// Verify returns an error if passwords can't be hashed with these parameters
+func (p HashParams) Verify() error {
+	switch {
+	case p.Time == 0:
+		return errZeroTime
+	case p.Threads == 0:
+		return errZeroThreads
+	case p.Memory < 8*uint32(p.Threads):
+		return errLowMemory
+	case p.Time > MaxHashTime:
+		return errHighTime
+	case p.Memory > MaxHashMemory:
+		return errHighMemory
+	case p.Threads > MaxHashThreads:
+		return errHighThreads
+	default:
+		return nil
+	}
+}
+
 // Hash of a password
 type Hash struct {
-	Password [32]byte `serialize:"true"` // The salted, hashed password
-	Salt     [16]byte `serialize:"true"` // The salt
+	Password [32]byte `serialize:"true" serializeV1:"true"` // The salted, hashed password
+	Salt     [16]byte `serialize:"true" serializeV1:"true"` // The salt
+	// The parameters [Password] was hashed with. If zero, [LegacyHashParams]
+	// were used.
+	Params HashParams `serializeV1:"true"`
 }
 
 // Set updates the password hash to be of the provided password
 func (h *Hash) Set(password string) error {
+	return h.SetWithParams(password, DefaultHashParams)
+}
+
+// SetWithParams updates the password hash to be of the provided password,
+// hashed with [params]
+func (h *Hash) SetWithParams(password string, params HashParams) error {
+	if err := params.Verify(); err != nil {
+		return err
+	}
 	if _, err := rand.Read(h.Salt[:]); err != nil {
 		return err
 	}
+	h.Params = params
 	// pw is the salted, hashed password
-	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
+	pw := h.hash(password)
 	copy(h.Password[:], pw[:32])
 	return nil
 }
 
 // Check returns true iff the provided password was the same as the last
-// password set.
+// password set. Returns false if the hash's parameters are invalid.
 func (h *Hash) Check(password string) bool {
-	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
-	return bytes.Equal(pw, h.Password[:])
+	if h.Verify() != nil {
+		return false
+	}
+	return bytes.Equal(h.hash(password), h.Password[:])
+}
+
+// Verify returns an error if the parameters the password was hashed with are
+// invalid
+func (h *Hash) Verify() error {
+	if err := h.params().Verify(); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidParam, err)
+	}
+	return nil
+}
+
+// IsLegacy returns true if the password was hashed with [LegacyHashParams]
+func (h *Hash) IsLegacy() bool {
+	return h.params() == LegacyHashParams
+}
+
+// NeedsRehash returns true if the password wasn't hashed with [params]
+func (h *Hash) NeedsRehash(params HashParams) bool {
+	return h.params() != params
+}
+
+func (h *Hash) params() HashParams {
+	if h.Params == (HashParams{}) {
+		return LegacyHashParams
+	}
+	return h.Params
+}
+
+func (h *Hash) hash(password string) []byte {
+	params := h.params()
+	return argon2.IDKey([]byte(password), h.Salt[:], params.Time, params.Memory, params.Threads, 32)
 }
@@ -22,3 +22,91 @@ func TestHash(t *testing.T) {
 		t.Fatalf("Shouldn't have verified the password")
 	}
 }
+
+func TestHashParams(t *testing.T) {
+	params := HashParams{
+		Time:    2,
+		Memory:  1024,
+		Threads: 1,
+	}
+
+	h := Hash{}
+	if err := h.SetWithParams("heytherepal", params); err != nil {
+		t.Fatal(err)
+	}
+	if !h.Check("heytherepal") {
+		t.Fatalf("Should have verified the password")
+	}
+	if h.Check("heytherepal!") {
+		t.Fatalf("Shouldn't have verified the password")
+	}
+	if h.NeedsRehash(params) {
+		t.Fatalf("Shouldn't need to be rehashed with the same parameters")
+	}
+	if !h.NeedsRehash(DefaultHashParams) {
+		t.Fatalf("Should need to be rehashed with other parameters")
+	}
+}
+
+func TestLegacyHash(t *testing.T) {
+	h := Hash{}
+	if err := h.SetWithParams("heytherepal", LegacyHashParams); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hashes stored before the parameters became configurable don't have
+	// parameters
+	h.Params = HashParams{}
+	if !h.Check("heytherepal") {
+		t.Fatalf("Should have verified the password")
+	}
+	if h.NeedsRehash(LegacyHashParams) {
+		t.Fatalf("Shouldn't need to be rehashed with the legacy parameters")
+	}
+	if !h.NeedsRehash(DefaultHashParams) {
+		t.Fatalf("Should need to be rehashed with the default parameters")
+	}
+}
+
+func TestHashParamsVerify(t *testing.T) {
+	tests := []struct {
+		params      HashParams
+		expectedErr error
+	}{
+		{params: DefaultHashParams},
+		{params: HashParams{Time: 0, Memory: 1024, Threads: 1}, expectedErr: errZeroTime},
+		{params: HashParams{Time: 1, Memory: 1024, Threads: 0}, expectedErr: errZeroThreads},
+		{params: HashParams{Time: 1, Memory: 31, Threads: 4}, expectedErr: errLowMemory},
+		{params: HashParams{Time: MaxHashTime + 1, Memory: 1024, Threads: 1}, expectedErr: errHighTime},
+		{params: HashParams{Time: 1, Memory: MaxHashMemory + 1, Threads: 1}, expectedErr: errHighMemory},
+		{params: HashParams{Time: 1, Memory: MaxHashMemory, Threads: MaxHashThreads + 1}, expectedErr: errHighThreads},
+	}
+	for _, test := range tests {
+		if err := test.params.Verify(); err != test.expectedErr {
+			t.Fatalf("expected %v but got %v", test.expectedErr, err)
+		}
+	}
+}
+
+func TestHashCheckInvalidParams(t *testing.T) {
+	h := Hash{}
+	if err := h.Set("heytherepal"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Checking must not hash the password with parameters that could exhaust
+	// resources or make argon2id panic
+	for _, params := range []HashParams{
+		{Time: 1, Memory: 1024, Threads: 0},
+		{Time: 1, Memory: 1 << 31, Threads: 1},
+		{Time: 1 << 31, Memory: 1024, Threads: 1},
+	} {
+		h.Params = params
+		if err := h.Verify(); err == nil {
+			t.Fatalf("Should have rejected the parameters %+v", params)
+		}
+		if h.Check("heytherepal") {
+			t.Fatalf("Shouldn't have verified the password with the parameters %+v", params)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+type call struct {
+	Method string `json:"method"`
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ReadMethods returns the JSON-RPC methods called by [r], which is either a
+// single call or a batch of calls. Only the first [maxSize] bytes of the body
+// are parsed. The body of [r] is replaced, so that it can still be read by the
+// handler of the request. A body that can't be parsed calls no method.
+func ReadMethods(r *http.Request, maxSize int64) ([]string, error) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSize))
+	if err != nil {
+		return nil, err
+	}
+	// The rest of the body, if any, is left to the handler
+	r.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(body), r.Body),
+		Closer: r.Body,
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(body))
+	var calls []call
+	if first, err := firstNonSpace(reader); err == nil && first == '[' {
+		_ = json.NewDecoder(reader).Decode(&calls)
+	} else {
+		c := call{}
+		_ = json.NewDecoder(reader).Decode(&c)
+		calls = []call{c}
+	}
+
+	methods := make([]string, 0, len(calls))
+	for _, c := range calls {
+		if c.Method != "" {
+			methods = append(methods, c.Method)
+		}
+	}
+	return methods, nil
+}
+
+// firstNonSpace returns the first non-whitespace byte of [reader] without
+// consuming it
+func firstNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return b, reader.UnreadByte()
+		}
+	}
+}
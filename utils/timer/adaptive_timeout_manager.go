@@ -19,6 +19,15 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 )
 
+const (
+	// AverageTimeoutAlgorithm sets the timeout based on an exponential moving
+	// average of the observed response times
+	AverageTimeoutAlgorithm = "average"
+	// PercentileTimeoutAlgorithm sets the timeout based on a percentile of the
+	// recently observed response times
+	PercentileTimeoutAlgorithm = "percentile"
+)
+
 var errNonPositiveHalflife = errors.New("timeout halflife must be positive")
 
 type adaptiveTimeout struct {
@@ -69,6 +78,20 @@ type AdaptiveTimeoutConfig struct {
 	// Larger halflife --> less volatile timeout
 	// [timeoutHalfLife] must be positive
 	TimeoutHalflife time.Duration `json:"timeoutHalflife"`
+	// Either [AverageTimeoutAlgorithm] or [PercentileTimeoutAlgorithm]. If
+	// empty, [AverageTimeoutAlgorithm] is used.
+	TimeoutAlgorithm string `json:"timeoutAlgorithm"`
+	// With [PercentileTimeoutAlgorithm], timeout is [timeoutCoefficient] *
+	// the [TimeoutPercentile] of the last [TimeoutPercentileWindowSize]
+	// response times. [TimeoutPercentile] must be in (0, 1].
+	TimeoutPercentile           float64 `json:"timeoutPercentile"`
+	TimeoutPercentileWindowSize int     `json:"timeoutPercentileWindowSize"`
+	// Portion of the difference between the current timeout and the target
+	// timeout that is closed on every observed response time, when the timeout
+	// increases or decreases respectively. Must be in [0, 1]. If 0, the
+	// timeout is set to the target timeout.
+	TimeoutIncreaseCoefficient float64 `json:"timeoutIncreaseCoefficient"`
+	TimeoutDecreaseCoefficient float64 `json:"timeoutDecreaseCoefficient"`
 }
 
 // AdaptiveTimeoutManager is a manager for timeouts.
@@ -83,12 +106,16 @@ type AdaptiveTimeoutManager struct {
 	// Timeout is [timeoutCoefficient] * average response time
 	// [timeoutCoefficient] must be > 1
 	timeoutCoefficient float64
-	minimumTimeout     time.Duration
-	maximumTimeout     time.Duration
-	currentTimeout     time.Duration // Amount of time before a timeout
-	timeoutMap         map[ids.ID]*adaptiveTimeout
-	timeoutQueue       timeoutQueue
-	timer              *Timer // Timer that will fire to clear the timeouts
+	// Portion of the difference to the target timeout that is closed on every
+	// observation
+	increaseCoefficient float64
+	decreaseCoefficient float64
+	minimumTimeout      time.Duration
+	maximumTimeout      time.Duration
+	currentTimeout      time.Duration // Amount of time before a timeout
+	timeoutMap          map[ids.ID]*adaptiveTimeout
+	timeoutQueue        timeoutQueue
+	timer               *Timer // Timer that will fire to clear the timeouts
 }
 
 // Initialize this timeout manager with the provided config
@@ -122,10 +149,32 @@ func (tm *AdaptiveTimeoutManager) Initialize(
 		return fmt.Errorf("timeout coefficient must be >= 1 but got %f", config.TimeoutCoefficient)
 	case config.TimeoutHalflife <= 0:
 		return errNonPositiveHalflife
+	case config.TimeoutIncreaseCoefficient < 0 || config.TimeoutIncreaseCoefficient > 1:
+		return fmt.Errorf("timeout increase coefficient must be in [0, 1] but got %f", config.TimeoutIncreaseCoefficient)
+	case config.TimeoutDecreaseCoefficient < 0 || config.TimeoutDecreaseCoefficient > 1:
+		return fmt.Errorf("timeout decrease coefficient must be in [0, 1] but got %f", config.TimeoutDecreaseCoefficient)
+	}
+
+	switch config.TimeoutAlgorithm {
+	case "", AverageTimeoutAlgorithm:
+		tm.averager = math.NewAverager(float64(config.InitialTimeout), config.TimeoutHalflife, tm.clock.Time())
+	case PercentileTimeoutAlgorithm:
+		if config.TimeoutPercentile <= 0 || config.TimeoutPercentile > 1 {
+			return fmt.Errorf("timeout percentile must be in (0, 1] but got %f", config.TimeoutPercentile)
+		}
+		if config.TimeoutPercentileWindowSize <= 0 {
+			return fmt.Errorf("timeout percentile window size must be positive but got %d", config.TimeoutPercentileWindowSize)
+		}
+		// The initial timeout is the target until a response time is observed
+		initialPrediction := float64(config.InitialTimeout) / config.TimeoutCoefficient
+		tm.averager = math.NewWindowedPercentile(initialPrediction, config.TimeoutPercentile, config.TimeoutPercentileWindowSize)
+	default:
+		return fmt.Errorf("unknown timeout algorithm %q", config.TimeoutAlgorithm)
 	}
 
 	tm.timeoutCoefficient = config.TimeoutCoefficient
-	tm.averager = math.NewAverager(float64(config.InitialTimeout), config.TimeoutHalflife, tm.clock.Time())
+	tm.increaseCoefficient = config.TimeoutIncreaseCoefficient
+	tm.decreaseCoefficient = config.TimeoutDecreaseCoefficient
 	tm.minimumTimeout = config.MinimumTimeout
 	tm.maximumTimeout = config.MaximumTimeout
 	tm.currentTimeout = config.InitialTimeout
@@ -252,7 +301,15 @@ func (tm *AdaptiveTimeoutManager) ObserveLatency(latency time.Duration) {
 func (tm *AdaptiveTimeoutManager) observeLatencyAndUpdateTimeout(latency time.Duration, now time.Time) {
 	tm.averager.Observe(float64(latency), now)
 	avgLatency := tm.averager.Read()
-	tm.currentTimeout = time.Duration(tm.timeoutCoefficient * avgLatency)
+	targetTimeout := tm.timeoutCoefficient * avgLatency
+	currentTimeout := float64(tm.currentTimeout)
+	switch {
+	case targetTimeout > currentTimeout && tm.increaseCoefficient > 0:
+		targetTimeout = currentTimeout + tm.increaseCoefficient*(targetTimeout-currentTimeout)
+	case targetTimeout < currentTimeout && tm.decreaseCoefficient > 0:
+		targetTimeout = currentTimeout - tm.decreaseCoefficient*(currentTimeout-targetTimeout)
+	}
+	tm.currentTimeout = time.Duration(targetTimeout)
 	if tm.currentTimeout > tm.maximumTimeout {
 		tm.currentTimeout = tm.maximumTimeout
 	} else if tm.currentTimeout < tm.minimumTimeout {
@@ -82,6 +82,68 @@ func TestAdaptiveTimeoutManagerInit(t *testing.T) {
 				TimeoutHalflife:    5 * time.Minute,
 			},
 		},
+		{
+			config: AdaptiveTimeoutConfig{
+				InitialTimeout:     2 * time.Second,
+				MinimumTimeout:     2 * time.Second,
+				MaximumTimeout:     3 * time.Second,
+				TimeoutCoefficient: 1,
+				TimeoutHalflife:    5 * time.Minute,
+				TimeoutAlgorithm:   "median",
+			},
+			shouldErrWith: "unknown timeout algorithm",
+		},
+		{
+			config: AdaptiveTimeoutConfig{
+				InitialTimeout:              2 * time.Second,
+				MinimumTimeout:              2 * time.Second,
+				MaximumTimeout:              3 * time.Second,
+				TimeoutCoefficient:          1,
+				TimeoutHalflife:             5 * time.Minute,
+				TimeoutAlgorithm:            PercentileTimeoutAlgorithm,
+				TimeoutPercentile:           0,
+				TimeoutPercentileWindowSize: 100,
+			},
+			shouldErrWith: "timeout percentile is 0",
+		},
+		{
+			config: AdaptiveTimeoutConfig{
+				InitialTimeout:              2 * time.Second,
+				MinimumTimeout:              2 * time.Second,
+				MaximumTimeout:              3 * time.Second,
+				TimeoutCoefficient:          1,
+				TimeoutHalflife:             5 * time.Minute,
+				TimeoutAlgorithm:            PercentileTimeoutAlgorithm,
+				TimeoutPercentile:           0.99,
+				TimeoutPercentileWindowSize: 0,
+			},
+			shouldErrWith: "timeout percentile window size is 0",
+		},
+		{
+			config: AdaptiveTimeoutConfig{
+				InitialTimeout:             2 * time.Second,
+				MinimumTimeout:             2 * time.Second,
+				MaximumTimeout:             3 * time.Second,
+				TimeoutCoefficient:         1,
+				TimeoutHalflife:            5 * time.Minute,
+				TimeoutIncreaseCoefficient: 1.5,
+			},
+			shouldErrWith: "timeout increase coefficient > 1",
+		},
+		{
+			config: AdaptiveTimeoutConfig{
+				InitialTimeout:              2 * time.Second,
+				MinimumTimeout:              2 * time.Second,
+				MaximumTimeout:              3 * time.Second,
+				TimeoutCoefficient:          1,
+				TimeoutHalflife:             5 * time.Minute,
+				TimeoutAlgorithm:            PercentileTimeoutAlgorithm,
+				TimeoutPercentile:           0.99,
+				TimeoutPercentileWindowSize: 100,
+				TimeoutIncreaseCoefficient:  0.5,
+				TimeoutDecreaseCoefficient:  0.1,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -142,3 +204,43 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestAdaptiveTimeoutManagerPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		&AdaptiveTimeoutConfig{
+			InitialTimeout:              time.Second,
+			MinimumTimeout:              time.Millisecond,
+			MaximumTimeout:              time.Hour,
+			TimeoutHalflife:             5 * time.Minute,
+			TimeoutCoefficient:          2,
+			TimeoutAlgorithm:            PercentileTimeoutAlgorithm,
+			TimeoutPercentile:           0.9,
+			TimeoutPercentileWindowSize: 10,
+			TimeoutDecreaseCoefficient:  0.5,
+		},
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+
+	// The timeout moves halfway towards the target when it decreases
+	tm.ObserveLatency(100 * time.Millisecond)
+	assert.Equal(600*time.Millisecond, tm.TimeoutDuration())
+
+	for i := 0; i < 9; i++ {
+		tm.ObserveLatency(100 * time.Millisecond)
+	}
+	// A single slow peer doesn't raise the timeout
+	tm.ObserveLatency(time.Minute)
+	timeout := tm.TimeoutDuration()
+	assert.Less(int64(timeout), int64(time.Second))
+
+	// Increases are applied at once
+	for i := 0; i < 10; i++ {
+		tm.ObserveLatency(time.Second)
+	}
+	assert.Equal(2*time.Second, tm.TimeoutDuration())
+}
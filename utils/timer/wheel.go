@@ -0,0 +1,264 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+)
+
+const (
+	// DefaultWheelSlots is the number of slots in each level of a wheel created
+	// with NewDefaultWheel
+	DefaultWheelSlots = 64
+	// DefaultWheelLevels is the number of levels of a wheel created with
+	// NewDefaultWheel. With 64 slots per level, a wheel with a 100ms tick
+	// covers a little over 3 days before timeouts overflow.
+	DefaultWheelLevels = 4
+)
+
+// Wheel is a hierarchical timer wheel. It schedules a large number of
+// coarse-grained timeouts, such as request timeouts, on a single goroutine and
+// a single runtime timer instead of allocating a runtime timer per timeout.
+//
+// Time is divided into ticks. Level 0 of the wheel has one slot per tick, and
+// each slot of level i covers a full rotation of level i-1. Timeouts are
+// stored in the lowest level that can hold them, and move down a level each
+// time the slot they are in is reached. Timeouts too far in the future for the
+// top level are kept aside until the top level completes a rotation.
+//
+// A timeout never fires before its duration has elapsed, and fires at most one
+// tick late while the dispatcher is keeping up.
+type Wheel struct {
+	tick  time.Duration
+	slots uint64
+	// spans[i] is the number of ticks covered by a slot of level i. The last
+	// element is the number of ticks covered by a full rotation of the wheel.
+	spans []uint64
+	clock mockable.Clock
+
+	lock  sync.Mutex
+	start time.Time
+	// current is the last tick that has been processed
+	current  uint64
+	levels   [][]*list.List
+	overflow *list.List
+	// pending is the number of scheduled timeouts
+	pending int
+
+	closer   chan struct{}
+	stopOnce sync.Once
+}
+
+// WheelTimer is a timeout scheduled on a Wheel
+type WheelTimer struct {
+	wheel   *Wheel
+	handler func()
+	expiry  uint64
+	// bucket is the list this timeout is stored in, or nil if the timeout has
+	// fired or been cancelled
+	bucket  *list.List
+	element *list.Element
+}
+
+// NewDefaultWheel returns a new wheel with the default number of slots and
+// levels that advances every [tick]
+func NewDefaultWheel(tick time.Duration) *Wheel {
+	return NewWheel(tick, DefaultWheelSlots, DefaultWheelLevels)
+}
+
+// NewWheel returns a new wheel that advances every [tick] and has [levels]
+// levels of [slots] slots each. [slots] is raised to 2 and [levels] to 1 if
+// they are smaller.
+func NewWheel(tick time.Duration, slots, levels int) *Wheel {
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+	if slots < 2 {
+		slots = 2
+	}
+	if levels < 1 {
+		levels = 1
+	}
+
+	w := &Wheel{
+		tick:     tick,
+		slots:    uint64(slots),
+		spans:    make([]uint64, levels+1),
+		levels:   make([][]*list.List, levels),
+		overflow: list.New(),
+		closer:   make(chan struct{}),
+	}
+	w.start = w.clock.Time()
+
+	w.spans[0] = 1
+	for i := range w.levels {
+		w.levels[i] = make([]*list.List, slots)
+		for j := range w.levels[i] {
+			w.levels[i][j] = list.New()
+		}
+		w.spans[i+1] = w.spans[i] * w.slots
+	}
+	return w
+}
+
+// Schedule [handler] to be called once [duration] has elapsed. The handler is
+// called on the dispatch goroutine, so it should return quickly.
+func (w *Wheel) Schedule(duration time.Duration, handler func()) *WheelTimer {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if duration < 0 {
+		duration = 0
+	}
+	elapsed := w.clock.Time().Sub(w.start) + duration
+	expiry := uint64((elapsed + w.tick - 1) / w.tick)
+	if expiry <= w.current {
+		expiry = w.current + 1
+	}
+
+	t := &WheelTimer{
+		wheel:   w,
+		handler: handler,
+		expiry:  expiry,
+	}
+	w.insert(t)
+	w.pending++
+	return t
+}
+
+// Len returns the number of scheduled timeouts
+func (w *Wheel) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.pending
+}
+
+// Dispatch advances the wheel and calls the handlers of the timeouts that have
+// fired. It only returns after Stop is called.
+func (w *Wheel) Dispatch() {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.lock.Lock()
+			handlers := w.advance(w.ticksAt(w.clock.Time()))
+			w.lock.Unlock()
+
+			// Don't execute a callback with a lock held
+			for _, handler := range handlers {
+				handler()
+			}
+		case <-w.closer:
+			return
+		}
+	}
+}
+
+// Stop the dispatcher. Timeouts that haven't fired yet never will.
+func (w *Wheel) Stop() {
+	w.stopOnce.Do(func() { close(w.closer) })
+}
+
+// Cancel this timeout. Returns true if the timeout was cancelled before it
+// fired.
+func (t *WheelTimer) Cancel() bool {
+	w := t.wheel
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if t.bucket == nil {
+		return false
+	}
+	t.bucket.Remove(t.element)
+	t.bucket = nil
+	t.element = nil
+	w.pending--
+	return true
+}
+
+// ticksAt returns the number of complete ticks between the creation of the
+// wheel and [now]
+func (w *Wheel) ticksAt(now time.Time) uint64 {
+	elapsed := now.Sub(w.start)
+	if elapsed < 0 {
+		return 0
+	}
+	return uint64(elapsed / w.tick)
+}
+
+// insert [t] into the lowest level whose rotation also contains the current
+// tick. Assumes the lock is held.
+func (w *Wheel) insert(t *WheelTimer) {
+	for level, slots := range w.levels {
+		rotation := w.spans[level+1]
+		if t.expiry/rotation == w.current/rotation {
+			t.bucket = slots[(t.expiry/w.spans[level])%w.slots]
+			t.element = t.bucket.PushBack(t)
+			return
+		}
+	}
+	t.bucket = w.overflow
+	t.element = w.overflow.PushBack(t)
+}
+
+// advance the wheel up to and including tick [to], and return the handlers of
+// the timeouts that fired. Assumes the lock is held.
+func (w *Wheel) advance(to uint64) []func() {
+	var handlers []func()
+	for w.current < to {
+		if w.pending == 0 {
+			// Nothing can fire, so there is no need to walk the slots
+			w.current = to
+			break
+		}
+		w.current++
+
+		// Move the timeouts in the slots that were just reached down the wheel,
+		// starting from the top so that timeouts can fall through several
+		// levels at once
+		top := len(w.levels)
+		if w.current%w.spans[top] == 0 {
+			w.cascade(w.overflow)
+		}
+		for level := top - 1; level > 0; level-- {
+			span := w.spans[level]
+			if w.current%span == 0 {
+				w.cascade(w.levels[level][(w.current/span)%w.slots])
+			}
+		}
+
+		bucket := w.levels[0][w.current%w.slots]
+		for e := bucket.Front(); e != nil; e = e.Next() {
+			t := e.Value.(*WheelTimer)
+			t.bucket = nil
+			t.element = nil
+			handlers = append(handlers, t.handler)
+		}
+		w.pending -= bucket.Len()
+		bucket.Init()
+	}
+	return handlers
+}
+
+// cascade re-inserts the timeouts in [bucket]. Assumes the lock is held.
+func (w *Wheel) cascade(bucket *list.List) {
+	if bucket.Len() == 0 {
+		return
+	}
+	timers := make([]*WheelTimer, 0, bucket.Len())
+	for e := bucket.Front(); e != nil; e = e.Next() {
+		timers = append(timers, e.Value.(*WheelTimer))
+	}
+	bucket.Init()
+	for _, t := range timers {
+		w.insert(t)
+	}
+}
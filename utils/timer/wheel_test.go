@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestWheel returns a wheel with a faked clock that starts at [now]
+func newTestWheel(now time.Time, slots, levels int) *Wheel {
+	w := NewWheel(time.Second, slots, levels)
+	w.clock.Set(now)
+	w.start = now
+	return w
+}
+
+func TestWheelFiresInOrder(t *testing.T) {
+	now := time.Unix(0, 0)
+	w := newTestWheel(now, 4, 2)
+
+	fired := map[int]uint64{}
+	delays := []int{1, 3, 4, 5, 15, 16, 17, 40, 100}
+	for _, delay := range delays {
+		delay := delay
+		w.Schedule(time.Duration(delay)*time.Second, func() {
+			fired[delay] = w.current
+		})
+	}
+	if pending := w.Len(); pending != len(delays) {
+		t.Fatalf("Expected %d pending timeouts, got %d", len(delays), pending)
+	}
+
+	for tick := uint64(1); tick <= 100; tick++ {
+		for _, handler := range w.advance(tick) {
+			handler()
+		}
+	}
+
+	for _, delay := range delays {
+		tick, ok := fired[delay]
+		switch {
+		case !ok:
+			t.Fatalf("Timeout after %ds never fired", delay)
+		case tick != uint64(delay):
+			t.Fatalf("Timeout after %ds fired at tick %d", delay, tick)
+		}
+	}
+	if pending := w.Len(); pending != 0 {
+		t.Fatalf("Expected no pending timeouts, got %d", pending)
+	}
+}
+
+func TestWheelNeverFiresEarly(t *testing.T) {
+	now := time.Unix(0, 0)
+	w := newTestWheel(now, 4, 2)
+
+	// Half a tick after the start of the wheel, a one tick timeout must wait
+	// for the second tick
+	w.clock.Set(now.Add(500 * time.Millisecond))
+	fired := false
+	w.Schedule(time.Second, func() { fired = true })
+
+	if handlers := w.advance(1); len(handlers) != 0 {
+		t.Fatalf("Timeout fired early")
+	}
+	for _, handler := range w.advance(2) {
+		handler()
+	}
+	if !fired {
+		t.Fatalf("Timeout should have fired")
+	}
+}
+
+func TestWheelCancel(t *testing.T) {
+	now := time.Unix(0, 0)
+	w := newTestWheel(now, 4, 2)
+
+	timer := w.Schedule(10*time.Second, func() { t.Fatalf("Cancelled timeout fired") })
+	if !timer.Cancel() {
+		t.Fatalf("Pending timeout should have been cancelled")
+	}
+	if timer.Cancel() {
+		t.Fatalf("Timeout shouldn't be cancelled twice")
+	}
+	if handlers := w.advance(100); len(handlers) != 0 {
+		t.Fatalf("Cancelled timeout fired")
+	}
+
+	fired := w.Schedule(time.Second, func() {})
+	if handlers := w.advance(101); len(handlers) != 1 {
+		t.Fatalf("Timeout should have fired")
+	}
+	if fired.Cancel() {
+		t.Fatalf("Fired timeout shouldn't be cancelled")
+	}
+}
+
+func TestWheelDispatch(t *testing.T) {
+	w := NewDefaultWheel(time.Millisecond)
+	go w.Dispatch()
+	defer w.Stop()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	defer wg.Wait()
+
+	w.Schedule(0, wg.Done)
+	w.Schedule(5*time.Millisecond, wg.Done)
+}
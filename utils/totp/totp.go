@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package totp implements time-based one-time passwords, as specified by
+// RFC 6238, with the parameters that authenticator apps support by default:
+// HMAC-SHA1, 30 second time steps and 6 digit codes.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// Period is the duration of a time step
+	Period = 30 * time.Second
+	// Digits is the number of digits of a code
+	Digits = 6
+	// SecretLen is the number of bytes of the secrets generated by NewSecret.
+	// RFC 4226 recommends 160 bits.
+	SecretLen = 20
+)
+
+var (
+	encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	digitsPower = [...]uint32{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000}
+)
+
+// NewSecret returns a new random secret
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, SecretLen)
+	_, err := rand.Read(secret)
+	return secret, err
+}
+
+// EncodeSecret returns [secret] in the base32 encoding that authenticator
+// apps expect
+func EncodeSecret(secret []byte) string {
+	return encoding.EncodeToString(secret)
+}
+
+// DecodeSecret returns the secret whose base32 encoding is [secret]
+func DecodeSecret(secret string) ([]byte, error) {
+	return encoding.DecodeString(secret)
+}
+
+// URI returns the otpauth URI of [secret], which authenticator apps import
+// from QR codes
+func URI(issuer, account string, secret []byte) string {
+	query := url.Values{}
+	query.Set("secret", EncodeSecret(secret))
+	query.Set("issuer", issuer)
+	return fmt.Sprintf("otpauth://totp/%s:%s?%s", url.PathEscape(issuer), url.PathEscape(account), query.Encode())
+}
+
+// Step returns the time step that [t] is in
+func Step(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(Period/time.Second)
+}
+
+// Code returns the code of [secret] at time step [step]
+func Code(secret []byte, step uint64) string {
+	return code(secret, step, Digits)
+}
+
+// Verify returns the time step that [code] was generated at, if it's a code
+// of [secret] at most [skew] time steps away from [t]
+func Verify(secret []byte, code string, t time.Time, skew int) (uint64, bool) {
+	now := Step(t)
+	for i := -skew; i <= skew; i++ {
+		step := now + uint64(i)
+		if i < 0 && now < uint64(-i) {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(Code(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// code implements the HOTP algorithm of RFC 4226
+func code(secret []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, secret)
+	_, _ = mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, value%digitsPower[digits])
+}
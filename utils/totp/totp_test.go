@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors of RFC 6238, appendix B, for SHA1
+func TestCode(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		time int64
+		code string
+	}{
+		{time: 59, code: "94287082"},
+		{time: 1111111109, code: "07081804"},
+		{time: 1111111111, code: "14050471"},
+		{time: 1234567890, code: "89005924"},
+		{time: 2000000000, code: "69279037"},
+		{time: 20000000000, code: "65353130"},
+	}
+	for _, test := range tests {
+		step := Step(time.Unix(test.time, 0))
+		assert.Equal(t, test.code, code(secret, step, 8))
+		assert.Equal(t, test.code[8-Digits:], Code(secret, step))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	secret, err := NewSecret()
+	assert.NoError(err)
+	now := time.Unix(1234567890, 0)
+	step := Step(now)
+
+	verifiedStep, ok := Verify(secret, Code(secret, step), now, 1)
+	assert.True(ok)
+	assert.Equal(step, verifiedStep)
+
+	// Codes of adjacent time steps are accepted to allow for clock skew
+	verifiedStep, ok = Verify(secret, Code(secret, step-1), now, 1)
+	assert.True(ok)
+	assert.Equal(step-1, verifiedStep)
+
+	_, ok = Verify(secret, Code(secret, step+2), now, 1)
+	assert.False(ok)
+
+	otherSecret, err := NewSecret()
+	assert.NoError(err)
+	_, ok = Verify(otherSecret, Code(secret, step), now, 1)
+	assert.False(ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Avalanche", "bob", []byte("12345678901234567890"))
+	assert.Equal(t, "otpauth://totp/Avalanche:bob?issuer=Avalanche&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri)
+}
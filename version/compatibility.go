@@ -36,6 +36,29 @@ type Compatibility interface {
 
 	// Returns when additional masking will occur.
 	MaskTime() time.Time
+
+	// Returns the policy that peer versions are checked against
+	Config() CompatibilityConfig
+}
+
+// CompatibilityConfig is the policy that peer versions are checked against
+type CompatibilityConfig struct {
+	// Peers before [MinCompatible] are incompatible from [MinCompatibleTime].
+	// Until then, peers before [PrevMinCompatible] are incompatible.
+	MinCompatible     Application
+	MinCompatibleTime time.Time
+	PrevMinCompatible Application
+
+	// Peers after [MaxCompatible] are incompatible. If nil, there is no
+	// maximum compatible version.
+	MaxCompatible Application
+
+	// The stake of peers before [MinUnmaskable] is masked from
+	// [MinUnmaskableTime]. Until then, the stake of peers before
+	// [PrevMinUnmaskable] is masked.
+	MinUnmaskable     Application
+	MinUnmaskableTime time.Time
+	PrevMinUnmaskable Application
 }
 
 type compatibility struct {
@@ -45,6 +68,8 @@ type compatibility struct {
 	minCompatableTime time.Time
 	prevMinCompatable Application
 
+	maxCompatible Application
+
 	minUnmaskable     Application
 	minUnmaskableTime time.Time
 	prevMinUnmaskable Application
@@ -62,14 +87,28 @@ func NewCompatibility(
 	minUnmaskableTime time.Time,
 	prevMinUnmaskable Application,
 ) Compatibility {
+	return NewCompatibilityFromConfig(version, CompatibilityConfig{
+		MinCompatible:     minCompatable,
+		MinCompatibleTime: minCompatableTime,
+		PrevMinCompatible: prevMinCompatable,
+		MinUnmaskable:     minUnmaskable,
+		MinUnmaskableTime: minUnmaskableTime,
+		PrevMinUnmaskable: prevMinUnmaskable,
+	})
+}
+
+// NewCompatibilityFromConfig returns a compatibility checker that enforces
+// [config]
+func NewCompatibilityFromConfig(version Application, config CompatibilityConfig) Compatibility {
 	return &compatibility{
 		version:           version,
-		minCompatable:     minCompatable,
-		minCompatableTime: minCompatableTime,
-		prevMinCompatable: prevMinCompatable,
-		minUnmaskable:     minUnmaskable,
-		minUnmaskableTime: minUnmaskableTime,
-		prevMinUnmaskable: prevMinUnmaskable,
+		minCompatable:     config.MinCompatible,
+		minCompatableTime: config.MinCompatibleTime,
+		prevMinCompatable: config.PrevMinCompatible,
+		maxCompatible:     config.MaxCompatible,
+		minUnmaskable:     config.MinUnmaskable,
+		minUnmaskableTime: config.MinUnmaskableTime,
+		prevMinUnmaskable: config.PrevMinUnmaskable,
 	}
 }
 
@@ -80,6 +119,11 @@ func (c *compatibility) Compatible(peer Application) error {
 		return err
 	}
 
+	if c.maxCompatible != nil && c.maxCompatible.Before(peer) {
+		// The peer is after the maximum compatible version.
+		return errIncompatible
+	}
+
 	if !peer.Before(c.minCompatable) {
 		// The peer is at least the minimum compatible version.
 		return nil
@@ -138,3 +182,15 @@ func (c *compatibility) WontMask(peer Application) error {
 func (c *compatibility) MaskTime() time.Time {
 	return c.minUnmaskableTime
 }
+
+func (c *compatibility) Config() CompatibilityConfig {
+	return CompatibilityConfig{
+		MinCompatible:     c.minCompatable,
+		MinCompatibleTime: c.minCompatableTime,
+		PrevMinCompatible: c.prevMinCompatable,
+		MaxCompatible:     c.maxCompatible,
+		MinUnmaskable:     c.minUnmaskable,
+		MinUnmaskableTime: c.minUnmaskableTime,
+		PrevMinUnmaskable: c.prevMinUnmaskable,
+	}
+}
@@ -127,3 +127,30 @@ func TestCompatibility(t *testing.T) {
 		})
 	}
 }
+
+func TestCompatibilityMaxCompatible(t *testing.T) {
+	assert := assert.New(t)
+
+	v := NewDefaultApplication("avalanche", 1, 4, 3)
+	config := CompatibilityConfig{
+		MinCompatible:     NewDefaultApplication("avalanche", 1, 4, 0),
+		MinCompatibleTime: time.Unix(9000, 0),
+		PrevMinCompatible: NewDefaultApplication("avalanche", 1, 3, 0),
+		MaxCompatible:     NewDefaultApplication("avalanche", 1, 5, 0),
+		MinUnmaskable:     NewDefaultApplication("avalanche", 1, 2, 0),
+		MinUnmaskableTime: time.Unix(7000, 0),
+		PrevMinUnmaskable: NewDefaultApplication("avalanche", 1, 1, 0),
+	}
+	c := NewCompatibilityFromConfig(v, config).(*compatibility)
+	c.clock.Set(time.Unix(9500, 0))
+	assert.Equal(config, c.Config())
+
+	assert.NoError(c.Compatible(NewDefaultApplication("avalanche", 1, 5, 0)))
+	assert.Error(c.Compatible(NewDefaultApplication("avalanche", 1, 5, 1)))
+
+	// Without a maximum, later peers are compatible
+	config.MaxCompatible = nil
+	c = NewCompatibilityFromConfig(v, config).(*compatibility)
+	c.clock.Set(time.Unix(9500, 0))
+	assert.NoError(c.Compatible(NewDefaultApplication("avalanche", 1, 5, 1)))
+}
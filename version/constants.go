@@ -4,6 +4,8 @@
 package version
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
@@ -65,8 +67,80 @@ var (
 		constants.FujiID:    time.Date(2021, time.November, 24, 15, 0, 0, 0, time.UTC),
 	}
 	ApricotPhase5DefaultTime = time.Date(2020, time.December, 5, 5, 0, 0, 0, time.UTC)
+
+	// ApricotPhase6 isn't scheduled on the public networks yet
+	ApricotPhase6Times = map[uint32]time.Time{
+		constants.MainnetID: time.Date(10000, time.December, 1, 0, 0, 0, 0, time.UTC),
+		constants.FujiID:    time.Date(10000, time.December, 1, 0, 0, 0, 0, time.UTC),
+	}
+	ApricotPhase6DefaultTime = time.Date(2020, time.December, 5, 5, 0, 0, 0, time.UTC)
+
+	errUnorderedUpgrades = errors.New("upgrades must activate in order")
 )
 
+// UpgradeConfig is when the network upgrades of a network activate
+type UpgradeConfig struct {
+	ApricotPhase0Time time.Time `json:"apricotPhase0Time"`
+	ApricotPhase1Time time.Time `json:"apricotPhase1Time"`
+	ApricotPhase2Time time.Time `json:"apricotPhase2Time"`
+	ApricotPhase3Time time.Time `json:"apricotPhase3Time"`
+	ApricotPhase4Time time.Time `json:"apricotPhase4Time"`
+	// Height of the P-chain that blocks built by the proposervm refer to, at
+	// least, once ApricotPhase4 activates
+	ApricotPhase4MinPChainHeight uint64    `json:"apricotPhase4MinPChainHeight"`
+	ApricotPhase5Time            time.Time `json:"apricotPhase5Time"`
+	ApricotPhase6Time            time.Time `json:"apricotPhase6Time"`
+}
+
+// GetUpgradeConfig returns when the network upgrades of [networkID] activate.
+// Networks other than mainnet and fuji activate them at the default times,
+// unless their nodes are configured otherwise.
+func GetUpgradeConfig(networkID uint32) UpgradeConfig {
+	return UpgradeConfig{
+		ApricotPhase0Time:            GetApricotPhase0Time(networkID),
+		ApricotPhase1Time:            GetApricotPhase1Time(networkID),
+		ApricotPhase2Time:            GetApricotPhase2Time(networkID),
+		ApricotPhase3Time:            GetApricotPhase3Time(networkID),
+		ApricotPhase4Time:            GetApricotPhase4Time(networkID),
+		ApricotPhase4MinPChainHeight: GetApricotPhase4MinPChainHeight(networkID),
+		ApricotPhase5Time:            GetApricotPhase5Time(networkID),
+		ApricotPhase6Time:            GetApricotPhase6Time(networkID),
+	}
+}
+
+// Valid returns an error if the upgrades don't activate in order
+func (c *UpgradeConfig) Valid() error {
+	times := []time.Time{
+		c.ApricotPhase0Time,
+		c.ApricotPhase1Time,
+		c.ApricotPhase2Time,
+		c.ApricotPhase3Time,
+		c.ApricotPhase4Time,
+		c.ApricotPhase5Time,
+		c.ApricotPhase6Time,
+	}
+	for i := 1; i < len(times); i++ {
+		if times[i].Before(times[i-1]) {
+			return fmt.Errorf("%w: ApricotPhase%d activates at %s, before ApricotPhase%d at %s",
+				errUnorderedUpgrades, i, times[i], i-1, times[i-1])
+		}
+	}
+	return nil
+}
+
+// CompatibilityConfig returns the default version policy of a network whose
+// upgrades activate at these times
+func (c *UpgradeConfig) CompatibilityConfig() CompatibilityConfig {
+	return CompatibilityConfig{
+		MinCompatible:     MinimumCompatibleVersion,
+		MinCompatibleTime: c.ApricotPhase5Time,
+		PrevMinCompatible: PrevMinimumCompatibleVersion,
+		MinUnmaskable:     MinimumUnmaskedVersion,
+		MinUnmaskableTime: c.ApricotPhase0Time,
+		PrevMinUnmaskable: PrevMinimumUnmaskedVersion,
+	}
+}
+
 func GetApricotPhase0Time(networkID uint32) time.Time {
 	if upgradeTime, exists := ApricotPhase0Times[networkID]; exists {
 		return upgradeTime
@@ -116,14 +190,19 @@ func GetApricotPhase5Time(networkID uint32) time.Time {
 	return ApricotPhase5DefaultTime
 }
 
+func GetApricotPhase6Time(networkID uint32) time.Time {
+	if upgradeTime, exists := ApricotPhase6Times[networkID]; exists {
+		return upgradeTime
+	}
+	return ApricotPhase6DefaultTime
+}
+
 func GetCompatibility(networkID uint32) Compatibility {
-	return NewCompatibility(
-		CurrentApp,
-		MinimumCompatibleVersion,
-		GetApricotPhase5Time(networkID),
-		PrevMinimumCompatibleVersion,
-		MinimumUnmaskedVersion,
-		GetApricotPhase0Time(networkID),
-		PrevMinimumUnmaskedVersion,
-	)
+	return NewCompatibilityFromConfig(CurrentApp, GetCompatibilityConfig(networkID))
+}
+
+// GetCompatibilityConfig returns the default version policy of [networkID]
+func GetCompatibilityConfig(networkID uint32) CompatibilityConfig {
+	upgradeConfig := GetUpgradeConfig(networkID)
+	return upgradeConfig.CompatibilityConfig()
 }
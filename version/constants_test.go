@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+)
+
+func TestGetUpgradeConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	mainnet := GetUpgradeConfig(constants.MainnetID)
+	assert.Equal(ApricotPhase0Times[constants.MainnetID], mainnet.ApricotPhase0Time)
+	assert.Equal(ApricotPhase4MinPChainHeight[constants.MainnetID], mainnet.ApricotPhase4MinPChainHeight)
+	assert.Equal(ApricotPhase5Times[constants.MainnetID], mainnet.ApricotPhase5Time)
+	assert.Equal(ApricotPhase6Times[constants.MainnetID], mainnet.ApricotPhase6Time)
+	assert.NoError(mainnet.Valid())
+
+	custom := GetUpgradeConfig(12345)
+	assert.Equal(ApricotPhase3DefaultTime, custom.ApricotPhase3Time)
+	assert.Equal(ApricotPhase4DefaultMinPChainHeight, custom.ApricotPhase4MinPChainHeight)
+	assert.NoError(custom.Valid())
+
+	assert.Equal(GetCompatibilityConfig(constants.MainnetID), mainnet.CompatibilityConfig())
+}
+
+func TestUpgradeConfigValid(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	config := UpgradeConfig{
+		ApricotPhase0Time: start,
+		ApricotPhase1Time: start,
+		ApricotPhase2Time: start,
+		ApricotPhase3Time: start.Add(time.Hour),
+		ApricotPhase4Time: start.Add(2 * time.Hour),
+		ApricotPhase5Time: start.Add(3 * time.Hour),
+		ApricotPhase6Time: start.Add(4 * time.Hour),
+	}
+	assert.NoError(config.Valid())
+
+	config.ApricotPhase4Time = start.Add(5 * time.Hour)
+	assert.ErrorIs(config.Valid(), errUnorderedUpgrades)
+}
@@ -89,6 +89,9 @@ func (t *BaseTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiable
 		if assetID := out.AssetID(); !vm.verifyFxUsage(fxIndex, assetID) {
 			return errIncompatibleFx
 		}
+		if err := vm.verifyActivated(out.Out); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -884,7 +884,7 @@ func TestBaseTxSemanticVerifyUnauthorizedFx(t *testing.T) {
 				Fx: fx,
 			},
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -1311,7 +1311,7 @@ func TestBaseTxSemanticVerifyPendingUnauthorizedFx(t *testing.T) {
 				Fx: fx,
 			},
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -1457,7 +1457,7 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 				Fx: fx,
 			},
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,402 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+
+	safemath "github.com/Toinounet21/avalanchego-mod/utils/math"
+)
+
+const (
+	// Default max number of inputs and outputs of each transaction issued by
+	// SendBatch
+	defaultMaxBatchInputsPerTx  = 256
+	defaultMaxBatchOutputsPerTx = 256
+)
+
+var (
+	errFeeBudgetExceeded   = errors.New("fee budget exceeded")
+	errPayoutExceedsLimits = errors.New("payout exceeds transaction limits")
+)
+
+// SendBatchArgs are arguments for passing into SendBatch requests
+type SendBatchArgs struct {
+	// User, password, from addrs, change addr
+	api.JSONSpendHeader
+
+	// The payouts to make. Each payout is made by exactly one of the issued
+	// transactions.
+	Payouts []SendOutput `json:"payouts"`
+
+	// Max number of inputs of each transaction. If 0, defaults to 256.
+	MaxInputsPerTx json.Uint32 `json:"maxInputsPerTx"`
+
+	// Max number of outputs, including change outputs, of each transaction.
+	// If 0, defaults to 256.
+	MaxOutputsPerTx json.Uint32 `json:"maxOutputsPerTx"`
+
+	// Max total fee paid by the issued transactions. If 0, the fee isn't
+	// limited.
+	MaxFee json.Uint64 `json:"maxFee"`
+
+	// Memo field of each transaction
+	Memo string `json:"memo"`
+}
+
+// SettlementTx describes a transaction issued by SendBatch
+type SettlementTx struct {
+	TxID ids.ID `json:"txID"`
+
+	// Indices into [SendBatchArgs.Payouts] of the payouts made by this
+	// transaction
+	Payouts []json.Uint32 `json:"payouts"`
+}
+
+// SendBatchReply is the response from SendBatch
+type SendBatchReply struct {
+	// The issued transactions, in the order they were issued
+	Txs []SettlementTx `json:"txs"`
+
+	// Total fee paid by the issued transactions
+	Fee json.Uint64 `json:"fee"`
+
+	api.JSONChangeAddr
+}
+
+type batchPayout struct {
+	// Index of this payout in [SendBatchArgs.Payouts]
+	index   int
+	assetID ids.ID
+	to      ids.ShortID
+	amount  uint64
+}
+
+// sendBatch makes the payouts in [args] using as few transactions as possible.
+// The payouts are ordered by asset, recipient and amount before they're split
+// into transactions, so the same payouts and UTXOs always result in the same
+// transactions. The transactions are only issued, using [issue], if all of
+// them can be built within the limits in [args]. [update] is applied to the
+// user's UTXOs before they're spent.
+// If a transaction fails to be issued after others were, [reply] describes
+// the transactions that were issued, and the returned *json2.Error carries
+// [reply] as its data so that JSON-RPC callers learn which payouts were made.
+func (vm *VM) sendBatch(
+	args *SendBatchArgs,
+	reply *SendBatchReply,
+	update func([]*avax.UTXO) ([]*avax.UTXO, error),
+	issue func([]byte) (ids.ID, error),
+) error {
+	// Validate the memo field
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > avax.MaxMemoSize {
+		return fmt.Errorf("max memo length is %d but provided memo field is length %d",
+			avax.MaxMemoSize,
+			l)
+	} else if len(args.Payouts) == 0 {
+		return errNoOutputs
+	}
+
+	maxInputs := int(args.MaxInputsPerTx)
+	if maxInputs == 0 {
+		maxInputs = defaultMaxBatchInputsPerTx
+	}
+	maxOutputs := int(args.MaxOutputsPerTx)
+	if maxOutputs == 0 {
+		maxOutputs = defaultMaxBatchOutputsPerTx
+	}
+
+	// Parse the payouts
+	// String repr. of asset ID --> asset ID
+	assetIDs := make(map[string]ids.ID)
+	payouts := make([]batchPayout, len(args.Payouts))
+	for i, payout := range args.Payouts {
+		if payout.Amount == 0 {
+			return errZeroAmount
+		}
+		assetID, ok := assetIDs[payout.AssetID]
+		if !ok {
+			var err error
+			assetID, err = vm.lookupAssetID(payout.AssetID)
+			if err != nil {
+				return fmt.Errorf("couldn't find asset %s", payout.AssetID)
+			}
+			assetIDs[payout.AssetID] = assetID
+		}
+		to, err := vm.ParseLocalAddress(payout.To)
+		if err != nil {
+			return fmt.Errorf("problem parsing to address %q: %w", payout.To, err)
+		}
+		payouts[i] = batchPayout{
+			index:   i,
+			assetID: assetID,
+			to:      to,
+			amount:  uint64(payout.Amount),
+		}
+	}
+	sortBatchPayouts(payouts)
+
+	// Parse the from addresses
+	fromAddrs := ids.NewShortSet(len(args.From))
+	for _, addrStr := range args.From {
+		addr, err := vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+		}
+		fromAddrs.Add(addr)
+	}
+
+	// Load user's UTXOs/keys
+	utxos, kc, err := vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	utxos, err = update(utxos)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(kc.Keys) == 0 {
+		return errNoKeys
+	}
+	keyAddr := kc.Keys[0].PublicKey().Address()
+	changeAddr, err := vm.selectChangeAddr(keyAddr, args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+
+	// Spend the UTXOs in a deterministic order
+	sortUTXOs(utxos)
+
+	// Build all of the transactions before issuing any of them
+	txs := []*Tx(nil)
+	manifest := []SettlementTx(nil)
+	for remaining := payouts; len(remaining) > 0; {
+		tx, numPayouts, err := vm.buildBatchTx(utxos, kc, keyAddr, changeAddr, remaining, maxInputs, maxOutputs, memoBytes)
+		if err != nil {
+			return err
+		}
+
+		settlementTx := SettlementTx{
+			Payouts: make([]json.Uint32, numPayouts),
+		}
+		for i, payout := range remaining[:numPayouts] {
+			settlementTx.Payouts[i] = json.Uint32(payout.index)
+		}
+		txs = append(txs, tx)
+		manifest = append(manifest, settlementTx)
+
+		// Later transactions can't spend the UTXOs consumed by [tx] but can
+		// spend its change
+		utxos = spendTx(utxos, tx)
+		remaining = remaining[numPayouts:]
+	}
+
+	fee, err := safemath.Mul64(uint64(len(txs)), vm.txFee())
+	if err != nil {
+		return fmt.Errorf("problem calculating fee: %w", err)
+	}
+	if args.MaxFee != 0 && fee > uint64(args.MaxFee) {
+		return fmt.Errorf("%w: %d transactions would pay %d but the budget is %d",
+			errFeeBudgetExceeded,
+			len(txs),
+			fee,
+			args.MaxFee,
+		)
+	}
+
+	for i, tx := range txs {
+		txID, err := issue(tx.Bytes())
+		if err != nil {
+			err = fmt.Errorf("problem issuing transaction %d of %d: %w", i+1, len(txs), err)
+			if i == 0 {
+				return err
+			}
+
+			// Report the transactions that were already issued
+			reply.Txs = manifest[:i]
+			reply.Fee = json.Uint64(uint64(i) * vm.txFee())
+			reply.ChangeAddr, _ = vm.FormatLocalAddress(changeAddr)
+			return &json2.Error{
+				Code:    json2.E_SERVER,
+				Message: err.Error(),
+				Data:    reply,
+			}
+		}
+		manifest[i].TxID = txID
+	}
+
+	reply.Txs = manifest
+	reply.Fee = json.Uint64(fee)
+	reply.ChangeAddr, err = vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// decodePartialBatchReply populates [reply] with the transactions that were
+// issued before a SendBatch request failed with [err], if any were
+func decodePartialBatchReply(err error, reply *SendBatchReply) {
+	var jsonErr *json2.Error
+	if !errors.As(err, &jsonErr) || jsonErr.Data == nil {
+		return
+	}
+	// The data was decoded into generic JSON values, so it's re-encoded to be
+	// decoded into [reply]
+	dataBytes, marshalErr := stdjson.Marshal(jsonErr.Data)
+	if marshalErr != nil {
+		return
+	}
+	_ = stdjson.Unmarshal(dataBytes, reply)
+}
+
+// buildBatchTx returns a transaction that makes as many of the first
+// [payouts] as possible without exceeding [maxInputs] inputs and [maxOutputs]
+// outputs, along with the number of payouts it makes.
+// If the transaction makes all of [payouts], its change is sent to
+// [changeAddr]. Otherwise, its change is sent to [keyAddr] so that it can be
+// spent by the following transactions.
+func (vm *VM) buildBatchTx(
+	utxos []*avax.UTXO,
+	kc *secp256k1fx.Keychain,
+	keyAddr ids.ShortID,
+	changeAddr ids.ShortID,
+	payouts []batchPayout,
+	maxInputs int,
+	maxOutputs int,
+	memo []byte,
+) (*Tx, int, error) {
+	// build returns nil if the transaction that makes the first [n] payouts
+	// doesn't fit within the limits
+	build := func(n int) (*Tx, error) {
+		amounts := make(map[ids.ID]uint64)
+		outs := make([]*avax.TransferableOutput, n)
+		for i, payout := range payouts[:n] {
+			amount, err := safemath.Add64(amounts[payout.assetID], payout.amount)
+			if err != nil {
+				return nil, fmt.Errorf("problem calculating required spend amount: %w", err)
+			}
+			amounts[payout.assetID] = amount
+
+			outs[i] = &avax.TransferableOutput{
+				Asset: avax.Asset{ID: payout.assetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: payout.amount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Locktime:  0,
+						Threshold: 1,
+						Addrs:     []ids.ShortID{payout.to},
+					},
+				},
+			}
+		}
+
+		to := keyAddr
+		if n == len(payouts) {
+			to = changeAddr
+		}
+		tx, err := vm.buildSendTx(utxos, kc, to, outs, amounts, memo)
+		if err != nil {
+			return nil, err
+		}
+		baseTx := tx.UnsignedTx.(*BaseTx)
+		if len(baseTx.Ins) > maxInputs || len(baseTx.Outs) > maxOutputs {
+			return nil, nil
+		}
+		return tx, nil
+	}
+
+	// The number of inputs and outputs only grows as more payouts are made,
+	// so search for the most payouts that fit.
+	high := len(payouts)
+	if high > maxOutputs {
+		high = maxOutputs
+	}
+	tx, err := build(high)
+	if err != nil || tx != nil {
+		return tx, high, err
+	}
+
+	var (
+		bestTx  *Tx
+		bestNum int
+		low     = 1
+	)
+	high--
+	for low <= high {
+		mid := (low + high) / 2
+		tx, err := build(mid)
+		if err != nil {
+			return nil, 0, err
+		}
+		if tx == nil {
+			high = mid - 1
+			continue
+		}
+		bestTx = tx
+		bestNum = mid
+		low = mid + 1
+	}
+	if bestTx == nil {
+		return nil, 0, fmt.Errorf("%w: payout %d can't be made with at most %d inputs and %d outputs",
+			errPayoutExceedsLimits,
+			payouts[0].index,
+			maxInputs,
+			maxOutputs,
+		)
+	}
+	return bestTx, bestNum, nil
+}
+
+// spendTx returns [utxos] without the UTXOs consumed by [tx] and with the
+// UTXOs produced by [tx]
+func spendTx(utxos []*avax.UTXO, tx *Tx) []*avax.UTXO {
+	consumed := ids.Set{}
+	for _, inputUTXO := range tx.InputUTXOs() {
+		consumed.Add(inputUTXO.InputID())
+	}
+
+	newUTXOs := make([]*avax.UTXO, 0, len(utxos))
+	for _, utxo := range utxos {
+		if !consumed.Contains(utxo.InputID()) {
+			newUTXOs = append(newUTXOs, utxo)
+		}
+	}
+	return append(newUTXOs, tx.UTXOs()...)
+}
+
+func sortBatchPayouts(payouts []batchPayout) {
+	sort.Slice(payouts, func(i, j int) bool {
+		a, b := payouts[i], payouts[j]
+		if c := bytes.Compare(a.assetID[:], b.assetID[:]); c != 0 {
+			return c < 0
+		}
+		if c := bytes.Compare(a.to[:], b.to[:]); c != 0 {
+			return c < 0
+		}
+		if a.amount != b.amount {
+			return a.amount < b.amount
+		}
+		return a.index < b.index
+	})
+}
+
+func sortUTXOs(utxos []*avax.UTXO) {
+	sort.Slice(utxos, func(i, j int) bool {
+		a, b := utxos[i].InputID(), utxos[j].InputID()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+}
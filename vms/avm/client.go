@@ -34,6 +34,12 @@ type Client interface {
 	ConfirmTx(ctx context.Context, txID ids.ID, numChecks int, checkFreq time.Duration) (choices.Status, error)
 	// GetTx returns the byte representation of [txID]
 	GetTx(ctx context.Context, txID ids.ID) ([]byte, error)
+	// EstimateFee returns the fee of a tx of type [txType]
+	EstimateFee(ctx context.Context, txType string) (*EstimateFeeReply, error)
+	// EstimateTxFee returns the fee of [unsignedTx] and the change it needs
+	EstimateTxFee(ctx context.Context, unsignedTx []byte) (*EstimateFeeReply, error)
+	// GetTxFee returns the fees that the txs built by the node currently burn
+	GetTxFee(ctx context.Context) (*GetTxFeeReply, error)
 	// GetUTXOs returns the byte representation of the UTXOs controlled by [addrs]
 	GetUTXOs(
 		ctx context.Context,
@@ -54,6 +60,19 @@ type Client interface {
 	) ([][]byte, api.Index, error)
 	// GetAssetDescription returns a description of [assetID]
 	GetAssetDescription(ctx context.Context, assetID string) (*GetAssetDescriptionReply, error)
+	// GetNFTGroups returns the IDs of the groups of the NFT asset [assetID]
+	GetNFTGroups(ctx context.Context, assetID string) ([]uint32, error)
+	// GetNFTPayloads returns up to [limit] of the NFTs minted in [groupID] of
+	// [assetID], after the one minted by operation [startOpIndex] of
+	// [startTxID]
+	GetNFTPayloads(
+		ctx context.Context,
+		assetID string,
+		groupID uint32,
+		startTxID ids.ID,
+		startOpIndex uint32,
+		limit uint32,
+	) ([]NFTPayload, error)
 	// GetBalance returns the balance of [assetID] held by [addr].
 	// If [includePartial], balance includes partial owned (i.e. in a multisig) funds.
 	GetBalance(ctx context.Context, addr string, assetID string, includePartial bool) (*GetBalanceReply, error)
@@ -111,6 +130,15 @@ type Client interface {
 	ExportKey(ctx context.Context, user api.UserPass, addr string) (string, error)
 	// ImportKey imports [privateKey] to [user]
 	ImportKey(ctx context.Context, user api.UserPass, privateKey string) (string, error)
+	// ListHDAddresses returns the addresses derived from the HD seed of [user]
+	ListHDAddresses(ctx context.Context, user api.UserPass) ([]HDAddress, error)
+	// DiscoverHDAddresses adds the used HD addresses of [user], stopping after
+	// [gapLimit] consecutive unused addresses. It returns the added addresses.
+	DiscoverHDAddresses(ctx context.Context, user api.UserPass, gapLimit uint32) ([]HDAddress, error)
+	// ExportSeed returns the hex encoded HD seed of [user]
+	ExportSeed(ctx context.Context, user api.UserPass) (string, error)
+	// ImportSeed sets the HD seed of [user] to the hex encoded [seed]
+	ImportSeed(ctx context.Context, user api.UserPass, seed string) error
 	// Mint [amount] of [assetID] to be owned by [to]
 	Mint(
 		ctx context.Context,
@@ -182,6 +210,33 @@ func (c *client) IssueTx(ctx context.Context, txBytes []byte) (ids.ID, error) {
 	return res.TxID, err
 }
 
+func (c *client) EstimateFee(ctx context.Context, txType string) (*EstimateFeeReply, error) {
+	res := &EstimateFeeReply{}
+	err := c.requester.SendRequest(ctx, "estimateFee", &EstimateFeeArgs{
+		TxType: txType,
+	}, res)
+	return res, err
+}
+
+func (c *client) EstimateTxFee(ctx context.Context, unsignedTx []byte) (*EstimateFeeReply, error) {
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	res := &EstimateFeeReply{}
+	err = c.requester.SendRequest(ctx, "estimateFee", &EstimateFeeArgs{
+		UnsignedTx: txStr,
+		Encoding:   formatting.Hex,
+	}, res)
+	return res, err
+}
+
+func (c *client) GetTxFee(ctx context.Context) (*GetTxFeeReply, error) {
+	res := &GetTxFeeReply{}
+	err := c.requester.SendRequest(ctx, "getTxFee", struct{}{}, res)
+	return res, err
+}
+
 func (c *client) GetTxStatus(ctx context.Context, txID ids.ID) (choices.Status, error) {
 	res := &GetTxStatusReply{}
 	err := c.requester.SendRequest(ctx, "getTxStatus", &api.JSONTxID{
@@ -262,6 +317,56 @@ func (c *client) GetAssetDescription(ctx context.Context, assetID string) (*GetA
 	return res, err
 }
 
+func (c *client) GetNFTGroups(ctx context.Context, assetID string) ([]uint32, error) {
+	res := &GetNFTGroupsReply{}
+	err := c.requester.SendRequest(ctx, "getNFTGroups", &GetNFTGroupsArgs{
+		AssetID: assetID,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs := make([]uint32, len(res.GroupIDs))
+	for i, groupID := range res.GroupIDs {
+		groupIDs[i] = uint32(groupID)
+	}
+	return groupIDs, nil
+}
+
+func (c *client) GetNFTPayloads(
+	ctx context.Context,
+	assetID string,
+	groupID uint32,
+	startTxID ids.ID,
+	startOpIndex uint32,
+	limit uint32,
+) ([]NFTPayload, error) {
+	res := &GetNFTPayloadsReply{}
+	err := c.requester.SendRequest(ctx, "getNFTPayloads", &GetNFTPayloadsArgs{
+		AssetID:             assetID,
+		GroupID:             cjson.Uint32(groupID),
+		StartTxID:           startTxID,
+		StartOperationIndex: cjson.Uint32(startOpIndex),
+		Limit:               cjson.Uint32(limit),
+		Encoding:            formatting.Hex,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+	payloads := make([]NFTPayload, len(res.NFTs))
+	for i, nft := range res.NFTs {
+		payloadBytes, err := formatting.Decode(res.Encoding, nft.Payload)
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = NFTPayload{
+			TxID:           nft.TxID,
+			OperationIndex: uint32(nft.OperationIndex),
+			Payload:        payloadBytes,
+		}
+	}
+	return payloads, nil
+}
+
 func (c *client) GetBalance(ctx context.Context, addr string, assetID string, includePartial bool) (*GetBalanceReply, error) {
 	res := &GetBalanceReply{}
 	err := c.requester.SendRequest(ctx, "getBalance", &GetBalanceArgs{
@@ -411,6 +516,34 @@ func (c *client) ImportKey(ctx context.Context, user api.UserPass, privateKey st
 	return res.Address, err
 }
 
+func (c *client) ListHDAddresses(ctx context.Context, user api.UserPass) ([]HDAddress, error) {
+	res := &HDAddressesReply{}
+	err := c.requester.SendRequest(ctx, "listHDAddresses", &user, res)
+	return res.Addresses, err
+}
+
+func (c *client) DiscoverHDAddresses(ctx context.Context, user api.UserPass, gapLimit uint32) ([]HDAddress, error) {
+	res := &HDAddressesReply{}
+	err := c.requester.SendRequest(ctx, "discoverHDAddresses", &DiscoverHDAddressesArgs{
+		UserPass: user,
+		GapLimit: cjson.Uint32(gapLimit),
+	}, res)
+	return res.Addresses, err
+}
+
+func (c *client) ExportSeed(ctx context.Context, user api.UserPass) (string, error) {
+	res := &ExportSeedReply{}
+	err := c.requester.SendRequest(ctx, "exportSeed", &user, res)
+	return res.Seed, err
+}
+
+func (c *client) ImportSeed(ctx context.Context, user api.UserPass, seed string) error {
+	return c.requester.SendRequest(ctx, "importSeed", &ImportSeedArgs{
+		UserPass: user,
+		Seed:     seed,
+	}, &api.SuccessResponse{})
+}
+
 func (c *client) Send(
 	ctx context.Context,
 	user api.UserPass,
@@ -459,6 +592,34 @@ func (c *client) SendMultiple(
 	return res.TxID, err
 }
 
+func (c *client) SendBatch(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	payouts []SendOutput,
+	maxInputsPerTx uint32,
+	maxOutputsPerTx uint32,
+	maxFee uint64,
+	memo string,
+) (*SendBatchReply, error) {
+	res := &SendBatchReply{}
+	err := c.requester.SendRequest(ctx, "sendBatch", &SendBatchArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		Payouts:         payouts,
+		MaxInputsPerTx:  cjson.Uint32(maxInputsPerTx),
+		MaxOutputsPerTx: cjson.Uint32(maxOutputsPerTx),
+		MaxFee:          cjson.Uint64(maxFee),
+		Memo:            memo,
+	}, res)
+	decodePartialBatchReply(err, res)
+	return res, err
+}
+
 func (c *client) Mint(
 	ctx context.Context,
 	user api.UserPass,
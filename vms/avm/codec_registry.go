@@ -100,6 +100,24 @@ func newCustomCodecs(
 			return nil, nil, err
 		}
 	}
+	// Timelocked types are registered after every fx so that the type IDs
+	// assigned by the fxs above are unchanged. A type can only be registered
+	// once, so only the first fx that provides them registers them.
+	for i, fx := range fxs {
+		fx, ok := fx.(timelockedFx)
+		if !ok {
+			continue
+		}
+		vm.codecRegistry = &codecRegistry{
+			codecs:      []codec.Registry{gc, c},
+			index:       i,
+			typeToIndex: vm.typeToFxIndex,
+		}
+		if err := fx.RegisterTimelockedTypes(); err != nil {
+			return nil, nil, err
+		}
+		break
+	}
 	return gcm, cm, nil
 }
 
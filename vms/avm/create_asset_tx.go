@@ -13,6 +13,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
 )
 
 const (
@@ -137,4 +138,16 @@ func (t *CreateAssetTx) SyntacticVerify(
 	return nil
 }
 
+// SemanticVerify that this transaction is valid to be spent.
+func (t *CreateAssetTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiable) error {
+	for _, state := range t.States {
+		for _, out := range state.Outs {
+			if err := vm.verifyActivated(out); err != nil {
+				return err
+			}
+		}
+	}
+	return t.BaseTx.SemanticVerify(vm, tx, creds)
+}
+
 func (t *CreateAssetTx) Sort() { sortInitialStates(t.States) }
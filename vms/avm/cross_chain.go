@@ -0,0 +1,250 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+
+	safemath "github.com/Toinounet21/avalanchego-mod/utils/math"
+)
+
+// maxImportInputs is the maximum number of atomic UTXOs that are imported by
+// each of the txs that ImportFromChain issues
+const maxImportInputs = 256
+
+var errNoAtomicUTXOs = errors.New("no funds to import")
+
+// ExportToChainReply is the response from ExportToChain
+type ExportToChainReply struct {
+	api.JSONTxIDChangeAddr
+
+	// The chain that the funds were exported to
+	DestinationChain ids.ID `json:"destinationChain"`
+	// The UTXOs that the import on the destination chain must consume
+	ExportedUTXOIDs []string `json:"exportedUTXOIDs"`
+}
+
+// ExportToChain sends an asset from this chain to the P/C-Chain, like Export.
+// Unlike Export, the destination chain is checked before any funds are spent
+// and the UTXOs that must then be imported on the destination chain are
+// returned. The destination chain's import must still be issued on that
+// chain, since this chain can't issue txs on other chains.
+func (service *Service) ExportToChain(r *http.Request, args *ExportArgs, reply *ExportToChainReply) error {
+	service.vm.ctx.Log.Debug("AVM: ExportToChain called with username: %s", args.Username)
+
+	chainID, _, err := service.vm.ParseAddress(args.To)
+	if err != nil {
+		return err
+	}
+	if err := verify.SameSubnet(service.vm.ctx, chainID); err != nil {
+		return fmt.Errorf("can't export to chain %s: %w", chainID, err)
+	}
+
+	if err := service.Export(r, args, &reply.JSONTxIDChangeAddr); err != nil {
+		return err
+	}
+
+	tx, err := service.vm.state.GetTx(reply.TxID)
+	if err != nil {
+		return fmt.Errorf("couldn't get issued tx %s: %w", reply.TxID, err)
+	}
+	exportTx, ok := tx.UnsignedTx.(*ExportTx)
+	if !ok {
+		return fmt.Errorf("expected tx %s to be an export tx but got %T", reply.TxID, tx.UnsignedTx)
+	}
+
+	reply.DestinationChain = chainID
+	reply.ExportedUTXOIDs = make([]string, len(exportTx.ExportedOuts))
+	for i := range exportTx.ExportedOuts {
+		utxoID := avax.UTXOID{
+			TxID:        reply.TxID,
+			OutputIndex: uint32(len(exportTx.Outs) + i),
+		}
+		reply.ExportedUTXOIDs[i] = utxoID.String()
+	}
+	return nil
+}
+
+// ImportFromChainReply is the response from ImportFromChain
+type ImportFromChainReply struct {
+	TxIDs []ids.ID `json:"txIDs"`
+}
+
+// ImportFromChain imports all of the funds that were exported from the P/C-Chain
+// to the user's addresses. Unlike Import, which only imports the first page of
+// the user's atomic UTXOs, all of them are found and imported, with as many
+// txs as needed.
+func (service *Service) ImportFromChain(_ *http.Request, args *ImportArgs, reply *ImportFromChainReply) error {
+	service.vm.ctx.Log.Debug("AVM: ImportFromChain called with username: %s", args.Username)
+
+	chainID, err := service.vm.ctx.BCLookup.Lookup(args.SourceChain)
+	if err != nil {
+		return fmt.Errorf("problem parsing chainID %q: %w", args.SourceChain, err)
+	}
+	if err := verify.SameSubnet(service.vm.ctx, chainID); err != nil {
+		return fmt.Errorf("can't import from chain %s: %w", chainID, err)
+	}
+
+	to, err := service.vm.ParseLocalAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	atomicUTXOs, err := service.vm.getAllAtomicUTXOs(chainID, kc.Addrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user's atomic UTXOs: %w", err)
+	}
+	if len(atomicUTXOs) == 0 {
+		return errNoAtomicUTXOs
+	}
+
+	for start := 0; start < len(atomicUTXOs); start += maxImportInputs {
+		end := start + maxImportInputs
+		if end > len(atomicUTXOs) {
+			end = len(atomicUTXOs)
+		}
+		tx, err := service.vm.buildImportTx(chainID, to, atomicUTXOs[start:end], utxos, kc)
+		if err != nil {
+			return err
+		}
+		txID, err := service.vm.IssueTx(tx.Bytes())
+		if err != nil {
+			return fmt.Errorf("problem issuing transaction: %w", err)
+		}
+		reply.TxIDs = append(reply.TxIDs, txID)
+
+		// Local UTXOs that were spent to pay the fee can't be spent by the
+		// next import
+		spent := ids.Set{}
+		for _, in := range tx.UnsignedTx.(*ImportTx).Ins {
+			spent.Add(in.InputID())
+		}
+		unspent := utxos[:0]
+		for _, utxo := range utxos {
+			if !spent.Contains(utxo.InputID()) {
+				unspent = append(unspent, utxo)
+			}
+		}
+		utxos = unspent
+	}
+	return nil
+}
+
+// getAllAtomicUTXOs returns all of the UTXOs that were exported from [chainID]
+// to [addrs]
+func (vm *VM) getAllAtomicUTXOs(chainID ids.ID, addrs ids.ShortSet) ([]*avax.UTXO, error) {
+	seen := ids.Set{}
+	utxos := []*avax.UTXO(nil)
+	startAddr := ids.ShortEmpty
+	startUTXOID := ids.Empty
+	for {
+		page, lastAddr, lastUTXOID, err := vm.GetAtomicUTXOs(chainID, addrs, startAddr, startUTXOID, int(maxPageSize))
+		if err != nil {
+			return nil, err
+		}
+		for _, utxo := range page {
+			// A UTXO owned by multiple addresses may be in multiple pages
+			utxoID := utxo.InputID()
+			if !seen.Contains(utxoID) {
+				seen.Add(utxoID)
+				utxos = append(utxos, utxo)
+			}
+		}
+		if len(page) < int(maxPageSize) {
+			return utxos, nil
+		}
+		startAddr = lastAddr
+		startUTXOID = lastUTXOID
+	}
+}
+
+// buildImportTx returns a signed tx that imports [atomicUTXOs] from [chainID]
+// to [to]. If the imported funds don't cover the fee, it's paid with [utxos].
+func (vm *VM) buildImportTx(
+	chainID ids.ID,
+	to ids.ShortID,
+	atomicUTXOs []*avax.UTXO,
+	utxos []*avax.UTXO,
+	kc *secp256k1fx.Keychain,
+) (*Tx, error) {
+	amountsSpent, importInputs, importKeys, err := vm.SpendAll(atomicUTXOs, kc)
+	if err != nil {
+		return nil, err
+	}
+
+	ins := []*avax.TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+
+	if amountSpent := amountsSpent[vm.feeAssetID]; amountSpent < vm.txFee() {
+		var localAmountsSpent map[ids.ID]uint64
+		localAmountsSpent, ins, keys, err = vm.Spend(
+			utxos,
+			kc,
+			map[ids.ID]uint64{
+				vm.feeAssetID: vm.txFee() - amountSpent,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		for asset, amount := range localAmountsSpent {
+			newAmount, err := safemath.Add64(amountsSpent[asset], amount)
+			if err != nil {
+				return nil, fmt.Errorf("problem calculating required spend amount: %w", err)
+			}
+			amountsSpent[asset] = newAmount
+		}
+	}
+
+	// Because we ensured that we had enough inputs for the fee, we can
+	// safely just remove it without concern for underflow.
+	amountsSpent[vm.feeAssetID] -= vm.txFee()
+
+	keys = append(keys, importKeys...)
+
+	outs := []*avax.TransferableOutput{}
+	for assetID, amount := range amountsSpent {
+		if amount > 0 {
+			outs = append(outs, &avax.TransferableOutput{
+				Asset: avax.Asset{ID: assetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Locktime:  0,
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			})
+		}
+	}
+	avax.SortTransferableOutputs(outs, vm.codec)
+
+	tx := &Tx{UnsignedTx: &ImportTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    vm.ctx.NetworkID,
+			BlockchainID: vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		SourceChain: chainID,
+		ImportedIns: importInputs,
+	}}
+	return tx, tx.SignSECP256K1Fx(vm.codec, keys)
+
+}
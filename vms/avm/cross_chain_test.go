@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/chains/atomic"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+func TestServiceExportToChain(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, genesisTx := setupWithKeys(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	to, err := vm.FormatAddress(platformChainID, keys[1].PublicKey().Address())
+	assert.NoError(err)
+	args := &ExportArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+		},
+		Amount:  100,
+		To:      to,
+		AssetID: genesisTx.ID().String(),
+	}
+	reply := &ExportToChainReply{}
+	assert.NoError(s.ExportToChain(nil, args, reply))
+	assert.Equal(platformChainID, reply.DestinationChain)
+	assert.True(vm.mempool.Has(reply.TxID))
+
+	tx, err := vm.state.GetTx(reply.TxID)
+	assert.NoError(err)
+	exportTx := tx.UnsignedTx.(*ExportTx)
+	assert.Equal([]string{fmt.Sprintf("%s:%d", reply.TxID, len(exportTx.Outs))}, reply.ExportedUTXOIDs)
+
+	// Exporting to this chain would lose the funds
+	args.To, err = vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	assert.NoError(err)
+	assert.Error(s.ExportToChain(nil, args, &ExportToChainReply{}))
+	assert.Equal(1, vm.mempool.Len())
+}
+
+func TestServiceImportFromChain(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, m, genesisTx := setupWithKeys(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(err)
+	args := &ImportArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		SourceChain: "P",
+		To:          addrStr,
+	}
+	err = s.ImportFromChain(nil, args, &ImportFromChainReply{})
+	assert.Equal(errNoAtomicUTXOs, err)
+
+	// Export more UTXOs than fit in one import tx
+	addr0 := keys[0].PublicKey().Address()
+	numUTXOs := maxImportInputs + 1
+	elems := make([]*atomic.Element, numUTXOs)
+	for i := range elems {
+		utxo := &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 2 * testTxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr0},
+				},
+			},
+		}
+		utxoBytes, err := vm.codec.Marshal(codecVersion, utxo)
+		assert.NoError(err)
+		utxoID := utxo.InputID()
+		elems[i] = &atomic.Element{
+			Key:    utxoID[:],
+			Value:  utxoBytes,
+			Traits: [][]byte{addr0.Bytes()},
+		}
+	}
+	peerSharedMemory := m.NewSharedMemory(platformChainID)
+	assert.NoError(peerSharedMemory.Apply(map[ids.ID]*atomic.Requests{
+		vm.ctx.ChainID: {PutRequests: elems},
+	}))
+
+	reply := &ImportFromChainReply{}
+	assert.NoError(s.ImportFromChain(nil, args, reply))
+	assert.Len(reply.TxIDs, 2)
+
+	imported := 0
+	for _, txID := range reply.TxIDs {
+		assert.True(vm.mempool.Has(txID))
+		tx, err := vm.state.GetTx(txID)
+		assert.NoError(err)
+		importTx := tx.UnsignedTx.(*ImportTx)
+		imported += len(importTx.ImportedIns)
+		// The fee is paid by the imported funds
+		assert.Empty(importTx.Ins)
+	}
+	assert.Equal(numUTXOs, imported)
+
+}
@@ -97,6 +97,9 @@ func (t *ExportTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiab
 		if !vm.verifyFxUsage(fxIndex, assetID) {
 			return errIncompatibleFx
 		}
+		if err := vm.verifyActivated(out.Out); err != nil {
+			return err
+		}
 	}
 
 	return t.BaseTx.SemanticVerify(vm, tx, creds)
@@ -1049,7 +1049,7 @@ func TestExportTxSemanticVerifyInvalidFx(t *testing.T) {
 				},
 			},
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -1211,7 +1211,7 @@ func TestIssueExportTx(t *testing.T) {
 			ID: ids.Empty,
 			Fx: &secp256k1fx.Fx{},
 		}},
-		nil,
+		&common.SenderTest{},
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -1348,7 +1348,7 @@ func TestClearForceAcceptedExportTx(t *testing.T) {
 			ID: ids.Empty,
 			Fx: &secp256k1fx.Fx{},
 		}},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
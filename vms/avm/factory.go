@@ -4,6 +4,8 @@
 package avm
 
 import (
+	"time"
+
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 )
@@ -16,6 +18,9 @@ var (
 type Factory struct {
 	TxFee            uint64
 	CreateAssetTxFee uint64
+
+	// Time of the ApricotPhase6 network upgrade
+	ApricotPhase6Time time.Time
 }
 
 func (f *Factory) New(*snow.Context) (interface{}, error) {
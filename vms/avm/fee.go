@@ -0,0 +1,281 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+
+	safemath "github.com/Toinounet21/avalanchego-mod/utils/math"
+)
+
+// Tx types that EstimateFee can be called with
+const (
+	BaseTxType        = "base"
+	CreateAssetTxType = "createAsset"
+	OperationTxType   = "operation"
+	ImportTxType      = "import"
+	ExportTxType      = "export"
+)
+
+var (
+	errNoFeeTemplate   = errors.New("either unsignedTx or txType must be provided")
+	errBothFeeTemplate = errors.New("only one of unsignedTx and txType may be provided")
+	errUnknownTxType   = errors.New("unknown tx type")
+
+	errUnsortedFeeSchedule = errors.New("fee schedule activation times must be strictly increasing")
+)
+
+// FeeConfig is the fees that the txs built by this node burn once
+// [ActivationTime] is reached
+type FeeConfig struct {
+	// Unix time, in seconds, from which these fees apply. 0 means that they
+	// apply from genesis.
+	ActivationTime uint64 `json:"activation-time"`
+	// Fee of every tx that doesn't create an asset
+	TxFee uint64 `json:"tx-fee"`
+	// Fee of txs that create an asset
+	CreateAssetTxFee uint64 `json:"create-asset-tx-fee"`
+}
+
+// verifyFeeSchedule returns an error unless the activation times of
+// [schedule] are strictly increasing
+func verifyFeeSchedule(schedule []FeeConfig) error {
+	for i := 1; i < len(schedule); i++ {
+		if schedule[i].ActivationTime <= schedule[i-1].ActivationTime {
+			return fmt.Errorf("%w: %d follows %d",
+				errUnsortedFeeSchedule,
+				schedule[i].ActivationTime,
+				schedule[i-1].ActivationTime,
+			)
+		}
+	}
+	return nil
+}
+
+// currentFees returns the fees that the txs built by this node burn at its
+// current time. Until the first entry of the fee schedule activates, the fees
+// that this VM was created with apply.
+//
+// Txs are verified against the fees that this VM was created with, which all
+// the nodes of the network agree on, rather than against the schedule, as
+// nodes don't agree on when a scheduled fee activates. The returned fees are
+// never lower than the verified fees, so that the txs built by this node are
+// accepted by every node.
+func (vm *VM) currentFees() FeeConfig {
+	fees := FeeConfig{
+		TxFee:            vm.Factory.TxFee,
+		CreateAssetTxFee: vm.Factory.CreateAssetTxFee,
+	}
+	now := vm.clock.Unix()
+	for _, scheduled := range vm.feeSchedule {
+		if scheduled.ActivationTime > now {
+			break
+		}
+		fees = scheduled
+	}
+	fees.TxFee = safemath.Max64(fees.TxFee, vm.Factory.TxFee)
+	fees.CreateAssetTxFee = safemath.Max64(fees.CreateAssetTxFee, vm.Factory.CreateAssetTxFee)
+	return fees
+}
+
+// txFee returns the fee that the txs built by this node burn if they don't
+// create an asset
+func (vm *VM) txFee() uint64 { return vm.currentFees().TxFee }
+
+// createAssetTxFee returns the fee that the txs built by this node burn if they
+// create an asset
+func (vm *VM) createAssetTxFee() uint64 { return vm.currentFees().CreateAssetTxFee }
+
+// GetTxFeeReply is the response from GetTxFee
+type GetTxFeeReply struct {
+	TxFee            json.Uint64 `json:"txFee"`
+	CreateAssetTxFee json.Uint64 `json:"createAssetTxFee"`
+	// Unix time, in seconds, at which the next scheduled fees activate. Nil if
+	// no fee change is scheduled.
+	NextActivationTime *json.Uint64 `json:"nextActivationTime,omitempty"`
+}
+
+// GetTxFee returns the fees that the txs built by this node currently burn
+func (service *Service) GetTxFee(_ *http.Request, _ *struct{}, reply *GetTxFeeReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetTxFee called")
+
+	fees := service.vm.currentFees()
+	reply.TxFee = json.Uint64(fees.TxFee)
+	reply.CreateAssetTxFee = json.Uint64(fees.CreateAssetTxFee)
+
+	now := service.vm.clock.Unix()
+	for _, scheduled := range service.vm.feeSchedule {
+		if scheduled.ActivationTime > now {
+			next := json.Uint64(scheduled.ActivationTime)
+			reply.NextActivationTime = &next
+			break
+		}
+	}
+	return nil
+}
+
+// EstimateFeeArgs are arguments for EstimateFee. Exactly one of [UnsignedTx]
+// and [TxType] must be provided.
+type EstimateFeeArgs struct {
+	// An unsigned tx to estimate the fee of. Its inputs and outputs are used
+	// to compute the change that it needs.
+	UnsignedTx string              `json:"unsignedTx"`
+	Encoding   formatting.Encoding `json:"encoding"`
+
+	// The type of tx to estimate the fee of, if no unsigned tx is given
+	TxType string `json:"txType"`
+}
+
+// EstimateFeeBalance is how a tx template changes the balance of an asset
+type EstimateFeeBalance struct {
+	AssetID ids.ID `json:"assetID"`
+	// Amount of the asset consumed by the template's inputs
+	Consumed json.Uint64 `json:"consumed"`
+	// Amount of the asset produced by the template's outputs, including the
+	// fee if the asset is the fee asset
+	Required json.Uint64 `json:"required"`
+	// Amount of the asset that must be added to the outputs as change
+	Change json.Uint64 `json:"change"`
+	// Amount of the asset that must be added to the inputs
+	Missing json.Uint64 `json:"missing"`
+}
+
+// EstimateFeeReply is the response from EstimateFee
+type EstimateFeeReply struct {
+	FeeAssetID ids.ID      `json:"feeAssetID"`
+	Fee        json.Uint64 `json:"fee"`
+	// The balance of each asset in the unsigned tx, sorted by asset ID. Empty
+	// if no unsigned tx was given.
+	Balances []EstimateFeeBalance `json:"balances"`
+}
+
+// EstimateFee returns the fee that a tx built by this node burns, using this
+// chain's fee configuration. The fee depends only on the type of the tx, not on how many
+// inputs and outputs it has. If an unsigned tx is given, the change that its
+// outputs must include is also returned.
+func (service *Service) EstimateFee(_ *http.Request, args *EstimateFeeArgs, reply *EstimateFeeReply) error {
+	service.vm.ctx.Log.Debug("AVM: EstimateFee called with txType %q", args.TxType)
+
+	reply.FeeAssetID = service.vm.feeAssetID
+	switch {
+	case args.UnsignedTx == "" && args.TxType == "":
+		return errNoFeeTemplate
+	case args.UnsignedTx != "" && args.TxType != "":
+		return errBothFeeTemplate
+	case args.TxType != "":
+		fee, err := service.vm.feeOfType(args.TxType)
+		reply.Fee = json.Uint64(fee)
+		return err
+	}
+
+	txBytes, err := formatting.Decode(args.Encoding, args.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem decoding unsigned tx: %w", err)
+	}
+	var unsignedTx UnsignedTx
+	if _, err := service.vm.codec.Unmarshal(txBytes, &unsignedTx); err != nil {
+		return fmt.Errorf("problem parsing unsigned tx: %w", err)
+	}
+
+	fee := service.vm.feeOf(unsignedTx)
+	ins, outs := feeInsAndOuts(unsignedTx)
+	consumed := map[ids.ID]uint64{}
+	required := map[ids.ID]uint64{
+		service.vm.feeAssetID: fee,
+	}
+	for _, in := range ins {
+		assetID := in.AssetID()
+		consumed[assetID], err = safemath.Add(consumed[assetID], in.Input().Amount())
+		if err != nil {
+			return err
+		}
+	}
+	for _, out := range outs {
+		assetID := out.AssetID()
+		required[assetID], err = safemath.Add(required[assetID], out.Output().Amount())
+		if err != nil {
+			return err
+		}
+	}
+
+	assetIDs := make([]ids.ID, 0, len(required)+len(consumed))
+	for assetID := range required {
+		assetIDs = append(assetIDs, assetID)
+	}
+	for assetID := range consumed {
+		if _, ok := required[assetID]; !ok {
+			assetIDs = append(assetIDs, assetID)
+		}
+	}
+	ids.SortIDs(assetIDs)
+
+	reply.Fee = json.Uint64(fee)
+	reply.Balances = make([]EstimateFeeBalance, len(assetIDs))
+	for i, assetID := range assetIDs {
+		balance := EstimateFeeBalance{
+			AssetID:  assetID,
+			Consumed: json.Uint64(consumed[assetID]),
+			Required: json.Uint64(required[assetID]),
+		}
+		if balance.Consumed > balance.Required {
+			balance.Change = balance.Consumed - balance.Required
+		} else {
+			balance.Missing = balance.Required - balance.Consumed
+		}
+		reply.Balances[i] = balance
+	}
+	return nil
+}
+
+// feeOf returns the fee that [tx] burns if it's built by this node
+func (vm *VM) feeOf(tx UnsignedTx) uint64 {
+	if _, ok := tx.(*CreateAssetTx); ok {
+		return vm.createAssetTxFee()
+	}
+	return vm.txFee()
+}
+
+// feeOfType returns the fee that a tx of type [txType] burns if it's built by
+// this node
+func (vm *VM) feeOfType(txType string) (uint64, error) {
+	fees := vm.currentFees()
+	switch txType {
+	case CreateAssetTxType:
+		return fees.CreateAssetTxFee, nil
+	case BaseTxType, OperationTxType, ImportTxType, ExportTxType:
+		return fees.TxFee, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", errUnknownTxType, txType)
+	}
+}
+
+// feeInsAndOuts returns the inputs and outputs of [tx] that must balance,
+// after the fee is burned
+func feeInsAndOuts(tx UnsignedTx) ([]*avax.TransferableInput, []*avax.TransferableOutput) {
+	switch tx := tx.(type) {
+	case *ImportTx:
+		ins := make([]*avax.TransferableInput, 0, len(tx.Ins)+len(tx.ImportedIns))
+		ins = append(ins, tx.Ins...)
+		return append(ins, tx.ImportedIns...), tx.Outs
+	case *ExportTx:
+		outs := make([]*avax.TransferableOutput, 0, len(tx.Outs)+len(tx.ExportedOuts))
+		outs = append(outs, tx.Outs...)
+		return tx.Ins, append(outs, tx.ExportedOuts...)
+	case *CreateAssetTx:
+		return tx.Ins, tx.Outs
+	case *OperationTx:
+		return tx.Ins, tx.Outs
+	case *BaseTx:
+		return tx.Ins, tx.Outs
+	default:
+		return nil, nil
+	}
+}
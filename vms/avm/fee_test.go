@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+func TestServiceEstimateFee(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.CreateAssetTxFee = 2 * testTxFee
+
+	reply := &EstimateFeeReply{}
+	assert.NoError(s.EstimateFee(nil, &EstimateFeeArgs{TxType: CreateAssetTxType}, reply))
+	assert.Equal(genesisTx.ID(), reply.FeeAssetID)
+	assert.EqualValues(2*testTxFee, reply.Fee)
+	assert.Empty(reply.Balances)
+
+	err := s.EstimateFee(nil, &EstimateFeeArgs{TxType: "unknown"}, &EstimateFeeReply{})
+	assert.True(errors.Is(err, errUnknownTxType))
+	err = s.EstimateFee(nil, &EstimateFeeArgs{}, &EstimateFeeReply{})
+	assert.True(errors.Is(err, errNoFeeTemplate))
+
+	// A template that spends the genesis UTXO without adding change
+	otherAssetID := ids.GenerateTestID()
+	var unsignedTx UnsignedTx = &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    vm.ctx.NetworkID,
+		BlockchainID: vm.ctx.ChainID,
+		Ins: []*avax.TransferableInput{{
+			UTXOID: avax.UTXOID{TxID: genesisTx.ID(), OutputIndex: 2},
+			Asset:  avax.Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   startBalance,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+		Outs: []*avax.TransferableOutput{
+			{
+				Asset: avax.Asset{ID: genesisTx.ID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 10,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{addrs[0]},
+					},
+				},
+			},
+			{
+				Asset: avax.Asset{ID: otherAssetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 5,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{addrs[0]},
+					},
+				},
+			},
+		},
+	}}
+	unsignedBytes, err := vm.codec.Marshal(codecVersion, &unsignedTx)
+	assert.NoError(err)
+	unsignedStr, err := formatting.EncodeWithChecksum(formatting.Hex, unsignedBytes)
+	assert.NoError(err)
+
+	reply = &EstimateFeeReply{}
+	assert.NoError(s.EstimateFee(nil, &EstimateFeeArgs{
+		UnsignedTx: unsignedStr,
+		Encoding:   formatting.Hex,
+	}, reply))
+	assert.EqualValues(testTxFee, reply.Fee)
+
+	expected := []EstimateFeeBalance{
+		{
+			AssetID:  genesisTx.ID(),
+			Consumed: json.Uint64(startBalance),
+			Required: json.Uint64(10 + testTxFee),
+			Change:   json.Uint64(startBalance - 10 - testTxFee),
+		},
+		{
+			AssetID:  otherAssetID,
+			Required: 5,
+			Missing:  5,
+		},
+	}
+	if expected[1].AssetID.Hex() < expected[0].AssetID.Hex() {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+	assert.Equal(expected, reply.Balances)
+}
+
+func TestServiceGetTxFeeSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.CreateAssetTxFee = 2 * testTxFee
+	vm.feeSchedule = []FeeConfig{
+		{
+			ActivationTime:   100,
+			TxFee:            3 * testTxFee,
+			CreateAssetTxFee: 4 * testTxFee,
+		},
+		{
+			ActivationTime:   200,
+			TxFee:            5 * testTxFee,
+			CreateAssetTxFee: 6 * testTxFee,
+		},
+	}
+
+	// Before the schedule activates, the fees the VM was created with apply
+	vm.clock.Set(time.Unix(99, 0))
+	reply := &GetTxFeeReply{}
+	assert.NoError(s.GetTxFee(nil, nil, reply))
+	assert.EqualValues(testTxFee, reply.TxFee)
+	assert.EqualValues(2*testTxFee, reply.CreateAssetTxFee)
+	assert.NotNil(reply.NextActivationTime)
+	assert.EqualValues(100, *reply.NextActivationTime)
+
+	vm.clock.Set(time.Unix(150, 0))
+	reply = &GetTxFeeReply{}
+	assert.NoError(s.GetTxFee(nil, nil, reply))
+	assert.EqualValues(3*testTxFee, reply.TxFee)
+	assert.EqualValues(4*testTxFee, reply.CreateAssetTxFee)
+	assert.EqualValues(200, *reply.NextActivationTime)
+	fee, err := vm.feeOfType(CreateAssetTxType)
+	assert.NoError(err)
+	assert.EqualValues(4*testTxFee, fee)
+
+	vm.clock.Set(time.Unix(200, 0))
+	reply = &GetTxFeeReply{}
+	assert.NoError(s.GetTxFee(nil, nil, reply))
+	assert.EqualValues(5*testTxFee, reply.TxFee)
+	assert.EqualValues(6*testTxFee, reply.CreateAssetTxFee)
+	assert.Nil(reply.NextActivationTime)
+}
+
+func TestVerifyFeeSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(verifyFeeSchedule(nil))
+	assert.NoError(verifyFeeSchedule([]FeeConfig{
+		{ActivationTime: 0},
+		{ActivationTime: 1},
+	}))
+	err := verifyFeeSchedule([]FeeConfig{
+		{ActivationTime: 1},
+		{ActivationTime: 1},
+	})
+	assert.True(errors.Is(err, errUnsortedFeeSchedule))
+}
+
+func TestFeeScheduleIsNotEnforced(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	// The scheduled fees are burned by the txs built by this node
+	vm.feeSchedule = []FeeConfig{{
+		TxFee:            2 * vm.TxFee,
+		CreateAssetTxFee: 2 * vm.CreateAssetTxFee,
+	}}
+	assert.Equal(2*vm.TxFee, vm.txFee())
+	assert.Equal(2*vm.CreateAssetTxFee, vm.createAssetTxFee())
+
+	// But txs only have to burn the fees of the network
+	_, err := vm.IssueTx(txs[1].Bytes())
+	assert.NoError(err)
+
+	// Scheduled fees below the fees of the network are raised to them
+	vm.feeSchedule = []FeeConfig{{}}
+	assert.Equal(vm.TxFee, vm.txFee())
+	assert.Equal(vm.CreateAssetTxFee, vm.createAssetTxFee())
+}
@@ -6,6 +6,7 @@ package avm
 import (
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/vms/blsfx"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
 	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
@@ -17,6 +18,7 @@ var (
 	_ Fx = &secp256k1fx.Fx{}
 	_ Fx = &nftfx.Fx{}
 	_ Fx = &propertyfx.Fx{}
+	_ Fx = &blsfx.Fx{}
 )
 
 type parsedFx struct {
@@ -49,6 +51,20 @@ type Fx interface {
 	VerifyOperation(tx, op, cred interface{}, utxos []interface{}) error
 }
 
+// batchFx is implemented by feature extensions that can defer the signature
+// checks of a transaction to verify them together.
+type batchFx interface {
+	StartBatch()
+	VerifyBatch() error
+	AbortBatch()
+}
+
+// timelockedFx is implemented by feature extensions that provide timelocked
+// types, which must be registered after every Fx has been initialized.
+type timelockedFx interface {
+	RegisterTimelockedTypes() error
+}
+
 type FxOperation interface {
 	verify.Verifiable
 	snow.ContextInitializable
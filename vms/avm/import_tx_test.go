@@ -258,7 +258,7 @@ func TestIssueImportTx(t *testing.T) {
 			ID: ids.Empty,
 			Fx: &secp256k1fx.Fx{},
 		}},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -431,7 +431,7 @@ func TestForceAcceptImportTx(t *testing.T) {
 			ID: ids.Empty,
 			Fx: &secp256k1fx.Fx{},
 		}},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+)
+
+var (
+	errNoLedger        = errors.New("no ledger is configured")
+	errNotLedgerSigner = errors.New("address isn't held by the ledger")
+)
+
+// SendWithLedgerArgs are arguments for SendWithLedger
+type SendWithLedgerArgs struct {
+	// Addresses to send funds from. Must be held by the Ledger. If empty, any
+	// Ledger address may be used.
+	api.JSONFromAddrs
+
+	// Address to send change to. If empty, change is sent to one of the
+	// addresses that funds are sent from.
+	api.JSONChangeAddr
+
+	// The outputs of the transaction
+	Outputs []SendOutput `json:"outputs"`
+
+	// Memo field
+	Memo string `json:"memo"`
+}
+
+// SendWithLedger sends [args.Outputs] from addresses held by the configured
+// Ledger. Each signature must be approved on the device, so this call blocks
+// until the user has approved or rejected them.
+func (service *Service) SendWithLedger(_ *http.Request, args *SendWithLedgerArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: SendWithLedger called")
+
+	if service.vm.newSigner == nil {
+		return errNoLedger
+	}
+
+	// Validate the memo field
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > avax.MaxMemoSize {
+		return fmt.Errorf("max memo length is %d but provided memo field is length %d",
+			avax.MaxMemoSize,
+			l)
+	} else if len(args.Outputs) == 0 {
+		return errNoOutputs
+	}
+
+	signer, err := service.vm.newSigner()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to the ledger: %w", err)
+	}
+	signerAddrs := signer.Addresses()
+
+	// Parse the from addresses
+	fromAddrs := signerAddrs
+	if len(args.From) > 0 {
+		fromAddrs = ids.NewShortSet(len(args.From))
+		for _, addrStr := range args.From {
+			addr, err := service.vm.ParseLocalAddress(addrStr)
+			if err != nil {
+				return fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+			}
+			if !signerAddrs.Contains(addr) {
+				return fmt.Errorf("%w: %s", errNotLedgerSigner, addrStr)
+			}
+			fromAddrs.Add(addr)
+		}
+	}
+
+	// Parse the change address
+	changeAddr := ids.ShortEmpty
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse change address: %w", err)
+		}
+	}
+
+	baseTx, signers, changeAddr, err := service.vm.buildUnsignedSendTx(fromAddrs, changeAddr, args.Outputs, memoBytes)
+	if err != nil {
+		return err
+	}
+	tx := Tx{UnsignedTx: baseTx}
+	if err := tx.SignWithSigner(service.vm.codec, signer, signers); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+func TestServiceSendWithLedger(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	toAddrStr, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &SendWithLedgerArgs{
+		Outputs: []SendOutput{{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      toAddrStr,
+		}},
+	}
+
+	if err := s.SendWithLedger(nil, args, &api.JSONTxIDChangeAddr{}); !errors.Is(err, errNoLedger) {
+		t.Fatalf("expected %s but got %v", errNoLedger, err)
+	}
+
+	// The keys aren't in the keystore, but are held by the signer
+	vm.newSigner = func() (secp256k1fx.Signer, error) {
+		return secp256k1fx.NewKeychain(keys[0]), nil
+	}
+
+	unknownAddrStr, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args.From = []string{unknownAddrStr}
+	if err := s.SendWithLedger(nil, args, &api.JSONTxIDChangeAddr{}); !errors.Is(err, errNotLedgerSigner) {
+		t.Fatalf("expected %s but got %v", errNotLedgerSigner, err)
+	}
+
+	args.From = nil
+	reply := &api.JSONTxIDChangeAddr{}
+	vm.timer.Cancel()
+	if err := s.SendWithLedger(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.ChangeAddr != addrStr {
+		t.Fatalf("expected change to be sent to %s but got %s", addrStr, reply.ChangeAddr)
+	}
+	if vm.mempool.Len() != 1 {
+		t.Fatalf("expected 1 pending tx but got %d", vm.mempool.Len())
+	} else if txID := vm.mempool.Peek()[0].ID(); txID != reply.TxID {
+		t.Fatalf("expected tx %s to be pending but got %s", reply.TxID, txID)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowstorm"
+	"github.com/Toinounet21/avalanchego-mod/utils/linkedhashmap"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+// maxMempoolSize is the maximum number of bytes allowed in the mempool
+const maxMempoolSize = 64 * units.MiB
+
+var (
+	errDuplicatedTx = errors.New("duplicated transaction")
+	errMempoolFull  = errors.New("mempool is full")
+)
+
+// mempool holds the txs that were issued to this node but haven't been handed
+// to consensus yet. Since every tx currently pays the same fee, txs are handed
+// to consensus in the order they were added.
+type mempool struct {
+	bytesAvailableMetric prometheus.Gauge
+	bytesAvailable       int
+
+	// Tx ID --> *UniqueTx, in the order they were added
+	txs linkedhashmap.LinkedHashmap
+}
+
+func newMempool(namespace string, registerer prometheus.Registerer) (*mempool, error) {
+	bytesAvailableMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "mempool_bytes_available",
+		Help:      "Number of bytes of space currently available in the mempool",
+	})
+	if err := registerer.Register(bytesAvailableMetric); err != nil {
+		return nil, err
+	}
+	bytesAvailableMetric.Set(maxMempoolSize)
+
+	return &mempool{
+		bytesAvailableMetric: bytesAvailableMetric,
+		bytesAvailable:       maxMempoolSize,
+		txs:                  linkedhashmap.New(),
+	}, nil
+}
+
+// Add [tx] to the mempool. Returns an error if the tx is already in the
+// mempool or if there isn't enough space for it.
+func (m *mempool) Add(tx *UniqueTx) error {
+	txID := tx.ID()
+	if m.Has(txID) {
+		return fmt.Errorf("%w: %s", errDuplicatedTx, txID)
+	}
+
+	txSize := len(tx.Bytes())
+	if txSize > m.bytesAvailable {
+		return fmt.Errorf("%w: tx %s of %d bytes doesn't fit in the remaining %d bytes",
+			errMempoolFull,
+			txID,
+			txSize,
+			m.bytesAvailable,
+		)
+	}
+
+	m.txs.Put(txID, tx)
+	m.bytesAvailable -= txSize
+	m.bytesAvailableMetric.Set(float64(m.bytesAvailable))
+	return nil
+}
+
+// Remove the tx [txID] from the mempool, if it's in the mempool
+func (m *mempool) Remove(txID ids.ID) {
+	txIntf, ok := m.txs.Get(txID)
+	if !ok {
+		return
+	}
+	m.txs.Delete(txID)
+	m.bytesAvailable += len(txIntf.(*UniqueTx).Bytes())
+	m.bytesAvailableMetric.Set(float64(m.bytesAvailable))
+}
+
+func (m *mempool) Has(txID ids.ID) bool {
+	_, ok := m.txs.Get(txID)
+	return ok
+}
+
+func (m *mempool) Len() int { return m.txs.Len() }
+
+// Peek returns the txs in the mempool, in the order they should be handed to
+// consensus, without removing them
+func (m *mempool) Peek() []snowstorm.Tx {
+	txs := make([]snowstorm.Tx, 0, m.txs.Len())
+	it := m.txs.NewIterator()
+	for it.Next() {
+		txs = append(txs, it.Value().(*UniqueTx))
+	}
+	return txs
+}
+
+// PopAll removes and returns all of the txs in the mempool, in the order they
+// should be handed to consensus
+func (m *mempool) PopAll() []snowstorm.Tx {
+	txs := m.Peek()
+	m.txs = linkedhashmap.New()
+	m.bytesAvailable = maxMempoolSize
+	m.bytesAvailableMetric.Set(maxMempoolSize)
+	return txs
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/codec"
+	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
+	"github.com/Toinounet21/avalanchego-mod/codec/reflectcodec"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+const (
+	codecVersion   uint16 = 0
+	maxMessageSize        = 512 * units.KiB
+	maxSliceLen           = maxMessageSize
+)
+
+// Codec does serialization and deserialization
+var c codec.Manager
+
+func init() {
+	c = codec.NewManager(maxMessageSize)
+	lc := linearcodec.New(reflectcodec.DefaultTagName, maxSliceLen)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		lc.RegisterType(&Tx{}),
+		c.RegisterCodec(codecVersion, lc),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
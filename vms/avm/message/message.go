@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"errors"
+)
+
+var (
+	_ Message = &Tx{}
+
+	errUnexpectedCodecVersion = errors.New("unexpected codec version")
+)
+
+type Message interface {
+	// initialize should be called whenever a message is built or parsed
+	initialize([]byte)
+
+	// Bytes returns the binary representation of this message
+	//
+	// Bytes should only be called after being initialized
+	Bytes() []byte
+}
+
+type message []byte
+
+func (m *message) initialize(bytes []byte) { *m = bytes }
+func (m *message) Bytes() []byte           { return *m }
+
+// Tx gossips a pending tx to other nodes
+type Tx struct {
+	message
+
+	Tx []byte `serialize:"true"`
+}
+
+func Parse(bytes []byte) (Message, error) {
+	var msg Message
+	version, err := c.Unmarshal(bytes, &msg)
+	if err != nil {
+		return nil, err
+	}
+	if version != codecVersion {
+		return nil, errUnexpectedCodecVersion
+	}
+	msg.initialize(bytes)
+	return msg, nil
+}
+
+func Build(msg Message) ([]byte, error) {
+	bytes, err := c.Marshal(codecVersion, &msg)
+	msg.initialize(bytes)
+	return bytes, err
+}
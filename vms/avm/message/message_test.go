@@ -0,0 +1,42 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/units"
+)
+
+func TestTx(t *testing.T) {
+	assert := assert.New(t)
+
+	tx := utils.RandomBytes(256 * units.KiB)
+	builtMsg := Tx{
+		Tx: tx,
+	}
+	builtMsgBytes, err := Build(&builtMsg)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, builtMsg.Bytes())
+
+	parsedMsgIntf, err := Parse(builtMsgBytes)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, parsedMsgIntf.Bytes())
+
+	parsedMsg, ok := parsedMsgIntf.(*Tx)
+	assert.True(ok)
+
+	assert.Equal(tx, parsedMsg.Tx)
+}
+
+func TestParseGibberish(t *testing.T) {
+	assert := assert.New(t)
+
+	randomBytes := utils.RandomBytes(256 * units.KiB)
+	_, err := Parse(randomBytes)
+	assert.Error(err)
+}
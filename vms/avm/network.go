@@ -0,0 +1,167 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/vms/avm/message"
+)
+
+const (
+	// We allow [recentCacheSize] to be fairly large because we only store
+	// hashes in the cache, not entire transactions.
+	recentCacheSize = 512
+
+	// Gossip from a peer is dropped for [invalidTxTimeout] after the peer
+	// gossips an invalid tx
+	invalidTxTimeout = time.Minute
+	// Max number of peers whose gossip is being dropped that are tracked
+	droppedPeersCacheSize = 256
+)
+
+// network gossips the txs in the mempool to other nodes, so that txs issued
+// to a node that isn't a validator reach the validators
+type network struct {
+	log       logging.Logger
+	appSender common.AppSender
+	vm        *VM
+	// IDs of the txs that were recently gossiped or received through gossip
+	recentTxs *cache.LRU
+	// Node ID --> time.Time until which gossip from the node is dropped
+	droppedPeers *cache.LRU
+}
+
+func newNetwork(appSender common.AppSender, vm *VM) *network {
+	return &network{
+		log:          vm.ctx.Log,
+		appSender:    appSender,
+		vm:           vm,
+		recentTxs:    &cache.LRU{Size: recentCacheSize},
+		droppedPeers: &cache.LRU{Size: droppedPeersCacheSize},
+	}
+}
+
+// This VM doesn't (currently) have any app-specific requests
+func (n *network) AppRequest(nodeID ids.ShortID, requestID uint32, deadline time.Time, request []byte) error {
+	return nil
+}
+
+// This VM doesn't (currently) have any app-specific requests
+func (n *network) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	return nil
+}
+
+// This VM doesn't (currently) have any app-specific requests
+func (n *network) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	return nil
+}
+
+// AppGossip adds the gossiped tx to the mempool, if it's valid and not already
+// known. Invalid messages are dropped rather than reported, since they come
+// from other nodes. The tx is verified before it's written to the database,
+// and the gossip of a node that sends an invalid tx is dropped for a while.
+func (n *network) AppGossip(nodeID ids.ShortID, msgBytes []byte) error {
+	n.log.Debug(
+		"AppGossip message handler called from %s with %d bytes",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		len(msgBytes),
+	)
+
+	if !n.vm.bootstrapped {
+		n.log.Debug("dropping AppGossip message while bootstrapping")
+		return nil
+	}
+
+	if until, dropped := n.droppedPeers.Get(nodeID); dropped {
+		if n.vm.clock.Time().Before(until.(time.Time)) {
+			n.log.Verbo(
+				"dropping AppGossip message from %s, which recently sent an invalid tx",
+				nodeID.PrefixedString(constants.NodeIDPrefix),
+			)
+			return nil
+		}
+		n.droppedPeers.Evict(nodeID)
+	}
+
+	msgIntf, err := message.Parse(msgBytes)
+	if err != nil {
+		n.log.Debug("dropping AppGossip message due to failing to parse message")
+		return nil
+	}
+
+	msg, ok := msgIntf.(*message.Tx)
+	if !ok {
+		n.log.Debug(
+			"dropping unexpected message from %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+		)
+		return nil
+	}
+
+	// A tx's ID is the hash of its bytes, so known txs are ignored without
+	// parsing them
+	txID := hashing.ComputeHash256Array(msg.Tx)
+	if _, has := n.recentTxs.Get(txID); has || n.vm.mempool.Has(txID) {
+		return nil
+	}
+
+	tx, err := n.vm.parseUnpersistedTx(msg.Tx)
+	if err != nil {
+		n.log.Verbo("AppGossip provided invalid tx: %s", err)
+		n.dropPeer(nodeID)
+		return nil
+	}
+	if tx.Status().Decided() {
+		return nil
+	}
+	if err := n.vm.verifyAndIssueTx(tx); err != nil {
+		n.log.Debug("failed to add gossiped tx %s to the mempool: %s", txID, err)
+		if !errors.Is(err, errMempoolFull) {
+			n.dropPeer(nodeID)
+		}
+		return nil
+	}
+	return n.GossipTx(tx)
+}
+
+// dropPeer drops the gossip of [nodeID] for [invalidTxTimeout]
+func (n *network) dropPeer(nodeID ids.ShortID) {
+	n.log.Debug(
+		"dropping AppGossip messages from %s for %s",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		invalidTxTimeout,
+	)
+	n.droppedPeers.Put(nodeID, n.vm.clock.Time().Add(invalidTxTimeout))
+}
+
+// GossipTx sends [tx] to a sample of the other nodes, unless it was recently
+// gossiped
+func (n *network) GossipTx(tx *UniqueTx) error {
+	txID := tx.ID()
+	// Don't gossip a transaction if it has been recently gossiped.
+	if _, has := n.recentTxs.Get(txID); has {
+		return nil
+	}
+	n.recentTxs.Put(txID, nil)
+
+	n.log.Debug("gossiping tx %s", txID)
+
+	msg := &message.Tx{
+		Tx: tx.Bytes(),
+	}
+	msgBytes, err := message.Build(msg)
+	if err != nil {
+		return fmt.Errorf("GossipTx: failed to build Tx message with: %w", err)
+	}
+	return n.appSender.SendAppGossip(msgBytes)
+}
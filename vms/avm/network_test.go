@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/vms/avm/message"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+func TestIssueTxGossips(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	gossiped := [][]byte(nil)
+	vm.network.appSender = &common.SenderTest{
+		SendAppGossipF: func(msgBytes []byte) error {
+			gossiped = append(gossiped, msgBytes)
+			return nil
+		},
+	}
+
+	tx := txs[1]
+	txID, err := vm.IssueTx(tx.Bytes())
+	assert.NoError(err)
+	assert.Equal(tx.ID(), txID)
+	assert.Len(gossiped, 1)
+
+	msgIntf, err := message.Parse(gossiped[0])
+	assert.NoError(err)
+	msg, ok := msgIntf.(*message.Tx)
+	assert.True(ok)
+	assert.Equal(tx.Bytes(), msg.Tx)
+
+	// Issuing the tx again neither duplicates it nor gossips it again
+	_, err = vm.IssueTx(tx.Bytes())
+	assert.NoError(err)
+	assert.Len(gossiped, 1)
+	assert.Equal(1, vm.mempool.Len())
+}
+
+func TestAppGossipAddsToMempool(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	gossiped := 0
+	vm.network.appSender = &common.SenderTest{
+		SendAppGossipF: func([]byte) error {
+			gossiped++
+			return nil
+		},
+	}
+
+	tx := txs[1]
+	msgBytes, err := message.Build(&message.Tx{Tx: tx.Bytes()})
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	assert.NoError(vm.AppGossip(nodeID, msgBytes))
+	assert.True(vm.mempool.Has(tx.ID()))
+	// The tx is gossiped on to the other nodes
+	assert.Equal(1, gossiped)
+
+	// Receiving the tx again is a no-op
+	assert.NoError(vm.AppGossip(nodeID, msgBytes))
+	assert.Equal(1, vm.mempool.Len())
+	assert.Equal(1, gossiped)
+
+	// Invalid messages are dropped
+	assert.NoError(vm.AppGossip(nodeID, []byte{1, 2, 3}))
+	assert.Equal(1, vm.mempool.Len())
+
+	// Txs handed to consensus leave the mempool
+	pending := vm.PendingTxs()
+	assert.Len(pending, 1)
+	assert.Equal(tx.ID(), pending[0].ID())
+	assert.Zero(vm.mempool.Len())
+}
+
+func TestMempoolFull(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	vm.mempool.bytesAvailable = len(txs[1].Bytes()) - 1
+	_, err := vm.IssueTx(txs[1].Bytes())
+	assert.ErrorIs(err, errMempoolFull)
+	assert.Zero(vm.mempool.Len())
+}
+
+func TestAppGossipDropsInvalidTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+	vm.network.appSender = &common.SenderTest{
+		SendAppGossipF: func([]byte) error { return nil },
+	}
+
+	// The tx is well formed, but isn't signed by the owner of its input
+	avaxTx := txs[0]
+	invalidTx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    networkID,
+		BlockchainID: chainID,
+		Ins: []*avax.TransferableInput{{
+			UTXOID: avax.UTXOID{
+				TxID:        avaxTx.ID(),
+				OutputIndex: 2,
+			},
+			Asset: avax.Asset{ID: avaxTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt: startBalance,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		}},
+	}}}
+	assert.NoError(invalidTx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[1]}}))
+	invalidMsgBytes, err := message.Build(&message.Tx{Tx: invalidTx.Bytes()})
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	assert.NoError(vm.AppGossip(nodeID, invalidMsgBytes))
+	assert.Zero(vm.mempool.Len())
+
+	// The invalid tx wasn't written to the database
+	_, err = vm.state.GetTx(invalidTx.ID())
+	assert.ErrorIs(err, database.ErrNotFound)
+	_, err = vm.state.GetStatus(invalidTx.ID())
+	assert.ErrorIs(err, database.ErrNotFound)
+
+	// The gossip of the node that sent the invalid tx is dropped
+	validMsgBytes, err := message.Build(&message.Tx{Tx: txs[1].Bytes()})
+	assert.NoError(err)
+	assert.NoError(vm.AppGossip(nodeID, validMsgBytes))
+	assert.Zero(vm.mempool.Len())
+
+	// Until the timeout passes
+	vm.clock.Set(vm.clock.Time().Add(invalidTxTimeout))
+	assert.NoError(vm.AppGossip(nodeID, validMsgBytes))
+	assert.True(vm.mempool.Has(txs[1].ID()))
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
+)
+
+var (
+	nftGroupPrefix   = []byte("group")
+	nftPayloadPrefix = []byte("payload")
+
+	errNFTIndexDisabled = errors.New("NFT indexing is disabled")
+)
+
+// NFTPayload is the payload of an NFT that was minted by an operation
+type NFTPayload struct {
+	TxID ids.ID
+	// Index of the operation that minted the NFT in the tx
+	OperationIndex uint32
+	Payload        []byte
+}
+
+// nftIndex indexes the groups of each NFT asset and the payloads of the NFTs
+// that were minted in each group. Only the txs that were accepted while the
+// index was enabled are indexed.
+type nftIndex struct {
+	// Key: asset ID || group ID
+	// Value: nil
+	groupDB database.Database
+	// Key: asset ID || group ID || tx ID || operation index
+	// Value: payload
+	payloadDB database.Database
+}
+
+func newNFTIndex(db database.Database) *nftIndex {
+	return &nftIndex{
+		groupDB:   prefixdb.New(nftGroupPrefix, db),
+		payloadDB: prefixdb.New(nftPayloadPrefix, db),
+	}
+}
+
+// Accept indexes the NFT groups created by [tx] and the NFTs it mints
+func (i *nftIndex) Accept(txID ids.ID, tx UnsignedTx) error {
+	switch tx := tx.(type) {
+	case *CreateAssetTx:
+		for _, state := range tx.States {
+			for _, out := range state.Outs {
+				if out, ok := out.(*nftfx.MintOutput); ok {
+					if err := i.groupDB.Put(nftGroupKey(txID, out.GroupID), nil); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	case *OperationTx:
+		for opIndex, op := range tx.Ops {
+			mintOp, ok := op.Op.(*nftfx.MintOperation)
+			if !ok {
+				continue
+			}
+			assetID := op.AssetID()
+			if err := i.groupDB.Put(nftGroupKey(assetID, mintOp.GroupID), nil); err != nil {
+				return err
+			}
+			key := nftPayloadKey(assetID, mintOp.GroupID, txID, uint32(opIndex))
+			if err := i.payloadDB.Put(key, mintOp.Payload); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Groups returns the IDs of the groups of [assetID], in increasing order
+func (i *nftIndex) Groups(assetID ids.ID) ([]uint32, error) {
+	it := i.groupDB.NewIteratorWithPrefix(assetID[:])
+	defer it.Release()
+
+	groupIDs := []uint32(nil)
+	for it.Next() {
+		groupIDs = append(groupIDs, binary.BigEndian.Uint32(it.Key()[len(assetID):]))
+	}
+	return groupIDs, it.Error()
+}
+
+// Payloads returns up to [limit] of the NFTs minted in [groupID] of
+// [assetID], ordered by tx ID and operation index. If [startTxID] isn't empty,
+// only the NFTs after the one minted by operation [startOpIndex] of
+// [startTxID] are returned.
+func (i *nftIndex) Payloads(
+	assetID ids.ID,
+	groupID uint32,
+	startTxID ids.ID,
+	startOpIndex uint32,
+	limit int,
+) ([]NFTPayload, error) {
+	prefix := nftGroupKey(assetID, groupID)
+	startKey := prefix
+	if startTxID != ids.Empty {
+		startKey = nftPayloadKey(assetID, groupID, startTxID, startOpIndex)
+	}
+	it := i.payloadDB.NewIteratorWithStartAndPrefix(startKey, prefix)
+	defer it.Release()
+
+	payloads := []NFTPayload(nil)
+	for len(payloads) < limit && it.Next() {
+		key := it.Key()
+		if startTxID != ids.Empty && bytes.Equal(key, startKey) {
+			continue
+		}
+		payload := NFTPayload{
+			OperationIndex: binary.BigEndian.Uint32(key[len(prefix)+len(startTxID):]),
+			Payload:        utils.CopyBytes(it.Value()),
+		}
+		copy(payload.TxID[:], key[len(prefix):])
+		payloads = append(payloads, payload)
+	}
+	return payloads, it.Error()
+}
+
+// GetNFTGroupsArgs are arguments for GetNFTGroups
+type GetNFTGroupsArgs struct {
+	AssetID string `json:"assetID"`
+}
+
+// GetNFTGroupsReply is the response from GetNFTGroups
+type GetNFTGroupsReply struct {
+	GroupIDs []json.Uint32 `json:"groupIDs"`
+}
+
+// GetNFTGroups returns the IDs of the groups of an NFT asset
+func (service *Service) GetNFTGroups(_ *http.Request, args *GetNFTGroupsArgs, reply *GetNFTGroupsReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetNFTGroups called with %s", args.AssetID)
+
+	if service.vm.nftIndex == nil {
+		return errNFTIndexDisabled
+	}
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	groupIDs, err := service.vm.nftIndex.Groups(assetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get groups of %s: %w", assetID, err)
+	}
+
+	reply.GroupIDs = make([]json.Uint32, len(groupIDs))
+	for i, groupID := range groupIDs {
+		reply.GroupIDs[i] = json.Uint32(groupID)
+	}
+	return nil
+}
+
+// GetNFTPayloadsArgs are arguments for GetNFTPayloads
+type GetNFTPayloadsArgs struct {
+	AssetID string      `json:"assetID"`
+	GroupID json.Uint32 `json:"groupID"`
+	// If provided, only the NFTs after the one minted by this operation are
+	// returned. Pass the last NFT of a page to get the next page.
+	StartTxID           ids.ID              `json:"startTxID"`
+	StartOperationIndex json.Uint32         `json:"startOperationIndex"`
+	Limit               json.Uint32         `json:"limit"`
+	Encoding            formatting.Encoding `json:"encoding"`
+}
+
+// APINFTPayload is an NFT returned by GetNFTPayloads
+type APINFTPayload struct {
+	TxID           ids.ID      `json:"txID"`
+	OperationIndex json.Uint32 `json:"operationIndex"`
+	Payload        string      `json:"payload"`
+}
+
+// GetNFTPayloadsReply is the response from GetNFTPayloads
+type GetNFTPayloadsReply struct {
+	NFTs     []APINFTPayload     `json:"nfts"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetNFTPayloads returns the payloads of the NFTs that were minted in a group
+// of an NFT asset, ordered by the ID of the tx that minted them
+func (service *Service) GetNFTPayloads(_ *http.Request, args *GetNFTPayloadsArgs, reply *GetNFTPayloadsReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetNFTPayloads called with %s and group %d", args.AssetID, args.GroupID)
+
+	if service.vm.nftIndex == nil {
+		return errNFTIndexDisabled
+	}
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || int(maxPageSize) < limit {
+		limit = int(maxPageSize)
+	}
+	payloads, err := service.vm.nftIndex.Payloads(
+		assetID,
+		uint32(args.GroupID),
+		args.StartTxID,
+		uint32(args.StartOperationIndex),
+		limit,
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't get payloads of %s: %w", assetID, err)
+	}
+
+	reply.NFTs = make([]APINFTPayload, len(payloads))
+	for i, payload := range payloads {
+		payloadStr, err := formatting.EncodeWithChecksum(args.Encoding, payload.Payload)
+		if err != nil {
+			return fmt.Errorf("couldn't encode payload as string: %w", err)
+		}
+		reply.NFTs[i] = APINFTPayload{
+			TxID:           payload.TxID,
+			OperationIndex: json.Uint32(payload.OperationIndex),
+			Payload:        payloadStr,
+		}
+	}
+	reply.Encoding = args.Encoding
+	return nil
+}
+
+func nftGroupKey(assetID ids.ID, groupID uint32) []byte {
+	key := make([]byte, len(assetID)+wrappers.IntLen)
+	copy(key, assetID[:])
+	binary.BigEndian.PutUint32(key[len(assetID):], groupID)
+	return key
+}
+
+func nftPayloadKey(assetID ids.ID, groupID uint32, txID ids.ID, opIndex uint32) []byte {
+	groupKey := nftGroupKey(assetID, groupID)
+	key := make([]byte, len(groupKey)+len(txID)+wrappers.IntLen)
+	copy(key, groupKey)
+	copy(key[len(groupKey):], txID[:])
+	binary.BigEndian.PutUint32(key[len(groupKey)+len(txID):], opIndex)
+	return key
+}
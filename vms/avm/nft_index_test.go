@@ -0,0 +1,131 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+// indexTestNFTs indexes an NFT asset with groups 1 and 2, and a tx that mints
+// two NFTs in group 1 and one in group 3. Returns the IDs of the asset and of
+// the mint tx.
+func indexTestNFTs(t *testing.T, i *nftIndex) (ids.ID, ids.ID) {
+	createAssetTx := &CreateAssetTx{
+		Name:   "Team Rocket",
+		Symbol: "TR",
+		States: []*InitialState{{
+			FxIndex: 1,
+			Outs: []verify.State{
+				&nftfx.MintOutput{GroupID: 2},
+				&nftfx.MintOutput{GroupID: 1},
+			},
+		}},
+	}
+	assetID := ids.GenerateTestID()
+	if err := i.Accept(assetID, createAssetTx); err != nil {
+		t.Fatal(err)
+	}
+
+	mint := func(groupID uint32, payload string) *Operation {
+		return &Operation{
+			Asset: avax.Asset{ID: assetID},
+			Op: &nftfx.MintOperation{
+				GroupID: groupID,
+				Payload: []byte(payload),
+				Outputs: []*secp256k1fx.OutputOwners{{}},
+			},
+		}
+	}
+	mintTx := &OperationTx{Ops: []*Operation{
+		mint(1, "hello"),
+		mint(3, "there"),
+		mint(1, "world"),
+	}}
+	mintTxID := ids.GenerateTestID()
+	if err := i.Accept(mintTxID, mintTx); err != nil {
+		t.Fatal(err)
+	}
+	return assetID, mintTxID
+}
+
+func TestNFTIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	i := newNFTIndex(memdb.New())
+	assetID, mintTxID := indexTestNFTs(t, i)
+
+	groupIDs, err := i.Groups(assetID)
+	assert.NoError(err)
+	assert.Equal([]uint32{1, 2, 3}, groupIDs)
+
+	groupIDs, err = i.Groups(ids.GenerateTestID())
+	assert.NoError(err)
+	assert.Empty(groupIDs)
+
+	payloads, err := i.Payloads(assetID, 1, ids.Empty, 0, 1)
+	assert.NoError(err)
+	assert.Equal([]NFTPayload{{
+		TxID:           mintTxID,
+		OperationIndex: 0,
+		Payload:        []byte("hello"),
+	}}, payloads)
+
+	payloads, err = i.Payloads(assetID, 1, mintTxID, 0, 10)
+	assert.NoError(err)
+	assert.Equal([]NFTPayload{{
+		TxID:           mintTxID,
+		OperationIndex: 2,
+		Payload:        []byte("world"),
+	}}, payloads)
+
+	payloads, err = i.Payloads(assetID, 2, ids.Empty, 0, 10)
+	assert.NoError(err)
+	assert.Empty(payloads)
+}
+
+func TestServiceGetNFTs(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	err := s.GetNFTGroups(nil, &GetNFTGroupsArgs{AssetID: ids.Empty.String()}, &GetNFTGroupsReply{})
+	assert.Equal(errNFTIndexDisabled, err)
+
+	vm.nftIndex = newNFTIndex(memdb.New())
+	assetID, mintTxID := indexTestNFTs(t, vm.nftIndex)
+
+	groupsReply := &GetNFTGroupsReply{}
+	assert.NoError(s.GetNFTGroups(nil, &GetNFTGroupsArgs{AssetID: assetID.String()}, groupsReply))
+	assert.Equal([]json.Uint32{1, 2, 3}, groupsReply.GroupIDs)
+
+	payloadsReply := &GetNFTPayloadsReply{}
+	assert.NoError(s.GetNFTPayloads(nil, &GetNFTPayloadsArgs{
+		AssetID:  assetID.String(),
+		GroupID:  3,
+		Encoding: formatting.Hex,
+	}, payloadsReply))
+	payloadStr, err := formatting.EncodeWithChecksum(formatting.Hex, []byte("there"))
+	assert.NoError(err)
+	assert.Equal([]APINFTPayload{{
+		TxID:           mintTxID,
+		OperationIndex: 1,
+		Payload:        payloadStr,
+	}}, payloadsReply.NFTs)
+	assert.Equal(formatting.Hex, payloadsReply.Encoding)
+}
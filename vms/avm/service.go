@@ -9,8 +9,10 @@ import (
 	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/database"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow/choices"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
@@ -18,6 +20,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
 	"github.com/Toinounet21/avalanchego-mod/utils/json"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/index"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/keystore"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
 	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
@@ -46,6 +49,7 @@ var (
 	errAddressesCantMintAsset = errors.New("provided addresses don't have the authority to mint the provided asset")
 	errInvalidUTXO            = errors.New("invalid utxo")
 	errNilTxID                = errors.New("nil transaction ID")
+	errPrunedTx               = errors.New("transaction was pruned")
 	errNoAddresses            = errors.New("no addresses provided")
 	errNoKeys                 = errors.New("from addresses have no keys or funds")
 )
@@ -88,10 +92,44 @@ type GetAddressTxsArgs struct {
 	PageSize json.Uint64 `json:"pageSize"`
 	// AssetID defaulted to AVAX if omitted or left blank
 	AssetID string `json:"assetID"`
+	// If true, the reply describes how each transaction changed the address'
+	// balances
+	IncludeDetails bool `json:"includeDetails"`
+}
+
+// Directions in which a transaction can move an asset, relative to an address
+const (
+	directionSent     = "sent"
+	directionReceived = "received"
+	// The address sent as much of the asset as it received
+	directionSelf = "self"
+)
+
+// AddressTxAssetChange is how a transaction changed an address' balance of an
+// asset
+type AddressTxAssetChange struct {
+	AssetID   ids.ID `json:"assetID"`
+	Direction string `json:"direction"`
+	// Amount of the asset in the address' UTXOs that the transaction consumed
+	Sent json.Uint64 `json:"sent"`
+	// Amount of the asset that the transaction gave to the address
+	Received json.Uint64 `json:"received"`
+}
+
+// AddressTx describes how an accepted transaction changed an address' balances
+type AddressTx struct {
+	TxID ids.ID `json:"txID"`
+	// When this node accepted the transaction
+	Timestamp time.Time              `json:"timestamp"`
+	Changes   []AddressTxAssetChange `json:"changes"`
 }
 
 type GetAddressTxsReply struct {
 	TxIDs []ids.ID `json:"txIDs"`
+	// If details were requested, how each of [TxIDs] changed the address'
+	// balances. Transactions that were indexed before details were recorded
+	// are omitted.
+	Txs []AddressTx `json:"txs,omitempty"`
 	// Cursor used as a page index / offset
 	Cursor json.Uint64 `json:"cursor"`
 }
@@ -128,6 +166,20 @@ func (service *Service) GetAddressTxs(r *http.Request, args *GetAddressTxsArgs,
 	}
 	service.vm.ctx.Log.Debug("Fetched %d transactions for address %s, assetID %s, cursor %d", len(reply.TxIDs), address, assetID, cursor)
 
+	if args.IncludeDetails {
+		reply.Txs = make([]AddressTx, 0, len(reply.TxIDs))
+		for _, txID := range reply.TxIDs {
+			details, err := service.vm.addressTxsIndexer.ReadDetails(address[:], txID)
+			if err == database.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("couldn't read details of tx %s: %w", txID, err)
+			}
+			reply.Txs = append(reply.Txs, newAddressTx(details))
+		}
+	}
+
 	// To get the next set of tx IDs, the user should provide this cursor.
 	// e.g. if they provided cursor 5, and read 6 tx IDs, they should start
 	// next time from index (cursor) 11.
@@ -135,6 +187,30 @@ func (service *Service) GetAddressTxs(r *http.Request, args *GetAddressTxsArgs,
 	return nil
 }
 
+func newAddressTx(details *index.TxDetails) AddressTx {
+	tx := AddressTx{
+		TxID:      details.TxID,
+		Timestamp: details.Timestamp,
+		Changes:   make([]AddressTxAssetChange, len(details.Changes)),
+	}
+	for i, change := range details.Changes {
+		direction := directionSelf
+		switch {
+		case change.Sent > change.Received:
+			direction = directionSent
+		case change.Sent < change.Received:
+			direction = directionReceived
+		}
+		tx.Changes[i] = AddressTxAssetChange{
+			AssetID:   change.AssetID,
+			Direction: direction,
+			Sent:      json.Uint64(change.Sent),
+			Received:  json.Uint64(change.Received),
+		}
+	}
+	return tx
+}
+
 // GetTxStatus returns the status of the specified transaction
 func (service *Service) GetTxStatus(r *http.Request, args *api.JSONTxID, reply *GetTxStatusReply) error {
 	service.vm.ctx.Log.Debug("AVM: GetTxStatus called with %s", args.TxID)
@@ -167,6 +243,9 @@ func (service *Service) GetTx(r *http.Request, args *api.GetTxArgs, reply *api.G
 	if status := tx.Status(); !status.Fetched() {
 		return errUnknownTx
 	}
+	if tx.Tx == nil {
+		return errPrunedTx
+	}
 
 	reply.Encoding = args.Encoding
 
@@ -234,6 +313,7 @@ func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply
 	}
 	if sourceChain == service.vm.ctx.ChainID {
 		utxos, endAddr, endUTXOID, err = avax.GetPaginatedUTXOs(
+			r.Context(),
 			service.vm.state,
 			addrSet,
 			startAddr,
@@ -306,6 +386,10 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	if status := tx.Status(); !status.Fetched() {
 		return errUnknownAssetID
 	}
+	if tx.Tx == nil {
+		// Only txs that don't create assets are pruned
+		return errTxNotCreateAsset
+	}
 	createAssetTx, ok := tx.UnsignedTx.(*CreateAssetTx)
 	if !ok {
 		return errTxNotCreateAsset
@@ -415,9 +499,16 @@ func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs
 	addrSet := ids.ShortSet{}
 	addrSet.Add(address)
 
-	utxos, err := avax.GetAllUTXOs(service.vm.state, addrSet)
+	reply.Balances, err = service.getBalances(addrSet, args.IncludePartial)
+	return err
+}
+
+// getBalances returns the balance of each asset that [addrs] hold a non-zero
+// amount of. [includePartial] is handled as in GetAllBalances.
+func (service *Service) getBalances(addrs ids.ShortSet, includePartial bool) ([]Balance, error) {
+	utxos, err := avax.GetAllUTXOs(service.vm.state, addrs)
 	if err != nil {
-		return fmt.Errorf("couldn't get address's UTXOs: %w", err)
+		return nil, fmt.Errorf("couldn't get address's UTXOs: %w", err)
 	}
 
 	now := service.vm.clock.Unix()
@@ -430,7 +521,7 @@ func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs
 			continue
 		}
 		owners := transferable.OutputOwners
-		if !args.IncludePartial && (len(owners.Addrs) != 1 || owners.Locktime > now) {
+		if !includePartial && (len(owners.Addrs) != 1 || owners.Locktime > now) {
 			continue
 		}
 		assetID := utxo.AssetID()
@@ -444,24 +535,23 @@ func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs
 		}
 	}
 
-	reply.Balances = make([]Balance, assetIDs.Len())
+	balancesList := make([]Balance, assetIDs.Len())
 	i := 0
 	for assetID := range assetIDs {
 		if alias, err := service.vm.PrimaryAlias(assetID); err == nil {
-			reply.Balances[i] = Balance{
+			balancesList[i] = Balance{
 				AssetID: alias,
 				Balance: json.Uint64(balances[assetID]),
 			}
 		} else {
-			reply.Balances[i] = Balance{
+			balancesList[i] = Balance{
 				AssetID: assetID.String(),
 				Balance: json.Uint64(balances[assetID]),
 			}
 		}
 		i++
 	}
-
-	return nil
+	return balancesList, nil
 }
 
 // Holder describes how much an address owns of an asset
@@ -530,7 +620,7 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 		utxos,
 		kc,
 		map[ids.ID]uint64{
-			service.vm.feeAssetID: service.vm.CreateAssetTxFee,
+			service.vm.feeAssetID: service.vm.createAssetTxFee(),
 		},
 	)
 	if err != nil {
@@ -538,11 +628,11 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.CreateAssetTxFee {
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.createAssetTxFee() {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.feeAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.CreateAssetTxFee,
+				Amt: amountSpent - service.vm.createAssetTxFee(),
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -681,7 +771,7 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 		utxos,
 		kc,
 		map[ids.ID]uint64{
-			service.vm.feeAssetID: service.vm.CreateAssetTxFee,
+			service.vm.feeAssetID: service.vm.createAssetTxFee(),
 		},
 	)
 	if err != nil {
@@ -689,11 +779,11 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.CreateAssetTxFee {
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.createAssetTxFee() {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.feeAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.CreateAssetTxFee,
+				Amt: amountSpent - service.vm.createAssetTxFee(),
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -752,7 +842,7 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 	return err
 }
 
-// CreateAddress creates an address for the user [args.Username]
+// CreateAddress derives the next HD address for the user [args.Username]
 func (service *Service) CreateAddress(r *http.Request, args *api.UserPass, reply *api.JSONAddress) error {
 	service.vm.ctx.Log.Debug("AVM: CreateAddress called for user '%s'", args.Username)
 
@@ -762,7 +852,7 @@ func (service *Service) CreateAddress(r *http.Request, args *api.UserPass, reply
 	}
 	defer user.Close()
 
-	sk, err := keystore.NewKey(user)
+	sk, err := keystore.NewHDKey(user)
 	if err != nil {
 		return err
 	}
@@ -808,6 +898,161 @@ func (service *Service) ListAddresses(_ *http.Request, args *api.UserPass, respo
 	return user.Close()
 }
 
+// HDAddress is an address derived from a keystore user's HD seed
+type HDAddress struct {
+	Index   json.Uint32 `json:"index"`
+	Path    string      `json:"path"`
+	Address string      `json:"address"`
+}
+
+// HDAddressesReply is the response for ListHDAddresses and DiscoverHDAddresses
+type HDAddressesReply struct {
+	Addresses []HDAddress `json:"addresses"`
+}
+
+// ListHDAddresses returns the addresses derived from the HD seed of user
+// [args.Username]
+func (service *Service) ListHDAddresses(_ *http.Request, args *api.UserPass, reply *HDAddressesReply) error {
+	service.vm.ctx.Log.Debug("AVM: ListHDAddresses called for user %q", args.Username)
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	keys, err := keystore.GetHDKeys(user)
+	if err != nil {
+		// Drop any potential error closing the database to report the original
+		// error
+		_ = user.Close()
+		return fmt.Errorf("problem retrieving HD keys: %w", err)
+	}
+
+	reply.Addresses, err = service.formatHDAddresses(keys)
+	if err != nil {
+		_ = user.Close()
+		return err
+	}
+	return user.Close()
+}
+
+// DiscoverHDAddressesArgs are arguments for DiscoverHDAddresses
+type DiscoverHDAddressesArgs struct {
+	api.UserPass
+	// Number of consecutive unused addresses after which discovery stops.
+	// Defaults to [keystore.DefaultGapLimit].
+	GapLimit json.Uint32 `json:"gapLimit"`
+}
+
+// DiscoverHDAddresses scans the HD addresses of user [args.Username] for
+// addresses that own UTXOs, and adds every address up to the last used one to
+// the user. The newly added addresses are returned.
+func (service *Service) DiscoverHDAddresses(_ *http.Request, args *DiscoverHDAddressesArgs, reply *HDAddressesReply) error {
+	service.vm.ctx.Log.Debug("AVM: DiscoverHDAddresses called for user %q", args.Username)
+
+	gapLimit := uint32(args.GapLimit)
+	if gapLimit == 0 {
+		gapLimit = keystore.DefaultGapLimit
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	keys, err := keystore.DiscoverHDKeys(user, gapLimit, func(addr ids.ShortID) (bool, error) {
+		utxoIDs, err := service.vm.state.UTXOIDs(addr.Bytes(), ids.Empty, 1)
+		return len(utxoIDs) > 0, err
+	})
+	if err != nil {
+		_ = user.Close()
+		return fmt.Errorf("problem discovering HD keys: %w", err)
+	}
+
+	reply.Addresses, err = service.formatHDAddresses(keys)
+	if err != nil {
+		_ = user.Close()
+		return err
+	}
+	return user.Close()
+}
+
+func (service *Service) formatHDAddresses(keys []keystore.HDKey) ([]HDAddress, error) {
+	addresses := make([]HDAddress, len(keys))
+	for i, key := range keys {
+		addr, err := service.vm.FormatLocalAddress(key.Key.PublicKey().Address())
+		if err != nil {
+			return nil, fmt.Errorf("problem formatting address: %w", err)
+		}
+		addresses[i] = HDAddress{
+			Index:   json.Uint32(key.Index),
+			Path:    crypto.FormatDerivationPath(crypto.AvalancheDerivationPath(0, 0, key.Index)),
+			Address: addr,
+		}
+	}
+	return addresses, nil
+}
+
+// ExportSeedReply is the response for ExportSeed
+type ExportSeedReply struct {
+	// The decrypted HD seed of the user, hex encoded
+	Seed string `json:"seed"`
+}
+
+// ExportSeed returns the HD seed of the provided user
+func (service *Service) ExportSeed(_ *http.Request, args *api.UserPass, reply *ExportSeedReply) error {
+	service.vm.ctx.Log.Debug("AVM: ExportSeed called for user %q", args.Username)
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	seed, err := user.GetSeed()
+	if err != nil {
+		_ = user.Close()
+		return fmt.Errorf("problem retrieving HD seed: %w", err)
+	}
+
+	reply.Seed, err = formatting.EncodeWithChecksum(formatting.Hex, seed)
+	if err != nil {
+		_ = user.Close()
+		return fmt.Errorf("problem encoding HD seed: %w", err)
+	}
+	return user.Close()
+}
+
+// ImportSeedArgs are arguments for ImportSeed
+type ImportSeedArgs struct {
+	api.UserPass
+	// The HD seed to import, hex encoded
+	Seed string `json:"seed"`
+}
+
+// ImportSeed sets the HD seed of the provided user. Addresses derived from the
+// seed can then be recovered with DiscoverHDAddresses.
+func (service *Service) ImportSeed(_ *http.Request, args *ImportSeedArgs, reply *api.SuccessResponse) error {
+	service.vm.ctx.Log.Debug("AVM: ImportSeed called for user %q", args.Username)
+
+	seed, err := formatting.Decode(formatting.Hex, args.Seed)
+	if err != nil {
+		return fmt.Errorf("problem parsing HD seed: %w", err)
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := user.PutSeed(seed); err != nil {
+		_ = user.Close()
+		return fmt.Errorf("problem saving HD seed: %w", err)
+	}
+
+	reply.Success = true
+	return user.Close()
+}
+
 // ExportKeyArgs are arguments for ExportKey
 type ExportKeyArgs struct {
 	api.UserPass
@@ -1030,7 +1275,7 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		amountsWithFee[assetID] = amount
 	}
 
-	amountWithFee, err := safemath.Add64(amounts[service.vm.feeAssetID], service.vm.TxFee)
+	amountWithFee, err := safemath.Add64(amounts[service.vm.feeAssetID], service.vm.txFee())
 	if err != nil {
 		return fmt.Errorf("problem calculating required spend amount: %w", err)
 	}
@@ -1086,6 +1331,14 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	return err
 }
 
+// SendBatch makes the provided payouts using as few transactions as possible
+// and returns the issued transactions.
+func (service *Service) SendBatch(_ *http.Request, args *SendBatchArgs, reply *SendBatchReply) error {
+	service.vm.ctx.Log.Debug("AVM: SendBatch called with username: %s", args.Username)
+	noUpdate := func(utxos []*avax.UTXO) ([]*avax.UTXO, error) { return utxos, nil }
+	return service.vm.sendBatch(args, reply, noUpdate, service.vm.IssueTx)
+}
+
 // MintArgs are arguments for passing into Mint requests
 type MintArgs struct {
 	api.JSONSpendHeader             // User, password, from addrs, change addr
@@ -1137,7 +1390,7 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 		feeUTXOs,
 		feeKc,
 		map[ids.ID]uint64{
-			service.vm.feeAssetID: service.vm.TxFee,
+			service.vm.feeAssetID: service.vm.txFee(),
 		},
 	)
 	if err != nil {
@@ -1145,11 +1398,11 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.txFee() {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.feeAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.TxFee,
+				Amt: amountSpent - service.vm.txFee(),
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1250,7 +1503,7 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 		utxos,
 		kc,
 		map[ids.ID]uint64{
-			service.vm.feeAssetID: service.vm.TxFee,
+			service.vm.feeAssetID: service.vm.txFee(),
 		},
 	)
 	if err != nil {
@@ -1258,11 +1511,11 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.txFee() {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.feeAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.TxFee,
+				Amt: amountSpent - service.vm.txFee(),
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1362,7 +1615,7 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 		feeUTXOs,
 		feeKc,
 		map[ids.ID]uint64{
-			service.vm.feeAssetID: service.vm.TxFee,
+			service.vm.feeAssetID: service.vm.txFee(),
 		},
 	)
 	if err != nil {
@@ -1370,11 +1623,11 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.txFee() {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.feeAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.TxFee,
+				Amt: amountSpent - service.vm.txFee(),
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1465,73 +1718,11 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 		return fmt.Errorf("problem retrieving user's atomic UTXOs: %w", err)
 	}
 
-	amountsSpent, importInputs, importKeys, err := service.vm.SpendAll(atomicUTXOs, kc)
+	tx, err := service.vm.buildImportTx(chainID, to, atomicUTXOs, utxos, kc)
 	if err != nil {
 		return err
 	}
 
-	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
-
-	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent < service.vm.TxFee {
-		var localAmountsSpent map[ids.ID]uint64
-		localAmountsSpent, ins, keys, err = service.vm.Spend(
-			utxos,
-			kc,
-			map[ids.ID]uint64{
-				service.vm.feeAssetID: service.vm.TxFee - amountSpent,
-			},
-		)
-		if err != nil {
-			return err
-		}
-		for asset, amount := range localAmountsSpent {
-			newAmount, err := safemath.Add64(amountsSpent[asset], amount)
-			if err != nil {
-				return fmt.Errorf("problem calculating required spend amount: %w", err)
-			}
-			amountsSpent[asset] = newAmount
-		}
-	}
-
-	// Because we ensured that we had enough inputs for the fee, we can
-	// safely just remove it without concern for underflow.
-	amountsSpent[service.vm.feeAssetID] -= service.vm.TxFee
-
-	keys = append(keys, importKeys...)
-
-	outs := []*avax.TransferableOutput{}
-	for assetID, amount := range amountsSpent {
-		if amount > 0 {
-			outs = append(outs, &avax.TransferableOutput{
-				Asset: avax.Asset{ID: assetID},
-				Out: &secp256k1fx.TransferOutput{
-					Amt: amount,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Locktime:  0,
-						Threshold: 1,
-						Addrs:     []ids.ShortID{to},
-					},
-				},
-			})
-		}
-	}
-	avax.SortTransferableOutputs(outs, service.vm.codec)
-
-	tx := Tx{UnsignedTx: &ImportTx{
-		BaseTx: BaseTx{BaseTx: avax.BaseTx{
-			NetworkID:    service.vm.ctx.NetworkID,
-			BlockchainID: service.vm.ctx.ChainID,
-			Outs:         outs,
-			Ins:          ins,
-		}},
-		SourceChain: chainID,
-		ImportedIns: importInputs,
-	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
-		return err
-	}
-
 	txID, err := service.vm.IssueTx(tx.Bytes())
 	if err != nil {
 		return fmt.Errorf("problem issuing transaction: %w", err)
@@ -1599,13 +1790,13 @@ func (service *Service) Export(_ *http.Request, args *ExportArgs, reply *api.JSO
 
 	amounts := map[ids.ID]uint64{}
 	if assetID == service.vm.feeAssetID {
-		amountWithFee, err := safemath.Add64(uint64(args.Amount), service.vm.TxFee)
+		amountWithFee, err := safemath.Add64(uint64(args.Amount), service.vm.txFee())
 		if err != nil {
 			return fmt.Errorf("problem calculating required spend amount: %w", err)
 		}
 		amounts[service.vm.feeAssetID] = amountWithFee
 	} else {
-		amounts[service.vm.feeAssetID] = service.vm.TxFee
+		amounts[service.vm.feeAssetID] = service.vm.txFee()
 		amounts[assetID] = uint64(args.Amount)
 	}
 
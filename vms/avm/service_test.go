@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -465,6 +466,75 @@ func TestServiceGetTxs(t *testing.T) {
 	assert.Equal(t, getTxsReply.TxIDs, testTxs[10:20])
 }
 
+func TestServiceGetTxsDetails(t *testing.T) {
+	_, vm, s, _, _ := setup(t, true)
+	var err error
+	vm.addressTxsIndexer, err = index.NewIndexer(vm.db, vm.ctx.Log, "", prometheus.NewRegistry(), false)
+	assert.NoError(t, err)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	assetID := ids.GenerateTestID()
+	sender := ids.GenerateTestShortID()
+	recipient := ids.GenerateTestShortID()
+	newUTXO := func(amount uint64, addr ids.ShortID) *avax.UTXO {
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			},
+		}
+	}
+
+	// [sender] sends 700 to [recipient] and 300 back to itself
+	txID := ids.GenerateTestID()
+	err = vm.addressTxsIndexer.Accept(
+		txID,
+		[]*avax.UTXO{newUTXO(1000, sender)},
+		[]*avax.UTXO{newUTXO(700, recipient), newUTXO(300, sender)},
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		addr           ids.ShortID
+		direction      string
+		sent, received uint64
+	}{
+		{addr: sender, direction: directionSent, sent: 1000, received: 300},
+		{addr: recipient, direction: directionReceived, sent: 0, received: 700},
+	}
+	for _, test := range tests {
+		addrStr, err := vm.FormatLocalAddress(test.addr)
+		assert.NoError(t, err)
+		reply := &GetAddressTxsReply{}
+		err = s.GetAddressTxs(nil, &GetAddressTxsArgs{
+			JSONAddress:    api.JSONAddress{Address: addrStr},
+			AssetID:        assetID.String(),
+			IncludeDetails: true,
+		}, reply)
+		assert.NoError(t, err)
+		assert.Equal(t, []ids.ID{txID}, reply.TxIDs)
+		assert.Len(t, reply.Txs, 1)
+		assert.Equal(t, txID, reply.Txs[0].TxID)
+		assert.False(t, reply.Txs[0].Timestamp.IsZero())
+		assert.Equal(t, []AddressTxAssetChange{{
+			AssetID:   assetID,
+			Direction: test.direction,
+			Sent:      json.Uint64(test.sent),
+			Received:  json.Uint64(test.received),
+		}}, reply.Txs[0].Changes)
+	}
+}
+
 func TestServiceGetAllBalances(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
 	defer func() {
@@ -2023,7 +2093,7 @@ func TestServiceGetUTXOs(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.label, func(t *testing.T) {
 			reply := &api.GetUTXOsReply{}
-			err := s.GetUTXOs(nil, test.args, reply)
+			err := s.GetUTXOs(&http.Request{}, test.args, reply)
 			if err != nil {
 				if !test.shouldErr {
 					t.Fatal(err)
@@ -2559,7 +2629,7 @@ func TestSend(t *testing.T) {
 		t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
 	}
 
-	pendingTxs := vm.txs
+	pendingTxs := vm.mempool.Peek()
 	if len(pendingTxs) != 1 {
 		t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
 	}
@@ -2623,7 +2693,7 @@ func TestSendMultiple(t *testing.T) {
 				t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
 			}
 
-			pendingTxs := vm.txs
+			pendingTxs := vm.mempool.Peek()
 			if len(pendingTxs) != 1 {
 				t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
 			}
@@ -2678,6 +2748,76 @@ func TestCreateAndListAddresses(t *testing.T) {
 	t.Fatalf("Failed to find newly created address among %d addresses", len(listReply.Addresses))
 }
 
+func TestHDAddresses(t *testing.T) {
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	user := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+
+	exportReply := &ExportSeedReply{}
+	if err := s.ExportSeed(nil, &user, exportReply); err == nil {
+		t.Fatal("should have failed to export a missing seed")
+	}
+
+	createdAddrs := make([]string, 2)
+	for i := range createdAddrs {
+		createReply := &api.JSONAddress{}
+		if err := s.CreateAddress(nil, &user, createReply); err != nil {
+			t.Fatalf("Failed to create address: %s", err)
+		}
+		createdAddrs[i] = createReply.Address
+	}
+
+	listReply := &HDAddressesReply{}
+	if err := s.ListHDAddresses(nil, &user, listReply); err != nil {
+		t.Fatalf("Failed to list HD addresses: %s", err)
+	}
+	if len(listReply.Addresses) != len(createdAddrs) {
+		t.Fatalf("expected %d HD addresses but got %d", len(createdAddrs), len(listReply.Addresses))
+	}
+	for i, hdAddr := range listReply.Addresses {
+		if hdAddr.Address != createdAddrs[i] {
+			t.Fatalf("expected HD address %s but got %s", createdAddrs[i], hdAddr.Address)
+		}
+		if int(hdAddr.Index) != i {
+			t.Fatalf("expected HD index %d but got %d", i, hdAddr.Index)
+		}
+		expectedPath := fmt.Sprintf("m/44'/9000'/0'/0/%d", i)
+		if hdAddr.Path != expectedPath {
+			t.Fatalf("expected HD path %s but got %s", expectedPath, hdAddr.Path)
+		}
+	}
+
+	if err := s.ExportSeed(nil, &user, exportReply); err != nil {
+		t.Fatalf("Failed to export seed: %s", err)
+	}
+	importArgs := &ImportSeedArgs{
+		UserPass: user,
+		Seed:     exportReply.Seed,
+	}
+	if err := s.ImportSeed(nil, importArgs, &api.SuccessResponse{}); err == nil {
+		t.Fatal("should have failed to replace the seed")
+	}
+
+	// None of the HD addresses own UTXOs, so nothing new is discovered
+	discoverArgs := &DiscoverHDAddressesArgs{UserPass: user}
+	discoverReply := &HDAddressesReply{}
+	if err := s.DiscoverHDAddresses(nil, discoverArgs, discoverReply); err != nil {
+		t.Fatalf("Failed to discover HD addresses: %s", err)
+	}
+	if len(discoverReply.Addresses) != 0 {
+		t.Fatalf("expected no discovered addresses but got %d", len(discoverReply.Addresses))
+	}
+}
+
 func TestImport(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -22,6 +22,8 @@ var (
 	statusStatePrefix          = []byte("status")
 	singletonStatePrefix       = []byte("singleton")
 	txStatePrefix              = []byte("tx")
+	txPruningPrefix            = []byte("txPruning")
+	nftIndexPrefix             = []byte("nftIndex")
 	_                    State = &state{}
 )
 
@@ -63,7 +65,10 @@ func NewState(db database.Database, genesisCodec, codec codec.Manager) State {
 	}
 }
 
-func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, metrics prometheus.Registerer) (State, error) {
+// NewMeteredState returns a State with metrics registered to [metrics]. If
+// [indexUTXOs] is true, the UTXOs of each address are indexed by UTXO ID, so
+// that they can be reliably paginated while UTXOs are being spent.
+func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, metrics prometheus.Registerer, indexUTXOs bool) (State, error) {
 	utxoDB := prefixdb.New(utxoStatePrefix, db)
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
@@ -73,6 +78,14 @@ func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, me
 	if err != nil {
 		return nil, err
 	}
+	if indexUTXOs {
+		utxoState, err = avax.NewSortedIndexUTXOState(utxoState, utxoDB, codec)
+	} else {
+		err = avax.DeleteSortedIndex(utxoDB)
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	statusState, err := avax.NewMeteredStatusState(statusDB, metrics)
 	if err != nil {
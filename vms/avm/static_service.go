@@ -33,6 +33,8 @@ var (
 	_ avax.TransferableOut = &secp256k1fx.TransferOutput{}
 	_ FxOperation          = &secp256k1fx.MintOperation{}
 	_ verify.Verifiable    = &secp256k1fx.Credential{}
+	_ avax.TransferableIn  = &secp256k1fx.TimelockedInput{}
+	_ avax.TransferableOut = &secp256k1fx.TimelockedOutput{}
 
 	_ verify.State      = &nftfx.MintOutput{}
 	_ verify.State      = &nftfx.TransferOutput{}
@@ -216,6 +218,9 @@ func staticCodec() (codec.Manager, error) {
 		c.RegisterType(&propertyfx.MintOperation{}),
 		c.RegisterType(&propertyfx.BurnOperation{}),
 		c.RegisterType(&propertyfx.Credential{}),
+		c.RegisterType(&propertyfx.TransferOperation{}),
+		c.RegisterType(&secp256k1fx.TimelockedInput{}),
+		c.RegisterType(&secp256k1fx.TimelockedOutput{}),
 		manager.RegisterCodec(codecVersion, c),
 	)
 	return manager, errs.Err
@@ -117,7 +117,28 @@ func (t *Tx) SemanticVerify(vm *VM, tx UnsignedTx) error {
 		return errNilTx
 	}
 
-	return t.UnsignedTx.SemanticVerify(vm, tx, t.Credentials())
+	batchFxs := make([]batchFx, 0, len(vm.fxs))
+	for _, fx := range vm.fxs {
+		if fx, ok := fx.Fx.(batchFx); ok {
+			fx.StartBatch()
+			batchFxs = append(batchFxs, fx)
+		}
+	}
+	if err := t.UnsignedTx.SemanticVerify(vm, tx, t.Credentials()); err != nil {
+		for _, fx := range batchFxs {
+			fx.AbortBatch()
+		}
+		return err
+	}
+	for i, fx := range batchFxs {
+		if err := fx.VerifyBatch(); err != nil {
+			for _, fx := range batchFxs[i+1:] {
+				fx.AbortBatch()
+			}
+			return err
+		}
+	}
+	return nil
 }
 
 func (t *Tx) SignSECP256K1Fx(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
@@ -149,6 +170,31 @@ func (t *Tx) SignSECP256K1Fx(c codec.Manager, signers [][]*crypto.PrivateKeySECP
 	return nil
 }
 
+// SignWithSigner signs the inputs of the transaction with [signer]. The i'th
+// credential contains the signatures of [addrs[i]], in order.
+func (t *Tx) SignWithSigner(c codec.Manager, signer secp256k1fx.Signer, addrs [][]ids.ShortID) error {
+	unsignedBytes, err := c.Marshal(codecVersion, &t.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	hash := hashing.ComputeHash256(unsignedBytes)
+	for _, inputSigners := range addrs {
+		cred, err := secp256k1fx.SignCredential(signer, hash, inputSigners)
+		if err != nil {
+			return fmt.Errorf("problem creating transaction: %w", err)
+		}
+		t.Creds = append(t.Creds, &FxCredential{Verifiable: cred})
+	}
+
+	signedBytes, err := c.Marshal(codecVersion, t)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	t.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
 func (t *Tx) SignPropertyFx(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
 	unsignedBytes, err := c.Marshal(codecVersion, &t.UnsignedTx)
 	if err != nil {
@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+)
+
+// maxPrunedTxsPerDecision is the maximum number of txs that are pruned each
+// time a tx is decided, so that deciding a tx after pruning was enabled on a
+// node with a long history doesn't stall consensus
+const maxPrunedTxsPerDecision = 64
+
+// maxPrunedTxsPerCommit is the maximum number of txs that are pruned in each
+// commit when the node prunes the chain
+const maxPrunedTxsPerCommit = 1024
+
+// txPruner deletes the bytes of decided txs once they're older than the
+// retention period. The statuses of pruned txs are kept, so they're still
+// known to have been decided. The txs that create assets are never pruned,
+// since they're needed to verify the txs that use their assets.
+type txPruner struct {
+	txs   TxState
+	clock *mockable.Clock
+
+	// If false, the times txs are decided at aren't recorded, so txs are
+	// never pruned
+	record bool
+	// If 0, txs are only pruned when the node prunes the chain
+	retention time.Duration
+
+	// Key: decision time (unix seconds) || tx ID
+	// Value: nil
+	decidedDB database.Database
+}
+
+func newTxPruner(txs TxState, decidedDB database.Database, clock *mockable.Clock, record bool, retention time.Duration) *txPruner {
+	return &txPruner{
+		txs:       txs,
+		clock:     clock,
+		record:    record,
+		retention: retention,
+		decidedDB: decidedDB,
+	}
+}
+
+// Decided records that [tx] was decided now, so that it's pruned once the
+// retention period has passed. Txs that were decided more than the retention
+// period ago are then pruned.
+func (p *txPruner) Decided(txID ids.ID, tx *Tx) error {
+	if !p.record {
+		return nil
+	}
+	if _, ok := tx.UnsignedTx.(*CreateAssetTx); ok {
+		return nil
+	}
+
+	now := p.clock.Time()
+	if err := p.decidedDB.Put(decidedKey(now, txID), nil); err != nil {
+		return err
+	}
+	if p.retention == 0 {
+		return nil
+	}
+	_, err := p.prune(now.Add(-p.retention), maxPrunedTxsPerDecision)
+	return err
+}
+
+// prune deletes up to [limit] txs that were decided before [cutoff], oldest
+// first. Returns the number of txs that were pruned.
+func (p *txPruner) prune(cutoff time.Time, limit int) (int, error) {
+	it := p.decidedDB.NewIterator()
+	keys := [][]byte(nil)
+	for len(keys) < limit && it.Next() {
+		key := it.Key()
+		if int64(binary.BigEndian.Uint64(key)) >= cutoff.Unix() {
+			break
+		}
+		keys = append(keys, utils.CopyBytes(key))
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		txID, err := ids.ToID(key[wrappers.LongLen:])
+		if err != nil {
+			return 0, err
+		}
+		if err := p.txs.DeleteTx(txID); err != nil {
+			return 0, err
+		}
+		if err := p.decidedDB.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+func decidedKey(decided time.Time, txID ids.ID) []byte {
+	key := make([]byte, wrappers.LongLen+len(txID))
+	binary.BigEndian.PutUint64(key, uint64(decided.Unix()))
+	copy(key[wrappers.LongLen:], txID[:])
+	return key
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/choices"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+)
+
+func TestTxPruner(t *testing.T) {
+	assert := assert.New(t)
+
+	codec, err := staticCodec()
+	assert.NoError(err)
+	s := NewTxState(memdb.New(), codec)
+
+	newTx := func(memo byte) *Tx {
+		tx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+			Memo:         []byte{memo},
+		}}}
+		assert.NoError(tx.SignSECP256K1Fx(codec, nil))
+		return tx
+	}
+	createAssetTx := &Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+		}},
+		Name:   "Team Rocket",
+		Symbol: "TR",
+	}}
+	assert.NoError(createAssetTx.SignSECP256K1Fx(codec, nil))
+	txs := []*Tx{newTx(0), newTx(1), newTx(2), createAssetTx}
+	assert.NoError(s.PutTxs(txs))
+
+	clock := mockable.Clock{}
+	start := time.Unix(1000000, 0)
+	clock.Set(start)
+	p := newTxPruner(s, memdb.New(), &clock, true, time.Hour)
+
+	assert.NoError(p.Decided(txs[0].ID(), txs[0]))
+	assert.NoError(p.Decided(createAssetTx.ID(), createAssetTx))
+
+	clock.Set(start.Add(30 * time.Minute))
+	assert.NoError(p.Decided(txs[1].ID(), txs[1]))
+	for _, tx := range txs {
+		_, err := s.GetTx(tx.ID())
+		assert.NoError(err)
+	}
+
+	// Only the txs decided more than an hour ago are pruned
+	clock.Set(start.Add(90 * time.Minute))
+	assert.NoError(p.Decided(txs[2].ID(), txs[2]))
+	_, err = s.GetTx(txs[0].ID())
+	assert.Equal(database.ErrNotFound, err)
+	for _, tx := range txs[1:] {
+		_, err := s.GetTx(tx.ID())
+		assert.NoError(err)
+	}
+
+	// The txs that create assets are kept
+	clock.Set(start.Add(24 * time.Hour))
+	pruned, err := p.prune(clock.Time().Add(-time.Hour), maxPrunedTxsPerDecision)
+	assert.NoError(err)
+	assert.Equal(2, pruned)
+	_, err = s.GetTx(createAssetTx.ID())
+	assert.NoError(err)
+}
+
+func TestTxPrunerDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	codec, err := staticCodec()
+	assert.NoError(err)
+	s := NewTxState(memdb.New(), codec)
+	decidedDB := memdb.New()
+	p := newTxPruner(s, decidedDB, &mockable.Clock{}, false, 0)
+
+	tx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    networkID,
+		BlockchainID: chainID,
+	}}}
+	assert.NoError(tx.SignSECP256K1Fx(codec, nil))
+	assert.NoError(p.Decided(tx.ID(), tx))
+
+	it := decidedDB.NewIterator()
+	defer it.Release()
+	assert.False(it.Next())
+}
+
+func TestServiceGetPrunedTx(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	// A pruned tx only has its status
+	txID := ids.GenerateTestID()
+	assert.NoError(vm.state.PutStatus(txID, choices.Accepted))
+
+	err := s.GetTx(nil, &api.GetTxArgs{TxID: txID}, &api.GetTxReply{})
+	assert.Equal(errPrunedTx, err)
+	err = s.GetAssetDescription(nil, &GetAssetDescriptionArgs{AssetID: txID.String()}, &GetAssetDescriptionReply{})
+	assert.Equal(errTxNotCreateAsset, err)
+	assert.False(vm.verifyFxUsage(0, txID))
+}
+
+func TestVMPrune(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, _, _, _ := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	// Only the decision times of the txs of chains that enabled pruning are
+	// recorded
+	vm.txPruner = newTxPruner(vm.state, prefixdb.New(txPruningPrefix, vm.db), &vm.clock, true, 0)
+
+	tx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    networkID,
+		BlockchainID: chainID,
+	}}}
+	assert.NoError(tx.SignSECP256K1Fx(vm.codec, nil))
+	assert.NoError(vm.state.PutTx(tx.ID(), tx))
+	assert.NoError(vm.txPruner.Decided(tx.ID(), tx))
+
+	// Txs decided after the cutoff are kept
+	pruned, err := vm.Prune(vm.clock.Time().Add(-time.Hour))
+	assert.NoError(err)
+	assert.Zero(pruned)
+
+	pruned, err = vm.Prune(vm.clock.Time().Add(time.Hour))
+	assert.NoError(err)
+	assert.Equal(1, pruned)
+	_, err = vm.state.GetTx(tx.ID())
+	assert.Equal(database.ErrNotFound, err)
+}
@@ -28,6 +28,10 @@ type TxState interface {
 	// PutTx saves the provided transaction to storage.
 	PutTx(txID ids.ID, tx *Tx) error
 
+	// PutTxs saves the provided transactions to storage, keyed by their IDs,
+	// in a single batch.
+	PutTxs(txs []*Tx) error
+
 	// DeleteTx removes the provided transaction from storage.
 	DeleteTx(txID ids.ID) error
 }
@@ -104,6 +108,23 @@ func (s *txState) PutTx(txID ids.ID, tx *Tx) error {
 	return s.txDB.Put(txID[:], tx.Bytes())
 }
 
+func (s *txState) PutTxs(txs []*Tx) error {
+	batch := s.txDB.NewBatch()
+	for _, tx := range txs {
+		txID := tx.ID()
+		if err := batch.Put(txID[:], tx.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		s.txCache.Put(tx.ID(), tx)
+	}
+	return nil
+}
+
 func (s *txState) DeleteTx(txID ids.ID) error {
 	s.txCache.Put(txID, nil)
 	return s.txDB.Delete(txID[:])
@@ -88,3 +88,35 @@ func TestMeteredTxState(t *testing.T) {
 	_, err = NewMeteredTxState(db, codec, prometheus.NewRegistry())
 	assert.NoError(err)
 }
+
+func TestTxStatePutTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	codec, err := staticCodec()
+	assert.NoError(err)
+
+	s := NewTxState(db, codec).(*txState)
+
+	txs := make([]*Tx, 3)
+	for i := range txs {
+		txs[i] = &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+			Memo:         []byte{byte(i)},
+		}}}
+		err := txs[i].SignSECP256K1Fx(codec, nil)
+		assert.NoError(err)
+	}
+
+	err = s.PutTxs(txs)
+	assert.NoError(err)
+
+	s.txCache.Flush()
+
+	for _, tx := range txs {
+		loadedTx, err := s.GetTx(tx.ID())
+		assert.NoError(err)
+		assert.Equal(tx.ID(), loadedTx.ID())
+	}
+}
@@ -168,6 +168,14 @@ func (tx *UniqueTx) Accept() error {
 	if err := tx.setStatus(choices.Accepted); err != nil {
 		return fmt.Errorf("couldn't set status of tx %s: %w", txID, err)
 	}
+	if err := tx.vm.txPruner.Decided(txID, tx.Tx); err != nil {
+		return fmt.Errorf("couldn't prune txs: %w", err)
+	}
+	if tx.vm.nftIndex != nil {
+		if err := tx.vm.nftIndex.Accept(txID, tx.UnsignedTx); err != nil {
+			return fmt.Errorf("error indexing NFTs: %w", err)
+		}
+	}
 
 	commitBatch, err := tx.vm.db.CommitBatch()
 	if err != nil {
@@ -198,6 +206,11 @@ func (tx *UniqueTx) Reject() error {
 	txID := tx.ID()
 	tx.vm.ctx.Log.Debug("Rejecting Tx: %s", txID)
 
+	if err := tx.vm.txPruner.Decided(txID, tx.Tx); err != nil {
+		tx.vm.ctx.Log.Error("Failed to prune txs while rejecting %s due to %s", txID, err)
+		return err
+	}
+
 	if err := tx.vm.db.Commit(); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit reject %s due to %s", tx.txID, err)
 		return err
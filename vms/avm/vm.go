@@ -19,6 +19,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/codec"
 	"github.com/Toinounet21/avalanchego-mod/database"
 	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/database/versiondb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/pubsub"
@@ -28,6 +29,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/avalanche/vertex"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
 	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto/ledger"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer"
 	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 	"github.com/Toinounet21/avalanchego-mod/version"
@@ -36,6 +38,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/vms/components/keystore"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
 	"github.com/Toinounet21/avalanchego-mod/vms/nftfx"
+	"github.com/Toinounet21/avalanchego-mod/vms/propertyfx"
 	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
 
 	cjson "github.com/Toinounet21/avalanchego-mod/utils/json"
@@ -54,8 +57,10 @@ var (
 	errGenesisAssetMustHaveState = errors.New("genesis asset must have non-empty state")
 	errBootstrapping             = errors.New("chain is currently bootstrapping")
 	errInsufficientFunds         = errors.New("insufficient funds")
+	errApricotPhase6NotActivated = errors.New("can't be used before ApricotPhase6 activates")
 
-	_ vertex.DAGVM = &VM{}
+	_ vertex.DAGVM  = &VM{}
+	_ common.Pruner = &VM{}
 )
 
 // VM implements the avalanche.DAGVM interface
@@ -86,13 +91,17 @@ type VM struct {
 	// asset id that will be used for fees
 	feeAssetID ids.ID
 
+	// Fee changes, sorted by activation time, that the txs built by this node
+	// burn once they activate
+	feeSchedule []FeeConfig
+
 	// Asset ID --> Bit set with fx IDs the asset supports
 	assetToFxCache *cache.LRU
 
 	// Transaction issuing
 	timer        *timer.Timer
 	batchTimeout time.Duration
-	txs          []snowstorm.Tx
+	mempool      *mempool
 	toEngine     chan<- common.Message
 
 	baseDB database.Database
@@ -104,6 +113,18 @@ type VM struct {
 	walletService WalletService
 
 	addressTxsIndexer index.AddressTxsIndexer
+
+	txPruner *txPruner
+
+	// Nil if NFT indexing is disabled
+	nftIndex *nftIndex
+
+	// Gossips pending txs to other nodes
+	*network
+
+	// Returns the signer used by the SendWithLedger API. Nil if no Ledger is
+	// configured.
+	newSigner func() (secp256k1fx.Signer, error)
 }
 
 func (vm *VM) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
@@ -123,6 +144,36 @@ func (vm *VM) Disconnected(nodeID ids.ShortID) error {
 type Config struct {
 	IndexTransactions    bool `json:"index-transactions"`
 	IndexAllowIncomplete bool `json:"index-allow-incomplete"`
+	// If true, the UTXOs of each address are indexed by UTXO ID, so that
+	// getUTXOs pages through them with cursors that stay valid while UTXOs
+	// are spent
+	IndexUTXOsByAddress bool `json:"index-utxos-by-address"`
+
+	// If non-zero, the bytes of decided txs are deleted once they were
+	// decided this many seconds ago. Their statuses are kept. The txs that
+	// create assets are never pruned.
+	TxRetentionSeconds uint64 `json:"tx-retention-seconds"`
+
+	// If true, the times txs are decided at are recorded, so that the node
+	// can prune the txs that were decided before its pruning retention
+	// window. Implied by a non-zero TxRetentionSeconds.
+	PruningEnabled bool `json:"pruning-enabled"`
+
+	// If true, the groups of NFT assets and the payloads of the NFTs minted
+	// in them are indexed
+	IndexNFTs bool `json:"index-nfts"`
+
+	// URL of the bridge to the Ledger that signs SendWithLedger transactions.
+	// If empty, SendWithLedger is disabled.
+	LedgerBridgeURL string `json:"ledger-bridge-url"`
+	// Number of Ledger addresses that SendWithLedger spends from
+	LedgerNumAddresses uint32 `json:"ledger-num-addresses"`
+
+	// Fees that the txs built by this node burn once their activation times
+	// are reached, sorted by activation time. They aren't enforced, as nodes
+	// don't agree on when they activate, so txs only have to burn the fees of
+	// the network. Scheduled fees below the network's fees are raised to them.
+	FeeSchedule []FeeConfig `json:"fee-schedule"`
 }
 
 // Initialize implements the avalanche.DAGVM interface
@@ -134,7 +185,7 @@ func (vm *VM) Initialize(
 	configBytes []byte,
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
-	_ common.AppSender,
+	appSender common.AppSender,
 ) error {
 	avmConfig := Config{}
 	if len(configBytes) > 0 {
@@ -143,6 +194,10 @@ func (vm *VM) Initialize(
 		}
 		ctx.Log.Info("VM config initialized %+v", avmConfig)
 	}
+	if err := verifyFeeSchedule(avmConfig.FeeSchedule); err != nil {
+		return fmt.Errorf("invalid fee schedule: %w", err)
+	}
+	vm.feeSchedule = avmConfig.FeeSchedule
 
 	registerer := prometheus.NewRegistry()
 	if err := ctx.Metrics.Register(registerer); err != nil {
@@ -195,7 +250,11 @@ func (vm *VM) Initialize(
 
 	vm.AtomicUTXOManager = avax.NewAtomicUTXOManager(ctx.SharedMemory, vm.codec)
 
-	state, err := NewMeteredState(vm.db, vm.genesisCodec, vm.codec, registerer)
+	if avmConfig.IndexNFTs {
+		vm.nftIndex = newNFTIndex(prefixdb.New(nftIndexPrefix, vm.db))
+	}
+
+	state, err := NewMeteredState(vm.db, vm.genesisCodec, vm.codec, registerer, avmConfig.IndexUTXOsByAddress)
 	if err != nil {
 		return err
 	}
@@ -205,6 +264,20 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	vm.txPruner = newTxPruner(
+		vm.state,
+		prefixdb.New(txPruningPrefix, vm.db),
+		&vm.clock,
+		avmConfig.PruningEnabled || avmConfig.TxRetentionSeconds != 0,
+		time.Duration(avmConfig.TxRetentionSeconds)*time.Second,
+	)
+
+	vm.mempool, err = newMempool("", registerer)
+	if err != nil {
+		return err
+	}
+	vm.network = newNetwork(appSender, vm)
+
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
 		defer ctx.Lock.Unlock()
@@ -218,6 +291,25 @@ func (vm *VM) Initialize(
 	vm.walletService.pendingTxMap = make(map[ids.ID]*list.Element)
 	vm.walletService.pendingTxOrdering = list.New()
 
+	if avmConfig.LedgerBridgeURL != "" {
+		ledgerConfig := ledger.Config{
+			BridgeURL:      avmConfig.LedgerBridgeURL,
+			NumAddresses:   avmConfig.LedgerNumAddresses,
+			RequestTimeout: ledger.DefaultRequestTimeout,
+		}
+		if ledgerConfig.NumAddresses == 0 {
+			ledgerConfig.NumAddresses = ledger.DefaultNumAddresses
+		}
+		if err := ledgerConfig.Verify(); err != nil {
+			return fmt.Errorf("invalid ledger config: %w", err)
+		}
+		// The device is only reached when it's used, so that the chain doesn't
+		// depend on it being connected
+		vm.newSigner = func() (secp256k1fx.Signer, error) {
+			return ledger.New(ledgerConfig)
+		}
+	}
+
 	// use no op impl when disabled in config
 	if avmConfig.IndexTransactions {
 		vm.ctx.Log.Info("address transaction indexing is enabled")
@@ -258,6 +350,25 @@ func (vm *VM) Bootstrapped() error {
 	return nil
 }
 
+// Prune implements the common.Pruner interface. The bytes of the txs that
+// were decided before [cutoff] are deleted. Their statuses are kept.
+func (vm *VM) Prune(cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		pruned, err := vm.txPruner.prune(cutoff, maxPrunedTxsPerCommit)
+		if err != nil {
+			return total, err
+		}
+		if err := vm.db.Commit(); err != nil {
+			return total, err
+		}
+		total += pruned
+		if pruned < maxPrunedTxsPerCommit {
+			return total, nil
+		}
+	}
+}
+
 // Shutdown implements the avalanche.DAGVM interface
 func (vm *VM) Shutdown() error {
 	if vm.timer == nil {
@@ -325,9 +436,7 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 func (vm *VM) PendingTxs() []snowstorm.Tx {
 	vm.timer.Cancel()
 
-	txs := vm.txs
-	vm.txs = nil
-	return txs
+	return vm.mempool.PopAll()
 }
 
 // Parse implements the avalanche.DAGVM interface
@@ -335,6 +444,22 @@ func (vm *VM) ParseTx(b []byte) (snowstorm.Tx, error) {
 	return vm.parseTx(b)
 }
 
+// Addresses returns the addresses of the outputs of the tx [txBytes]. Used to
+// filter the decided txs published by the events API.
+func (vm *VM) Addresses(txBytes []byte) ([][]byte, error) {
+	tx, err := vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	var addrs [][]byte
+	for _, utxo := range tx.UTXOs() {
+		if addressable, ok := utxo.Out.(avax.Addressable); ok {
+			addrs = append(addrs, addressable.Addresses()...)
+		}
+	}
+	return addrs, nil
+}
+
 // Get implements the avalanche.DAGVM interface
 func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 	tx := &UniqueTx{
@@ -360,14 +485,16 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if !vm.bootstrapped {
 		return ids.ID{}, errBootstrapping
 	}
-	tx, err := vm.parseTx(b)
+	tx, err := vm.parseUnpersistedTx(b)
 	if err != nil {
 		return ids.ID{}, err
 	}
-	if err := tx.verifyWithoutCacheWrites(); err != nil {
+	if err := vm.verifyAndIssueTx(tx); err != nil {
 		return ids.ID{}, err
 	}
-	vm.issueTx(tx)
+	if err := vm.network.GossipTx(tx); err != nil {
+		vm.ctx.Log.Debug("failed to gossip tx %s: %s", tx.ID(), err)
+	}
 	return tx.ID(), nil
 }
 
@@ -380,7 +507,7 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 // FlushTxs into consensus
 func (vm *VM) FlushTxs() {
 	vm.timer.Cancel()
-	if len(vm.txs) != 0 {
+	if vm.mempool.Len() != 0 {
 		select {
 		case vm.toEngine <- common.PendingTxs:
 		default:
@@ -455,6 +582,11 @@ func (vm *VM) initState(tx Tx) error {
 	if err := vm.state.PutStatus(txID, choices.Accepted); err != nil {
 		return err
 	}
+	if vm.nftIndex != nil {
+		if err := vm.nftIndex.Accept(txID, tx.UnsignedTx); err != nil {
+			return err
+		}
+	}
 	for _, utxo := range tx.UTXOs() {
 		if err := vm.state.PutUTXO(utxo.InputID(), utxo); err != nil {
 			return err
@@ -464,6 +596,16 @@ func (vm *VM) initState(tx Tx) error {
 }
 
 func (vm *VM) parseTx(bytes []byte) (*UniqueTx, error) {
+	tx, err := vm.parseUnpersistedTx(bytes)
+	if err != nil {
+		return nil, err
+	}
+	return tx, vm.persistTx(tx)
+}
+
+// parseUnpersistedTx returns the well formed tx [bytes] encodes, without
+// writing it to the database
+func (vm *VM) parseUnpersistedTx(bytes []byte) (*UniqueTx, error) {
 	rawTx, err := vm.parsePrivateTx(bytes)
 	if err != nil {
 		return nil, err
@@ -479,18 +621,22 @@ func (vm *VM) parseTx(bytes []byte) (*UniqueTx, error) {
 	if err := tx.SyntacticVerify(); err != nil {
 		return nil, err
 	}
+	return tx, nil
+}
 
-	if tx.Status() == choices.Unknown {
-		if err := vm.state.PutTx(tx.ID(), tx.Tx); err != nil {
-			return nil, err
-		}
-		if err := tx.setStatus(choices.Processing); err != nil {
-			return nil, err
-		}
-		return tx, vm.db.Commit()
+// persistTx writes [tx] to the database as processing, unless it's already
+// known
+func (vm *VM) persistTx(tx *UniqueTx) error {
+	if tx.Status() != choices.Unknown {
+		return nil
 	}
-
-	return tx, nil
+	if err := vm.state.PutTx(tx.ID(), tx.Tx); err != nil {
+		return err
+	}
+	if err := tx.setStatus(choices.Processing); err != nil {
+		return err
+	}
+	return vm.db.Commit()
 }
 
 func (vm *VM) parsePrivateTx(txBytes []byte) (*Tx, error) {
@@ -507,14 +653,39 @@ func (vm *VM) parsePrivateTx(txBytes []byte) (*Tx, error) {
 	return tx, nil
 }
 
-func (vm *VM) issueTx(tx snowstorm.Tx) {
-	vm.txs = append(vm.txs, tx)
+// verifyAndIssueTx verifies [tx], which was parsed by parseUnpersistedTx, and
+// adds it to the mempool, to be handed to consensus. A tx that isn't known yet
+// is verified in memory, and is only written to the database once it's in the
+// mempool. Adding a tx that is already in the mempool is a no-op.
+func (vm *VM) verifyAndIssueTx(tx *UniqueTx) error {
+	if vm.mempool.Has(tx.ID()) {
+		return nil
+	}
+
+	var err error
+	if tx.Status() == choices.Unknown {
+		err = tx.SemanticVerify()
+	} else {
+		err = tx.verifyWithoutCacheWrites()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := vm.mempool.Add(tx); err != nil {
+		return err
+	}
+	if err := vm.persistTx(tx); err != nil {
+		vm.mempool.Remove(tx.ID())
+		return err
+	}
 	switch {
-	case len(vm.txs) == batchSize:
+	case vm.mempool.Len() == batchSize:
 		vm.FlushTxs()
-	case len(vm.txs) == 1:
+	case vm.mempool.Len() == 1:
 		vm.timer.SetTimeoutIn(vm.batchTimeout)
 	}
+	return nil
 }
 
 func (vm *VM) getUTXO(utxoID *avax.UTXOID) (*avax.UTXO, error) {
@@ -564,6 +735,25 @@ func (vm *VM) getParsedFx(val interface{}) (*parsedFx, error) {
 	return fx, nil
 }
 
+// apricotPhase6Activated returns true if ApricotPhase6 has activated. Since
+// txs don't carry a timestamp, the upgrade time is compared to the VM clock,
+// like the locktimes of outputs are.
+func (vm *VM) apricotPhase6Activated() bool {
+	return !vm.clock.Time().Before(vm.ApricotPhase6Time)
+}
+
+// verifyActivated returns an error if [fxType] is an fx type that can't be
+// used until ApricotPhase6 activates, and it hasn't activated yet
+func (vm *VM) verifyActivated(fxType interface{}) error {
+	switch fxType.(type) {
+	case *propertyfx.TransferOperation, *secp256k1fx.TimelockedInput, *secp256k1fx.TimelockedOutput:
+		if !vm.apricotPhase6Activated() {
+			return fmt.Errorf("%T %w", fxType, errApricotPhase6NotActivated)
+		}
+	}
+	return nil
+}
+
 func (vm *VM) verifyFxUsage(fxID int, assetID ids.ID) bool {
 	// Check cache to see whether this asset supports this fx
 	fxIDsIntf, assetInCache := vm.assetToFxCache.Get(assetID)
@@ -576,7 +766,8 @@ func (vm *VM) verifyFxUsage(fxID int, assetID ids.ID) bool {
 		vm:   vm,
 		txID: assetID,
 	}
-	if status := tx.Status(); !status.Fetched() {
+	if status := tx.Status(); !status.Fetched() || tx.Tx == nil {
+		// The tx is unknown or its bytes were pruned
 		return false
 	}
 	createAssetTx, ok := tx.UnsignedTx.(*CreateAssetTx)
@@ -611,6 +802,9 @@ func (vm *VM) verifyTransferOfUTXO(tx UnsignedTx, in *avax.TransferableInput, cr
 	if !vm.verifyFxUsage(fxIndex, inAssetID) {
 		return errIncompatibleFx
 	}
+	if err := vm.verifyActivated(in.In); err != nil {
+		return err
+	}
 
 	return fx.VerifyTransfer(tx, in.In, cred, utxo.Out)
 }
@@ -624,6 +818,10 @@ func (vm *VM) verifyTransfer(tx UnsignedTx, in *avax.TransferableInput, cred ver
 }
 
 func (vm *VM) verifyOperation(tx UnsignedTx, op *Operation, cred verify.Verifiable) error {
+	if err := vm.verifyActivated(op.Op); err != nil {
+		return err
+	}
+
 	opAssetID := op.AssetID()
 
 	numUTXOs := len(op.UTXOIDs)
@@ -754,6 +952,70 @@ func (vm *VM) Spend(
 	return amountsSpent, ins, keys, nil
 }
 
+// buildSendTx returns a signed BaseTx that creates [outs] by spending [utxos].
+// [amounts] is the total amount of each asset in [outs]. The tx fee is paid
+// and any change is sent to [changeAddr].
+func (vm *VM) buildSendTx(
+	utxos []*avax.UTXO,
+	kc *secp256k1fx.Keychain,
+	changeAddr ids.ShortID,
+	outs []*avax.TransferableOutput,
+	amounts map[ids.ID]uint64,
+	memo []byte,
+) (*Tx, error) {
+	amountsWithFee := make(map[ids.ID]uint64, len(amounts)+1)
+	for assetKey, amount := range amounts {
+		amountsWithFee[assetKey] = amount
+	}
+
+	amountWithFee, err := safemath.Add64(amounts[vm.feeAssetID], vm.txFee())
+	if err != nil {
+		return nil, fmt.Errorf("problem calculating required spend amount: %w", err)
+	}
+	amountsWithFee[vm.feeAssetID] = amountWithFee
+
+	amountsSpent, ins, keys, err := vm.Spend(
+		utxos,
+		kc,
+		amountsWithFee,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the required change outputs
+	for assetID, amountWithFee := range amountsWithFee {
+		amountSpent := amountsSpent[assetID]
+
+		if amountSpent > amountWithFee {
+			outs = append(outs, &avax.TransferableOutput{
+				Asset: avax.Asset{ID: assetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amountSpent - amountWithFee,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Locktime:  0,
+						Threshold: 1,
+						Addrs:     []ids.ShortID{changeAddr},
+					},
+				},
+			})
+		}
+	}
+	avax.SortTransferableOutputs(outs, vm.codec)
+
+	tx := Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    vm.ctx.NetworkID,
+		BlockchainID: vm.ctx.ChainID,
+		Outs:         outs,
+		Ins:          ins,
+		Memo:         memo,
+	}}}
+	if err := tx.SignSECP256K1Fx(vm.codec, keys); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
 func (vm *VM) SpendNFT(
 	utxos []*avax.UTXO,
 	kc *secp256k1fx.Keychain,
@@ -1045,23 +1307,3 @@ func (vm *VM) lookupAssetID(asset string) (ids.ID, error) {
 	}
 	return ids.ID{}, fmt.Errorf("asset '%s' not found", asset)
 }
-
-// This VM doesn't (currently) have any app-specific messages
-func (vm *VM) AppRequest(nodeID ids.ShortID, requestID uint32, deadline time.Time, request []byte) error {
-	return nil
-}
-
-// This VM doesn't (currently) have any app-specific messages
-func (vm *VM) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
-	return nil
-}
-
-// This VM doesn't (currently) have any app-specific messages
-func (vm *VM) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
-	return nil
-}
-
-// This VM doesn't (currently) have any app-specific messages
-func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
-	return nil
-}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/api/keystore"
 	"github.com/Toinounet21/avalanchego-mod/chains/atomic"
@@ -300,7 +301,7 @@ func GenesisVMWithArgs(tb testing.TB, additionalFxs []*common.Fx, args *BuildGen
 			},
 			additionalFxs...,
 		),
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		tb.Fatal(err)
@@ -639,7 +640,7 @@ func TestInvalidFx(t *testing.T) {
 		[]*common.Fx{ // fxs
 			nil,
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err == nil {
 		t.Fatalf("Should have errored due to an invalid interface")
@@ -673,7 +674,7 @@ func TestFxInitializationFailure(t *testing.T) {
 				},
 			},
 		}},
-		nil,
+		&common.SenderTest{},
 	)
 	if err == nil {
 		t.Fatalf("Should have errored due to an invalid fx initialization")
@@ -777,7 +778,7 @@ func TestIssueNFT(t *testing.T) {
 				Fx: &nftfx.Fx{},
 			},
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -929,7 +930,7 @@ func TestIssueProperty(t *testing.T) {
 				Fx: &propertyfx.Fx{},
 			},
 		},
-		nil,
+		&common.SenderTest{},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -1065,6 +1066,74 @@ func TestIssueProperty(t *testing.T) {
 	}
 }
 
+func TestPropertyTransferOperationRequiresApricotPhase6(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	vm.ApricotPhase6Time = vm.clock.Time().Add(time.Hour)
+	op := &Operation{
+		Asset: avax.Asset{ID: ids.GenerateTestID()},
+		UTXOIDs: []*avax.UTXOID{{
+			TxID: ids.GenerateTestID(),
+		}},
+		Op: &propertyfx.TransferOperation{},
+	}
+	err := vm.verifyOperation(&OperationTx{}, op, &propertyfx.Credential{})
+	assert.ErrorIs(err, errApricotPhase6NotActivated)
+
+	// Once ApricotPhase6 activates, the operation is verified
+	vm.clock.Set(vm.ApricotPhase6Time)
+	err = vm.verifyOperation(&OperationTx{}, op, &propertyfx.Credential{})
+	assert.ErrorIs(err, errMissingUTXO)
+}
+
+func TestTimelockedTypesRequireApricotPhase6(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	vm.ApricotPhase6Time = vm.clock.Time().Add(time.Hour)
+	avaxTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
+	asset := avax.Asset{ID: avaxTx.ID()}
+
+	// Timelocked outputs can't be created
+	tx := &BaseTx{BaseTx: avax.BaseTx{
+		Outs: []*avax.TransferableOutput{{
+			Asset: asset,
+			Out:   &secp256k1fx.TimelockedOutput{Amt: 1},
+		}},
+	}}
+	err := tx.SemanticVerify(vm, tx, nil)
+	assert.ErrorIs(err, errApricotPhase6NotActivated)
+
+	// Nor spent
+	in := &avax.TransferableInput{
+		Asset: asset,
+		In:    &secp256k1fx.TimelockedInput{},
+	}
+	utxo := &avax.UTXO{
+		Asset: asset,
+		Out:   &secp256k1fx.TimelockedOutput{Amt: 1},
+	}
+	err = vm.verifyTransferOfUTXO(tx, in, &secp256k1fx.Credential{}, utxo)
+	assert.ErrorIs(err, errApricotPhase6NotActivated)
+
+	// Until ApricotPhase6 activates
+	vm.clock.Set(vm.ApricotPhase6Time)
+	assert.NoError(tx.SemanticVerify(vm, tx, nil))
+}
+
 func setupTxFeeAssets(t *testing.T) ([]byte, chan common.Message, *VM, *atomic.Memory) {
 	addr0Str, _ := formatting.FormatBech32(testHRP, addrs[0].Bytes())
 	addr1Str, _ := formatting.FormatBech32(testHRP, addrs[1].Bytes())
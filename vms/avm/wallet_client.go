@@ -16,7 +16,7 @@ import (
 )
 
 // Interface compliance
-var _ WalletClient = &client{}
+var _ WalletClient = &walletClient{}
 
 // interface of an AVM wallet client for interacting with avm managed wallet on [chain]
 type WalletClient interface {
@@ -42,6 +42,21 @@ type WalletClient interface {
 		outputs []SendOutput,
 		memo string,
 	) (ids.ID, error)
+	// SendBatch makes [payouts] from [user] using as few transactions as
+	// possible and returns the issued transactions. If an error is returned
+	// after some of the transactions were issued, the reply still describes
+	// the issued transactions.
+	SendBatch(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		payouts []SendOutput,
+		maxInputsPerTx uint32,
+		maxOutputsPerTx uint32,
+		maxFee uint64,
+		memo string,
+	) (*SendBatchReply, error)
 }
 
 // implementation of an AVM wallet client for interacting with avm managed wallet on [chain]
@@ -116,3 +131,31 @@ func (c *walletClient) SendMultiple(
 	}, res)
 	return res.TxID, err
 }
+
+func (c *walletClient) SendBatch(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	payouts []SendOutput,
+	maxInputsPerTx uint32,
+	maxOutputsPerTx uint32,
+	maxFee uint64,
+	memo string,
+) (*SendBatchReply, error) {
+	res := &SendBatchReply{}
+	err := c.requester.SendRequest(ctx, "sendBatch", &SendBatchArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		Payouts:         payouts,
+		MaxInputsPerTx:  cjson.Uint32(maxInputsPerTx),
+		MaxOutputsPerTx: cjson.Uint32(maxOutputsPerTx),
+		MaxFee:          cjson.Uint64(maxFee),
+		Memo:            memo,
+	}, res)
+	decodePartialBatchReply(err, res)
+	return res, err
+}
@@ -5,10 +5,12 @@ package avm
 
 import (
 	"container/list"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/api/auth"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
 	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
@@ -17,6 +19,11 @@ import (
 	safemath "github.com/Toinounet21/avalanchego-mod/utils/math"
 )
 
+var errAssetNotAuthorized = errors.New("the provided auth token does not allow using this asset")
+
+// WalletService issues transactions of the user's keystore accounts. If the
+// request's auth token is restricted to specific assets, only transactions
+// that use those assets may be issued.
 type WalletService struct {
 	vm *VM
 
@@ -93,6 +100,17 @@ func (w *WalletService) IssueTx(r *http.Request, args *api.FormattedTx, reply *a
 	if err != nil {
 		return fmt.Errorf("problem decoding transaction: %w", err)
 	}
+	if _, restricted := auth.RestrictedAssets(r); restricted {
+		// Every asset the transaction uses, including the asset its fee is
+		// paid in, must be allowed
+		tx, err := w.vm.parsePrivateTx(txBytes)
+		if err != nil {
+			return err
+		}
+		if err := checkAssets(r, tx.UnsignedTx.AssetIDs()); err != nil {
+			return err
+		}
+	}
 	txID, err := w.issue(txBytes)
 	reply.TxID = txID
 	return err
@@ -120,6 +138,9 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	} else if len(args.Outputs) == 0 {
 		return errNoOutputs
 	}
+	if err := w.checkOutputAssets(r, args.Outputs); err != nil {
+		return err
+	}
 
 	// Parse the from addresses
 	fromAddrs := ids.NewShortSet(len(args.From))
@@ -197,54 +218,8 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		})
 	}
 
-	amountsWithFee := make(map[ids.ID]uint64, len(amounts)+1)
-	for assetKey, amount := range amounts {
-		amountsWithFee[assetKey] = amount
-	}
-
-	amountWithFee, err := safemath.Add64(amounts[w.vm.feeAssetID], w.vm.TxFee)
+	tx, err := w.vm.buildSendTx(utxos, kc, changeAddr, outs, amounts, memoBytes)
 	if err != nil {
-		return fmt.Errorf("problem calculating required spend amount: %w", err)
-	}
-	amountsWithFee[w.vm.feeAssetID] = amountWithFee
-
-	amountsSpent, ins, keys, err := w.vm.Spend(
-		utxos,
-		kc,
-		amountsWithFee,
-	)
-	if err != nil {
-		return err
-	}
-
-	// Add the required change outputs
-	for assetID, amountWithFee := range amountsWithFee {
-		amountSpent := amountsSpent[assetID]
-
-		if amountSpent > amountWithFee {
-			outs = append(outs, &avax.TransferableOutput{
-				Asset: avax.Asset{ID: assetID},
-				Out: &secp256k1fx.TransferOutput{
-					Amt: amountSpent - amountWithFee,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Locktime:  0,
-						Threshold: 1,
-						Addrs:     []ids.ShortID{changeAddr},
-					},
-				},
-			})
-		}
-	}
-	avax.SortTransferableOutputs(outs, w.vm.codec)
-
-	tx := Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
-		NetworkID:    w.vm.ctx.NetworkID,
-		BlockchainID: w.vm.ctx.ChainID,
-		Outs:         outs,
-		Ins:          ins,
-		Memo:         memoBytes,
-	}}}
-	if err := tx.SignSECP256K1Fx(w.vm.codec, keys); err != nil {
 		return err
 	}
 
@@ -257,3 +232,47 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	reply.ChangeAddr, err = w.vm.FormatLocalAddress(changeAddr)
 	return err
 }
+
+// SendBatch makes the provided payouts using as few transactions as possible
+// and returns the issued transactions.
+func (w *WalletService) SendBatch(r *http.Request, args *SendBatchArgs, reply *SendBatchReply) error {
+	w.vm.ctx.Log.Debug("AVM Wallet: SendBatch called with username: %s", args.Username)
+
+	if err := w.checkOutputAssets(r, args.Payouts); err != nil {
+		return err
+	}
+	return w.vm.sendBatch(args, reply, w.update, w.issue)
+}
+
+// checkOutputAssets returns an error if the auth token of [r] doesn't allow
+// sending the assets of [outputs]. The fee is paid regardless of the token's
+// restrictions.
+func (w *WalletService) checkOutputAssets(r *http.Request, outputs []SendOutput) error {
+	if _, restricted := auth.RestrictedAssets(r); !restricted {
+		return nil
+	}
+	assetIDs := ids.NewSet(len(outputs))
+	for _, output := range outputs {
+		assetID, err := w.vm.lookupAssetID(output.AssetID)
+		if err != nil {
+			return fmt.Errorf("couldn't find asset %s", output.AssetID)
+		}
+		assetIDs.Add(assetID)
+	}
+	return checkAssets(r, assetIDs)
+}
+
+// checkAssets returns an error if the auth token of [r] doesn't allow using
+// each of [assetIDs]
+func checkAssets(r *http.Request, assetIDs ids.Set) error {
+	allowedAssetIDs, restricted := auth.RestrictedAssets(r)
+	if !restricted {
+		return nil
+	}
+	for assetID := range assetIDs {
+		if !allowedAssetIDs.Contains(assetID) {
+			return fmt.Errorf("%w: %s", errAssetNotAuthorized, assetID)
+		}
+	}
+	return nil
+}
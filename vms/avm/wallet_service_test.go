@@ -5,11 +5,22 @@ package avm
 
 import (
 	"container/list"
+	stdjson "encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2/json2"
 
 	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/api/auth"
 	"github.com/Toinounet21/avalanchego-mod/chains/atomic"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/keystore"
 )
 
@@ -113,7 +124,7 @@ func TestWalletService_SendMultiple(t *testing.T) {
 				t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
 			}
 
-			pendingTxs := vm.txs
+			pendingTxs := vm.mempool.Peek()
 			if len(pendingTxs) != 1 {
 				t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
 			}
@@ -128,3 +139,310 @@ func TestWalletService_SendMultiple(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletService_SendBatch(t *testing.T) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, vm, ws, _, genesisTx := setupWSWithKeys(t, tc.avaxAsset)
+			defer func() {
+				if err := vm.Shutdown(); err != nil {
+					t.Fatal(err)
+				}
+				vm.ctx.Lock.Unlock()
+			}()
+
+			assetID := genesisTx.ID()
+			changeAddrStr, err := vm.FormatLocalAddress(testChangeAddr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, fromAddrsStr := sampleAddrs(t, vm, addrs)
+
+			payouts := make([]SendOutput, 5)
+			for i := range payouts {
+				addrStr, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+				if err != nil {
+					t.Fatal(err)
+				}
+				payouts[i] = SendOutput{
+					Amount:  json.Uint64(100 * (i + 1)),
+					AssetID: assetID.String(),
+					To:      addrStr,
+				}
+			}
+
+			maxOutputs := 4
+			args := &SendBatchArgs{
+				JSONSpendHeader: api.JSONSpendHeader{
+					UserPass: api.UserPass{
+						Username: username,
+						Password: password,
+					},
+					JSONFromAddrs:  api.JSONFromAddrs{From: fromAddrsStr},
+					JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddrStr},
+				},
+				Payouts:         payouts,
+				MaxOutputsPerTx: json.Uint32(maxOutputs),
+			}
+			reply := &SendBatchReply{}
+			vm.timer.Cancel()
+			if err := ws.SendBatch(nil, args, reply); err != nil {
+				t.Fatalf("Failed to send batch: %s", err)
+			}
+			if reply.ChangeAddr != changeAddrStr {
+				t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
+			}
+			if len(reply.Txs) < 2 {
+				t.Fatalf("expected the payouts to be split across multiple transactions but got %d", len(reply.Txs))
+			}
+			if vm.mempool.Len() != len(reply.Txs) {
+				t.Fatalf("expected %d pending txs but found %d", len(reply.Txs), vm.mempool.Len())
+			}
+			if expectedFee := uint64(len(reply.Txs)) * vm.TxFee; uint64(reply.Fee) != expectedFee {
+				t.Fatalf("expected fee to be %d but got %d", expectedFee, reply.Fee)
+			}
+
+			// Each payout should be made by exactly one transaction
+			paid := make(map[json.Uint32]bool)
+			for _, settlementTx := range reply.Txs {
+				e, ok := ws.pendingTxMap[settlementTx.TxID]
+				if !ok {
+					t.Fatalf("transaction %s wasn't issued", settlementTx.TxID)
+				}
+				tx := e.Value.(*Tx)
+				if numOuts := len(tx.UnsignedTx.(*BaseTx).Outs); numOuts > maxOutputs {
+					t.Fatalf("transaction %s has %d outputs but the limit is %d", settlementTx.TxID, numOuts, maxOutputs)
+				}
+				for _, index := range settlementTx.Payouts {
+					if paid[index] {
+						t.Fatalf("payout %d was made more than once", index)
+					}
+					paid[index] = true
+				}
+			}
+			if len(paid) != len(payouts) {
+				t.Fatalf("expected %d payouts to be made but %d were", len(payouts), len(paid))
+			}
+		})
+	}
+}
+
+func TestWalletService_SendBatchFeeBudget(t *testing.T) {
+	_, vm, ws, _, genesisTx := setupWSWithKeys(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addrStr, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fromAddrsStr := sampleAddrs(t, vm, addrs)
+
+	payout := SendOutput{
+		Amount:  500,
+		AssetID: genesisTx.ID().String(),
+		To:      addrStr,
+	}
+	args := &SendBatchArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+			JSONFromAddrs: api.JSONFromAddrs{From: fromAddrsStr},
+		},
+		Payouts: []SendOutput{payout, payout, payout},
+		// Each transaction can make a single payout and pay its change
+		MaxOutputsPerTx: 2,
+		// The budget only covers two of the three transactions
+		MaxFee: json.Uint64(2 * vm.TxFee),
+	}
+	vm.timer.Cancel()
+	err = ws.SendBatch(nil, args, &SendBatchReply{})
+	if !errors.Is(err, errFeeBudgetExceeded) {
+		t.Fatalf("expected %s but got %v", errFeeBudgetExceeded, err)
+	}
+	if vm.mempool.Len() != 0 {
+		t.Fatalf("expected no txs to be issued but found %d", vm.mempool.Len())
+	}
+}
+
+func TestWalletService_SendBatchReportsIssuedTxs(t *testing.T) {
+	_, vm, ws, _, genesisTx := setupWSWithKeys(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addrStr, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fromAddrsStr := sampleAddrs(t, vm, addrs)
+
+	payout := SendOutput{
+		Amount:  500,
+		AssetID: genesisTx.ID().String(),
+		To:      addrStr,
+	}
+	args := &SendBatchArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+			JSONFromAddrs: api.JSONFromAddrs{From: fromAddrsStr},
+		},
+		Payouts: []SendOutput{payout, payout, payout},
+		// Each transaction can make a single payout and pay its change
+		MaxOutputsPerTx: 2,
+	}
+
+	// The second transaction fails to be issued
+	errIssue := errors.New("issue failed")
+	numIssued := 0
+	issue := func(txBytes []byte) (ids.ID, error) {
+		if numIssued == 1 {
+			return ids.ID{}, errIssue
+		}
+		numIssued++
+		return ws.issue(txBytes)
+	}
+
+	vm.timer.Cancel()
+	reply := &SendBatchReply{}
+	err = vm.sendBatch(args, reply, ws.update, issue)
+	jsonErr, ok := err.(*json2.Error)
+	if !ok {
+		t.Fatalf("expected a *json2.Error but got %v", err)
+	}
+	if len(reply.Txs) != 1 {
+		t.Fatalf("expected 1 issued tx to be reported but got %d", len(reply.Txs))
+	}
+	if _, ok := ws.pendingTxMap[reply.Txs[0].TxID]; !ok {
+		t.Fatalf("transaction %s wasn't issued", reply.Txs[0].TxID)
+	}
+	if uint64(reply.Fee) != vm.TxFee {
+		t.Fatalf("expected fee to be %d but got %d", vm.TxFee, reply.Fee)
+	}
+
+	// The issued transactions are reported to JSON-RPC clients
+	errBytes, err := stdjson.Marshal(jsonErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedErr := &json2.Error{}
+	if err := stdjson.Unmarshal(errBytes, decodedErr); err != nil {
+		t.Fatal(err)
+	}
+	clientReply := &SendBatchReply{}
+	decodePartialBatchReply(decodedErr, clientReply)
+	if len(clientReply.Txs) != 1 || clientReply.Txs[0].TxID != reply.Txs[0].TxID {
+		t.Fatalf("expected the client to learn about tx %s but got %v", reply.Txs[0].TxID, clientReply.Txs)
+	}
+	if clientReply.Fee != reply.Fee {
+		t.Fatalf("expected the client to learn about fee %d but got %d", reply.Fee, clientReply.Fee)
+	}
+}
+
+// restrictedRequest returns a request whose auth token only allows using
+// [assetIDs]
+func restrictedRequest(t *testing.T, assetIDs ...ids.ID) *http.Request {
+	a, err := auth.New(logging.NoLog{}, "auth", "Pa$$w0rd!Pa$$w0rd!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := a.NewToken("Pa$$w0rd!Pa$$w0rd!", time.Hour, []string{"*"}, assetIDs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restrictedReq *http.Request
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X/wallet", nil)
+	req.Header.Add("Authorization", "Bearer "+token)
+	a.WrapHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		restrictedReq = r
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	if restrictedReq == nil {
+		t.Fatal("request wasn't authenticated")
+	}
+	return restrictedReq
+}
+
+func TestWalletService_SendRestrictedAssets(t *testing.T) {
+	genesisBytes, vm, ws, _, _ := setupWSWithKeys(t, false)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	// The fee is paid in a different asset
+	assetID := GetCreateTxFromGenesisTest(t, genesisBytes, otherAssetName).ID()
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fromAddrsStr := sampleAddrs(t, vm, addrs)
+
+	args := &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+			JSONFromAddrs: api.JSONFromAddrs{From: fromAddrsStr},
+		},
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: assetID.String(),
+			To:      addrStr,
+		},
+	}
+	vm.timer.Cancel()
+
+	// The token doesn't allow sending [assetID]
+	reply := &api.JSONTxIDChangeAddr{}
+	if err := ws.Send(restrictedRequest(t, ids.GenerateTestID()), args, reply); !errors.Is(err, errAssetNotAuthorized) {
+		t.Fatalf("expected %s but got %v", errAssetNotAuthorized, err)
+	}
+	batchArgs := &SendBatchArgs{
+		JSONSpendHeader: args.JSONSpendHeader,
+		Payouts:         []SendOutput{args.SendOutput},
+	}
+	if err := ws.SendBatch(restrictedRequest(t, ids.GenerateTestID()), batchArgs, &SendBatchReply{}); !errors.Is(err, errAssetNotAuthorized) {
+		t.Fatalf("expected %s but got %v", errAssetNotAuthorized, err)
+	}
+	if len(vm.PendingTxs()) != 0 {
+		t.Fatal("no transaction should have been issued")
+	}
+
+	// The token doesn't need to allow the fee asset
+	if err := ws.Send(restrictedRequest(t, assetID), args, reply); err != nil {
+		t.Fatalf("Failed to send transaction: %s", err)
+	}
+	pendingTxs := vm.PendingTxs()
+	if len(pendingTxs) != 1 {
+		t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
+	}
+
+	// Issuing a raw transaction requires every asset it uses to be allowed
+	formatted, err := formatting.EncodeWithChecksum(formatting.Hex, pendingTxs[0].Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	issueArgs := &api.FormattedTx{Tx: formatted, Encoding: formatting.Hex}
+	if err := ws.IssueTx(restrictedRequest(t, assetID), issueArgs, &api.JSONTxID{}); !errors.Is(err, errAssetNotAuthorized) {
+		t.Fatalf("expected %s but got %v", errAssetNotAuthorized, err)
+	}
+	if err := ws.IssueTx(restrictedRequest(t, assetID, vm.feeAssetID), issueArgs, &api.JSONTxID{}); err != nil {
+		t.Fatalf("Failed to issue transaction: %s", err)
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/keystore"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+
+	safemath "github.com/Toinounet21/avalanchego-mod/utils/math"
+)
+
+var (
+	errNoWatchOnlyAddresses = errors.New("user doesn't watch any of the addresses")
+	errNotWatched           = errors.New("address isn't watched by the user")
+)
+
+// AddWatchOnlyAddressesArgs are arguments for AddWatchOnlyAddresses
+type AddWatchOnlyAddressesArgs struct {
+	api.UserPass
+	// Addresses to watch
+	Addresses []string `json:"addresses"`
+}
+
+// AddWatchOnlyAddresses registers addresses that user [args.Username] watches
+// without holding their private keys. The wallet APIs that take a user's
+// watch-only addresses can report their balances and build unsigned
+// transactions that spend from them.
+//
+// Extended public keys aren't supported, so each address must be registered.
+// Events for the watched addresses can be subscribed to with the pubsub
+// address filter.
+func (service *Service) AddWatchOnlyAddresses(_ *http.Request, args *AddWatchOnlyAddressesArgs, reply *api.SuccessResponse) error {
+	service.vm.ctx.Log.Debug("AVM: AddWatchOnlyAddresses called for user '%s' with %d addresses", args.Username, len(args.Addresses))
+
+	addrs := make([]ids.ShortID, len(args.Addresses))
+	for i, addrStr := range args.Addresses {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addrs[i] = addr
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	if err := user.PutWatchOnlyAddresses(addrs...); err != nil {
+		// Drop any potential error closing the database to report the
+		// original error
+		_ = user.Close()
+		return fmt.Errorf("problem saving watch-only addresses: %w", err)
+	}
+
+	reply.Success = true
+	return user.Close()
+}
+
+// ListWatchOnlyAddresses returns the addresses that user [args.Username]
+// watches without holding their private keys
+func (service *Service) ListWatchOnlyAddresses(_ *http.Request, args *api.UserPass, reply *api.JSONAddresses) error {
+	service.vm.ctx.Log.Debug("AVM: ListWatchOnlyAddresses called for user '%s'", args.Username)
+
+	addrs, err := service.vm.loadWatchOnlyAddresses(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	reply.Addresses = make([]string, len(addrs))
+	for i, addr := range addrs {
+		reply.Addresses[i], err = service.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetWatchOnlyBalancesArgs are arguments for GetWatchOnlyBalances
+type GetWatchOnlyBalancesArgs struct {
+	api.UserPass
+	IncludePartial bool `json:"includePartial"`
+}
+
+// GetWatchOnlyBalances returns the total balance of each asset held by the
+// addresses that user [args.Username] watches. [args.IncludePartial] has the
+// same meaning as in GetAllBalances.
+func (service *Service) GetWatchOnlyBalances(_ *http.Request, args *GetWatchOnlyBalancesArgs, reply *GetAllBalancesReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetWatchOnlyBalances called for user '%s'", args.Username)
+
+	addrs, err := service.vm.loadWatchOnlyAddresses(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	addrSet := ids.NewShortSet(len(addrs))
+	addrSet.Add(addrs...)
+
+	reply.Balances, err = service.getBalances(addrSet, args.IncludePartial)
+	return err
+}
+
+// BuildUnsignedSendArgs are arguments for BuildUnsignedSend
+type BuildUnsignedSendArgs struct {
+	api.UserPass
+
+	// Watch-only addresses to spend from. If empty, any of the user's
+	// watch-only addresses may be spent from.
+	From []string `json:"from"`
+
+	// Watch-only address that receives the change. If empty, the first
+	// watch-only address is used.
+	ChangeAddr string `json:"changeAddr"`
+
+	// The outputs of the transaction
+	Outputs []SendOutput `json:"outputs"`
+
+	// Memo field
+	Memo string `json:"memo"`
+
+	// Encoding of the returned transaction
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BuildUnsignedSendReply is the response from BuildUnsignedSend
+type BuildUnsignedSendReply struct {
+	// The unsigned transaction
+	UnsignedTx string              `json:"unsignedTx"`
+	Encoding   formatting.Encoding `json:"encoding"`
+
+	// For each input of the transaction, in order, the addresses whose
+	// signatures the input's credential must contain, in order
+	Signers [][]string `json:"signers"`
+
+	api.JSONChangeAddr
+}
+
+// BuildUnsignedSend builds, but doesn't sign or issue, a transaction that
+// sends [args.Outputs] from the watch-only addresses of user [args.Username].
+// The transaction must be signed by the holders of the keys of [reply.Signers]
+// before it's issued.
+func (service *Service) BuildUnsignedSend(_ *http.Request, args *BuildUnsignedSendArgs, reply *BuildUnsignedSendReply) error {
+	service.vm.ctx.Log.Debug("AVM: BuildUnsignedSend called for user '%s'", args.Username)
+
+	// Validate the memo field
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > avax.MaxMemoSize {
+		return fmt.Errorf("max memo length is %d but provided memo field is length %d",
+			avax.MaxMemoSize,
+			l)
+	} else if len(args.Outputs) == 0 {
+		return errNoOutputs
+	}
+
+	watchOnlyAddrs, err := service.vm.loadWatchOnlyAddresses(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	if len(watchOnlyAddrs) == 0 {
+		return errNoWatchOnlyAddresses
+	}
+	watched := ids.NewShortSet(len(watchOnlyAddrs))
+	watched.Add(watchOnlyAddrs...)
+
+	// Parse the from addresses
+	fromAddrs := watched
+	if len(args.From) > 0 {
+		fromAddrs = ids.NewShortSet(len(args.From))
+		for _, addrStr := range args.From {
+			addr, err := service.vm.ParseLocalAddress(addrStr)
+			if err != nil {
+				return fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+			}
+			if !watched.Contains(addr) {
+				return fmt.Errorf("%w: %s", errNotWatched, addrStr)
+			}
+			fromAddrs.Add(addr)
+		}
+	}
+
+	// Parse the change address
+	changeAddr := watchOnlyAddrs[0]
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse change address: %w", err)
+		}
+	}
+
+	baseTx, signers, changeAddr, err := service.vm.buildUnsignedSendTx(fromAddrs, changeAddr, args.Outputs, memoBytes)
+	if err != nil {
+		return err
+	}
+	var unsignedTx UnsignedTx = baseTx
+	unsignedBytes, err := service.vm.codec.Marshal(codecVersion, &unsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	reply.UnsignedTx, err = formatting.EncodeWithChecksum(args.Encoding, unsignedBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't encode transaction: %w", err)
+	}
+	reply.Encoding = args.Encoding
+	reply.Signers = make([][]string, len(signers))
+	for i, inputSigners := range signers {
+		reply.Signers[i] = make([]string, len(inputSigners))
+		for j, signer := range inputSigners {
+			reply.Signers[i][j], err = service.vm.FormatLocalAddress(signer)
+			if err != nil {
+				return fmt.Errorf("problem formatting address: %w", err)
+			}
+		}
+	}
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// buildUnsignedSendTx returns an unsigned transaction that sends [outputs] from
+// [fromAddrs], for each of its inputs, the addresses that must sign it, and
+// the address that receives the change. If [changeAddr] is empty, the change
+// is sent to the first signer of the first input.
+func (vm *VM) buildUnsignedSendTx(
+	fromAddrs ids.ShortSet,
+	changeAddr ids.ShortID,
+	outputs []SendOutput,
+	memo []byte,
+) (*BaseTx, [][]ids.ShortID, ids.ShortID, error) {
+	// Asset ID --> amount of that asset being sent, including the fee
+	amounts := map[ids.ID]uint64{
+		vm.feeAssetID: vm.txFee(),
+	}
+	outs := []*avax.TransferableOutput{}
+	for _, output := range outputs {
+		if output.Amount == 0 {
+			return nil, nil, ids.ShortEmpty, errZeroAmount
+		}
+		assetID, err := vm.lookupAssetID(output.AssetID)
+		if err != nil {
+			return nil, nil, ids.ShortEmpty, fmt.Errorf("couldn't find asset %s", output.AssetID)
+		}
+		amounts[assetID], err = safemath.Add64(amounts[assetID], uint64(output.Amount))
+		if err != nil {
+			return nil, nil, ids.ShortEmpty, fmt.Errorf("problem calculating required spend amount: %w", err)
+		}
+
+		to, err := vm.ParseLocalAddress(output.To)
+		if err != nil {
+			return nil, nil, ids.ShortEmpty, fmt.Errorf("problem parsing to address %q: %w", output.To, err)
+		}
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: uint64(output.Amount),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		})
+	}
+
+	utxos, err := avax.GetAllUTXOs(vm.state, fromAddrs)
+	if err != nil {
+		return nil, nil, ids.ShortEmpty, fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+	amountsSpent, ins, signers, err := vm.spendWatchOnly(utxos, fromAddrs, amounts)
+	if err != nil {
+		return nil, nil, ids.ShortEmpty, err
+	}
+	if changeAddr == ids.ShortEmpty {
+		// Every transaction has at least one input, since it pays a fee
+		changeAddr = signers[0][0]
+	}
+
+	// Add the required change outputs
+	for assetID, amount := range amounts {
+		if amountSpent := amountsSpent[assetID]; amountSpent > amount {
+			outs = append(outs, &avax.TransferableOutput{
+				Asset: avax.Asset{ID: assetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amountSpent - amount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{changeAddr},
+					},
+				},
+			})
+		}
+	}
+	avax.SortTransferableOutputs(outs, vm.codec)
+
+	unsignedTx := &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    vm.ctx.NetworkID,
+		BlockchainID: vm.ctx.ChainID,
+		Outs:         outs,
+		Ins:          ins,
+		Memo:         memo,
+	}}
+	return unsignedTx, signers, changeAddr, nil
+}
+
+// loadWatchOnlyAddresses returns the watch-only addresses of the user
+func (vm *VM) loadWatchOnlyAddresses(username, password string) ([]ids.ShortID, error) {
+	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
+	if err != nil {
+		return nil, err
+	}
+	// Drop any potential error closing the database to report the original
+	// error
+	defer user.Close()
+
+	addrs, err := user.GetWatchOnlyAddresses()
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving watch-only addresses: %w", err)
+	}
+	return addrs, user.Close()
+}
+
+// spendWatchOnly is like Spend, but the inputs are spent by [addrs], whose
+// keys aren't known. Returns, for each of the sorted inputs, the addresses
+// that must sign it.
+func (vm *VM) spendWatchOnly(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	amounts map[ids.ID]uint64,
+) (
+	map[ids.ID]uint64,
+	[]*avax.TransferableInput,
+	[][]ids.ShortID,
+	error,
+) {
+	amountsSpent := make(map[ids.ID]uint64, len(amounts))
+	now := vm.clock.Unix()
+
+	ins := []*avax.TransferableInput{}
+	// Input ID --> Addresses that must sign the input
+	inputSigners := make(map[ids.ID][]ids.ShortID)
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		if amountsSpent[assetID] >= amounts[assetID] {
+			// we already have enough inputs allocated to this asset
+			continue
+		}
+
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || now < out.Locktime {
+			continue
+		}
+		sigIndices := make([]uint32, 0, out.Threshold)
+		signers := make([]ids.ShortID, 0, out.Threshold)
+		for i, addr := range out.Addrs {
+			if uint32(len(signers)) == out.Threshold {
+				break
+			}
+			if addrs.Contains(addr) {
+				sigIndices = append(sigIndices, uint32(i))
+				signers = append(signers, addr)
+			}
+		}
+		if uint32(len(signers)) != out.Threshold {
+			// these addresses can't spend this utxo
+			continue
+		}
+
+		newAmountSpent, err := safemath.Add64(amountsSpent[assetID], out.Amt)
+		if err != nil {
+			return nil, nil, nil, errSpendOverflow
+		}
+		amountsSpent[assetID] = newAmountSpent
+
+		ins = append(ins, &avax.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  avax.Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt:   out.Amt,
+				Input: secp256k1fx.Input{SigIndices: sigIndices},
+			},
+		})
+		inputSigners[utxo.InputID()] = signers
+	}
+
+	for assetID, amount := range amounts {
+		if amountsSpent[assetID] < amount {
+			return nil, nil, nil, fmt.Errorf("want to spend %d of asset %s but only have %d",
+				amount,
+				assetID,
+				amountsSpent[assetID],
+			)
+		}
+	}
+
+	avax.SortTransferableInputs(ins)
+	signers := make([][]ids.ShortID, len(ins))
+	for i, in := range ins {
+		signers[i] = inputSigners[in.InputID()]
+	}
+	return amountsSpent, ins, signers, nil
+}
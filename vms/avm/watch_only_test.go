@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/api"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+)
+
+func TestServiceWatchOnlyAddresses(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	userPass := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+
+	// The user doesn't hold any keys, but watches the funded addresses
+	addrsStr := make([]string, len(addrs))
+	for i, addr := range addrs {
+		addrStr, err := vm.FormatLocalAddress(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrsStr[i] = addrStr
+	}
+	if err := s.AddWatchOnlyAddresses(nil, &AddWatchOnlyAddressesArgs{
+		UserPass:  userPass,
+		Addresses: addrsStr,
+	}, &api.SuccessResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	listReply := &api.JSONAddresses{}
+	if err := s.ListWatchOnlyAddresses(nil, &userPass, listReply); err != nil {
+		t.Fatal(err)
+	} else if len(listReply.Addresses) != len(addrs) {
+		t.Fatalf("expected %d watch-only addresses but got %d", len(addrs), len(listReply.Addresses))
+	}
+
+	balancesReply := &GetAllBalancesReply{}
+	if err := s.GetWatchOnlyBalances(nil, &GetWatchOnlyBalancesArgs{UserPass: userPass}, balancesReply); err != nil {
+		t.Fatal(err)
+	} else if len(balancesReply.Balances) != 1 {
+		t.Fatalf("expected 1 balance but got %d", len(balancesReply.Balances))
+	} else if expected := startBalance * uint64(len(addrs)); uint64(balancesReply.Balances[0].Balance) != expected {
+		t.Fatalf("expected balance %d but got %d", expected, balancesReply.Balances[0].Balance)
+	}
+
+	changeAddrStr, err := vm.FormatLocalAddress(testChangeAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toAddrStr, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendReply := &BuildUnsignedSendReply{}
+	if err := s.BuildUnsignedSend(nil, &BuildUnsignedSendArgs{
+		UserPass:   userPass,
+		From:       addrsStr[:1],
+		ChangeAddr: changeAddrStr,
+		Outputs: []SendOutput{{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      toAddrStr,
+		}},
+		Encoding: formatting.Hex,
+	}, sendReply); err != nil {
+		t.Fatal(err)
+	} else if sendReply.ChangeAddr != changeAddrStr {
+		t.Fatalf("expected change address to be %s but got %s", changeAddrStr, sendReply.ChangeAddr)
+	}
+
+	unsignedBytes, err := formatting.Decode(sendReply.Encoding, sendReply.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unsignedTx UnsignedTx
+	if _, err := vm.codec.Unmarshal(unsignedBytes, &unsignedTx); err != nil {
+		t.Fatal(err)
+	}
+	ins := unsignedTx.InputUTXOs()
+	if len(ins) != 1 {
+		t.Fatalf("expected 1 input but got %d", len(ins))
+	} else if len(sendReply.Signers) != len(ins) {
+		t.Fatalf("expected signers for %d inputs but got %d", len(ins), len(sendReply.Signers))
+	} else if len(sendReply.Signers[0]) != 1 || sendReply.Signers[0][0] != addrsStr[0] {
+		t.Fatalf("expected input to be signed by %s but got %v", addrsStr[0], sendReply.Signers[0])
+	}
+	// The sent amount, the change and the fee are accounted for
+	if outs := unsignedTx.(*BaseTx).Outs; len(outs) != 2 {
+		t.Fatalf("expected 2 outputs but got %d", len(outs))
+	}
+
+	// Addresses that aren't watched can't be spent from
+	unwatchedAddrStr, err := vm.FormatLocalAddress(testChangeAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.BuildUnsignedSend(nil, &BuildUnsignedSendArgs{
+		UserPass: userPass,
+		From:     []string{unwatchedAddrStr},
+		Outputs: []SendOutput{{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      toAddrStr,
+		}},
+	}, &BuildUnsignedSendReply{})
+	if !errors.Is(err, errNotWatched) {
+		t.Fatalf("expected %s but got %v", errNotWatched, err)
+	}
+}
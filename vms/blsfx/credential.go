@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+)
+
+var errNilCredential = errors.New("nil credential")
+
+const (
+	defaultEncoding = formatting.Hex
+)
+
+// Credential carries a single signature that aggregates the signatures of
+// every signer of an input
+type Credential struct {
+	Sig [crypto.BLSSigLen]byte `serialize:"true" json:"signature"`
+}
+
+// MarshalJSON marshals [cr] to JSON
+// The string representation of the signature is created using the hex formatter
+func (cr *Credential) MarshalJSON() ([]byte, error) {
+	sigStr, err := formatting.EncodeWithoutChecksum(defaultEncoding, cr.Sig[:])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't convert signature to string: %w", err)
+	}
+	return json.Marshal(map[string]interface{}{
+		"signature": sigStr,
+	})
+}
+
+func (cr *Credential) Verify() error {
+	switch {
+	case cr == nil:
+		return errNilCredential
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+)
+
+// ID that this Fx uses when labeled
+var (
+	ID = ids.ID{'b', 'l', 's', 'f', 'x'}
+)
+
+type Factory struct{}
+
+func (f *Factory) New(*snow.Context) (interface{}, error) { return &Fx{}, nil }
@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+var (
+	errWrongVMType                 = errors.New("wrong vm type")
+	errWrongTxType                 = errors.New("wrong tx type")
+	errWrongOpType                 = errors.New("wrong operation type")
+	errWrongUTXOType               = errors.New("wrong utxo type")
+	errWrongInputType              = errors.New("wrong input type")
+	errWrongCredentialType         = errors.New("wrong credential type")
+	errTimelocked                  = errors.New("output is time locked")
+	errTooManySigners              = errors.New("input has more signers than expected")
+	errTooFewSigners               = errors.New("input has less signers than expected")
+	errInputOutputIndexOutOfBounds = errors.New("input referenced a nonexistent public key in the output")
+	errInvalidAggregateSignature   = errors.New("aggregate signature doesn't match the signers")
+)
+
+// Fx describes the BLS feature extension. Every input is spent with a single
+// aggregated signature, regardless of the number of signers.
+type Fx struct {
+	VM           secp256k1fx.VM
+	BLSFactory   crypto.FactoryBLS
+	bootstrapped bool
+}
+
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	vm, ok := vmIntf.(secp256k1fx.VM)
+	if !ok {
+		return errWrongVMType
+	}
+	fx.VM = vm
+
+	log := fx.VM.Logger()
+	log.Debug("initializing bls fx")
+
+	c := fx.VM.CodecRegistry()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&TransferInput{}),
+		c.RegisterType(&TransferOutput{}),
+		c.RegisterType(&Credential{}),
+	)
+	return errs.Err
+}
+
+func (fx *Fx) Bootstrapping() error { return nil }
+
+func (fx *Fx) Bootstrapped() error { fx.bootstrapped = true; return nil }
+
+// VerifyOperation always fails, as this fx doesn't define any operations
+func (fx *Fx) VerifyOperation(interface{}, interface{}, interface{}, []interface{}) error {
+	return errWrongOpType
+}
+
+func (fx *Fx) VerifyTransfer(txIntf, inIntf, credIntf, utxoIntf interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	in, ok := inIntf.(*TransferInput)
+	if !ok {
+		return errWrongInputType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	out, ok := utxoIntf.(*TransferOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	return fx.VerifySpend(tx, in, cred, out)
+}
+
+// VerifySpend ensures that the utxo can be sent to any address
+func (fx *Fx) VerifySpend(tx secp256k1fx.Tx, in *TransferInput, cred *Credential, utxo *TransferOutput) error {
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	} else if utxo.Amt != in.Amt {
+		return fmt.Errorf("utxo amount and input amount should be same but are %d and %d", utxo.Amt, in.Amt)
+	}
+
+	return fx.VerifyCredentials(tx, &in.Input, cred, &utxo.OutputOwners)
+}
+
+// VerifyCredentials ensures that the output can be spent by the input with the
+// credential. A nil return values means the output can be spent.
+func (fx *Fx) VerifyCredentials(tx secp256k1fx.Tx, in *Input, cred *Credential, out *OutputOwners) error {
+	numSigs := len(in.SigIndices)
+	switch {
+	case out.Locktime > fx.VM.Clock().Unix():
+		return errTimelocked
+	case out.Threshold < uint32(numSigs):
+		return errTooManySigners
+	case out.Threshold > uint32(numSigs):
+		return errTooFewSigners
+	case numSigs == 0: // nothing to sign when the output has no owners
+		return nil
+	case !fx.bootstrapped: // disable signature verification during bootstrapping
+		return nil
+	}
+
+	pks := make([]*crypto.PublicKeyBLS, numSigs)
+	for i, index := range in.SigIndices {
+		// Make sure the input references a public key that exists
+		if index >= uint32(len(out.PublicKeys)) {
+			return errInputOutputIndexOutOfBounds
+		}
+		pk, err := fx.BLSFactory.ToPublicKey(out.PublicKeys[index][:])
+		if err != nil {
+			return err
+		}
+		pks[i] = pk.(*crypto.PublicKeyBLS)
+	}
+
+	txHash := hashing.ComputeHash256(tx.UnsignedBytes())
+	if !crypto.AggregateVerifyBLSHash(pks, txHash, cred.Sig[:]) {
+		return errInvalidAggregateSignature
+	}
+	return nil
+}
+
+// NewCredential returns a credential with the aggregated signature of [keys]
+// over [tx]. The keys must be those referenced by the signature indices of the
+// input being spent.
+func NewCredential(tx secp256k1fx.Tx, keys []*crypto.PrivateKeyBLS) (*Credential, error) {
+	cred := &Credential{}
+	if len(keys) == 0 {
+		return cred, nil
+	}
+
+	txHash := hashing.ComputeHash256(tx.UnsignedBytes())
+	sigs := make([][]byte, len(keys))
+	for i, key := range keys {
+		sig, err := key.SignHash(txHash)
+		if err != nil {
+			return nil, err
+		}
+		sigs[i] = sig
+	}
+	sig, err := crypto.AggregateBLSSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	copy(cred.Sig[:], sig)
+	return cred, nil
+}
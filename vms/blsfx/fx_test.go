@@ -0,0 +1,248 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+var txBytes = []byte{0, 1, 2, 3, 4, 5}
+
+// newTestKeys returns [n] private keys sorted by their public keys
+func newTestKeys(t *testing.T, n int) ([]*crypto.PrivateKeyBLS, [][crypto.BLSPKLen]byte) {
+	factory := crypto.FactoryBLS{}
+	sks := make([]*crypto.PrivateKeyBLS, n)
+	for i := range sks {
+		sk, err := factory.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sks[i] = sk.(*crypto.PrivateKeyBLS)
+	}
+	sort.Slice(sks, func(i, j int) bool {
+		return bytes.Compare(sks[i].PublicKey().Bytes(), sks[j].PublicKey().Bytes()) < 0
+	})
+	pks := make([][crypto.BLSPKLen]byte, n)
+	for i, sk := range sks {
+		copy(pks[i][:], sk.PublicKey().Bytes())
+	}
+	return sks, pks
+}
+
+func newTestFx(t *testing.T) *Fx {
+	vm := &secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	vm.CLK.Set(time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC))
+	fx := &Fx{}
+	if err := fx.Initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapping(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	return fx
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err != errWrongVMType {
+		t.Fatalf("expected %v but got %v", errWrongVMType, err)
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	fx := newTestFx(t)
+	sks, pks := newTestKeys(t, 3)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	out := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Threshold:  2,
+			PublicKeys: pks,
+		},
+	}
+	in := &TransferInput{
+		Amt: 1,
+		Input: Input{
+			SigIndices: []uint32{0, 2},
+		},
+	}
+	cred, err := NewCredential(tx, []*crypto.PrivateKeyBLS{sks[0], sks[2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyTransfer(tx, in, cred, out); err != nil {
+		t.Fatal(err)
+	}
+
+	wrongCred, err := NewCredential(tx, []*crypto.PrivateKeyBLS{sks[0], sks[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyTransfer(tx, in, wrongCred, out); err != errInvalidAggregateSignature {
+		t.Fatalf("expected %v but got %v", errInvalidAggregateSignature, err)
+	}
+
+	otherTx := &secp256k1fx.TestTx{Bytes: []byte{6}}
+	if err := fx.VerifyTransfer(otherTx, in, cred, out); err != errInvalidAggregateSignature {
+		t.Fatalf("expected %v but got %v", errInvalidAggregateSignature, err)
+	}
+}
+
+func TestFxVerifyTransferInvalid(t *testing.T) {
+	fx := newTestFx(t)
+	sks, pks := newTestKeys(t, 1)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	cred, err := NewCredential(tx, sks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newOutput := func() *TransferOutput {
+		return &TransferOutput{
+			Amt: 1,
+			OutputOwners: OutputOwners{
+				Threshold:  1,
+				PublicKeys: pks,
+			},
+		}
+	}
+	newInput := func() *TransferInput {
+		return &TransferInput{
+			Amt: 1,
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		tx          interface{}
+		in          interface{}
+		cred        interface{}
+		out         interface{}
+		expectedErr error
+	}{
+		{
+			name:        "wrong tx",
+			in:          newInput(),
+			cred:        cred,
+			out:         newOutput(),
+			expectedErr: errWrongTxType,
+		},
+		{
+			name:        "wrong input",
+			tx:          tx,
+			cred:        cred,
+			out:         newOutput(),
+			expectedErr: errWrongInputType,
+		},
+		{
+			name:        "wrong credential",
+			tx:          tx,
+			in:          newInput(),
+			out:         newOutput(),
+			expectedErr: errWrongCredentialType,
+		},
+		{
+			name:        "wrong utxo",
+			tx:          tx,
+			in:          newInput(),
+			cred:        cred,
+			expectedErr: errWrongUTXOType,
+		},
+		{
+			name: "timelocked",
+			tx:   tx,
+			in:   newInput(),
+			cred: cred,
+			out: func() *TransferOutput {
+				out := newOutput()
+				out.Locktime = uint64(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+				return out
+			}(),
+			expectedErr: errTimelocked,
+		},
+		{
+			name: "too few signers",
+			tx:   tx,
+			in: func() *TransferInput {
+				in := newInput()
+				in.SigIndices = nil
+				return in
+			}(),
+			cred:        cred,
+			out:         newOutput(),
+			expectedErr: errTooFewSigners,
+		},
+		{
+			name: "out of bounds signer",
+			tx:   tx,
+			in: func() *TransferInput {
+				in := newInput()
+				in.SigIndices = []uint32{1}
+				return in
+			}(),
+			cred:        cred,
+			out:         newOutput(),
+			expectedErr: errInputOutputIndexOutOfBounds,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := fx.VerifyTransfer(test.tx, test.in, test.cred, test.out); err != test.expectedErr {
+				t.Fatalf("expected %v but got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestFxVerifyTransferNotBootstrapped(t *testing.T) {
+	vm := &secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	fx := &Fx{}
+	if err := fx.Initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	_, pks := newTestKeys(t, 1)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	out := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Threshold:  1,
+			PublicKeys: pks,
+		},
+	}
+	in := &TransferInput{
+		Amt: 1,
+		Input: Input{
+			SigIndices: []uint32{0},
+		},
+	}
+	if err := fx.VerifyTransfer(tx, in, &Credential{}, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyOperation(t *testing.T) {
+	fx := newTestFx(t)
+	if err := fx.VerifyOperation(nil, nil, nil, nil); err != errWrongOpType {
+		t.Fatalf("expected %v but got %v", errWrongOpType, err)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"errors"
+
+	"github.com/Toinounet21/avalanchego-mod/utils"
+	"github.com/Toinounet21/avalanchego-mod/utils/math"
+)
+
+const (
+	// CostPerSigner is charged for every signer of an input, as each signer
+	// adds a pairing to the verification of the aggregated signature
+	CostPerSigner uint64 = 2000
+)
+
+var (
+	errNilInput        = errors.New("nil input")
+	errNotSortedUnique = errors.New("signers not sorted and unique")
+)
+
+type Input struct {
+	// SigIndices[i] is the index of the public key in the output's owner list
+	// whose signature is included in the credential's aggregated signature.
+	SigIndices []uint32 `serialize:"true" json:"signatureIndices"`
+}
+
+func (in *Input) Cost() (uint64, error) {
+	numSigs := uint64(len(in.SigIndices))
+	return math.Mul64(numSigs, CostPerSigner)
+}
+
+// Verify this input is syntactically valid
+func (in *Input) Verify() error {
+	switch {
+	case in == nil:
+		return errNilInput
+	case !utils.IsSortedAndUniqueUint32(in.SigIndices):
+		return errNotSortedUnique
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+)
+
+var (
+	errNilOutput                 = errors.New("nil output")
+	errOutputUnspendable         = errors.New("output is unspendable")
+	errOutputUnoptimized         = errors.New("output representation should be optimized")
+	errPublicKeysNotSortedUnique = errors.New("public keys not sorted and unique")
+
+	_ verify.State = &OutputOwners{}
+)
+
+// OutputOwners lists the public keys that control an output. Unlike
+// secp256k1fx, BLS signatures don't allow recovering the signer's public key,
+// so the public keys are stored in the output rather than their addresses.
+type OutputOwners struct {
+	Locktime   uint64                  `serialize:"true" json:"locktime"`
+	Threshold  uint32                  `serialize:"true" json:"threshold"`
+	PublicKeys [][crypto.BLSPKLen]byte `serialize:"true" json:"publicKeys"`
+}
+
+func (out *OutputOwners) InitCtx(*snow.Context) {}
+
+// MarshalJSON marshals OutputOwners as JSON with hex encoded public keys
+func (out *OutputOwners) MarshalJSON() ([]byte, error) {
+	result, err := out.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(result)
+}
+
+// Fields returns JSON keys in a map that can be used with marshal JSON
+// to serialise OutputOwners struct
+func (out *OutputOwners) Fields() (map[string]interface{}, error) {
+	publicKeys := make([]string, len(out.PublicKeys))
+	for i, pk := range out.PublicKeys {
+		pkStr, err := formatting.EncodeWithoutChecksum(defaultEncoding, pk[:])
+		if err != nil {
+			return nil, err
+		}
+		publicKeys[i] = pkStr
+	}
+	return map[string]interface{}{
+		"locktime":   out.Locktime,
+		"threshold":  out.Threshold,
+		"publicKeys": publicKeys,
+	}, nil
+}
+
+// Addresses returns the addresses of the public keys that manage this output
+func (out *OutputOwners) Addresses() [][]byte {
+	addrs := make([][]byte, len(out.PublicKeys))
+	for i, pk := range out.PublicKeys {
+		addrs[i] = hashing.PubkeyBytesToAddress(pk[:])
+	}
+	return addrs
+}
+
+// Equals returns true if the provided owners create the same condition
+func (out *OutputOwners) Equals(other *OutputOwners) bool {
+	if out == other {
+		return true
+	}
+	if out == nil || other == nil || out.Locktime != other.Locktime || out.Threshold != other.Threshold || len(out.PublicKeys) != len(other.PublicKeys) {
+		return false
+	}
+	for i, pk := range out.PublicKeys {
+		if pk != other.PublicKeys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (out *OutputOwners) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case out.Threshold > uint32(len(out.PublicKeys)):
+		return errOutputUnspendable
+	case out.Threshold == 0 && len(out.PublicKeys) > 0:
+		return errOutputUnoptimized
+	case !isSortedAndUnique(out.PublicKeys):
+		return errPublicKeysNotSortedUnique
+	default:
+		return nil
+	}
+}
+
+func (out *OutputOwners) VerifyState() error { return out.Verify() }
+
+func isSortedAndUnique(pks [][crypto.BLSPKLen]byte) bool {
+	for i := 1; i < len(pks); i++ {
+		if bytes.Compare(pks[i-1][:], pks[i][:]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blsfx
+
+import (
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+func TestOutputOwnersVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		out         *OutputOwners
+		expectedErr error
+	}{
+		{
+			name:        "nil",
+			out:         nil,
+			expectedErr: errNilOutput,
+		},
+		{
+			name: "no owners",
+			out:  &OutputOwners{},
+		},
+		{
+			name: "valid",
+			out: &OutputOwners{
+				Threshold:  1,
+				PublicKeys: [][crypto.BLSPKLen]byte{{1}, {2}},
+			},
+		},
+		{
+			name: "unspendable",
+			out: &OutputOwners{
+				Threshold:  3,
+				PublicKeys: [][crypto.BLSPKLen]byte{{1}, {2}},
+			},
+			expectedErr: errOutputUnspendable,
+		},
+		{
+			name: "unoptimized",
+			out: &OutputOwners{
+				PublicKeys: [][crypto.BLSPKLen]byte{{1}},
+			},
+			expectedErr: errOutputUnoptimized,
+		},
+		{
+			name: "unsorted",
+			out: &OutputOwners{
+				Threshold:  1,
+				PublicKeys: [][crypto.BLSPKLen]byte{{2}, {1}},
+			},
+			expectedErr: errPublicKeysNotSortedUnique,
+		},
+		{
+			name: "duplicated",
+			out: &OutputOwners{
+				Threshold:  1,
+				PublicKeys: [][crypto.BLSPKLen]byte{{1}, {1}},
+			},
+			expectedErr: errPublicKeysNotSortedUnique,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.out.Verify(); err != test.expectedErr {
+				t.Fatalf("expected %v but got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestOutputOwnersEquals(t *testing.T) {
+	out := &OutputOwners{
+		Threshold:  1,
+		PublicKeys: [][crypto.BLSPKLen]byte{{1}, {2}},
+	}
+	same := &OutputOwners{
+		Threshold:  1,
+		PublicKeys: [][crypto.BLSPKLen]byte{{1}, {2}},
+	}
+	different := &OutputOwners{
+		Threshold:  1,
+		PublicKeys: [][crypto.BLSPKLen]byte{{1}, {3}},
+	}
+	if !out.Equals(same) {
+		t.Fatalf("owners should have been equal")
+	}
+	if out.Equals(different) {
+		t.Fatalf("owners shouldn't have been equal")
+	}
+	if out.Equals(nil) {
+		t.Fatalf("owners shouldn't have equaled nil")
+	}
+}
@@ -47,11 +47,10 @@ func (a *addressManager) ParseLocalAddress(addrStr string) (ids.ShortID, error)
 		return ids.ShortID{}, err
 	}
 	if chainID != a.ctx.ChainID {
-		return ids.ShortID{}, fmt.Errorf(
-			"expected chainID to be %q but was %q",
-			a.ctx.ChainID,
-			chainID,
-		)
+		return ids.ShortID{}, &formatting.ChainError{
+			Expected: a.chainAlias(a.ctx.ChainID),
+			Actual:   a.chainAlias(chainID),
+		}
 	}
 	return addr, nil
 }
@@ -69,11 +68,10 @@ func (a *addressManager) ParseAddress(addrStr string) (ids.ID, ids.ShortID, erro
 
 	expectedHRP := constants.GetHRP(a.ctx.NetworkID)
 	if hrp != expectedHRP {
-		return ids.ID{}, ids.ShortID{}, fmt.Errorf(
-			"expected hrp %q but got %q",
-			expectedHRP,
-			hrp,
-		)
+		return ids.ID{}, ids.ShortID{}, &formatting.HRPError{
+			Expected: expectedHRP,
+			Actual:   hrp,
+		}
 	}
 
 	addr, err := ids.ToShortID(addrBytes)
@@ -83,6 +81,15 @@ func (a *addressManager) ParseAddress(addrStr string) (ids.ID, ids.ShortID, erro
 	return chainID, addr, nil
 }
 
+// chainAlias returns the primary alias of [chainID], or its string
+// representation if it has no alias
+func (a *addressManager) chainAlias(chainID ids.ID) string {
+	if alias, err := a.ctx.BCLookup.PrimaryAlias(chainID); err == nil {
+		return alias
+	}
+	return chainID.String()
+}
+
 func (a *addressManager) FormatLocalAddress(addr ids.ShortID) (string, error) {
 	return a.FormatAddress(a.ctx.ChainID, addr)
 }
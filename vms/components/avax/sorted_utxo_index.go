@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"bytes"
+
+	"github.com/Toinounet21/avalanchego-mod/codec"
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+var (
+	sortedIndexPrefix   = []byte("sortedIndex")
+	sortedIndexBuiltKey = []byte("sortedIndexBuilt")
+
+	_ UTXOState = &sortedIndexUTXOState{}
+)
+
+// sortedIndexUTXOState is a UTXOState that also indexes the UTXOs of each
+// address by UTXO ID. UTXOIDs is served from this index, so a UTXO ID that was
+// returned by UTXOIDs remains a valid starting point after the UTXO is
+// spent.
+type sortedIndexUTXOState struct {
+	UTXOState
+
+	// Key: address || UTXO ID
+	// Value: nil
+	sortedIndexDB database.Database
+}
+
+// NewSortedIndexUTXOState wraps [state], whose UTXOs are stored in [db], with
+// an index of each address' UTXOs sorted by UTXO ID. The index is built from
+// the UTXOs in [db] if it wasn't kept up to date by a previous
+// sortedIndexUTXOState.
+func NewSortedIndexUTXOState(state UTXOState, db database.Database, codec codec.Manager) (UTXOState, error) {
+	s := &sortedIndexUTXOState{
+		UTXOState:     state,
+		sortedIndexDB: prefixdb.New(sortedIndexPrefix, db),
+	}
+
+	built, err := db.Has(sortedIndexBuiltKey)
+	if err != nil || built {
+		return s, err
+	}
+
+	// Remove any stale entries, left from before the index was last disabled
+	if err := database.Clear(s.sortedIndexDB, s.sortedIndexDB); err != nil {
+		return nil, err
+	}
+
+	utxoDB := prefixdb.New(utxoPrefix, db)
+	it := utxoDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		utxoID, err := ids.ToID(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		utxo := &UTXO{}
+		if _, err := codec.Unmarshal(it.Value(), utxo); err != nil {
+			return nil, err
+		}
+		if err := s.putIndex(utxoID, utxo); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return s, db.Put(sortedIndexBuiltKey, nil)
+}
+
+// DeleteSortedIndex marks the sorted index in [db] as out of date, so that it
+// is rebuilt the next time it's used. Must be called whenever [db]'s UTXOs are
+// modified without maintaining the index.
+func DeleteSortedIndex(db database.Database) error {
+	return db.Delete(sortedIndexBuiltKey)
+}
+
+func (s *sortedIndexUTXOState) PutUTXO(utxoID ids.ID, utxo *UTXO) error {
+	if err := s.UTXOState.PutUTXO(utxoID, utxo); err != nil {
+		return err
+	}
+	return s.putIndex(utxoID, utxo)
+}
+
+func (s *sortedIndexUTXOState) DeleteUTXO(utxoID ids.ID) error {
+	utxo, err := s.GetUTXO(utxoID)
+	if err != nil {
+		return err
+	}
+	if err := s.UTXOState.DeleteUTXO(utxoID); err != nil {
+		return err
+	}
+
+	addressable, ok := utxo.Out.(Addressable)
+	if !ok {
+		return nil
+	}
+	for _, addr := range addressable.Addresses() {
+		if err := s.sortedIndexDB.Delete(sortedIndexKey(addr, utxoID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UTXOIDs returns the IDs of the UTXOs associated with [addr] that are greater
+// than [start], in order. [start] doesn't need to be the ID of an existing UTXO.
+func (s *sortedIndexUTXOState) UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error) {
+	startKey := sortedIndexKey(addr, start)
+	iter := s.sortedIndexDB.NewIteratorWithStartAndPrefix(startKey, addr)
+	defer iter.Release()
+
+	utxoIDs := []ids.ID(nil)
+	for len(utxoIDs) < limit && iter.Next() {
+		key := iter.Key()
+		if bytes.Equal(key, startKey) {
+			continue
+		}
+		utxoID, err := ids.ToID(key[len(addr):])
+		if err != nil {
+			return nil, err
+		}
+		utxoIDs = append(utxoIDs, utxoID)
+	}
+	return utxoIDs, iter.Error()
+}
+
+func (s *sortedIndexUTXOState) putIndex(utxoID ids.ID, utxo *UTXO) error {
+	addressable, ok := utxo.Out.(Addressable)
+	if !ok {
+		return nil
+	}
+	for _, addr := range addressable.Addresses() {
+		if err := s.sortedIndexDB.Put(sortedIndexKey(addr, utxoID), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedIndexKey(addr []byte, utxoID ids.ID) []byte {
+	key := make([]byte, len(addr)+len(utxoID))
+	copy(key, addr)
+	copy(key[len(addr):], utxoID[:])
+	return key
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/codec"
+	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+func newSortedIndexTestCodec(t *testing.T) codec.Manager {
+	c := linearcodec.NewDefault()
+	manager := codec.NewDefaultManager()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&secp256k1fx.TransferOutput{}),
+		manager.RegisterCodec(codecVersion, c),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+	return manager
+}
+
+func newSortedIndexTestUTXO(addr ids.ShortID) *UTXO {
+	return &UTXO{
+		UTXOID: UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  Asset{ID: ids.Empty},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+}
+
+func TestSortedIndexPaginationWhileSpending(t *testing.T) {
+	assert := assert.New(t)
+
+	manager := newSortedIndexTestCodec(t)
+	db := memdb.New()
+	s, err := NewSortedIndexUTXOState(NewUTXOState(db, manager), db, manager)
+	assert.NoError(err)
+
+	addr := ids.GenerateTestShortID()
+	utxoIDs := make([]ids.ID, 10)
+	for i := range utxoIDs {
+		utxo := newSortedIndexTestUTXO(addr)
+		utxoIDs[i] = utxo.InputID()
+		assert.NoError(s.PutUTXO(utxoIDs[i], utxo))
+	}
+	ids.SortIDs(utxoIDs)
+
+	addrs := ids.ShortSet{}
+	addrs.Add(addr)
+	page, lastAddr, lastUTXOID, err := GetPaginatedUTXOs(context.Background(), s, addrs, ids.ShortEmpty, ids.Empty, 4)
+	assert.NoError(err)
+	assert.Len(page, 4)
+	assert.Equal(utxoIDs[3], lastUTXOID)
+
+	// The cursor remains valid after the UTXO it points to is spent
+	assert.NoError(s.DeleteUTXO(lastUTXOID))
+	page, _, _, err = GetPaginatedUTXOs(context.Background(), s, addrs, lastAddr, lastUTXOID, 10)
+	assert.NoError(err)
+	fetched := make([]ids.ID, len(page))
+	for i, utxo := range page {
+		fetched[i] = utxo.InputID()
+	}
+	assert.Equal(utxoIDs[4:], fetched)
+}
+
+func TestSortedIndexRebuild(t *testing.T) {
+	assert := assert.New(t)
+
+	manager := newSortedIndexTestCodec(t)
+	db := memdb.New()
+	addr := ids.GenerateTestShortID()
+
+	// UTXOs written without the index are indexed once it's enabled
+	unindexed := NewUTXOState(db, manager)
+	utxo0 := newSortedIndexTestUTXO(addr)
+	assert.NoError(unindexed.PutUTXO(utxo0.InputID(), utxo0))
+
+	s, err := NewSortedIndexUTXOState(NewUTXOState(db, manager), db, manager)
+	assert.NoError(err)
+	utxoIDs, err := s.UTXOIDs(addr[:], ids.Empty, 10)
+	assert.NoError(err)
+	assert.Equal([]ids.ID{utxo0.InputID()}, utxoIDs)
+
+	// Disabling the index and then modifying the UTXOs causes a rebuild
+	assert.NoError(DeleteSortedIndex(db))
+	utxo1 := newSortedIndexTestUTXO(addr)
+	assert.NoError(unindexed.PutUTXO(utxo1.InputID(), utxo1))
+	assert.NoError(unindexed.DeleteUTXO(utxo0.InputID()))
+
+	s, err = NewSortedIndexUTXOState(NewUTXOState(db, manager), db, manager)
+	assert.NoError(err)
+	utxoIDs, err = s.UTXOIDs(addr[:], ids.Empty, 10)
+	assert.NoError(err)
+	assert.Equal([]ids.ID{utxo1.InputID()}, utxoIDs)
+}
@@ -5,6 +5,7 @@ package avax
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
@@ -31,6 +32,7 @@ func GetBalance(db UTXOReader, addrs ids.ShortSet) (uint64, error) {
 
 func GetAllUTXOs(db UTXOReader, addrs ids.ShortSet) ([]*UTXO, error) {
 	utxos, _, _, err := GetPaginatedUTXOs(
+		context.Background(),
 		db,
 		addrs,
 		ids.ShortEmpty,
@@ -50,11 +52,15 @@ func GetAllUTXOs(db UTXOReader, addrs ids.ShortSet) ([]*UTXO, error) {
 // For address [startAddr], only returns UTXOs whose IDs are greater than
 // [startUTXOID].
 //
+// Stops, and returns [ctx]'s error, if [ctx] is done before the search is
+// over.
+//
 // Returns:
 // * The fetched UTXOs
 // * The address associated with the last UTXO fetched
 // * The ID of the last UTXO fetched
 func GetPaginatedUTXOs(
+	ctx context.Context,
 	db UTXOReader,
 	addrs ids.ShortSet,
 	lastAddr ids.ShortID,
@@ -82,6 +88,10 @@ func GetPaginatedUTXOs(
 			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXOs for address %s: %w", addr, err)
 		}
 		for _, utxoID := range utxoIDs {
+			if err := ctx.Err(); err != nil {
+				return nil, ids.ShortID{}, ids.ID{}, err
+			}
+
 			lastUTXOID = utxoID // The last searched UTXO - not the last found
 
 			if seen.Contains(utxoID) { // Already have this UTXO in the list
@@ -4,6 +4,7 @@
 package avax
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -161,7 +162,7 @@ func TestGetPaginatedUTXOs(t *testing.T) {
 
 	var totalUTXOs []*UTXO
 	for i := 0; i <= 10; i++ {
-		fetchedUTXOs, lastAddr, lastIdx, err = GetPaginatedUTXOs(s, addrs, lastAddr, lastIdx, 512)
+		fetchedUTXOs, lastAddr, lastIdx, err = GetPaginatedUTXOs(context.Background(), s, addrs, lastAddr, lastIdx, 512)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -182,4 +183,12 @@ func TestGetPaginatedUTXOs(t *testing.T) {
 	if len(notPaginatedUTXOs) != len(totalUTXOs) {
 		t.Fatalf("Wrong number of utxos. Expected (%d) returned (%d)", len(totalUTXOs), len(notPaginatedUTXOs))
 	}
+
+	// A cancelled search is interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, _, err = GetPaginatedUTXOs(ctx, s, addrs, ids.ShortEmpty, ids.Empty, 512)
+	if err != context.Canceled {
+		t.Fatalf("expected %s but got %v", context.Canceled, err)
+	}
 }
@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package index
+
+import (
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+
+	safemath "github.com/Toinounet21/avalanchego-mod/utils/math"
+)
+
+// Size of an asset's change in serialized transaction details
+const assetChangeLen = hashing.HashLen + 2*wrappers.LongLen
+
+var detailsPrefix = []byte("details")
+
+// AssetChange is how much of an asset a transaction took from and gave to an
+// address
+type AssetChange struct {
+	AssetID ids.ID
+	// Amount of the asset in the address' UTXOs that the transaction consumed
+	Sent uint64
+	// Amount of the asset in the UTXOs, owned by the address, that the
+	// transaction produced
+	Received uint64
+}
+
+// TxDetails describes how an accepted transaction changed an address'
+// balances
+type TxDetails struct {
+	TxID ids.ID
+	// When this node accepted the transaction
+	Timestamp time.Time
+	// The changes to each asset's balance, sorted by asset ID
+	Changes []AssetChange
+}
+
+// balanceChanges returns, for each address that owns one of [inputUTXOs] or
+// [outputUTXOs], the amount of each asset that it sent and received.
+// UTXOs without an amount still mark their assets as changed.
+func balanceChanges(inputUTXOs, outputUTXOs []*avax.UTXO) (map[string]map[ids.ID]*AssetChange, error) {
+	// Address -> AssetID -> change of the address' balance of the asset
+	changes := make(map[string]map[ids.ID]*AssetChange)
+	add := func(utxo *avax.UTXO, sent bool) error {
+		out, ok := utxo.Out.(avax.Addressable)
+		if !ok {
+			return nil
+		}
+		amount := uint64(0)
+		if amounter, ok := utxo.Out.(avax.Amounter); ok {
+			amount = amounter.Amount()
+		}
+
+		assetID := utxo.AssetID()
+		for _, addressBytes := range out.Addresses() {
+			address := string(addressBytes)
+			addressChanges, exists := changes[address]
+			if !exists {
+				addressChanges = make(map[ids.ID]*AssetChange)
+				changes[address] = addressChanges
+			}
+			change, exists := addressChanges[assetID]
+			if !exists {
+				change = &AssetChange{AssetID: assetID}
+				addressChanges[assetID] = change
+			}
+
+			var err error
+			if sent {
+				change.Sent, err = safemath.Add64(change.Sent, amount)
+			} else {
+				change.Received, err = safemath.Add64(change.Received, amount)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, utxo := range inputUTXOs {
+		if err := add(utxo, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, utxo := range outputUTXOs {
+		if err := add(utxo, false); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// marshalDetails serializes the accept time and asset changes of a transaction
+//
+// The format is:
+// timestamp (unix seconds) | num changes | [asset ID | sent | received]...
+func marshalDetails(timestamp time.Time, changes map[ids.ID]*AssetChange) []byte {
+	assetIDs := make([]ids.ID, 0, len(changes))
+	for assetID := range changes {
+		assetIDs = append(assetIDs, assetID)
+	}
+	ids.SortIDs(assetIDs)
+
+	p := wrappers.Packer{
+		Bytes: make([]byte, wrappers.LongLen+wrappers.IntLen+len(changes)*assetChangeLen),
+	}
+	p.PackLong(uint64(timestamp.Unix()))
+	p.PackInt(uint32(len(assetIDs)))
+	for _, assetID := range assetIDs {
+		change := changes[assetID]
+		p.PackFixedBytes(assetID[:])
+		p.PackLong(change.Sent)
+		p.PackLong(change.Received)
+	}
+	return p.Bytes
+}
+
+func unmarshalDetails(txID ids.ID, b []byte) (*TxDetails, error) {
+	p := wrappers.Packer{Bytes: b}
+	details := &TxDetails{
+		TxID:      txID,
+		Timestamp: time.Unix(int64(p.UnpackLong()), 0),
+	}
+	numChanges := p.UnpackInt()
+	if p.Errored() {
+		return nil, p.Err
+	}
+	if uint64(numChanges)*assetChangeLen > uint64(len(b)) {
+		return nil, errCorruptedDetails
+	}
+	details.Changes = make([]AssetChange, numChanges)
+	for i := range details.Changes {
+		change := &details.Changes[i]
+		copy(change.AssetID[:], p.UnpackFixedBytes(hashing.HashLen))
+		change.Sent = p.UnpackLong()
+		change.Received = p.UnpackLong()
+	}
+	return details, p.Err
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/utils/timer/mockable"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
 )
@@ -24,6 +25,7 @@ var (
 	idxCompleteKey                 = []byte("complete")
 	errIndexingRequiredFromGenesis = errors.New("running would create incomplete index. Allow incomplete indices or re-sync from genesis with indexing enabled")
 	errCausesIncompleteIndex       = errors.New("running would create incomplete index. Allow incomplete indices or enable indexing")
+	errCorruptedDetails            = errors.New("corrupted transaction details")
 )
 
 // AddressTxsIndexer maintains information about which transactions changed
@@ -49,13 +51,22 @@ type AddressTxsIndexer interface {
 	// The length of the returned slice <= [pageSize].
 	// [cursor] is the offset to start reading from.
 	Read(address []byte, assetID ids.ID, cursor, pageSize uint64) ([]ids.ID, error)
+
+	// ReadDetails returns how [txID] changed [address]'s balances. Returns
+	// database.ErrNotFound if [txID] didn't change [address]'s balances, or
+	// was indexed before details were recorded.
+	ReadDetails(address []byte, txID ids.ID) (*TxDetails, error)
 }
 
 // indexer implements AddressTxsIndexer
 type indexer struct {
 	log     logging.Logger
 	metrics metrics
+	clock   mockable.Clock
 	db      database.Database
+	// Address --> txID --> details of how the tx changed the address'
+	// balances
+	detailsDB database.Database
 }
 
 // NewIndexer Returns a new AddressTxsIndexer.
@@ -68,8 +79,9 @@ func NewIndexer(
 	allowIncompleteIndices bool,
 ) (AddressTxsIndexer, error) {
 	i := &indexer{
-		db:  db,
-		log: log,
+		db:        db,
+		log:       log,
+		detailsDB: prefixdb.New(detailsPrefix, db),
 	}
 	// initialize the indexer
 	if err := checkIndexStatus(i.db, true, allowIncompleteIndices); err != nil {
@@ -91,40 +103,30 @@ func NewIndexer(
 // |  | "idx" => 2 		Running transaction index key, represents the next index
 // |  | "0"   => txID1
 // |  | "1"   => txID1
+// "details"
+// |  [address]
+// |  |  txID1 => accept time and amounts of each asset sent and received
 // See interface documentation AddressTxsIndexer.Accept
 func (i *indexer) Accept(txID ids.ID, inputUTXOs []*avax.UTXO, outputUTXOs []*avax.UTXO) error {
-	utxos := inputUTXOs
-	// Fetch and add the output UTXOs
-	utxos = append(utxos, outputUTXOs...)
-
 	// convert UTXOs into balance changes
-	// Address -> AssetID --> exists if the address's balance
+	// Address -> AssetID --> change if the address's balance
 	// of the asset is changed by processing tx [txID]
 	// we do this step separately to simplify the write process later
-	balanceChanges := make(map[string]map[ids.ID]struct{})
-	for _, utxo := range utxos {
-		out, ok := utxo.Out.(avax.Addressable)
-		if !ok {
-			i.log.Verbo("skipping UTXO %s for indexing", utxo.InputID())
-			continue
-		}
-
-		for _, addressBytes := range out.Addresses() {
-			address := string(addressBytes)
-
-			addressChanges, exists := balanceChanges[address]
-			if !exists {
-				addressChanges = make(map[ids.ID]struct{})
-				balanceChanges[address] = addressChanges
-			}
-			addressChanges[utxo.AssetID()] = struct{}{}
-		}
+	changes, err := balanceChanges(inputUTXOs, outputUTXOs)
+	if err != nil {
+		return fmt.Errorf("couldn't calculate balance changes of txID %s: %w", txID, err)
 	}
 
 	// Process the balance changes
-	for address, assetIDs := range balanceChanges {
+	timestamp := i.clock.Time()
+	for address, assetChanges := range changes {
+		addressDetailsDB := prefixdb.New([]byte(address), i.detailsDB)
+		if err := addressDetailsDB.Put(txID[:], marshalDetails(timestamp, assetChanges)); err != nil {
+			return fmt.Errorf("failed to write details while indexing %s: %w", txID, err)
+		}
+
 		addressPrefixDB := prefixdb.New([]byte(address), i.db)
-		for assetID := range assetIDs {
+		for assetID := range assetChanges {
 			assetPrefixDB := prefixdb.New(assetID[:], addressPrefixDB)
 
 			var idx uint64
@@ -197,6 +199,17 @@ func (i *indexer) Read(address []byte, assetID ids.ID, cursor, pageSize uint64)
 	return txIDs, nil
 }
 
+// ReadDetails returns how [txID] changed [address]'s balances.
+// See AddressTxsIndexer
+func (i *indexer) ReadDetails(address []byte, txID ids.ID) (*TxDetails, error) {
+	addressDetailsDB := prefixdb.New(address, i.detailsDB)
+	detailsBytes, err := addressDetailsDB.Get(txID[:])
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDetails(txID, detailsBytes)
+}
+
 // checkIndexStatus checks the indexing status in the database, returning error if the state
 // with respect to provided parameters is invalid
 func checkIndexStatus(db database.KeyValueReaderWriter, enableIndexing, allowIncomplete bool) error {
@@ -247,3 +260,7 @@ func (i *noIndexer) Accept(ids.ID, []*avax.UTXO, []*avax.UTXO) error {
 func (i *noIndexer) Read([]byte, ids.ID, uint64, uint64) ([]ids.ID, error) {
 	return nil, nil
 }
+
+func (i *noIndexer) ReadDetails([]byte, ids.ID) (*TxDetails, error) {
+	return nil, database.ErrNotFound
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+// DefaultGapLimit is the number of consecutive unused HD addresses after which
+// discovery stops, as recommended by BIP44
+const DefaultGapLimit = 20
+
+var (
+	errNoSeed          = errors.New("keystore user has no HD seed")
+	errZeroGapLimit    = errors.New("gap limit must be positive")
+	errMaxHDAddresses  = fmt.Errorf("keystore user has reached its limit of %d HD addresses", maxKeystoreAddresses)
+	errHDIndexOverflow = errors.New("HD index overflow")
+)
+
+// HDKey is a key derived from a keystore user's HD seed
+type HDKey struct {
+	Index uint32
+	Key   *crypto.PrivateKeySECP256K1R
+}
+
+// NewSeed returns a new random HD seed of the recommended length
+func NewSeed() ([]byte, error) {
+	seed := make([]byte, crypto.RecommendedSeedLen)
+	_, err := rand.Read(seed)
+	return seed, err
+}
+
+// DeriveHDKeys derives the [count] keys starting at [start] on the external
+// chain of the first Avalanche account of [seed].
+func DeriveHDKeys(seed []byte, start, count uint32) ([]HDKey, error) {
+	if start+count < start {
+		return nil, errHDIndexOverflow
+	}
+
+	master, err := crypto.NewMasterKeySECP256K1R(seed)
+	if err != nil {
+		return nil, err
+	}
+	// The account path is shared by every key, so derive it only once.
+	accountPath := crypto.AvalancheDerivationPath(0, 0, 0)
+	chain, err := master.Derive(accountPath[:len(accountPath)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]HDKey, 0, count)
+	for index := start; index < start+count; index++ {
+		child, err := chain.Child(index)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't derive HD key %d: %w", index, err)
+		}
+		keys = append(keys, HDKey{
+			Index: index,
+			Key:   child.PrivateKey(),
+		})
+	}
+	return keys, nil
+}
+
+// NewHDKey derives, stores and returns the next HD key of [u]. If [u] doesn't
+// have a seed yet, a new one is generated.
+func NewHDKey(u User) (*crypto.PrivateKeySECP256K1R, error) {
+	seed, err := u.GetSeed()
+	if err == database.ErrNotFound {
+		seed, err = NewSeed()
+		if err != nil {
+			return nil, err
+		}
+		err = u.PutSeed(seed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := u.GetNextHDIndex()
+	if err != nil {
+		return nil, err
+	}
+	if index >= maxKeystoreAddresses {
+		return nil, errMaxHDAddresses
+	}
+
+	keys, err := DeriveHDKeys(seed, index, 1)
+	if err != nil {
+		return nil, err
+	}
+	sk := keys[0].Key
+	if err := u.PutKeys(sk); err != nil {
+		return nil, err
+	}
+	return sk, u.PutNextHDIndex(index + 1)
+}
+
+// GetHDKeys returns every HD key that has been derived for [u]
+func GetHDKeys(u User) ([]HDKey, error) {
+	seed, err := u.GetSeed()
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := u.GetNextHDIndex()
+	if err != nil {
+		return nil, err
+	}
+	return DeriveHDKeys(seed, 0, index)
+}
+
+// DiscoverHDKeys scans the HD keys of [u] from index 0 until [gapLimit]
+// consecutive keys control addresses that [isUsed] reports as unused. Every
+// key up to the last used one is stored and the returned keys are the ones
+// that weren't derived before.
+func DiscoverHDKeys(u User, gapLimit uint32, isUsed func(ids.ShortID) (bool, error)) ([]HDKey, error) {
+	if gapLimit == 0 {
+		return nil, errZeroGapLimit
+	}
+	if gapLimit > maxKeystoreAddresses {
+		gapLimit = maxKeystoreAddresses
+	}
+
+	seed, err := u.GetSeed()
+	if err == database.ErrNotFound {
+		return nil, errNoSeed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nextIndex, err := u.GetNextHDIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		scanned []HDKey
+		end     uint32 // 1 + the index of the last used key
+	)
+	for start := uint32(0); start < end+gapLimit && start < maxKeystoreAddresses; {
+		count := end + gapLimit - start
+		if start+count > maxKeystoreAddresses {
+			count = maxKeystoreAddresses - start
+		}
+		keys, err := DeriveHDKeys(seed, start, count)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			used, err := isUsed(key.Key.PublicKey().Address())
+			if err != nil {
+				return nil, err
+			}
+			if used {
+				end = key.Index + 1
+			}
+		}
+		scanned = append(scanned, keys...)
+		start += count
+	}
+
+	if end <= nextIndex {
+		return nil, nil
+	}
+
+	discovered := scanned[nextIndex:end]
+	sks := make([]*crypto.PrivateKeySECP256K1R, len(discovered))
+	for i, key := range discovered {
+		sks[i] = key.Key
+	}
+	if err := u.PutKeys(sks...); err != nil {
+		return nil, err
+	}
+	return discovered, u.PutNextHDIndex(end)
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/encdb"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func newTestUser(t *testing.T) User {
+	db, err := encdb.New([]byte(testPassword), memdb.New())
+	assert.NoError(t, err)
+	return NewUserFromDB(db)
+}
+
+// assertHDKeysEqual compares keys by value, ignoring their cached fields
+func assertHDKeysEqual(t *testing.T, expected, actual []HDKey) {
+	assert.Len(t, actual, len(expected))
+	for i, key := range actual {
+		assert.Equal(t, expected[i].Index, key.Index)
+		assert.Equal(t, expected[i].Key.Bytes(), key.Key.Bytes())
+	}
+}
+
+func TestNewHDKey(t *testing.T) {
+	assert := assert.New(t)
+
+	u := newTestUser(t)
+
+	_, err := u.GetSeed()
+	assert.Equal(database.ErrNotFound, err)
+
+	sk0, err := NewHDKey(u)
+	assert.NoError(err)
+	sk1, err := NewHDKey(u)
+	assert.NoError(err)
+
+	seed, err := u.GetSeed()
+	assert.NoError(err)
+	assert.ErrorIs(u.PutSeed(seed), errSeedAlreadySet)
+
+	expected, err := DeriveHDKeys(seed, 0, 2)
+	assert.NoError(err)
+	assert.Equal(expected[0].Key.Bytes(), sk0.Bytes())
+	assert.Equal(expected[1].Key.Bytes(), sk1.Bytes())
+
+	addresses, err := u.GetAddresses()
+	assert.NoError(err)
+	assert.Equal([]ids.ShortID{sk0.PublicKey().Address(), sk1.PublicKey().Address()}, addresses)
+
+	hdKeys, err := GetHDKeys(u)
+	assert.NoError(err)
+	assertHDKeysEqual(t, expected, hdKeys)
+}
+
+func TestGetHDKeysNoSeed(t *testing.T) {
+	assert := assert.New(t)
+
+	keys, err := GetHDKeys(newTestUser(t))
+	assert.NoError(err)
+	assert.Empty(keys)
+}
+
+func TestDiscoverHDKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	seed, err := NewSeed()
+	assert.NoError(err)
+
+	expected, err := DeriveHDKeys(seed, 0, 10)
+	assert.NoError(err)
+
+	// Indices 1 and 6 are used, with a gap of 4 unused keys between them
+	used := ids.ShortSet{}
+	used.Add(
+		expected[1].Key.PublicKey().Address(),
+		expected[6].Key.PublicKey().Address(),
+	)
+	isUsed := func(addr ids.ShortID) (bool, error) { return used.Contains(addr), nil }
+
+	u := newTestUser(t)
+	_, err = DiscoverHDKeys(u, DefaultGapLimit, isUsed)
+	assert.ErrorIs(err, errNoSeed)
+	assert.NoError(u.PutSeed(seed))
+
+	_, err = DiscoverHDKeys(u, 0, isUsed)
+	assert.ErrorIs(err, errZeroGapLimit)
+
+	// A gap limit of 4 stops the scan before index 6
+	discovered, err := DiscoverHDKeys(u, 4, isUsed)
+	assert.NoError(err)
+	assertHDKeysEqual(t, expected[:2], discovered)
+
+	discovered, err = DiscoverHDKeys(u, 5, isUsed)
+	assert.NoError(err)
+	assertHDKeysEqual(t, expected[2:7], discovered)
+
+	nextIndex, err := u.GetNextHDIndex()
+	assert.NoError(err)
+	assert.EqualValues(7, nextIndex)
+
+	addresses, err := u.GetAddresses()
+	assert.NoError(err)
+	assert.Len(addresses, 7)
+
+	// Nothing new is discovered on a second pass
+	discovered, err = DiscoverHDKeys(u, DefaultGapLimit, isUsed)
+	assert.NoError(err)
+	assert.Empty(discovered)
+}
@@ -4,6 +4,8 @@
 package keystore
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 
@@ -22,8 +24,21 @@ var (
 	// Key in the database whose corresponding value is the list of addresses
 	// this user controls
 	addressesKey = ids.Empty[:]
+	// Key in the database whose corresponding value is the list of addresses
+	// this user watches without controlling them. Its length differs from
+	// the length of an address and of [addressesKey].
+	watchOnlyAddressesKey = []byte("watchOnlyAddresses")
+	// Key in the database whose corresponding value is the seed this user's
+	// HD keys are derived from
+	hdSeedKey = []byte("hdSeed")
+	// Key in the database whose corresponding value is the index of the next
+	// HD key to derive
+	hdNextIndexKey = []byte("hdNextIndex")
 
-	errMaxAddresses = fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
+	errMaxAddresses          = fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
+	errMaxWatchOnlyAddresses = fmt.Errorf("keystore user has reached its limit of %d watch-only addresses", maxKeystoreAddresses)
+	errSeedAlreadySet        = errors.New("keystore user already has an HD seed")
+	errInvalidHDIndex        = errors.New("invalid HD index")
 
 	_ User = &user{}
 )
@@ -39,6 +54,28 @@ type User interface {
 
 	// GetKey returns the private key that controls the given address
 	GetKey(address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error)
+
+	// Get the addresses this user watches without controlling them
+	GetWatchOnlyAddresses() ([]ids.ShortID, error)
+
+	// PutWatchOnlyAddresses persists [addresses] as watched by this user. No
+	// private key is stored for them.
+	PutWatchOnlyAddresses(addresses ...ids.ShortID) error
+
+	// GetSeed returns the seed this user's HD keys are derived from. Returns
+	// database.ErrNotFound if the user has no seed.
+	GetSeed() ([]byte, error)
+
+	// PutSeed persists [seed] as this user's HD seed. A user's seed can't be
+	// replaced once it is set.
+	PutSeed(seed []byte) error
+
+	// GetNextHDIndex returns the index of the next HD key to derive
+	GetNextHDIndex() (uint32, error)
+
+	// PutNextHDIndex persists [index] as the index of the next HD key to
+	// derive
+	PutNextHDIndex(index uint32) error
 }
 
 type user struct {
@@ -61,8 +98,16 @@ func NewUserFromDB(db *encdb.Database) User {
 }
 
 func (u *user) GetAddresses() ([]ids.ShortID, error) {
-	// Get user's addresses
-	addressBytes, err := u.db.Get(addressesKey)
+	return u.getAddresses(addressesKey)
+}
+
+func (u *user) GetWatchOnlyAddresses() ([]ids.ShortID, error) {
+	return u.getAddresses(watchOnlyAddressesKey)
+}
+
+// getAddresses returns the list of addresses stored under [key]
+func (u *user) getAddresses(key []byte) ([]ids.ShortID, error) {
+	addressBytes, err := u.db.Get(key)
 	if err == database.ErrNotFound {
 		// If user has no addresses, return empty list
 		return nil, nil
@@ -119,6 +164,69 @@ func (u *user) PutKeys(privKeys ...*crypto.PrivateKeySECP256K1R) error {
 	return u.db.Put(addressesKey, addressBytes)
 }
 
+func (u *user) PutWatchOnlyAddresses(addresses ...ids.ShortID) error {
+	watchOnlyAddresses, err := u.GetWatchOnlyAddresses()
+	if err != nil {
+		return err
+	}
+
+	watched := ids.NewShortSet(len(watchOnlyAddresses) + len(addresses))
+	watched.Add(watchOnlyAddresses...)
+	for _, address := range addresses {
+		if watched.Contains(address) {
+			continue
+		}
+		watched.Add(address)
+		watchOnlyAddresses = append(watchOnlyAddresses, address)
+	}
+	if len(watchOnlyAddresses) > maxKeystoreAddresses {
+		return errMaxWatchOnlyAddresses
+	}
+
+	addressBytes, err := Codec.Marshal(CodecVersion, watchOnlyAddresses)
+	if err != nil {
+		return err
+	}
+	return u.db.Put(watchOnlyAddressesKey, addressBytes)
+}
+
+func (u *user) GetSeed() ([]byte, error) { return u.db.Get(hdSeedKey) }
+
+func (u *user) PutSeed(seed []byte) error {
+	hasSeed, err := u.db.Has(hdSeedKey)
+	if err != nil {
+		return err
+	}
+	if hasSeed {
+		return errSeedAlreadySet
+	}
+	// Make sure the seed is usable before persisting it
+	if _, err := crypto.NewMasterKeySECP256K1R(seed); err != nil {
+		return err
+	}
+	return u.db.Put(hdSeedKey, seed)
+}
+
+func (u *user) GetNextHDIndex() (uint32, error) {
+	indexBytes, err := u.db.Get(hdNextIndexKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(indexBytes) != 4 {
+		return 0, errInvalidHDIndex
+	}
+	return binary.BigEndian.Uint32(indexBytes), nil
+}
+
+func (u *user) PutNextHDIndex(index uint32) error {
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	return u.db.Put(hdNextIndexKey, indexBytes)
+}
+
 func (u *user) GetKey(address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
 	bytes, err := u.db.Get(address.Bytes())
 	if err != nil {
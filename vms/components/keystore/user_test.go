@@ -86,3 +86,38 @@ func TestUser(t *testing.T) {
 	assert.Len(savedKeychain.Keys, 1, "key should have been added")
 	assert.Equal(sk.Bytes(), savedKeychain.Keys[0].Bytes(), "wrong key returned")
 }
+
+func TestUserWatchOnlyAddresses(t *testing.T) {
+	assert := assert.New(t)
+
+	db, err := encdb.New([]byte(testPassword), memdb.New())
+	assert.NoError(err)
+
+	u := NewUserFromDB(db)
+
+	addresses, err := u.GetWatchOnlyAddresses()
+	assert.NoError(err)
+	assert.Empty(addresses, "new user shouldn't watch any address")
+
+	addr0 := ids.GenerateTestShortID()
+	addr1 := ids.GenerateTestShortID()
+	assert.NoError(u.PutWatchOnlyAddresses(addr0, addr1, addr0))
+	assert.NoError(u.PutWatchOnlyAddresses(addr1))
+
+	addresses, err = u.GetWatchOnlyAddresses()
+	assert.NoError(err)
+	assert.Equal([]ids.ShortID{addr0, addr1}, addresses)
+
+	// Watched addresses aren't controlled by the user
+	addresses, err = u.GetAddresses()
+	assert.NoError(err)
+	assert.Empty(addresses)
+	_, err = u.GetKey(addr0)
+	assert.Error(err)
+
+	tooMany := make([]ids.ShortID, maxKeystoreAddresses)
+	for i := range tooMany {
+		tooMany[i] = ids.GenerateTestShortID()
+	}
+	assert.ErrorIs(u.PutWatchOnlyAddresses(tooMany...), errMaxWatchOnlyAddresses)
+}
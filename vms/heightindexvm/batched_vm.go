@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightindexvm
+
+import (
+	"context"
+	"time"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+)
+
+func (vm *VM) GetAncestors(
+	ctx context.Context,
+	blkID ids.ID,
+	maxBlocksNum int,
+	maxBlocksSize int,
+	maxBlocksRetrivalTime time.Duration,
+) ([][]byte, error) {
+	rVM, ok := vm.ChainVM.(block.BatchedChainVM)
+	if !ok {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+	return rVM.GetAncestors(
+		ctx,
+		blkID,
+		maxBlocksNum,
+		maxBlocksSize,
+		maxBlocksRetrivalTime,
+	)
+}
+
+func (vm *VM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error) {
+	rVM, ok := vm.ChainVM.(block.BatchedChainVM)
+	if !ok {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+
+	blocks, err := rVM.BatchedParseBlock(ctx, blks)
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, blk := range blocks {
+		wrappedBlocks[i] = vm.wrap(blk)
+	}
+	return wrappedBlocks, err
+}
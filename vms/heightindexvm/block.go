@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightindexvm
+
+import "github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman"
+
+var (
+	_ snowman.Block       = &indexedBlock{}
+	_ snowman.OracleBlock = &indexedBlock{}
+)
+
+// indexedBlock adds the block to the height index when it's accepted
+type indexedBlock struct {
+	snowman.Block
+
+	vm *VM
+}
+
+func (ib *indexedBlock) Accept() error {
+	if err := ib.Block.Accept(); err != nil {
+		return err
+	}
+	if err := ib.vm.putHeight(ib.Height(), ib.ID()); err != nil {
+		return err
+	}
+	return ib.vm.db.Commit()
+}
+
+func (ib *indexedBlock) Options() ([2]snowman.Block, error) {
+	oracleBlock, ok := ib.Block.(snowman.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, snowman.ErrNotOracle
+	}
+
+	blks, err := oracleBlock.Options()
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	return [2]snowman.Block{
+		ib.vm.wrap(blks[0]),
+		ib.vm.wrap(blks[1]),
+	}, nil
+}
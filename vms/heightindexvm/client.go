@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightindexvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/constants"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+	"github.com/Toinounet21/avalanchego-mod/utils/rpc"
+)
+
+// Interface compliance
+var _ Client = &client{}
+
+// Client for the height index of a Snowman chain
+type Client interface {
+	// GetBlockByHeight returns the ID and the bytes of the block accepted at
+	// [height]
+	GetBlockByHeight(ctx context.Context, height uint64) (ids.ID, []byte, error)
+}
+
+// Client implementation for the height index of a Snowman chain
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a Client for the height index of the chain [chain], which
+// can be its ID or one of its aliases
+func NewClient(uri, chain string) Client {
+	return &client{
+		requester: rpc.NewEndpointRequester(uri, fmt.Sprintf("/ext/%s%s", constants.ChainAliasPrefix+chain, endpoint), "height"),
+	}
+}
+
+func (c *client) GetBlockByHeight(ctx context.Context, height uint64) (ids.ID, []byte, error) {
+	res := &GetBlockByHeightReply{}
+	err := c.requester.SendRequest(ctx, "getBlockByHeight", &GetBlockByHeightArgs{
+		Height:   json.Uint64(height),
+		Encoding: formatting.Hex,
+	}, res)
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+	blkBytes, err := formatting.Decode(res.Encoding, res.Block)
+	return res.ID, blkBytes, err
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightindexvm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+// Service serves the accepted blocks of a chain by height
+type Service struct {
+	vm *VM
+}
+
+// GetBlockByHeightArgs are the arguments for calling GetBlockByHeight
+type GetBlockByHeightArgs struct {
+	Height   json.Uint64         `json:"height"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetBlockByHeightReply is the response from calling GetBlockByHeight
+type GetBlockByHeightReply struct {
+	ID       ids.ID              `json:"id"`
+	Height   json.Uint64         `json:"height"`
+	Block    string              `json:"block"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetBlockByHeight returns the block accepted at the specified height
+func (s *Service) GetBlockByHeight(_ *http.Request, args *GetBlockByHeightArgs, reply *GetBlockByHeightReply) error {
+	s.vm.ctx.Log.Debug("Height: GetBlockByHeight called with Height %d", args.Height)
+
+	blkID, err := s.vm.GetBlockIDAtHeight(uint64(args.Height))
+	if err == database.ErrNotFound {
+		return fmt.Errorf("no block has been accepted at height %d", args.Height)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get the block at height %d: %w", args.Height, err)
+	}
+
+	blk, err := s.vm.ChainVM.GetBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+	blkStr, err := formatting.EncodeWithChecksum(args.Encoding, blk.Bytes())
+	if err != nil {
+		return fmt.Errorf("couldn't encode block %s as string: %w", blkID, err)
+	}
+
+	reply.ID = blkID
+	reply.Height = args.Height
+	reply.Block = blkStr
+	reply.Encoding = args.Encoding
+	return nil
+}
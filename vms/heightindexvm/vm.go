@@ -0,0 +1,228 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightindexvm
+
+import (
+	"fmt"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
+	"github.com/Toinounet21/avalanchego-mod/database/versiondb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/utils/json"
+)
+
+const (
+	// Extension of the endpoint the index is served at
+	endpoint = "/height"
+
+	// Number of blocks indexed between commits when backfilling the index
+	backfillCommitFrequency = 1024
+)
+
+var (
+	dbPrefix      = []byte("heightindexvm")
+	heightsPrefix = []byte("heights")
+	checkpointKey = []byte("checkpoint")
+
+	_ block.ChainVM              = &VM{}
+	_ block.BatchedChainVM       = &VM{}
+	_ block.HeightIndexedChainVM = &VM{}
+)
+
+// VM indexes the blocks accepted by the ChainVM it wraps by height, and serves
+// them by height under [endpoint].
+type VM struct {
+	block.ChainVM
+
+	ctx *snow.Context
+	db  *versiondb.Database
+	// Maps heights to the IDs of the blocks accepted at them
+	heights database.Database
+}
+
+// New returns a VM that indexes the accepted blocks of [vm] by height. If [vm]
+// already maintains such an index, it is returned as is.
+func New(vm block.ChainVM) block.ChainVM {
+	if _, ok := vm.(block.HeightIndexedChainVM); ok {
+		return vm
+	}
+	return &VM{ChainVM: vm}
+}
+
+func (vm *VM) Initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisBytes []byte,
+	upgradeBytes []byte,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+	appSender common.AppSender,
+) error {
+	vm.ctx = ctx
+	vm.db = versiondb.New(prefixdb.New(dbPrefix, dbManager.Current().Database))
+	vm.heights = prefixdb.New(heightsPrefix, vm.db)
+
+	if err := vm.ChainVM.Initialize(
+		ctx,
+		dbManager,
+		genesisBytes,
+		upgradeBytes,
+		configBytes,
+		toEngine,
+		fxs,
+		appSender,
+	); err != nil {
+		return err
+	}
+	return vm.repairHeightIndex()
+}
+
+func (vm *VM) BuildBlock() (snowman.Block, error) {
+	blk, err := vm.ChainVM.BuildBlock()
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrap(blk), nil
+}
+
+func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
+	blk, err := vm.ChainVM.ParseBlock(b)
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrap(blk), nil
+}
+
+func (vm *VM) GetBlock(id ids.ID) (snowman.Block, error) {
+	blk, err := vm.ChainVM.GetBlock(id)
+	if err != nil {
+		return nil, err
+	}
+	return vm.wrap(blk), nil
+}
+
+func (vm *VM) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	return database.GetID(vm.heights, database.PackUInt64(height))
+}
+
+// CreateHandlers adds the handler of the height index to the handlers of the
+// wrapped VM
+func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
+	handlers, err := vm.ChainVM.CreateHandlers()
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := handlers[endpoint]; exists {
+		vm.ctx.Log.Warn("not serving the height index since the VM serves %q", endpoint)
+		return handlers, nil
+	}
+
+	server := rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	if err := server.RegisterService(&Service{vm: vm}, "height"); err != nil {
+		return nil, err
+	}
+
+	if handlers == nil {
+		handlers = make(map[string]*common.HTTPHandler, 1)
+	}
+	handlers[endpoint] = &common.HTTPHandler{
+		LockOptions: common.ReadLock,
+		Handler:     server,
+	}
+	return handlers, nil
+}
+
+func (vm *VM) wrap(blk snowman.Block) snowman.Block {
+	return &indexedBlock{
+		Block: blk,
+		vm:    vm,
+	}
+}
+
+// putHeight records that [blkID] was accepted at [height]
+func (vm *VM) putHeight(height uint64, blkID ids.ID) error {
+	return database.PutID(vm.heights, database.PackUInt64(height), blkID)
+}
+
+// repairHeightIndex indexes the accepted blocks that weren't indexed, because
+// they were accepted before the index existed or before a crash. An
+// interrupted repair is resumed before the blocks accepted since are indexed,
+// so that at most one repair is ever in progress.
+func (vm *VM) repairHeightIndex() error {
+	checkpoint, err := database.GetID(vm.db, checkpointKey)
+	switch err {
+	case nil:
+		vm.ctx.Log.Info("resuming the indexing of the accepted blocks by height from %s", checkpoint)
+		if err := vm.indexAncestors(checkpoint); err != nil {
+			return err
+		}
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
+	lastAcceptedID, err := vm.ChainVM.LastAccepted()
+	if err != nil {
+		return err
+	}
+	return vm.indexAncestors(lastAcceptedID)
+}
+
+// indexAncestors indexes [blkID] and its ancestors by height, until it reaches
+// a block that is already indexed or the genesis block
+func (vm *VM) indexAncestors(blkID ids.ID) error {
+	numIndexed := 0
+	for {
+		blk, err := vm.ChainVM.GetBlock(blkID)
+		if err != nil {
+			return fmt.Errorf("couldn't get accepted block %s: %w", blkID, err)
+		}
+		height := blk.Height()
+		indexedID, err := vm.GetBlockIDAtHeight(height)
+		if err == nil && indexedID == blkID {
+			break
+		}
+		if err != nil && err != database.ErrNotFound {
+			return err
+		}
+
+		if err := vm.putHeight(height, blkID); err != nil {
+			return err
+		}
+		numIndexed++
+		if height == 0 {
+			break
+		}
+
+		blkID = blk.Parent()
+		if numIndexed%backfillCommitFrequency == 0 {
+			if err := database.PutID(vm.db, checkpointKey, blkID); err != nil {
+				return err
+			}
+			if err := vm.db.Commit(); err != nil {
+				return err
+			}
+			vm.ctx.Log.Info("indexed %d accepted blocks by height, down to height %d", numIndexed, height)
+		}
+	}
+
+	if err := vm.db.Delete(checkpointKey); err != nil {
+		return err
+	}
+	if numIndexed >= backfillCommitFrequency {
+		vm.ctx.Log.Info("indexed %d accepted blocks by height", numIndexed)
+	}
+	return vm.db.Commit()
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightindexvm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/choices"
+	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowman"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
+	"github.com/Toinounet21/avalanchego-mod/version"
+)
+
+var errUnknownBlock = errors.New("unknown block")
+
+// newTestChain returns a chain of [length] blocks, of which the first
+// [numAccepted] are accepted, and a VM serving them
+func newTestChain(t *testing.T, length, numAccepted int) ([]*snowman.TestBlock, *block.TestVM) {
+	blks := make([]*snowman.TestBlock, length)
+	for i := range blks {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			HeightV: uint64(i),
+			BytesV:  []byte{byte(i)},
+		}
+		if i > 0 {
+			blks[i].ParentV = blks[i-1].ID()
+		}
+		if i < numAccepted {
+			blks[i].StatusV = choices.Accepted
+		}
+	}
+
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.InitializeF = func(*snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+		return nil
+	}
+	vm.LastAcceptedF = func() (ids.ID, error) {
+		lastAccepted := blks[0]
+		for _, blk := range blks {
+			if blk.Status() == choices.Accepted {
+				lastAccepted = blk
+			}
+		}
+		return lastAccepted.ID(), nil
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(blk.Bytes(), b) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	return blks, vm
+}
+
+func TestHeightIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	blks, innerVM := newTestChain(t, 5, 3)
+	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
+
+	vm := New(innerVM).(*VM)
+	assert.NoError(vm.Initialize(snow.DefaultContextTest(), dbManager, nil, nil, nil, nil, nil, nil))
+
+	// The blocks accepted before the index existed are indexed
+	for _, blk := range blks[:3] {
+		blkID, err := vm.GetBlockIDAtHeight(blk.Height())
+		assert.NoError(err)
+		assert.Equal(blk.ID(), blkID)
+	}
+	_, err := vm.GetBlockIDAtHeight(3)
+	assert.ErrorIs(err, database.ErrNotFound)
+
+	// Blocks are indexed when they're accepted
+	blk, err := vm.ParseBlock(blks[3].Bytes())
+	assert.NoError(err)
+	assert.NoError(blk.Accept())
+	blkID, err := vm.GetBlockIDAtHeight(3)
+	assert.NoError(err)
+	assert.Equal(blks[3].ID(), blkID)
+
+	// The index is persisted
+	vm = New(innerVM).(*VM)
+	assert.NoError(vm.Initialize(snow.DefaultContextTest(), dbManager, nil, nil, nil, nil, nil, nil))
+	blkID, err = vm.GetBlockIDAtHeight(3)
+	assert.NoError(err)
+	assert.Equal(blks[3].ID(), blkID)
+	_, err = vm.GetBlockIDAtHeight(4)
+	assert.ErrorIs(err, database.ErrNotFound)
+}
+
+// Test that the blocks accepted while the VM wasn't wrapped are indexed, even
+// if the previous repair of the index was interrupted
+func TestRepairHeightIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	blks, innerVM := newTestChain(t, 8, 6)
+	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
+
+	vm := New(innerVM).(*VM)
+	assert.NoError(vm.Initialize(snow.DefaultContextTest(), dbManager, nil, nil, nil, nil, nil, nil))
+
+	// Simulate a repair of the index that was interrupted after indexing
+	// blocks 5 and 4, and blocks 6 and 7 being accepted since
+	for height := uint64(0); height < 4; height++ {
+		assert.NoError(vm.heights.Delete(database.PackUInt64(height)))
+	}
+	assert.NoError(database.PutID(vm.db, checkpointKey, blks[3].ID()))
+	assert.NoError(vm.db.Commit())
+	blks[6].StatusV = choices.Accepted
+	blks[7].StatusV = choices.Accepted
+
+	vm = New(innerVM).(*VM)
+	assert.NoError(vm.Initialize(snow.DefaultContextTest(), dbManager, nil, nil, nil, nil, nil, nil))
+	for _, blk := range blks {
+		blkID, err := vm.GetBlockIDAtHeight(blk.Height())
+		assert.NoError(err)
+		assert.Equal(blk.ID(), blkID)
+	}
+	_, err := vm.db.Get(checkpointKey)
+	assert.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestServiceGetBlockByHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	blks, innerVM := newTestChain(t, 3, 2)
+	vm := New(innerVM).(*VM)
+	assert.NoError(vm.Initialize(snow.DefaultContextTest(), manager.NewMemDB(version.DefaultVersion1_0_0), nil, nil, nil, nil, nil, nil))
+
+	handlers, err := vm.CreateHandlers()
+	assert.NoError(err)
+	assert.Contains(handlers, endpoint)
+
+	s := &Service{vm: vm}
+	reply := GetBlockByHeightReply{}
+	assert.NoError(s.GetBlockByHeight(nil, &GetBlockByHeightArgs{
+		Height:   1,
+		Encoding: formatting.Hex,
+	}, &reply))
+	assert.Equal(blks[1].ID(), reply.ID)
+	assert.EqualValues(1, reply.Height)
+	blkBytes, err := formatting.Decode(reply.Encoding, reply.Block)
+	assert.NoError(err)
+	assert.Equal(blks[1].Bytes(), blkBytes)
+
+	// Block 2 hasn't been accepted
+	assert.Error(s.GetBlockByHeight(nil, &GetBlockByHeightArgs{
+		Height:   2,
+		Encoding: formatting.Hex,
+	}, &reply))
+}
@@ -6,6 +6,7 @@ package vms
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
@@ -57,6 +58,9 @@ type manager struct {
 	// alias of the VM. That is, [vmID].String() is an alias for [vmID].
 	ids.Aliaser
 
+	// VMs may be registered through the admin API while chains are created
+	lock sync.RWMutex
+
 	// Key: A VM's ID
 	// Value: A factory that creates new instances of that VM
 	factories map[ids.ID]Factory
@@ -76,6 +80,9 @@ func NewManager() Manager {
 }
 
 func (m *manager) GetFactory(vmID ids.ID) (Factory, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	if factory, ok := m.factories[vmID]; ok {
 		return factory, nil
 	}
@@ -83,14 +90,17 @@ func (m *manager) GetFactory(vmID ids.ID) (Factory, error) {
 }
 
 func (m *manager) RegisterFactory(vmID ids.ID, factory Factory) error {
+	m.lock.Lock()
 	if _, exists := m.factories[vmID]; exists {
+		m.lock.Unlock()
 		return fmt.Errorf("%q was already registered as a vm", vmID)
 	}
 	if err := m.Alias(vmID, vmID.String()); err != nil {
+		m.lock.Unlock()
 		return err
 	}
-
 	m.factories[vmID] = factory
+	m.lock.Unlock()
 
 	vm, err := factory.New(nil)
 	if err != nil {
@@ -109,11 +119,16 @@ func (m *manager) RegisterFactory(vmID ids.ID, factory Factory) error {
 		return err
 	}
 
+	m.lock.Lock()
 	m.versions[vmID] = version
+	m.lock.Unlock()
 	return commonVM.Shutdown()
 }
 
 func (m *manager) ListFactories() ([]ids.ID, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	vmIDs := make([]ids.ID, 0, len(m.factories))
 	for vmID := range m.factories {
 		vmIDs = append(vmIDs, vmID)
@@ -122,6 +137,9 @@ func (m *manager) ListFactories() ([]ids.ID, error) {
 }
 
 func (m *manager) Versions() (map[string]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	versions := make(map[string]string, len(m.versions))
 	for vmID, version := range m.versions {
 		alias, err := m.PrimaryAlias(vmID)
@@ -4,6 +4,7 @@
 package metervm
 
 import (
+	"context"
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/ids"
@@ -14,6 +15,7 @@ import (
 var _ block.BatchedChainVM = &blockVM{}
 
 func (vm *blockVM) GetAncestors(
+	ctx context.Context,
 	blkID ids.ID,
 	maxBlocksNum int,
 	maxBlocksSize int,
@@ -26,6 +28,7 @@ func (vm *blockVM) GetAncestors(
 
 	start := vm.clock.Time()
 	ancestors, err := rVM.GetAncestors(
+		ctx,
 		blkID,
 		maxBlocksNum,
 		maxBlocksSize,
@@ -36,14 +39,14 @@ func (vm *blockVM) GetAncestors(
 	return ancestors, err
 }
 
-func (vm *blockVM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
+func (vm *blockVM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error) {
 	rVM, ok := vm.ChainVM.(block.BatchedChainVM)
 	if !ok {
 		return nil, block.ErrRemoteVMNotImplemented
 	}
 
 	start := vm.clock.Time()
-	blocks, err := rVM.BatchedParseBlock(blks)
+	blocks, err := rVM.BatchedParseBlock(ctx, blks)
 	end := vm.clock.Time()
 	vm.blockMetrics.batchedParseBlock.Observe(float64(end.Sub(start)))
 
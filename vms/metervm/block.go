@@ -20,6 +20,7 @@ func (mb *meterBlock) Verify() error {
 	start := mb.vm.clock.Time()
 	err := mb.Block.Verify()
 	end := mb.vm.clock.Time()
+	mb.vm.logCall("Verify", end.Sub(start))
 	duration := float64(end.Sub(start))
 	if err != nil {
 		mb.vm.blockMetrics.verifyErr.Observe(duration)
@@ -33,6 +34,7 @@ func (mb *meterBlock) Accept() error {
 	start := mb.vm.clock.Time()
 	err := mb.Block.Accept()
 	end := mb.vm.clock.Time()
+	mb.vm.logCall("Accept", end.Sub(start))
 	duration := float64(end.Sub(start))
 	mb.vm.blockMetrics.accept.Observe(duration)
 	return err
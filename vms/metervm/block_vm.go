@@ -4,6 +4,8 @@
 package metervm
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/Toinounet21/avalanchego-mod/api/metrics"
@@ -28,6 +30,7 @@ type blockVM struct {
 	block.ChainVM
 	blockMetrics
 	clock mockable.Clock
+	ctx   *snow.Context
 }
 
 func (vm *blockVM) Initialize(
@@ -40,6 +43,7 @@ func (vm *blockVM) Initialize(
 	fxs []*common.Fx,
 	appSender common.AppSender,
 ) error {
+	vm.ctx = ctx
 	registerer := prometheus.NewRegistry()
 	_, supportsBatchedFetching := vm.ChainVM.(block.BatchedChainVM)
 	if err := vm.blockMetrics.Initialize(supportsBatchedFetching, "", registerer); err != nil {
@@ -66,6 +70,7 @@ func (vm *blockVM) BuildBlock() (snowman.Block, error) {
 	start := vm.clock.Time()
 	blk, err := vm.ChainVM.BuildBlock()
 	end := vm.clock.Time()
+	vm.logCall("BuildBlock", end.Sub(start))
 	duration := float64(end.Sub(start))
 	if err != nil {
 		vm.blockMetrics.buildBlockErr.Observe(duration)
@@ -125,3 +130,14 @@ func (vm *blockVM) LastAccepted() (ids.ID, error) {
 	vm.blockMetrics.lastAccepted.Observe(float64(end.Sub(start)))
 	return lastAcceptedID, err
 }
+
+// logCall logs that [call] took [duration], along with the request of the
+// engine that [call] was made on behalf of
+func (vm *blockVM) logCall(call string, duration time.Duration) {
+	if request := vm.ctx.Request(); request != nil {
+		vm.ctx.Log.Debug("%s took %s while processing %s, received %s ago",
+			call, duration, request, vm.clock.Time().Sub(request.Received))
+		return
+	}
+	vm.ctx.Log.Debug("%s took %s outside of any request", call, duration)
+}
@@ -89,7 +89,7 @@ func (tx *UnsignedAddDelegatorTx) SyntacticVerify(ctx *snow.Context) error {
 		if err := out.Verify(); err != nil {
 			return fmt.Errorf("output verification failed: %w", err)
 		}
-		newWeight, err := math.Add64(totalStakeWeight, out.Output().Amount())
+		newWeight, err := math.Add(totalStakeWeight, out.Output().Amount())
 		if err != nil {
 			return err
 		}
@@ -212,18 +212,18 @@ func (tx *UnsignedAddDelegatorTx) Execute(
 		// Ensure that the period this delegator delegates wouldn't become over
 		// delegated.
 		vdrWeight := vdrTx.Weight()
-		currentWeight, err := math.Add64(vdrWeight, currentDelegatorWeight)
+		currentWeight, err := math.Add(vdrWeight, currentDelegatorWeight)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		maximumWeight, err := math.Mul64(MaxValidatorWeightFactor, vdrWeight)
+		maximumWeight, err := math.Mul(MaxValidatorWeightFactor, vdrWeight)
 		if err != nil {
 			return nil, nil, errStakeOverflow
 		}
 
 		if !currentTimestamp.Before(vm.ApricotPhase3Time) {
-			maximumWeight = math.Min64(maximumWeight, vm.MaxValidatorStake)
+			maximumWeight = math.Min(maximumWeight, vm.MaxValidatorStake)
 		}
 
 		canDelegate, err := CanDelegate(
@@ -339,7 +339,7 @@ func CanDelegate(
 	if err != nil {
 		return false, err
 	}
-	newMaxStake, err := math.Add64(maxStake, new.Validator.Wght)
+	newMaxStake, err := math.Add(maxStake, new.Validator.Wght)
 	if err != nil {
 		return false, err
 	}
@@ -411,7 +411,7 @@ func maxStakeAmount(
 				maxStake = currentStake
 			}
 
-			currentStake, err = math.Sub64(currentStake, toRemove.Wght)
+			currentStake, err = math.Sub(currentStake, toRemove.Wght)
 			if err != nil {
 				return 0, err
 			}
@@ -425,7 +425,7 @@ func maxStakeAmount(
 		// Add to [currentStake] the stake of this pending delegator to
 		// calculate what the stake will be when this pending delegation has
 		// started.
-		currentStake, err = math.Add64(currentStake, nextPending.Validator.Wght)
+		currentStake, err = math.Add(currentStake, nextPending.Validator.Wght)
 		if err != nil {
 			return 0, err
 		}
@@ -459,7 +459,7 @@ func maxStakeAmount(
 			break
 		}
 
-		currentStake, err = math.Sub64(currentStake, toRemove.Wght)
+		currentStake, err = math.Sub(currentStake, toRemove.Wght)
 		if err != nil {
 			return 0, err
 		}
@@ -558,7 +558,7 @@ func (vm *VM) maxPrimarySubnetStakeAmount(
 		}
 
 		currentWeight := vdrTx.Weight()
-		currentWeight, err = math.Add64(currentWeight, currentValidator.DelegatorWeight())
+		currentWeight, err = math.Add(currentWeight, currentValidator.DelegatorWeight())
 		if err != nil {
 			return 0, err
 		}
@@ -125,7 +125,7 @@ pendingStakerLoop:
 				staker.Validator.Wght,
 				currentSupply,
 			)
-			currentSupply, err = safemath.Add64(currentSupply, r)
+			currentSupply, err = safemath.Add(currentSupply, r)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -145,7 +145,7 @@ pendingStakerLoop:
 				staker.Validator.Wght,
 				currentSupply,
 			)
-			currentSupply, err = safemath.Add64(currentSupply, r)
+			currentSupply, err = safemath.Add(currentSupply, r)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -230,3 +230,41 @@ func (vm *VM) newAdvanceTimeTx(timestamp time.Time) (*Tx, error) {
 	}}
 	return tx, tx.Sign(Codec, nil)
 }
+
+// projectedSupply returns the supply once every pending staker that starts at
+// or before [startTime] has been added to the current staker set. If
+// [untilTxID] is a pending staker, only the stakers ahead of it are included.
+func (vm *VM) projectedSupply(startTime time.Time, untilTxID ids.ID) (uint64, error) {
+	currentSupply := vm.internalState.GetCurrentSupply()
+	for _, tx := range vm.internalState.PendingStakerChainState().Stakers() {
+		if tx.ID() == untilTxID {
+			break
+		}
+
+		var vdr *Validator
+		switch staker := tx.UnsignedTx.(type) {
+		case *UnsignedAddValidatorTx:
+			vdr = &staker.Validator
+		case *UnsignedAddDelegatorTx:
+			vdr = &staker.Validator
+		case *UnsignedAddSubnetValidatorTx:
+			if staker.StartTime().After(startTime) {
+				return currentSupply, nil
+			}
+			continue
+		default:
+			return 0, fmt.Errorf("expected validator but got %T", tx.UnsignedTx)
+		}
+		if vdr.StartTime().After(startTime) {
+			break
+		}
+
+		r := vm.rewards.Calculate(vdr.Duration(), vdr.Wght, currentSupply)
+		var err error
+		currentSupply, err = safemath.Add(currentSupply, r)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return currentSupply, nil
+}
@@ -298,7 +298,7 @@ func (cs *currentStakerChainStateImpl) primaryValidatorSet() (validators.Set, er
 	var err error
 	for nodeID, vdr := range cs.validatorsByNodeID {
 		vdrWeight := vdr.addValidatorTx.Validator.Wght
-		vdrWeight, err = safemath.Add64(vdrWeight, vdr.delegatorWeight)
+		vdrWeight, err = safemath.Add(vdrWeight, vdr.delegatorWeight)
 		if err != nil {
 			return nil, err
 		}
@@ -36,18 +36,26 @@ var (
 	delegatorPrefix       = []byte("delegator")
 	subnetValidatorPrefix = []byte("subnetValidator")
 	validatorDiffsPrefix  = []byte("validatorDiffs")
+	stakerDiffsPrefix     = []byte("stakerDiffs")
 	blockPrefix           = []byte("block")
+	heightIndexPrefix     = []byte("heightIndex")
+	txIndexPrefix         = []byte("txIndex")
 	txPrefix              = []byte("tx")
 	rewardUTXOsPrefix     = []byte("rewardUTXOs")
 	utxoPrefix            = []byte("utxo")
 	subnetPrefix          = []byte("subnet")
 	chainPrefix           = []byte("chain")
 	singletonPrefix       = []byte("singleton")
+	// The uptime history isn't part of the internal state, but shares its
+	// database
+	uptimeHistoryPrefix = []byte("uptimeHistory")
 
-	timestampKey     = []byte("timestamp")
-	currentSupplyKey = []byte("current supply")
-	lastAcceptedKey  = []byte("last accepted")
-	initializedKey   = []byte("initialized")
+	timestampKey              = []byte("timestamp")
+	currentSupplyKey          = []byte("current supply")
+	lastAcceptedKey           = []byte("last accepted")
+	initializedKey            = []byte("initialized")
+	stakerDiffsStartHeightKey = []byte("staker diffs start height")
+	indexStartHeightKey       = []byte("index start height")
 
 	errWrongNetworkID = errors.New("tx has wrong network ID")
 
@@ -81,6 +89,14 @@ type InternalState interface {
 	DeleteCurrentStaker(tx *Tx)
 	GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.ShortID]*ValidatorWeightDiff, error)
 
+	// GetStakerDiff returns the stakers that were added to and removed from
+	// the current staker set by the block at [height].
+	GetStakerDiff(height uint64) (*StakerDiff, error)
+	// GetStakerDiffsStartHeight returns the first height whose staker diff was
+	// recorded. Staker diffs of prior heights aren't available, because they
+	// were accepted before staker diffs were recorded.
+	GetStakerDiffsStartHeight() (uint64, bool)
+
 	AddPendingStaker(tx *Tx)
 	DeletePendingStaker(tx *Tx)
 
@@ -93,6 +109,15 @@ type InternalState interface {
 	GetBlock(blockID ids.ID) (Block, error)
 	AddBlock(block Block)
 
+	// GetBlockIndex returns the accepted block at [height].
+	GetBlockIndex(height uint64) (*BlockIndex, error)
+	// GetTxIndex returns the accepted block that included [txID].
+	GetTxIndex(txID ids.ID) (*BlockIndex, error)
+	// GetIndexStartHeight returns the first height whose block was indexed.
+	// Blocks of prior heights aren't indexed, because they were accepted
+	// before the index existed.
+	GetIndexStartHeight() (uint64, bool)
+
 	Abort()
 	Commit() error
 	CommitBatch() (database.Batch, error)
@@ -122,12 +147,18 @@ type InternalState interface {
  * | | '-. subnetValidator
  * | |   '-. list
  * | |     '-- txID -> nil
- * | '-. diffs
- * |   '-. height+subnet
- * |     '-. list
- * |       '-- nodeID -> weightChange
+ * | |-. diffs
+ * | | '-. height+subnet
+ * | |   '-. list
+ * | |     '-- nodeID -> weightChange
+ * | '-. stakerDiffs
+ * |   '-- height -> added + removed stakers
  * |-. blocks
  * | '-- blockID -> block bytes
+ * |-. heightIndex
+ * | '-- height -> blockID + height + timestamp
+ * |-. txIndex
+ * | '-- txID -> blockID + height + timestamp
  * |-. txs
  * | '-- txID -> tx bytes + tx status
  * |- rewardUTXOs
@@ -147,7 +178,9 @@ type InternalState interface {
  *   |-- initializedKey -> nil
  *   |-- timestampKey -> timestamp
  *   |-- currentSupplyKey -> currentSupply
- *   '-- lastAcceptedKey -> lastAccepted
+ *   |-- lastAcceptedKey -> lastAccepted
+ *   |-- stakerDiffsStartHeightKey -> stakerDiffsStartHeight
+ *   '-- indexStartHeightKey -> indexStartHeight
  */
 type internalStateImpl struct {
 	vm *VM
@@ -184,10 +217,19 @@ type internalStateImpl struct {
 	validatorDiffsCache cache.Cacher // cache of heightWithSubnet -> map[ids.ShortID]*ValidatorWeightDiff
 	validatorDiffsDB    database.Database
 
+	stakerDiffsDB             database.Database
+	stakerDiffsStartHeight    uint64
+	hasStakerDiffsStartHeight bool
+
 	addedBlocks map[ids.ID]Block // map of blockID -> Block
 	blockCache  cache.Cacher     // cache of blockID -> Block, if the entry is nil, it is not in the database
 	blockDB     database.Database
 
+	heightIndexDB       database.Database
+	txIndexDB           database.Database
+	indexStartHeight    uint64
+	hasIndexStartHeight bool
+
 	addedTxs map[ids.ID]*txStatusImpl // map of txID -> {*Tx, Status}
 	txCache  cache.Cacher             // cache of txID -> {*Tx, Status} if the entry is nil, it is not in the database
 	txDB     database.Database
@@ -221,6 +263,27 @@ type ValidatorWeightDiff struct {
 	Amount   uint64 `serialize:"true"`
 }
 
+// StakerDiff is the change made to the current staker set by a block
+type StakerDiff struct {
+	Added   []StakerDiffEntry `serialize:"true"`
+	Removed []StakerDiffEntry `serialize:"true"`
+}
+
+// StakerDiffEntry is a staker that was added to or removed from the current
+// staker set
+type StakerDiffEntry struct {
+	TxID            ids.ID `serialize:"true"`
+	PotentialReward uint64 `serialize:"true"`
+}
+
+// BlockIndex locates an accepted block
+type BlockIndex struct {
+	BlkID  ids.ID `serialize:"true"`
+	Height uint64 `serialize:"true"`
+	// Unix time of the chain once the block was accepted
+	Timestamp uint64 `serialize:"true"`
+}
+
 type heightWithSubnet struct {
 	Height   uint64 `serialize:"true"`
 	SubnetID ids.ID `serialize:"true"`
@@ -252,6 +315,7 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 	pendingSubnetValidatorBaseDB := prefixdb.New(subnetValidatorPrefix, pendingValidatorsDB)
 
 	validatorDiffsDB := prefixdb.New(validatorDiffsPrefix, validatorsDB)
+	stakerDiffsDB := prefixdb.New(stakerDiffsPrefix, validatorsDB)
 
 	rewardUTXODB := prefixdb.New(rewardUTXOsPrefix, baseDB)
 	utxoDB := prefixdb.New(utxoPrefix, baseDB)
@@ -280,10 +344,14 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 		pendingSubnetValidatorBaseDB: pendingSubnetValidatorBaseDB,
 		pendingSubnetValidatorList:   linkeddb.NewDefault(pendingSubnetValidatorBaseDB),
 		validatorDiffsDB:             validatorDiffsDB,
+		stakerDiffsDB:                stakerDiffsDB,
 
 		addedBlocks: make(map[ids.ID]Block),
 		blockDB:     prefixdb.New(blockPrefix, baseDB),
 
+		heightIndexDB: prefixdb.New(heightIndexPrefix, baseDB),
+		txIndexDB:     prefixdb.New(txIndexPrefix, baseDB),
+
 		addedTxs: make(map[ids.ID]*txStatusImpl),
 		txDB:     prefixdb.New(txPrefix, baseDB),
 
@@ -673,6 +741,30 @@ func (st *internalStateImpl) AddBlock(block Block) {
 	st.addedBlocks[block.ID()] = block
 }
 
+func (st *internalStateImpl) GetBlockIndex(height uint64) (*BlockIndex, error) {
+	return getBlockIndex(st.heightIndexDB, database.PackUInt64(height))
+}
+
+func (st *internalStateImpl) GetTxIndex(txID ids.ID) (*BlockIndex, error) {
+	return getBlockIndex(st.txIndexDB, txID[:])
+}
+
+func (st *internalStateImpl) GetIndexStartHeight() (uint64, bool) {
+	return st.indexStartHeight, st.hasIndexStartHeight
+}
+
+func getBlockIndex(db database.KeyValueReader, key []byte) (*BlockIndex, error) {
+	indexBytes, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	index := &BlockIndex{}
+	if _, err := GenesisCodec.Unmarshal(indexBytes, index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
 func (st *internalStateImpl) UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error) {
 	return st.utxoState.UTXOIDs(addr, start, limit)
 }
@@ -782,6 +874,27 @@ func (st *internalStateImpl) GetValidatorWeightDiffs(height uint64, subnetID ids
 	return weightDiffs, nil
 }
 
+func (st *internalStateImpl) GetStakerDiff(height uint64) (*StakerDiff, error) {
+	diffBytes, err := st.stakerDiffsDB.Get(database.PackUInt64(height))
+	if err == database.ErrNotFound {
+		// The block at [height] didn't change the current staker set
+		return &StakerDiff{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &StakerDiff{}
+	if _, err := GenesisCodec.Unmarshal(diffBytes, diff); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func (st *internalStateImpl) GetStakerDiffsStartHeight() (uint64, bool) {
+	return st.stakerDiffsStartHeight, st.hasStakerDiffsStartHeight
+}
+
 func (st *internalStateImpl) Abort() {
 	st.baseDB.Abort()
 }
@@ -842,6 +955,8 @@ func (st *internalStateImpl) Close() error {
 		st.currentValidatorsDB.Close(),
 		st.validatorsDB.Close(),
 		st.blockDB.Close(),
+		st.heightIndexDB.Close(),
+		st.txIndexDB.Close(),
 		st.txDB.Close(),
 		st.rewardUTXODB.Close(),
 		st.utxoDB.Close(),
@@ -864,9 +979,14 @@ type currentValidatorState struct {
 
 func (st *internalStateImpl) writeCurrentStakers() error {
 	weightDiffs := make(map[ids.ID]map[ids.ShortID]*ValidatorWeightDiff) // subnetID -> nodeID -> weightDiff
+	stakerDiff := StakerDiff{}
 	for _, currentStaker := range st.addedCurrentStakers {
 		txID := currentStaker.addStakerTx.ID()
 		potentialReward := currentStaker.potentialReward
+		stakerDiff.Added = append(stakerDiff.Added, StakerDiffEntry{
+			TxID:            txID,
+			PotentialReward: potentialReward,
+		})
 
 		var (
 			subnetID ids.ID
@@ -930,7 +1050,7 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 			subnetDiffs[nodeID] = nodeDiff
 		}
 
-		newWeight, err := safemath.Add64(nodeDiff.Amount, weight)
+		newWeight, err := safemath.Add(nodeDiff.Amount, weight)
 		if err != nil {
 			return err
 		}
@@ -939,16 +1059,22 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 	st.addedCurrentStakers = nil
 
 	for _, tx := range st.deletedCurrentStakers {
+		txID := tx.ID()
+
 		var (
-			db       database.KeyValueDeleter
-			subnetID ids.ID
-			nodeID   ids.ShortID
-			weight   uint64
+			db              database.KeyValueDeleter
+			subnetID        ids.ID
+			nodeID          ids.ShortID
+			weight          uint64
+			potentialReward uint64
 		)
 		switch tx := tx.UnsignedTx.(type) {
 		case *UnsignedAddValidatorTx:
 			db = st.currentValidatorList
 
+			if vdr, ok := st.uptimes[tx.Validator.NodeID]; ok {
+				potentialReward = vdr.PotentialReward
+			}
 			delete(st.uptimes, tx.Validator.NodeID)
 			delete(st.updatedUptimes, tx.Validator.NodeID)
 
@@ -958,6 +1084,12 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 		case *UnsignedAddDelegatorTx:
 			db = st.currentDelegatorList
 
+			reward, err := database.GetUInt64(st.currentDelegatorList, txID[:])
+			if err != nil {
+				return err
+			}
+			potentialReward = reward
+
 			subnetID = constants.PrimaryNetworkID
 			nodeID = tx.Validator.NodeID
 			weight = tx.Validator.Wght
@@ -971,10 +1103,13 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 			return errWrongTxType
 		}
 
-		txID := tx.ID()
 		if err := db.Delete(txID[:]); err != nil {
 			return err
 		}
+		stakerDiff.Removed = append(stakerDiff.Removed, StakerDiffEntry{
+			TxID:            txID,
+			PotentialReward: potentialReward,
+		})
 
 		subnetDiffs, ok := weightDiffs[subnetID]
 		if !ok {
@@ -989,18 +1124,22 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 		}
 
 		if nodeDiff.Decrease {
-			newWeight, err := safemath.Add64(nodeDiff.Amount, weight)
+			newWeight, err := safemath.Add(nodeDiff.Amount, weight)
 			if err != nil {
 				return err
 			}
 			nodeDiff.Amount = newWeight
 		} else {
 			nodeDiff.Decrease = nodeDiff.Amount < weight
-			nodeDiff.Amount = safemath.Diff64(nodeDiff.Amount, weight)
+			nodeDiff.Amount = safemath.Diff(nodeDiff.Amount, weight)
 		}
 	}
 	st.deletedCurrentStakers = nil
 
+	if err := st.writeStakerDiff(&stakerDiff); err != nil {
+		return err
+	}
+
 	for subnetID, nodeUpdates := range weightDiffs {
 		prefixStruct := heightWithSubnet{
 			Height:   st.currentHeight,
@@ -1055,6 +1194,30 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 	return nil
 }
 
+// writeStakerDiff records [diff] as the change made to the current staker set
+// at the current height.
+func (st *internalStateImpl) writeStakerDiff(diff *StakerDiff) error {
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 {
+		return nil
+	}
+
+	// The first time a staker diff is written, record its height so that
+	// snapshots aren't built from the incomplete diffs of prior heights.
+	if !st.hasStakerDiffsStartHeight {
+		if err := database.PutUInt64(st.singletonDB, stakerDiffsStartHeightKey, st.currentHeight); err != nil {
+			return err
+		}
+		st.stakerDiffsStartHeight = st.currentHeight
+		st.hasStakerDiffsStartHeight = true
+	}
+
+	diffBytes, err := GenesisCodec.Marshal(CodecVersion, diff)
+	if err != nil {
+		return err
+	}
+	return st.stakerDiffsDB.Put(database.PackUInt64(st.currentHeight), diffBytes)
+}
+
 func (st *internalStateImpl) writePendingStakers() error {
 	for _, tx := range st.addedPendingStakers {
 		var db database.KeyValueWriter
@@ -1135,10 +1298,65 @@ func (st *internalStateImpl) writeBlocks() error {
 		if err := st.blockDB.Put(blkID[:], btxBytes); err != nil {
 			return err
 		}
+
+		if blk.Status() == choices.Accepted {
+			if err := st.writeBlockIndex(blk); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// writeBlockIndex indexes the accepted block [blk] by its height and by the
+// IDs of the txs it includes.
+func (st *internalStateImpl) writeBlockIndex(blk Block) error {
+	height := blk.Height()
+
+	// The first time a block is indexed, record its height so that lookups
+	// of prior heights are known to be unindexed rather than missing.
+	if !st.hasIndexStartHeight {
+		if err := database.PutUInt64(st.singletonDB, indexStartHeightKey, height); err != nil {
+			return err
+		}
+		st.indexStartHeight = height
+		st.hasIndexStartHeight = true
+	}
+
+	indexBytes, err := GenesisCodec.Marshal(CodecVersion, &BlockIndex{
+		BlkID:     blk.ID(),
+		Height:    height,
+		Timestamp: uint64(st.timestamp.Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	if err := st.heightIndexDB.Put(database.PackUInt64(height), indexBytes); err != nil {
+		return err
+	}
+	for _, tx := range blockTxs(blk) {
+		txID := tx.ID()
+		if err := st.txIndexDB.Put(txID[:], indexBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockTxs returns the txs included in [blk]
+func blockTxs(blk Block) []*Tx {
+	switch blk := blk.(type) {
+	case *ProposalBlock:
+		return []*Tx{&blk.Tx}
+	case *AtomicBlock:
+		return []*Tx{&blk.Tx}
+	case *StandardBlock:
+		return blk.Txs
+	default:
+		return nil
+	}
+}
+
 func (st *internalStateImpl) writeTXs() error {
 	for txID, txStatus := range st.addedTxs {
 		txID := txID
@@ -1282,6 +1500,28 @@ func (st *internalStateImpl) loadSingletons() error {
 	st.originalLastAccepted = lastAccepted
 	st.lastAccepted = lastAccepted
 
+	stakerDiffsStartHeight, err := database.GetUInt64(st.singletonDB, stakerDiffsStartHeightKey)
+	switch err {
+	case nil:
+		st.stakerDiffsStartHeight = stakerDiffsStartHeight
+		st.hasStakerDiffsStartHeight = true
+	case database.ErrNotFound:
+		// No staker diffs have been recorded yet
+	default:
+		return err
+	}
+
+	indexStartHeight, err := database.GetUInt64(st.singletonDB, indexStartHeightKey)
+	switch err {
+	case nil:
+		st.indexStartHeight = indexStartHeight
+		st.hasIndexStartHeight = true
+	case database.ErrNotFound:
+		// No blocks have been indexed yet
+	default:
+		return err
+	}
+
 	return nil
 }
 
@@ -1565,7 +1805,7 @@ func (st *internalStateImpl) init(genesisBytes []byte) error {
 			stakeAmount,
 			currentSupply,
 		)
-		newCurrentSupply, err := safemath.Add64(currentSupply, r)
+		newCurrentSupply, err := safemath.Add(currentSupply, r)
 		if err != nil {
 			return err
 		}
@@ -58,8 +58,18 @@ type Client interface {
 	GetStakingAssetID(context.Context, ids.ID) (ids.ID, error)
 	// GetCurrentValidators returns the list of current validators for subnet with ID [subnetID]
 	GetCurrentValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.ShortID) ([]interface{}, error)
+	// GetCurrentValidatorsPage returns at most [limit] current validators for
+	// subnet with ID [subnetID], starting at [startNodeID] in order of node ID,
+	// and the node ID the next page starts at, or "" if there are no more
+	// validators
+	GetCurrentValidatorsPage(ctx context.Context, subnetID ids.ID, startNodeID ids.ShortID, limit uint32) ([]interface{}, string, error)
 	// GetPendingValidators returns the list of pending validators for subnet with ID [subnetID]
 	GetPendingValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.ShortID) ([]interface{}, []interface{}, error)
+	// GetPendingValidatorsPage returns the pending validators and delegators
+	// of at most [limit] node IDs for subnet with ID [subnetID], starting at
+	// [startNodeID] in order of node ID, and the node ID the next page starts
+	// at, or "" if there are no more stakers
+	GetPendingValidatorsPage(ctx context.Context, subnetID ids.ID, startNodeID ids.ShortID, limit uint32) ([]interface{}, []interface{}, string, error)
 	// GetCurrentSupply returns an upper bound on the supply of AVAX in the system
 	GetCurrentSupply(ctx context.Context) (uint64, error)
 	// SampleValidators returns the nodeIDs of a sample of [sampleSize] validators from the current validator set for subnet with ID [subnetID]
@@ -168,13 +178,29 @@ type Client interface {
 	// GetMaxStakeAmount returns the maximum amount of nAVAX staking to the named
 	// node during the time period.
 	GetMaxStakeAmount(ctx context.Context, subnetID ids.ID, nodeID string, startTime uint64, endTime uint64) (uint64, error)
+	// GetExpectedReward returns the reward of a hypothetical staker of [amount]
+	// nAVAX for [duration] seconds, starting at the unix time [startTime]
+	GetExpectedReward(ctx context.Context, amount uint64, duration uint64, startTime uint64) (uint64, error)
+	// GetStakerReward returns the reward of the staker added by [txID] and
+	// whether the staker is current, pending or already rewarded
+	GetStakerReward(ctx context.Context, txID ids.ID) (uint64, string, error)
 	// GetRewardUTXOs returns the reward UTXOs for a transaction
 	GetRewardUTXOs(context.Context, *api.GetTxArgs) ([][]byte, error)
 	// GetTimestamp returns the current chain timestamp
 	GetTimestamp(ctx context.Context) (time.Time, error)
+	// GetBlockByHeight returns the bytes of the accepted block at [height],
+	// and where it is in the chain
+	GetBlockByHeight(ctx context.Context, height uint64) ([]byte, *APIBlockIndex, error)
 	// GetValidatorsAt returns the weights of the validator set of a provided subnet
 	// at the specified height.
 	GetValidatorsAt(ctx context.Context, subnetID ids.ID, height uint64) (map[string]uint64, error)
+	// GetStakingSnapshot returns the stakers as of the specified height.
+	// [format] is either "json" or "csv".
+	GetStakingSnapshot(ctx context.Context, height uint64, format string) (*GetStakingSnapshotReply, error)
+	// GetUptimeHistory returns the uptime of [nodeID], as observed by the
+	// node, between the unix times [startTime] and [endTime]. If [endTime] is
+	// 0, it defaults to the current time.
+	GetUptimeHistory(ctx context.Context, nodeID string, startTime uint64, endTime uint64) (*GetUptimeHistoryReply, error)
 }
 
 // Client implementation for interacting with the P Chain endpoint
@@ -293,6 +319,16 @@ func (c *client) GetCurrentValidators(ctx context.Context, subnetID ids.ID, node
 	return res.Validators, err
 }
 
+func (c *client) GetCurrentValidatorsPage(ctx context.Context, subnetID ids.ID, startNodeID ids.ShortID, limit uint32) ([]interface{}, string, error) {
+	res := &GetCurrentValidatorsReply{}
+	err := c.requester.SendRequest(ctx, "getCurrentValidators", &GetCurrentValidatorsArgs{
+		SubnetID:    subnetID,
+		StartNodeID: startNodeID.PrefixedString(constants.NodeIDPrefix),
+		Limit:       json.Uint32(limit),
+	}, res)
+	return res.Validators, res.NextNodeID, err
+}
+
 func (c *client) GetPendingValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.ShortID) ([]interface{}, []interface{}, error) {
 	nodeIDsStr := []string{}
 	for _, nodeID := range nodeIDs {
@@ -306,6 +342,16 @@ func (c *client) GetPendingValidators(ctx context.Context, subnetID ids.ID, node
 	return res.Validators, res.Delegators, err
 }
 
+func (c *client) GetPendingValidatorsPage(ctx context.Context, subnetID ids.ID, startNodeID ids.ShortID, limit uint32) ([]interface{}, []interface{}, string, error) {
+	res := &GetPendingValidatorsReply{}
+	err := c.requester.SendRequest(ctx, "getPendingValidators", &GetPendingValidatorsArgs{
+		SubnetID:    subnetID,
+		StartNodeID: startNodeID.PrefixedString(constants.NodeIDPrefix),
+		Limit:       json.Uint32(limit),
+	}, res)
+	return res.Validators, res.Delegators, res.NextNodeID, err
+}
+
 func (c *client) GetCurrentSupply(ctx context.Context) (uint64, error) {
 	res := &GetCurrentSupplyReply{}
 	err := c.requester.SendRequest(ctx, "getCurrentSupply", struct{}{}, res)
@@ -605,6 +651,24 @@ func (c *client) GetMaxStakeAmount(ctx context.Context, subnetID ids.ID, nodeID
 	return uint64(res.Amount), err
 }
 
+func (c *client) GetExpectedReward(ctx context.Context, amount, duration, startTime uint64) (uint64, error) {
+	res := &GetExpectedRewardReply{}
+	err := c.requester.SendRequest(ctx, "getExpectedReward", &GetExpectedRewardArgs{
+		Amount:    json.Uint64(amount),
+		Duration:  json.Uint64(duration),
+		StartTime: json.Uint64(startTime),
+	}, res)
+	return uint64(res.Reward), err
+}
+
+func (c *client) GetStakerReward(ctx context.Context, txID ids.ID) (uint64, string, error) {
+	res := &GetExpectedRewardReply{}
+	err := c.requester.SendRequest(ctx, "getExpectedReward", &GetExpectedRewardArgs{
+		TxID: txID,
+	}, res)
+	return uint64(res.Reward), res.StakerStatus, err
+}
+
 func (c *client) GetRewardUTXOs(ctx context.Context, args *api.GetTxArgs) ([][]byte, error) {
 	res := &GetRewardUTXOsReply{}
 	err := c.requester.SendRequest(ctx, "getRewardUTXOs", args, res)
@@ -628,6 +692,19 @@ func (c *client) GetTimestamp(ctx context.Context) (time.Time, error) {
 	return res.Timestamp, err
 }
 
+func (c *client) GetBlockByHeight(ctx context.Context, height uint64) ([]byte, *APIBlockIndex, error) {
+	res := &GetBlockByHeightReply{}
+	err := c.requester.SendRequest(ctx, "getBlockByHeight", &GetBlockByHeightArgs{
+		Height:   json.Uint64(height),
+		Encoding: formatting.Hex,
+	}, res)
+	if err != nil {
+		return nil, nil, err
+	}
+	blkBytes, err := formatting.Decode(res.Encoding, res.Block)
+	return blkBytes, &res.APIBlockIndex, err
+}
+
 func (c *client) GetValidatorsAt(ctx context.Context, subnetID ids.ID, height uint64) (map[string]uint64, error) {
 	res := &GetValidatorsAtReply{}
 	err := c.requester.SendRequest(ctx, "getValidatorsAt", &GetValidatorsAtArgs{
@@ -636,3 +713,22 @@ func (c *client) GetValidatorsAt(ctx context.Context, subnetID ids.ID, height ui
 	}, res)
 	return res.Validators, err
 }
+
+func (c *client) GetStakingSnapshot(ctx context.Context, height uint64, format string) (*GetStakingSnapshotReply, error) {
+	res := &GetStakingSnapshotReply{}
+	err := c.requester.SendRequest(ctx, "getStakingSnapshot", &GetStakingSnapshotArgs{
+		Height: json.Uint64(height),
+		Format: format,
+	}, res)
+	return res, err
+}
+
+func (c *client) GetUptimeHistory(ctx context.Context, nodeID string, startTime uint64, endTime uint64) (*GetUptimeHistoryReply, error) {
+	res := &GetUptimeHistoryReply{}
+	err := c.requester.SendRequest(ctx, "getUptimeHistory", &GetUptimeHistoryArgs{
+		NodeID:    nodeID,
+		StartTime: json.Uint64(startTime),
+		EndTime:   json.Uint64(endTime),
+	}, res)
+	return res, err
+}
@@ -187,7 +187,7 @@ func (vm *VM) newExportTx(
 	keys []*crypto.PrivateKeySECP256K1R, // Pay the fee and provide the tokens
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	toBurn, err := math.Add64(amount, vm.TxFee)
+	toBurn, err := math.Add(amount, vm.TxFee)
 	if err != nil {
 		return nil, errOverflowExport
 	}
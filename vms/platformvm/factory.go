@@ -36,6 +36,10 @@ type Factory struct {
 	// Set of subnets that this node is validating
 	WhitelistedSubnets ids.Set
 
+	// Nodes that txs issued to this node's API are forwarded to. If empty, txs
+	// are only gossiped.
+	TxForwardingNodeIDs ids.ShortSet
+
 	// Fee that must be burned by every create staker transaction
 	AddStakerTxFee uint64
 
@@ -36,6 +36,14 @@ type Fx interface {
 	CreateOutput(amount uint64, controlGroup interface{}) (interface{}, error)
 }
 
+// batchFx is implemented by feature extensions that can defer the signature
+// checks of a transaction to verify them together.
+type batchFx interface {
+	StartBatch()
+	VerifyBatch() error
+	AbortBatch()
+}
+
 type Owner interface {
 	verify.Verifiable
 	snow.ContextInitializable
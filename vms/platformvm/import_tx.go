@@ -227,7 +227,7 @@ func (vm *VM) newImportTx(
 		if !ok {
 			continue
 		}
-		importedAmount, err = math.Add64(importedAmount, input.Amount())
+		importedAmount, err = math.Add(importedAmount, input.Amount())
 		if err != nil {
 			return nil, err
 		}
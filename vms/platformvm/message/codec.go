@@ -27,6 +27,7 @@ func init() {
 	errs := wrappers.Errs{}
 	errs.Add(
 		lc.RegisterType(&Tx{}),
+		lc.RegisterType(&TxAck{}),
 		c.RegisterCodec(codecVersion, lc),
 	)
 	if errs.Errored() {
@@ -13,6 +13,7 @@ var _ Handler = NoopHandler{}
 
 type Handler interface {
 	HandleTx(nodeID ids.ShortID, requestID uint32, msg *Tx) error
+	HandleTxAck(nodeID ids.ShortID, requestID uint32, msg *TxAck) error
 }
 
 type NoopHandler struct {
@@ -27,3 +28,12 @@ func (h NoopHandler) HandleTx(nodeID ids.ShortID, requestID uint32, _ *Tx) error
 	)
 	return nil
 }
+
+func (h NoopHandler) HandleTxAck(nodeID ids.ShortID, requestID uint32, _ *TxAck) error {
+	h.Log.Debug(
+		"dropping unexpected TxAck message from %s with requestID %s",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		requestID,
+	)
+	return nil
+}
@@ -13,7 +13,8 @@ import (
 )
 
 type CounterHandler struct {
-	Tx int
+	Tx    int
+	TxAck int
 }
 
 func (h *CounterHandler) HandleTx(ids.ShortID, uint32, *Tx) error {
@@ -21,6 +22,11 @@ func (h *CounterHandler) HandleTx(ids.ShortID, uint32, *Tx) error {
 	return nil
 }
 
+func (h *CounterHandler) HandleTxAck(ids.ShortID, uint32, *TxAck) error {
+	h.TxAck++
+	return nil
+}
+
 func TestHandleTx(t *testing.T) {
 	assert := assert.New(t)
 
@@ -32,6 +38,17 @@ func TestHandleTx(t *testing.T) {
 	assert.Equal(1, handler.Tx)
 }
 
+func TestHandleTxAck(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := CounterHandler{}
+	msg := TxAck{}
+
+	err := msg.Handle(&handler, ids.ShortEmpty, 0)
+	assert.NoError(err)
+	assert.Equal(1, handler.TxAck)
+}
+
 func TestNoopHandler(t *testing.T) {
 	assert := assert.New(t)
 
@@ -41,4 +58,7 @@ func TestNoopHandler(t *testing.T) {
 
 	err := handler.HandleTx(ids.ShortEmpty, 0, nil)
 	assert.NoError(err)
+
+	err = handler.HandleTxAck(ids.ShortEmpty, 0, nil)
+	assert.NoError(err)
 }
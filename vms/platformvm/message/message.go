@@ -11,6 +11,7 @@ import (
 
 var (
 	_ Message = &Tx{}
+	_ Message = &TxAck{}
 
 	errUnexpectedCodecVersion = errors.New("unexpected codec version")
 )
@@ -43,6 +44,19 @@ func (msg *Tx) Handle(handler Handler, nodeID ids.ShortID, requestID uint32) err
 	return handler.HandleTx(nodeID, requestID, msg)
 }
 
+// TxAck acknowledges the receipt of a Tx that was sent in an AppRequest.
+type TxAck struct {
+	message
+
+	TxID ids.ID `serialize:"true"`
+	// Added is true if the tx was added to the recipient's mempool.
+	Added bool `serialize:"true"`
+}
+
+func (msg *TxAck) Handle(handler Handler, nodeID ids.ShortID, requestID uint32) error {
+	return handler.HandleTxAck(nodeID, requestID, msg)
+}
+
 func Parse(bytes []byte) (Message, error) {
 	var msg Message
 	version, err := c.Unmarshal(bytes, &msg)
@@ -6,6 +6,7 @@ package message
 import (
 	"testing"
 
+	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils"
 	"github.com/Toinounet21/avalanchego-mod/utils/units"
 
@@ -33,6 +34,29 @@ func TestTx(t *testing.T) {
 	assert.Equal(tx, parsedMsg.Tx)
 }
 
+func TestTxAck(t *testing.T) {
+	assert := assert.New(t)
+
+	txID := ids.GenerateTestID()
+	builtMsg := TxAck{
+		TxID:  txID,
+		Added: true,
+	}
+	builtMsgBytes, err := Build(&builtMsg)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, builtMsg.Bytes())
+
+	parsedMsgIntf, err := Parse(builtMsgBytes)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, parsedMsgIntf.Bytes())
+
+	parsedMsg, ok := parsedMsgIntf.(*TxAck)
+	assert.True(ok)
+
+	assert.Equal(txID, parsedMsg.TxID)
+	assert.True(parsedMsg.Added)
+}
+
 func TestParseGibberish(t *testing.T) {
 	assert := assert.New(t)
 
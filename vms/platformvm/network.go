@@ -19,8 +19,19 @@ const (
 	// We allow [recentCacheSize] to be fairly large because we only store hashes
 	// in the cache, not entire transactions.
 	recentCacheSize = 512
+
+	// Max number of times a tx is forwarded to the same node before giving up
+	maxForwardAttempts = 3
 )
 
+// forwardedTx is a tx that was forwarded to a node and not acknowledged yet
+type forwardedTx struct {
+	txID    ids.ID
+	msg     []byte
+	nodeID  ids.ShortID
+	attempt int
+}
+
 type network struct {
 	log logging.Logger
 	// gossip related attributes
@@ -29,6 +40,12 @@ type network struct {
 	mempool              *blockBuilder
 	vm                   *VM
 	recentTxs            *cache.LRU
+
+	// tx forwarding related attributes
+	forwardingNodeIDs ids.ShortSet
+	requestID         uint32
+	// requestID -> tx that was forwarded in the request
+	forwardedTxs map[uint32]forwardedTx
 }
 
 func newNetwork(activationTime time.Time, appSender common.AppSender, vm *VM) *network {
@@ -39,26 +56,132 @@ func newNetwork(activationTime time.Time, appSender common.AppSender, vm *VM) *n
 		mempool:              &vm.blockBuilder,
 		vm:                   vm,
 		recentTxs:            &cache.LRU{Size: recentCacheSize},
+		forwardingNodeIDs:    vm.TxForwardingNodeIDs,
+		forwardedTxs:         make(map[uint32]forwardedTx),
 	}
 
 	return n
 }
 
 func (n *network) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
-	// This VM currently only supports gossiping of txs, so there are no
-	// requests.
+	forwarded, ok := n.forwardedTxs[requestID]
+	if !ok {
+		return nil
+	}
+	delete(n.forwardedTxs, requestID)
+
+	if forwarded.attempt >= maxForwardAttempts {
+		n.log.Debug(
+			"failed to forward tx %s to %s after %d attempts",
+			forwarded.txID,
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+			forwarded.attempt,
+		)
+		return nil
+	}
+
+	n.log.Debug(
+		"retrying to forward tx %s to %s",
+		forwarded.txID,
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+	)
+	forwarded.attempt++
+	n.sendForwardedTx(forwarded)
 	return nil
 }
 
 func (n *network) AppRequest(nodeID ids.ShortID, requestID uint32, deadline time.Time, msgBytes []byte) error {
-	// This VM currently only supports gossiping of txs, so there are no
-	// requests.
-	return nil
+	n.log.Debug(
+		"AppRequest message handler called from %s with %d bytes",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		len(msgBytes),
+	)
+
+	if time.Now().Before(n.gossipActivationTime) {
+		n.log.Debug("AppRequest message called before activation time")
+		return nil
+	}
+
+	msgIntf, err := message.Parse(msgBytes)
+	if err != nil {
+		n.log.Debug("dropping AppRequest message due to failing to parse message")
+		return nil
+	}
+
+	// The only supported request is a tx forwarded by an RPC node.
+	msg, ok := msgIntf.(*message.Tx)
+	if !ok {
+		n.log.Debug(
+			"dropping unexpected request from %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+		)
+		return nil
+	}
+
+	tx, err := n.parseTx(msg.Tx)
+	if err != nil {
+		n.log.Verbo("AppRequest provided invalid tx: %s", err)
+		return nil
+	}
+
+	txID := tx.ID()
+	added := false
+	if !n.mempool.WasDropped(txID) {
+		if err := n.mempool.AddUnverifiedTx(tx); err != nil {
+			n.log.Debug(
+				"AppRequest failed AddUnverifiedTx from %s with: %s",
+				nodeID.PrefixedString(constants.NodeIDPrefix),
+				err,
+			)
+		} else {
+			added = true
+		}
+	}
+
+	ack := &message.TxAck{
+		TxID:  txID,
+		Added: added,
+	}
+	ackBytes, err := message.Build(ack)
+	if err != nil {
+		return fmt.Errorf("AppRequest: failed to build TxAck message with: %w", err)
+	}
+	return n.appSender.SendAppResponse(nodeID, requestID, ackBytes)
 }
 
 func (n *network) AppResponse(nodeID ids.ShortID, requestID uint32, msgBytes []byte) error {
-	// This VM currently only supports gossiping of txs, so there are no
-	// requests.
+	forwarded, ok := n.forwardedTxs[requestID]
+	if !ok {
+		n.log.Debug(
+			"dropping AppResponse from %s with unknown requestID %d",
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+			requestID,
+		)
+		return nil
+	}
+	delete(n.forwardedTxs, requestID)
+
+	msgIntf, err := message.Parse(msgBytes)
+	if err != nil {
+		n.log.Debug("dropping AppResponse message due to failing to parse message")
+		return nil
+	}
+
+	msg, ok := msgIntf.(*message.TxAck)
+	if !ok || msg.TxID != forwarded.txID {
+		n.log.Debug(
+			"dropping unexpected response from %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+		)
+		return nil
+	}
+
+	n.log.Debug(
+		"forwarded tx %s was acknowledged by %s with added=%v",
+		forwarded.txID,
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		msg.Added,
+	)
 	return nil
 }
 
@@ -89,17 +212,11 @@ func (n *network) AppGossip(nodeID ids.ShortID, msgBytes []byte) error {
 		return nil
 	}
 
-	tx := &Tx{}
-	if _, err := Codec.Unmarshal(msg.Tx, tx); err != nil {
-		n.log.Verbo("AppGossip provided invalid tx: %s", err)
-		return nil
-	}
-	unsignedBytes, err := Codec.Marshal(CodecVersion, &tx.UnsignedTx)
+	tx, err := n.parseTx(msg.Tx)
 	if err != nil {
-		n.log.Warn("AppGossip failed to marshal unsigned tx: %s", err)
+		n.log.Verbo("AppGossip provided invalid tx: %s", err)
 		return nil
 	}
-	tx.Initialize(unsignedBytes, msg.Tx)
 
 	txID := tx.ID()
 	if n.mempool.WasDropped(txID) {
@@ -137,3 +254,71 @@ func (n *network) GossipTx(tx *Tx) error {
 	}
 	return n.appSender.SendAppGossip(msgBytes)
 }
+
+// ForwardTx sends [tx] directly to each of the configured tx forwarding nodes.
+// This allows a node that isn't a validator to get its txs to validators
+// without relying on gossip. A request that fails is retried up to
+// [maxForwardAttempts] times. It's a no-op if no forwarding nodes are
+// configured or if this node is a validator, since validators issue their
+// txs themselves. Failures are logged rather than returned, since the tx is
+// gossiped regardless.
+func (n *network) ForwardTx(tx *Tx) {
+	if n.forwardingNodeIDs.Len() == 0 || time.Now().Before(n.gossipActivationTime) {
+		return
+	}
+	if vdrs, ok := n.vm.Validators.GetValidators(constants.PrimaryNetworkID); ok && vdrs.Contains(n.vm.ctx.NodeID) {
+		return
+	}
+
+	txID := tx.ID()
+	n.log.Debug("forwarding tx %s to %s", txID, n.forwardingNodeIDs)
+
+	msg := &message.Tx{
+		Tx: tx.Bytes(),
+	}
+	msgBytes, err := message.Build(msg)
+	if err != nil {
+		n.log.Warn("failed to build Tx message to forward tx %s: %s", txID, err)
+		return
+	}
+
+	for nodeID := range n.forwardingNodeIDs {
+		n.sendForwardedTx(forwardedTx{
+			txID:    txID,
+			msg:     msgBytes,
+			nodeID:  nodeID,
+			attempt: 1,
+		})
+	}
+}
+
+// sendForwardedTx sends [forwarded] to its node with a new request ID
+func (n *network) sendForwardedTx(forwarded forwardedTx) {
+	n.requestID++
+	n.forwardedTxs[n.requestID] = forwarded
+
+	nodeIDs := ids.ShortSet{}
+	nodeIDs.Add(forwarded.nodeID)
+	if err := n.appSender.SendAppRequest(nodeIDs, n.requestID, forwarded.msg); err != nil {
+		delete(n.forwardedTxs, n.requestID)
+		n.log.Debug(
+			"failed to forward tx %s to %s: %s",
+			forwarded.txID,
+			forwarded.nodeID.PrefixedString(constants.NodeIDPrefix),
+			err,
+		)
+	}
+}
+
+func (n *network) parseTx(txBytes []byte) (*Tx, error) {
+	tx := &Tx{}
+	if _, err := Codec.Unmarshal(txBytes, tx); err != nil {
+		return nil, err
+	}
+	unsignedBytes, err := Codec.Marshal(CodecVersion, &tx.UnsignedTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal unsigned tx: %w", err)
+	}
+	tx.Initialize(unsignedBytes, txBytes)
+	return tx, nil
+}
@@ -174,3 +174,172 @@ func TestMempoolNewLocaTxIsGossiped(t *testing.T) {
 
 	assert.True(gossipedBytes == nil)
 }
+
+// show that a tx is forwarded to the configured nodes and that the
+// acknowledgment completes the request
+func TestForwardTx(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, sender := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+	nodeID := ids.GenerateTestShortID()
+	vm.forwardingNodeIDs.Add(nodeID)
+
+	var (
+		requestedNodeIDs ids.ShortSet
+		requestID        uint32
+		requestBytes     []byte
+	)
+	sender.SendAppRequestF = func(nodeIDs ids.ShortSet, reqID uint32, b []byte) error {
+		requestedNodeIDs = nodeIDs
+		requestID = reqID
+		requestBytes = b
+		return nil
+	}
+
+	tx := getValidTx(vm, t)
+	txID := tx.ID()
+	vm.ForwardTx(tx)
+	assert.Equal(1, requestedNodeIDs.Len())
+	assert.True(requestedNodeIDs.Contains(nodeID))
+
+	requestIntf, err := message.Parse(requestBytes)
+	assert.NoError(err)
+	request, ok := requestIntf.(*message.Tx)
+	assert.True(ok)
+	assert.Equal(tx.Bytes(), request.Tx)
+	assert.Contains(vm.forwardedTxs, requestID)
+
+	ack := message.TxAck{
+		TxID:  txID,
+		Added: true,
+	}
+	ackBytes, err := message.Build(&ack)
+	assert.NoError(err)
+
+	err = vm.AppResponse(nodeID, requestID, ackBytes)
+	assert.NoError(err)
+	assert.NotContains(vm.forwardedTxs, requestID)
+}
+
+// show that a tx is forwarded to each node with its own request ID and that
+// failed requests are retried a limited number of times
+func TestForwardTxRetriesFailedRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, sender := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	vm.forwardingNodeIDs.Add(nodeID0, nodeID1)
+
+	// Node ID --> Request IDs sent to the node
+	requests := make(map[ids.ShortID][]uint32)
+	sender.SendAppRequestF = func(nodeIDs ids.ShortSet, reqID uint32, b []byte) error {
+		assert.Equal(1, nodeIDs.Len())
+		nodeID := nodeIDs.List()[0]
+		requests[nodeID] = append(requests[nodeID], reqID)
+		return nil
+	}
+
+	tx := getValidTx(vm, t)
+	vm.ForwardTx(tx)
+	assert.Len(requests[nodeID0], 1)
+	assert.Len(requests[nodeID1], 1)
+	assert.NotEqual(requests[nodeID0][0], requests[nodeID1][0])
+
+	// Fail the requests to [nodeID0] until it's given up on
+	for i := 0; i < maxForwardAttempts; i++ {
+		reqIDs := requests[nodeID0]
+		assert.Len(reqIDs, i+1)
+		err := vm.AppRequestFailed(nodeID0, reqIDs[len(reqIDs)-1])
+		assert.NoError(err)
+	}
+	assert.Len(requests[nodeID0], maxForwardAttempts)
+
+	// Only the request to [nodeID1] is still outstanding
+	assert.Len(vm.forwardedTxs, 1)
+	assert.Contains(vm.forwardedTxs, requests[nodeID1][0])
+}
+
+// show that a validator doesn't forward its txs
+func TestForwardTxSkippedByValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, sender := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+	vm.ctx.NodeID = keys[0].PublicKey().Address()
+	vm.forwardingNodeIDs.Add(ids.GenerateTestShortID())
+
+	sender.SendAppRequestF = func(ids.ShortSet, uint32, []byte) error {
+		t.Fatal("a validator shouldn't forward txs")
+		return nil
+	}
+
+	vm.ForwardTx(getValidTx(vm, t))
+	assert.Empty(vm.forwardedTxs)
+}
+
+// show that a forwarded tx is added to the mempool and acknowledged
+func TestForwardedTxIsAddedAndAcked(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, sender := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+	nodeID := ids.GenerateTestShortID()
+
+	var ackBytes []byte
+	sender.SendAppResponseF = func(respNodeID ids.ShortID, _ uint32, b []byte) error {
+		assert.Equal(nodeID, respNodeID)
+		ackBytes = b
+		return nil
+	}
+
+	tx := getValidTx(vm, t)
+	txID := tx.ID()
+
+	msg := message.Tx{
+		Tx: tx.Bytes(),
+	}
+	msgBytes, err := message.Build(&msg)
+	assert.NoError(err)
+
+	err = vm.AppRequest(nodeID, 1, time.Now().Add(time.Second), msgBytes)
+	assert.NoError(err)
+	assert.True(vm.mempool.Has(txID))
+
+	ackIntf, err := message.Parse(ackBytes)
+	assert.NoError(err)
+	ack, ok := ackIntf.(*message.TxAck)
+	assert.True(ok)
+	assert.Equal(txID, ack.TxID)
+	assert.True(ack.Added)
+}
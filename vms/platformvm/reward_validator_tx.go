@@ -127,7 +127,7 @@ func (tx *UnsignedRewardValidatorTx) Execute(
 
 	// If the reward is aborted, then the current supply should be decreased.
 	currentSupply := onAbortState.GetCurrentSupply()
-	newSupply, err := math.Sub64(currentSupply, stakerReward)
+	newSupply, err := math.Sub(currentSupply, stakerReward)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -212,7 +212,7 @@ func (tx *UnsignedRewardValidatorTx) Execute(
 		delegatorShares := reward.PercentDenominator - uint64(vdrTx.Shares)             // parentTx.Shares <= reward.PercentDenominator so no underflow
 		delegatorReward := delegatorShares * (stakerReward / reward.PercentDenominator) // delegatorShares <= reward.PercentDenominator so no overflow
 		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := math.Mul64(delegatorShares, stakerReward); err == nil {
+		if optimisticReward, err := math.Mul(delegatorShares, stakerReward); err == nil {
 			delegatorReward = optimisticReward / reward.PercentDenominator
 		}
 		delegateeReward := stakerReward - delegatorReward // delegatorReward <= reward so no underflow
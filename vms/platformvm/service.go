@@ -4,9 +4,12 @@
 package platformvm
 
 import (
+	"bytes"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -52,6 +55,7 @@ var (
 	errNoKeys                     = errors.New("user has no keys or funds")
 	errNoPrimaryValidators        = errors.New("no default subnet validators")
 	errCorruptedReason            = errors.New("tx validity corrupted")
+	errBlockNotIndexed            = errors.New("blocks weren't indexed at the requested height")
 	errStartTimeTooSoon           = fmt.Errorf("start time must be at least %s in the future", minAddStakerDelay)
 	errStartTimeTooLate           = errors.New("start time is too far in the future")
 	errTotalOverflow              = errors.New("overflow while calculating total balance")
@@ -65,6 +69,7 @@ var (
 	errMissingName                = errors.New("argument 'name' not given")
 	errMissingVMID                = errors.New("argument 'vmID' not given")
 	errMissingBlockchainID        = errors.New("argument 'blockchainID' not given")
+	errEmptyUptimeWindow          = errors.New("argument 'startTime' must be before 'endTime'")
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -378,7 +383,7 @@ type GetUTXOsResponse struct {
 }
 
 // GetUTXOs returns the UTXOs controlled by the given addresses
-func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, response *GetUTXOsResponse) error {
+func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, response *GetUTXOsResponse) error {
 	service.vm.ctx.Log.Debug("Platform: GetUTXOs called")
 
 	if len(args.Addresses) == 0 {
@@ -434,6 +439,7 @@ func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, response *
 	}
 	if sourceChain == service.vm.ctx.ChainID {
 		utxos, endAddr, endUTXOID, err = avax.GetPaginatedUTXOs(
+			r.Context(),
 			service.vm.internalState,
 			addrSet,
 			startAddr,
@@ -643,12 +649,26 @@ type GetCurrentValidatorsArgs struct {
 	// some nodeIDs are not currently validators, they
 	// will be omitted from the response.
 	NodeIDs []string `json:"nodeIDs"`
+	// If non-zero, only the stakers that stake at least [MinStake] are
+	// returned. The stake of a validator doesn't include its delegations.
+	MinStake json.Uint64 `json:"minStake"`
+	// If non-zero, only the stakers whose staking period ends before the unix
+	// timestamp [EndTimeBefore] are returned.
+	EndTimeBefore json.Uint64 `json:"endTimeBefore"`
+	// If [Limit] is non-zero, only the validators whose node ID is >=
+	// [StartNodeID] are considered, in order of node ID, and at most [Limit]
+	// of them are returned.
+	StartNodeID string      `json:"startNodeID"`
+	Limit       json.Uint32 `json:"limit"`
 }
 
 // GetCurrentValidatorsReply are the results from calling GetCurrentValidators.
 // Each validator contains a list of delegators to itself.
 type GetCurrentValidatorsReply struct {
 	Validators []interface{} `json:"validators"`
+	// If non-empty, there are more validators to return. They can be fetched
+	// by passing this as [StartNodeID].
+	NextNodeID string `json:"nextNodeID,omitempty"`
 }
 
 // GetCurrentValidators returns current validators and delegators
@@ -660,19 +680,17 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	// Validator's node ID as string --> Delegators to them
 	vdrToDelegators := map[string][]APIPrimaryDelegator{}
 
-	// Create set of nodeIDs
-	nodeIDs := ids.ShortSet{}
-	for _, nodeID := range args.NodeIDs {
-		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
-		if err != nil {
-			return err
-		}
-		nodeIDs.Add(nID)
+	filter, err := newStakerFilter(args.NodeIDs, args.MinStake, args.EndTimeBefore)
+	if err != nil {
+		return err
 	}
-	includeAllNodes := nodeIDs.Len() == 0
 
 	currentValidators := service.vm.internalState.CurrentStakerChainState()
 
+	if args.Limit > 0 {
+		return service.getCurrentValidatorsPage(currentValidators, filter, args, reply)
+	}
+
 	for _, tx := range currentValidators.Stakers() { // Iterates in order of increasing stop time
 		_, rewardAmount, err := currentValidators.GetStaker(tx.ID())
 		if err != nil {
@@ -683,108 +701,37 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 			if args.SubnetID != constants.PrimaryNetworkID {
 				continue
 			}
-			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
+			if !filter.matches(&staker.Validator) {
 				continue
 			}
 
-			weight := json.Uint64(staker.Validator.Weight())
-
-			var rewardOwner *APIOwner
-			owner, ok := staker.RewardsOwner.(*secp256k1fx.OutputOwners)
-			if ok {
-				rewardOwner = &APIOwner{
-					Locktime:  json.Uint64(owner.Locktime),
-					Threshold: json.Uint32(owner.Threshold),
-				}
-				for _, addr := range owner.Addrs {
-					addrStr, err := service.vm.FormatLocalAddress(addr)
-					if err != nil {
-						return err
-					}
-					rewardOwner.Addresses = append(rewardOwner.Addresses, addrStr)
-				}
-			}
-
-			potentialReward := json.Uint64(rewardAmount)
-			delegator := APIPrimaryDelegator{
-				APIStaker: APIStaker{
-					TxID:        tx.ID(),
-					StartTime:   json.Uint64(staker.StartTime().Unix()),
-					EndTime:     json.Uint64(staker.EndTime().Unix()),
-					StakeAmount: &weight,
-					NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
-				},
-				RewardOwner:     rewardOwner,
-				PotentialReward: &potentialReward,
+			delegator, err := service.apiPrimaryDelegator(tx.ID(), staker, rewardAmount)
+			if err != nil {
+				return err
 			}
 			vdrToDelegators[delegator.NodeID] = append(vdrToDelegators[delegator.NodeID], delegator)
 		case *UnsignedAddValidatorTx:
 			if args.SubnetID != constants.PrimaryNetworkID {
 				continue
 			}
-			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
+			if !filter.matches(&staker.Validator) {
 				continue
 			}
 
-			nodeID := staker.Validator.ID()
-			startTime := staker.StartTime()
-			weight := json.Uint64(staker.Validator.Weight())
-			potentialReward := json.Uint64(rewardAmount)
-			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(reward.PercentDenominator))
-			rawUptime, err := service.vm.uptimeManager.CalculateUptimePercentFrom(nodeID, startTime)
+			vdr, err := service.apiPrimaryValidator(tx.ID(), staker, rewardAmount)
 			if err != nil {
 				return err
 			}
-			uptime := json.Float32(rawUptime)
-
-			connected := service.vm.uptimeManager.IsConnected(nodeID)
-
-			var rewardOwner *APIOwner
-			owner, ok := staker.RewardsOwner.(*secp256k1fx.OutputOwners)
-			if ok {
-				rewardOwner = &APIOwner{
-					Locktime:  json.Uint64(owner.Locktime),
-					Threshold: json.Uint32(owner.Threshold),
-				}
-				for _, addr := range owner.Addrs {
-					addrStr, err := service.vm.FormatLocalAddress(addr)
-					if err != nil {
-						return err
-					}
-					rewardOwner.Addresses = append(rewardOwner.Addresses, addrStr)
-				}
-			}
-
-			reply.Validators = append(reply.Validators, APIPrimaryValidator{
-				APIStaker: APIStaker{
-					TxID:        tx.ID(),
-					NodeID:      nodeID.PrefixedString(constants.NodeIDPrefix),
-					StartTime:   json.Uint64(startTime.Unix()),
-					EndTime:     json.Uint64(staker.EndTime().Unix()),
-					StakeAmount: &weight,
-				},
-				Uptime:          &uptime,
-				Connected:       &connected,
-				PotentialReward: &potentialReward,
-				RewardOwner:     rewardOwner,
-				DelegationFee:   delegationFee,
-			})
+			reply.Validators = append(reply.Validators, vdr)
 		case *UnsignedAddSubnetValidatorTx:
 			if args.SubnetID != staker.Validator.Subnet {
 				continue
 			}
-			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
+			if !filter.matches(&staker.Validator.Validator) {
 				continue
 			}
 
-			weight := json.Uint64(staker.Validator.Weight())
-			reply.Validators = append(reply.Validators, APIStaker{
-				TxID:      tx.ID(),
-				NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
-				StartTime: json.Uint64(staker.StartTime().Unix()),
-				EndTime:   json.Uint64(staker.EndTime().Unix()),
-				Weight:    &weight,
-			})
+			reply.Validators = append(reply.Validators, apiSubnetValidator(tx.ID(), staker))
 		default:
 			return fmt.Errorf("expected validator but got %T", tx.UnsignedTx)
 		}
@@ -804,6 +751,243 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	return nil
 }
 
+// getCurrentValidatorsPage populates [reply] with at most [args.Limit] current
+// validators of [args.SubnetID] that match [filter], in order of node ID,
+// starting at [args.StartNodeID]. The delegators of a primary network
+// validator are only reported if they match [filter] too.
+func (service *Service) getCurrentValidatorsPage(
+	currentValidators currentStakerChainState,
+	filter *stakerFilter,
+	args *GetCurrentValidatorsArgs,
+	reply *GetCurrentValidatorsReply,
+) error {
+	start, err := parsePageStart(args.StartNodeID)
+	if err != nil {
+		return err
+	}
+
+	// The validator sets of the primary network and of the whitelisted
+	// subnets are kept up to date by the VM, along with their node ID index.
+	// The validator sets of other subnets are built on demand.
+	vdrs, ok := service.vm.Validators.GetValidators(args.SubnetID)
+	if !ok {
+		vdrs, err = currentValidators.ValidatorSet(args.SubnetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, vdrIntf := range vdrs.Page(start, -1) {
+		nodeID := vdrIntf.ID()
+		vdr, err := currentValidators.GetValidator(nodeID)
+		if err != nil {
+			return fmt.Errorf("couldn't get validator %s: %w", nodeID.PrefixedString(constants.NodeIDPrefix), err)
+		}
+
+		var (
+			subnetStaker *UnsignedAddSubnetValidatorTx
+			staker       *Validator
+		)
+		if args.SubnetID != constants.PrimaryNetworkID {
+			subnetStaker, ok = vdr.SubnetValidators()[args.SubnetID]
+			if !ok {
+				return fmt.Errorf("%s isn't a validator of subnet %s", nodeID.PrefixedString(constants.NodeIDPrefix), args.SubnetID)
+			}
+			staker = &subnetStaker.Validator.Validator
+		} else {
+			staker = &vdr.AddValidatorTx().Validator
+		}
+		if !filter.matches(staker) {
+			continue
+		}
+
+		// The validator after the last one on this page starts the next page
+		if len(reply.Validators) == int(args.Limit) {
+			reply.NextNodeID = nodeID.PrefixedString(constants.NodeIDPrefix)
+			return nil
+		}
+
+		if subnetStaker != nil {
+			reply.Validators = append(reply.Validators, apiSubnetValidator(subnetStaker.ID(), subnetStaker))
+			continue
+		}
+
+		addValidatorTx := vdr.AddValidatorTx()
+		_, rewardAmount, err := currentValidators.GetStaker(addValidatorTx.ID())
+		if err != nil {
+			return err
+		}
+		apiVdr, err := service.apiPrimaryValidator(addValidatorTx.ID(), addValidatorTx, rewardAmount)
+		if err != nil {
+			return err
+		}
+		for _, delegatorTx := range vdr.Delegators() { // Sorted in order of increasing stop time
+			if !filter.matches(&delegatorTx.Validator) {
+				continue
+			}
+			_, rewardAmount, err := currentValidators.GetStaker(delegatorTx.ID())
+			if err != nil {
+				return err
+			}
+			delegator, err := service.apiPrimaryDelegator(delegatorTx.ID(), delegatorTx, rewardAmount)
+			if err != nil {
+				return err
+			}
+			apiVdr.Delegators = append(apiVdr.Delegators, delegator)
+		}
+		reply.Validators = append(reply.Validators, apiVdr)
+	}
+	return nil
+}
+
+// parsePageStart returns the node ID a page of validators starts at, given
+// the cursor the previous page returned. An empty cursor starts at the first
+// validator.
+func parsePageStart(startNodeID string) (ids.ShortID, error) {
+	if startNodeID == "" {
+		return ids.ShortEmpty, nil
+	}
+	start, err := ids.ShortFromPrefixedString(startNodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("couldn't parse startNodeID: %w", err)
+	}
+	return start, nil
+}
+
+// stakerFilter restricts the stakers reported by GetCurrentValidators and
+// GetPendingValidators
+type stakerFilter struct {
+	// If non-empty, only the stakers of these nodes match
+	nodeIDs ids.ShortSet
+	// Stakers that stake less than this don't match
+	minStake uint64
+	// If non-zero, only the stakers that stop staking before this unix
+	// timestamp match
+	endTimeBefore uint64
+}
+
+func newStakerFilter(nodeIDs []string, minStake, endTimeBefore json.Uint64) (*stakerFilter, error) {
+	filter := &stakerFilter{
+		minStake:      uint64(minStake),
+		endTimeBefore: uint64(endTimeBefore),
+	}
+	for _, nodeID := range nodeIDs {
+		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		filter.nodeIDs.Add(nID)
+	}
+	return filter, nil
+}
+
+// matches returns true if the staker described by [vdr] passes the filter
+func (f *stakerFilter) matches(vdr *Validator) bool {
+	switch {
+	case f.nodeIDs.Len() > 0 && !f.nodeIDs.Contains(vdr.NodeID):
+		return false
+	case vdr.Wght < f.minStake:
+		return false
+	default:
+		return f.endTimeBefore == 0 || vdr.End < f.endTimeBefore
+	}
+}
+
+// apiOwner returns the API representation of [owner], or nil if [owner] isn't
+// an *secp256k1fx.OutputOwners
+func (service *Service) apiOwner(owner Owner) (*APIOwner, error) {
+	outputOwners, ok := owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil, nil
+	}
+	apiOwner := &APIOwner{
+		Locktime:  json.Uint64(outputOwners.Locktime),
+		Threshold: json.Uint32(outputOwners.Threshold),
+	}
+	for _, addr := range outputOwners.Addrs {
+		addrStr, err := service.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		apiOwner.Addresses = append(apiOwner.Addresses, addrStr)
+	}
+	return apiOwner, nil
+}
+
+// apiPrimaryValidator returns the API representation of the current validator
+// added by [staker]. The delegators to the validator aren't populated.
+func (service *Service) apiPrimaryValidator(txID ids.ID, staker *UnsignedAddValidatorTx, rewardAmount uint64) (APIPrimaryValidator, error) {
+	nodeID := staker.Validator.ID()
+	startTime := staker.StartTime()
+	weight := json.Uint64(staker.Validator.Weight())
+	potentialReward := json.Uint64(rewardAmount)
+	delegationFee := json.Float32(100 * float32(staker.Shares) / float32(reward.PercentDenominator))
+	rawUptime, err := service.vm.uptimeManager.CalculateUptimePercentFrom(nodeID, startTime)
+	if err != nil {
+		return APIPrimaryValidator{}, err
+	}
+	uptime := json.Float32(rawUptime)
+
+	connected := service.vm.uptimeManager.IsConnected(nodeID)
+
+	rewardOwner, err := service.apiOwner(staker.RewardsOwner)
+	if err != nil {
+		return APIPrimaryValidator{}, err
+	}
+
+	return APIPrimaryValidator{
+		APIStaker: APIStaker{
+			TxID:        txID,
+			NodeID:      nodeID.PrefixedString(constants.NodeIDPrefix),
+			StartTime:   json.Uint64(startTime.Unix()),
+			EndTime:     json.Uint64(staker.EndTime().Unix()),
+			StakeAmount: &weight,
+		},
+		Uptime:          &uptime,
+		Connected:       &connected,
+		PotentialReward: &potentialReward,
+		RewardOwner:     rewardOwner,
+		DelegationFee:   delegationFee,
+	}, nil
+}
+
+// apiPrimaryDelegator returns the API representation of the current delegator
+// added by [staker]
+func (service *Service) apiPrimaryDelegator(txID ids.ID, staker *UnsignedAddDelegatorTx, rewardAmount uint64) (APIPrimaryDelegator, error) {
+	weight := json.Uint64(staker.Validator.Weight())
+
+	rewardOwner, err := service.apiOwner(staker.RewardsOwner)
+	if err != nil {
+		return APIPrimaryDelegator{}, err
+	}
+
+	potentialReward := json.Uint64(rewardAmount)
+	return APIPrimaryDelegator{
+		APIStaker: APIStaker{
+			TxID:        txID,
+			StartTime:   json.Uint64(staker.StartTime().Unix()),
+			EndTime:     json.Uint64(staker.EndTime().Unix()),
+			StakeAmount: &weight,
+			NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+		},
+		RewardOwner:     rewardOwner,
+		PotentialReward: &potentialReward,
+	}, nil
+}
+
+// apiSubnetValidator returns the API representation of the current subnet
+// validator added by [staker]
+func apiSubnetValidator(txID ids.ID, staker *UnsignedAddSubnetValidatorTx) APIStaker {
+	weight := json.Uint64(staker.Validator.Weight())
+	return APIStaker{
+		TxID:      txID,
+		NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+		StartTime: json.Uint64(staker.StartTime().Unix()),
+		EndTime:   json.Uint64(staker.EndTime().Unix()),
+		Weight:    &weight,
+	}
+}
+
 // GetPendingValidatorsArgs are the arguments for calling GetPendingValidators
 type GetPendingValidatorsArgs struct {
 	// Subnet we're getting the pending validators of
@@ -814,6 +998,17 @@ type GetPendingValidatorsArgs struct {
 	// some requested nodeIDs are not pending validators,
 	// they are omitted from the response.
 	NodeIDs []string `json:"nodeIDs"`
+	// If non-zero, only the stakers that stake at least [MinStake] are
+	// returned
+	MinStake json.Uint64 `json:"minStake"`
+	// If non-zero, only the stakers whose staking period ends before the unix
+	// timestamp [EndTimeBefore] are returned.
+	EndTimeBefore json.Uint64 `json:"endTimeBefore"`
+	// If [Limit] is non-zero, only the stakers whose node ID is >=
+	// [StartNodeID] are considered, and only the stakers of the first [Limit]
+	// such node IDs, in order of node ID, are returned.
+	StartNodeID string      `json:"startNodeID"`
+	Limit       json.Uint32 `json:"limit"`
 }
 
 // GetPendingValidatorsReply are the results from calling GetPendingValidators.
@@ -821,6 +1016,9 @@ type GetPendingValidatorsArgs struct {
 type GetPendingValidatorsReply struct {
 	Validators []interface{} `json:"validators"`
 	Delegators []interface{} `json:"delegators"`
+	// If non-empty, there are more stakers to return. They can be fetched by
+	// passing this as [StartNodeID].
+	NextNodeID string `json:"nextNodeID,omitempty"`
 }
 
 // GetPendingValidators returns the list of pending validators
@@ -830,79 +1028,91 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 	reply.Validators = []interface{}{}
 	reply.Delegators = []interface{}{}
 
-	// Create set of nodeIDs
-	nodeIDs := ids.ShortSet{}
-	for _, nodeID := range args.NodeIDs {
-		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
-		if err != nil {
-			return err
-		}
-		nodeIDs.Add(nID)
+	filter, err := newStakerFilter(args.NodeIDs, args.MinStake, args.EndTimeBefore)
+	if err != nil {
+		return err
 	}
-	includeAllNodes := nodeIDs.Len() == 0
 
 	pendingValidators := service.vm.internalState.PendingStakerChainState()
+	stakers := pendingValidators.Stakers() // Sorted in order of increasing start time
 
-	for _, tx := range pendingValidators.Stakers() { // Iterates in order of increasing start time
+	// matches returns the staking details of [tx] if it is reported
+	matches := func(tx *Tx) (*Validator, bool, error) {
 		switch staker := tx.UnsignedTx.(type) {
 		case *UnsignedAddDelegatorTx:
-			if args.SubnetID != constants.PrimaryNetworkID {
-				continue
-			}
-			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
-				continue
-			}
-
-			weight := json.Uint64(staker.Validator.Weight())
-			reply.Delegators = append(reply.Delegators, APIStaker{
-				TxID:        tx.ID(),
-				NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
-				StartTime:   json.Uint64(staker.StartTime().Unix()),
-				EndTime:     json.Uint64(staker.EndTime().Unix()),
-				StakeAmount: &weight,
-			})
+			vdr := &staker.Validator
+			return vdr, args.SubnetID == constants.PrimaryNetworkID && filter.matches(vdr), nil
 		case *UnsignedAddValidatorTx:
-			if args.SubnetID != constants.PrimaryNetworkID {
-				continue
+			vdr := &staker.Validator
+			return vdr, args.SubnetID == constants.PrimaryNetworkID && filter.matches(vdr), nil
+		case *UnsignedAddSubnetValidatorTx:
+			vdr := &staker.Validator.Validator
+			return vdr, args.SubnetID == staker.Validator.Subnet && filter.matches(vdr), nil
+		default:
+			return nil, false, fmt.Errorf("expected validator but got %T", tx.UnsignedTx)
+		}
+	}
+
+	// Pending stakers aren't indexed by node ID, so the page is found by
+	// sorting the node IDs of every matching staker
+	var page ids.ShortSet
+	if args.Limit > 0 {
+		start, err := parsePageStart(args.StartNodeID)
+		if err != nil {
+			return err
+		}
+
+		nodeIDSet := ids.ShortSet{}
+		for _, tx := range stakers {
+			vdr, ok, err := matches(tx)
+			if err != nil {
+				return err
 			}
-			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
-				continue
+			if ok && bytes.Compare(vdr.NodeID[:], start[:]) >= 0 {
+				nodeIDSet.Add(vdr.NodeID)
 			}
+		}
+		nodeIDs := nodeIDSet.List()
+		ids.SortShortIDs(nodeIDs)
+		if len(nodeIDs) > int(args.Limit) {
+			reply.NextNodeID = nodeIDs[args.Limit].PrefixedString(constants.NodeIDPrefix)
+			nodeIDs = nodeIDs[:args.Limit]
+		}
+		page.Add(nodeIDs...)
+	}
 
-			nodeID := staker.Validator.ID()
-			weight := json.Uint64(staker.Validator.Weight())
-			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(reward.PercentDenominator))
+	for _, tx := range stakers {
+		vdr, ok, err := matches(tx)
+		if err != nil {
+			return err
+		}
+		if !ok || (args.Limit > 0 && !page.Contains(vdr.NodeID)) {
+			continue
+		}
 
-			connected := service.vm.uptimeManager.IsConnected(nodeID)
+		weight := json.Uint64(vdr.Weight())
+		apiStaker := APIStaker{
+			TxID:      tx.ID(),
+			NodeID:    vdr.ID().PrefixedString(constants.NodeIDPrefix),
+			StartTime: json.Uint64(vdr.StartTime().Unix()),
+			EndTime:   json.Uint64(vdr.EndTime().Unix()),
+		}
+		switch staker := tx.UnsignedTx.(type) {
+		case *UnsignedAddDelegatorTx:
+			apiStaker.StakeAmount = &weight
+			reply.Delegators = append(reply.Delegators, apiStaker)
+		case *UnsignedAddValidatorTx:
+			apiStaker.StakeAmount = &weight
+			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(reward.PercentDenominator))
+			connected := service.vm.uptimeManager.IsConnected(vdr.ID())
 			reply.Validators = append(reply.Validators, APIPrimaryValidator{
-				APIStaker: APIStaker{
-					TxID:        tx.ID(),
-					NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
-					StartTime:   json.Uint64(staker.StartTime().Unix()),
-					EndTime:     json.Uint64(staker.EndTime().Unix()),
-					StakeAmount: &weight,
-				},
+				APIStaker:     apiStaker,
 				DelegationFee: delegationFee,
 				Connected:     &connected,
 			})
 		case *UnsignedAddSubnetValidatorTx:
-			if args.SubnetID != staker.Validator.Subnet {
-				continue
-			}
-			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
-				continue
-			}
-
-			weight := json.Uint64(staker.Validator.Weight())
-			reply.Validators = append(reply.Validators, APIStaker{
-				TxID:      tx.ID(),
-				NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
-				StartTime: json.Uint64(staker.StartTime().Unix()),
-				EndTime:   json.Uint64(staker.EndTime().Unix()),
-				Weight:    &weight,
-			})
-		default:
-			return fmt.Errorf("expected validator but got %T", tx.UnsignedTx)
+			apiStaker.Weight = &weight
+			reply.Validators = append(reply.Validators, apiStaker)
 		}
 	}
 	return nil
@@ -1077,7 +1287,7 @@ func (service *Service) AddValidator(_ *http.Request, args *AddValidatorArgs, re
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1183,7 +1393,7 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1286,7 +1496,7 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1358,7 +1568,7 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1440,7 +1650,7 @@ func (service *Service) ExportAVAX(_ *http.Request, args *ExportAVAXArgs, respon
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1516,7 +1726,7 @@ func (service *Service) ImportAVAX(_ *http.Request, args *ImportAVAXArgs, respon
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1637,7 +1847,7 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 	errs := wrappers.Errs{}
 	errs.Add(
 		err,
-		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		service.issueTx(tx),
 		user.Close(),
 	)
 	return errs.Err
@@ -1914,7 +2124,7 @@ func (service *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response
 	if _, err := Codec.Unmarshal(txBytes, tx); err != nil {
 		return fmt.Errorf("couldn't parse tx: %w", err)
 	}
-	if err := service.vm.blockBuilder.AddUnverifiedTx(tx); err != nil {
+	if err := service.issueTx(tx); err != nil {
 		return fmt.Errorf("couldn't issue tx: %w", err)
 	}
 
@@ -1922,6 +2132,16 @@ func (service *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response
 	return nil
 }
 
+// issueTx adds [tx] to the mempool and forwards it to the configured tx
+// forwarding nodes
+func (service *Service) issueTx(tx *Tx) error {
+	if err := service.vm.blockBuilder.AddUnverifiedTx(tx); err != nil {
+		return err
+	}
+	service.vm.ForwardTx(tx)
+	return nil
+}
+
 // GetTx gets a tx
 func (service *Service) GetTx(_ *http.Request, args *api.GetTxArgs, response *api.FormattedTx) error {
 	service.vm.ctx.Log.Debug("Platform: GetTx called")
@@ -1966,6 +2186,25 @@ type GetTxStatusResponse struct {
 	// Reason this tx was dropped.
 	// Only non-empty if Status is dropped
 	Reason string `json:"reason,omitempty"`
+	// Accepted block that included this tx.
+	// Only non-nil if the tx was decided in an indexed block
+	Block *APIBlockIndex `json:"block,omitempty"`
+}
+
+// APIBlockIndex is the API representation of where an accepted block is
+type APIBlockIndex struct {
+	BlockID ids.ID      `json:"blockID"`
+	Height  json.Uint64 `json:"height"`
+	// Unix time of the chain once the block was accepted
+	Timestamp json.Uint64 `json:"timestamp"`
+}
+
+func newAPIBlockIndex(index *BlockIndex) *APIBlockIndex {
+	return &APIBlockIndex{
+		BlockID:   index.BlkID,
+		Height:    json.Uint64(index.Height),
+		Timestamp: json.Uint64(index.Timestamp),
+	}
 }
 
 // GetTxStatus gets a tx's status
@@ -1975,6 +2214,17 @@ func (service *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, resp
 	_, txStatus, err := service.vm.internalState.GetTx(args.TxID)
 	if err == nil { // Found the status. Report it.
 		response.Status = txStatus
+
+		// Txs in the genesis, or accepted before the index existed, aren't
+		// indexed
+		index, err := service.vm.internalState.GetTxIndex(args.TxID)
+		switch err {
+		case nil:
+			response.Block = newAPIBlockIndex(index)
+		case database.ErrNotFound:
+		default:
+			return err
+		}
 		return nil
 	}
 	if err != database.ErrNotFound {
@@ -2247,6 +2497,119 @@ func (service *Service) GetMaxStakeAmount(_ *http.Request, args *GetMaxStakeAmou
 	return err
 }
 
+const (
+	expectedRewardCurrent  = "current"
+	expectedRewardPending  = "pending"
+	expectedRewardRealized = "realized"
+)
+
+// GetExpectedRewardArgs are the arguments for calling GetExpectedReward.
+//
+// If [TxID] is set, the reward of that staker is returned. Otherwise, the
+// reward of a hypothetical staker of [Amount] nAVAX for [Duration] seconds,
+// starting at [StartTime], is returned.
+type GetExpectedRewardArgs struct {
+	TxID     ids.ID      `json:"txID"`
+	Amount   json.Uint64 `json:"amount"`
+	Duration json.Uint64 `json:"duration"`
+	// Unix time. Defaults to the current chain time.
+	StartTime json.Uint64 `json:"startTime"`
+}
+
+// GetExpectedRewardReply is the response from calling GetExpectedReward.
+type GetExpectedRewardReply struct {
+	Reward json.Uint64 `json:"reward"`
+	// "current", "pending" or "realized" if [TxID] was provided
+	StakerStatus string `json:"stakerStatus,omitempty"`
+}
+
+// GetExpectedReward returns the staking reward of a hypothetical staker, or
+// of an existing one.
+//
+// The reward of a staker that hasn't started yet depends on the supply when it
+// starts, which is projected from the current supply and the rewards of the
+// pending stakers that start before it. The reward of a staker that has
+// already been removed is the total value of its reward UTXOs, which, for a
+// delegator, includes the delegation fee paid to its validator.
+func (service *Service) GetExpectedReward(_ *http.Request, args *GetExpectedRewardArgs, reply *GetExpectedRewardReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetExpectedReward called")
+
+	if args.TxID == ids.Empty {
+		duration := time.Duration(args.Duration) * time.Second
+		switch {
+		case args.Amount == 0:
+			return errNoAmount
+		case duration < service.vm.MinStakeDuration:
+			return fmt.Errorf("duration must be at least %s", service.vm.MinStakeDuration)
+		case duration > service.vm.MaxStakeDuration:
+			return fmt.Errorf("duration must be at most %s", service.vm.MaxStakeDuration)
+		}
+
+		startTime := service.vm.internalState.GetTimestamp()
+		if args.StartTime != 0 {
+			startTime = time.Unix(int64(args.StartTime), 0)
+		}
+		supply, err := service.vm.projectedSupply(startTime, ids.Empty)
+		if err != nil {
+			return err
+		}
+		reply.Reward = json.Uint64(service.vm.rewards.Calculate(duration, uint64(args.Amount), supply))
+		return nil
+	}
+
+	if _, potentialReward, err := service.vm.internalState.CurrentStakerChainState().GetStaker(args.TxID); err == nil {
+		reply.Reward = json.Uint64(potentialReward)
+		reply.StakerStatus = expectedRewardCurrent
+		return nil
+	} else if err != database.ErrNotFound {
+		return err
+	}
+
+	for _, tx := range service.vm.internalState.PendingStakerChainState().Stakers() {
+		if tx.ID() != args.TxID {
+			continue
+		}
+		var vdr *Validator
+		switch staker := tx.UnsignedTx.(type) {
+		case *UnsignedAddValidatorTx:
+			vdr = &staker.Validator
+		case *UnsignedAddDelegatorTx:
+			vdr = &staker.Validator
+		default:
+			return fmt.Errorf("%s doesn't earn a staking reward", args.TxID)
+		}
+		supply, err := service.vm.projectedSupply(vdr.StartTime(), args.TxID)
+		if err != nil {
+			return err
+		}
+		reply.Reward = json.Uint64(service.vm.rewards.Calculate(vdr.Duration(), vdr.Wght, supply))
+		reply.StakerStatus = expectedRewardPending
+		return nil
+	}
+
+	if _, _, err := service.vm.internalState.GetTx(args.TxID); err != nil {
+		return fmt.Errorf("couldn't get tx %s: %w", args.TxID, err)
+	}
+	utxos, err := service.vm.internalState.GetRewardUTXOs(args.TxID)
+	if err != nil {
+		return fmt.Errorf("couldn't get reward UTXOs: %w", err)
+	}
+	reward := uint64(0)
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(avax.Amounter)
+		if !ok {
+			continue
+		}
+		reward, err = math.Add64(reward, out.Amount())
+		if err != nil {
+			return err
+		}
+	}
+	reply.Reward = json.Uint64(reward)
+	reply.StakerStatus = expectedRewardRealized
+	return nil
+}
+
 // GetRewardUTXOsReply defines the GetRewardUTXOs replies returned from the API
 type GetRewardUTXOsReply struct {
 	// Number of UTXOs returned
@@ -2285,6 +2648,49 @@ func (service *Service) GetRewardUTXOs(_ *http.Request, args *api.GetTxArgs, rep
 	return nil
 }
 
+// GetBlockByHeightArgs are the arguments for calling GetBlockByHeight
+type GetBlockByHeightArgs struct {
+	Height   json.Uint64         `json:"height"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetBlockByHeightReply is the response from calling GetBlockByHeight
+type GetBlockByHeightReply struct {
+	APIBlockIndex
+	Block    string              `json:"block"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetBlockByHeight returns the accepted block at the specified height.
+func (service *Service) GetBlockByHeight(_ *http.Request, args *GetBlockByHeightArgs, reply *GetBlockByHeightReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetBlockByHeight called with Height %d", args.Height)
+
+	index, err := service.vm.internalState.GetBlockIndex(uint64(args.Height))
+	if err == database.ErrNotFound {
+		if startHeight, ok := service.vm.internalState.GetIndexStartHeight(); ok && startHeight > uint64(args.Height) {
+			return errBlockNotIndexed
+		}
+		return fmt.Errorf("no block has been accepted at height %d", args.Height)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get block index: %w", err)
+	}
+
+	blk, err := service.vm.internalState.GetBlock(index.BlkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", index.BlkID, err)
+	}
+	blkStr, err := formatting.EncodeWithChecksum(args.Encoding, blk.Bytes())
+	if err != nil {
+		return fmt.Errorf("couldn't encode block %s as string: %w", index.BlkID, err)
+	}
+
+	reply.APIBlockIndex = *newAPIBlockIndex(index)
+	reply.Block = blkStr
+	reply.Encoding = args.Encoding
+	return nil
+}
+
 // GetTimestampReply is the response from GetTimestamp
 type GetTimestampReply struct {
 	// Current timestamp
@@ -2330,3 +2736,236 @@ func (service *Service) GetValidatorsAt(_ *http.Request, args *GetValidatorsAtAr
 	}
 	return nil
 }
+
+const (
+	stakingSnapshotFormatJSON = "json"
+	stakingSnapshotFormatCSV  = "csv"
+
+	stakingSnapshotValidator       = "validator"
+	stakingSnapshotDelegator       = "delegator"
+	stakingSnapshotSubnetValidator = "subnetValidator"
+)
+
+var stakingSnapshotCSVHeader = []string{
+	"txID",
+	"type",
+	"subnetID",
+	"nodeID",
+	"startTime",
+	"endTime",
+	"stakeAmount",
+	"potentialReward",
+	"delegationFee",
+	"rewardLocktime",
+	"rewardThreshold",
+	"rewardAddresses",
+}
+
+// GetStakingSnapshotArgs are the arguments for calling GetStakingSnapshot
+type GetStakingSnapshotArgs struct {
+	Height json.Uint64 `json:"height"`
+	// Format of the reply. Either "json" or "csv".
+	// If omitted, defaults to "json".
+	Format string `json:"format"`
+}
+
+// APIStakingSnapshotStaker is the repr. of a staker in a staking snapshot
+type APIStakingSnapshotStaker struct {
+	TxID            ids.ID       `json:"txID"`
+	Type            string       `json:"type"`
+	SubnetID        ids.ID       `json:"subnetID"`
+	NodeID          string       `json:"nodeID"`
+	StartTime       json.Uint64  `json:"startTime"`
+	EndTime         json.Uint64  `json:"endTime"`
+	StakeAmount     json.Uint64  `json:"stakeAmount"`
+	PotentialReward json.Uint64  `json:"potentialReward"`
+	DelegationFee   json.Float32 `json:"delegationFee"`
+	RewardOwner     *APIOwner    `json:"rewardOwner,omitempty"`
+}
+
+// GetStakingSnapshotReply is the response from GetStakingSnapshot.
+// Exactly one of [Stakers] and [CSV] is populated, depending on the requested
+// format.
+type GetStakingSnapshotReply struct {
+	Height  json.Uint64                `json:"height"`
+	Stakers []APIStakingSnapshotStaker `json:"stakers,omitempty"`
+	CSV     string                     `json:"csv,omitempty"`
+}
+
+// GetStakingSnapshot returns every validator, delegator and subnet validator
+// that was staking as of the block at the specified height, along with their
+// stake amounts, staking periods and reward configurations.
+func (service *Service) GetStakingSnapshot(_ *http.Request, args *GetStakingSnapshotArgs, reply *GetStakingSnapshotReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetStakingSnapshot called with Height %d", args.Height)
+
+	format := strings.ToLower(args.Format)
+	switch format {
+	case "":
+		format = stakingSnapshotFormatJSON
+	case stakingSnapshotFormatJSON, stakingSnapshotFormatCSV:
+	default:
+		return fmt.Errorf("unknown format %q, expected %q or %q", args.Format, stakingSnapshotFormatJSON, stakingSnapshotFormatCSV)
+	}
+
+	snapshot, err := service.vm.getStakingSnapshot(uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("couldn't get staking snapshot: %w", err)
+	}
+
+	stakers := make([]APIStakingSnapshotStaker, 0, len(snapshot))
+	for _, staker := range snapshot {
+		apiStaker := APIStakingSnapshotStaker{
+			TxID:            staker.addStakerTx.ID(),
+			PotentialReward: json.Uint64(staker.potentialReward),
+		}
+
+		var (
+			validator    Validator
+			rewardsOwner Owner
+		)
+		switch tx := staker.addStakerTx.UnsignedTx.(type) {
+		case *UnsignedAddValidatorTx:
+			apiStaker.Type = stakingSnapshotValidator
+			apiStaker.SubnetID = constants.PrimaryNetworkID
+			apiStaker.DelegationFee = json.Float32(100 * float32(tx.Shares) / float32(reward.PercentDenominator))
+			validator = tx.Validator
+			rewardsOwner = tx.RewardsOwner
+		case *UnsignedAddDelegatorTx:
+			apiStaker.Type = stakingSnapshotDelegator
+			apiStaker.SubnetID = constants.PrimaryNetworkID
+			validator = tx.Validator
+			rewardsOwner = tx.RewardsOwner
+		case *UnsignedAddSubnetValidatorTx:
+			apiStaker.Type = stakingSnapshotSubnetValidator
+			apiStaker.SubnetID = tx.Validator.Subnet
+			validator = tx.Validator.Validator
+		default:
+			return fmt.Errorf("expected staker but got %T", staker.addStakerTx.UnsignedTx)
+		}
+		apiStaker.NodeID = validator.ID().PrefixedString(constants.NodeIDPrefix)
+		apiStaker.StartTime = json.Uint64(validator.StartTime().Unix())
+		apiStaker.EndTime = json.Uint64(validator.EndTime().Unix())
+		apiStaker.StakeAmount = json.Uint64(validator.Weight())
+
+		if owner, ok := rewardsOwner.(*secp256k1fx.OutputOwners); ok {
+			apiStaker.RewardOwner = &APIOwner{
+				Locktime:  json.Uint64(owner.Locktime),
+				Threshold: json.Uint32(owner.Threshold),
+			}
+			for _, addr := range owner.Addrs {
+				addrStr, err := service.vm.FormatLocalAddress(addr)
+				if err != nil {
+					return err
+				}
+				apiStaker.RewardOwner.Addresses = append(apiStaker.RewardOwner.Addresses, addrStr)
+			}
+		}
+		stakers = append(stakers, apiStaker)
+	}
+
+	reply.Height = args.Height
+	if format == stakingSnapshotFormatJSON {
+		reply.Stakers = stakers
+		return nil
+	}
+
+	csvStr, err := stakingSnapshotToCSV(stakers)
+	if err != nil {
+		return fmt.Errorf("couldn't encode staking snapshot: %w", err)
+	}
+	reply.CSV = csvStr
+	return nil
+}
+
+// stakingSnapshotToCSV encodes [stakers] as CSV, with one row per staker.
+// Reward addresses are separated by spaces.
+func stakingSnapshotToCSV(stakers []APIStakingSnapshotStaker) (string, error) {
+	sb := strings.Builder{}
+	w := csv.NewWriter(&sb)
+	if err := w.Write(stakingSnapshotCSVHeader); err != nil {
+		return "", err
+	}
+	for _, staker := range stakers {
+		var rewardLocktime, rewardThreshold, rewardAddresses string
+		if staker.RewardOwner != nil {
+			rewardLocktime = strconv.FormatUint(uint64(staker.RewardOwner.Locktime), 10)
+			rewardThreshold = strconv.FormatUint(uint64(staker.RewardOwner.Threshold), 10)
+			rewardAddresses = strings.Join(staker.RewardOwner.Addresses, " ")
+		}
+		err := w.Write([]string{
+			staker.TxID.String(),
+			staker.Type,
+			staker.SubnetID.String(),
+			staker.NodeID,
+			strconv.FormatUint(uint64(staker.StartTime), 10),
+			strconv.FormatUint(uint64(staker.EndTime), 10),
+			strconv.FormatUint(uint64(staker.StakeAmount), 10),
+			strconv.FormatUint(uint64(staker.PotentialReward), 10),
+			strconv.FormatFloat(float64(staker.DelegationFee), 'f', -1, 32),
+			rewardLocktime,
+			rewardThreshold,
+			rewardAddresses,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
+
+// GetUptimeHistoryArgs are the arguments for calling GetUptimeHistory
+type GetUptimeHistoryArgs struct {
+	NodeID string `json:"nodeID"`
+	// Unix timestamps of the window the uptime is calculated over.
+	// If [EndTime] is omitted, it defaults to the current time.
+	StartTime json.Uint64 `json:"startTime"`
+	EndTime   json.Uint64 `json:"endTime"`
+}
+
+// GetUptimeHistoryReply is the response from GetUptimeHistory
+type GetUptimeHistoryReply struct {
+	// Number of seconds the validator was observed to be connected during the
+	// window
+	UpDuration json.Uint64 `json:"upDuration"`
+	// Number of seconds this node observed the validator for during the
+	// window. The time this node was offline isn't observed.
+	ObservedDuration json.Uint64 `json:"observedDuration"`
+	// The fraction of the observed time the validator was connected. It's 1
+	// if the validator wasn't observed.
+	Uptime json.Float32 `json:"uptime"`
+}
+
+// GetUptimeHistory returns the uptime of a validator, as observed by this
+// node, over an arbitrary window. The history is recorded in hourly buckets, so
+// the hours that are only partially in the window are counted in proportion.
+func (service *Service) GetUptimeHistory(_ *http.Request, args *GetUptimeHistoryArgs, reply *GetUptimeHistoryReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetUptimeHistory called with NodeID %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("couldn't parse nodeID: %w", err)
+	}
+
+	startTime := time.Unix(int64(args.StartTime), 0)
+	endTime := service.vm.clock.Time()
+	if args.EndTime != 0 {
+		endTime = time.Unix(int64(args.EndTime), 0)
+	}
+	if !startTime.Before(endTime) {
+		return errEmptyUptimeWindow
+	}
+
+	upDuration, observedDuration, err := service.vm.uptimeManager.CalculateUptimeHistory(nodeID, startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("couldn't get uptime history: %w", err)
+	}
+
+	reply.UpDuration = json.Uint64(upDuration / time.Second)
+	reply.ObservedDuration = json.Uint64(observedDuration / time.Second)
+	reply.Uptime = 1
+	if observedDuration > 0 {
+		reply.Uptime = json.Float32(float64(upDuration) / float64(observedDuration))
+	}
+	return nil
+}
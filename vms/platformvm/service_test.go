@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -20,6 +21,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/database/manager"
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/uptime"
 	"github.com/Toinounet21/avalanchego-mod/utils/constants"
 	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
 	"github.com/Toinounet21/avalanchego-mod/utils/formatting"
@@ -27,6 +29,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/version"
 	"github.com/Toinounet21/avalanchego-mod/vms/avm"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/avax"
+	"github.com/Toinounet21/avalanchego-mod/vms/platformvm/reward"
 	"github.com/Toinounet21/avalanchego-mod/vms/platformvm/status"
 	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
 
@@ -295,9 +298,75 @@ func TestGetTxStatus(t *testing.T) {
 		t.Fatalf("status should be Committed but is %s", resp.Status)
 	case resp.Reason != "":
 		t.Fatalf("reason should be empty but is %s", resp.Reason)
+	case resp.Block == nil:
+		t.Fatal("block should be reported")
+	case resp.Block.BlockID != service.vm.lastAcceptedID:
+		t.Fatalf("block should be %s but is %s", service.vm.lastAcceptedID, resp.Block.BlockID)
+	case resp.Block.Height != 1:
+		t.Fatalf("height should be 1 but is %d", resp.Block.Height)
 	}
 }
 
+func TestGetBlockByHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	lastAccepted, err := service.vm.LastAccepted()
+	assert.NoError(err)
+	lastAcceptedHeight, err := service.vm.GetCurrentHeight()
+	assert.NoError(err)
+
+	// Accept a block that includes a tx
+	tx, err := service.vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+	assert.NoError(service.vm.SetPreference(lastAccepted))
+	assert.NoError(service.vm.blockBuilder.AddUnverifiedTx(tx))
+	blk, err := service.vm.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(blk.Verify())
+	assert.NoError(blk.Accept())
+
+	for i, blkID := range []ids.ID{lastAccepted, blk.ID()} {
+		height := lastAcceptedHeight + uint64(i)
+		reply := GetBlockByHeightReply{}
+		assert.NoError(service.GetBlockByHeight(nil, &GetBlockByHeightArgs{
+			Height:   cjson.Uint64(height),
+			Encoding: formatting.Hex,
+		}, &reply))
+		assert.Equal(blkID, reply.BlockID)
+		assert.EqualValues(height, reply.Height)
+		assert.EqualValues(service.vm.internalState.GetTimestamp().Unix(), reply.Timestamp)
+
+		blkBytes, err := formatting.Decode(reply.Encoding, reply.Block)
+		assert.NoError(err)
+		parsedBlk, err := service.vm.ParseBlock(blkBytes)
+		assert.NoError(err)
+		assert.Equal(blkID, parsedBlk.ID())
+	}
+
+	txIndex, err := service.vm.internalState.GetTxIndex(tx.ID())
+	assert.NoError(err)
+	assert.Equal(blk.ID(), txIndex.BlkID)
+
+	// No block has been accepted after it yet
+	err = service.GetBlockByHeight(nil, &GetBlockByHeightArgs{Height: cjson.Uint64(lastAcceptedHeight + 2)}, &GetBlockByHeightReply{})
+	assert.Error(err)
+}
+
 // Test issuing and then retrieving a transaction
 func TestGetTx(t *testing.T) {
 	service := defaultService(t)
@@ -726,6 +795,275 @@ func TestGetCurrentValidators(t *testing.T) {
 	}
 }
 
+func TestGetCurrentValidatorsPaginated(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	genesis, _ := defaultGenesis()
+
+	allResponse := GetCurrentValidatorsReply{}
+	assert.NoError(service.GetCurrentValidators(nil, &GetCurrentValidatorsArgs{SubnetID: constants.PrimaryNetworkID}, &allResponse))
+	allValidators := map[string]interface{}{}
+	for _, vdrIntf := range allResponse.Validators {
+		allValidators[vdrIntf.(APIPrimaryValidator).NodeID] = vdrIntf
+	}
+
+	// Page through the validators, 2 at a time
+	nodeIDs := []string(nil)
+	args := GetCurrentValidatorsArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		Limit:    2,
+	}
+	for numPages := 0; ; numPages++ {
+		assert.Less(numPages, len(genesis.Validators), "pagination didn't terminate")
+
+		response := GetCurrentValidatorsReply{}
+		assert.NoError(service.GetCurrentValidators(nil, &args, &response))
+		assert.LessOrEqual(len(response.Validators), 2)
+		for _, vdrIntf := range response.Validators {
+			vdr, ok := vdrIntf.(APIPrimaryValidator)
+			assert.True(ok)
+			nodeIDs = append(nodeIDs, vdr.NodeID)
+			// Paging doesn't change how a validator is reported
+			assert.Equal(allValidators[vdr.NodeID], vdrIntf)
+		}
+
+		if response.NextNodeID == "" {
+			break
+		}
+		args.StartNodeID = response.NextNodeID
+	}
+
+	// Every validator is returned exactly once, in order of node ID
+	expectedNodeIDs := make([]ids.ShortID, len(genesis.Validators))
+	for i, vdr := range genesis.Validators {
+		nodeID, err := ids.ShortFromPrefixedString(vdr.NodeID, constants.NodeIDPrefix)
+		assert.NoError(err)
+		expectedNodeIDs[i] = nodeID
+	}
+	ids.SortShortIDs(expectedNodeIDs)
+	expectedNodeIDStrs := make([]string, len(expectedNodeIDs))
+	for i, nodeID := range expectedNodeIDs {
+		expectedNodeIDStrs[i] = nodeID.PrefixedString(constants.NodeIDPrefix)
+	}
+	assert.Equal(expectedNodeIDStrs, nodeIDs)
+
+	// An invalid cursor is reported
+	args.StartNodeID = "not a node ID"
+	assert.Error(service.GetCurrentValidators(nil, &args, &GetCurrentValidatorsReply{}))
+}
+
+func TestGetCurrentValidatorsFiltered(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	genesis, _ := defaultGenesis()
+
+	// Add a delegator that stops staking before the validators
+	validatorNodeID := keys[1].PublicKey().Address()
+	delegatorEndTime := uint64(defaultValidateStartTime.Add(defaultMinStakingDuration).Unix())
+	tx, err := service.vm.newAddDelegatorTx(
+		service.vm.MinDelegatorStake,
+		uint64(defaultValidateStartTime.Unix()),
+		delegatorEndTime,
+		validatorNodeID,
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+	service.vm.internalState.AddCurrentStaker(tx, 0)
+	service.vm.internalState.AddTx(tx, status.Committed)
+	assert.NoError(service.vm.internalState.Commit())
+	assert.NoError(service.vm.internalState.(*internalStateImpl).loadCurrentValidators())
+
+	// getValidators returns the validators reported with and without
+	// pagination, and checks that both agree
+	getValidators := func(args GetCurrentValidatorsArgs) []APIPrimaryValidator {
+		args.SubnetID = constants.PrimaryNetworkID
+		response := GetCurrentValidatorsReply{}
+		assert.NoError(service.GetCurrentValidators(nil, &args, &response))
+
+		pagedValidators := []interface{}{}
+		args.Limit = 1
+		for {
+			page := GetCurrentValidatorsReply{}
+			assert.NoError(service.GetCurrentValidators(nil, &args, &page))
+			assert.LessOrEqual(len(page.Validators), 1)
+			pagedValidators = append(pagedValidators, page.Validators...)
+			if page.NextNodeID == "" {
+				break
+			}
+			args.StartNodeID = page.NextNodeID
+		}
+		assert.ElementsMatch(response.Validators, pagedValidators)
+
+		vdrs := make([]APIPrimaryValidator, len(response.Validators))
+		for i, vdrIntf := range response.Validators {
+			vdr, ok := vdrIntf.(APIPrimaryValidator)
+			assert.True(ok)
+			vdrs[i] = vdr
+		}
+		return vdrs
+	}
+
+	// Without filters, every validator is reported
+	assert.Len(getValidators(GetCurrentValidatorsArgs{}), len(genesis.Validators))
+
+	// Filter by node ID
+	vdrs := getValidators(GetCurrentValidatorsArgs{
+		NodeIDs: []string{validatorNodeID.PrefixedString(constants.NodeIDPrefix)},
+	})
+	assert.Len(vdrs, 1)
+	assert.Equal(validatorNodeID.PrefixedString(constants.NodeIDPrefix), vdrs[0].NodeID)
+	assert.Len(vdrs[0].Delegators, 1)
+
+	// The stake of a validator doesn't include its delegations
+	genesisStake := uint64(*vdrs[0].StakeAmount)
+	assert.Len(getValidators(GetCurrentValidatorsArgs{MinStake: cjson.Uint64(genesisStake)}), len(genesis.Validators))
+	assert.Empty(getValidators(GetCurrentValidatorsArgs{MinStake: cjson.Uint64(genesisStake + 1)}))
+
+	// Only the stakers that stop staking before the timestamp are reported
+	assert.Empty(getValidators(GetCurrentValidatorsArgs{EndTimeBefore: cjson.Uint64(defaultValidateEndTime.Unix())}))
+	vdrs = getValidators(GetCurrentValidatorsArgs{
+		NodeIDs:       []string{validatorNodeID.PrefixedString(constants.NodeIDPrefix)},
+		EndTimeBefore: cjson.Uint64(defaultValidateEndTime.Unix() + 1),
+	})
+	assert.Len(vdrs, 1)
+	assert.Len(vdrs[0].Delegators, 1)
+	assert.Equal(delegatorEndTime, uint64(vdrs[0].Delegators[0].EndTime))
+
+	// Delegators are filtered separately from their validator
+	vdrs = getValidators(GetCurrentValidatorsArgs{
+		NodeIDs:  []string{validatorNodeID.PrefixedString(constants.NodeIDPrefix)},
+		MinStake: cjson.Uint64(genesisStake),
+	})
+	assert.Len(vdrs, 1)
+	assert.Len(vdrs[0].Delegators, 1)
+	vdrs = getValidators(GetCurrentValidatorsArgs{
+		NodeIDs:  []string{validatorNodeID.PrefixedString(constants.NodeIDPrefix)},
+		MinStake: cjson.Uint64(service.vm.MinDelegatorStake + 1),
+	})
+	assert.Empty(vdrs)
+}
+
+func TestGetPendingValidatorsPaginated(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	// Add pending validators with increasing stake, and a pending delegator
+	// to the first of them
+	startTime := defaultValidateStartTime.Add(time.Second)
+	endTime := startTime.Add(defaultMinStakingDuration)
+	nodeIDs := make([]ids.ShortID, 3)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestShortID()
+		tx, err := service.vm.newAddValidatorTx(
+			service.vm.MinValidatorStake+uint64(i),
+			uint64(startTime.Unix()),
+			uint64(endTime.Unix()),
+			nodeIDs[i],
+			ids.GenerateTestShortID(),
+			reward.PercentDenominator,
+			[]*crypto.PrivateKeySECP256K1R{keys[0]},
+			ids.ShortEmpty, // change addr
+		)
+		assert.NoError(err)
+		service.vm.internalState.AddPendingStaker(tx)
+		service.vm.internalState.AddTx(tx, status.Committed)
+	}
+	tx, err := service.vm.newAddDelegatorTx(
+		service.vm.MinDelegatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(defaultMinStakingDuration/2).Unix()),
+		nodeIDs[0],
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+	service.vm.internalState.AddPendingStaker(tx)
+	service.vm.internalState.AddTx(tx, status.Committed)
+	assert.NoError(service.vm.internalState.Commit())
+	assert.NoError(service.vm.internalState.(*internalStateImpl).loadPendingValidators())
+
+	ids.SortShortIDs(nodeIDs)
+
+	// Page through the stakers, 2 node IDs at a time
+	pagedNodeIDs := []string(nil)
+	numDelegators := 0
+	args := GetPendingValidatorsArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		Limit:    2,
+	}
+	for numPages := 0; ; numPages++ {
+		assert.Less(numPages, len(nodeIDs), "pagination didn't terminate")
+
+		response := GetPendingValidatorsReply{}
+		assert.NoError(service.GetPendingValidators(nil, &args, &response))
+		assert.LessOrEqual(len(response.Validators), 2)
+		for _, vdrIntf := range response.Validators {
+			vdr, ok := vdrIntf.(APIPrimaryValidator)
+			assert.True(ok)
+			pagedNodeIDs = append(pagedNodeIDs, vdr.NodeID)
+		}
+		numDelegators += len(response.Delegators)
+
+		if response.NextNodeID == "" {
+			break
+		}
+		args.StartNodeID = response.NextNodeID
+	}
+
+	// Every staker is returned exactly once, in order of node ID
+	expectedNodeIDs := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		expectedNodeIDs[i] = nodeID.PrefixedString(constants.NodeIDPrefix)
+	}
+	assert.Equal(expectedNodeIDs, pagedNodeIDs)
+	assert.Equal(1, numDelegators)
+
+	// Filter by stake
+	response := GetPendingValidatorsReply{}
+	assert.NoError(service.GetPendingValidators(nil, &GetPendingValidatorsArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		MinStake: cjson.Uint64(service.vm.MinValidatorStake + 1),
+	}, &response))
+	assert.Len(response.Validators, 2)
+	assert.Empty(response.Delegators)
+
+	// Filter by end time
+	response = GetPendingValidatorsReply{}
+	assert.NoError(service.GetPendingValidators(nil, &GetPendingValidatorsArgs{
+		SubnetID:      constants.PrimaryNetworkID,
+		EndTimeBefore: cjson.Uint64(endTime.Unix()),
+	}, &response))
+	assert.Empty(response.Validators)
+	assert.Len(response.Delegators, 1)
+
+	// An invalid cursor is reported
+	args.StartNodeID = "not a node ID"
+	assert.Error(service.GetPendingValidators(nil, &args, &GetPendingValidatorsReply{}))
+}
+
 func TestGetTimestamp(t *testing.T) {
 	assert := assert.New(t)
 
@@ -752,3 +1090,354 @@ func TestGetTimestamp(t *testing.T) {
 
 	assert.Equal(newTimestamp, reply.Timestamp)
 }
+
+func TestGetStakingSnapshot(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		err := service.vm.Shutdown()
+		assert.NoError(err)
+
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	genesis, _ := defaultGenesis()
+
+	// acceptStakerChanges applies the staker changes as if they were made by
+	// an accepted block at [height]
+	acceptStakerChanges := func(height uint64) {
+		blk, err := service.vm.newCommitBlock(service.vm.lastAcceptedID, height, true)
+		assert.NoError(err)
+
+		service.vm.internalState.SetHeight(height)
+		service.vm.internalState.AddBlock(blk)
+		service.vm.internalState.SetLastAccepted(blk.ID())
+		service.vm.lastAcceptedID = blk.ID()
+		assert.NoError(service.vm.internalState.Commit())
+		assert.NoError(service.vm.internalState.(*internalStateImpl).loadCurrentValidators())
+	}
+
+	getSnapshot := func(height uint64) map[ids.ID]APIStakingSnapshotStaker {
+		reply := GetStakingSnapshotReply{}
+		err := service.GetStakingSnapshot(nil, &GetStakingSnapshotArgs{Height: cjson.Uint64(height)}, &reply)
+		assert.NoError(err)
+		assert.EqualValues(height, reply.Height)
+
+		stakers := make(map[ids.ID]APIStakingSnapshotStaker, len(reply.Stakers))
+		for _, staker := range reply.Stakers {
+			stakers[staker.TxID] = staker
+		}
+		return stakers
+	}
+
+	genesisStakers := getSnapshot(0)
+	assert.Len(genesisStakers, len(genesis.Validators))
+	for _, staker := range genesisStakers {
+		assert.Equal(stakingSnapshotValidator, staker.Type)
+		assert.Equal(constants.PrimaryNetworkID, staker.SubnetID)
+		assert.NotNil(staker.RewardOwner)
+	}
+
+	// Add a delegator at height 1
+	stakeAmt := service.vm.MinDelegatorStake + 12345
+	delegatorStartTime := uint64(defaultValidateStartTime.Unix())
+	delegatorEndTime := uint64(defaultValidateStartTime.Add(defaultMinStakingDuration).Unix())
+	tx, err := service.vm.newAddDelegatorTx(
+		stakeAmt,
+		delegatorStartTime,
+		delegatorEndTime,
+		keys[1].PublicKey().Address(),
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+
+	service.vm.internalState.AddCurrentStaker(tx, 1234)
+	service.vm.internalState.AddTx(tx, status.Committed)
+	acceptStakerChanges(1)
+
+	// Remove the delegator at height 2
+	service.vm.internalState.DeleteCurrentStaker(tx)
+	acceptStakerChanges(2)
+
+	assert.Equal(genesisStakers, getSnapshot(0))
+	assert.Equal(genesisStakers, getSnapshot(2))
+
+	stakers := getSnapshot(1)
+	assert.Len(stakers, len(genesis.Validators)+1)
+	delegator, ok := stakers[tx.ID()]
+	assert.True(ok)
+	assert.Equal(stakingSnapshotDelegator, delegator.Type)
+	assert.Equal(keys[1].PublicKey().Address().PrefixedString(constants.NodeIDPrefix), delegator.NodeID)
+	assert.EqualValues(delegatorStartTime, delegator.StartTime)
+	assert.EqualValues(delegatorEndTime, delegator.EndTime)
+	assert.EqualValues(stakeAmt, delegator.StakeAmount)
+	assert.EqualValues(1234, delegator.PotentialReward)
+
+	// The same snapshot can be exported as CSV
+	reply := GetStakingSnapshotReply{}
+	err = service.GetStakingSnapshot(nil, &GetStakingSnapshotArgs{Height: 1, Format: "csv"}, &reply)
+	assert.NoError(err)
+	assert.Empty(reply.Stakers)
+	rows, err := csv.NewReader(strings.NewReader(reply.CSV)).ReadAll()
+	assert.NoError(err)
+	assert.Len(rows, len(stakers)+1)
+	assert.Equal(stakingSnapshotCSVHeader, rows[0])
+
+	// Heights that haven't been accepted yet don't have a snapshot
+	err = service.GetStakingSnapshot(nil, &GetStakingSnapshotArgs{Height: 3}, &reply)
+	assert.Error(err)
+
+	err = service.GetStakingSnapshot(nil, &GetStakingSnapshotArgs{Height: 1, Format: "xml"}, &reply)
+	assert.Error(err)
+}
+
+func TestGetExpectedReward(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		err := service.vm.Shutdown()
+		assert.NoError(err)
+
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	supply := service.vm.internalState.GetCurrentSupply()
+	stakeAmt := service.vm.MinValidatorStake
+	reply := GetExpectedRewardReply{}
+
+	// A hypothetical staker is rewarded based on the current supply
+	err := service.GetExpectedReward(nil, &GetExpectedRewardArgs{
+		Amount:   cjson.Uint64(stakeAmt),
+		Duration: cjson.Uint64(defaultMinStakingDuration / time.Second),
+	}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(service.vm.rewards.Calculate(defaultMinStakingDuration, stakeAmt, supply), reply.Reward)
+	assert.Empty(reply.StakerStatus)
+
+	err = service.GetExpectedReward(nil, &GetExpectedRewardArgs{
+		Amount:   cjson.Uint64(stakeAmt),
+		Duration: cjson.Uint64(defaultMaxStakingDuration/time.Second + 1),
+	}, &reply)
+	assert.Error(err)
+
+	// Current stakers report their potential reward
+	currentStakers := service.vm.internalState.CurrentStakerChainState()
+	currentTx := currentStakers.Stakers()[0]
+	_, potentialReward, err := currentStakers.GetStaker(currentTx.ID())
+	assert.NoError(err)
+	err = service.GetExpectedReward(nil, &GetExpectedRewardArgs{TxID: currentTx.ID()}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(potentialReward, reply.Reward)
+	assert.Equal(expectedRewardCurrent, reply.StakerStatus)
+
+	// Add a pending staker
+	pendingStartTime := defaultGenesisTime.Add(time.Hour)
+	pendingTx, err := service.vm.newAddValidatorTx(
+		stakeAmt,
+		uint64(pendingStartTime.Unix()),
+		uint64(pendingStartTime.Add(defaultMinStakingDuration).Unix()),
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+		0,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+
+	service.vm.internalState.AddPendingStaker(pendingTx)
+	service.vm.internalState.AddTx(pendingTx, status.Committed)
+	assert.NoError(service.vm.internalState.Commit())
+	assert.NoError(service.vm.internalState.(*internalStateImpl).loadPendingValidators())
+
+	pendingReward := service.vm.rewards.Calculate(defaultMinStakingDuration, stakeAmt, supply)
+	err = service.GetExpectedReward(nil, &GetExpectedRewardArgs{TxID: pendingTx.ID()}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(pendingReward, reply.Reward)
+	assert.Equal(expectedRewardPending, reply.StakerStatus)
+
+	// Stakers that start after the pending staker are rewarded based on the
+	// supply after the pending staker's reward is added
+	err = service.GetExpectedReward(nil, &GetExpectedRewardArgs{
+		Amount:    cjson.Uint64(stakeAmt),
+		Duration:  cjson.Uint64(defaultMinStakingDuration / time.Second),
+		StartTime: cjson.Uint64(pendingStartTime.Unix()),
+	}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(service.vm.rewards.Calculate(defaultMinStakingDuration, stakeAmt, supply+pendingReward), reply.Reward)
+
+	// Removed stakers report the value of their reward UTXOs
+	rewardedTx, err := service.vm.newAddDelegatorTx(
+		service.vm.MinDelegatorStake,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateStartTime.Add(defaultMinStakingDuration).Unix()),
+		keys[1].PublicKey().Address(),
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+
+	service.vm.internalState.AddTx(rewardedTx, status.Committed)
+	for i, amount := range []uint64{100, 23} {
+		service.vm.internalState.AddRewardUTXO(rewardedTx.ID(), &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: rewardedTx.ID(), OutputIndex: uint32(i)},
+			Asset:  avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+				},
+			},
+		})
+	}
+	assert.NoError(service.vm.internalState.Commit())
+
+	err = service.GetExpectedReward(nil, &GetExpectedRewardArgs{TxID: rewardedTx.ID()}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(123, reply.Reward)
+	assert.Equal(expectedRewardRealized, reply.StakerStatus)
+
+	err = service.GetExpectedReward(nil, &GetExpectedRewardArgs{TxID: ids.GenerateTestID()}, &reply)
+	assert.Error(err)
+}
+
+func TestGetValidatorsAtUntrackedSubnet(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		err := service.vm.Shutdown()
+		assert.NoError(err)
+
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	// acceptStakerChanges applies the staker changes as if they were made by
+	// an accepted block at [height]
+	acceptStakerChanges := func(height uint64) {
+		blk, err := service.vm.newCommitBlock(service.vm.lastAcceptedID, height, true)
+		assert.NoError(err)
+
+		service.vm.internalState.SetHeight(height)
+		service.vm.internalState.AddBlock(blk)
+		service.vm.internalState.SetLastAccepted(blk.ID())
+		service.vm.lastAcceptedID = blk.ID()
+		assert.NoError(service.vm.internalState.Commit())
+		assert.NoError(service.vm.internalState.(*internalStateImpl).loadCurrentValidators())
+	}
+
+	getValidatorsAt := func(height uint64) map[string]uint64 {
+		reply := GetValidatorsAtReply{}
+		err := service.GetValidatorsAt(nil, &GetValidatorsAtArgs{
+			Height:   cjson.Uint64(height),
+			SubnetID: testSubnet1.ID(),
+		}, &reply)
+		assert.NoError(err)
+		return reply.Validators
+	}
+
+	// [testSubnet1] isn't whitelisted, so its validators aren't tracked
+	_, tracked := service.vm.Validators.GetValidators(testSubnet1.ID())
+	assert.False(tracked)
+
+	// Add a subnet validator at height 1
+	nodeID := keys[0].PublicKey().Address()
+	tx, err := service.vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateStartTime.Add(defaultMinStakingDuration).Unix()),
+		nodeID,
+		testSubnet1.ID(),
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+
+	service.vm.internalState.AddCurrentStaker(tx, 0)
+	service.vm.internalState.AddTx(tx, status.Committed)
+	acceptStakerChanges(1)
+
+	// Remove it at height 2
+	service.vm.internalState.DeleteCurrentStaker(tx)
+	acceptStakerChanges(2)
+
+	assert.Empty(getValidatorsAt(0))
+	assert.Equal(map[string]uint64{
+		nodeID.PrefixedString(constants.NodeIDPrefix): defaultWeight,
+	}, getValidatorsAt(1))
+	assert.Empty(getValidatorsAt(2))
+
+	// Heights that haven't been accepted yet don't have a validator set
+	reply := GetValidatorsAtReply{}
+	err = service.GetValidatorsAt(nil, &GetValidatorsAtArgs{
+		Height:   3,
+		SubnetID: testSubnet1.ID(),
+	}, &reply)
+	assert.Error(err)
+}
+
+func TestGetUptimeHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		err := service.vm.Shutdown()
+		assert.NoError(err)
+
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	nodeID := keys[0].PublicKey().Address()
+	nodeIDStr := nodeID.PrefixedString(constants.NodeIDPrefix)
+
+	// The validator is disconnected until the next hour, then connected for an
+	// hour
+	uptimeManager := service.vm.uptimeManager.(uptime.TestManager)
+	start := time.Now().Truncate(time.Hour).Add(time.Hour)
+	uptimeManager.SetTime(start)
+	assert.NoError(service.vm.Connected(nodeID, version.CurrentApp))
+	uptimeManager.SetTime(start.Add(time.Hour))
+
+	reply := GetUptimeHistoryReply{}
+	err := service.GetUptimeHistory(nil, &GetUptimeHistoryArgs{
+		NodeID:    nodeIDStr,
+		StartTime: cjson.Uint64(start.Unix()),
+		EndTime:   cjson.Uint64(start.Add(time.Hour).Unix()),
+	}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(3600, reply.UpDuration)
+	assert.EqualValues(3600, reply.ObservedDuration)
+	assert.EqualValues(1, reply.Uptime)
+
+	err = service.GetUptimeHistory(nil, &GetUptimeHistoryArgs{
+		NodeID:    nodeIDStr,
+		StartTime: cjson.Uint64(start.Add(-time.Hour).Unix()),
+		EndTime:   cjson.Uint64(start.Add(time.Hour).Unix()),
+	}, &reply)
+	assert.NoError(err)
+	assert.EqualValues(3600, reply.UpDuration)
+	// The validator was observed since the VM was bootstrapped
+	assert.GreaterOrEqual(uint64(reply.ObservedDuration), uint64(3600))
+
+	err = service.GetUptimeHistory(nil, &GetUptimeHistoryArgs{
+		NodeID:    nodeIDStr,
+		StartTime: cjson.Uint64(start.Unix()),
+		EndTime:   cjson.Uint64(start.Unix()),
+	}, &reply)
+	assert.ErrorIs(err, errEmptyUptimeWindow)
+
+	err = service.GetUptimeHistory(nil, &GetUptimeHistoryArgs{
+		NodeID:    "not a node ID",
+		StartTime: cjson.Uint64(start.Unix()),
+	}, &reply)
+	assert.Error(err)
+}
@@ -115,7 +115,7 @@ func (vm *VM) stake(
 		remainingValue := in.Amount()
 
 		// Stake any value that should be staked
-		amountToStake := math.Min64(
+		amountToStake := math.Min(
 			amount-amountStaked, // Amount we still need to stake
 			remainingValue,      // Amount available to stake
 		)
@@ -206,7 +206,7 @@ func (vm *VM) stake(
 		remainingValue := in.Amount()
 
 		// Burn any value that should be burned
-		amountToBurn := math.Min64(
+		amountToBurn := math.Min(
 			fee-amountBurned, // Amount we still need to burn
 			remainingValue,   // Amount available to burn
 		)
@@ -214,7 +214,7 @@ func (vm *VM) stake(
 		remainingValue -= amountToBurn
 
 		// Stake any value that should be staked
-		amountToStake := math.Min64(
+		amountToStake := math.Min(
 			amount-amountStaked, // Amount we still need to stake
 			remainingValue,      // Amount available to stake
 		)
@@ -355,6 +355,8 @@ func (vm *VM) semanticVerifySpend(
 // [creds] are the credentials of [tx], which allow [ins] to be spent.
 // [utxos[i]] is the UTXO being consumed by [ins[i]]
 // Precondition: [tx] has already been syntactically verified
+// If the fx supports it, the signatures of every input are verified together
+// once all other checks have passed.
 func (vm *VM) semanticVerifySpendUTXOs(
 	tx UnsignedTx,
 	utxos []*avax.UTXO,
@@ -363,6 +365,32 @@ func (vm *VM) semanticVerifySpendUTXOs(
 	creds []verify.Verifiable,
 	feeAmount uint64,
 	feeAssetID ids.ID,
+) error {
+	fx, ok := vm.fx.(batchFx)
+	if !ok {
+		return vm.verifySpendUTXOs(tx, utxos, ins, outs, creds, feeAmount, feeAssetID)
+	}
+
+	fx.StartBatch()
+	if err := vm.verifySpendUTXOs(tx, utxos, ins, outs, creds, feeAmount, feeAssetID); err != nil {
+		fx.AbortBatch()
+		return err
+	}
+	if err := fx.VerifyBatch(); err != nil {
+		return fmt.Errorf("failed to verify transfer: %w", err)
+	}
+	return nil
+}
+
+// verifySpendUTXOs performs the checks of semanticVerifySpendUTXOs
+func (vm *VM) verifySpendUTXOs(
+	tx UnsignedTx,
+	utxos []*avax.UTXO,
+	ins []*avax.TransferableInput,
+	outs []*avax.TransferableOutput,
+	creds []verify.Verifiable,
+	feeAmount uint64,
+	feeAssetID ids.ID,
 ) error {
 	if len(ins) != len(creds) {
 		return fmt.Errorf(
@@ -436,7 +464,7 @@ func (vm *VM) semanticVerifySpendUTXOs(
 		amount := in.Amount()
 
 		if now >= locktime {
-			newUnlockedConsumed, err := math.Add64(unlockedConsumed, amount)
+			newUnlockedConsumed, err := math.Add(unlockedConsumed, amount)
 			if err != nil {
 				return err
 			}
@@ -459,7 +487,7 @@ func (vm *VM) semanticVerifySpendUTXOs(
 			owners = make(map[ids.ID]uint64)
 			lockedConsumed[locktime] = owners
 		}
-		newAmount, err := math.Add64(owners[ownerID], amount)
+		newAmount, err := math.Add(owners[ownerID], amount)
 		if err != nil {
 			return err
 		}
@@ -482,7 +510,7 @@ func (vm *VM) semanticVerifySpendUTXOs(
 		amount := output.Amount()
 
 		if locktime == 0 {
-			newUnlockedProduced, err := math.Add64(unlockedProduced, amount)
+			newUnlockedProduced, err := math.Add(unlockedProduced, amount)
 			if err != nil {
 				return err
 			}
@@ -505,7 +533,7 @@ func (vm *VM) semanticVerifySpendUTXOs(
 			owners = make(map[ids.ID]uint64)
 			lockedProduced[locktime] = owners
 		}
-		newAmount, err := math.Add64(owners[ownerID], amount)
+		newAmount, err := math.Add(owners[ownerID], amount)
 		if err != nil {
 			return err
 		}
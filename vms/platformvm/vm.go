@@ -4,8 +4,10 @@
 package platformvm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gorilla/rpc/v2"
@@ -18,6 +20,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
 	"github.com/Toinounet21/avalanchego-mod/database"
 	"github.com/Toinounet21/avalanchego-mod/database/manager"
+	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/choices"
@@ -59,9 +62,12 @@ var (
 	errStartTimeTooEarly = errors.New("start time is before the current chain time")
 	errStartAfterEndTime = errors.New("start time is after the end time")
 	errWrongCacheType    = errors.New("unexpectedly cached type")
+	errNoStakerDiffs     = errors.New("staker diffs weren't recorded at the requested height")
 
 	_ block.ChainVM        = &VM{}
 	_ validators.Connector = &VM{}
+	_ validators.State     = &VM{}
+	_ chains.SubnetTracker = &VM{}
 	_ secp256k1fx.VM       = &VM{}
 	_ Fx                   = &secp256k1fx.Fx{}
 )
@@ -161,6 +167,12 @@ func (vm *VM) Initialize(
 	vm.dbManager = dbManager
 	vm.toEngine = msgs
 
+	// Subnets are tracked and untracked at runtime, so the set isn't shared
+	// with the rest of the node
+	whitelistedSubnets := ids.NewSet(vm.WhitelistedSubnets.Len())
+	whitelistedSubnets.Union(vm.WhitelistedSubnets)
+	vm.WhitelistedSubnets = whitelistedSubnets
+
 	vm.codecRegistry = linearcodec.NewDefault()
 	if err := vm.fx.Initialize(vm); err != nil {
 		return err
@@ -185,8 +197,10 @@ func (vm *VM) Initialize(
 	}
 	vm.internalState = is
 
-	// Initialize the utility to track validator uptimes
-	vm.uptimeManager = uptime.NewManager(is)
+	// Initialize the utility to track validator uptimes. The history is
+	// written as it's observed, so it doesn't go through the internal state.
+	uptimeHistory := uptime.NewHistory(prefixdb.New(uptimeHistoryPrefix, vm.dbManager.Current().Database))
+	vm.uptimeManager = uptime.NewManagerWithHistory(is, uptimeHistory)
 	vm.UptimeLockedCalculator.SetCalculator(&vm.bootstrapped, &ctx.Lock, vm.uptimeManager)
 
 	if err := vm.updateValidators(); err != nil {
@@ -279,6 +293,53 @@ func (vm *VM) createChain(tx *Tx) error {
 	return nil
 }
 
+// TrackSubnet loads the validator set of the subnet with ID [subnetID] and
+// creates its chains, as if it had been whitelisted when the node started.
+func (vm *VM) TrackSubnet(subnetID ids.ID) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if vm.WhitelistedSubnets.Contains(subnetID) {
+		return nil
+	}
+
+	subnetTx, _, err := vm.internalState.GetTx(subnetID)
+	if err != nil {
+		return fmt.Errorf("problem retrieving subnet %q: %w", subnetID, err)
+	}
+	if _, ok := subnetTx.UnsignedTx.(*UnsignedCreateSubnetTx); !ok {
+		return fmt.Errorf("%q is not a subnet", subnetID)
+	}
+
+	currentValidators := vm.internalState.CurrentStakerChainState()
+	subnetValidators, err := currentValidators.ValidatorSet(subnetID)
+	if err != nil {
+		return err
+	}
+	if err := vm.Validators.Set(subnetID, subnetValidators); err != nil {
+		return err
+	}
+
+	vm.WhitelistedSubnets.Add(subnetID)
+	return vm.createSubnet(subnetID)
+}
+
+// UntrackSubnet stops updating the validator set of the subnet with ID
+// [subnetID] and creating its chains.
+func (vm *VM) UntrackSubnet(subnetID ids.ID) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if !vm.WhitelistedSubnets.Contains(subnetID) {
+		return nil
+	}
+	vm.WhitelistedSubnets.Remove(subnetID)
+	delete(vm.validatorSetCaches, subnetID)
+
+	// The validator set would go stale, so it's emptied instead
+	return vm.Validators.Set(subnetID, validators.NewSet())
+}
+
 // Bootstrapping marks this VM as bootstrapping
 func (vm *VM) Bootstrapping() error {
 	vm.bootstrapped.SetValue(false)
@@ -470,6 +531,10 @@ func (vm *VM) Disconnected(vdrID ids.ShortID) error {
 
 // GetValidatorSet returns the validator set at the specified height for the
 // provided subnetID.
+//
+// The validator set is computed by reverting the persisted weight diffs of
+// every block after [height]. This works for any subnet, not only for the
+// subnets that this node tracks.
 func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
 	validatorSetsCache, exists := vm.validatorSetCaches[subnetID]
 	if !exists {
@@ -502,7 +567,12 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]u
 
 	currentValidators, ok := vm.Validators.GetValidators(subnetID)
 	if !ok {
-		return nil, errNotEnoughValidators
+		// The validators of subnets that aren't tracked aren't kept in
+		// [vm.Validators], so they are read from the current staker set
+		currentValidators, err = vm.internalState.CurrentStakerChainState().ValidatorSet(subnetID)
+		if err != nil {
+			return nil, err
+		}
 	}
 	currentValidatorList := currentValidators.List()
 
@@ -551,6 +621,72 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]u
 	return vdrSet, nil
 }
 
+// getStakingSnapshot returns the current stakers, and their potential rewards,
+// as of the block at [height]. The stakers are sorted by tx ID.
+func (vm *VM) getStakingSnapshot(height uint64) ([]*validatorReward, error) {
+	lastAcceptedHeight, err := vm.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	if lastAcceptedHeight < height {
+		return nil, database.ErrNotFound
+	}
+	if lastAcceptedHeight > height {
+		// The diffs of every height after [height] are needed to revert the
+		// current staker set back to [height].
+		startHeight, ok := vm.internalState.GetStakerDiffsStartHeight()
+		if !ok || startHeight > height+1 {
+			return nil, errNoStakerDiffs
+		}
+	}
+
+	// txID -> potential reward
+	stakers := make(map[ids.ID]uint64)
+	currentStakers := vm.internalState.CurrentStakerChainState()
+	for _, tx := range currentStakers.Stakers() {
+		txID := tx.ID()
+		_, potentialReward, err := currentStakers.GetStaker(txID)
+		if err != nil {
+			return nil, err
+		}
+		stakers[txID] = potentialReward
+	}
+
+	for i := lastAcceptedHeight; i > height; i-- {
+		diff, err := vm.internalState.GetStakerDiff(i)
+		if err != nil {
+			return nil, err
+		}
+
+		// The stakers added at this block weren't stakers in the prior block,
+		// and the stakers removed at this block were.
+		for _, added := range diff.Added {
+			delete(stakers, added.TxID)
+		}
+		for _, removed := range diff.Removed {
+			stakers[removed.TxID] = removed.PotentialReward
+		}
+	}
+
+	snapshot := make([]*validatorReward, 0, len(stakers))
+	for txID, potentialReward := range stakers {
+		tx, _, err := vm.internalState.GetTx(txID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get staker tx %s: %w", txID, err)
+		}
+		snapshot = append(snapshot, &validatorReward{
+			addStakerTx:     tx,
+			potentialReward: potentialReward,
+		})
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		iTxID := snapshot[i].addStakerTx.ID()
+		jTxID := snapshot[j].addStakerTx.ID()
+		return bytes.Compare(iTxID[:], jTxID[:]) < 0
+	})
+	return snapshot, nil
+}
+
 // GetCurrentHeight returns the height of the last accepted block
 func (vm *VM) GetCurrentHeight() (uint64, error) {
 	lastAccepted, err := vm.getBlock(vm.lastAcceptedID)
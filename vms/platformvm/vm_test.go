@@ -21,6 +21,7 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/database/prefixdb"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/message"
+	"github.com/Toinounet21/avalanchego-mod/network/scoring"
 	"github.com/Toinounet21/avalanchego-mod/snow"
 	"github.com/Toinounet21/avalanchego-mod/snow/choices"
 	"github.com/Toinounet21/avalanchego-mod/snow/consensus/snowball"
@@ -1302,6 +1303,87 @@ func TestCreateChain(t *testing.T) {
 	}
 }
 
+// chainsRecorder records the chains it's asked to create
+type chainsRecorder struct {
+	chains.MockManager
+	created []chains.ChainParameters
+}
+
+func (r *chainsRecorder) CreateChain(chainParams chains.ChainParameters) {
+	r.created = append(r.created, chainParams)
+}
+
+func TestTrackSubnet(t *testing.T) {
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		vm.ctx.Lock.Lock()
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	recorder := &chainsRecorder{}
+	vm.Chains = recorder
+	vm.StakingEnabled = true
+
+	tx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		ids.ID{'t', 'e', 's', 't', 'v', 'm'},
+		nil,
+		"name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.blockBuilder.AddUnverifiedTx(tx); err != nil {
+		t.Fatal(err)
+	} else if blk, err := vm.BuildBlock(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	vm.ctx.Lock.Unlock()
+
+	if len(recorder.created) != 0 {
+		t.Fatal("shouldn't have created the chain of an untracked subnet")
+	}
+
+	if err := vm.TrackSubnet(testSubnet1.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.created) != 1 || recorder.created[0].ID != tx.ID() {
+		t.Fatalf("should've created the chain of the tracked subnet but created %v", recorder.created)
+	}
+	if _, ok := vm.Validators.GetValidators(testSubnet1.ID()); !ok {
+		t.Fatal("should've loaded the validators of the tracked subnet")
+	}
+
+	// Tracking the subnet again is a no-op
+	if err := vm.TrackSubnet(testSubnet1.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.created) != 1 {
+		t.Fatal("shouldn't have created the chain twice")
+	}
+
+	if err := vm.TrackSubnet(ids.GenerateTestID()); err == nil {
+		t.Fatal("shouldn't track an unknown subnet")
+	}
+
+	if err := vm.UntrackSubnet(testSubnet1.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if vm.WhitelistedSubnets.Contains(testSubnet1.ID()) {
+		t.Fatal("shouldn't track the untracked subnet")
+	}
+}
+
 // test where we:
 // 1) Create a subnet
 // 2) Add a validator to the subnet's pending validator set
@@ -2017,6 +2099,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		scoring.NewNoScorer(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -2074,6 +2157,8 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		Subnet:                         subnet,
 		AncestorsMaxContainersSent:     2000,
 		AncestorsMaxContainersReceived: 2000,
+		PeerScorer:                     scoring.NewNoScorer(),
+		Events:                         common.NewEventBus(logging.NoLog{}),
 		SharedCfg:                      &common.SharedConfig{},
 	}
 
@@ -2112,6 +2197,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		VM:            bootstrapConfig.VM,
 		Sender:        bootstrapConfig.Sender,
 		Validators:    vdrs,
+		Events:        bootstrapConfig.Events,
 		Params: snowball.Parameters{
 			K:                     1,
 			Alpha:                 1,
@@ -39,6 +39,7 @@ func (fx *Fx) Initialize(vmIntf interface{}) error {
 		c.RegisterType(&MintOperation{}),
 		c.RegisterType(&BurnOperation{}),
 		c.RegisterType(&Credential{}),
+		c.RegisterType(&TransferOperation{}),
 	)
 	return errs.Err
 }
@@ -61,6 +62,8 @@ func (fx *Fx) VerifyOperation(txIntf, opIntf, credIntf interface{}, utxosIntf []
 	case *MintOperation:
 		return fx.VerifyMintOperation(tx, op, cred, utxosIntf[0])
 	case *BurnOperation:
+		return fx.VerifyBurnOperation(tx, op, cred, utxosIntf[0])
+	case *TransferOperation:
 		return fx.VerifyTransferOperation(tx, op, cred, utxosIntf[0])
 	default:
 		return errWrongOperationType
@@ -85,7 +88,20 @@ func (fx *Fx) VerifyMintOperation(tx secp256k1fx.Tx, op *MintOperation, cred *Cr
 	}
 }
 
-func (fx *Fx) VerifyTransferOperation(tx secp256k1fx.Tx, op *BurnOperation, cred *Credential, utxoIntf interface{}) error {
+func (fx *Fx) VerifyBurnOperation(tx secp256k1fx.Tx, op *BurnOperation, cred *Credential, utxoIntf interface{}) error {
+	out, ok := utxoIntf.(*OwnedOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+
+	if err := verify.All(op, cred, out); err != nil {
+		return err
+	}
+
+	return fx.VerifyCredentials(tx, &op.Input, &cred.Credential, &out.OutputOwners)
+}
+
+func (fx *Fx) VerifyTransferOperation(tx secp256k1fx.Tx, op *TransferOperation, cred *Credential, utxoIntf interface{}) error {
 	out, ok := utxoIntf.(*OwnedOutput)
 	if !ok {
 		return errWrongUTXOType
@@ -4,14 +4,17 @@
 package propertyfx
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/Toinounet21/avalanchego-mod/codec"
 	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
 	"github.com/Toinounet21/avalanchego-mod/ids"
 	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
 	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
 	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
 )
 
@@ -308,7 +311,7 @@ func TestFxVerifyMintOperationInvalidGroupID(t *testing.T) {
 	}
 }
 
-func TestFxVerifyTransferOperation(t *testing.T) {
+func TestFxVerifyBurnOperation(t *testing.T) {
 	vm := secp256k1fx.TestVM{
 		Codec: linearcodec.NewDefault(),
 		Log:   logging.NoLog{},
@@ -344,7 +347,7 @@ func TestFxVerifyTransferOperation(t *testing.T) {
 	}
 }
 
-func TestFxVerifyTransferOperationWrongUTXO(t *testing.T) {
+func TestFxVerifyBurnOperationWrongUTXO(t *testing.T) {
 	vm := secp256k1fx.TestVM{
 		Codec: linearcodec.NewDefault(),
 		Log:   logging.NoLog{},
@@ -374,7 +377,7 @@ func TestFxVerifyTransferOperationWrongUTXO(t *testing.T) {
 	}
 }
 
-func TestFxVerifyTransferOperationFailedVerify(t *testing.T) {
+func TestFxVerifyBurnOperationFailedVerify(t *testing.T) {
 	vm := secp256k1fx.TestVM{
 		Codec: linearcodec.NewDefault(),
 		Log:   logging.NoLog{},
@@ -410,6 +413,201 @@ func TestFxVerifyTransferOperationFailedVerify(t *testing.T) {
 	}
 }
 
+func TestFxVerifyTransferOperation(t *testing.T) {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.CLK.Set(date)
+
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{
+		Bytes: txBytes,
+	}
+	cred := &Credential{Credential: secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}}
+	utxo := &OwnedOutput{OutputOwners: secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			addr,
+		},
+	}}
+	op := &TransferOperation{
+		Input: secp256k1fx.Input{
+			SigIndices: []uint32{0},
+		},
+		Output: OwnedOutput{OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.GenerateTestShortID(),
+			},
+		}},
+	}
+
+	utxos := []interface{}{utxo}
+	if err := fx.VerifyOperation(tx, op, cred, utxos); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferOperationWrongUTXO(t *testing.T) {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.CLK.Set(date)
+
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{
+		Bytes: txBytes,
+	}
+	cred := &Credential{Credential: secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}}
+	utxo := &MintOutput{OutputOwners: secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			addr,
+		},
+	}}
+	op := &TransferOperation{Input: secp256k1fx.Input{
+		SigIndices: []uint32{0},
+	}}
+
+	utxos := []interface{}{utxo}
+	if err := fx.VerifyOperation(tx, op, cred, utxos); err == nil {
+		t.Fatalf("VerifyOperation should have errored due to a mint output utxo")
+	}
+}
+
+func TestFxVerifyTransferOperationFailedVerify(t *testing.T) {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.CLK.Set(date)
+
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{
+		Bytes: txBytes,
+	}
+	cred := &Credential{Credential: secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}}
+	utxo := &OwnedOutput{OutputOwners: secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			addr,
+		},
+	}}
+	op := &TransferOperation{Input: secp256k1fx.Input{
+		SigIndices: []uint32{1, 0},
+	}}
+
+	utxos := []interface{}{utxo}
+	if err := fx.VerifyOperation(tx, op, cred, utxos); err == nil {
+		t.Fatalf("VerifyOperation should have errored due to an invalid input")
+	}
+}
+
+func TestFxVerifyTransferOperationWrongOwner(t *testing.T) {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.CLK.Set(date)
+
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{
+		Bytes: txBytes,
+	}
+	cred := &Credential{Credential: secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}}
+	utxo := &OwnedOutput{OutputOwners: secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.GenerateTestShortID(),
+		},
+	}}
+	op := &TransferOperation{Input: secp256k1fx.Input{
+		SigIndices: []uint32{0},
+	}}
+
+	utxos := []interface{}{utxo}
+	if err := fx.VerifyOperation(tx, op, cred, utxos); err == nil {
+		t.Fatalf("VerifyOperation should have errored due to a signature from the wrong owner")
+	}
+}
+
+func TestFxTransferOperationSerialization(t *testing.T) {
+	c := linearcodec.NewDefault()
+	vm := secp256k1fx.TestVM{
+		Codec: c,
+		Log:   logging.NoLog{},
+	}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	m := codec.NewDefaultManager()
+	if err := m.RegisterCodec(0, c); err != nil {
+		t.Fatal(err)
+	}
+
+	var op verify.Verifiable = &TransferOperation{
+		Input: secp256k1fx.Input{
+			SigIndices: []uint32{0},
+		},
+		Output: OwnedOutput{OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				addr,
+			},
+		}},
+	}
+	opBytes, err := m.Marshal(0, &op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsedOp verify.Verifiable
+	if _, err := m.Unmarshal(opBytes, &parsedOp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(op, parsedOp) {
+		t.Fatalf("expected %v but got %v", op, parsedOp)
+	}
+}
+
 func TestFxVerifyOperationUnknownOperation(t *testing.T) {
 	vm := secp256k1fx.TestVM{
 		Codec: linearcodec.NewDefault(),
@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package propertyfx
+
+import (
+	"errors"
+
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+var errNilTransferOperation = errors.New("nil transfer operation")
+
+// TransferOperation reassigns the ownership of a property to [Output]
+type TransferOperation struct {
+	Input  secp256k1fx.Input `serialize:"true" json:"input"`
+	Output OwnedOutput       `serialize:"true" json:"output"`
+}
+
+func (op *TransferOperation) InitCtx(ctx *snow.Context) {
+	op.Output.OutputOwners.InitCtx(ctx)
+}
+
+func (op *TransferOperation) Cost() (uint64, error) {
+	return op.Input.Cost()
+}
+
+func (op *TransferOperation) Outs() []verify.State {
+	return []verify.State{&op.Output}
+}
+
+func (op *TransferOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilTransferOperation
+	default:
+		return verify.All(&op.Input, &op.Output)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package propertyfx
+
+import (
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+	"github.com/Toinounet21/avalanchego-mod/vms/secp256k1fx"
+)
+
+func TestTransferOperationVerifyNil(t *testing.T) {
+	op := (*TransferOperation)(nil)
+	if err := op.Verify(); err == nil {
+		t.Fatalf("nil operation should have failed verification")
+	}
+}
+
+func TestTransferOperationInvalid(t *testing.T) {
+	op := TransferOperation{Input: secp256k1fx.Input{
+		SigIndices: []uint32{1, 0},
+	}}
+	if err := op.Verify(); err == nil {
+		t.Fatalf("operation should have failed verification")
+	}
+}
+
+func TestTransferOperationInvalidOutput(t *testing.T) {
+	op := TransferOperation{Output: OwnedOutput{OutputOwners: secp256k1fx.OutputOwners{
+		Threshold: 2,
+		Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+	}}}
+	if err := op.Verify(); err == nil {
+		t.Fatalf("operation should have failed verification")
+	}
+}
+
+func TestTransferOperationOuts(t *testing.T) {
+	op := TransferOperation{
+		Output: OwnedOutput{},
+	}
+	if outs := op.Outs(); len(outs) != 1 {
+		t.Fatalf("Wrong number of outputs returned")
+	}
+}
+
+func TestTransferOperationState(t *testing.T) {
+	intf := interface{}(&TransferOperation{})
+	if _, ok := intf.(verify.State); ok {
+		t.Fatalf("shouldn't be marked as state")
+	}
+}
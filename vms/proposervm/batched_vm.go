@@ -4,6 +4,7 @@
 package proposervm
 
 import (
+	"context"
 	"time"
 
 	"github.com/Toinounet21/avalanchego-mod/database"
@@ -19,6 +20,7 @@ import (
 var _ block.BatchedChainVM = &VM{}
 
 func (vm *VM) GetAncestors(
+	ctx context.Context,
 	blkID ids.ID,
 	maxBlocksNum int,
 	maxBlocksSize int,
@@ -48,8 +50,8 @@ func (vm *VM) GetAncestors(
 		// the size of the message is included with each container, and the size
 		// is repr. by an int.
 		currentByteLength += wrappers.IntLen + len(blkBytes)
-		if len(res) > 0 && (currentByteLength >= maxBlocksSize || maxBlocksRetrivalTime <= time.Since(startTime)) {
-			return res, nil // reached maximum size or ran out of time
+		if len(res) > 0 && (currentByteLength >= maxBlocksSize || maxBlocksRetrivalTime <= time.Since(startTime) || ctx.Err() != nil) {
+			return res, nil // reached maximum size, ran out of time or was cancelled
 		}
 
 		res = append(res, blkBytes)
@@ -65,7 +67,7 @@ func (vm *VM) GetAncestors(
 	preMaxBlocksNum := maxBlocksNum - len(res)
 	preMaxBlocksSize := maxBlocksSize - currentByteLength
 	preMaxBlocksRetrivalTime := maxBlocksRetrivalTime - time.Since(startTime)
-	innerBytes, err := rVM.GetAncestors(blkID, preMaxBlocksNum, preMaxBlocksSize, preMaxBlocksRetrivalTime)
+	innerBytes, err := rVM.GetAncestors(ctx, blkID, preMaxBlocksNum, preMaxBlocksSize, preMaxBlocksRetrivalTime)
 	if err != nil {
 		if len(res) == 0 {
 			return nil, err
@@ -76,7 +78,7 @@ func (vm *VM) GetAncestors(
 	return res, nil
 }
 
-func (vm *VM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
+func (vm *VM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error) {
 	rVM, ok := vm.ChainVM.(block.BatchedChainVM)
 	if !ok {
 		return nil, block.ErrRemoteVMNotImplemented
@@ -117,7 +119,7 @@ func (vm *VM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
 	innerBlockBytes = append(innerBlockBytes, blks[blocksIndex:]...)
 
 	// parse all inner blocks at once
-	innerBlks, err := rVM.BatchedParseBlock(innerBlockBytes)
+	innerBlks, err := rVM.BatchedParseBlock(ctx, innerBlockBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ package proposervm
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"fmt"
 	"testing"
@@ -35,11 +36,11 @@ func TestCoreVMNotRemote(t *testing.T) {
 	maxBlocksNum := 1000                            // an high value to get all built blocks
 	maxBlocksSize := 1000000                        // an high value to get all built blocks
 	maxBlocksRetrivalTime := time.Duration(1000000) // an high value to get all built blocks
-	_, errAncestors := proVM.GetAncestors(blkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	_, errAncestors := proVM.GetAncestors(context.Background(), blkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.Error(errAncestors)
 
 	var blks [][]byte
-	_, errBatchedParse := proVM.BatchedParseBlock(blks)
+	_, errBatchedParse := proVM.BatchedParseBlock(context.Background(), blks)
 	assert.Error(errBatchedParse)
 }
 
@@ -141,7 +142,7 @@ func TestGetAncestorsPreForkOnly(t *testing.T) {
 	maxBlocksNum := 1000                            // an high value to get all built blocks
 	maxBlocksSize := 1000000                        // an high value to get all built blocks
 	maxBlocksRetrivalTime := time.Duration(1000000) // an high value to get all built blocks
-	res, err := proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err := proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 
 	// ... and check returned values are as expected
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
@@ -152,14 +153,14 @@ func TestGetAncestorsPreForkOnly(t *testing.T) {
 
 	// another good call
 	reqBlkID = builtBlk1.ID()
-	res, err = proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err = proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
 	assert.Len(res, 1, "GetAncestor returned %v entries instead of %v", len(res), 1)
 	assert.EqualValues(res[0], builtBlk1.Bytes())
 
 	// a faulty call
 	reqBlkID = ids.Empty
-	res, err = proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err = proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
 	assert.Empty(res, "GetAncestor returned %v entries instead of %v", len(res), 0)
 }
@@ -268,7 +269,7 @@ func TestGetAncestorsPostForkOnly(t *testing.T) {
 	maxBlocksNum := 1000                            // an high value to get all built blocks
 	maxBlocksSize := 1000000                        // an high value to get all built blocks
 	maxBlocksRetrivalTime := time.Duration(1000000) // an high value to get all built blocks
-	res, err := proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err := proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 
 	// ... and check returned values are as expected
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
@@ -279,14 +280,14 @@ func TestGetAncestorsPostForkOnly(t *testing.T) {
 
 	// another good call
 	reqBlkID = builtBlk1.ID()
-	res, err = proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err = proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
 	assert.Len(res, 1, "GetAncestor returned %v entries instead of %v", len(res), 1)
 	assert.EqualValues(res[0], builtBlk1.Bytes())
 
 	// a faulty call
 	reqBlkID = ids.Empty
-	res, err = proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err = proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
 	assert.Empty(res, "GetAncestor returned %v entries instead of %v", len(res), 0)
 }
@@ -431,7 +432,7 @@ func TestGetAncestorsAtSnomanPlusPlusFork(t *testing.T) {
 	maxBlocksNum := 1000                      // an high value to get all built blocks
 	maxBlocksSize := 1000000                  // an high value to get all built blocks
 	maxBlocksRetrivalTime := 10 * time.Minute // an high value to get all built blocks
-	res, err := proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err := proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 
 	// ... and check returned values are as expected
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
@@ -443,14 +444,14 @@ func TestGetAncestorsAtSnomanPlusPlusFork(t *testing.T) {
 
 	// another good call
 	reqBlkID = builtBlk1.ID()
-	res, err = proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err = proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
 	assert.Len(res, 1, "GetAncestor returned %v entries instead of %v", len(res), 1)
 	assert.EqualValues(res[0], builtBlk1.Bytes())
 
 	// a faulty call
 	reqBlkID = ids.Empty
-	res, err = proRemoteVM.GetAncestors(reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
+	res, err = proRemoteVM.GetAncestors(context.Background(), reqBlkID, maxBlocksNum, maxBlocksSize, maxBlocksRetrivalTime)
 	assert.NoError(err, "Error calling GetAncestors: %v", err)
 	assert.Len(res, 0, "GetAncestor returned %v entries instead of %v", len(res), 0)
 }
@@ -559,7 +560,7 @@ func TestBatchedParseBlockPreForkOnly(t *testing.T) {
 		builtBlk2.Bytes(),
 		builtBlk3.Bytes(),
 	}
-	res, err := proRemoteVM.BatchedParseBlock(bytesToParse)
+	res, err := proRemoteVM.BatchedParseBlock(context.Background(), bytesToParse)
 	assert.NoError(err, "Error calling BatchedParseBlock: %v", err)
 	assert.Len(res, 3, "BatchedParseBlock returned %v entries instead of %v", len(res), 3)
 	assert.Equal(res[0].ID(), builtBlk1.ID())
@@ -659,7 +660,7 @@ func TestBatchedParseBlockPostForkOnly(t *testing.T) {
 		builtBlk2.Bytes(),
 		builtBlk3.Bytes(),
 	}
-	res, err := proRemoteVM.BatchedParseBlock(bytesToParse)
+	res, err := proRemoteVM.BatchedParseBlock(context.Background(), bytesToParse)
 	assert.NoError(err, "Error calling BatchedParseBlock: %v", err)
 	assert.Len(res, 3, "BatchedParseBlock returned %v entries instead of %v", len(res), 3)
 	assert.Equal(res[0].ID(), builtBlk1.ID())
@@ -813,7 +814,7 @@ func TestBatchedParseBlockAtSnomanPlusPlusFork(t *testing.T) {
 		builtBlk1.Bytes(),
 	}
 
-	res, err := proRemoteVM.BatchedParseBlock(bytesToParse)
+	res, err := proRemoteVM.BatchedParseBlock(context.Background(), bytesToParse)
 	assert.NoError(err, "Error calling BatchedParseBlock: %v", err)
 	assert.Len(res, 4, "BatchedParseBlock returned %v entries instead of %v", len(res), 4)
 	assert.Equal(res[0].ID(), builtBlk4.ID())
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vms
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+// RegistryEntry describes how a VM is run, and the names it's known by
+type RegistryEntry struct {
+	// Plugin is the path of the plugin binary that runs the VM. Relative paths
+	// are relative to the plugin directory. If empty, the VM must already be
+	// registered, for instance because it's built into the node.
+	Plugin string `json:"plugin,omitempty"`
+
+	// Aliases of the VM
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Registry persists the VMs registered while the node is running, so that they
+// can be registered again when the node restarts.
+type Registry struct {
+	db database.Database
+}
+
+// NewRegistry returns a registry that persists its entries in [db]
+func NewRegistry(db database.Database) *Registry {
+	return &Registry{db: db}
+}
+
+// Get returns the entry of [vmID], or database.ErrNotFound if there is none
+func (r *Registry) Get(vmID ids.ID) (RegistryEntry, error) {
+	entryBytes, err := r.db.Get(vmID[:])
+	if err != nil {
+		return RegistryEntry{}, err
+	}
+	entry := RegistryEntry{}
+	if err := json.Unmarshal(entryBytes, &entry); err != nil {
+		return RegistryEntry{}, fmt.Errorf("couldn't parse the registry entry of %s: %w", vmID, err)
+	}
+	return entry, nil
+}
+
+// Put replaces the entry of [vmID]
+func (r *Registry) Put(vmID ids.ID, entry RegistryEntry) error {
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return r.db.Put(vmID[:], entryBytes)
+}
+
+// List returns all of the entries of the registry
+func (r *Registry) List() (map[ids.ID]RegistryEntry, error) {
+	it := r.db.NewIterator()
+	defer it.Release()
+
+	entries := make(map[ids.ID]RegistryEntry)
+	for it.Next() {
+		vmID, err := ids.ToID(it.Key())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse registered VM ID: %w", err)
+		}
+		entry := RegistryEntry{}
+		if err := json.Unmarshal(it.Value(), &entry); err != nil {
+			return nil, fmt.Errorf("couldn't parse the registry entry of %s: %w", vmID, err)
+		}
+		entries[vmID] = entry
+	}
+	return entries, it.Error()
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/database"
+	"github.com/Toinounet21/avalanchego-mod/database/memdb"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	registry := NewRegistry(db)
+
+	vmID := ids.GenerateTestID()
+	_, err := registry.Get(vmID)
+	assert.Equal(database.ErrNotFound, err)
+
+	entry := RegistryEntry{
+		Plugin:  "subnet-evm-v2",
+		Aliases: []string{"subnetevm2"},
+	}
+	assert.NoError(registry.Put(vmID, entry))
+
+	// Entries are persisted in the database
+	registry = NewRegistry(db)
+	got, err := registry.Get(vmID)
+	assert.NoError(err)
+	assert.Equal(entry, got)
+
+	entries, err := registry.List()
+	assert.NoError(err)
+	assert.Equal(map[ids.ID]RegistryEntry{vmID: entry}, entries)
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/messenger"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
+)
+
+// openEvents opens the event stream with the plugin. The plugin pushes the
+// messages for the consensus engine over it, and the node pushes a notification
+// when it's ready to issue a newly built block.
+func (vm *VMClient) openEvents() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := vm.client.Events(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	vm.events = stream
+	vm.cancelEvents = cancel
+	go vm.ctx.Log.RecoverAndPanic(func() { vm.receiveEvents(stream) })
+	return nil
+}
+
+// receiveEvents delivers the messages pushed by the plugin to the consensus
+// engine until the stream is closed
+func (vm *VMClient) receiveEvents(stream vmproto.VM_EventsClient) {
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return
+		}
+		msg := common.Message(event.Message)
+		select {
+		case vm.toEngine <- msg:
+		default:
+			vm.ctx.Log.Debug("dropping %s from the plugin because the message queue is full", msg)
+		}
+	}
+}
+
+// closeEvents closes the event stream, if it's open
+func (vm *VMClient) closeEvents() {
+	if vm.cancelEvents == nil {
+		return
+	}
+	vm.cancelEvents()
+	vm.cancelEvents = nil
+	vm.events = nil
+}
+
+// notifyReadyToBuild tells the plugin that a newly built block can be issued,
+// if every block that was verified has been decided.
+//
+// Assumes the context lock is held.
+func (vm *VMClient) notifyReadyToBuild() {
+	if vm.events == nil || vm.processing.Len() != 0 {
+		return
+	}
+	if err := vm.events.Send(&vmproto.NodeEvent{BuildBlock: true}); err != nil {
+		vm.ctx.Log.Debug("failed to notify the plugin that a block can be built: %s", err)
+	}
+}
+
+func (vm *VMServer) Events(stream vmproto.VM_EventsServer) error {
+	vm.eventsLock.Lock()
+	vm.events = stream
+	vm.eventsLock.Unlock()
+
+	defer func() {
+		vm.eventsLock.Lock()
+		if vm.events == stream {
+			vm.events = nil
+		}
+		vm.eventsLock.Unlock()
+	}()
+
+	notifiee, _ := vm.vm.(block.BuildBlockNotifiee)
+	for {
+		event, err := stream.Recv()
+		switch {
+		case err == io.EOF, status.Code(err) == codes.Canceled:
+			return nil
+		case err != nil:
+			return err
+		}
+		if event.BuildBlock && notifiee != nil {
+			notifiee.ReadyToBuild()
+		}
+	}
+}
+
+// notify delivers [msg] to the consensus engine. It's pushed over the event
+// stream if the node opened one, and sent to the messenger otherwise.
+func (vm *VMServer) notify(msgClient *messenger.Client, msg common.Message) error {
+	vm.eventsLock.Lock()
+	events := vm.events
+	vm.eventsLock.Unlock()
+
+	if events != nil {
+		if err := events.Send(&vmproto.PluginEvent{Message: uint32(msg)}); err == nil {
+			return nil
+		}
+	}
+	return msgClient.Notify(msg)
+}
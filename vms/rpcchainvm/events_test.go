@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/common"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/utils/linkedhashmap"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
+)
+
+const bufSize = 1024 * 1024
+
+type notifieeVM struct {
+	block.TestVM
+	ready chan struct{}
+}
+
+func (vm *notifieeVM) ReadyToBuild() { vm.ready <- struct{}{} }
+
+func TestEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	listener := bufconn.Listen(bufSize)
+	vm := &notifieeVM{ready: make(chan struct{}, 1)}
+	vmServer := NewServer(vm, nil)
+	server := grpc.NewServer()
+	vmproto.RegisterVMServer(server, vmServer)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Logf("Server exited with error: %v", err)
+		}
+	}()
+	defer server.Stop()
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+
+	toEngine := make(chan common.Message, 1)
+	vmClient := NewClient(vmproto.NewVMClient(conn), nil)
+	vmClient.ctx = snow.DefaultContextTest()
+	vmClient.toEngine = toEngine
+	vmClient.processing = linkedhashmap.New()
+
+	assert.NoError(vmClient.openEvents())
+	hasEvents := func() bool {
+		vmServer.eventsLock.Lock()
+		defer vmServer.eventsLock.Unlock()
+		return vmServer.events != nil
+	}
+	assert.Eventually(hasEvents, time.Second, 10*time.Millisecond)
+
+	// The plugin pushes its messages over the stream, so the messenger isn't
+	// used
+	assert.NoError(vmServer.notify(nil, common.PendingTxs))
+	select {
+	case msg := <-toEngine:
+		assert.Equal(common.PendingTxs, msg)
+	case <-time.After(time.Second):
+		t.Fatal("the message of the plugin wasn't delivered")
+	}
+
+	// The plugin isn't notified while a block is processing
+	vmClient.processing.Put(ids.GenerateTestID(), nil)
+	vmClient.notifyReadyToBuild()
+	vmClient.processing = linkedhashmap.New()
+
+	vmClient.notifyReadyToBuild()
+	select {
+	case <-vm.ready:
+	case <-time.After(time.Second):
+		t.Fatal("the plugin wasn't notified that a block can be built")
+	}
+	select {
+	case <-vm.ready:
+		t.Fatal("the plugin was notified while a block was processing")
+	default:
+	}
+
+	vmClient.closeEvents()
+	assert.Eventually(func() bool { return !hasEvents() }, time.Second, 10*time.Millisecond)
+}
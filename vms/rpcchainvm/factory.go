@@ -37,9 +37,25 @@ var (
 
 type Factory struct {
 	Path string
+
+	// Supervisor configures the health checks of the plugin processes, and
+	// their restart after a crash
+	Supervisor SupervisorConfig
 }
 
 func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
+	vm, err := f.launch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vm.factory = f
+	vm.ctx = ctx
+	return vm, nil
+}
+
+// launch starts a new plugin process and performs the rpcchainvm handshake
+// with it.
+func (f *Factory) launch(ctx *snow.Context) (*VMClient, error) {
 	config := &plugin.ClientConfig{
 		HandshakeConfig: Handshake,
 		Plugins:         PluginMap,
@@ -94,23 +110,72 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 		return nil, errWrongVM
 	}
 
+	if err := vm.handshake(); err != nil {
+		client.Kill()
+		return nil, err
+	}
+
 	vm.SetProcess(client)
-	vm.ctx = ctx
 	return vm, nil
 }
 
+// RegisterVM registers the VM [vmID] as described by [entry]. If [entry] names
+// a plugin, the VM is run by that plugin, which is supervised according to
+// [supervisor].
+func RegisterVM(pluginDir string, manager vms.Manager, vmID ids.ID, entry vms.RegistryEntry, supervisor SupervisorConfig) error {
+	if entry.Plugin != "" {
+		path := entry.Plugin
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(pluginDir, path)
+		}
+		err := manager.RegisterFactory(vmID, &Factory{
+			Path:       path,
+			Supervisor: supervisor,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, alias := range entry.Aliases {
+		if err := manager.Alias(vmID, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterVMs registers each of the VMs in [registry]
+func RegisterVMs(pluginDir string, manager vms.Manager, registry map[ids.ID]vms.RegistryEntry, supervisor SupervisorConfig) error {
+	for vmID, entry := range registry {
+		if err := RegisterVM(pluginDir, manager, vmID, entry, supervisor); err != nil {
+			return fmt.Errorf("couldn't register VM %s: %w", vmID, err)
+		}
+	}
+	return nil
+}
+
 // RegisterPlugins iterates over a given plugin dir and registers rpcchain VMs
-// for each of the discovered plugins.
-func RegisterPlugins(pluginDir string, manager vms.Manager) error {
+// for each of the discovered plugins. The plugins are supervised according to
+// [supervisor]. Plugins that already run a registered VM are skipped.
+func RegisterPlugins(pluginDir string, manager vms.Manager, supervisor SupervisorConfig) error {
 	files, err := ioutil.ReadDir(pluginDir)
 	if err != nil {
 		return err
 	}
 
+	registeredPlugins, err := registeredPluginPaths(manager)
+	if err != nil {
+		return err
+	}
+
 	for _, file := range files {
 		if file.IsDir() {
 			continue
 		}
+		if registeredPlugins[filepath.Join(pluginDir, file.Name())] {
+			continue
+		}
 
 		nameWithExtension := file.Name()
 		// Strip any extension from the file. This is to support windows .exe
@@ -146,7 +211,8 @@ func RegisterPlugins(pluginDir string, manager vms.Manager) error {
 		err = manager.RegisterFactory(
 			vmID,
 			&Factory{
-				Path: filepath.Join(pluginDir, file.Name()),
+				Path:       filepath.Join(pluginDir, file.Name()),
+				Supervisor: supervisor,
 			},
 		)
 		if err != nil {
@@ -155,3 +221,24 @@ func RegisterPlugins(pluginDir string, manager vms.Manager) error {
 	}
 	return nil
 }
+
+// registeredPluginPaths returns the paths of the plugins that run the VMs
+// registered in [manager]
+func registeredPluginPaths(manager vms.Manager) (map[string]bool, error) {
+	vmIDs, err := manager.ListFactories()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool, len(vmIDs))
+	for _, vmID := range vmIDs {
+		factory, err := manager.GetFactory(vmID)
+		if err != nil {
+			return nil, err
+		}
+		if factory, ok := factory.(*Factory); ok {
+			paths[filepath.Clean(factory.Path)] = true
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
+)
+
+const (
+	// ProtocolVersion is the version of the rpcchainvm protocol implemented by
+	// this package. It must be bumped whenever the VM service changes in a way
+	// that older nodes or plugins can't understand.
+	ProtocolVersion uint32 = 1
+
+	// MinProtocolVersion is the oldest rpcchainvm protocol version that this
+	// package can interoperate with.
+	MinProtocolVersion uint32 = 1
+)
+
+// Optional protocol features. A feature is only used if both the node and the
+// plugin support it.
+const (
+	FeatureGetAncestors      = "getAncestors"
+	FeatureBatchedParseBlock = "batchedParseBlock"
+	FeatureConfigReload      = "configReload"
+	FeatureEvents            = "events"
+
+	FeatureAppGossipValidators = "appGossipValidators"
+)
+
+var (
+	// ErrIncompatibleProtocol is returned when the node and the plugin can't
+	// agree on an rpcchainvm protocol version.
+	ErrIncompatibleProtocol = errors.New("incompatible rpcchainvm protocol")
+
+	errNoHandshake = errors.New("the node didn't perform the rpcchainvm handshake before initializing the plugin")
+
+	// supportedFeatures are the optional protocol features implemented by this
+	// package
+	supportedFeatures = []string{
+		FeatureGetAncestors,
+		FeatureBatchedParseBlock,
+		FeatureConfigReload,
+		FeatureEvents,
+		FeatureAppGossipValidators,
+	}
+)
+
+// checkProtocolVersion returns an error if the remote side, which speaks
+// [remoteVersion] and supports versions as old as [remoteMinVersion], can't
+// interoperate with this package.
+func checkProtocolVersion(remote string, remoteVersion, remoteMinVersion uint32) error {
+	switch {
+	case remoteVersion < MinProtocolVersion:
+		return fmt.Errorf("%w: the %s speaks protocol version %d but version %d or later is required",
+			ErrIncompatibleProtocol, remote, remoteVersion, MinProtocolVersion)
+	case ProtocolVersion < remoteMinVersion:
+		return fmt.Errorf("%w: the %s requires protocol version %d or later but version %d is spoken",
+			ErrIncompatibleProtocol, remote, remoteMinVersion, ProtocolVersion)
+	default:
+		return nil
+	}
+}
+
+// negotiateFeatures returns the features that are supported both by this
+// package and by the remote side.
+func negotiateFeatures(remoteFeatures []string) map[string]bool {
+	supported := make(map[string]bool, len(supportedFeatures))
+	for _, feature := range supportedFeatures {
+		supported[feature] = true
+	}
+	negotiated := make(map[string]bool, len(remoteFeatures))
+	for _, feature := range remoteFeatures {
+		if supported[feature] {
+			negotiated[feature] = true
+		}
+	}
+	return negotiated
+}
+
+// handshakeError converts the error returned by the plugin's handshake into an
+// error that explains the version skew.
+func handshakeError(err error) error {
+	switch status.Code(err) {
+	case codes.Unimplemented:
+		return fmt.Errorf("%w: the plugin doesn't implement the rpcchainvm handshake and was likely built against an older version of avalanchego",
+			ErrIncompatibleProtocol)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: the plugin rejected the handshake: %s",
+			ErrIncompatibleProtocol, status.Convert(err).Message())
+	default:
+		return fmt.Errorf("rpcchainvm handshake failed: %w", err)
+	}
+}
+
+func newHandshakeRequest() *vmproto.HandshakeRequest {
+	return &vmproto.HandshakeRequest{
+		ProtocolVersion:    ProtocolVersion,
+		MinProtocolVersion: MinProtocolVersion,
+		Features:           supportedFeatures,
+	}
+}
+
+func newHandshakeResponse() *vmproto.HandshakeResponse {
+	return &vmproto.HandshakeResponse{
+		ProtocolVersion:    ProtocolVersion,
+		MinProtocolVersion: MinProtocolVersion,
+		Features:           supportedFeatures,
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
+)
+
+func TestCheckProtocolVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(checkProtocolVersion("plugin", ProtocolVersion, MinProtocolVersion))
+
+	err := checkProtocolVersion("plugin", MinProtocolVersion-1, MinProtocolVersion-1)
+	assert.ErrorIs(err, ErrIncompatibleProtocol)
+
+	err = checkProtocolVersion("plugin", ProtocolVersion+1, ProtocolVersion+1)
+	assert.ErrorIs(err, ErrIncompatibleProtocol)
+}
+
+func TestNegotiateFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	features := negotiateFeatures([]string{FeatureGetAncestors, "unknownFeature"})
+	assert.Equal(map[string]bool{FeatureGetAncestors: true}, features)
+
+	features = negotiateFeatures(nil)
+	assert.Empty(features)
+	assert.NotNil(features)
+}
+
+func TestHandshakeError(t *testing.T) {
+	assert := assert.New(t)
+
+	err := handshakeError(status.Error(codes.Unimplemented, "method Handshake not implemented"))
+	assert.ErrorIs(err, ErrIncompatibleProtocol)
+
+	err = handshakeError(status.Error(codes.FailedPrecondition, "too old"))
+	assert.ErrorIs(err, ErrIncompatibleProtocol)
+	assert.Contains(err.Error(), "too old")
+
+	errUnavailable := errors.New("connection refused")
+	err = handshakeError(errUnavailable)
+	assert.ErrorIs(err, errUnavailable)
+	assert.NotErrorIs(err, ErrIncompatibleProtocol)
+}
+
+func TestServerHandshake(t *testing.T) {
+	assert := assert.New(t)
+
+	server := NewServer(nil, nil)
+	_, err := server.Initialize(context.Background(), &vmproto.InitializeRequest{})
+	assert.ErrorIs(err, errNoHandshake)
+
+	_, err = server.Handshake(context.Background(), &vmproto.HandshakeRequest{
+		ProtocolVersion:    ProtocolVersion + 1,
+		MinProtocolVersion: ProtocolVersion + 1,
+	})
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+
+	resp, err := server.Handshake(context.Background(), newHandshakeRequest())
+	assert.NoError(err)
+	assert.Equal(ProtocolVersion, resp.ProtocolVersion)
+	assert.Equal(MinProtocolVersion, resp.MinProtocolVersion)
+	assert.ElementsMatch(supportedFeatures, resp.Features)
+}
@@ -5,6 +5,7 @@ package rpcchainvm
 
 import (
 	"context"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -13,12 +14,27 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// gatherTimeout is how long the plugin has to report its metrics
+const gatherTimeout = 5 * time.Second
+
 var _ prometheus.Gatherer = &VMClient{}
 
+// Gather returns the metrics of the plugin. If the plugin doesn't report them
+// in time, no plugin metrics are returned so that the metrics of the rest of
+// the node can still be scraped.
 func (vm *VMClient) Gather() ([]*dto.MetricFamily, error) {
-	resp, err := vm.client.Gather(context.Background(), &emptypb.Empty{})
+	// Metrics are gathered without holding the context lock
+	vm.clientLock.RLock()
+	client := vm.client
+	vm.clientLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), gatherTimeout)
+	defer cancel()
+	resp, err := client.Gather(ctx, &emptypb.Empty{})
 	if err != nil {
-		return nil, err
+		vm.gatherFailures.Inc()
+		vm.ctx.Log.Warn("failed to gather the metrics of the plugin: %s", err)
+		return nil, nil
 	}
 	return resp.MetricFamilies, nil
 }
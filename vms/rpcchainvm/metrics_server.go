@@ -6,12 +6,37 @@ package rpcchainvm
 import (
 	"context"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/Toinounet21/avalanchego-mod/api/metrics"
 	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
 )
 
+// pluginMetricsNamespace prefixes the metrics of the plugin process itself, to
+// distinguish them from the metrics of the node process
+const pluginMetricsNamespace = "plugin"
+
+// newPluginGatherer returns a gatherer of the metrics registered by the VM and
+// of the runtime metrics of the plugin process.
+func newPluginGatherer(vmMetrics prometheus.Gatherer) (metrics.MultiGatherer, error) {
+	processMetrics := prometheus.NewRegistry()
+	if err := processMetrics.Register(prometheus.NewGoCollector()); err != nil {
+		return nil, err
+	}
+	if err := processMetrics.Register(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{})); err != nil {
+		return nil, err
+	}
+
+	gatherer := metrics.NewMultiGatherer()
+	if err := gatherer.Register("", vmMetrics); err != nil {
+		return nil, err
+	}
+	return gatherer, gatherer.Register(pluginMetricsNamespace, processMetrics)
+}
+
 func (vm *VMServer) Gather(context.Context, *emptypb.Empty) (*vmproto.GatherResponse, error) {
-	mfs, err := vm.ctx.Metrics.Gather()
+	mfs, err := vm.gatherer.Gather()
 	return &vmproto.GatherResponse{MetricFamilies: mfs}, err
 }
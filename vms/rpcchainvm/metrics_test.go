@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
+)
+
+func TestPluginGatherer(t *testing.T) {
+	assert := assert.New(t)
+
+	vmMetrics := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "blocks",
+		Help: "blocks",
+	})
+	assert.NoError(vmMetrics.Register(counter))
+
+	gatherer, err := newPluginGatherer(vmMetrics)
+	assert.NoError(err)
+
+	mfs, err := gatherer.Gather()
+	assert.NoError(err)
+
+	names := make(map[string]bool, len(mfs))
+	for _, mf := range mfs {
+		names[mf.GetName()] = true
+	}
+	assert.True(names["blocks"])
+	assert.True(names["plugin_go_goroutines"])
+}
+
+type failingGatherVMClient struct {
+	vmproto.VMClient
+}
+
+func (failingGatherVMClient) Gather(context.Context, *emptypb.Empty, ...grpc.CallOption) (*vmproto.GatherResponse, error) {
+	return nil, errors.New("plugin is gone")
+}
+
+// A plugin that fails to report its metrics doesn't fail the whole scrape
+func TestGatherFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	vm := NewClient(failingGatherVMClient{}, nil)
+	vm.ctx = snow.DefaultContextTest()
+	vm.gatherFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_gather_failures",
+	})
+
+	mfs, err := vm.Gather()
+	assert.NoError(err)
+	assert.Empty(mfs)
+}
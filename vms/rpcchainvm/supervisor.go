@@ -0,0 +1,331 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/ghttp"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/ghttp/ghttpproto"
+)
+
+var (
+	errPluginExited       = errors.New("plugin process exited")
+	errPluginNotServing   = errors.New("plugin isn't serving")
+	errVMClosed           = errors.New("vm is shut down")
+	errLastAcceptedDiffer = errors.New("restarted plugin has a different last accepted block")
+
+	// DefaultSupervisorConfig health checks plugins every 10 seconds and
+	// restarts them after they crash or fail 3 consecutive health checks.
+	DefaultSupervisorConfig = SupervisorConfig{
+		HealthCheckFrequency:   10 * time.Second,
+		HealthCheckTimeout:     5 * time.Second,
+		MaxHealthCheckFailures: 3,
+		InitialRestartBackoff:  time.Second,
+		MaxRestartBackoff:      time.Minute,
+	}
+)
+
+// SupervisorConfig configures how the node monitors the plugin processes of
+// its chains
+type SupervisorConfig struct {
+	// HealthCheckFrequency is how often the plugin process is checked. If 0,
+	// plugins aren't supervised and a crashed plugin isn't restarted.
+	HealthCheckFrequency time.Duration `json:"healthCheckFrequency"`
+
+	// HealthCheckTimeout is how long the plugin has to answer a gRPC health
+	// check
+	HealthCheckTimeout time.Duration `json:"healthCheckTimeout"`
+
+	// MaxHealthCheckFailures is the number of consecutive failed gRPC health
+	// checks after which a running plugin is restarted. A plugin whose process
+	// exited is restarted immediately.
+	MaxHealthCheckFailures int `json:"maxHealthCheckFailures"`
+
+	// InitialRestartBackoff is how long to wait before retrying a failed
+	// restart. The wait doubles after each failed attempt, up to
+	// MaxRestartBackoff.
+	InitialRestartBackoff time.Duration `json:"initialRestartBackoff"`
+	MaxRestartBackoff     time.Duration `json:"maxRestartBackoff"`
+}
+
+// Verify returns an error if the config is invalid
+func (c *SupervisorConfig) Verify() error {
+	switch {
+	case c.HealthCheckFrequency < 0:
+		return errors.New("health check frequency must be >= 0")
+	case c.HealthCheckFrequency == 0:
+		return nil
+	case c.HealthCheckTimeout <= 0:
+		return errors.New("health check timeout must be > 0")
+	case c.MaxHealthCheckFailures <= 0:
+		return errors.New("max health check failures must be > 0")
+	case c.InitialRestartBackoff <= 0:
+		return errors.New("initial restart backoff must be > 0")
+	case c.MaxRestartBackoff < c.InitialRestartBackoff:
+		return errors.New("max restart backoff must be >= initial restart backoff")
+	default:
+		return nil
+	}
+}
+
+type supervisorMetrics struct {
+	healthCheckFailures,
+	restarts,
+	restartFailures prometheus.Counter
+}
+
+func (m *supervisorMetrics) Initialize(registerer prometheus.Registerer) error {
+	m.healthCheckFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_health_check_failures",
+		Help: "Number of failed health checks of the plugin process",
+	})
+	m.restarts = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_restarts",
+		Help: "Number of times the plugin process was restarted",
+	})
+	m.restartFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_restart_failures",
+		Help: "Number of failed attempts to restart the plugin process",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.healthCheckFailures),
+		registerer.Register(m.restarts),
+		registerer.Register(m.restartFailures),
+	)
+	return errs.Err
+}
+
+// pluginHandler is an HTTP handler served by the plugin. The underlying
+// handler is replaced when the plugin is restarted, so the handler that is
+// registered with the API server stays valid.
+type pluginHandler struct {
+	lock    sync.RWMutex
+	handler http.Handler
+}
+
+func (h *pluginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.lock.RLock()
+	handler := h.handler
+	h.lock.RUnlock()
+
+	handler.ServeHTTP(w, r)
+}
+
+func (h *pluginHandler) set(handler http.Handler) {
+	h.lock.Lock()
+	h.handler = handler
+	h.lock.Unlock()
+}
+
+// supervise health checks the plugin until the VM is shut down, restarting it
+// if it crashes or stops answering health checks.
+func (vm *VMClient) supervise() {
+	config := vm.factory.Supervisor
+	ticker := time.NewTicker(config.HealthCheckFrequency)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-vm.closed:
+			return
+		case <-ticker.C:
+		}
+
+		err := vm.checkPlugin()
+		if err == nil {
+			failures = 0
+			continue
+		}
+		vm.metrics.healthCheckFailures.Inc()
+		failures++
+
+		if !errors.Is(err, errPluginExited) && failures < config.MaxHealthCheckFailures {
+			vm.ctx.Log.Warn("plugin %s failed health check %d/%d: %s",
+				vm.factory.Path, failures, config.MaxHealthCheckFailures, err)
+			continue
+		}
+
+		vm.ctx.Log.Error("restarting plugin %s: %s", vm.factory.Path, err)
+		failures = 0
+		if !vm.restartWithBackoff() {
+			return
+		}
+	}
+}
+
+// checkPlugin returns an error if the plugin process exited or doesn't report
+// itself as serving.
+func (vm *VMClient) checkPlugin() error {
+	vm.clientLock.RLock()
+	proc, conn := vm.proc, vm.conn
+	vm.clientLock.RUnlock()
+
+	if proc.Exited() {
+		return errPluginExited
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), vm.factory.Supervisor.HealthCheckTimeout)
+	defer cancel()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: plugin.GRPCServiceName,
+	})
+	if err != nil {
+		return fmt.Errorf("gRPC health check failed: %w", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: status is %s", errPluginNotServing, resp.Status)
+	}
+	return nil
+}
+
+// restartWithBackoff restarts the plugin, retrying with exponential backoff
+// until it succeeds. Returns false if the VM was shut down first.
+func (vm *VMClient) restartWithBackoff() bool {
+	config := vm.factory.Supervisor
+	backoff := config.InitialRestartBackoff
+	for {
+		vm.ctx.Lock.Lock()
+		err := vm.restart()
+		vm.ctx.Lock.Unlock()
+
+		switch {
+		case err == nil:
+			vm.metrics.restarts.Inc()
+			vm.ctx.Log.Info("restarted plugin %s", vm.factory.Path)
+			return true
+		case errors.Is(err, errVMClosed):
+			return false
+		}
+
+		vm.metrics.restartFailures.Inc()
+		vm.ctx.Log.Error("failed to restart plugin %s, retrying in %s: %s", vm.factory.Path, backoff, err)
+		select {
+		case <-vm.closed:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > config.MaxRestartBackoff {
+			backoff = config.MaxRestartBackoff
+		}
+	}
+}
+
+// restart replaces the plugin process with a new one and re-initializes it
+// with the arguments the VM was initialized with. Blocks that were verified
+// but not yet decided are verified again by the new process.
+//
+// Assumes the context lock is held.
+func (vm *VMClient) restart() error {
+	select {
+	case <-vm.closed:
+		return errVMClosed
+	default:
+	}
+
+	vm.stopPlugin()
+
+	newVM, err := vm.factory.launch(vm.ctx)
+	if err != nil {
+		return err
+	}
+
+	vm.clientLock.Lock()
+	vm.client = newVM.client
+	vm.broker = newVM.broker
+	vm.proc = newVM.proc
+	vm.conn = newVM.conn
+	vm.features = newVM.features
+	vm.clientLock.Unlock()
+
+	vm.keystore.Reset(vm.broker)
+	resp, err := vm.initializePlugin()
+	if err != nil {
+		return err
+	}
+
+	lastAcceptedID, err := ids.ToID(resp.LastAcceptedID)
+	if err != nil {
+		return err
+	}
+	expectedID, err := vm.State.LastAccepted()
+	if err != nil {
+		return err
+	}
+	if lastAcceptedID != expectedID {
+		return fmt.Errorf("%w: expected %s but got %s", errLastAcceptedDiffer, expectedID, lastAcceptedID)
+	}
+
+	if err := vm.restartHandlers(); err != nil {
+		return err
+	}
+
+	// Replay the verification of the processing blocks, oldest first, so that
+	// parents are verified before their children.
+	it := vm.processing.NewIterator()
+	for it.Next() {
+		blk := it.Value().(*BlockClient)
+		if err := blk.verify(); err != nil {
+			return fmt.Errorf("couldn't verify processing block %s again: %w", blk.id, err)
+		}
+	}
+	return nil
+}
+
+// stopPlugin kills the plugin process and stops every server and connection
+// that was shared with it.
+func (vm *VMClient) stopPlugin() {
+	vm.closeEvents()
+	vm.serverCloser.Stop()
+	for _, conn := range vm.conns {
+		_ = conn.Close()
+	}
+	vm.conns = nil
+	vm.proc.Kill()
+}
+
+// restartHandlers points the HTTP handlers of the VM to the restarted plugin
+func (vm *VMClient) restartHandlers() error {
+	if len(vm.handlers) == 0 {
+		return nil
+	}
+
+	resp, err := vm.client.CreateHandlers(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+	for _, handler := range resp.Handlers {
+		pluginHandler, ok := vm.handlers[handler.Prefix]
+		if !ok {
+			vm.ctx.Log.Warn("restarted plugin %s serves new handler %q, which won't be registered", vm.factory.Path, handler.Prefix)
+			continue
+		}
+
+		conn, err := vm.broker.Dial(handler.Server)
+		if err != nil {
+			return err
+		}
+		vm.conns = append(vm.conns, conn)
+		pluginHandler.set(ghttp.NewClient(ghttpproto.NewHTTPClient(conn), vm.broker))
+	}
+	return nil
+}
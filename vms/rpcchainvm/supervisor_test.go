@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupervisorConfigVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	config := DefaultSupervisorConfig
+	assert.NoError(config.Verify())
+
+	// Supervision is disabled, so the other fields are ignored
+	assert.NoError((&SupervisorConfig{}).Verify())
+
+	config = DefaultSupervisorConfig
+	config.HealthCheckFrequency = -1
+	assert.Error(config.Verify())
+
+	config = DefaultSupervisorConfig
+	config.HealthCheckTimeout = 0
+	assert.Error(config.Verify())
+
+	config = DefaultSupervisorConfig
+	config.MaxHealthCheckFailures = 0
+	assert.Error(config.Verify())
+
+	config = DefaultSupervisorConfig
+	config.InitialRestartBackoff = 0
+	assert.Error(config.Verify())
+
+	config = DefaultSupervisorConfig
+	config.MaxRestartBackoff = config.InitialRestartBackoff - 1
+	assert.Error(config.Verify())
+}
+
+func TestPluginHandlerSet(t *testing.T) {
+	assert := assert.New(t)
+
+	newHandler := func(code int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(code)
+		})
+	}
+
+	handler := &pluginHandler{handler: newHandler(http.StatusOK)}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(http.StatusOK, w.Code)
+
+	// Requests are served by the handler of the restarted plugin
+	handler.set(newHandler(http.StatusTeapot))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(http.StatusTeapot, w.Code)
+}
@@ -46,5 +46,7 @@ func (p *Plugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
 
 // GRPCClient returns a new GRPC client
 func (p *Plugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
-	return NewClient(vmproto.NewVMClient(c), broker), nil
+	vm := NewClient(vmproto.NewVMClient(c), broker)
+	vm.conn = c
+	return vm, nil
 }
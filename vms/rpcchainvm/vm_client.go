@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-plugin"
@@ -34,6 +35,8 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/appsender"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/appsender/appsenderproto"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils/linkedhashmap"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 	"github.com/Toinounet21/avalanchego-mod/version"
 	"github.com/Toinounet21/avalanchego-mod/vms/components/chain"
@@ -50,8 +53,10 @@ import (
 var (
 	errUnsupportedFXs = errors.New("unsupported feature extensions")
 
-	_ block.ChainVM        = &VMClient{}
-	_ block.BatchedChainVM = &VMClient{}
+	_ block.ChainVM                = &VMClient{}
+	_ block.BatchedChainVM         = &VMClient{}
+	_ common.ConfigReloader        = &VMClient{}
+	_ validators.PeerInfoConnector = &VMClient{}
 )
 
 const (
@@ -64,9 +69,15 @@ const (
 // VMClient is an implementation of VM that talks over RPC.
 type VMClient struct {
 	*chain.State
-	client vmproto.VMClient
-	broker *plugin.GRPCBroker
-	proc   *plugin.Client
+
+	// clientLock must be held when replacing the plugin process, or when
+	// accessing it without holding the context lock.
+	clientLock sync.RWMutex
+	client     vmproto.VMClient
+	broker     *plugin.GRPCBroker
+	proc       *plugin.Client
+	// conn is the connection to the plugin, used to health check it
+	conn *grpc.ClientConn
 
 	messenger    *messenger.Server
 	keystore     *gkeystore.Server
@@ -78,6 +89,35 @@ type VMClient struct {
 	serverCloser grpcutils.ServerCloser
 	conns        []*grpc.ClientConn
 
+	// Optional protocol features supported by both the node and the plugin
+	features map[string]bool
+
+	toEngine chan<- common.Message
+	// The event stream with the plugin, if FeatureEvents is supported
+	events       vmproto.VM_EventsClient
+	cancelEvents context.CancelFunc
+
+	// factory launched the plugin, and launches it again if it crashes
+	factory *Factory
+	metrics supervisorMetrics
+	// Number of times the plugin failed to report its metrics
+	gatherFailures prometheus.Counter
+	// Closed when the VM is shut down
+	closed chan struct{}
+
+	// Arguments of Initialize, replayed when the plugin is restarted
+	dbManager    manager.Manager
+	genesisBytes []byte
+	upgradeBytes []byte
+	configBytes  []byte
+
+	// Blocks that were verified but not yet decided, in the order they were
+	// verified. They are verified again when the plugin is restarted.
+	// Block ID --> *BlockClient
+	processing linkedhashmap.LinkedHashmap
+	// Handler prefix --> the handler registered with the API server
+	handlers map[string]*pluginHandler
+
 	ctx *snow.Context
 }
 
@@ -94,6 +134,20 @@ func (vm *VMClient) SetProcess(proc *plugin.Client) {
 	vm.proc = proc
 }
 
+// handshake negotiates the rpcchainvm protocol with the plugin. It must be
+// called before Initialize.
+func (vm *VMClient) handshake() error {
+	resp, err := vm.client.Handshake(context.Background(), newHandshakeRequest())
+	if err != nil {
+		return handshakeError(err)
+	}
+	if err := checkProtocolVersion("plugin", resp.ProtocolVersion, resp.MinProtocolVersion); err != nil {
+		return err
+	}
+	vm.features = negotiateFeatures(resp.Features)
+	return nil
+}
+
 func (vm *VMClient) Initialize(
 	ctx *snow.Context,
 	dbManager manager.Manager,
@@ -109,70 +163,38 @@ func (vm *VMClient) Initialize(
 	}
 
 	vm.ctx = ctx
-
-	// Initialize and serve each database and construct the db manager
-	// initialize request parameters
-	versionedDBs := dbManager.GetDatabases()
-	versionedDBServers := make([]*vmproto.VersionedDBServer, len(versionedDBs))
-	for i, semDB := range versionedDBs {
-		dbBrokerID := vm.broker.NextId()
-		db := rpcdb.NewServer(semDB.Database)
-		go vm.broker.AcceptAndServe(dbBrokerID, vm.startDBServerFunc(db))
-		versionedDBServers[i] = &vmproto.VersionedDBServer{
-			DbServer: dbBrokerID,
-			Version:  semDB.Version.String(),
-		}
-	}
+	vm.closed = make(chan struct{})
+	vm.dbManager = dbManager
+	vm.genesisBytes = genesisBytes
+	vm.upgradeBytes = upgradeBytes
+	vm.configBytes = configBytes
+	vm.processing = linkedhashmap.New()
+	vm.toEngine = toEngine
 
 	vm.messenger = messenger.NewServer(toEngine)
-	vm.keystore = gkeystore.NewServer(ctx.Keystore, vm.broker)
+	registerer := prometheus.NewRegistry()
+	var err error
+	vm.keystore, err = gkeystore.NewServer(ctx.Keystore, vm.broker, gkeystore.DefaultIdleTimeout, registerer)
+	if err != nil {
+		return err
+	}
 	vm.sharedMemory = gsharedmemory.NewServer(ctx.SharedMemory, dbManager.Current().Database)
 	vm.bcLookup = galiasreader.NewServer(ctx.BCLookup)
 	vm.snLookup = gsubnetlookup.NewServer(ctx.SNLookup)
 	vm.appSender = appsender.NewServer(appSender)
 
-	// start the messenger server
-	messengerBrokerID := vm.broker.NextId()
-	go vm.broker.AcceptAndServe(messengerBrokerID, vm.startMessengerServer)
-
-	// start the keystore server
-	keystoreBrokerID := vm.broker.NextId()
-	go vm.broker.AcceptAndServe(keystoreBrokerID, vm.startKeystoreServer)
-
-	// start the shared memory server
-	sharedMemoryBrokerID := vm.broker.NextId()
-	go vm.broker.AcceptAndServe(sharedMemoryBrokerID, vm.startSharedMemoryServer)
-
-	// start the blockchain alias server
-	bcLookupBrokerID := vm.broker.NextId()
-	go vm.broker.AcceptAndServe(bcLookupBrokerID, vm.startBCLookupServer)
-
-	// start the subnet alias server
-	snLookupBrokerID := vm.broker.NextId()
-	go vm.broker.AcceptAndServe(snLookupBrokerID, vm.startSNLookupServer)
-
-	// start the AppSender server
-	appSenderBrokerID := vm.broker.NextId()
-	go vm.broker.AcceptAndServe(appSenderBrokerID, vm.startAppSenderServer)
-
-	resp, err := vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
-		NetworkID:          ctx.NetworkID,
-		SubnetID:           ctx.SubnetID[:],
-		ChainID:            ctx.ChainID[:],
-		NodeID:             ctx.NodeID.Bytes(),
-		XChainID:           ctx.XChainID[:],
-		AvaxAssetID:        ctx.AVAXAssetID[:],
-		GenesisBytes:       genesisBytes,
-		UpgradeBytes:       upgradeBytes,
-		ConfigBytes:        configBytes,
-		DbServers:          versionedDBServers,
-		EngineServer:       messengerBrokerID,
-		KeystoreServer:     keystoreBrokerID,
-		SharedMemoryServer: sharedMemoryBrokerID,
-		BcLookupServer:     bcLookupBrokerID,
-		SnLookupServer:     snLookupBrokerID,
-		AppSenderServer:    appSenderBrokerID,
+	if err := vm.metrics.Initialize(registerer); err != nil {
+		return err
+	}
+	vm.gatherFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "plugin_gather_failures",
+		Help: "Number of times the plugin failed to report its metrics",
 	})
+	if err := registerer.Register(vm.gatherFailures); err != nil {
+		return err
+	}
+
+	resp, err := vm.initializePlugin()
 	if err != nil {
 		return err
 	}
@@ -206,7 +228,6 @@ func (vm *VMClient) Initialize(
 		time:     timestamp,
 	}
 
-	registerer := prometheus.NewRegistry()
 	multiGatherer := metrics.NewMultiGatherer()
 	if err := multiGatherer.Register("rpcchainvm", registerer); err != nil {
 		return err
@@ -233,7 +254,85 @@ func (vm *VMClient) Initialize(
 	}
 	vm.State = chainState
 
-	return vm.ctx.Metrics.Register(multiGatherer)
+	if err := vm.ctx.Metrics.Register(multiGatherer); err != nil {
+		return err
+	}
+
+	if vm.factory != nil && vm.factory.Supervisor.HealthCheckFrequency > 0 {
+		go vm.ctx.Log.RecoverAndPanic(vm.supervise)
+	}
+	return nil
+}
+
+// initializePlugin serves the node's services to the plugin process and
+// initializes the VM running in it.
+func (vm *VMClient) initializePlugin() (*vmproto.InitializeResponse, error) {
+	// Initialize and serve each database and construct the db manager
+	// initialize request parameters
+	versionedDBs := vm.dbManager.GetDatabases()
+	versionedDBServers := make([]*vmproto.VersionedDBServer, len(versionedDBs))
+	for i, semDB := range versionedDBs {
+		dbBrokerID := vm.broker.NextId()
+		db := rpcdb.NewServer(semDB.Database)
+		go vm.broker.AcceptAndServe(dbBrokerID, vm.startDBServerFunc(db))
+		versionedDBServers[i] = &vmproto.VersionedDBServer{
+			DbServer: dbBrokerID,
+			Version:  semDB.Version.String(),
+		}
+	}
+
+	// start the messenger server
+	messengerBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(messengerBrokerID, vm.startMessengerServer)
+
+	// start the keystore server
+	keystoreBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(keystoreBrokerID, vm.startKeystoreServer)
+
+	// start the shared memory server
+	sharedMemoryBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(sharedMemoryBrokerID, vm.startSharedMemoryServer)
+
+	// start the blockchain alias server
+	bcLookupBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(bcLookupBrokerID, vm.startBCLookupServer)
+
+	// start the subnet alias server
+	snLookupBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(snLookupBrokerID, vm.startSNLookupServer)
+
+	// start the AppSender server
+	appSenderBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(appSenderBrokerID, vm.startAppSenderServer)
+
+	resp, err := vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
+		NetworkID:          vm.ctx.NetworkID,
+		SubnetID:           vm.ctx.SubnetID[:],
+		ChainID:            vm.ctx.ChainID[:],
+		NodeID:             vm.ctx.NodeID.Bytes(),
+		XChainID:           vm.ctx.XChainID[:],
+		AvaxAssetID:        vm.ctx.AVAXAssetID[:],
+		GenesisBytes:       vm.genesisBytes,
+		UpgradeBytes:       vm.upgradeBytes,
+		ConfigBytes:        vm.configBytes,
+		DbServers:          versionedDBServers,
+		EngineServer:       messengerBrokerID,
+		KeystoreServer:     keystoreBrokerID,
+		SharedMemoryServer: sharedMemoryBrokerID,
+		BcLookupServer:     bcLookupBrokerID,
+		SnLookupServer:     snLookupBrokerID,
+		AppSenderServer:    appSenderBrokerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if vm.features[FeatureEvents] {
+		if err := vm.openEvents(); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
 }
 
 func (vm *VMClient) startDBServerFunc(db rpcdbproto.DatabaseServer) func(opts []grpc.ServerOption) *grpc.Server { // #nolint
@@ -305,11 +404,20 @@ func (vm *VMClient) Bootstrapped() error {
 }
 
 func (vm *VMClient) Shutdown() error {
+	if vm.closed != nil {
+		close(vm.closed)
+	}
+
 	errs := wrappers.Errs{}
 	_, err := vm.client.Shutdown(context.Background(), &emptypb.Empty{})
 	errs.Add(err)
 
+	vm.closeEvents()
+
 	vm.serverCloser.Stop()
+	if vm.keystore != nil {
+		vm.keystore.Close()
+	}
 	for _, conn := range vm.conns {
 		errs.Add(conn.Close())
 	}
@@ -324,6 +432,10 @@ func (vm *VMClient) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		return nil, err
 	}
 
+	if vm.handlers == nil {
+		vm.handlers = make(map[string]*pluginHandler, len(resp.Handlers))
+	}
+
 	handlers := make(map[string]*common.HTTPHandler, len(resp.Handlers))
 	for _, handler := range resp.Handlers {
 		conn, err := vm.broker.Dial(handler.Server)
@@ -332,9 +444,13 @@ func (vm *VMClient) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		}
 
 		vm.conns = append(vm.conns, conn)
+		pluginHandler := &pluginHandler{
+			handler: ghttp.NewClient(ghttpproto.NewHTTPClient(conn), vm.broker),
+		}
+		vm.handlers[handler.Prefix] = pluginHandler
 		handlers[handler.Prefix] = &common.HTTPHandler{
 			LockOptions: common.LockOption(handler.LockOptions),
-			Handler:     ghttp.NewClient(ghttpproto.NewHTTPClient(conn), vm.broker),
+			Handler:     pluginHandler,
 		}
 	}
 	return handlers, nil
@@ -537,12 +653,16 @@ func (vm *VMClient) AppGossip(nodeID ids.ShortID, msg []byte) error {
 }
 
 func (vm *VMClient) GetAncestors(
+	ctx context.Context,
 	blkID ids.ID,
 	maxBlocksNum int,
 	maxBlocksSize int,
 	maxBlocksRetrivalTime time.Duration,
 ) ([][]byte, error) {
-	resp, err := vm.client.GetAncestors(context.Background(), &vmproto.GetAncestorsRequest{
+	if !vm.features[FeatureGetAncestors] {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+	resp, err := vm.client.GetAncestors(ctx, &vmproto.GetAncestorsRequest{
 		BlkID:                 blkID[:],
 		MaxBlocksNum:          int32(maxBlocksNum),
 		MaxBlocksSize:         int32(maxBlocksSize),
@@ -554,8 +674,11 @@ func (vm *VMClient) GetAncestors(
 	return resp.BlksBytes, nil
 }
 
-func (vm *VMClient) BatchedParseBlock(blksBytes [][]byte) ([]snowman.Block, error) {
-	resp, err := vm.client.BatchedParseBlock(context.Background(), &vmproto.BatchedParseBlockRequest{
+func (vm *VMClient) BatchedParseBlock(ctx context.Context, blksBytes [][]byte) ([]snowman.Block, error) {
+	if !vm.features[FeatureBatchedParseBlock] {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+	resp, err := vm.client.BatchedParseBlock(ctx, &vmproto.BatchedParseBlockRequest{
 		Request: blksBytes,
 	})
 	if err != nil {
@@ -614,10 +737,58 @@ func (vm *VMClient) Version() (string, error) {
 	return resp.Version, nil
 }
 
+func (vm *VMClient) ReloadableConfigKeys() ([]string, error) {
+	if !vm.features[FeatureConfigReload] {
+		// A plugin that doesn't support reloading has no reloadable keys
+		return nil, nil
+	}
+	resp, err := vm.client.ReloadableConfigKeys(
+		context.Background(),
+		&emptypb.Empty{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+func (vm *VMClient) ReloadConfig(changes map[string][]byte) error {
+	if !vm.features[FeatureConfigReload] {
+		return common.ErrConfigReloadNotSupported
+	}
+	req := &vmproto.ReloadConfigRequest{
+		Changes: make([]*vmproto.ConfigChange, 0, len(changes)),
+	}
+	for key, value := range changes {
+		req.Changes = append(req.Changes, &vmproto.ConfigChange{
+			Key:   key,
+			Value: value,
+		})
+	}
+	_, err := vm.client.ReloadConfig(context.Background(), req)
+	return err
+}
+
 func (vm *VMClient) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
+	return vm.ConnectedWithInfo(nodeID, validators.PeerInfo{Version: nodeVersion})
+}
+
+func (vm *VMClient) ConnectedWithInfo(nodeID ids.ShortID, peerInfo validators.PeerInfo) error {
+	ip := ""
+	if !peerInfo.IP.IsZero() {
+		ip = peerInfo.IP.String()
+	}
+	trackedSubnets := make([][]byte, 0, peerInfo.TrackedSubnets.Len())
+	for subnetID := range peerInfo.TrackedSubnets {
+		subnetID := subnetID
+		trackedSubnets = append(trackedSubnets, subnetID[:])
+	}
 	_, err := vm.client.Connected(context.Background(), &vmproto.ConnectedRequest{
-		NodeID:  nodeID[:],
-		Version: nodeVersion.String(),
+		NodeID:         nodeID[:],
+		Version:        peerInfo.Version.String(),
+		Ip:             ip,
+		TrackedSubnets: trackedSubnets,
+		Features:       peerInfo.Features,
 	})
 	return err
 }
@@ -645,18 +816,28 @@ func (b *BlockClient) ID() ids.ID { return b.id }
 
 func (b *BlockClient) Accept() error {
 	b.status = choices.Accepted
+	b.vm.processing.Delete(b.id)
 	_, err := b.vm.client.BlockAccept(context.Background(), &vmproto.BlockAcceptRequest{
 		Id: b.id[:],
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	b.vm.notifyReadyToBuild()
+	return nil
 }
 
 func (b *BlockClient) Reject() error {
 	b.status = choices.Rejected
+	b.vm.processing.Delete(b.id)
 	_, err := b.vm.client.BlockReject(context.Background(), &vmproto.BlockRejectRequest{
 		Id: b.id[:],
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	b.vm.notifyReadyToBuild()
+	return nil
 }
 
 func (b *BlockClient) Status() choices.Status { return b.status }
@@ -666,6 +847,14 @@ func (b *BlockClient) Parent() ids.ID {
 }
 
 func (b *BlockClient) Verify() error {
+	if err := b.verify(); err != nil {
+		return err
+	}
+	b.vm.processing.Put(b.id, b)
+	return nil
+}
+
+func (b *BlockClient) verify() error {
 	resp, err := b.vm.client.BlockVerify(context.Background(), &vmproto.BlockVerifyRequest{
 		Bytes: b.bytes,
 	})
@@ -6,9 +6,12 @@ package rpcchainvm
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/hashicorp/go-plugin"
@@ -31,6 +34,8 @@ import (
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/appsender"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/common/appsender/appsenderproto"
 	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/utils"
 	"github.com/Toinounet21/avalanchego-mod/utils/logging"
 	"github.com/Toinounet21/avalanchego-mod/utils/wrappers"
 	"github.com/Toinounet21/avalanchego-mod/version"
@@ -59,6 +64,17 @@ type VMServer struct {
 	serverCloser grpcutils.ServerCloser
 	connCloser   wrappers.Closer
 
+	// Optional protocol features supported by both the node and the plugin.
+	// nil until the node performs the handshake.
+	features map[string]bool
+
+	// Gathers the metrics of the VM and of the plugin process
+	gatherer metrics.MultiGatherer
+
+	// The event stream opened by the node, if any
+	eventsLock sync.Mutex
+	events     vmproto.VM_EventsServer
+
 	ctx    *snow.Context
 	closed chan struct{}
 }
@@ -71,7 +87,18 @@ func NewServer(vm block.ChainVM, broker *plugin.GRPCBroker) *VMServer {
 	}
 }
 
+func (vm *VMServer) Handshake(_ context.Context, req *vmproto.HandshakeRequest) (*vmproto.HandshakeResponse, error) {
+	if err := checkProtocolVersion("node", req.ProtocolVersion, req.MinProtocolVersion); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	vm.features = negotiateFeatures(req.Features)
+	return newHandshakeResponse(), nil
+}
+
 func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest) (*vmproto.InitializeResponse, error) {
+	if vm.features == nil {
+		return nil, errNoHandshake
+	}
 	subnetID, err := ids.ToID(req.SubnetID)
 	if err != nil {
 		return nil, err
@@ -178,6 +205,17 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 	bcLookupClient := galiasreader.NewClient(galiasreaderproto.NewAliasReaderClient(bcLookupConn))
 	snLookupClient := gsubnetlookup.NewClient(gsubnetlookupproto.NewSubnetLookupClient(snLookupConn))
 	appSenderClient := appsender.NewClient(appsenderproto.NewAppSenderClient(appSenderConn))
+	if !vm.features[FeatureAppGossipValidators] {
+		appSenderClient.DisableGossipValidators()
+	}
+
+	vmMetrics := metrics.NewOptionalGatherer()
+	vm.gatherer, err = newPluginGatherer(vmMetrics)
+	if err != nil {
+		// Ignore closing error to return the original error
+		_ = vm.connCloser.Close()
+		return nil, err
+	}
 
 	toEngine := make(chan common.Message, 1)
 	vm.closed = make(chan struct{})
@@ -189,7 +227,7 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 					return
 				}
 				// Nothing to do with the error within the goroutine
-				_ = msgClient.Notify(msg)
+				_ = vm.notify(msgClient, msg)
 			case <-vm.closed:
 				return
 			}
@@ -210,7 +248,7 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		SharedMemory: sharedMemoryClient,
 		BCLookup:     bcLookupClient,
 		SNLookup:     snLookupClient,
-		Metrics:      metrics.NewOptionalGatherer(),
+		Metrics:      vmMetrics,
 
 		// TODO: support snowman++ fields
 	}
@@ -361,7 +399,7 @@ func (vm *VMServer) ParseBlock(_ context.Context, req *vmproto.ParseBlockRequest
 	}, err
 }
 
-func (vm *VMServer) GetAncestors(_ context.Context, req *vmproto.GetAncestorsRequest) (*vmproto.GetAncestorsResponse, error) {
+func (vm *VMServer) GetAncestors(ctx context.Context, req *vmproto.GetAncestorsRequest) (*vmproto.GetAncestorsResponse, error) {
 	blkID, err := ids.ToID(req.BlkID)
 	if err != nil {
 		return nil, err
@@ -371,6 +409,7 @@ func (vm *VMServer) GetAncestors(_ context.Context, req *vmproto.GetAncestorsReq
 	maxBlocksRetrivalTime := time.Duration(req.MaxBlocksRetrivalTime)
 
 	blocks, err := block.GetAncestors(
+		ctx,
 		vm.vm,
 		blkID,
 		maxBlksNum,
@@ -388,6 +427,9 @@ func (vm *VMServer) BatchedParseBlock(
 ) (*vmproto.BatchedParseBlockResponse, error) {
 	blocks := make([]*vmproto.ParseBlockResponse, len(req.Request))
 	for i, blockBytes := range req.Request {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		block, err := vm.ParseBlock(ctx, &vmproto.ParseBlockRequest{
 			Bytes: blockBytes,
 		})
@@ -463,6 +505,30 @@ func (vm *VMServer) Version(context.Context, *emptypb.Empty) (*vmproto.VersionRe
 	}, err
 }
 
+func (vm *VMServer) ReloadableConfigKeys(context.Context, *emptypb.Empty) (*vmproto.ReloadableConfigKeysResponse, error) {
+	reloader, ok := vm.vm.(common.ConfigReloader)
+	if !ok {
+		// A VM that doesn't support reloading has no reloadable keys
+		return &vmproto.ReloadableConfigKeysResponse{}, nil
+	}
+	keys, err := reloader.ReloadableConfigKeys()
+	return &vmproto.ReloadableConfigKeysResponse{
+		Keys: keys,
+	}, err
+}
+
+func (vm *VMServer) ReloadConfig(_ context.Context, req *vmproto.ReloadConfigRequest) (*emptypb.Empty, error) {
+	reloader, ok := vm.vm.(common.ConfigReloader)
+	if !ok {
+		return nil, common.ErrConfigReloadNotSupported
+	}
+	changes := make(map[string][]byte, len(req.Changes))
+	for _, change := range req.Changes {
+		changes[change.Key] = change.Value
+	}
+	return &emptypb.Empty{}, reloader.ReloadConfig(changes)
+}
+
 func (vm *VMServer) Connected(_ context.Context, req *vmproto.ConnectedRequest) (*emptypb.Empty, error) {
 	nodeID, err := ids.ToShortID(req.NodeID)
 	if err != nil {
@@ -474,7 +540,26 @@ func (vm *VMServer) Connected(_ context.Context, req *vmproto.ConnectedRequest)
 		return nil, err
 	}
 
-	return &emptypb.Empty{}, vm.vm.Connected(nodeID, peerVersion)
+	// Nodes that predate the peer info only send the version of the peer
+	peerInfo := validators.PeerInfo{
+		Version:        peerVersion,
+		TrackedSubnets: ids.NewSet(len(req.TrackedSubnets)),
+		Features:       req.Features,
+	}
+	if req.Ip != "" {
+		peerInfo.IP, err = utils.ToIPDesc(req.Ip)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, subnetIDBytes := range req.TrackedSubnets {
+		subnetID, err := ids.ToID(subnetIDBytes)
+		if err != nil {
+			return nil, err
+		}
+		peerInfo.TrackedSubnets.Add(subnetID)
+	}
+	return &emptypb.Empty{}, validators.Connect(vm.vm, nodeID, peerInfo)
 }
 
 func (vm *VMServer) Disconnected(_ context.Context, req *vmproto.DisconnectedRequest) (*emptypb.Empty, error) {
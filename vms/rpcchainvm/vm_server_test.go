@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow/engine/snowman/block"
+	"github.com/Toinounet21/avalanchego-mod/snow/validators"
+	"github.com/Toinounet21/avalanchego-mod/version"
+	"github.com/Toinounet21/avalanchego-mod/vms/rpcchainvm/vmproto"
+)
+
+type peerInfoVM struct {
+	block.TestVM
+
+	nodeID   ids.ShortID
+	peerInfo validators.PeerInfo
+}
+
+func (vm *peerInfoVM) ConnectedWithInfo(nodeID ids.ShortID, peerInfo validators.PeerInfo) error {
+	vm.nodeID = nodeID
+	vm.peerInfo = peerInfo
+	return nil
+}
+
+func TestServerConnected(t *testing.T) {
+	assert := assert.New(t)
+
+	vm := &peerInfoVM{}
+	server := NewServer(vm, nil)
+
+	nodeID := ids.GenerateTestShortID()
+	subnetID := ids.GenerateTestID()
+	_, err := server.Connected(context.Background(), &vmproto.ConnectedRequest{
+		NodeID:         nodeID[:],
+		Version:        version.CurrentApp.String(),
+		Ip:             "127.0.0.1:9651",
+		TrackedSubnets: [][]byte{subnetID[:]},
+		Features:       []string{"subnets"},
+	})
+	assert.NoError(err)
+	assert.Equal(nodeID, vm.nodeID)
+	assert.Equal(version.CurrentApp.String(), vm.peerInfo.Version.String())
+	assert.Equal("127.0.0.1:9651", vm.peerInfo.IP.String())
+	assert.True(vm.peerInfo.TrackedSubnets.Contains(subnetID))
+	assert.Equal([]string{"subnets"}, vm.peerInfo.Features)
+
+	// Older nodes only send the version of the peer
+	_, err = server.Connected(context.Background(), &vmproto.ConnectedRequest{
+		NodeID:  nodeID[:],
+		Version: version.CurrentApp.String(),
+	})
+	assert.NoError(err)
+	assert.True(vm.peerInfo.IP.IsZero())
+	assert.Zero(vm.peerInfo.TrackedSubnets.Len())
+
+	_, err = server.Connected(context.Background(), &vmproto.ConnectedRequest{
+		NodeID:  nodeID[:],
+		Version: version.CurrentApp.String(),
+		Ip:      "not an ip",
+	})
+	assert.Error(err)
+}
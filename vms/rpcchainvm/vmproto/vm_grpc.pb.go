@@ -19,6 +19,7 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type VMClient interface {
+	Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error)
 	Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error)
 	Bootstrapping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Bootstrapped(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -43,6 +44,9 @@ type VMClient interface {
 	BlockReject(ctx context.Context, in *BlockRejectRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetAncestors(ctx context.Context, in *GetAncestorsRequest, opts ...grpc.CallOption) (*GetAncestorsResponse, error)
 	BatchedParseBlock(ctx context.Context, in *BatchedParseBlockRequest, opts ...grpc.CallOption) (*BatchedParseBlockResponse, error)
+	ReloadableConfigKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadableConfigKeysResponse, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Events(ctx context.Context, opts ...grpc.CallOption) (VM_EventsClient, error)
 }
 
 type vMClient struct {
@@ -53,6 +57,15 @@ func NewVMClient(cc grpc.ClientConnInterface) VMClient {
 	return &vMClient{cc}
 }
 
+func (c *vMClient) Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error) {
+	out := new(HandshakeResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/Handshake", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error) {
 	out := new(InitializeResponse)
 	err := c.cc.Invoke(ctx, "/vmproto.VM/Initialize", in, out, opts...)
@@ -269,10 +282,60 @@ func (c *vMClient) BatchedParseBlock(ctx context.Context, in *BatchedParseBlockR
 	return out, nil
 }
 
+func (c *vMClient) ReloadableConfigKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadableConfigKeysResponse, error) {
+	out := new(ReloadableConfigKeysResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/ReloadableConfigKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) Events(ctx context.Context, opts ...grpc.CallOption) (VM_EventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VM_ServiceDesc.Streams[0], "/vmproto.VM/Events", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vMEventsClient{stream}
+	return x, nil
+}
+
+type VM_EventsClient interface {
+	Send(*NodeEvent) error
+	Recv() (*PluginEvent, error)
+	grpc.ClientStream
+}
+
+type vMEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *vMEventsClient) Send(m *NodeEvent) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *vMEventsClient) Recv() (*PluginEvent, error) {
+	m := new(PluginEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // VMServer is the server API for VM service.
 // All implementations must embed UnimplementedVMServer
 // for forward compatibility
 type VMServer interface {
+	Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error)
 	Initialize(context.Context, *InitializeRequest) (*InitializeResponse, error)
 	Bootstrapping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	Bootstrapped(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
@@ -297,6 +360,9 @@ type VMServer interface {
 	BlockReject(context.Context, *BlockRejectRequest) (*emptypb.Empty, error)
 	GetAncestors(context.Context, *GetAncestorsRequest) (*GetAncestorsResponse, error)
 	BatchedParseBlock(context.Context, *BatchedParseBlockRequest) (*BatchedParseBlockResponse, error)
+	ReloadableConfigKeys(context.Context, *emptypb.Empty) (*ReloadableConfigKeysResponse, error)
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*emptypb.Empty, error)
+	Events(VM_EventsServer) error
 	mustEmbedUnimplementedVMServer()
 }
 
@@ -304,6 +370,9 @@ type VMServer interface {
 type UnimplementedVMServer struct {
 }
 
+func (UnimplementedVMServer) Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Handshake not implemented")
+}
 func (UnimplementedVMServer) Initialize(context.Context, *InitializeRequest) (*InitializeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Initialize not implemented")
 }
@@ -376,6 +445,15 @@ func (UnimplementedVMServer) GetAncestors(context.Context, *GetAncestorsRequest)
 func (UnimplementedVMServer) BatchedParseBlock(context.Context, *BatchedParseBlockRequest) (*BatchedParseBlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchedParseBlock not implemented")
 }
+func (UnimplementedVMServer) ReloadableConfigKeys(context.Context, *emptypb.Empty) (*ReloadableConfigKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadableConfigKeys not implemented")
+}
+func (UnimplementedVMServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedVMServer) Events(VM_EventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Events not implemented")
+}
 func (UnimplementedVMServer) mustEmbedUnimplementedVMServer() {}
 
 // UnsafeVMServer may be embedded to opt out of forward compatibility for this service.
@@ -389,6 +467,24 @@ func RegisterVMServer(s grpc.ServiceRegistrar, srv VMServer) {
 	s.RegisterService(&VM_ServiceDesc, srv)
 }
 
+func _VM_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/Handshake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).Handshake(ctx, req.(*HandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_Initialize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(InitializeRequest)
 	if err := dec(in); err != nil {
@@ -821,6 +917,68 @@ func _VM_BatchedParseBlock_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_ReloadableConfigKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).ReloadableConfigKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/ReloadableConfigKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).ReloadableConfigKeys(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VMServer).Events(&vMEventsServer{stream})
+}
+
+type VM_EventsServer interface {
+	Send(*PluginEvent) error
+	Recv() (*NodeEvent, error)
+	grpc.ServerStream
+}
+
+type vMEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *vMEventsServer) Send(m *PluginEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *vMEventsServer) Recv() (*NodeEvent, error) {
+	m := new(NodeEvent)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // VM_ServiceDesc is the grpc.ServiceDesc for VM service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -828,6 +986,10 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "vmproto.VM",
 	HandlerType: (*VMServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    _VM_Handshake_Handler,
+		},
 		{
 			MethodName: "Initialize",
 			Handler:    _VM_Initialize_Handler,
@@ -924,7 +1086,22 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchedParseBlock",
 			Handler:    _VM_BatchedParseBlock_Handler,
 		},
+		{
+			MethodName: "ReloadableConfigKeys",
+			Handler:    _VM_ReloadableConfigKeys_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _VM_ReloadConfig_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Events",
+			Handler:       _VM_Events_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "vm.proto",
 }
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+// minBatchParallelism is the number of signatures below which a batch is
+// verified on the calling goroutine
+const minBatchParallelism = 4
+
+type pendingSignature struct {
+	hash     []byte
+	sig      [crypto.SECP256K1RSigLen]byte
+	expected ids.ShortID
+}
+
+// BatchVerifier collects signatures and verifies them together. Signatures
+// are verified concurrently; if any of them is invalid, the batch is verified
+// again one signature at a time so that the reported error always references
+// the first invalid signature that was added.
+type BatchVerifier struct {
+	factory *crypto.FactorySECP256K1R
+	sigs    []pendingSignature
+}
+
+// NewBatchVerifier returns a new, empty, batch that recovers public keys with
+// [factory]
+func NewBatchVerifier(factory *crypto.FactorySECP256K1R) *BatchVerifier {
+	return &BatchVerifier{factory: factory}
+}
+
+// Add a signature over [hash] that must have been produced by [expected]
+func (b *BatchVerifier) Add(hash []byte, sig [crypto.SECP256K1RSigLen]byte, expected ids.ShortID) {
+	b.sigs = append(b.sigs, pendingSignature{
+		hash:     hash,
+		sig:      sig,
+		expected: expected,
+	})
+}
+
+// Len returns the number of signatures in the batch
+func (b *BatchVerifier) Len() int { return len(b.sigs) }
+
+// Verify returns nil iff every signature in the batch is valid. The batch is
+// emptied.
+func (b *BatchVerifier) Verify() error {
+	sigs := b.sigs
+	b.sigs = nil
+
+	if len(sigs) >= minBatchParallelism && b.verifyParallel(sigs) {
+		return nil
+	}
+	for _, sig := range sigs {
+		if err := verifySignature(b.factory, sig.hash, sig.sig, sig.expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyParallel returns true iff every signature in [sigs] is valid. Workers
+// stop as soon as any invalid signature is found.
+func (b *BatchVerifier) verifyParallel(sigs []pendingSignature) bool {
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(sigs) {
+		numWorkers = len(sigs)
+	}
+
+	var (
+		next   int64 = -1
+		failed int32
+		wg     sync.WaitGroup
+	)
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				index := atomic.AddInt64(&next, 1)
+				if index >= int64(len(sigs)) {
+					return
+				}
+				sig := sigs[index]
+				if err := verifySignature(b.factory, sig.hash, sig.sig, sig.expected); err != nil {
+					atomic.StoreInt32(&failed, 1)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return atomic.LoadInt32(&failed) == 0
+}
+
+// verifySignature returns nil iff [sig] is a signature of [hash] by [expected]
+func verifySignature(factory *crypto.FactorySECP256K1R, hash []byte, sig [crypto.SECP256K1RSigLen]byte, expected ids.ShortID) error {
+	pk, err := factory.RecoverHashPublicKey(hash, sig[:])
+	if err != nil {
+		return err
+	}
+	if expected != pk.Address() {
+		return fmt.Errorf("expected signature from %s but got from %s",
+			expected,
+			pk.Address())
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/cache"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+func TestBatchVerifier(t *testing.T) {
+	factory := &crypto.FactorySECP256K1R{Cache: cache.LRU{Size: defaultCacheSize}}
+	hash := hashing.ComputeHash256(txBytes)
+
+	tests := []struct {
+		name      string
+		numSigs   int
+		invalidAt int // -1 if every signature is valid
+	}{
+		{
+			name:      "empty",
+			invalidAt: -1,
+		},
+		{
+			name:      "sequential valid",
+			numSigs:   minBatchParallelism - 1,
+			invalidAt: -1,
+		},
+		{
+			name:      "sequential invalid",
+			numSigs:   minBatchParallelism - 1,
+			invalidAt: 1,
+		},
+		{
+			name:      "parallel valid",
+			numSigs:   4 * minBatchParallelism,
+			invalidAt: -1,
+		},
+		{
+			name:      "parallel invalid",
+			numSigs:   4 * minBatchParallelism,
+			invalidAt: 3 * minBatchParallelism,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			batch := NewBatchVerifier(factory)
+			for i := 0; i < test.numSigs; i++ {
+				expected := addr
+				if i == test.invalidAt {
+					expected = addr2
+				}
+				batch.Add(hash, sigBytes, expected)
+			}
+			if batch.Len() != test.numSigs {
+				t.Fatalf("expected %d signatures but got %d", test.numSigs, batch.Len())
+			}
+
+			err := batch.Verify()
+			if test.invalidAt < 0 && err != nil {
+				t.Fatal(err)
+			} else if test.invalidAt >= 0 && err == nil {
+				t.Fatalf("should have errored due to the invalid signature")
+			}
+			if batch.Len() != 0 {
+				t.Fatalf("batch should have been emptied")
+			}
+		})
+	}
+}
+
+func TestBatchVerifierReportsFirstInvalid(t *testing.T) {
+	factory := &crypto.FactorySECP256K1R{Cache: cache.LRU{Size: defaultCacheSize}}
+	hash := hashing.ComputeHash256(txBytes)
+
+	batch := NewBatchVerifier(factory)
+	for i := 0; i < 2*minBatchParallelism; i++ {
+		batch.Add(hash, sigBytes, addr)
+	}
+	first := ids.ShortID{1}
+	batch.Add(hash, sigBytes, first)
+	batch.Add(hash, sigBytes, ids.ShortID{2})
+
+	err := batch.Verify()
+	if err == nil {
+		t.Fatalf("should have errored due to the invalid signatures")
+	}
+	expectedErr := verifySignature(factory, hash, sigBytes, first)
+	if err.Error() != expectedErr.Error() {
+		t.Fatalf("expected %q but got %q", expectedErr, err)
+	}
+}
@@ -40,6 +40,9 @@ type Fx struct {
 	VM           VM
 	SECPFactory  crypto.FactorySECP256K1R
 	bootstrapped bool
+
+	// batch is non-nil while signature verification is being deferred
+	batch *BatchVerifier
 }
 
 func (fx *Fx) Initialize(vmIntf interface{}) error {
@@ -65,6 +68,20 @@ func (fx *Fx) Initialize(vmIntf interface{}) error {
 	return errs.Err
 }
 
+// RegisterTimelockedTypes registers the timelocked output and input types with
+// the VM's codec. These types are registered separately from Initialize so that
+// VMs that already assigned type IDs to the types of other feature extensions
+// can register them after every feature extension has been initialized.
+func (fx *Fx) RegisterTimelockedTypes() error {
+	c := fx.VM.CodecRegistry()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&TimelockedInput{}),
+		c.RegisterType(&TimelockedOutput{}),
+	)
+	return errs.Err
+}
+
 func (fx *Fx) InitializeVM(vmIntf interface{}) error {
 	vm, ok := vmIntf.(VM)
 	if !ok {
@@ -140,19 +157,26 @@ func (fx *Fx) VerifyTransfer(txIntf, inIntf, credIntf, utxoIntf interface{}) err
 	if !ok {
 		return errWrongTxType
 	}
-	in, ok := inIntf.(*TransferInput)
-	if !ok {
-		return errWrongInputType
-	}
 	cred, ok := credIntf.(*Credential)
 	if !ok {
 		return errWrongCredentialType
 	}
-	out, ok := utxoIntf.(*TransferOutput)
-	if !ok {
-		return errWrongUTXOType
+	switch in := inIntf.(type) {
+	case *TransferInput:
+		out, ok := utxoIntf.(*TransferOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.VerifySpend(tx, in, cred, out)
+	case *TimelockedInput:
+		out, ok := utxoIntf.(*TimelockedOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.VerifyTimelockedSpend(tx, in, cred, out)
+	default:
+		return errWrongInputType
 	}
-	return fx.VerifySpend(tx, in, cred, out)
 }
 
 // VerifySpend ensures that the utxo can be sent to any address
@@ -166,6 +190,22 @@ func (fx *Fx) VerifySpend(tx Tx, in *TransferInput, cred *Credential, utxo *Tran
 	return fx.VerifyCredentials(tx, &in.Input, cred, &utxo.OutputOwners)
 }
 
+// VerifyTimelockedSpend ensures that the timelocked utxo can be sent to any
+// address by the owners selected by the input at the current time
+func (fx *Fx) VerifyTimelockedSpend(tx Tx, in *TimelockedInput, cred *Credential, utxo *TimelockedOutput) error {
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	} else if utxo.Amt != in.Amt {
+		return fmt.Errorf("utxo amount and input amount should be same but are %d and %d", utxo.Amt, in.Amt)
+	}
+
+	owners, err := utxo.SpendingOwners(in.Fallback, fx.VM.Clock().Unix())
+	if err != nil {
+		return err
+	}
+	return fx.VerifyCredentials(tx, &in.Input, cred, owners)
+}
+
 // VerifyCredentials ensures that the output can be spent by the input with the
 // credential. A nil return values means the output can be spent.
 func (fx *Fx) VerifyCredentials(tx Tx, in *Input, cred *Credential, out *OutputOwners) error {
@@ -191,21 +231,37 @@ func (fx *Fx) VerifyCredentials(tx Tx, in *Input, cred *Credential, out *OutputO
 		}
 		// Make sure each signature in the signature list is from an owner of
 		// the output being consumed
-		sig := cred.Sigs[i]
-		pk, err := fx.SECPFactory.RecoverHashPublicKey(txHash, sig[:])
-		if err != nil {
-			return err
+		if fx.batch != nil {
+			fx.batch.Add(txHash, cred.Sigs[i], out.Addrs[index])
+			continue
 		}
-		if expectedAddress := out.Addrs[index]; expectedAddress != pk.Address() {
-			return fmt.Errorf("expected signature from %s but got from %s",
-				expectedAddress,
-				pk.Address())
+		if err := verifySignature(&fx.SECPFactory, txHash, cred.Sigs[i], out.Addrs[index]); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// StartBatch defers the signature checks of VerifyCredentials until
+// VerifyBatch is called. All other checks are still performed immediately.
+func (fx *Fx) StartBatch() { fx.batch = NewBatchVerifier(&fx.SECPFactory) }
+
+// VerifyBatch verifies the signatures deferred since StartBatch was called and
+// returns to verifying signatures immediately.
+func (fx *Fx) VerifyBatch() error {
+	batch := fx.batch
+	fx.batch = nil
+	if batch == nil {
+		return nil
+	}
+	return batch.Verify()
+}
+
+// AbortBatch discards the signatures deferred since StartBatch was called and
+// returns to verifying signatures immediately.
+func (fx *Fx) AbortBatch() { fx.batch = nil }
+
 // CreateOutput creates a new output with the provided control group worth
 // the specified amount
 func (fx *Fx) CreateOutput(amount uint64, ownerIntf interface{}) (interface{}, error) {
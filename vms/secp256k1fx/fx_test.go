@@ -1141,3 +1141,231 @@ func TestVerifyPermission(t *testing.T) {
 		}
 	}
 }
+
+func TestFxVerifyTransferTimelockedOutput(t *testing.T) {
+	vm := TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.CLK.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.RegisterTimelockedTypes(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapping(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	tx := &TestTx{Bytes: txBytes}
+	newOutput := func(locktime uint64, earlyThreshold uint32) *TimelockedOutput {
+		return &TimelockedOutput{
+			Amt:            1,
+			Locktime:       locktime,
+			EarlyThreshold: earlyThreshold,
+			Owners: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					addr,
+				},
+			},
+			Fallback: OutputOwners{
+				Threshold: 1,
+				Addrs: []ids.ShortID{
+					addr2,
+				},
+			},
+		}
+	}
+	newInput := func(fallback bool) *TimelockedInput {
+		return &TimelockedInput{
+			TransferInput: TransferInput{
+				Amt: 1,
+				Input: Input{
+					SigIndices: []uint32{0},
+				},
+			},
+			Fallback: fallback,
+		}
+	}
+	ownerCred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+	fallbackCred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sig2Bytes,
+		},
+	}
+	locked := uint64(date.Add(time.Hour).Unix())
+	unlocked := uint64(date.Add(-time.Hour).Unix())
+
+	tests := []struct {
+		name      string
+		out       *TimelockedOutput
+		in        *TimelockedInput
+		cred      *Credential
+		shouldErr bool
+	}{
+		{
+			name: "owners before locktime",
+			out:  newOutput(locked, 1),
+			in:   newInput(false),
+			cred: ownerCred,
+		},
+		{
+			name:      "owners before locktime without early spending",
+			out:       newOutput(locked, 0),
+			in:        newInput(false),
+			cred:      ownerCred,
+			shouldErr: true,
+		},
+		{
+			name:      "fallback before locktime",
+			out:       newOutput(locked, 1),
+			in:        newInput(true),
+			cred:      fallbackCred,
+			shouldErr: true,
+		},
+		{
+			name: "owners after locktime",
+			out:  newOutput(unlocked, 0),
+			in:   newInput(false),
+			cred: ownerCred,
+		},
+		{
+			name: "fallback after locktime",
+			out:  newOutput(unlocked, 0),
+			in:   newInput(true),
+			cred: fallbackCred,
+		},
+		{
+			name:      "fallback signed by owner",
+			out:       newOutput(unlocked, 0),
+			in:        newInput(true),
+			cred:      ownerCred,
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := fx.VerifyTransfer(tx, test.in, test.cred, test.out)
+			if test.shouldErr && err == nil {
+				t.Fatalf("should have errored")
+			} else if !test.shouldErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestFxVerifyTransferTimelockedWrongUTXO(t *testing.T) {
+	vm := TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &TestTx{Bytes: txBytes}
+	out := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				addr,
+			},
+		},
+	}
+	in := &TimelockedInput{
+		TransferInput: TransferInput{
+			Amt: 1,
+			Input: Input{
+				SigIndices: []uint32{0},
+			},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+	if err := fx.VerifyTransfer(tx, in, cred, out); err != errWrongUTXOType {
+		t.Fatalf("expected %v but got %v", errWrongUTXOType, err)
+	}
+}
+
+func TestFxVerifyTransferBatch(t *testing.T) {
+	vm := TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	tx := &TestTx{Bytes: txBytes}
+	out := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				addr,
+			},
+		},
+	}
+	in := &TransferInput{
+		Amt: 1,
+		Input: Input{
+			SigIndices: []uint32{0},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+	wrongCred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sig2Bytes,
+		},
+	}
+
+	fx.StartBatch()
+	if err := fx.VerifyTransfer(tx, in, cred, out); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyBatch(); err != nil {
+		t.Fatal(err)
+	}
+
+	fx.StartBatch()
+	if err := fx.VerifyTransfer(tx, in, wrongCred, out); err != nil {
+		t.Fatalf("signature check should have been deferred but got %s", err)
+	}
+	if err := fx.VerifyBatch(); err == nil {
+		t.Fatalf("should have errored due to the wrong signer")
+	}
+
+	fx.StartBatch()
+	if err := fx.VerifyTransfer(tx, in, wrongCred, out); err != nil {
+		t.Fatalf("signature check should have been deferred but got %s", err)
+	}
+	fx.AbortBatch()
+	if err := fx.VerifyBatch(); err != nil {
+		t.Fatalf("aborted batch shouldn't have been verified but got %s", err)
+	}
+	if err := fx.VerifyTransfer(tx, in, wrongCred, out); err == nil {
+		t.Fatalf("should have errored due to the wrong signer")
+	}
+}
@@ -91,6 +91,25 @@ func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable
 			}, keys, nil
 		}
 		return nil, nil, errCantSpend
+	case *TimelockedOutput:
+		for _, fallback := range []bool{false, true} {
+			owners, err := out.SpendingOwners(fallback, time)
+			if err != nil {
+				continue
+			}
+			if sigIndices, keys, able := kc.Match(owners, time); able {
+				return &TimelockedInput{
+					TransferInput: TransferInput{
+						Amt: out.Amt,
+						Input: Input{
+							SigIndices: sigIndices,
+						},
+					},
+					Fallback: fallback,
+				}, keys, nil
+			}
+		}
+		return nil, nil, errCantSpend
 	}
 	return nil, nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
 }
@@ -294,6 +294,80 @@ func TestKeychainSpendTransfer(t *testing.T) {
 	}
 }
 
+func TestKeychainSpendTimelocked(t *testing.T) {
+	kc := NewKeychain()
+
+	sks := []*crypto.PrivateKeySECP256K1R{}
+	for _, keyStr := range keys {
+		skBytes, err := formatting.Decode(defaultEncoding, keyStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		skIntf, err := kc.factory.ToPrivateKey(skBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk, ok := skIntf.(*crypto.PrivateKeySECP256K1R)
+		if !ok {
+			t.Fatalf("Factory should have returned secp256k1r private key")
+		}
+		sks = append(sks, sk)
+	}
+
+	timelocked := TimelockedOutput{
+		Amt:      12345,
+		Locktime: 54321,
+		Owners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				sks[1].PublicKey().Address(),
+			},
+		},
+		Fallback: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				sks[2].PublicKey().Address(),
+			},
+		},
+	}
+	if err := timelocked.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	kc.Add(sks[2])
+
+	if _, _, err := kc.Spend(&timelocked, 4321); err == nil {
+		t.Fatalf("Shouldn't have been able to spend timelocked funds")
+	}
+
+	if input, keys, err := kc.Spend(&timelocked, 54321); err != nil {
+		t.Fatal(err)
+	} else if input, ok := input.(*TimelockedInput); !ok {
+		t.Fatalf("Wrong input type returned")
+	} else if err := input.Verify(); err != nil {
+		t.Fatal(err)
+	} else if !input.Fallback {
+		t.Fatalf("Should have spent with the fallback owners")
+	} else if amt := input.Amount(); amt != 12345 {
+		t.Fatalf("Wrong amount returned from input")
+	} else if numKeys := len(keys); numKeys != 1 {
+		t.Fatalf("Should have returned one key")
+	} else if key := keys[0]; key.PublicKey().Address() != sks[2].PublicKey().Address() {
+		t.Fatalf("Returned wrong key")
+	}
+
+	kc.Add(sks[1])
+
+	if input, _, err := kc.Spend(&timelocked, 54321); err != nil {
+		t.Fatal(err)
+	} else if input, ok := input.(*TimelockedInput); !ok {
+		t.Fatalf("Wrong input type returned")
+	} else if input.Fallback {
+		t.Fatalf("Should have spent with the primary owners")
+	}
+}
+
 func TestKeychainString(t *testing.T) {
 	kc := NewKeychain()
 
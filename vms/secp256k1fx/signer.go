@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"fmt"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+)
+
+var _ Signer = &Keychain{}
+
+// Signer signs hashes with the private keys of addresses. The keys may be held
+// outside of the node, for example on a hardware wallet.
+type Signer interface {
+	// Addresses returns the addresses that this signer holds the keys of
+	Addresses() ids.ShortSet
+
+	// SignHash returns the recoverable signature of [hash] by the key of
+	// [addr]
+	SignHash(addr ids.ShortID, hash []byte) ([]byte, error)
+}
+
+// SignHash implements the Signer interface
+func (kc *Keychain) SignHash(addr ids.ShortID, hash []byte) ([]byte, error) {
+	key, ok := kc.Get(addr)
+	if !ok {
+		return nil, fmt.Errorf("no key for address %s", addr)
+	}
+	return key.SignHash(hash)
+}
+
+// SignCredential returns a credential that contains the signatures of [hash]
+// by each of [addrs], in order
+func SignCredential(signer Signer, hash []byte, addrs []ids.ShortID) (*Credential, error) {
+	cred := &Credential{
+		Sigs: make([][crypto.SECP256K1RSigLen]byte, len(addrs)),
+	}
+	for i, addr := range addrs {
+		sig, err := signer.SignHash(addr, hash)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't sign with %s: %w", addr, err)
+		}
+		if len(sig) != crypto.SECP256K1RSigLen {
+			return nil, fmt.Errorf("signature by %s has length %d but should have length %d", addr, len(sig), crypto.SECP256K1RSigLen)
+		}
+		copy(cred.Sigs[i][:], sig)
+	}
+	return cred, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/utils/crypto"
+	"github.com/Toinounet21/avalanchego-mod/utils/hashing"
+)
+
+func TestSignCredential(t *testing.T) {
+	assert := assert.New(t)
+
+	kc := NewKeychain()
+	key0, err := kc.New()
+	assert.NoError(err)
+	key1, err := kc.New()
+	assert.NoError(err)
+	addr0 := key0.PublicKey().Address()
+	addr1 := key1.PublicKey().Address()
+
+	hash := hashing.ComputeHash256([]byte("unsigned tx"))
+	cred, err := SignCredential(kc, hash, []ids.ShortID{addr1, addr0})
+	assert.NoError(err)
+	assert.Len(cred.Sigs, 2)
+
+	factory := crypto.FactorySECP256K1R{}
+	for i, expectedAddr := range []ids.ShortID{addr1, addr0} {
+		pk, err := factory.RecoverHashPublicKey(hash, cred.Sigs[i][:])
+		assert.NoError(err)
+		assert.Equal(expectedAddr, pk.Address())
+	}
+
+	_, err = SignCredential(kc, hash, []ids.ShortID{ids.GenerateTestShortID()})
+	assert.Error(err)
+}
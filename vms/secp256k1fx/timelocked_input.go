@@ -0,0 +1,20 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+// TimelockedInput spends a TimelockedOutput. If [Fallback] is true, the
+// signature indices reference the fallback owners of the output, otherwise
+// they reference the primary owners.
+type TimelockedInput struct {
+	TransferInput `serialize:"true"`
+	Fallback      bool `serialize:"true" json:"fallback"`
+}
+
+// Verify this input is syntactically valid
+func (in *TimelockedInput) Verify() error {
+	if in == nil {
+		return errNilInput
+	}
+	return in.TransferInput.Verify()
+}
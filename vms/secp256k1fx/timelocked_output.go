@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/snow"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+)
+
+var (
+	errNestedLocktime       = errors.New("timelocked output owners can't specify their own locktime")
+	errEarlyThresholdTooLow = errors.New("early threshold is lower than the owners threshold")
+	errNoFallbackOwners     = errors.New("output doesn't specify fallback owners")
+
+	_ verify.State = &TimelockedOutput{}
+)
+
+// TimelockedOutput is an output whose spending conditions change at
+// [Locktime].
+//
+// Before [Locktime], [EarlyThreshold] signatures from [Owners] are required to
+// spend the output. An [EarlyThreshold] of 0 means the output can't be spent
+// before [Locktime].
+//
+// After [Locktime], the output can be spent either by [Owners] with their
+// normal threshold or by the [Fallback] owners. If [Fallback] has no
+// addresses, only [Owners] can spend the output.
+type TimelockedOutput struct {
+	Amt            uint64       `serialize:"true" json:"amount"`
+	Locktime       uint64       `serialize:"true" json:"locktime"`
+	EarlyThreshold uint32       `serialize:"true" json:"earlyThreshold"`
+	Owners         OutputOwners `serialize:"true" json:"owners"`
+	Fallback       OutputOwners `serialize:"true" json:"fallback"`
+}
+
+// InitCtx assigns [ctx] to both owner sets so they can be marshalled to JSON
+func (out *TimelockedOutput) InitCtx(ctx *snow.Context) {
+	out.Owners.InitCtx(ctx)
+	out.Fallback.InitCtx(ctx)
+}
+
+// MarshalJSON marshals the output into a JSON readable format
+func (out *TimelockedOutput) MarshalJSON() ([]byte, error) {
+	owners, err := out.Owners.Fields()
+	if err != nil {
+		return nil, err
+	}
+	fallback, err := out.Fallback.Fields()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{
+		"amount":         out.Amt,
+		"locktime":       out.Locktime,
+		"earlyThreshold": out.EarlyThreshold,
+		"owners":         owners,
+		"fallback":       fallback,
+	})
+}
+
+// Amount returns the quantity of the asset this output consumes
+func (out *TimelockedOutput) Amount() uint64 { return out.Amt }
+
+// Addresses returns the sorted union of the owner and fallback addresses
+func (out *TimelockedOutput) Addresses() [][]byte {
+	set := out.Owners.AddressesSet()
+	set.Add(out.Fallback.Addrs...)
+	addrs := set.List()
+	ids.SortShortIDs(addrs)
+
+	addrBytes := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		addrBytes[i] = addr.Bytes()
+	}
+	return addrBytes
+}
+
+// SpendingOwners returns the owners that must sign to spend this output at
+// [time]. If [fallback] is true, the fallback owners are returned.
+func (out *TimelockedOutput) SpendingOwners(fallback bool, time uint64) (*OutputOwners, error) {
+	unlocked := out.Locktime <= time
+	switch {
+	case fallback && !unlocked:
+		return nil, errTimelocked
+	case fallback && len(out.Fallback.Addrs) == 0:
+		return nil, errNoFallbackOwners
+	case fallback:
+		return &out.Fallback, nil
+	case unlocked:
+		return &out.Owners, nil
+	case out.EarlyThreshold == 0:
+		return nil, errTimelocked
+	default:
+		return &OutputOwners{
+			Threshold: out.EarlyThreshold,
+			Addrs:     out.Owners.Addrs,
+		}, nil
+	}
+}
+
+func (out *TimelockedOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case out.Amt == 0:
+		return errNoValueOutput
+	case out.Owners.Locktime != 0 || out.Fallback.Locktime != 0:
+		return errNestedLocktime
+	case out.EarlyThreshold > uint32(len(out.Owners.Addrs)):
+		return errOutputUnspendable
+	case out.EarlyThreshold != 0 && out.EarlyThreshold < out.Owners.Threshold:
+		return errEarlyThresholdTooLow
+	default:
+		return verify.All(&out.Owners, &out.Fallback)
+	}
+}
+
+func (out *TimelockedOutput) VerifyState() error { return out.Verify() }
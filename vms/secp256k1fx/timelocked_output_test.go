@@ -0,0 +1,219 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"testing"
+
+	"github.com/Toinounet21/avalanchego-mod/codec"
+	"github.com/Toinounet21/avalanchego-mod/codec/linearcodec"
+	"github.com/Toinounet21/avalanchego-mod/ids"
+	"github.com/Toinounet21/avalanchego-mod/vms/components/verify"
+)
+
+func newTestTimelockedOutput() *TimelockedOutput {
+	return &TimelockedOutput{
+		Amt:            1,
+		Locktime:       10,
+		EarlyThreshold: 2,
+		Owners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				{1},
+				{2},
+			},
+		},
+		Fallback: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				{3},
+			},
+		},
+	}
+}
+
+func TestTimelockedOutputVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(out *TimelockedOutput)
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			modify: func(*TimelockedOutput) {},
+		},
+		{
+			name: "no early spending",
+			modify: func(out *TimelockedOutput) {
+				out.EarlyThreshold = 0
+			},
+		},
+		{
+			name: "no fallback",
+			modify: func(out *TimelockedOutput) {
+				out.Fallback = OutputOwners{}
+			},
+		},
+		{
+			name: "no value",
+			modify: func(out *TimelockedOutput) {
+				out.Amt = 0
+			},
+			expectedErr: errNoValueOutput,
+		},
+		{
+			name: "owners locktime",
+			modify: func(out *TimelockedOutput) {
+				out.Owners.Locktime = 1
+			},
+			expectedErr: errNestedLocktime,
+		},
+		{
+			name: "fallback locktime",
+			modify: func(out *TimelockedOutput) {
+				out.Fallback.Locktime = 1
+			},
+			expectedErr: errNestedLocktime,
+		},
+		{
+			name: "early threshold too high",
+			modify: func(out *TimelockedOutput) {
+				out.EarlyThreshold = 3
+			},
+			expectedErr: errOutputUnspendable,
+		},
+		{
+			name: "early threshold too low",
+			modify: func(out *TimelockedOutput) {
+				out.Owners.Threshold = 2
+				out.EarlyThreshold = 1
+			},
+			expectedErr: errEarlyThresholdTooLow,
+		},
+		{
+			name: "invalid owners",
+			modify: func(out *TimelockedOutput) {
+				out.Owners.Addrs[0], out.Owners.Addrs[1] = out.Owners.Addrs[1], out.Owners.Addrs[0]
+			},
+			expectedErr: errAddrsNotSortedUnique,
+		},
+		{
+			name: "invalid fallback",
+			modify: func(out *TimelockedOutput) {
+				out.Fallback.Threshold = 2
+			},
+			expectedErr: errOutputUnspendable,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := newTestTimelockedOutput()
+			test.modify(out)
+			if err := out.Verify(); err != test.expectedErr {
+				t.Fatalf("expected %v but got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTimelockedOutputVerifyNil(t *testing.T) {
+	out := (*TimelockedOutput)(nil)
+	if err := out.Verify(); err != errNilOutput {
+		t.Fatalf("expected %v but got %v", errNilOutput, err)
+	}
+}
+
+func TestTimelockedOutputSpendingOwners(t *testing.T) {
+	out := newTestTimelockedOutput()
+
+	owners, err := out.SpendingOwners(false, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners.Threshold != out.EarlyThreshold || len(owners.Addrs) != len(out.Owners.Addrs) {
+		t.Fatalf("expected early owners but got %+v", owners)
+	}
+
+	if _, err := out.SpendingOwners(true, 9); err != errTimelocked {
+		t.Fatalf("expected %v but got %v", errTimelocked, err)
+	}
+
+	owners, err = out.SpendingOwners(false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners != &out.Owners {
+		t.Fatalf("expected owners but got %+v", owners)
+	}
+
+	owners, err = out.SpendingOwners(true, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners != &out.Fallback {
+		t.Fatalf("expected fallback owners but got %+v", owners)
+	}
+
+	out.EarlyThreshold = 0
+	if _, err := out.SpendingOwners(false, 9); err != errTimelocked {
+		t.Fatalf("expected %v but got %v", errTimelocked, err)
+	}
+
+	out.Fallback = OutputOwners{}
+	if _, err := out.SpendingOwners(true, 10); err != errNoFallbackOwners {
+		t.Fatalf("expected %v but got %v", errNoFallbackOwners, err)
+	}
+}
+
+func TestTimelockedOutputAddresses(t *testing.T) {
+	out := newTestTimelockedOutput()
+	out.Fallback.Addrs = append(out.Fallback.Addrs, ids.ShortID{2})
+	ids.SortShortIDs(out.Fallback.Addrs)
+
+	addrs := out.Addresses()
+	if len(addrs) != 3 {
+		t.Fatalf("expected 3 addresses but got %d", len(addrs))
+	}
+	for i, expected := range []ids.ShortID{{1}, {2}, {3}} {
+		addr, err := ids.ToShortID(addrs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != expected {
+			t.Fatalf("expected %s at index %d but got %s", expected, i, addr)
+		}
+	}
+}
+
+func TestTimelockedOutputSerialize(t *testing.T) {
+	c := linearcodec.NewDefault()
+	m := codec.NewDefaultManager()
+	if err := c.RegisterType(&TimelockedOutput{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterCodec(0, c); err != nil {
+		t.Fatal(err)
+	}
+
+	out := newTestTimelockedOutput()
+	outBytes, err := m.Marshal(0, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &TimelockedOutput{}
+	if _, err := m.Unmarshal(outBytes, parsed); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify.All(parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Amt != out.Amt ||
+		parsed.Locktime != out.Locktime ||
+		parsed.EarlyThreshold != out.EarlyThreshold ||
+		!parsed.Owners.Equals(&out.Owners) ||
+		!parsed.Fallback.Equals(&out.Fallback) {
+		t.Fatalf("expected %+v but got %+v", out, parsed)
+	}
+}